@@ -0,0 +1,44 @@
+// Package main は画面を使わずにスクリプトから CloudLaunch を操作するコマンドラインの入口を提供する。
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"CloudLaunch_Go/internal/app"
+	"CloudLaunch_Go/internal/cli"
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/i18n"
+)
+
+func main() {
+	// DB を開く前（引数の誤りなど）の表示も、アプリと同じ環境変数の表示言語に合わせる。
+	// DB を開いた後は保存済みの設定の表示言語になる。
+	_ = i18n.SetLanguage(config.LoadFromEnv().Language)
+	// Ctrl+C やタスクスケジューラの停止で同期を途中で取り消せるようにする。
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := cli.Run(ctx, os.Args[1:], os.Stdout, os.Stderr, openBackend)
+	stop()
+	os.Exit(code)
+}
+
+// openBackend はアプリと同じ設定・DB でサービスを用意する。ログは結果と混ざらないよう画面には出さず、app.log にだけ残す。
+func openBackend(ctx context.Context) (*cli.Backend, func(), error) {
+	backend, err := app.NewHeadlessApp(ctx, io.Discard)
+	if err != nil {
+		return nil, nil, fmt.Errorf(i18n.Text("cli.initFailed"), err)
+	}
+	closeBackend := func() {
+		if err := backend.Shutdown(context.Background()); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, i18n.Text("cli.closeFailed")+"\n", err)
+		}
+	}
+	return &cli.Backend{
+		Games:  backend.GameService,
+		Sync:   backend.ContentSyncService,
+		Export: backend.MaintenanceService,
+	}, closeBackend, nil
+}
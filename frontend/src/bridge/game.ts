@@ -6,6 +6,7 @@
 
 import { LaunchGame, CaptureGameScreenshot } from "../../wailsjs/go/app/App";
 import { getErrorMessage, toApiResult, toApiResultVoid } from "./helpers";
+import type { CaptureResult } from "src/types/game";
 import type { WindowApi } from "./types";
 
 export function createGameBridge(): WindowApi["game"] {
@@ -13,7 +14,7 @@ export function createGameBridge(): WindowApi["game"] {
     launchGame: async (exePath) => toApiResultVoid(await LaunchGame(exePath)),
     captureWindow: async (gameId) => {
       try {
-        return toApiResult<string>(await CaptureGameScreenshot(gameId));
+        return toApiResult<CaptureResult>(await CaptureGameScreenshot(gameId));
       } catch (error) {
         return {
           success: false,
@@ -12,6 +12,7 @@ import type {
   PlayStatus,
   MonitoringGameStatus,
   GameImport,
+  CaptureResult,
 } from "src/types/game";
 import type { ErogameScapeSearchResult } from "src/types/erogamescape";
 import type { SortOption, FilterOption, SortDirection } from "src/types/menu";
@@ -208,7 +209,7 @@ export type WindowApi = {
   };
   game: {
     launchGame: (exePath: string) => Promise<ApiResult<void>>;
-    captureWindow: (gameId: string) => Promise<ApiResult<string>>;
+    captureWindow: (gameId: string) => Promise<ApiResult<CaptureResult>>;
   };
   erogameScape: {
     fetchById: (id: string) => Promise<ApiResult<GameImport>>;
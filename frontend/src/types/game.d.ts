@@ -50,6 +50,17 @@ export type MonitoringGameStatus = {
   needsResume: boolean;
 };
 
+export type CaptureResult = {
+  path: string;
+  gameId: string;
+  hwnd: number;
+  backend: string;
+  width: number;
+  height: number;
+  durationMs: number;
+  warnings?: string[];
+};
+
 export type PlaySessionType = {
   id: string;
   sessionName?: string;
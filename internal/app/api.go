@@ -33,6 +33,37 @@ func (app *App) ListGames(searchText string, filter string, sortBy string, sortD
 	return serviceResult(games, err, "ゲーム一覧取得に失敗しました")
 }
 
+// ListGamesSummary は一覧表示用の縮小版ゲーム一覧を取得する。
+// グリッド表示など、詳細項目を使わない画面では ListGames の代わりに使う。
+func (app *App) ListGamesSummary(searchText string, filter string, sortBy string, sortDirection string) result.ApiResult[[]domain.GameSummary] {
+	status := normalizePlayStatus(filter)
+	games, err := app.GameService.ListGamesSummary(app.context(), searchText, status, sortBy, sortDirection)
+	return serviceResult(games, err, "ゲーム一覧取得に失敗しました")
+}
+
+// ListGamesPage は ListGames のページング版。limit 件ずつ offset から取得し、全件数も返す。
+func (app *App) ListGamesPage(searchText string, filter string, sortBy string, sortDirection string, limit int, offset int) result.ApiResult[domain.Page[domain.Game]] {
+	status := normalizePlayStatus(filter)
+	page := domain.PageRequest{Limit: limit, Offset: offset}
+	games, err := app.GameService.ListGamesPage(app.context(), searchText, status, sortBy, sortDirection, page)
+	return serviceResult(games, err, "ゲーム一覧取得に失敗しました")
+}
+
+// ListGamesSummaryPage は ListGamesSummary のページング版。
+func (app *App) ListGamesSummaryPage(searchText string, filter string, sortBy string, sortDirection string, limit int, offset int) result.ApiResult[domain.Page[domain.GameSummary]] {
+	status := normalizePlayStatus(filter)
+	page := domain.PageRequest{Limit: limit, Offset: offset}
+	games, err := app.GameService.ListGamesSummaryPage(app.context(), searchText, status, sortBy, sortDirection, page)
+	return serviceResult(games, err, "ゲーム一覧取得に失敗しました")
+}
+
+// ListRecentlyPlayed はホーム画面の「続きから遊ぶ」向けに、最近遊んだゲームを最後のセッション・現在のルートと併せて取得する。
+// limit が 0 以下なら既定の件数を返す。
+func (app *App) ListRecentlyPlayed(limit int) result.ApiResult[[]domain.RecentlyPlayedGame] {
+	games, err := app.GameService.ListRecentlyPlayed(app.context(), limit)
+	return serviceResult(games, err, "最近遊んだゲームの取得に失敗しました")
+}
+
 // GetGameByID はゲームを取得する。
 func (app *App) GetGameByID(gameID string) result.ApiResult[*domain.Game] {
 	game, err := app.GameService.GetGameByID(app.context(), gameID)
@@ -46,7 +77,11 @@ func (app *App) CreateGame(input services.GameInput) result.ApiResult[*domain.Ga
 		return serviceErrorResult[*domain.Game](err, "ゲーム作成に失敗しました")
 	}
 	if created != nil {
+		// 取り込み元に画像が無いゲームは、実行ファイルのアイコンを代わりのカバー画像にする。
+		created = app.applyExeIconCover(created)
+		app.recordActivity(domain.ActivityActionCreate, domain.ChangeEntityGame, created.ID, "ゲーム「"+created.Title+"」を追加")
 		app.syncGameAsync(created.ID)
+		app.reloadSaveFolderWatchAsync()
 	}
 	return result.OkResult(created)
 }
@@ -58,20 +93,54 @@ func (app *App) UpdateGame(gameID string, input services.GameUpdateInput) result
 		return serviceErrorResult[*domain.Game](err, "ゲーム更新に失敗しました")
 	}
 	if updated != nil {
+		app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityGame, updated.ID, "ゲーム「"+updated.Title+"」を更新")
 		app.syncGameAsync(updated.ID)
+		app.reloadSaveFolderWatchAsync()
+	}
+	return result.OkResult(updated)
+}
+
+// ToggleFavorite はゲームのお気に入り（ライブラリ先頭への固定）を切り替える。
+func (app *App) ToggleFavorite(gameID string) result.ApiResult[*domain.Game] {
+	updated, err := app.GameService.ToggleFavorite(app.context(), gameID)
+	if err != nil {
+		return serviceErrorResult[*domain.Game](err, "お気に入りの更新に失敗しました")
+	}
+	summary := "ゲーム「" + updated.Title + "」をお気に入りから外す"
+	if updated.IsFavorite {
+		summary = "ゲーム「" + updated.Title + "」をお気に入りに追加"
 	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityGame, updated.ID, summary)
+	app.syncGameAsync(updated.ID)
 	return result.OkResult(updated)
 }
 
 // UpdatePlayTime はプレイ時間を更新する。
 func (app *App) UpdatePlayTime(gameID string, totalPlayTime int64, lastPlayed time.Time) result.ApiResult[*domain.Game] {
 	game, err := app.GameService.UpdatePlayTime(app.context(), gameID, totalPlayTime, lastPlayed)
-	return serviceResult(game, err, "プレイ時間更新に失敗しました")
+	if err != nil {
+		return serviceErrorResult[*domain.Game](err, "プレイ時間更新に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityGame, game.ID, "ゲーム「"+game.Title+"」のプレイ時間を更新")
+	return result.OkResult(game)
 }
 
 // DeleteGame はゲームを削除する。
 func (app *App) DeleteGame(gameID string) result.ApiResult[bool] {
-	return boolResult(app.GameService.DeleteGame(app.context(), gameID), "ゲーム削除に失敗しました")
+	// 削除後はタイトルを引けないため、履歴に残す要約は削除前に組み立てる。
+	summary := "ゲームを削除"
+	if game, err := app.GameService.GetGameByID(app.context(), gameID); err == nil && game != nil {
+		summary = "ゲーム「" + game.Title + "」を削除"
+	}
+	if err := app.GameService.DeleteGame(app.context(), gameID); err != nil {
+		return serviceErrorResult[bool](err, "ゲーム削除に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionDelete, domain.ChangeEntityGame, gameID, summary)
+	if app.ContentSyncService != nil {
+		app.ContentSyncService.ForgetGameCache(gameID)
+	}
+	app.reloadSaveFolderWatchAsync()
+	return result.OkResult(true)
 }
 
 // ListRoutesByGame はルート一覧を取得する。
@@ -83,12 +152,18 @@ func (app *App) ListRoutesByGame(gameID string) result.ApiResult[[]domain.Route]
 // CreateRoute はルートを作成する。
 func (app *App) CreateRoute(input services.RouteInput) result.ApiResult[*domain.Route] {
 	route, err := app.RouteService.CreateRoute(app.context(), input)
+	if err == nil && route != nil {
+		app.recordActivity(domain.ActivityActionCreate, domain.ChangeEntityRoute, route.ID, "ルート「"+route.Name+"」を追加")
+	}
 	return serviceResult(route, err, "ルート作成に失敗しました")
 }
 
 // UpdateRoute はルートを更新する。
 func (app *App) UpdateRoute(routeID string, input services.RouteUpdateInput) result.ApiResult[*domain.Route] {
 	route, err := app.RouteService.UpdateRoute(app.context(), routeID, input)
+	if err == nil && route != nil {
+		app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityRoute, route.ID, "ルート「"+route.Name+"」を更新")
+	}
 	return serviceResult(route, err, "ルート更新に失敗しました")
 }
 
@@ -103,6 +178,34 @@ func (app *App) GetRouteStats(gameID string) result.ApiResult[[]domain.RouteStat
 	return serviceResult(stats, err, "ルート統計取得に失敗しました")
 }
 
+// GetRouteProgress はルートごとの達成率・残り見込み時間とゲーム全体のクリア状況を取得する。
+func (app *App) GetRouteProgress(gameID string) result.ApiResult[domain.RouteProgress] {
+	progress, err := app.RouteService.GetRouteProgress(app.context(), gameID)
+	return serviceResult(progress, err, "ルート進捗取得に失敗しました")
+}
+
+// SetRouteEstimatedTime はルートの見込み時間（秒、0 で未設定）を設定する。
+func (app *App) SetRouteEstimatedTime(routeID string, seconds int64) result.ApiResult[*domain.Route] {
+	route, err := app.RouteService.SetRouteEstimatedTime(app.context(), routeID, seconds)
+	return serviceResult(route, err, "ルート進捗更新に失敗しました")
+}
+
+// SetRouteCompleted はルートのクリア状態を設定する。
+func (app *App) SetRouteCompleted(routeID string, completed bool) result.ApiResult[*domain.Route] {
+	route, err := app.RouteService.SetRouteCompleted(app.context(), routeID, completed)
+	return serviceResult(route, err, "ルート進捗更新に失敗しました")
+}
+
+// ComparePlaythroughs は2周分のプレイを章（ルート）ごとに比較する。
+func (app *App) ComparePlaythroughs(
+	gameID string,
+	selectorA services.PlaythroughSelector,
+	selectorB services.PlaythroughSelector,
+) result.ApiResult[domain.PlaythroughComparison] {
+	comparison, err := app.RouteService.ComparePlaythroughs(app.context(), gameID, selectorA, selectorB)
+	return serviceResult(comparison, err, "周回比較に失敗しました")
+}
+
 // SetCurrentRoute はゲームの現在ルートを設定する。
 func (app *App) SetCurrentRoute(gameID string, routeID string) result.ApiResult[bool] {
 	return boolResult(app.RouteService.SetCurrentRoute(app.context(), gameID, routeID), "現在ルート更新に失敗しました")
@@ -110,7 +213,11 @@ func (app *App) SetCurrentRoute(gameID string, routeID string) result.ApiResult[
 
 // DeleteRoute はルートを削除する。
 func (app *App) DeleteRoute(routeID string) result.ApiResult[bool] {
-	return boolResult(app.RouteService.DeleteRoute(app.context(), routeID), "ルート削除に失敗しました")
+	if err := app.RouteService.DeleteRoute(app.context(), routeID); err != nil {
+		return serviceErrorResult[bool](err, "ルート削除に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionDelete, domain.ChangeEntityRoute, routeID, "ルートを削除")
+	return result.OkResult(true)
 }
 
 // CreateSession はセッションを作成する。
@@ -120,6 +227,7 @@ func (app *App) CreateSession(input services.SessionInput) result.ApiResult[*dom
 		return serviceErrorResult[*domain.PlaySession](err, "セッション作成に失敗しました")
 	}
 	if created != nil {
+		app.recordActivity(domain.ActivityActionCreate, domain.ChangeEntitySession, created.ID, "プレイセッションを追加")
 		app.syncGameAsync(created.GameID)
 	}
 	return result.OkResult(created)
@@ -131,12 +239,27 @@ func (app *App) ListSessionsByGame(gameID string) result.ApiResult[[]domain.Play
 	return serviceResult(sessions, err, "セッション取得に失敗しました")
 }
 
+// ListSessionsByGamePage は ListSessionsByGame のページング版。新しい順に limit 件ずつ返す。
+func (app *App) ListSessionsByGamePage(gameID string, limit int, offset int) result.ApiResult[domain.Page[domain.PlaySession]] {
+	page := domain.PageRequest{Limit: limit, Offset: offset}
+	sessions, err := app.SessionService.ListSessionsByGamePage(app.context(), gameID, page)
+	return serviceResult(sessions, err, "セッション取得に失敗しました")
+}
+
+// GetPlayCalendar は year 年の日別プレイ時間（分）をコントリビューションカレンダー向けに返す。
+// includeGames が true なら日ごとのゲーム別内訳も含める。
+func (app *App) GetPlayCalendar(year int, includeGames bool) result.ApiResult[domain.PlayCalendar] {
+	calendar, err := app.SessionService.GetPlayCalendar(app.context(), year, includeGames)
+	return serviceResult(calendar, err, "プレイカレンダーの取得に失敗しました")
+}
+
 // DeleteSession はセッションを削除する。
 func (app *App) DeleteSession(sessionID string) result.ApiResult[bool] {
 	deleted, err := app.SessionService.DeleteSession(app.context(), sessionID)
 	if err != nil {
 		return serviceErrorResult[bool](err, "セッション削除に失敗しました")
 	}
+	app.recordActivity(domain.ActivityActionDelete, domain.ChangeEntitySession, sessionID, "プレイセッションを削除")
 	if deleted.GameID != "" {
 		app.syncGameAsync(deleted.GameID)
 	}
@@ -149,6 +272,20 @@ func (app *App) UpdateSessionRoute(sessionID string, routeID *string) result.Api
 	if err != nil {
 		return serviceErrorResult[bool](err, "セッションルート更新に失敗しました")
 	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntitySession, sessionID, "プレイセッションのルートを変更")
+	if updated.GameID != "" {
+		app.syncGameAsync(updated.GameID)
+	}
+	return result.OkResult(true)
+}
+
+// UpdateSession はセッションの開始日時とプレイ時間（秒）を補正し、ゲームの累計プレイ時間を再計算する。
+func (app *App) UpdateSession(sessionID string, playedAt time.Time, duration int64) result.ApiResult[bool] {
+	updated, err := app.SessionService.UpdateSession(app.context(), sessionID, playedAt, duration)
+	if err != nil {
+		return serviceErrorResult[bool](err, "セッション更新に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntitySession, sessionID, "プレイセッションの日時・プレイ時間を補正")
 	if updated.GameID != "" {
 		app.syncGameAsync(updated.GameID)
 	}
@@ -161,21 +298,60 @@ func (app *App) UpdateSessionName(sessionID string, sessionName string) result.A
 	if err != nil {
 		return serviceErrorResult[bool](err, "セッション名更新に失敗しました")
 	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntitySession, sessionID, "プレイセッション名を変更")
 	if updated.GameID != "" {
 		app.syncGameAsync(updated.GameID)
 	}
 	return result.OkResult(true)
 }
 
+// UpdateSessionNotes はセッションのメモ（何を進めたか）を更新する。空文字で未記入に戻す。
+func (app *App) UpdateSessionNotes(sessionID string, notes string) result.ApiResult[bool] {
+	updated, err := app.SessionService.UpdateSessionNotes(app.context(), sessionID, notes)
+	if err != nil {
+		return serviceErrorResult[bool](err, "セッションメモ更新に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntitySession, sessionID, "プレイセッションのメモを更新")
+	if updated.GameID != "" {
+		app.syncGameAsync(updated.GameID)
+	}
+	return result.OkResult(true)
+}
+
+// gameAggregatesUpdatedEvent はゲームの集計値を再計算したときにフロントエンドへ送るイベント名。
+const gameAggregatesUpdatedEvent = "game:aggregatesUpdated"
+
+// RecalculateGameAggregates はセッションの一括編集・取り込み後に、累計プレイ時間・最終プレイ日時・ルート統計を再計算する。
+// 結果は gameAggregatesUpdatedEvent でも通知し、開いている画面が表示を更新できるようにする。
+func (app *App) RecalculateGameAggregates(gameID string) result.ApiResult[domain.GameAggregates] {
+	aggregates, err := app.SessionService.RecalculateGameAggregates(app.context(), gameID)
+	if err != nil {
+		return serviceErrorResult[domain.GameAggregates](err, "ゲーム集計の再計算に失敗しました")
+	}
+	if aggregates.Changed {
+		app.syncGameAsync(aggregates.GameID)
+	}
+	if app.ctx != nil {
+		runtime.EventsEmit(app.ctx, gameAggregatesUpdatedEvent, aggregates)
+	}
+	return result.OkResult(aggregates)
+}
+
 // CreateMemo はメモを作成する。
 func (app *App) CreateMemo(input services.MemoInput) result.ApiResult[*domain.Memo] {
 	memo, err := app.MemoService.CreateMemo(app.context(), input)
+	if err == nil && memo != nil {
+		app.recordActivity(domain.ActivityActionCreate, domain.ChangeEntityMemo, memo.ID, "メモ「"+memo.Title+"」を作成")
+	}
 	return serviceResult(memo, err, "メモ作成に失敗しました")
 }
 
 // UpdateMemo はメモを更新する。
 func (app *App) UpdateMemo(memoID string, input services.MemoUpdateInput) result.ApiResult[*domain.Memo] {
 	memo, err := app.MemoService.UpdateMemo(app.context(), memoID, input)
+	if err == nil && memo != nil {
+		app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityMemo, memo.ID, "メモ「"+memo.Title+"」を更新")
+	}
 	return serviceResult(memo, err, "メモ更新に失敗しました")
 }
 
@@ -185,6 +361,12 @@ func (app *App) GetMemoByID(memoID string) result.ApiResult[*domain.Memo] {
 	return serviceResult(memo, err, "メモ取得に失敗しました")
 }
 
+// GetMemoOutline はメモの目次（見出し）・チェックリスト進捗・語数と読了時間の目安を返す。
+func (app *App) GetMemoOutline(memoID string) result.ApiResult[domain.MemoOutline] {
+	outline, err := app.MemoService.GetMemoOutline(app.context(), memoID)
+	return serviceResult(outline, err, "メモの解析に失敗しました")
+}
+
 // ListAllMemos は全メモを取得する。
 func (app *App) ListAllMemos() result.ApiResult[[]domain.Memo] {
 	memos, err := app.MemoService.ListAllMemos(app.context())
@@ -199,7 +381,11 @@ func (app *App) ListMemosByGame(gameID string) result.ApiResult[[]domain.Memo] {
 
 // DeleteMemo はメモを削除する。
 func (app *App) DeleteMemo(memoID string) result.ApiResult[bool] {
-	return boolResult(app.MemoService.DeleteMemo(app.context(), memoID), "メモ削除に失敗しました")
+	if err := app.MemoService.DeleteMemo(app.context(), memoID); err != nil {
+		return serviceErrorResult[bool](err, "メモ削除に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionDelete, domain.ChangeEntityMemo, memoID, "メモを削除")
+	return result.OkResult(true)
 }
 
 // FileFilterInput はファイル選択フィルタを表す。
@@ -292,6 +478,7 @@ func (app *App) UpdateAutoTracking(enabled bool) result.ApiResult[bool] {
 		app.ProcessMonitor.UpdateAutoTracking(enabled)
 		app.isMonitoring = app.ProcessMonitor.IsMonitoring()
 	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -299,9 +486,16 @@ func (app *App) UpdateAutoTracking(enabled bool) result.ApiResult[bool] {
 // ON の間は ContentSyncService.Push/Pull/DeleteFromCloud が ErrOffline を返し、
 // process_monitor からの自動同期も静かにスキップされる。フロントエンドの atom
 // 状態は永続化されているため、起動時にもこの API を再度呼んでバックエンドへ同期させる。
+// スキップした同期・アップロードは送信待ちに残り、OFF にした時点で順番に再送する。
+// NetworkMonitor がクラウドに到達できないと判定している間は、OFF にしてもオフラインのまま。
 func (app *App) UpdateOfflineMode(enabled bool) result.ApiResult[bool] {
+	app.offlineMode = enabled
 	if app.ContentSyncService != nil {
-		app.ContentSyncService.SetOfflineMode(enabled)
+		app.ContentSyncService.SetOfflineMode(app.isOffline())
+	}
+	app.persistSettings()
+	if !enabled {
+		app.replaySyncQueueAsync()
 	}
 	return result.OkResult(true)
 }
@@ -316,6 +510,21 @@ func (app *App) UpdateUploadConcurrency(value int) result.ApiResult[bool] {
 	if app.ContentSyncService != nil {
 		app.ContentSyncService.SetUploadConcurrency(value)
 	}
+	if app.ScreenshotCloudService != nil {
+		app.ScreenshotCloudService.SetUploadConcurrency(value)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateSaveCompression はセーブフォルダを zip にまとめてアップロードするかを更新する。
+// 小さなファイルが大量にあるセーブ向け。取り込み側は形式を自動判別する。
+func (app *App) UpdateSaveCompression(enabled bool) result.ApiResult[bool] {
+	app.Config.SaveCompression = enabled
+	if app.ContentSyncService != nil {
+		app.ContentSyncService.SetSaveCompression(enabled)
+	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -328,6 +537,16 @@ func (app *App) UpdateS3ForcePathStyle(enabled bool) result.ApiResult[bool] {
 	if app.MemoCloudService != nil {
 		app.MemoCloudService.SetS3ForcePathStyle(enabled)
 	}
+	if app.ScreenshotCloudService != nil {
+		app.ScreenshotCloudService.SetS3ForcePathStyle(enabled)
+	}
+	if app.CloudPathMigration != nil {
+		app.CloudPathMigration.SetS3ForcePathStyle(enabled)
+	}
+	if app.NetworkMonitor != nil {
+		app.NetworkMonitor.SetS3ForcePathStyle(enabled)
+	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -340,39 +559,47 @@ func (app *App) UpdateS3UseTLS(enabled bool) result.ApiResult[bool] {
 	if app.MemoCloudService != nil {
 		app.MemoCloudService.SetS3UseTLS(enabled)
 	}
+	if app.ScreenshotCloudService != nil {
+		app.ScreenshotCloudService.SetS3UseTLS(enabled)
+	}
+	if app.CloudPathMigration != nil {
+		app.CloudPathMigration.SetS3UseTLS(enabled)
+	}
+	if app.NetworkMonitor != nil {
+		app.NetworkMonitor.SetS3UseTLS(enabled)
+	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
 // UpdateLogLevel はバックエンドのログレベルを実行時に変更する。
 // 受け付ける値: debug / info / warn / error（大文字小文字・空白は無視）。
 func (app *App) UpdateLogLevel(level string) result.ApiResult[bool] {
-	normalized := strings.ToLower(strings.TrimSpace(level))
-	switch normalized {
-	case "debug", "info", "warn", "warning", "error":
-	default:
+	normalized, ok := services.NormalizeLogLevel(level)
+	if !ok {
 		app.Logger.Warn("ログレベルが不正です", "operation", "UpdateLogLevel", "level", level)
 		return result.ErrorResult[bool]("ログレベルが不正です", "level must be debug|info|warn|error")
 	}
-	if normalized == "warning" {
-		normalized = "warn"
-	}
 	app.Config.LogLevel = normalized
 	if app.logLevel != nil {
 		app.logLevel.Set(logging.ParseLevel(normalized))
 	}
 	app.Logger.Info("ログレベルを更新しました", "level", normalized)
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
 // UpdateScreenshotSyncEnabled はスクリーンショット同期の有効/無効を更新する。
 func (app *App) UpdateScreenshotSyncEnabled(enabled bool) result.ApiResult[bool] {
 	app.Config.ScreenshotSyncEnabled = enabled
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
 // UpdateScreenshotUploadJpeg はスクリーンショットをJPEG変換してアップロードするか更新する。
 func (app *App) UpdateScreenshotUploadJpeg(enabled bool) result.ApiResult[bool] {
 	app.Config.ScreenshotUploadJpeg = enabled
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -386,6 +613,7 @@ func (app *App) UpdateScreenshotJpegQuality(value int) result.ApiResult[bool] {
 	if app.ScreenshotService != nil {
 		app.ScreenshotService.SetJpegQuality(value)
 	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -395,6 +623,7 @@ func (app *App) UpdateScreenshotClientOnly(enabled bool) result.ApiResult[bool]
 	if app.ScreenshotService != nil {
 		app.ScreenshotService.SetClientOnly(enabled)
 	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -404,6 +633,7 @@ func (app *App) UpdateScreenshotLocalJpeg(enabled bool) result.ApiResult[bool] {
 	if app.ScreenshotService != nil {
 		app.ScreenshotService.SetLocalJpeg(enabled)
 	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -440,10 +670,18 @@ func (app *App) UpdateScreenshotHotkey(combo string) result.ApiResult[bool] {
 		// already registered になるのを防ぐ。
 		return result.OkResult(true)
 	}
+	if _, err := services.NormalizeHotkeyActions(app.Config.HotkeyActions, trimmed); err != nil {
+		app.Logger.Warn("ホットキーが不正です", "operation", "UpdateScreenshotHotkey", "combo", trimmed, "error", err)
+		return result.ErrorResult[bool]("ホットキーが不正です", err.Error())
+	}
 	prev := app.Config.ScreenshotHotkey
 	app.Config.ScreenshotHotkey = trimmed
-	return app.applyHotkeyChange("UpdateScreenshotHotkey", "ホットキーの更新に失敗しました",
+	changed := app.applyHotkeyChange("UpdateScreenshotHotkey", "ホットキーの更新に失敗しました",
 		func() { app.Config.ScreenshotHotkey = prev }, "combo", trimmed)
+	if changed.Success {
+		app.persistSettings()
+	}
+	return changed
 }
 
 // UpdateScreenshotHotkeyNotify はホットキー通知の有効/無効を更新する。
@@ -458,6 +696,18 @@ func (app *App) UpdateScreenshotHotkeyNotify(enabled bool) result.ApiResult[bool
 		app.HotkeyService.SetNotify(enabled)
 	}
 	app.hotkeyMu.Unlock()
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateScreenshotExcludedApps はホットキー撮影から除外するアプリ（カンマ区切りの実行ファイル名）を更新する。
+func (app *App) UpdateScreenshotExcludedApps(value string) result.ApiResult[bool] {
+	normalized := services.NormalizeScreenshotExcludedApps(value)
+	app.Config.ScreenshotExcludedApps = normalized
+	if app.ScreenshotService != nil {
+		app.ScreenshotService.SetExcludedApps(normalized)
+	}
+	app.persistSettings()
 	return result.OkResult(true)
 }
 
@@ -528,6 +778,36 @@ func (app *App) EndMonitoringSession(gameID string) result.ApiResult[bool] {
 	return result.OkResult(true)
 }
 
+// monitorWarmupEvent は開始時刻の見積もりが必要なゲームを検出したときにフロントエンドへ送るイベント名。
+const monitorWarmupEvent = "monitor:warmup"
+
+// handleWarmupPrompt は prompt 方針で見積もり待ちになったゲームをフロントエンドへ通知する。
+func (app *App) handleWarmupPrompt(gameIDs []string) {
+	if app.ctx != nil {
+		runtime.EventsEmit(app.ctx, monitorWarmupEvent, gameIDs)
+	}
+}
+
+// EstimateWarmupSessionStart はアプリ起動前から起動していたゲームの実際の開始時刻の見積もりを反映する。
+func (app *App) EstimateWarmupSessionStart(gameID string, startedAt time.Time) result.ApiResult[bool] {
+	if errResult := app.requireProcessMonitor("EstimateWarmupSessionStart"); !errResult.Success {
+		return errResult
+	}
+	err := app.ProcessMonitor.EstimateWarmupStart(strings.TrimSpace(gameID), startedAt)
+	if err != nil {
+		app.Logger.Warn("開始時刻の反映に失敗しました", "operation", "EstimateWarmupSessionStart", "gameId", gameID, "error", err)
+	}
+	return boolResult(err, "開始時刻の反映に失敗しました")
+}
+
+// DismissWarmupPrompt は開始時刻の見積もりを尋ねずに閉じる（セッションは partial のまま保存される）。
+func (app *App) DismissWarmupPrompt(gameID string) result.ApiResult[bool] {
+	if errResult := app.requireProcessMonitor("DismissWarmupPrompt"); !errResult.Success {
+		return errResult
+	}
+	return result.OkResult(app.ProcessMonitor.DismissWarmupPrompt(strings.TrimSpace(gameID)))
+}
+
 // SelectFile はファイル選択ダイアログを開く。
 func (app *App) SelectFile(filters []FileFilterInput) result.ApiResult[string] {
 	dialogContext := app.runtimeContext()
@@ -624,44 +904,114 @@ func (app *App) LoadCredential(key string) result.ApiResult[*services.Credential
 	return toCredentialOutput(app.CredentialService.LoadCredential(app.context(), key))
 }
 
+// ListCredentialKeys は保存済みの認証情報プロファイルのキー一覧を返す。
+func (app *App) ListCredentialKeys() result.ApiResult[[]string] {
+	keys, err := app.CredentialService.ListCredentialKeys(app.context())
+	return serviceResult(keys, err, "認証情報一覧の取得に失敗しました")
+}
+
+// UpdateActiveCredentialKey はクラウド同期・メモ同期で使う認証情報プロファイルを切り替える。
+// 未保存のキーへの切り替えは、接続できない設定を永続化してしまうため拒否する。
+func (app *App) UpdateActiveCredentialKey(key string) result.ApiResult[bool] {
+	trimmed := strings.TrimSpace(key)
+	if trimmed == "" {
+		app.Logger.Warn("認証情報キーが不正です", "operation", "UpdateActiveCredentialKey", "reason", "empty key")
+		return result.ErrorResult[bool]("認証情報キーが不正です", "key is empty")
+	}
+	credential, err := app.CredentialService.LoadCredential(app.context(), trimmed)
+	if err != nil {
+		return serviceErrorResult[bool](err, "認証情報取得に失敗しました")
+	}
+	if credential == nil {
+		app.Logger.Warn("認証情報が見つかりません", "operation", "UpdateActiveCredentialKey", "key", trimmed)
+		return result.ErrorResult[bool]("認証情報が見つかりません", "key not found: "+trimmed)
+	}
+	app.Config.CredentialKey = trimmed
+	if app.ContentSyncService != nil {
+		app.ContentSyncService.SetCredentialKey(trimmed)
+	}
+	if app.MemoCloudService != nil {
+		app.MemoCloudService.SetCredentialKey(trimmed)
+	}
+	if app.ScreenshotCloudService != nil {
+		app.ScreenshotCloudService.SetCredentialKey(trimmed)
+	}
+	if app.CloudPathMigration != nil {
+		app.CloudPathMigration.SetCredentialKey(trimmed)
+	}
+	if app.NetworkMonitor != nil {
+		app.NetworkMonitor.SetCredentialKey(trimmed)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
 // DeleteCredential は認証情報を削除する。
 func (app *App) DeleteCredential(key string) result.ApiResult[bool] {
 	return boolResult(app.CredentialService.DeleteCredential(app.context(), key), "認証情報削除に失敗しました")
 }
 
-// LaunchGame は指定された実行ファイルを起動する。
-func (app *App) LaunchGame(exePath string) result.ApiResult[bool] {
+// LaunchGame は指定された実行ファイルを起動し、起動したプロセスの PID を返す。
+// 登録済みのゲームはその PID でプロセス監視に登録し、次のスキャンを待たずに計測を始める。
+func (app *App) LaunchGame(exePath string) result.ApiResult[int] {
 	if strings.TrimSpace(exePath) == "" || exePath == services.UnconfiguredExePath {
 		app.Logger.Warn("実行ファイルが不正です", "operation", "LaunchGame", "exePath", exePath)
-		return result.ErrorResult[bool]("実行ファイルが不正です", "exePathが空です")
+		return result.ErrorResult[int]("実行ファイルが不正です", "exePathが空です")
 	}
-	command := exec.Command(exePath)
+	// 起動ラッパーが設定されたゲームはラッパー経由で起動する。作業フォルダはどちらもゲーム本体のフォルダにする。
+	name, args, err := app.GameService.ResolveLaunchCommand(app.context(), exePath)
+	if err != nil {
+		return serviceErrorResult[int](err, "ゲーム起動に失敗しました")
+	}
+	// 起動前コマンド（ドライブのマウント等）が設定されていれば、終わるのを待ってから起動する。
+	if err := app.GameService.RunPreLaunchHook(app.context(), exePath); err != nil {
+		return serviceErrorResult[int](err, "起動前コマンドの実行に失敗しました")
+	}
+	command := exec.Command(name, args...)
 	command.Dir = filepath.Dir(exePath)
 	if error := command.Start(); error != nil {
 		app.Logger.Error("ゲーム起動に失敗", "error", error)
-		return result.ErrorResult[bool]("ゲーム起動に失敗しました", error.Error())
+		return result.ErrorResult[int]("ゲーム起動に失敗しました", error.Error())
 	}
-	return result.OkResult(true)
+	pid := command.Process.Pid
+	game, err := app.GameService.GetGameByExePath(app.context(), exePath)
+	if err != nil || game == nil {
+		app.recordActivity(domain.ActivityActionLaunch, "", "", filepath.Base(exePath)+" を起動")
+		return result.OkResult(pid)
+	}
+	app.recordActivity(domain.ActivityActionLaunch, domain.ChangeEntityGame, game.ID, "ゲーム「"+game.Title+"」を起動")
+	app.attachLaunchedGame(*game, pid)
+	return result.OkResult(pid)
+}
+
+// attachLaunchedGame は起動したゲームをプロセス監視に登録する。
+// 登録できなくても次のスキャンで実行ファイル名から検出されるため、起動自体は成功として扱う。
+func (app *App) attachLaunchedGame(game domain.Game, pid int) {
+	if app.ProcessMonitor == nil {
+		return
+	}
+	app.ProcessMonitor.AttachLaunchedGame(game, pid)
 }
 
-// CaptureGameScreenshot は指定されたゲームのスクリーンショットを保存する。
-func (app *App) CaptureGameScreenshot(gameID string) result.ApiResult[string] {
+// CaptureGameScreenshot は指定されたゲームのスクリーンショットを保存し、撮影結果（保存先・方式・サイズ・所要時間など）を返す。
+func (app *App) CaptureGameScreenshot(gameID string) result.ApiResult[domain.CaptureResult] {
 	if app.ScreenshotService == nil {
 		app.Logger.Warn("スクリーンショット機能が無効です", "operation", "CaptureGameScreenshot", "reason", "screenshot service is nil")
-		return result.ErrorResult[string]("スクリーンショット機能が無効です", "screenshot service is nil")
+		return result.ErrorResult[domain.CaptureResult]("スクリーンショット機能が無効です", "screenshot service is nil")
 	}
-	path, err := app.ScreenshotService.CaptureGameScreenshot(app.context(), strings.TrimSpace(gameID))
+	captured, err := app.ScreenshotService.CaptureGameScreenshot(app.context(), strings.TrimSpace(gameID))
 	if err != nil {
 		app.Logger.Error("スクリーンショット取得に失敗", "error", err)
-		return serviceErrorResult[string](err, "スクリーンショットの取得に失敗しました")
+		return serviceErrorResult[domain.CaptureResult](err, "スクリーンショットの取得に失敗しました")
 	}
+	app.copyCaptureToClipboard(captured.Path)
 	if app.Config.ScreenshotSyncEnabled {
-		if syncErr := app.uploadScreenshot(app.context(), strings.TrimSpace(gameID), path); syncErr != nil {
+		if syncErr := app.uploadScreenshot(app.context(), captured.GameID, captured.Path); syncErr != nil {
 			app.Logger.Error("スクリーンショット同期に失敗", "error", syncErr)
-			return result.ErrorResult[string]("スクリーンショットの同期に失敗しました", syncErr.Error())
+			return result.ErrorResult[domain.CaptureResult]("スクリーンショットの同期に失敗しました", syncErr.Error())
 		}
 	}
-	return result.OkResult(path)
+	return result.OkResult(captured)
 }
 
 func (app *App) uploadScreenshot(ctx context.Context, gameID string, filePath string) error {
@@ -773,8 +1123,12 @@ func openPath(path string) error {
 }
 
 // normalizePlayStatus はUIのフィルタ文字列をモデル値へ変換する。
+// "custom:<slug>" はカスタムステータスでの絞り込みとして扱う。
 func normalizePlayStatus(filter string) domain.PlayStatus {
 	value := strings.ToLower(strings.TrimSpace(filter))
+	if slug, ok := domain.PlayStatus(value).CustomStatusSlug(); ok {
+		return domain.CustomStatusFilter(strings.TrimSpace(slug))
+	}
 	switch value {
 	case "unplayed":
 		return domain.PlayStatusUnplayed
@@ -782,6 +1136,12 @@ func normalizePlayStatus(filter string) domain.PlayStatus {
 		return domain.PlayStatusPlaying
 	case "played":
 		return domain.PlayStatusPlayed
+	case "archived":
+		return domain.GameFilterArchived
+	case "all":
+		return domain.GameFilterAll
+	case "favorite":
+		return domain.GameFilterFavorite
 	default:
 		return ""
 	}
@@ -796,9 +1156,13 @@ func toCredentialOutput(credential *credentials.Credential, err error) result.Ap
 		return result.OkResult[*services.CredentialOutput](nil)
 	}
 	return result.OkResult(&services.CredentialOutput{
-		AccessKeyID: credential.AccessKeyID,
-		BucketName:  credential.BucketName,
-		Region:      credential.Region,
-		Endpoint:    credential.Endpoint,
+		AccessKeyID:             credential.AccessKeyID,
+		BucketName:              credential.BucketName,
+		Region:                  credential.Region,
+		Endpoint:                credential.Endpoint,
+		HasSessionToken:         credential.SessionToken != "",
+		RoleARN:                 credential.RoleARN,
+		RoleSessionName:         credential.RoleSessionName,
+		HasEncryptionPassphrase: credential.EncryptionPassphrase != "",
 	})
 }
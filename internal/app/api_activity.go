@@ -0,0 +1,22 @@
+// ユーザー操作の監査ログ（アクティビティログ）の記録と参照APIを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// ListActivityLog は作成・更新・削除・同期・起動の操作履歴を新しい順に1ページ分返す。
+// filter の空の項目は絞り込まない。entityType は game / session / route / memo / gameLink のいずれか。
+func (app *App) ListActivityLog(filter domain.ActivityFilter, page domain.PageRequest) result.ApiResult[domain.Page[domain.ActivityEntry]] {
+	entries, err := app.ActivityLogService.ListActivityLog(app.context(), filter, page)
+	return serviceResult(entries, err, "操作履歴の取得に失敗しました")
+}
+
+// recordActivity はユーザー操作をアクティビティログに残す。サービス未初期化のときは何もしない。
+func (app *App) recordActivity(action, entityType, entityID, summary string) {
+	if app.ActivityLogService == nil {
+		return
+	}
+	app.ActivityLogService.Record(app.context(), action, entityType, entityID, summary)
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/services"
+)
+
+func TestGameLinkAndArchiveOperationsAreRecordedInActivityLog(t *testing.T) {
+	t.Parallel()
+
+	app, repository := newMaintenanceTestApp(t)
+	game := createGameForTest(t, repository, domain.Game{Title: "テストゲーム", Publisher: "テスト", ExePath: "/games/test.exe", PlayStatus: domain.PlayStatusUnplayed})
+
+	created := app.CreateGameLink(services.GameLinkInput{GameID: game.ID, Label: "公式サイト", URL: "https://example.com"})
+	if !created.Success {
+		t.Fatalf("CreateGameLink failed: %#v", created.Error)
+	}
+	if deleted := app.DeleteGameLink(created.Data.ID); !deleted.Success {
+		t.Fatalf("DeleteGameLink failed: %#v", deleted.Error)
+	}
+	if archived := app.ArchiveGame(game.ID, false); !archived.Success {
+		t.Fatalf("ArchiveGame failed: %#v", archived.Error)
+	}
+
+	links := app.ListActivityLog(domain.ActivityFilter{EntityType: domain.ChangeEntityLink}, domain.PageRequest{Limit: 10})
+	if !links.Success || links.Data.Total != 2 {
+		t.Fatalf("expected create and delete link entries, got %#v", links)
+	}
+	games := app.ListActivityLog(domain.ActivityFilter{EntityType: domain.ChangeEntityGame, EntityID: game.ID}, domain.PageRequest{Limit: 10})
+	if !games.Success || games.Data.Total != 1 || games.Data.Items[0].Summary != "ゲーム「テストゲーム」をアーカイブ" {
+		t.Fatalf("expected the archive entry, got %#v", games)
+	}
+}
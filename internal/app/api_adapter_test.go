@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"strings"
@@ -26,6 +27,10 @@ func (r noopAppGameRepository) ListGames(ctx context.Context, searchText string,
 	return nil, r.listErr
 }
 
+func (r noopAppGameRepository) ListGamesPage(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string, page domain.PageRequest) ([]domain.Game, int, error) {
+	return nil, 0, r.listErr
+}
+
 func (r noopAppGameRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
 	return nil, nil
 }
@@ -44,6 +49,38 @@ func (r noopAppGameRepository) UpdateGame(ctx context.Context, game domain.Game)
 	return &game, nil
 }
 
+func (r noopAppGameRepository) SetGameArchived(ctx context.Context, gameID string, archivedAt *time.Time) error {
+	return nil
+}
+
+func (r noopAppGameRepository) SetGameLaunchWrapper(ctx context.Context, gameID string, wrapper *domain.LaunchWrapper) error {
+	return nil
+}
+
+func (r noopAppGameRepository) SetGameTrackingMode(ctx context.Context, gameID string, mode domain.TrackingMode) error {
+	return nil
+}
+
+func (r noopAppGameRepository) SetGameAutoTrackingExcluded(ctx context.Context, gameID string, excluded bool) error {
+	return nil
+}
+
+func (r noopAppGameRepository) SetGameProcessMatchPattern(ctx context.Context, gameID string, pattern *string) error {
+	return nil
+}
+
+func (r noopAppGameRepository) GetPlayStatusDefinition(ctx context.Context, slug string) (*domain.PlayStatusDefinition, error) {
+	return nil, nil
+}
+
+func (r noopAppGameRepository) ListRecentlyPlayed(ctx context.Context, limit int) ([]domain.RecentlyPlayedGame, error) {
+	return nil, nil
+}
+
+func (r noopAppGameRepository) GetGameByExePath(ctx context.Context, exePath string) (*domain.Game, error) {
+	return nil, nil
+}
+
 func (r noopAppGameRepository) DeleteGame(ctx context.Context, gameID string) error {
 	return r.deleteErr
 }
@@ -72,6 +109,9 @@ func (r noopAppSessionRepository) CreatePlaySession(ctx context.Context, session
 func (r noopAppSessionRepository) ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error) {
 	return nil, nil
 }
+func (r noopAppSessionRepository) ListPlaySessionsByGamePage(ctx context.Context, gameID string, page domain.PageRequest) ([]domain.PlaySession, int, error) {
+	return nil, 0, nil
+}
 func (r noopAppSessionRepository) GetPlaySessionByID(ctx context.Context, sessionID string) (*domain.PlaySession, error) {
 	if r.getErr != nil {
 		return nil, r.getErr
@@ -90,18 +130,38 @@ func (r noopAppSessionRepository) UpdatePlaySessionRoute(ctx context.Context, se
 func (r noopAppSessionRepository) UpdatePlaySessionName(ctx context.Context, sessionID string, sessionName string) error {
 	return r.updateErr
 }
+func (r noopAppSessionRepository) UpdatePlaySessionTiming(ctx context.Context, sessionID string, playedAt time.Time, duration int64) error {
+	return r.updateErr
+}
+func (r noopAppSessionRepository) UpdatePlaySessionNotes(ctx context.Context, sessionID string, notes string) error {
+	return r.updateErr
+}
 func (r noopAppSessionRepository) TouchGameUpdatedAt(ctx context.Context, gameID string) error {
 	return nil
 }
+func (r noopAppSessionRepository) ListPlayTimeBuckets(ctx context.Context, from, to time.Time) ([]domain.PlayTimeBucket, error) {
+	return nil, nil
+}
 func (r noopAppSessionRepository) SumPlaySessionDurationsByGame(ctx context.Context, gameID string) (int64, error) {
 	return 0, nil
 }
 func (r noopAppSessionRepository) UpdateGameTotalPlayTime(ctx context.Context, gameID string, totalPlayTime int64) error {
 	return nil
 }
+
+func (r noopAppSessionRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
+	return nil, nil
+}
+
 func (r noopAppSessionRepository) UpdateGameTotalPlayTimeWithLastPlayed(ctx context.Context, gameID string, totalPlayTime int64, playedAt time.Time) error {
 	return nil
 }
+func (r noopAppSessionRepository) SetGameAggregates(ctx context.Context, gameID string, totalPlayTime int64, lastPlayed *time.Time) error {
+	return nil
+}
+func (r noopAppSessionRepository) GetRouteStats(ctx context.Context, gameID string) ([]domain.RouteStat, error) {
+	return nil, nil
+}
 
 type noopAppRouteRepository struct {
 	listErr error
@@ -129,6 +189,9 @@ func (r noopAppRouteRepository) UpdateRouteOrders(ctx context.Context, gameID st
 func (r noopAppRouteRepository) GetRouteStats(ctx context.Context, gameID string) ([]domain.RouteStat, error) {
 	return nil, nil
 }
+func (r noopAppRouteRepository) ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error) {
+	return nil, nil
+}
 func (r noopAppRouteRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
 	return nil, nil
 }
@@ -166,6 +229,10 @@ func (noopAppMemoRepository) DeleteMemo(ctx context.Context, memoID string) erro
 	return nil
 }
 
+func (noopAppMemoRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
+	return nil, nil
+}
+
 type adapterTestCredentialStore struct {
 	loadResult *credentials.Credential
 	loadErr    error
@@ -183,6 +250,10 @@ func (store *adapterTestCredentialStore) Delete(ctx context.Context, key string)
 	return nil
 }
 
+func (store *adapterTestCredentialStore) List(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
 func newAdapterTestLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
@@ -468,3 +539,14 @@ func TestNormalizeDeletePrefixRejectsEmptyOrWildcard(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceErrorResultReportsCancellation(t *testing.T) {
+	t.Parallel()
+
+	for _, err := range []error{context.Canceled, fmt.Errorf("push: %w", context.Canceled)} {
+		res := serviceErrorResult[bool](err, "アップロードに失敗しました")
+		if res.Success || res.Error == nil || res.Error.Message != canceledMessage {
+			t.Fatalf("expected cancellation message for %v, got %#v", err, res.Error)
+		}
+	}
+}
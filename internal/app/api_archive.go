@@ -0,0 +1,43 @@
+// ゲームのアーカイブ・解除 API を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// ArchiveGame はゲームをアーカイブする。
+// アーカイブ中はセッション・メモを変更できず、プロセス監視と既定のゲーム一覧から外れる（検索では表示される）。
+// compressScreenshots が true ならローカルのスクリーンショットを zip にまとめる。
+// 圧縮に失敗してもアーカイブ自体は完了しているため、警告ログのみとする。
+func (app *App) ArchiveGame(gameID string, compressScreenshots bool) result.ApiResult[*domain.Game] {
+	game, err := app.GameService.ArchiveGame(app.context(), gameID)
+	if err != nil {
+		return serviceErrorResult[*domain.Game](err, "ゲームのアーカイブに失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityGame, game.ID, "ゲーム「"+game.Title+"」をアーカイブ")
+	app.reloadSaveFolderWatchAsync()
+	if compressScreenshots && app.ScreenshotService != nil {
+		if _, err := app.ScreenshotService.CompressGameScreenshots(game.ID); err != nil {
+			app.Logger.Warn("スクリーンショットの圧縮に失敗しました", "operation", "ArchiveGame", "gameId", game.ID, "error", err)
+		}
+	}
+	return result.OkResult(game)
+}
+
+// UnarchiveGame はゲームのアーカイブを解除し、圧縮済みのスクリーンショットがあれば展開する。
+// 展開に失敗しても解除自体は保存済みのため、ArchiveGame の圧縮と同じく警告ログのみとする。
+func (app *App) UnarchiveGame(gameID string) result.ApiResult[*domain.Game] {
+	game, err := app.GameService.UnarchiveGame(app.context(), gameID)
+	if err != nil {
+		return serviceErrorResult[*domain.Game](err, "ゲームのアーカイブ解除に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityGame, game.ID, "ゲーム「"+game.Title+"」のアーカイブを解除")
+	app.reloadSaveFolderWatchAsync()
+	if app.ScreenshotService != nil {
+		if _, err := app.ScreenshotService.RestoreGameScreenshots(game.ID); err != nil {
+			app.Logger.Warn("スクリーンショットの展開に失敗しました", "operation", "UnarchiveGame", "gameId", game.ID, "error", err)
+		}
+	}
+	return result.OkResult(game)
+}
@@ -0,0 +1,22 @@
+// プレイ中の定期スクリーンショットのゲームごとの設定APIを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// GetGameAutoScreenshots は定期スクリーンショットを有効にしたゲームの設定（ゲームID → 設定）を返す。
+// 含まれないゲームは自動撮影しない。
+func (app *App) GetGameAutoScreenshots() result.ApiResult[map[string]domain.AutoScreenshotSetting] {
+	settings, err := app.AutoScreenshotService.ListGameSettings(app.context())
+	return serviceResult(settings, err, "定期スクリーンショット設定の取得に失敗しました")
+}
+
+// SetGameAutoScreenshot はゲームの定期スクリーンショットを設定する。setting が null なら無効にする。
+// 有効なゲームはプレイ中、ウィンドウが前面にあるときだけ intervalMinutes 分ごとに撮影し、
+// 自動撮影分が maxCount 枚を超えたら古いものから削除する（手動で撮った分は消さない）。
+func (app *App) SetGameAutoScreenshot(gameID string, setting *domain.AutoScreenshotSetting) result.ApiResult[bool] {
+	err := app.AutoScreenshotService.SetGameSetting(app.context(), gameID, setting)
+	return boolResult(err, "定期スクリーンショット設定の保存に失敗しました")
+}
@@ -0,0 +1,88 @@
+// ゲームプレイの動画クリップの保存・一覧・削除と、録画の設定APIを提供する。
+package app
+
+import (
+	"strings"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// clipSavedEvent は動画クリップを保存したときにフロントエンドへ送るイベント名。
+const clipSavedEvent = "clip:saved"
+
+// UpdateClipBufferSeconds はプレイ中に録画しておく直近の秒数を更新する。0 で録画しない。
+func (app *App) UpdateClipBufferSeconds(seconds int) result.ApiResult[bool] {
+	if err := services.ValidateClipBufferSeconds(seconds); err != nil {
+		app.Logger.Warn("クリップの秒数が不正です", "operation", "UpdateClipBufferSeconds", "value", seconds)
+		return result.ErrorResult[bool]("クリップの秒数が不正です", err.Error())
+	}
+	app.Config.ClipBufferSeconds = seconds
+	if app.ClipService != nil {
+		app.ClipService.SetBufferSeconds(seconds)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateClipFFmpegPath は録画に使う ffmpeg のパスを更新する。空なら実行ファイルと同じフォルダ、PATH の順に探す。
+func (app *App) UpdateClipFFmpegPath(path string) result.ApiResult[bool] {
+	trimmed := strings.TrimSpace(path)
+	app.Config.ClipFFmpegPath = trimmed
+	if app.ClipService != nil {
+		app.ClipService.SetFFmpegPath(trimmed)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// GetClipRecorderStatus は録画の状態（録画中のゲームと秒数）を返す。
+func (app *App) GetClipRecorderStatus() result.ApiResult[domain.ClipRecorderStatus] {
+	if app.ClipService == nil {
+		return result.ErrorResult[domain.ClipRecorderStatus]("クリップの録画を利用できません", "clip service is not initialized")
+	}
+	return result.OkResult(app.ClipService.Status())
+}
+
+// SaveClip は録画中の直近の映像を clips/<gameID> に mp4 で保存する。
+func (app *App) SaveClip() result.ApiResult[domain.ClipInfo] {
+	if app.ClipService == nil {
+		return result.ErrorResult[domain.ClipInfo]("クリップの録画を利用できません", "clip service is not initialized")
+	}
+	clip, err := app.ClipService.SaveClip(app.context())
+	if err != nil {
+		app.Logger.Warn("クリップの保存に失敗", "error", err)
+		return serviceErrorResult[domain.ClipInfo](err, "クリップの保存に失敗しました")
+	}
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, clipSavedEvent, clip)
+	}
+	return result.OkResult(clip)
+}
+
+// ListGameClips はゲームの保存済みクリップを新しい順に返す。
+func (app *App) ListGameClips(gameID string) result.ApiResult[[]domain.ClipInfo] {
+	clips, err := app.ClipService.ListGameClips(gameID)
+	return serviceResult(clips, err, "クリップ一覧の取得に失敗しました")
+}
+
+// DeleteGameClip はゲームの保存済みクリップを1件削除する。name は ListGameClips の name。
+func (app *App) DeleteGameClip(gameID string, name string) result.ApiResult[bool] {
+	err := app.ClipService.DeleteGameClip(gameID, name)
+	return boolResult(err, "クリップの削除に失敗しました")
+}
+
+// handleHotkeySaveClip は録画中の直近の映像をクリップとして保存する。
+func (app *App) handleHotkeySaveClip() (string, bool) {
+	if app.ClipService == nil || !app.ClipService.Status().Recording {
+		app.Logger.Info("ホットキーの対象になる録画がありません", "action", services.HotkeyActionSaveClip)
+		return "", false
+	}
+	if saved := app.SaveClip(); !saved.Success {
+		return "", false
+	}
+	return "クリップを保存しました", true
+}
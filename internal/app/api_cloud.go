@@ -8,7 +8,6 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -126,11 +125,7 @@ func (app *App) GetGameDirectoryNode(gameID string) result.ApiResult[CloudDirect
 		// HEAD 未設定でも UI が空ツリーを描けるよう、子なしゲームノードを返す。
 		return result.OkResult(CloudDirectoryNode{Name: trimmed, Path: trimmed, IsDirectory: true, Children: []CloudDirectoryNode{}})
 	}
-	node := buildGameDirectoryNode(*view)
-	if node.Children == nil {
-		node.Children = []CloudDirectoryNode{}
-	}
-	return result.OkResult(node)
+	return result.OkResult(buildGameDirectoryNode(*view))
 }
 
 // GetDirectoryTree はクラウドのディレクトリツリー（ゲーム単位の論理ビュー）を取得する。
@@ -148,76 +143,6 @@ func (app *App) GetDirectoryTree() result.ApiResult[[]CloudDirectoryNode] {
 	return result.OkResult(nodes)
 }
 
-// buildGameDirectoryNode は1ゲームの論理ファイル一覧から階層ディレクトリツリーを構築する。
-// トップノードはゲーム（IsDirectory=true, Path=gameID）で、配下にセーブファイルの階層を持つ。
-func buildGameDirectoryNode(view services.CloudGameView) CloudDirectoryNode {
-	root := &dirBuilder{
-		node:     CloudDirectoryNode{Name: view.Title, Path: view.GameID, IsDirectory: true, LastModified: view.LastModified},
-		children: map[string]*dirBuilder{},
-	}
-	for _, f := range view.Files {
-		segments := strings.Split(f.RelPath, "/")
-		cur := root
-		curPath := view.GameID
-		for idx, seg := range segments {
-			if seg == "" {
-				continue
-			}
-			curPath = curPath + "/" + seg
-			isLeaf := idx == len(segments)-1
-			child, ok := cur.children[seg]
-			if !ok {
-				child = &dirBuilder{
-					node: CloudDirectoryNode{
-						Name:        seg,
-						Path:        curPath,
-						IsDirectory: !isLeaf,
-					},
-					children: map[string]*dirBuilder{},
-				}
-				cur.children[seg] = child
-			}
-			if isLeaf {
-				child.node.IsDirectory = false
-				child.node.Size = f.Size
-				child.node.LastModified = view.LastModified
-			}
-			cur = child
-		}
-	}
-	return finalizeDirBuilder(root)
-}
-
-type dirBuilder struct {
-	node     CloudDirectoryNode
-	children map[string]*dirBuilder
-}
-
-// finalizeDirBuilder は dirBuilder を CloudDirectoryNode へ変換する。
-// ディレクトリノードの Size には子の合計サイズを集約する（ObjectKey は設定しない）。
-func finalizeDirBuilder(b *dirBuilder) CloudDirectoryNode {
-	if len(b.children) == 0 {
-		return b.node
-	}
-	names := make([]string, 0, len(b.children))
-	for name := range b.children {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-	children := make([]CloudDirectoryNode, 0, len(names))
-	var total int64
-	for _, name := range names {
-		c := finalizeDirBuilder(b.children[name])
-		total += c.Size
-		children = append(children, c)
-	}
-	b.node.Children = children
-	if b.node.IsDirectory {
-		b.node.Size = total
-	}
-	return b.node
-}
-
 // DeleteCloudData は指定パス配下を削除する。
 func (app *App) DeleteCloudData(path string) result.ApiResult[bool] {
 	exactKey, childPrefix, ok := normalizeDeletePrefix(path)
@@ -258,6 +183,103 @@ func (app *App) DeleteFile(key string) result.ApiResult[bool] {
 	return result.OkResult(true)
 }
 
+// PreviewCloudObject はオブジェクトの先頭 maxBytes バイトだけを取得し、種別に応じて整形して返す。
+// maxBytes が 0 以下なら既定値、上限を超える場合は上限に丸める。
+// 暗号化されたオブジェクトは全体を取得して復号してから整形する（大きすぎる場合は断る）。
+func (app *App) PreviewCloudObject(key string, maxBytes int) result.ApiResult[services.CloudObjectPreview] {
+	trimmed := strings.TrimSpace(key)
+	if trimmed == "" {
+		return result.ErrorResult[services.CloudObjectPreview]("プレビュー対象のファイルが不正です", "preview object key is empty")
+	}
+	limit := services.ClampCloudObjectPreviewBytes(maxBytes)
+
+	ctx := app.context()
+	client, bucket, err := app.getDefaultS3Client(ctx)
+	if err != nil {
+		return errorResultWithLog[services.CloudObjectPreview](app, "プレビューの取得に失敗しました", err, "operation", "PreviewCloudObject.getDefaultS3Client")
+	}
+	data, totalSize, contentType, err := storage.DownloadObjectHead(ctx, client, bucket, trimmed, int64(limit))
+	if err != nil {
+		return errorResultWithLog[services.CloudObjectPreview](app, "プレビューの取得に失敗しました", err, "operation", "PreviewCloudObject.downloadObjectHead", "key", trimmed)
+	}
+	if storage.IsEncryptedEnvelope(data) {
+		if totalSize > services.MaxEncryptedCloudObjectPreviewBytes {
+			return result.ErrorResult[services.CloudObjectPreview]("暗号化されたファイルが大きすぎるためプレビューできません", trimmed)
+		}
+		plaintext, err := app.ContentSyncService.ReadCloudObject(ctx, trimmed)
+		if errors.Is(err, storage.ErrEncryptionPassphraseRequired) {
+			return result.ErrorResult[services.CloudObjectPreview]("暗号化されたファイルはパスフレーズを設定するとプレビューできます", err.Error())
+		}
+		if err != nil {
+			return errorResultWithLog[services.CloudObjectPreview](app, "暗号化されたファイルを復号できません", err, "operation", "PreviewCloudObject.ReadCloudObject", "key", trimmed)
+		}
+		data, totalSize = plaintext[:min(len(plaintext), limit)], int64(len(plaintext))
+	}
+	return result.OkResult(services.BuildCloudObjectPreview(trimmed, data, totalSize, contentType))
+}
+
+// DownloadCloudFile は単一オブジェクトを targetDir へダウンロードし、保存したファイルのパスを返す。
+// ファイル名はキーの末尾から作り、同名のファイルがあれば連番を付けて上書きしない。
+func (app *App) DownloadCloudFile(key string, targetDir string) result.ApiResult[string] {
+	trimmed := strings.TrimSpace(key)
+	if trimmed == "" {
+		return result.ErrorResult[string]("ダウンロード対象のファイルが不正です", "download object key is empty")
+	}
+	dir := strings.TrimSpace(targetDir)
+	if dir == "" {
+		return result.ErrorResult[string]("保存先のフォルダが不正です", "target directory is empty")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return result.ErrorResult[string]("保存先のフォルダが見つかりません", dir)
+	}
+	localPath, err := app.downloadCloudFileTo(trimmed, dir)
+	if errors.Is(err, context.Canceled) {
+		return serviceErrorResult[string](err, "ダウンロードに失敗しました")
+	}
+	if err != nil {
+		return errorResultWithLog[string](app, "ダウンロードに失敗しました", err, "operation", "DownloadCloudFile", "key", trimmed)
+	}
+	return result.OkResult(localPath)
+}
+
+// OpenCloudFileLocally は単一オブジェクトを一時フォルダへダウンロードし、関連付けられたアプリで開く。
+// 開いたファイルのパスを返す。一時フォルダのファイルは OS の一時ファイルの掃除に任せる。
+func (app *App) OpenCloudFileLocally(key string) result.ApiResult[string] {
+	trimmed := strings.TrimSpace(key)
+	if trimmed == "" {
+		return result.ErrorResult[string]("開くファイルが不正です", "open object key is empty")
+	}
+	dir := filepath.Join(os.TempDir(), services.CloudFileOpenDirName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return errorResultWithLog[string](app, "ファイルを開くのに失敗しました", err, "operation", "OpenCloudFileLocally.mkdir", "dir", dir)
+	}
+	localPath, err := app.downloadCloudFileTo(trimmed, dir)
+	if errors.Is(err, context.Canceled) {
+		return serviceErrorResult[string](err, "ファイルを開くのに失敗しました")
+	}
+	if err != nil {
+		return errorResultWithLog[string](app, "ファイルを開くのに失敗しました", err, "operation", "OpenCloudFileLocally.download", "key", trimmed)
+	}
+	if err := openPath(localPath); err != nil {
+		return errorResultWithLog[string](app, "ファイルを開くのに失敗しました", err, "operation", "OpenCloudFileLocally.open", "path", localPath)
+	}
+	return result.OkResult(localPath)
+}
+
+// downloadCloudFileTo はオブジェクトを dir へ重複しない名前でダウンロードし、保存先のパスを返す。
+// 設定中のストレージバックエンドから取得し、暗号化されていれば復号してから書き出す。
+// ダウンロード中は処理一覧に載り、CancelOperation で中断できる。
+func (app *App) downloadCloudFileTo(key string, dir string) (string, error) {
+	ctx, op := app.Operations.Begin(app.context(), services.OperationDownloadFile, "")
+	defer op.Finish()
+	localPath, written, err := app.ContentSyncService.DownloadCloudObject(ctx, key, dir)
+	if err != nil {
+		return "", err
+	}
+	app.Logger.Info("クラウドのファイルをダウンロード", "key", key, "path", localPath, "bytes", written)
+	return localPath, nil
+}
+
 // GetCloudFileDetails はプレフィックス（先頭セグメント=gameID、残り=サブパス）配下の
 // 論理セーブファイル詳細を取得する。互換のため戻り型は []CloudFileDetail を維持する。
 func (app *App) GetCloudFileDetails(prefix string) result.ApiResult[[]CloudFileDetail] {
@@ -336,9 +358,18 @@ func (app *App) LoadImageFromLocal(filePath string) result.ApiResult[string] {
 	return result.OkResult("data:" + mime + ";base64," + encoded)
 }
 
+// ListStorageProviderPresets は認証情報設定で選べる S3 互換ストレージのプリセットを返す。
+func (app *App) ListStorageProviderPresets() result.ApiResult[[]services.StorageProviderPreset] {
+	return result.OkResult(services.StorageProviderPresets())
+}
+
 // ValidateCredential は認証情報の検証を行う。
+// バケットへの到達に加えて、小さな一時オブジェクトの書き込み・削除ができるかも確かめる。
 func (app *App) ValidateCredential(input CredentialValidationInput) result.ApiResult[bool] {
 	ctx := app.context()
+	if error := storage.ValidateEndpoint(input.Endpoint); error != nil {
+		return result.ErrorResult[bool]("エンドポイントが不正です", error.Error())
+	}
 	cfg := storage.S3Config{
 		Endpoint:       input.Endpoint,
 		Region:         input.Region,
@@ -346,24 +377,30 @@ func (app *App) ValidateCredential(input CredentialValidationInput) result.ApiRe
 		ForcePathStyle: app.Config.S3ForcePathStyle,
 		UseTLS:         app.Config.S3UseTLS,
 	}
+	if input.ForcePathStyle != nil {
+		cfg.ForcePathStyle = *input.ForcePathStyle
+	}
+	if input.UseTLS != nil {
+		cfg.UseTLS = *input.UseTLS
+	}
 	client, error := storage.NewClient(ctx, cfg, credentials.Credential{
 		AccessKeyID:     input.AccessKeyID,
 		SecretAccessKey: input.SecretAccessKey,
+		SessionToken:    input.SessionToken,
+		RoleARN:         input.RoleARN,
+		RoleSessionName: input.RoleSessionName,
+		ExternalID:      input.ExternalID,
 	})
 	if error != nil {
 		return errorResultWithLog[bool](app, "認証情報検証に失敗しました", error, "operation", "ValidateCredential.newClient", "bucket", cfg.Bucket)
 	}
-	_, error = client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &cfg.Bucket})
-	if error != nil {
-		return errorResultWithLog[bool](app, "認証情報検証に失敗しました", error, "operation", "ValidateCredential.headBucket", "bucket", cfg.Bucket)
-	}
-	return result.OkResult(true)
+	return app.verifyBucketAccess(ctx, client, cfg.Bucket, "ValidateCredential")
 }
 
 // ValidateSavedCredential は保存済み認証情報の検証を行う。
 func (app *App) ValidateSavedCredential(key string) result.ApiResult[bool] {
 	ctx := app.context()
-	cfg, credential, error := app.resolveS3Config(ctx)
+	cfg, credential, error := app.resolveS3ConfigForKey(ctx, util.FirstNonEmpty(key, app.Config.CredentialKey))
 	if error != nil {
 		return errorResultWithLog[bool](app, "認証情報検証に失敗しました", error, "operation", "ValidateSavedCredential.resolveS3Config")
 	}
@@ -371,9 +408,16 @@ func (app *App) ValidateSavedCredential(key string) result.ApiResult[bool] {
 	if error != nil {
 		return errorResultWithLog[bool](app, "認証情報検証に失敗しました", error, "operation", "ValidateSavedCredential.newClient", "bucket", cfg.Bucket)
 	}
-	_, error = client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &cfg.Bucket})
-	if error != nil {
-		return errorResultWithLog[bool](app, "認証情報検証に失敗しました", error, "operation", "ValidateSavedCredential.headBucket", "bucket", cfg.Bucket)
+	return app.verifyBucketAccess(ctx, client, cfg.Bucket, "ValidateSavedCredential")
+}
+
+// verifyBucketAccess はバケットへの到達（HeadBucket）と書き込み・削除の権限を確かめる。
+func (app *App) verifyBucketAccess(ctx context.Context, client *s3.Client, bucket string, operation string) result.ApiResult[bool] {
+	if error := storage.HeadBucket(ctx, client, bucket); error != nil {
+		return errorResultWithLog[bool](app, "認証情報検証に失敗しました", error, "operation", operation+".headBucket", "bucket", bucket)
+	}
+	if error := storage.ProbeWriteAccess(ctx, client, bucket); error != nil {
+		return errorResultWithLog[bool](app, "バケットへの書き込み権限がありません", error, "operation", operation+".probeWrite", "bucket", bucket)
 	}
 	return result.OkResult(true)
 }
@@ -385,6 +429,13 @@ type CredentialValidationInput struct {
 	Endpoint        string `json:"endpoint"`
 	AccessKeyID     string `json:"accessKeyId"`
 	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken"`
+	RoleARN         string `json:"roleArn"`
+	RoleSessionName string `json:"roleSessionName"`
+	ExternalID      string `json:"externalId"`
+	// ForcePathStyle / UseTLS はプリセットの値で試すときに指定する。nil ならアプリ設定の値を使う。
+	ForcePathStyle *bool `json:"forcePathStyle,omitempty"`
+	UseTLS         *bool `json:"useTls,omitempty"`
 }
 
 func (app *App) getDefaultS3Client(ctx context.Context) (*s3.Client, string, error) {
@@ -400,7 +451,11 @@ func (app *App) getDefaultS3Client(ctx context.Context) (*s3.Client, string, err
 }
 
 func (app *App) resolveS3Config(ctx context.Context) (storage.S3Config, credentials.Credential, error) {
-	credential, err := app.CredentialService.LoadCredential(ctx, "default")
+	return app.resolveS3ConfigForKey(ctx, util.FirstNonEmpty(app.Config.CredentialKey, "default"))
+}
+
+func (app *App) resolveS3ConfigForKey(ctx context.Context, key string) (storage.S3Config, credentials.Credential, error) {
+	credential, err := app.CredentialService.LoadCredential(ctx, key)
 	if err != nil || credential == nil {
 		return storage.S3Config{}, credentials.Credential{}, errors.New("認証情報がありません")
 	}
@@ -0,0 +1,49 @@
+// クラウド整合性チェック（週次ジョブ・同期ログ・一括修復）関連の API を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// cloudConsistencyEvent は週次チェックで修復可能な問題が見つかったときにフロントエンドへ送るイベント名。
+const cloudConsistencyEvent = "cloud:consistency"
+
+// CheckCloudConsistency はすぐにクラウド整合性チェックを実行し、結果を同期ログへ記録して返す。
+func (app *App) CheckCloudConsistency() result.ApiResult[domain.CloudConsistencyReport] {
+	if app.isOffline() {
+		return serviceErrorResult[domain.CloudConsistencyReport](services.ErrOffline, "クラウド整合性チェックに失敗しました")
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationCloudCheck, "")
+	defer op.Finish()
+	report, err := app.ContentSyncService.CheckCloudConsistency(ctx)
+	return serviceResult(report, err, "クラウド整合性チェックに失敗しました")
+}
+
+// RepairCloudConsistency は修復可能な問題を持つゲームをローカルから push し直す（通知の「修復」ボタン用）。
+func (app *App) RepairCloudConsistency() result.ApiResult[domain.CloudConsistencyReport] {
+	if app.isOffline() {
+		return serviceErrorResult[domain.CloudConsistencyReport](services.ErrOffline, "クラウド整合性の修復に失敗しました")
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationCloudRepair, "")
+	defer op.Finish()
+	report, err := app.ContentSyncService.RepairCloudConsistency(ctx)
+	return serviceResult(report, err, "クラウド整合性の修復に失敗しました")
+}
+
+// GetCloudConsistencyLog は記録済みの整合性チェック結果を新しい順に返す。
+func (app *App) GetCloudConsistencyLog() result.ApiResult[[]domain.CloudConsistencyReport] {
+	reports, err := app.ContentSyncService.CloudConsistencyLog(app.context())
+	return serviceResult(reports, err, "同期ログの取得に失敗しました")
+}
+
+// handleCloudConsistencyReport は週次チェックで見つかった修復可能な問題をフロントエンドへ通知する。
+func (app *App) handleCloudConsistencyReport(report domain.CloudConsistencyReport) {
+	app.Logger.Warn("クラウドに修復可能な不整合があります", "games", len(report.RepairableGameIDs()), "problems", len(report.Problems))
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, cloudConsistencyEvent, report)
+	}
+}
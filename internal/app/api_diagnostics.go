@@ -0,0 +1,87 @@
+// 不具合報告に貼り付ける診断情報を集める API を提供する。
+package app
+
+import (
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// GetDiagnostics はアプリのバージョン・DB スキーマとサイズ・データフォルダ・認証情報の有無・クラウドへの到達状況・
+// 補助ツールの配置・ホットキーとプロセス監視の状態をまとめて返す。秘密情報は含めない。
+// 取得できなかった項目は errors に理由を入れ、他の項目はそのまま返す。
+func (app *App) GetDiagnostics() result.ApiResult[services.Diagnostics] {
+	ctx := app.context()
+	executableDir := config.ExecutableDir()
+	diagnostics := services.Diagnostics{
+		AppVersion:  config.Version,
+		GoVersion:   runtime.Version(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		GeneratedAt: time.Now(),
+		Paths: services.DiagnosticsPaths{
+			AppDataDir:    app.Config.AppDataDir,
+			DatabasePath:  app.Config.DatabasePath,
+			LogDir:        filepath.Join(app.Config.AppDataDir, "logs"),
+			ExecutableDir: executableDir,
+		},
+		Helpers: services.DiagnoseHelpers(executableDir),
+		Storage: services.DiagnosticsStorage{
+			Backend: services.NormalizeStorageBackend(app.Config.StorageBackend),
+			Network: services.NetworkStatus{Online: true},
+			Offline: app.isOffline(),
+		},
+		Errors: []string{},
+	}
+	addError := func(item string, err error) {
+		diagnostics.Errors = append(diagnostics.Errors, item+": "+err.Error())
+	}
+
+	if schema, err := app.MaintenanceService.GetSchemaVersion(ctx); err != nil {
+		addError("schema", err)
+	} else {
+		diagnostics.Schema = schema
+	}
+	if database, err := services.DiagnoseDatabaseFile(app.Config.DatabasePath); err != nil {
+		addError("database", err)
+	} else {
+		diagnostics.Database = database
+	}
+	if keys, err := app.CredentialService.DiagnoseCredentials(ctx, app.Config); err != nil {
+		addError("credentials", err)
+	} else {
+		diagnostics.Credentials = keys
+	}
+	if app.GoogleDriveService != nil {
+		if status, err := app.GoogleDriveService.Status(ctx); err != nil {
+			addError("googleDrive", err)
+		} else {
+			diagnostics.GoogleDrive = status
+		}
+	}
+	if app.NetworkMonitor != nil {
+		// 直近の結果ではなくその場で確かめる。S3 以外の保存先では確認せず、直近の状態を返す。
+		diagnostics.Storage.Network = app.NetworkMonitor.CheckNow(ctx)
+	}
+
+	app.hotkeyMu.Lock()
+	diagnostics.Hotkey = services.DiagnosticsHotkey{Combo: app.Config.ScreenshotHotkey, Registered: app.HotkeyService != nil}
+	app.hotkeyMu.Unlock()
+
+	diagnostics.Monitor.AutoTracking = app.autoTracking
+	if app.ProcessMonitor != nil {
+		diagnostics.Monitor.Running = app.ProcessMonitor.IsMonitoring()
+		statuses := app.ProcessMonitor.GetMonitoringStatus()
+		diagnostics.Monitor.TrackedGames = len(statuses)
+		for _, status := range statuses {
+			if status.IsPlaying {
+				diagnostics.Monitor.ActiveSessions++
+			}
+		}
+	}
+	return result.OkResult(diagnostics)
+}
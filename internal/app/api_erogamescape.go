@@ -1,4 +1,4 @@
-// 批評空間からのゲーム情報取得APIを提供する。
+// 批評空間からのゲーム情報取得と、そのページキャッシュの設定APIを提供する。
 package app
 
 import (
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
 )
 
 // FetchFromErogameScape は批評空間URLからゲーム情報を取得する。
@@ -35,3 +37,58 @@ func (app *App) SearchErogameScape(query string, pageURL string) (domain.Erogame
 	}
 	return result, nil
 }
+
+// UpdateErogameScapeCacheTTL は批評空間の検索結果・ゲームページを再取得せずに使う分数を更新する。0 でキャッシュしない。
+func (app *App) UpdateErogameScapeCacheTTL(minutes int) result.ApiResult[bool] {
+	if err := services.ValidateErogameScapeCacheTTL(minutes); err != nil {
+		app.Logger.Warn("キャッシュの有効期間が不正です", "operation", "UpdateErogameScapeCacheTTL", "value", minutes)
+		return result.ErrorResult[bool]("キャッシュの有効期間が不正です", err.Error())
+	}
+	app.Config.ErogameScapeCacheTTLMinutes = minutes
+	if app.ErogameScapeService != nil {
+		app.ErogameScapeService.SetCacheTTL(minutes)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// PurgeErogameScapeCache は保存済みの批評空間のページキャッシュを削除し、削除した件数を返す。
+func (app *App) PurgeErogameScapeCache() result.ApiResult[int] {
+	if app.ErogameScapeService == nil {
+		return result.ErrorResult[int]("批評空間サービスが未初期化です", "ErogameScapeService is nil")
+	}
+	removed, err := app.ErogameScapeService.PurgeCache()
+	return serviceResult(removed, err, "キャッシュの削除に失敗しました")
+}
+
+// MatchGamesWithErogameScape は gameIDs のゲームをタイトルで批評空間と照合する。
+// 確度の高い候補はタイトル・ブランド・（未設定なら）画像に反映し、決められなかったゲームは候補を返す。
+// 進捗は "sync:progress"（operation=metadataMatch）でゲーム単位に通知し、CancelOperation で中断できる。
+// 中断した場合はそこまでの結果を canceled 付きで返す。
+func (app *App) MatchGamesWithErogameScape(gameIDs []string) result.ApiResult[domain.ErogameScapeMatchResult] {
+	if app.ErogameScapeMatch == nil {
+		return result.ErrorResult[domain.ErogameScapeMatchResult]("批評空間サービスが未初期化です", "ErogameScapeMatch is nil")
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationMetadataMatch, "")
+	defer op.Finish()
+	matched, err := app.ErogameScapeMatch.MatchGames(ctx, gameIDs, countProgressEmitter(ctx, op))
+	if err == nil {
+		for _, applied := range matched.Applied {
+			app.syncGameAsync(applied.GameID)
+		}
+	}
+	return serviceResult(matched, err, "批評空間との照合に失敗しました")
+}
+
+// ApplyErogameScapeMatch は MatchGamesWithErogameScape の候補から手動で選んだ作品をゲームに反映する。
+func (app *App) ApplyErogameScapeMatch(gameID string, gamePageURL string) result.ApiResult[*domain.Game] {
+	if app.ErogameScapeMatch == nil {
+		return result.ErrorResult[*domain.Game]("批評空間サービスが未初期化です", "ErogameScapeMatch is nil")
+	}
+	updated, err := app.ErogameScapeMatch.ApplyMatch(app.context(), gameID, gamePageURL)
+	if err != nil {
+		return serviceErrorResult[*domain.Game](err, "批評空間の情報の反映に失敗しました")
+	}
+	app.syncGameAsync(updated.ID)
+	return result.OkResult(updated)
+}
@@ -0,0 +1,59 @@
+// ゲームの外部リンク関連のAPIを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ListGameLinks はゲームのリンク一覧を取得する。
+func (app *App) ListGameLinks(gameID string) result.ApiResult[[]domain.GameLink] {
+	links, err := app.GameLinkService.ListGameLinksByGame(app.context(), gameID)
+	return serviceResult(links, err, "リンク取得に失敗しました")
+}
+
+// CreateGameLink はリンクを追加する。
+func (app *App) CreateGameLink(input services.GameLinkInput) result.ApiResult[*domain.GameLink] {
+	created, err := app.GameLinkService.CreateGameLink(app.context(), input)
+	if err != nil {
+		return serviceErrorResult[*domain.GameLink](err, "リンク作成に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionCreate, domain.ChangeEntityLink, created.ID, "リンク「"+created.Label+"」を追加")
+	app.syncGameAsync(created.GameID)
+	return result.OkResult(created)
+}
+
+// UpdateGameLink はリンクを更新する。
+func (app *App) UpdateGameLink(linkID string, input services.GameLinkUpdateInput) result.ApiResult[*domain.GameLink] {
+	updated, err := app.GameLinkService.UpdateGameLink(app.context(), linkID, input)
+	if err != nil {
+		return serviceErrorResult[*domain.GameLink](err, "リンク更新に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionUpdate, domain.ChangeEntityLink, updated.ID, "リンク「"+updated.Label+"」を更新")
+	app.syncGameAsync(updated.GameID)
+	return result.OkResult(updated)
+}
+
+// DeleteGameLink はリンクを削除する。
+func (app *App) DeleteGameLink(linkID string) result.ApiResult[bool] {
+	deleted, err := app.GameLinkService.DeleteGameLink(app.context(), linkID)
+	if err != nil {
+		return serviceErrorResult[bool](err, "リンク削除に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionDelete, domain.ChangeEntityLink, linkID, "リンク「"+deleted.Label+"」を削除")
+	app.syncGameAsync(deleted.GameID)
+	return result.OkResult(true)
+}
+
+// OpenGameLink はリンクを既定のブラウザで開く。
+func (app *App) OpenGameLink(linkID string) result.ApiResult[bool] {
+	linkURL, err := app.GameLinkService.ResolveGameLinkURL(app.context(), linkID)
+	if err != nil {
+		return serviceErrorResult[bool](err, "リンクを開けませんでした")
+	}
+	wailsruntime.BrowserOpenURL(app.context(), linkURL)
+	return result.OkResult(true)
+}
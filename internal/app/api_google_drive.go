@@ -0,0 +1,82 @@
+// Google ドライブへの接続（OAuth デバイス認可フロー）の API を提供する。
+package app
+
+import (
+	"context"
+	"errors"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/logging"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// googleDriveAuthEvent は Google ドライブの認可待ちが終わったときにフロントエンドへ送るイベント名。
+// ペイロードは googleDriveAuthResult。
+const googleDriveAuthEvent = "gdrive:auth"
+
+// googleDriveAuthResult は認可待ちの結果。失敗時は Connected=false で Error に理由を入れる。
+type googleDriveAuthResult struct {
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"`
+}
+
+// googleDriveCredentialNamespace は Google ドライブのリフレッシュトークンを保存する名前空間。
+// ストレージの認証情報と分け、ListCredentialKeys の一覧に混ざらないようにする。
+func googleDriveCredentialNamespace(cfg config.Config) string {
+	return cfg.CredentialNamespace + "-gdrive"
+}
+
+// GetGoogleDriveStatus は Google ドライブへの接続状態を返す。
+func (app *App) GetGoogleDriveStatus() result.ApiResult[services.GoogleDriveStatus] {
+	if app.GoogleDriveService == nil {
+		return result.ErrorResult[services.GoogleDriveStatus]("Google ドライブの接続が未初期化です", "GoogleDriveService is nil")
+	}
+	status, err := app.GoogleDriveService.Status(app.context())
+	return serviceResult(status, err, "Google ドライブの接続状態を取得できません")
+}
+
+// StartGoogleDriveAuth は Google ドライブへの接続を始め、ユーザーに入力してもらうコードを返す。
+// 認可ページをブラウザで開き、バックグラウンドで認可を待って結果を googleDriveAuthEvent で通知する。
+func (app *App) StartGoogleDriveAuth() result.ApiResult[services.GoogleDriveDeviceCode] {
+	if app.GoogleDriveService == nil {
+		return result.ErrorResult[services.GoogleDriveDeviceCode]("Google ドライブの接続が未初期化です", "GoogleDriveService is nil")
+	}
+	code, err := app.GoogleDriveService.StartAuthorization(app.context())
+	if err != nil {
+		return serviceErrorResult[services.GoogleDriveDeviceCode](err, "Google ドライブへの接続を開始できません")
+	}
+	if app.ctx != nil && code.VerificationURL != "" {
+		wailsruntime.BrowserOpenURL(app.ctx, code.VerificationURL)
+	}
+	go app.waitGoogleDriveAuth()
+	return result.OkResult(code)
+}
+
+// waitGoogleDriveAuth は認可の完了を待ってフロントエンドへ通知する。接続をやり直して打ち切られた待機は通知しない。
+func (app *App) waitGoogleDriveAuth() {
+	defer logging.Recover(app.Logger, "app.waitGoogleDriveAuth")
+	err := app.GoogleDriveService.CompleteAuthorization(app.context())
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	payload := googleDriveAuthResult{Connected: err == nil}
+	if err != nil {
+		app.Logger.Warn("Google ドライブに接続できません", "error", err)
+		payload.Error = err.Error()
+	}
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, googleDriveAuthEvent, payload)
+	}
+}
+
+// DisconnectGoogleDrive は保存した Google ドライブの接続情報を削除する。
+// 保存先が Google ドライブのままなら、再接続するまで同期は失敗する。
+func (app *App) DisconnectGoogleDrive() result.ApiResult[bool] {
+	if app.GoogleDriveService == nil {
+		return result.ErrorResult[bool]("Google ドライブの接続が未初期化です", "GoogleDriveService is nil")
+	}
+	return boolResult(app.GoogleDriveService.Disconnect(app.context()), "Google ドライブの接続を解除できません")
+}
@@ -2,6 +2,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -9,6 +10,9 @@ import (
 	"CloudLaunch_Go/internal/services"
 )
 
+// canceledMessage はキャンセルした処理の API が返すメッセージ。
+const canceledMessage = "処理をキャンセルしました"
+
 func errorResultWithLog[T any](app *App, message string, err error, attrs ...any) result.ApiResult[T] {
 	if app != nil && app.Logger != nil {
 		logAttrs := make([]any, 0, len(attrs)+2)
@@ -23,6 +27,10 @@ func serviceErrorResult[T any](err error, fallbackMessage string) result.ApiResu
 	if err == nil {
 		return result.ErrorResult[T](fallbackMessage, "不明なエラーです")
 	}
+	// CancelOperation でキャンセルした処理は失敗ではなく、キャンセルしたことを返す。
+	if errors.Is(err, context.Canceled) {
+		return result.ErrorResult[T](canceledMessage, err.Error())
+	}
 	serviceErr := &services.ServiceError{}
 	if errors.As(err, &serviceErr) {
 		message := serviceErr.Message
@@ -0,0 +1,120 @@
+// スクリーンショット以外のホットキー（セッションの中断・再開、終了、今すぐ同期、クリップ保存）の設定と処理を提供する。
+package app
+
+import (
+	"strings"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// UpdateHotkeyActions は操作（pauseResume / endSession / syncNow / saveClip）ごとのホットキーを更新する。
+// 空のキーの組み合わせはその操作の割り当てを外す。ホットキーは登録し直し、失敗したら元の割り当てに戻す。
+func (app *App) UpdateHotkeyActions(actions config.HotkeyActions) result.ApiResult[bool] {
+	normalized, err := services.NormalizeHotkeyActions(actions, app.Config.ScreenshotHotkey)
+	if err != nil {
+		app.Logger.Warn("ホットキーが不正です", "operation", "UpdateHotkeyActions", "error", err)
+		return result.ErrorResult[bool]("ホットキーが不正です", err.Error())
+	}
+	if app.Config.HotkeyActions == normalized {
+		return result.OkResult(true)
+	}
+	prev := app.Config.HotkeyActions
+	app.Config.HotkeyActions = normalized
+	changed := app.applyHotkeyChange("UpdateHotkeyActions", "ホットキーの更新に失敗しました",
+		func() { app.Config.HotkeyActions = prev })
+	if changed.Success {
+		app.persistSettings()
+	}
+	return changed
+}
+
+// hotkeyBindings は設定された割り当てから、ホットキーサービスに登録する処理の一覧を作る。
+func (app *App) hotkeyBindings() []services.HotkeyBinding {
+	handlers := map[string]services.HotkeyActionHandler{
+		services.HotkeyActionPauseResume: app.handleHotkeyPauseResume,
+		services.HotkeyActionEndSession:  app.handleHotkeyEndSession,
+		services.HotkeyActionSyncNow:     app.handleHotkeySyncNow,
+		services.HotkeyActionSaveClip:    app.handleHotkeySaveClip,
+	}
+	bindings := make([]services.HotkeyBinding, 0, len(services.HotkeyActionNames))
+	for _, action := range services.HotkeyActionNames {
+		combo := strings.TrimSpace(services.HotkeyActionCombo(app.Config.HotkeyActions, action))
+		if combo == "" {
+			continue
+		}
+		bindings = append(bindings, services.HotkeyBinding{Action: action, Combo: combo, Handler: handlers[action]})
+	}
+	return bindings
+}
+
+// hotkeySessionTarget はホットキーで操作するセッションのゲームIDとタイトル、中断中かを返す。
+// 撮影と同じく「現在プレイ中」を優先し、なければ「中断中」のゲームを対象にする。
+func (app *App) hotkeySessionTarget() (string, string, bool, bool) {
+	if app.ProcessMonitor == nil {
+		return "", "", false, false
+	}
+	gameID := app.ProcessMonitor.GetHotkeyTargetGameID()
+	if gameID == "" {
+		return "", "", false, false
+	}
+	for _, status := range app.ProcessMonitor.GetMonitoringStatus() {
+		if status.GameID == gameID {
+			return gameID, status.GameTitle, status.IsPaused, true
+		}
+	}
+	return "", "", false, false
+}
+
+// handleHotkeyPauseResume は現在のセッションが中断中なら再開し、計測中なら中断する。
+func (app *App) handleHotkeyPauseResume() (string, bool) {
+	gameID, title, paused, ok := app.hotkeySessionTarget()
+	if !ok {
+		app.Logger.Info("ホットキーの対象になるセッションがありません", "action", services.HotkeyActionPauseResume)
+		return "", false
+	}
+	if paused {
+		if resumed := app.ResumeMonitoringSession(gameID); !resumed.Success {
+			return "", false
+		}
+		return "「" + title + "」の計測を再開しました", true
+	}
+	if paused := app.PauseMonitoringSession(gameID); !paused.Success {
+		return "", false
+	}
+	return "「" + title + "」の計測を中断しました", true
+}
+
+// handleHotkeyEndSession は現在のセッションを終了して保存する。
+func (app *App) handleHotkeyEndSession() (string, bool) {
+	gameID, title, _, ok := app.hotkeySessionTarget()
+	if !ok {
+		app.Logger.Info("ホットキーの対象になるセッションがありません", "action", services.HotkeyActionEndSession)
+		return "", false
+	}
+	if ended := app.EndMonitoringSession(gameID); !ended.Success {
+		return "", false
+	}
+	return "「" + title + "」のセッションを終了しました", true
+}
+
+// handleHotkeySyncNow は現在のゲーム（無ければ直近に遊んだゲーム）をクラウドへアップロードする。
+func (app *App) handleHotkeySyncNow() (string, bool) {
+	if app.ProcessMonitor == nil {
+		return "", false
+	}
+	gameID := app.ProcessMonitor.GetHotkeyTargetGameID()
+	if gameID == "" {
+		gameID, _ = app.ProcessMonitor.LastTrackedGame()
+	}
+	if gameID == "" {
+		app.Logger.Info("ホットキーの対象になるゲームがありません", "action", services.HotkeyActionSyncNow)
+		return "", false
+	}
+	if pushed := app.PushSync(gameID); !pushed.Success {
+		app.Logger.Warn("ホットキーからの同期に失敗しました", "gameId", gameID, "error", pushed.Error)
+		return "", false
+	}
+	return "クラウドへアップロードしました", true
+}
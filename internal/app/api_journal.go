@@ -0,0 +1,14 @@
+// ローカル変更履歴の参照APIを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// GetChangeHistory はエンティティの変更履歴（フィールド単位の差分と操作名）を新しい順に返す。
+// entityType は game / session / route / memo のいずれか。
+func (app *App) GetChangeHistory(entityType string, entityID string) result.ApiResult[[]domain.ChangeEntry] {
+	entries, err := app.ChangeJournalService.GetChangeHistory(app.context(), entityType, entityID)
+	return serviceResult(entries, err, "変更履歴の取得に失敗しました")
+}
@@ -0,0 +1,37 @@
+// API の結果メッセージの表示言語の切り替えと、フロントエンド向けのメッセージカタログを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/i18n"
+	"CloudLaunch_Go/internal/result"
+)
+
+// UpdateLanguage は API の結果メッセージの表示言語（"ja" か "en"）を切り替える。
+func (app *App) UpdateLanguage(language string) result.ApiResult[bool] {
+	normalized, err := i18n.NormalizeLanguage(language)
+	if err != nil {
+		app.Logger.Warn("表示言語が不正です", "operation", "UpdateLanguage", "value", language)
+		return result.ErrorResult[bool]("表示言語が不正です", err.Error())
+	}
+	app.Config.Language = normalized
+	if err := i18n.SetLanguage(normalized); err != nil {
+		return result.ErrorResult[bool]("表示言語が不正です", err.Error())
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// ListLanguages は選べる表示言語のコードを返す。
+func (app *App) ListLanguages() result.ApiResult[[]string] {
+	return serviceResult(i18n.SupportedLanguages(), nil, "表示言語の取得に失敗しました")
+}
+
+// GetMessageCatalog は language（空なら現在の表示言語）のメッセージカタログ（コード → 文言）を返す。
+// フロントエンドは ApiError.Code やイベントのコードからこのカタログで文言を引く。
+func (app *App) GetMessageCatalog(language string) result.ApiResult[map[string]string] {
+	catalog, err := i18n.Catalog(language)
+	if err != nil {
+		return result.ErrorResult[map[string]string]("表示言語が不正です", err.Error())
+	}
+	return result.OkResult(catalog)
+}
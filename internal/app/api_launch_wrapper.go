@@ -0,0 +1,20 @@
+// ゲーム起動ラッパー（ロケールエミュレーター等）の設定 API を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// SetGameLaunchWrapper はゲームの起動ラッパーを設定する。wrapper が nil かパスが空なら解除して直接起動に戻す。
+// 設定は端末ローカルで、クラウド同期の対象にはならない。
+func (app *App) SetGameLaunchWrapper(gameID string, wrapper *domain.LaunchWrapper) result.ApiResult[*domain.Game] {
+	game, err := app.GameService.SetLaunchWrapper(app.context(), gameID, wrapper)
+	return serviceResult(game, err, "起動ラッパーの設定に失敗しました")
+}
+
+// DetectLaunchWrappers は端末にインストールされている既知のラッパーと既定の引数テンプレートを返す。
+func (app *App) DetectLaunchWrappers() result.ApiResult[[]domain.LaunchWrapperCandidate] {
+	return result.OkResult(services.DetectLaunchWrappers())
+}
@@ -0,0 +1,33 @@
+// バックエンドのログファイルを画面から参照する API を提供する。
+package app
+
+import (
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/logging"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// QueryLogs は app.log / error.log（ローテーション済みの世代を含む）から、level 以上・since 以降で
+// text を含むログを新しい順に返す。level が空なら全レベル、since がゼロ値なら期間で絞り込まない。
+func (app *App) QueryLogs(level string, since time.Time, text string) result.ApiResult[[]logging.LogEntry] {
+	if strings.TrimSpace(level) != "" {
+		normalized, ok := services.NormalizeLogLevel(level)
+		if !ok {
+			app.Logger.Warn("ログレベルが不正です", "operation", "QueryLogs", "level", level)
+			return result.ErrorResult[[]logging.LogEntry]("ログレベルが不正です", "level must be debug|info|warn|error")
+		}
+		level = normalized
+	}
+	if app.Config.AppDataDir == "" {
+		app.Logger.Warn("ログディレクトリが不明です", "operation", "QueryLogs", "reason", "AppDataDir is empty")
+		return result.ErrorResult[[]logging.LogEntry]("ログディレクトリが不明です", "AppDataDirが空です")
+	}
+	entries, err := logging.QueryLogs(app.Config.AppDataDir, logging.LogQuery{Level: level, Since: since, Text: text})
+	if err != nil {
+		return errorResultWithLog[[]logging.LogEntry](app, "ログの取得に失敗しました", err, "operation", "QueryLogs")
+	}
+	return result.OkResult(entries)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"CloudLaunch_Go/internal/domain"
 	"CloudLaunch_Go/internal/infrastructure/db"
 	"CloudLaunch_Go/internal/result"
 	"CloudLaunch_Go/internal/services"
@@ -31,6 +32,26 @@ func (app *App) RestoreFullBackup(backupPath string) result.ApiResult[bool] {
 	return result.OkResult(true)
 }
 
+// CheckDatabaseIntegrity は DB の整合性チェック（integrity_check / foreign_key_check / 孤立データ）を行う。
+// repair が true なら、ゲームの無いセッション・メモ・ルート・リンクを削除し、存在しないルートへの参照を外す。
+func (app *App) CheckDatabaseIntegrity(repair bool) result.ApiResult[domain.DatabaseIntegrityReport] {
+	report, err := app.MaintenanceService.CheckDatabaseIntegrity(app.context(), repair)
+	return serviceResult(report, err, "整合性チェックに失敗しました")
+}
+
+// GetSchemaVersion は DB のスキーマバージョン（適用済み・未適用・このアプリが知らないマイグレーション）を返す。
+func (app *App) GetSchemaVersion() result.ApiResult[domain.SchemaVersion] {
+	version, err := app.MaintenanceService.GetSchemaVersion(app.context())
+	return serviceResult(version, err, "スキーマバージョンの取得に失敗しました")
+}
+
+// CleanupOrphanedAssets は DB から参照されていないサムネイル・スクリーンショット・メモファイルを種類ごとのサイズつきで返す。
+// deleteFiles が true なら見つけたファイルを削除する（false なら検出のみ）。
+func (app *App) CleanupOrphanedAssets(deleteFiles bool) result.ApiResult[domain.OrphanedAssetReport] {
+	report, err := app.MaintenanceService.CleanupOrphanedAssets(app.context(), deleteFiles)
+	return serviceResult(report, err, "未使用ファイルの整理に失敗しました")
+}
+
 func (app *App) createDatabaseSnapshot(destinationPath string) error {
 	_ = os.Remove(destinationPath)
 	if app.dbConnection == nil {
@@ -41,6 +62,10 @@ func (app *App) createDatabaseSnapshot(destinationPath string) error {
 	if _, err := app.dbConnection.Exec(statement); err == nil {
 		return nil
 	}
+	// ファイルをそのままコピーする場合は、WAL に残っている内容を先に DB ファイルへ書き戻す。
+	if err := db.Checkpoint(app.dbConnection); err != nil {
+		app.Logger.Warn("WAL のチェックポイントに失敗しました", "error", err)
+	}
 	return services.CopyFilePath(app.Config.DatabasePath, destinationPath)
 }
 
@@ -61,20 +86,40 @@ func (app *App) stopRuntimeServicesForRestore() {
 		app.isMonitoring = false
 	}
 	app.stopHotkey()
+	if app.WebhookService != nil {
+		// 送信は設定を DB から読むため、DB を閉じる前に終わるのを待つ。
+		app.WebhookService.Close()
+		app.WebhookService.Wait()
+	}
+	// スキャンから始めた撮影・録画やプレイ後の同期の goroutine が終わるのを待ってから各サービスを閉じる。
+	app.backgroundTasks.Close()
+	app.backgroundTasks.Wait()
 	if app.ScreenshotService != nil {
 		_ = app.ScreenshotService.Close()
 	}
+	if app.ClipService != nil {
+		app.ClipService.Close()
+	}
 	// 同期 goroutine が古い DB 接続を触ったまま Close → 「database is closed」 panic
 	// になるのを防ぐため、DB を閉じる前に in-flight な Push を確実に静止させる。
-	// reopenDatabaseAndServices が新しい coalescer に差し替えるため、ここで停止した
+	// reopenDatabaseAndServices が新しい coalescer・BackgroundTasks に差し替えるため、ここで停止した
 	// インスタンスは以後使われない。
 	if app.syncCoalescer != nil {
 		app.syncCoalescer.stop()
 	}
+	if app.CloudConsistencyJob != nil {
+		app.CloudConsistencyJob.Stop()
+	}
+	if app.MemoFileWatcher != nil {
+		app.MemoFileWatcher.Stop()
+	}
+	if app.SaveFolderWatcher != nil {
+		app.SaveFolderWatcher.Stop()
+	}
 }
 
 func (app *App) reopenDatabaseAndServices() error {
-	connection, err := db.Open(app.Config.DatabasePath)
+	connection, err := db.OpenWithOptions(app.Config.DatabasePath, databaseOptions(app.Config))
 	if err != nil {
 		return err
 	}
@@ -86,13 +131,14 @@ func (app *App) reopenDatabaseAndServices() error {
 	app.dbConnection = connection
 	repository := db.NewRepository(connection)
 	credentialStore := newCredentialStore(app.Config)
+	app.loadPersistedSettings(repository)
 	app.configureServices(repository, credentialStore)
 	return nil
 }
 
 func (app *App) resumeRuntimeServicesAfterRestore() error {
 	if app.ProcessMonitor != nil {
-		app.ProcessMonitor.StartMonitoring()
+		app.ProcessMonitor.StartMonitoring(app.context())
 		if !app.autoTracking {
 			app.ProcessMonitor.UpdateAutoTracking(false)
 		}
@@ -103,5 +149,15 @@ func (app *App) resumeRuntimeServicesAfterRestore() error {
 	if err := app.startHotkey(); err != nil {
 		app.Logger.Warn("復元後のホットキー開始に失敗しました（復元自体は成功）", "error", err)
 	}
+	if app.CloudConsistencyJob != nil && app.ctx != nil {
+		app.CloudConsistencyJob.Start(app.ctx)
+	}
+	if app.WebhookService != nil && app.ctx != nil {
+		app.WebhookService.Start(app.ctx, app.eventBus)
+	}
+	if app.ctx != nil {
+		app.startMemoFileWatcher()
+		app.startSaveFolderWatcher()
+	}
 	return nil
 }
@@ -2,6 +2,9 @@
 package app
 
 import (
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
 	"CloudLaunch_Go/internal/result"
 	"CloudLaunch_Go/internal/services"
 )
@@ -19,7 +22,12 @@ func (app *App) DownloadMemoFromCloud(gameID string, memoFileName string) result
 }
 
 // UploadMemoToCloud はメモをクラウドへ保存する。
+// オフラインモード中はアップロードせず送信待ちに追加し、オンライン復帰後に再送する。
 func (app *App) UploadMemoToCloud(memoID string) result.ApiResult[bool] {
+	if app.isOffline() {
+		app.enqueueOfflineSync(domain.SyncQueueKindMemoUpload, memoID)
+		return serviceErrorResult[bool](services.ErrOffline, "メモのアップロードに失敗しました")
+	}
 	if err := app.MemoCloudService.UploadMemoToCloud(app.context(), memoID); err != nil {
 		return serviceErrorResult[bool](err, "メモのアップロードに失敗しました")
 	}
@@ -28,6 +36,8 @@ func (app *App) UploadMemoToCloud(memoID string) result.ApiResult[bool] {
 
 // SyncMemosFromCloud はメモをクラウドと同期する。
 func (app *App) SyncMemosFromCloud(gameID string) result.ApiResult[services.MemoSyncResult] {
-	summary, err := app.MemoCloudService.SyncMemosFromCloud(app.context(), gameID)
+	ctx, op := app.Operations.Begin(app.context(), services.OperationMemoSync, strings.TrimSpace(gameID))
+	defer op.Finish()
+	summary, err := app.MemoCloudService.SyncMemosFromCloud(ctx, gameID)
 	return serviceResult(summary, err, "メモ同期に失敗しました")
 }
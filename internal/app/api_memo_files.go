@@ -1,11 +1,18 @@
-// メモファイル関連APIを提供する。
+// メモファイル関連APIと、メモファイルの外部編集の反映を提供する。
 package app
 
 import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/logging"
 	"CloudLaunch_Go/internal/memo"
 	"CloudLaunch_Go/internal/result"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// memoFileEditedEvent はメモファイルの外部編集を DB に反映したときにフロントエンドへ送るイベント名。
+const memoFileEditedEvent = "memo:externalEdit"
+
 // GetMemoRootDir はメモのルートディレクトリを返す。
 func (app *App) GetMemoRootDir() result.ApiResult[string] {
 	manager := app.memoManager()
@@ -38,3 +45,36 @@ func (app *App) memoManager() *memo.FileManager {
 	}
 	return memo.NewFileManager(app.Config.AppDataDir)
 }
+
+// UpdateMemoExternalEditUpload はメモファイルの外部編集を反映したとき、クラウドへもアップロードするかを更新する。
+func (app *App) UpdateMemoExternalEditUpload(enabled bool) result.ApiResult[bool] {
+	app.Config.MemoExternalEditUpload = enabled
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// startMemoFileWatcher はメモファイルの監視を開始する。監視できなくてもメモ機能自体は使えるため警告に留める。
+func (app *App) startMemoFileWatcher() {
+	if app.MemoFileWatcher == nil {
+		return
+	}
+	if err := app.MemoFileWatcher.Start(app.context()); err != nil {
+		app.Logger.Warn("メモファイルの監視を開始できません", "error", err)
+	}
+}
+
+// handleMemoFileEdited は外部編集を反映したメモをフロントエンドへ通知し、設定に応じてクラウドへアップロードする。
+func (app *App) handleMemoFileEdited(updated domain.Memo) {
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, memoFileEditedEvent, updated)
+	}
+	if !app.Config.MemoExternalEditUpload || app.MemoCloudService == nil || app.isOffline() {
+		return
+	}
+	app.backgroundTasks.Go(func() {
+		defer logging.Recover(app.Logger, "app.uploadEditedMemo")
+		if err := app.MemoCloudService.UploadMemoToCloud(app.context(), updated.ID); err != nil {
+			app.Logger.Warn("外部編集したメモのアップロードに失敗", "memoId", updated.ID, "error", err)
+		}
+	})
+}
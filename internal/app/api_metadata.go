@@ -0,0 +1,49 @@
+// 取り込み元（批評空間・DLSite・DMM・IGDB・Steam）を選んでゲーム情報を取得するAPIを提供する。
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// metadataCredentialNamespace は取り込み元の API キーを保存する名前空間。
+// ストレージの認証情報と分け、ListCredentialKeys の一覧に混ざらないようにする。
+func metadataCredentialNamespace(cfg config.Config) string {
+	return cfg.CredentialNamespace + "-metadata"
+}
+
+// ListMetadataProviders はゲーム情報の取り込み元を表示順に返す。
+func (app *App) ListMetadataProviders() []domain.MetadataProviderInfo {
+	if app.MetadataService == nil {
+		return []domain.MetadataProviderInfo{}
+	}
+	return app.MetadataService.Providers()
+}
+
+// FetchGameMetadata は providerID（erogamescape|dlsite|dmm|igdb|steam）の取り込み元で作品ページの URL からゲーム情報を取得する。
+// providerID が空なら URL から取り込み元を判定する。カバー画像はサムネイルとして保存し、そのパスを返す。
+func (app *App) FetchGameMetadata(providerID string, pageURL string) (domain.GameImport, error) {
+	if app.MetadataService == nil {
+		app.Logger.Error("ゲーム情報の取り込みサービスが未初期化です", "operation", "FetchGameMetadata")
+		return domain.GameImport{}, errors.New("MetadataService is not initialized")
+	}
+	imported, err := app.MetadataService.Fetch(app.context(), providerID, pageURL)
+	if err != nil {
+		app.Logger.Error("ゲーム情報の取得に失敗しました", "operation", "FetchGameMetadata", "provider", strings.TrimSpace(providerID), "url", strings.TrimSpace(pageURL), "error", err)
+		return domain.GameImport{}, err
+	}
+	return imported, nil
+}
+
+// SetMetadataProviderCredential は API キーが必要な取り込み元（IGDB）のクライアント ID とシークレットを保存する。
+// どちらかを空にすると保存済みのキーを削除する。シークレットは保存後に読み出せない。
+func (app *App) SetMetadataProviderCredential(providerID string, clientID string, clientSecret string) result.ApiResult[bool] {
+	if app.MetadataService == nil {
+		return result.ErrorResult[bool]("ゲーム情報の取り込みサービスが未初期化です", "MetadataService is nil")
+	}
+	return boolResult(app.MetadataService.SetProviderCredential(app.context(), providerID, clientID, clientSecret), "API キーの設定に失敗しました")
+}
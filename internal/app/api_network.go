@@ -0,0 +1,48 @@
+// クラウドへの接続状態（自動オフライン判定）関連の API を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// networkStatusEvent は接続状態が切り替わったときにフロントエンドへ送るイベント名。
+const networkStatusEvent = "network:status"
+
+// GetNetworkStatus は NetworkMonitor が最後に判定した接続状態を返す。
+func (app *App) GetNetworkStatus() result.ApiResult[services.NetworkStatus] {
+	if app.NetworkMonitor == nil {
+		return result.OkResult(services.NetworkStatus{Online: true})
+	}
+	return result.OkResult(app.NetworkMonitor.Status())
+}
+
+// CheckNetworkStatus はすぐに疎通を確認し、その結果を返す（オフラインバナーの「再確認」用）。
+func (app *App) CheckNetworkStatus() result.ApiResult[services.NetworkStatus] {
+	if app.NetworkMonitor == nil {
+		return result.OkResult(services.NetworkStatus{Online: true})
+	}
+	return result.OkResult(app.NetworkMonitor.CheckNow(app.context()))
+}
+
+// isOffline はユーザー設定または接続断の自動判定によりクラウド操作を控えるべきかを返す。
+func (app *App) isOffline() bool {
+	return app.offlineMode || app.autoOffline.Load()
+}
+
+// handleNetworkStatus は NetworkMonitor の状態変化を ContentSyncService とフロントエンドへ反映する。
+// 接続が戻ったときはオフライン中に溜まった送信待ちを再送する。
+func (app *App) handleNetworkStatus(status services.NetworkStatus) {
+	app.autoOffline.Store(!status.Online)
+	if app.ContentSyncService != nil {
+		app.ContentSyncService.SetOfflineMode(app.isOffline())
+	}
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, networkStatusEvent, status)
+	}
+	if status.Online {
+		app.replaySyncQueueAsync()
+	}
+}
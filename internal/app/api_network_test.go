@@ -0,0 +1,35 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/services"
+)
+
+func TestHandleNetworkStatusKeepsOfflineUntilReachable(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	app := &App{
+		Logger:             logger,
+		ContentSyncService: services.NewContentSyncService(config.Config{}, nil, nil, logger),
+	}
+
+	app.handleNetworkStatus(services.NetworkStatus{Online: false})
+	if !app.isOffline() || !app.ContentSyncService.IsOffline() {
+		t.Fatal("expected unreachable cloud to switch into offline mode")
+	}
+	// 手動のオフラインを解除しても、接続断の間はオフラインのまま。
+	app.UpdateOfflineMode(false)
+	if !app.ContentSyncService.IsOffline() {
+		t.Fatal("expected auto offline to survive manual toggle")
+	}
+
+	app.handleNetworkStatus(services.NetworkStatus{Online: true})
+	if app.isOffline() || app.ContentSyncService.IsOffline() {
+		t.Fatal("expected reconnect to leave offline mode")
+	}
+}
@@ -0,0 +1,71 @@
+// 初回セットアップ（オンボーディング）の進み具合を返す API を提供する。
+package app
+
+import (
+	"context"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+	"CloudLaunch_Go/internal/util"
+)
+
+// GetOnboardingState は初回セットアップの手順（同期先の設定・最初のゲームの登録・最初の同期）の完了状況を返す。
+// 同期先とゲームの登録は現在の状態からも判定し、満たしていれば完了として記録する。
+func (app *App) GetOnboardingState() result.ApiResult[domain.OnboardingState] {
+	ctx := app.context()
+	state, err := app.OnboardingService.CompleteSteps(ctx, app.detectOnboardingSteps(ctx)...)
+	return serviceResult(state, err, "初回セットアップの状態の取得に失敗しました")
+}
+
+// CompleteOnboardingStep は手順を完了として記録する（案内のスキップなど）。step は credentials / firstGame / firstSync のいずれか。
+func (app *App) CompleteOnboardingStep(step string) result.ApiResult[domain.OnboardingState] {
+	state, err := app.OnboardingService.CompleteSteps(app.context(), step)
+	return serviceResult(state, err, "初回セットアップの状態の保存に失敗しました")
+}
+
+// markOnboardingStep は操作の成功に合わせて手順を完了にする。記録できなくても元の操作は成功として扱う。
+func (app *App) markOnboardingStep(step string) {
+	if app.OnboardingService == nil {
+		return
+	}
+	if _, err := app.OnboardingService.CompleteSteps(app.context(), step); err != nil {
+		app.Logger.Warn("初回セットアップの状態の保存に失敗", "step", step, "error", err)
+	}
+}
+
+// detectOnboardingSteps は現在の設定・ライブラリから完了とみなせる手順を返す。
+func (app *App) detectOnboardingSteps(ctx context.Context) []string {
+	steps := make([]string, 0, 2)
+	if app.syncTargetConfigured(ctx) {
+		steps = append(steps, domain.OnboardingStepCredentials)
+	}
+	if app.GameService != nil {
+		page, err := app.GameService.ListGamesPage(ctx, "", domain.GameFilterAll, "", "", domain.PageRequest{Limit: 1})
+		if err == nil && page.Total > 0 {
+			steps = append(steps, domain.OnboardingStepFirstGame)
+		}
+	}
+	return steps
+}
+
+// syncTargetConfigured は選択中の同期先が使える状態まで設定されているかを返す。
+func (app *App) syncTargetConfigured(ctx context.Context) bool {
+	switch services.NormalizeStorageBackend(app.Config.StorageBackend) {
+	case services.StorageBackendLocal:
+		return strings.TrimSpace(app.Config.LocalStorageDir) != ""
+	case services.StorageBackendGoogleDrive:
+		if app.GoogleDriveService == nil {
+			return false
+		}
+		status, err := app.GoogleDriveService.Status(ctx)
+		return err == nil && status.Connected
+	default:
+		if app.CredentialService == nil {
+			return false
+		}
+		credential, err := app.CredentialService.LoadCredential(ctx, util.FirstNonEmpty(app.Config.CredentialKey, "default"))
+		return err == nil && credential != nil && credential.AccessKeyID != "" && credential.SecretAccessKey != ""
+	}
+}
@@ -0,0 +1,78 @@
+// 実行中の長時間処理（全体のアクティビティ表示・キャンセル）関連の API を提供する。
+package app
+
+import (
+	"context"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// operationsChangedEvent は長時間処理の開始・終了・キャンセル要求時にフロントエンドへ送るイベント名。
+// 進捗そのものは従来どおり "sync:progress" で通知する。
+const operationsChangedEvent = "operations:changed"
+
+// GetActiveOperations は実行中の同期・転送・一括処理を、進捗と残り時間つきで開始順に返す。
+func (app *App) GetActiveOperations() result.ApiResult[[]domain.ActiveOperation] {
+	return result.OkResult(app.Operations.List())
+}
+
+// CancelOperation は GetActiveOperations の ID で指定した処理をキャンセルする。
+// 既に終了していた場合は false を返す。処理は中断できる地点で止まり、呼び出し元の API はエラーを返す。
+func (app *App) CancelOperation(operationID string) result.ApiResult[bool] {
+	trimmed := strings.TrimSpace(operationID)
+	if trimmed == "" {
+		return result.ErrorResult[bool]("処理IDが不正です", "operationID is empty")
+	}
+	canceled := app.Operations.Cancel(trimmed)
+	if canceled {
+		app.Logger.Info("処理のキャンセルを要求", "operationId", trimmed)
+	}
+	return result.OkResult(canceled)
+}
+
+// handleOperationsChanged は実行中の処理一覧の変化をフロントエンドへ通知する。
+func (app *App) handleOperationsChanged(operations []domain.ActiveOperation) {
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, operationsChangedEvent, operations)
+	}
+}
+
+// countProgressEmitter は件数単位の進捗を Operation に記録し、残り時間を付けて "sync:progress" で通知するコールバックを返す。
+func countProgressEmitter(ctx context.Context, op *services.Operation) services.ProgressFunc {
+	return func(current, total int) {
+		emitOperationProgress(ctx, op.ReportCount(current, total), false)
+	}
+}
+
+// transferProgressEmitter はセーブ転送の進捗を Operation に記録し、"sync:progress" で通知するコールバックを返す。
+// current / total はファイル数（従来の進捗バー用）で、バイト数・残り時間・処理中のファイル名も同じイベントに載せる。
+func transferProgressEmitter(ctx context.Context, op *services.Operation) services.TransferProgressFunc {
+	return func(progress domain.TransferProgress) {
+		emitOperationProgress(ctx, op.ReportTransfer(progress), true)
+	}
+}
+
+// emitOperationProgress は進捗を "sync:progress" で通知する。transfer はバイト数・ファイル名を載せるか。
+func emitOperationProgress(ctx context.Context, state domain.ActiveOperation, transfer bool) {
+	payload := map[string]any{
+		"operation":   state.Kind,
+		"operationId": state.ID,
+		"current":     state.Current,
+		"total":       state.Total,
+		"etaSeconds":  state.EtaSeconds,
+	}
+	if state.GameID != "" {
+		payload["gameId"] = state.GameID
+	}
+	if transfer {
+		payload["currentFile"] = state.CurrentFile
+		payload["bytesDone"] = state.BytesDone
+		payload["bytesTotal"] = state.BytesTotal
+	}
+	wailsruntime.EventsEmit(ctx, "sync:progress", payload)
+}
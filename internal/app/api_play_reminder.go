@@ -0,0 +1,50 @@
+// プレイ時間のリマインダー通知の設定APIと、プロセス監視のスキャン通知の受け口を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// UpdatePlayReminderMinutes は全ゲーム共通のリマインダー間隔（分）を更新する。0 で無効。
+func (app *App) UpdatePlayReminderMinutes(minutes int) result.ApiResult[bool] {
+	if err := services.ValidatePlayReminderMinutes(minutes); err != nil {
+		app.Logger.Warn("リマインダー間隔が不正です", "operation", "UpdatePlayReminderMinutes", "value", minutes)
+		return result.ErrorResult[bool]("リマインダー間隔が不正です", err.Error())
+	}
+	app.Config.PlayReminderMinutes = minutes
+	if app.PlayReminderService != nil {
+		app.PlayReminderService.SetDefaultMinutes(minutes)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// GetGamePlayReminders はゲームごとのリマインダー間隔（ゲームID → 分、0 は通知しない）を返す。
+// 含まれないゲームは全体の設定（playReminderMinutes）に従う。
+func (app *App) GetGamePlayReminders() result.ApiResult[map[string]int] {
+	reminders, err := app.PlayReminderService.ListGameReminders(app.context())
+	return serviceResult(reminders, err, "リマインダー設定の取得に失敗しました")
+}
+
+// SetGamePlayReminder はゲームのリマインダー間隔を設定する。minutes が null なら全体の設定に戻し、0 なら通知しない。
+func (app *App) SetGamePlayReminder(gameID string, minutes *int) result.ApiResult[bool] {
+	err := app.PlayReminderService.SetGameReminder(app.context(), gameID, minutes)
+	return boolResult(err, "リマインダー設定の保存に失敗しました")
+}
+
+// handleMonitorScan はプロセス監視のスキャンごとに、累計プレイ時間からリマインダーの通知と定期スクリーンショットを行い、
+// プレイ中のゲームに合わせてクリップの録画を始めたり止めたりする。
+// 撮影や録画の停止は時間がかかるため、監視のループを止めないよう別ゴルーチンで行う。
+func (app *App) handleMonitorScan(statuses []domain.MonitoringGameStatus) {
+	if app.PlayReminderService != nil {
+		app.PlayReminderService.Check(app.context(), statuses)
+	}
+	if app.AutoScreenshotService != nil {
+		app.backgroundTasks.Go(func() { app.AutoScreenshotService.Check(app.context(), statuses) })
+	}
+	if app.ClipService != nil {
+		app.backgroundTasks.Go(func() { app.ClipService.Update(app.context(), statuses) })
+	}
+}
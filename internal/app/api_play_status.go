@@ -0,0 +1,38 @@
+// プレイ状況の定義（組み込み・カスタムステータス）関連の API を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// ListPlayStatusDefinitions は組み込みのプレイ状況とカスタムステータスを表示順で取得する。
+func (app *App) ListPlayStatusDefinitions() result.ApiResult[[]domain.PlayStatusDefinition] {
+	definitions, err := app.PlayStatusService.ListPlayStatusDefinitions(app.context())
+	return serviceResult(definitions, err, "プレイ状況の取得に失敗しました")
+}
+
+// CreatePlayStatusDefinition はカスタムステータスを追加する。
+func (app *App) CreatePlayStatusDefinition(input services.PlayStatusDefinitionInput) result.ApiResult[*domain.PlayStatusDefinition] {
+	created, err := app.PlayStatusService.CreatePlayStatusDefinition(app.context(), input)
+	return serviceResult(created, err, "プレイ状況の作成に失敗しました")
+}
+
+// UpdatePlayStatusDefinition はカスタムステータスの表示名・色・表示順を更新する。
+func (app *App) UpdatePlayStatusDefinition(slug string, input services.PlayStatusDefinitionInput) result.ApiResult[*domain.PlayStatusDefinition] {
+	updated, err := app.PlayStatusService.UpdatePlayStatusDefinition(app.context(), slug, input)
+	return serviceResult(updated, err, "プレイ状況の更新に失敗しました")
+}
+
+// DeletePlayStatusDefinition はカスタムステータスを削除する。そのステータスだったゲームは未設定に戻して同期する。
+func (app *App) DeletePlayStatusDefinition(slug string) result.ApiResult[bool] {
+	gameIDs, err := app.PlayStatusService.DeletePlayStatusDefinition(app.context(), slug)
+	if err != nil {
+		return serviceErrorResult[bool](err, "プレイ状況の削除に失敗しました")
+	}
+	for _, gameID := range gameIDs {
+		app.syncGameAsync(gameID)
+	}
+	return result.OkResult(true)
+}
@@ -0,0 +1,97 @@
+// cloudlaunch:// URI（起動引数・2つ目のインスタンスから渡されたもの）を App の操作につなぐ。
+package app
+
+import (
+	"os"
+
+	"CloudLaunch_Go/internal/logging"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// protocolErrorEvent は URI の操作に失敗したときにフロントエンドへ送るイベント名（result.ApiError を渡す）。
+const protocolErrorEvent = "protocol:error"
+
+// SetLaunchArgs は起動時のコマンドライン引数を受け取る。cloudlaunch:// URI があれば Startup 後に実行する。
+func (app *App) SetLaunchArgs(args []string) {
+	if uri, ok := services.FindProtocolURI(args); ok {
+		app.pendingProtocolURI = uri
+	}
+}
+
+// HandleSecondInstance は起動中に2つ目のインスタンスが起動されたときの引数を処理する。
+// URI があれば操作だけを行い（撮影対象のゲームから前面を奪わないよう）ウィンドウは出さない。
+// URI が無ければ通常の起動とみなし、既存のウィンドウを前面に出す。
+func (app *App) HandleSecondInstance(args []string) {
+	uri, ok := services.FindProtocolURI(args)
+	if !ok {
+		if app.ctx != nil {
+			wailsruntime.WindowUnminimise(app.ctx)
+			wailsruntime.WindowShow(app.ctx)
+		}
+		return
+	}
+	app.backgroundTasks.Go(func() {
+		defer logging.Recover(app.Logger, "app.handleProtocolURI")
+		app.handleProtocolURI(uri)
+	})
+}
+
+// startProtocolHandler は cloudlaunch:// のハンドラを現在の実行ファイルで登録し、起動引数の URI を実行する。
+// URI の起動・同期が終わる前に DB を閉じないよう、終了時に待つバックグラウンド処理として動かす。
+func (app *App) startProtocolHandler() {
+	pending := app.pendingProtocolURI
+	app.pendingProtocolURI = ""
+	app.backgroundTasks.Go(func() {
+		defer logging.Recover(app.Logger, "app.startProtocolHandler")
+		if exePath, err := os.Executable(); err != nil {
+			app.Logger.Warn("実行ファイルのパスを取得できないため URI ハンドラを登録しません", "error", err)
+		} else if err := services.RegisterProtocolHandler(exePath); err != nil {
+			app.Logger.Warn("URI ハンドラの登録に失敗しました", "error", err)
+		}
+		if pending != "" {
+			app.handleProtocolURI(pending)
+		}
+	})
+}
+
+// handleProtocolURI は URI を解釈してゲームの起動・スクリーンショット撮影を行う。
+// 失敗は protocolErrorEvent で API と同じ形のエラーとして通知する。
+func (app *App) handleProtocolURI(uri string) {
+	command, err := services.ParseProtocolURI(uri)
+	if err != nil {
+		app.reportProtocolError(uri, result.ErrorResult[any]("URI が不正です", err.Error()).Error)
+		return
+	}
+	app.Logger.Info("URI の操作を実行", "action", command.Action, "gameId", command.GameID)
+	switch command.Action {
+	case services.ProtocolActionLaunch:
+		game, err := app.GameService.GetGameByID(app.context(), command.GameID)
+		if err != nil || game == nil {
+			app.reportProtocolError(uri, result.ErrorResult[any]("ゲームが見つかりません", command.GameID).Error)
+			return
+		}
+		if launched := app.LaunchGame(game.ExePath); !launched.Success {
+			app.reportProtocolError(uri, launched.Error)
+		}
+	case services.ProtocolActionScreenshot:
+		if command.GameID == "" {
+			if !app.handleHotkeyCapture() {
+				app.reportProtocolError(uri, result.ErrorResult[any]("スクリーンショットの取得に失敗しました", "foreground capture failed").Error)
+			}
+			return
+		}
+		if captured := app.CaptureGameScreenshot(command.GameID); !captured.Success {
+			app.reportProtocolError(uri, captured.Error)
+		}
+	}
+}
+
+func (app *App) reportProtocolError(uri string, apiError *result.ApiError) {
+	app.Logger.Warn("URI の操作に失敗しました", "uri", uri, "message", apiError.Message, "detail", apiError.Detail)
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, protocolErrorEvent, apiError)
+	}
+}
@@ -0,0 +1,36 @@
+// ルートテンプレート（ゲームをまたいで使い回すルート構成）関連のAPIを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// ListRouteTemplates は保存済みのルートテンプレート一覧を取得する。
+func (app *App) ListRouteTemplates() result.ApiResult[[]domain.RouteTemplate] {
+	templates, err := app.RouteTemplateService.ListRouteTemplates(app.context())
+	return serviceResult(templates, err, "ルートテンプレート取得に失敗しました")
+}
+
+// SaveRouteTemplate はゲームのルート構成を name のテンプレートとして保存する（同名なら上書き）。
+func (app *App) SaveRouteTemplate(gameID string, name string) result.ApiResult[*domain.RouteTemplate] {
+	saved, err := app.RouteTemplateService.SaveRouteTemplate(app.context(), gameID, name)
+	return serviceResult(saved, err, "ルートテンプレート保存に失敗しました")
+}
+
+// ApplyRouteTemplate はテンプレートのルートをゲームへ追加し、追加したルートを返す。
+func (app *App) ApplyRouteTemplate(templateID string, gameID string) result.ApiResult[[]domain.Route] {
+	created, err := app.RouteTemplateService.ApplyRouteTemplate(app.context(), templateID, gameID)
+	if err != nil {
+		return serviceErrorResult[[]domain.Route](err, "ルートテンプレート適用に失敗しました")
+	}
+	if len(created) > 0 {
+		app.syncGameAsync(created[0].GameID)
+	}
+	return result.OkResult(created)
+}
+
+// DeleteRouteTemplate はルートテンプレートを削除する。
+func (app *App) DeleteRouteTemplate(templateID string) result.ApiResult[bool] {
+	return boolResult(app.RouteTemplateService.DeleteRouteTemplate(app.context(), templateID), "ルートテンプレート削除に失敗しました")
+}
@@ -0,0 +1,55 @@
+// セッション終了後のセーブフォルダの変更検出と、その通知・アップロードを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/logging"
+	"CloudLaunch_Go/internal/result"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// saveChangedEvent はセーブフォルダの変更を検出してローカルセーブハッシュを更新したときにフロントエンドへ送るイベント名。
+// ペイロードは更新後のゲームで、自動アップロードが無効ならフロントエンドがアップロードを促す。
+const saveChangedEvent = "save:changed"
+
+// UpdateSaveWatchAutoUpload はセーブフォルダの変更を検出したとき、確認せずにクラウドへアップロードするかを更新する。
+func (app *App) UpdateSaveWatchAutoUpload(enabled bool) result.ApiResult[bool] {
+	app.Config.SaveWatchAutoUpload = enabled
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// startSaveFolderWatcher はセーブフォルダの監視を開始する。監視できなくても同期自体は使えるため警告に留める。
+func (app *App) startSaveFolderWatcher() {
+	if app.SaveFolderWatcher == nil {
+		return
+	}
+	if err := app.SaveFolderWatcher.Start(app.context()); err != nil {
+		app.Logger.Warn("セーブフォルダの監視を開始できません", "error", err)
+	}
+}
+
+// reloadSaveFolderWatchAsync はゲームの追加・変更・削除に合わせて、監視するセーブフォルダをバックグラウンドで取り直す。
+func (app *App) reloadSaveFolderWatchAsync() {
+	if app.SaveFolderWatcher == nil {
+		return
+	}
+	app.backgroundTasks.Go(func() {
+		defer logging.Recover(app.Logger, "app.reloadSaveFolderWatch")
+		if err := app.SaveFolderWatcher.Reload(app.context()); err != nil {
+			app.Logger.Warn("セーブフォルダの監視対象を更新できません", "error", err)
+		}
+	})
+}
+
+// handleSaveFolderChanged はセーブフォルダの変更をフロントエンドへ通知し、設定に応じてクラウドへアップロードする。
+func (app *App) handleSaveFolderChanged(updated domain.Game) {
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, saveChangedEvent, updated)
+	}
+	if !app.Config.SaveWatchAutoUpload {
+		return
+	}
+	app.syncGameAsync(updated.ID)
+}
@@ -0,0 +1,34 @@
+// スクリーンショットのクリップボードへのコピーと、その設定APIを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// UpdateScreenshotCopyToClipboard は撮影に成功したスクリーンショットをクリップボードにもコピーするか更新する。
+func (app *App) UpdateScreenshotCopyToClipboard(enabled bool) result.ApiResult[bool] {
+	app.Config.ScreenshotCopyToClipboard = enabled
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// CopyScreenshotToClipboard は指定した画像ファイル（PNG / JPEG）をクリップボードへコピーする（Windows のみ）。
+func (app *App) CopyScreenshotToClipboard(path string) result.ApiResult[bool] {
+	err := services.CopyImageFileToClipboard(path)
+	if err != nil {
+		app.Logger.Warn("クリップボードへのコピーに失敗", "path", path, "error", err)
+	}
+	return boolResult(err, "クリップボードへのコピーに失敗しました")
+}
+
+// copyCaptureToClipboard は設定が有効なとき、撮影したスクリーンショットをクリップボードへコピーする。
+// 保存自体は成功しているため、コピーに失敗しても撮影は失敗扱いにしない。
+func (app *App) copyCaptureToClipboard(path string) {
+	if !app.Config.ScreenshotCopyToClipboard {
+		return
+	}
+	if err := services.CopyImageFileToClipboard(path); err != nil {
+		app.Logger.Warn("スクリーンショットをクリップボードにコピーできませんでした", "path", path, "error", err)
+	}
+}
@@ -0,0 +1,21 @@
+// スクリーンショットのクラウド取得APIを提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// DownloadGameScreenshots はクラウド上のゲームのスクリーンショットを一括で取り込む。
+// destination が空ならローカルのスクリーンショットフォルダへ保存する。
+// 進捗は "sync:progress"（operation=screenshots）でファイル単位に、残り時間つきで通知する。
+func (app *App) DownloadGameScreenshots(gameID string, destination string) result.ApiResult[services.ScreenshotDownloadResult] {
+	trimmed, errResult, ok := requireGameID[services.ScreenshotDownloadResult](gameID)
+	if !ok {
+		return errResult
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationScreenshots, trimmed)
+	defer op.Finish()
+	res, err := app.ScreenshotCloudService.DownloadGameScreenshots(ctx, trimmed, destination, countProgressEmitter(ctx, op))
+	return serviceResult(res, err, "スクリーンショットのダウンロードに失敗しました")
+}
@@ -0,0 +1,74 @@
+// スクリーンショットのタイマー撮影・連写とそのキャンセルの API を提供する。
+package app
+
+import (
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// CaptureScreenshotDelayed は delaySeconds 秒待ってから指定ゲームのウィンドウを1枚撮影する。
+// 待ち時間中に CancelScreenshotSeries（または CancelOperation）でキャンセルすると、Canceled 付きの空の結果を返す。
+func (app *App) CaptureScreenshotDelayed(gameID string, delaySeconds int) result.ApiResult[domain.ScreenshotSeriesResult] {
+	return app.captureScreenshotSeries(gameID, services.ScreenshotSeries{DelaySeconds: delaySeconds, Count: 1})
+}
+
+// CaptureScreenshotBurst は指定ゲームのウィンドウを intervalMs ミリ秒間隔で count 枚撮影する（ムービーシーンの記録向け）。
+// ファイル名は開始時刻を共有した連番になる。進捗は "sync:progress"（operation=screenshotSeries）で1枚ごとに通知し、
+// キャンセルした場合はそこまでに撮れた分を Canceled 付きで返す。
+func (app *App) CaptureScreenshotBurst(gameID string, count int, intervalMs int) result.ApiResult[domain.ScreenshotSeriesResult] {
+	return app.captureScreenshotSeries(gameID, services.ScreenshotSeries{Count: count, IntervalMs: intervalMs})
+}
+
+// CancelScreenshotSeries は指定ゲームで実行中のタイマー撮影・連写をキャンセルする。
+// gameID が空なら全ゲーム分をキャンセルする。キャンセルした処理があれば true を返す。
+func (app *App) CancelScreenshotSeries(gameID string) result.ApiResult[bool] {
+	trimmed := strings.TrimSpace(gameID)
+	canceled := false
+	for _, operation := range app.Operations.List() {
+		if operation.Kind != services.OperationScreenshotSeries || (trimmed != "" && operation.GameID != trimmed) {
+			continue
+		}
+		if app.Operations.Cancel(operation.ID) {
+			canceled = true
+		}
+	}
+	if canceled {
+		app.Logger.Info("連続撮影のキャンセルを要求", "gameId", trimmed)
+	}
+	return result.OkResult(canceled)
+}
+
+// captureScreenshotSeries は連続撮影を長時間処理として登録して実行し、撮れた分をクラウドへ同期する。
+// 同期はキャンセル後も撮影済みの分について行うため、処理のコンテキストではなくアプリのコンテキストで行う。
+// 同期に失敗しても撮影したファイルはローカルに残っているため、結果は返して UploadFailed で知らせる。
+func (app *App) captureScreenshotSeries(gameID string, series services.ScreenshotSeries) result.ApiResult[domain.ScreenshotSeriesResult] {
+	if app.ScreenshotService == nil {
+		app.Logger.Warn("スクリーンショット機能が無効です", "operation", "captureScreenshotSeries", "reason", "screenshot service is nil")
+		return result.ErrorResult[domain.ScreenshotSeriesResult]("スクリーンショット機能が無効です", "screenshot service is nil")
+	}
+	trimmed := strings.TrimSpace(gameID)
+	ctx, op := app.Operations.Begin(app.context(), services.OperationScreenshotSeries, trimmed)
+	outcome, err := app.ScreenshotService.CaptureScreenshotSeries(ctx, trimmed, series, countProgressEmitter(ctx, op))
+	op.Finish()
+	if err != nil {
+		app.Logger.Error("連続撮影に失敗", "gameId", trimmed, "error", err)
+		return serviceErrorResult[domain.ScreenshotSeriesResult](err, "スクリーンショットの取得に失敗しました")
+	}
+	if count := len(outcome.Captures); count > 0 {
+		app.copyCaptureToClipboard(outcome.Captures[count-1].Path)
+	}
+	if app.Config.ScreenshotSyncEnabled {
+		for _, captured := range outcome.Captures {
+			if syncErr := app.uploadScreenshot(app.context(), captured.GameID, captured.Path); syncErr != nil {
+				app.Logger.Error("スクリーンショット同期に失敗", "path", captured.Path, "error", syncErr)
+				if !outcome.UploadFailed {
+					outcome.UploadFailed, outcome.UploadError = true, syncErr.Error()
+				}
+			}
+		}
+	}
+	return result.OkResult(outcome)
+}
@@ -0,0 +1,350 @@
+// アプリ設定の取得・一括更新 API を提供する。
+package app
+
+import (
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// currentSettings は実行中の設定値を AppSettings にまとめる。
+func (app *App) currentSettings() services.AppSettings {
+	settings := services.AppSettingsFromConfig(app.Config)
+	settings.AutoTracking = app.autoTracking
+	settings.OfflineMode = app.offlineMode
+	return settings
+}
+
+// persistSettings は実行中の設定値を保存する。
+// 反映自体は済んでいるため、保存失敗は API エラーにせずログのみとする（次回起動時に以前の値へ戻るだけ）。
+func (app *App) persistSettings() {
+	if app.SettingsService == nil {
+		return
+	}
+	if _, err := app.SettingsService.SaveSettings(app.context(), app.currentSettings()); err != nil {
+		app.Logger.Warn("設定の保存に失敗しました", "error", err)
+	}
+}
+
+// GetSettings は現在のアプリ設定を返す。
+func (app *App) GetSettings() result.ApiResult[services.AppSettings] {
+	return result.OkResult(app.currentSettings())
+}
+
+// UpdateSettings はアプリ設定を一括で更新し、保存後の値を返す。
+// 変更された項目だけを個別の Update* と同じ経路で反映するため、ホットキー再登録などの副作用も揃う。
+// 途中の項目で失敗した場合、それ以前に反映済みの項目は残る（個別 API を順に呼んだのと同じ）。
+func (app *App) UpdateSettings(input services.AppSettings) result.ApiResult[services.AppSettings] {
+	settings, err := services.NormalizeAppSettings(input)
+	if err != nil {
+		app.Logger.Warn("設定が不正です", "operation", "UpdateSettings", "error", err)
+		return result.ErrorResult[services.AppSettings]("設定が不正です", err.Error())
+	}
+
+	current := app.currentSettings()
+	steps := []struct {
+		changed bool
+		apply   func() result.ApiResult[bool]
+	}{
+		{
+			changed: current.LogLevel != settings.LogLevel,
+			apply:   func() result.ApiResult[bool] { return app.UpdateLogLevel(settings.LogLevel) },
+		},
+		{
+			changed: current.Language != settings.Language,
+			apply:   func() result.ApiResult[bool] { return app.UpdateLanguage(settings.Language) },
+		},
+		{
+			changed: current.AutoTracking != settings.AutoTracking,
+			apply:   func() result.ApiResult[bool] { return app.UpdateAutoTracking(settings.AutoTracking) },
+		},
+		{
+			changed: current.MonitorIntervalSeconds != settings.MonitorIntervalSeconds,
+			apply:   func() result.ApiResult[bool] { return app.UpdateMonitorInterval(settings.MonitorIntervalSeconds) },
+		},
+		{
+			changed: current.MonitorWarmupPolicy != settings.MonitorWarmupPolicy,
+			apply:   func() result.ApiResult[bool] { return app.UpdateMonitorWarmupPolicy(settings.MonitorWarmupPolicy) },
+		},
+		{
+			changed: current.MonitorIdleThresholdMinutes != settings.MonitorIdleThresholdMinutes,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateMonitorIdleThreshold(settings.MonitorIdleThresholdMinutes)
+			},
+		},
+		{
+			changed: current.MonitorSplitAtMidnight != settings.MonitorSplitAtMidnight,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateMonitorSplitAtMidnight(settings.MonitorSplitAtMidnight)
+			},
+		},
+		{
+			changed: current.MonitorSessionTimeoutSeconds != settings.MonitorSessionTimeoutSeconds ||
+				current.MonitorCleanupTimeoutSeconds != settings.MonitorCleanupTimeoutSeconds,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateMonitorTimeouts(settings.MonitorSessionTimeoutSeconds, settings.MonitorCleanupTimeoutSeconds)
+			},
+		},
+		{
+			changed: current.PlayReminderMinutes != settings.PlayReminderMinutes,
+			apply:   func() result.ApiResult[bool] { return app.UpdatePlayReminderMinutes(settings.PlayReminderMinutes) },
+		},
+		{
+			changed: current.OfflineMode != settings.OfflineMode,
+			apply:   func() result.ApiResult[bool] { return app.UpdateOfflineMode(settings.OfflineMode) },
+		},
+		{
+			changed: current.S3ForcePathStyle != settings.S3ForcePathStyle,
+			apply:   func() result.ApiResult[bool] { return app.UpdateS3ForcePathStyle(settings.S3ForcePathStyle) },
+		},
+		{
+			changed: current.S3UseTLS != settings.S3UseTLS,
+			apply:   func() result.ApiResult[bool] { return app.UpdateS3UseTLS(settings.S3UseTLS) },
+		},
+		{
+			changed: current.StorageBackend != settings.StorageBackend ||
+				current.LocalStorageDir != settings.LocalStorageDir,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateStorageBackend(settings.StorageBackend, settings.LocalStorageDir)
+			},
+		},
+		{
+			changed: current.ActiveCredentialKey != settings.ActiveCredentialKey,
+			apply:   func() result.ApiResult[bool] { return app.UpdateActiveCredentialKey(settings.ActiveCredentialKey) },
+		},
+		{
+			changed: current.S3UploadConcurrency != settings.S3UploadConcurrency,
+			apply:   func() result.ApiResult[bool] { return app.UpdateUploadConcurrency(settings.S3UploadConcurrency) },
+		},
+		{
+			changed: current.S3MultipartPartSizeMB != settings.S3MultipartPartSizeMB,
+			apply:   func() result.ApiResult[bool] { return app.UpdateMultipartPartSize(settings.S3MultipartPartSizeMB) },
+		},
+		{
+			changed: current.SaveCompression != settings.SaveCompression,
+			apply:   func() result.ApiResult[bool] { return app.UpdateSaveCompression(settings.SaveCompression) },
+		},
+		{
+			changed: current.SyncConflictPolicy != settings.SyncConflictPolicy,
+			apply:   func() result.ApiResult[bool] { return app.UpdateSyncConflictPolicy(settings.SyncConflictPolicy) },
+		},
+		{
+			changed: current.ScreenshotSyncEnabled != settings.ScreenshotSyncEnabled,
+			apply:   func() result.ApiResult[bool] { return app.UpdateScreenshotSyncEnabled(settings.ScreenshotSyncEnabled) },
+		},
+		{
+			changed: current.ScreenshotUploadJpeg != settings.ScreenshotUploadJpeg,
+			apply:   func() result.ApiResult[bool] { return app.UpdateScreenshotUploadJpeg(settings.ScreenshotUploadJpeg) },
+		},
+		{
+			changed: current.ScreenshotJpegQuality != settings.ScreenshotJpegQuality,
+			apply:   func() result.ApiResult[bool] { return app.UpdateScreenshotJpegQuality(settings.ScreenshotJpegQuality) },
+		},
+		{
+			changed: current.ScreenshotClientOnly != settings.ScreenshotClientOnly,
+			apply:   func() result.ApiResult[bool] { return app.UpdateScreenshotClientOnly(settings.ScreenshotClientOnly) },
+		},
+		{
+			changed: current.ScreenshotLocalJpeg != settings.ScreenshotLocalJpeg,
+			apply:   func() result.ApiResult[bool] { return app.UpdateScreenshotLocalJpeg(settings.ScreenshotLocalJpeg) },
+		},
+		{
+			changed: current.ScreenshotHotkey != settings.ScreenshotHotkey,
+			apply:   func() result.ApiResult[bool] { return app.UpdateScreenshotHotkey(settings.ScreenshotHotkey) },
+		},
+		{
+			changed: current.HotkeyActions != settings.HotkeyActions,
+			apply:   func() result.ApiResult[bool] { return app.UpdateHotkeyActions(settings.HotkeyActions) },
+		},
+		{
+			changed: current.ScreenshotHotkeyNotify != settings.ScreenshotHotkeyNotify,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateScreenshotHotkeyNotify(settings.ScreenshotHotkeyNotify)
+			},
+		},
+		{
+			changed: current.ThumbnailShortEdgePx != settings.ThumbnailShortEdgePx,
+			apply:   func() result.ApiResult[bool] { return app.UpdateThumbnailShortEdge(settings.ThumbnailShortEdgePx) },
+		},
+		{
+			changed: current.ErogameScapeCacheTTLMinutes != settings.ErogameScapeCacheTTLMinutes,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateErogameScapeCacheTTL(settings.ErogameScapeCacheTTLMinutes)
+			},
+		},
+		{
+			changed: current.MemoExternalEditUpload != settings.MemoExternalEditUpload,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateMemoExternalEditUpload(settings.MemoExternalEditUpload)
+			},
+		},
+		{
+			changed: current.SaveWatchAutoUpload != settings.SaveWatchAutoUpload,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateSaveWatchAutoUpload(settings.SaveWatchAutoUpload)
+			},
+		},
+		{
+			changed: current.ScreenshotExcludedApps != settings.ScreenshotExcludedApps,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateScreenshotExcludedApps(settings.ScreenshotExcludedApps)
+			},
+		},
+		{
+			changed: current.ScreenshotCopyToClipboard != settings.ScreenshotCopyToClipboard,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateScreenshotCopyToClipboard(settings.ScreenshotCopyToClipboard)
+			},
+		},
+		{
+			changed: current.ClipBufferSeconds != settings.ClipBufferSeconds,
+			apply:   func() result.ApiResult[bool] { return app.UpdateClipBufferSeconds(settings.ClipBufferSeconds) },
+		},
+		{
+			changed: current.ClipFFmpegPath != settings.ClipFFmpegPath,
+			apply:   func() result.ApiResult[bool] { return app.UpdateClipFFmpegPath(settings.ClipFFmpegPath) },
+		},
+		{
+			changed: current.HTTPTimeoutSeconds != settings.HTTPTimeoutSeconds ||
+				current.HTTPProxyURL != settings.HTTPProxyURL ||
+				current.HTTPMaxRetries != settings.HTTPMaxRetries,
+			apply: func() result.ApiResult[bool] {
+				return app.UpdateHTTPSettings(settings.HTTPTimeoutSeconds, settings.HTTPProxyURL, settings.HTTPMaxRetries)
+			},
+		},
+	}
+	for _, step := range steps {
+		if !step.changed {
+			continue
+		}
+		if applied := step.apply(); !applied.Success {
+			return result.ApiResult[services.AppSettings]{Success: false, Error: applied.Error}
+		}
+	}
+	return result.OkResult(app.currentSettings())
+}
+
+// UpdateMonitorInterval はプロセス監視の間隔（秒）を更新する。
+func (app *App) UpdateMonitorInterval(seconds int) result.ApiResult[bool] {
+	if seconds < 1 || seconds > 60 {
+		app.Logger.Warn("監視間隔が不正です", "operation", "UpdateMonitorInterval", "value", seconds)
+		return result.ErrorResult[bool]("監視間隔が不正です", "value must be 1-60")
+	}
+	app.Config.MonitorIntervalSeconds = seconds
+	if app.ProcessMonitor != nil {
+		app.ProcessMonitor.SetInterval(time.Duration(seconds) * time.Second)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateMonitorWarmupPolicy はアプリ起動前から起動していたゲームの扱い（partial|prompt）を更新する。
+func (app *App) UpdateMonitorWarmupPolicy(policy string) result.ApiResult[bool] {
+	normalized, ok := services.NormalizeWarmupPolicy(policy)
+	if !ok {
+		app.Logger.Warn("起動済みゲームの扱いが不正です", "operation", "UpdateMonitorWarmupPolicy", "value", policy)
+		return result.ErrorResult[bool]("起動済みゲームの扱いが不正です", "value must be partial|prompt")
+	}
+	app.Config.MonitorWarmupPolicy = normalized
+	if app.ProcessMonitor != nil {
+		app.ProcessMonitor.SetWarmupPolicy(normalized)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateMonitorSplitAtMidnight は日付をまたいだセッションを 0:00 で分けて記録するか更新する。
+// 計測中のセッションにも、保存する時点の設定が使われる。
+func (app *App) UpdateMonitorSplitAtMidnight(enabled bool) result.ApiResult[bool] {
+	app.Config.MonitorSplitAtMidnight = enabled
+	if app.ProcessMonitor != nil {
+		app.ProcessMonitor.SetSplitAtMidnight(enabled)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateMonitorIdleThreshold は無操作とみなしてプレイ時間の計測を止めるまでの分数を更新する。0 で無効。
+func (app *App) UpdateMonitorIdleThreshold(minutes int) result.ApiResult[bool] {
+	if minutes < 0 || minutes > 240 {
+		app.Logger.Warn("無操作しきい値が不正です", "operation", "UpdateMonitorIdleThreshold", "value", minutes)
+		return result.ErrorResult[bool]("無操作しきい値が不正です", "value must be 0-240")
+	}
+	app.Config.MonitorIdleThresholdMinutes = minutes
+	if app.ProcessMonitor != nil {
+		app.ProcessMonitor.SetIdleThreshold(time.Duration(minutes) * time.Minute)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateMonitorTimeouts はプロセスが見つからなくなってから終了確認待ちにするまでの秒数と、
+// 監視対象から外すまでの秒数を更新する。
+func (app *App) UpdateMonitorTimeouts(sessionTimeoutSeconds int, cleanupTimeoutSeconds int) result.ApiResult[bool] {
+	if err := services.ValidateMonitorTimeouts(sessionTimeoutSeconds, cleanupTimeoutSeconds); err != nil {
+		app.Logger.Warn("監視のタイムアウトが不正です", "operation", "UpdateMonitorTimeouts", "error", err)
+		return result.ErrorResult[bool]("監視のタイムアウトが不正です", err.Error())
+	}
+	app.Config.MonitorSessionTimeoutSeconds = sessionTimeoutSeconds
+	app.Config.MonitorCleanupTimeoutSeconds = cleanupTimeoutSeconds
+	if app.ProcessMonitor != nil {
+		app.ProcessMonitor.SetTimeouts(
+			time.Duration(sessionTimeoutSeconds)*time.Second,
+			time.Duration(cleanupTimeoutSeconds)*time.Second,
+		)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateHTTPSettings は外部サイト向け HTTP 通信のタイムアウト秒・プロキシURL・リトライ回数を更新する。
+// プロキシURLが空の場合は環境変数（HTTPS_PROXY 等）に従う。
+func (app *App) UpdateHTTPSettings(timeoutSeconds int, proxyURL string, maxRetries int) result.ApiResult[bool] {
+	if err := services.ValidateHTTPSettings(timeoutSeconds, proxyURL, maxRetries); err != nil {
+		app.Logger.Warn("HTTP設定が不正です", "operation", "UpdateHTTPSettings", "error", err)
+		return result.ErrorResult[bool]("HTTP設定が不正です", err.Error())
+	}
+	next := app.Config
+	next.HTTPTimeoutSeconds = timeoutSeconds
+	next.HTTPProxyURL = strings.TrimSpace(proxyURL)
+	next.HTTPMaxRetries = maxRetries
+	client, err := services.NewHTTPClient(next)
+	if err != nil {
+		return errorResultWithLog[bool](app, "HTTPクライアントの生成に失敗しました", err, "operation", "UpdateHTTPSettings")
+	}
+	app.Config = next
+	if app.ErogameScapeService != nil {
+		app.ErogameScapeService.SetHTTPClient(client)
+	}
+	if app.GoogleDriveService != nil {
+		// 接続中のセッションを切らないよう、OAuth クライアントは作り直さずに HTTP クライアントだけ差し替える。
+		if driveClient, err := services.NewGoogleDriveHTTPClient(next); err != nil {
+			app.Logger.Warn("Google ドライブの HTTP 設定の更新に失敗しました", "error", err)
+		} else {
+			app.GoogleDriveService.SetHTTPClient(driveClient)
+		}
+	}
+	if app.WebhookService != nil {
+		// Webhook は送信先ごとの事情があるため、タイムアウト・リトライ回数は独自の値のままプロキシだけ合わせる。
+		if err := app.WebhookService.SetProxyURL(next.HTTPProxyURL); err != nil {
+			app.Logger.Warn("Webhook のプロキシ設定の更新に失敗しました", "error", err)
+		}
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// UpdateMultipartPartSize はマルチパートアップロードのパートサイズ（MB）を更新する。
+// これを超える大きさのオブジェクトはパート単位で送られ、中断しても次回に続きから再開する。
+func (app *App) UpdateMultipartPartSize(sizeMB int) result.ApiResult[bool] {
+	if err := storage.SetMultipartPartSizeMB(sizeMB); err != nil {
+		app.Logger.Warn("パートサイズが不正です", "operation", "UpdateMultipartPartSize", "value", sizeMB)
+		return result.ErrorResult[bool]("パートサイズが不正です", err.Error())
+	}
+	app.Config.S3MultipartPartSizeMB = sizeMB
+	app.persistSettings()
+	return result.OkResult(true)
+}
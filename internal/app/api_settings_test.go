@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/services"
+)
+
+type memorySettingsRepository struct {
+	values map[string]string
+}
+
+func (repository *memorySettingsRepository) GetSetting(_ context.Context, key string) (string, error) {
+	return repository.values[key], nil
+}
+
+func (repository *memorySettingsRepository) UpsertSetting(_ context.Context, key, value string) error {
+	repository.values[key] = value
+	return nil
+}
+
+func newSettingsTestApp(repository *memorySettingsRepository) *App {
+	return &App{
+		Config: config.Config{
+			LogLevel:                     "info",
+			MonitorIntervalSeconds:       2,
+			MonitorWarmupPolicy:          "partial",
+			MonitorCleanupTimeoutSeconds: 20,
+			S3UploadConcurrency:          6,
+			S3MultipartPartSizeMB:        16,
+			ThumbnailShortEdgePx:         200,
+			CredentialKey:                "default",
+			ScreenshotJpegQuality:        85,
+			ScreenshotHotkey:             "Ctrl+Alt+S",
+			HTTPTimeoutSeconds:           15,
+			HTTPMaxRetries:               2,
+		},
+		Logger:          slog.Default(),
+		SettingsService: services.NewSettingsService(repository, slog.Default()),
+		HotkeyService:   &stubHotkeyService{},
+		autoTracking:    true,
+	}
+}
+
+func TestUpdateSettingsAppliesAndPersistsChanges(t *testing.T) {
+	repository := &memorySettingsRepository{values: map[string]string{}}
+	app := newSettingsTestApp(repository)
+
+	input := app.currentSettings()
+	input.ScreenshotJpegQuality = 60
+	input.OfflineMode = true
+	input.AutoTracking = false
+
+	updated := app.UpdateSettings(input)
+	if !updated.Success {
+		t.Fatalf("expected success, got %#v", updated.Error)
+	}
+	if app.Config.ScreenshotJpegQuality != 60 || !app.offlineMode || app.autoTracking {
+		t.Fatalf("settings not applied: config=%#v offline=%v autoTracking=%v", app.Config, app.offlineMode, app.autoTracking)
+	}
+
+	reloaded, err := app.SettingsService.LoadSettings(context.Background(), services.AppSettingsFromConfig(config.Config{}))
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if reloaded != updated.Data {
+		t.Fatalf("persisted %#v, want %#v", reloaded, updated.Data)
+	}
+}
+
+func TestUpdateSettingsRejectsInvalidInputWithoutApplying(t *testing.T) {
+	repository := &memorySettingsRepository{values: map[string]string{}}
+	app := newSettingsTestApp(repository)
+
+	input := app.currentSettings()
+	input.ScreenshotJpegQuality = 60
+	input.MonitorIntervalSeconds = 0
+
+	if updated := app.UpdateSettings(input); updated.Success {
+		t.Fatal("expected failure for invalid interval")
+	}
+	if app.Config.ScreenshotJpegQuality != 85 {
+		t.Fatalf("config should stay unchanged, got %d", app.Config.ScreenshotJpegQuality)
+	}
+	if len(repository.values) != 0 {
+		t.Fatalf("nothing should be persisted, got %#v", repository.values)
+	}
+}
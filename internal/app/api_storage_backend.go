@@ -0,0 +1,51 @@
+// 同期データの保存先（S3 互換ストレージ・ローカルフォルダ・Google ドライブ）の切り替え API を提供する。
+package app
+
+import (
+	"strings"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// UpdateStorageBackend はセーブデータ同期の保存先を切り替える。
+// backend は "s3"・"local"・"gdrive" で、"local" のときは localDir（NAS の共有フォルダやクラウドドライブの同期フォルダ等）に
+// 保存するため、クラウドの認証情報が無くても同期できる。アクセスできないフォルダへの切り替えは拒否する。
+// "gdrive" は StartGoogleDriveAuth で接続を済ませてから選ぶ。
+// メモ・スクリーンショットのクラウド保存は引き続き S3 を使う。
+func (app *App) UpdateStorageBackend(backend string, localDir string) result.ApiResult[bool] {
+	normalized := services.NormalizeStorageBackend(backend)
+	trimmedDir := strings.TrimSpace(localDir)
+	if normalized == services.StorageBackendLocal {
+		objects, err := storage.NewLocalObjectStore(trimmedDir)
+		if err != nil {
+			app.Logger.Warn("同期先フォルダが不正です", "operation", "UpdateStorageBackend", "dir", trimmedDir, "error", err)
+			return result.ErrorResult[bool]("同期先フォルダが不正です", err.Error())
+		}
+		trimmedDir = objects.Root()
+	}
+	if normalized == services.StorageBackendGoogleDrive {
+		if app.GoogleDriveService == nil {
+			return result.ErrorResult[bool]("Google ドライブの接続が未初期化です", "GoogleDriveService is nil")
+		}
+		status, err := app.GoogleDriveService.Status(app.context())
+		if err != nil {
+			return serviceErrorResult[bool](err, "Google ドライブの接続状態を取得できません")
+		}
+		if !status.Connected {
+			return result.ErrorResult[bool]("Google ドライブに接続されていません", services.ErrGoogleDriveNotConnected.Error())
+		}
+	}
+	app.Config.StorageBackend = normalized
+	app.Config.LocalStorageDir = trimmedDir
+	if app.ContentSyncService != nil {
+		app.ContentSyncService.SetStorageBackend(normalized, trimmedDir)
+	}
+	if app.NetworkMonitor != nil {
+		app.NetworkMonitor.SetStorageBackend(normalized)
+	}
+	app.persistSettings()
+	app.Logger.Info("同期データの保存先を切り替え", "backend", normalized, "dir", trimmedDir)
+	return result.OkResult(true)
+}
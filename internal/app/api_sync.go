@@ -2,6 +2,8 @@
 package app
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,6 +21,19 @@ type CloudMetadataResult struct {
 	Games     []services.CloudGameInfo `json:"games"`
 }
 
+// AutoSyncQueueStatus は自動同期キューの状態。Waiting は実行予定の早い順。
+type AutoSyncQueueStatus struct {
+	RunningGameID string              `json:"runningGameId,omitempty"`
+	RunningSince  *time.Time          `json:"runningSince,omitempty"`
+	Waiting       []AutoSyncQueueItem `json:"waiting"`
+}
+
+// AutoSyncQueueItem は自動同期キューで実行を待っている同期1件。
+type AutoSyncQueueItem struct {
+	GameID string    `json:"gameId"`
+	DueAt  time.Time `json:"dueAt"`
+}
+
 // SyncStatus は指定ゲームの同期状態を返す。
 func (app *App) SyncStatus(gameID string) result.ApiResult[domain.SyncStatusDetail] {
 	trimmed, errResult, ok := requireGameID[domain.SyncStatusDetail](gameID)
@@ -32,23 +47,29 @@ func (app *App) SyncStatus(gameID string) result.ApiResult[domain.SyncStatusDeta
 	return result.OkResult(detail)
 }
 
+// GetDeviceIdentity はこの端末のデバイスID・デバイス名を返す。
+func (app *App) GetDeviceIdentity() result.ApiResult[services.DeviceIdentity] {
+	identity, err := app.ContentSyncService.DeviceIdentity(app.context())
+	return serviceResult(identity, err, "デバイス情報の取得に失敗しました")
+}
+
 // PushSync は指定ゲームのデータをリモートへアップロードする。
 func (app *App) PushSync(gameID string) result.ApiResult[any] {
 	trimmed, errResult, ok := requireGameID[any](gameID)
 	if !ok {
 		return errResult
 	}
-	ctx := app.context()
-	onProgress := func(current, total int) {
-		wailsruntime.EventsEmit(ctx, "sync:progress", map[string]any{
-			"operation": "push",
-			"current":   current,
-			"total":     total,
-		})
-	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationPush, trimmed)
+	defer op.Finish()
+	onProgress := transferProgressEmitter(ctx, op)
 	if err := app.ContentSyncService.Push(ctx, trimmed, onProgress); err != nil {
+		if isOfflineError(err) {
+			app.enqueueOfflinePush(trimmed)
+		}
 		return serviceErrorResult[any](err, "アップロードに失敗しました")
 	}
+	app.recordActivity(domain.ActivityActionSync, domain.ChangeEntityGame, trimmed, "クラウドへアップロード")
+	app.markOnboardingStep(domain.OnboardingStepFirstSync)
 	return result.OkResult[any](nil)
 }
 
@@ -60,21 +81,44 @@ func (app *App) PullSync(gameID string, deleteUntracked bool) result.ApiResult[d
 	if !ok {
 		return errResult
 	}
-	ctx := app.context()
-	onProgress := func(current, total int) {
-		wailsruntime.EventsEmit(ctx, "sync:progress", map[string]any{
-			"operation": "pull",
-			"current":   current,
-			"total":     total,
-		})
-	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationPull, trimmed)
+	defer op.Finish()
+	onProgress := transferProgressEmitter(ctx, op)
 	res, err := app.ContentSyncService.Pull(ctx, trimmed, onProgress, deleteUntracked)
 	if err != nil {
 		return serviceErrorResult[domain.PullResult](err, "ダウンロードに失敗しました")
 	}
+	if res.Applied {
+		app.recordActivity(domain.ActivityActionSync, domain.ChangeEntityGame, trimmed, "クラウドからダウンロード")
+		app.markOnboardingStep(domain.OnboardingStepFirstSync)
+	}
+	return result.OkResult(res)
+}
+
+// PullAllSync はクラウド上の未取り込みゲームを並列に一括ダウンロードする。
+// 進捗は "sync:progress"（operation=pullAll）でゲーム単位に、直近の速度から見積もった残り時間つきで通知する。
+// 失敗したゲームがあっても全体は成功として返し、再実行で失敗分だけを取り直せる。
+func (app *App) PullAllSync() result.ApiResult[domain.PullAllResult] {
+	ctx, op := app.Operations.Begin(app.context(), services.OperationPullAll, "")
+	defer op.Finish()
+	res, err := app.ContentSyncService.PullAll(ctx, countProgressEmitter(ctx, op))
+	if err != nil {
+		return serviceErrorResult[domain.PullAllResult](err, "一括ダウンロードに失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionSync, "", "", pullAllActivitySummary(res))
 	return result.OkResult(res)
 }
 
+// PreviewCloudSync は同期した場合に各ゲームがアップロード・ダウンロード・スキップのどれになるかと
+// その理由を返す。ローカル・クラウドとも変更しない。
+func (app *App) PreviewCloudSync() result.ApiResult[domain.SyncPlan] {
+	plan, err := app.ContentSyncService.PreviewSync(app.context())
+	if err != nil {
+		return serviceErrorResult[domain.SyncPlan](err, "同期プレビューに失敗しました")
+	}
+	return result.OkResult(plan)
+}
+
 // ResolveConflict はコンフリクトを解決する。
 // useLocal=false（リモート採用）は Pull と同様に未追跡ファイルの削除確認を経由する。
 func (app *App) ResolveConflict(gameID string, useLocal, deleteUntracked bool) result.ApiResult[domain.PullResult] {
@@ -82,15 +126,100 @@ func (app *App) ResolveConflict(gameID string, useLocal, deleteUntracked bool) r
 	if !ok {
 		return errResult
 	}
-	res, err := app.ContentSyncService.ResolveConflict(app.context(), trimmed, useLocal, deleteUntracked)
+	ctx, op := app.Operations.Begin(app.context(), services.OperationResolveConflict, trimmed)
+	defer op.Finish()
+	res, err := app.ContentSyncService.ResolveConflict(ctx, trimmed, useLocal, deleteUntracked)
 	if err != nil {
 		return serviceErrorResult[domain.PullResult](err, "コンフリクト解決に失敗しました")
 	}
+	if res.Applied {
+		summary := "コンフリクトをクラウド側で解決"
+		if useLocal {
+			summary = "コンフリクトをローカル側で解決"
+		}
+		app.recordActivity(domain.ActivityActionSync, domain.ChangeEntityGame, trimmed, summary)
+	}
+	return result.OkResult(res)
+}
+
+// MergeConflict はコンフリクトをフィールド単位の統合で解決する。
+// keepLocalSaves=false（リモートのセーブを採用）は Pull と同様に未追跡ファイルの削除確認を経由する。
+func (app *App) MergeConflict(gameID string, keepLocalSaves, deleteUntracked bool) result.ApiResult[domain.PullResult] {
+	trimmed, errResult, ok := requireGameID[domain.PullResult](gameID)
+	if !ok {
+		return errResult
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationResolveConflict, trimmed)
+	defer op.Finish()
+	res, err := app.ContentSyncService.MergeConflict(ctx, trimmed, keepLocalSaves, deleteUntracked)
+	if err != nil {
+		return serviceErrorResult[domain.PullResult](err, "コンフリクトの統合に失敗しました")
+	}
+	if res.Applied {
+		app.recordActivity(domain.ActivityActionSync, domain.ChangeEntityGame, trimmed, "コンフリクトを統合して解決")
+	}
 	return result.OkResult(res)
 }
 
-// syncGameAsync は指定ゲームのクラウド同期を非同期に要求する。
-// 同一 gameID の同期は直列化され、実行中の再要求は完了後に1回だけ畳み込まれる。
+// syncConflictEvent は自動同期のコンフリクトをユーザーの判断待ちにしたときにフロントエンドへ送るイベント名。
+// ペイロードは判断待ちのコンフリクト一覧（GetPendingSyncConflicts と同じ）。
+const syncConflictEvent = "sync:conflict"
+
+// UpdateSyncConflictPolicy は自動同期でコンフリクトを見つけたときの扱い（prefer-local|prefer-cloud|newest-wins|always-ask）を更新する。
+func (app *App) UpdateSyncConflictPolicy(policy string) result.ApiResult[bool] {
+	normalized, ok := services.NormalizeSyncConflictPolicy(policy)
+	if !ok {
+		app.Logger.Warn("コンフリクトの解決方針が不正です", "operation", "UpdateSyncConflictPolicy", "value", policy)
+		return result.ErrorResult[bool]("コンフリクトの解決方針が不正です", "value must be prefer-local|prefer-cloud|newest-wins|always-ask")
+	}
+	app.Config.SyncConflictPolicy = normalized
+	if app.ContentSyncService != nil {
+		app.ContentSyncService.SetConflictPolicy(normalized)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// GetPendingSyncConflicts は自動同期で見つかり、ユーザーの判断を待っているコンフリクトを返す。
+func (app *App) GetPendingSyncConflicts() result.ApiResult[[]domain.SyncConflict] {
+	return result.OkResult(app.ContentSyncService.PendingConflicts())
+}
+
+// ResolveSyncConflicts は判断待ちのコンフリクトにユーザーの判断をまとめて適用する。
+// 失敗したゲームがあっても全体は成功として返し、結果の Failed / PendingConfirmation に残す。
+func (app *App) ResolveSyncConflicts(decisions []domain.SyncConflictDecision) result.ApiResult[domain.SyncConflictResolveResult] {
+	ctx, op := app.Operations.Begin(app.context(), services.OperationResolveConflict, "")
+	defer op.Finish()
+	res := app.ContentSyncService.ResolveConflicts(ctx, decisions)
+	for _, decision := range decisions {
+		gameID := strings.TrimSpace(decision.GameID)
+		if !slices.Contains(res.Resolved, gameID) {
+			continue
+		}
+		summary := "コンフリクトをクラウド側で解決"
+		if decision.UseLocal {
+			summary = "コンフリクトをローカル側で解決"
+		}
+		app.recordActivity(domain.ActivityActionSync, domain.ChangeEntityGame, gameID, summary)
+	}
+	return result.OkResult(res)
+}
+
+// notifySyncConflicts は判断待ちのコンフリクト一覧をフロントエンドへ通知する。
+func (app *App) notifySyncConflicts() {
+	if app.ctx == nil {
+		return
+	}
+	wailsruntime.EventsEmit(app.ctx, syncConflictEvent, app.ContentSyncService.PendingConflicts())
+}
+
+// autoSyncDebounceWindow は自動同期キューで同じゲームの要求をまとめる時間。
+// まとめて複数のゲームを終了したときや、続けて編集したときの同期を1回ずつにする。
+const autoSyncDebounceWindow = 3 * time.Second
+
+// syncGameAsync は指定ゲームのクラウド同期を自動同期キューへ要求する。
+// 同一 gameID の要求は autoSyncDebounceWindow の間1回にまとめ、同期はゲームをまたいで1つずつ実行する。
+// オフラインモード中は送信待ちに追加し、オンライン復帰後に再送する。
 func (app *App) syncGameAsync(gameID string) {
 	if app.ContentSyncService == nil || app.syncCoalescer == nil {
 		return
@@ -99,9 +228,29 @@ func (app *App) syncGameAsync(gameID string) {
 	if id == "" {
 		return
 	}
+	if app.isOffline() {
+		app.enqueueOfflinePush(id)
+		return
+	}
 	app.syncCoalescer.trigger(id)
 }
 
+// GetAutoSyncQueueStatus は自動同期キューの状態（同期中のゲームと、待っているゲーム）を返す。
+func (app *App) GetAutoSyncQueueStatus() result.ApiResult[AutoSyncQueueStatus] {
+	if app.syncCoalescer == nil {
+		return result.OkResult(AutoSyncQueueStatus{Waiting: []AutoSyncQueueItem{}})
+	}
+	return result.OkResult(app.syncCoalescer.status())
+}
+
+// handleSessionEnded はプロセス監視の計測終了（session.ended）を受けて、プレイ後の Push を自動同期キューへ回す。
+// 複数のゲームを続けて終了しても同期が重ならず、1つずつ実行される。失敗は自動同期キュー側でログに残す。
+func (app *App) handleSessionEnded(event services.Event) {
+	if ended, ok := event.Payload.(services.SessionEndedEvent); ok {
+		app.syncGameAsync(ended.GameID)
+	}
+}
+
 // LoadCloudMetadata はクラウド上の全ゲームメタ情報を返す。
 func (app *App) LoadCloudMetadata() result.ApiResult[CloudMetadataResult] {
 	games, err := app.ContentSyncService.LoadCloudMetadata(app.context())
@@ -127,5 +276,58 @@ func (app *App) DeleteGameFromCloud(gameID string) result.ApiResult[any] {
 	if err := app.ContentSyncService.DeleteFromCloud(app.context(), trimmed); err != nil {
 		return serviceErrorResult[any](err, "クラウドデータ削除に失敗しました")
 	}
+	app.recordActivity(domain.ActivityActionDelete, domain.ChangeEntityGame, trimmed, "クラウドのデータを削除")
 	return result.OkResult[any](nil)
 }
+
+// ListMetadataSnapshots は指定ゲームのリモート HEAD 履歴を新しい順に返す。
+func (app *App) ListMetadataSnapshots(gameID string) result.ApiResult[[]services.MetadataSnapshot] {
+	trimmed, errResult, ok := requireGameID[[]services.MetadataSnapshot](gameID)
+	if !ok {
+		return errResult
+	}
+	snapshots, err := app.ContentSyncService.ListMetadataSnapshots(app.context(), trimmed)
+	if err != nil {
+		return serviceErrorResult[[]services.MetadataSnapshot](err, "クラウド履歴の取得に失敗しました")
+	}
+	return result.OkResult(snapshots)
+}
+
+// RestoreMetadataSnapshot は指定ゲームのリモート HEAD を履歴の時点に戻す。
+// ローカルへの反映は呼び出し側で続けて PullSync する。
+func (app *App) RestoreMetadataSnapshot(gameID string, snapshotID string) result.ApiResult[any] {
+	trimmed, errResult, ok := requireGameID[any](gameID)
+	if !ok {
+		return errResult
+	}
+	if err := app.ContentSyncService.RestoreMetadataSnapshot(app.context(), trimmed, strings.TrimSpace(snapshotID)); err != nil {
+		return serviceErrorResult[any](err, "クラウド履歴の復元に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionSync, domain.ChangeEntityGame, trimmed, "クラウドを過去の履歴に戻す")
+	return result.OkResult[any](nil)
+}
+
+// RestoreSaveData はクラウドのセーブデータを、ゲームに設定されたセーブフォルダへ復元する。
+// uploadID は ListMetadataSnapshots の履歴ID で、空なら現在のリモート HEAD を復元する。
+// 既存のセーブファイルは AppData 配下の日時つきのフォルダへ退避してから置き換え、空き容量が足りなければ始めない。
+// 進捗は "sync:progress"（operation=restoreSave）で通知する。
+func (app *App) RestoreSaveData(gameID, uploadID string) result.ApiResult[domain.SaveRestoreResult] {
+	trimmed, errResult, ok := requireGameID[domain.SaveRestoreResult](gameID)
+	if !ok {
+		return errResult
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationRestoreSave, trimmed)
+	defer op.Finish()
+	onProgress := transferProgressEmitter(ctx, op)
+	res, err := app.ContentSyncService.RestoreSaveData(ctx, trimmed, strings.TrimSpace(uploadID), onProgress)
+	if err != nil {
+		return serviceErrorResult[domain.SaveRestoreResult](err, "セーブの復元に失敗しました")
+	}
+	app.recordActivity(domain.ActivityActionSync, domain.ChangeEntityGame, trimmed, "クラウドからセーブデータを復元")
+	return result.OkResult(res)
+}
+
+// pullAllActivitySummary は一括ダウンロードの結果を操作履歴向けの1行に要約する。
+func pullAllActivitySummary(res domain.PullAllResult) string {
+	return fmt.Sprintf("クラウドから一括ダウンロード（取り込み %d 件・失敗 %d 件）", len(res.Applied), len(res.Failed))
+}
@@ -0,0 +1,95 @@
+// オフライン中のクラウド操作の送信待ち（SyncQueue）関連の API を提供する。
+package app
+
+import (
+	"context"
+	"errors"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/logging"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// GetPendingSyncQueue はオンライン復帰後に再送される送信待ち操作を追加順に返す。
+func (app *App) GetPendingSyncQueue() result.ApiResult[[]domain.SyncQueueItem] {
+	items, err := app.SyncQueueService.List(app.context())
+	return serviceResult(items, err, "送信待ち一覧の取得に失敗しました")
+}
+
+// ReplayPendingSyncQueue は送信待ち操作をすぐに再送する。オフラインモード中は何もしない。
+func (app *App) ReplayPendingSyncQueue() result.ApiResult[services.SyncQueueReplayResult] {
+	if app.isOffline() {
+		return serviceErrorResult[services.SyncQueueReplayResult](services.ErrOffline, "送信待ちの再送に失敗しました")
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationSyncQueueReplay, "")
+	defer op.Finish()
+	res, err := app.SyncQueueService.Replay(ctx)
+	return serviceResult(res, err, "送信待ちの再送に失敗しました")
+}
+
+// DiscardPendingSyncItem は送信待ち操作を再送せずに取り除く。
+func (app *App) DiscardPendingSyncItem(id int64) result.ApiResult[bool] {
+	return boolResult(app.SyncQueueService.Discard(app.context(), id), "送信待ちの削除に失敗しました")
+}
+
+// configureSyncQueue は送信待ちの種別ごとの再送処理を登録する。
+// 対象が削除済み・セーブフォルダ未設定で送る物が無い場合は ErrSyncTargetGone で取り除かせる。
+func (app *App) configureSyncQueue() {
+	app.SyncQueueService.SetHandler(domain.SyncQueueKindGamePush, func(ctx context.Context, gameID string) error {
+		game, err := app.GameService.GetGameByID(ctx, gameID)
+		if err != nil {
+			return err
+		}
+		if game == nil || game.SaveFolderPath == nil || *game.SaveFolderPath == "" {
+			return services.ErrSyncTargetGone
+		}
+		return app.ContentSyncService.Push(ctx, gameID, nil)
+	})
+	app.SyncQueueService.SetHandler(domain.SyncQueueKindMemoUpload, func(ctx context.Context, memoID string) error {
+		memo, err := app.MemoService.GetMemoByID(ctx, memoID)
+		if err != nil {
+			return err
+		}
+		if memo == nil {
+			return services.ErrSyncTargetGone
+		}
+		return app.MemoCloudService.UploadMemoToCloud(ctx, memoID)
+	})
+}
+
+// enqueueOfflineSync はオフラインで送れなかった操作を送信待ちに追加する。
+// 終了処理中でも記録できるよう、アプリのコンテキストの取り消しは引き継がない。
+func (app *App) enqueueOfflineSync(kind domain.SyncQueueKind, targetID string) {
+	if app.SyncQueueService == nil {
+		return
+	}
+	if err := app.SyncQueueService.Enqueue(context.WithoutCancel(app.context()), kind, targetID); err != nil {
+		app.Logger.Warn("送信待ちへの追加に失敗しました", "kind", kind, "targetId", targetID, "error", err)
+	}
+}
+
+// enqueueOfflinePush はオフラインで送れなかったゲームの Push を送信待ちに追加する。
+func (app *App) enqueueOfflinePush(gameID string) {
+	app.enqueueOfflineSync(domain.SyncQueueKindGamePush, gameID)
+}
+
+// replaySyncQueueAsync はオンライン時に送信待ち操作をバックグラウンドで再送する。
+func (app *App) replaySyncQueueAsync() {
+	if app.SyncQueueService == nil || app.isOffline() {
+		return
+	}
+	app.backgroundTasks.Go(func() {
+		defer logging.Recover(app.Logger, "app.replaySyncQueue")
+		ctx, op := app.Operations.Begin(app.context(), services.OperationSyncQueueReplay, "")
+		defer op.Finish()
+		if _, err := app.SyncQueueService.Replay(ctx); err != nil {
+			app.Logger.Warn("送信待ちの再送に失敗しました", "error", err)
+		}
+	})
+}
+
+// isOfflineError はオフラインモードにより同期が拒否されたエラーかを返す。
+func isOfflineError(err error) bool {
+	return errors.Is(err, services.ErrOffline)
+}
@@ -0,0 +1,77 @@
+// ゲーム画像サムネイルのサイズ設定・再生成と、カバー画像の手動設定・実行ファイルのアイコンからの設定APIを提供する。
+package app
+
+import (
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+	"CloudLaunch_Go/internal/services"
+)
+
+// UpdateThumbnailShortEdge は以降に取り込む画像のサムネイル短辺ピクセル数を更新する。
+// 既存の画像は変わらない（描き直す場合は RegenerateThumbnails を使う）。
+func (app *App) UpdateThumbnailShortEdge(shortEdge int) result.ApiResult[bool] {
+	if err := services.ValidateThumbnailShortEdge(shortEdge); err != nil {
+		app.Logger.Warn("サムネイルサイズが不正です", "operation", "UpdateThumbnailShortEdge", "value", shortEdge)
+		return result.ErrorResult[bool]("サムネイルサイズが不正です", err.Error())
+	}
+	app.Config.ThumbnailShortEdgePx = shortEdge
+	if app.ErogameScapeService != nil {
+		app.ErogameScapeService.SetThumbnailShortEdge(shortEdge)
+	}
+	app.persistSettings()
+	return result.OkResult(true)
+}
+
+// RegenerateThumbnails はサムネイルサイズを shortEdge に変更し、既存のゲーム画像を原寸画像から描き直す。
+func (app *App) RegenerateThumbnails(shortEdge int) result.ApiResult[domain.ThumbnailRegenerationResult] {
+	if updated := app.UpdateThumbnailShortEdge(shortEdge); !updated.Success {
+		return result.ErrorResult[domain.ThumbnailRegenerationResult](updated.Error.Message, updated.Error.Detail)
+	}
+	regenerated, err := app.ThumbnailService.RegenerateThumbnails(app.context(), shortEdge)
+	return serviceResult(regenerated, err, "サムネイルの再生成に失敗しました")
+}
+
+// SetGameCoverFromFile は画像ファイルを現在のサムネイルサイズに縮小してゲームのカバー画像に設定する。
+// 画像が変わると game.json の imageHash も変わるため、続けて同期して新しいカバー画像をクラウドへ送る。
+func (app *App) SetGameCoverFromFile(gameID string, filePath string) result.ApiResult[*domain.Game] {
+	updated, err := app.ThumbnailService.SetGameCoverFromFile(app.context(), gameID, filePath, app.Config.ThumbnailShortEdgePx)
+	return app.coverUpdatedResult(updated, err)
+}
+
+// SetGameCoverFromClipboard はクリップボードの画像をゲームのカバー画像に設定する（Windows のみ）。
+func (app *App) SetGameCoverFromClipboard(gameID string) result.ApiResult[*domain.Game] {
+	updated, err := app.ThumbnailService.SetGameCoverFromClipboard(app.context(), gameID, app.Config.ThumbnailShortEdgePx)
+	return app.coverUpdatedResult(updated, err)
+}
+
+func (app *App) coverUpdatedResult(updated *domain.Game, err error) result.ApiResult[*domain.Game] {
+	if err != nil {
+		return serviceErrorResult[*domain.Game](err, "カバー画像の設定に失敗しました")
+	}
+	if updated != nil {
+		app.syncGameAsync(updated.ID)
+	}
+	return result.OkResult(updated)
+}
+
+// ExtractExeIcon はゲームの実行ファイルに含まれる最大のアイコンをカバー画像に設定する（Windows のみ）。
+func (app *App) ExtractExeIcon(gameID string) result.ApiResult[*domain.Game] {
+	updated, err := app.ThumbnailService.SetGameCoverFromExeIcon(app.context(), gameID, app.Config.ThumbnailShortEdgePx)
+	return app.coverUpdatedResult(updated, err)
+}
+
+// applyExeIconCover はカバー画像の無いゲームに実行ファイルのアイコンを設定した結果を返す。
+// 取り込み時の補完のため、アイコンを取れなくてもゲームはそのまま返す。
+func (app *App) applyExeIconCover(game *domain.Game) *domain.Game {
+	if game == nil || app.ThumbnailService == nil || (game.ImagePath != nil && strings.TrimSpace(*game.ImagePath) != "") {
+		return game
+	}
+	updated, err := app.ThumbnailService.SetGameCoverFromExeIcon(app.context(), game.ID, app.Config.ThumbnailShortEdgePx)
+	if err != nil || updated == nil {
+		app.Logger.Info("実行ファイルのアイコンをカバー画像にできませんでした", "gameId", game.ID, "error", err)
+		return game
+	}
+	return updated
+}
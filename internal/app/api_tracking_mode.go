@@ -0,0 +1,39 @@
+// ゲームごとのプレイ時間計測の設定（数え方・自動計測からの除外・プロセスの一致条件）API を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// SetGameTrackingMode はゲームのプレイ時間の数え方（process|foreground）を設定する。
+// foreground ではゲームのウィンドウが前面にある間だけ数える。設定は端末ローカルで、監視中のゲームにも即座に反映する。
+func (app *App) SetGameTrackingMode(gameID string, mode string) result.ApiResult[*domain.Game] {
+	game, err := app.GameService.SetTrackingMode(app.context(), gameID, domain.TrackingMode(mode))
+	if err == nil && game != nil && app.ProcessMonitor != nil {
+		app.ProcessMonitor.SetGameTrackingMode(game.ID, game.TrackingMode)
+	}
+	return serviceResult(game, err, "プレイ時間の数え方の設定に失敗しました")
+}
+
+// SetGameAutoTrackingExcluded はゲームをプロセス監視の自動計測から外すかを設定する。
+// 実行ファイル名を共有するツール（エミュレーター等）を誤って計測しないようにするためのもので、
+// 計測中に外した場合はそこまでのセッションを保存して監視をやめる。設定は端末ローカル。
+func (app *App) SetGameAutoTrackingExcluded(gameID string, excluded bool) result.ApiResult[*domain.Game] {
+	game, err := app.GameService.SetAutoTrackingExcluded(app.context(), gameID, excluded)
+	if err == nil && game != nil && excluded && app.ProcessMonitor != nil {
+		app.ProcessMonitor.ExcludeGame(game.ID)
+	}
+	return serviceResult(game, err, "自動計測の除外設定に失敗しました")
+}
+
+// SetGameProcessMatchPattern はゲームのプロセスのコマンドラインの一致条件を設定する。空文字で解除する。
+// エミュレーター（retroarch.exe 等）で起動する ROM ごとに、ROM のファイル名などを指定して別のゲームとして数える。
+// "regex:" で始めると正規表現（大文字小文字は区別しない）、それ以外は部分一致。設定は端末ローカル。
+func (app *App) SetGameProcessMatchPattern(gameID string, pattern string) result.ApiResult[*domain.Game] {
+	game, err := app.GameService.SetProcessMatchPattern(app.context(), gameID, pattern)
+	if err == nil && game != nil && app.ProcessMonitor != nil {
+		app.ProcessMonitor.SetGameProcessMatchPattern(game.ID, game.ProcessMatchPattern)
+	}
+	return serviceResult(game, err, "プロセスの一致条件の設定に失敗しました")
+}
@@ -0,0 +1,25 @@
+// 外部の自動化へ出来事を知らせる Webhook の設定・テスト送信 API を提供する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/result"
+)
+
+// GetWebhooks は登録済みの Webhook を返す。
+func (app *App) GetWebhooks() result.ApiResult[[]domain.Webhook] {
+	webhooks, err := app.WebhookService.ListWebhooks(app.context())
+	return serviceResult(webhooks, err, "Webhook 設定の取得に失敗しました")
+}
+
+// SaveWebhooks は Webhook の一覧を丸ごと置き換えて保存し、ID を振った一覧を返す。
+// きっかけは session.ended / game.cleared / sync.finished。本文テンプレートは Go の text/template で、出力は JSON にする。
+func (app *App) SaveWebhooks(webhooks []domain.Webhook) result.ApiResult[[]domain.Webhook] {
+	saved, err := app.WebhookService.SaveWebhooks(app.context(), webhooks)
+	return serviceResult(saved, err, "Webhook 設定の保存に失敗しました")
+}
+
+// TestWebhook は保存済みの Webhook へ見本の内容を送り、届いたかを返す。
+func (app *App) TestWebhook(webhookID string) result.ApiResult[bool] {
+	return boolResult(app.WebhookService.SendTest(app.context(), webhookID), "Webhook の送信に失敗しました")
+}
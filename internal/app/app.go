@@ -4,14 +4,23 @@ package app
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/i18n"
 	"CloudLaunch_Go/internal/infrastructure/credentials"
 	"CloudLaunch_Go/internal/infrastructure/db"
+	"CloudLaunch_Go/internal/infrastructure/storage"
 	"CloudLaunch_Go/internal/logging"
 	"CloudLaunch_Go/internal/memo"
 	"CloudLaunch_Go/internal/services"
@@ -19,34 +28,81 @@ import (
 
 // App はWailsと連携するアプリケーション本体を表す。
 type App struct {
-	ctx                 context.Context
-	Config              config.Config
-	Logger              *slog.Logger
-	logLevel            *slog.LevelVar
-	GameService         *services.GameService
-	SessionService      *services.SessionService
-	RouteService        *services.RouteService
-	MemoService         *services.MemoService
-	MemoFiles           *memo.FileManager
-	CredentialService   *services.CredentialService
-	ContentSyncService  *services.ContentSyncService
-	ErogameScapeService *services.ErogameScapeService
-	ProcessMonitor      *services.ProcessMonitorService
-	ScreenshotService   *services.ScreenshotService
-	MemoCloudService    *services.MemoCloudService
-	MaintenanceService  *services.MaintenanceService
-	HotkeyService       services.HotkeyService
-	hotkeyMu            sync.Mutex
-	dbConnection        *sql.DB
-	autoTracking        bool
-	isMonitoring        bool
-	syncCoalescer       *asyncCoalescer
+	// ctx は Wails のコンテキストから作った、Shutdown で取り消されるコンテキスト。
+	// バックグラウンドの同期や保存はこれを親にし、DB を閉じた後まで動き続けないようにする。
+	ctx                    context.Context
+	cancelCtx              context.CancelFunc
+	Config                 config.Config
+	Logger                 *slog.Logger
+	logLevel               *slog.LevelVar
+	GameService            *services.GameService
+	SessionService         *services.SessionService
+	RouteService           *services.RouteService
+	RouteTemplateService   *services.RouteTemplateService
+	PlayStatusService      *services.PlayStatusService
+	GameLinkService        *services.GameLinkService
+	MemoService            *services.MemoService
+	MemoFiles              *memo.FileManager
+	MemoFileWatcher        *services.MemoFileWatcher
+	SaveFolderWatcher      *services.SaveFolderWatcher
+	CredentialService      *services.CredentialService
+	ContentSyncService     *services.ContentSyncService
+	GoogleDriveService     *services.GoogleDriveService
+	ErogameScapeService    *services.ErogameScapeService
+	ErogameScapeMatch      *services.ErogameScapeMatchService
+	MetadataService        *services.MetadataService
+	ProcessMonitor         *services.ProcessMonitorService
+	ScreenshotService      *services.ScreenshotService
+	MemoCloudService       *services.MemoCloudService
+	ScreenshotCloudService *services.ScreenshotCloudService
+	CloudPathMigration     *services.CloudPathMigrationService
+	NetworkMonitor         *services.NetworkMonitor
+	CloudConsistencyJob    *services.CloudConsistencyJob
+	Operations             *services.OperationRegistry
+	MaintenanceService     *services.MaintenanceService
+	SettingsService        *services.SettingsService
+	ChangeJournalService   *services.ChangeJournalService
+	ActivityLogService     *services.ActivityLogService
+	OnboardingService      *services.OnboardingService
+	WebhookService         *services.WebhookService
+	NotificationService    services.NotificationService
+	PlayReminderService    *services.PlayReminderService
+	AutoScreenshotService  *services.AutoScreenshotService
+	ClipService            *services.ClipService
+	SyncQueueService       *services.SyncQueueService
+	ThumbnailService       *services.ThumbnailService
+	HotkeyService          services.HotkeyService
+	hotkeyMu               sync.Mutex
+	dbConnection           *sql.DB
+	autoTracking           bool
+	offlineMode            bool
+	// autoOffline は NetworkMonitor がクラウドに到達できないと判定した状態。
+	// ユーザー設定の offlineMode とは別に持ち、永続化しない。
+	autoOffline   atomic.Bool
+	isMonitoring  bool
+	syncCoalescer *asyncCoalescer
+	// backgroundTasks は終了時や DB を閉じる前に完了を待つバックグラウンド処理。DB を開き直すたびに作り直す。
+	backgroundTasks *services.BackgroundTasks
+	// eventBus はサービス間のイベントバス。購読（フロントエンドへの中継など）を保つため DB 再オープン時にも作り直さない。
+	eventBus *services.EventBus
+	// pendingProtocolURI は起動引数で渡された cloudlaunch:// URI。Startup 後に実行する。
+	pendingProtocolURI string
 }
 
 // NewApp はアプリケーションを初期化する。
 func NewApp(ctx context.Context) (*App, error) {
+	return newApp(ctx, os.Stdout)
+}
+
+// NewHeadlessApp は画面を持たない CLI 向けにアプリケーションを初期化する。ログは標準出力ではなく logOutput へ出す。
+// Startup は呼ばないため、プロセス監視・ホットキー・フォルダ監視などの常駐処理は始まらない。終了時は Shutdown を呼ぶこと。
+func NewHeadlessApp(ctx context.Context, logOutput io.Writer) (*App, error) {
+	return newApp(ctx, logOutput)
+}
+
+func newApp(ctx context.Context, logOutput io.Writer) (*App, error) {
 	cfg := config.LoadFromEnv()
-	logger, logLevel := logging.NewLogger(cfg.AppDataDir, cfg.LogLevel)
+	logger, logLevel := logging.NewLoggerTo(cfg.AppDataDir, cfg.LogLevel, logOutput)
 
 	if error := os.MkdirAll(cfg.AppDataDir, 0o700); error != nil {
 		return nil, error
@@ -55,11 +111,11 @@ func NewApp(ctx context.Context) (*App, error) {
 		return nil, error
 	}
 
-	connection, error := db.Open(cfg.DatabasePath)
+	connection, error := db.OpenWithOptions(cfg.DatabasePath, databaseOptions(cfg))
 	if error != nil {
 		return nil, error
 	}
-	if error := db.ApplyMigrations(connection); error != nil {
+	if error := migrateDatabase(cfg, connection, logger); error != nil {
 		_ = connection.Close()
 		return nil, error
 	}
@@ -81,22 +137,91 @@ func NewApp(ctx context.Context) (*App, error) {
 		autoTracking: true,
 		isMonitoring: false,
 	}
+	app.loadPersistedSettings(repository)
 	app.configureServices(repository, credentialStore)
+	app.ChangeJournalService.PruneExpired(app.context())
+	app.ActivityLogService.PruneExpired(app.context())
 
 	logger.Info("CloudLaunch backend initialized")
 	return app, nil
 }
 
+// databaseOptions は設定から SQLite 接続の設定を作る。
+func databaseOptions(cfg config.Config) db.Options {
+	return db.Options{
+		JournalMode:  cfg.DatabaseJournalMode,
+		BusyTimeout:  time.Duration(cfg.DatabaseBusyTimeoutMs) * time.Millisecond,
+		MaxOpenConns: cfg.DatabaseMaxOpenConns,
+		MaxIdleConns: cfg.DatabaseMaxIdleConns,
+	}
+}
+
+// migrateDatabase はマイグレーションを適用する。
+// DB がこのアプリより新しいアプリで更新されていた場合は起動を止める。ただし AllowSchemaDowngrade が有効で
+// 未知のマイグレーションをすべて戻せるなら、DB ファイルを退避してから巻き戻し、このアプリのスキーマで開き直す。
+func migrateDatabase(cfg config.Config, connection *sql.DB, logger *slog.Logger) error {
+	error := db.ApplyMigrations(connection)
+	var tooNew *db.SchemaTooNewError
+	if !errors.As(error, &tooNew) {
+		return error
+	}
+	if !cfg.AllowSchemaDowngrade || !tooNew.Downgradable {
+		logger.Error("データベースのスキーマがこのアプリより新しいため起動できません",
+			"unknown", tooNew.Unknown, "downgradable", tooNew.Downgradable)
+		return error
+	}
+
+	backupPath := fmt.Sprintf("%s.pre-downgrade-%s", cfg.DatabasePath, time.Now().Format("20060102-150405"))
+	if _, error := connection.Exec(fmt.Sprintf("VACUUM INTO '%s'", strings.ReplaceAll(backupPath, "'", "''"))); error != nil {
+		return fmt.Errorf("巻き戻し前のデータベース退避に失敗: %w", error)
+	}
+	if error := db.RollbackUnknownMigrations(connection); error != nil {
+		return error
+	}
+	logger.Warn("新しいアプリのスキーマを巻き戻しました", "unknown", tooNew.Unknown, "backup", backupPath)
+	return db.ApplyMigrations(connection)
+}
+
 // Startup はWailsの起動時に呼ばれる。
 func (app *App) Startup(ctx context.Context) {
-	app.ctx = ctx
+	app.ctx, app.cancelCtx = context.WithCancel(ctx)
+	ctx = app.ctx
 	if app.ProcessMonitor != nil {
-		app.ProcessMonitor.StartMonitoring()
+		app.ProcessMonitor.StartMonitoring(ctx)
 		app.isMonitoring = app.ProcessMonitor.IsMonitoring()
 	}
 	if err := app.startHotkey(); err != nil {
 		app.Logger.Warn("ホットキーの開始に失敗しました", "error", err)
 	}
+	// 前回オフラインのまま終了した場合の送信待ちを送る。
+	app.replaySyncQueueAsync()
+	app.migrateCloudPathsAsync()
+	if app.NetworkMonitor != nil {
+		app.NetworkMonitor.Start(ctx)
+	}
+	if app.CloudConsistencyJob != nil {
+		app.CloudConsistencyJob.Start(ctx)
+	}
+	if app.WebhookService != nil {
+		app.WebhookService.Start(ctx, app.eventBus)
+	}
+	app.startMemoFileWatcher()
+	app.startSaveFolderWatcher()
+	app.startProtocolHandler()
+}
+
+// migrateCloudPathsAsync はタイトル名ベースの旧クラウドパスを ID ベースへバックグラウンドで移行する。
+// 移行が終わるまでは MemoCloudService が旧パスも読むため、起動を待たせない。
+func (app *App) migrateCloudPathsAsync() {
+	if app.CloudPathMigration == nil || app.isOffline() {
+		return
+	}
+	app.backgroundTasks.Go(func() {
+		defer logging.Recover(app.Logger, "app.migrateCloudPaths")
+		if _, err := app.CloudPathMigration.Migrate(app.context()); err != nil {
+			app.Logger.Warn("旧クラウドパスの移行に失敗しました", "error", err)
+		}
+	})
 }
 
 func (app *App) context() context.Context {
@@ -109,10 +234,35 @@ func (app *App) context() context.Context {
 // Shutdown はアプリケーションの終了処理を行う。
 func (app *App) Shutdown(ctx context.Context) error {
 	app.Logger.Info("CloudLaunch backend shutting down")
+	// 計測中のセッションの保存はアプリのコンテキストを使うため、取り消す前に監視を止める。
 	if app.ProcessMonitor != nil {
 		app.ProcessMonitor.StopMonitoring()
 	}
 	app.stopHotkey()
+	if app.NetworkMonitor != nil {
+		app.NetworkMonitor.Stop()
+	}
+	if app.CloudConsistencyJob != nil {
+		app.CloudConsistencyJob.Stop()
+	}
+	if app.MemoFileWatcher != nil {
+		app.MemoFileWatcher.Stop()
+	}
+	if app.SaveFolderWatcher != nil {
+		app.SaveFolderWatcher.Stop()
+	}
+	// 新しい処理の元を止めてから、実行中の保存・同期が DB を使い終わるのを待つ。
+	app.waitBackgroundWork()
+	app.closeWebhooks()
+	if app.cancelCtx != nil {
+		app.cancelCtx()
+	}
+	if app.NotificationService != nil {
+		app.NotificationService.Close()
+	}
+	if app.ClipService != nil {
+		app.ClipService.Close()
+	}
 	if app.ScreenshotService != nil {
 		if err := app.ScreenshotService.Close(); err != nil {
 			app.Logger.Warn("スクリーンショットログのクローズに失敗しました", "error", err)
@@ -124,25 +274,244 @@ func (app *App) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+const (
+	// shutdownGracePeriod は終了時に実行中の保存・同期が終わるのを待つ時間。
+	shutdownGracePeriod = 10 * time.Second
+	// shutdownCancelWait はコンテキストを取り消した後、処理が中断して戻るのを待つ時間。
+	shutdownCancelWait = 3 * time.Second
+)
+
+// runAutoSync は自動同期キューに積まれたゲームを Push する。
+// 終了処理でアプリのコンテキストが取り消された後に drain で回ってきた分や、終了のために中断した分は
+// 送信待ちに残し、次回起動時に再送する。
+func (app *App) runAutoSync(id string) {
+	if app.context().Err() != nil {
+		app.Logger.Info("終了処理中のため自動同期を送信待ちに追加", "gameId", id)
+		app.enqueueOfflinePush(id)
+		return
+	}
+	ctx, op := app.Operations.Begin(app.context(), services.OperationAutoSync, id)
+	defer op.Finish()
+	if err := app.ContentSyncService.PushWithConflictPolicy(ctx, id, transferProgressEmitter(ctx, op)); err != nil {
+		if errors.Is(err, context.Canceled) {
+			if app.context().Err() != nil {
+				app.Logger.Info("終了処理で中断した自動同期を送信待ちに追加", "gameId", id)
+				app.enqueueOfflinePush(id)
+				return
+			}
+			app.Logger.Info("自動同期をキャンセル", "gameId", id)
+			return
+		}
+		if isOfflineError(err) {
+			app.enqueueOfflinePush(id)
+			return
+		}
+		if errors.Is(err, services.ErrSyncConflictPending) {
+			app.notifySyncConflicts()
+			return
+		}
+		app.Logger.Warn("クラウド同期に失敗", "gameId", id, "detail", err)
+		return
+	}
+	app.markOnboardingStep(domain.OnboardingStepFirstSync)
+}
+
+// waitBackgroundWork は新しいバックグラウンド処理を受け付けなくし、実行中の処理と自動同期キューに
+// 積まれた同期が終わるのを待つ。shutdownGracePeriod を過ぎたらアプリのコンテキストを取り消して中断させ、
+// さらに shutdownCancelWait だけ待つ。それでも終わらなければ待たずに戻る。
+func (app *App) waitBackgroundWork() {
+	tasks, coalescer, bus := app.backgroundTasks, app.syncCoalescer, app.eventBus
+	tasks.Close()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// プレイ後の同期は session.ended の購読側で自動同期キューに積まれるため、配り終えるのを待ってからキューを空にする。
+		tasks.Wait()
+		bus.Wait()
+		if coalescer != nil {
+			coalescer.drain()
+		}
+	}()
+	select {
+	case <-done:
+		return
+	case <-time.After(shutdownGracePeriod):
+	}
+	app.Logger.Warn("実行中の処理が終わらないため中断します", "waited", shutdownGracePeriod)
+	if app.cancelCtx != nil {
+		app.cancelCtx()
+	}
+	select {
+	case <-done:
+	case <-time.After(shutdownCancelWait):
+		app.Logger.Warn("実行中の処理の中断を待たずに終了します")
+	}
+}
+
+// closeWebhooks は終了処理の最後に流れたイベントを配り終えてから Webhook の購読をやめ、送信中のものを
+// shutdownGracePeriod まで待つ。バックグラウンド処理が終わった後に呼ぶ。
+func (app *App) closeWebhooks() {
+	if app.WebhookService == nil {
+		return
+	}
+	app.eventBus.Wait()
+	app.WebhookService.Close()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		app.WebhookService.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownGracePeriod):
+		app.Logger.Warn("Webhook の送信を待たずに終了します")
+	}
+}
+
+// loadPersistedSettings は保存済み設定を読み込み、サービス生成前に Config と実行時フラグへ反映する。
+// サービスは Config を値コピーするため、configureServices より先に呼ぶ必要がある。
+func (app *App) loadPersistedSettings(repository *db.Repository) {
+	app.SettingsService = services.NewSettingsService(repository, app.Logger)
+	settings, err := app.SettingsService.LoadSettings(app.context(), app.currentSettings())
+	if err != nil {
+		// 読めなくても環境変数由来の設定で起動できるため、致命扱いしない。
+		app.Logger.Warn("保存済み設定の読み込みに失敗しました（既定値で起動）", "error", err)
+		return
+	}
+	settings.ApplyTo(&app.Config)
+	app.autoTracking = settings.AutoTracking
+	app.offlineMode = settings.OfflineMode
+	if app.logLevel != nil {
+		app.logLevel.Set(logging.ParseLevel(app.Config.LogLevel))
+	}
+}
+
+// dbTxRunner は repository.WithTx をサービス向けの TxRunner に変換する。
+// bind はトランザクションに束ねた Repository をサービスのリポジトリ境界として渡すためのもの。
+func dbTxRunner[R any](repository *db.Repository, bind func(tx *db.Repository) R) services.TxRunner[R] {
+	return func(ctx context.Context, fn func(R) error) error {
+		return repository.WithTx(ctx, func(tx *db.Repository) error { return fn(bind(tx)) })
+	}
+}
+
+// dbReadTxRunner は repository.WithReadTx をサービス向けの TxRunner に変換する。
+func dbReadTxRunner[R any](repository *db.Repository, bind func(tx *db.Repository) R) services.TxRunner[R] {
+	return func(ctx context.Context, fn func(R) error) error {
+		return repository.WithReadTx(ctx, func(tx *db.Repository) error { return fn(bind(tx)) })
+	}
+}
+
 func (app *App) configureServices(repository *db.Repository, credentialStore credentials.Store) {
+	if err := storage.SetMultipartPartSizeMB(app.Config.S3MultipartPartSizeMB); err != nil {
+		app.Logger.Warn("パートサイズが不正です（既定値を使用）", "value", app.Config.S3MultipartPartSizeMB, "error", err)
+	}
+	if err := i18n.SetLanguage(app.Config.Language); err != nil {
+		app.Logger.Warn("表示言語が不正です（既定値を使用）", "value", app.Config.Language, "error", err)
+	}
+	if app.eventBus == nil {
+		app.eventBus = services.NewEventBus(app.Logger)
+		app.eventBus.SubscribeAll(app.emitBusEvent)
+		app.eventBus.Subscribe(services.EventSessionEnded, app.handleSessionEnded)
+	}
 	app.GameService = services.NewGameService(repository, app.Logger)
+	app.GameService.SetTxRunner(dbTxRunner(repository, func(tx *db.Repository) services.GameRepository { return tx }))
+	app.GameService.SetEventBus(app.eventBus)
 	app.SessionService = services.NewSessionService(repository, app.Logger)
+	app.SessionService.SetTxRunner(dbTxRunner(repository, func(tx *db.Repository) services.SessionRepository { return tx }))
+	app.SessionService.SetEventBus(app.eventBus)
 	app.RouteService = services.NewRouteService(repository, app.Logger)
+	app.RouteTemplateService = services.NewRouteTemplateService(repository, app.Logger)
+	app.RouteTemplateService.SetTxRunner(dbTxRunner(repository, func(tx *db.Repository) services.RouteTemplateRepository { return tx }))
+	app.GameLinkService = services.NewGameLinkService(repository, app.Logger)
+	app.PlayStatusService = services.NewPlayStatusService(repository, app.Logger)
 	app.MemoService = services.NewMemoService(repository, app.MemoFiles, app.Logger)
 	app.CredentialService = services.NewCredentialService(credentialStore, app.Logger)
+	app.ChangeJournalService = services.NewChangeJournalService(repository, app.Logger)
+	app.ActivityLogService = services.NewActivityLogService(repository, app.Logger)
+	app.OnboardingService = services.NewOnboardingService(repository, app.Logger)
 	app.ContentSyncService = services.NewContentSyncService(app.Config, credentialStore, repository, app.Logger)
-	app.syncCoalescer = newAsyncCoalescer(func(id string) {
-		if err := app.ContentSyncService.Push(app.context(), id, nil); err != nil {
-			app.Logger.Warn("クラウド同期に失敗", "gameId", id, "detail", err)
-		}
-	})
+	app.ContentSyncService.SetOfflineMode(app.isOffline())
+	app.ContentSyncService.SetEventBus(app.eventBus)
+	app.ContentSyncService.SetConflictPolicy(app.Config.SyncConflictPolicy)
+	// Google ドライブの接続は DB に依存せず、取得済みのアクセストークンを使い回すため、DB 再オープン時には作り直さない。
+	if app.GoogleDriveService == nil {
+		app.GoogleDriveService = services.NewGoogleDriveService(app.Config, newGoogleDriveCredentialStore(app.Config), app.Logger)
+	}
+	app.ContentSyncService.SetGoogleDrive(app.GoogleDriveService)
+	// デバイスIDは初回起動時に確定させ、初回 push 前でも設定画面・ログで参照できるようにする。
+	if identity, err := app.ContentSyncService.DeviceIdentity(app.context()); err != nil {
+		app.Logger.Warn("デバイスIDの初期化に失敗", "error", err)
+	} else {
+		app.Logger.Info("デバイス情報", "deviceId", identity.ID, "deviceName", identity.Name)
+	}
+	app.SyncQueueService = services.NewSyncQueueService(repository, app.Logger)
+	app.syncCoalescer = newAsyncCoalescer(autoSyncDebounceWindow, app.runAutoSync)
 	app.syncCoalescer.onPanic = func(id string, recovered any) {
 		app.Logger.Error("クラウド同期中に panic を回収", "gameId", id, "recovered", recovered)
 	}
 	app.ErogameScapeService = services.NewErogameScapeService(app.Config, app.Logger)
-	app.ProcessMonitor = services.NewProcessMonitorService(repository, app.Logger, app.ContentSyncService)
+	app.ErogameScapeMatch = services.NewErogameScapeMatchService(app.ErogameScapeService, repository, app.Logger)
+	app.MetadataService = services.NewMetadataService(app.ErogameScapeService, newMetadataCredentialStore(app.Config), app.Logger)
+	app.ThumbnailService = services.NewThumbnailService(repository, app.Config.AppDataDir, app.Logger)
+	app.backgroundTasks = services.NewBackgroundTasks()
+	app.ProcessMonitor = services.NewProcessMonitorService(repository, app.Logger)
+	app.ProcessMonitor.SetBackgroundTasks(app.backgroundTasks)
+	app.ProcessMonitor.SetEventBus(app.eventBus)
+	repository.OnGamesChanged(app.ProcessMonitor.InvalidateGamesCache)
+	app.ProcessMonitor.SetTxRunner(dbTxRunner(repository, func(tx *db.Repository) services.ProcessMonitorRepository { return tx }))
+	app.ProcessMonitor.SetSessionSpoolDir(filepath.Join(app.Config.AppDataDir, services.SessionSpoolDirName))
+	app.ProcessMonitor.SetInterval(time.Duration(app.Config.MonitorIntervalSeconds) * time.Second)
+	app.ProcessMonitor.SetWarmupPolicy(app.Config.MonitorWarmupPolicy)
+	app.ProcessMonitor.SetIdleThreshold(time.Duration(app.Config.MonitorIdleThresholdMinutes) * time.Minute)
+	app.ProcessMonitor.SetSplitAtMidnight(app.Config.MonitorSplitAtMidnight)
+	app.ProcessMonitor.SetTimeouts(
+		time.Duration(app.Config.MonitorSessionTimeoutSeconds)*time.Second,
+		time.Duration(app.Config.MonitorCleanupTimeoutSeconds)*time.Second,
+	)
+	app.ProcessMonitor.SetWarmupListener(app.handleWarmupPrompt)
+	// 通知は DB に依存せずアイコンを持ち続けるため、DB 再オープン時には作り直さない。
+	if app.NotificationService == nil {
+		app.NotificationService = services.NewNotificationService(app.Logger)
+	}
+	app.PlayReminderService = services.NewPlayReminderService(repository, app.NotificationService, app.Config.PlayReminderMinutes, app.Logger)
+	app.ProcessMonitor.SetScanListener(app.handleMonitorScan)
+	app.ProcessMonitor.UpdateAutoTracking(app.autoTracking)
 	app.ScreenshotService = services.NewScreenshotService(app.Config, repository, app.ProcessMonitor, app.Logger)
+	app.ScreenshotService.SetRecentGameTracker(app.ProcessMonitor)
+	app.ScreenshotService.SetEventBus(app.eventBus)
+	app.AutoScreenshotService = services.NewAutoScreenshotService(repository, app.ScreenshotService, app.Logger)
+	// 録画中の ffmpeg は旧インスタンスが持つため、作り直す前に止める。
+	if app.ClipService != nil {
+		app.ClipService.Close()
+	}
+	app.ClipService = services.NewClipService(app.Config, repository, app.ProcessMonitor, app.Logger)
 	app.MemoCloudService = services.NewMemoCloudService(app.Config, credentialStore, app.GameService, app.MemoService, app.Logger)
+	// DB の Repository を参照するため、DB 再オープン時は作り直す（旧監視は復元前に停止済み）。
+	app.MemoFileWatcher = services.NewMemoFileWatcher(repository, app.MemoFiles, app.Logger)
+	app.MemoFileWatcher.SetOnUpdated(app.handleMemoFileEdited)
+	app.SaveFolderWatcher = services.NewSaveFolderWatcher(repository, app.Logger)
+	app.SaveFolderWatcher.SetOnChanged(app.handleSaveFolderChanged)
+	app.SaveFolderWatcher.SetPlayingChecker(app.ProcessMonitor.HasActiveSession)
+	app.ScreenshotCloudService = services.NewScreenshotCloudService(app.Config, credentialStore, app.Logger)
+	app.CloudPathMigration = services.NewCloudPathMigrationService(app.Config, credentialStore, repository, app.Logger)
+	// NetworkMonitor は DB に依存せず監視ループを持つため、DB 再オープン時には作り直さない。
+	if app.NetworkMonitor == nil {
+		app.NetworkMonitor = services.NewNetworkMonitor(app.Config, credentialStore, app.Logger)
+		app.NetworkMonitor.SetOnChange(app.handleNetworkStatus)
+	}
+	// 実行中の処理一覧は DB に依存しないため、DB 再オープン時にも引き継ぐ。
+	if app.Operations == nil {
+		app.Operations = services.NewOperationRegistry()
+		app.Operations.SetOnChange(app.handleOperationsChanged)
+	}
+	// ContentSyncService を参照するため、DB 再オープン時は作り直す（旧ジョブは復元前に停止済み）。
+	app.CloudConsistencyJob = services.NewCloudConsistencyJob(app.ContentSyncService, app.Logger)
+	app.CloudConsistencyJob.SetOnReport(app.handleCloudConsistencyReport)
+	// DB の Repository を参照するため、DB 再オープン時は作り直す（旧インスタンスは復元前に購読を解除済み）。
+	app.WebhookService = services.NewWebhookService(app.Config, repository, app.Logger)
+	// 終了処理で最後に流れる同期・セッションのイベントも送れるよう、アプリの処理とは別に数えて後から待つ。
+	app.WebhookService.SetBackgroundTasks(services.NewBackgroundTasks())
+	app.configureSyncQueue()
 	app.MaintenanceService = services.NewMaintenanceService(
 		app.Config,
 		repository,
@@ -155,4 +524,5 @@ func (app *App) configureServices(repository *db.Repository, credentialStore cre
 			ResumeRuntimeServices:     app.resumeRuntimeServicesAfterRestore,
 		},
 	)
+	app.MaintenanceService.SetReadTxRunner(dbReadTxRunner(repository, func(tx *db.Repository) services.MaintenanceRepository { return tx }))
 }
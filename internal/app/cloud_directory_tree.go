@@ -0,0 +1,112 @@
+// クラウドデータ管理画面向けに、論理ファイル一覧から階層ディレクトリツリーを組み立てる処理を提供する。
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"CloudLaunch_Go/internal/services"
+)
+
+// dirTreeNode はツリー構築中のノード。子はポインタで持ち、ファイルを1件加えるたびに祖先のサイズを直接更新する。
+type dirTreeNode struct {
+	node     CloudDirectoryNode
+	children map[string]*dirTreeNode
+}
+
+// buildGameDirectoryNode は1ゲームの論理ファイル一覧から階層ディレクトリツリーを構築する。
+// トップノードはゲーム（IsDirectory=true, Path=gameID）で、配下にセーブファイルの階層を持つ。
+// ディレクトリの Size は配下のファイルの合計で、子はディレクトリ→ファイルの順にそれぞれ名前順で並べる。
+func buildGameDirectoryNode(view services.CloudGameView) CloudDirectoryNode {
+	root := newDirTreeNode(view.Title, view.GameID, true)
+	root.node.LastModified = view.LastModified
+	for _, file := range view.Files {
+		root.addFile(view.GameID, file, view)
+	}
+	return root.finalize()
+}
+
+func newDirTreeNode(name, nodePath string, isDirectory bool) *dirTreeNode {
+	return &dirTreeNode{
+		node:     CloudDirectoryNode{Name: name, Path: nodePath, IsDirectory: isDirectory},
+		children: map[string]*dirTreeNode{},
+	}
+}
+
+// addFile は relPath の途中のディレクトリを作りながらファイルを1件加え、通った各ディレクトリに Size を足す。
+// 同じパスのファイルが重複していれば後のもので置き換え、同名のファイルとディレクトリは別ノードとして両方残す。
+func (root *dirTreeNode) addFile(gameID string, file services.CloudLogicalFile, view services.CloudGameView) {
+	segments := splitTreePath(file.RelPath)
+	if len(segments) == 0 {
+		return
+	}
+	ancestors := make([]*dirTreeNode, 0, len(segments))
+	current := root
+	currentPath := gameID
+	for _, segment := range segments[:len(segments)-1] {
+		currentPath += "/" + segment
+		ancestors = append(ancestors, current)
+		current = current.child(segment, currentPath, true)
+		current.node.LastModified = view.LastModified
+	}
+	ancestors = append(ancestors, current)
+
+	name := segments[len(segments)-1]
+	leaf := current.child(name, currentPath+"/"+name, false)
+	delta := file.Size - leaf.node.Size
+	leaf.node.Size = file.Size
+	leaf.node.LastModified = view.LastModified
+	for _, ancestor := range ancestors {
+		ancestor.node.Size += delta
+	}
+}
+
+// child は名前と種別が一致する子を返し、無ければ作る。
+func (parent *dirTreeNode) child(name, nodePath string, isDirectory bool) *dirTreeNode {
+	key := name
+	if isDirectory {
+		key += "/"
+	}
+	if existing, ok := parent.children[key]; ok {
+		return existing
+	}
+	created := newDirTreeNode(name, nodePath, isDirectory)
+	parent.children[key] = created
+	return created
+}
+
+// finalize はツリーを CloudDirectoryNode に変換する。ディレクトリの Children は空でも nil にしない。
+func (tree *dirTreeNode) finalize() CloudDirectoryNode {
+	node := tree.node
+	if !node.IsDirectory {
+		return node
+	}
+	ordered := make([]*dirTreeNode, 0, len(tree.children))
+	for _, child := range tree.children {
+		ordered = append(ordered, child)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].node.IsDirectory != ordered[j].node.IsDirectory {
+			return ordered[i].node.IsDirectory
+		}
+		return ordered[i].node.Name < ordered[j].node.Name
+	})
+	node.Children = make([]CloudDirectoryNode, 0, len(ordered))
+	for _, child := range ordered {
+		node.Children = append(node.Children, child.finalize())
+	}
+	return node
+}
+
+// splitTreePath は相対パスを区切り（"/" と "\"）で分け、空・"." のセグメントを取り除く。
+func splitTreePath(relPath string) []string {
+	parts := strings.Split(strings.ReplaceAll(relPath, `\`, "/"), "/")
+	segments := parts[:0]
+	for _, part := range parts {
+		if part == "" || part == "." {
+			continue
+		}
+		segments = append(segments, part)
+	}
+	return segments
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/services"
+)
+
+func findTreeChild(t *testing.T, node CloudDirectoryNode, name string, isDirectory bool) CloudDirectoryNode {
+	t.Helper()
+	for _, child := range node.Children {
+		if child.Name == name && child.IsDirectory == isDirectory {
+			return child
+		}
+	}
+	t.Fatalf("child %q (dir=%v) not found under %q: %+v", name, isDirectory, node.Path, node.Children)
+	return CloudDirectoryNode{}
+}
+
+func TestBuildGameDirectoryNodeKeepsNestedChildren(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	root := buildGameDirectoryNode(services.CloudGameView{
+		GameID:       "g1",
+		Title:        "Game",
+		LastModified: modified,
+		Files: []services.CloudLogicalFile{
+			{RelPath: "slot1/data/save.dat", Size: 10},
+			{RelPath: "slot1/data/extra/deep.dat", Size: 5},
+			{RelPath: "slot1/thumb.png", Size: 3},
+			{RelPath: "slot2/save.dat", Size: 7},
+			{RelPath: "config.ini", Size: 1},
+		},
+	})
+
+	if !root.IsDirectory || root.Path != "g1" || root.Name != "Game" || root.Size != 26 {
+		t.Fatalf("unexpected root: %+v", root)
+	}
+	// ディレクトリが先、ファイルが後で、それぞれ名前順。
+	if len(root.Children) != 3 || root.Children[0].Name != "slot1" || root.Children[1].Name != "slot2" || root.Children[2].Name != "config.ini" {
+		t.Fatalf("unexpected root children: %+v", root.Children)
+	}
+	slot1 := findTreeChild(t, root, "slot1", true)
+	if slot1.Size != 18 || slot1.Path != "g1/slot1" {
+		t.Fatalf("unexpected slot1: %+v", slot1)
+	}
+	data := findTreeChild(t, slot1, "data", true)
+	if data.Size != 15 || len(data.Children) != 2 {
+		t.Fatalf("unexpected data dir: %+v", data)
+	}
+	extra := findTreeChild(t, data, "extra", true)
+	deep := findTreeChild(t, extra, "deep.dat", false)
+	if deep.Size != 5 || deep.Path != "g1/slot1/data/extra/deep.dat" || !deep.LastModified.Equal(modified) || deep.Children != nil {
+		t.Fatalf("unexpected leaf: %+v", deep)
+	}
+	if save := findTreeChild(t, data, "save.dat", false); save.Size != 10 {
+		t.Fatalf("unexpected save.dat: %+v", save)
+	}
+}
+
+func TestBuildGameDirectoryNodeHandlesEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	root := buildGameDirectoryNode(services.CloudGameView{
+		GameID: "g1",
+		Files: []services.CloudLogicalFile{
+			{RelPath: "a", Size: 2},
+			{RelPath: "a/b.dat", Size: 4},
+			{RelPath: `win\path.dat`, Size: 1},
+			{RelPath: "dup.dat", Size: 9},
+			{RelPath: "dup.dat", Size: 3},
+			{RelPath: "", Size: 100},
+		},
+	})
+	if root.Size != 2+4+1+3 {
+		t.Fatalf("sizes should not double count duplicates or empty paths: %d", root.Size)
+	}
+	// 同名のファイルとディレクトリはどちらも残す。
+	if dir := findTreeChild(t, root, "a", true); dir.Size != 4 || len(dir.Children) != 1 {
+		t.Fatalf("unexpected dir a: %+v", dir)
+	}
+	if file := findTreeChild(t, root, "a", false); file.Size != 2 {
+		t.Fatalf("unexpected file a: %+v", file)
+	}
+	findTreeChild(t, findTreeChild(t, root, "win", true), "path.dat", false)
+	if dup := findTreeChild(t, root, "dup.dat", false); dup.Size != 3 {
+		t.Fatalf("duplicate path should keep the later size: %+v", dup)
+	}
+
+	empty := buildGameDirectoryNode(services.CloudGameView{GameID: "g2"})
+	if empty.Children == nil || len(empty.Children) != 0 {
+		t.Fatalf("empty game should have an empty children slice: %+v", empty)
+	}
+}
+
+func TestBuildGameDirectoryNodeLargeTree(t *testing.T) {
+	t.Parallel()
+
+	files := make([]services.CloudLogicalFile, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		files = append(files, services.CloudLogicalFile{RelPath: fmt.Sprintf("d%d/e%d/f%d.dat", i%10, i%100, i), Size: 1})
+	}
+	root := buildGameDirectoryNode(services.CloudGameView{GameID: "g1", Files: files})
+	if root.Size != 2000 || len(root.Children) != 10 {
+		t.Fatalf("unexpected root: size=%d children=%d", root.Size, len(root.Children))
+	}
+	var count func(node CloudDirectoryNode) int
+	count = func(node CloudDirectoryNode) int {
+		if !node.IsDirectory {
+			return 1
+		}
+		total := 0
+		for _, child := range node.Children {
+			total += count(child)
+		}
+		return total
+	}
+	if got := count(root); got != 2000 {
+		t.Fatalf("all files should be reachable from the root, got %d", got)
+	}
+}
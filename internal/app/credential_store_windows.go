@@ -11,3 +11,11 @@ import (
 func newCredentialStore(cfg config.Config) credentials.Store {
 	return credentials.NewWindowsStore(cfg.CredentialNamespace)
 }
+
+func newMetadataCredentialStore(cfg config.Config) credentials.Store {
+	return credentials.NewWindowsStore(metadataCredentialNamespace(cfg))
+}
+
+func newGoogleDriveCredentialStore(cfg config.Config) credentials.Store {
+	return credentials.NewWindowsStore(googleDriveCredentialNamespace(cfg))
+}
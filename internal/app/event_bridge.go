@@ -0,0 +1,19 @@
+// サービスのイベントバスをフロントエンドの Wails イベントへ中継する。
+package app
+
+import (
+	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// busEventPrefix はイベントバスのイベントをフロントエンドへ送るときのイベント名の接頭辞。
+// "game.updated" は "bus:game.updated" として届く（既存の "screenshot:captured" などとは別物）。
+const busEventPrefix = "bus:"
+
+// emitBusEvent はイベントバスのイベントを、ペイロードをそのまま載せてフロントエンドへ送る。
+func (app *App) emitBusEvent(event services.Event) {
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, busEventPrefix+string(event.Topic), event.Payload)
+	}
+}
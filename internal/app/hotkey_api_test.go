@@ -130,5 +130,36 @@ func TestUpdateScreenshotHotkeyAcceptsF8(t *testing.T) {
 	}
 }
 
+func TestUpdateHotkeyActionsRejectsScreenshotConflict(t *testing.T) {
+	app := &App{
+		Config: config.Config{ScreenshotHotkey: "Ctrl+Alt+S"},
+		Logger: slog.Default(),
+	}
+
+	result := app.UpdateHotkeyActions(config.HotkeyActions{PauseResume: "Ctrl+Alt+S"})
+	if result.Success {
+		t.Fatal("expected failure for conflicting hotkey")
+	}
+	if app.Config.HotkeyActions != (config.HotkeyActions{}) {
+		t.Fatalf("config should stay unchanged, got %+v", app.Config.HotkeyActions)
+	}
+
+	result = app.UpdateHotkeyActions(config.HotkeyActions{PauseResume: " Ctrl+Alt+P "})
+	if !result.Success {
+		t.Fatalf("expected success, got %#v", result)
+	}
+	if app.Config.HotkeyActions.PauseResume != "Ctrl+Alt+P" {
+		t.Fatalf("config not updated: %+v", app.Config.HotkeyActions)
+	}
+	if bindings := app.hotkeyBindings(); len(bindings) != 1 || bindings[0].Action != services.HotkeyActionPauseResume {
+		t.Fatalf("unexpected bindings: %+v", bindings)
+	}
+
+	// 割り当て済みのキーはスクリーンショットのホットキーにできない。
+	if result := app.UpdateScreenshotHotkey("Ctrl+Alt+P"); result.Success {
+		t.Fatal("expected screenshot hotkey conflict")
+	}
+}
+
 // Ensure stub satisfies interface at compile time.
 var _ services.HotkeyService = (*stubHotkeyService)(nil)
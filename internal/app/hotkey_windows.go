@@ -8,8 +8,13 @@ import (
 	"strings"
 
 	"CloudLaunch_Go/internal/services"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// screenshotCapturedEvent はホットキー撮影が完了したときにフロントエンドへ送るイベント名（CaptureResult を渡す）。
+const screenshotCapturedEvent = "screenshot:captured"
+
 func (app *App) startHotkey() error {
 	app.hotkeyMu.Lock()
 	defer app.hotkeyMu.Unlock()
@@ -45,12 +50,14 @@ func (app *App) stopHotkeyLocked() {
 
 func (app *App) startHotkeyService(combo string, handler services.HotkeyHandler) (services.HotkeyService, error) {
 	trimmed := strings.TrimSpace(combo)
-	if trimmed == "" {
+	bindings := app.hotkeyBindings()
+	if trimmed == "" && len(bindings) == 0 {
 		return nil, errors.New("hotkey is not configured")
 	}
 	config := services.HotkeyConfig{
-		Combo:  trimmed,
-		Notify: app.Config.ScreenshotHotkeyNotify,
+		Combo:    trimmed,
+		Notify:   app.Config.ScreenshotHotkeyNotify,
+		Bindings: bindings,
 	}
 	service := services.NewHotkeyService(app.Logger, config, handler)
 	if service == nil {
@@ -71,12 +78,16 @@ func (app *App) handleHotkeyCapture() bool {
 	if app.ProcessMonitor != nil {
 		hotkeyTargetGameID = app.ProcessMonitor.GetHotkeyTargetGameID()
 	}
-	gameID, path, err := app.ScreenshotService.CaptureHotkey(app.context(), hotkeyTargetGameID)
+	captured, err := app.ScreenshotService.CaptureHotkey(app.context(), hotkeyTargetGameID)
 	if err != nil {
 		app.Logger.Error("ホットキーキャプチャに失敗", "error", err)
 		return false
 	}
-	app.syncScreenshotAfterHotkey(gameID, path)
+	if app.ctx != nil {
+		wailsruntime.EventsEmit(app.ctx, screenshotCapturedEvent, captured)
+	}
+	app.copyCaptureToClipboard(captured.Path)
+	app.syncScreenshotAfterHotkey(captured.GameID, captured.Path)
 	return true
 }
 
@@ -20,6 +20,19 @@ func (app *App) startHotkeyLocked() error {
 
 func (app *App) stopHotkeyLocked() {}
 
+// handleHotkeyCapture は非Windowsでは撮影できないため常に失敗を返す。
+func (app *App) handleHotkeyCapture() bool {
+	return false
+}
+
 func newCredentialStore(cfg config.Config) credentials.Store {
 	return credentials.NewUnsupportedStore(cfg.CredentialNamespace)
 }
+
+func newMetadataCredentialStore(cfg config.Config) credentials.Store {
+	return credentials.NewUnsupportedStore(metadataCredentialNamespace(cfg))
+}
+
+func newGoogleDriveCredentialStore(cfg config.Config) credentials.Store {
+	return credentials.NewUnsupportedStore(googleDriveCredentialNamespace(cfg))
+}
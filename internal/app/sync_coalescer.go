@@ -1,95 +1,177 @@
-// 同一キーの非同期タスクを直列化し、実行中の再要求を1回に畳み込む仕組みを提供する。
+// キー（gameID）ごとの非同期タスクを一定時間まとめてから1つずつ順に実行する自動同期キューを提供する。
 package app
 
-import "sync"
+import (
+	"sort"
+	"sync"
+	"time"
+)
 
-// asyncCoalescer は同一キー（gameID）のタスクを直列実行し、実行中に来た再要求を
-// 完了後に1回だけ再実行する（coalescing）。異なるキーは並行に実行される。
+// asyncCoalescer は自動同期キュー。trigger された id は window だけ待ってから実行し、その間に来た同じ id の
+// 要求は1回にまとめる（debounce）。実行は全キーを通して1つずつ行い、実行予定の早い順に進める。
+// 実行中の id に来た要求は改めてキューに入り、今の実行が終わってから1回だけ実行する。
 type asyncCoalescer struct {
-	mu       sync.Mutex
-	inFlight map[string]bool
-	pending  map[string]bool
-	stopped  bool
-	wg       sync.WaitGroup
-	run      func(id string)
+	mu      sync.Mutex
+	window  time.Duration
+	queued  map[string]time.Time // id → 実行予定時刻
+	running string               // 実行中の id（無ければ ""）
+	since   time.Time            // running の開始時刻
+	closed  bool                 // stop・drain 後は trigger を受け付けない
+	working bool                 // worker goroutine が動いているか
+	wake    chan struct{}
+	wg      sync.WaitGroup
+	run     func(id string)
 	// onPanic は run が panic を起こした際に回収した値とともに呼ばれる（任意）。
 	onPanic func(id string, recovered any)
 }
 
-// newAsyncCoalescer は run を実行関数とする asyncCoalescer を生成する。
-func newAsyncCoalescer(run func(id string)) *asyncCoalescer {
+// newAsyncCoalescer は run を実行関数とし、window だけ要求をまとめる asyncCoalescer を生成する。
+// window が 0 なら待たずに実行する（実行は直列のまま）。
+func newAsyncCoalescer(window time.Duration, run func(id string)) *asyncCoalescer {
 	return &asyncCoalescer{
-		inFlight: make(map[string]bool),
-		pending:  make(map[string]bool),
-		run:      run,
+		window: window,
+		queued: make(map[string]time.Time),
+		wake:   make(chan struct{}, 1),
+		run:    run,
 	}
 }
 
-// trigger は id のタスクを要求する。既に実行中なら完了後の再実行をマークするだけで即座に返る。
+// trigger は id のタスクを要求する。既に待っている同じ id があればそれにまとめ、即座に返る。
 // stop 後の trigger は no-op となる（バックアップ復元中などに新規同期が走るのを防ぐ）。
 func (c *asyncCoalescer) trigger(id string) {
 	c.mu.Lock()
-	if c.stopped {
-		c.mu.Unlock()
+	defer c.mu.Unlock()
+	if c.closed {
 		return
 	}
-	if c.inFlight[id] {
-		c.pending[id] = true
-		c.mu.Unlock()
+	if _, ok := c.queued[id]; ok {
 		return
 	}
-	c.inFlight[id] = true
+	c.queued[id] = time.Now().Add(c.window)
+	if c.working {
+		c.notify()
+		return
+	}
+	c.working = true
 	c.wg.Add(1)
-	c.mu.Unlock()
 	go func() {
 		defer c.wg.Done()
-		c.loop(id)
+		c.work()
 	}()
 }
 
-// stop は新規 trigger を無効化し、実行中の loop が完了するまで待つ。
+// notify は待機中の worker を起こす。c.mu を保持した状態で呼ぶ。
+func (c *asyncCoalescer) notify() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// stop は新規 trigger を無効化し、待っているタスクを捨てて実行中のタスクが完了するまで待つ。
 // 戻った後は run が呼ばれることはない。バックアップ復元のように DB 接続を閉じる前に
 // 同期 goroutine を確実に静止させる用途で使う。多重呼び出しは安全。
 func (c *asyncCoalescer) stop() {
 	c.mu.Lock()
-	c.stopped = true
+	c.closed = true
+	c.queued = make(map[string]time.Time)
+	c.notify()
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+// drain は新規 trigger を無効化し、待っているタスクを window を待たずに実行して、すべて完了するまで待つ。
+// アプリの終了時に、プレイ直後のセーブのアップロードなど受け付け済みの同期を捨てずに済ませる用途で使う。
+func (c *asyncCoalescer) drain() {
+	c.mu.Lock()
+	c.closed = true
+	now := time.Now()
+	for id := range c.queued {
+		c.queued[id] = now
+	}
+	c.notify()
 	c.mu.Unlock()
 	c.wg.Wait()
 }
 
-// loop は id のタスクを実行し、実行中に再要求があれば1回だけ追加実行してから終了する。
-func (c *asyncCoalescer) loop(id string) {
+// status は実行中のタスクと、待っているタスクを実行予定の早い順に返す。
+func (c *asyncCoalescer) status() AutoSyncQueueStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := AutoSyncQueueStatus{Waiting: make([]AutoSyncQueueItem, 0, len(c.queued))}
+	if c.running != "" {
+		since := c.since
+		res.RunningGameID = c.running
+		res.RunningSince = &since
+	}
+	for id, due := range c.queued {
+		res.Waiting = append(res.Waiting, AutoSyncQueueItem{GameID: id, DueAt: due})
+	}
+	sort.Slice(res.Waiting, func(i, j int) bool {
+		if !res.Waiting[i].DueAt.Equal(res.Waiting[j].DueAt) {
+			return res.Waiting[i].DueAt.Before(res.Waiting[j].DueAt)
+		}
+		return res.Waiting[i].GameID < res.Waiting[j].GameID
+	})
+	return res
+}
+
+// work は待っているタスクを実行予定の順に1つずつ実行し、無くなったら終了する。
+func (c *asyncCoalescer) work() {
 	for {
-		if c.runSafely(id) {
-			// run が panic した場合は再実行せず（pending を消化すると panic ループに
-			// なりうる）、inFlight を確実にクリアして終了する。次回 trigger で再開できる。
-			c.mu.Lock()
-			delete(c.pending, id)
-			delete(c.inFlight, id)
-			c.mu.Unlock()
+		id, wait, ok := c.next()
+		if !ok {
 			return
 		}
-		c.mu.Lock()
-		// stop 中なら pending を消化せず即座に終了する（DB が閉じられる前に静止する）。
-		if c.stopped {
-			delete(c.pending, id)
-			delete(c.inFlight, id)
-			c.mu.Unlock()
-			return
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-c.wake:
+				timer.Stop()
+			}
+			continue
 		}
-		if c.pending[id] {
-			delete(c.pending, id)
+		if c.runSafely(id) {
+			// run が panic した場合は、実行中に来た同じ id の要求も捨てる（消化すると panic ループに
+			// なりうる）。次回 trigger で再開できる。
+			c.mu.Lock()
+			delete(c.queued, id)
 			c.mu.Unlock()
-			continue
 		}
-		delete(c.inFlight, id)
+		c.mu.Lock()
+		c.running = ""
 		c.mu.Unlock()
-		return
 	}
 }
 
-// runSafely は run を実行し、panic を回収する。panic したら true を返し、loop 側で
-// inFlight を確実にクリアして同期が永久にブロックされるのを防ぐ。
+// next は次に実行する id を取り出す。実行予定まで間があれば取り出さずに待ち時間を返す。
+// タスクが無ければ worker の終了を記録して ok=false を返す。
+func (c *asyncCoalescer) next() (id string, wait time.Duration, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.queued) == 0 {
+		c.working = false
+		return "", 0, false
+	}
+	var due time.Time
+	for candidate, at := range c.queued {
+		if id == "" || at.Before(due) || (at.Equal(due) && candidate < id) {
+			id, due = candidate, at
+		}
+	}
+	now := time.Now()
+	if wait := due.Sub(now); wait > 0 {
+		return "", wait, true
+	}
+	delete(c.queued, id)
+	c.running = id
+	c.since = now
+	return id, 0, true
+}
+
+// runSafely は run を実行し、panic を回収する。panic したら true を返し、キューが
+// 止まったままにならないようにする。
 func (c *asyncCoalescer) runSafely(id string) (panicked bool) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -1,9 +1,12 @@
 package app
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
+
+	"CloudLaunch_Go/internal/domain"
 )
 
 // TestAsyncCoalescerCoalescesWhileInFlight は、実行中に来た複数の再要求が
@@ -13,7 +16,7 @@ func TestAsyncCoalescerCoalescesWhileInFlight(t *testing.T) {
 	release := make(chan struct{})
 	var mu sync.Mutex
 	calls := 0
-	c := newAsyncCoalescer(func(_ string) {
+	c := newAsyncCoalescer(0, func(_ string) {
 		mu.Lock()
 		calls++
 		n := calls
@@ -55,7 +58,7 @@ func TestAsyncCoalescerCoalescesWhileInFlight(t *testing.T) {
 // 各要求がそれぞれ実行されることを確認する（畳み込みは実行中の場合のみ）。
 func TestAsyncCoalescerRunsOncePerTriggerWhenSequential(t *testing.T) {
 	doneCh := make(chan struct{}, 8)
-	c := newAsyncCoalescer(func(_ string) {
+	c := newAsyncCoalescer(0, func(_ string) {
 		doneCh <- struct{}{}
 	})
 
@@ -74,31 +77,79 @@ func TestAsyncCoalescerRunsOncePerTriggerWhenSequential(t *testing.T) {
 	}
 }
 
-// TestAsyncCoalescerDifferentKeysRunConcurrently は異なるキーが並行実行されることを確認する。
-func TestAsyncCoalescerDifferentKeysRunConcurrently(t *testing.T) {
-	bothStarted := make(chan struct{})
-	var once sync.Once
+// TestAsyncCoalescerDifferentKeysRunSerially は異なるキーも1つずつ順に実行されることを確認する。
+func TestAsyncCoalescerDifferentKeysRunSerially(t *testing.T) {
 	started := make(chan string, 2)
 	hold := make(chan struct{})
-	c := newAsyncCoalescer(func(id string) {
+	c := newAsyncCoalescer(0, func(id string) {
 		started <- id
 		<-hold
 	})
 	c.trigger("a")
+	select {
+	case id := <-started:
+		if id != "a" {
+			t.Fatalf("expected a to start first, got %s", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("first key did not start")
+	}
 	c.trigger("b")
-	got := map[string]bool{}
+	select {
+	case id := <-started:
+		t.Fatalf("%s started while a was running", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if status := c.status(); status.RunningGameID != "a" || len(status.Waiting) != 1 || status.Waiting[0].GameID != "b" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	close(hold)
+	select {
+	case id := <-started:
+		if id != "b" {
+			t.Fatalf("expected b to run next, got %s", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second key did not run after the first finished")
+	}
+}
+
+// TestAsyncCoalescerDebouncesWithinWindow は、待ち時間の間に来た同じキーの要求が1回にまとまり、
+// 待ち時間が過ぎてから実行されることを確認する。
+func TestAsyncCoalescerDebouncesWithinWindow(t *testing.T) {
+	const window = 100 * time.Millisecond
+	done := make(chan string, 8)
+	c := newAsyncCoalescer(window, func(id string) {
+		done <- id
+	})
+	start := time.Now()
+	c.trigger("g1")
+	c.trigger("g2")
+	c.trigger("g1")
+	c.trigger("g1")
+	if status := c.status(); len(status.Waiting) != 2 || status.RunningGameID != "" {
+		t.Fatalf("expected two waiting keys, got %+v", status)
+	}
+
+	got := map[string]int{}
 	for i := 0; i < 2; i++ {
 		select {
-		case id := <-started:
-			got[id] = true
+		case id := <-done:
+			got[id]++
 		case <-time.After(2 * time.Second):
-			t.Fatal("both keys did not start concurrently")
+			t.Fatal("debounced runs did not happen")
 		}
 	}
-	once.Do(func() { close(bothStarted) })
-	close(hold)
-	if !got["a"] || !got["b"] {
-		t.Fatalf("expected both a and b to start, got %v", got)
+	if elapsed := time.Since(start); elapsed < window {
+		t.Fatalf("runs should wait for the window, ran after %v", elapsed)
+	}
+	if got["g1"] != 1 || got["g2"] != 1 {
+		t.Fatalf("expected one run per key, got %v", got)
+	}
+	select {
+	case id := <-done:
+		t.Fatalf("unexpected extra run: %s", id)
+	case <-time.After(2 * window):
 	}
 }
 
@@ -109,7 +160,7 @@ func TestAsyncCoalescerRecoversFromPanic(t *testing.T) {
 	panics := make(chan struct{}, 4)
 	var mu sync.Mutex
 	n := 0
-	c := newAsyncCoalescer(func(_ string) {
+	c := newAsyncCoalescer(0, func(_ string) {
 		mu.Lock()
 		n++
 		cur := n
@@ -152,7 +203,7 @@ func TestAsyncCoalescerStopWaitsForInFlightAndBlocksNewTriggers(t *testing.T) {
 	started := make(chan struct{}, 1)
 	var calls int
 	var mu sync.Mutex
-	c := newAsyncCoalescer(func(_ string) {
+	c := newAsyncCoalescer(0, func(_ string) {
 		mu.Lock()
 		calls++
 		mu.Unlock()
@@ -213,3 +264,56 @@ func TestAsyncCoalescerStopWaitsForInFlightAndBlocksNewTriggers(t *testing.T) {
 		t.Fatalf("post-stop trigger increased calls to %d", got)
 	}
 }
+
+// TestAsyncCoalescerDrainRunsQueuedWithoutWaitingWindow は、drain が待っている要求を window を待たずに
+// 実行してから戻り、以後の trigger を受け付けないことを確認する。
+func TestAsyncCoalescerDrainRunsQueuedWithoutWaitingWindow(t *testing.T) {
+	var mu sync.Mutex
+	var ran []string
+	c := newAsyncCoalescer(time.Hour, func(id string) {
+		mu.Lock()
+		ran = append(ran, id)
+		mu.Unlock()
+	})
+	c.trigger("g1")
+	c.trigger("g2")
+
+	done := make(chan struct{})
+	go func() {
+		c.drain()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("drain did not return")
+	}
+	c.trigger("g3")
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ran) != 2 || ran[0] != "g1" || ran[1] != "g2" {
+		t.Fatalf("ran = %v, want [g1 g2]", ran)
+	}
+}
+
+// TestAutoSyncDrainAfterCancelKeepsQueuedPush は、終了処理でアプリのコンテキストを取り消した後に
+// drain で回ってきた自動同期が捨てられず、送信待ちに残ることを確認する。
+func TestAutoSyncDrainAfterCancelKeepsQueuedPush(t *testing.T) {
+	app, _ := newMaintenanceTestApp(t)
+	app.ctx, app.cancelCtx = context.WithCancel(context.Background())
+	app.syncCoalescer = newAsyncCoalescer(time.Hour, app.runAutoSync)
+
+	app.syncCoalescer.trigger("game-1")
+	app.cancelCtx()
+	app.syncCoalescer.drain()
+
+	items, err := app.SyncQueueService.List(context.Background())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(items) != 1 || items[0].Kind != domain.SyncQueueKindGamePush || items[0].TargetID != "game-1" {
+		t.Fatalf("queued items = %+v, want one game push for game-1", items)
+	}
+}
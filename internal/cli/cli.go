@@ -0,0 +1,165 @@
+// Package cli は画面を使わずにスクリプトやタスクスケジューラから実行するサブコマンドを提供する。
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/i18n"
+	"CloudLaunch_Go/internal/services"
+)
+
+// 終了コード。
+const (
+	ExitOK = 0
+	// ExitFailure はコマンドの失敗（同期で失敗したゲームがある場合を含む）。
+	ExitFailure = 1
+	// ExitUsage は引数の誤り。
+	ExitUsage = 2
+	// ExitIncomplete は sync でコンフリクトや未追跡ファイルの削除確認のため、ユーザーの判断待ちのゲームが残ったこと。
+	ExitIncomplete = 3
+)
+
+// GameLister はゲーム一覧の取得（GameService）。
+type GameLister interface {
+	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
+}
+
+// Syncer はクラウド同期（ContentSyncService）。
+type Syncer interface {
+	PreviewSync(ctx context.Context) (domain.SyncPlan, error)
+	Push(ctx context.Context, gameID string, onProgress services.TransferProgressFunc) error
+	Pull(ctx context.Context, gameID string, onProgress services.TransferProgressFunc, deleteUntracked bool) (domain.PullResult, error)
+}
+
+// Exporter はゲームデータのエクスポート（MaintenanceService）。
+type Exporter interface {
+	ExportPayload(ctx context.Context) (services.GameExportPayload, error)
+}
+
+// Backend はサブコマンドが使うサービス。
+type Backend struct {
+	Games  GameLister
+	Sync   Syncer
+	Export Exporter
+}
+
+// Opener は DB を開いて Backend を用意し、使い終わったときに呼ぶ後始末を返す。
+// 引数の誤りで DB に触れずに済むよう、サブコマンドは引数を検証してから呼ぶ。
+type Opener func(ctx context.Context) (*Backend, func(), error)
+
+type command struct {
+	name string
+	args string
+	// summaryCode は説明文の i18n のコード。表示言語は実行時に決まるため、表示するときに引く。
+	summaryCode string
+	run         func(ctx context.Context, env *environment, args []string) int
+}
+
+var commands = []command{
+	{"list-games", "[--format table|json] [--filter all|unplayed|playing|played|archived|favorite] [--search TEXT]", "cli.listGames.summary", runListGames},
+	{"sync", "[--dry-run] [--delete-untracked] [--format text|json]", "cli.sync.summary", runSync},
+	{"backup-saves", "<gameID>", "cli.backupSaves.summary", runBackupSaves},
+	{"export", "--format json [--output FILE]", "cli.export.summary", runExport},
+}
+
+// environment はサブコマンドの入出力と Backend の開き方。
+type environment struct {
+	stdout io.Writer
+	stderr io.Writer
+	open   Opener
+}
+
+// Run は args（プログラム名を除いたコマンドライン）のサブコマンドを実行し、終了コードを返す。
+// 結果は stdout へ、エラーと進み具合は stderr へ書く。表示はサービスのエラーと同じく i18n の表示言語に従う。
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer, open Opener) int {
+	env := &environment{stdout: stdout, stderr: stderr, open: open}
+	if len(args) == 0 {
+		env.usage()
+		return ExitUsage
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		env.usage()
+		return ExitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, env, args[1:])
+		}
+	}
+	fmt.Fprintf(stderr, i18n.Text("cli.unknownCommand")+"\n\n", args[0])
+	env.usage()
+	return ExitUsage
+}
+
+func (env *environment) usage() {
+	fmt.Fprintln(env.stderr, i18n.Text("cli.usage"))
+	fmt.Fprintln(env.stderr)
+	fmt.Fprintln(env.stderr, i18n.Text("cli.commands"))
+	for _, cmd := range commands {
+		fmt.Fprintf(env.stderr, "  %s %s\n      %s\n", cmd.name, cmd.args, i18n.Text(cmd.summaryCode))
+	}
+	fmt.Fprintln(env.stderr)
+	fmt.Fprintf(env.stderr, i18n.Text("cli.exitCodes")+"\n", ExitOK, ExitFailure, ExitUsage, ExitIncomplete)
+}
+
+// newFlagSet はエラーを stderr へ出し、プログラムを終了させないサブコマンド用の FlagSet を作る。
+func (env *environment) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("cloudlaunch "+name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	return flags
+}
+
+// parseFlags は args を解析し、失敗した場合の終了コードを返す。-h は成功として扱う。
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK, false
+		}
+		return ExitUsage, false
+	}
+	return ExitOK, true
+}
+
+// withBackend は Backend を開いて fn を実行し、後始末をしてから終了コードを返す。
+func (env *environment) withBackend(ctx context.Context, fn func(backend *Backend) int) int {
+	backend, closeBackend, err := env.open(ctx)
+	if err != nil {
+		env.printError(err)
+		return ExitFailure
+	}
+	defer closeBackend()
+	return fn(backend)
+}
+
+// usageError は引数の誤りを code の文言（args はその書式の値）で stderr へ書く。
+func (env *environment) usageError(code string, args ...any) int {
+	fmt.Fprintf(env.stderr, i18n.Text(code)+"\n", args...)
+	return ExitUsage
+}
+
+func (env *environment) printError(err error) {
+	fmt.Fprintf(env.stderr, i18n.Text("cli.error")+"\n", describeError(err))
+}
+
+// describeError はサービスのエラーを表示言語の文言にする（詳細はそのまま付ける）。
+func describeError(err error) string {
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		_, message := i18n.Localize(serviceErr.Message)
+		if strings.TrimSpace(serviceErr.Detail) == "" {
+			return message
+		}
+		return message + ": " + serviceErr.Detail
+	}
+	if errors.Is(err, services.ErrOffline) {
+		return i18n.Text("cli.offline")
+	}
+	return err.Error()
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/i18n"
+	"CloudLaunch_Go/internal/services"
+)
+
+type fakeBackend struct {
+	games      []domain.Game
+	lastFilter domain.PlayStatus
+	plan       domain.SyncPlan
+	pushed     []string
+	pulled     []string
+	pushErr    map[string]error
+	untracked  map[string][]string
+	opened     int
+	closed     int
+}
+
+func (fake *fakeBackend) ListGames(_ context.Context, _ string, filter domain.PlayStatus, _ string, _ string) ([]domain.Game, error) {
+	fake.lastFilter = filter
+	return fake.games, nil
+}
+
+func (fake *fakeBackend) PreviewSync(context.Context) (domain.SyncPlan, error) {
+	return fake.plan, nil
+}
+
+func (fake *fakeBackend) Push(_ context.Context, gameID string, _ services.TransferProgressFunc) error {
+	if err := fake.pushErr[gameID]; err != nil {
+		return err
+	}
+	fake.pushed = append(fake.pushed, gameID)
+	return nil
+}
+
+func (fake *fakeBackend) Pull(_ context.Context, gameID string, _ services.TransferProgressFunc, deleteUntracked bool) (domain.PullResult, error) {
+	if deletes := fake.untracked[gameID]; len(deletes) > 0 && !deleteUntracked {
+		return domain.PullResult{UntrackedDeletes: deletes}, nil
+	}
+	fake.pulled = append(fake.pulled, gameID)
+	return domain.PullResult{Applied: true}, nil
+}
+
+func (fake *fakeBackend) ExportPayload(context.Context) (services.GameExportPayload, error) {
+	return services.GameExportPayload{Games: fake.games}, nil
+}
+
+func (fake *fakeBackend) open(context.Context) (*Backend, func(), error) {
+	fake.opened++
+	return &Backend{Games: fake, Sync: fake, Export: fake}, func() { fake.closed++ }, nil
+}
+
+func run(fake *fakeBackend, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(context.Background(), args, &stdout, &stderr, fake.open)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunListGames(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBackend{games: []domain.Game{{ID: "g1", Title: "Alpha", PlayStatus: domain.PlayStatusPlaying, TotalPlayTime: 3*3600 + 5*60}}}
+	code, stdout, _ := run(fake, "list-games")
+	if code != ExitOK || !strings.Contains(stdout, "g1") || !strings.Contains(stdout, "3h05m") {
+		t.Fatalf("unexpected table output (code %d): %q", code, stdout)
+	}
+	if fake.lastFilter != domain.GameFilterAll || fake.closed != 1 {
+		t.Fatalf("unexpected filter %q or close count %d", fake.lastFilter, fake.closed)
+	}
+
+	code, stdout, _ = run(fake, "list-games", "--format", "json", "--filter", "favorite")
+	var games []domain.Game
+	if code != ExitOK || json.Unmarshal([]byte(stdout), &games) != nil || len(games) != 1 || fake.lastFilter != domain.GameFilterFavorite {
+		t.Fatalf("unexpected json output (code %d): %q", code, stdout)
+	}
+}
+
+func TestRunRejectsBadArgumentsWithoutOpeningDatabase(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBackend{}
+	cases := [][]string{
+		{},
+		{"unknown"},
+		{"list-games", "--format", "xml"},
+		{"list-games", "--filter", "deleted"},
+		{"export", "--format", "csv"},
+		{"backup-saves"},
+		{"sync", "extra"},
+	}
+	for _, args := range cases {
+		if code, _, _ := run(fake, args...); code != ExitUsage {
+			t.Errorf("%v: expected usage error, got %d", args, code)
+		}
+	}
+	if fake.opened != 0 {
+		t.Fatalf("database opened %d times for invalid arguments", fake.opened)
+	}
+	if code, _, stderr := run(fake, "help"); code != ExitOK || !strings.Contains(stderr, "backup-saves") {
+		t.Fatalf("unexpected help (code %d): %q", code, stderr)
+	}
+}
+
+func TestRunSyncAppliesPlan(t *testing.T) {
+	t.Parallel()
+
+	newFake := func() *fakeBackend {
+		return &fakeBackend{
+			plan: domain.SyncPlan{Items: []domain.SyncPlanItem{
+				{GameID: "up", Action: domain.SyncPlanActionUpload},
+				{GameID: "down", Action: domain.SyncPlanActionDownload},
+				{GameID: "same", Action: domain.SyncPlanActionSkip},
+				{GameID: "both", Action: domain.SyncPlanActionConflict},
+			}},
+		}
+	}
+
+	fake := newFake()
+	code, stdout, _ := run(fake, "sync", "--dry-run")
+	if code != ExitIncomplete || len(fake.pushed)+len(fake.pulled) != 0 || !strings.Contains(stdout, "planned") {
+		t.Fatalf("dry run changed something or wrong code %d: %q", code, stdout)
+	}
+
+	fake = newFake()
+	code, stdout, _ = run(fake, "sync", "--format", "json")
+	var report syncReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if code != ExitIncomplete || !slices.Equal(fake.pushed, []string{"up"}) || !slices.Equal(fake.pulled, []string{"down"}) {
+		t.Fatalf("unexpected sync (code %d): pushed %v pulled %v", code, fake.pushed, fake.pulled)
+	}
+	if report.Uploaded != 1 || report.Downloaded != 1 || report.Skipped != 1 || report.Conflicts != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	fake = newFake()
+	fake.plan.Items = fake.plan.Items[:2]
+	fake.pushErr = map[string]error{"up": &services.ServiceError{Message: "アップロードに失敗しました", Detail: "boom"}}
+	fake.untracked = map[string][]string{"down": {"extra.sav"}}
+	code, stdout, _ = run(fake, "sync")
+	if code != ExitFailure || !strings.Contains(stdout, "boom") || !strings.Contains(stdout, "--delete-untracked") {
+		t.Fatalf("expected failure with pending download (code %d): %q", code, stdout)
+	}
+}
+
+func TestRunBackupSavesAndExport(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBackend{pushErr: map[string]error{"missing": errors.New("game not found")}, games: []domain.Game{{ID: "g1"}}}
+	if code, stdout, _ := run(fake, "backup-saves", "g1"); code != ExitOK || !strings.Contains(stdout, "g1") || !slices.Equal(fake.pushed, []string{"g1"}) {
+		t.Fatalf("unexpected backup (code %d): %q", code, stdout)
+	}
+	if code, _, stderr := run(fake, "backup-saves", "missing"); code != ExitFailure || !strings.Contains(stderr, "game not found") {
+		t.Fatalf("expected backup failure (code %d): %q", code, stderr)
+	}
+
+	output := filepath.Join(t.TempDir(), "export.json")
+	if code, _, stderr := run(fake, "export", "--format", "json", "--output", output); code != ExitOK {
+		t.Fatalf("export failed (code %d): %q", code, stderr)
+	}
+	data, err := os.ReadFile(output)
+	var payload services.GameExportPayload
+	if err != nil || json.Unmarshal(data, &payload) != nil || len(payload.Games) != 1 {
+		t.Fatalf("unexpected export file: %s, %v", data, err)
+	}
+}
+
+func TestRunFollowsLanguage(t *testing.T) {
+	defer func() { _ = i18n.SetLanguage(i18n.DefaultLanguage) }()
+
+	fake := &fakeBackend{pushErr: map[string]error{"g1": &services.ServiceError{Message: "アップロードに失敗しました", Detail: "boom"}}}
+	cases := map[string][]string{
+		"en": {"commands:", "error: Upload failed: boom"},
+		"ja": {"コマンド:", "エラー: アップロードに失敗しました: boom"},
+	}
+	for language, want := range cases {
+		if err := i18n.SetLanguage(language); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, stderr := run(fake, "help"); !strings.Contains(stderr, want[0]) {
+			t.Errorf("%s: unexpected help: %q", language, stderr)
+		}
+		if _, _, stderr := run(fake, "backup-saves", "g1"); !strings.Contains(stderr, want[1]) {
+			t.Errorf("%s: unexpected error: %q", language, stderr)
+		}
+	}
+}
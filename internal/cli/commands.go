@@ -0,0 +1,301 @@
+// CLI の各サブコマンド（list-games・sync・backup-saves・export）を実装する。
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/i18n"
+)
+
+// gameListFilters は list-games の --filter に指定できる値。
+var gameListFilters = map[string]domain.PlayStatus{
+	"all":      domain.GameFilterAll,
+	"unplayed": domain.PlayStatusUnplayed,
+	"playing":  domain.PlayStatusPlaying,
+	"played":   domain.PlayStatusPlayed,
+	"archived": domain.GameFilterArchived,
+	"favorite": domain.GameFilterFavorite,
+}
+
+func runListGames(ctx context.Context, env *environment, args []string) int {
+	flags := env.newFlagSet("list-games")
+	format := flags.String("format", "table", i18n.Text("cli.listGames.format"))
+	filter := flags.String("filter", "all", i18n.Text("cli.listGames.filter"))
+	search := flags.String("search", "", i18n.Text("cli.listGames.search"))
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if *format != "table" && *format != "json" {
+		return env.usageError("cli.unsupportedFormat", *format, "table / json")
+	}
+	status, ok := gameListFilters[strings.ToLower(strings.TrimSpace(*filter))]
+	if !ok {
+		return env.usageError("cli.unsupportedFilter", *filter)
+	}
+	if flags.NArg() > 0 {
+		return env.usageError("cli.noArguments", "list-games")
+	}
+
+	return env.withBackend(ctx, func(backend *Backend) int {
+		games, err := backend.Games.ListGames(ctx, *search, status, "title", "asc")
+		if err != nil {
+			env.printError(err)
+			return ExitFailure
+		}
+		if *format == "json" {
+			return env.writeJSON(env.stdout, games)
+		}
+		writer := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, i18n.Text("cli.listGames.header"))
+		for _, game := range games {
+			lastPlayed := "-"
+			if game.LastPlayed != nil {
+				lastPlayed = game.LastPlayed.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", game.ID, game.Title, game.PlayStatus, formatPlayTime(game.TotalPlayTime), lastPlayed)
+		}
+		if err := writer.Flush(); err != nil {
+			env.printError(err)
+			return ExitFailure
+		}
+		return ExitOK
+	})
+}
+
+// syncOutcome は sync で1ゲームに行った操作と結果。
+type syncOutcome struct {
+	GameID string                `json:"gameId"`
+	Title  string                `json:"title"`
+	Action domain.SyncPlanAction `json:"action"`
+	// Result は done・skipped・failed・conflict・pending（未追跡ファイルの削除確認待ち）・planned（--dry-run）のいずれか。
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// UntrackedDeletes は pending のとき、--delete-untracked を付けると削除されるファイル。
+	UntrackedDeletes []string `json:"untrackedDeletes,omitempty"`
+}
+
+// syncReport は sync の結果全体。
+type syncReport struct {
+	DryRun     bool          `json:"dryRun"`
+	Items      []syncOutcome `json:"items"`
+	Uploaded   int           `json:"uploaded"`
+	Downloaded int           `json:"downloaded"`
+	Skipped    int           `json:"skipped"`
+	Conflicts  int           `json:"conflicts"`
+	Pending    int           `json:"pending"`
+	Failed     int           `json:"failed"`
+}
+
+func runSync(ctx context.Context, env *environment, args []string) int {
+	flags := env.newFlagSet("sync")
+	dryRun := flags.Bool("dry-run", false, i18n.Text("cli.sync.dryRun"))
+	deleteUntracked := flags.Bool("delete-untracked", false, i18n.Text("cli.sync.deleteUntracked"))
+	format := flags.String("format", "text", i18n.Text("cli.sync.format"))
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if *format != "text" && *format != "json" {
+		return env.usageError("cli.unsupportedFormat", *format, "text / json")
+	}
+	if flags.NArg() > 0 {
+		return env.usageError("cli.noArguments", "sync")
+	}
+
+	return env.withBackend(ctx, func(backend *Backend) int {
+		// 画面の「同期プレビュー」と同じ判定で、ゲームごとにアップロード・ダウンロードを決める。
+		plan, err := backend.Sync.PreviewSync(ctx)
+		if err != nil {
+			env.printError(err)
+			return ExitFailure
+		}
+		report := syncReport{DryRun: *dryRun, Items: make([]syncOutcome, 0, len(plan.Items))}
+		for _, item := range plan.Items {
+			if ctx.Err() != nil {
+				env.printError(ctx.Err())
+				return ExitFailure
+			}
+			outcome := applySyncPlanItem(ctx, backend.Sync, item, *dryRun, *deleteUntracked)
+			report.add(outcome)
+			if *format == "text" {
+				fmt.Fprintln(env.stdout, outcome.line())
+			}
+		}
+		if *format == "json" {
+			if code := env.writeJSON(env.stdout, report); code != ExitOK {
+				return code
+			}
+		} else {
+			fmt.Fprintf(env.stdout, i18n.Text("cli.sync.totals")+"\n",
+				report.Uploaded, report.Downloaded, report.Skipped, report.Conflicts, report.Pending, report.Failed)
+		}
+		switch {
+		case report.Failed > 0:
+			return ExitFailure
+		case report.Conflicts > 0 || report.Pending > 0:
+			return ExitIncomplete
+		}
+		return ExitOK
+	})
+}
+
+// applySyncPlanItem は1ゲーム分の同期予定を実行する。コンフリクトはどちらを残すか決められないため触らない。
+func applySyncPlanItem(ctx context.Context, syncer Syncer, item domain.SyncPlanItem, dryRun, deleteUntracked bool) syncOutcome {
+	outcome := syncOutcome{GameID: item.GameID, Title: item.Title, Action: item.Action, Reason: item.Reason, Error: item.Error}
+	switch item.Action {
+	case domain.SyncPlanActionConflict:
+		outcome.Result = "conflict"
+		return outcome
+	case domain.SyncPlanActionUpload, domain.SyncPlanActionDownload:
+	default:
+		outcome.Result = "skipped"
+		return outcome
+	}
+	if dryRun {
+		outcome.Result = "planned"
+		return outcome
+	}
+
+	if item.Action == domain.SyncPlanActionUpload {
+		if err := syncer.Push(ctx, item.GameID, nil); err != nil {
+			outcome.Result, outcome.Error = "failed", describeError(err)
+			return outcome
+		}
+		outcome.Result = "done"
+		return outcome
+	}
+	res, err := syncer.Pull(ctx, item.GameID, nil, deleteUntracked)
+	if err != nil {
+		outcome.Result, outcome.Error = "failed", describeError(err)
+		return outcome
+	}
+	if !res.Applied {
+		outcome.Result = "pending"
+		outcome.UntrackedDeletes = res.UntrackedDeletes
+		return outcome
+	}
+	outcome.Result = "done"
+	return outcome
+}
+
+func (report *syncReport) add(outcome syncOutcome) {
+	report.Items = append(report.Items, outcome)
+	switch outcome.Result {
+	case "done":
+		if outcome.Action == domain.SyncPlanActionUpload {
+			report.Uploaded++
+		} else {
+			report.Downloaded++
+		}
+	case "failed":
+		report.Failed++
+	case "conflict":
+		report.Conflicts++
+	case "pending":
+		report.Pending++
+	case "skipped":
+		report.Skipped++
+	}
+}
+
+// line は sync のテキスト出力の1行（操作・結果・ゲームID・タイトル・理由）。
+// 操作・結果は JSON と同じ値のまま出し、スクリプトから表示言語によらず読めるようにする。
+func (outcome syncOutcome) line() string {
+	detail := outcome.Reason
+	switch {
+	case outcome.Error != "":
+		detail = outcome.Error
+	case outcome.Result == "pending":
+		detail = fmt.Sprintf(i18n.Text("cli.sync.pendingDeletes"), len(outcome.UntrackedDeletes))
+	}
+	return fmt.Sprintf("%-8s %-8s %s %s: %s", outcome.Action, outcome.Result, outcome.GameID, outcome.Title, detail)
+}
+
+func runBackupSaves(ctx context.Context, env *environment, args []string) int {
+	flags := env.newFlagSet("backup-saves")
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if flags.NArg() != 1 || strings.TrimSpace(flags.Arg(0)) == "" {
+		return env.usageError("cli.backupSaves.usage")
+	}
+	gameID := strings.TrimSpace(flags.Arg(0))
+
+	return env.withBackend(ctx, func(backend *Backend) int {
+		if err := backend.Sync.Push(ctx, gameID, nil); err != nil {
+			env.printError(err)
+			return ExitFailure
+		}
+		fmt.Fprintf(env.stdout, i18n.Text("cli.backupSaves.done")+"\n", gameID)
+		return ExitOK
+	})
+}
+
+func runExport(ctx context.Context, env *environment, args []string) int {
+	flags := env.newFlagSet("export")
+	format := flags.String("format", "json", i18n.Text("cli.export.format"))
+	output := flags.String("output", "", i18n.Text("cli.export.output"))
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+	if *format != "json" {
+		return env.usageError("cli.unsupportedFormat", *format, "json")
+	}
+	if flags.NArg() > 0 {
+		return env.usageError("cli.noArguments", "export")
+	}
+
+	return env.withBackend(ctx, func(backend *Backend) int {
+		payload, err := backend.Export.ExportPayload(ctx)
+		if err != nil {
+			env.printError(err)
+			return ExitFailure
+		}
+		path := strings.TrimSpace(*output)
+		if path == "" || path == "-" {
+			return env.writeJSON(env.stdout, payload)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			env.printError(err)
+			return ExitFailure
+		}
+		if code := env.writeJSON(file, payload); code != ExitOK {
+			_ = file.Close()
+			return code
+		}
+		if err := file.Close(); err != nil {
+			env.printError(err)
+			return ExitFailure
+		}
+		fmt.Fprintf(env.stderr, i18n.Text("cli.export.done")+"\n", path)
+		return ExitOK
+	})
+}
+
+// writeJSON は value を整形した JSON で writer へ書く。
+func (env *environment) writeJSON(writer io.Writer, value any) int {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		env.printError(err)
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// formatPlayTime はプレイ時間（秒）を "12h05m" の形にする。
+func formatPlayTime(seconds int64) string {
+	duration := time.Duration(seconds) * time.Second
+	hours := int64(duration / time.Hour)
+	minutes := int64((duration % time.Hour) / time.Minute)
+	return fmt.Sprintf("%dh%02dm", hours, minutes)
+}
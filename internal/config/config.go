@@ -19,13 +19,73 @@ type Config struct {
 	ScreenshotLocalJpeg    bool
 	ScreenshotHotkey       string
 	ScreenshotHotkeyNotify bool
+	ScreenshotExcludedApps string
+	ThumbnailShortEdgePx   int
 	S3Endpoint             string
 	S3Region               string
 	S3Bucket               string
 	S3ForcePathStyle       bool
 	S3UseTLS               bool
 	S3UploadConcurrency    int
-	CredentialNamespace    string
+	S3MultipartPartSizeMB  int
+	SaveCompression        bool
+	MonitorIntervalSeconds int
+	MonitorWarmupPolicy    string
+	// MonitorIdleThresholdMinutes は無操作とみなしてプレイ時間の計測を止めるまでの分数（0 で無効）。
+	MonitorIdleThresholdMinutes int
+	// MonitorSessionTimeoutSeconds はプロセスが見つからなくなってから終了確認待ちにするまでの秒数。
+	// MonitorCleanupTimeoutSeconds は終了したゲームを監視対象から外すまでの秒数。
+	MonitorSessionTimeoutSeconds int
+	MonitorCleanupTimeoutSeconds int
+	CredentialNamespace          string
+	CredentialKey                string
+	HTTPTimeoutSeconds           int
+	HTTPProxyURL                 string
+	HTTPMaxRetries               int
+	// PlayReminderMinutes はプレイ時間のリマインダー通知を出す間隔の分数（0 で無効）。ゲームごとの設定が優先する。
+	PlayReminderMinutes int
+	// MonitorSplitAtMidnight は日付をまたいだセッションをローカル時刻の 0:00 で分けて記録するか。
+	MonitorSplitAtMidnight bool
+	// HotkeyActions はスクリーンショット以外のホットキーの割り当て。環境変数では設定しない。
+	HotkeyActions HotkeyActions
+	// ErogameScapeCacheTTLMinutes は批評空間の検索結果・ゲームページを再取得せずに使う分数（0 でキャッシュしない）。
+	ErogameScapeCacheTTLMinutes int
+	// MemoExternalEditUpload はエディタで直接編集したメモファイルを DB へ反映したとき、続けてクラウドへアップロードするか。
+	MemoExternalEditUpload bool
+	// SaveWatchAutoUpload はセッション終了後にセーブフォルダの変更を検出したとき、確認せずにクラウドへアップロードするか。
+	SaveWatchAutoUpload bool
+	// ScreenshotCopyToClipboard は撮影に成功したスクリーンショットを、保存に加えてクリップボードにもコピーするか。
+	ScreenshotCopyToClipboard bool
+	// ClipBufferSeconds はプレイ中に録画しておき、ホットキーで動画クリップとして保存できる直近の秒数（0 で録画しない）。
+	// ClipFFmpegPath は録画に使う ffmpeg の実行ファイル（空なら実行ファイルと同じフォルダ、なければ PATH から探す）。
+	ClipBufferSeconds int
+	ClipFFmpegPath    string
+	// StorageBackend は同期データの保存先（"s3"・"local"・"gdrive"）。LocalStorageDir は "local" のときの保存先フォルダ。
+	StorageBackend  string
+	LocalStorageDir string
+	// SyncConflictPolicy は自動同期でコンフリクトを見つけたときの扱い（"prefer-local"・"prefer-cloud"・"newest-wins"・"always-ask"）。
+	SyncConflictPolicy string
+	// GoogleDriveClientID / GoogleDriveClientSecret は Google ドライブへの接続に使う OAuth クライアント（デバイス種別）。
+	GoogleDriveClientID     string
+	GoogleDriveClientSecret string
+	// Language は API の結果メッセージの表示言語（"ja" か "en"）。
+	Language string
+	// AllowSchemaDowngrade は DB がこのアプリより新しいスキーマのとき、退避してから巻き戻して起動することを許可する。
+	AllowSchemaDowngrade bool
+	// DatabaseJournalMode は SQLite の journal_mode（"wal"・"delete" など）。DatabaseBusyTimeoutMs はロック待ちの上限ミリ秒。
+	// DatabaseMaxOpenConns / DatabaseMaxIdleConns はコネクションプールの上限。
+	DatabaseJournalMode   string
+	DatabaseBusyTimeoutMs int
+	DatabaseMaxOpenConns  int
+	DatabaseMaxIdleConns  int
+}
+
+// HotkeyActions は操作ごとのグローバルホットキー（空ならその操作は割り当てない）。
+type HotkeyActions struct {
+	PauseResume string `json:"pauseResume"`
+	EndSession  string `json:"endSession"`
+	SyncNow     string `json:"syncNow"`
+	SaveClip    string `json:"saveClip"`
 }
 
 // LoadFromEnv は環境変数から設定を読み込む。
@@ -34,23 +94,55 @@ func LoadFromEnv() Config {
 	databasePath := getEnv("CLOUDLAUNCH_DB_PATH", filepath.Join(appDataDir, "app.db"))
 
 	return Config{
-		AppDataDir:             appDataDir,
-		DatabasePath:           databasePath,
-		LogLevel:               getEnv("CLOUDLAUNCH_LOG_LEVEL", "info"),
-		ScreenshotSyncEnabled:  getEnvBool("CLOUDLAUNCH_SCREENSHOT_SYNC", false),
-		ScreenshotUploadJpeg:   getEnvBool("CLOUDLAUNCH_SCREENSHOT_UPLOAD_JPEG", true),
-		ScreenshotJpegQuality:  getEnvInt("CLOUDLAUNCH_SCREENSHOT_JPEG_QUALITY", 85),
-		ScreenshotClientOnly:   getEnvBool("CLOUDLAUNCH_SCREENSHOT_CLIENT_ONLY", true),
-		ScreenshotLocalJpeg:    getEnvBool("CLOUDLAUNCH_SCREENSHOT_LOCAL_JPEG", false),
-		ScreenshotHotkey:       getEnv("CLOUDLAUNCH_SCREENSHOT_HOTKEY", "Ctrl+Alt+S"),
-		ScreenshotHotkeyNotify: getEnvBool("CLOUDLAUNCH_SCREENSHOT_HOTKEY_NOTIFY", true),
-		S3Endpoint:             getEnv("CLOUDLAUNCH_S3_ENDPOINT", ""),
-		S3Region:               getEnv("CLOUDLAUNCH_S3_REGION", "auto"),
-		S3Bucket:               getEnv("CLOUDLAUNCH_S3_BUCKET", ""),
-		S3ForcePathStyle:       getEnvBool("CLOUDLAUNCH_S3_FORCE_PATH_STYLE", false),
-		S3UseTLS:               getEnvBool("CLOUDLAUNCH_S3_USE_TLS", true),
-		S3UploadConcurrency:    getEnvInt("CLOUDLAUNCH_S3_UPLOAD_CONCURRENCY", 6),
-		CredentialNamespace:    getEnv("CLOUDLAUNCH_CREDENTIAL_NAMESPACE", "CloudLaunch"),
+		AppDataDir:                   appDataDir,
+		DatabasePath:                 databasePath,
+		LogLevel:                     getEnv("CLOUDLAUNCH_LOG_LEVEL", "info"),
+		ScreenshotSyncEnabled:        getEnvBool("CLOUDLAUNCH_SCREENSHOT_SYNC", false),
+		ScreenshotUploadJpeg:         getEnvBool("CLOUDLAUNCH_SCREENSHOT_UPLOAD_JPEG", true),
+		ScreenshotJpegQuality:        getEnvInt("CLOUDLAUNCH_SCREENSHOT_JPEG_QUALITY", 85),
+		ScreenshotClientOnly:         getEnvBool("CLOUDLAUNCH_SCREENSHOT_CLIENT_ONLY", true),
+		ScreenshotLocalJpeg:          getEnvBool("CLOUDLAUNCH_SCREENSHOT_LOCAL_JPEG", false),
+		ScreenshotHotkey:             getEnv("CLOUDLAUNCH_SCREENSHOT_HOTKEY", "Ctrl+Alt+S"),
+		ScreenshotHotkeyNotify:       getEnvBool("CLOUDLAUNCH_SCREENSHOT_HOTKEY_NOTIFY", true),
+		ScreenshotExcludedApps:       getEnv("CLOUDLAUNCH_SCREENSHOT_EXCLUDED_APPS", ""),
+		ScreenshotCopyToClipboard:    getEnvBool("CLOUDLAUNCH_SCREENSHOT_COPY_TO_CLIPBOARD", false),
+		ClipBufferSeconds:            getEnvInt("CLOUDLAUNCH_CLIP_BUFFER_SECONDS", 0),
+		ClipFFmpegPath:               getEnv("CLOUDLAUNCH_CLIP_FFMPEG_PATH", ""),
+		ThumbnailShortEdgePx:         getEnvInt("CLOUDLAUNCH_THUMBNAIL_SHORT_EDGE_PX", 200),
+		ErogameScapeCacheTTLMinutes:  getEnvInt("CLOUDLAUNCH_EROGAMESCAPE_CACHE_TTL_MINUTES", 24*60),
+		MemoExternalEditUpload:       getEnvBool("CLOUDLAUNCH_MEMO_EXTERNAL_EDIT_UPLOAD", false),
+		SaveWatchAutoUpload:          getEnvBool("CLOUDLAUNCH_SAVE_WATCH_AUTO_UPLOAD", false),
+		S3Endpoint:                   getEnv("CLOUDLAUNCH_S3_ENDPOINT", ""),
+		S3Region:                     getEnv("CLOUDLAUNCH_S3_REGION", "auto"),
+		S3Bucket:                     getEnv("CLOUDLAUNCH_S3_BUCKET", ""),
+		S3ForcePathStyle:             getEnvBool("CLOUDLAUNCH_S3_FORCE_PATH_STYLE", false),
+		S3UseTLS:                     getEnvBool("CLOUDLAUNCH_S3_USE_TLS", true),
+		S3UploadConcurrency:          getEnvInt("CLOUDLAUNCH_S3_UPLOAD_CONCURRENCY", 6),
+		S3MultipartPartSizeMB:        getEnvInt("CLOUDLAUNCH_S3_MULTIPART_PART_SIZE_MB", 16),
+		SaveCompression:              getEnvBool("CLOUDLAUNCH_SAVE_COMPRESSION", false),
+		MonitorIntervalSeconds:       getEnvInt("CLOUDLAUNCH_MONITOR_INTERVAL_SECONDS", 2),
+		MonitorWarmupPolicy:          getEnv("CLOUDLAUNCH_MONITOR_WARMUP_POLICY", "partial"),
+		MonitorIdleThresholdMinutes:  getEnvInt("CLOUDLAUNCH_MONITOR_IDLE_THRESHOLD_MINUTES", 0),
+		MonitorSessionTimeoutSeconds: getEnvInt("CLOUDLAUNCH_MONITOR_SESSION_TIMEOUT_SECONDS", 0),
+		MonitorCleanupTimeoutSeconds: getEnvInt("CLOUDLAUNCH_MONITOR_CLEANUP_TIMEOUT_SECONDS", 20),
+		MonitorSplitAtMidnight:       getEnvBool("CLOUDLAUNCH_MONITOR_SPLIT_AT_MIDNIGHT", false),
+		PlayReminderMinutes:          getEnvInt("CLOUDLAUNCH_PLAY_REMINDER_MINUTES", 0),
+		CredentialNamespace:          getEnv("CLOUDLAUNCH_CREDENTIAL_NAMESPACE", "CloudLaunch"),
+		CredentialKey:                getEnv("CLOUDLAUNCH_CREDENTIAL_KEY", "default"),
+		HTTPTimeoutSeconds:           getEnvInt("CLOUDLAUNCH_HTTP_TIMEOUT_SECONDS", 15),
+		HTTPProxyURL:                 getEnv("CLOUDLAUNCH_HTTP_PROXY", ""),
+		HTTPMaxRetries:               getEnvInt("CLOUDLAUNCH_HTTP_MAX_RETRIES", 2),
+		StorageBackend:               getEnv("CLOUDLAUNCH_STORAGE_BACKEND", "s3"),
+		LocalStorageDir:              getEnv("CLOUDLAUNCH_LOCAL_STORAGE_DIR", ""),
+		SyncConflictPolicy:           getEnv("CLOUDLAUNCH_SYNC_CONFLICT_POLICY", "always-ask"),
+		GoogleDriveClientID:          getEnv("CLOUDLAUNCH_GDRIVE_CLIENT_ID", ""),
+		GoogleDriveClientSecret:      getEnv("CLOUDLAUNCH_GDRIVE_CLIENT_SECRET", ""),
+		Language:                     getEnv("CLOUDLAUNCH_LANGUAGE", "ja"),
+		AllowSchemaDowngrade:         getEnvBool("CLOUDLAUNCH_ALLOW_SCHEMA_DOWNGRADE", false),
+		DatabaseJournalMode:          getEnv("CLOUDLAUNCH_DB_JOURNAL_MODE", "wal"),
+		DatabaseBusyTimeoutMs:        getEnvInt("CLOUDLAUNCH_DB_BUSY_TIMEOUT_MS", 5000),
+		DatabaseMaxOpenConns:         getEnvInt("CLOUDLAUNCH_DB_MAX_OPEN_CONNS", 4),
+		DatabaseMaxIdleConns:         getEnvInt("CLOUDLAUNCH_DB_MAX_IDLE_CONNS", 2),
 	}
 }
 
@@ -0,0 +1,6 @@
+// アプリのバージョン情報を定義する。
+package config
+
+// Version はアプリのバージョン。wails.json の productVersion と揃え、
+// リリースビルドでは -ldflags "-X CloudLaunch_Go/internal/config.Version=..." で上書きする。
+var Version = "0.1.0"
@@ -0,0 +1,34 @@
+// ユーザー操作の監査ログ（アクティビティログ）を定義する。
+package domain
+
+import "time"
+
+// アクティビティログの操作種別。対象エンティティ種別は変更ジャーナルと同じ ChangeEntity* を使う。
+const (
+	ActivityActionCreate = "create"
+	ActivityActionUpdate = "update"
+	ActivityActionDelete = "delete"
+	ActivityActionSync   = "sync"
+	ActivityActionLaunch = "launch"
+)
+
+// ActivityEntry はアクティビティログの1件を表す。
+// ライブラリ全体への操作（一括ダウンロードなど）は EntityType / EntityID を空にする。
+type ActivityEntry struct {
+	ID         int64     `json:"id"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entityType"`
+	EntityID   string    `json:"entityId"`
+	Summary    string    `json:"summary"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ActivityFilter はアクティビティログの絞り込み条件を表す。空の項目・ゼロ値の時刻は条件にしない。
+// Since は含み、Until は含まない。
+type ActivityFilter struct {
+	Action     string    `json:"action"`
+	EntityType string    `json:"entityType"`
+	EntityID   string    `json:"entityId"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+}
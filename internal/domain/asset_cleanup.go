@@ -0,0 +1,41 @@
+// AppDataDir 配下の参照されていない画像・スクリーンショット・メモファイルの検出結果モデルを定義する。
+package domain
+
+// OrphanedAssetKind は参照されていないファイルの種類。
+type OrphanedAssetKind string
+
+const (
+	// OrphanedAssetThumbnail はどのゲームの画像にも使われていないサムネイル。
+	OrphanedAssetThumbnail OrphanedAssetKind = "thumbnail"
+	// OrphanedAssetThumbnailOriginal は対応するサムネイルが使われていない原寸画像。
+	OrphanedAssetThumbnailOriginal OrphanedAssetKind = "thumbnailOriginal"
+	// OrphanedAssetScreenshot は削除済みのゲームのスクリーンショット（アーカイブの zip を含む）。
+	OrphanedAssetScreenshot OrphanedAssetKind = "screenshot"
+	// OrphanedAssetMemoFile は DB のメモに対応しないメモファイル。
+	OrphanedAssetMemoFile OrphanedAssetKind = "memoFile"
+)
+
+// OrphanedAsset は参照されていないファイル1件を表す。
+type OrphanedAsset struct {
+	Kind OrphanedAssetKind `json:"kind"`
+	Path string            `json:"path"`
+	Size int64             `json:"size"`
+}
+
+// OrphanedAssetSummary は種類ごとの件数と合計サイズを表す。
+type OrphanedAssetSummary struct {
+	Kind  OrphanedAssetKind `json:"kind"`
+	Count int               `json:"count"`
+	Bytes int64             `json:"bytes"`
+}
+
+// OrphanedAssetReport は参照されていないファイルの検出・削除の結果を表す。
+// Deleted は削除まで行ったかで、Failed は削除できなかったファイルのパス（Assets・集計には含めたまま）。
+type OrphanedAssetReport struct {
+	Assets     []OrphanedAsset        `json:"assets"`
+	Summary    []OrphanedAssetSummary `json:"summary"`
+	TotalCount int                    `json:"totalCount"`
+	TotalBytes int64                  `json:"totalBytes"`
+	Deleted    bool                   `json:"deleted"`
+	Failed     []string               `json:"failed"`
+}
@@ -0,0 +1,34 @@
+// スクリーンショット撮影結果のモデルを定義する。
+package domain
+
+// CaptureResult は1回のスクリーンショット撮影の結果を表す。
+// GameID は保存先のゲーム（対象ゲームが無いホットキー撮影では空）、Hwnd は撮影したウィンドウのハンドル（不明なら 0）。
+// Width/Height は保存した画像の大きさで、読み取れなかった場合は 0。
+// Warnings は撮影自体は成功したが利用者に伝えたい注意（真っ黒の画像など）。
+type CaptureResult struct {
+	Path       string   `json:"path"`
+	GameID     string   `json:"gameId"`
+	Hwnd       uint64   `json:"hwnd"`
+	Backend    string   `json:"backend"`
+	Width      int      `json:"width"`
+	Height     int      `json:"height"`
+	DurationMs int64    `json:"durationMs"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// ScreenshotSeriesResult はタイマー撮影・連写の結果を表す。
+// Captures は撮影できた分を撮影順に並べたもの。Canceled は途中でキャンセルされ、予定枚数に届かなかったか。
+// UploadFailed はクラウドへの同期に失敗した撮影があったか（ローカルには保存済み）。UploadError はそのエラー内容。
+type ScreenshotSeriesResult struct {
+	Captures     []CaptureResult `json:"captures"`
+	Canceled     bool            `json:"canceled"`
+	UploadFailed bool            `json:"uploadFailed"`
+	UploadError  string          `json:"uploadError,omitempty"`
+}
+
+// AutoScreenshotSetting はゲームごとの定期スクリーンショットの設定を表す。
+// IntervalMinutes 分ごとに撮影し、自動撮影分が MaxCount 枚を超えたら古いものから削除する。
+type AutoScreenshotSetting struct {
+	IntervalMinutes int `json:"intervalMinutes"`
+	MaxCount        int `json:"maxCount"`
+}
@@ -0,0 +1,21 @@
+// ゲームプレイの動画クリップのモデルを定義する。
+package domain
+
+import "time"
+
+// ClipInfo は保存済みの動画クリップ（mp4）1件を表す。Name はファイル名で、削除時の指定に使う。
+type ClipInfo struct {
+	GameID    string    `json:"gameId"`
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	SizeBytes int64     `json:"sizeBytes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ClipRecorderStatus はクリップ用の常時録画の状態を表す。
+// 録画していないときの GameID は空。BufferSeconds は保存できる直近の秒数（0 なら録画が無効）。
+type ClipRecorderStatus struct {
+	Recording     bool   `json:"recording"`
+	GameID        string `json:"gameId"`
+	BufferSeconds int    `json:"bufferSeconds"`
+}
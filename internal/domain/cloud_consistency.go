@@ -0,0 +1,69 @@
+// クラウド上のメタデータとオブジェクトの整合性チェック結果のモデルを定義する。
+package domain
+
+import "time"
+
+// CloudProblemKind はクラウド整合性チェックで見つかった問題の種類を表す。
+type CloudProblemKind string
+
+const (
+	// CloudProblemHeadUnreadable は HEAD が読めないこと。
+	CloudProblemHeadUnreadable CloudProblemKind = "head_unreadable"
+	// CloudProblemCommitUnreadable は HEAD が指すコミットが無い、または JSON として読めないこと。
+	CloudProblemCommitUnreadable CloudProblemKind = "commit_unreadable"
+	// CloudProblemTreeUnreadable はセーブスナップショット（trees/）が無い、または読めないこと。
+	CloudProblemTreeUnreadable CloudProblemKind = "tree_unreadable"
+	// CloudProblemGameJSONUnreadable は game.json が無い、読めない、または別ゲームのものであること。
+	CloudProblemGameJSONUnreadable CloudProblemKind = "game_json_unreadable"
+	// CloudProblemSessionsJSONUnreadable は sessions.json が無い、または読めないこと。
+	CloudProblemSessionsJSONUnreadable CloudProblemKind = "sessions_json_unreadable"
+	// CloudProblemRoutesJSONUnreadable はコミットが参照する routes.json が無い、または読めないこと。
+	CloudProblemRoutesJSONUnreadable CloudProblemKind = "routes_json_unreadable"
+	// CloudProblemImageMissing は game.json の imageHash に対応する画像オブジェクトが無いこと。
+	CloudProblemImageMissing CloudProblemKind = "image_missing"
+	// CloudProblemSaveObjectsMissing はセーブスナップショットが参照するオブジェクト（またはパック）が無いこと。
+	CloudProblemSaveObjectsMissing CloudProblemKind = "save_objects_missing"
+)
+
+// CloudConsistencyProblem はゲーム1件で見つかった問題1件を表す。
+// Repairable はローカルの状態を push し直すことで解消できるかを示す。
+type CloudConsistencyProblem struct {
+	GameID     string           `json:"gameId"`
+	Title      string           `json:"title"`
+	Kind       CloudProblemKind `json:"kind"`
+	Detail     string           `json:"detail"`
+	Repairable bool             `json:"repairable"`
+}
+
+// CloudConsistencyReport はクラウド整合性チェック1回分の結果を表す。
+// Repaired は RepairCloudConsistency で push し直したゲームID（チェックのみの場合は空）。
+type CloudConsistencyReport struct {
+	CheckedAt    time.Time                 `json:"checkedAt"`
+	GameCount    int                       `json:"gameCount"`
+	Problems     []CloudConsistencyProblem `json:"problems"`
+	Repaired     []string                  `json:"repaired"`
+	RepairFailed []CloudRepairFailure      `json:"repairFailed"`
+}
+
+// CloudRepairFailure は修復に失敗したゲームと理由を表す。
+type CloudRepairFailure struct {
+	GameID  string `json:"gameId"`
+	Message string `json:"message"`
+}
+
+// RepairableGameIDs は修復可能な問題を持つゲームIDを重複なく出現順に返す。
+func (report CloudConsistencyReport) RepairableGameIDs() []string {
+	seen := make(map[string]struct{})
+	ids := make([]string, 0)
+	for _, problem := range report.Problems {
+		if !problem.Repairable {
+			continue
+		}
+		if _, ok := seen[problem.GameID]; ok {
+			continue
+		}
+		seen[problem.GameID] = struct{}{}
+		ids = append(ids, problem.GameID)
+	}
+	return ids
+}
@@ -1,4 +1,4 @@
-// 批評空間の検索結果モデルを定義する。
+// 批評空間の検索結果と、一括照合の結果モデルを定義する。
 package domain
 
 // ErogameScapeSearchItem は検索結果のゲーム情報を表す。
@@ -14,3 +14,43 @@ type ErogameScapeSearchResult struct {
 	Items       []ErogameScapeSearchItem `json:"items"`
 	NextPageURL string                   `json:"nextPageUrl,omitempty"`
 }
+
+// ErogameScapeMatchCandidate は一括照合で見つかった候補と、タイトルの類似度（0〜1）を表す。
+type ErogameScapeMatchCandidate struct {
+	ErogameScapeID string  `json:"erogameScapeId"`
+	Title          string  `json:"title"`
+	Brand          string  `json:"brand,omitempty"`
+	GameURL        string  `json:"gameUrl"`
+	Score          float64 `json:"score"`
+}
+
+// ErogameScapeMatchApplied は候補を自動で反映したゲームを表す。
+type ErogameScapeMatchApplied struct {
+	GameID         string  `json:"gameId"`
+	ErogameScapeID string  `json:"erogameScapeId"`
+	Title          string  `json:"title"`
+	Score          float64 `json:"score"`
+}
+
+// ErogameScapeMatchAmbiguous は候補を1件に決められなかったゲームと、手動で選ぶための候補（類似度の高い順）を表す。
+type ErogameScapeMatchAmbiguous struct {
+	GameID     string                       `json:"gameId"`
+	Title      string                       `json:"title"`
+	Candidates []ErogameScapeMatchCandidate `json:"candidates"`
+}
+
+// ErogameScapeMatchFailed は照合に失敗したゲームと理由を表す。
+type ErogameScapeMatchFailed struct {
+	GameID  string `json:"gameId"`
+	Message string `json:"message"`
+}
+
+// ErogameScapeMatchResult は批評空間との一括照合の結果を表す。NotFound は候補が1件も無かったゲームの ID。
+// Canceled は途中でキャンセルされ、照合していないゲームが残ったか（それまでの結果は各一覧に入る）。
+type ErogameScapeMatchResult struct {
+	Applied   []ErogameScapeMatchApplied   `json:"applied"`
+	Ambiguous []ErogameScapeMatchAmbiguous `json:"ambiguous"`
+	NotFound  []string                     `json:"notFound"`
+	Failed    []ErogameScapeMatchFailed    `json:"failed"`
+	Canceled  bool                         `json:"canceled"`
+}
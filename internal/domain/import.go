@@ -2,10 +2,42 @@
 package domain
 
 // GameImport は外部サイトから取得したゲーム情報を表す。
+// Provider は取り込み元（erogamescape|dlsite|dmm|igdb|steam）、SourceID は取り込み元での作品 ID（批評空間なら ErogameScapeID と同じ）。
+// ReleaseDate は分かる場合のみの発売日（YYYY-MM-DD）、Genres / Description は API で取得できる取り込み元のみのジャンルと概要。
+// Score は分かる場合のみの取り込み元での得点（1〜100、批評空間は中央値）で、ゲームの評価の初期値に使う。
 type GameImport struct {
-	ErogameScapeID string `json:"erogameScapeId"`
-	Title          string `json:"title"`
-	Brand          string `json:"brand"`
-	ImagePath      string `json:"imagePath"`
-	ImageURL       string `json:"imageUrl,omitempty"`
+	ErogameScapeID string   `json:"erogameScapeId"`
+	Provider       string   `json:"provider"`
+	SourceID       string   `json:"sourceId"`
+	Title          string   `json:"title"`
+	Brand          string   `json:"brand"`
+	ImagePath      string   `json:"imagePath"`
+	ImageURL       string   `json:"imageUrl,omitempty"`
+	ReleaseDate    string   `json:"releaseDate,omitempty"`
+	Genres         []string `json:"genres,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Score          int      `json:"score,omitempty"`
+}
+
+// MetadataProviderInfo は選択できるゲーム情報の取り込み元を表す。
+// RequiresCredential は API キーの設定が必要な取り込み元か、Configured はそのキーが設定済みかを示す。
+type MetadataProviderInfo struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	RequiresCredential bool   `json:"requiresCredential"`
+	Configured         bool   `json:"configured"`
+}
+
+// ThumbnailRegenerationResult はサムネイル一括再生成の結果を表す。
+// Skipped は原寸画像が残っておらず再生成できなかった画像の数（画像はそのまま残す）。
+type ThumbnailRegenerationResult struct {
+	Regenerated int                           `json:"regenerated"`
+	Skipped     int                           `json:"skipped"`
+	Failed      []ThumbnailRegenerationFailed `json:"failed"`
+}
+
+// ThumbnailRegenerationFailed は再生成に失敗したゲームと理由を表す。
+type ThumbnailRegenerationFailed struct {
+	GameID  string `json:"gameId"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,45 @@
+// データベース整合性チェックの結果モデルを定義する。
+package domain
+
+// OrphanKind は整合性チェックで数える孤立データの種類。
+type OrphanKind string
+
+const (
+	// OrphanKindRoute はゲームが存在しないルート。
+	OrphanKindRoute OrphanKind = "route"
+	// OrphanKindSession はゲームが存在しないプレイセッション。
+	OrphanKindSession OrphanKind = "session"
+	// OrphanKindMemo はゲームが存在しないメモ。
+	OrphanKindMemo OrphanKind = "memo"
+	// OrphanKindGameLink はゲームが存在しないリンク。
+	OrphanKindGameLink OrphanKind = "gameLink"
+	// OrphanKindSessionRoute は存在しないルートを参照しているセッション（修復時は参照を外す）。
+	OrphanKindSessionRoute OrphanKind = "sessionRoute"
+	// OrphanKindCurrentRoute は存在しないルートを現在のルートにしているゲーム（修復時は参照を外す）。
+	OrphanKindCurrentRoute OrphanKind = "currentRoute"
+)
+
+// OrphanCount は種類ごとの孤立データ件数を表す。Repaired は修復（削除・参照解除）した件数。
+type OrphanCount struct {
+	Kind     OrphanKind `json:"kind"`
+	Count    int        `json:"count"`
+	Repaired int        `json:"repaired"`
+}
+
+// ForeignKeyViolation は PRAGMA foreign_key_check の1行を表す。
+type ForeignKeyViolation struct {
+	Table  string `json:"table"`
+	RowID  int64  `json:"rowId"`
+	Parent string `json:"parent"`
+}
+
+// DatabaseIntegrityReport はデータベース整合性チェックの結果を表す。
+// IntegrityMessages は PRAGMA integrity_check の結果で、問題が無ければ空。
+// Healthy は integrity_check・外部キー・孤立データのいずれにも問題が無い（修復後に残っていない）ことを示す。
+type DatabaseIntegrityReport struct {
+	Healthy              bool                  `json:"healthy"`
+	IntegrityMessages    []string              `json:"integrityMessages"`
+	ForeignKeyViolations []ForeignKeyViolation `json:"foreignKeyViolations"`
+	Orphans              []OrphanCount         `json:"orphans"`
+	Repaired             bool                  `json:"repaired"`
+}
@@ -0,0 +1,39 @@
+// ローカルの変更履歴（変更ジャーナル）を定義する。
+package domain
+
+import "time"
+
+// 変更ジャーナルの対象エンティティ種別。
+const (
+	ChangeEntityGame    = "game"
+	ChangeEntitySession = "session"
+	ChangeEntityRoute   = "route"
+	ChangeEntityMemo    = "memo"
+	ChangeEntityLink    = "gameLink"
+)
+
+// 変更ジャーナルの操作種別。
+const (
+	ChangeOperationCreate = "create"
+	ChangeOperationUpdate = "update"
+	ChangeOperationDelete = "delete"
+)
+
+// FieldChange は1フィールド分の変更前後の値を表す（JSON 表現のまま持つ）。
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// ChangeEntry は変更ジャーナルの1件を表す。
+// Source は変更を行ったリポジトリ操作名（例: UpdateGame, ApplyPullResult）。
+type ChangeEntry struct {
+	ID         int64         `json:"id"`
+	EntityType string        `json:"entityType"`
+	EntityID   string        `json:"entityId"`
+	Operation  string        `json:"operation"`
+	Source     string        `json:"source"`
+	Changes    []FieldChange `json:"changes"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
@@ -0,0 +1,18 @@
+// ゲーム起動ラッパー（ロケールエミュレーター等）のモデルを定義する。
+package domain
+
+// LaunchWrapper はゲームをラッパー経由で起動するための設定を表す。
+// Args は引数テンプレートで、{exe}（ゲームの実行ファイル）・{dir}（そのフォルダ）・{name}（ファイル名）を置換する。
+// {exe} を含まない場合はテンプレートの末尾に実行ファイルを渡す。
+type LaunchWrapper struct {
+	Path string `json:"path"`
+	Args string `json:"args"`
+}
+
+// LaunchWrapperCandidate は端末で見つかった既知のラッパーと、その既定の引数テンプレートを表す。
+type LaunchWrapperCandidate struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Args string `json:"args"`
+}
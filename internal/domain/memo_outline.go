@@ -0,0 +1,28 @@
+// メモ（Markdown）の目次・進捗表示用モデルを定義する。
+package domain
+
+// MemoOutline はメモ本文から抽出した目次・チェックリスト進捗・分量を表す。
+// WordCount は空白区切りの語数（英数字）、CharCount は空白を除いた文字数。
+// ReadingMinutes は日本語 500 字/分・英語 200 語/分の目安で、本文があれば最低 1 分。
+type MemoOutline struct {
+	MemoID         string        `json:"memoId"`
+	Headings       []MemoHeading `json:"headings"`
+	Checklist      MemoChecklist `json:"checklist"`
+	WordCount      int           `json:"wordCount"`
+	CharCount      int           `json:"charCount"`
+	ReadingMinutes int           `json:"readingMinutes"`
+}
+
+// MemoHeading は見出し1件を表す。Line は本文中の行番号（1 始まり）。
+type MemoHeading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	Line  int    `json:"line"`
+}
+
+// MemoChecklist はチェックリスト（- [ ] / - [x]）の進捗を表す。Percent は 0〜100 の整数（項目が無ければ 0）。
+type MemoChecklist struct {
+	Total   int `json:"total"`
+	Checked int `json:"checked"`
+	Percent int `json:"percent"`
+}
@@ -12,11 +12,35 @@ const (
 	PlayStatusPlayed   PlayStatus = "played"
 )
 
+// ListGames の filter に渡す、プレイ状態以外の一覧条件。
+// 既定（空文字）ではアーカイブ済みゲームを除外する。検索文字列がある場合はアーカイブ済みも含める。
+const (
+	GameFilterArchived PlayStatus = "archived" // アーカイブ済みのみ
+	GameFilterAll      PlayStatus = "all"      // アーカイブ済みも含めた全件（バックアップ・同期用）
+	GameFilterFavorite PlayStatus = "favorite" // お気に入りのみ
+)
+
 // IsValidPlayStatus は有効なプレイ状態かを返す。
 func IsValidPlayStatus(s PlayStatus) bool {
 	return s == PlayStatusUnplayed || s == PlayStatusPlaying || s == PlayStatusPlayed
 }
 
+// TrackingMode はプレイ時間の数え方を表す。
+type TrackingMode string
+
+const (
+	// TrackingModeProcess はゲームのプロセスが存在する間プレイ時間を数える（既定）。
+	TrackingModeProcess TrackingMode = "process"
+	// TrackingModeForeground はゲームのウィンドウが前面にある間だけ数える。
+	// 最小化したままのゲームや常駐するランチャーで総プレイ時間が膨らまないようにする。
+	TrackingModeForeground TrackingMode = "foreground"
+)
+
+// IsValidTrackingMode は有効なプレイ時間の数え方かを返す。
+func IsValidTrackingMode(mode TrackingMode) bool {
+	return mode == TrackingModeProcess || mode == TrackingModeForeground
+}
+
 // Game はゲーム基本情報を表す。
 type Game struct {
 	ID                     string     `json:"id"`
@@ -35,6 +59,76 @@ type Game struct {
 	LastPlayed             *time.Time `json:"lastPlayed,omitempty"`
 	ClearedAt              *time.Time `json:"clearedAt,omitempty"`
 	CurrentRouteID         *string    `json:"currentRouteId,omitempty"`
+	ArchivedAt             *time.Time `json:"archivedAt,omitempty"`
+	// Description / ReleaseDate（YYYY-MM-DD）/ Genres は取り込み元から取得した作品情報、Rating は利用者の評価（1〜100）。
+	// いずれも同期対象で、nil（Genres は空）なら未設定。
+	Description *string  `json:"description,omitempty"`
+	ReleaseDate *string  `json:"releaseDate,omitempty"`
+	Rating      *int     `json:"rating,omitempty"`
+	Genres      []string `json:"genres,omitempty"`
+	// CustomStatus は利用者が追加したプレイ状況（PlayStatusDefinition）の slug で、nil なら未設定。
+	// 組み込みの PlayStatus とは独立して持ち、ゲーム情報として同期する。
+	CustomStatus *string `json:"customStatus,omitempty"`
+	// IsFavorite はライブラリの先頭に固定するお気に入りで、同期対象。
+	IsFavorite bool `json:"isFavorite"`
+	// SaveIncludePatterns / SaveExcludePatterns はセーブフォルダ内で同期するファイルを絞り込む glob パターン（同期対象）。
+	// Include が空なら全ファイルが対象で、Exclude に一致するファイル（ディレクトリなら配下すべて）は除く。
+	SaveIncludePatterns []string `json:"saveIncludePatterns,omitempty"`
+	SaveExcludePatterns []string `json:"saveExcludePatterns,omitempty"`
+	// LaunchWrapper は端末ローカルの起動ラッパー設定（nil なら直接起動）。
+	LaunchWrapper *LaunchWrapper `json:"launchWrapper,omitempty"`
+	// TrackingMode は端末ローカルのプレイ時間の数え方。
+	TrackingMode TrackingMode `json:"trackingMode"`
+	// AutoTrackingExcluded は端末ローカルの、プロセス監視の自動計測から外す設定。
+	AutoTrackingExcluded bool `json:"autoTrackingExcluded"`
+	// ProcessMatchPattern は端末ローカルの、プロセスのコマンドラインの一致条件（エミュレーターの ROM 等）。
+	// "regex:" で始まれば正規表現、それ以外は部分一致。nil なら実行ファイルだけで判定する。
+	ProcessMatchPattern *string `json:"processMatchPattern,omitempty"`
+	// PreLaunchCommand / PostExitCommand は端末ローカルの、起動前・終了後に実行するコマンド（nil なら実行しない）。
+	PreLaunchCommand *string `json:"preLaunchCommand,omitempty"`
+	PostExitCommand  *string `json:"postExitCommand,omitempty"`
+}
+
+// IsArchived はアーカイブ済みかを返す。
+func (game Game) IsArchived() bool {
+	return game.ArchivedAt != nil
+}
+
+// GameSummary は一覧（グリッド）表示に必要な項目だけを持つ Game の縮小版。
+// 大量のゲームを Wails ブリッジ越しに渡す際のシリアライズ量を抑えるために使う。
+type GameSummary struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Publisher     string     `json:"publisher"`
+	ImagePath     *string    `json:"imagePath,omitempty"`
+	PlayStatus    PlayStatus `json:"playStatus"`
+	TotalPlayTime int64      `json:"totalPlayTime"`
+	LastPlayed    *time.Time `json:"lastPlayed,omitempty"`
+	ArchivedAt    *time.Time `json:"archivedAt,omitempty"`
+	ReleaseDate   *string    `json:"releaseDate,omitempty"`
+	Rating        *int       `json:"rating,omitempty"`
+	Genres        []string   `json:"genres,omitempty"`
+	CustomStatus  *string    `json:"customStatus,omitempty"`
+	IsFavorite    bool       `json:"isFavorite"`
+}
+
+// Summary は一覧表示用の GameSummary を返す。
+func (game Game) Summary() GameSummary {
+	return GameSummary{
+		ID:            game.ID,
+		Title:         game.Title,
+		Publisher:     game.Publisher,
+		ImagePath:     game.ImagePath,
+		PlayStatus:    game.PlayStatus,
+		TotalPlayTime: game.TotalPlayTime,
+		LastPlayed:    game.LastPlayed,
+		ArchivedAt:    game.ArchivedAt,
+		ReleaseDate:   game.ReleaseDate,
+		Rating:        game.Rating,
+		Genres:        game.Genres,
+		CustomStatus:  game.CustomStatus,
+		IsFavorite:    game.IsFavorite,
+	}
 }
 
 // PlaySession はプレイセッションを表す。
@@ -44,17 +138,45 @@ type PlaySession struct {
 	PlayedAt    time.Time `json:"playedAt"`
 	Duration    int64     `json:"duration"`
 	SessionName *string   `json:"sessionName,omitempty"`
-	RouteID     *string   `json:"routeId,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	// Notes はそのセッションで進めた内容などを書き留める自由記述のメモ。
+	Notes     *string   `json:"notes,omitempty"`
+	RouteID   *string   `json:"routeId,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Partial は監視開始前から起動していたゲームのセッションで、それ以前のプレイ時間を含まないことを表す。
+	Partial bool `json:"partial,omitempty"`
+	// IdleDuration は無操作のため自動で計測を止めていた時間（秒）。Duration には含まれない。
+	IdleDuration int64 `json:"idleDuration,omitempty"`
+	// CPU* / Memory* は計測中に採ったゲームのプロセスの CPU 使用率（全コアに対する %）とメモリ使用量（ワーキングセット）の
+	// 最大値と平均値。採れなかったセッション（手動登録・非Windows など）は 0。
+	CPUPeakPercent     float64 `json:"cpuPeakPercent,omitempty"`
+	CPUAveragePercent  float64 `json:"cpuAveragePercent,omitempty"`
+	MemoryPeakBytes    int64   `json:"memoryPeakBytes,omitempty"`
+	MemoryAverageBytes int64   `json:"memoryAverageBytes,omitempty"`
 }
 
 // Route はルート情報を表す。
+// EstimatedTime はクリアまでの見込み時間（秒）、CompletedAt はクリア済みにした日時で、いずれも nil なら未設定。
+// Completed は CompletedAt が設定されているかを表す（保存はしない）。
 type Route struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Order         int64      `json:"order"`
+	GameID        string     `json:"gameId"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	EstimatedTime *int64     `json:"estimatedTime"`
+	Completed     bool       `json:"completed"`
+	CompletedAt   *time.Time `json:"completedAt"`
+}
+
+// GameLink はゲームの外部リンク（公式サイト・販売ページ等）を表す。
+type GameLink struct {
 	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Order     int64     `json:"order"`
 	GameID    string    `json:"gameId"`
+	Label     string    `json:"label"`
+	URL       string    `json:"url"`
+	SortOrder int64     `json:"sortOrder"`
 	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 // Memo はメモ情報を表す。
@@ -74,13 +196,70 @@ type RouteOrderItem struct {
 }
 
 // RouteStat はルート統計の出力を表す。
+// EstimatedTime は見込み時間（秒、未設定なら 0）。CompletionPercent はクリア済みなら 100、
+// それ以外は見込み時間に対するプレイ時間の割合（100 で頭打ち、見込み未設定なら 0）。
+// RemainingTime は見込み時間までの残り（秒）で、クリア済み・見込み未設定なら 0。
 type RouteStat struct {
-	RouteID      string  `json:"routeId"`
-	RouteName    string  `json:"routeName"`
-	TotalTime    int64   `json:"totalTime"`
-	SessionCount int64   `json:"sessionCount"`
-	AverageTime  float64 `json:"averageTime"`
-	Order        int64   `json:"order"`
+	RouteID           string     `json:"routeId"`
+	RouteName         string     `json:"routeName"`
+	TotalTime         int64      `json:"totalTime"`
+	SessionCount      int64      `json:"sessionCount"`
+	AverageTime       float64    `json:"averageTime"`
+	Order             int64      `json:"order"`
+	EstimatedTime     int64      `json:"estimatedTime"`
+	Completed         bool       `json:"completed"`
+	CompletedAt       *time.Time `json:"completedAt"`
+	CompletionPercent float64    `json:"completionPercent"`
+	RemainingTime     int64      `json:"remainingTime"`
+}
+
+// RouteProgress はゲーム全体のルート攻略の進捗を表す。
+// CompletionPercent はクリア済みルートの割合、RemainingTime は未クリアのルートの残り見込み時間の合計（秒）。
+type RouteProgress struct {
+	GameID            string      `json:"gameId"`
+	RouteCount        int         `json:"routeCount"`
+	CompletedCount    int         `json:"completedCount"`
+	CompletionPercent float64     `json:"completionPercent"`
+	TotalTime         int64       `json:"totalTime"`
+	EstimatedTime     int64       `json:"estimatedTime"`
+	RemainingTime     int64       `json:"remainingTime"`
+	Routes            []RouteStat `json:"routes"`
+}
+
+// PlaythroughSummary は比較対象の1周分（期間またはルート集合）の集計を表す。
+type PlaythroughSummary struct {
+	TotalTime     int64      `json:"totalTime"`
+	SessionCount  int64      `json:"sessionCount"`
+	AverageTime   float64    `json:"averageTime"`
+	FirstPlayedAt *time.Time `json:"firstPlayedAt,omitempty"`
+	LastPlayedAt  *time.Time `json:"lastPlayedAt,omitempty"`
+	ChapterCount  int64      `json:"chapterCount"`
+}
+
+// ChapterComparison は2周分の同じ章（ルート）のプレイ時間の比較を表す。
+// Delta は B - A（秒）、DeltaRatio は A に対する増減率（A が 0 の場合は 0）。
+// ShareA / ShareB はそれぞれの周の合計に占める割合で、ペース配分の差を見るために使う。
+type ChapterComparison struct {
+	RouteIDA   *string `json:"routeIdA,omitempty"`
+	RouteIDB   *string `json:"routeIdB,omitempty"`
+	Name       string  `json:"name"`
+	TimeA      int64   `json:"timeA"`
+	TimeB      int64   `json:"timeB"`
+	SessionsA  int64   `json:"sessionsA"`
+	SessionsB  int64   `json:"sessionsB"`
+	Delta      int64   `json:"delta"`
+	DeltaRatio float64 `json:"deltaRatio"`
+	ShareA     float64 `json:"shareA"`
+	ShareB     float64 `json:"shareB"`
+}
+
+// PlaythroughComparison は2周分のプレイの比較結果を表す。
+type PlaythroughComparison struct {
+	GameID     string              `json:"gameId"`
+	A          PlaythroughSummary  `json:"a"`
+	B          PlaythroughSummary  `json:"b"`
+	TotalDelta int64               `json:"totalDelta"`
+	Chapters   []ChapterComparison `json:"chapters"`
 }
 
 // MonitoringGameStatus はゲーム監視の状態を表す。
@@ -93,6 +272,22 @@ type MonitoringGameStatus struct {
 	IsPaused          bool   `json:"isPaused"`
 	NeedsConfirmation bool   `json:"needsConfirmation"`
 	NeedsResume       bool   `json:"needsResume"`
+	// Partial はアプリ起動前から起動していたため、計測がアプリ起動時刻から始まっていることを表す。
+	Partial            bool `json:"partial"`
+	NeedsStartEstimate bool `json:"needsStartEstimate"`
+	// IsIdle は無操作のため計測を自動で止めていることを、IdleTime はこのセッションの無操作時間（秒）を表す。
+	IsIdle   bool  `json:"isIdle"`
+	IdleTime int64 `json:"idleTime"`
+	// IsBackground は前面表示中のみ数えるゲームが背面にあるため、計測を止めていることを表す。
+	IsBackground bool `json:"isBackground"`
+	// CPUPercent / MemoryBytes は直近のスキャンで採ったプロセスの使用率と使用量（採れていなければ 0）、
+	// CPU* / Memory* の Peak / Average はこのセッションの最大値と平均値。
+	CPUPercent         float64 `json:"cpuPercent"`
+	MemoryBytes        int64   `json:"memoryBytes"`
+	CPUPeakPercent     float64 `json:"cpuPeakPercent"`
+	CPUAveragePercent  float64 `json:"cpuAveragePercent"`
+	MemoryPeakBytes    int64   `json:"memoryPeakBytes"`
+	MemoryAverageBytes int64   `json:"memoryAverageBytes"`
 }
 
 // ProcessSnapshotItem はプロセス監視デバッグ用の情報を表す。
@@ -109,3 +304,14 @@ type ProcessSnapshot struct {
 	Source string                `json:"source"`
 	Items  []ProcessSnapshotItem `json:"items"`
 }
+
+// GameAggregates はセッションから再計算したゲームの集計値（累計プレイ時間・最終プレイ日時・ルート統計）を表す。
+// Changed は再計算で累計プレイ時間か最終プレイ日時が変わったかを示す。
+type GameAggregates struct {
+	GameID        string      `json:"gameId"`
+	TotalPlayTime int64       `json:"totalPlayTime"`
+	LastPlayed    *time.Time  `json:"lastPlayed"`
+	SessionCount  int         `json:"sessionCount"`
+	RouteStats    []RouteStat `json:"routeStats"`
+	Changed       bool        `json:"changed"`
+}
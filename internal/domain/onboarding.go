@@ -0,0 +1,32 @@
+// 初回セットアップ（オンボーディング）の進み具合を定義する。
+package domain
+
+import "time"
+
+// 初回セットアップの手順。
+const (
+	// OnboardingStepCredentials は同期先（S3 の認証情報・ローカルフォルダ・Google ドライブ）の設定。
+	OnboardingStepCredentials = "credentials"
+	// OnboardingStepFirstGame は最初のゲームの登録。
+	OnboardingStepFirstGame = "firstGame"
+	// OnboardingStepFirstSync は最初の同期の成功。
+	OnboardingStepFirstSync = "firstSync"
+)
+
+// OnboardingSteps は初回セットアップの手順を案内する順に並べたもの。
+var OnboardingSteps = []string{OnboardingStepCredentials, OnboardingStepFirstGame, OnboardingStepFirstSync}
+
+// OnboardingStep は手順1件の完了状況を表す。CompletedAt は未完了なら nil。
+type OnboardingStep struct {
+	ID          string     `json:"id"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// OnboardingState は初回セットアップ全体の進み具合を表す。
+// Completed は全手順が完了したか。NextStep は次に案内する未完了の手順（全て完了なら空）。
+type OnboardingState struct {
+	Steps     []OnboardingStep `json:"steps"`
+	Completed bool             `json:"completed"`
+	NextStep  string           `json:"nextStep"`
+}
@@ -0,0 +1,22 @@
+// 実行中の長時間処理（同期・転送・一括処理）の状態モデルを定義する。
+package domain
+
+import "time"
+
+// ActiveOperation は実行中の長時間処理1件の進捗を表す。
+// Current / Total は件数（ゲーム数・ファイル数）、BytesDone / BytesTotal は分かる場合のみのバイト数。
+// EtaSeconds は直近の処理速度から見積もった残り時間で、見積もれない間は 0。
+// ID は CancelOperation に渡すキャンセル用のハンドルで、Canceling はキャンセル要求済みで終了待ちであることを示す。
+type ActiveOperation struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	GameID      string    `json:"gameId"`
+	StartedAt   time.Time `json:"startedAt"`
+	Current     int       `json:"current"`
+	Total       int       `json:"total"`
+	CurrentFile string    `json:"currentFile"`
+	BytesDone   int64     `json:"bytesDone"`
+	BytesTotal  int64     `json:"bytesTotal"`
+	EtaSeconds  float64   `json:"etaSeconds"`
+	Canceling   bool      `json:"canceling"`
+}
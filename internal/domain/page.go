@@ -0,0 +1,53 @@
+// 一覧取得のページング条件と結果のモデルを定義する。
+package domain
+
+// DefaultPageLimit は Limit 未指定（0 以下）のときに使う1ページあたりの件数。
+const DefaultPageLimit = 50
+
+// MaxPageLimit は1ページで取得できる最大件数。
+const MaxPageLimit = 500
+
+// PageRequest は limit/offset 方式のページング条件を表す。
+type PageRequest struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// Normalize は範囲外の値を補正したページング条件を返す。
+// Limit は 0 以下なら DefaultPageLimit、MaxPageLimit 超は MaxPageLimit に丸め、負の Offset は 0 にする。
+func (page PageRequest) Normalize() PageRequest {
+	if page.Limit <= 0 {
+		page.Limit = DefaultPageLimit
+	}
+	if page.Limit > MaxPageLimit {
+		page.Limit = MaxPageLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+	return page
+}
+
+// Page は1ページ分の一覧と、条件に一致する全件数を表す。
+// HasMore は Offset+len(Items) より後ろにまだ項目があるかを示す。
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"hasMore"`
+}
+
+// NewPage は取得済みの items と全件数からページを組み立てる。
+func NewPage[T any](items []T, total int, page PageRequest) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{
+		Items:   items,
+		Total:   total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		HasMore: page.Offset+len(items) < total,
+	}
+}
@@ -0,0 +1,35 @@
+// プレイ履歴カレンダー（日別プレイ時間）の表示用モデルを定義する。
+package domain
+
+import "time"
+
+// PlayTimeBucket はセッションを1時間単位・ゲーム単位に集計した値を表す。
+// Start はバケットの開始時刻（保存時のタイムゾーン）、Duration は合計秒数。
+type PlayTimeBucket struct {
+	GameID   string    `json:"gameId"`
+	Start    time.Time `json:"start"`
+	Duration int64     `json:"duration"`
+}
+
+// PlayCalendar は1年分の日別プレイ時間を表す。Days はプレイのあった日だけを日付順に持つ。
+// MaxMinutes は色の段階付け用に、最もプレイした日の分数を返す。
+type PlayCalendar struct {
+	Year         int               `json:"year"`
+	Days         []PlayCalendarDay `json:"days"`
+	TotalMinutes int64             `json:"totalMinutes"`
+	MaxMinutes   int64             `json:"maxMinutes"`
+}
+
+// PlayCalendarDay は1日分のプレイ時間を表す。Date はローカル日付（YYYY-MM-DD）。
+// Games はゲーム別内訳を要求したときだけ入る。
+type PlayCalendarDay struct {
+	Date    string                `json:"date"`
+	Minutes int64                 `json:"minutes"`
+	Games   []PlayCalendarGameDay `json:"games,omitempty"`
+}
+
+// PlayCalendarGameDay は1日のうち1ゲーム分のプレイ時間を表す。
+type PlayCalendarGameDay struct {
+	GameID  string `json:"gameId"`
+	Minutes int64  `json:"minutes"`
+}
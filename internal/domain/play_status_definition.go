@@ -0,0 +1,46 @@
+// 利用者が追加できるプレイ状況（カスタムステータス）のモデルを定義する。
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// GameFilterCustomStatusPrefix は ListGames の filter でカスタムステータスを指定する接頭辞（"custom:<slug>"）。
+const GameFilterCustomStatusPrefix PlayStatus = "custom:"
+
+// PlayStatusDefinition はプレイ状況の定義を表す。
+// Builtin は組み込みの playStatus（unplayed/playing/played）で、保存・変更・削除はできない。
+// Color は "#RRGGBB"（空なら UI の既定色）。
+type PlayStatusDefinition struct {
+	Slug      string    `json:"slug"`
+	Label     string    `json:"label"`
+	Color     string    `json:"color"`
+	SortOrder int64     `json:"sortOrder"`
+	Builtin   bool      `json:"builtin"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// BuiltinPlayStatusDefinitions は組み込みのプレイ状況を表示順で返す。
+func BuiltinPlayStatusDefinitions() []PlayStatusDefinition {
+	return []PlayStatusDefinition{
+		{Slug: string(PlayStatusUnplayed), Label: "未プレイ", Builtin: true, SortOrder: 0},
+		{Slug: string(PlayStatusPlaying), Label: "プレイ中", Builtin: true, SortOrder: 1},
+		{Slug: string(PlayStatusPlayed), Label: "クリア済み", Builtin: true, SortOrder: 2},
+	}
+}
+
+// CustomStatusFilter は slug のカスタムステータスで絞り込む ListGames の filter を返す。
+func CustomStatusFilter(slug string) PlayStatus {
+	return GameFilterCustomStatusPrefix + PlayStatus(slug)
+}
+
+// CustomStatusSlug は filter がカスタムステータスの絞り込みなら slug を返す。
+func (s PlayStatus) CustomStatusSlug() (string, bool) {
+	slug, ok := strings.CutPrefix(string(s), string(GameFilterCustomStatusPrefix))
+	if !ok || slug == "" {
+		return "", false
+	}
+	return slug, true
+}
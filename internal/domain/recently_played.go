@@ -0,0 +1,33 @@
+// ホーム画面の「続きから遊ぶ」に使う、最近遊んだゲームのモデルを定義する。
+package domain
+
+import "time"
+
+// 最近遊んだゲームの取得件数の既定値と上限。
+const (
+	DefaultRecentlyPlayedLimit = 10
+	MaxRecentlyPlayedLimit     = 50
+)
+
+// RecentlyPlayedGame は最近遊んだゲーム1件と、その最後のセッション・現在のルートを表す。
+// LastSession はセッションが1件も無い（最終プレイ日時だけ手動で設定された）場合 nil。
+type RecentlyPlayedGame struct {
+	Game         GameSummary         `json:"game"`
+	LastSession  *LastSessionSummary `json:"lastSession,omitempty"`
+	CurrentRoute *RouteRef           `json:"currentRoute,omitempty"`
+}
+
+// LastSessionSummary は最後のプレイセッションの要約を表す。Route はセッションにルートが無ければ nil。
+type LastSessionSummary struct {
+	ID          string    `json:"id"`
+	PlayedAt    time.Time `json:"playedAt"`
+	Duration    int64     `json:"duration"`
+	SessionName *string   `json:"sessionName,omitempty"`
+	Route       *RouteRef `json:"route,omitempty"`
+}
+
+// RouteRef はルートの ID と名前だけの参照を表す。
+type RouteRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
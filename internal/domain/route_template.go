@@ -0,0 +1,13 @@
+// ゲームをまたいで使い回すルート構成（ルートテンプレート）のモデルを定義する。
+package domain
+
+import "time"
+
+// RouteTemplate は保存済みのルート構成を表す。RouteNames はルート名を表示順に並べたもの。
+type RouteTemplate struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	RouteNames []string  `json:"routeNames"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,14 @@
+// データベースのスキーマバージョン（適用済みマイグレーション）のモデルを定義する。
+package domain
+
+// SchemaVersion はデータベースに適用済みのマイグレーションと、このアプリが知るマイグレーションの対応を表す。
+// Unknown はより新しいアプリが適用した（このアプリが知らない）マイグレーションで、空でなければ DB の方が新しい。
+// Downgradable は Unknown のすべてに戻し用 SQL が記録されており、このアプリで巻き戻せるかを示す。
+type SchemaVersion struct {
+	Current      string   `json:"current"`
+	Latest       string   `json:"latest"`
+	Applied      int      `json:"applied"`
+	Pending      []string `json:"pending"`
+	Unknown      []string `json:"unknown"`
+	Downgradable bool     `json:"downgradable"`
+}
@@ -17,14 +17,49 @@ type SaveSnapshot struct {
 // 同期判定（contentFingerprint）には含まれず、欠落しても整合性に影響しない。
 // 旧クライアントが書いた commit には値が無いため omitempty + ゼロ値時は
 // 表示側で「未取得」として扱う。
+//
+// SavesPack が空でないときは、セーブファイルの実データを個別ブロブではなく
+// このハッシュの zip ブロブ1つ（エントリ名は SaveSnapshot.Files のキー）にまとめている。
+// 保存形式の違いでセーブ内容の差分に見えないよう、Saves（ツリー）や fingerprint には含めない。
+// SavesPack 非対応の旧クライアントはこの commit のセーブを取得できない。
+//
+// DeviceID / DeviceName はこの commit を作成した端末。DeviceID はデバイスID導入以前の commit では空。
+//
+// RoutesJSON はゲームのルート一覧（routes.json）のハッシュ。ルートが無いときも空の一覧を置き、
+// 全て削除したことを別の端末へ伝える。RoutesJSON が空（routes.json を持たない）の commit を
+// Pull してもローカルのルートは維持する（ルート同期以前の commit との互換）。
+//
+// SessionChunks はプレイセッションを playedAt の月（UTC の "2006-01"）ごとに分けたログのハッシュ。
+// 空でないときはセッションをこちらに置き、sessions.json 自体はアップロードしない（SessionsJSON は
+// 全セッションを並べた JSON のハッシュのまま残し、fingerprint を分割前と揃える）。変わった月のログだけが
+// 新しいブロブになるため、Push・Pull で転送するのはその月の分だけになる。セッションが無いときは従来どおり
+// sessions.json を置く。SessionChunks 非対応の旧クライアントはこの commit のセッションを取得できない。
 type MetaSnapshot struct {
 	GameJSON     BlobHash  `json:"game.json"`
 	SessionsJSON BlobHash  `json:"sessions.json"`
 	Saves        BlobHash  `json:"saves"`
+	DeviceID     string    `json:"deviceId,omitempty"`
 	DeviceName   string    `json:"deviceName"`
 	CreatedAt    time.Time `json:"createdAt"`
 	FileCount    int64     `json:"fileCount,omitempty"`
 	TotalSize    int64     `json:"totalSize,omitempty"`
+	SavesPack    BlobHash  `json:"savesPack,omitempty"`
+	RoutesJSON   BlobHash  `json:"routes.json,omitempty"`
+
+	SessionChunks map[string]BlobHash `json:"sessionChunks,omitempty"`
+}
+
+// TransferProgress はセーブファイル転送の進捗を表す。
+// FilesDone / FilesTotal にはローカルと一致して転送不要だったファイルも含む。
+// BytesTotal が 0 の場合（Pull では転送前にサイズが分からない）は総量不明として扱う。
+// EtaSeconds は実際に転送した分の速度から見積もった残り時間で、見積もれない間は 0。
+type TransferProgress struct {
+	CurrentFile string  `json:"currentFile"`
+	FilesDone   int     `json:"filesDone"`
+	FilesTotal  int     `json:"filesTotal"`
+	BytesDone   int64   `json:"bytesDone"`
+	BytesTotal  int64   `json:"bytesTotal"`
+	EtaSeconds  float64 `json:"etaSeconds"`
 }
 
 type SyncStatus string
@@ -51,6 +86,38 @@ type SyncStatusDetail struct {
 	RemoteMeta  *MetaSnapshot `json:"remoteMeta,omitempty"`
 }
 
+// SyncPlanAction は同期プレビューでゲームごとに予定される操作を表す。
+type SyncPlanAction string
+
+const (
+	SyncPlanActionUpload   SyncPlanAction = "upload"
+	SyncPlanActionDownload SyncPlanAction = "download"
+	SyncPlanActionSkip     SyncPlanAction = "skip"
+	SyncPlanActionConflict SyncPlanAction = "conflict"
+)
+
+// SyncPlanItem は1ゲーム分の同期予定を表す。
+// Status は Status() と同じ判定結果（クラウドかローカルの一方にしか無い場合は空）。
+// Reason は操作を選んだ理由の表示用文言、Error は判定に失敗したときの理由（Action は skip）。
+type SyncPlanItem struct {
+	GameID      string         `json:"gameId"`
+	Title       string         `json:"title"`
+	Action      SyncPlanAction `json:"action"`
+	Status      SyncStatus     `json:"status,omitempty"`
+	Reason      string         `json:"reason"`
+	SavesDiffer bool           `json:"savesDiffer"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// SyncPlan は変更を加えずに算出した同期計画を表す。Items はゲームID順。
+type SyncPlan struct {
+	Items    []SyncPlanItem `json:"items"`
+	Upload   int            `json:"upload"`
+	Download int            `json:"download"`
+	Skip     int            `json:"skip"`
+	Conflict int            `json:"conflict"`
+}
+
 // PullResult は Pull / ResolveConflict(リモート採用) の結果を表す。
 //
 // Applied=false かつ UntrackedDeletes が非空のときは「未追跡ファイルの削除確認待ち」を表し、
@@ -60,3 +127,58 @@ type PullResult struct {
 	Applied          bool     `json:"applied"`
 	UntrackedDeletes []string `json:"untrackedDeletes,omitempty"`
 }
+
+// SyncConflict は自動同期で見つかり、ユーザーの判断を待っているコンフリクトを表す。
+// LocalMeta / RemoteMeta は検出した時点のローカル・クラウドの内容。
+type SyncConflict struct {
+	GameID     string       `json:"gameId"`
+	Title      string       `json:"title"`
+	LocalMeta  MetaSnapshot `json:"localMeta"`
+	RemoteMeta MetaSnapshot `json:"remoteMeta"`
+	DetectedAt time.Time    `json:"detectedAt"`
+}
+
+// SyncConflictDecision はコンフリクト1件に対するユーザーの判断を表す。
+// UseLocal=false はクラウド側の採用で、DeleteUntracked は Pull と同じく未追跡ファイルの削除を承認済みかどうか。
+type SyncConflictDecision struct {
+	GameID          string `json:"gameId"`
+	UseLocal        bool   `json:"useLocal"`
+	DeleteUntracked bool   `json:"deleteUntracked"`
+}
+
+// SyncConflictResolveResult は判断待ちのコンフリクトをまとめて解決した結果を表す。
+// PendingConfirmation は未追跡ファイルの削除確認が必要で適用しなかったゲーム（判断待ちの一覧に残る）。
+type SyncConflictResolveResult struct {
+	Resolved            []string        `json:"resolved"`
+	PendingConfirmation []string        `json:"pendingConfirmation"`
+	Failed              []PullAllFailed `json:"failed"`
+}
+
+// PullAllResult は全ゲーム一括 Pull の結果を表す。
+//
+// 失敗したゲームは Failed に残し、他のゲームの取り込みは続行する。取り込み済みのゲームは
+// 次回の一括 Pull で in_sync と判定されて Skipped になるため、再実行で失敗分だけを取り直せる。
+// PendingConfirmation は未追跡ファイルの削除確認が必要でスキップしたゲーム（個別 Pull で確認する）。
+type PullAllResult struct {
+	Applied             []string        `json:"applied"`
+	Skipped             []string        `json:"skipped"`
+	PendingConfirmation []string        `json:"pendingConfirmation"`
+	Failed              []PullAllFailed `json:"failed"`
+}
+
+// PullAllFailed は一括 Pull で失敗したゲームと理由を表す。
+type PullAllFailed struct {
+	GameID  string `json:"gameId"`
+	Message string `json:"message"`
+}
+
+// SaveRestoreResult はクラウドのセーブデータをゲームのセーブフォルダへ復元した結果を表す。
+// Head は復元したコミット、BackupDir は復元前のセーブファイルの退避先（退避するファイルが無ければ空）。
+type SaveRestoreResult struct {
+	GameID     string    `json:"gameId"`
+	Head       string    `json:"head"`
+	SaveDir    string    `json:"saveDir"`
+	BackupDir  string    `json:"backupDir,omitempty"`
+	FileCount  int       `json:"fileCount"`
+	RestoredAt time.Time `json:"restoredAt"`
+}
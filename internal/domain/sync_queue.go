@@ -0,0 +1,28 @@
+// オフライン中のクラウド操作を再送するための送信待ち行列を定義する。
+package domain
+
+import "time"
+
+// SyncQueueKind は送信待ち操作の種別を表す。
+type SyncQueueKind string
+
+const (
+	// SyncQueueKindGamePush はゲーム（セッション保存・セーブを含む）のクラウドへの Push。TargetID はゲームID。
+	SyncQueueKindGamePush SyncQueueKind = "gamePush"
+	// SyncQueueKindMemoUpload はメモのクラウドへのアップロード。TargetID はメモID。
+	SyncQueueKindMemoUpload SyncQueueKind = "memoUpload"
+)
+
+// SyncQueueItem は送信待ちの操作1件を表す。
+// 同じ Kind・TargetID は1件にまとめられ、Revision は追加されるたびに増える。
+// Attempts / LastError は再送に失敗した回数と直近の失敗理由。
+type SyncQueueItem struct {
+	ID        int64         `json:"id"`
+	Kind      SyncQueueKind `json:"kind"`
+	TargetID  string        `json:"targetId"`
+	Revision  int64         `json:"revision"`
+	Attempts  int64         `json:"attempts"`
+	LastError *string       `json:"lastError,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
@@ -0,0 +1,51 @@
+// 外部の自動化（Home Assistant・IFTTT・自作スクリプト等）へ送る Webhook の設定と送信内容を定義する。
+package domain
+
+import "time"
+
+// WebhookEvent は Webhook を送るきっかけ。
+type WebhookEvent string
+
+const (
+	// WebhookEventSessionEnded はプロセス監視で記録したプレイセッションの終了。
+	WebhookEventSessionEnded WebhookEvent = "session.ended"
+	// WebhookEventGameCleared はゲームを未クリアからクリア済みにしたとき。
+	WebhookEventGameCleared WebhookEvent = "game.cleared"
+	// WebhookEventSyncFinished はゲーム1件のクラウド同期（Push / Pull）の終了。失敗も含む。
+	WebhookEventSyncFinished WebhookEvent = "sync.finished"
+)
+
+// WebhookEvents は設定できるきっかけの一覧。
+var WebhookEvents = []WebhookEvent{WebhookEventSessionEnded, WebhookEventGameCleared, WebhookEventSyncFinished}
+
+// Webhook は送信先1件の設定を表す。
+type Webhook struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// URL は POST 先（http / https）。
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+	// BodyTemplate は本文を組み立てる Go の text/template。空なら WebhookPayload をそのまま JSON にする。
+	// 出力は JSON でなければならない。文字列の埋め込みには {{json .GameTitle}} のように json 関数を使う。
+	BodyTemplate string `json:"bodyTemplate"`
+	Enabled      bool   `json:"enabled"`
+}
+
+// WebhookPayload は Webhook で送る内容で、本文テンプレートに渡すデータ。
+// Game は送信時点のゲーム（削除済みなら nil）。Session・Sync はきっかけに応じて設定する。
+type WebhookPayload struct {
+	Event     WebhookEvent       `json:"event"`
+	Timestamp time.Time          `json:"timestamp"`
+	GameID    string             `json:"gameId"`
+	GameTitle string             `json:"gameTitle"`
+	Game      *Game              `json:"game,omitempty"`
+	Session   *PlaySession       `json:"session,omitempty"`
+	Sync      *WebhookSyncResult `json:"sync,omitempty"`
+}
+
+// WebhookSyncResult は sync.finished で送る同期の結果。Direction は "push" か "pull"。
+type WebhookSyncResult struct {
+	Direction string `json:"direction"`
+	Applied   bool   `json:"applied"`
+	Error     string `json:"error,omitempty"`
+}
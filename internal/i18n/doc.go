@@ -0,0 +1,2 @@
+// Package i18n はフロントエンドへ返すメッセージのカタログと表示言語の切り替えを提供する。
+package i18n
@@ -0,0 +1,102 @@
+// 表示言語の設定と、メッセージの翻訳を提供する。
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// 対応している表示言語。
+const (
+	LanguageJapanese = "ja"
+	LanguageEnglish  = "en"
+)
+
+// DefaultLanguage は未設定時の表示言語。メッセージはソース上も日本語で書く。
+const DefaultLanguage = LanguageJapanese
+
+// current は API の結果メッセージに使う表示言語。
+// 引数で引き回さずアプリ全体で1つの値を持ち、設定変更時に SetLanguage で更新する。
+var current atomic.Value
+
+func init() {
+	current.Store(DefaultLanguage)
+}
+
+// SupportedLanguages は対応している表示言語を返す。
+func SupportedLanguages() []string {
+	return []string{LanguageJapanese, LanguageEnglish}
+}
+
+// NormalizeLanguage は言語コード（"ja"・"en-US" 等）を対応言語に正規化する。空は既定の言語とし、未対応ならエラーを返す。
+func NormalizeLanguage(value string) (string, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(value))
+	if trimmed == "" {
+		return DefaultLanguage, nil
+	}
+	base, _, _ := strings.Cut(strings.ReplaceAll(trimmed, "_", "-"), "-")
+	for _, language := range SupportedLanguages() {
+		if base == language {
+			return language, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language: %s", value)
+}
+
+// SetLanguage は表示言語を切り替える。
+func SetLanguage(value string) error {
+	language, err := NormalizeLanguage(value)
+	if err != nil {
+		return err
+	}
+	current.Store(language)
+	return nil
+}
+
+// Language は現在の表示言語を返す。
+func Language() string {
+	return current.Load().(string)
+}
+
+// Localize は日本語のメッセージをカタログのコードと現在の表示言語の文言に置き換える。
+// カタログに無いメッセージはコードを空にしてそのまま返す。
+func Localize(message string) (string, string) {
+	entry, ok := byJapanese[message]
+	if !ok {
+		return "", message
+	}
+	return entry.code, entry.text(Language())
+}
+
+// Text はコードのメッセージを現在の表示言語で返す。未知のコードは空文字を返す。
+func Text(code string) string {
+	entry, ok := byCode[code]
+	if !ok {
+		return ""
+	}
+	return entry.text(Language())
+}
+
+// Catalog は language（空なら現在の表示言語）のコード → 文言の一覧を返す（フロントエンドがコードから表示するため）。
+func Catalog(language string) (map[string]string, error) {
+	if language == "" {
+		language = Language()
+	}
+	normalized, err := NormalizeLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]string, len(messages))
+	for _, entry := range messages {
+		catalog[entry.code] = entry.text(normalized)
+	}
+	return catalog, nil
+}
+
+func (entry message) text(language string) string {
+	if language == LanguageEnglish {
+		return entry.en
+	}
+	return entry.ja
+}
@@ -0,0 +1,132 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{"": LanguageJapanese, "ja": LanguageJapanese, "EN": LanguageEnglish, "en-US": LanguageEnglish, "ja_JP": LanguageJapanese}
+	for input, want := range cases {
+		if got, err := NormalizeLanguage(input); err != nil || got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := NormalizeLanguage("fr"); err == nil {
+		t.Fatal("unsupported languages should be rejected")
+	}
+}
+
+func TestLocalizeFollowsLanguage(t *testing.T) {
+	defer func() { _ = SetLanguage(DefaultLanguage) }()
+
+	if err := SetLanguage("en"); err != nil {
+		t.Fatal(err)
+	}
+	if code, text := Localize("ゲームが見つかりません"); code != "game.notFound" || text != "Game not found" {
+		t.Fatalf("Localize = %q, %q", code, text)
+	}
+	if Text("game.notFound") != "Game not found" {
+		t.Fatalf("Text = %q", Text("game.notFound"))
+	}
+	if code, text := Localize("カタログに無いメッセージ"); code != "" || text != "カタログに無いメッセージ" {
+		t.Fatalf("unknown messages should pass through: %q, %q", code, text)
+	}
+	if err := SetLanguage("ja"); err != nil {
+		t.Fatal(err)
+	}
+	if _, text := Localize("ゲームが見つかりません"); text != "ゲームが見つかりません" {
+		t.Fatalf("Japanese should be returned as is: %q", text)
+	}
+}
+
+func TestCatalogEntriesAreComplete(t *testing.T) {
+	t.Parallel()
+
+	codes := make(map[string]bool, len(messages))
+	japanese := make(map[string]bool, len(messages))
+	for _, entry := range messages {
+		if entry.code == "" || entry.ja == "" || entry.en == "" {
+			t.Errorf("incomplete entry: %+v", entry)
+		}
+		if codes[entry.code] || japanese[entry.ja] {
+			t.Errorf("duplicate entry: %+v", entry)
+		}
+		codes[entry.code] = true
+		japanese[entry.ja] = true
+	}
+	english, err := Catalog(LanguageEnglish)
+	if err != nil || len(english) != len(messages) {
+		t.Fatalf("Catalog(en) = %d entries, %v", len(english), err)
+	}
+}
+
+// TestCatalogCoversAPIMessages は app / services から API の結果として返すメッセージがすべてカタログにあるかを確かめる。
+func TestCatalogCoversAPIMessages(t *testing.T) {
+	t.Parallel()
+
+	// 関数名 → メッセージを渡す引数の位置。
+	targets := map[string]int{
+		"ErrorResult":        0,
+		"newServiceError":    0,
+		"serviceErrorResult": 1,
+		"serviceResult":      2,
+		"boolResult":         1,
+		"errorResultWithLog": 1,
+	}
+	for _, dir := range []string{"../app", "../services"} {
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return walkErr
+			}
+			fileSet := token.NewFileSet()
+			file, err := parser.ParseFile(fileSet, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				position, ok := targets[calleeName(call.Fun)]
+				if !ok || len(call.Args) <= position {
+					return true
+				}
+				literal, ok := call.Args[position].(*ast.BasicLit)
+				if !ok || literal.Kind != token.STRING {
+					return true
+				}
+				text, _ := strconv.Unquote(literal.Value)
+				if _, found := byJapanese[text]; !found {
+					t.Errorf("%s: message is missing from the catalog: %q", fileSet.Position(literal.Pos()), text)
+				}
+				return true
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("walk %s: %v", dir, err)
+		}
+	}
+}
+
+func calleeName(expr ast.Expr) string {
+	switch fun := expr.(type) {
+	case *ast.IndexExpr:
+		return calleeName(fun.X)
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
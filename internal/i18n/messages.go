@@ -0,0 +1,384 @@
+// API の結果メッセージと cloudlaunch コマンドの表示のカタログ（コード・日本語・英語）を定義する。
+package i18n
+
+// message はカタログの1件。ja はソース上のメッセージと一致させ、Localize の照合に使う。
+type message struct {
+	code string
+	ja   string
+	en   string
+}
+
+// messages は API の結果として返すメッセージとコマンドラインの表示の一覧。新しいメッセージを返すときはここにも追加する。
+var messages = []message{
+	// 共通
+	{"common.invalidId", "IDが不正です", "Invalid ID"},
+	{"common.invalidKey", "キーが不正です", "Invalid key"},
+	{"common.invalidPath", "パスが不正です", "Invalid path"},
+	{"common.deleteFailed", "削除に失敗しました", "Failed to delete"},
+	{"common.uploadFailed", "アップロードに失敗しました", "Upload failed"},
+	{"common.downloadFailed", "ダウンロードに失敗しました", "Download failed"},
+	{"common.jsonBuildFailed", "JSONの生成に失敗しました", "Failed to build JSON"},
+	{"common.jsonSaveFailed", "JSONファイルの保存に失敗しました", "Failed to save the JSON file"},
+	{"common.csvSaveFailed", "CSVファイルの保存に失敗しました", "Failed to save the CSV file"},
+	{"common.detailFetchFailed", "詳細取得に失敗しました", "Failed to load details"},
+	{"common.invalidYear", "年の指定が不正です", "Invalid year"},
+	{"common.invalidComparison", "比較条件が不正です", "Invalid comparison"},
+	{"common.canceled", "処理をキャンセルしました", "The operation was canceled"},
+
+	// ファイル・フォルダ
+	{"file.noFileSelected", "ファイルが選択されませんでした", "No file was selected"},
+	{"file.selectFailed", "ファイル選択に失敗しました", "Failed to select a file"},
+	{"file.openFailed", "ファイルを開くのに失敗しました", "Failed to open the file"},
+	{"file.existsCheckFailed", "ファイル存在チェックに失敗しました", "Failed to check whether the file exists"},
+	{"file.invalidOpenTarget", "開くファイルが不正です", "Invalid file to open"},
+	{"file.invalidExecutable", "実行ファイルが不正です", "Invalid executable"},
+	{"file.noFolderSelected", "フォルダが選択されませんでした", "No folder was selected"},
+	{"file.folderSelectFailed", "フォルダ選択に失敗しました", "Failed to select a folder"},
+	{"file.folderOpenFailed", "フォルダを開くのに失敗しました", "Failed to open the folder"},
+	{"file.dirExistsCheckFailed", "ディレクトリ存在チェックに失敗しました", "Failed to check whether the folder exists"},
+	{"file.invalidTargetFolder", "保存先のフォルダが不正です", "Invalid destination folder"},
+	{"file.targetFolderNotFound", "保存先のフォルダが見つかりません", "Destination folder not found"},
+	{"file.invalidOutputFolder", "出力先フォルダが不正です", "Invalid output folder"},
+	{"file.outputFolderCreateFailed", "出力先フォルダの作成に失敗しました", "Failed to create the output folder"},
+	{"file.invalidDeletePath", "削除対象のパスが不正です", "Invalid path to delete"},
+	{"file.invalidDeleteFile", "削除対象のファイルが不正です", "Invalid file to delete"},
+	{"file.invalidDownloadFile", "ダウンロード対象のファイルが不正です", "Invalid file to download"},
+	{"file.invalidPreviewFile", "プレビュー対象のファイルが不正です", "Invalid file to preview"},
+	{"file.dbRelativePathFailed", "DB相対パスの解決に失敗しました", "Failed to resolve the database-relative path"},
+
+	// 画像・サムネイル
+	{"image.notSpecified", "画像ファイルが指定されていません", "No image file was specified"},
+	{"image.readFailed", "画像ファイルを読み込めませんでした", "Could not read the image file"},
+	{"image.decodeFailed", "画像として読み込めませんでした", "Could not read the file as an image"},
+	{"image.loadFailed", "画像読み込みに失敗しました", "Failed to load the image"},
+	{"image.clipboardEmpty", "クリップボードに画像がありません", "There is no image on the clipboard"},
+	{"image.coverSaveFailed", "カバー画像の保存に失敗しました", "Failed to save the cover image"},
+	{"image.coverSetFailed", "カバー画像の設定に失敗しました", "Failed to set the cover image"},
+	{"image.exeIconFailed", "実行ファイルのアイコンを取得できませんでした", "Could not get the icon of the executable"},
+	{"image.invalidJpegQuality", "JPEG品質が不正です", "Invalid JPEG quality"},
+	{"thumbnail.invalidSize", "サムネイルサイズが不正です", "Invalid thumbnail size"},
+	{"thumbnail.regenerateFailed", "サムネイルの再生成に失敗しました", "Failed to regenerate thumbnails"},
+
+	// ゲーム
+	{"game.invalidId", "ゲームIDが不正です", "Invalid game ID"},
+	{"game.invalidInput", "ゲーム入力が不正です", "Invalid game input"},
+	{"game.notFound", "ゲームが見つかりません", "Game not found"},
+	{"game.fetchFailed", "ゲーム取得に失敗しました", "Failed to load the game"},
+	{"game.getFailed", "ゲームの取得に失敗しました", "Failed to get the game"},
+	{"game.listFailed", "ゲーム一覧取得に失敗しました", "Failed to load the game list"},
+	{"game.listGetFailed", "ゲーム一覧の取得に失敗しました", "Failed to get the game list"},
+	{"game.exportFailed", "ゲーム一覧の出力に失敗しました", "Failed to export the game list"},
+	{"game.createFailed", "ゲーム作成に失敗しました", "Failed to create the game"},
+	{"game.updateFailed", "ゲーム更新に失敗しました", "Failed to update the game"},
+	{"game.deleteFailed", "ゲーム削除に失敗しました", "Failed to delete the game"},
+	{"game.launchFailed", "ゲーム起動に失敗しました", "Failed to launch the game"},
+	{"game.preLaunchFailed", "起動前コマンドの実行に失敗しました", "The pre-launch command failed"},
+	{"game.processNotFound", "ゲームのプロセスが見つかりません", "The game process was not found"},
+	{"game.archivedReadOnly", "アーカイブ済みのゲームは変更できません", "Archived games cannot be changed"},
+	{"game.archiveFailed", "ゲームのアーカイブに失敗しました", "Failed to archive the game"},
+	{"game.unarchiveFailed", "ゲームのアーカイブ解除に失敗しました", "Failed to unarchive the game"},
+	{"game.archiveStateFailed", "アーカイブ状態の更新に失敗しました", "Failed to update the archive state"},
+	{"game.favoriteFailed", "お気に入りの更新に失敗しました", "Failed to update favorites"},
+	{"game.playTimeUpdateFailed", "プレイ時間更新に失敗しました", "Failed to update play time"},
+	{"game.aggregatesFailed", "ゲーム集計の再計算に失敗しました", "Failed to recalculate game totals"},
+	{"game.recentFailed", "最近遊んだゲームの取得に失敗しました", "Failed to load recently played games"},
+	{"game.invalidEstimate", "見込み時間が不正です", "Invalid estimated time"},
+	{"game.invalidEntityType", "エンティティ種別が不正です", "Invalid entity type"},
+	{"game.invalidSaveInclude", "セーブの対象パターンが不正です", "Invalid save include patterns"},
+	{"game.invalidSaveExclude", "セーブの除外パターンが不正です", "Invalid save exclude patterns"},
+	{"game.invalidLaunchWrapper", "起動ラッパーの設定が不正です", "Invalid launch wrapper settings"},
+	{"game.launchWrapperSetFailed", "起動ラッパーの設定に失敗しました", "Failed to set the launch wrapper"},
+	{"game.launchWrapperSaveFailed", "起動ラッパーの保存に失敗しました", "Failed to save the launch wrapper"},
+	{"game.invalidProcessMatch", "プロセスの一致条件が不正です", "Invalid process match rule"},
+	{"game.processMatchSetFailed", "プロセスの一致条件の設定に失敗しました", "Failed to set the process match rule"},
+	{"game.processMatchSaveFailed", "プロセスの一致条件の保存に失敗しました", "Failed to save the process match rule"},
+	{"game.invalidPlayTimeMode", "プレイ時間の数え方が不正です", "Invalid play time mode"},
+	{"game.playTimeModeSetFailed", "プレイ時間の数え方の設定に失敗しました", "Failed to set the play time mode"},
+	{"game.playTimeModeSaveFailed", "プレイ時間の数え方の保存に失敗しました", "Failed to save the play time mode"},
+	{"game.invalidPlayStatus", "playStatus が不正です", "Invalid playStatus"},
+	{"game.playCalendarFailed", "プレイカレンダーの取得に失敗しました", "Failed to load the play calendar"},
+
+	// プレイ状況
+	{"playStatus.invalidId", "プレイ状況の ID が不正です", "Invalid play status ID"},
+	{"playStatus.invalidInput", "プレイ状況の入力が不正です", "Invalid play status input"},
+	{"playStatus.notFound", "プレイ状況が見つかりません", "Play status not found"},
+	{"playStatus.duplicate", "同じ ID のプレイ状況が既にあります", "A play status with the same ID already exists"},
+	{"playStatus.builtinReadOnly", "組み込みのプレイ状況は変更できません", "Built-in play statuses cannot be changed"},
+	{"playStatus.fetchFailed", "プレイ状況の取得に失敗しました", "Failed to load play statuses"},
+	{"playStatus.createFailed", "プレイ状況の作成に失敗しました", "Failed to create the play status"},
+	{"playStatus.updateFailed", "プレイ状況の更新に失敗しました", "Failed to update the play status"},
+	{"playStatus.deleteFailed", "プレイ状況の削除に失敗しました", "Failed to delete the play status"},
+
+	// セッション
+	{"session.invalidId", "セッションIDが不正です", "Invalid session ID"},
+	{"session.invalidInput", "セッション入力が不正です", "Invalid session input"},
+	{"session.notFound", "セッションが見つかりません", "Session not found"},
+	{"session.fetchFailed", "セッション取得に失敗しました", "Failed to load sessions"},
+	{"session.createFailed", "セッション作成に失敗しました", "Failed to create the session"},
+	{"session.updateFailed", "セッション更新に失敗しました", "Failed to update the session"},
+	{"session.deleteFailed", "セッション削除に失敗しました", "Failed to delete the session"},
+	{"session.nameUpdateFailed", "セッション名更新に失敗しました", "Failed to rename the session"},
+	{"session.memoUpdateFailed", "セッションメモ更新に失敗しました", "Failed to update the session memo"},
+	{"session.routeUpdateFailed", "セッションルート更新に失敗しました", "Failed to update the session route"},
+	{"session.invalidStartTime", "開始時刻が不正です", "Invalid start time"},
+	{"session.startTimeApplyFailed", "開始時刻の反映に失敗しました", "Failed to apply the start time"},
+	{"session.startTimeApplyUnavailable", "開始時刻を反映できません", "The start time cannot be applied"},
+	{"session.pauseFailed", "中断に失敗しました", "Failed to pause the session"},
+	{"session.resumeFailed", "再開に失敗しました", "Failed to resume the session"},
+	{"session.endFailed", "終了に失敗しました", "Failed to end the session"},
+
+	// ルート・テンプレート
+	{"route.invalidInput", "ルート入力が不正です", "Invalid route input"},
+	{"route.notFound", "ルートが見つかりません", "Route not found"},
+	{"route.fetchFailed", "ルート取得に失敗しました", "Failed to load routes"},
+	{"route.createFailed", "ルート作成に失敗しました", "Failed to create the route"},
+	{"route.updateFailed", "ルート更新に失敗しました", "Failed to update the route"},
+	{"route.deleteFailed", "ルート削除に失敗しました", "Failed to delete the route"},
+	{"route.invalidOrder", "ルート順序が不正です", "Invalid route order"},
+	{"route.orderUpdateFailed", "ルート順序更新に失敗しました", "Failed to update the route order"},
+	{"route.currentUpdateFailed", "現在ルート更新に失敗しました", "Failed to update the current route"},
+	{"route.progressFetchFailed", "ルート進捗取得に失敗しました", "Failed to load route progress"},
+	{"route.progressUpdateFailed", "ルート進捗更新に失敗しました", "Failed to update route progress"},
+	{"route.statsFailed", "ルート統計取得に失敗しました", "Failed to load route statistics"},
+	{"route.compareFailed", "周回比較に失敗しました", "Failed to compare playthroughs"},
+	{"routeTemplate.invalidId", "テンプレートIDが不正です", "Invalid template ID"},
+	{"routeTemplate.invalidName", "テンプレート名が不正です", "Invalid template name"},
+	{"routeTemplate.noRoutes", "テンプレートにするルートがありません", "There are no routes to turn into a template"},
+	{"routeTemplate.notFound", "ルートテンプレートが見つかりません", "Route template not found"},
+	{"routeTemplate.fetchFailed", "ルートテンプレート取得に失敗しました", "Failed to load route templates"},
+	{"routeTemplate.saveFailed", "ルートテンプレート保存に失敗しました", "Failed to save the route template"},
+	{"routeTemplate.deleteFailed", "ルートテンプレート削除に失敗しました", "Failed to delete the route template"},
+	{"routeTemplate.applyFailed", "ルートテンプレート適用に失敗しました", "Failed to apply the route template"},
+
+	// リンク
+	{"link.invalidId", "リンクIDが不正です", "Invalid link ID"},
+	{"link.invalidInput", "リンク入力が不正です", "Invalid link input"},
+	{"link.invalidUrl", "リンクのURLが不正です", "Invalid link URL"},
+	{"link.notFound", "リンクが見つかりません", "Link not found"},
+	{"link.fetchFailed", "リンク取得に失敗しました", "Failed to load links"},
+	{"link.createFailed", "リンク作成に失敗しました", "Failed to create the link"},
+	{"link.updateFailed", "リンク更新に失敗しました", "Failed to update the link"},
+	{"link.deleteFailed", "リンク削除に失敗しました", "Failed to delete the link"},
+	{"link.openFailed", "リンクを開けませんでした", "Could not open the link"},
+
+	// メモ
+	{"memo.invalidId", "メモIDが不正です", "Invalid memo ID"},
+	{"memo.invalidInput", "メモ入力が不正です", "Invalid memo input"},
+	{"memo.invalidTitle", "メモタイトルが不正です", "Invalid memo title"},
+	{"memo.notFound", "メモが見つかりません", "Memo not found"},
+	{"memo.fetchFailed", "メモ取得に失敗しました", "Failed to load memos"},
+	{"memo.createFailed", "メモ作成に失敗しました", "Failed to create the memo"},
+	{"memo.updateFailed", "メモ更新に失敗しました", "Failed to update the memo"},
+	{"memo.deleteFailed", "メモ削除に失敗しました", "Failed to delete the memo"},
+	{"memo.parseFailed", "メモの解析に失敗しました", "Failed to parse the memo"},
+	{"memo.fileCreateFailed", "メモファイル作成に失敗しました", "Failed to create the memo file"},
+	{"memo.fileUpdateFailed", "メモファイル更新に失敗しました", "Failed to update the memo file"},
+	{"memo.fileDeleteFailed", "メモファイル削除に失敗しました", "Failed to delete the memo file"},
+	{"memo.syncFailed", "メモ同期に失敗しました", "Failed to sync memos"},
+	{"memo.uploadFailed", "メモのアップロードに失敗しました", "Failed to upload the memo"},
+	{"memo.downloadFailed", "メモのダウンロードに失敗しました", "Failed to download the memo"},
+	{"memo.cloudFetchFailed", "クラウドメモ取得に失敗しました", "Failed to load cloud memos"},
+
+	// スクリーンショット
+	{"screenshot.disabled", "スクリーンショット機能が無効です", "Screenshots are disabled"},
+	{"screenshot.selfCapture", "CloudLaunch のウィンドウは撮影できません", "The CloudLaunch window cannot be captured"},
+	{"screenshot.excludedForeground", "除外対象のアプリが前面にあるため撮影しませんでした", "Skipped because an excluded app is in the foreground"},
+	{"screenshot.fetchFailed", "スクリーンショットの取得に失敗しました", "Failed to load screenshots"},
+	{"screenshot.syncFailed", "スクリーンショットの同期に失敗しました", "Failed to sync screenshots"},
+	{"screenshot.downloadFailed", "スクリーンショットのダウンロードに失敗しました", "Failed to download screenshots"},
+	{"screenshot.compressFailed", "スクリーンショットの圧縮に失敗しました", "Failed to compress screenshots"},
+	{"screenshot.extractFailed", "スクリーンショットの展開に失敗しました", "Failed to extract screenshots"},
+	{"screenshot.clipboardFailed", "クリップボードへのコピーに失敗しました", "Failed to copy to the clipboard"},
+	{"screenshot.invalidDelay", "撮影までの待ち時間が不正です", "Invalid capture delay"},
+	{"screenshot.invalidBurstCount", "連写の枚数が不正です", "Invalid burst count"},
+	{"screenshot.invalidBurstInterval", "連写の間隔が不正です", "Invalid burst interval"},
+	{"screenshot.invalidAutoSetting", "定期スクリーンショットの設定が不正です", "Invalid periodic screenshot settings"},
+	{"screenshot.autoFetchFailed", "定期スクリーンショット設定の取得に失敗しました", "Failed to load periodic screenshot settings"},
+	{"screenshot.autoSaveFailed", "定期スクリーンショット設定の保存に失敗しました", "Failed to save periodic screenshot settings"},
+
+	// 動画クリップ
+	{"clip.invalidBufferSeconds", "クリップの秒数が不正です", "Invalid clip length"},
+	{"clip.unavailable", "クリップの録画を利用できません", "Clip recording is unavailable"},
+	{"clip.notRecording", "録画していません", "Not recording"},
+	{"clip.noData", "録画データがまだありません", "No recording data yet"},
+	{"clip.ffmpegNotFound", "ffmpeg が見つかりません", "ffmpeg was not found"},
+	{"clip.saveFailed", "クリップの保存に失敗しました", "Failed to save the clip"},
+	{"clip.listFailed", "クリップ一覧の取得に失敗しました", "Failed to list clips"},
+	{"clip.invalidName", "クリップ名が不正です", "Invalid clip name"},
+	{"clip.notFound", "クリップが見つかりません", "Clip not found"},
+	{"clip.deleteFailed", "クリップの削除に失敗しました", "Failed to delete the clip"},
+
+	// 同期・クラウド
+	{"sync.statusFailed", "同期状態の取得に失敗しました", "Failed to get the sync status"},
+	{"sync.previewFailed", "同期プレビューに失敗しました", "Failed to preview the sync"},
+	{"sync.logFailed", "同期ログの取得に失敗しました", "Failed to load the sync log"},
+	{"sync.conflictResolveFailed", "コンフリクト解決に失敗しました", "Failed to resolve the conflict"},
+	{"sync.conflictMergeFailed", "コンフリクトの統合に失敗しました", "Failed to merge the conflict"},
+	{"sync.invalidConflictPolicy", "コンフリクトの解決方針が不正です", "Invalid conflict resolution policy"},
+	{"sync.restoreSaveFailed", "セーブの復元に失敗しました", "Failed to restore the save"},
+	{"sync.deviceInfoFailed", "デバイス情報の取得に失敗しました", "Failed to get device information"},
+	{"sync.invalidConcurrency", "同時実行数が不正です", "Invalid concurrency"},
+	{"sync.invalidPartSize", "パートサイズが不正です", "Invalid part size"},
+	{"sync.invalidLocalFolder", "同期先フォルダが不正です", "Invalid sync folder"},
+	{"sync.queueAddFailed", "送信待ちへの追加に失敗しました", "Failed to add to the upload queue"},
+	{"sync.queueListFailed", "送信待ち一覧の取得に失敗しました", "Failed to load the upload queue"},
+	{"sync.queueRetryFailed", "送信待ちの再送に失敗しました", "Failed to resend queued uploads"},
+	{"sync.queueDeleteFailed", "送信待ちの削除に失敗しました", "Failed to remove from the upload queue"},
+	{"cloud.dataFetchFailed", "クラウドデータ取得に失敗しました", "Failed to load cloud data"},
+	{"cloud.dataDeleteFailed", "クラウドデータ削除に失敗しました", "Failed to delete cloud data"},
+	{"cloud.metaFetchFailed", "クラウドメタ情報の取得に失敗しました", "Failed to load cloud metadata"},
+	{"cloud.historyFetchFailed", "クラウド履歴の取得に失敗しました", "Failed to load cloud history"},
+	{"cloud.historyRestoreFailed", "クラウド履歴の復元に失敗しました", "Failed to restore from cloud history"},
+	{"cloud.treeFetchFailed", "ディレクトリツリー取得に失敗しました", "Failed to load the folder tree"},
+	{"cloud.previewFailed", "プレビューの取得に失敗しました", "Failed to load the preview"},
+	{"cloud.encryptedPreviewTooLarge", "暗号化されたファイルが大きすぎるためプレビューできません", "This encrypted file is too large to preview"},
+	{"cloud.encryptedPreviewNeedsPassphrase", "暗号化されたファイルはパスフレーズを設定するとプレビューできます", "Set the encryption passphrase to preview encrypted files"},
+	{"cloud.decryptFailed", "暗号化されたファイルを復号できません", "Could not decrypt the encrypted file"},
+	{"cloud.bulkDownloadFailed", "一括ダウンロードに失敗しました", "Bulk download failed"},
+	{"cloud.consistencyCheckFailed", "クラウド整合性チェックに失敗しました", "Cloud consistency check failed"},
+	{"cloud.consistencyRepairFailed", "クラウド整合性の修復に失敗しました", "Failed to repair cloud consistency"},
+	{"cloud.invalidEndpoint", "エンドポイントが不正です", "Invalid endpoint"},
+	{"cloud.bucketNotWritable", "バケットへの書き込み権限がありません", "You do not have write access to the bucket"},
+
+	// Google ドライブ
+	{"gdrive.notConnected", "Google ドライブに接続されていません", "Google Drive is not connected"},
+	{"gdrive.clientNotConfigured", "Google ドライブの OAuth クライアントが未設定です", "The Google Drive OAuth client is not configured"},
+	{"gdrive.refreshTokenMissing", "Google ドライブのリフレッシュトークンを取得できません", "Could not obtain a Google Drive refresh token"},
+	{"gdrive.notInitialized", "Google ドライブの接続が未初期化です", "Google Drive is not initialized"},
+	{"gdrive.disconnectFailed", "Google ドライブの接続を解除できません", "Could not disconnect Google Drive"},
+	{"gdrive.saveFailed", "Google ドライブの接続情報の保存に失敗しました", "Failed to save the Google Drive connection"},
+	{"gdrive.deleteFailed", "Google ドライブの接続情報の削除に失敗しました", "Failed to delete the Google Drive connection"},
+	{"gdrive.loadFailed", "Google ドライブの接続情報の取得に失敗しました", "Failed to load the Google Drive connection"},
+	{"gdrive.statusFailed", "Google ドライブの接続状態を取得できません", "Could not get the Google Drive connection status"},
+	{"gdrive.notStarted", "Google ドライブへの接続が開始されていません", "Google Drive sign-in has not been started"},
+	{"gdrive.startFailed", "Google ドライブへの接続を開始できません", "Could not start Google Drive sign-in"},
+	{"gdrive.storeNotInitialized", "接続情報の保存先が未初期化です", "The connection store is not initialized"},
+
+	// 認証情報
+	{"credential.invalid", "認証情報が不正です", "Invalid credentials"},
+	{"credential.invalidKey", "認証情報キーが不正です", "Invalid credential key"},
+	{"credential.notFound", "認証情報が見つかりません", "Credentials not found"},
+	{"credential.fetchFailed", "認証情報取得に失敗しました", "Failed to load credentials"},
+	{"credential.listFailed", "認証情報一覧の取得に失敗しました", "Failed to load the credential list"},
+	{"credential.saveFailed", "認証情報保存に失敗しました", "Failed to save credentials"},
+	{"credential.deleteFailed", "認証情報削除に失敗しました", "Failed to delete credentials"},
+	{"credential.validateFailed", "認証情報検証に失敗しました", "Failed to validate credentials"},
+
+	// ゲーム情報の取り込み
+	{"metadata.notInitialized", "ゲーム情報の取り込みサービスが未初期化です", "The game info import service is not initialized"},
+	{"metadata.apiKeySaveFailed", "API キーの保存に失敗しました", "Failed to save the API key"},
+	{"metadata.apiKeyStoreNotInitialized", "API キーの保存先が未初期化です", "The API key store is not initialized"},
+	{"metadata.apiKeyDeleteFailed", "API キーの削除に失敗しました", "Failed to delete the API key"},
+	{"metadata.apiKeySetFailed", "API キーの設定に失敗しました", "Failed to set the API key"},
+	{"metadata.apiKeyUnsupported", "API キーを設定できない取り込み元です", "This source does not take an API key"},
+	{"erogamescape.notInitialized", "批評空間サービスが未初期化です", "The ErogameScape service is not initialized"},
+	{"erogamescape.matchFailed", "批評空間との照合に失敗しました", "Failed to match with ErogameScape"},
+	{"erogamescape.applyFailed", "批評空間の情報の反映に失敗しました", "Failed to apply ErogameScape data"},
+
+	// 監視・ホットキー
+	{"monitor.disabled", "監視が無効です", "Monitoring is disabled"},
+	{"monitor.invalidInterval", "監視間隔が不正です", "Invalid monitoring interval"},
+	{"monitor.invalidTimeout", "監視のタイムアウトが不正です", "Invalid monitoring timeout"},
+	{"monitor.invalidIdleThreshold", "無操作しきい値が不正です", "Invalid idle threshold"},
+	{"monitor.invalidWarmupPolicy", "起動済みゲームの扱いが不正です", "Invalid policy for games already running"},
+	{"monitor.processListFailed", "プロセス一覧の取得に失敗しました", "Failed to list processes"},
+	{"monitor.exclusionSetFailed", "自動計測の除外設定に失敗しました", "Failed to set the auto-tracking exclusion"},
+	{"monitor.exclusionSaveFailed", "自動計測の除外設定の保存に失敗しました", "Failed to save the auto-tracking exclusion"},
+	{"hotkey.invalid", "ホットキーが不正です", "Invalid hotkey"},
+	{"playReminder.invalidMinutes", "リマインダー間隔が不正です", "Invalid reminder interval"},
+	{"playReminder.fetchFailed", "リマインダー設定の取得に失敗しました", "Failed to load the reminder settings"},
+	{"playReminder.saveFailed", "リマインダー設定の保存に失敗しました", "Failed to save the reminder settings"},
+
+	// 設定・ログ
+	{"settings.invalid", "設定が不正です", "Invalid settings"},
+	{"settings.fetchFailed", "設定の取得に失敗しました", "Failed to load settings"},
+	{"settings.saveFailed", "設定の保存に失敗しました", "Failed to save settings"},
+	{"settings.invalidHttp", "HTTP設定が不正です", "Invalid HTTP settings"},
+	{"settings.httpClientFailed", "HTTPクライアントの生成に失敗しました", "Failed to create the HTTP client"},
+	{"settings.invalidCacheTtl", "キャッシュの有効期間が不正です", "Invalid cache lifetime"},
+	{"settings.cacheClearFailed", "キャッシュの削除に失敗しました", "Failed to clear the cache"},
+	{"settings.invalidLanguage", "表示言語が不正です", "Invalid language"},
+	{"settings.languagesFetchFailed", "表示言語の取得に失敗しました", "Failed to list languages"},
+	{"log.invalidLevel", "ログレベルが不正です", "Invalid log level"},
+	{"log.dirUnknown", "ログディレクトリが不明です", "The log folder is unknown"},
+	{"log.dirOpenFailed", "ログディレクトリを開くのに失敗しました", "Failed to open the log folder"},
+	{"log.queryFailed", "ログの取得に失敗しました", "Failed to load the logs"},
+	{"journal.fetchFailed", "変更履歴の取得に失敗しました", "Failed to load the change history"},
+	{"activity.fetchFailed", "操作履歴の取得に失敗しました", "Failed to load the activity log"},
+	{"activity.invalidAction", "操作種別が不正です", "Invalid action"},
+	{"activity.invalidPeriod", "期間の指定が不正です", "Invalid period"},
+	{"onboarding.invalidStep", "初回セットアップの手順が不正です", "Invalid setup step"},
+	{"onboarding.fetchFailed", "初回セットアップの状態の取得に失敗しました", "Failed to load the setup progress"},
+	{"onboarding.saveFailed", "初回セットアップの状態の保存に失敗しました", "Failed to save the setup progress"},
+	{"protocol.invalidUri", "URI が不正です", "Invalid URI"},
+
+	// バックアップ・メンテナンス
+	{"backup.createFailed", "バックアップ作成に失敗しました", "Failed to create the backup"},
+	{"backup.restoreFailed", "バックアップ復元に失敗しました", "Failed to restore the backup"},
+	{"backup.prepareFailed", "バックアップ準備に失敗しました", "Failed to prepare the backup"},
+	{"backup.extractFailed", "バックアップ展開に失敗しました", "Failed to extract the backup"},
+	{"backup.invalidFile", "バックアップファイルが不正です", "Invalid backup file"},
+	{"backup.fileNotFound", "バックアップファイルが見つかりません", "Backup file not found"},
+	{"backup.fileCheckFailed", "バックアップファイルの確認に失敗しました", "Failed to check the backup file"},
+	{"backup.invalidSourceDir", "バックアップ元ディレクトリが不正です", "Invalid backup source folder"},
+	{"backup.invalidTargetDb", "バックアップ対象DBが不正です", "Invalid database to back up"},
+	{"backup.tempDirFailed", "復元用一時ディレクトリの作成に失敗しました", "Failed to create a temporary folder for the restore"},
+	{"backup.snapshotFailed", "DBスナップショットの取得に失敗しました", "Failed to take a database snapshot"},
+	{"maintenance.schemaVersionFailed", "スキーマバージョンの取得に失敗しました", "Failed to get the schema version"},
+	{"maintenance.integrityFailed", "整合性チェックに失敗しました", "Integrity check failed"},
+	{"maintenance.unusedScanFailed", "未使用ファイルの検出に失敗しました", "Failed to find unused files"},
+	{"maintenance.unusedCleanupFailed", "未使用ファイルの整理に失敗しました", "Failed to clean up unused files"},
+
+	// 実行中の処理
+	{"operation.invalidId", "処理IDが不正です", "Invalid operation ID"},
+
+	// Webhook
+	{"webhook.invalid", "Webhook の設定が不正です", "Invalid webhook settings"},
+	{"webhook.fetchFailed", "Webhook 設定の取得に失敗しました", "Failed to load the webhook settings"},
+	{"webhook.saveFailed", "Webhook 設定の保存に失敗しました", "Failed to save the webhook settings"},
+	{"webhook.notFound", "Webhook が見つかりません", "Webhook not found"},
+	{"webhook.sendFailed", "Webhook の送信に失敗しました", "Failed to send the webhook"},
+
+	// コマンドライン（cloudlaunch コマンドの表示。%d などは fmt の書式）
+	{"cli.usage", "使い方: cloudlaunch <コマンド> [オプション]", "usage: cloudlaunch <command> [options]"},
+	{"cli.commands", "コマンド:", "commands:"},
+	{"cli.exitCodes", "終了コード: %d 成功、%d 失敗、%d 引数の誤り、%d 同期で判断待ち（コンフリクト・未追跡ファイルの削除確認）", "exit codes: %d ok, %d failure, %d usage, %d sync needs attention (conflict / untracked deletes)"},
+	{"cli.unknownCommand", "不明なコマンドです: %q", "unknown command %q"},
+	{"cli.error", "エラー: %s", "error: %s"},
+	{"cli.offline", "オフラインモードが有効です。同期するにはアプリでオフにしてください", "offline mode is enabled; turn it off in the app to sync"},
+	{"cli.initFailed", "アプリの初期化に失敗しました: %w", "failed to initialize app: %w"},
+	{"cli.closeFailed", "データベースを閉じられませんでした: %v", "failed to close database: %v"},
+	{"cli.unsupportedFormat", "未対応の出力形式です: %q（%s を指定してください）", "unsupported format %q (use %s)"},
+	{"cli.unsupportedFilter", "未対応の絞り込みです: %q", "unsupported filter %q"},
+	{"cli.noArguments", "%s は引数を取りません", "%s takes no arguments"},
+	{"cli.listGames.summary", "登録済みのゲームを一覧表示する", "list registered games"},
+	{"cli.listGames.format", "出力形式: table か json", "output format: table or json"},
+	{"cli.listGames.filter", "all・unplayed・playing・played・archived・favorite のいずれか", "all, unplayed, playing, played, archived or favorite"},
+	{"cli.listGames.search", "タイトル・ブランド・ジャンルに TEXT を含むゲームだけを表示する", "only games whose title, publisher or genre contains TEXT"},
+	{"cli.listGames.header", "ID\tタイトル\t状態\tプレイ時間\t最終プレイ", "ID\tTITLE\tSTATUS\tPLAY TIME\tLAST PLAYED"},
+	{"cli.sync.summary", "全ゲームをクラウドと同期する（コンフリクトは触らない）", "sync every game with the cloud (conflicts are left untouched)"},
+	{"cli.sync.dryRun", "アップロード・ダウンロードの予定だけを表示する", "only show what would be uploaded or downloaded"},
+	{"cli.sync.deleteUntracked", "ダウンロード時に、クラウドに無いローカルのセーブファイルを削除する", "allow downloads to delete local save files that are not in the cloud"},
+	{"cli.sync.format", "出力形式: text か json", "output format: text or json"},
+	{"cli.sync.totals", "アップロード %d、ダウンロード %d、スキップ %d、コンフリクト %d、確認待ち %d、失敗 %d", "uploaded %d, downloaded %d, skipped %d, conflicts %d, pending %d, failed %d"},
+	{"cli.sync.pendingDeletes", "未追跡のファイル %d 件が削除されます。--delete-untracked を付けて実行し直してください", "%d untracked file(s) would be deleted; rerun with --delete-untracked"},
+	{"cli.backupSaves.summary", "ゲームのセーブデータをクラウドへアップロードする", "upload a game's save data to the cloud"},
+	{"cli.backupSaves.usage", "使い方: cloudlaunch backup-saves <gameID>", "usage: cloudlaunch backup-saves <gameID>"},
+	{"cli.backupSaves.done", "%s をバックアップしました", "backed up %s"},
+	{"cli.export.summary", "ゲーム・セッション・統計をエクスポートする（既定は標準出力）", "export games, sessions and statistics (stdout by default)"},
+	{"cli.export.format", "出力形式（json のみ）", "output format (only json is supported)"},
+	{"cli.export.output", "標準出力ではなく FILE へ書き出す", "write to FILE instead of stdout"},
+	{"cli.export.done", "%s へエクスポートしました", "exported to %s"},
+}
+
+var (
+	byJapanese = make(map[string]message, len(messages))
+	byCode     = make(map[string]message, len(messages))
+)
+
+func init() {
+	for _, entry := range messages {
+		byJapanese[entry.ja] = entry
+		byCode[entry.code] = entry
+	}
+}
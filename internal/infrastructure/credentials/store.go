@@ -9,6 +9,16 @@ type Credential struct {
 	BucketName      string
 	Region          string
 	Endpoint        string
+	// SessionToken は一時認証情報（STS / SSO で発行されたキー）の場合に指定する。
+	SessionToken string
+	// RoleARN を指定すると上記キーを元に STS AssumeRole し、得た一時認証情報で接続する。
+	RoleARN         string
+	RoleSessionName string
+	ExternalID      string
+	// EncryptionPassphrase を指定するとセーブデータ等をアップロード前に暗号化する（空なら平文）。
+	EncryptionPassphrase string
+	// OAuthRefreshToken は Google ドライブ等 OAuth で接続する保存先のリフレッシュトークン（S3 の認証情報では空）。
+	OAuthRefreshToken string
 }
 
 // Store は認証情報の保存・取得・削除を提供する。
@@ -16,4 +26,6 @@ type Store interface {
 	Save(ctx context.Context, key string, credential Credential) error
 	Load(ctx context.Context, key string) (*Credential, error)
 	Delete(ctx context.Context, key string) error
+	// List は保存済みのキー（名前空間を除いたもの）を返す。
+	List(ctx context.Context) ([]string, error)
 }
@@ -27,3 +27,7 @@ func (store *unsupportedStore) Load(ctx context.Context, key string) (*Credentia
 func (store *unsupportedStore) Delete(ctx context.Context, key string) error {
 	return errors.New("credential store is only supported on Windows")
 }
+
+func (store *unsupportedStore) List(ctx context.Context) ([]string, error) {
+	return nil, errors.New("credential store is only supported on Windows")
+}
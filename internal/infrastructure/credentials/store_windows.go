@@ -6,6 +6,7 @@ package credentials
 import (
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/danieljoos/wincred"
 )
@@ -60,6 +61,25 @@ func (store *WindowsStore) Delete(_ context.Context, key string) error {
 	return cred.Delete()
 }
 
+// List は名前空間配下の汎用資格情報からキー一覧を返す。
+func (store *WindowsStore) List(_ context.Context) ([]string, error) {
+	prefix := store.qualifiedKey("")
+	creds, error := wincred.FilteredList(prefix + "*")
+	if error != nil {
+		if error == wincred.ErrElementNotFound {
+			return []string{}, nil
+		}
+		return nil, error
+	}
+	keys := make([]string, 0, len(creds))
+	for _, cred := range creds {
+		if key, ok := strings.CutPrefix(cred.TargetName, prefix); ok && key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
 func (store *WindowsStore) qualifiedKey(key string) string {
 	return store.Namespace + ":" + key
 }
@@ -0,0 +1,99 @@
+// ユーザー操作の監査ログ（ActivityLog）の永続化を提供する。
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const activityLogSelectCols = `id, action, entityType, entityId, summary, createdAt`
+
+// InsertActivity はアクティビティを1件追記する。ID と CreatedAt は DB 側で採番する。
+func (repository *Repository) InsertActivity(ctx context.Context, entry domain.ActivityEntry) error {
+	_, err := repository.connection.ExecContext(ctx, `
+		INSERT INTO "ActivityLog" (action, entityType, entityId, summary) VALUES (?, ?, ?, ?)
+	`, entry.Action, entry.EntityType, entry.EntityID, entry.Summary)
+	return err
+}
+
+// ListActivityPage は条件に一致するアクティビティを新しい順に1ページ分と、全件数を取得する。
+func (repository *Repository) ListActivityPage(
+	ctx context.Context,
+	filter domain.ActivityFilter,
+	page domain.PageRequest,
+) ([]domain.ActivityEntry, int, error) {
+	page = page.Normalize()
+	where, args := activityFilterClause(filter)
+	var entries []domain.ActivityEntry
+	var total int
+	err := repository.WithReadTx(ctx, func(tx *Repository) error {
+		if err := tx.connection.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM "ActivityLog"`+where, args...).Scan(&total); err != nil {
+			return err
+		}
+		var err error
+		entries, err = queryAll(ctx, tx.connection,
+			`SELECT `+activityLogSelectCols+` FROM "ActivityLog"`+where+` ORDER BY id DESC LIMIT ? OFFSET ?`,
+			scanActivityEntry, append(args, page.Limit, page.Offset)...)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// PruneActivityLog は before より古いアクティビティを削除し、削除件数を返す。
+func (repository *Repository) PruneActivityLog(ctx context.Context, before time.Time) (int64, error) {
+	res, err := repository.connection.ExecContext(ctx, `DELETE FROM "ActivityLog" WHERE createdAt < ?`,
+		activityTimestamp(before))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func activityFilterClause(filter domain.ActivityFilter) (string, []any) {
+	conditions := make([]string, 0, 5)
+	args := make([]any, 0, 5)
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entityType = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entityId = ?")
+		args = append(args, filter.EntityID)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "createdAt >= ?")
+		args = append(args, activityTimestamp(filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "createdAt < ?")
+		args = append(args, activityTimestamp(filter.Until))
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// activityTimestamp は createdAt（CURRENT_TIMESTAMP の UTC "YYYY-MM-DD HH:MM:SS"）と比べられる書式にする。
+func activityTimestamp(value time.Time) string {
+	return value.UTC().Format(time.DateTime)
+}
+
+func scanActivityEntry(row scanner) (*domain.ActivityEntry, error) {
+	entry := domain.ActivityEntry{}
+	if err := row.Scan(&entry.ID, &entry.Action, &entry.EntityType, &entry.EntityID, &entry.Summary, &entry.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,54 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRepositoryActivityLogFiltersAndPages(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	entries := []domain.ActivityEntry{
+		{Action: domain.ActivityActionCreate, EntityType: domain.ChangeEntityGame, EntityID: "game-1", Summary: "追加"},
+		{Action: domain.ActivityActionLaunch, EntityType: domain.ChangeEntityGame, EntityID: "game-1", Summary: "起動"},
+		{Action: domain.ActivityActionSync, Summary: "一括ダウンロード"},
+		{Action: domain.ActivityActionLaunch, EntityType: domain.ChangeEntityGame, EntityID: "game-2", Summary: "起動"},
+	}
+	for _, entry := range entries {
+		if err := repo.InsertActivity(ctx, entry); err != nil {
+			t.Fatalf("InsertActivity: %v", err)
+		}
+	}
+
+	all, total, err := repo.ListActivityPage(ctx, domain.ActivityFilter{}, domain.PageRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListActivityPage: %v", err)
+	}
+	if total != 4 || len(all) != 2 || all[0].EntityID != "game-2" || all[1].Action != domain.ActivityActionSync {
+		t.Fatalf("expected newest first, got total=%d %+v", total, all)
+	}
+	if all[0].CreatedAt.IsZero() {
+		t.Fatalf("expected createdAt to be set: %+v", all[0])
+	}
+
+	launches, total, err := repo.ListActivityPage(ctx,
+		domain.ActivityFilter{Action: domain.ActivityActionLaunch, EntityID: "game-1"}, domain.PageRequest{})
+	if err != nil || total != 1 || len(launches) != 1 || launches[0].Summary != "起動" {
+		t.Fatalf("unexpected filtered result: total=%d %+v err=%v", total, launches, err)
+	}
+
+	future := domain.ActivityFilter{Since: time.Now().Add(time.Hour)}
+	if none, total, err := repo.ListActivityPage(ctx, future, domain.PageRequest{}); err != nil || total != 0 || len(none) != 0 {
+		t.Fatalf("expected no entries after since, got total=%d %+v err=%v", total, none, err)
+	}
+
+	removed, err := repo.PruneActivityLog(ctx, time.Now().Add(time.Hour))
+	if err != nil || removed != 4 {
+		t.Fatalf("PruneActivityLog: removed=%d err=%v", removed, err)
+	}
+}
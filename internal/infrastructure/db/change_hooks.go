@@ -0,0 +1,83 @@
+// Repository の書き込みを購読するフックを提供する。
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+)
+
+// changeHooks は Repository とそのトランザクションで共有する書き込みの購読先。
+type changeHooks struct {
+	mu           sync.Mutex
+	gamesChanged []func()
+}
+
+// notifyGamesChanged は "Game" テーブルの購読先を順に呼ぶ。購読先の中から OnGamesChanged を呼んでもよい。
+func (hooks *changeHooks) notifyGamesChanged() {
+	hooks.mu.Lock()
+	listeners := append([]func(){}, hooks.gamesChanged...)
+	hooks.mu.Unlock()
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// OnGamesChanged は "Game" テーブルへの書き込み（作成・更新・削除）のたびに fn を呼ぶよう登録する。
+// トランザクション内の書き込みは、コミットした後に1回だけ通知する（ロールバックした場合は通知しない）。
+// fn は書き込んだ goroutine で同期的に呼ばれるため、キャッシュの破棄など軽い処理にとどめること。
+func (repository *Repository) OnGamesChanged(fn func()) {
+	if repository.hooks == nil || fn == nil {
+		return
+	}
+	repository.hooks.mu.Lock()
+	defer repository.hooks.mu.Unlock()
+	repository.hooks.gamesChanged = append(repository.hooks.gamesChanged, fn)
+}
+
+// hookedConn は成功した書き込み文のうち "Game" テーブルに触れるものを onGameWrite で知らせる dbConn。
+// INSERT ... RETURNING のように QueryRowContext で書き込む文も対象にする。
+type hookedConn struct {
+	dbConn
+	onGameWrite func()
+}
+
+func (conn hookedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := conn.dbConn.ExecContext(ctx, query, args...)
+	if err == nil && writesGames(query) {
+		conn.onGameWrite()
+	}
+	return result, err
+}
+
+func (conn hookedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := conn.dbConn.QueryContext(ctx, query, args...)
+	if err == nil && writesGames(query) {
+		conn.onGameWrite()
+	}
+	return rows, err
+}
+
+func (conn hookedConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	row := conn.dbConn.QueryRowContext(ctx, query, args...)
+	if row.Err() == nil && writesGames(query) {
+		conn.onGameWrite()
+	}
+	return row
+}
+
+// writesGames は query が "Game" テーブルへの書き込み文かを返す。
+// 判定は文頭のキーワードとテーブル名だけの粗いもので、余分に通知すること（購読側の読み直し）は許容する。
+func writesGames(query string) bool {
+	if !strings.Contains(query, `"Game"`) {
+		return false
+	}
+	head := strings.TrimSpace(query)
+	for _, keyword := range []string{"INSERT", "UPDATE", "DELETE", "REPLACE"} {
+		if len(head) >= len(keyword) && strings.EqualFold(head[:len(keyword)], keyword) {
+			return true
+		}
+	}
+	return false
+}
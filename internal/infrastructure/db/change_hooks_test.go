@@ -0,0 +1,86 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/db"
+)
+
+func TestOnGamesChangedNotifiesGameWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	var calls atomic.Int32
+	repo.OnGamesChanged(func() { calls.Add(1) })
+
+	game, err := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls after CreateGame = %d, want 1", got)
+	}
+	if _, err := repo.ListGames(ctx, "", "", "title", "asc"); err != nil {
+		t.Fatalf("ListGames: %v", err)
+	}
+	if _, err := repo.CreateMemo(ctx, domain.Memo{Title: "memo", Content: "text", GameID: game.ID}); err != nil {
+		t.Fatalf("CreateMemo: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("reads and other tables should not notify, calls = %d", got)
+	}
+	if err := repo.DeleteGame(ctx, game.ID); err != nil {
+		t.Fatalf("DeleteGame: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("calls after DeleteGame = %d, want 2", got)
+	}
+}
+
+func TestOnGamesChangedNotifiesOnceAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	var calls atomic.Int32
+	repo.OnGamesChanged(func() { calls.Add(1) })
+
+	err := repo.WithTx(ctx, func(tx *db.Repository) error {
+		if err := tx.UpdateGameTotalPlayTime(ctx, game.ID, 60); err != nil {
+			return err
+		}
+		if err := tx.UpdateGameTotalPlayTime(ctx, game.ID, 30); err != nil {
+			return err
+		}
+		if got := calls.Load(); got != 0 {
+			t.Errorf("notified before commit, calls = %d", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls after commit = %d, want 1", got)
+	}
+
+	failure := errors.New("boom")
+	err = repo.WithTx(ctx, func(tx *db.Repository) error {
+		if err := tx.UpdateGameTotalPlayTime(ctx, game.ID, 60); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("rolled back writes should not notify, calls = %d", got)
+	}
+}
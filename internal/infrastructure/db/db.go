@@ -4,31 +4,104 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
-// Open は必要なプラグマを設定して SQLite データベースを開く。
+// 接続設定の既定値。
+const (
+	DefaultJournalMode  = "wal"
+	DefaultBusyTimeout  = 5 * time.Second
+	DefaultMaxOpenConns = 4
+	DefaultMaxIdleConns = 2
+)
+
+// Options は SQLite 接続のプラグマとコネクションプールの設定。ゼロ値の項目は既定値を使う。
+type Options struct {
+	// JournalMode は journal_mode（"wal"・"delete"・"truncate"・"persist"）。
+	// WAL が使えないネットワークドライブ上に DB を置く場合などは "delete" に戻す。
+	JournalMode string
+	// BusyTimeout は別のコネクションが書き込み中のとき、SQLITE_BUSY で失敗せずに待つ上限。
+	BusyTimeout time.Duration
+	// MaxOpenConns / MaxIdleConns はコネクションプールの上限。
+	MaxOpenConns int
+	MaxIdleConns int
+}
+
+// normalized は未指定の項目を既定値で埋め、journal_mode を検証した Options を返す。
+func (options Options) normalized() (Options, error) {
+	options.JournalMode = strings.ToLower(strings.TrimSpace(options.JournalMode))
+	switch options.JournalMode {
+	case "":
+		options.JournalMode = DefaultJournalMode
+	case "wal", "delete", "truncate", "persist":
+	default:
+		return Options{}, fmt.Errorf("unsupported journal mode %q", options.JournalMode)
+	}
+	if options.BusyTimeout <= 0 {
+		options.BusyTimeout = DefaultBusyTimeout
+	}
+	if options.MaxOpenConns <= 0 {
+		options.MaxOpenConns = DefaultMaxOpenConns
+	}
+	if options.MaxIdleConns <= 0 {
+		options.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if options.MaxIdleConns > options.MaxOpenConns {
+		options.MaxIdleConns = options.MaxOpenConns
+	}
+	return options, nil
+}
+
+// Open は既定の設定で SQLite データベースを開く。
+func Open(databasePath string) (*sql.DB, error) {
+	return OpenWithOptions(databasePath, Options{})
+}
+
+// OpenWithOptions は必要なプラグマを設定して SQLite データベースを開く。
+//
+// journal_mode=WAL: 監視 goroutine のセッション保存や自動同期の書き込み中も、画面からの読み込みを
+// 待たせない。フルバックアップは VACUUM INTO でスナップショットを取るため、チェックポイント前の
+// WAL の内容も含まれる。
 //
 // busy_timeout: ApplyPullResult 等の複数文の書き込みトランザクションと、バックグラウンドの
 // 自動 Push・別 Pull が別コネクションで競合したとき、即 SQLITE_BUSY（"database is
-// locked"）で失敗せず一定時間（5秒）待機・リトライさせる。
-// （journal_mode=WAL は読み書き並行性を高められるが、フルバックアップがDBファイルの
+// locked"）で失敗せず一定時間待機・リトライさせる。
 //
-//	コピー方式のため WAL 化するとチェックポイント未済データを取りこぼす。ここでは採用しない。）
-func Open(databasePath string) (*sql.DB, error) {
+// _txlock=immediate: トランザクションは BEGIN IMMEDIATE で始め、開始時点で書き込みロックを取る。
+// 書き込みトランザクション同士は busy_timeout の範囲で順番待ちになり、読み込みから書き込みへの
+// 昇格で待たずに失敗する（WAL では busy_timeout が効かない）ことがなくなる。
+func OpenWithOptions(databasePath string, options Options) (*sql.DB, error) {
+	options, error := options.normalized()
+	if error != nil {
+		return nil, error
+	}
 	dsn := fmt.Sprintf(
-		"file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)",
+		"file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(%d)&_pragma=journal_mode(%s)&_txlock=immediate",
 		databasePath,
+		options.BusyTimeout.Milliseconds(),
+		options.JournalMode,
 	)
 	connection, error := sql.Open("sqlite", dsn)
 	if error != nil {
 		return nil, error
 	}
+	connection.SetMaxOpenConns(options.MaxOpenConns)
+	connection.SetMaxIdleConns(options.MaxIdleConns)
 
 	if error := connection.Ping(); error != nil {
+		_ = connection.Close()
 		return nil, error
 	}
 
 	return connection, nil
 }
+
+// Checkpoint は WAL の内容を DB ファイルへ書き戻し、WAL を空にする。
+// DB ファイルを直接コピーする前に呼び、コピーに最新の内容が含まれるようにする。WAL でなければ何もしない。
+func Checkpoint(connection *sql.DB) error {
+	_, error := connection.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	return error
+}
@@ -0,0 +1,101 @@
+package db_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/infrastructure/db"
+)
+
+func TestOpenUsesWALByDefault(t *testing.T) {
+	t.Parallel()
+	conn, err := db.Open(filepath.Join(t.TempDir(), "wal.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	var mode string
+	if err := conn.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("PRAGMA journal_mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Fatalf("journal_mode = %q, want wal", mode)
+	}
+	if got := conn.Stats().MaxOpenConnections; got != db.DefaultMaxOpenConns {
+		t.Fatalf("MaxOpenConnections = %d, want %d", got, db.DefaultMaxOpenConns)
+	}
+}
+
+func TestOpenWithOptionsAppliesSettings(t *testing.T) {
+	t.Parallel()
+	conn, err := db.OpenWithOptions(filepath.Join(t.TempDir(), "options.db"), db.Options{
+		JournalMode:  "DELETE",
+		BusyTimeout:  1500 * time.Millisecond,
+		MaxOpenConns: 2,
+	})
+	if err != nil {
+		t.Fatalf("OpenWithOptions: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	var mode string
+	var timeout int
+	if err := conn.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("PRAGMA journal_mode: %v", err)
+	}
+	if err := conn.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("PRAGMA busy_timeout: %v", err)
+	}
+	if mode != "delete" || timeout != 1500 || conn.Stats().MaxOpenConnections != 2 {
+		t.Fatalf("journal_mode = %q, busy_timeout = %d, max open = %d", mode, timeout, conn.Stats().MaxOpenConnections)
+	}
+
+	if _, err := db.OpenWithOptions(filepath.Join(t.TempDir(), "bad.db"), db.Options{JournalMode: "off"}); err == nil {
+		t.Fatal("unsupported journal mode should be rejected")
+	}
+}
+
+// TestConcurrentWriteTransactionsDoNotFailWithBusy は、読み込んでから書き込むトランザクションを
+// 並行に実行しても "database is locked" で失敗せず、順番に実行されて更新を取りこぼさないことを確認する。
+func TestConcurrentWriteTransactionsDoNotFailWithBusy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, err := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.WithTx(ctx, func(tx *db.Repository) error {
+				current, err := tx.GetGameByID(ctx, game.ID)
+				if err != nil {
+					return err
+				}
+				return tx.UpdateGameTotalPlayTime(ctx, game.ID, current.TotalPlayTime+10)
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("WithTx: %v", err)
+		}
+	}
+	got, err := repo.GetGameByID(ctx, game.ID)
+	if err != nil || got.TotalPlayTime != writers*10 {
+		t.Fatalf("TotalPlayTime = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,132 @@
+// ゲームの外部リンク（GameLink）の永続化を提供する。
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const gameLinkSelectCols = `id, gameId, label, url, sortOrder, createdAt, updatedAt`
+
+// ListGameLinksByGame はゲームのリンク一覧を表示順で取得する。
+func (repository *Repository) ListGameLinksByGame(ctx context.Context, gameID string) ([]domain.GameLink, error) {
+	return queryAll(ctx, repository.connection,
+		`SELECT `+gameLinkSelectCols+` FROM "GameLink" WHERE gameId = ? ORDER BY sortOrder ASC, createdAt ASC, id`,
+		scanGameLink, gameID)
+}
+
+// ListGameLinksByGames は複数ゲームのリンクをゲームIDごとにまとめて取得する。
+// 指定した全 gameID がキーとして含まれる（リンクが無いゲームは nil）。
+func (repository *Repository) ListGameLinksByGames(ctx context.Context, gameIDs []string) (map[string][]domain.GameLink, error) {
+	result := make(map[string][]domain.GameLink, len(gameIDs))
+	for _, id := range gameIDs {
+		result[id] = nil
+	}
+	if len(gameIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(gameIDs))
+	args := make([]any, len(gameIDs))
+	for i, id := range gameIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	query := `SELECT ` + gameLinkSelectCols + ` FROM "GameLink" WHERE gameId IN (` +
+		strings.Join(placeholders, ",") + `) ORDER BY gameId, sortOrder ASC, createdAt ASC, id`
+
+	links, err := queryAll(ctx, repository.connection, query, scanGameLink, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		result[link.GameID] = append(result[link.GameID], link)
+	}
+	return result, nil
+}
+
+// GetGameLinkByID はリンクIDでリンクを取得する。
+func (repository *Repository) GetGameLinkByID(ctx context.Context, linkID string) (*domain.GameLink, error) {
+	row := repository.connection.QueryRowContext(ctx, `SELECT `+gameLinkSelectCols+` FROM "GameLink" WHERE id = ?`, linkID)
+	link, err := scanGameLink(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// CreateGameLink はリンクをゲームの末尾に追加して返す。
+func (repository *Repository) CreateGameLink(ctx context.Context, link domain.GameLink) (*domain.GameLink, error) {
+	var id string
+	err := repository.connection.QueryRowContext(ctx, `
+		INSERT INTO "GameLink" (gameId, label, url, sortOrder)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(sortOrder) + 1, 0) FROM "GameLink" WHERE gameId = ?))
+		RETURNING id
+	`, link.GameID, link.Label, link.URL, link.GameID).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := repository.GetGameLinkByID(ctx, id)
+	if err != nil || created == nil {
+		return created, err
+	}
+	recordChange(ctx, repository, domain.ChangeEntityLink, created.ID, "CreateGameLink", nil, created)
+	return created, nil
+}
+
+// UpdateGameLink はリンクのラベル・URL・表示順を更新して返す。
+func (repository *Repository) UpdateGameLink(ctx context.Context, link domain.GameLink) (*domain.GameLink, error) {
+	before := repository.snapshotGameLink(ctx, link.ID)
+	if _, err := repository.connection.ExecContext(ctx, `
+		UPDATE "GameLink" SET label = ?, url = ?, sortOrder = ? WHERE id = ?
+	`, link.Label, link.URL, link.SortOrder, link.ID); err != nil {
+		return nil, err
+	}
+	updated, err := repository.GetGameLinkByID(ctx, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	recordChange(ctx, repository, domain.ChangeEntityLink, link.ID, "UpdateGameLink", before, updated)
+	return updated, nil
+}
+
+// DeleteGameLink はリンクを削除する。
+func (repository *Repository) DeleteGameLink(ctx context.Context, linkID string) error {
+	before := repository.snapshotGameLink(ctx, linkID)
+	if _, err := repository.connection.ExecContext(ctx, `DELETE FROM "GameLink" WHERE id = ?`, linkID); err != nil {
+		return err
+	}
+	recordChange[domain.GameLink](ctx, repository, domain.ChangeEntityLink, linkID, "DeleteGameLink", before, nil)
+	return nil
+}
+
+// replaceGameLinksTx はゲームのリンクを links で置き換える（Pull の反映用）。
+func replaceGameLinksTx(ctx context.Context, tx dbConn, gameID string, links []domain.GameLink) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "GameLink" WHERE gameId = ?`, gameID); err != nil {
+		return err
+	}
+	for _, link := range links {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO "GameLink" (id, gameId, label, url, sortOrder, createdAt, updatedAt)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, link.ID, gameID, link.Label, link.URL, link.SortOrder, link.CreatedAt, link.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanGameLink(row scanner) (*domain.GameLink, error) {
+	link := domain.GameLink{}
+	if err := row.Scan(&link.ID, &link.GameID, &link.Label, &link.URL, &link.SortOrder, &link.CreatedAt, &link.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,82 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRepositoryGameLinksCRUDAppendsInOrder(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	game, err := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	first, err := repo.CreateGameLink(ctx, domain.GameLink{GameID: game.ID, Label: "公式サイト", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("CreateGameLink: %v", err)
+	}
+	second, err := repo.CreateGameLink(ctx, domain.GameLink{GameID: game.ID, Label: "パッチ", URL: "https://example.com/patch"})
+	if err != nil {
+		t.Fatalf("CreateGameLink: %v", err)
+	}
+	if first.SortOrder != 0 || second.SortOrder != 1 {
+		t.Fatalf("expected links to be appended, got %d and %d", first.SortOrder, second.SortOrder)
+	}
+
+	second.SortOrder = -1
+	second.Label = "先頭"
+	if _, err := repo.UpdateGameLink(ctx, *second); err != nil {
+		t.Fatalf("UpdateGameLink: %v", err)
+	}
+	links, err := repo.ListGameLinksByGame(ctx, game.ID)
+	if err != nil {
+		t.Fatalf("ListGameLinksByGame: %v", err)
+	}
+	if len(links) != 2 || links[0].ID != second.ID || links[0].Label != "先頭" {
+		t.Fatalf("unexpected links: %#v", links)
+	}
+
+	if err := repo.DeleteGameLink(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteGameLink: %v", err)
+	}
+	byGame, err := repo.ListGameLinksByGames(ctx, []string{game.ID, "missing"})
+	if err != nil {
+		t.Fatalf("ListGameLinksByGames: %v", err)
+	}
+	if len(byGame[game.ID]) != 1 || byGame["missing"] != nil {
+		t.Fatalf("unexpected links by game: %#v", byGame)
+	}
+}
+
+func TestRepositoryApplyPullResultReplacesGameLinks(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	game, err := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if _, err := repo.CreateGameLink(ctx, domain.GameLink{GameID: game.ID, Label: "old", URL: "https://old.example.com"}); err != nil {
+		t.Fatalf("CreateGameLink: %v", err)
+	}
+
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	remote := []domain.GameLink{{ID: "link-remote", Label: "DLsite", URL: "https://www.dlsite.com/", CreatedAt: now, UpdatedAt: now}}
+	if err := repo.ApplyPullResult(ctx, *game, nil, nil, remote, nil, "head", ""); err != nil {
+		t.Fatalf("ApplyPullResult: %v", err)
+	}
+	links, err := repo.ListGameLinksByGame(ctx, game.ID)
+	if err != nil {
+		t.Fatalf("ListGameLinksByGame: %v", err)
+	}
+	if len(links) != 1 || links[0].ID != "link-remote" || links[0].GameID != game.ID || !links[0].UpdatedAt.Equal(now) {
+		t.Fatalf("expected links to be replaced by remote, got %#v", links)
+	}
+}
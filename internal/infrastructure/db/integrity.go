@@ -0,0 +1,183 @@
+// データベースの整合性チェックと孤立データの修復を提供する。
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// orphanRepairSource は修復による変更を変更ジャーナルへ記録するときの操作元。
+const orphanRepairSource = "CheckIntegrity"
+
+// orphanCheck は孤立データ1種類の検出条件（FROM 以降）と修復文を表す。
+// journal は修復前の対象行を読み、修復後に変更ジャーナルへ記録する関数を返す。
+type orphanCheck struct {
+	kind    domain.OrphanKind
+	from    string
+	repair  string
+	journal func(ctx context.Context, tx *Repository, from string) (func(), error)
+}
+
+// orphanChecks は修復時に実行する順番で並べる（親の無いルートを先に消し、残った参照を外す）。
+var orphanChecks = []orphanCheck{
+	{
+		kind:    domain.OrphanKindRoute,
+		from:    `"Route" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		repair:  `DELETE FROM "Route" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		journal: journalOrphans(domain.ChangeEntityRoute, routeSelectCols, scanRoute, func(route domain.Route) string { return route.ID }, nil),
+	},
+	{
+		kind:    domain.OrphanKindSession,
+		from:    `"PlaySession" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		repair:  `DELETE FROM "PlaySession" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		journal: journalOrphans(domain.ChangeEntitySession, playSessionSelectCols, scanPlaySession, func(session domain.PlaySession) string { return session.ID }, nil),
+	},
+	{
+		kind:    domain.OrphanKindMemo,
+		from:    `"Memo" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		repair:  `DELETE FROM "Memo" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		journal: journalOrphans(domain.ChangeEntityMemo, memoSelectCols, scanMemo, func(memo domain.Memo) string { return memo.ID }, nil),
+	},
+	{
+		kind:    domain.OrphanKindGameLink,
+		from:    `"GameLink" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		repair:  `DELETE FROM "GameLink" WHERE gameId NOT IN (SELECT id FROM "Game")`,
+		journal: journalOrphans(domain.ChangeEntityLink, gameLinkSelectCols, scanGameLink, func(link domain.GameLink) string { return link.ID }, nil),
+	},
+	{
+		kind:    domain.OrphanKindSessionRoute,
+		from:    `"PlaySession" WHERE routeId IS NOT NULL AND routeId NOT IN (SELECT id FROM "Route")`,
+		repair:  `UPDATE "PlaySession" SET routeId = NULL WHERE routeId IS NOT NULL AND routeId NOT IN (SELECT id FROM "Route")`,
+		journal: journalOrphans(domain.ChangeEntitySession, playSessionSelectCols, scanPlaySession, func(session domain.PlaySession) string { return session.ID }, (*Repository).snapshotPlaySession),
+	},
+	{
+		kind:    domain.OrphanKindCurrentRoute,
+		from:    `"Game" WHERE currentRouteId IS NOT NULL AND currentRouteId NOT IN (SELECT id FROM "Route")`,
+		repair:  `UPDATE "Game" SET currentRouteId = NULL WHERE currentRouteId IS NOT NULL AND currentRouteId NOT IN (SELECT id FROM "Route")`,
+		journal: journalOrphans(domain.ChangeEntityGame, gameSelectCols, scanGame, func(game domain.Game) string { return game.ID }, (*Repository).snapshotGame),
+	},
+}
+
+// CheckIntegrity は PRAGMA integrity_check / foreign_key_check と孤立データの件数を調べる。
+// repair が true なら孤立データを1トランザクションで削除（参照のみ壊れている行は参照を外す）し、
+// 修復後の状態で外部キー違反を数え直す。integrity_check の問題（ファイル破損）は修復しない。
+// 修復で削除・変更した行は、他の変更と同じく変更ジャーナルへ記録する。
+func (repository *Repository) CheckIntegrity(ctx context.Context, repair bool) (domain.DatabaseIntegrityReport, error) {
+	report := domain.DatabaseIntegrityReport{
+		IntegrityMessages:    []string{},
+		ForeignKeyViolations: []domain.ForeignKeyViolation{},
+		Orphans:              make([]domain.OrphanCount, 0, len(orphanChecks)),
+	}
+
+	messages, err := queryAll(ctx, repository.connection, `PRAGMA integrity_check`, scanIntegrityMessage)
+	if err != nil {
+		return report, err
+	}
+	for _, message := range messages {
+		if !strings.EqualFold(message, "ok") {
+			report.IntegrityMessages = append(report.IntegrityMessages, message)
+		}
+	}
+
+	for _, check := range orphanChecks {
+		var count int
+		if err := repository.connection.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+check.from).Scan(&count); err != nil {
+			return report, err
+		}
+		report.Orphans = append(report.Orphans, domain.OrphanCount{Kind: check.kind, Count: count})
+	}
+
+	if repair {
+		err := repository.WithTx(ctx, func(tx *Repository) error {
+			for index, check := range orphanChecks {
+				record, err := check.journal(ctx, tx, check.from)
+				if err != nil {
+					return err
+				}
+				res, err := tx.connection.ExecContext(ctx, check.repair)
+				if err != nil {
+					return err
+				}
+				record()
+				affected, err := res.RowsAffected()
+				if err != nil {
+					return err
+				}
+				report.Orphans[index].Repaired = int(affected)
+			}
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+		report.Repaired = true
+	}
+
+	violations, err := queryAll(ctx, repository.connection, `PRAGMA foreign_key_check`, scanForeignKeyViolation)
+	if err != nil {
+		return report, err
+	}
+	if violations != nil {
+		report.ForeignKeyViolations = violations
+	}
+
+	report.Healthy = len(report.IntegrityMessages) == 0 && len(report.ForeignKeyViolations) == 0
+	for _, orphan := range report.Orphans {
+		if orphan.Count > orphan.Repaired {
+			report.Healthy = false
+		}
+	}
+	return report, nil
+}
+
+// journalOrphans は修復対象の行を読んでおき、修復後にその変更を記録する orphanCheck.journal を作る。
+// snapshot が nil なら行は削除されたものとして記録し、そうでなければ修復後の行を読み直して差分を記録する。
+func journalOrphans[T any](
+	entityType, columns string,
+	scan func(scanner) (*T, error),
+	idOf func(T) string,
+	snapshot func(repository *Repository, ctx context.Context, id string) *T,
+) func(ctx context.Context, tx *Repository, from string) (func(), error) {
+	return func(ctx context.Context, tx *Repository, from string) (func(), error) {
+		before, err := queryAll(ctx, tx.connection, `SELECT `+columns+` FROM `+from, scan)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			for i := range before {
+				id := idOf(before[i])
+				var after *T
+				if snapshot != nil {
+					after = snapshot(tx, ctx, id)
+				}
+				recordChange(ctx, tx, entityType, id, orphanRepairSource, &before[i], after)
+			}
+		}, nil
+	}
+}
+
+func scanIntegrityMessage(row scanner) (*string, error) {
+	var message string
+	if err := row.Scan(&message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// scanForeignKeyViolation は foreign_key_check の1行（table, rowid, parent, fkid）を読み取る。
+// WITHOUT ROWID テーブルでは rowid が NULL になる。
+func scanForeignKeyViolation(row scanner) (*domain.ForeignKeyViolation, error) {
+	var (
+		violation domain.ForeignKeyViolation
+		rowID     sql.NullInt64
+		fkID      int64
+	)
+	if err := row.Scan(&violation.Table, &rowID, &violation.Parent, &fkID); err != nil {
+		return nil, err
+	}
+	violation.RowID = rowID.Int64
+	return &violation, nil
+}
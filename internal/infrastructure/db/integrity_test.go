@@ -0,0 +1,93 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/db"
+)
+
+func TestCheckIntegrityReportsAndRepairsOrphans(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+	conn, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	if err := db.ApplyMigrations(conn); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repo := db.NewRepository(conn)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+
+	// クラッシュや外部キー無効時の書き込みで残った孤立データを、外部キーを切った接続で再現する。
+	raw, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(0)")
+	if err != nil {
+		t.Fatalf("open raw: %v", err)
+	}
+	t.Cleanup(func() { _ = raw.Close() })
+	for _, statement := range []string{
+		`INSERT INTO "PlaySession" (gameId, playedAt, duration) VALUES ('missing', CURRENT_TIMESTAMP, 60)`,
+		`INSERT INTO "Memo" (title, content, gameId) VALUES ('memo', 'body', 'missing')`,
+		`INSERT INTO "PlaySession" (gameId, playedAt, duration, routeId) VALUES ('` + game.ID + `', CURRENT_TIMESTAMP, 60, 'gone')`,
+		`UPDATE "Game" SET currentRouteId = 'gone' WHERE id = '` + game.ID + `'`,
+	} {
+		if _, err := raw.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("seed %q: %v", statement, err)
+		}
+	}
+
+	report, err := repo.CheckIntegrity(ctx, false)
+	if err != nil {
+		t.Fatalf("CheckIntegrity: %v", err)
+	}
+	wantCounts := map[domain.OrphanKind]int{
+		domain.OrphanKindSession:      1,
+		domain.OrphanKindMemo:         1,
+		domain.OrphanKindSessionRoute: 1,
+		domain.OrphanKindCurrentRoute: 1,
+	}
+	for _, orphan := range report.Orphans {
+		if orphan.Count != wantCounts[orphan.Kind] || orphan.Repaired != 0 {
+			t.Errorf("%s: got count=%d repaired=%d", orphan.Kind, orphan.Count, orphan.Repaired)
+		}
+	}
+	if report.Healthy || report.Repaired || len(report.ForeignKeyViolations) == 0 || len(report.IntegrityMessages) != 0 {
+		t.Fatalf("unexpected report before repair: %+v", report)
+	}
+
+	report, err = repo.CheckIntegrity(ctx, true)
+	if err != nil {
+		t.Fatalf("CheckIntegrity(repair): %v", err)
+	}
+	if !report.Healthy || !report.Repaired || len(report.ForeignKeyViolations) != 0 {
+		t.Fatalf("expected repaired database to be healthy, got %+v", report)
+	}
+
+	sessions, _ := repo.ListPlaySessionsByGame(ctx, game.ID)
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if len(sessions) != 1 || sessions[0].RouteID != nil || got.CurrentRouteID != nil {
+		t.Fatalf("expected dangling route refs to be cleared, got sessions=%+v game=%+v", sessions, got)
+	}
+	gameHistory, _ := repo.ListChangeHistory(ctx, domain.ChangeEntityGame, game.ID, 10)
+	if len(gameHistory) == 0 || gameHistory[0].Source != "CheckIntegrity" || gameHistory[0].Changes[0].Field != "currentRouteId" {
+		t.Fatalf("expected the repair to be journaled, got %+v", gameHistory)
+	}
+	sessionHistory, _ := repo.ListChangeHistory(ctx, domain.ChangeEntitySession, sessions[0].ID, 10)
+	if len(sessionHistory) == 0 || sessionHistory[0].Source != "CheckIntegrity" {
+		t.Fatalf("expected the session route repair to be journaled, got %+v", sessionHistory)
+	}
+
+	report, _ = repo.CheckIntegrity(ctx, false)
+	for _, orphan := range report.Orphans {
+		if orphan.Count != 0 {
+			t.Errorf("%s: expected no orphans after repair, got %d", orphan.Kind, orphan.Count)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// ローカル変更をフィールド単位の差分として記録する変更ジャーナルを提供する。
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/util"
+)
+
+const changeJournalSelectCols = `id, entityType, entityId, operation, source, changes, createdAt`
+
+// recordChange は before → after の差分をジャーナルへ追記する。
+// before が nil なら作成、after が nil なら削除として扱い、差分が無い更新は記録しない。
+// ジャーナルは調査用の付随情報のため、記録に失敗しても本来の変更は失敗扱いにしない。
+func recordChange[T any](ctx context.Context, repository *Repository, entityType, entityID, source string, before, after *T) {
+	if before == nil && after == nil {
+		return
+	}
+	operation := domain.ChangeOperationUpdate
+	switch {
+	case before == nil:
+		operation = domain.ChangeOperationCreate
+	case after == nil:
+		operation = domain.ChangeOperationDelete
+	}
+	changes, err := diffFields(before, after)
+	if err != nil || (operation == domain.ChangeOperationUpdate && len(changes) == 0) {
+		return
+	}
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return
+	}
+	_, _ = repository.connection.ExecContext(ctx, `
+		INSERT INTO "ChangeJournal" (entityType, entityId, operation, source, changes)
+		VALUES (?, ?, ?, ?, ?)
+	`, entityType, entityID, operation, source, string(payload))
+}
+
+// diffFields は JSON 表現をフィールドごとに比べ、値の変わったフィールドを名前順で返す。
+func diffFields[T any](before, after *T) ([]domain.FieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := make([]domain.FieldChange, 0)
+	for _, name := range names {
+		beforeValue, afterValue := beforeFields[name], afterFields[name]
+		if bytes.Equal(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, domain.FieldChange{Field: name, Before: beforeValue, After: afterValue})
+	}
+	return changes, nil
+}
+
+func jsonFields[T any](value *T) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if value == nil {
+		return fields, nil
+	}
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// ListChangeHistory はエンティティの変更履歴を新しい順に最大 limit 件返す。
+func (repository *Repository) ListChangeHistory(ctx context.Context, entityType, entityID string, limit int) ([]domain.ChangeEntry, error) {
+	return queryAll(ctx, repository.connection,
+		`SELECT `+changeJournalSelectCols+` FROM "ChangeJournal"
+		 WHERE entityType = ? AND entityId = ? ORDER BY id DESC LIMIT ?`,
+		scanChangeEntry, entityType, entityID, limit)
+}
+
+// PruneChangeJournal は before より古いジャーナルを削除し、削除件数を返す。
+func (repository *Repository) PruneChangeJournal(ctx context.Context, before time.Time) (int64, error) {
+	res, err := repository.connection.ExecContext(ctx, `DELETE FROM "ChangeJournal" WHERE createdAt < ?`,
+		// createdAt は CURRENT_TIMESTAMP（UTC の "YYYY-MM-DD HH:MM:SS"）なので同じ書式で比べる。
+		before.UTC().Format(time.DateTime))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func scanChangeEntry(row scanner) (*domain.ChangeEntry, error) {
+	entry := domain.ChangeEntry{}
+	var changes string
+	if err := row.Scan(&entry.ID, &entry.EntityType, &entry.EntityID, &entry.Operation, &entry.Source, &changes, &entry.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// snapshot 系は変更前の状態を取得する。取得に失敗した場合は nil（作成扱い）にする。
+
+func (repository *Repository) snapshotGame(ctx context.Context, gameID string) *domain.Game {
+	game, _ := repository.GetGameByID(ctx, gameID)
+	return game
+}
+
+func (repository *Repository) snapshotRoute(ctx context.Context, routeID string) *domain.Route {
+	route, _ := repository.GetRouteByID(ctx, routeID)
+	return route
+}
+
+func (repository *Repository) snapshotPlaySession(ctx context.Context, sessionID string) *domain.PlaySession {
+	session, _ := repository.GetPlaySessionByID(ctx, sessionID)
+	return session
+}
+
+func (repository *Repository) snapshotMemo(ctx context.Context, memoID string) *domain.Memo {
+	memo, _ := repository.GetMemoByID(ctx, memoID)
+	return memo
+}
+
+func (repository *Repository) snapshotGameLink(ctx context.Context, linkID string) *domain.GameLink {
+	link, _ := repository.GetGameLinkByID(ctx, linkID)
+	return link
+}
+
+// saveTreeField は Game に含まれない localSaveTree の変更をジャーナルに残すための表現。
+// ツリーの JSON はファイル数に比例して大きくなるため、内容ではなくハッシュを記録する。
+type saveTreeField struct {
+	LocalSaveTreeHash string `json:"localSaveTreeHash"`
+}
+
+func newSaveTreeField(tree string) *saveTreeField {
+	if tree == "" {
+		return &saveTreeField{}
+	}
+	return &saveTreeField{LocalSaveTreeHash: util.Sha256Hex([]byte(tree))}
+}
+
+// recordGameChange は操作後のゲームを取得して before との差分を記録する。
+func (repository *Repository) recordGameChange(ctx context.Context, gameID, source string, before *domain.Game) {
+	recordChange(ctx, repository, domain.ChangeEntityGame, gameID, source, before, repository.snapshotGame(ctx, gameID))
+}
+
+// recordSessionListChanges はゲーム配下のセッション一覧の before → after の差分を ID ごとに記録する。
+func (repository *Repository) recordSessionListChanges(ctx context.Context, gameID, source string, before []domain.PlaySession) {
+	after, err := repository.ListPlaySessionsByGame(ctx, gameID)
+	if err != nil {
+		return
+	}
+	recordListChanges(ctx, repository, domain.ChangeEntitySession, source, before, after, func(session domain.PlaySession) string { return session.ID })
+}
+
+// recordRouteListChanges はゲーム配下のルート一覧の before → after の差分を ID ごとに記録する。
+func (repository *Repository) recordRouteListChanges(ctx context.Context, gameID, source string, before []domain.Route) {
+	after, err := repository.ListRoutesByGame(ctx, gameID)
+	if err != nil {
+		return
+	}
+	recordListChanges(ctx, repository, domain.ChangeEntityRoute, source, before, after, func(route domain.Route) string { return route.ID })
+}
+
+// recordGameLinkListChanges はゲーム配下のリンク一覧の before → after の差分を ID ごとに記録する。
+func (repository *Repository) recordGameLinkListChanges(ctx context.Context, gameID, source string, before []domain.GameLink) {
+	after, err := repository.ListGameLinksByGame(ctx, gameID)
+	if err != nil {
+		return
+	}
+	recordListChanges(ctx, repository, domain.ChangeEntityLink, source, before, after, func(link domain.GameLink) string { return link.ID })
+}
+
+func recordListChanges[T any](
+	ctx context.Context,
+	repository *Repository,
+	entityType, source string,
+	before, after []T,
+	idOf func(T) string,
+) {
+	beforeByID := make(map[string]*T, len(before))
+	for i := range before {
+		beforeByID[idOf(before[i])] = &before[i]
+	}
+	for i := range after {
+		id := idOf(after[i])
+		recordChange(ctx, repository, entityType, id, source, beforeByID[id], &after[i])
+		delete(beforeByID, id)
+	}
+	removed := make([]string, 0, len(beforeByID))
+	for id := range beforeByID {
+		removed = append(removed, id)
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		recordChange[T](ctx, repository, entityType, id, source, beforeByID[id], nil)
+	}
+}
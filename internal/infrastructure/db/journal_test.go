@@ -0,0 +1,131 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRepositoryJournalsFieldLevelChanges(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	created, err := repo.CreateGame(ctx, newGame("Journal", `C:\journal.exe`))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	created.Title = "Journal 2"
+	if _, err := repo.UpdateGame(ctx, *created); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if err := repo.UpdateGameTotalPlayTime(ctx, created.ID, created.TotalPlayTime); err != nil {
+		t.Fatalf("UpdateGameTotalPlayTime: %v", err)
+	}
+
+	entries, err := repo.ListChangeHistory(ctx, domain.ChangeEntityGame, created.ID, 10)
+	if err != nil {
+		t.Fatalf("ListChangeHistory: %v", err)
+	}
+	// 値の変わらない UpdateGameTotalPlayTime は記録されない。
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	update := entries[0]
+	if update.Operation != domain.ChangeOperationUpdate || update.Source != "UpdateGame" {
+		t.Fatalf("unexpected update entry: %+v", update)
+	}
+	var titleChange *domain.FieldChange
+	for i := range update.Changes {
+		if update.Changes[i].Field == "title" {
+			titleChange = &update.Changes[i]
+		}
+	}
+	if titleChange == nil || titleChange.Before != "Journal" || titleChange.After != "Journal 2" {
+		t.Fatalf("expected title diff, got %+v", update.Changes)
+	}
+	if entries[1].Operation != domain.ChangeOperationCreate || entries[1].Source != "CreateGame" {
+		t.Fatalf("unexpected create entry: %+v", entries[1])
+	}
+
+	removed, err := repo.PruneChangeJournal(ctx, time.Now().Add(time.Hour))
+	if err != nil || removed != 2 {
+		t.Fatalf("PruneChangeJournal: removed=%d err=%v", removed, err)
+	}
+}
+
+func TestRepositoryJournalsPullSessionReplacement(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	game, err := repo.CreateGame(ctx, newGame("Pull", `C:\pull.exe`))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	local, err := repo.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: time.Now().UTC(), Duration: 60})
+	if err != nil {
+		t.Fatalf("CreatePlaySession: %v", err)
+	}
+	remote := domain.PlaySession{ID: "remote-1", GameID: game.ID, PlayedAt: time.Now().UTC(), Duration: 120, UpdatedAt: time.Now().UTC()}
+	pulled := *game
+	pulled.UpdatedAt = time.Now().UTC().Add(time.Hour)
+	if err := repo.ApplyPullResult(ctx, pulled, []domain.PlaySession{remote}, []string{local.ID}, nil, nil, "head", ""); err != nil {
+		t.Fatalf("ApplyPullResult: %v", err)
+	}
+
+	gameEntries, err := repo.ListChangeHistory(ctx, domain.ChangeEntityGame, game.ID, 10)
+	if err != nil || len(gameEntries) == 0 || gameEntries[0].Source != "ApplyPullResult" {
+		t.Fatalf("expected ApplyPullResult game entry, got %+v err=%v", gameEntries, err)
+	}
+	deleted, err := repo.ListChangeHistory(ctx, domain.ChangeEntitySession, local.ID, 10)
+	if err != nil || len(deleted) != 2 || deleted[0].Operation != domain.ChangeOperationDelete {
+		t.Fatalf("expected local session delete entry, got %+v err=%v", deleted, err)
+	}
+	added, err := repo.ListChangeHistory(ctx, domain.ChangeEntitySession, remote.ID, 10)
+	if err != nil || len(added) != 1 || added[0].Operation != domain.ChangeOperationCreate {
+		t.Fatalf("expected remote session create entry, got %+v err=%v", added, err)
+	}
+}
+
+func TestRepositoryJournalsLocalSaveHashAndTree(t *testing.T) {
+	t.Parallel()
+
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	game, err := repo.CreateGame(ctx, newGame("Save", `C:\save.exe`))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if err := repo.SetLocalSaveHash(ctx, game.ID, "hash-1", time.Now().UTC()); err != nil {
+		t.Fatalf("SetLocalSaveHash: %v", err)
+	}
+	if err := repo.SetLocalSaveTree(ctx, game.ID, `{"files":[]}`); err != nil {
+		t.Fatalf("SetLocalSaveTree: %v", err)
+	}
+	// 内容の変わらないツリーの書き直しは記録されない。
+	if err := repo.SetLocalSaveTree(ctx, game.ID, `{"files":[]}`); err != nil {
+		t.Fatalf("SetLocalSaveTree: %v", err)
+	}
+
+	entries, err := repo.ListChangeHistory(ctx, domain.ChangeEntityGame, game.ID, 10)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v err=%v", entries, err)
+	}
+	if entries[0].Source != "SetLocalSaveTree" || len(entries[0].Changes) != 1 || entries[0].Changes[0].Field != "localSaveTreeHash" {
+		t.Fatalf("unexpected tree entry: %+v", entries[0])
+	}
+	hashEntry := entries[1]
+	if hashEntry.Source != "SetLocalSaveHash" {
+		t.Fatalf("unexpected hash entry: %+v", hashEntry)
+	}
+	found := false
+	for _, change := range hashEntry.Changes {
+		found = found || (change.Field == "localSaveHash" && change.After == "hash-1")
+	}
+	if !found {
+		t.Fatalf("expected localSaveHash diff, got %+v", hashEntry.Changes)
+	}
+}
@@ -1,27 +1,85 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"sort"
 	"strings"
+
+	"CloudLaunch_Go/internal/domain"
 )
 
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
+// downMigrationFiles は各マイグレーションを戻す SQL。ファイル名は対応する up と同じにする。
+// 0006 以前はテーブル再作成で列を捨てており元に戻せないため、0007 以降にのみ用意する。
+//
+//go:embed migrations/down/*.sql
+var downMigrationFiles embed.FS
+
+// SchemaTooNewError は DB にこのアプリが知らないマイグレーションが適用済みであることを表す。
+// 古いアプリで新しい DB を開いた場合に、未知の列・テーブルで SQL エラーになる前に起動を止めるために返す。
+type SchemaTooNewError struct {
+	Unknown      []string
+	Downgradable bool
+}
+
+func (err *SchemaTooNewError) Error() string {
+	return fmt.Sprintf("データベースがこのバージョンより新しいアプリで更新されています（未知のマイグレーション: %s）", strings.Join(err.Unknown, ", "))
+}
+
 // ApplyMigrations は未適用の SQL マイグレーションを順に実行する。
+// このアプリが知らないマイグレーションが適用済みなら何も変更せず *SchemaTooNewError を返す。
 func ApplyMigrations(connection *sql.DB) error {
 	if error := ensureSchemaTable(connection); error != nil {
 		return error
 	}
 
-	entries, error := migrationFiles.ReadDir("migrations")
+	fileNames, error := migrationNames(migrationFiles, "migrations")
+	if error != nil {
+		return error
+	}
+	applied, error := listAppliedMigrations(context.Background(), connection)
 	if error != nil {
 		return error
 	}
+	if unknown, downgradable := unknownMigrations(fileNames, applied); len(unknown) > 0 {
+		return &SchemaTooNewError{Unknown: unknown, Downgradable: downgradable}
+	}
+
+	for _, fileName := range fileNames {
+		if _, ok := applied[fileName]; ok {
+			continue
+		}
 
+		sqlBytes, error := migrationFiles.ReadFile(fmt.Sprintf("migrations/%s", fileName))
+		if error != nil {
+			return error
+		}
+		downSQL, error := readDownMigration(fileName)
+		if error != nil {
+			return error
+		}
+
+		if error := applyMigration(connection, fileName, string(sqlBytes), downSQL); error != nil {
+			return error
+		}
+	}
+
+	return nil
+}
+
+// migrationNames は埋め込みディレクトリ直下の SQL ファイル名を昇順で返す。
+func migrationNames(files embed.FS, dir string) ([]string, error) {
+	entries, error := files.ReadDir(dir)
+	if error != nil {
+		return nil, error
+	}
 	fileNames := make([]string, 0, len(entries))
 	for _, entry := range entries {
 		if entry.IsDir() {
@@ -30,52 +88,192 @@ func ApplyMigrations(connection *sql.DB) error {
 		fileNames = append(fileNames, entry.Name())
 	}
 	sort.Strings(fileNames)
+	return fileNames, nil
+}
 
-	for _, fileName := range fileNames {
-		applied, error := isMigrationApplied(connection, fileName)
-		if error != nil {
-			return error
+// readDownMigration は fileName を戻す SQL を返す。用意されていない（戻せない）場合は空文字。
+func readDownMigration(fileName string) (string, error) {
+	data, error := downMigrationFiles.ReadFile(fmt.Sprintf("migrations/down/%s", fileName))
+	if errors.Is(error, fs.ErrNotExist) {
+		return "", nil
+	}
+	if error != nil {
+		return "", error
+	}
+	return string(data), nil
+}
+
+// appliedMigration は schema_migrations の1行を表す。DownSQL が空なら戻せない。
+type appliedMigration struct {
+	ID      string
+	DownSQL string
+}
+
+func listAppliedMigrations(ctx context.Context, conn dbConn) (map[string]appliedMigration, error) {
+	rows, error := queryAll(ctx, conn, `SELECT id, COALESCE(down_sql, '') FROM schema_migrations`, func(row scanner) (*appliedMigration, error) {
+		var migration appliedMigration
+		if error := row.Scan(&migration.ID, &migration.DownSQL); error != nil {
+			return nil, error
 		}
-		if applied {
+		return &migration, nil
+	})
+	if error != nil {
+		return nil, error
+	}
+	applied := make(map[string]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.ID] = row
+	}
+	return applied, nil
+}
+
+// unknownMigrations は適用済みのうち known に無いものを昇順で返し、そのすべてが戻せるかを併せて返す。
+func unknownMigrations(known []string, applied map[string]appliedMigration) ([]string, bool) {
+	knownSet := make(map[string]struct{}, len(known))
+	for _, name := range known {
+		knownSet[name] = struct{}{}
+	}
+	unknown := make([]string, 0)
+	downgradable := true
+	for id, migration := range applied {
+		if _, ok := knownSet[id]; ok {
 			continue
 		}
-
-		sqlBytes, error := migrationFiles.ReadFile(fmt.Sprintf("migrations/%s", fileName))
-		if error != nil {
-			return error
+		unknown = append(unknown, id)
+		if strings.TrimSpace(migration.DownSQL) == "" {
+			downgradable = false
 		}
+	}
+	sort.Strings(unknown)
+	return unknown, downgradable
+}
 
-		if error := applyMigration(connection, fileName, string(sqlBytes)); error != nil {
-			return error
+// readSchemaVersion は適用済みマイグレーションとこのアプリが知るマイグレーションを突き合わせる。
+func readSchemaVersion(ctx context.Context, conn dbConn) (domain.SchemaVersion, error) {
+	fileNames, error := migrationNames(migrationFiles, "migrations")
+	if error != nil {
+		return domain.SchemaVersion{}, error
+	}
+	applied, error := listAppliedMigrations(ctx, conn)
+	if error != nil {
+		return domain.SchemaVersion{}, error
+	}
+	unknown, downgradable := unknownMigrations(fileNames, applied)
+	version := domain.SchemaVersion{
+		Applied:      len(applied),
+		Pending:      []string{},
+		Unknown:      unknown,
+		Downgradable: len(unknown) > 0 && downgradable,
+	}
+	if len(fileNames) > 0 {
+		version.Latest = fileNames[len(fileNames)-1]
+	}
+	for id := range applied {
+		if id > version.Current {
+			version.Current = id
+		}
+	}
+	for _, fileName := range fileNames {
+		if _, ok := applied[fileName]; !ok {
+			version.Pending = append(version.Pending, fileName)
 		}
 	}
+	return version, nil
+}
+
+// SchemaVersion は DB のスキーマバージョン情報を返す。
+func (repository *Repository) SchemaVersion(ctx context.Context) (domain.SchemaVersion, error) {
+	return readSchemaVersion(ctx, repository.connection)
+}
 
+// RollbackMigrations は target より後に適用されたマイグレーションを新しい順に戻す。
+// 戻し用 SQL は適用時に schema_migrations へ記録したものを使うため、このアプリが知らない
+// （より新しいアプリが適用した）マイグレーションも戻せる。1件でも戻せないものがあれば何も変更しない。
+func RollbackMigrations(connection *sql.DB, target string) error {
+	if error := ensureSchemaTable(connection); error != nil {
+		return error
+	}
+	applied, error := listAppliedMigrations(context.Background(), connection)
+	if error != nil {
+		return error
+	}
+	targets := make([]appliedMigration, 0)
+	irreversible := make([]string, 0)
+	for id, migration := range applied {
+		if id <= target {
+			continue
+		}
+		targets = append(targets, migration)
+		if strings.TrimSpace(migration.DownSQL) == "" {
+			irreversible = append(irreversible, id)
+		}
+	}
+	if len(irreversible) > 0 {
+		sort.Strings(irreversible)
+		return fmt.Errorf("戻せないマイグレーションがあります: %s", strings.Join(irreversible, ", "))
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].ID > targets[j].ID })
+	for _, migration := range targets {
+		if error := rollbackMigration(connection, migration); error != nil {
+			return fmt.Errorf("マイグレーション %s の巻き戻しに失敗: %w", migration.ID, error)
+		}
+	}
 	return nil
 }
 
+// RollbackUnknownMigrations はこのアプリが知らないマイグレーションをすべて戻し、
+// DB をこのアプリの最新スキーマに合わせる（古いバージョンへ戻したときの復旧用）。
+func RollbackUnknownMigrations(connection *sql.DB) error {
+	fileNames, error := migrationNames(migrationFiles, "migrations")
+	if error != nil {
+		return error
+	}
+	if len(fileNames) == 0 {
+		return nil
+	}
+	return RollbackMigrations(connection, fileNames[len(fileNames)-1])
+}
+
 // ensureSchemaTable はマイグレーション管理テーブルを作成する。
+// down_sql は戻し用 SQL で、このアプリより古いアプリでも巻き戻せるよう適用時に DB へ記録する。
+// down_sql 導入前に適用済みの行は、埋め込みの戻し用 SQL で補う。
 func ensureSchemaTable(connection *sql.DB) error {
-	_, error := connection.Exec(`
+	if _, error := connection.Exec(`
 		CREATE TABLE IF NOT EXISTS schema_migrations (
 			id TEXT NOT NULL PRIMARY KEY,
 			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 		);
-	`)
-	return error
-}
+	`); error != nil {
+		return error
+	}
+	var hasDownSQL int
+	if error := connection.QueryRow(`SELECT COUNT(1) FROM pragma_table_info('schema_migrations') WHERE name = 'down_sql'`).Scan(&hasDownSQL); error != nil {
+		return error
+	}
+	if hasDownSQL == 0 {
+		if _, error := connection.Exec(`ALTER TABLE schema_migrations ADD COLUMN down_sql TEXT`); error != nil {
+			return error
+		}
+	}
 
-// isMigrationApplied は指定マイグレーションが適用済みかを返す。
-func isMigrationApplied(connection *sql.DB, fileName string) (bool, error) {
-	var count int
-	error := connection.QueryRow(`SELECT COUNT(1) FROM schema_migrations WHERE id = ?`, fileName).Scan(&count)
+	downNames, error := migrationNames(downMigrationFiles, "migrations/down")
 	if error != nil {
-		return false, error
+		return error
+	}
+	for _, fileName := range downNames {
+		downSQL, error := readDownMigration(fileName)
+		if error != nil {
+			return error
+		}
+		if _, error := connection.Exec(`UPDATE schema_migrations SET down_sql = ? WHERE id = ? AND down_sql IS NULL`, downSQL, fileName); error != nil {
+			return error
+		}
 	}
-	return count > 0, nil
+	return nil
 }
 
-// applyMigration は単一マイグレーションをトランザクションで適用する。
-func applyMigration(connection *sql.DB, fileName string, sqlText string) error {
+// applyMigration は単一マイグレーションをトランザクションで適用し、戻し用 SQL を併せて記録する。
+func applyMigration(connection *sql.DB, fileName string, sqlText string, downSQL string) error {
 	statements := splitSQLStatements(sqlText)
 	transaction, error := connection.Begin()
 	if error != nil {
@@ -92,7 +290,36 @@ func applyMigration(connection *sql.DB, fileName string, sqlText string) error {
 		}
 	}
 
-	if _, error := transaction.Exec(`INSERT INTO schema_migrations (id) VALUES (?)`, fileName); error != nil {
+	var storedDownSQL any
+	if strings.TrimSpace(downSQL) != "" {
+		storedDownSQL = downSQL
+	}
+	if _, error := transaction.Exec(`INSERT INTO schema_migrations (id, down_sql) VALUES (?, ?)`, fileName, storedDownSQL); error != nil {
+		_ = transaction.Rollback()
+		return error
+	}
+
+	return transaction.Commit()
+}
+
+// rollbackMigration は単一マイグレーションの戻し用 SQL をトランザクションで実行し、適用記録を消す。
+func rollbackMigration(connection *sql.DB, migration appliedMigration) error {
+	transaction, error := connection.Begin()
+	if error != nil {
+		return error
+	}
+
+	for _, statement := range splitSQLStatements(migration.DownSQL) {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if _, error := transaction.Exec(statement); error != nil {
+			_ = transaction.Rollback()
+			return error
+		}
+	}
+
+	if _, error := transaction.Exec(`DELETE FROM schema_migrations WHERE id = ?`, migration.ID); error != nil {
 		_ = transaction.Rollback()
 		return error
 	}
@@ -0,0 +1,5 @@
+-- archivedAt はゲームをアーカイブした日時。NULL なら通常のゲーム。
+-- アーカイブ中はセッション・メモの変更と監視を止め、既定の一覧からも除外する（検索では表示する）。
+ALTER TABLE "Game" ADD COLUMN "archivedAt" DATETIME;
+
+CREATE INDEX IF NOT EXISTS "idx_games_archived_at" ON "Game"("archivedAt");
@@ -0,0 +1,14 @@
+-- ChangeJournal はローカルでの変更（エンティティ・フィールド単位の差分・操作名）を記録する。
+-- 同期のデバッグ用で、古い行は起動時に保持期間を過ぎたものから削除する。
+CREATE TABLE IF NOT EXISTS "ChangeJournal" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "entityType" TEXT NOT NULL,
+  "entityId" TEXT NOT NULL,
+  "operation" TEXT NOT NULL,
+  "source" TEXT NOT NULL,
+  "changes" TEXT NOT NULL,
+  "createdAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_change_journal_entity" ON "ChangeJournal"("entityType", "entityId", "id");
+CREATE INDEX IF NOT EXISTS "idx_change_journal_created_at" ON "ChangeJournal"("createdAt");
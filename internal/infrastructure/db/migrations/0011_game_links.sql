@@ -0,0 +1,23 @@
+-- GameLink はゲームごとの外部リンク（公式サイト・販売ページ・パッチ配布ページ等）。
+-- sortOrder は表示順で、ゲーム情報と同じくクラウド同期の game.json に含める。
+CREATE TABLE IF NOT EXISTS "GameLink" (
+  "id" TEXT NOT NULL PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+  "gameId" TEXT NOT NULL,
+  "label" TEXT NOT NULL,
+  "url" TEXT NOT NULL,
+  "sortOrder" INTEGER NOT NULL DEFAULT 0,
+  "createdAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY ("gameId") REFERENCES "Game"("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CHECK ("label" != ''),
+  CHECK ("url" != '')
+);
+
+CREATE INDEX IF NOT EXISTS "idx_game_links_game_id" ON "GameLink"("gameId", "sortOrder");
+
+CREATE TRIGGER IF NOT EXISTS "trigger_game_link_updated_at"
+AFTER UPDATE ON "GameLink"
+FOR EACH ROW
+BEGIN
+  UPDATE "GameLink" SET "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = OLD."id";
+END;
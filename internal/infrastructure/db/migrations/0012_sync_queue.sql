@@ -0,0 +1,22 @@
+-- SyncQueue はオフライン中に行われ、オンライン復帰後に再送すべきクラウド操作の送信待ち行列。
+-- 同じ (kind, targetId) は1行にまとめ、追加のたびに revision を進める。
+-- 再送中に同じ対象が再追加された場合に取りこぼさないよう、削除は revision が一致するときだけ行う。
+CREATE TABLE IF NOT EXISTS "SyncQueue" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "kind" TEXT NOT NULL,
+  "targetId" TEXT NOT NULL,
+  "revision" INTEGER NOT NULL DEFAULT 0,
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "lastError" TEXT,
+  "createdAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE ("kind", "targetId"),
+  CHECK ("targetId" != '')
+);
+
+CREATE TRIGGER IF NOT EXISTS "trigger_sync_queue_updated_at"
+AFTER UPDATE ON "SyncQueue"
+FOR EACH ROW
+BEGIN
+  UPDATE "SyncQueue" SET "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = OLD."id";
+END;
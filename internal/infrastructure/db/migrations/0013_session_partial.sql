@@ -0,0 +1,2 @@
+-- partial はアプリ起動時点で既に起動していたゲームのセッションで、起動前のプレイ時間を含まないことを表す。
+ALTER TABLE "PlaySession" ADD COLUMN "partial" INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- launchWrapperPath/launchWrapperArgs はゲームを Locale Emulator などのラッパー経由で起動する設定。
+-- ラッパーのインストール先は端末ごとに異なるため同期対象外の端末ローカル設定として扱う。NULL なら直接起動する。
+ALTER TABLE "Game" ADD COLUMN "launchWrapperPath" TEXT;
+ALTER TABLE "Game" ADD COLUMN "launchWrapperArgs" TEXT;
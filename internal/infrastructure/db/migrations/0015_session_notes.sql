@@ -0,0 +1,2 @@
+-- notes はセッションで何を進めたかを書き留める自由記述のメモ（未記入は NULL）。
+ALTER TABLE "PlaySession" ADD COLUMN "notes" TEXT;
@@ -0,0 +1,2 @@
+-- idleDuration は無操作のため自動で計測を止めていた時間（秒）。duration には含めない。
+ALTER TABLE "PlaySession" ADD COLUMN "idleDuration" INTEGER NOT NULL DEFAULT 0;
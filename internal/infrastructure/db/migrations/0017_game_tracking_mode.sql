@@ -0,0 +1,3 @@
+-- trackingMode はプレイ時間の数え方。process はプロセスが存在する間、foreground はゲームのウィンドウが前面にある間だけ数える。
+-- 起動ラッパーと同様に端末ローカルの設定として扱い、同期対象外とする。
+ALTER TABLE "Game" ADD COLUMN "trackingMode" TEXT NOT NULL DEFAULT 'process';
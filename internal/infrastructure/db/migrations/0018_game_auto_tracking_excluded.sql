@@ -0,0 +1,3 @@
+-- autoTrackingExcluded はプロセス監視の自動計測から外すゲームの印（エミュレーター等、実行ファイル名を共有するツール向け）。
+-- trackingMode と同様に端末ローカルの設定として扱い、同期対象外とする。
+ALTER TABLE "Game" ADD COLUMN "autoTrackingExcluded" INTEGER NOT NULL DEFAULT 0;
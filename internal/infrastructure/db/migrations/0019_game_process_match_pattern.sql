@@ -0,0 +1,4 @@
+-- processMatchPattern はエミュレーター経由で起動するゲームを ROM ごとに見分けるための、コマンドラインの一致条件。
+-- 実行ファイル名が同じプロセス（retroarch.exe 等）のうち、コマンドラインが一致するものだけをこのゲームとして数える。
+-- ROM の置き場所は端末ごとに異なるため、起動ラッパーと同様に同期対象外の端末ローカル設定とする。NULL なら実行ファイルだけで判定する。
+ALTER TABLE "Game" ADD COLUMN "processMatchPattern" TEXT;
@@ -0,0 +1,4 @@
+-- preLaunchCommand / postExitCommand はゲームの起動前・終了後に実行するコマンド（ドライブのマウント、コントローラー設定ツールの起動・終了、セーブ同期など）。
+-- コマンドが参照するツールやパスは端末ごとに異なるため、起動ラッパーと同様に同期対象外の端末ローカル設定とする。NULL なら実行しない。
+ALTER TABLE "Game" ADD COLUMN "preLaunchCommand" TEXT;
+ALTER TABLE "Game" ADD COLUMN "postExitCommand" TEXT;
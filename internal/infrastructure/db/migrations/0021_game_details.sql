@@ -0,0 +1,6 @@
+-- description / releaseDate / genres は取り込み元（批評空間・DLSite・IGDB 等）から取得した作品の概要・発売日（YYYY-MM-DD）・ジャンル（JSON 配列）。
+-- rating は利用者の評価（1〜100、取り込み時は取り込み元の得点で初期化）。いずれもゲーム情報として同期する。NULL なら未設定。
+ALTER TABLE "Game" ADD COLUMN "description" TEXT;
+ALTER TABLE "Game" ADD COLUMN "releaseDate" TEXT;
+ALTER TABLE "Game" ADD COLUMN "rating" INTEGER;
+ALTER TABLE "Game" ADD COLUMN "genres" TEXT;
@@ -0,0 +1,17 @@
+-- RouteTemplate はゲームをまたいで使い回すルート構成（ルート名の並び）。
+-- シリーズ作品など同じルート構成のゲームに一括でルートを作成するためのもので、端末ローカルの設定として同期しない。
+CREATE TABLE IF NOT EXISTS "RouteTemplate" (
+  "id" TEXT NOT NULL PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+  "name" TEXT NOT NULL UNIQUE,
+  "routeNames" TEXT NOT NULL DEFAULT '[]',
+  "createdAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK ("name" != '')
+);
+
+CREATE TRIGGER IF NOT EXISTS "trigger_route_template_updated_at"
+AFTER UPDATE ON "RouteTemplate"
+FOR EACH ROW
+BEGIN
+  UPDATE "RouteTemplate" SET "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = OLD."id";
+END;
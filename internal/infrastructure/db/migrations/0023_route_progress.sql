@@ -0,0 +1,4 @@
+-- estimatedTime はルートのクリアまでの見込み時間（秒）、completedAt はクリア済みにした日時。いずれも NULL なら未設定。
+-- ルート情報として routes.json に含めて同期する。
+ALTER TABLE "Route" ADD COLUMN "estimatedTime" INTEGER;
+ALTER TABLE "Route" ADD COLUMN "completedAt" DATETIME;
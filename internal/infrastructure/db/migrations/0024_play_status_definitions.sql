@@ -0,0 +1,27 @@
+-- PlayStatusDefinition は利用者が追加できるプレイ状況（ウィッシュリスト・積みゲー等）。
+-- 組み込みの playStatus（unplayed/playing/played）はそのまま残し、ゲームには別列 customStatus で slug を持たせる。
+-- 定義は端末ローカルだが、customStatus はゲーム情報として同期する（定義の無い端末では slug のまま表示する）。
+CREATE TABLE IF NOT EXISTS "PlayStatusDefinition" (
+  "slug" TEXT NOT NULL PRIMARY KEY,
+  "label" TEXT NOT NULL,
+  "color" TEXT NOT NULL DEFAULT '',
+  "sortOrder" INTEGER NOT NULL DEFAULT 0,
+  "createdAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK ("slug" != ''),
+  CHECK ("label" != '')
+);
+
+CREATE TRIGGER IF NOT EXISTS "trigger_play_status_definition_updated_at"
+AFTER UPDATE ON "PlayStatusDefinition"
+FOR EACH ROW
+BEGIN
+  UPDATE "PlayStatusDefinition" SET "updatedAt" = CURRENT_TIMESTAMP WHERE "slug" = OLD."slug";
+END;
+
+INSERT OR IGNORE INTO "PlayStatusDefinition" ("slug", "label", "color", "sortOrder") VALUES
+  ('wishlist', 'ウィッシュリスト', '#8b5cf6', 0),
+  ('backlog', '積みゲー', '#f59e0b', 1);
+
+ALTER TABLE "Game" ADD COLUMN "customStatus" TEXT;
+CREATE INDEX IF NOT EXISTS "idx_games_custom_status" ON "Game"("customStatus");
@@ -0,0 +1,2 @@
+-- isFavorite はライブラリの先頭に固定するお気に入り。ゲーム情報として同期する。
+ALTER TABLE "Game" ADD COLUMN "isFavorite" INTEGER NOT NULL DEFAULT 0;
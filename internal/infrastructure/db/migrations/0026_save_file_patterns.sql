@@ -0,0 +1,3 @@
+-- saveIncludePatterns / saveExcludePatterns はセーブの同期対象を絞り込む glob パターンの JSON 配列。ゲーム情報として同期する。
+ALTER TABLE "Game" ADD COLUMN "saveIncludePatterns" TEXT;
+ALTER TABLE "Game" ADD COLUMN "saveExcludePatterns" TEXT;
@@ -0,0 +1,13 @@
+-- ActivityLog はユーザー操作（作成・更新・削除・同期・起動）をいつ何をしたかの要約つきで記録する監査ログ。
+-- フィールド単位の差分は ChangeJournal が持ち、こちらは操作の一覧表示に使う。古い行は起動時に保持期間を過ぎたものから削除する。
+CREATE TABLE IF NOT EXISTS "ActivityLog" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "action" TEXT NOT NULL,
+  "entityType" TEXT NOT NULL DEFAULT '',
+  "entityId" TEXT NOT NULL DEFAULT '',
+  "summary" TEXT NOT NULL DEFAULT '',
+  "createdAt" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_activity_log_created_at" ON "ActivityLog"("createdAt");
+CREATE INDEX IF NOT EXISTS "idx_activity_log_entity" ON "ActivityLog"("entityType", "entityId", "id");
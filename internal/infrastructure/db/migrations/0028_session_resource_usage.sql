@@ -0,0 +1,6 @@
+-- cpu* は計測中に採ったゲームのプロセスの CPU 使用率（全コアに対する %）、memory* はワーキングセット（バイト）の最大値と平均値。
+-- 採れなかったセッションは 0 のまま。
+ALTER TABLE "PlaySession" ADD COLUMN "cpuPeakPercent" REAL NOT NULL DEFAULT 0;
+ALTER TABLE "PlaySession" ADD COLUMN "cpuAveragePercent" REAL NOT NULL DEFAULT 0;
+ALTER TABLE "PlaySession" ADD COLUMN "memoryPeakBytes" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "PlaySession" ADD COLUMN "memoryAverageBytes" INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "Settings";
+ALTER TABLE "Game" DROP COLUMN "localSyncHead";
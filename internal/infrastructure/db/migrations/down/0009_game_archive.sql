@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_games_archived_at";
+ALTER TABLE "Game" DROP COLUMN "archivedAt";
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "trigger_game_link_updated_at";
+DROP TABLE IF EXISTS "GameLink";
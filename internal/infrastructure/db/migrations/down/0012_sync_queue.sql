@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "trigger_sync_queue_updated_at";
+DROP TABLE IF EXISTS "SyncQueue";
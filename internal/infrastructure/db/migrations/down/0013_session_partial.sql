@@ -0,0 +1 @@
+ALTER TABLE "PlaySession" DROP COLUMN "partial";
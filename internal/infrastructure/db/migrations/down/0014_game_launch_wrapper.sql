@@ -0,0 +1,2 @@
+ALTER TABLE "Game" DROP COLUMN "launchWrapperPath";
+ALTER TABLE "Game" DROP COLUMN "launchWrapperArgs";
@@ -0,0 +1 @@
+ALTER TABLE "Game" DROP COLUMN "autoTrackingExcluded";
@@ -0,0 +1,2 @@
+ALTER TABLE "Game" DROP COLUMN "postExitCommand";
+ALTER TABLE "Game" DROP COLUMN "preLaunchCommand";
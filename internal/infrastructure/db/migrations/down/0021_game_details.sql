@@ -0,0 +1,4 @@
+ALTER TABLE "Game" DROP COLUMN "genres";
+ALTER TABLE "Game" DROP COLUMN "rating";
+ALTER TABLE "Game" DROP COLUMN "releaseDate";
+ALTER TABLE "Game" DROP COLUMN "description";
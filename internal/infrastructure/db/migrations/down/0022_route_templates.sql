@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "trigger_route_template_updated_at";
+DROP TABLE IF EXISTS "RouteTemplate";
@@ -0,0 +1,2 @@
+ALTER TABLE "Route" DROP COLUMN "completedAt";
+ALTER TABLE "Route" DROP COLUMN "estimatedTime";
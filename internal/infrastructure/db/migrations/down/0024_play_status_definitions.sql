@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "idx_games_custom_status";
+ALTER TABLE "Game" DROP COLUMN "customStatus";
+DROP TRIGGER IF EXISTS "trigger_play_status_definition_updated_at";
+DROP TABLE IF EXISTS "PlayStatusDefinition";
@@ -0,0 +1,2 @@
+ALTER TABLE "Game" DROP COLUMN "saveExcludePatterns";
+ALTER TABLE "Game" DROP COLUMN "saveIncludePatterns";
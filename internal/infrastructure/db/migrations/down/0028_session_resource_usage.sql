@@ -0,0 +1,4 @@
+ALTER TABLE "PlaySession" DROP COLUMN "memoryAverageBytes";
+ALTER TABLE "PlaySession" DROP COLUMN "memoryPeakBytes";
+ALTER TABLE "PlaySession" DROP COLUMN "cpuAveragePercent";
+ALTER TABLE "PlaySession" DROP COLUMN "cpuPeakPercent";
@@ -0,0 +1,158 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"CloudLaunch_Go/internal/infrastructure/db"
+)
+
+// firstReversibleMigration より前のマイグレーションはテーブル再作成で列を捨てており戻せない。
+const firstReversibleMigration = "0007"
+
+func openMigratedDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	if err := db.ApplyMigrations(conn); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	return conn
+}
+
+func TestMigrationsHaveDownScripts(t *testing.T) {
+	t.Parallel()
+
+	entries, err := os.ReadDir("migrations")
+	if err != nil {
+		t.Fatalf("read migrations: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() < firstReversibleMigration {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join("migrations", "down", entry.Name())); err != nil {
+			t.Errorf("missing down script for %s: %v", entry.Name(), err)
+		}
+	}
+}
+
+func TestSchemaVersionReportsLatest(t *testing.T) {
+	t.Parallel()
+
+	repo := db.NewRepository(openMigratedDB(t))
+	version, err := repo.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+	if version.Current == "" || version.Current != version.Latest {
+		t.Fatalf("expected current to be latest, got %#v", version)
+	}
+	if len(version.Pending) != 0 || len(version.Unknown) != 0 || version.Downgradable {
+		t.Fatalf("unexpected version: %#v", version)
+	}
+}
+
+func TestRollbackMigrationsAndReapply(t *testing.T) {
+	t.Parallel()
+
+	conn := openMigratedDB(t)
+	ctx := context.Background()
+	repo := db.NewRepository(conn)
+	if _, err := repo.CreateGame(ctx, newGame("Game", `C:\game.exe`)); err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+
+	if err := db.RollbackMigrations(conn, "0006_update_game_schema.sql"); err != nil {
+		t.Fatalf("RollbackMigrations: %v", err)
+	}
+	version, err := repo.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+	if version.Current != "0006_update_game_schema.sql" || len(version.Pending) == 0 {
+		t.Fatalf("expected rollback to 0006, got %#v", version)
+	}
+	var count int
+	if err := conn.QueryRow(`SELECT COUNT(1) FROM "Game"`).Scan(&count); err != nil || count != 1 {
+		t.Fatalf("expected game row to survive rollback, count=%d err=%v", count, err)
+	}
+
+	if err := db.ApplyMigrations(conn); err != nil {
+		t.Fatalf("reapply: %v", err)
+	}
+	games, err := repo.ListGames(ctx, "", "", "title", "asc")
+	if err != nil || len(games) != 1 {
+		t.Fatalf("expected game after reapply, got %v err=%v", games, err)
+	}
+}
+
+func TestRollbackMigrationsRefusesIrreversible(t *testing.T) {
+	t.Parallel()
+
+	conn := openMigratedDB(t)
+	err := db.RollbackMigrations(conn, "0004_remove_upload.sql")
+	if err == nil || !strings.Contains(err.Error(), "0005_rename_chapter_to_route.sql") {
+		t.Fatalf("expected irreversible error, got %v", err)
+	}
+	version, _ := db.NewRepository(conn).SchemaVersion(context.Background())
+	if len(version.Pending) != 0 {
+		t.Fatalf("expected nothing rolled back, got %#v", version)
+	}
+}
+
+func TestApplyMigrationsRefusesNewerSchema(t *testing.T) {
+	t.Parallel()
+
+	conn := openMigratedDB(t)
+	if _, err := conn.Exec(`CREATE TABLE "Future" (id TEXT)`); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := conn.Exec(`INSERT INTO schema_migrations (id, down_sql) VALUES ('9999_future.sql', 'DROP TABLE "Future";')`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	err := db.ApplyMigrations(conn)
+	var tooNew *db.SchemaTooNewError
+	if !errors.As(err, &tooNew) {
+		t.Fatalf("expected SchemaTooNewError, got %v", err)
+	}
+	if len(tooNew.Unknown) != 1 || tooNew.Unknown[0] != "9999_future.sql" || !tooNew.Downgradable {
+		t.Fatalf("unexpected error detail: %#v", tooNew)
+	}
+
+	if err := db.RollbackUnknownMigrations(conn); err != nil {
+		t.Fatalf("RollbackUnknownMigrations: %v", err)
+	}
+	if err := db.ApplyMigrations(conn); err != nil {
+		t.Fatalf("expected migrations to apply after downgrade, got %v", err)
+	}
+	var count int
+	if err := conn.QueryRow(`SELECT COUNT(1) FROM sqlite_master WHERE name = 'Future'`).Scan(&count); err != nil || count != 0 {
+		t.Fatalf("expected Future table to be dropped, count=%d err=%v", count, err)
+	}
+}
+
+func TestApplyMigrationsNewerSchemaWithoutDownScript(t *testing.T) {
+	t.Parallel()
+
+	conn := openMigratedDB(t)
+	if _, err := conn.Exec(`INSERT INTO schema_migrations (id) VALUES ('9999_future.sql')`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	var tooNew *db.SchemaTooNewError
+	if err := db.ApplyMigrations(conn); !errors.As(err, &tooNew) || tooNew.Downgradable {
+		t.Fatalf("expected non-downgradable SchemaTooNewError, got %v", err)
+	}
+	if err := db.RollbackUnknownMigrations(conn); err == nil {
+		t.Fatal("expected rollback to be refused")
+	}
+}
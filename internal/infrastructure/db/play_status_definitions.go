@@ -0,0 +1,98 @@
+// 利用者が追加したプレイ状況（PlayStatusDefinition）の永続化を提供する。
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const playStatusDefinitionSelectCols = `slug, label, color, sortOrder, createdAt, updatedAt`
+
+// ListPlayStatusDefinitions はカスタムステータスを表示順で取得する（組み込みのプレイ状況は含まない）。
+func (repository *Repository) ListPlayStatusDefinitions(ctx context.Context) ([]domain.PlayStatusDefinition, error) {
+	return queryAll(ctx, repository.connection,
+		`SELECT `+playStatusDefinitionSelectCols+` FROM "PlayStatusDefinition" ORDER BY sortOrder ASC, slug`,
+		scanPlayStatusDefinition)
+}
+
+// GetPlayStatusDefinition は slug でカスタムステータスを取得する。
+func (repository *Repository) GetPlayStatusDefinition(ctx context.Context, slug string) (*domain.PlayStatusDefinition, error) {
+	row := repository.connection.QueryRowContext(ctx,
+		`SELECT `+playStatusDefinitionSelectCols+` FROM "PlayStatusDefinition" WHERE slug = ?`, slug)
+	definition, err := scanPlayStatusDefinition(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// CreatePlayStatusDefinition はカスタムステータスを末尾に追加して返す。
+func (repository *Repository) CreatePlayStatusDefinition(ctx context.Context, definition domain.PlayStatusDefinition) (*domain.PlayStatusDefinition, error) {
+	if _, err := repository.connection.ExecContext(ctx, `
+		INSERT INTO "PlayStatusDefinition" (slug, label, color, sortOrder)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(sortOrder) + 1, 0) FROM "PlayStatusDefinition"))
+	`, definition.Slug, definition.Label, definition.Color); err != nil {
+		return nil, err
+	}
+	return repository.GetPlayStatusDefinition(ctx, definition.Slug)
+}
+
+// UpdatePlayStatusDefinition はカスタムステータスの表示名・色・表示順を更新して返す。
+func (repository *Repository) UpdatePlayStatusDefinition(ctx context.Context, definition domain.PlayStatusDefinition) (*domain.PlayStatusDefinition, error) {
+	if _, err := repository.connection.ExecContext(ctx, `
+		UPDATE "PlayStatusDefinition" SET label = ?, color = ?, sortOrder = ? WHERE slug = ?
+	`, definition.Label, definition.Color, definition.SortOrder, definition.Slug); err != nil {
+		return nil, err
+	}
+	return repository.GetPlayStatusDefinition(ctx, definition.Slug)
+}
+
+// DeletePlayStatusDefinition はカスタムステータスを削除し、そのステータスだったゲームの ID を返す。
+// 該当ゲームの customStatus は未設定に戻し、同期で他の端末にも反映されるよう updatedAt を進める。
+func (repository *Repository) DeletePlayStatusDefinition(ctx context.Context, slug string) ([]string, error) {
+	var gameIDs []string
+	err := repository.WithTx(ctx, func(tx *Repository) error {
+		ids, err := queryAll(ctx, tx.connection, `SELECT id FROM "Game" WHERE customStatus = ? ORDER BY id`, scanGameID, slug)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			before := tx.snapshotGame(ctx, id)
+			if _, err := tx.connection.ExecContext(ctx, `
+				UPDATE "Game" SET customStatus = NULL, updatedAt = CURRENT_TIMESTAMP WHERE id = ?
+			`, id); err != nil {
+				return err
+			}
+			tx.recordGameChange(ctx, id, "DeletePlayStatusDefinition", before)
+			gameIDs = append(gameIDs, id)
+		}
+		_, err = tx.connection.ExecContext(ctx, `DELETE FROM "PlayStatusDefinition" WHERE slug = ?`, slug)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gameIDs, nil
+}
+
+func scanPlayStatusDefinition(row scanner) (*domain.PlayStatusDefinition, error) {
+	definition := domain.PlayStatusDefinition{}
+	if err := row.Scan(&definition.Slug, &definition.Label, &definition.Color, &definition.SortOrder,
+		&definition.CreatedAt, &definition.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func scanGameID(row scanner) (*string, error) {
+	var id string
+	if err := row.Scan(&id); err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,65 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRepositoryPlayStatusDefinitionsSeedAndFilterGames(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	seeded, err := repo.ListPlayStatusDefinitions(ctx)
+	if err != nil {
+		t.Fatalf("ListPlayStatusDefinitions: %v", err)
+	}
+	if len(seeded) != 2 || seeded[0].Slug != "wishlist" || seeded[1].Slug != "backlog" {
+		t.Fatalf("unexpected seeded definitions: %+v", seeded)
+	}
+	created, err := repo.CreatePlayStatusDefinition(ctx, domain.PlayStatusDefinition{Slug: "dropped", Label: "中断", Color: "#ff0000"})
+	if err != nil {
+		t.Fatalf("CreatePlayStatusDefinition: %v", err)
+	}
+	if created.SortOrder != 2 {
+		t.Fatalf("expected definition to be appended, got sortOrder %d", created.SortOrder)
+	}
+
+	slug := "dropped"
+	tagged := newGame("Tagged", "/tagged.exe")
+	tagged.CustomStatus = &slug
+	taggedGame, err := repo.CreateGame(ctx, tagged)
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if _, err := repo.CreateGame(ctx, newGame("Plain", "/plain.exe")); err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	filtered, err := repo.ListGames(ctx, "", domain.CustomStatusFilter(slug), "title", "asc")
+	if err != nil {
+		t.Fatalf("ListGames: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].ID != taggedGame.ID || filtered[0].CustomStatus == nil || *filtered[0].CustomStatus != slug {
+		t.Fatalf("unexpected filtered games: %+v", filtered)
+	}
+
+	cleared, err := repo.DeletePlayStatusDefinition(ctx, slug)
+	if err != nil {
+		t.Fatalf("DeletePlayStatusDefinition: %v", err)
+	}
+	if len(cleared) != 1 || cleared[0] != taggedGame.ID {
+		t.Fatalf("expected tagged game to be cleared, got %v", cleared)
+	}
+	after, err := repo.GetGameByID(ctx, taggedGame.ID)
+	if err != nil {
+		t.Fatalf("GetGameByID: %v", err)
+	}
+	if after.CustomStatus != nil {
+		t.Fatalf("expected custom status to be cleared, got %v", *after.CustomStatus)
+	}
+	if definition, err := repo.GetPlayStatusDefinition(ctx, slug); err != nil || definition != nil {
+		t.Fatalf("expected definition to be deleted, got %+v (%v)", definition, err)
+	}
+}
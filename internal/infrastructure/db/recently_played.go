@@ -0,0 +1,82 @@
+// ホーム画面の「続きから遊ぶ」向けに、最近遊んだゲームを最後のセッション・現在のルートと併せて取得する。
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// ListRecentlyPlayed は最終プレイ日時の新しい順に、アーカイブされていないゲームを limit 件取得する。
+// 最後のセッション（playedAt が最新のもの）とルート名・現在のルート名も同じクエリで解決し、
+// 画面側がゲームごとにセッションやルートを問い合わせずに済むようにする。
+func (repository *Repository) ListRecentlyPlayed(ctx context.Context, limit int) ([]domain.RecentlyPlayedGame, error) {
+	return queryAll(ctx, repository.connection, `
+		SELECT g.*,
+		       ps.id, ps.playedAt, ps.duration, ps.sessionName, sr.id, sr.name,
+		       cr.id, cr.name
+		FROM (
+			SELECT `+gameSelectCols+` FROM "Game"
+			WHERE lastPlayed IS NOT NULL AND archivedAt IS NULL
+			ORDER BY lastPlayed DESC, id
+			LIMIT ?
+		) g
+		LEFT JOIN "PlaySession" ps ON ps.id = (
+			SELECT id FROM "PlaySession" WHERE gameId = g.id ORDER BY playedAt DESC, id DESC LIMIT 1
+		)
+		LEFT JOIN "Route" sr ON sr.id = ps.routeId
+		LEFT JOIN "Route" cr ON cr.id = g.currentRouteId
+		ORDER BY g.lastPlayed DESC, g.id
+	`, scanRecentlyPlayed, limit)
+}
+
+// extendedScanner は scanner が読み取る列の後ろに続く列を extra に読み取る。
+type extendedScanner struct {
+	row   scanner
+	extra []any
+}
+
+func (s extendedScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
+
+func scanRecentlyPlayed(row scanner) (*domain.RecentlyPlayedGame, error) {
+	var (
+		sessionID        sql.NullString
+		sessionPlayedAt  sql.NullTime
+		sessionDuration  sql.NullInt64
+		sessionName      sql.NullString
+		sessionRouteID   sql.NullString
+		sessionRouteName sql.NullString
+		currentRouteID   sql.NullString
+		currentRouteName sql.NullString
+	)
+	game, err := scanGame(extendedScanner{row: row, extra: []any{
+		&sessionID, &sessionPlayedAt, &sessionDuration, &sessionName, &sessionRouteID, &sessionRouteName,
+		&currentRouteID, &currentRouteName,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	recent := domain.RecentlyPlayedGame{Game: game.Summary()}
+	if sessionID.Valid {
+		recent.LastSession = &domain.LastSessionSummary{
+			ID:          sessionID.String,
+			PlayedAt:    sessionPlayedAt.Time,
+			Duration:    sessionDuration.Int64,
+			SessionName: nullStringPtr(sessionName),
+			Route:       routeRef(sessionRouteID, sessionRouteName),
+		}
+	}
+	recent.CurrentRoute = routeRef(currentRouteID, currentRouteName)
+	return &recent, nil
+}
+
+func routeRef(id, name sql.NullString) *domain.RouteRef {
+	if !id.Valid {
+		return nil
+	}
+	return &domain.RouteRef{ID: id.String, Name: name.String}
+}
@@ -0,0 +1,78 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRepositoryListRecentlyPlayedIncludesLastSessionAndRoute(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	older, err := repo.CreateGame(ctx, newGame("Older", "/older.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	newer, err := repo.CreateGame(ctx, newGame("Newer", "/newer.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if _, err := repo.CreateGame(ctx, newGame("Never", "/never.exe")); err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	route, err := repo.CreateRoute(ctx, domain.Route{Name: "ヒロインA", Order: 5, GameID: newer.ID})
+	if err != nil {
+		t.Fatalf("CreateRoute: %v", err)
+	}
+	newer.CurrentRouteID = &route.ID
+	newerLastPlayed := base.Add(2 * time.Hour)
+	newer.LastPlayed = &newerLastPlayed
+	if _, err := repo.UpdateGame(ctx, *newer); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	olderLastPlayed := base
+	older.LastPlayed = &olderLastPlayed
+	if _, err := repo.UpdateGame(ctx, *older); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	for i, playedAt := range []time.Time{base, base.Add(2 * time.Hour)} {
+		session := domain.PlaySession{GameID: newer.ID, PlayedAt: playedAt, Duration: int64(600 * (i + 1))}
+		if i == 1 {
+			session.RouteID = &route.ID
+		}
+		if _, err := repo.CreatePlaySession(ctx, session); err != nil {
+			t.Fatalf("CreatePlaySession: %v", err)
+		}
+	}
+
+	recent, err := repo.ListRecentlyPlayed(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListRecentlyPlayed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Game.ID != newer.ID || recent[1].Game.ID != older.ID {
+		t.Fatalf("unexpected recently played order: %+v", recent)
+	}
+	last := recent[0].LastSession
+	if last == nil || last.Duration != 1200 || last.Route == nil || last.Route.Name != "ヒロインA" {
+		t.Fatalf("unexpected last session: %+v", last)
+	}
+	if recent[0].CurrentRoute == nil || recent[0].CurrentRoute.ID != route.ID {
+		t.Fatalf("unexpected current route: %+v", recent[0].CurrentRoute)
+	}
+	if recent[1].LastSession != nil || recent[1].CurrentRoute != nil {
+		t.Fatalf("expected game without sessions to have no summary, got %+v", recent[1])
+	}
+
+	limited, err := repo.ListRecentlyPlayed(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListRecentlyPlayed: %v", err)
+	}
+	if len(limited) != 1 || limited[0].Game.ID != newer.ID {
+		t.Fatalf("expected limit to apply, got %+v", limited)
+	}
+}
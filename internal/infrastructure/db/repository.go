@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -12,22 +13,33 @@ import (
 )
 
 // Repository は主要テーブルへのCRUDを提供する。
+// WithTx から渡される Repository は connection がトランザクションで、db は nil になる。
 type Repository struct {
-	connection *sql.DB
+	connection dbConn
+	db         *sql.DB
+	hooks      *changeHooks
 }
 
 // NewRepository は Repository を初期化する。
 func NewRepository(connection *sql.DB) *Repository {
-	return &Repository{connection: connection}
+	hooks := &changeHooks{}
+	return &Repository{
+		connection: hookedConn{dbConn: connection, onGameWrite: hooks.notifyGamesChanged},
+		db:         connection,
+		hooks:      hooks,
+	}
 }
 
 // 同じカラム並びで SELECT する箇所をまとめ、列追加時の更新漏れを防ぐ。
 const (
 	gameSelectCols = `id, title, publisher, imagePath, exePath, saveFolderPath, createdAt, updatedAt,
 		       localSaveHash, localSaveHashUpdatedAt, localSyncHead,
-		       totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId`
-	routeSelectCols       = `id, name, "order", gameId, createdAt`
-	playSessionSelectCols = `id, gameId, playedAt, duration, sessionName, routeId, updatedAt`
+		       totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId, archivedAt,
+		       launchWrapperPath, launchWrapperArgs, trackingMode, autoTrackingExcluded, processMatchPattern,
+		       preLaunchCommand, postExitCommand, description, releaseDate, rating, genres, customStatus, isFavorite,
+		       saveIncludePatterns, saveExcludePatterns`
+	routeSelectCols       = `id, name, "order", gameId, createdAt, estimatedTime, completedAt`
+	playSessionSelectCols = `id, gameId, playedAt, duration, sessionName, routeId, updatedAt, partial, notes, idleDuration, cpuPeakPercent, cpuAveragePercent, memoryPeakBytes, memoryAverageBytes`
 	memoSelectCols        = `id, title, content, gameId, createdAt, updatedAt`
 )
 
@@ -35,7 +47,7 @@ const (
 // scan は1行ぶんを domain 型に変換する関数。
 func queryAll[T any](
 	ctx context.Context,
-	conn *sql.DB,
+	conn dbConn,
 	query string,
 	scan func(scanner) (*T, error),
 	args ...any,
@@ -102,71 +114,145 @@ func (repository *Repository) ListGames(
 	sortBy string,
 	sortDirection string,
 ) ([]domain.Game, error) {
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString(`SELECT ` + gameSelectCols + ` FROM "Game"`)
+	where, args := gameListWhere(searchText, filter)
+	query := `SELECT ` + gameSelectCols + ` FROM "Game"` + where + gameListOrder(sortBy, sortDirection)
+	return queryAll(ctx, repository.connection, query, scanGame, args...)
+}
 
+// ListGamesPage は ListGames と同じ条件で1ページ分のゲームと、条件に一致する全件数を取得する。
+// ページ境界で並びが揺れないよう、ソート列が同値のときは id で順序を固定する。
+func (repository *Repository) ListGamesPage(
+	ctx context.Context,
+	searchText string,
+	filter domain.PlayStatus,
+	sortBy string,
+	sortDirection string,
+	page domain.PageRequest,
+) ([]domain.Game, int, error) {
+	page = page.Normalize()
+	where, args := gameListWhere(searchText, filter)
+
+	var games []domain.Game
+	var total int
+	// 全件数とページを同じスナップショットから取り、間に追加・削除があっても食い違わないようにする。
+	error := repository.WithReadTx(ctx, func(tx *Repository) error {
+		if error := tx.connection.QueryRowContext(ctx, `SELECT COUNT(*) FROM "Game"`+where, args...).Scan(&total); error != nil {
+			return error
+		}
+		query := `SELECT ` + gameSelectCols + ` FROM "Game"` + where + gameListOrder(sortBy, sortDirection) + `, id LIMIT ? OFFSET ?`
+		list, error := queryAll(ctx, tx.connection, query, scanGame, append(args, page.Limit, page.Offset)...)
+		games = list
+		return error
+	})
+	if error != nil {
+		return nil, 0, error
+	}
+	return games, total, nil
+}
+
+// gameListWhere はゲーム一覧の検索・フィルタ条件を WHERE 句（条件が無ければ空文字）と引数にする。
+func gameListWhere(searchText string, filter domain.PlayStatus) (string, []any) {
 	whereClauses := make([]string, 0, 2)
 	args := make([]any, 0, 2)
 	if searchText != "" {
-		whereClauses = append(whereClauses, "(title LIKE ? OR publisher LIKE ?)")
+		whereClauses = append(whereClauses, "(title LIKE ? OR publisher LIKE ? OR genres LIKE ?)")
 		pattern := fmt.Sprintf("%%%s%%", searchText)
-		args = append(args, pattern, pattern)
+		args = append(args, pattern, pattern, pattern)
 	}
 	switch filter {
 	case domain.PlayStatusPlayed, domain.PlayStatusPlaying, domain.PlayStatusUnplayed:
 		whereClauses = append(whereClauses, "playStatus = ?")
 		args = append(args, string(filter))
+	case domain.GameFilterArchived:
+		whereClauses = append(whereClauses, "archivedAt IS NOT NULL")
+	case domain.GameFilterFavorite:
+		whereClauses = append(whereClauses, "isFavorite = 1")
+	default:
+		if slug, ok := filter.CustomStatusSlug(); ok {
+			whereClauses = append(whereClauses, "customStatus = ?")
+			args = append(args, slug)
+		}
+	}
+	// アーカイブ済みは既定の一覧から外すが、検索すれば見つかるようにする。
+	if searchText == "" && filter != domain.GameFilterArchived && filter != domain.GameFilterAll {
+		whereClauses = append(whereClauses, "archivedAt IS NULL")
 	}
-	if len(whereClauses) > 0 {
-		queryBuilder.WriteString(" WHERE ")
-		queryBuilder.WriteString(strings.Join(whereClauses, " AND "))
+	if len(whereClauses) == 0 {
+		return "", args
 	}
+	return " WHERE " + strings.Join(whereClauses, " AND "), args
+}
 
-	_, _ = fmt.Fprintf(&queryBuilder, " ORDER BY %s %s", normalizeSortColumn(sortBy), normalizeSortDirection(sortDirection))
-	return queryAll(ctx, repository.connection, queryBuilder.String(), scanGame, args...)
+// gameListOrder はゲーム一覧の ORDER BY 句を返す。お気に入りはソート条件によらず先頭に並べる。
+func gameListOrder(sortBy string, sortDirection string) string {
+	return fmt.Sprintf(" ORDER BY isFavorite DESC, %s %s", normalizeSortColumn(sortBy), normalizeSortDirection(sortDirection))
 }
 
 // CreateGame はゲームを作成して返す。
 func (repository *Repository) CreateGame(ctx context.Context, game domain.Game) (*domain.Game, error) {
-	_, error := repository.connection.ExecContext(ctx, `
+	var id string
+	error := repository.connection.QueryRowContext(ctx, `
 		INSERT INTO "Game" (title, publisher, imagePath, exePath, saveFolderPath, localSaveHash, localSaveHashUpdatedAt,
-			totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId,
+			description, releaseDate, rating, genres, customStatus, isFavorite, saveIncludePatterns, saveExcludePatterns)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
 	`, game.Title, game.Publisher, game.ImagePath, game.ExePath, game.SaveFolderPath,
 		game.LocalSaveHash, game.LocalSaveHashUpdatedAt,
-		game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID)
+		game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID,
+		game.Description, game.ReleaseDate, game.Rating, encodeGenres(game.Genres), game.CustomStatus, game.IsFavorite,
+		encodeStringList(game.SaveIncludePatterns), encodeStringList(game.SaveExcludePatterns)).Scan(&id)
 	if error != nil {
 		return nil, error
 	}
 
-	return repository.findLatestGame(ctx, game.Title, game.ExePath)
+	created, error := repository.GetGameByID(ctx, id)
+	if error != nil || created == nil {
+		return created, error
+	}
+	recordChange(ctx, repository, domain.ChangeEntityGame, created.ID, "CreateGame", nil, created)
+	return created, nil
 }
 
 // UpdateGame はゲームを更新して返す。
 func (repository *Repository) UpdateGame(ctx context.Context, game domain.Game) (*domain.Game, error) {
+	before := repository.snapshotGame(ctx, game.ID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "Game" SET title = ?, publisher = ?, imagePath = ?, exePath = ?, saveFolderPath = ?,
 			localSaveHash = ?, localSaveHashUpdatedAt = ?,
-			totalPlayTime = ?, lastPlayed = ?, clearedAt = ?, playStatus = ?, currentRouteId = ?
+			totalPlayTime = ?, lastPlayed = ?, clearedAt = ?, playStatus = ?, currentRouteId = ?,
+			preLaunchCommand = ?, postExitCommand = ?,
+			description = ?, releaseDate = ?, rating = ?, genres = ?, customStatus = ?, isFavorite = ?,
+			saveIncludePatterns = ?, saveExcludePatterns = ?
 		WHERE id = ?
 	`, game.Title, game.Publisher, game.ImagePath, game.ExePath, game.SaveFolderPath,
 		game.LocalSaveHash, game.LocalSaveHashUpdatedAt,
-		game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID, game.ID)
+		game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID,
+		game.PreLaunchCommand, game.PostExitCommand,
+		game.Description, game.ReleaseDate, game.Rating, encodeGenres(game.Genres), game.CustomStatus, game.IsFavorite,
+		encodeStringList(game.SaveIncludePatterns), encodeStringList(game.SaveExcludePatterns), game.ID)
 	if error != nil {
 		return nil, error
 	}
 
-	return repository.GetGameByID(ctx, game.ID)
+	updated, error := repository.GetGameByID(ctx, game.ID)
+	if error != nil {
+		return nil, error
+	}
+	recordChange(ctx, repository, domain.ChangeEntityGame, game.ID, "UpdateGame", before, updated)
+	return updated, nil
 }
 
 // UpsertGameSync はID指定でゲームを追加/更新する。
 func (repository *Repository) UpsertGameSync(ctx context.Context, game domain.Game) error {
+	before := repository.snapshotGame(ctx, game.ID)
 	_, error := repository.connection.ExecContext(ctx, `
 		INSERT INTO "Game" (
 			id, title, publisher, imagePath, exePath, saveFolderPath, createdAt, updatedAt,
 			localSaveHash, localSaveHashUpdatedAt,
-			totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId,
+			description, releaseDate, rating, genres, customStatus, isFavorite, saveIncludePatterns, saveExcludePatterns
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			title = excluded.title,
 			publisher = excluded.publisher,
@@ -181,38 +267,143 @@ func (repository *Repository) UpsertGameSync(ctx context.Context, game domain.Ga
 			lastPlayed = excluded.lastPlayed,
 			clearedAt = excluded.clearedAt,
 			playStatus = excluded.playStatus,
-			currentRouteId = excluded.currentRouteId
+			currentRouteId = excluded.currentRouteId,
+			description = excluded.description,
+			releaseDate = excluded.releaseDate,
+			rating = excluded.rating,
+			genres = excluded.genres,
+			customStatus = excluded.customStatus,
+			isFavorite = excluded.isFavorite,
+			saveIncludePatterns = excluded.saveIncludePatterns,
+			saveExcludePatterns = excluded.saveExcludePatterns
 	`, game.ID, game.Title, game.Publisher, game.ImagePath, game.ExePath, game.SaveFolderPath,
 		game.CreatedAt, game.UpdatedAt, game.LocalSaveHash, game.LocalSaveHashUpdatedAt,
-		game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID)
-	return error
+		game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID,
+		game.Description, game.ReleaseDate, game.Rating, encodeGenres(game.Genres), game.CustomStatus, game.IsFavorite,
+		encodeStringList(game.SaveIncludePatterns), encodeStringList(game.SaveExcludePatterns))
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, game.ID, "UpsertGameSync", before)
+	return nil
 }
 
 // TouchGameUpdatedAt はゲームのupdatedAtを現在時刻に更新する。
 func (repository *Repository) TouchGameUpdatedAt(ctx context.Context, gameID string) error {
+	before := repository.snapshotGame(ctx, gameID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "Game" SET updatedAt = CURRENT_TIMESTAMP WHERE id = ?
 	`, gameID)
-	return error
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "TouchGameUpdatedAt", before)
+	return nil
+}
+
+// SetGameArchived はゲームのアーカイブ日時を設定する。nil でアーカイブを解除する。
+// アーカイブは端末ローカルの状態で同期対象外のため、updatedAt は更新しない（不要な Push を避ける）。
+func (repository *Repository) SetGameArchived(ctx context.Context, gameID string, archivedAt *time.Time) error {
+	before := repository.snapshotGame(ctx, gameID)
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "Game" SET archivedAt = ? WHERE id = ?
+	`, archivedAt, gameID)
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "SetGameArchived", before)
+	return nil
+}
+
+// SetGameLaunchWrapper はゲームの起動ラッパー設定を保存する。nil で解除する。
+// ラッパーのパスは端末ごとに異なり同期対象外のため、SetGameArchived と同様に updatedAt は更新しない。
+func (repository *Repository) SetGameLaunchWrapper(ctx context.Context, gameID string, wrapper *domain.LaunchWrapper) error {
+	before := repository.snapshotGame(ctx, gameID)
+	var path, args any
+	if wrapper != nil {
+		path, args = wrapper.Path, wrapper.Args
+	}
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "Game" SET launchWrapperPath = ?, launchWrapperArgs = ? WHERE id = ?
+	`, path, args, gameID)
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "SetGameLaunchWrapper", before)
+	return nil
+}
+
+// SetGameTrackingMode はゲームのプレイ時間の数え方を保存する。
+// 端末ローカルの設定のため、SetGameLaunchWrapper と同様に updatedAt は更新しない。
+func (repository *Repository) SetGameTrackingMode(ctx context.Context, gameID string, mode domain.TrackingMode) error {
+	before := repository.snapshotGame(ctx, gameID)
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "Game" SET trackingMode = ? WHERE id = ?
+	`, mode, gameID)
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "SetGameTrackingMode", before)
+	return nil
+}
+
+// SetGameAutoTrackingExcluded はゲームを自動計測から外すかを保存する。
+// 端末ローカルの設定のため、SetGameTrackingMode と同様に updatedAt は更新しない。
+func (repository *Repository) SetGameAutoTrackingExcluded(ctx context.Context, gameID string, excluded bool) error {
+	before := repository.snapshotGame(ctx, gameID)
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "Game" SET autoTrackingExcluded = ? WHERE id = ?
+	`, excluded, gameID)
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "SetGameAutoTrackingExcluded", before)
+	return nil
+}
+
+// SetGameProcessMatchPattern はゲームのプロセスのコマンドラインの一致条件を保存する。nil で解除する。
+// 端末ローカルの設定のため、SetGameLaunchWrapper と同様に updatedAt は更新しない。
+func (repository *Repository) SetGameProcessMatchPattern(ctx context.Context, gameID string, pattern *string) error {
+	before := repository.snapshotGame(ctx, gameID)
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "Game" SET processMatchPattern = ? WHERE id = ?
+	`, pattern, gameID)
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "SetGameProcessMatchPattern", before)
+	return nil
 }
 
 // DeleteGame はゲームを削除する。
 func (repository *Repository) DeleteGame(ctx context.Context, gameID string) error {
+	before := repository.snapshotGame(ctx, gameID)
 	_, error := repository.connection.ExecContext(ctx, `DELETE FROM "Game" WHERE id = ?`, gameID)
-	return error
+	if error != nil {
+		return error
+	}
+	recordChange[domain.Game](ctx, repository, domain.ChangeEntityGame, gameID, "DeleteGame", before, nil)
+	return nil
 }
 
 // CreateRoute はルートを作成して返す。
 func (repository *Repository) CreateRoute(ctx context.Context, route domain.Route) (*domain.Route, error) {
-	_, error := repository.connection.ExecContext(ctx, `
-		INSERT INTO "Route" (name, "order", gameId)
-		VALUES (?, ?, ?)
-	`, route.Name, route.Order, route.GameID)
+	var id string
+	error := repository.connection.QueryRowContext(ctx, `
+		INSERT INTO "Route" (name, "order", gameId, estimatedTime, completedAt)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`, route.Name, route.Order, route.GameID, route.EstimatedTime, route.CompletedAt).Scan(&id)
 	if error != nil {
 		return nil, error
 	}
 
-	return repository.findLatestRoute(ctx, route.GameID, route.Name)
+	created, error := repository.GetRouteByID(ctx, id)
+	if error != nil || created == nil {
+		return created, error
+	}
+	recordChange(ctx, repository, domain.ChangeEntityRoute, created.ID, "CreateRoute", nil, created)
+	return created, nil
 }
 
 // ListRoutesByGame はゲームIDでルート一覧を取得する。
@@ -224,21 +415,32 @@ func (repository *Repository) ListRoutesByGame(ctx context.Context, gameID strin
 
 // UpdateRoute はルートを更新して返す。
 func (repository *Repository) UpdateRoute(ctx context.Context, route domain.Route) (*domain.Route, error) {
+	before := repository.snapshotRoute(ctx, route.ID)
 	_, error := repository.connection.ExecContext(ctx, `
-		UPDATE "Route" SET name = ?, "order" = ? WHERE id = ?
-	`, route.Name, route.Order, route.ID)
+		UPDATE "Route" SET name = ?, "order" = ?, estimatedTime = ?, completedAt = ? WHERE id = ?
+	`, route.Name, route.Order, route.EstimatedTime, route.CompletedAt, route.ID)
+	if error != nil {
+		return nil, error
+	}
+	updated, error := repository.GetRouteByID(ctx, route.ID)
 	if error != nil {
 		return nil, error
 	}
-	return repository.GetRouteByID(ctx, route.ID)
+	recordChange(ctx, repository, domain.ChangeEntityRoute, route.ID, "UpdateRoute", before, updated)
+	return updated, nil
 }
 
 // UpdateRouteOrder はルートの順序を更新する。
 func (repository *Repository) UpdateRouteOrder(ctx context.Context, routeID string, order int64) error {
+	before := repository.snapshotRoute(ctx, routeID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "Route" SET "order" = ? WHERE id = ?
 	`, order, routeID)
-	return error
+	if error != nil {
+		return error
+	}
+	recordChange(ctx, repository, domain.ChangeEntityRoute, routeID, "UpdateRouteOrder", before, repository.snapshotRoute(ctx, routeID))
+	return nil
 }
 
 // UpdateRouteOrders は gameID 配下のルートの順序更新を単一トランザクションで実行する。
@@ -248,21 +450,20 @@ func (repository *Repository) UpdateRouteOrders(ctx context.Context, gameID stri
 	if len(items) == 0 {
 		return nil
 	}
-	tx, err := repository.connection.BeginTx(ctx, nil)
+	before, _ := repository.ListRoutesByGame(ctx, gameID)
+	err = repository.WithTx(ctx, func(tx *Repository) error {
+		for _, item := range items {
+			if _, execErr := tx.connection.ExecContext(ctx, `UPDATE "Route" SET "order" = ? WHERE id = ? AND gameId = ?`, item.Order, item.ID, gameID); execErr != nil {
+				return execErr
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-	for _, item := range items {
-		if _, execErr := tx.ExecContext(ctx, `UPDATE "Route" SET "order" = ? WHERE id = ? AND gameId = ?`, item.Order, item.ID, gameID); execErr != nil {
-			return execErr
-		}
-	}
-	return tx.Commit()
+	repository.recordRouteListChanges(ctx, gameID, "UpdateRouteOrders", before)
+	return nil
 }
 
 // GetRouteByID はルートIDでルートを取得する。
@@ -280,23 +481,36 @@ func (repository *Repository) GetRouteByID(ctx context.Context, routeID string)
 
 // DeleteRoute はルートを削除する。
 func (repository *Repository) DeleteRoute(ctx context.Context, routeID string) error {
+	before := repository.snapshotRoute(ctx, routeID)
 	_, error := repository.connection.ExecContext(ctx, `DELETE FROM "Route" WHERE id = ?`, routeID)
-	return error
+	if error != nil {
+		return error
+	}
+	recordChange[domain.Route](ctx, repository, domain.ChangeEntityRoute, routeID, "DeleteRoute", before, nil)
+	return nil
 }
 
 // CreatePlaySession はプレイセッションを作成して返す。
 func (repository *Repository) CreatePlaySession(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
 	var id string
 	error := repository.connection.QueryRowContext(ctx, `
-		INSERT INTO "PlaySession" (gameId, playedAt, duration, sessionName, routeId)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO "PlaySession" (gameId, playedAt, duration, sessionName, routeId, partial, notes, idleDuration,
+			cpuPeakPercent, cpuAveragePercent, memoryPeakBytes, memoryAverageBytes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		RETURNING id
-	`, session.GameID, session.PlayedAt, session.Duration, session.SessionName, session.RouteID).Scan(&id)
+	`, session.GameID, session.PlayedAt, session.Duration, session.SessionName, session.RouteID, session.Partial, session.Notes,
+		session.IdleDuration, session.CPUPeakPercent, session.CPUAveragePercent, session.MemoryPeakBytes,
+		session.MemoryAverageBytes).Scan(&id)
 	if error != nil {
 		return nil, error
 	}
 
-	return repository.GetPlaySessionByID(ctx, id)
+	created, error := repository.GetPlaySessionByID(ctx, id)
+	if error != nil || created == nil {
+		return created, error
+	}
+	recordChange(ctx, repository, domain.ChangeEntitySession, created.ID, "CreatePlaySession", nil, created)
+	return created, nil
 }
 
 // GetPlaySessionByID はID指定でセッションを取得する。
@@ -321,10 +535,83 @@ func (repository *Repository) ListPlaySessionsByGame(ctx context.Context, gameID
 		scanPlaySession, gameID)
 }
 
+// ListPlaySessionsByGamePage は ListPlaySessionsByGame と同じ並び順で1ページ分のセッションと全件数を取得する。
+func (repository *Repository) ListPlaySessionsByGamePage(
+	ctx context.Context,
+	gameID string,
+	page domain.PageRequest,
+) ([]domain.PlaySession, int, error) {
+	page = page.Normalize()
+	var sessions []domain.PlaySession
+	var total int
+	// プレイ中に監視がセッションを保存しても、全件数とページが食い違わないよう同じスナップショットから取る。
+	error := repository.WithReadTx(ctx, func(tx *Repository) error {
+		if error := tx.connection.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM "PlaySession" WHERE gameId = ?`, gameID).Scan(&total); error != nil {
+			return error
+		}
+		list, error := queryAll(ctx, tx.connection,
+			`SELECT `+playSessionSelectCols+` FROM "PlaySession" WHERE gameId = ? ORDER BY playedAt DESC, id LIMIT ? OFFSET ?`,
+			scanPlaySession, gameID, page.Limit, page.Offset)
+		sessions = list
+		return error
+	})
+	if error != nil {
+		return nil, 0, error
+	}
+	return sessions, total, nil
+}
+
+// ListPlayTimeBuckets は from〜to（日付の文字列比較）のセッションを1時間・ゲーム単位で合計する。
+// playedAt は driver が time.Time.String() 形式（"2006-01-02 15:04:05.999 -0700 MST"）で保存し、
+// SQLite の日付関数では解釈できないため、先頭13文字（時まで）とオフセット部分でグループ化して
+// ローカル日付への変換は呼び出し側に任せる。範囲は保存タイムゾーンの差を吸収できるよう前後に余裕を持たせて渡す。
+func (repository *Repository) ListPlayTimeBuckets(ctx context.Context, from, to time.Time) ([]domain.PlayTimeBucket, error) {
+	return queryAll(ctx, repository.connection, `
+		SELECT
+			substr(playedAt, 1, 13) AS hour,
+			CASE WHEN instr(substr(playedAt, 20), ' ') > 0
+				THEN substr(playedAt, 20 + instr(substr(playedAt, 20), ' '), 5)
+				ELSE '' END AS zone,
+			gameId,
+			SUM(duration)
+		FROM "PlaySession"
+		WHERE playedAt >= ? AND playedAt < ?
+		GROUP BY hour, zone, gameId
+		ORDER BY hour, gameId
+	`, scanPlayTimeBucket, from.Format(time.DateOnly), to.Format(time.DateOnly))
+}
+
+func scanPlayTimeBucket(row scanner) (*domain.PlayTimeBucket, error) {
+	var hour, zone string
+	var bucket domain.PlayTimeBucket
+	if err := row.Scan(&hour, &zone, &bucket.GameID, &bucket.Duration); err != nil {
+		return nil, err
+	}
+	hour = strings.Replace(hour, "T", " ", 1)
+	// オフセットの無い値（CURRENT_TIMESTAMP の既定値や ISO 形式の "Z"）は UTC とみなす。
+	location := time.UTC
+	if offset, err := time.Parse("-0700", zone); err == nil {
+		_, seconds := offset.Zone()
+		location = time.FixedZone("", seconds)
+	}
+	start, err := time.ParseInLocation("2006-01-02 15", hour, location)
+	if err != nil {
+		return nil, fmt.Errorf("playedAt の解析に失敗: %q: %w", hour, err)
+	}
+	bucket.Start = start
+	return &bucket, nil
+}
+
 // DeletePlaySession はセッションを削除する。
 func (repository *Repository) DeletePlaySession(ctx context.Context, sessionID string) error {
+	before := repository.snapshotPlaySession(ctx, sessionID)
 	_, error := repository.connection.ExecContext(ctx, `DELETE FROM "PlaySession" WHERE id = ?`, sessionID)
-	return error
+	if error != nil {
+		return error
+	}
+	recordChange[domain.PlaySession](ctx, repository, domain.ChangeEntitySession, sessionID, "DeletePlaySession", before, nil)
+	return nil
 }
 
 // ListPlaySessionsByGames は複数ゲームのセッションを一括取得し、gameID→sessions の map を返す。
@@ -359,8 +646,13 @@ func (repository *Repository) ListPlaySessionsByGames(ctx context.Context, gameI
 
 // DeletePlaySessionsByGame はゲームID配下のセッションを削除する。
 func (repository *Repository) DeletePlaySessionsByGame(ctx context.Context, gameID string) error {
+	before, _ := repository.ListPlaySessionsByGame(ctx, gameID)
 	_, error := repository.connection.ExecContext(ctx, `DELETE FROM "PlaySession" WHERE gameId = ?`, gameID)
-	return error
+	if error != nil {
+		return error
+	}
+	repository.recordSessionListChanges(ctx, gameID, "DeletePlaySessionsByGame", before)
+	return nil
 }
 
 // SumPlaySessionDurationsByGame はゲームIDのセッション合計時間を取得する。
@@ -377,10 +669,29 @@ func (repository *Repository) SumPlaySessionDurationsByGame(ctx context.Context,
 
 // UpdateGameTotalPlayTime はゲームの総プレイ時間のみ更新する。
 func (repository *Repository) UpdateGameTotalPlayTime(ctx context.Context, gameID string, totalPlayTime int64) error {
+	before := repository.snapshotGame(ctx, gameID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "Game" SET totalPlayTime = ? WHERE id = ?
 	`, totalPlayTime, gameID)
-	return error
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "UpdateGameTotalPlayTime", before)
+	return nil
+}
+
+// SetGameAggregates はセッションから再計算した総プレイ時間と最終プレイ日時で上書きする。
+// UpdateGameTotalPlayTimeWithLastPlayed と異なり、最終プレイ日時を過去へ戻すこともある（セッション削除後など）。
+func (repository *Repository) SetGameAggregates(ctx context.Context, gameID string, totalPlayTime int64, lastPlayed *time.Time) error {
+	before := repository.snapshotGame(ctx, gameID)
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "Game" SET totalPlayTime = ?, lastPlayed = ? WHERE id = ?
+	`, totalPlayTime, lastPlayed, gameID)
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "SetGameAggregates", before)
+	return nil
 }
 
 // UpdateGameTotalPlayTimeWithLastPlayed は総プレイ時間と最終プレイ日時を更新する。
@@ -390,6 +701,7 @@ func (repository *Repository) UpdateGameTotalPlayTimeWithLastPlayed(
 	totalPlayTime int64,
 	playedAt time.Time,
 ) error {
+	before := repository.snapshotGame(ctx, gameID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "Game"
 		SET totalPlayTime = ?,
@@ -399,15 +711,24 @@ func (repository *Repository) UpdateGameTotalPlayTimeWithLastPlayed(
 		    END
 		WHERE id = ?
 	`, totalPlayTime, playedAt, playedAt, gameID)
-	return error
+	if error != nil {
+		return error
+	}
+	repository.recordGameChange(ctx, gameID, "UpdateGameTotalPlayTimeWithLastPlayed", before)
+	return nil
 }
 
 // SetLocalSyncHead はゲームの localSyncHead を更新する。
 func (repository *Repository) SetLocalSyncHead(ctx context.Context, gameID, hash string) error {
+	before := repository.snapshotGame(ctx, gameID)
 	_, err := repository.connection.ExecContext(ctx, `
 		UPDATE "Game" SET localSyncHead = ? WHERE id = ?
 	`, hash, gameID)
-	return err
+	if err != nil {
+		return err
+	}
+	repository.recordGameChange(ctx, gameID, "SetLocalSyncHead", before)
+	return nil
 }
 
 // GetLocalSaveTree はゲームの localSaveTree（前回同期した SaveSnapshot JSON）を取得する。
@@ -428,10 +749,28 @@ func (repository *Repository) GetLocalSaveTree(ctx context.Context, gameID strin
 
 // SetLocalSaveTree はゲームの localSaveTree を更新する。
 func (repository *Repository) SetLocalSaveTree(ctx context.Context, gameID, tree string) error {
+	before, _ := repository.GetLocalSaveTree(ctx, gameID)
 	_, err := repository.connection.ExecContext(ctx, `
 		UPDATE "Game" SET localSaveTree = ? WHERE id = ?
 	`, tree, gameID)
-	return err
+	if err != nil {
+		return err
+	}
+	recordChange(ctx, repository, domain.ChangeEntityGame, gameID, "SetLocalSaveTree", newSaveTreeField(before), newSaveTreeField(tree))
+	return nil
+}
+
+// SetLocalSaveHash はゲームの localSaveHash（ローカルのセーブフォルダの現在のハッシュ）と計算日時を更新する。
+func (repository *Repository) SetLocalSaveHash(ctx context.Context, gameID, hash string, updatedAt time.Time) error {
+	before := repository.snapshotGame(ctx, gameID)
+	_, err := repository.connection.ExecContext(ctx, `
+		UPDATE "Game" SET localSaveHash = ?, localSaveHashUpdatedAt = ? WHERE id = ?
+	`, hash, updatedAt, gameID)
+	if err != nil {
+		return err
+	}
+	repository.recordGameChange(ctx, gameID, "SetLocalSaveHash", before)
+	return nil
 }
 
 // GetSetting は Settings テーブルから値を取得する。存在しない場合は "" を返す。
@@ -457,23 +796,37 @@ func (repository *Repository) UpsertSetting(ctx context.Context, key, value stri
 
 // UpsertPlaySessionSync はID指定でセッションを追加/更新する。
 func (repository *Repository) UpsertPlaySessionSync(ctx context.Context, session domain.PlaySession) error {
+	before := repository.snapshotPlaySession(ctx, session.ID)
 	_, error := repository.connection.ExecContext(ctx, `
-		INSERT INTO "PlaySession" (id, gameId, playedAt, duration, sessionName, routeId, updatedAt)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO "PlaySession" (id, gameId, playedAt, duration, sessionName, routeId, updatedAt, partial, notes, idleDuration,
+			cpuPeakPercent, cpuAveragePercent, memoryPeakBytes, memoryAverageBytes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			gameId = excluded.gameId,
 			playedAt = excluded.playedAt,
 			duration = excluded.duration,
 			sessionName = excluded.sessionName,
 			routeId = excluded.routeId,
-			updatedAt = excluded.updatedAt
+			updatedAt = excluded.updatedAt,
+			partial = excluded.partial,
+			notes = excluded.notes,
+			idleDuration = excluded.idleDuration,
+			cpuPeakPercent = excluded.cpuPeakPercent,
+			cpuAveragePercent = excluded.cpuAveragePercent,
+			memoryPeakBytes = excluded.memoryPeakBytes,
+			memoryAverageBytes = excluded.memoryAverageBytes
 	`, session.ID, session.GameID, session.PlayedAt, session.Duration, session.SessionName,
-		session.RouteID, session.UpdatedAt)
-	return error
+		session.RouteID, session.UpdatedAt, session.Partial, session.Notes, session.IdleDuration,
+		session.CPUPeakPercent, session.CPUAveragePercent, session.MemoryPeakBytes, session.MemoryAverageBytes)
+	if error != nil {
+		return error
+	}
+	recordChange(ctx, repository, domain.ChangeEntitySession, session.ID, "UpsertPlaySessionSync", before, repository.snapshotPlaySession(ctx, session.ID))
+	return nil
 }
 
 // routeExistsTx は tx 内で Route が存在するか確認する。存在しなければ NULL 正規化のため nil を返す。
-func routeExistsTx(ctx context.Context, tx *sql.Tx, routeID *string) (*string, error) {
+func routeExistsTx(ctx context.Context, tx dbConn, routeID *string) (*string, error) {
 	if routeID == nil || *routeID == "" {
 		return nil, nil
 	}
@@ -488,108 +841,250 @@ func routeExistsTx(ctx context.Context, tx *sql.Tx, routeID *string) (*string, e
 	return routeID, nil
 }
 
-// ApplyPullResult は Pull のローカル反映を単一トランザクションで実行する。
-// 存在しない Route 参照（currentRouteId / routeId）は NULL に正規化して FK 違反を防ぐ。
-func (repository *Repository) ApplyPullResult(
-	ctx context.Context,
-	game domain.Game,
-	sessions []domain.PlaySession,
-	syncHead, saveTree string,
-) (err error) {
-	tx, err := repository.connection.BeginTx(ctx, nil)
+// replaceRoutesTx はゲーム配下のルートを routes で置き換える。
+// 残るルートは削除せずに更新し、書き換えないセッションの routeId が FK（ON DELETE SET NULL）で外れないようにする。
+// routes に無いルートの削除で外れた currentRouteId は呼び出し側で張り直す。
+func replaceRoutesTx(ctx context.Context, tx dbConn, gameID string, routes []domain.Route) error {
+	keep := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		keep[route.ID] = struct{}{}
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM "Route" WHERE gameId = ?`, gameID)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
+	var stale []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if _, ok := keep[id]; !ok {
+			stale = append(stale, id)
 		}
-	}()
-
-	game.CurrentRouteID, err = routeExistsTx(ctx, tx, game.CurrentRouteID)
-	if err != nil {
-		return err
 	}
-
-	if _, err = tx.ExecContext(ctx, `
-		INSERT INTO "Game" (
-			id, title, publisher, imagePath, exePath, saveFolderPath, createdAt, updatedAt,
-			localSaveHash, localSaveHashUpdatedAt,
-			totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			title = excluded.title,
-			publisher = excluded.publisher,
-			imagePath = excluded.imagePath,
-			exePath = excluded.exePath,
-			saveFolderPath = excluded.saveFolderPath,
-			createdAt = excluded.createdAt,
-			updatedAt = excluded.updatedAt,
-			localSaveHash = excluded.localSaveHash,
-			localSaveHashUpdatedAt = excluded.localSaveHashUpdatedAt,
-			totalPlayTime = excluded.totalPlayTime,
-			lastPlayed = excluded.lastPlayed,
-			clearedAt = excluded.clearedAt,
-			playStatus = excluded.playStatus,
-			currentRouteId = excluded.currentRouteId
-	`, game.ID, game.Title, game.Publisher, game.ImagePath, game.ExePath, game.SaveFolderPath,
-		game.CreatedAt, game.UpdatedAt, game.LocalSaveHash, game.LocalSaveHashUpdatedAt,
-		game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID); err != nil {
+	if err := rows.Close(); err != nil {
 		return err
 	}
-
-	if _, err = tx.ExecContext(ctx, `DELETE FROM "PlaySession" WHERE gameId = ?`, game.ID); err != nil {
+	if err := rows.Err(); err != nil {
 		return err
 	}
-
-	for _, session := range sessions {
-		var routeID *string
-		routeID, err = routeExistsTx(ctx, tx, session.RouteID)
-		if err != nil {
+	for _, id := range stale {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM "Route" WHERE id = ?`, id); err != nil {
 			return err
 		}
-		if _, err = tx.ExecContext(ctx, `
-			INSERT INTO "PlaySession" (id, gameId, playedAt, duration, sessionName, routeId, updatedAt)
+	}
+	for _, route := range routes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO "Route" (id, name, "order", gameId, createdAt, estimatedTime, completedAt)
 			VALUES (?, ?, ?, ?, ?, ?, ?)
 			ON CONFLICT(id) DO UPDATE SET
+				name = excluded.name,
+				"order" = excluded."order",
 				gameId = excluded.gameId,
-				playedAt = excluded.playedAt,
-				duration = excluded.duration,
-				sessionName = excluded.sessionName,
-				routeId = excluded.routeId,
-				updatedAt = excluded.updatedAt
-		`, session.ID, game.ID, session.PlayedAt, session.Duration, session.SessionName,
-			routeID, session.UpdatedAt); err != nil {
+				createdAt = excluded.createdAt,
+				estimatedTime = excluded.estimatedTime,
+				completedAt = excluded.completedAt
+		`, route.ID, route.Name, route.Order, gameID, route.CreatedAt, route.EstimatedTime, route.CompletedAt); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	if _, err = tx.ExecContext(ctx, `UPDATE "Game" SET localSyncHead = ? WHERE id = ?`, syncHead, game.ID); err != nil {
+// ApplyPullResult は Pull のローカル反映を単一トランザクションで実行する。
+// セッションは sessions を ID で upsert し、deleteSessionIDs を削除する（それ以外のセッションは触らない）。
+// 存在しない Route 参照（currentRouteId / routeId）は NULL に正規化して FK 違反を防ぐ。
+// routes が nil の場合（routes.json を持たない commit）はローカルのルートを変更しない。
+func (repository *Repository) ApplyPullResult(
+	ctx context.Context,
+	game domain.Game,
+	sessions []domain.PlaySession,
+	deleteSessionIDs []string,
+	links []domain.GameLink,
+	routes []domain.Route,
+	syncHead, saveTree string,
+) (err error) {
+	beforeGame := repository.snapshotGame(ctx, game.ID)
+	beforeSessions, _ := repository.ListPlaySessionsByGame(ctx, game.ID)
+	beforeLinks, _ := repository.ListGameLinksByGame(ctx, game.ID)
+	beforeRoutes, _ := repository.ListRoutesByGame(ctx, game.ID)
+	desiredRouteID := game.CurrentRouteID
+	err = repository.WithTx(ctx, func(txRepository *Repository) error {
+		tx := txRepository.connection
+		var err error
+		game.CurrentRouteID, err = routeExistsTx(ctx, tx, game.CurrentRouteID)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO "Game" (
+				id, title, publisher, imagePath, exePath, saveFolderPath, createdAt, updatedAt,
+				localSaveHash, localSaveHashUpdatedAt,
+				totalPlayTime, lastPlayed, clearedAt, playStatus, currentRouteId,
+				description, releaseDate, rating, genres, customStatus, isFavorite, saveIncludePatterns, saveExcludePatterns
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				title = excluded.title,
+				publisher = excluded.publisher,
+				imagePath = excluded.imagePath,
+				exePath = excluded.exePath,
+				saveFolderPath = excluded.saveFolderPath,
+				createdAt = excluded.createdAt,
+				updatedAt = excluded.updatedAt,
+				localSaveHash = excluded.localSaveHash,
+				localSaveHashUpdatedAt = excluded.localSaveHashUpdatedAt,
+				totalPlayTime = excluded.totalPlayTime,
+				lastPlayed = excluded.lastPlayed,
+				clearedAt = excluded.clearedAt,
+				playStatus = excluded.playStatus,
+				currentRouteId = excluded.currentRouteId,
+				description = excluded.description,
+				releaseDate = excluded.releaseDate,
+				rating = excluded.rating,
+				genres = excluded.genres,
+				customStatus = excluded.customStatus,
+				isFavorite = excluded.isFavorite,
+				saveIncludePatterns = excluded.saveIncludePatterns,
+				saveExcludePatterns = excluded.saveExcludePatterns
+		`, game.ID, game.Title, game.Publisher, game.ImagePath, game.ExePath, game.SaveFolderPath,
+			game.CreatedAt, game.UpdatedAt, game.LocalSaveHash, game.LocalSaveHashUpdatedAt,
+			game.TotalPlayTime, game.LastPlayed, game.ClearedAt, game.PlayStatus, game.CurrentRouteID,
+			game.Description, game.ReleaseDate, game.Rating, encodeGenres(game.Genres), game.CustomStatus, game.IsFavorite,
+			encodeStringList(game.SaveIncludePatterns), encodeStringList(game.SaveExcludePatterns)); err != nil {
+			return err
+		}
+
+		// セッションの routeId を解決できるよう、ルートはセッションより先に置き換える。
+		if routes != nil {
+			if err = replaceRoutesTx(ctx, tx, game.ID, routes); err != nil {
+				return err
+			}
+			var currentRouteID *string
+			currentRouteID, err = routeExistsTx(ctx, tx, desiredRouteID)
+			if err != nil {
+				return err
+			}
+			if _, err = tx.ExecContext(ctx, `UPDATE "Game" SET currentRouteId = ? WHERE id = ?`, currentRouteID, game.ID); err != nil {
+				return err
+			}
+		}
+
+		for _, id := range deleteSessionIDs {
+			if _, err = tx.ExecContext(ctx, `DELETE FROM "PlaySession" WHERE id = ? AND gameId = ?`, id, game.ID); err != nil {
+				return err
+			}
+		}
+
+		for _, session := range sessions {
+			var routeID *string
+			routeID, err = routeExistsTx(ctx, tx, session.RouteID)
+			if err != nil {
+				return err
+			}
+			if _, err = tx.ExecContext(ctx, `
+				INSERT INTO "PlaySession" (id, gameId, playedAt, duration, sessionName, routeId, updatedAt, partial, notes, idleDuration,
+					cpuPeakPercent, cpuAveragePercent, memoryPeakBytes, memoryAverageBytes)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(id) DO UPDATE SET
+					gameId = excluded.gameId,
+					playedAt = excluded.playedAt,
+					duration = excluded.duration,
+					sessionName = excluded.sessionName,
+					routeId = excluded.routeId,
+					updatedAt = excluded.updatedAt,
+					partial = excluded.partial,
+					notes = excluded.notes,
+					idleDuration = excluded.idleDuration,
+					cpuPeakPercent = excluded.cpuPeakPercent,
+					cpuAveragePercent = excluded.cpuAveragePercent,
+					memoryPeakBytes = excluded.memoryPeakBytes,
+					memoryAverageBytes = excluded.memoryAverageBytes
+			`, session.ID, game.ID, session.PlayedAt, session.Duration, session.SessionName,
+				routeID, session.UpdatedAt, session.Partial, session.Notes, session.IdleDuration,
+				session.CPUPeakPercent, session.CPUAveragePercent, session.MemoryPeakBytes, session.MemoryAverageBytes); err != nil {
+				return err
+			}
+		}
+
+		if err = replaceGameLinksTx(ctx, tx, game.ID, links); err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, `UPDATE "Game" SET localSyncHead = ? WHERE id = ?`, syncHead, game.ID); err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `UPDATE "Game" SET localSaveTree = ? WHERE id = ?`, saveTree, game.ID); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
-	if _, err = tx.ExecContext(ctx, `UPDATE "Game" SET localSaveTree = ? WHERE id = ?`, saveTree, game.ID); err != nil {
-		return err
+	repository.recordGameChange(ctx, game.ID, "ApplyPullResult", beforeGame)
+	repository.recordSessionListChanges(ctx, game.ID, "ApplyPullResult", beforeSessions)
+	repository.recordGameLinkListChanges(ctx, game.ID, "ApplyPullResult", beforeLinks)
+	if routes != nil {
+		repository.recordRouteListChanges(ctx, game.ID, "ApplyPullResult", beforeRoutes)
 	}
-
-	err = tx.Commit()
-	return err
+	return nil
 }
 
 // UpdatePlaySessionRoute はセッションのルートを更新する。
 func (repository *Repository) UpdatePlaySessionRoute(ctx context.Context, sessionID string, routeID *string) error {
+	before := repository.snapshotPlaySession(ctx, sessionID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "PlaySession" SET routeId = ? WHERE id = ?
 	`, routeID, sessionID)
-	return error
+	if error != nil {
+		return error
+	}
+	recordChange(ctx, repository, domain.ChangeEntitySession, sessionID, "UpdatePlaySessionRoute", before, repository.snapshotPlaySession(ctx, sessionID))
+	return nil
+}
+
+// UpdatePlaySessionTiming はセッションの開始日時とプレイ時間（秒）を更新する。
+// 手動で補正した時間は実際の値とみなすため、partial（起動前の時間を含まない）も外す。
+func (repository *Repository) UpdatePlaySessionTiming(ctx context.Context, sessionID string, playedAt time.Time, duration int64) error {
+	before := repository.snapshotPlaySession(ctx, sessionID)
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "PlaySession" SET playedAt = ?, duration = ?, partial = 0 WHERE id = ?
+	`, playedAt, duration, sessionID)
+	if error != nil {
+		return error
+	}
+	recordChange(ctx, repository, domain.ChangeEntitySession, sessionID, "UpdatePlaySessionTiming", before, repository.snapshotPlaySession(ctx, sessionID))
+	return nil
 }
 
 // UpdatePlaySessionName はセッション名を更新する。
 // 空文字は NULL に丸めることで、フロントエンドからのクリア要求（"未設定"に戻す）を実現する。
 func (repository *Repository) UpdatePlaySessionName(ctx context.Context, sessionID string, sessionName string) error {
+	before := repository.snapshotPlaySession(ctx, sessionID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "PlaySession" SET sessionName = NULLIF(?, '') WHERE id = ?
 	`, sessionName, sessionID)
-	return error
+	if error != nil {
+		return error
+	}
+	recordChange(ctx, repository, domain.ChangeEntitySession, sessionID, "UpdatePlaySessionName", before, repository.snapshotPlaySession(ctx, sessionID))
+	return nil
+}
+
+// UpdatePlaySessionNotes はセッションのメモを更新する。空文字は NULL に丸める（未記入に戻す）。
+func (repository *Repository) UpdatePlaySessionNotes(ctx context.Context, sessionID string, notes string) error {
+	before := repository.snapshotPlaySession(ctx, sessionID)
+	_, error := repository.connection.ExecContext(ctx, `
+		UPDATE "PlaySession" SET notes = NULLIF(?, '') WHERE id = ?
+	`, notes, sessionID)
+	if error != nil {
+		return error
+	}
+	recordChange(ctx, repository, domain.ChangeEntitySession, sessionID, "UpdatePlaySessionNotes", before, repository.snapshotPlaySession(ctx, sessionID))
+	return nil
 }
 
 // CreateMemo はメモを作成して返す。
@@ -604,29 +1099,47 @@ func (repository *Repository) CreateMemo(ctx context.Context, memo domain.Memo)
 		if error != nil {
 			return nil, error
 		}
-		return repository.GetMemoByID(ctx, memo.ID)
+		created, error := repository.GetMemoByID(ctx, memo.ID)
+		if error != nil || created == nil {
+			return created, error
+		}
+		recordChange(ctx, repository, domain.ChangeEntityMemo, created.ID, "CreateMemo", nil, created)
+		return created, nil
 	}
 
-	_, error := repository.connection.ExecContext(ctx, `
+	var id string
+	error := repository.connection.QueryRowContext(ctx, `
 		INSERT INTO "Memo" (title, content, gameId)
 		VALUES (?, ?, ?)
-	`, memo.Title, memo.Content, memo.GameID)
+		RETURNING id
+	`, memo.Title, memo.Content, memo.GameID).Scan(&id)
 	if error != nil {
 		return nil, error
 	}
 
-	return repository.findLatestMemo(ctx, memo.GameID, memo.Title)
+	created, error := repository.GetMemoByID(ctx, id)
+	if error != nil || created == nil {
+		return created, error
+	}
+	recordChange(ctx, repository, domain.ChangeEntityMemo, created.ID, "CreateMemo", nil, created)
+	return created, nil
 }
 
 // UpdateMemo はメモを更新して返す。
 func (repository *Repository) UpdateMemo(ctx context.Context, memo domain.Memo) (*domain.Memo, error) {
+	before := repository.snapshotMemo(ctx, memo.ID)
 	_, error := repository.connection.ExecContext(ctx, `
 		UPDATE "Memo" SET title = ?, content = ? WHERE id = ?
 	`, memo.Title, memo.Content, memo.ID)
 	if error != nil {
 		return nil, error
 	}
-	return repository.GetMemoByID(ctx, memo.ID)
+	updated, error := repository.GetMemoByID(ctx, memo.ID)
+	if error != nil {
+		return nil, error
+	}
+	recordChange(ctx, repository, domain.ChangeEntityMemo, memo.ID, "UpdateMemo", before, updated)
+	return updated, nil
 }
 
 // GetMemoByID はメモIDでメモを取得する。
@@ -665,13 +1178,13 @@ func (repository *Repository) ListMemosByGame(ctx context.Context, gameID string
 // GetRouteStats はルートごとの統計を取得する。
 func (repository *Repository) GetRouteStats(ctx context.Context, gameID string) (stats []domain.RouteStat, err error) {
 	rows, err := repository.connection.QueryContext(ctx, `
-		SELECT r.id, r.name, r."order",
+		SELECT r.id, r.name, r."order", COALESCE(r.estimatedTime, 0), r.completedAt,
 		       COALESCE(SUM(ps.duration), 0) as total_time,
 		       COUNT(ps.id) as session_count
 		FROM "Route" r
 		LEFT JOIN "PlaySession" ps ON ps.routeId = r.id
 		WHERE r.gameId = ?
-		GROUP BY r.id, r.name, r."order"
+		GROUP BY r.id, r.name, r."order", r.estimatedTime, r.completedAt
 		ORDER BY r."order" ASC
 	`, gameID)
 	if err != nil {
@@ -686,27 +1199,34 @@ func (repository *Repository) GetRouteStats(ctx context.Context, gameID string)
 	stats = make([]domain.RouteStat, 0)
 	for rows.Next() {
 		var (
-			routeID      string
-			routeName    string
-			orderValue   int64
-			totalTime    int64
-			sessionCount int64
+			routeID       string
+			routeName     string
+			orderValue    int64
+			estimatedTime int64
+			completedAt   sql.NullTime
+			totalTime     int64
+			sessionCount  int64
 		)
-		if err := rows.Scan(&routeID, &routeName, &orderValue, &totalTime, &sessionCount); err != nil {
+		if err := rows.Scan(&routeID, &routeName, &orderValue, &estimatedTime, &completedAt, &totalTime, &sessionCount); err != nil {
 			return nil, err
 		}
 		average := float64(0)
 		if sessionCount > 0 {
 			average = float64(totalTime) / float64(sessionCount)
 		}
-		stats = append(stats, domain.RouteStat{
-			RouteID:      routeID,
-			RouteName:    routeName,
-			TotalTime:    totalTime,
-			SessionCount: sessionCount,
-			AverageTime:  average,
-			Order:        orderValue,
-		})
+		stat := domain.RouteStat{
+			RouteID:       routeID,
+			RouteName:     routeName,
+			TotalTime:     totalTime,
+			SessionCount:  sessionCount,
+			AverageTime:   average,
+			Order:         orderValue,
+			EstimatedTime: estimatedTime,
+			Completed:     completedAt.Valid,
+			CompletedAt:   nullTimePtr(completedAt),
+		}
+		applyRouteProgress(&stat)
+		stats = append(stats, stat)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -714,6 +1234,21 @@ func (repository *Repository) GetRouteStats(ctx context.Context, gameID string)
 	return stats, nil
 }
 
+// applyRouteProgress はプレイ時間・見込み時間・クリア状態からルートの達成率と残り見込み時間を求める。
+func applyRouteProgress(stat *domain.RouteStat) {
+	switch {
+	case stat.Completed:
+		stat.CompletionPercent = 100
+		stat.RemainingTime = 0
+	case stat.EstimatedTime > 0:
+		stat.CompletionPercent = min(float64(stat.TotalTime)*100/float64(stat.EstimatedTime), 100)
+		stat.RemainingTime = max(stat.EstimatedTime-stat.TotalTime, 0)
+	default:
+		stat.CompletionPercent = 0
+		stat.RemainingTime = 0
+	}
+}
+
 // ListAllMemos は全メモを取得する。
 func (repository *Repository) ListAllMemos(ctx context.Context) ([]domain.Memo, error) {
 	return queryAll(ctx, repository.connection,
@@ -723,14 +1258,19 @@ func (repository *Repository) ListAllMemos(ctx context.Context) ([]domain.Memo,
 
 // DeleteMemo はメモを削除する。
 func (repository *Repository) DeleteMemo(ctx context.Context, memoID string) error {
+	before := repository.snapshotMemo(ctx, memoID)
 	_, error := repository.connection.ExecContext(ctx, `DELETE FROM "Memo" WHERE id = ?`, memoID)
-	return error
+	if error != nil {
+		return error
+	}
+	recordChange[domain.Memo](ctx, repository, domain.ChangeEntityMemo, memoID, "DeleteMemo", before, nil)
+	return nil
 }
 
 // normalizeSortColumn は許可されたソート対象に変換する。
 func normalizeSortColumn(sortBy string) string {
 	switch sortBy {
-	case "title", "publisher", "lastPlayed", "totalPlayTime", "createdAt":
+	case "title", "publisher", "lastPlayed", "totalPlayTime", "createdAt", "releaseDate", "rating":
 		return sortBy
 	default:
 		return "title"
@@ -756,6 +1296,19 @@ func scanGame(row scanner) (*domain.Game, error) {
 		lastPlayed             sql.NullTime
 		clearedAt              sql.NullTime
 		currentRouteId         sql.NullString
+		archivedAt             sql.NullTime
+		launchWrapperPath      sql.NullString
+		launchWrapperArgs      sql.NullString
+		processMatchPattern    sql.NullString
+		preLaunchCommand       sql.NullString
+		postExitCommand        sql.NullString
+		description            sql.NullString
+		releaseDate            sql.NullString
+		rating                 sql.NullInt64
+		genres                 sql.NullString
+		customStatus           sql.NullString
+		saveIncludePatterns    sql.NullString
+		saveExcludePatterns    sql.NullString
 	)
 
 	game := domain.Game{}
@@ -776,6 +1329,22 @@ func scanGame(row scanner) (*domain.Game, error) {
 		&clearedAt,
 		&game.PlayStatus,
 		&currentRouteId,
+		&archivedAt,
+		&launchWrapperPath,
+		&launchWrapperArgs,
+		&game.TrackingMode,
+		&game.AutoTrackingExcluded,
+		&processMatchPattern,
+		&preLaunchCommand,
+		&postExitCommand,
+		&description,
+		&releaseDate,
+		&rating,
+		&genres,
+		&customStatus,
+		&game.IsFavorite,
+		&saveIncludePatterns,
+		&saveExcludePatterns,
 	)
 	if error != nil {
 		return nil, error
@@ -789,6 +1358,23 @@ func scanGame(row scanner) (*domain.Game, error) {
 	game.LastPlayed = nullTimePtr(lastPlayed)
 	game.ClearedAt = nullTimePtr(clearedAt)
 	game.CurrentRouteID = nullStringPtr(currentRouteId)
+	game.ArchivedAt = nullTimePtr(archivedAt)
+	game.ProcessMatchPattern = nullStringPtr(processMatchPattern)
+	game.PreLaunchCommand = nullStringPtr(preLaunchCommand)
+	game.PostExitCommand = nullStringPtr(postExitCommand)
+	game.Description = nullStringPtr(description)
+	game.ReleaseDate = nullStringPtr(releaseDate)
+	if rating.Valid {
+		value := int(rating.Int64)
+		game.Rating = &value
+	}
+	game.Genres = decodeGenres(genres)
+	game.CustomStatus = nullStringPtr(customStatus)
+	game.SaveIncludePatterns = decodeStringList(saveIncludePatterns)
+	game.SaveExcludePatterns = decodeStringList(saveExcludePatterns)
+	if launchWrapperPath.Valid && launchWrapperPath.String != "" {
+		game.LaunchWrapper = &domain.LaunchWrapper{Path: launchWrapperPath.String, Args: launchWrapperArgs.String}
+	}
 
 	return &game, nil
 }
@@ -796,10 +1382,19 @@ func scanGame(row scanner) (*domain.Game, error) {
 // scanRoute は1行分のルートデータを読み取る。
 func scanRoute(row scanner) (*domain.Route, error) {
 	route := domain.Route{}
-	error := row.Scan(&route.ID, &route.Name, &route.Order, &route.GameID, &route.CreatedAt)
+	var (
+		estimatedTime sql.NullInt64
+		completedAt   sql.NullTime
+	)
+	error := row.Scan(&route.ID, &route.Name, &route.Order, &route.GameID, &route.CreatedAt, &estimatedTime, &completedAt)
 	if error != nil {
 		return nil, error
 	}
+	if estimatedTime.Valid {
+		route.EstimatedTime = &estimatedTime.Int64
+	}
+	route.CompletedAt = nullTimePtr(completedAt)
+	route.Completed = route.CompletedAt != nil
 	return &route, nil
 }
 
@@ -808,6 +1403,7 @@ func scanPlaySession(row scanner) (*domain.PlaySession, error) {
 	var (
 		sessionName sql.NullString
 		routeID     sql.NullString
+		notes       sql.NullString
 	)
 
 	session := domain.PlaySession{}
@@ -819,6 +1415,13 @@ func scanPlaySession(row scanner) (*domain.PlaySession, error) {
 		&sessionName,
 		&routeID,
 		&session.UpdatedAt,
+		&session.Partial,
+		&notes,
+		&session.IdleDuration,
+		&session.CPUPeakPercent,
+		&session.CPUAveragePercent,
+		&session.MemoryPeakBytes,
+		&session.MemoryAverageBytes,
 	)
 	if error != nil {
 		return nil, error
@@ -826,6 +1429,7 @@ func scanPlaySession(row scanner) (*domain.PlaySession, error) {
 
 	session.SessionName = nullStringPtr(sessionName)
 	session.RouteID = nullStringPtr(routeID)
+	session.Notes = nullStringPtr(notes)
 
 	return &session, nil
 }
@@ -848,36 +1452,46 @@ func nullStringPtr(value sql.NullString) *string {
 	return &value.String
 }
 
-// nullTimePtr は NULL 時刻をポインタに変換する。
-func nullTimePtr(value sql.NullTime) *time.Time {
-	if !value.Valid {
-		return nil
-	}
-	return &value.Time
+// encodeGenres はジャンルを genres 列の JSON 配列にする。空なら NULL にする。
+func encodeGenres(genres []string) any {
+	return encodeStringList(genres)
 }
 
-// findLatestGame は直近作成のゲームを取得する。
-func (repository *Repository) findLatestGame(ctx context.Context, title string, exePath string) (*domain.Game, error) {
-	row := repository.connection.QueryRowContext(ctx,
-		`SELECT `+gameSelectCols+` FROM "Game" WHERE title = ? AND exePath = ? ORDER BY createdAt DESC LIMIT 1`,
-		title, exePath)
-	return scanGame(row)
+// decodeGenres は genres 列の JSON 配列を読み取る。NULL・壊れた値は未設定として扱う。
+func decodeGenres(value sql.NullString) []string {
+	return decodeStringList(value)
 }
 
-// findLatestRoute は直近作成のルートを取得する。
-func (repository *Repository) findLatestRoute(ctx context.Context, gameID string, name string) (*domain.Route, error) {
-	row := repository.connection.QueryRowContext(ctx,
-		`SELECT `+routeSelectCols+` FROM "Route" WHERE gameId = ? AND name = ? ORDER BY createdAt DESC LIMIT 1`,
-		gameID, name)
-	return scanRoute(row)
+// encodeStringList は文字列の一覧を TEXT 列の JSON 配列にする。空なら NULL にする。
+func encodeStringList(values []string) any {
+	if len(values) == 0 {
+		return nil
+	}
+	raw, error := json.Marshal(values)
+	if error != nil {
+		return nil
+	}
+	return string(raw)
+}
+
+// decodeStringList は TEXT 列の JSON 配列を読み取る。NULL・壊れた値は空として扱う。
+func decodeStringList(value sql.NullString) []string {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	var values []string
+	if error := json.Unmarshal([]byte(value.String), &values); error != nil || len(values) == 0 {
+		return nil
+	}
+	return values
 }
 
-// findLatestMemo は直近のメモを取得する。
-func (repository *Repository) findLatestMemo(ctx context.Context, gameID string, title string) (*domain.Memo, error) {
-	row := repository.connection.QueryRowContext(ctx,
-		`SELECT `+memoSelectCols+` FROM "Memo" WHERE gameId = ? AND title = ? ORDER BY createdAt DESC LIMIT 1`,
-		gameID, title)
-	return scanMemo(row)
+// nullTimePtr は NULL 時刻をポインタに変換する。
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
 }
 
 // scanner はScanだけを要求する簡易インターフェース。
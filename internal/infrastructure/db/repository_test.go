@@ -111,6 +111,168 @@ func TestRepositoryListGamesFilterByPlayStatus(t *testing.T) {
 	}
 }
 
+func TestRepositoryListGamesHidesArchivedByDefault(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	active, _ := repo.CreateGame(ctx, newGame("Active", "/a.exe"))
+	archived, _ := repo.CreateGame(ctx, newGame("Archived", "/b.exe"))
+	archivedAt := time.Now()
+	if err := repo.SetGameArchived(ctx, archived.ID, &archivedAt); err != nil {
+		t.Fatalf("SetGameArchived: %v", err)
+	}
+
+	cases := []struct {
+		name       string
+		searchText string
+		filter     domain.PlayStatus
+		wantIDs    []string
+	}{
+		{"default excludes archived", "", "", []string{active.ID}},
+		{"archived filter", "", domain.GameFilterArchived, []string{archived.ID}},
+		{"all filter", "", domain.GameFilterAll, []string{active.ID, archived.ID}},
+		{"search includes archived", "Archived", "", []string{archived.ID}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			games, err := repo.ListGames(ctx, tc.searchText, tc.filter, "title", "asc")
+			if err != nil {
+				t.Fatalf("ListGames: %v", err)
+			}
+			if len(games) != len(tc.wantIDs) {
+				t.Fatalf("got %d games, want %d", len(games), len(tc.wantIDs))
+			}
+			for i, id := range tc.wantIDs {
+				if games[i].ID != id {
+					t.Errorf("games[%d] = %q, want %q", i, games[i].ID, id)
+				}
+			}
+		})
+	}
+
+	got, err := repo.GetGameByID(ctx, archived.ID)
+	if err != nil || got == nil || !got.IsArchived() {
+		t.Fatalf("expected archivedAt to be read back, got %#v (%v)", got, err)
+	}
+}
+
+func TestRepositoryListGamesPageReturnsSliceAndTotal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	for _, title := range []string{"A", "B", "C", "D", "E"} {
+		if _, err := repo.CreateGame(ctx, newGame(title, "/"+title+".exe")); err != nil {
+			t.Fatalf("CreateGame: %v", err)
+		}
+	}
+
+	games, total, err := repo.ListGamesPage(ctx, "", domain.GameFilterAll, "title", "asc", domain.PageRequest{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("ListGamesPage: %v", err)
+	}
+	if total != 5 || len(games) != 2 || games[0].Title != "C" || games[1].Title != "D" {
+		t.Fatalf("unexpected page: total=%d games=%v", total, games)
+	}
+
+	// 全件数は検索条件を反映し、範囲外の offset は空ページになる。
+	games, total, err = repo.ListGamesPage(ctx, "E", domain.GameFilterAll, "title", "asc", domain.PageRequest{Limit: 10, Offset: 5})
+	if err != nil || total != 1 || len(games) != 0 {
+		t.Fatalf("expected empty page with total 1, got total=%d games=%v err=%v", total, games, err)
+	}
+}
+
+func TestRepositoryLaunchWrapperIsLocalAndSurvivesSyncUpsert(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+
+	wrapper := &domain.LaunchWrapper{Path: `C:\LE\LEProc.exe`, Args: `-run "{exe}"`}
+	if err := repo.SetGameLaunchWrapper(ctx, game.ID, wrapper); err != nil {
+		t.Fatalf("SetGameLaunchWrapper: %v", err)
+	}
+	synced := *game
+	synced.Title = "Renamed"
+	if err := repo.UpsertGameSync(ctx, synced); err != nil {
+		t.Fatalf("UpsertGameSync: %v", err)
+	}
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if got.LaunchWrapper == nil || *got.LaunchWrapper != *wrapper || !got.UpdatedAt.Equal(game.UpdatedAt) {
+		t.Fatalf("expected wrapper to be kept without touching updatedAt, got %+v", got)
+	}
+
+	if err := repo.SetGameLaunchWrapper(ctx, game.ID, nil); err != nil {
+		t.Fatalf("SetGameLaunchWrapper(nil): %v", err)
+	}
+	got, _ = repo.GetGameByID(ctx, game.ID)
+	if got.LaunchWrapper != nil {
+		t.Fatalf("expected wrapper to be cleared, got %+v", got.LaunchWrapper)
+	}
+}
+
+func TestRepositoryTrackingSettingsDefaultAndAreLocal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if game.TrackingMode != domain.TrackingModeProcess {
+		t.Fatalf("expected process mode by default, got %q", game.TrackingMode)
+	}
+
+	if err := repo.SetGameTrackingMode(ctx, game.ID, domain.TrackingModeForeground); err != nil {
+		t.Fatalf("SetGameTrackingMode: %v", err)
+	}
+	if err := repo.SetGameAutoTrackingExcluded(ctx, game.ID, true); err != nil {
+		t.Fatalf("SetGameAutoTrackingExcluded: %v", err)
+	}
+	pattern := "Chrono Trigger"
+	if err := repo.SetGameProcessMatchPattern(ctx, game.ID, &pattern); err != nil {
+		t.Fatalf("SetGameProcessMatchPattern: %v", err)
+	}
+	synced := *game
+	synced.Title = "Renamed"
+	if err := repo.UpsertGameSync(ctx, synced); err != nil {
+		t.Fatalf("UpsertGameSync: %v", err)
+	}
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if got.TrackingMode != domain.TrackingModeForeground || !got.AutoTrackingExcluded ||
+		got.ProcessMatchPattern == nil || *got.ProcessMatchPattern != pattern || !got.UpdatedAt.Equal(game.UpdatedAt) {
+		t.Fatalf("expected tracking settings to be kept without touching updatedAt, got %+v", got)
+	}
+}
+
+func TestRepositoryLaunchHooksAreSavedByUpdateGameAndKeptBySync(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if game.PreLaunchCommand != nil || game.PostExitCommand != nil {
+		t.Fatalf("expected no hooks by default, got %+v", game)
+	}
+
+	pre, post := "mount.bat", "sync.bat"
+	game.PreLaunchCommand, game.PostExitCommand = &pre, &post
+	if _, err := repo.UpdateGame(ctx, *game); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	synced := *game
+	synced.PreLaunchCommand, synced.PostExitCommand = nil, nil
+	if err := repo.UpsertGameSync(ctx, synced); err != nil {
+		t.Fatalf("UpsertGameSync: %v", err)
+	}
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if got.PreLaunchCommand == nil || *got.PreLaunchCommand != pre || got.PostExitCommand == nil || *got.PostExitCommand != post {
+		t.Fatalf("expected launch hooks to stay device-local, got %+v", got)
+	}
+}
+
 // --- Game CRUD ---
 
 func TestRepositoryGameCRUDRoundTrip(t *testing.T) {
@@ -144,6 +306,50 @@ func TestRepositoryGameCRUDRoundTrip(t *testing.T) {
 	}
 }
 
+func TestRepositoryCreateReturnsDistinctRowsForDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	// 同じタイトル・同じ作成時刻の行があっても、作成したその行が返ること。
+	first, err := repo.CreateGame(ctx, newGame("Same", "/same.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	second, err := repo.CreateGame(ctx, newGame("Same", "/same.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("expected distinct game ids, got %q twice", first.ID)
+	}
+
+	routeA, err := repo.CreateRoute(ctx, domain.Route{Name: "Route", Order: 1, GameID: first.ID})
+	if err != nil {
+		t.Fatalf("CreateRoute: %v", err)
+	}
+	routeB, err := repo.CreateRoute(ctx, domain.Route{Name: "Route", Order: 2, GameID: second.ID})
+	if err != nil {
+		t.Fatalf("CreateRoute: %v", err)
+	}
+	if routeA.ID == routeB.ID || routeB.GameID != second.ID {
+		t.Fatalf("expected the second route to be returned, got %#v and %#v", routeA, routeB)
+	}
+
+	memoA, err := repo.CreateMemo(ctx, domain.Memo{Title: "Memo", Content: "a", GameID: first.ID})
+	if err != nil {
+		t.Fatalf("CreateMemo: %v", err)
+	}
+	memoB, err := repo.CreateMemo(ctx, domain.Memo{Title: "Memo", Content: "b", GameID: first.ID})
+	if err != nil {
+		t.Fatalf("CreateMemo: %v", err)
+	}
+	if memoA.ID == memoB.ID || memoB.Content != "b" {
+		t.Fatalf("expected the second memo to be returned, got %#v and %#v", memoA, memoB)
+	}
+}
+
 // --- UpdateGameTotalPlayTimeWithLastPlayed ---
 
 func TestRepositoryLastPlayedOnlyAdvances(t *testing.T) {
@@ -182,6 +388,28 @@ func TestRepositoryLastPlayedOnlyAdvances(t *testing.T) {
 	}
 }
 
+func TestRepositorySetGameAggregatesCanMoveLastPlayedBack(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if err := repo.UpdateGameTotalPlayTimeWithLastPlayed(ctx, game.ID, 300, newer); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := repo.SetGameAggregates(ctx, game.ID, 120, &older); err != nil {
+		t.Fatalf("SetGameAggregates: %v", err)
+	}
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if got.TotalPlayTime != 120 || got.LastPlayed == nil || !got.LastPlayed.Equal(older) {
+		t.Fatalf("want total=120 lastPlayed=%v, got total=%d lastPlayed=%v", older, got.TotalPlayTime, got.LastPlayed)
+	}
+}
+
 // --- Session CRUD ---
 
 func TestRepositorySessionCRUD(t *testing.T) {
@@ -194,18 +422,54 @@ func TestRepositorySessionCRUD(t *testing.T) {
 	playedAt := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
 
 	session, err := repo.CreatePlaySession(ctx, domain.PlaySession{
-		GameID:   game.ID,
-		PlayedAt: playedAt,
-		Duration: 3600,
+		GameID:             game.ID,
+		PlayedAt:           playedAt,
+		Duration:           3600,
+		Partial:            true,
+		IdleDuration:       600,
+		CPUPeakPercent:     62.5,
+		CPUAveragePercent:  31.2,
+		MemoryPeakBytes:    4 << 30,
+		MemoryAverageBytes: 3 << 30,
 	})
 	if err != nil || session == nil {
 		t.Fatalf("CreatePlaySession: %v", err)
 	}
 
 	sessions, err := repo.ListPlaySessionsByGame(ctx, game.ID)
-	if err != nil || len(sessions) != 1 || sessions[0].Duration != 3600 {
+	if err != nil || len(sessions) != 1 || sessions[0].Duration != 3600 || !sessions[0].Partial || sessions[0].IdleDuration != 600 {
 		t.Fatalf("ListPlaySessionsByGame: got %v, err=%v", sessions, err)
 	}
+	if sessions[0].CPUPeakPercent != 62.5 || sessions[0].CPUAveragePercent != 31.2 ||
+		sessions[0].MemoryPeakBytes != 4<<30 || sessions[0].MemoryAverageBytes != 3<<30 {
+		t.Fatalf("expected resource usage to round-trip, got %+v", sessions[0])
+	}
+	if sessions[0].Notes != nil {
+		t.Fatalf("expected no notes on a new session, got %v", *sessions[0].Notes)
+	}
+
+	corrected := playedAt.Add(30 * time.Minute)
+	if err := repo.UpdatePlaySessionTiming(ctx, session.ID, corrected, 1800); err != nil {
+		t.Fatalf("UpdatePlaySessionTiming: %v", err)
+	}
+	timed, err := repo.GetPlaySessionByID(ctx, session.ID)
+	if err != nil || timed == nil || timed.Duration != 1800 || !timed.PlayedAt.Equal(corrected) || timed.Partial {
+		t.Fatalf("GetPlaySessionByID after timing: got %+v, err=%v", timed, err)
+	}
+
+	if err := repo.UpdatePlaySessionNotes(ctx, session.ID, "共通ルート終了\n次はヒロインA"); err != nil {
+		t.Fatalf("UpdatePlaySessionNotes: %v", err)
+	}
+	noted, err := repo.GetPlaySessionByID(ctx, session.ID)
+	if err != nil || noted == nil || noted.Notes == nil || *noted.Notes != "共通ルート終了\n次はヒロインA" {
+		t.Fatalf("GetPlaySessionByID after notes: got %v, err=%v", noted, err)
+	}
+	if err := repo.UpdatePlaySessionNotes(ctx, session.ID, ""); err != nil {
+		t.Fatalf("UpdatePlaySessionNotes(clear): %v", err)
+	}
+	if cleared, _ := repo.GetPlaySessionByID(ctx, session.ID); cleared == nil || cleared.Notes != nil {
+		t.Fatalf("expected notes to be cleared to NULL, got %v", cleared)
+	}
 
 	if err := repo.DeletePlaySession(ctx, session.ID); err != nil {
 		t.Fatalf("DeletePlaySession: %v", err)
@@ -216,6 +480,34 @@ func TestRepositorySessionCRUD(t *testing.T) {
 	}
 }
 
+func TestRepositoryListPlaySessionsByGamePageOrdersNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	other, _ := repo.CreateGame(ctx, newGame("Other", "/other.exe"))
+	base := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		if _, err := repo.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: base.Add(time.Duration(i) * time.Hour), Duration: int64(i + 1)}); err != nil {
+			t.Fatalf("CreatePlaySession: %v", err)
+		}
+	}
+	_, _ = repo.CreatePlaySession(ctx, domain.PlaySession{GameID: other.ID, PlayedAt: base, Duration: 99})
+
+	sessions, total, err := repo.ListPlaySessionsByGamePage(ctx, game.ID, domain.PageRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListPlaySessionsByGamePage: %v", err)
+	}
+	if total != 3 || len(sessions) != 2 || sessions[0].Duration != 3 || sessions[1].Duration != 2 {
+		t.Fatalf("unexpected first page: total=%d sessions=%v", total, sessions)
+	}
+	sessions, _, _ = repo.ListPlaySessionsByGamePage(ctx, game.ID, domain.PageRequest{Limit: 2, Offset: 2})
+	if len(sessions) != 1 || sessions[0].Duration != 1 {
+		t.Fatalf("unexpected second page: %v", sessions)
+	}
+}
+
 // --- ApplyPullResult ---
 
 func TestApplyPullResultNormalizesMissingRouteRefs(t *testing.T) {
@@ -235,7 +527,7 @@ func TestApplyPullResultNormalizesMissingRouteRefs(t *testing.T) {
 		{ID: "sess-1", GameID: created.ID, PlayedAt: time.Now().UTC(), Duration: 60, RouteID: &missingRoute, UpdatedAt: time.Now().UTC()},
 	}
 
-	if err := repo.ApplyPullResult(ctx, game, sessions, "head-1", "{\"files\":{}}"); err != nil {
+	if err := repo.ApplyPullResult(ctx, game, sessions, nil, nil, nil, "head-1", "{\"files\":{}}"); err != nil {
 		t.Fatalf("ApplyPullResult should not fail on missing route refs: %v", err)
 	}
 
@@ -269,7 +561,7 @@ func TestApplyPullResultPersistsHeadAndTree(t *testing.T) {
 		t.Fatalf("CreateGame: %v", err)
 	}
 
-	if err := repo.ApplyPullResult(ctx, *created, nil, "head-xyz", "{\"files\":{\"a.sav\":\"h\"}}"); err != nil {
+	if err := repo.ApplyPullResult(ctx, *created, nil, nil, nil, nil, "head-xyz", "{\"files\":{\"a.sav\":\"h\"}}"); err != nil {
 		t.Fatalf("ApplyPullResult: %v", err)
 	}
 
@@ -289,6 +581,114 @@ func TestApplyPullResultPersistsHeadAndTree(t *testing.T) {
 	}
 }
 
+func TestApplyPullResultReplacesRoutesAndResolvesRefs(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	created, err := repo.CreateGame(ctx, newGame("RouteSyncGame", "/routesync.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if _, err := repo.CreateRoute(ctx, domain.Route{Name: "ローカル専用", Order: 0, GameID: created.ID}); err != nil {
+		t.Fatalf("CreateRoute: %v", err)
+	}
+
+	remoteRouteID := "remote-route-1"
+	game := *created
+	game.CurrentRouteID = &remoteRouteID
+	routes := []domain.Route{
+		{ID: "remote-route-0", Name: "共通", Order: 0, GameID: created.ID, CreatedAt: time.Now().UTC()},
+		{ID: remoteRouteID, Name: "ヒロインA", Order: 1, GameID: created.ID, CreatedAt: time.Now().UTC()},
+	}
+	sessions := []domain.PlaySession{
+		{ID: "sess-r", GameID: created.ID, PlayedAt: time.Now().UTC(), Duration: 60, RouteID: &remoteRouteID, UpdatedAt: time.Now().UTC()},
+	}
+
+	if err := repo.ApplyPullResult(ctx, game, sessions, nil, nil, routes, "head-r", ""); err != nil {
+		t.Fatalf("ApplyPullResult: %v", err)
+	}
+
+	gotRoutes, err := repo.ListRoutesByGame(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("ListRoutesByGame: %v", err)
+	}
+	if len(gotRoutes) != 2 || gotRoutes[0].ID != "remote-route-0" || gotRoutes[1].ID != remoteRouteID {
+		t.Fatalf("expected remote routes to replace local ones, got %#v", gotRoutes)
+	}
+	got, err := repo.GetGameByID(ctx, created.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetGameByID: %v", err)
+	}
+	if got.CurrentRouteID == nil || *got.CurrentRouteID != remoteRouteID {
+		t.Fatalf("currentRouteId should point to the pulled route, got %v", got.CurrentRouteID)
+	}
+	savedSessions, err := repo.ListPlaySessionsByGame(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("ListPlaySessionsByGame: %v", err)
+	}
+	if len(savedSessions) != 1 || savedSessions[0].RouteID == nil || *savedSessions[0].RouteID != remoteRouteID {
+		t.Fatalf("session routeId should point to the pulled route, got %#v", savedSessions)
+	}
+
+	// routes が nil（routes.json を持たない commit）ならローカルのルートは維持する。
+	if err := repo.ApplyPullResult(ctx, game, sessions, nil, nil, nil, "head-legacy", ""); err != nil {
+		t.Fatalf("ApplyPullResult (legacy): %v", err)
+	}
+	gotRoutes, err = repo.ListRoutesByGame(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("ListRoutesByGame: %v", err)
+	}
+	if len(gotRoutes) != 2 {
+		t.Fatalf("expected routes to be kept for legacy commit, got %#v", gotRoutes)
+	}
+}
+
+func TestApplyPullResultAppliesSessionDiffAndKeepsRouteRefs(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	created, err := repo.CreateGame(ctx, newGame("DiffGame", "/diff.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	routeID := "route-1"
+	routes := []domain.Route{{ID: routeID, Name: "共通", Order: 0, GameID: created.ID, CreatedAt: time.Now().UTC()}}
+	now := time.Now().UTC()
+	initial := []domain.PlaySession{
+		{ID: "keep", GameID: created.ID, PlayedAt: now, Duration: 60, RouteID: &routeID, UpdatedAt: now},
+		{ID: "drop", GameID: created.ID, PlayedAt: now, Duration: 30, UpdatedAt: now},
+	}
+	if err := repo.ApplyPullResult(ctx, *created, initial, nil, nil, routes, "head-1", ""); err != nil {
+		t.Fatalf("ApplyPullResult: %v", err)
+	}
+
+	// 書き込まないセッション（keep）はそのまま残り、同じルートを受け取ってもルート参照は外れない。
+	added := []domain.PlaySession{{ID: "new", GameID: created.ID, PlayedAt: now, Duration: 90, UpdatedAt: now}}
+	if err := repo.ApplyPullResult(ctx, *created, added, []string{"drop"}, nil, routes, "head-2", ""); err != nil {
+		t.Fatalf("ApplyPullResult (diff): %v", err)
+	}
+
+	saved, err := repo.ListPlaySessionsByGame(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("ListPlaySessionsByGame: %v", err)
+	}
+	byID := make(map[string]domain.PlaySession, len(saved))
+	for _, session := range saved {
+		byID[session.ID] = session
+	}
+	if len(byID) != 2 {
+		t.Fatalf("expected keep and new sessions, got %#v", saved)
+	}
+	if _, ok := byID["drop"]; ok {
+		t.Fatal("drop session should be deleted")
+	}
+	if keep, ok := byID["keep"]; !ok || keep.RouteID == nil || *keep.RouteID != routeID {
+		t.Fatalf("untouched session should keep its route, got %#v", keep)
+	}
+}
+
 // --- Route カスケード削除 ---
 
 func TestRepositoryRoutesDeletedWithGame(t *testing.T) {
@@ -335,3 +735,187 @@ func TestOpenSetsBusyTimeout(t *testing.T) {
 		t.Fatalf("busy_timeout should be >= 5000ms, got %d", timeout)
 	}
 }
+
+func TestListPlayTimeBucketsGroupsByHourAndKeepsOffset(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	game, err := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	jst := time.FixedZone("JST", 9*60*60)
+	for _, playedAt := range []time.Time{
+		time.Date(2024, 1, 1, 23, 10, 0, 0, jst),
+		time.Date(2024, 1, 1, 23, 40, 30, 5, jst),
+		time.Date(2024, 1, 1, 14, 50, 0, 0, time.UTC),
+	} {
+		if _, err := repo.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: playedAt, Duration: 60}); err != nil {
+			t.Fatalf("CreatePlaySession: %v", err)
+		}
+	}
+
+	buckets, err := repo.ListPlayTimeBuckets(ctx, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ListPlayTimeBuckets: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 hour buckets, got %#v", buckets)
+	}
+	var jstBucket, utcBucket *domain.PlayTimeBucket
+	for i := range buckets {
+		if _, offset := buckets[i].Start.Zone(); offset == 9*60*60 {
+			jstBucket = &buckets[i]
+		} else {
+			utcBucket = &buckets[i]
+		}
+	}
+	if jstBucket == nil || jstBucket.Duration != 120 || !jstBucket.Start.Equal(time.Date(2024, 1, 1, 23, 0, 0, 0, jst)) {
+		t.Fatalf("unexpected JST bucket: %#v", jstBucket)
+	}
+	if utcBucket == nil || utcBucket.Duration != 60 || !utcBucket.Start.Equal(time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected UTC bucket: %#v", utcBucket)
+	}
+}
+
+func TestRepositoryGameDetailsRoundTripAndSearchByGenre(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if game.Description != nil || game.ReleaseDate != nil || game.Rating != nil || game.Genres != nil {
+		t.Fatalf("expected no details by default, got %+v", game)
+	}
+
+	description, releaseDate, rating := "概要", "2024-05-31", 85
+	game.Description, game.ReleaseDate, game.Rating, game.Genres = &description, &releaseDate, &rating, []string{"ADV", "ミステリー"}
+	if _, err := repo.UpdateGame(ctx, *game); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if got.Description == nil || *got.Description != description || got.ReleaseDate == nil || *got.ReleaseDate != releaseDate ||
+		got.Rating == nil || *got.Rating != rating || len(got.Genres) != 2 || got.Genres[1] != "ミステリー" {
+		t.Fatalf("details should round-trip, got %+v", got)
+	}
+
+	found, err := repo.ListGames(ctx, "ミステリー", domain.PlayStatus(""), "title", "asc")
+	if err != nil || len(found) != 1 {
+		t.Fatalf("expected search to match genre, got %d games (err=%v)", len(found), err)
+	}
+
+	synced := *got
+	synced.Genres = nil
+	if err := repo.UpsertGameSync(ctx, synced); err != nil {
+		t.Fatalf("UpsertGameSync: %v", err)
+	}
+	got, _ = repo.GetGameByID(ctx, game.ID)
+	if got.Genres != nil {
+		t.Fatalf("synced game should clear genres, got %+v", got.Genres)
+	}
+}
+
+func TestRepositoryRouteStatsIncludeProgress(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	game, err := repo.CreateGame(ctx, newGame("ProgressGame", "/progress.exe"))
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	estimate := int64(3600)
+	inProgress, err := repo.CreateRoute(ctx, domain.Route{Name: "共通", Order: 0, GameID: game.ID, EstimatedTime: &estimate})
+	if err != nil {
+		t.Fatalf("CreateRoute: %v", err)
+	}
+	done, err := repo.CreateRoute(ctx, domain.Route{Name: "ヒロインA", Order: 1, GameID: game.ID})
+	if err != nil {
+		t.Fatalf("CreateRoute: %v", err)
+	}
+	completedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	done.CompletedAt = &completedAt
+	updated, err := repo.UpdateRoute(ctx, *done)
+	if err != nil {
+		t.Fatalf("UpdateRoute: %v", err)
+	}
+	if !updated.Completed || updated.CompletedAt == nil || !updated.CompletedAt.Equal(completedAt) {
+		t.Fatalf("expected completion to round-trip, got %+v", updated)
+	}
+	if _, err := repo.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: time.Now().UTC(), Duration: 900, RouteID: &inProgress.ID}); err != nil {
+		t.Fatalf("CreatePlaySession: %v", err)
+	}
+
+	stats, err := repo.GetRouteStats(ctx, game.ID)
+	if err != nil {
+		t.Fatalf("GetRouteStats: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 stats, got %d", len(stats))
+	}
+	if stats[0].EstimatedTime != 3600 || stats[0].CompletionPercent != 25 || stats[0].RemainingTime != 2700 || stats[0].Completed {
+		t.Fatalf("unexpected in-progress stat: %+v", stats[0])
+	}
+	if !stats[1].Completed || stats[1].CompletionPercent != 100 || stats[1].RemainingTime != 0 {
+		t.Fatalf("unexpected completed stat: %+v", stats[1])
+	}
+}
+
+func TestRepositoryListGamesPinsFavoritesFirst(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, title := range []string{"A", "B", "C"} {
+		if _, err := repo.CreateGame(ctx, newGame(title, "/"+title+".exe")); err != nil {
+			t.Fatalf("CreateGame: %v", err)
+		}
+	}
+	favorite := newGame("Z", "/z.exe")
+	favorite.IsFavorite = true
+	if _, err := repo.CreateGame(ctx, favorite); err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+
+	games, err := repo.ListGames(ctx, "", "", "title", "asc")
+	if err != nil {
+		t.Fatalf("ListGames: %v", err)
+	}
+	if len(games) != 4 || games[0].Title != "Z" || !games[0].IsFavorite || games[1].Title != "A" {
+		t.Fatalf("expected favorite to be pinned first, got %+v", games)
+	}
+	favorites, err := repo.ListGames(ctx, "", domain.GameFilterFavorite, "title", "asc")
+	if err != nil {
+		t.Fatalf("ListGames: %v", err)
+	}
+	if len(favorites) != 1 || favorites[0].Title != "Z" {
+		t.Fatalf("expected only favorites, got %+v", favorites)
+	}
+}
+
+func TestRepositoryGameSavePatternsRoundTrip(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	game := newGame("A", "/a.exe")
+	game.SaveExcludePatterns = []string{"*.log", "cache"}
+	created, err := repo.CreateGame(ctx, game)
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if len(created.SaveExcludePatterns) != 2 || created.SaveIncludePatterns != nil {
+		t.Fatalf("unexpected patterns after create: %+v", created)
+	}
+
+	created.SaveIncludePatterns = []string{"saves"}
+	created.SaveExcludePatterns = nil
+	updated, err := repo.UpdateGame(ctx, *created)
+	if err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if len(updated.SaveIncludePatterns) != 1 || updated.SaveIncludePatterns[0] != "saves" || updated.SaveExcludePatterns != nil {
+		t.Fatalf("unexpected patterns after update: include=%v exclude=%v", updated.SaveIncludePatterns, updated.SaveExcludePatterns)
+	}
+}
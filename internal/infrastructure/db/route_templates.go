@@ -0,0 +1,74 @@
+// ルートテンプレート（RouteTemplate）の永続化を提供する。
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const routeTemplateSelectCols = `id, name, routeNames, createdAt, updatedAt`
+
+// ListRouteTemplates はルートテンプレートを名前順で取得する。
+func (repository *Repository) ListRouteTemplates(ctx context.Context) ([]domain.RouteTemplate, error) {
+	return queryAll(ctx, repository.connection,
+		`SELECT `+routeTemplateSelectCols+` FROM "RouteTemplate" ORDER BY name ASC, id`,
+		scanRouteTemplate)
+}
+
+// GetRouteTemplateByID はIDでルートテンプレートを取得する。
+func (repository *Repository) GetRouteTemplateByID(ctx context.Context, templateID string) (*domain.RouteTemplate, error) {
+	row := repository.connection.QueryRowContext(ctx, `SELECT `+routeTemplateSelectCols+` FROM "RouteTemplate" WHERE id = ?`, templateID)
+	template, err := scanRouteTemplate(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// SaveRouteTemplate はルートテンプレートを保存して返す。同名のテンプレートがあればルート構成を上書きする。
+func (repository *Repository) SaveRouteTemplate(ctx context.Context, template domain.RouteTemplate) (*domain.RouteTemplate, error) {
+	names := template.RouteNames
+	if names == nil {
+		names = []string{}
+	}
+	raw, err := json.Marshal(names)
+	if err != nil {
+		return nil, err
+	}
+	var id string
+	err = repository.connection.QueryRowContext(ctx, `
+		INSERT INTO "RouteTemplate" (name, routeNames)
+		VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET routeNames = excluded.routeNames
+		RETURNING id
+	`, template.Name, string(raw)).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return repository.GetRouteTemplateByID(ctx, id)
+}
+
+// DeleteRouteTemplate はルートテンプレートを削除する。
+func (repository *Repository) DeleteRouteTemplate(ctx context.Context, templateID string) error {
+	_, err := repository.connection.ExecContext(ctx, `DELETE FROM "RouteTemplate" WHERE id = ?`, templateID)
+	return err
+}
+
+func scanRouteTemplate(row scanner) (*domain.RouteTemplate, error) {
+	template := domain.RouteTemplate{}
+	var routeNames string
+	if err := row.Scan(&template.ID, &template.Name, &routeNames, &template.CreatedAt, &template.UpdatedAt); err != nil {
+		return nil, err
+	}
+	// 壊れた値は空の構成として扱い、一覧の取得自体は失敗させない。
+	if err := json.Unmarshal([]byte(routeNames), &template.RouteNames); err != nil || template.RouteNames == nil {
+		template.RouteNames = []string{}
+	}
+	return &template, nil
+}
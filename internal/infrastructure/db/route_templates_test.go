@@ -0,0 +1,49 @@
+package db_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRepositoryRouteTemplateSaveOverwritesSameName(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	first, err := repo.SaveRouteTemplate(ctx, domain.RouteTemplate{Name: "シリーズ", RouteNames: []string{"共通", "A"}})
+	if err != nil {
+		t.Fatalf("SaveRouteTemplate: %v", err)
+	}
+	second, err := repo.SaveRouteTemplate(ctx, domain.RouteTemplate{Name: "シリーズ", RouteNames: []string{"共通", "A", "B"}})
+	if err != nil {
+		t.Fatalf("SaveRouteTemplate (overwrite): %v", err)
+	}
+	if second.ID != first.ID {
+		t.Fatalf("expected same template to be overwritten, got %s and %s", first.ID, second.ID)
+	}
+	if !reflect.DeepEqual(second.RouteNames, []string{"共通", "A", "B"}) {
+		t.Fatalf("unexpected route names: %v", second.RouteNames)
+	}
+
+	templates, err := repo.ListRouteTemplates(ctx)
+	if err != nil {
+		t.Fatalf("ListRouteTemplates: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("expected 1 template, got %d", len(templates))
+	}
+
+	if err := repo.DeleteRouteTemplate(ctx, first.ID); err != nil {
+		t.Fatalf("DeleteRouteTemplate: %v", err)
+	}
+	deleted, err := repo.GetRouteTemplateByID(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetRouteTemplateByID: %v", err)
+	}
+	if deleted != nil {
+		t.Fatalf("expected template to be deleted, got %+v", deleted)
+	}
+}
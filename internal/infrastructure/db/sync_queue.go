@@ -0,0 +1,65 @@
+// オフライン中のクラウド操作の送信待ち行列（SyncQueue）の永続化を提供する。
+package db
+
+import (
+	"context"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const syncQueueSelectCols = `id, kind, targetId, revision, attempts, lastError, createdAt, updatedAt`
+
+// EnqueueSyncItem は送信待ち操作を追加する。同じ kind・targetID が既にあれば revision を進めるだけにし、
+// 行の順番（最初に追加された位置）は変えない。
+func (repository *Repository) EnqueueSyncItem(ctx context.Context, kind domain.SyncQueueKind, targetID string) error {
+	_, err := repository.connection.ExecContext(ctx, `
+		INSERT INTO "SyncQueue" (kind, targetId) VALUES (?, ?)
+		ON CONFLICT(kind, targetId) DO UPDATE SET revision = revision + 1
+	`, string(kind), targetID)
+	return err
+}
+
+// ListSyncQueue は送信待ち操作を追加順に取得する。
+func (repository *Repository) ListSyncQueue(ctx context.Context) ([]domain.SyncQueueItem, error) {
+	return queryAll(ctx, repository.connection,
+		`SELECT `+syncQueueSelectCols+` FROM "SyncQueue" ORDER BY id ASC`,
+		scanSyncQueueItem)
+}
+
+// CompleteSyncItem は再送できた操作を削除する。revision が変わっていれば（再送中に再追加された）残す。
+// 削除したかどうかを返す。
+func (repository *Repository) CompleteSyncItem(ctx context.Context, id int64, revision int64) (bool, error) {
+	res, err := repository.connection.ExecContext(ctx, `DELETE FROM "SyncQueue" WHERE id = ? AND revision = ?`, id, revision)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteSyncItem は送信待ち操作を revision に関わらず削除する。
+func (repository *Repository) DeleteSyncItem(ctx context.Context, id int64) error {
+	_, err := repository.connection.ExecContext(ctx, `DELETE FROM "SyncQueue" WHERE id = ?`, id)
+	return err
+}
+
+// RecordSyncItemFailure は再送の失敗回数と理由を記録する。
+func (repository *Repository) RecordSyncItemFailure(ctx context.Context, id int64, message string) error {
+	_, err := repository.connection.ExecContext(ctx, `
+		UPDATE "SyncQueue" SET attempts = attempts + 1, lastError = ? WHERE id = ?
+	`, message, id)
+	return err
+}
+
+func scanSyncQueueItem(row scanner) (*domain.SyncQueueItem, error) {
+	item := domain.SyncQueueItem{}
+	var kind string
+	if err := row.Scan(&item.ID, &kind, &item.TargetID, &item.Revision, &item.Attempts, &item.LastError, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		return nil, err
+	}
+	item.Kind = domain.SyncQueueKind(kind)
+	return &item, nil
+}
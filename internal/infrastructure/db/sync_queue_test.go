@@ -0,0 +1,86 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRepositorySyncQueueCoalescesAndKeepsOrder(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, target := range []struct {
+		kind domain.SyncQueueKind
+		id   string
+	}{
+		{domain.SyncQueueKindGamePush, "game-1"},
+		{domain.SyncQueueKindMemoUpload, "memo-1"},
+		{domain.SyncQueueKindGamePush, "game-1"},
+	} {
+		if err := repo.EnqueueSyncItem(ctx, target.kind, target.id); err != nil {
+			t.Fatalf("EnqueueSyncItem: %v", err)
+		}
+	}
+
+	items, err := repo.ListSyncQueue(ctx)
+	if err != nil {
+		t.Fatalf("ListSyncQueue: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected duplicate enqueue to be coalesced, got %#v", items)
+	}
+	if items[0].Kind != domain.SyncQueueKindGamePush || items[0].TargetID != "game-1" || items[0].Revision != 1 {
+		t.Fatalf("expected game push first with bumped revision, got %#v", items[0])
+	}
+	if items[1].Kind != domain.SyncQueueKindMemoUpload || items[1].Revision != 0 {
+		t.Fatalf("unexpected second item: %#v", items[1])
+	}
+
+	if err := repo.RecordSyncItemFailure(ctx, items[1].ID, "network"); err != nil {
+		t.Fatalf("RecordSyncItemFailure: %v", err)
+	}
+	items, _ = repo.ListSyncQueue(ctx)
+	if items[1].Attempts != 1 || items[1].LastError == nil || *items[1].LastError != "network" {
+		t.Fatalf("expected failure to be recorded, got %#v", items[1])
+	}
+}
+
+func TestRepositoryCompleteSyncItemKeepsReenqueuedItem(t *testing.T) {
+	t.Parallel()
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	if err := repo.EnqueueSyncItem(ctx, domain.SyncQueueKindGamePush, "game-1"); err != nil {
+		t.Fatalf("EnqueueSyncItem: %v", err)
+	}
+	items, _ := repo.ListSyncQueue(ctx)
+	listed := items[0]
+
+	// 再送中に同じ対象が再追加された。
+	if err := repo.EnqueueSyncItem(ctx, domain.SyncQueueKindGamePush, "game-1"); err != nil {
+		t.Fatalf("EnqueueSyncItem: %v", err)
+	}
+	done, err := repo.CompleteSyncItem(ctx, listed.ID, listed.Revision)
+	if err != nil {
+		t.Fatalf("CompleteSyncItem: %v", err)
+	}
+	if done {
+		t.Fatal("expected stale revision not to delete the item")
+	}
+	items, _ = repo.ListSyncQueue(ctx)
+	if len(items) != 1 {
+		t.Fatalf("expected re-enqueued item to remain, got %#v", items)
+	}
+
+	done, err = repo.CompleteSyncItem(ctx, items[0].ID, items[0].Revision)
+	if err != nil || !done {
+		t.Fatalf("expected current revision to delete the item, got %v / %v", done, err)
+	}
+	items, _ = repo.ListSyncQueue(ctx)
+	if len(items) != 0 {
+		t.Fatalf("expected empty queue, got %#v", items)
+	}
+}
@@ -0,0 +1,70 @@
+// 複数の書き込みを1トランザクションにまとめるための Repository ヘルパーを提供する。
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// dbConn は *sql.DB と *sql.Tx の共通部分。トランザクション内でも同じ Repository メソッドを使えるようにする。
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithTx は fn をトランザクション内で実行する。fn に渡す Repository の操作はすべて同じトランザクションに入り、
+// fn がエラーを返すか panic した場合はロールバックする。既にトランザクション内なら入れ子にせずそのまま fn を呼ぶ。
+// fn の中では元の Repository を使わないこと（別接続になり、SQLite のロック待ちになる）。
+func (repository *Repository) WithTx(ctx context.Context, fn func(tx *Repository) error) (err error) {
+	if repository.db == nil {
+		return fn(repository)
+	}
+	tx, err := repository.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = tx.Rollback()
+			panic(recovered)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	// "Game" への書き込みはコミットするまで他の接続から見えないため、購読先への通知はコミット後にまとめて行う。
+	gamesWritten := false
+	child := &Repository{
+		connection: hookedConn{dbConn: tx, onGameWrite: func() { gamesWritten = true }},
+		hooks:      repository.hooks,
+	}
+	if err = fn(child); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	if gamesWritten && repository.hooks != nil {
+		repository.hooks.notifyGamesChanged()
+	}
+	return nil
+}
+
+// WithReadTx は fn を読み込み専用のトランザクション内で実行する。fn の中の読み込みはすべて同じ時点の
+// スナップショットを見るため、集計と一覧を別々のクエリで取っても、その間の監視 goroutine によるセッション保存で
+// 数が食い違わない。書き込みロックを取らない（BEGIN IMMEDIATE にしない）ので、書き込みを待たせることもない。
+// fn の中で書き込んではならない。既にトランザクション内ならそのまま fn を呼ぶ。
+func (repository *Repository) WithReadTx(ctx context.Context, fn func(tx *Repository) error) error {
+	if repository.db == nil {
+		return fn(repository)
+	}
+	tx, err := repository.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	// 読み込みだけなので、成否にかかわらずロールバックでスナップショットを手放す。
+	defer func() { _ = tx.Rollback() }()
+	return fn(&Repository{connection: tx, hooks: repository.hooks})
+}
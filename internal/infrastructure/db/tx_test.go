@@ -0,0 +1,104 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/db"
+)
+
+func TestWithTxRollsBackAllStepsOnError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+
+	failure := errors.New("boom")
+	err := repo.WithTx(ctx, func(tx *db.Repository) error {
+		if _, err := tx.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: time.Now().UTC(), Duration: 60}); err != nil {
+			return err
+		}
+		if err := tx.UpdateGameTotalPlayTime(ctx, game.ID, 60); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+
+	sessions, _ := repo.ListPlaySessionsByGame(ctx, game.ID)
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if len(sessions) != 0 || got.TotalPlayTime != 0 {
+		t.Fatalf("expected rollback, got %d sessions and total %d", len(sessions), got.TotalPlayTime)
+	}
+}
+
+func TestWithTxCommitsAndNestsIntoOuterTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+
+	err := repo.WithTx(ctx, func(tx *db.Repository) error {
+		if _, err := tx.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: time.Now().UTC(), Duration: 60}); err != nil {
+			return err
+		}
+		// 入れ子の WithTx は外側のトランザクションにそのまま参加する。
+		return tx.WithTx(ctx, func(inner *db.Repository) error {
+			return inner.UpdateGameTotalPlayTime(ctx, game.ID, 60)
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+
+	sessions, _ := repo.ListPlaySessionsByGame(ctx, game.ID)
+	got, _ := repo.GetGameByID(ctx, game.ID)
+	if len(sessions) != 1 || got.TotalPlayTime != 60 {
+		t.Fatalf("expected commit, got %d sessions and total %d", len(sessions), got.TotalPlayTime)
+	}
+}
+
+func TestWithReadTxSeesConsistentSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	game, _ := repo.CreateGame(ctx, newGame("Game", "/game.exe"))
+	if _, err := repo.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: time.Now().UTC(), Duration: 60}); err != nil {
+		t.Fatalf("CreatePlaySession: %v", err)
+	}
+
+	err := repo.WithReadTx(ctx, func(tx *db.Repository) error {
+		before, err := tx.ListPlaySessionsByGame(ctx, game.ID)
+		if err != nil {
+			return err
+		}
+		// 読み込み中に別のコネクションから書き込んでも待たされず、スナップショットには現れない。
+		if _, err := repo.CreatePlaySession(ctx, domain.PlaySession{GameID: game.ID, PlayedAt: time.Now().UTC(), Duration: 30}); err != nil {
+			return err
+		}
+		after, total, err := tx.ListPlaySessionsByGamePage(ctx, game.ID, domain.PageRequest{Limit: 10})
+		if err != nil {
+			return err
+		}
+		if len(before) != 1 || len(after) != 1 || total != 1 {
+			t.Errorf("snapshot changed: before=%d after=%d total=%d", len(before), len(after), total)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithReadTx: %v", err)
+	}
+
+	sessions, _ := repo.ListPlaySessionsByGame(ctx, game.ID)
+	if len(sessions) != 2 {
+		t.Fatalf("write during the read transaction should be kept, got %d sessions", len(sessions))
+	}
+}
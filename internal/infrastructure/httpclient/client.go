@@ -0,0 +1,100 @@
+// タイムアウト・User-Agent・プロキシ・リトライを揃えた HTTP クライアントを生成する。
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultUserAgent は User-Agent 未指定時に付与する値。
+const DefaultUserAgent = "CloudLaunch/1.0"
+
+const (
+	defaultTimeout   = 15 * time.Second
+	retryBaseBackoff = 500 * time.Millisecond
+	retryMaxBackoff  = 5 * time.Second
+)
+
+// Options は HTTP クライアントの生成設定を表す。
+type Options struct {
+	// Timeout はリトライを含めない1リクエストあたりの上限。0 以下なら既定値（15秒）。
+	Timeout time.Duration
+	// UserAgent はリクエストに User-Agent が無い場合に付与する値。空なら DefaultUserAgent。
+	UserAgent string
+	// ProxyURL は明示的なプロキシ。空なら環境変数（HTTPS_PROXY 等）に従う。
+	ProxyURL string
+	// MaxRetries は通信エラー・429・5xx 時の再試行回数。
+	MaxRetries int
+}
+
+// ValidateProxyURL はプロキシURLが http/https/socks5 の絶対URLかを検証する。空文字は許可する。
+func ValidateProxyURL(raw string) error {
+	_, err := parseProxyURL(raw)
+	return err
+}
+
+// New は Options に従った HTTP クライアントを生成する。
+func New(options Options) (*http.Client, error) {
+	proxyURL, err := parseProxyURL(options.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	userAgent := strings.TrimSpace(options.UserAgent)
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	maxRetries := max(options.MaxRetries, 0)
+
+	return &http.Client{
+		Transport: &retryTransport{
+			base:       transport,
+			userAgent:  userAgent,
+			timeout:    timeout,
+			maxRetries: maxRetries,
+			backoff:    exponentialBackoff,
+		},
+	}, nil
+}
+
+func parseProxyURL(raw string) (*url.URL, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.New("proxy url scheme must be http, https or socks5")
+	}
+	if parsed.Host == "" {
+		return nil, errors.New("proxy url host is empty")
+	}
+	return parsed, nil
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	delay := retryBaseBackoff << attempt
+	if delay <= 0 || delay > retryMaxBackoff {
+		return retryMaxBackoff
+	}
+	return delay
+}
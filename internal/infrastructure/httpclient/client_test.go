@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, options Options) *http.Client {
+	t.Helper()
+	client, err := New(options)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	client.Transport.(*retryTransport).backoff = func(int) time.Duration { return time.Millisecond }
+	return client
+}
+
+func TestClientSetsDefaultUserAgent(t *testing.T) {
+	t.Parallel()
+
+	var got atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Store(r.Header.Get("User-Agent"))
+	}))
+	defer server.Close()
+
+	response, err := newTestClient(t, Options{}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	_ = response.Body.Close()
+	if got.Load() != DefaultUserAgent {
+		t.Fatalf("user-agent = %v, want %s", got.Load(), DefaultUserAgent)
+	}
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	response, err := newTestClient(t, Options{MaxRetries: 2}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("status = %d calls = %d, want 200 after 3 calls", response.StatusCode, calls.Load())
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	response, err := newTestClient(t, Options{MaxRetries: 3}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	_ = response.Body.Close()
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestValidateProxyURL(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{"", "http://proxy.local:8080", "socks5://127.0.0.1:1080"}
+	for _, raw := range valid {
+		if err := ValidateProxyURL(raw); err != nil {
+			t.Errorf("ValidateProxyURL(%q) = %v, want nil", raw, err)
+		}
+	}
+	invalid := []string{"ftp://proxy.local", "proxy.local:8080", "http://"}
+	for _, raw := range invalid {
+		if err := ValidateProxyURL(raw); err == nil {
+			t.Errorf("ValidateProxyURL(%q) = nil, want error", raw)
+		}
+	}
+}
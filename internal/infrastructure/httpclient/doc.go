@@ -0,0 +1,2 @@
+// Package httpclient は外部サイト向け HTTP クライアントの生成を提供する。
+package httpclient
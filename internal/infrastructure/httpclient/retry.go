@@ -0,0 +1,96 @@
+// User-Agent の付与と一時的な失敗の再試行を行う RoundTripper を提供する。
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+type retryTransport struct {
+	base       http.RoundTripper
+	userAgent  string
+	timeout    time.Duration
+	maxRetries int
+	backoff    func(attempt int) time.Duration
+}
+
+// RoundTrip はリクエストを送信し、再試行可能な失敗なら backoff を挟んで送り直す。
+// http.Client.Timeout はリトライ全体に掛かってしまうため、タイムアウトは試行ごとに付ける。
+func (transport *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Header.Get("User-Agent") == "" {
+		request = request.Clone(request.Context())
+		request.Header.Set("User-Agent", transport.userAgent)
+	}
+	retryable := canRewind(request)
+
+	for attempt := 0; ; attempt++ {
+		attemptRequest, err := rewindRequest(request, attempt)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(request.Context(), transport.timeout)
+		response, err := transport.base.RoundTrip(attemptRequest.WithContext(ctx))
+
+		if !retryable || attempt >= transport.maxRetries || !shouldRetry(request.Context(), response, err) {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			response.Body = &cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+			return response, nil
+		}
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+		cancel()
+
+		timer := time.NewTimer(transport.backoff(attempt))
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry は通信エラー（呼び出し側のキャンセルを除く）・429・5xx を再試行対象とする。
+func shouldRetry(ctx context.Context, response *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError
+}
+
+// canRewind は本文を送り直せるリクエストかを返す（本文なし、または GetBody あり）。
+func canRewind(request *http.Request) bool {
+	return request.Body == nil || request.Body == http.NoBody || request.GetBody != nil
+}
+
+func rewindRequest(request *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || request.GetBody == nil {
+		return request, nil
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	rewound := request.Clone(request.Context())
+	rewound.Body = body
+	return rewound, nil
+}
+
+// cancelOnClose は本文を読み終えて Close されるまで試行ごとの context を生かしておく。
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (body *cancelOnClose) Close() error {
+	err := body.ReadCloser.Close()
+	body.cancel()
+	return err
+}
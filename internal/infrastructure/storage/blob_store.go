@@ -11,11 +11,9 @@ import (
 	"sync"
 
 	"CloudLaunch_Go/internal/util"
-
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-// BlobKind はS3上のオブジェクト種別を表す。
+// BlobKind は同期先のオブジェクト種別を表す。
 type BlobKind = string
 
 const (
@@ -38,6 +36,14 @@ func contentTypeForKind(kind string) string {
 	}
 }
 
+// contentTypeForBlob は暗号化時にはバケット側から中身の種類が分からないよう octet-stream にする。
+func contentTypeForBlob(kind string, blobCipher *BlobCipher) string {
+	if blobCipher != nil {
+		return "application/octet-stream"
+	}
+	return contentTypeForKind(kind)
+}
+
 func blobHashBytes(data []byte) string {
 	return util.Sha256Hex(data)
 }
@@ -80,90 +86,75 @@ func ResolveSafeRelativePath(baseDir, relPath string) (string, error) {
 	return absTarget, nil
 }
 
-func blobExists(ctx context.Context, client *s3.Client, bucket, gameID, kind, hash string) (bool, error) {
-	key := blobKey(gameID, kind, hash)
-	_, err := client.HeadObject(ctx, &s3.HeadObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-	})
-	if err != nil {
-		if IsNotFoundError(err) {
-			return false, nil
-		}
-		return false, err
-	}
-	return true, nil
-}
-
-// PutBlob はブロブをS3にアップロードする。既に存在する場合はスキップする。
-func PutBlob(ctx context.Context, client *s3.Client, bucket, gameID, kind, hash string, data []byte) error {
+// PutBlob はブロブをアップロードする。既に存在する場合はスキップする。
+// blobCipher が nil でなければ暗号化してから送る（キーは平文のハッシュのまま）。
+func PutBlob(ctx context.Context, store ObjectStore, blobCipher *BlobCipher, gameID, kind, hash string, data []byte) error {
 	if blobHashBytes(data) != hash {
 		return fmt.Errorf("blob hash mismatch: %s/%s", kind, hash)
 	}
-	exists, err := blobExists(ctx, client, bucket, gameID, kind, hash)
+	key := blobKey(gameID, kind, hash)
+	exists, err := store.Exists(ctx, key)
 	if err != nil {
 		return err
 	}
 	if exists {
 		return nil
 	}
-	return UploadBytes(ctx, client, bucket, blobKey(gameID, kind, hash), data, contentTypeForKind(kind))
+	payload, err := sealBlob(blobCipher, key, data)
+	if err != nil {
+		return err
+	}
+	return store.Upload(ctx, key, payload, contentTypeForBlob(kind, blobCipher))
 }
 
-// GetBlob はS3からブロブを取得する。
-func GetBlob(ctx context.Context, client *s3.Client, bucket, gameID, kind, hash string) ([]byte, error) {
-	data, err := DownloadObject(ctx, client, bucket, blobKey(gameID, kind, hash))
+// GetBlob はブロブを取得する。暗号化済みなら blobCipher で復号する。
+func GetBlob(ctx context.Context, store ObjectStore, blobCipher *BlobCipher, gameID, kind, hash string) ([]byte, error) {
+	key := blobKey(gameID, kind, hash)
+	data, err := store.Download(ctx, key)
 	if err != nil {
 		return nil, err
 	}
-	if blobHashBytes(data) != hash {
-		return nil, fmt.Errorf("blob hash mismatch: %s/%s", kind, hash)
-	}
-	return data, nil
+	return openBlob(blobCipher, key, kind, hash, data)
 }
 
 // ListBlobHashes はゲームの既存セーブファイルブロブのハッシュを一括取得する。
 // objects/ プレフィックスのみを対象とする。
-func ListBlobHashes(ctx context.Context, client *s3.Client, bucket, gameID string) (map[string]struct{}, error) {
+func ListBlobHashes(ctx context.Context, store ObjectStore, gameID string) (map[string]struct{}, error) {
 	prefix := fmt.Sprintf("games/%s/%s/", gameID, BlobKindObject)
-	existing := make(map[string]struct{})
-	input := &s3.ListObjectsV2Input{
-		Bucket: &bucket,
-		Prefix: &prefix,
-	}
-	paginator := s3.NewListObjectsV2Paginator(client, input)
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(ctx)
-		if err != nil {
-			return nil, err
-		}
-		for _, obj := range page.Contents {
-			if obj.Key == nil {
-				continue
-			}
-			existing[path.Base(*obj.Key)] = struct{}{}
-		}
+	objects, err := store.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]struct{}, len(objects))
+	for _, obj := range objects {
+		existing[path.Base(obj.Key)] = struct{}{}
 	}
 	return existing, nil
 }
 
+// BlobTransferFunc はブロブ1件の転送完了を通知するコールバック。
+// name は PutBlobs ではハッシュ、DownloadBlobs では相対パス。size は平文のバイト数。
+// skipped はリモートに既にあり転送しなかったことを表す。
+type BlobTransferFunc func(name string, size int64, skipped bool)
+
 // PutBlobs はセーブファイルブロブを一括アップロードする（objects/ 固定）。
 // ListObjectsV2 でリモートの既存ハッシュを一括取得し、不足分のみ並列アップロードする。
-// onProgress は (アップロード済み件数, 総件数) を受け取るコールバック。nil 可。
+// onBlob はブロブごとに1回呼ばれる（既にリモートにあるものはアップロード前にまとめて呼ぶ）。nil 可。
 func PutBlobs(
 	ctx context.Context,
-	client *s3.Client,
-	bucket, gameID string,
+	store ObjectStore,
+	blobCipher *BlobCipher,
+	gameID string,
 	blobs map[string][]byte,
 	concurrency int,
-	onProgress func(uploaded, total int),
+	onBlob BlobTransferFunc,
 ) error {
 	total := len(blobs)
 	if total == 0 {
 		return nil
 	}
 
-	existing, err := ListBlobHashes(ctx, client, bucket, gameID)
+	existing, err := ListBlobHashes(ctx, store, gameID)
 	if err != nil {
 		return err
 	}
@@ -182,9 +173,12 @@ func PutBlobs(
 		}
 	}
 
-	alreadyDone := total - len(tasks)
-	if onProgress != nil {
-		onProgress(alreadyDone, total)
+	if onBlob != nil {
+		for hash, data := range blobs {
+			if _, ok := existing[hash]; ok {
+				onBlob(hash, int64(len(data)), true)
+			}
+		}
 	}
 	if len(tasks) == 0 {
 		return nil
@@ -211,7 +205,6 @@ func PutBlobs(
 	var errOnce sync.Once
 	var firstErr error
 	var mu sync.Mutex
-	uploaded := alreadyDone
 
 	for i := 0; i < workerCount; i++ {
 		wg.Add(1)
@@ -221,7 +214,11 @@ func PutBlobs(
 				if ctx.Err() != nil {
 					return
 				}
-				putErr := UploadBytes(ctx, client, bucket, blobKey(gameID, BlobKindObject, t.hash), t.data, contentTypeForKind(BlobKindObject))
+				key := blobKey(gameID, BlobKindObject, t.hash)
+				payload, putErr := sealBlob(blobCipher, key, t.data)
+				if putErr == nil {
+					putErr = store.Upload(ctx, key, payload, contentTypeForBlob(BlobKindObject, blobCipher))
+				}
 				if putErr != nil {
 					errOnce.Do(func() {
 						firstErr = putErr
@@ -229,10 +226,9 @@ func PutBlobs(
 					})
 					return
 				}
-				if onProgress != nil {
+				if onBlob != nil {
 					mu.Lock()
-					uploaded++
-					onProgress(uploaded, total)
+					onBlob(t.hash, int64(len(t.data)), false)
 					mu.Unlock()
 				}
 			}
@@ -245,14 +241,15 @@ func PutBlobs(
 
 // DownloadBlobs はセーブファイルブロブを並列ダウンロードしてローカルに保存する（objects/ 固定）。
 // blobs は relPath → hash のマップ。saveDir 配下の relPath に書き込む。
-// onProgress は (ダウンロード済み件数, 総件数) を受け取るコールバック。nil 可。
+// onFile はファイルを書き出すたびに呼ばれる。nil 可。
 func DownloadBlobs(
 	ctx context.Context,
-	client *s3.Client,
-	bucket, gameID, saveDir string,
+	store ObjectStore,
+	blobCipher *BlobCipher,
+	gameID, saveDir string,
 	blobs map[string]string,
 	concurrency int,
-	onProgress func(downloaded, total int),
+	onFile BlobTransferFunc,
 ) error {
 	if len(blobs) == 0 {
 		return nil
@@ -290,7 +287,6 @@ func DownloadBlobs(
 	var errOnce sync.Once
 	var firstErr error
 	var mu sync.Mutex
-	downloaded := 0
 
 	for i := 0; i < workerCount; i++ {
 		wg.Add(1)
@@ -300,7 +296,7 @@ func DownloadBlobs(
 				if ctx.Err() != nil {
 					return
 				}
-				data, err := GetBlob(ctx, client, bucket, gameID, BlobKindObject, t.hash)
+				data, err := GetBlob(ctx, store, blobCipher, gameID, BlobKindObject, t.hash)
 				if err != nil {
 					errOnce.Do(func() { firstErr = err; cancel() })
 					return
@@ -318,10 +314,9 @@ func DownloadBlobs(
 					errOnce.Do(func() { firstErr = err; cancel() })
 					return
 				}
-				if onProgress != nil {
+				if onFile != nil {
 					mu.Lock()
-					downloaded++
-					onProgress(downloaded, total)
+					onFile(t.relPath, int64(len(data)), false)
 					mu.Unlock()
 				}
 			}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+)
+
+func TestReadHEADIfNoneMatchUsesETag(t *testing.T) {
+	t.Parallel()
+
+	const etag = `"v1"`
+	var gets, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/bucket/games/game-1/HEAD" {
+			http.NotFound(w, r)
+			return
+		}
+		gets++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte("head-hash\n"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), S3Config{Endpoint: server.URL, Region: "auto", ForcePathStyle: true}, credentials.Credential{
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	store := NewS3ObjectStore(client, "bucket")
+
+	hash, newETag, err := ReadHEADIfNoneMatch(context.Background(), store, "game-1", "")
+	if err != nil || hash != "head-hash" || newETag != etag {
+		t.Fatalf("first read = %q, %q, %v", hash, newETag, err)
+	}
+	if _, _, err := ReadHEADIfNoneMatch(context.Background(), store, "game-1", newETag); !errors.Is(err, ErrNotModified) {
+		t.Fatalf("second read error = %v, want ErrNotModified", err)
+	}
+	if gets != 2 || notModified != 1 {
+		t.Fatalf("gets = %d, notModified = %d", gets, notModified)
+	}
+}
+
+func TestReadHEADIfNoneMatchFallsBackWithoutConditionalSupport(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewLocalObjectStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalObjectStore: %v", err)
+	}
+	if err := WriteHEAD(context.Background(), store, "game-1", "head-hash"); err != nil {
+		t.Fatalf("WriteHEAD: %v", err)
+	}
+	hash, etag, err := ReadHEADIfNoneMatch(context.Background(), store, "game-1", `"ignored"`)
+	if err != nil || hash != "head-hash" || etag != "" {
+		t.Fatalf("read = %q, %q, %v", hash, etag, err)
+	}
+	if hash, _, err := ReadHEADIfNoneMatch(context.Background(), store, "missing", ""); err != nil || hash != "" {
+		t.Fatalf("missing HEAD = %q, %v", hash, err)
+	}
+}
+
+func TestUploadIfAbsentSendsIfNoneMatch(t *testing.T) {
+	t.Parallel()
+
+	var stored []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("If-None-Match") != "*" {
+			t.Errorf("unexpected request: %s %v", r.Method, r.Header)
+		}
+		if stored != nil {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`<Error><Code>PreconditionFailed</Code></Error>`))
+			return
+		}
+		stored, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), S3Config{Endpoint: server.URL, Region: "auto", ForcePathStyle: true}, credentials.Credential{
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	store := NewS3ObjectStore(client, "bucket")
+	if err := store.UploadIfAbsent(context.Background(), "encryption.json", []byte("first"), "application/json"); err != nil {
+		t.Fatalf("first UploadIfAbsent: %v", err)
+	}
+	if err := store.UploadIfAbsent(context.Background(), "encryption.json", []byte("second"), "application/json"); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("second UploadIfAbsent error = %v, want ErrAlreadyExists", err)
+	}
+	if string(stored) != "first" {
+		t.Fatalf("stored = %q", stored)
+	}
+}
@@ -0,0 +1,236 @@
+// ブロブのクライアントサイド暗号化（AES-GCM）を提供する。
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+)
+
+const (
+	// encryptionConfigKey はバケット単位の暗号化設定（ソルト・検証値）を置くキー。
+	// 鍵の導出に使うソルトは全端末で同じである必要があるため、パスフレーズと違いバケット側に置く。
+	encryptionConfigKey = "encryption.json"
+
+	encryptionVersion       = 1
+	encryptionKDF           = "pbkdf2-sha256"
+	encryptionKDFIterations = 600_000
+	encryptionSaltSize      = 16
+	encryptionKeySize       = 32
+
+	// blobEnvelopeMagic は暗号化済みブロブの先頭に付く識別子。続けて nonce・暗号文（タグ込み）が並ぶ。
+	blobEnvelopeMagic = "CLE1"
+	// encryptionCheckPlaintext はパスフレーズ照合用に暗号化しておく固定文字列。
+	encryptionCheckPlaintext = "CloudLaunch"
+)
+
+var (
+	// ErrEncryptionPassphraseMismatch はバケットの暗号化設定とパスフレーズが一致しないことを表す。
+	ErrEncryptionPassphraseMismatch = errors.New("暗号化パスフレーズがバケットの設定と一致しません")
+	// ErrEncryptionPassphraseRequired は暗号化済みバケットにパスフレーズ無しで接続したことを表す。
+	ErrEncryptionPassphraseRequired = errors.New("バケットは暗号化されていますがパスフレーズが未設定です")
+)
+
+// EncryptionConfig はバケットに保存する暗号化設定を表す。鍵そのものは含まない。
+type EncryptionConfig struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Check      []byte `json:"check"`
+}
+
+// BlobCipher はパスフレーズから導出した鍵でブロブを暗号化・復号する。
+type BlobCipher struct {
+	aead cipher.AEAD
+}
+
+// NewEncryptionConfig は新しいソルトで暗号化設定を作り、その鍵の BlobCipher を返す。
+func NewEncryptionConfig(passphrase string) (EncryptionConfig, *BlobCipher, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return EncryptionConfig{}, nil, err
+	}
+	cfg := EncryptionConfig{
+		Version:    encryptionVersion,
+		KDF:        encryptionKDF,
+		Iterations: encryptionKDFIterations,
+		Salt:       salt,
+	}
+	blobCipher, err := deriveBlobCipher(passphrase, cfg)
+	if err != nil {
+		return EncryptionConfig{}, nil, err
+	}
+	cfg.Check, err = blobCipher.Seal(encryptionConfigKey, []byte(encryptionCheckPlaintext))
+	if err != nil {
+		return EncryptionConfig{}, nil, err
+	}
+	return cfg, blobCipher, nil
+}
+
+// NewBlobCipher は既存の暗号化設定とパスフレーズから BlobCipher を作る。
+// パスフレーズが異なる場合は ErrEncryptionPassphraseMismatch を返す。
+func NewBlobCipher(passphrase string, cfg EncryptionConfig) (*BlobCipher, error) {
+	if cfg.Version != encryptionVersion || cfg.KDF != encryptionKDF {
+		return nil, fmt.Errorf("unsupported encryption config: version=%d kdf=%s", cfg.Version, cfg.KDF)
+	}
+	blobCipher, err := deriveBlobCipher(passphrase, cfg)
+	if err != nil {
+		return nil, err
+	}
+	check, err := blobCipher.Open(encryptionConfigKey, cfg.Check)
+	if err != nil || string(check) != encryptionCheckPlaintext {
+		return nil, ErrEncryptionPassphraseMismatch
+	}
+	return blobCipher, nil
+}
+
+func deriveBlobCipher(passphrase string, cfg EncryptionConfig) (*BlobCipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("encryption passphrase is empty")
+	}
+	if cfg.Iterations <= 0 || len(cfg.Salt) == 0 {
+		return nil, errors.New("invalid encryption config")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, cfg.Salt, cfg.Iterations, encryptionKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &BlobCipher{aead: aead}, nil
+}
+
+// Seal は plaintext を暗号化し、magic・nonce・暗号文を連結したエンベロープを返す。
+// objectKey を追加認証データにするため、別キーへ差し替えられた暗号文は復号できない。
+func (c *BlobCipher) Seal(objectKey string, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	envelope := make([]byte, 0, len(blobEnvelopeMagic)+len(nonce)+len(plaintext)+c.aead.Overhead())
+	envelope = append(envelope, blobEnvelopeMagic...)
+	envelope = append(envelope, nonce...)
+	return c.aead.Seal(envelope, nonce, plaintext, []byte(objectKey)), nil
+}
+
+// Open は Seal で作られたエンベロープを復号する。
+func (c *BlobCipher) Open(objectKey string, envelope []byte) ([]byte, error) {
+	if !isEncryptedEnvelope(envelope) {
+		return nil, errors.New("not an encrypted blob")
+	}
+	body := envelope[len(blobEnvelopeMagic):]
+	nonceSize := c.aead.NonceSize()
+	if len(body) < nonceSize+c.aead.Overhead() {
+		return nil, errors.New("encrypted blob is truncated")
+	}
+	return c.aead.Open(nil, body[:nonceSize], body[nonceSize:], []byte(objectKey))
+}
+
+// IsEncryptedEnvelope は data が暗号化ブロブのエンベロープで始まるかを返す。
+func IsEncryptedEnvelope(data []byte) bool {
+	return isEncryptedEnvelope(data)
+}
+
+func isEncryptedEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(blobEnvelopeMagic))
+}
+
+// sealBlob は cipher が nil なら平文のまま、そうでなければ暗号化して返す。
+func sealBlob(blobCipher *BlobCipher, objectKey string, data []byte) ([]byte, error) {
+	if blobCipher == nil {
+		return data, nil
+	}
+	return blobCipher.Seal(objectKey, data)
+}
+
+// openBlob は取得したブロブを平文に戻し、ハッシュを検証する。
+// 暗号化を有効にする前にアップロードされた平文ブロブもそのまま読めるよう、
+// 復号できなくても内容のハッシュが一致すれば平文として扱う。
+func openBlob(blobCipher *BlobCipher, objectKey, kind, hash string, data []byte) ([]byte, error) {
+	if blobHashBytes(data) == hash {
+		return data, nil
+	}
+	if isEncryptedEnvelope(data) {
+		if blobCipher == nil {
+			return nil, ErrEncryptionPassphraseRequired
+		}
+		plaintext, err := blobCipher.Open(objectKey, data)
+		if err != nil {
+			return nil, fmt.Errorf("blob decrypt failed: %s/%s: %w", kind, hash, err)
+		}
+		data = plaintext
+	}
+	if blobHashBytes(data) != hash {
+		return nil, fmt.Errorf("blob hash mismatch: %s/%s", kind, hash)
+	}
+	return data, nil
+}
+
+// OpenObject はクラウドデータ閲覧で取得したオブジェクトを平文に戻す。暗号化されていなければそのまま返す。
+// ブロブのキーは末尾が内容のハッシュなので、ハッシュが一致すればたまたま magic で始まる平文として扱う。
+func OpenObject(blobCipher *BlobCipher, objectKey string, data []byte) ([]byte, error) {
+	if !isEncryptedEnvelope(data) || blobHashBytes(data) == path.Base(objectKey) {
+		return data, nil
+	}
+	if blobCipher == nil {
+		return nil, ErrEncryptionPassphraseRequired
+	}
+	return blobCipher.Open(objectKey, data)
+}
+
+// ReadEncryptionConfig は同期先の暗号化設定を取得する。未設定なら nil を返す。
+func ReadEncryptionConfig(ctx context.Context, store ObjectStore) (*EncryptionConfig, error) {
+	data, err := store.Download(ctx, encryptionConfigKey)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cfg EncryptionConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid encryption config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// CreateEncryptionConfig は同期先に暗号化設定が無いときだけ cfg を書き込み、実際に同期先に残った設定を返す。
+// 複数の端末が同時に初めて暗号化を有効にしても全端末が同じソルトを使うよう、作成のみの書き込みに
+// 対応するストアでは先に書いた側を優先する。対応しないストアでも書き込み後に読み直し、残った設定に合わせる。
+func CreateEncryptionConfig(ctx context.Context, store ObjectStore, cfg EncryptionConfig) (EncryptionConfig, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return EncryptionConfig{}, err
+	}
+	if creator, ok := store.(CreateOnlyUploader); ok {
+		err = creator.UploadIfAbsent(ctx, encryptionConfigKey, data, "application/json")
+	} else {
+		err = store.Upload(ctx, encryptionConfigKey, data, "application/json")
+	}
+	if err != nil && !errors.Is(err, ErrAlreadyExists) {
+		return EncryptionConfig{}, err
+	}
+	stored, err := ReadEncryptionConfig(ctx, store)
+	if err != nil {
+		return EncryptionConfig{}, err
+	}
+	if stored == nil {
+		return EncryptionConfig{}, errors.New("encryption config was not found after writing")
+	}
+	return *stored, nil
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBlobCipherRoundTripAndPassphraseCheck(t *testing.T) {
+	t.Parallel()
+
+	cfg, sealer, err := NewEncryptionConfig("correct horse")
+	if err != nil {
+		t.Fatalf("NewEncryptionConfig: %v", err)
+	}
+	key := blobKey("game-1", BlobKindObject, "hash")
+	envelope, err := sealer.Seal(key, []byte("save data"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(envelope, []byte("save data")) {
+		t.Fatal("envelope should not contain plaintext")
+	}
+
+	opener, err := NewBlobCipher("correct horse", cfg)
+	if err != nil {
+		t.Fatalf("NewBlobCipher: %v", err)
+	}
+	plaintext, err := opener.Open(key, envelope)
+	if err != nil || string(plaintext) != "save data" {
+		t.Fatalf("Open = %q, %v", plaintext, err)
+	}
+	if _, err := opener.Open(blobKey("game-2", BlobKindObject, "hash"), envelope); err == nil {
+		t.Fatal("expected Open to fail for a different object key")
+	}
+
+	if _, err := NewBlobCipher("wrong", cfg); !errors.Is(err, ErrEncryptionPassphraseMismatch) {
+		t.Fatalf("err = %v, want ErrEncryptionPassphraseMismatch", err)
+	}
+}
+
+func TestOpenBlobAcceptsLegacyPlaintextAndRequiresPassphrase(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("legacy")
+	hash := blobHashBytes(data)
+	key := blobKey("game-1", BlobKindMeta, hash)
+
+	got, err := openBlob(nil, key, BlobKindMeta, hash, data)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("openBlob plaintext = %q, %v", got, err)
+	}
+
+	_, blobCipher, err := NewEncryptionConfig("pass")
+	if err != nil {
+		t.Fatalf("NewEncryptionConfig: %v", err)
+	}
+	envelope, err := sealBlob(blobCipher, key, data)
+	if err != nil {
+		t.Fatalf("sealBlob: %v", err)
+	}
+	if _, err := openBlob(nil, key, BlobKindMeta, hash, envelope); !errors.Is(err, ErrEncryptionPassphraseRequired) {
+		t.Fatalf("err = %v, want ErrEncryptionPassphraseRequired", err)
+	}
+	got, err = openBlob(blobCipher, key, BlobKindMeta, hash, envelope)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("openBlob encrypted = %q, %v", got, err)
+	}
+	if _, err := openBlob(blobCipher, key, BlobKindMeta, "other", envelope); err == nil {
+		t.Fatal("expected hash mismatch")
+	}
+}
+
+func TestOpenObjectDecryptsEnvelopeAndPassesPlaintext(t *testing.T) {
+	t.Parallel()
+
+	_, blobCipher, err := NewEncryptionConfig("pass")
+	if err != nil {
+		t.Fatalf("NewEncryptionConfig: %v", err)
+	}
+	data := []byte(`{"title":"game"}`)
+	key := blobKey("game-1", BlobKindMeta, blobHashBytes(data))
+	envelope, err := sealBlob(blobCipher, key, data)
+	if err != nil {
+		t.Fatalf("sealBlob: %v", err)
+	}
+	if got, err := OpenObject(blobCipher, key, envelope); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("OpenObject encrypted = %q, %v", got, err)
+	}
+	if _, err := OpenObject(nil, key, envelope); !errors.Is(err, ErrEncryptionPassphraseRequired) {
+		t.Fatalf("err = %v, want ErrEncryptionPassphraseRequired", err)
+	}
+	if got, err := OpenObject(nil, "games/game-1/notes.json", data); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("OpenObject plaintext = %q, %v", got, err)
+	}
+}
+
+func TestCreateEncryptionConfigKeepsFirstWriter(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewLocalObjectStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalObjectStore: %v", err)
+	}
+	first, _, err := NewEncryptionConfig("pass")
+	if err != nil {
+		t.Fatalf("NewEncryptionConfig: %v", err)
+	}
+	second, _, err := NewEncryptionConfig("pass")
+	if err != nil {
+		t.Fatalf("NewEncryptionConfig: %v", err)
+	}
+	ctx := context.Background()
+	if stored, err := CreateEncryptionConfig(ctx, store, first); err != nil || !bytes.Equal(stored.Salt, first.Salt) {
+		t.Fatalf("first CreateEncryptionConfig = %+v, %v", stored, err)
+	}
+	// 後から作ろうとした端末は先に書かれた設定（ソルト）を受け取る。
+	stored, err := CreateEncryptionConfig(ctx, store, second)
+	if err != nil || !bytes.Equal(stored.Salt, first.Salt) {
+		t.Fatalf("second CreateEncryptionConfig = %+v, %v", stored, err)
+	}
+	if _, err := NewBlobCipher("pass", stored); err != nil {
+		t.Fatalf("NewBlobCipher with the kept config: %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+// Google ドライブへの接続に使う OAuth（デバイス認可フロー）とアクセストークンの更新を提供する。
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	driveDeviceCodeURL = "https://oauth2.googleapis.com/device/code"
+	driveTokenURL      = "https://oauth2.googleapis.com/token"
+	// DriveScope はアプリが作成したファイルだけを扱える Google ドライブのスコープ。
+	DriveScope = "https://www.googleapis.com/auth/drive.file"
+	// driveDeviceGrantType はデバイス認可フローでトークンを取得するときの grant_type。
+	driveDeviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// driveTokenMargin はアクセストークンの期限切れ直前の利用を避けるための余裕。
+	driveTokenMargin = time.Minute
+)
+
+var (
+	// ErrDriveAuthorizationPending はユーザーがまだ認可を終えていないことを表す（ポーリングを続ける）。
+	ErrDriveAuthorizationPending = errors.New("Google ドライブの認可待ちです")
+	// ErrDriveSlowDown はポーリング間隔を広げるよう求められたことを表す。
+	ErrDriveSlowDown = errors.New("Google ドライブの認可確認の間隔が短すぎます")
+	// ErrDriveAuthorizationDenied はユーザーが認可を拒否したことを表す。
+	ErrDriveAuthorizationDenied = errors.New("Google ドライブへのアクセスが拒否されました")
+	// ErrDriveAuthorizationExpired はデバイスコードの有効期限が切れたことを表す。
+	ErrDriveAuthorizationExpired = errors.New("Google ドライブの認可コードの有効期限が切れました")
+)
+
+// DriveDeviceCode はデバイス認可フローの開始時に受け取るコード。
+// ユーザーは VerificationURL を開いて UserCode を入力し、アプリは DeviceCode で認可の完了を確かめる。
+type DriveDeviceCode struct {
+	DeviceCode      string
+	UserCode        string
+	VerificationURL string
+	ExpiresAt       time.Time
+	Interval        time.Duration
+}
+
+// DriveToken は OAuth のトークン。RefreshToken は認可完了時のみ返り、更新時は空になる。
+type DriveToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// DriveAuth は Google の OAuth クライアント（「テレビと入力限定デバイス」種別）でトークンを取得・更新する。
+type DriveAuth struct {
+	clientID      string
+	clientSecret  string
+	httpClient    atomic.Pointer[http.Client]
+	deviceCodeURL string
+	tokenURL      string
+	now           func() time.Time
+}
+
+// NewDriveAuth は DriveAuth を生成する。httpClient が nil なら http.DefaultClient を使う。
+func NewDriveAuth(clientID, clientSecret string, httpClient *http.Client) *DriveAuth {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	auth := &DriveAuth{
+		clientID:      strings.TrimSpace(clientID),
+		clientSecret:  strings.TrimSpace(clientSecret),
+		deviceCodeURL: driveDeviceCodeURL,
+		tokenURL:      driveTokenURL,
+		now:           time.Now,
+	}
+	auth.httpClient.Store(httpClient)
+	return auth
+}
+
+// SetHTTPClient は以後のトークン取得・更新に使う HTTP クライアントを差し替える。nil なら http.DefaultClient を使う。
+func (auth *DriveAuth) SetHTTPClient(httpClient *http.Client) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	auth.httpClient.Store(httpClient)
+}
+
+// Configured はクライアント ID が設定済みかを返す。
+func (auth *DriveAuth) Configured() bool {
+	return auth.clientID != ""
+}
+
+// RequestDeviceCode はデバイス認可フローを開始し、ユーザーに提示するコードを返す。
+func (auth *DriveAuth) RequestDeviceCode(ctx context.Context) (DriveDeviceCode, error) {
+	if !auth.Configured() {
+		return DriveDeviceCode{}, errors.New("Google ドライブの OAuth クライアント ID が未設定です")
+	}
+	form := url.Values{"client_id": {auth.clientID}, "scope": {DriveScope}}
+	var body struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURL string `json:"verification_url"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	if err := auth.postForm(ctx, auth.deviceCodeURL, form, &body); err != nil {
+		return DriveDeviceCode{}, err
+	}
+	if body.DeviceCode == "" || body.UserCode == "" {
+		return DriveDeviceCode{}, errors.New("デバイスコードの応答が不正です")
+	}
+	interval := time.Duration(body.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return DriveDeviceCode{
+		DeviceCode:      body.DeviceCode,
+		UserCode:        body.UserCode,
+		VerificationURL: body.VerificationURL,
+		ExpiresAt:       auth.now().Add(time.Duration(body.ExpiresIn) * time.Second),
+		Interval:        interval,
+	}, nil
+}
+
+// PollToken はデバイスコードの認可が済んだかを1回確かめる。
+// 未完了なら ErrDriveAuthorizationPending / ErrDriveSlowDown、拒否・期限切れならそれぞれのエラーを返す。
+func (auth *DriveAuth) PollToken(ctx context.Context, deviceCode string) (DriveToken, error) {
+	form := url.Values{
+		"client_id":     {auth.clientID},
+		"client_secret": {auth.clientSecret},
+		"device_code":   {deviceCode},
+		"grant_type":    {driveDeviceGrantType},
+	}
+	return auth.requestToken(ctx, form)
+}
+
+// WaitForToken は認可が済むまで code.Interval ごとに PollToken を繰り返す。期限切れ・拒否・ctx の終了で止まる。
+func (auth *DriveAuth) WaitForToken(ctx context.Context, code DriveDeviceCode) (DriveToken, error) {
+	interval := code.Interval
+	for {
+		token, err := auth.PollToken(ctx, code.DeviceCode)
+		switch {
+		case err == nil:
+			return token, nil
+		case errors.Is(err, ErrDriveSlowDown):
+			interval += 5 * time.Second
+		case !errors.Is(err, ErrDriveAuthorizationPending):
+			return DriveToken{}, err
+		}
+		if !code.ExpiresAt.IsZero() && auth.now().Add(interval).After(code.ExpiresAt) {
+			return DriveToken{}, ErrDriveAuthorizationExpired
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return DriveToken{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Refresh はリフレッシュトークンから新しいアクセストークンを取得する。
+func (auth *DriveAuth) Refresh(ctx context.Context, refreshToken string) (DriveToken, error) {
+	form := url.Values{
+		"client_id":     {auth.clientID},
+		"client_secret": {auth.clientSecret},
+		"refresh_token": {refreshToken},
+		"grant_type":    {"refresh_token"},
+	}
+	return auth.requestToken(ctx, form)
+}
+
+func (auth *DriveAuth) requestToken(ctx context.Context, form url.Values) (DriveToken, error) {
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := auth.postForm(ctx, auth.tokenURL, form, &body); err != nil {
+		var oauthErr *driveOAuthError
+		if errors.As(err, &oauthErr) {
+			switch oauthErr.Code {
+			case "authorization_pending":
+				return DriveToken{}, ErrDriveAuthorizationPending
+			case "slow_down":
+				return DriveToken{}, ErrDriveSlowDown
+			case "access_denied":
+				return DriveToken{}, ErrDriveAuthorizationDenied
+			case "expired_token":
+				return DriveToken{}, ErrDriveAuthorizationExpired
+			}
+		}
+		return DriveToken{}, err
+	}
+	if body.AccessToken == "" {
+		return DriveToken{}, errors.New("アクセストークンの応答が不正です")
+	}
+	return DriveToken{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		ExpiresAt:    auth.now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// driveOAuthError は OAuth エンドポイントが返したエラー（RFC 6749 の error / error_description）。
+type driveOAuthError struct {
+	StatusCode  int
+	Code        string
+	Description string
+}
+
+func (err *driveOAuthError) Error() string {
+	if err.Description != "" {
+		return fmt.Sprintf("OAuth エラー %s: %s", err.Code, err.Description)
+	}
+	return fmt.Sprintf("OAuth エラー %s (HTTP %d)", err.Code, err.StatusCode)
+}
+
+// HTTPStatusCode は応答の HTTP ステータスを返す（IsUnreachableError で到達済みと判定するため）。
+func (err *driveOAuthError) HTTPStatusCode() int {
+	return err.StatusCode
+}
+
+func (auth *DriveAuth) postForm(ctx context.Context, endpoint string, form url.Values, out any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	response, err := auth.httpClient.Load().Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		oauthErr := &driveOAuthError{StatusCode: response.StatusCode}
+		var body struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if json.NewDecoder(response.Body).Decode(&body) == nil {
+			oauthErr.Code = body.Error
+			oauthErr.Description = body.ErrorDescription
+		}
+		return oauthErr
+	}
+	return json.NewDecoder(response.Body).Decode(out)
+}
+
+// DriveTokenSource はリフレッシュトークンからアクセストークンを取得し、期限が切れるまで使い回す。
+type DriveTokenSource struct {
+	auth         *DriveAuth
+	refreshToken string
+
+	mu    sync.Mutex
+	token DriveToken
+}
+
+// NewDriveTokenSource は DriveTokenSource を生成する。
+func NewDriveTokenSource(auth *DriveAuth, refreshToken string) *DriveTokenSource {
+	return &DriveTokenSource{auth: auth, refreshToken: refreshToken}
+}
+
+// RefreshToken は元にしたリフレッシュトークンを返す。
+func (source *DriveTokenSource) RefreshToken() string {
+	return source.refreshToken
+}
+
+// AccessToken は有効なアクセストークンを返す。未取得・期限切れ間近なら取り直す。
+func (source *DriveTokenSource) AccessToken(ctx context.Context) (string, error) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if source.token.AccessToken != "" && source.auth.now().Add(driveTokenMargin).Before(source.token.ExpiresAt) {
+		return source.token.AccessToken, nil
+	}
+	token, err := source.auth.Refresh(ctx, source.refreshToken)
+	if err != nil {
+		return "", fmt.Errorf("Google ドライブのアクセストークンを更新できません: %w", err)
+	}
+	source.token = token
+	return token.AccessToken, nil
+}
+
+// Invalidate は取得済みのアクセストークンを捨てる（401 を受けたときに次回取り直すため）。
+func (source *DriveTokenSource) Invalidate() {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.token = DriveToken{}
+}
@@ -0,0 +1,515 @@
+// Google ドライブのフォルダを同期先にするバックエンドを提供する。
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	driveAPIBaseURL    = "https://www.googleapis.com/drive/v3"
+	driveUploadBaseURL = "https://www.googleapis.com/upload/drive/v3"
+	driveFolderMIME    = "application/vnd.google-apps.folder"
+	driveFileFields    = "id,name,mimeType,size,modifiedTime"
+	// DriveRootFolderName はマイドライブ直下に作る同期データのフォルダ名。
+	DriveRootFolderName = "CloudLaunch"
+)
+
+// DriveAPIError は Drive API が返したエラー応答。404 は IsNotFoundError で判定できる。
+type DriveAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (err *DriveAPIError) Error() string {
+	if err.Message != "" {
+		return fmt.Sprintf("Google ドライブ API エラー (HTTP %d): %s", err.StatusCode, err.Message)
+	}
+	return fmt.Sprintf("Google ドライブ API エラー (HTTP %d)", err.StatusCode)
+}
+
+// HTTPStatusCode は応答の HTTP ステータスを返す。
+func (err *DriveAPIError) HTTPStatusCode() int {
+	return err.StatusCode
+}
+
+// Is は 404 を fs.ErrNotExist として扱う。
+func (err *DriveAPIError) Is(target error) bool {
+	return target == fs.ErrNotExist && err.StatusCode == http.StatusNotFound
+}
+
+type driveFile struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	MimeType     string `json:"mimeType"`
+	Size         int64  `json:"size,string"`
+	ModifiedTime string `json:"modifiedTime"`
+}
+
+func (file driveFile) isFolder() bool {
+	return file.MimeType == driveFolderMIME
+}
+
+// DriveObjectStore は Google ドライブの DriveRootFolderName フォルダ配下をオブジェクトとして扱う ObjectStore。
+// キーの "/" 区切りをそのままフォルダの階層にし、"games/<id>/HEAD" は CloudLaunch/games/<id>/HEAD というファイルになる。
+// drive.file スコープのため、アプリが作成したフォルダ・ファイルだけを読み書きする。
+type DriveObjectStore struct {
+	tokens     *DriveTokenSource
+	httpClient atomic.Pointer[http.Client]
+	apiURL     string
+	uploadURL  string
+
+	// folderMu はフォルダの作成を直列化する（並列アップロードで同名フォルダが重複して作られないように）。
+	folderMu sync.Mutex
+	mu       sync.Mutex
+	folders  map[string]string // キーのディレクトリ部分（"" はルート）→ フォルダID
+}
+
+// NewDriveObjectStore は tokens で認可した DriveObjectStore を生成する。httpClient が nil なら http.DefaultClient を使う。
+func NewDriveObjectStore(tokens *DriveTokenSource, httpClient *http.Client) *DriveObjectStore {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	store := &DriveObjectStore{
+		tokens:    tokens,
+		apiURL:    driveAPIBaseURL,
+		uploadURL: driveUploadBaseURL,
+		folders:   make(map[string]string),
+	}
+	store.httpClient.Store(httpClient)
+	return store
+}
+
+// SetHTTPClient は以後の Drive API 呼び出しに使う HTTP クライアントを差し替える。nil なら http.DefaultClient を使う。
+func (store *DriveObjectStore) SetHTTPClient(httpClient *http.Client) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	store.httpClient.Store(httpClient)
+}
+
+// RootFolderID は同期データのルートフォルダの ID を返す。無ければ作成する。
+func (store *DriveObjectStore) RootFolderID(ctx context.Context) (string, error) {
+	return store.folderID(ctx, "", true)
+}
+
+// ListObjects は prefix で始まるキーのファイルをキー順に返す。
+func (store *DriveObjectStore) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	// prefix の最後の "/" までをフォルダとして辿り、残りはキーの前方一致で絞る。
+	dir := path.Dir(prefix + "x")
+	if dir == "." {
+		dir = ""
+	}
+	startID, err := store.folderID(ctx, dir, false)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]ObjectInfo, 0)
+	if startID == "" {
+		return objects, nil
+	}
+	type pending struct{ id, dir string }
+	queue := []pending{{id: startID, dir: dir}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		children, err := store.listChildren(ctx, current.id, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			key := joinDriveKey(current.dir, child.Name)
+			if child.isFolder() {
+				store.rememberFolder(key, child.ID)
+				queue = append(queue, pending{id: child.ID, dir: key})
+				continue
+			}
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			object := ObjectInfo{Key: key, Size: child.Size}
+			if modified, err := time.Parse(time.RFC3339, child.ModifiedTime); err == nil {
+				object.LastModified = modified.UnixMilli()
+			}
+			objects = append(objects, object)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// Upload は key のファイルを payload で置き換える。無ければ親フォルダを作ってから作成する。
+func (store *DriveObjectStore) Upload(ctx context.Context, key string, payload []byte, contentType string) error {
+	dir, name, err := splitDriveKey(key)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	existing, err := store.findFile(ctx, dir, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		endpoint := store.uploadURL + "/files/" + url.PathEscape(existing.ID) + "?uploadType=media"
+		return store.do(ctx, http.MethodPatch, endpoint, contentType, payload, nil)
+	}
+	parentID, err := store.folderID(ctx, dir, true)
+	if err != nil {
+		return err
+	}
+	body, multipartType, err := driveMultipartBody(map[string]any{"name": name, "parents": []string{parentID}}, contentType, payload)
+	if err != nil {
+		return err
+	}
+	err = store.do(ctx, http.MethodPost, store.uploadURL+"/files?uploadType=multipart", multipartType, body, nil)
+	if IsNotFoundError(err) {
+		// ドライブ上でフォルダが消されていた場合に備え、フォルダIDを取り直して1回だけやり直す。
+		store.forgetFolders()
+		parentID, err = store.folderID(ctx, dir, true)
+		if err != nil {
+			return err
+		}
+		body, multipartType, err = driveMultipartBody(map[string]any{"name": name, "parents": []string{parentID}}, contentType, payload)
+		if err != nil {
+			return err
+		}
+		err = store.do(ctx, http.MethodPost, store.uploadURL+"/files?uploadType=multipart", multipartType, body, nil)
+	}
+	return err
+}
+
+// Download は key のファイルを取得する。無ければ IsNotFoundError が true になるエラーを返す。
+func (store *DriveObjectStore) Download(ctx context.Context, key string) ([]byte, error) {
+	dir, name, err := splitDriveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := store.findFile(ctx, dir, name)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%w: %s", fs.ErrNotExist, key)
+	}
+	var buffer bytes.Buffer
+	if err := store.do(ctx, http.MethodGet, store.apiURL+"/files/"+url.PathEscape(file.ID)+"?alt=media", "", nil, &buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Exists は key のファイルがあるかを返す。
+func (store *DriveObjectStore) Exists(ctx context.Context, key string) (bool, error) {
+	dir, name, err := splitDriveKey(key)
+	if err != nil {
+		return false, err
+	}
+	file, err := store.findFile(ctx, dir, name)
+	if err != nil {
+		return false, err
+	}
+	return file != nil, nil
+}
+
+// Delete は key のファイルを削除する。無ければ何もしない（S3 と同じ）。フォルダは残す。
+func (store *DriveObjectStore) Delete(ctx context.Context, key string) error {
+	dir, name, err := splitDriveKey(key)
+	if err != nil {
+		return err
+	}
+	file, err := store.findFile(ctx, dir, name)
+	if err != nil || file == nil {
+		return err
+	}
+	err = store.do(ctx, http.MethodDelete, store.apiURL+"/files/"+url.PathEscape(file.ID), "", nil, nil)
+	if IsNotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteByPrefix は prefix で始まるキーのファイルをすべて削除する。
+func (store *DriveObjectStore) DeleteByPrefix(ctx context.Context, prefix string) error {
+	objects, err := store.ListObjects(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, object := range objects {
+		if err := store.Delete(ctx, object.Key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// findFile は dir フォルダ内の name のファイルを返す。フォルダかファイルが無ければ nil を返す。
+func (store *DriveObjectStore) findFile(ctx context.Context, dir, name string) (*driveFile, error) {
+	parentID, err := store.folderID(ctx, dir, false)
+	if err != nil || parentID == "" {
+		return nil, err
+	}
+	children, err := store.listChildren(ctx, parentID, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if !child.isFolder() {
+			return &child, nil
+		}
+	}
+	return nil, nil
+}
+
+// folderID はキーのディレクトリ部分 dir に対応するフォルダの ID を返す。
+// create が false で途中のフォルダが無ければ空文字を返し、true なら足りないフォルダを作る。
+func (store *DriveObjectStore) folderID(ctx context.Context, dir string, create bool) (string, error) {
+	if id := store.cachedFolder(dir); id != "" {
+		return id, nil
+	}
+	if create {
+		store.folderMu.Lock()
+		defer store.folderMu.Unlock()
+		if id := store.cachedFolder(dir); id != "" {
+			return id, nil
+		}
+	}
+	parentID := "root"
+	current := ""
+	names := []string{DriveRootFolderName}
+	if dir != "" {
+		names = append(names, strings.Split(dir, "/")...)
+	}
+	for i, name := range names {
+		if i > 0 {
+			current = joinDriveKey(current, name)
+		}
+		if id := store.cachedFolder(current); id != "" {
+			parentID = id
+			continue
+		}
+		id, err := store.findFolder(ctx, parentID, name)
+		if err != nil {
+			return "", err
+		}
+		if id == "" {
+			if !create {
+				return "", nil
+			}
+			if id, err = store.createFolder(ctx, parentID, name); err != nil {
+				return "", err
+			}
+		}
+		store.rememberFolder(current, id)
+		parentID = id
+	}
+	return parentID, nil
+}
+
+func (store *DriveObjectStore) findFolder(ctx context.Context, parentID, name string) (string, error) {
+	children, err := store.listChildren(ctx, parentID, name)
+	if err != nil {
+		return "", err
+	}
+	for _, child := range children {
+		if child.isFolder() {
+			return child.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (store *DriveObjectStore) createFolder(ctx context.Context, parentID, name string) (string, error) {
+	payload, err := json.Marshal(map[string]any{"name": name, "mimeType": driveFolderMIME, "parents": []string{parentID}})
+	if err != nil {
+		return "", err
+	}
+	var created driveFile
+	if err := store.do(ctx, http.MethodPost, store.apiURL+"/files?fields=id", "application/json", payload, &created); err != nil {
+		return "", err
+	}
+	if created.ID == "" {
+		return "", errors.New("作成したフォルダの ID を取得できません")
+	}
+	return created.ID, nil
+}
+
+// listChildren はフォルダ直下のファイル・フォルダを作成順に返す。name を指定すればその名前のものだけを返す。
+func (store *DriveObjectStore) listChildren(ctx context.Context, parentID, name string) ([]driveFile, error) {
+	query := fmt.Sprintf("'%s' in parents and trashed = false", escapeDriveQuery(parentID))
+	if name != "" {
+		query += fmt.Sprintf(" and name = '%s'", escapeDriveQuery(name))
+	}
+	files := make([]driveFile, 0)
+	pageToken := ""
+	for {
+		params := url.Values{
+			"q":        {query},
+			"fields":   {"nextPageToken,files(" + driveFileFields + ")"},
+			"orderBy":  {"createdTime"},
+			"pageSize": {"1000"},
+			"spaces":   {"drive"},
+		}
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+		var page struct {
+			NextPageToken string      `json:"nextPageToken"`
+			Files         []driveFile `json:"files"`
+		}
+		if err := store.do(ctx, http.MethodGet, store.apiURL+"/files?"+params.Encode(), "", nil, &page); err != nil {
+			return nil, err
+		}
+		files = append(files, page.Files...)
+		if page.NextPageToken == "" {
+			return files, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// do はアクセストークンを付けて Drive API を呼ぶ。out が *bytes.Buffer なら本文をそのまま、それ以外は JSON として読む。
+// 401 を受けたらアクセストークンを取り直して1回だけやり直す。
+func (store *DriveObjectStore) do(ctx context.Context, method, endpoint, contentType string, payload []byte, out any) error {
+	for attempt := 0; ; attempt++ {
+		token, err := store.tokens.AccessToken(ctx)
+		if err != nil {
+			return err
+		}
+		var body io.Reader
+		if payload != nil {
+			body = bytes.NewReader(payload)
+		}
+		request, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+		response, err := store.httpClient.Load().Do(request)
+		if err != nil {
+			return err
+		}
+		if response.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			response.Body.Close()
+			store.tokens.Invalidate()
+			continue
+		}
+		err = readDriveResponse(response, out)
+		response.Body.Close()
+		return err
+	}
+}
+
+func readDriveResponse(response *http.Response, out any) error {
+	if response.StatusCode >= 300 {
+		apiErr := &DriveAPIError{StatusCode: response.StatusCode}
+		var body struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(response.Body).Decode(&body) == nil {
+			apiErr.Message = body.Error.Message
+		}
+		return apiErr
+	}
+	switch target := out.(type) {
+	case nil:
+		_, err := io.Copy(io.Discard, response.Body)
+		return err
+	case *bytes.Buffer:
+		_, err := target.ReadFrom(response.Body)
+		return err
+	default:
+		return json.NewDecoder(response.Body).Decode(out)
+	}
+}
+
+// driveMultipartBody はメタデータと本文を multipart/related にまとめ、本文と Content-Type を返す。
+func driveMultipartBody(metadata map[string]any, contentType string, payload []byte) ([]byte, string, error) {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, "", err
+	}
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+	metaPart, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := metaPart.Write(metadataJSON); err != nil {
+		return nil, "", err
+	}
+	dataPart, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := dataPart.Write(payload); err != nil {
+		return nil, "", err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buffer.Bytes(), "multipart/related; boundary=" + writer.Boundary(), nil
+}
+
+func (store *DriveObjectStore) cachedFolder(dir string) string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.folders[dir]
+}
+
+func (store *DriveObjectStore) rememberFolder(dir, id string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.folders[dir] = id
+}
+
+func (store *DriveObjectStore) forgetFolders() {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.folders = make(map[string]string)
+}
+
+// splitDriveKey はキーをディレクトリ部分とファイル名に分ける。空の階層や "." / ".." を含むキーは拒否する。
+func splitDriveKey(key string) (string, string, error) {
+	segments := strings.Split(key, "/")
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", "", fmt.Errorf("不正なキーです: %s", key)
+		}
+	}
+	return strings.Join(segments[:len(segments)-1], "/"), segments[len(segments)-1], nil
+}
+
+func joinDriveKey(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// escapeDriveQuery は Drive の検索クエリの文字列リテラル用に "\" と "'" をエスケープする。
+func escapeDriveQuery(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+}
@@ -0,0 +1,309 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDrive は Drive API の files エンドポイントと OAuth のトークンエンドポイントの最小限の実装。
+type fakeDrive struct {
+	mu       sync.Mutex
+	nextID   int
+	files    map[string]*fakeDriveFile
+	order    []string
+	tokens   int
+	pending  int
+	expireAt int // この回数目のトークン発行後、最初の API 呼び出しを 401 にする
+	rejected bool
+}
+
+type fakeDriveFile struct {
+	id, name, parent, mimeType string
+	data                       []byte
+}
+
+var fakeDriveQuery = regexp.MustCompile(`^'([^']*)' in parents and trashed = false(?: and name = '((?:[^'\\]|\\.)*)')?$`)
+
+func newFakeDrive(t *testing.T) (*fakeDrive, *httptest.Server) {
+	t.Helper()
+	drive := &fakeDrive{files: make(map[string]*fakeDriveFile)}
+	server := httptest.NewServer(http.HandlerFunc(drive.serve))
+	t.Cleanup(server.Close)
+	return drive, server
+}
+
+func (drive *fakeDrive) serve(w http.ResponseWriter, r *http.Request) {
+	drive.mu.Lock()
+	defer drive.mu.Unlock()
+	if r.URL.Path == "/token" {
+		drive.serveToken(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer access-%d", drive.tokens) || (drive.expireAt == drive.tokens && !drive.rejected) {
+		drive.rejected = drive.expireAt == drive.tokens
+		http.Error(w, `{"error":{"message":"invalid token"}}`, http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/drive/v3/files":
+		drive.serveList(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/drive/v3/files":
+		var meta struct {
+			Name     string   `json:"name"`
+			MimeType string   `json:"mimeType"`
+			Parents  []string `json:"parents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&meta)
+		id := drive.create(meta.Name, meta.Parents[0], meta.MimeType, nil)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": id})
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/drive/v3/files":
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		metaPart, _ := reader.NextPart()
+		var meta struct {
+			Name    string   `json:"name"`
+			Parents []string `json:"parents"`
+		}
+		_ = json.NewDecoder(metaPart).Decode(&meta)
+		dataPart, _ := reader.NextPart()
+		data, _ := io.ReadAll(dataPart)
+		if _, ok := drive.files[meta.Parents[0]]; !ok {
+			http.Error(w, `{"error":{"message":"parent not found"}}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": drive.create(meta.Name, meta.Parents[0], "application/octet-stream", data)})
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/upload/drive/v3/files/"):
+		file := drive.files[strings.TrimPrefix(r.URL.Path, "/upload/drive/v3/files/")]
+		if file == nil {
+			http.NotFound(w, r)
+			return
+		}
+		file.data, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{}`))
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/drive/v3/files/"):
+		file := drive.files[strings.TrimPrefix(r.URL.Path, "/drive/v3/files/")]
+		if file == nil || r.URL.Query().Get("alt") != "media" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(file.data)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/drive/v3/files/"):
+		id := strings.TrimPrefix(r.URL.Path, "/drive/v3/files/")
+		if drive.files[id] == nil {
+			http.NotFound(w, r)
+			return
+		}
+		delete(drive.files, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func (drive *fakeDrive) serveToken(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.PostForm.Get("grant_type") == driveDeviceGrantType && drive.pending > 0 {
+		drive.pending--
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+		return
+	}
+	drive.tokens++
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token":  fmt.Sprintf("access-%d", drive.tokens),
+		"refresh_token": "refresh",
+		"expires_in":    3600,
+	})
+}
+
+func (drive *fakeDrive) serveList(w http.ResponseWriter, r *http.Request) {
+	matches := fakeDriveQuery.FindStringSubmatch(r.URL.Query().Get("q"))
+	if matches == nil {
+		http.Error(w, "bad query", http.StatusBadRequest)
+		return
+	}
+	parent, name := matches[1], strings.ReplaceAll(matches[2], `\'`, `'`)
+	if parent == "root" {
+		parent = ""
+	}
+	files := make([]map[string]string, 0)
+	for _, id := range drive.order {
+		file := drive.files[id]
+		if file == nil || file.parent != parent || (matches[2] != "" && file.name != name) {
+			continue
+		}
+		files = append(files, map[string]string{
+			"id": file.id, "name": file.name, "mimeType": file.mimeType,
+			"size": fmt.Sprint(len(file.data)), "modifiedTime": "2026-10-01T12:00:00Z",
+		})
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"files": files})
+}
+
+func (drive *fakeDrive) create(name, parent, mimeType string, data []byte) string {
+	if parent == "root" {
+		parent = ""
+	}
+	drive.nextID++
+	id := fmt.Sprintf("id%d", drive.nextID)
+	drive.files[id] = &fakeDriveFile{id: id, name: name, parent: parent, mimeType: mimeType, data: data}
+	drive.order = append(drive.order, id)
+	return id
+}
+
+func (drive *fakeDrive) countNamed(name string) int {
+	drive.mu.Lock()
+	defer drive.mu.Unlock()
+	count := 0
+	for _, file := range drive.files {
+		if file.name == name {
+			count++
+		}
+	}
+	return count
+}
+
+func newTestDriveStore(server *httptest.Server) *DriveObjectStore {
+	auth := NewDriveAuth("client", "secret", server.Client())
+	auth.tokenURL = server.URL + "/token"
+	auth.deviceCodeURL = server.URL + "/device"
+	store := NewDriveObjectStore(NewDriveTokenSource(auth, "refresh"), server.Client())
+	store.apiURL = server.URL + "/drive/v3"
+	store.uploadURL = server.URL + "/upload/drive/v3"
+	return store
+}
+
+func TestDriveObjectStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	drive, server := newFakeDrive(t)
+	store := newTestDriveStore(server)
+
+	if head, err := ReadHEAD(ctx, store, "g1"); err != nil || head != "" {
+		t.Fatalf("missing HEAD should read as empty: %q, %v", head, err)
+	}
+	if err := WriteHEAD(ctx, store, "g1", "abc"); err != nil {
+		t.Fatalf("WriteHEAD: %v", err)
+	}
+	if err := WriteHEAD(ctx, store, "g1", "def"); err != nil {
+		t.Fatalf("WriteHEAD overwrite: %v", err)
+	}
+	if head, err := ReadHEAD(ctx, store, "g1"); err != nil || head != "def" {
+		t.Fatalf("ReadHEAD = %q, %v", head, err)
+	}
+	if count := drive.countNamed("HEAD"); count != 1 {
+		t.Fatalf("overwriting should update the existing file, found %d", count)
+	}
+
+	if err := store.Upload(ctx, "games/g1/objects/it's", []byte("quoted"), ""); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if data, err := store.Download(ctx, "games/g1/objects/it's"); err != nil || string(data) != "quoted" {
+		t.Fatalf("Download = %q, %v", data, err)
+	}
+	if _, err := store.Download(ctx, "games/g1/objects/none"); !IsNotFoundError(err) {
+		t.Fatalf("missing object should be a not-found error, got %v", err)
+	}
+	if err := store.Upload(ctx, "games/../x", []byte("x"), ""); err == nil {
+		t.Fatal("keys with .. should be rejected")
+	}
+	if count := drive.countNamed("games"); count != 1 {
+		t.Fatalf("folders should be reused, found %d", count)
+	}
+}
+
+func TestDriveObjectStoreListAndDeleteByPrefix(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, server := newFakeDrive(t)
+	store := newTestDriveStore(server)
+	for _, key := range []string{"games/g1/HEAD", "games/g1/objects/a", "games/g10/HEAD", "encryption.json"} {
+		if err := store.Upload(ctx, key, []byte(key), ""); err != nil {
+			t.Fatalf("Upload %s: %v", key, err)
+		}
+	}
+
+	// 別のインスタンス（フォルダIDのキャッシュ無し）からも一覧できる。
+	fresh := newTestDriveStore(server)
+	objects, err := fresh.ListObjects(ctx, "games/g1")
+	if err != nil {
+		t.Fatalf("ListObjects: %v", err)
+	}
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	if strings.Join(keys, ",") != "games/g1/HEAD,games/g1/objects/a,games/g10/HEAD" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+	if objects[0].Size != int64(len("games/g1/HEAD")) || objects[0].LastModified == 0 {
+		t.Fatalf("unexpected object info: %+v", objects[0])
+	}
+	if missing, err := fresh.ListObjects(ctx, "nothing/"); err != nil || len(missing) != 0 {
+		t.Fatalf("missing folder should list nothing: %v %v", missing, err)
+	}
+
+	if err := fresh.DeleteByPrefix(ctx, "games/g1/"); err != nil {
+		t.Fatalf("DeleteByPrefix: %v", err)
+	}
+	remaining, err := store.ListObjects(ctx, "")
+	if err != nil || len(remaining) != 2 {
+		t.Fatalf("other games should be kept: %+v %v", remaining, err)
+	}
+	if err := store.Delete(ctx, "games/g1/HEAD"); err != nil {
+		t.Fatalf("deleting a missing object should succeed: %v", err)
+	}
+}
+
+func TestDriveObjectStoreRefreshesRejectedToken(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	drive, server := newFakeDrive(t)
+	drive.expireAt = 1
+	store := newTestDriveStore(server)
+	if exists, err := store.Exists(ctx, "games/g1/HEAD"); err != nil || exists {
+		t.Fatalf("Exists = %v, %v", exists, err)
+	}
+	if drive.tokens != 2 {
+		t.Fatalf("a rejected access token should be refreshed once, issued %d", drive.tokens)
+	}
+}
+
+func TestDriveAuthWaitForToken(t *testing.T) {
+	t.Parallel()
+
+	drive, server := newFakeDrive(t)
+	drive.pending = 2
+	auth := NewDriveAuth("client", "secret", server.Client())
+	auth.tokenURL = server.URL + "/token"
+	code := DriveDeviceCode{DeviceCode: "device", Interval: time.Millisecond, ExpiresAt: time.Now().Add(time.Minute)}
+	token, err := auth.WaitForToken(context.Background(), code)
+	if err != nil {
+		t.Fatalf("WaitForToken: %v", err)
+	}
+	if token.RefreshToken != "refresh" || token.AccessToken == "" {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+
+	drive.mu.Lock()
+	drive.pending = 100
+	drive.mu.Unlock()
+	code.ExpiresAt = time.Now().Add(5 * time.Millisecond)
+	if _, err := auth.WaitForToken(context.Background(), code); err != ErrDriveAuthorizationExpired {
+		t.Fatalf("expired device code should stop polling, got %v", err)
+	}
+}
@@ -1,17 +1,23 @@
 package storage
 
 import (
+	"context"
 	"errors"
+	"io/fs"
+	"net"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 )
 
-// IsNotFoundError はS3のNotFound系エラーかどうかを判定する。
+// IsNotFoundError はS3のNotFound系エラー（ローカルバックエンドではファイルが無いエラー）かどうかを判定する。
 func IsNotFoundError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
 	var apiErr smithy.APIError
 	if errors.As(err, &apiErr) {
 		code := apiErr.ErrorCode()
@@ -22,3 +28,20 @@ func IsNotFoundError(err error) bool {
 	var noSuchKey *types.NoSuchKey
 	return errors.As(err, &noSuchKey)
 }
+
+// IsUnreachableError はエンドポイントから応答を得られなかった（通信できなかった）エラーかを判定する。
+// 403 や 404 などHTTP応答が返ったエラーは到達できているので false を返す。
+func IsUnreachableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) && statusErr.HTTPStatusCode() > 0 {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func TestIsUnreachableError(t *testing.T) {
+	t.Parallel()
+
+	responded := &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+		Err:      errors.New("AccessDenied"),
+	}
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "dial", err: fmt.Errorf("send: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), want: true},
+		{name: "timeout", err: fmt.Errorf("send: %w", context.DeadlineExceeded), want: true},
+		{name: "http response", err: fmt.Errorf("operation: %w", responded), want: false},
+		{name: "other", err: errors.New("invalid config"), want: false},
+	}
+	for _, tc := range cases {
+		if got := IsUnreachableError(tc.err); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// ゲームごとのリモートHEAD履歴（メタデータスナップショット）の読み書きを提供する。
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HeadHistoryIDLayout は履歴エントリIDの時刻フォーマット。
+// 辞書順ソートがそのまま時系列順になるよう、桁固定の UTC 表記にする。
+const HeadHistoryIDLayout = "20060102T150405.000Z"
+
+// HeadHistoryEntry は HEAD 書き換え前に退避したコミットハッシュ1件を表す。
+type HeadHistoryEntry struct {
+	ID         string    `json:"id"`
+	Head       string    `json:"head"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+func headHistoryPrefix(gameID string) string {
+	return fmt.Sprintf("games/%s/metadata_history/", gameID)
+}
+
+func headHistoryKey(gameID, id string) string {
+	return headHistoryPrefix(gameID) + id + ".json"
+}
+
+// ValidateHeadHistoryID は履歴IDが HeadHistoryIDLayout 形式かを検証する。
+// ID はキーに埋め込まれるため、任意文字列で別オブジェクトを指せないようにする。
+func ValidateHeadHistoryID(id string) error {
+	if _, err := time.Parse(HeadHistoryIDLayout, id); err != nil {
+		return fmt.Errorf("invalid history id: %s", id)
+	}
+	return nil
+}
+
+// WriteHeadHistory は履歴エントリを書き込む。
+func WriteHeadHistory(ctx context.Context, store ObjectStore, gameID string, entry HeadHistoryEntry) error {
+	if err := ValidateHeadHistoryID(entry.ID); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return store.Upload(ctx, headHistoryKey(gameID, entry.ID), payload, "application/json")
+}
+
+// ReadHeadHistory は履歴エントリを取得する。存在しない場合は nil を返す。
+func ReadHeadHistory(ctx context.Context, store ObjectStore, gameID, id string) (*HeadHistoryEntry, error) {
+	if err := ValidateHeadHistoryID(id); err != nil {
+		return nil, err
+	}
+	data, err := store.Download(ctx, headHistoryKey(gameID, id))
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entry HeadHistoryEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	entry.ID = id
+	return &entry, nil
+}
+
+// ListHeadHistoryIDs は履歴エントリIDを古い順に返す。
+func ListHeadHistoryIDs(ctx context.Context, store ObjectStore, gameID string) ([]string, error) {
+	prefix := headHistoryPrefix(gameID)
+	objects, err := store.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		id := strings.TrimSuffix(strings.TrimPrefix(obj.Key, prefix), ".json")
+		if ValidateHeadHistoryID(id) != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// DeleteHeadHistory は履歴エントリを削除する。
+func DeleteHeadHistory(ctx context.Context, store ObjectStore, gameID, id string) error {
+	if err := ValidateHeadHistoryID(id); err != nil {
+		return err
+	}
+	return store.Delete(ctx, headHistoryKey(gameID, id))
+}
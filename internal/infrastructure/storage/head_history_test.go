@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateHeadHistoryIDAcceptsLayout(t *testing.T) {
+	t.Parallel()
+
+	id := time.Date(2026, 1, 2, 3, 4, 5, 6_000_000, time.UTC).Format(HeadHistoryIDLayout)
+	if err := ValidateHeadHistoryID(id); err != nil {
+		t.Fatalf("ValidateHeadHistoryID(%q) returned error: %v", id, err)
+	}
+}
+
+func TestValidateHeadHistoryIDRejectsOtherKeys(t *testing.T) {
+	t.Parallel()
+
+	cases := []string{"", "HEAD", "../HEAD", "20260102T030405.006Z/../../HEAD"}
+	for _, id := range cases {
+		if err := ValidateHeadHistoryID(id); err == nil {
+			t.Errorf("ValidateHeadHistoryID(%q) should fail", id)
+		}
+	}
+}
@@ -5,22 +5,20 @@ import (
 	"context"
 	"fmt"
 	"strings"
-
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
 func headKey(gameID string) string {
 	return fmt.Sprintf("games/%s/HEAD", gameID)
 }
 
-// WriteHEAD はリモートHEADをS3に書き込む。
-func WriteHEAD(ctx context.Context, client *s3.Client, bucket, gameID, hash string) error {
-	return UploadBytes(ctx, client, bucket, headKey(gameID), []byte(hash), "text/plain")
+// WriteHEAD はリモートHEADを書き込む。
+func WriteHEAD(ctx context.Context, store ObjectStore, gameID, hash string) error {
+	return store.Upload(ctx, headKey(gameID), []byte(hash), "text/plain")
 }
 
-// ReadHEAD はS3からリモートHEADを取得する。存在しない場合は "" を返す。
-func ReadHEAD(ctx context.Context, client *s3.Client, bucket, gameID string) (string, error) {
-	data, err := DownloadObject(ctx, client, bucket, headKey(gameID))
+// ReadHEAD はリモートHEADを取得する。存在しない場合は "" を返す。
+func ReadHEAD(ctx context.Context, store ObjectStore, gameID string) (string, error) {
+	data, err := store.Download(ctx, headKey(gameID))
 	if err != nil {
 		if IsNotFoundError(err) {
 			return "", nil
@@ -0,0 +1,261 @@
+// ローカルディレクトリ（NAS の共有フォルダやクラウドドライブの同期フォルダ等）を同期先にするバックエンドを提供する。
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// localTempSuffix は書き込み途中の一時ファイルに付ける拡張子。一覧には出さない。
+const localTempSuffix = ".cltmp"
+
+// LocalObjectStore はディレクトリ配下のファイルをオブジェクトとして扱う ObjectStore。
+// キー "games/<id>/HEAD" は <root>/games/<id>/HEAD に対応する。書き込みは一時ファイルからの置き換えで行い、
+// 他の端末（同じ共有フォルダを見る PC）が書き込み途中のファイルを読まないようにする。
+type LocalObjectStore struct {
+	root string
+}
+
+// NewLocalObjectStore は root を同期先にする LocalObjectStore を生成する。root は既存のディレクトリでなければならない。
+func NewLocalObjectStore(root string) (*LocalObjectStore, error) {
+	trimmed := strings.TrimSpace(root)
+	if trimmed == "" {
+		return nil, errors.New("同期先フォルダが未設定です")
+	}
+	absRoot, err := filepath.Abs(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("同期先フォルダにアクセスできません: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("同期先がフォルダではありません: %s", absRoot)
+	}
+	return &LocalObjectStore{root: absRoot}, nil
+}
+
+// Root は同期先フォルダの絶対パスを返す。
+func (store *LocalObjectStore) Root() string {
+	return store.root
+}
+
+func (store *LocalObjectStore) objectPath(key string) (string, error) {
+	return ResolveSafeRelativePath(store.root, key)
+}
+
+// ListObjects は prefix で始まるキーのファイルをキー順に返す。
+func (store *LocalObjectStore) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	// prefix の最後の "/" までをディレクトリとして辿り、残りはキーの前方一致で絞る。
+	walkRoot := store.root
+	if dir := path.Dir(prefix + "x"); dir != "." {
+		resolved, err := store.objectPath(dir)
+		if err != nil {
+			return nil, err
+		}
+		walkRoot = resolved
+	}
+	objects := make([]ObjectInfo, 0)
+	err := filepath.WalkDir(walkRoot, func(walkPath string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, fs.ErrNotExist) {
+				return nil
+			}
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), localTempSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(store.root, walkPath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime().UnixMilli()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// Upload は key のファイルを payload で置き換える。contentType は使わない。
+func (store *LocalObjectStore) Upload(ctx context.Context, key string, payload []byte, _ string) error {
+	target, tmpPath, err := store.writeTemp(ctx, key, payload)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// UploadIfAbsent は key のファイルが無いときだけ payload で作る。あれば ErrAlreadyExists を返す。
+// 書き終えた一時ファイルをハードリンクで置くため、同時に作ろうとしても片方だけが成功し、途中の内容も見えない。
+// SMB の共有・FAT/exFAT・クラウドドライブの同期フォルダなどハードリンクを作れない場所では、
+// 排他作成（O_EXCL）で直接書き込む。片方だけが成功するのは同じだが、書き込み途中の内容は見え得る。
+func (store *LocalObjectStore) UploadIfAbsent(ctx context.Context, key string, payload []byte, _ string) error {
+	target, tmpPath, err := store.writeTemp(ctx, key, payload)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	if err := os.Link(tmpPath, target); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return ErrAlreadyExists
+		}
+		return createExclusive(target, payload)
+	}
+	return nil
+}
+
+// createExclusive は target が無いときだけ作って payload を書き込む。あれば ErrAlreadyExists を返す。
+// 書き込みに失敗したら、中途半端な内容を残さないよう作ったファイルを消す。
+func createExclusive(target string, payload []byte) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+	_, err = file.Write(payload)
+	if syncErr := file.Sync(); syncErr != nil && err == nil {
+		err = syncErr
+	}
+	if closeErr := file.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(target)
+		return err
+	}
+	return nil
+}
+
+// writeTemp は key のファイルと同じフォルダの一時ファイルへ payload を書き、置き先と一時ファイルのパスを返す。
+func (store *LocalObjectStore) writeTemp(ctx context.Context, key string, payload []byte) (string, string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+	target, err := store.objectPath(key)
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return "", "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*"+localTempSuffix)
+	if err != nil {
+		return "", "", err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(payload)
+	if syncErr := tmp.Sync(); syncErr != nil && err == nil {
+		err = syncErr
+	}
+	if closeErr := tmp.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return "", "", err
+	}
+	return target, tmpPath, nil
+}
+
+// Download は key のファイルを読み込む。無ければ IsNotFoundError が true になるエラーを返す。
+func (store *LocalObjectStore) Download(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	target, err := store.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(target)
+}
+
+// Exists は key のファイルがあるかを返す。
+func (store *LocalObjectStore) Exists(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	target, err := store.objectPath(key)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(target)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !info.IsDir(), nil
+}
+
+// Delete は key のファイルを削除し、空になった親ディレクトリを取り除く。無ければ何もしない（S3 と同じ）。
+func (store *LocalObjectStore) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	target, err := store.objectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	store.pruneEmptyDirs(filepath.Dir(target))
+	return nil
+}
+
+// DeleteByPrefix は prefix で始まるキーのファイルをすべて削除する。
+func (store *LocalObjectStore) DeleteByPrefix(ctx context.Context, prefix string) error {
+	objects, err := store.ListObjects(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, object := range objects {
+		if err := store.Delete(ctx, object.Key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pruneEmptyDirs は dir から root の手前まで、空のディレクトリを取り除く。
+func (store *LocalObjectStore) pruneEmptyDirs(dir string) {
+	for current := dir; current != store.root && strings.HasPrefix(current, store.root); current = filepath.Dir(current) {
+		if err := os.Remove(current); err != nil {
+			return
+		}
+	}
+}
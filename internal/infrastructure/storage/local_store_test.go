@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalObjectStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewLocalObjectStore(root)
+	if err != nil {
+		t.Fatalf("NewLocalObjectStore: %v", err)
+	}
+	if err := WriteHEAD(ctx, store, "g1", "abc"); err != nil {
+		t.Fatalf("WriteHEAD: %v", err)
+	}
+	if head, err := ReadHEAD(ctx, store, "g1"); err != nil || head != "abc" {
+		t.Fatalf("ReadHEAD = %q, %v", head, err)
+	}
+	if head, err := ReadHEAD(ctx, store, "missing"); err != nil || head != "" {
+		t.Fatalf("missing HEAD should read as empty: %q, %v", head, err)
+	}
+
+	data := []byte("save data")
+	hash := blobHashBytes(data)
+	if err := PutBlob(ctx, store, nil, "g1", BlobKindObject, hash, data); err != nil {
+		t.Fatalf("PutBlob: %v", err)
+	}
+	if exists, err := store.Exists(ctx, blobKey("g1", BlobKindObject, hash)); err != nil || !exists {
+		t.Fatalf("blob should exist: %v %v", exists, err)
+	}
+	got, err := GetBlob(ctx, store, nil, "g1", BlobKindObject, hash)
+	if err != nil || string(got) != "save data" {
+		t.Fatalf("GetBlob = %q, %v", got, err)
+	}
+	hashes, err := ListBlobHashes(ctx, store, "g1")
+	if _, ok := hashes[hash]; err != nil || !ok || len(hashes) != 1 {
+		t.Fatalf("ListBlobHashes = %v, %v", hashes, err)
+	}
+
+	if _, err := store.Download(ctx, "games/g1/objects/none"); !IsNotFoundError(err) {
+		t.Fatalf("missing object should be a not-found error, got %v", err)
+	}
+	if err := store.Upload(ctx, "../escape", []byte("x"), ""); err == nil {
+		t.Fatal("keys escaping the root should be rejected")
+	}
+}
+
+func TestLocalObjectStoreListAndDeleteByPrefix(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewLocalObjectStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"games/g1/HEAD", "games/g1/objects/a", "games/g10/HEAD", "encryption.json"} {
+		if err := store.Upload(ctx, key, []byte(key), ""); err != nil {
+			t.Fatalf("Upload %s: %v", key, err)
+		}
+	}
+	// 書き込み途中の一時ファイルは一覧に出さない。
+	if err := os.WriteFile(filepath.Join(root, "games", "g1", ".HEAD.123"+localTempSuffix), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := store.ListObjects(ctx, "games/g1")
+	if err != nil {
+		t.Fatalf("ListObjects: %v", err)
+	}
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	if len(keys) != 3 || keys[0] != "games/g1/HEAD" || keys[1] != "games/g1/objects/a" || keys[2] != "games/g10/HEAD" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+	if objects[0].Size != int64(len("games/g1/HEAD")) || objects[0].LastModified == 0 {
+		t.Fatalf("unexpected object info: %+v", objects[0])
+	}
+
+	if err := store.DeleteByPrefix(ctx, "games/g1/"); err != nil {
+		t.Fatalf("DeleteByPrefix: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "games", "g1", "objects")); !os.IsNotExist(err) {
+		t.Fatalf("emptied directories should be removed, stat err: %v", err)
+	}
+	remaining, _ := store.ListObjects(ctx, "")
+	if len(remaining) != 2 {
+		t.Fatalf("other games should be kept: %+v", remaining)
+	}
+	if err := store.Delete(ctx, "games/g1/HEAD"); err != nil {
+		t.Fatalf("deleting a missing object should succeed: %v", err)
+	}
+}
+
+func TestNewLocalObjectStoreRequiresDirectory(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewLocalObjectStore(""); err == nil {
+		t.Fatal("empty root should be rejected")
+	}
+	if _, err := NewLocalObjectStore(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("missing root should be rejected")
+	}
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLocalObjectStore(file); err == nil {
+		t.Fatal("file root should be rejected")
+	}
+}
+
+func TestCreateExclusiveWritesOnlyWhenAbsent(t *testing.T) {
+	t.Parallel()
+
+	// ハードリンクを作れない同期先で UploadIfAbsent が使う排他作成。
+	target := filepath.Join(t.TempDir(), "encryption.json")
+	if err := createExclusive(target, []byte("first")); err != nil {
+		t.Fatalf("first createExclusive: %v", err)
+	}
+	if err := createExclusive(target, []byte("second")); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+	if data, err := os.ReadFile(target); err != nil || string(data) != "first" {
+		t.Fatalf("existing file should be kept: %q, %v", data, err)
+	}
+}
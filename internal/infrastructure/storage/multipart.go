@@ -0,0 +1,266 @@
+// 大きなオブジェクトのマルチパートアップロード（中断からの再開込み）を提供する。
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// MinMultipartPartSizeMB は S3 が許すパートサイズの下限（最終パートを除く）。
+	MinMultipartPartSizeMB = 5
+	// MaxMultipartPartSizeMB はパートをメモリに載せる前提での上限。
+	MaxMultipartPartSizeMB = 512
+	// DefaultMultipartPartSizeMB はパートサイズの既定値。
+	DefaultMultipartPartSizeMB = 16
+
+	maxMultipartParts    = 10000
+	multipartConcurrency = 4
+	bytesPerMB           = 1024 * 1024
+)
+
+// multipartPartSize は現在のパートサイズ（バイト）。UploadBytes の呼び出し元は多岐にわたるため、
+// 引数で引き回さずアプリ全体で1つの値を持ち、設定変更時に SetMultipartPartSizeMB で更新する。
+var multipartPartSize atomic.Int64
+
+func init() {
+	multipartPartSize.Store(DefaultMultipartPartSizeMB * bytesPerMB)
+}
+
+// ValidateMultipartPartSizeMB はパートサイズ（MB）が許容範囲かを検証する。
+func ValidateMultipartPartSizeMB(sizeMB int) error {
+	if sizeMB < MinMultipartPartSizeMB || sizeMB > MaxMultipartPartSizeMB {
+		return fmt.Errorf("multipart part size must be %d-%d MB", MinMultipartPartSizeMB, MaxMultipartPartSizeMB)
+	}
+	return nil
+}
+
+// SetMultipartPartSizeMB はマルチパートアップロードのパートサイズを更新する。
+// パートサイズを超えるオブジェクトはマルチパートでアップロードされる。
+func SetMultipartPartSizeMB(sizeMB int) error {
+	if err := ValidateMultipartPartSizeMB(sizeMB); err != nil {
+		return err
+	}
+	multipartPartSize.Store(int64(sizeMB) * bytesPerMB)
+	return nil
+}
+
+// partSizeFor は payloadSize から決めるパートサイズを返す。設定値を下限とし、
+// 最大パート数に収まらない大きさなら MB 単位で切り上げて広げる。
+// 同じ大きさのオブジェクトには同じ値を返すため、再開時もパートの境界がそろう。
+func partSizeFor(payloadSize int64) int64 {
+	size := multipartPartSize.Load()
+	if minSize := (payloadSize + maxMultipartParts - 1) / maxMultipartParts; size < minSize {
+		size = (minSize + bytesPerMB - 1) / bytesPerMB * bytesPerMB
+	}
+	return size
+}
+
+// uploadMultipart は payload をパートに分けてアップロードする。
+// 同じキーへの未完了アップロードが残っていれば再開し、内容（MD5）とサイズが一致する
+// アップロード済みパートは送り直さない。中断や通信エラーで失敗した場合は Abort せずに残し、
+// 次回同じキーへアップロードしたときに再開する。再開しても直らない失敗では Abort する。
+func uploadMultipart(ctx context.Context, client *s3.Client, bucket, key string, payload []byte, contentType string, partSize int64) error {
+	uploadID, uploaded, err := findResumableUpload(ctx, client, bucket, key)
+	if err != nil {
+		return err
+	}
+	if uploadID != "" && !partsMatchLayout(uploaded, partSize) {
+		// パートサイズの設定が変わったなどで境界が合わないパートは使えないため、作り直す。
+		abortMultipart(ctx, client, bucket, key, uploadID)
+		uploadID, uploaded = "", nil
+	}
+	if uploadID == "" {
+		input := &s3.CreateMultipartUploadInput{Bucket: &bucket, Key: &key}
+		if strings.TrimSpace(contentType) != "" {
+			input.ContentType = stringPtr(contentType)
+		}
+		created, err := client.CreateMultipartUpload(ctx, input)
+		if err != nil {
+			return err
+		}
+		uploadID = aws.ToString(created.UploadId)
+	}
+
+	partCount := int32((int64(len(payload)) + partSize - 1) / partSize)
+	completed := make([]s3types.CompletedPart, partCount)
+	partCh := make(chan int32, partCount)
+	for number := int32(1); number <= partCount; number++ {
+		partCh <- number
+	}
+	close(partCh)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	workerCount := multipartConcurrency
+	if workerCount > int(partCount) {
+		workerCount = int(partCount)
+	}
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range partCh {
+				if ctx.Err() != nil {
+					return
+				}
+				start := int64(number-1) * partSize
+				end := min(start+partSize, int64(len(payload)))
+				etag, err := uploadPartIfNeeded(ctx, client, bucket, key, uploadID, number, payload[start:end], uploaded[number])
+				if err != nil {
+					errOnce.Do(func() { firstErr = fmt.Errorf("upload part %d/%d: %w", number, partCount, err); cancel() })
+					return
+				}
+				completed[number-1] = s3types.CompletedPart{PartNumber: aws.Int32(number), ETag: aws.String(etag)}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr == nil {
+		_, firstErr = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          &bucket,
+			Key:             &key,
+			UploadId:        &uploadID,
+			MultipartUpload: &s3types.CompletedMultipartUpload{Parts: completed},
+		})
+	}
+	if firstErr != nil && !multipartResumable(firstErr) {
+		abortMultipart(ctx, client, bucket, key, uploadID)
+	}
+	return firstErr
+}
+
+// partsMatchLayout はアップロード済みパートが partSize で区切った境界と合うかを返す。
+// 最終パート以外は partSize ちょうどのはずなので、それより大きいパートや
+// 最大番号以外で partSize と異なるパートがあれば合わない。
+func partsMatchLayout(uploaded map[int32]*s3types.Part, partSize int64) bool {
+	var last int32
+	for number := range uploaded {
+		last = max(last, number)
+	}
+	for number, part := range uploaded {
+		size := aws.ToInt64(part.Size)
+		if size > partSize || (number != last && size != partSize) {
+			return false
+		}
+	}
+	return true
+}
+
+// multipartResumable は err が中断・通信エラー・サーバー側の一時的な失敗で、
+// 未完了アップロードを残しておけば次回再開できるかを返す。
+func multipartResumable(err error) bool {
+	if errors.Is(err, context.Canceled) || IsUnreachableError(err) {
+		return true
+	}
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) {
+		code := statusErr.HTTPStatusCode()
+		return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// abortMultipart は未完了アップロードを破棄する。呼び出し元が中断されていても破棄できるよう、
+// キャンセルを引き継がない context で送る。破棄に失敗しても本来のエラーを優先するため結果は使わない。
+func abortMultipart(ctx context.Context, client *s3.Client, bucket, key, uploadID string) {
+	_, _ = client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+		Bucket:   &bucket,
+		Key:      &key,
+		UploadId: &uploadID,
+	})
+}
+
+func uploadPartIfNeeded(
+	ctx context.Context,
+	client *s3.Client,
+	bucket, key, uploadID string,
+	number int32,
+	data []byte,
+	existing *s3types.Part,
+) (string, error) {
+	if partReusable(existing, data) {
+		return aws.ToString(existing.ETag), nil
+	}
+	output, err := client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     &bucket,
+		Key:        &key,
+		UploadId:   &uploadID,
+		PartNumber: aws.Int32(number),
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.ETag), nil
+}
+
+// partReusable はアップロード済みパートが data と同じ内容かを返す。
+// 暗号化したブロブは毎回ノンスが変わるため一致せず、送り直しになる（内容を取り違えることは無い）。
+func partReusable(existing *s3types.Part, data []byte) bool {
+	if existing == nil || aws.ToInt64(existing.Size) != int64(len(data)) {
+		return false
+	}
+	sum := md5.Sum(data)
+	return strings.Trim(aws.ToString(existing.ETag), `"`) == hex.EncodeToString(sum[:])
+}
+
+// findResumableUpload は key に対する最新の未完了アップロードとそのアップロード済みパートを返す。
+// 見つからなければ空の uploadID を返す。
+func findResumableUpload(ctx context.Context, client *s3.Client, bucket, key string) (string, map[int32]*s3types.Part, error) {
+	var candidates []s3types.MultipartUpload
+	input := &s3.ListMultipartUploadsInput{Bucket: &bucket, Prefix: &key}
+	for {
+		listed, err := client.ListMultipartUploads(ctx, input)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, upload := range listed.Uploads {
+			if aws.ToString(upload.Key) == key {
+				candidates = append(candidates, upload)
+			}
+		}
+		// 1回の応答は最大 1000 件なので、続きがあればマーカーを進めて取り直す。
+		if !aws.ToBool(listed.IsTruncated) || (listed.NextKeyMarker == nil && listed.NextUploadIdMarker == nil) {
+			break
+		}
+		input.KeyMarker, input.UploadIdMarker = listed.NextKeyMarker, listed.NextUploadIdMarker
+	}
+	if len(candidates) == 0 {
+		return "", nil, nil
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return aws.ToTime(candidates[i].Initiated).After(aws.ToTime(candidates[j].Initiated))
+	})
+	uploadID := aws.ToString(candidates[0].UploadId)
+
+	parts := make(map[int32]*s3types.Part)
+	paginator := s3.NewListPartsPaginator(client, &s3.ListPartsInput{Bucket: &bucket, Key: &key, UploadId: &uploadID})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", nil, err
+		}
+		for i := range page.Parts {
+			part := page.Parts[i]
+			parts[aws.ToInt32(part.PartNumber)] = &part
+		}
+	}
+	return uploadID, parts, nil
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestPartSizeForKeepsPartCountWithinLimit(t *testing.T) {
+	if err := SetMultipartPartSizeMB(DefaultMultipartPartSizeMB); err != nil {
+		t.Fatalf("SetMultipartPartSizeMB: %v", err)
+	}
+	defaultSize := int64(DefaultMultipartPartSizeMB * bytesPerMB)
+	if got := partSizeFor(100 * bytesPerMB); got != defaultSize {
+		t.Fatalf("expected default part size, got %d", got)
+	}
+	huge := defaultSize*maxMultipartParts + 1
+	size := partSizeFor(huge)
+	if (huge+size-1)/size > maxMultipartParts {
+		t.Fatalf("part count exceeds limit: size=%d", size)
+	}
+	if err := SetMultipartPartSizeMB(MinMultipartPartSizeMB - 1); err == nil {
+		t.Fatal("expected error for too small part size")
+	}
+}
+
+func TestPartReusableMatchesSizeAndMD5(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("part-data")
+	sum := md5.Sum(data)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	part := &s3types.Part{ETag: aws.String(etag), Size: aws.Int64(int64(len(data)))}
+	if !partReusable(part, data) {
+		t.Fatal("identical part should be reused")
+	}
+	if partReusable(part, []byte("part-datb")) {
+		t.Fatal("changed content should not be reused")
+	}
+	if partReusable(nil, data) {
+		t.Fatal("missing part should not be reused")
+	}
+}
+
+// fakeMultipartS3 はマルチパートアップロードの API だけを受け付ける S3 の代わり。
+// ListMultipartUploads と ListParts は続きがあるページに分けて返す。
+type fakeMultipartS3 struct {
+	mu          sync.Mutex
+	existing    map[int32][]byte
+	uploaded    []int32
+	created     int
+	completed   int
+	aborted     int
+	completeErr bool
+}
+
+func (fake *fakeMultipartS3) serve(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodGet && query.Has("uploads"):
+		if query.Get("key-marker") == "" {
+			fmt.Fprint(w, `<ListMultipartUploadsResult><IsTruncated>true</IsTruncated><NextKeyMarker>saves/game.bin.old</NextKeyMarker><NextUploadIdMarker>old</NextUploadIdMarker>`+
+				`<Upload><Key>saves/game.bin.old</Key><UploadId>old</UploadId><Initiated>2024-01-01T00:00:00Z</Initiated></Upload></ListMultipartUploadsResult>`)
+			return
+		}
+		fmt.Fprint(w, `<ListMultipartUploadsResult><IsTruncated>false</IsTruncated>`)
+		if fake.existing != nil {
+			fmt.Fprint(w, `<Upload><Key>saves/game.bin</Key><UploadId>resume</UploadId><Initiated>2024-01-02T00:00:00Z</Initiated></Upload>`)
+		}
+		fmt.Fprint(w, `</ListMultipartUploadsResult>`)
+	case r.Method == http.MethodGet && query.Has("uploadId"):
+		marker, _ := strconv.Atoi(query.Get("part-number-marker"))
+		numbers := make([]int, 0, len(fake.existing))
+		for number := range fake.existing {
+			numbers = append(numbers, int(number))
+		}
+		sort.Ints(numbers)
+		fmt.Fprint(w, `<ListPartsResult>`)
+		listed := 0
+		for _, number := range numbers {
+			if number <= marker {
+				continue
+			}
+			if listed == 2 {
+				fmt.Fprintf(w, `<IsTruncated>true</IsTruncated><NextPartNumberMarker>%d</NextPartNumberMarker>`, marker)
+				break
+			}
+			data := fake.existing[int32(number)]
+			sum := md5.Sum(data)
+			fmt.Fprintf(w, `<Part><PartNumber>%d</PartNumber><ETag>"%s"</ETag><Size>%d</Size></Part>`, number, hex.EncodeToString(sum[:]), len(data))
+			marker = number
+			listed++
+		}
+		fmt.Fprint(w, `</ListPartsResult>`)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		fake.created++
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>new</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		data, _ := io.ReadAll(r.Body)
+		fake.uploaded = append(fake.uploaded, int32(number))
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		if fake.completeErr {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<Error><Code>InvalidPart</Code><Message>invalid part</Message></Error>`)
+			return
+		}
+		fake.completed++
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><Key>saves/game.bin</Key></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		fake.aborted++
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeMultipartClient(t *testing.T, fake *fakeMultipartS3) *s3.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+	client, err := NewClient(context.Background(), S3Config{Endpoint: server.URL, Region: "auto", ForcePathStyle: true}, credentials.Credential{
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestUploadMultipartResumesAndSkipsUploadedParts(t *testing.T) {
+	t.Parallel()
+
+	const partSize = 1024
+	payload := bytes.Repeat([]byte("0123456789abcdef"), partSize*5/16+10)
+	fake := &fakeMultipartS3{existing: map[int32][]byte{}}
+	for number := int32(1); number <= 3; number++ {
+		fake.existing[number] = payload[int64(number-1)*partSize : int64(number)*partSize]
+	}
+	// 4 番は内容が違うため送り直しになる。
+	fake.existing[4] = bytes.Repeat([]byte("x"), partSize)
+	client := newFakeMultipartClient(t, fake)
+
+	if err := uploadMultipart(context.Background(), client, "bucket", "saves/game.bin", payload, "", partSize); err != nil {
+		t.Fatalf("uploadMultipart: %v", err)
+	}
+	slices.Sort(fake.uploaded)
+	if !slices.Equal(fake.uploaded, []int32{4, 5, 6}) || fake.created != 0 || fake.completed != 1 || fake.aborted != 0 {
+		t.Fatalf("uploaded %v, created %d, completed %d, aborted %d", fake.uploaded, fake.created, fake.completed, fake.aborted)
+	}
+}
+
+func TestUploadMultipartAbortsOnUnrecoverableError(t *testing.T) {
+	t.Parallel()
+
+	const partSize = 1024
+	payload := bytes.Repeat([]byte("a"), partSize*2+1)
+	fake := &fakeMultipartS3{completeErr: true}
+	client := newFakeMultipartClient(t, fake)
+	if err := uploadMultipart(context.Background(), client, "bucket", "saves/game.bin", payload, "", partSize); err == nil {
+		t.Fatal("expected complete error")
+	}
+	if fake.created != 1 || fake.aborted != 1 {
+		t.Fatalf("created %d, aborted %d", fake.created, fake.aborted)
+	}
+
+	// 境界の合わないパートしか残っていない未完了アップロードは破棄して作り直す。
+	fake = &fakeMultipartS3{existing: map[int32][]byte{1: bytes.Repeat([]byte("a"), partSize/2), 2: payload[:partSize]}}
+	client = newFakeMultipartClient(t, fake)
+	if err := uploadMultipart(context.Background(), client, "bucket", "saves/game.bin", payload, "", partSize); err != nil {
+		t.Fatalf("uploadMultipart: %v", err)
+	}
+	if fake.aborted != 1 || fake.created != 1 || len(fake.uploaded) != 3 {
+		t.Fatalf("aborted %d, created %d, uploaded %v", fake.aborted, fake.created, fake.uploaded)
+	}
+}
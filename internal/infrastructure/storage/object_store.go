@@ -0,0 +1,71 @@
+// 同期データを置くストレージバックエンドのオブジェクト操作の抽象と、その S3 実装を提供する。
+package storage
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ObjectStore は同期データを置くストレージのオブジェクト操作を抽象化する。
+// キーはスラッシュ区切りで、S3 ではバケット内のキー、ローカルバックエンドではルートからの相対パスになる。
+// 存在しないキーの Download は IsNotFoundError で判定できるエラーを返す。
+type ObjectStore interface {
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	Upload(ctx context.Context, key string, payload []byte, contentType string) error
+	Download(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	DeleteByPrefix(ctx context.Context, prefix string) error
+}
+
+// S3ObjectStore は S3 互換ストレージのバケットを ObjectStore として扱う。
+type S3ObjectStore struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3ObjectStore は client と bucket から S3ObjectStore を生成する。
+func NewS3ObjectStore(client *s3.Client, bucket string) *S3ObjectStore {
+	return &S3ObjectStore{client: client, bucket: bucket}
+}
+
+// ListObjects は prefix 配下のオブジェクトを返す。
+func (store *S3ObjectStore) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return ListObjects(ctx, store.client, store.bucket, prefix)
+}
+
+// Upload はオブジェクトを書き込む。
+func (store *S3ObjectStore) Upload(ctx context.Context, key string, payload []byte, contentType string) error {
+	return UploadBytes(ctx, store.client, store.bucket, key, payload, contentType)
+}
+
+// Download はオブジェクトを取得する。
+func (store *S3ObjectStore) Download(ctx context.Context, key string) ([]byte, error) {
+	return DownloadObject(ctx, store.client, store.bucket, key)
+}
+
+// Exists はオブジェクトがあるかを HeadObject で確かめる。
+func (store *S3ObjectStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := store.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &store.bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		if IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete はオブジェクトを削除する。
+func (store *S3ObjectStore) Delete(ctx context.Context, key string) error {
+	return DeleteObject(ctx, store.client, store.bucket, key)
+}
+
+// DeleteByPrefix は prefix 配下のオブジェクトを削除する。
+func (store *S3ObjectStore) DeleteByPrefix(ctx context.Context, prefix string) error {
+	return DeleteObjectsByPrefix(ctx, store.client, store.bucket, prefix)
+}
@@ -3,10 +3,15 @@ package storage
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
@@ -132,6 +137,48 @@ func DeleteObject(ctx context.Context, client *s3.Client, bucket string, key str
 	return error
 }
 
+// HeadBucket はバケットへの到達可否と権限を確認する。
+func HeadBucket(ctx context.Context, client *s3.Client, bucket string) error {
+	_, err := client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: &bucket})
+	return err
+}
+
+// writeProbePrefix は書き込み権限の確認に使う一時オブジェクトのプレフィックス。
+const writeProbePrefix = ".cloudlaunch/probe/"
+
+// ProbeWriteAccess はバケットへ小さな一時オブジェクトを書き込んでから削除し、書き込み・削除の権限を確認する。
+// HeadBucket だけでは読み取り専用のキーでも通ってしまうため、認証情報の検証で使う。
+func ProbeWriteAccess(ctx context.Context, client *s3.Client, bucket string) error {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	key := writeProbePrefix + hex.EncodeToString(suffix)
+	if err := UploadBytes(ctx, client, bucket, key, []byte("cloudlaunch write probe"), "text/plain"); err != nil {
+		return fmt.Errorf("書き込み権限がありません: %w", err)
+	}
+	if err := DeleteObject(ctx, client, bucket, key); err != nil {
+		return fmt.Errorf("削除権限がありません（%s が残っています）: %w", key, err)
+	}
+	return nil
+}
+
+// CopyObject は同一バケット内でオブジェクトをサーバー側コピーする。
+// CopySource は URL エンコード必須なので、日本語タイトルを含むキーもセグメント単位でエスケープする。
+func CopyObject(ctx context.Context, client *s3.Client, bucket string, sourceKey string, destKey string) error {
+	segments := strings.Split(sourceKey, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	source := bucket + "/" + strings.Join(segments, "/")
+	_, err := client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     &bucket,
+		CopySource: &source,
+		Key:        &destKey,
+	})
+	return err
+}
+
 // DownloadObject は単一オブジェクトをダウンロードする。
 func DownloadObject(ctx context.Context, client *s3.Client, bucket string, key string) (data []byte, err error) {
 	response, err := client.GetObject(ctx, &s3.GetObjectInput{
@@ -148,3 +195,50 @@ func DownloadObject(ctx context.Context, client *s3.Client, bucket string, key s
 	}()
 	return io.ReadAll(response.Body)
 }
+
+// DownloadObjectHead はオブジェクトの先頭 maxBytes バイトを Range 取得し、
+// (先頭データ, オブジェクト全体のサイズ, Content-Type) を返す。
+func DownloadObjectHead(ctx context.Context, client *s3.Client, bucket string, key string, maxBytes int64) (data []byte, totalSize int64, contentType string, err error) {
+	if maxBytes <= 0 {
+		return nil, 0, "", fmt.Errorf("maxBytes must be positive")
+	}
+	response, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Range:  stringPtr(fmt.Sprintf("bytes=0-%d", maxBytes-1)),
+	})
+	if err != nil {
+		return nil, 0, "", err
+	}
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	// Range 非対応のエンドポイントでも全体を読み込まないよう上限をかける。
+	data, err = io.ReadAll(io.LimitReader(response.Body, maxBytes))
+	if err != nil {
+		return nil, 0, "", err
+	}
+	totalSize = int64(len(data))
+	if response.ContentLength != nil {
+		totalSize = *response.ContentLength
+	}
+	if total, ok := parseContentRangeTotal(aws.ToString(response.ContentRange)); ok {
+		totalSize = total
+	}
+	return data, totalSize, aws.ToString(response.ContentType), nil
+}
+
+// parseContentRangeTotal は "bytes 0-99/1234" 形式の Content-Range から全体サイズを取り出す。
+func parseContentRangeTotal(contentRange string) (int64, bool) {
+	idx := strings.LastIndex(contentRange, "/")
+	if idx < 0 {
+		return 0, false
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(contentRange[idx+1:]), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return total, true
+}
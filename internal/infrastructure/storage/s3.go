@@ -3,7 +3,9 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"CloudLaunch_Go/internal/infrastructure/credentials"
@@ -11,9 +13,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	awscreds "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+// defaultRoleSessionName は RoleSessionName 未指定時に AssumeRole で名乗るセッション名。
+const defaultRoleSessionName = "CloudLaunch"
+
 // S3Config はS3接続に必要な設定を表す。
 type S3Config struct {
 	Endpoint       string
@@ -24,6 +31,7 @@ type S3Config struct {
 }
 
 // NewClient は S3Config と認証情報からクライアントを生成する。
+// RoleARN がある場合は AssumeRole の一時認証情報を使い、期限前の再取得は SDK のキャッシュに任せる。
 func NewClient(ctx context.Context, cfg S3Config, credential credentials.Credential) (*s3.Client, error) {
 	awsCfg, error := awsconfig.LoadDefaultConfig(
 		ctx,
@@ -31,12 +39,15 @@ func NewClient(ctx context.Context, cfg S3Config, credential credentials.Credent
 		awsconfig.WithCredentialsProvider(awscreds.NewStaticCredentialsProvider(
 			credential.AccessKeyID,
 			credential.SecretAccessKey,
-			"",
+			credential.SessionToken,
 		)),
 	)
 	if error != nil {
 		return nil, error
 	}
+	if roleARN := strings.TrimSpace(credential.RoleARN); roleARN != "" {
+		awsCfg.Credentials = assumeRoleCredentials(awsCfg, roleARN, credential)
+	}
 
 	options := []func(*s3.Options){
 		func(o *s3.Options) {
@@ -54,6 +65,23 @@ func NewClient(ctx context.Context, cfg S3Config, credential credentials.Credent
 	return s3.NewFromConfig(awsCfg, options...), nil
 }
 
+// assumeRoleCredentials は base の認証情報で STS AssumeRole するプロバイダを返す。
+// STS は S3 互換エンドポイントではなく AWS 既定のエンドポイントへ問い合わせる。
+func assumeRoleCredentials(base aws.Config, roleARN string, credential credentials.Credential) aws.CredentialsProvider {
+	sessionName := strings.TrimSpace(credential.RoleSessionName)
+	if sessionName == "" {
+		sessionName = defaultRoleSessionName
+	}
+	externalID := strings.TrimSpace(credential.ExternalID)
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(base), roleARN, func(options *stscreds.AssumeRoleOptions) {
+		options.RoleSessionName = sessionName
+		if externalID != "" {
+			options.ExternalID = aws.String(externalID)
+		}
+	})
+	return aws.NewCredentialsCache(provider)
+}
+
 // normalizeEndpoint はエンドポイントのスキームを補完する。
 func normalizeEndpoint(endpoint string, useTLS bool) string {
 	trimmed := strings.TrimSpace(endpoint)
@@ -69,3 +97,30 @@ func normalizeEndpoint(endpoint string, useTLS bool) string {
 	}
 	return fmt.Sprintf("%s://%s", scheme, trimmed)
 }
+
+// ValidateEndpoint は認証情報に設定するエンドポイントの形式を確かめる。空（AWS 既定）は許可する。
+// スキームは省略可（UseTLS で補完）で、指定するなら http / https のみ。パス・クエリは付けられない。
+// プリセットのテンプレートの "{accountId}" 等を埋め忘れたものも弾く。
+func ValidateEndpoint(endpoint string) error {
+	trimmed := strings.TrimSpace(endpoint)
+	if trimmed == "" {
+		return nil
+	}
+	if strings.ContainsAny(trimmed, "{}<>") {
+		return errors.New("エンドポイントのプレースホルダーが置き換えられていません")
+	}
+	parsed, err := url.Parse(normalizeEndpoint(trimmed, true))
+	if err != nil {
+		return fmt.Errorf("エンドポイントの形式が不正です: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("エンドポイントのスキームは http か https にしてください: %s", parsed.Scheme)
+	}
+	if parsed.Hostname() == "" {
+		return errors.New("エンドポイントにホスト名がありません")
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+		return errors.New("エンドポイントにはスキームとホスト名（とポート）だけを指定してください")
+	}
+	return nil
+}
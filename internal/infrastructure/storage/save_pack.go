@@ -0,0 +1,97 @@
+// 小さなセーブファイルを1つのブロブにまとめる zip パックを提供する。
+package storage
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// BuildSavePack は files（相対パス → ハッシュ）の内容を1つの zip にまとめ、そのハッシュと中身を返す。
+// エントリ名は相対パス、更新日時は固定にするため、内容が同じなら毎回同じハッシュになり
+// 既存ブロブとして再アップロードを省ける。
+func BuildSavePack(files map[string]string, blobs map[string][]byte) (string, []byte, error) {
+	relPaths := make([]string, 0, len(files))
+	for relPath := range files {
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, relPath := range relPaths {
+		data, ok := blobs[files[relPath]]
+		if !ok {
+			return "", nil, fmt.Errorf("save pack: blob missing for %s", relPath)
+		}
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: relPath, Method: zip.Deflate})
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := entry.Write(data); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", nil, err
+	}
+	data := buf.Bytes()
+	return blobHashBytes(data), data, nil
+}
+
+// ExtractSavePack は BuildSavePack の zip から files に含まれるエントリだけを saveDir へ書き出す。
+// 各エントリはハッシュを検証してから書き込む。onFile は書き出したファイルごとに呼ばれる（nil 可）。
+func ExtractSavePack(data []byte, saveDir string, files map[string]string, onFile BlobTransferFunc) error {
+	if len(files) == 0 {
+		return nil
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("save pack: %w", err)
+	}
+	entries := make(map[string]*zip.File, len(reader.File))
+	for _, entry := range reader.File {
+		entries[entry.Name] = entry
+	}
+
+	for relPath, hash := range files {
+		entry, ok := entries[relPath]
+		if !ok {
+			return fmt.Errorf("save pack: entry missing: %s", relPath)
+		}
+		content, err := readZipEntry(entry)
+		if err != nil {
+			return err
+		}
+		if blobHashBytes(content) != hash {
+			return fmt.Errorf("save pack: hash mismatch: %s", relPath)
+		}
+		targetPath, err := ResolveSafeRelativePath(saveDir, relPath)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(targetPath, content, 0o600); err != nil {
+			return err
+		}
+		if onFile != nil {
+			onFile(relPath, int64(len(content)), false)
+		}
+	}
+	return nil
+}
+
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	source, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = source.Close() }()
+	return io.ReadAll(source)
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSavePackIsDeterministicAndExtractsSubset(t *testing.T) {
+	t.Parallel()
+
+	blobs := map[string][]byte{
+		blobHashBytes([]byte("a")): []byte("a"),
+		blobHashBytes([]byte("b")): []byte("b"),
+	}
+	files := map[string]string{
+		"a.dat":     blobHashBytes([]byte("a")),
+		"dir/b.dat": blobHashBytes([]byte("b")),
+	}
+
+	hash1, data, err := BuildSavePack(files, blobs)
+	if err != nil {
+		t.Fatalf("BuildSavePack: %v", err)
+	}
+	hash2, _, err := BuildSavePack(files, blobs)
+	if err != nil {
+		t.Fatalf("BuildSavePack: %v", err)
+	}
+	if hash1 != hash2 || hash1 != blobHashBytes(data) {
+		t.Fatalf("pack hash should be stable: %s / %s", hash1, hash2)
+	}
+
+	saveDir := t.TempDir()
+	subset := map[string]string{"dir/b.dat": files["dir/b.dat"]}
+	if err := ExtractSavePack(data, saveDir, subset, nil); err != nil {
+		t.Fatalf("ExtractSavePack: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(saveDir, "dir", "b.dat")); err != nil || string(got) != "b" {
+		t.Fatalf("dir/b.dat = %q (%v)", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(saveDir, "a.dat")); !os.IsNotExist(err) {
+		t.Fatalf("a.dat should not be extracted, err=%v", err)
+	}
+
+	if err := ExtractSavePack(data, saveDir, map[string]string{"a.dat": files["dir/b.dat"]}, nil); err == nil {
+		t.Fatal("expected hash mismatch error")
+	}
+}
@@ -14,7 +14,11 @@ const (
 )
 
 // UploadBytes は任意のバイト列をアップロードする。
+// パートサイズを超える場合はマルチパートアップロードにし、中断されたアップロードを再開する。
 func UploadBytes(ctx context.Context, client *s3.Client, bucket string, key string, payload []byte, contentType string) error {
+	if partSize := partSizeFor(int64(len(payload))); int64(len(payload)) > partSize {
+		return uploadMultipart(ctx, client, bucket, key, payload, contentType, partSize)
+	}
 	reader := bytes.NewReader(payload)
 	input := &s3.PutObjectInput{
 		Bucket: &bucket,
@@ -27,10 +27,16 @@ const (
 // error 以上を同時出力する。各ファイルはサイズ上限でローテーションする。
 // 戻り値の *slog.LevelVar を書き換えれば実行時にログレベルを変更できる。
 func NewLogger(appDataDir string, level string) (*slog.Logger, *slog.LevelVar) {
+	return NewLoggerTo(appDataDir, level, os.Stdout)
+}
+
+// NewLoggerTo は標準出力の代わりに console へ出力する NewLogger。
+// 標準出力をコマンドの結果に使う CLI では os.Stderr を渡す。
+func NewLoggerTo(appDataDir string, level string, console io.Writer) (*slog.Logger, *slog.LevelVar) {
 	levelVar := &slog.LevelVar{}
 	levelVar.Set(ParseLevel(level))
 
-	var mainWriter io.Writer = os.Stdout
+	mainWriter := console
 	var errorHandler slog.Handler
 
 	logDir, dirErr := ensureLogDir(appDataDir)
@@ -40,7 +46,7 @@ func NewLogger(appDataDir string, level string) (*slog.Logger, *slog.LevelVar) {
 		_, _ = fmt.Fprintf(os.Stderr, "failed to initialize log dir: %v\n", dirErr)
 	} else {
 		if appWriter, ok := tryOpenRotatingLog(filepath.Join(logDir, logFileName), "log file"); ok {
-			mainWriter = io.MultiWriter(os.Stdout, appWriter)
+			mainWriter = io.MultiWriter(console, appWriter)
 		}
 		// error 以上だけを集約する専用ファイル。重大なエラーを探しやすくする。
 		if errWriter, ok := tryOpenRotatingLog(filepath.Join(logDir, errorFileName), "error log file"); ok {
@@ -59,6 +65,21 @@ func NewLogger(appDataDir string, level string) (*slog.Logger, *slog.LevelVar) {
 	return slog.New(handler).With("scope", "backend"), levelVar
 }
 
+// NewFileLogger は appDataDir/logs/fileName へ JSON で出力する機能別の slog.Logger を生成する。
+// ファイルは NewLogger と同じサイズ上限・世代数でローテーションする。戻り値の io.Closer でファイルを閉じる。
+func NewFileLogger(appDataDir, fileName, level string) (*slog.Logger, io.Closer, error) {
+	logDir, err := ensureLogDir(appDataDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	writer, err := newRotatingWriter(filepath.Join(logDir, fileName), maxLogSize, maxLogBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+	handler := slog.NewJSONHandler(writer, &slog.HandlerOptions{Level: ParseLevel(level)})
+	return slog.New(handler), writer, nil
+}
+
 // ParseLevel は文字列から slog.Level を決定する。
 func ParseLevel(level string) slog.Level {
 	switch strings.ToLower(strings.TrimSpace(level)) {
@@ -173,6 +194,18 @@ func (w *rotatingWriter) Write(p []byte) (int, error) {
 	return n, err
 }
 
+// Close はログファイルを閉じる。以降の Write はエラーになる。
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
 // rotate は現在のファイルを path.1 .. path.N へシフトし、新しい空ファイルを開く。
 func (w *rotatingWriter) rotate() error {
 	_ = w.file.Close()
@@ -0,0 +1,153 @@
+// ログファイル（JSON Lines）を読み、レベル・時刻・文字列で絞り込んで返す。
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxQueryResults は QueryLogs が返す最大件数（新しい順）。
+	maxQueryResults = 500
+	// maxLogLineSize は1行として読み込む最大バイト数。超える行は読み飛ばす。
+	maxLogLineSize = 1024 * 1024
+)
+
+// LogEntry はログ1件を表す。Attrs には time / level / msg / source 以外の属性をそのまま入れる。
+type LogEntry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Source  string         `json:"source,omitempty"`
+	File    string         `json:"file"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// LogQuery はログの絞り込み条件を表す。
+// Level は最低レベル（空なら全レベル）、Since はゼロ値なら時刻で絞り込まない。
+// Text は大文字小文字を区別せず、メッセージと属性を含む行全体に部分一致させる。
+type LogQuery struct {
+	Level string
+	Since time.Time
+	Text  string
+	Limit int
+}
+
+// QueryLogs は appDataDir/logs の app.log と error.log（ローテーション済みの世代を含む）から
+// 条件に一致するログを新しい順に最大 Limit 件（0 以下なら maxQueryResults 件）返す。
+// error.log は app.log の error 以上の写しなので、同じ行は1件にまとめる。
+// 機能別のログ（screenshot.log など）はメインのロガーにも同じ内容を出しているため読まない。
+func QueryLogs(appDataDir string, query LogQuery) ([]LogEntry, error) {
+	baseDir := strings.TrimSpace(appDataDir)
+	if baseDir == "" {
+		return nil, fmt.Errorf("appDataDir is empty")
+	}
+	logDir := filepath.Join(baseDir, logDirName)
+	limit := query.Limit
+	if limit <= 0 || limit > maxQueryResults {
+		limit = maxQueryResults
+	}
+	minLevel := slog.Level(-1 << 10)
+	if strings.TrimSpace(query.Level) != "" {
+		minLevel = ParseLevel(query.Level)
+	}
+	text := strings.ToLower(strings.TrimSpace(query.Text))
+
+	seen := make(map[string]struct{})
+	entries := make([]LogEntry, 0)
+	for _, name := range []string{logFileName, errorFileName} {
+		for i := 0; i <= maxLogBackups; i++ {
+			fileName := name
+			if i > 0 {
+				fileName = fmt.Sprintf("%s.%d", name, i)
+			}
+			err := scanLogFile(filepath.Join(logDir, fileName), func(line string) {
+				if _, ok := seen[line]; ok {
+					return
+				}
+				seen[line] = struct{}{}
+				if text != "" && !strings.Contains(strings.ToLower(line), text) {
+					return
+				}
+				entry, level, ok := parseLogLine(line)
+				if !ok || level < minLevel || (!query.Since.IsZero() && entry.Time.Before(query.Since)) {
+					return
+				}
+				entry.File = fileName
+				entries = append(entries, entry)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// scanLogFile は path を1行ずつ読む。ファイルが無ければ何もしない。
+func scanLogFile(path string, onLine func(line string)) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if trimmed := strings.TrimSpace(line); trimmed != "" && len(trimmed) <= maxLogLineSize {
+			onLine(trimmed)
+		}
+		if err != nil {
+			// 書き込み途中の最終行も読めた分だけ扱い、EOF で終える。
+			return nil
+		}
+	}
+}
+
+// parseLogLine は slog.JSONHandler の1行を LogEntry にする。JSON でない行は ok=false。
+func parseLogLine(line string) (LogEntry, slog.Level, bool) {
+	fields := make(map[string]any)
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return LogEntry{}, 0, false
+	}
+	entry := LogEntry{}
+	if value, ok := fields[slog.TimeKey].(string); ok {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, value)
+	}
+	entry.Level, _ = fields[slog.LevelKey].(string)
+	entry.Message, _ = fields[slog.MessageKey].(string)
+	if source, ok := fields[slog.SourceKey].(map[string]any); ok {
+		file, _ := source["file"].(string)
+		line, _ := source["line"].(float64)
+		if file != "" {
+			entry.Source = fmt.Sprintf("%s:%d", filepath.Base(file), int(line))
+		}
+	}
+	for _, key := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey} {
+		delete(fields, key)
+	}
+	if len(fields) > 0 {
+		entry.Attrs = fields
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(entry.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+	return entry, level, true
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryLogsFiltersAndDeduplicatesErrorLog(t *testing.T) {
+	dir := t.TempDir()
+	logger, _ := NewLogger(dir, "debug")
+
+	logger.Debug("詳細ログ")
+	logger.Info("同期を開始", "gameId", "game-1")
+	logger.Error("同期に失敗", "gameId", "game-2")
+
+	all, err := QueryLogs(dir, LogQuery{})
+	if err != nil {
+		t.Fatalf("QueryLogs: %v", err)
+	}
+	// error.log にも同じ行があるが、1件にまとめる。
+	if len(all) != 3 || all[0].Message != "同期に失敗" {
+		t.Fatalf("expected 3 entries newest first, got %+v", all)
+	}
+	if all[0].Level != "ERROR" || all[0].Source == "" || all[0].Attrs["gameId"] != "game-2" || all[0].Attrs["scope"] != "backend" {
+		t.Fatalf("unexpected parsed entry: %+v", all[0])
+	}
+
+	warnings, err := QueryLogs(dir, LogQuery{Level: "warn"})
+	if err != nil || len(warnings) != 1 || warnings[0].Message != "同期に失敗" {
+		t.Fatalf("expected only the error entry, got %+v err=%v", warnings, err)
+	}
+
+	matched, err := QueryLogs(dir, LogQuery{Text: "GAME-1"})
+	if err != nil || len(matched) != 1 || matched[0].Message != "同期を開始" {
+		t.Fatalf("expected text match on attrs, got %+v err=%v", matched, err)
+	}
+
+	future, err := QueryLogs(dir, LogQuery{Since: time.Now().Add(time.Hour)})
+	if err != nil || len(future) != 0 {
+		t.Fatalf("expected no entries after since, got %+v err=%v", future, err)
+	}
+}
+
+func TestQueryLogsReadsRotatedFilesAndSkipsBrokenLines(t *testing.T) {
+	dir := t.TempDir()
+	logDir := filepath.Join(dir, logDirName)
+	if err := os.MkdirAll(logDir, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	older := `{"time":"2025-01-01T00:00:00Z","level":"INFO","msg":"古いログ"}` + "\n"
+	newer := `{"time":"2025-01-02T00:00:00Z","level":"WARN","msg":"新しいログ"}` + "\n" + "not json\n"
+	if err := os.WriteFile(filepath.Join(logDir, logFileName+".1"), []byte(older), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(logDir, logFileName), []byte(newer), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	entries, err := QueryLogs(dir, LogQuery{Limit: 1})
+	if err != nil {
+		t.Fatalf("QueryLogs: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Message != "新しいログ" || entries[0].File != logFileName {
+		t.Fatalf("expected newest entry only, got %+v", entries)
+	}
+	entries, _ = QueryLogs(dir, LogQuery{})
+	if len(entries) != 2 || entries[1].File != logFileName+".1" {
+		t.Fatalf("expected rotated file to be read, got %+v", entries)
+	}
+}
+
+func TestNewFileLoggerWritesRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	logger, closer, err := NewFileLogger(dir, "feature.log", "info")
+	if err != nil {
+		t.Fatalf("NewFileLogger: %v", err)
+	}
+	logger.Debug("出力されない")
+	logger.Info("機能ログ")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	content := readFile(t, filepath.Join(dir, logDirName, "feature.log"))
+	if !strings.Contains(content, "機能ログ") || strings.Contains(content, "出力されない") {
+		t.Fatalf("unexpected feature.log: %q", content)
+	}
+}
@@ -0,0 +1,100 @@
+// メモ Markdown から目次・チェックリスト進捗・分量を抽出する。
+package memo
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// cjkCharsPerMinute / wordsPerMinute は読了時間の目安に使う1分あたりの分量。
+	cjkCharsPerMinute = 500
+	wordsPerMinute    = 200
+)
+
+var (
+	headingRegex   = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	checklistRegex = regexp.MustCompile(`^[ \t]*(?:[-*+]|\d+[.)])[ \t]+\[([ xX])\](?:[ \t]|$)`)
+	fenceRegex     = regexp.MustCompile("^ {0,3}(```|~~~)")
+)
+
+// ParseOutline はメモ本文から見出し・チェックリスト進捗・語数などを抽出する。
+// コードブロック内の行は見出し・チェックリストとして扱わない（分量には含める）。
+func ParseOutline(memoID string, content string) domain.MemoOutline {
+	outline := domain.MemoOutline{MemoID: memoID, Headings: []domain.MemoHeading{}}
+	fence := ""
+	cjkChars := 0
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for index, line := range lines {
+		words, chars, cjk := countText(line)
+		outline.WordCount += words
+		outline.CharCount += chars
+		cjkChars += cjk
+
+		if match := fenceRegex.FindStringSubmatch(line); match != nil {
+			switch {
+			case fence == "":
+				fence = match[1]
+			case fence == match[1]:
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		if match := headingRegex.FindStringSubmatch(line); match != nil {
+			if text := strings.TrimSpace(match[2]); text != "" {
+				outline.Headings = append(outline.Headings, domain.MemoHeading{Level: len(match[1]), Text: text, Line: index + 1})
+			}
+			continue
+		}
+		if match := checklistRegex.FindStringSubmatch(line); match != nil {
+			outline.Checklist.Total++
+			if match[1] != " " {
+				outline.Checklist.Checked++
+			}
+		}
+	}
+
+	if outline.Checklist.Total > 0 {
+		outline.Checklist.Percent = outline.Checklist.Checked * 100 / outline.Checklist.Total
+	}
+	if outline.CharCount > 0 {
+		// 日本語は文字数、それ以外は語数で見積もり、端数は切り上げる。
+		minutes := (cjkChars*wordsPerMinute + outline.WordCount*cjkCharsPerMinute + cjkCharsPerMinute*wordsPerMinute - 1) /
+			(cjkCharsPerMinute * wordsPerMinute)
+		outline.ReadingMinutes = max(minutes, 1)
+	}
+	return outline
+}
+
+// countText は1行の語数（空白区切りの英数字の塊）・空白以外の文字数・CJK 文字数を返す。
+// CJK 文字は語の区切りとして扱い、語数には含めない。
+func countText(line string) (words int, chars int, cjk int) {
+	inWord := false
+	for _, r := range line {
+		switch {
+		case unicode.IsSpace(r):
+			inWord = false
+			continue
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		}
+		chars++
+	}
+	return words, chars, cjk
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r == 'ー'
+}
@@ -0,0 +1,72 @@
+package memo
+
+import (
+	"strings"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestParseOutlineExtractsHeadingsAndChecklist(t *testing.T) {
+	t.Parallel()
+
+	content := "# 攻略メモ\r\n" +
+		"\n" +
+		"## 共通ルート ##\n" +
+		"- [x] 選択肢A\n" +
+		"* [X] 選択肢B\n" +
+		"1. [ ] 選択肢C\n" +
+		"- [ ]\n" +
+		"- [link](https://example.com)\n" +
+		"```\n" +
+		"# not a heading\n" +
+		"- [ ] not a task\n" +
+		"```\n" +
+		"#hashtag\n" +
+		"### True End\n"
+
+	outline := ParseOutline("memo-1", content)
+
+	wantHeadings := []domain.MemoHeading{
+		{Level: 1, Text: "攻略メモ", Line: 1},
+		{Level: 2, Text: "共通ルート", Line: 3},
+		{Level: 3, Text: "True End", Line: 14},
+	}
+	if len(outline.Headings) != len(wantHeadings) {
+		t.Fatalf("headings: got %+v", outline.Headings)
+	}
+	for i, want := range wantHeadings {
+		if outline.Headings[i] != want {
+			t.Errorf("headings[%d]: want %+v got %+v", i, want, outline.Headings[i])
+		}
+	}
+	if outline.Checklist != (domain.MemoChecklist{Total: 4, Checked: 2, Percent: 50}) {
+		t.Fatalf("checklist: got %+v", outline.Checklist)
+	}
+}
+
+func TestParseOutlineCountsWordsAndReadingTime(t *testing.T) {
+	t.Parallel()
+
+	outline := ParseOutline("memo-1", "Hello world, it's 2026\nこんにちは")
+	// 空白区切りで Hello / world, / it's / 2026 の4語、かなは語数に含めない。
+	if outline.WordCount != 4 {
+		t.Fatalf("wordCount: got %d", outline.WordCount)
+	}
+	if outline.CharCount != 24 {
+		t.Fatalf("charCount: got %d", outline.CharCount)
+	}
+	if outline.ReadingMinutes != 1 {
+		t.Fatalf("readingMinutes: got %d", outline.ReadingMinutes)
+	}
+
+	long := ParseOutline("memo-2", strings.Repeat("あ", 1001))
+	if long.ReadingMinutes != 3 {
+		t.Fatalf("expected 1001 kana to take 3 minutes, got %d", long.ReadingMinutes)
+	}
+
+	empty := ParseOutline("memo-3", " \n\t")
+	if empty.ReadingMinutes != 0 || empty.CharCount != 0 || empty.Headings == nil {
+		t.Fatalf("unexpected empty outline: %+v", empty)
+	}
+}
@@ -1,10 +1,16 @@
 // API結果型とエラー型を提供する。
 package result
 
-import "time"
+import (
+	"time"
+
+	"CloudLaunch_Go/internal/i18n"
+)
 
 // ApiError は API で返すエラー情報を表す。
+// Message は表示言語に合わせた文言、Code はメッセージカタログのコード（カタログに無いメッセージでは空）。
 type ApiError struct {
+	Code    string    `json:"code,omitempty"`
 	Message string    `json:"message"`
 	Detail  string    `json:"detail"`
 	At      time.Time `json:"at"`
@@ -25,12 +31,15 @@ func OkResult[T any](data T) ApiResult[T] {
 	}
 }
 
-// ErrorResult は失敗時の ApiResult を生成する。
+// ErrorResult は失敗時の ApiResult を生成する。message は日本語で渡し、表示言語の文言に置き換える。
+// detail は原因の調査用なので翻訳しない。
 func ErrorResult[T any](message string, detail string) ApiResult[T] {
+	code, localized := i18n.Localize(message)
 	return ApiResult[T]{
 		Success: false,
 		Error: &ApiError{
-			Message: message,
+			Code:    code,
+			Message: localized,
 			Detail:  detail,
 			At:      time.Now(),
 		},
@@ -28,3 +28,13 @@ func TestErrorResult(t *testing.T) {
 		t.Fatalf("unexpected error message")
 	}
 }
+
+func TestErrorResultLocalizesCatalogMessages(t *testing.T) {
+	value := ErrorResult[int]("ゲームが見つかりません", "id=1")
+	if value.Error.Code != "game.notFound" || value.Error.Message != "ゲームが見つかりません" {
+		t.Fatalf("unexpected error: %+v", value.Error)
+	}
+	if value.Error.Detail != "id=1" {
+		t.Fatalf("detail should be kept as is: %q", value.Error.Detail)
+	}
+}
@@ -0,0 +1,86 @@
+// ユーザー操作の監査ログ（アクティビティログ）の記録・参照と保持期間の管理を提供する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// activityLogRetention はアクティビティログを残す期間。ライブラリの変化を後から追えるよう変更ジャーナルより長く残す。
+const activityLogRetention = 180 * 24 * time.Hour
+
+// ActivityLogService はアクティビティログの記録と参照を提供する。
+type ActivityLogService struct {
+	repository ActivityLogRepository
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewActivityLogService は ActivityLogService を生成する。
+func NewActivityLogService(repository ActivityLogRepository, logger *slog.Logger) *ActivityLogService {
+	return &ActivityLogService{repository: repository, logger: logger, now: time.Now}
+}
+
+// Record はアクティビティを1件記録する。監査ログは付随情報のため、失敗しても呼び出し元の操作は失敗扱いにしない。
+func (service *ActivityLogService) Record(ctx context.Context, action, entityType, entityID, summary string) {
+	entry := domain.ActivityEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   strings.TrimSpace(entityID),
+		Summary:    strings.TrimSpace(summary),
+	}
+	if err := service.repository.InsertActivity(ctx, entry); err != nil {
+		service.logger.Warn("操作履歴の記録に失敗", "action", action, "entityType", entityType, "entityId", entry.EntityID, "error", err)
+	}
+}
+
+// ListActivityLog は条件に一致するアクティビティを新しい順に1ページ分と全件数を返す。
+func (service *ActivityLogService) ListActivityLog(
+	ctx context.Context,
+	filter domain.ActivityFilter,
+	page domain.PageRequest,
+) (domain.Page[domain.ActivityEntry], error) {
+	filter.Action = strings.TrimSpace(filter.Action)
+	filter.EntityType = strings.TrimSpace(filter.EntityType)
+	filter.EntityID = strings.TrimSpace(filter.EntityID)
+	switch filter.Action {
+	case "", domain.ActivityActionCreate, domain.ActivityActionUpdate, domain.ActivityActionDelete,
+		domain.ActivityActionSync, domain.ActivityActionLaunch:
+	default:
+		service.logger.Warn("操作種別が不正です", "action", filter.Action)
+		return domain.Page[domain.ActivityEntry]{}, newServiceError("操作種別が不正です", "actionはcreate|update|delete|sync|launchのいずれかです")
+	}
+	switch filter.EntityType {
+	case "", domain.ChangeEntityGame, domain.ChangeEntitySession, domain.ChangeEntityRoute,
+		domain.ChangeEntityMemo, domain.ChangeEntityLink:
+	default:
+		service.logger.Warn("エンティティ種別が不正です", "entityType", filter.EntityType)
+		return domain.Page[domain.ActivityEntry]{}, newServiceError("エンティティ種別が不正です", "entityTypeはgame|session|route|memo|gameLinkのいずれかです")
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Until.After(filter.Since) {
+		return domain.Page[domain.ActivityEntry]{}, newServiceError("期間の指定が不正です", "untilはsinceより後にしてください")
+	}
+	page = page.Normalize()
+	entries, total, err := service.repository.ListActivityPage(ctx, filter, page)
+	if err != nil {
+		service.logger.Error("操作履歴の取得に失敗", "error", err)
+		return domain.Page[domain.ActivityEntry]{}, newServiceError("操作履歴の取得に失敗しました", err.Error())
+	}
+	return domain.NewPage(entries, total, page), nil
+}
+
+// PruneExpired は保持期間を過ぎたアクティビティを削除する。失敗しても起動は止めない。
+func (service *ActivityLogService) PruneExpired(ctx context.Context) {
+	removed, err := service.repository.PruneActivityLog(ctx, service.now().Add(-activityLogRetention))
+	if err != nil {
+		service.logger.Warn("操作履歴の整理に失敗", "error", err)
+		return
+	}
+	if removed > 0 {
+		service.logger.Info("古い操作履歴を削除", "count", removed)
+	}
+}
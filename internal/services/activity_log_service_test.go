@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+type fakeActivityLogRepository struct {
+	inserted  []domain.ActivityEntry
+	insertErr error
+	filter    domain.ActivityFilter
+	page      domain.PageRequest
+	prunedAt  time.Time
+}
+
+func (r *fakeActivityLogRepository) InsertActivity(ctx context.Context, entry domain.ActivityEntry) error {
+	if r.insertErr != nil {
+		return r.insertErr
+	}
+	r.inserted = append(r.inserted, entry)
+	return nil
+}
+
+func (r *fakeActivityLogRepository) ListActivityPage(ctx context.Context, filter domain.ActivityFilter, page domain.PageRequest) ([]domain.ActivityEntry, int, error) {
+	r.filter = filter
+	r.page = page
+	return []domain.ActivityEntry{{ID: 1, Action: domain.ActivityActionLaunch}}, 3, nil
+}
+
+func (r *fakeActivityLogRepository) PruneActivityLog(ctx context.Context, before time.Time) (int64, error) {
+	r.prunedAt = before
+	return 0, nil
+}
+
+func TestActivityLogServiceRecordIgnoresFailures(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeActivityLogRepository{}
+	service := NewActivityLogService(repo, newTestLogger())
+	service.Record(context.Background(), domain.ActivityActionCreate, domain.ChangeEntityGame, " game-1 ", " ゲームを追加 ")
+	if len(repo.inserted) != 1 || repo.inserted[0].EntityID != "game-1" || repo.inserted[0].Summary != "ゲームを追加" {
+		t.Fatalf("unexpected inserted: %+v", repo.inserted)
+	}
+
+	repo.insertErr = errors.New("disk full")
+	service.Record(context.Background(), domain.ActivityActionDelete, domain.ChangeEntityGame, "game-1", "削除")
+	if len(repo.inserted) != 1 {
+		t.Fatalf("expected failed insert to be dropped, got %+v", repo.inserted)
+	}
+}
+
+func TestActivityLogServiceValidatesFilter(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeActivityLogRepository{}
+	service := NewActivityLogService(repo, newTestLogger())
+	ctx := context.Background()
+
+	if _, err := service.ListActivityLog(ctx, domain.ActivityFilter{Action: "rename"}, domain.PageRequest{}); err == nil {
+		t.Fatal("expected error for unknown action")
+	}
+	if _, err := service.ListActivityLog(ctx, domain.ActivityFilter{EntityType: "unknown"}, domain.PageRequest{}); err == nil {
+		t.Fatal("expected error for unknown entity type")
+	}
+	now := time.Now()
+	if _, err := service.ListActivityLog(ctx, domain.ActivityFilter{Since: now, Until: now.Add(-time.Hour)}, domain.PageRequest{}); err == nil {
+		t.Fatal("expected error for inverted period")
+	}
+
+	page, err := service.ListActivityLog(ctx, domain.ActivityFilter{Action: " launch ", EntityID: " game-1 "}, domain.PageRequest{Limit: 1})
+	if err != nil {
+		t.Fatalf("ListActivityLog: %v", err)
+	}
+	if repo.filter.Action != domain.ActivityActionLaunch || repo.filter.EntityID != "game-1" || repo.page.Limit != 1 {
+		t.Fatalf("unexpected forwarded filter: %+v page=%+v", repo.filter, repo.page)
+	}
+	if page.Total != 3 || len(page.Items) != 1 || !page.HasMore {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
+
+func TestActivityLogServicePrunesByRetention(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeActivityLogRepository{}
+	service := NewActivityLogService(repo, newTestLogger())
+	fixed := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return fixed }
+	service.PruneExpired(context.Background())
+	if !repo.prunedAt.Equal(fixed.Add(-activityLogRetention)) {
+		t.Fatalf("unexpected prune cutoff: %v", repo.prunedAt)
+	}
+}
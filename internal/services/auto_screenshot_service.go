@@ -0,0 +1,217 @@
+// プレイ中の定期スクリーンショット（ゲームごとに有効化し、N 分ごとに自動撮影）のスケジュールを提供する。
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// autoScreenshotSettingKey は Settings テーブル上でゲームごとの定期スクリーンショット設定を保存するキー。
+	autoScreenshotSettingKey = "auto_screenshots"
+	// MaxAutoScreenshotIntervalMinutes は撮影間隔の上限（12時間）。
+	MaxAutoScreenshotIntervalMinutes = 12 * 60
+	// MaxAutoScreenshotCount はゲームごとに残す自動撮影分の枚数の上限。
+	MaxAutoScreenshotCount = 1000
+)
+
+// ValidateAutoScreenshotSetting は撮影間隔が 1 から MaxAutoScreenshotIntervalMinutes 分、
+// 残す枚数が 1 から MaxAutoScreenshotCount 枚の範囲かを検証する。
+func ValidateAutoScreenshotSetting(setting domain.AutoScreenshotSetting) error {
+	if setting.IntervalMinutes < 1 || setting.IntervalMinutes > MaxAutoScreenshotIntervalMinutes {
+		return fmt.Errorf("intervalMinutes must be 1-%d", MaxAutoScreenshotIntervalMinutes)
+	}
+	if setting.MaxCount < 1 || setting.MaxCount > MaxAutoScreenshotCount {
+		return fmt.Errorf("maxCount must be 1-%d", MaxAutoScreenshotCount)
+	}
+	return nil
+}
+
+// autoScreenshotCapturer は定期スクリーンショットの撮影を行う（ScreenshotService が実装する）。
+type autoScreenshotCapturer interface {
+	CaptureAutoScreenshot(ctx context.Context, gameID string, maxCount int) (domain.CaptureResult, bool, error)
+}
+
+// autoScreenshotProgress は計測中のセッションで撮影を試みた区切りの数を表す。
+type autoScreenshotProgress struct {
+	slots    int64
+	playTime int64
+}
+
+// AutoScreenshotService はプロセス監視の累計プレイ時間を見て、設定したゲームを間隔ごとに自動撮影する。
+// ゲームのウィンドウが前面に無い区切りは撮影せずに飛ばす。既定では無効で、ゲームごとに有効にする。
+type AutoScreenshotService struct {
+	repository SettingsRepository
+	capturer   autoScreenshotCapturer
+	logger     *slog.Logger
+	// running は撮影中の Check が重ならないようにする（撮影は数百ミリ秒かかり、監視のスキャンと並行して呼ばれうる）。
+	running atomic.Bool
+
+	mu            sync.Mutex
+	settings      map[string]domain.AutoScreenshotSetting
+	settingsReady bool
+	progress      map[string]autoScreenshotProgress
+}
+
+// NewAutoScreenshotService は AutoScreenshotService を生成する。
+func NewAutoScreenshotService(repository SettingsRepository, capturer autoScreenshotCapturer, logger *slog.Logger) *AutoScreenshotService {
+	return &AutoScreenshotService{
+		repository: repository,
+		capturer:   capturer,
+		logger:     logger,
+		progress:   make(map[string]autoScreenshotProgress),
+	}
+}
+
+// ListGameSettings は定期スクリーンショットを有効にしたゲームの設定（ゲームID → 設定）を返す。
+func (service *AutoScreenshotService) ListGameSettings(ctx context.Context) (map[string]domain.AutoScreenshotSetting, error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	settings, err := service.loadSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	copied := make(map[string]domain.AutoScreenshotSetting, len(settings))
+	for gameID, setting := range settings {
+		copied[gameID] = setting
+	}
+	return copied, nil
+}
+
+// SetGameSetting はゲームの定期スクリーンショット設定を保存する。setting が nil なら無効にする。
+func (service *AutoScreenshotService) SetGameSetting(ctx context.Context, gameID string, setting *domain.AutoScreenshotSetting) error {
+	gameID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return newServiceError("ゲームIDが不正です", detail)
+	}
+	if setting != nil {
+		if err := ValidateAutoScreenshotSetting(*setting); err != nil {
+			service.logger.Warn("定期スクリーンショットの設定が不正です", "gameId", gameID, "error", err)
+			return newServiceError("定期スクリーンショットの設定が不正です", err.Error())
+		}
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	settings, err := service.loadSettings(ctx)
+	if err != nil {
+		return err
+	}
+	updated := make(map[string]domain.AutoScreenshotSetting, len(settings)+1)
+	for id, value := range settings {
+		updated[id] = value
+	}
+	if setting == nil {
+		delete(updated, gameID)
+	} else {
+		updated[gameID] = *setting
+	}
+	payload, err := json.Marshal(updated)
+	if err != nil {
+		return newServiceError("定期スクリーンショット設定の保存に失敗しました", err.Error())
+	}
+	if err := service.repository.UpsertSetting(ctx, autoScreenshotSettingKey, string(payload)); err != nil {
+		service.logger.Error("定期スクリーンショット設定の保存に失敗", "gameId", gameID, "error", err)
+		return newServiceError("定期スクリーンショット設定の保存に失敗しました", err.Error())
+	}
+	service.settings = updated
+	delete(service.progress, gameID)
+	return nil
+}
+
+// Check は監視中のゲームの累計プレイ時間を見て、撮影間隔の区切りを超えたゲームを1枚ずつ撮影する。
+// プロセス監視のスキャンごとに呼ばれる想定で、同じ区切りでは（前面に無く撮れなかった場合も）1回だけ試みる。
+// プレイ時間が前回より減ったとき（新しいセッション）は数え直す。前回の撮影がまだ終わっていなければ何もしない。
+func (service *AutoScreenshotService) Check(ctx context.Context, statuses []domain.MonitoringGameStatus) {
+	if !service.running.CompareAndSwap(false, true) {
+		return
+	}
+	defer service.running.Store(false)
+
+	type dueCapture struct {
+		gameID   string
+		maxCount int
+	}
+	due := make([]dueCapture, 0)
+
+	service.mu.Lock()
+	settings, err := service.loadSettings(ctx)
+	if err != nil {
+		settings = nil
+	}
+	active := make(map[string]struct{}, len(statuses))
+	for _, status := range statuses {
+		setting, ok := settings[status.GameID]
+		if !ok {
+			continue
+		}
+		active[status.GameID] = struct{}{}
+		progress := service.progress[status.GameID]
+		if status.PlayTime < progress.playTime {
+			progress = autoScreenshotProgress{}
+		}
+		progress.playTime = status.PlayTime
+		if status.IsPlaying {
+			reached := status.PlayTime / int64(setting.IntervalMinutes*60)
+			if reached > progress.slots {
+				progress.slots = reached
+				due = append(due, dueCapture{gameID: status.GameID, maxCount: setting.MaxCount})
+			}
+		}
+		service.progress[status.GameID] = progress
+	}
+	for gameID := range service.progress {
+		if _, ok := active[gameID]; !ok {
+			delete(service.progress, gameID)
+		}
+	}
+	service.mu.Unlock()
+
+	for _, item := range due {
+		captured, ok, err := service.capturer.CaptureAutoScreenshot(ctx, item.gameID, item.maxCount)
+		if err != nil {
+			service.logger.Warn("定期スクリーンショットの撮影に失敗", "gameId", item.gameID, "error", err)
+			continue
+		}
+		if !ok {
+			service.logger.Debug("ゲームが前面に無いため定期スクリーンショットを見送り", "gameId", item.gameID)
+			continue
+		}
+		service.logger.Info("定期スクリーンショットを撮影", "gameId", item.gameID, "path", captured.Path)
+	}
+}
+
+// loadSettings はゲームごとの設定を読み込む（初回のみ DB から読み、以降はキャッシュを返す）。
+// service.mu を保持した状態で呼ぶ。
+func (service *AutoScreenshotService) loadSettings(ctx context.Context) (map[string]domain.AutoScreenshotSetting, error) {
+	if service.settingsReady {
+		return service.settings, nil
+	}
+	raw, err := service.repository.GetSetting(ctx, autoScreenshotSettingKey)
+	if err != nil {
+		service.logger.Error("定期スクリーンショット設定の取得に失敗", "error", err)
+		return nil, newServiceError("定期スクリーンショット設定の取得に失敗しました", err.Error())
+	}
+	settings := make(map[string]domain.AutoScreenshotSetting)
+	if strings.TrimSpace(raw) != "" {
+		if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+			// 壊れている場合は自動撮影を止めるだけで済むため、全ゲーム無効として扱う。
+			service.logger.Warn("定期スクリーンショット設定の解析に失敗（全ゲーム無効として扱う）", "error", err)
+			settings = make(map[string]domain.AutoScreenshotSetting)
+		}
+	}
+	for gameID, setting := range settings {
+		if ValidateAutoScreenshotSetting(setting) != nil {
+			delete(settings, gameID)
+		}
+	}
+	service.settings = settings
+	service.settingsReady = true
+	return settings, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+)
+
+type recordingAutoCapturer struct {
+	gameIDs []string
+}
+
+func (capturer *recordingAutoCapturer) CaptureAutoScreenshot(ctx context.Context, gameID string, maxCount int) (domain.CaptureResult, bool, error) {
+	capturer.gameIDs = append(capturer.gameIDs, gameID)
+	return domain.CaptureResult{GameID: gameID}, true, nil
+}
+
+func TestAutoScreenshotServiceCapturesOncePerIntervalForEnabledGames(t *testing.T) {
+	t.Parallel()
+
+	capturer := &recordingAutoCapturer{}
+	service := NewAutoScreenshotService(&fakeSettingsRepository{}, capturer, newTestLogger())
+	ctx := context.Background()
+	if err := service.SetGameSetting(ctx, "a", &domain.AutoScreenshotSetting{IntervalMinutes: 10, MaxCount: 5}); err != nil {
+		t.Fatalf("SetGameSetting: %v", err)
+	}
+
+	for _, playTime := range []int64{9 * 60, 10 * 60, 11 * 60, 25 * 60} {
+		service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", playTime), playingStatus("b", playTime)})
+	}
+	if len(capturer.gameIDs) != 2 || capturer.gameIDs[0] != "a" || capturer.gameIDs[1] != "a" {
+		t.Fatalf("expected 2 captures of the enabled game, got %v", capturer.gameIDs)
+	}
+
+	// 新しいセッション（プレイ時間が減った）では数え直す。
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 10)})
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 10*60)})
+	if len(capturer.gameIDs) != 3 {
+		t.Fatalf("new session should be counted again: %v", capturer.gameIDs)
+	}
+
+	if err := service.SetGameSetting(ctx, "a", nil); err != nil {
+		t.Fatalf("SetGameSetting(nil): %v", err)
+	}
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 60*60)})
+	if len(capturer.gameIDs) != 3 {
+		t.Fatalf("disabled game should not be captured: %v", capturer.gameIDs)
+	}
+}
+
+func TestAutoScreenshotServiceRejectsInvalidSetting(t *testing.T) {
+	t.Parallel()
+
+	service := NewAutoScreenshotService(&fakeSettingsRepository{}, &recordingAutoCapturer{}, newTestLogger())
+	for _, setting := range []domain.AutoScreenshotSetting{
+		{IntervalMinutes: 0, MaxCount: 10},
+		{IntervalMinutes: MaxAutoScreenshotIntervalMinutes + 1, MaxCount: 10},
+		{IntervalMinutes: 5, MaxCount: 0},
+	} {
+		if err := service.SetGameSetting(context.Background(), "a", &setting); err == nil {
+			t.Fatalf("expected validation error for %+v", setting)
+		}
+	}
+}
+
+func TestCaptureAutoScreenshotSkipsWhenGameIsNotForeground(t *testing.T) {
+	t.Parallel()
+
+	service := NewScreenshotService(config.Config{AppDataDir: t.TempDir()}, fakeScreenshotRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game", ExePath: "game.exe"}, nil
+		},
+	}, resolverReturning(1234), newTestLogger())
+	service.foregroundFunc = func() (int, string, error) { return 999, "browser.exe", nil }
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		t.Fatalf("capture should not run while another window is in front")
+		return captureDetail{}, nil
+	}
+
+	_, captured, err := service.CaptureAutoScreenshot(context.Background(), "game-1", 5)
+	if err != nil || captured {
+		t.Fatalf("expected skip without error, got captured=%v err=%v", captured, err)
+	}
+}
+
+func TestCaptureAutoScreenshotPrunesOnlyAutoCaptures(t *testing.T) {
+	t.Parallel()
+
+	appDataDir := t.TempDir()
+	service := NewScreenshotService(config.Config{AppDataDir: appDataDir}, fakeScreenshotRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game", ExePath: "game.exe"}, nil
+		},
+	}, resolverReturning(1234), newTestLogger())
+	service.foregroundFunc = func() (int, string, error) { return 1234, "game.exe", nil }
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		return captureDetail{}, os.WriteFile(outPath, []byte("png"), 0o600)
+	}
+	saveDir := filepath.Join(localScreenshotsRoot(appDataDir), "game-1")
+	if err := os.MkdirAll(saveDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		"20200101_000000_000_game-1_auto.png",
+		"20200101_000100_000_game-1_auto.png",
+		"20200101_000200_000_game-1.png",
+	} {
+		if err := os.WriteFile(filepath.Join(saveDir, name), []byte("png"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, captured, err := service.CaptureAutoScreenshot(context.Background(), "game-1", 2)
+	if err != nil || !captured {
+		t.Fatalf("expected capture, got captured=%v err=%v", captured, err)
+	}
+	if _, err := os.Stat(filepath.Join(saveDir, "20200101_000000_000_game-1_auto.png")); !os.IsNotExist(err) {
+		t.Fatalf("oldest auto capture should be pruned, stat err=%v", err)
+	}
+	for _, name := range []string{"20200101_000100_000_game-1_auto.png", "20200101_000200_000_game-1.png"} {
+		if _, err := os.Stat(filepath.Join(saveDir, name)); err != nil {
+			t.Fatalf("%s should be kept: %v", name, err)
+		}
+	}
+}
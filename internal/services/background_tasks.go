@@ -0,0 +1,56 @@
+// 終了時に完了を待つバックグラウンド処理の追跡を提供する。
+package services
+
+import "sync"
+
+// BackgroundTasks は実行中のバックグラウンド処理（セッション保存後の同期、終了後コマンドなど）を数える。
+// 終了時や DB を閉じる前に Close で新しい処理を止め、Wait で実行中の処理が終わるのを待つ。
+// nil の BackgroundTasks でも Go は使え、その場合は追跡せずに実行する。
+type BackgroundTasks struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewBackgroundTasks は BackgroundTasks を生成する。
+func NewBackgroundTasks() *BackgroundTasks {
+	return &BackgroundTasks{}
+}
+
+// Go は fn を goroutine で実行する。Close 後は実行せずに false を返す。
+func (tasks *BackgroundTasks) Go(fn func()) bool {
+	if tasks == nil {
+		go fn()
+		return true
+	}
+	tasks.mu.Lock()
+	if tasks.closed {
+		tasks.mu.Unlock()
+		return false
+	}
+	tasks.wg.Add(1)
+	tasks.mu.Unlock()
+	go func() {
+		defer tasks.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+// Close は以後の Go を受け付けなくする。実行中の処理は止めない。多重呼び出しは安全。
+func (tasks *BackgroundTasks) Close() {
+	if tasks == nil {
+		return
+	}
+	tasks.mu.Lock()
+	defer tasks.mu.Unlock()
+	tasks.closed = true
+}
+
+// Wait は実行中の処理がすべて終わるまで待つ。新しい処理が始まらないよう Close の後に呼ぶ。
+func (tasks *BackgroundTasks) Wait() {
+	if tasks == nil {
+		return
+	}
+	tasks.wg.Wait()
+}
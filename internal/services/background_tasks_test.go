@@ -0,0 +1,58 @@
+package services
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackgroundTasksWaitsForRunningAndRejectsAfterClose(t *testing.T) {
+	t.Parallel()
+
+	tasks := NewBackgroundTasks()
+	release := make(chan struct{})
+	var finished atomic.Bool
+	if !tasks.Go(func() {
+		<-release
+		finished.Store(true)
+	}) {
+		t.Fatal("Go before Close should start the task")
+	}
+	tasks.Close()
+	if tasks.Go(func() { t.Error("task after Close must not run") }) {
+		t.Fatal("Go after Close should report false")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		tasks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Wait returned before the running task finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait did not return")
+	}
+	if !finished.Load() {
+		t.Fatal("running task should have finished")
+	}
+}
+
+func TestNilBackgroundTasksRunsWithoutTracking(t *testing.T) {
+	t.Parallel()
+
+	var tasks *BackgroundTasks
+	ran := make(chan struct{})
+	if !tasks.Go(func() { close(ran) }) {
+		t.Fatal("nil BackgroundTasks should run the task")
+	}
+	<-ran
+	tasks.Close()
+	tasks.Wait()
+}
@@ -0,0 +1,61 @@
+// ローカル変更履歴（変更ジャーナル）の参照と保持期間の管理を提供する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// changeJournalRetention はジャーナルを残す期間。
+	changeJournalRetention = 30 * 24 * time.Hour
+	// changeHistoryLimit は1回の取得で返す最大件数。
+	changeHistoryLimit = 200
+)
+
+// ChangeJournalService は変更ジャーナルの参照を提供する。
+type ChangeJournalService struct {
+	repository ChangeJournalRepository
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewChangeJournalService は ChangeJournalService を生成する。
+func NewChangeJournalService(repository ChangeJournalRepository, logger *slog.Logger) *ChangeJournalService {
+	return &ChangeJournalService{repository: repository, logger: logger, now: time.Now}
+}
+
+// GetChangeHistory はエンティティ（game / session / route / memo）の変更履歴を新しい順に返す。
+func (service *ChangeJournalService) GetChangeHistory(ctx context.Context, entityType, entityID string) ([]domain.ChangeEntry, error) {
+	switch entityType {
+	case domain.ChangeEntityGame, domain.ChangeEntitySession, domain.ChangeEntityRoute, domain.ChangeEntityMemo:
+	default:
+		service.logger.Warn("エンティティ種別が不正です", "entityType", entityType)
+		return nil, newServiceError("エンティティ種別が不正です", "entityTypeはgame|session|route|memoのいずれかです")
+	}
+	trimmedID, detail, ok := requireNonEmpty(entityID, "entityID")
+	if !ok {
+		return nil, newServiceError("IDが不正です", detail)
+	}
+	entries, err := service.repository.ListChangeHistory(ctx, entityType, trimmedID, changeHistoryLimit)
+	if err != nil {
+		service.logger.Error("変更履歴の取得に失敗", "entityType", entityType, "entityId", trimmedID, "error", err)
+		return nil, newServiceError("変更履歴の取得に失敗しました", err.Error())
+	}
+	return entries, nil
+}
+
+// PruneExpired は保持期間を過ぎたジャーナルを削除する。失敗しても起動は止めない。
+func (service *ChangeJournalService) PruneExpired(ctx context.Context) {
+	removed, err := service.repository.PruneChangeJournal(ctx, service.now().Add(-changeJournalRetention))
+	if err != nil {
+		service.logger.Warn("変更ジャーナルの整理に失敗", "error", err)
+		return
+	}
+	if removed > 0 {
+		service.logger.Info("古い変更ジャーナルを削除", "count", removed)
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+type fakeChangeJournalRepository struct {
+	listedType  string
+	listedLimit int
+	prunedAt    time.Time
+}
+
+func (r *fakeChangeJournalRepository) ListChangeHistory(ctx context.Context, entityType, entityID string, limit int) ([]domain.ChangeEntry, error) {
+	r.listedType = entityType
+	r.listedLimit = limit
+	return []domain.ChangeEntry{{EntityType: entityType, EntityID: entityID}}, nil
+}
+
+func (r *fakeChangeJournalRepository) PruneChangeJournal(ctx context.Context, before time.Time) (int64, error) {
+	r.prunedAt = before
+	return 0, nil
+}
+
+func TestChangeJournalServiceValidatesEntityType(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeChangeJournalRepository{}
+	service := NewChangeJournalService(repo, newTestLogger())
+
+	if _, err := service.GetChangeHistory(context.Background(), "unknown", "id"); err == nil {
+		t.Fatal("expected error for unknown entity type")
+	}
+	if _, err := service.GetChangeHistory(context.Background(), domain.ChangeEntityGame, " "); err == nil {
+		t.Fatal("expected error for empty id")
+	}
+	entries, err := service.GetChangeHistory(context.Background(), domain.ChangeEntityGame, " game-1 ")
+	if err != nil || len(entries) != 1 || entries[0].EntityID != "game-1" || repo.listedLimit != changeHistoryLimit {
+		t.Fatalf("unexpected result: %+v err=%v", entries, err)
+	}
+}
+
+func TestChangeJournalServicePrunesByRetention(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeChangeJournalRepository{}
+	service := NewChangeJournalService(repo, newTestLogger())
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	service.PruneExpired(context.Background())
+	if !repo.prunedAt.Equal(now.Add(-changeJournalRetention)) {
+		t.Fatalf("unexpected prune cutoff: %v", repo.prunedAt)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+// 非Windows向けの動画クリップ録画入力のスタブ実装。
+package services
+
+import "errors"
+
+// clipInputArgs は非Windowsではサポート外。
+func clipInputArgs(pid int) ([]string, error) {
+	return nil, errors.New("clip recording is only supported on Windows")
+}
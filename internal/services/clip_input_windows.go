@@ -0,0 +1,23 @@
+//go:build windows
+
+// Windows向けにゲームウィンドウの範囲を録画する ffmpeg の入力を組み立てる。
+package services
+
+import "errors"
+
+// clipInputArgs は pid のウィンドウのクライアント領域を gdigrab で録る入力引数を返す。
+// ウィンドウの上に重なったものも映るが、WGC のようにアプリ側でフレームを受け渡す必要がない。
+func clipInputArgs(pid int) ([]string, error) {
+	hwnd := findProcessWindow(uint32(pid))
+	if hwnd == 0 {
+		return nil, errors.New("録画するウィンドウが見つかりません")
+	}
+	if iconic, _, _ := procIsIconic.Call(uintptr(hwnd)); iconic != 0 {
+		return nil, errors.New("ウィンドウが最小化されています")
+	}
+	rect, err := windowScreenRect(hwnd, true)
+	if err != nil {
+		return nil, err
+	}
+	return gdigrabRegionArgs(rect)
+}
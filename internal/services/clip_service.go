@@ -0,0 +1,635 @@
+// プレイ中のゲームウィンドウを常時録画し、直近の映像を動画クリップとして保存するサービス。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// MinClipBufferSeconds / MaxClipBufferSeconds はクリップとして保存できる秒数の範囲（0 は録画しない）。
+	MinClipBufferSeconds = 5
+	MaxClipBufferSeconds = 300
+
+	// clipSegmentSeconds は録画を区切るセグメントの長さ。保存するクリップの長さはこの単位で丸まる。
+	clipSegmentSeconds = 2
+	clipFrameRate      = 30
+	// clipStopTimeout は ffmpeg に終了を指示してから強制終了するまでの猶予。
+	clipStopTimeout = 3 * time.Second
+	// clipRetryInterval は録画の開始に失敗した（ffmpeg が異常終了した）ゲームで再試行するまでの間隔。
+	clipRetryInterval = time.Minute
+	// clipSaveTimeout はセグメントを mp4 にまとめる ffmpeg の実行時間の上限。
+	clipSaveTimeout = time.Minute
+
+	clipsDirName       = "clips"
+	clipBufferDirName  = "clip-buffer"
+	clipSegmentPattern = "seg%03d.ts"
+	clipExt            = ".mp4"
+)
+
+// ValidateClipBufferSeconds はクリップの秒数を検証する。0 は録画しないことを表す。
+func ValidateClipBufferSeconds(seconds int) error {
+	if seconds != 0 && (seconds < MinClipBufferSeconds || seconds > MaxClipBufferSeconds) {
+		return fmt.Errorf("clipBufferSeconds must be 0 or %d-%d", MinClipBufferSeconds, MaxClipBufferSeconds)
+	}
+	return nil
+}
+
+// clipProcess は録画中の ffmpeg プロセス。テストで差し替える。
+type clipProcess interface {
+	// stop は録画を終え、timeout までに終わらなければ強制終了する。
+	stop(timeout time.Duration) error
+	// exited はプロセスが終了すると閉じられる。
+	exited() <-chan struct{}
+}
+
+// clipUpdate は Update で受け取った、まだ反映していないスキャン結果。
+type clipUpdate struct {
+	ctx      context.Context
+	statuses []domain.MonitoringGameStatus
+}
+
+// clipRecording は1ゲーム分の録画。input は録画範囲を表す ffmpeg の入力引数で、変わったら録り直す。
+type clipRecording struct {
+	gameID  string
+	input   []string
+	dir     string
+	process clipProcess
+}
+
+// ClipService はプレイ中のゲームウィンドウを ffmpeg で常時録画し、ホットキーなどで直近の映像を mp4 に保存する。
+// 録画は clipSegmentSeconds 秒ごとのセグメントを循環して上書きするリングバッファで、
+// 保存時に直近のセグメントだけを再エンコードせずにつなげる。
+type ClipService struct {
+	repository ScreenshotRepository
+	resolver   ProcessIDResolver
+	logger     *slog.Logger
+	appDataDir string
+
+	// mu は以下の録画状態を保護する。
+	mu            sync.Mutex
+	bufferSeconds int
+	ffmpegPath    string
+	recording     *clipRecording
+	// retryAfter は録画に失敗したゲームの再試行時刻（ゲームID → 時刻）。
+	retryAfter map[string]time.Time
+	// pending は反映待ちの最新のスキャン結果、updating は反映中の Update があるか。
+	pending  *clipUpdate
+	updating bool
+
+	// workMu は録画の開始（セグメントの削除を含む）・停止と、SaveClip のセグメントの書き出しを直列化する。
+	// mu より先に取る。
+	workMu sync.Mutex
+
+	// inputArgsFunc は PID のウィンドウを録画する ffmpeg の入力引数を返す。テストで差し替える。
+	inputArgsFunc func(pid int) ([]string, error)
+	// startFunc は録画の ffmpeg を起動する。テストで差し替える。
+	startFunc func(ffmpegPath string, args []string) (clipProcess, error)
+	// runFunc はクリップを書き出す ffmpeg を実行し、出力を返す。テストで差し替える。
+	runFunc func(ctx context.Context, ffmpegPath string, args []string) ([]byte, error)
+	now     func() time.Time
+}
+
+// NewClipService は ClipService を生成する。resolver は nil を許容する（nil の場合は録画しない）。
+func NewClipService(
+	cfg config.Config,
+	repository ScreenshotRepository,
+	resolver ProcessIDResolver,
+	logger *slog.Logger,
+) *ClipService {
+	return &ClipService{
+		repository:    repository,
+		resolver:      resolver,
+		logger:        logger,
+		appDataDir:    cfg.AppDataDir,
+		bufferSeconds: cfg.ClipBufferSeconds,
+		ffmpegPath:    strings.TrimSpace(cfg.ClipFFmpegPath),
+		retryAfter:    make(map[string]time.Time),
+		inputArgsFunc: clipInputArgs,
+		startFunc:     startFFmpegProcess,
+		runFunc:       runFFmpeg,
+		now:           time.Now,
+	}
+}
+
+// SetBufferSeconds はクリップの秒数を更新する。録画中なら止め、次のスキャンで新しい秒数で録り直す。
+func (service *ClipService) SetBufferSeconds(seconds int) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.bufferSeconds == seconds {
+		return
+	}
+	service.bufferSeconds = seconds
+	service.stopLocked()
+}
+
+// SetFFmpegPath は録画に使う ffmpeg のパスを更新する。録画中なら止め、次のスキャンで録り直す。
+func (service *ClipService) SetFFmpegPath(path string) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	trimmed := strings.TrimSpace(path)
+	if service.ffmpegPath == trimmed {
+		return
+	}
+	service.ffmpegPath = trimmed
+	clear(service.retryAfter)
+	service.stopLocked()
+}
+
+// Update はプロセス監視のスキャンごとに呼ばれ、プレイ中（中断していない）のゲームがあれば録画する。
+// 録画中のゲームが終わったとき・別のゲームに替わったとき・ウィンドウの位置や大きさが変わったときは録り直す。
+// 録画できなかったゲームは clipRetryInterval の間は再試行しない。
+// 録画の停止・開始には数秒かかることがあるため、反映中に来たスキャン結果は最新の1件だけを残し、
+// 反映中の呼び出しが続けて反映する（後から来た呼び出しは待たずに戻る）。
+func (service *ClipService) Update(ctx context.Context, statuses []domain.MonitoringGameStatus) {
+	service.mu.Lock()
+	service.pending = &clipUpdate{ctx: ctx, statuses: statuses}
+	if service.updating {
+		service.mu.Unlock()
+		return
+	}
+	service.updating = true
+	service.mu.Unlock()
+	for {
+		service.mu.Lock()
+		next := service.pending
+		service.pending = nil
+		if next == nil {
+			service.updating = false
+			service.mu.Unlock()
+			return
+		}
+		service.mu.Unlock()
+		service.workMu.Lock()
+		service.applyUpdate(next.ctx, next.statuses)
+		service.workMu.Unlock()
+	}
+}
+
+// applyUpdate は1回分のスキャン結果を録画に反映する。service.workMu を保持した状態で呼ぶ。
+func (service *ClipService) applyUpdate(ctx context.Context, statuses []domain.MonitoringGameStatus) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.bufferSeconds == 0 {
+		service.stopLocked()
+		return
+	}
+	gameID := ""
+	for _, status := range statuses {
+		if status.IsPlaying && !status.IsPaused {
+			gameID = status.GameID
+			break
+		}
+	}
+	if gameID == "" {
+		service.stopLocked()
+		return
+	}
+
+	recording := service.recording
+	if recording != nil {
+		select {
+		case <-recording.process.exited():
+			service.logger.Warn("クリップの録画が終了しました", "gameId", recording.gameID)
+			service.retryAfter[recording.gameID] = service.now().Add(clipRetryInterval)
+			service.recording = nil
+			recording = nil
+		default:
+		}
+	}
+	if recording == nil {
+		if retry, ok := service.retryAfter[gameID]; ok && service.now().Before(retry) {
+			return
+		}
+	}
+
+	pid, err := service.resolveGamePID(ctx, gameID)
+	if err != nil || pid == 0 {
+		service.stopLocked()
+		return
+	}
+	input, err := service.inputArgsFunc(pid)
+	if err != nil {
+		// 最小化中などは一時的なので、録画中ならそのまま続ける。
+		if recording == nil {
+			service.logger.Debug("クリップの録画範囲を決められません", "gameId", gameID, "error", err)
+		}
+		return
+	}
+	if recording != nil && recording.gameID == gameID && slices.Equal(recording.input, input) {
+		return
+	}
+	service.stopLocked()
+	if err := service.startLocked(gameID, input); err != nil {
+		service.logger.Warn("クリップの録画を開始できません", "gameId", gameID, "error", err)
+		service.retryAfter[gameID] = service.now().Add(clipRetryInterval)
+		return
+	}
+	delete(service.retryAfter, gameID)
+}
+
+// resolveGamePID はゲームの実行ファイルから稼働中のプロセスIDを引く。見つからなければ 0。
+func (service *ClipService) resolveGamePID(ctx context.Context, gameID string) (int, error) {
+	if service.resolver == nil {
+		return 0, nil
+	}
+	game, err := service.repository.GetGameByID(ctx, gameID)
+	if err != nil || game == nil || strings.TrimSpace(game.ExePath) == "" {
+		return 0, err
+	}
+	pids, err := service.resolver.FindProcessIDsByExe(game.ExePath)
+	if err != nil || len(pids) == 0 {
+		return 0, err
+	}
+	return pids[0], nil
+}
+
+// startLocked は gameID の録画を始める。前回のセグメントは大きさが違いうるため消してから録る。
+// service.mu を保持した状態で呼ぶ。
+func (service *ClipService) startLocked(gameID string, input []string) error {
+	ffmpegPath, err := resolveFFmpegPath(service.ffmpegPath)
+	if err != nil {
+		return err
+	}
+	dir := service.bufferDir()
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	process, err := service.startFunc(ffmpegPath, buildClipRecordArgs(input, service.bufferSeconds, dir))
+	if err != nil {
+		return err
+	}
+	service.recording = &clipRecording{gameID: gameID, input: input, dir: dir, process: process}
+	service.logger.Info("クリップの録画を開始", "gameId", gameID, "bufferSeconds", service.bufferSeconds)
+	return nil
+}
+
+// stopLocked は録画中なら止める。service.mu を保持した状態で呼ぶ。
+func (service *ClipService) stopLocked() {
+	recording := service.recording
+	if recording == nil {
+		return
+	}
+	service.recording = nil
+	if err := recording.process.stop(clipStopTimeout); err != nil {
+		service.logger.Warn("クリップの録画の停止に失敗", "gameId", recording.gameID, "error", err)
+		return
+	}
+	service.logger.Info("クリップの録画を停止", "gameId", recording.gameID)
+}
+
+// SaveClip は録画中の直近 bufferSeconds 秒を clips/<gameID> に mp4 で保存する。
+// セグメントを再エンコードせずにつなげるため、長さは clipSegmentSeconds 秒単位で前後する。
+// 書き出しの間に録り直しでセグメントが消されないよう、書き出しが終わるまで Update の反映を待たせる。
+func (service *ClipService) SaveClip(ctx context.Context) (domain.ClipInfo, error) {
+	service.workMu.Lock()
+	defer service.workMu.Unlock()
+	service.mu.Lock()
+	recording := service.recording
+	bufferSeconds := service.bufferSeconds
+	configuredPath := service.ffmpegPath
+	service.mu.Unlock()
+	if recording == nil {
+		return domain.ClipInfo{}, newServiceError("録画していません", "クリップの録画を有効にして、ゲームをプレイ中に保存してください")
+	}
+
+	segments, err := listClipSegments(recording.dir)
+	if err != nil {
+		return domain.ClipInfo{}, newServiceError("クリップの保存に失敗しました", err.Error())
+	}
+	segments = selectClipSegments(segments, bufferSeconds)
+	if len(segments) == 0 {
+		return domain.ClipInfo{}, newServiceError("録画データがまだありません", "録画が始まってから数秒待ってください")
+	}
+	ffmpegPath, err := resolveFFmpegPath(configuredPath)
+	if err != nil {
+		return domain.ClipInfo{}, err
+	}
+
+	saveDir := filepath.Join(service.clipsRoot(), recording.gameID)
+	if err := os.MkdirAll(saveDir, 0o755); err != nil {
+		return domain.ClipInfo{}, newServiceError("クリップの保存に失敗しました", err.Error())
+	}
+	now := service.now()
+	name := fmt.Sprintf("%s_%03d_%s%s",
+		now.Format("20060102_150405"), now.Nanosecond()/int(time.Millisecond), recording.gameID, clipExt)
+	outPath := filepath.Join(saveDir, name)
+
+	listFile, err := os.CreateTemp("", "cloudlaunch-clip-*.txt")
+	if err != nil {
+		return domain.ClipInfo{}, newServiceError("クリップの保存に失敗しました", err.Error())
+	}
+	defer func() { _ = os.Remove(listFile.Name()) }()
+	_, writeErr := io.WriteString(listFile, buildClipConcatList(segments))
+	if closeErr := listFile.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		return domain.ClipInfo{}, newServiceError("クリップの保存に失敗しました", writeErr.Error())
+	}
+
+	saveCtx, cancel := context.WithTimeout(ctx, clipSaveTimeout)
+	defer cancel()
+	output, err := service.runFunc(saveCtx, ffmpegPath, buildClipConcatArgs(listFile.Name(), outPath))
+	if err != nil {
+		_ = os.Remove(outPath)
+		service.logger.Warn("クリップの書き出しに失敗", "gameId", recording.gameID, "error", err, "output", strings.TrimSpace(string(output)))
+		return domain.ClipInfo{}, newServiceError("クリップの保存に失敗しました", err.Error())
+	}
+	info, err := os.Stat(outPath)
+	if err != nil {
+		return domain.ClipInfo{}, newServiceError("クリップの保存に失敗しました", err.Error())
+	}
+	service.logger.Info("クリップを保存", "gameId", recording.gameID, "output", outPath, "segments", len(segments))
+	return domain.ClipInfo{
+		GameID:    recording.gameID,
+		Name:      name,
+		Path:      outPath,
+		SizeBytes: info.Size(),
+		CreatedAt: info.ModTime(),
+	}, nil
+}
+
+// Status は録画の状態を返す。
+func (service *ClipService) Status() domain.ClipRecorderStatus {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	status := domain.ClipRecorderStatus{BufferSeconds: service.bufferSeconds}
+	if service.recording != nil {
+		status.Recording = true
+		status.GameID = service.recording.gameID
+	}
+	return status
+}
+
+// ListGameClips はゲームの保存済みクリップを新しい順に返す。
+func (service *ClipService) ListGameClips(gameID string) ([]domain.ClipInfo, error) {
+	trimmedID, ok := clipGameID(gameID)
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", "gameIDが空か不正です")
+	}
+	dir := filepath.Join(service.clipsRoot(), trimmedID)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []domain.ClipInfo{}, nil
+	}
+	if err != nil {
+		return nil, newServiceError("クリップ一覧の取得に失敗しました", err.Error())
+	}
+	clips := make([]domain.ClipInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), clipExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		clips = append(clips, domain.ClipInfo{
+			GameID:    trimmedID,
+			Name:      entry.Name(),
+			Path:      filepath.Join(dir, entry.Name()),
+			SizeBytes: info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+	sort.SliceStable(clips, func(i, j int) bool { return clips[i].CreatedAt.After(clips[j].CreatedAt) })
+	return clips, nil
+}
+
+// DeleteGameClip はゲームの保存済みクリップを1件削除する。name は ListGameClips の Name。
+func (service *ClipService) DeleteGameClip(gameID string, name string) error {
+	trimmedID, ok := clipGameID(gameID)
+	if !ok {
+		return newServiceError("ゲームIDが不正です", "gameIDが空か不正です")
+	}
+	trimmedName := strings.TrimSpace(name)
+	if trimmedName == "" || filepath.Base(trimmedName) != trimmedName || !strings.EqualFold(filepath.Ext(trimmedName), clipExt) {
+		return newServiceError("クリップ名が不正です", "nameはクリップのファイル名（.mp4）です")
+	}
+	if err := os.Remove(filepath.Join(service.clipsRoot(), trimmedID, trimmedName)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return newServiceError("クリップが見つかりません", trimmedName)
+		}
+		return newServiceError("クリップの削除に失敗しました", err.Error())
+	}
+	return nil
+}
+
+// Close は録画中なら止める。
+func (service *ClipService) Close() {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.stopLocked()
+}
+
+func (service *ClipService) baseDir() string {
+	baseDir := strings.TrimSpace(service.appDataDir)
+	if baseDir == "" {
+		baseDir = os.TempDir()
+	}
+	return baseDir
+}
+
+func (service *ClipService) clipsRoot() string {
+	return filepath.Join(service.baseDir(), clipsDirName)
+}
+
+func (service *ClipService) bufferDir() string {
+	return filepath.Join(service.baseDir(), clipBufferDirName)
+}
+
+// clipGameID はパスの一部に使うゲームIDを検証する（区切り文字や .. を含むものは拒否する）。
+func clipGameID(gameID string) (string, bool) {
+	trimmed := strings.TrimSpace(gameID)
+	if trimmed == "" || trimmed == "." || trimmed == ".." || filepath.Base(trimmed) != trimmed {
+		return "", false
+	}
+	return trimmed, true
+}
+
+// clipSegment は録画セグメントのファイル1件。
+type clipSegment struct {
+	path    string
+	modTime time.Time
+}
+
+// listClipSegments は録画ディレクトリのセグメントを列挙する。
+func listClipSegments(dir string) ([]clipSegment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]clipSegment, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".ts" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Size() == 0 {
+			continue
+		}
+		segments = append(segments, clipSegment{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()})
+	}
+	return segments, nil
+}
+
+// selectClipSegments は古い順に並べ、直近 bufferSeconds 秒分のセグメントを返す。
+// 最新のセグメントは書き込み途中のため、1つ多めに含める。
+func selectClipSegments(segments []clipSegment, bufferSeconds int) []clipSegment {
+	sorted := append([]clipSegment(nil), segments...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].modTime.Before(sorted[j].modTime) })
+	count := (bufferSeconds+clipSegmentSeconds-1)/clipSegmentSeconds + 1
+	if len(sorted) > count {
+		sorted = sorted[len(sorted)-count:]
+	}
+	return sorted
+}
+
+// buildClipRecordArgs は input の範囲を clipSegmentSeconds 秒ごとのセグメントに循環して書き込む ffmpeg の引数を組み立てる。
+// セグメントの境目でつなげられるよう、キーフレームを区切りごとに強制する。
+func buildClipRecordArgs(input []string, bufferSeconds int, dir string) []string {
+	wrap := (bufferSeconds+clipSegmentSeconds-1)/clipSegmentSeconds + 2
+	args := []string{"-hide_banner", "-loglevel", "error", "-y"}
+	args = append(args, input...)
+	return append(args,
+		"-c:v", "libx264", "-preset", "veryfast", "-pix_fmt", "yuv420p",
+		"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", clipSegmentSeconds),
+		"-f", "segment",
+		"-segment_time", strconv.Itoa(clipSegmentSeconds),
+		"-segment_wrap", strconv.Itoa(wrap),
+		"-segment_format", "mpegts",
+		"-reset_timestamps", "1",
+		filepath.Join(dir, clipSegmentPattern),
+	)
+}
+
+// buildClipConcatList は ffmpeg の concat demuxer に渡すファイル一覧を組み立てる。
+func buildClipConcatList(segments []clipSegment) string {
+	var builder strings.Builder
+	for _, segment := range segments {
+		path := filepath.ToSlash(segment.path)
+		builder.WriteString("file '" + strings.ReplaceAll(path, "'", `'\''`) + "'\n")
+	}
+	return builder.String()
+}
+
+// buildClipConcatArgs はセグメントを再エンコードせずに1つの mp4 にまとめる ffmpeg の引数を組み立てる。
+func buildClipConcatArgs(listPath string, outPath string) []string {
+	return []string{
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-f", "concat", "-safe", "0", "-i", listPath,
+		"-c", "copy", "-movflags", "+faststart",
+		outPath,
+	}
+}
+
+// gdigrabRegionArgs は画面上の rect を gdigrab で録る ffmpeg の入力引数を返す。
+// libx264 の yuv420p は幅・高さが偶数である必要があるため切り捨てる。
+func gdigrabRegionArgs(rect screenRect) ([]string, error) {
+	width := int(rect.Right-rect.Left) &^ 1
+	height := int(rect.Bottom-rect.Top) &^ 1
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("録画する範囲が空です")
+	}
+	return []string{
+		"-f", "gdigrab",
+		"-framerate", strconv.Itoa(clipFrameRate),
+		"-offset_x", strconv.Itoa(int(rect.Left)),
+		"-offset_y", strconv.Itoa(int(rect.Top)),
+		"-video_size", fmt.Sprintf("%dx%d", width, height),
+		"-draw_mouse", "0",
+		"-i", "desktop",
+	}, nil
+}
+
+// resolveFFmpegPath は設定されたパス、実行ファイルと同じフォルダ、PATH の順に ffmpeg を探す。
+func resolveFFmpegPath(configured string) (string, error) {
+	if trimmed := strings.TrimSpace(configured); trimmed != "" {
+		if _, err := os.Stat(trimmed); err != nil {
+			return "", newServiceError("ffmpeg が見つかりません", trimmed)
+		}
+		return trimmed, nil
+	}
+	name := "ffmpeg"
+	if runtime.GOOS == "windows" {
+		name = "ffmpeg.exe"
+	}
+	if dir := config.ExecutableDir(); dir != "" {
+		candidate := filepath.Join(dir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+	return "", newServiceError("ffmpeg が見つかりません", "ffmpeg を実行ファイルと同じフォルダに置くか、パスを設定してください")
+}
+
+// ffmpegProcess は録画中の ffmpeg。標準入力に q を送ると書きかけのセグメントを閉じて終わる。
+type ffmpegProcess struct {
+	command *exec.Cmd
+	stdin   io.WriteCloser
+	done    chan struct{}
+}
+
+func startFFmpegProcess(ffmpegPath string, args []string) (clipProcess, error) {
+	command := execCommandHidden(context.Background(), ffmpegPath, args...)
+	stdin, err := command.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+	process := &ffmpegProcess{command: command, stdin: stdin, done: make(chan struct{})}
+	go func() {
+		_ = command.Wait()
+		close(process.done)
+	}()
+	return process, nil
+}
+
+func (process *ffmpegProcess) stop(timeout time.Duration) error {
+	_, _ = io.WriteString(process.stdin, "q")
+	_ = process.stdin.Close()
+	select {
+	case <-process.done:
+		return nil
+	case <-time.After(timeout):
+	}
+	if err := process.command.Process.Kill(); err != nil {
+		return err
+	}
+	<-process.done
+	return errors.New("ffmpeg did not exit in time")
+}
+
+func (process *ffmpegProcess) exited() <-chan struct{} {
+	return process.done
+}
+
+func runFFmpeg(ctx context.Context, ffmpegPath string, args []string) ([]byte, error) {
+	return execCommandHidden(ctx, ffmpegPath, args...).CombinedOutput()
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+)
+
+type fakeClipProcess struct {
+	stopped bool
+	done    chan struct{}
+}
+
+func (process *fakeClipProcess) stop(time.Duration) error {
+	process.stopped = true
+	return nil
+}
+
+func (process *fakeClipProcess) exited() <-chan struct{} {
+	return process.done
+}
+
+// newTestClipService は ffmpeg を起動しない ClipService を返す。started に起動した録画を積む。
+func newTestClipService(t *testing.T, started *[]*fakeClipProcess) (*ClipService, *[]string) {
+	t.Helper()
+	dir := t.TempDir()
+	ffmpegPath := filepath.Join(dir, "ffmpeg")
+	if err := os.WriteFile(ffmpegPath, nil, 0o755); err != nil {
+		t.Fatalf("write ffmpeg: %v", err)
+	}
+	repository := fakeScreenshotRepository{getGameByIDFn: func(_ context.Context, gameID string) (*domain.Game, error) {
+		return &domain.Game{ID: gameID, ExePath: `C:\games\` + gameID + `.exe`}, nil
+	}}
+	service := NewClipService(
+		config.Config{AppDataDir: dir, ClipBufferSeconds: 10, ClipFFmpegPath: ffmpegPath},
+		repository, resolverReturning(42), newTestLogger(),
+	)
+	input := []string{"-i", "desktop"}
+	service.inputArgsFunc = func(int) ([]string, error) { return input, nil }
+	service.startFunc = func(string, []string) (clipProcess, error) {
+		process := &fakeClipProcess{done: make(chan struct{})}
+		*started = append(*started, process)
+		return process, nil
+	}
+	return service, &input
+}
+
+func TestClipServiceUpdateFollowsPlayingGame(t *testing.T) {
+	t.Parallel()
+
+	var started []*fakeClipProcess
+	service, input := newTestClipService(t, &started)
+	ctx := context.Background()
+
+	service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", 60)})
+	service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", 65)})
+	if len(started) != 1 || service.Status().GameID != "a" {
+		t.Fatalf("expected one recording of a, got %d (%+v)", len(started), service.Status())
+	}
+
+	// ウィンドウの範囲が変わったら録り直す。
+	*input = []string{"-i", "desktop", "-video_size", "800x600"}
+	service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", 70)})
+	if len(started) != 2 || !started[0].stopped {
+		t.Fatalf("region change should restart recording: %d started", len(started))
+	}
+
+	paused := playingStatus("a", 75)
+	paused.IsPaused = true
+	service.Update(ctx, []domain.MonitoringGameStatus{paused})
+	if !started[1].stopped || service.Status().Recording {
+		t.Fatalf("paused game should stop recording: %+v", service.Status())
+	}
+}
+
+func TestClipServiceDoesNotRecordWhenDisabled(t *testing.T) {
+	t.Parallel()
+
+	var started []*fakeClipProcess
+	service, _ := newTestClipService(t, &started)
+	service.SetBufferSeconds(0)
+	service.Update(context.Background(), []domain.MonitoringGameStatus{playingStatus("a", 60)})
+	if len(started) != 0 {
+		t.Fatalf("disabled recorder should not start, got %d", len(started))
+	}
+}
+
+func TestClipServiceWaitsBeforeRetryingExitedRecorder(t *testing.T) {
+	t.Parallel()
+
+	var started []*fakeClipProcess
+	service, _ := newTestClipService(t, &started)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", 60)})
+	close(started[0].done)
+	service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", 65)})
+	if len(started) != 1 || service.Status().Recording {
+		t.Fatalf("exited recorder should not restart immediately: %d started", len(started))
+	}
+	now = now.Add(clipRetryInterval)
+	service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", 70)})
+	if len(started) != 2 {
+		t.Fatalf("recorder should restart after the retry interval: %d started", len(started))
+	}
+}
+
+func TestClipServiceSaveClipConcatenatesRecentSegments(t *testing.T) {
+	t.Parallel()
+
+	var started []*fakeClipProcess
+	service, _ := newTestClipService(t, &started)
+	if _, err := service.SaveClip(context.Background()); err == nil {
+		t.Fatal("expected error when not recording")
+	}
+	service.Update(context.Background(), []domain.MonitoringGameStatus{playingStatus("a", 60)})
+
+	bufferDir := service.bufferDir()
+	base := time.Now().Add(-time.Minute)
+	for i := 0; i < 10; i++ {
+		path := filepath.Join(bufferDir, "seg"+string(rune('0'+i))+".ts")
+		if err := os.WriteFile(path, []byte("ts"), 0o644); err != nil {
+			t.Fatalf("write segment: %v", err)
+		}
+		modTime := base.Add(time.Duration(i) * clipSegmentSeconds * time.Second)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	var concatList string
+	service.runFunc = func(_ context.Context, _ string, args []string) ([]byte, error) {
+		listPath := args[slices.Index(args, "-i")+1]
+		data, err := os.ReadFile(listPath)
+		if err != nil {
+			return nil, err
+		}
+		concatList = string(data)
+		return nil, os.WriteFile(args[len(args)-1], []byte("mp4"), 0o644)
+	}
+
+	clip, err := service.SaveClip(context.Background())
+	if err != nil {
+		t.Fatalf("SaveClip: %v", err)
+	}
+	if clip.GameID != "a" || filepath.Dir(clip.Path) != filepath.Join(service.clipsRoot(), "a") {
+		t.Fatalf("unexpected clip: %+v", clip)
+	}
+	// 10 秒分（5 セグメント）と書き込み途中の 1 セグメント。
+	lines := strings.Split(strings.TrimSpace(concatList), "\n")
+	if len(lines) != 6 || !strings.Contains(lines[0], "seg4.ts") || !strings.Contains(lines[5], "seg9.ts") {
+		t.Fatalf("unexpected concat list:\n%s", concatList)
+	}
+
+	clips, err := service.ListGameClips("a")
+	if err != nil || len(clips) != 1 || clips[0].Name != clip.Name {
+		t.Fatalf("ListGameClips = %+v, %v", clips, err)
+	}
+	if err := service.DeleteGameClip("a", "../"+clip.Name); err == nil {
+		t.Fatal("expected error for a path outside the clip directory")
+	}
+	if err := service.DeleteGameClip("a", clip.Name); err != nil {
+		t.Fatalf("DeleteGameClip: %v", err)
+	}
+	if clips, _ := service.ListGameClips("a"); len(clips) != 0 {
+		t.Fatalf("clip should be deleted: %+v", clips)
+	}
+}
+
+func TestClipServiceSerializesUpdatesAndSaveClip(t *testing.T) {
+	t.Parallel()
+
+	var started []*fakeClipProcess
+	service, _ := newTestClipService(t, &started)
+	ctx := context.Background()
+	service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", 60)})
+	segment := filepath.Join(service.bufferDir(), "seg000.ts")
+	if err := os.WriteFile(segment, []byte("ts"), 0o644); err != nil {
+		t.Fatalf("write segment: %v", err)
+	}
+
+	// 録画範囲が毎回変わり、反映のたびに録り直し（セグメントの削除）が起きるようにする。
+	var regions atomic.Int32
+	service.inputArgsFunc = func(int) ([]string, error) {
+		return []string{"-i", "desktop", "-n", strconv.Itoa(int(regions.Add(1)))}, nil
+	}
+	saving := make(chan struct{})
+	release := make(chan struct{})
+	service.runFunc = func(_ context.Context, _ string, args []string) ([]byte, error) {
+		close(saving)
+		<-release
+		if _, err := os.Stat(segment); err != nil {
+			t.Errorf("segment removed while saving: %v", err)
+		}
+		return nil, os.WriteFile(args[len(args)-1], []byte("mp4"), 0o644)
+	}
+	saved := make(chan error, 1)
+	go func() {
+		_, err := service.SaveClip(ctx)
+		saved <- err
+	}()
+	<-saving
+
+	// 書き出し中のスキャンは積み上がらず、1件の反映が待つだけで他はすぐ戻る。
+	var wg sync.WaitGroup
+	var returned atomic.Int32
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.Update(ctx, []domain.MonitoringGameStatus{playingStatus("a", int64(61+i))})
+			returned.Add(1)
+		}()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for returned.Load() < 19 {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d of 20 updates returned while saving", returned.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if err := <-saved; err != nil {
+		t.Fatalf("SaveClip: %v", err)
+	}
+	wg.Wait()
+
+	if got := regions.Load(); got < 1 || got > 2 {
+		t.Fatalf("pending scans should be coalesced, applied %d", got)
+	}
+}
+
+func TestValidateClipBufferSeconds(t *testing.T) {
+	t.Parallel()
+
+	for _, seconds := range []int{0, MinClipBufferSeconds, MaxClipBufferSeconds} {
+		if err := ValidateClipBufferSeconds(seconds); err != nil {
+			t.Fatalf("ValidateClipBufferSeconds(%d): %v", seconds, err)
+		}
+	}
+	for _, seconds := range []int{-1, 1, MaxClipBufferSeconds + 1} {
+		if err := ValidateClipBufferSeconds(seconds); err == nil {
+			t.Fatalf("ValidateClipBufferSeconds(%d) should fail", seconds)
+		}
+	}
+}
+
+func TestGdigrabRegionArgsUsesEvenSize(t *testing.T) {
+	t.Parallel()
+
+	args, err := gdigrabRegionArgs(screenRect{Left: -1920, Top: 10, Right: -1, Bottom: 731})
+	if err != nil {
+		t.Fatalf("gdigrabRegionArgs: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "-offset_x -1920 -offset_y 10 -video_size 1918x720") {
+		t.Fatalf("unexpected args: %s", joined)
+	}
+	if _, err := gdigrabRegionArgs(screenRect{Left: 0, Top: 0, Right: 1, Bottom: 100}); err == nil {
+		t.Fatal("expected error for an empty region")
+	}
+}
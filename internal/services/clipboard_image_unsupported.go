@@ -0,0 +1,16 @@
+//go:build !windows
+
+// 非Windows向けのクリップボード画像取得・設定のスタブ実装。
+package services
+
+import "errors"
+
+// readClipboardImage は非Windowsではサポート外。
+func readClipboardImage() ([]byte, error) {
+	return nil, errors.New("clipboard image is only supported on Windows")
+}
+
+// writeClipboardImage は非Windowsではサポート外。
+func writeClipboardImage(pngData []byte, dib []byte) error {
+	return errors.New("clipboard image is only supported on Windows")
+}
@@ -0,0 +1,165 @@
+//go:build windows
+
+// Windows向けにクリップボードの画像（PNG 形式または DIB）を取得・設定する。
+package services
+
+import (
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	cfDIB = 8
+	// biBitfields は BITMAPINFOHEADER の biCompression で、ヘッダー直後に色マスクが続く形式。
+	biBitfields        = 3
+	bitmapFileHeaderSz = 14
+	// gmemMoveable は GlobalAlloc の GMEM_MOVEABLE。SetClipboardData には移動可能なメモリを渡す。
+	gmemMoveable = 0x0002
+)
+
+var (
+	procOpenClipboard              = user32.NewProc("OpenClipboard")
+	procCloseClipboard             = user32.NewProc("CloseClipboard")
+	procGetClipboardData           = user32.NewProc("GetClipboardData")
+	procIsClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+	procRegisterClipboardFormatW   = user32.NewProc("RegisterClipboardFormatW")
+	procGlobalLock                 = kernel32dll.NewProc("GlobalLock")
+	procGlobalUnlock               = kernel32dll.NewProc("GlobalUnlock")
+	procGlobalSize                 = kernel32dll.NewProc("GlobalSize")
+	procRtlMoveMemory              = kernel32dll.NewProc("RtlMoveMemory")
+	procEmptyClipboard             = user32.NewProc("EmptyClipboard")
+	procSetClipboardData           = user32.NewProc("SetClipboardData")
+	procGlobalAlloc                = kernel32dll.NewProc("GlobalAlloc")
+	procGlobalFree                 = kernel32dll.NewProc("GlobalFree")
+)
+
+// readClipboardImage はクリップボードの画像を画像ファイルのバイト列で返す。
+// ブラウザ等が置く "PNG" 形式を優先し、無ければ DIB に BMP のファイルヘッダーを付けて返す。
+func readClipboardImage() ([]byte, error) {
+	// OpenClipboard は呼び出しスレッドに結び付くため、閉じるまで同じスレッドで扱う。
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if ret, _, callErr := procOpenClipboard.Call(0); ret == 0 {
+		return nil, callErr
+	}
+	defer procCloseClipboard.Call()
+
+	pngName, err := windows.UTF16PtrFromString("PNG")
+	if err != nil {
+		return nil, err
+	}
+	if pngFormat, _, _ := procRegisterClipboardFormatW.Call(uintptr(unsafe.Pointer(pngName))); pngFormat != 0 {
+		if available, _, _ := procIsClipboardFormatAvailable.Call(pngFormat); available != 0 {
+			return clipboardData(pngFormat)
+		}
+	}
+	if available, _, _ := procIsClipboardFormatAvailable.Call(cfDIB); available == 0 {
+		return nil, errors.New("clipboard has no image")
+	}
+	dib, err := clipboardData(cfDIB)
+	if err != nil {
+		return nil, err
+	}
+	return dibToBMP(dib)
+}
+
+// clipboardData は指定形式のクリップボードデータをコピーして返す。
+func clipboardData(format uintptr) ([]byte, error) {
+	handle, _, callErr := procGetClipboardData.Call(format)
+	if handle == 0 {
+		return nil, callErr
+	}
+	size, _, _ := procGlobalSize.Call(handle)
+	pointer, _, callErr := procGlobalLock.Call(handle)
+	if pointer == 0 {
+		return nil, callErr
+	}
+	defer procGlobalUnlock.Call(handle)
+	if size == 0 {
+		return nil, errors.New("clipboard data is empty")
+	}
+	// ロックしたメモリは Go のポインタにせず、RtlMoveMemory で Go 側のバッファへ写す。
+	data := make([]byte, size)
+	procRtlMoveMemory.Call(uintptr(unsafe.Pointer(&data[0])), pointer, size)
+	return data, nil
+}
+
+// writeClipboardImage はクリップボードの中身を画像（"PNG" 形式と CF_DIB）に置き換える。
+func writeClipboardImage(pngData []byte, dib []byte) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if ret, _, callErr := procOpenClipboard.Call(0); ret == 0 {
+		return callErr
+	}
+	defer procCloseClipboard.Call()
+	if ret, _, callErr := procEmptyClipboard.Call(); ret == 0 {
+		return callErr
+	}
+	if err := setClipboardData(cfDIB, dib); err != nil {
+		return err
+	}
+	// "PNG" 形式は付加的なもの。置けなくても DIB があれば貼り付けられる。
+	if pngName, err := windows.UTF16PtrFromString("PNG"); err == nil {
+		if pngFormat, _, _ := procRegisterClipboardFormatW.Call(uintptr(unsafe.Pointer(pngName))); pngFormat != 0 {
+			_ = setClipboardData(pngFormat, pngData)
+		}
+	}
+	return nil
+}
+
+// setClipboardData は data を移動可能なグローバルメモリに写してクリップボードに置く。
+// 置けた場合はメモリの所有権がシステムに移るため、解放するのは失敗したときだけ。
+func setClipboardData(format uintptr, data []byte) error {
+	if len(data) == 0 {
+		return errors.New("clipboard data is empty")
+	}
+	handle, _, callErr := procGlobalAlloc.Call(gmemMoveable, uintptr(len(data)))
+	if handle == 0 {
+		return callErr
+	}
+	pointer, _, callErr := procGlobalLock.Call(handle)
+	if pointer == 0 {
+		procGlobalFree.Call(handle)
+		return callErr
+	}
+	procRtlMoveMemory.Call(pointer, uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)))
+	procGlobalUnlock.Call(handle)
+	if ret, _, callErr := procSetClipboardData.Call(format, handle); ret == 0 {
+		procGlobalFree.Call(handle)
+		return callErr
+	}
+	return nil
+}
+
+// dibToBMP は DIB（BITMAPINFO と画素データ）の先頭に BITMAPFILEHEADER を付けて BMP ファイルにする。
+func dibToBMP(dib []byte) ([]byte, error) {
+	if len(dib) < bitmapInfoHeaderSz {
+		return nil, errors.New("clipboard bitmap is too small")
+	}
+	headerSize := binary.LittleEndian.Uint32(dib[0:4])
+	bitCount := binary.LittleEndian.Uint16(dib[14:16])
+	compression := binary.LittleEndian.Uint32(dib[16:20])
+	colorsUsed := binary.LittleEndian.Uint32(dib[32:36])
+
+	offset := bitmapFileHeaderSz + headerSize
+	if compression == biBitfields && headerSize == bitmapInfoHeaderSz {
+		offset += 12
+	}
+	if colorsUsed > 0 {
+		offset += colorsUsed * 4
+	} else if bitCount <= 8 {
+		offset += (1 << bitCount) * 4
+	}
+
+	bmp := make([]byte, bitmapFileHeaderSz, bitmapFileHeaderSz+len(dib))
+	bmp[0], bmp[1] = 'B', 'M'
+	binary.LittleEndian.PutUint32(bmp[2:6], uint32(bitmapFileHeaderSz+len(dib)))
+	binary.LittleEndian.PutUint32(bmp[10:14], offset)
+	return append(bmp, dib...), nil
+}
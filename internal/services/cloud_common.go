@@ -11,6 +11,15 @@ import (
 	"CloudLaunch_Go/internal/util"
 )
 
+// defaultCredentialKey は認証情報プロファイル未指定時に使うキー。
+const defaultCredentialKey = "default"
+
+// credentialKeyOf は Config から使用する認証情報プロファイルのキーを返す。
+// テスト等で Config をゼロ値のまま渡しても既存の "default" プロファイルを引けるようにする。
+func credentialKeyOf(cfg config.Config) string {
+	return util.FirstNonEmpty(cfg.CredentialKey, defaultCredentialKey)
+}
+
 // resolveS3Config はアプリ設定と認証情報から S3Config を構築する。
 // ForcePathStyle は ValidateCredential（app 層）と揃えないと、接続テストは通るが
 // Push/Pull だけ MinIO 等で失敗する経路分裂になる。
@@ -24,11 +33,12 @@ func resolveS3Config(base config.Config, credential *credentials.Credential) sto
 	}
 }
 
-// cloudObjectStore は MemoCloudService が依存するストレージ操作を抽象化する。
+// cloudObjectStore はメモ・スクリーンショット等のクラウド操作が依存するストレージ操作を抽象化する。
 type cloudObjectStore interface {
 	ListObjects(ctx context.Context, cfg storage.S3Config, credential credentials.Credential, prefix string) ([]storage.ObjectInfo, error)
 	UploadBytes(ctx context.Context, cfg storage.S3Config, credential credentials.Credential, key string, payload []byte, contentType string) error
 	DownloadObject(ctx context.Context, cfg storage.S3Config, credential credentials.Credential, key string) ([]byte, error)
+	CopyObject(ctx context.Context, cfg storage.S3Config, credential credentials.Credential, sourceKey string, destKey string) error
 }
 
 type storageCloudObjectStore struct{}
@@ -57,6 +67,14 @@ func (storageCloudObjectStore) DownloadObject(ctx context.Context, cfg storage.S
 	return storage.DownloadObject(ctx, client, cfg.Bucket, key)
 }
 
+func (storageCloudObjectStore) CopyObject(ctx context.Context, cfg storage.S3Config, credential credentials.Credential, sourceKey string, destKey string) error {
+	client, err := storage.NewClient(ctx, cfg, credential)
+	if err != nil {
+		return err
+	}
+	return storage.CopyObject(ctx, client, cfg.Bucket, sourceKey, destKey)
+}
+
 // normalizeImageExt はコンテンツタイプから画像拡張子を決定する。
 // 既知の画像フォーマット（jpeg/png/gif/webp/bmp/avif）はそれぞれの拡張子に正規化。
 // 不明な content-type は ".png" を返すが、これは「拡張子が嘘」になる失敗モード
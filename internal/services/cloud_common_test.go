@@ -65,3 +65,14 @@ func TestResolveS3ConfigPropagatesForcePathStyle(t *testing.T) {
 		t.Fatal("expected UseTLS from base config")
 	}
 }
+
+func TestCredentialKeyOfFallsBackToDefault(t *testing.T) {
+	t.Parallel()
+
+	if got := credentialKeyOf(config.Config{}); got != "default" {
+		t.Fatalf("zero config: got %q, want %q", got, "default")
+	}
+	if got := credentialKeyOf(config.Config{CredentialKey: " nas "}); got != "nas" {
+		t.Fatalf("explicit key: got %q, want %q", got, "nas")
+	}
+}
@@ -0,0 +1,128 @@
+// クラウド整合性チェックを週1回バックグラウンドで実行するジョブを提供する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/logging"
+)
+
+const (
+	// defaultCloudConsistencyInterval は整合性チェックの実行間隔。
+	defaultCloudConsistencyInterval = 7 * 24 * time.Hour
+	// defaultCloudConsistencyPoll は実行時期が来たかを確かめる間隔。
+	// アプリを毎日短時間だけ起動する使い方でも、起動中に一度は判定されるようにする。
+	defaultCloudConsistencyPoll = time.Hour
+)
+
+// CloudConsistencyJob は前回のチェックから一定期間経っていれば CheckCloudConsistency を実行する。
+// 前回実行時刻は同期ログから読むため、アプリを再起動しても間隔が保たれる。
+type CloudConsistencyJob struct {
+	contentSync *ContentSyncService
+	logger      *slog.Logger
+	interval    time.Duration
+	poll        time.Duration
+	now         func() time.Time
+	mu          sync.Mutex
+	onReport    func(domain.CloudConsistencyReport)
+	stop        chan struct{}
+	runLock     sync.Mutex
+}
+
+// NewCloudConsistencyJob は CloudConsistencyJob を生成する。
+func NewCloudConsistencyJob(syncService *ContentSyncService, logger *slog.Logger) *CloudConsistencyJob {
+	return &CloudConsistencyJob{
+		contentSync: syncService,
+		logger:      logger,
+		interval:    defaultCloudConsistencyInterval,
+		poll:        defaultCloudConsistencyPoll,
+		now:         time.Now,
+	}
+}
+
+// SetOnReport は修復可能な問題が見つかったときの通知先を設定する。
+func (job *CloudConsistencyJob) SetOnReport(fn func(domain.CloudConsistencyReport)) {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	job.onReport = fn
+}
+
+// Start は定期判定を開始する。起動直後にも1回判定する。
+func (job *CloudConsistencyJob) Start(ctx context.Context) {
+	job.mu.Lock()
+	if job.stop != nil {
+		job.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	job.stop = stop
+	poll := job.poll
+	job.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+		tick := func() {
+			defer logging.Recover(job.logger, "cloud-consistency.check")
+			job.RunIfDue(ctx)
+		}
+		tick()
+		for {
+			select {
+			case <-ticker.C:
+				tick()
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop は定期判定を停止する。実行中のチェックは中断しない。
+func (job *CloudConsistencyJob) Stop() {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.stop == nil {
+		return
+	}
+	close(job.stop)
+	job.stop = nil
+}
+
+// RunIfDue は前回のチェックから interval 以上経っていればチェックを実行し、実行したかを返す。
+// オフライン中や認証情報未設定などでチェックできなかった場合は次回の判定に回す。
+func (job *CloudConsistencyJob) RunIfDue(ctx context.Context) bool {
+	job.runLock.Lock()
+	defer job.runLock.Unlock()
+
+	if job.contentSync.IsOffline() {
+		return false
+	}
+	last, err := job.contentSync.LastCloudConsistencyCheck(ctx)
+	if err != nil {
+		job.logger.Warn("前回のクラウド整合性チェック時刻の取得に失敗", "error", err)
+	}
+	if !last.IsZero() && job.now().Sub(last) < job.interval {
+		return false
+	}
+	report, err := job.contentSync.CheckCloudConsistency(ctx)
+	if err != nil {
+		job.logger.Debug("クラウド整合性チェックを見送りました", "error", err)
+		return false
+	}
+	job.logger.Info("クラウド整合性チェックを実行", "games", report.GameCount, "problems", len(report.Problems))
+
+	job.mu.Lock()
+	onReport := job.onReport
+	job.mu.Unlock()
+	if onReport != nil && len(report.RepairableGameIDs()) > 0 {
+		onReport(report)
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+// クラウドデータ閲覧から単一オブジェクトをローカルへ保存するときのファイル名の決定を提供する。
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// CloudFileOpenDirName は OpenCloudFileLocally でダウンロードしたファイルを置く一時ディレクトリ名。
+const CloudFileOpenDirName = "cloudlaunch-cloud-files"
+
+// maxCloudFileNameSuffix は同名ファイルがあるときに付ける連番の上限。
+const maxCloudFileNameSuffix = 1000
+
+// CloudObjectFileName はオブジェクトキーの末尾セグメントから、Windows で使えるローカルのファイル名を作る。
+// 使えない文字は "_" に置き換え、末尾のドットと空白は取り除く。名前が残らなければ "download" を返す。
+func CloudObjectFileName(key string) string {
+	base := path.Base(strings.TrimRight(strings.TrimSpace(key), "/"))
+	if base == "." || base == "/" {
+		base = ""
+	}
+	name := strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, base)
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return "download"
+	}
+	return name
+}
+
+// UniqueLocalFilePath は dir に name を置くパスを返す。既に同名のファイルがあれば "name (1).ext" のように連番を付ける。
+func UniqueLocalFilePath(dir, name string) (string, error) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	candidate := filepath.Join(dir, name)
+	for i := 1; i <= maxCloudFileNameSuffix; i++ {
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		} else if err != nil {
+			return "", err
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", stem, i, ext))
+	}
+	return "", fmt.Errorf("保存先に同名のファイルが多すぎます: %s", name)
+}
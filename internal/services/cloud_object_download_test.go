@@ -0,0 +1,45 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCloudObjectFileName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"games/g1/blobs/saves/ab/abcdef": "abcdef",
+		"games/g1/meta/HEAD":             "HEAD",
+		"games/g1/notes/memo:1?.md":      "memo_1_.md",
+		"games/g1/trailing. ":            "trailing",
+		"games/g1/":                      "g1",
+		"":                               "download",
+		"/":                              "download",
+	}
+	for key, want := range cases {
+		if got := CloudObjectFileName(key); got != want {
+			t.Errorf("CloudObjectFileName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestUniqueLocalFilePath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first, err := UniqueLocalFilePath(dir, "save.dat")
+	if err != nil || first != filepath.Join(dir, "save.dat") {
+		t.Fatalf("unexpected path %q err=%v", first, err)
+	}
+	for _, name := range []string{"save.dat", "save (1).dat"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	next, err := UniqueLocalFilePath(dir, "save.dat")
+	if err != nil || next != filepath.Join(dir, "save (2).dat") {
+		t.Fatalf("existing files should get a numbered name, got %q err=%v", next, err)
+	}
+}
@@ -0,0 +1,123 @@
+// クラウドデータ閲覧用に、オブジェクト先頭部分の種別判定と整形を提供する。
+package services
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+const (
+	// DefaultCloudObjectPreviewBytes はプレビューで取得する既定バイト数。
+	DefaultCloudObjectPreviewBytes = 64 * 1024
+	// MaxCloudObjectPreviewBytes はプレビューで取得できる最大バイト数。
+	MaxCloudObjectPreviewBytes = 1024 * 1024
+)
+
+// CloudObjectPreviewKind はプレビューの表示種別。
+type CloudObjectPreviewKind string
+
+const (
+	CloudObjectPreviewJSON      CloudObjectPreviewKind = "json"
+	CloudObjectPreviewMarkdown  CloudObjectPreviewKind = "markdown"
+	CloudObjectPreviewText      CloudObjectPreviewKind = "text"
+	CloudObjectPreviewBinary    CloudObjectPreviewKind = "binary"
+	CloudObjectPreviewEncrypted CloudObjectPreviewKind = "encrypted"
+)
+
+// CloudObjectPreview はクラウドオブジェクト先頭部分のプレビューを表す。
+type CloudObjectPreview struct {
+	Key          string                 `json:"key"`
+	Kind         CloudObjectPreviewKind `json:"kind"`
+	ContentType  string                 `json:"contentType"`
+	Size         int64                  `json:"size"`
+	PreviewBytes int                    `json:"previewBytes"`
+	Truncated    bool                   `json:"truncated"`
+	Content      string                 `json:"content"`
+}
+
+// ClampCloudObjectPreviewBytes は要求バイト数を既定値・上限に丸める。
+func ClampCloudObjectPreviewBytes(maxBytes int) int {
+	if maxBytes <= 0 {
+		return DefaultCloudObjectPreviewBytes
+	}
+	if maxBytes > MaxCloudObjectPreviewBytes {
+		return MaxCloudObjectPreviewBytes
+	}
+	return maxBytes
+}
+
+// BuildCloudObjectPreview はオブジェクトの先頭 data から表示種別を判定し、表示用文字列を組み立てる。
+// JSON は整形し（途中で切れていて整形できなければ原文のまま）、Markdown とテキストは原文、
+// それ以外は hexdump で返す。暗号化ブロブのまま渡された場合は暗号文を見せても意味がないため、種別だけを返す。
+func BuildCloudObjectPreview(key string, data []byte, totalSize int64, contentType string) CloudObjectPreview {
+	if totalSize < int64(len(data)) {
+		totalSize = int64(len(data))
+	}
+	preview := CloudObjectPreview{
+		Key:          key,
+		ContentType:  contentType,
+		Size:         totalSize,
+		PreviewBytes: len(data),
+		Truncated:    int64(len(data)) < totalSize,
+	}
+
+	if storage.IsEncryptedEnvelope(data) {
+		preview.Kind = CloudObjectPreviewEncrypted
+		return preview
+	}
+
+	text, ok := previewText(data, preview.Truncated)
+	if !ok {
+		preview.Kind = CloudObjectPreviewBinary
+		preview.Content = hex.Dump(data)
+		return preview
+	}
+
+	ext := strings.ToLower(path.Ext(key))
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch {
+	case ext == ".json" || mediaType == "application/json" || looksLikeJSON(text):
+		preview.Kind = CloudObjectPreviewJSON
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, []byte(text), "", "  "); err == nil {
+			preview.Content = indented.String()
+		} else {
+			preview.Content = text
+		}
+	case ext == ".md" || ext == ".markdown" || mediaType == "text/markdown":
+		preview.Kind = CloudObjectPreviewMarkdown
+		preview.Content = text
+	default:
+		preview.Kind = CloudObjectPreviewText
+		preview.Content = text
+	}
+	return preview
+}
+
+// previewText は data が UTF-8 テキストとして表示できる場合にその文字列を返す。
+// 先頭だけ取得した場合は末尾で途切れたマルチバイト文字を取り除いてから判定する。
+func previewText(data []byte, truncated bool) (string, bool) {
+	if truncated {
+		for trimmed := 0; trimmed < utf8.UTFMax && len(data) > 0; trimmed++ {
+			if r, _ := utf8.DecodeLastRune(data); r != utf8.RuneError {
+				break
+			}
+			data = data[:len(data)-1]
+		}
+	}
+	if !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+		return "", false
+	}
+	return string(data), true
+}
+
+func looksLikeJSON(text string) bool {
+	trimmed := strings.TrimSpace(text)
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
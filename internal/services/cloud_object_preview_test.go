@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildCloudObjectPreviewPrettyPrintsJSON(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"title":"ゲーム","sessions":[1,2]}`)
+	preview := BuildCloudObjectPreview("games/g1/sessions.json", data, int64(len(data)), "application/json")
+	if preview.Kind != CloudObjectPreviewJSON || preview.Truncated {
+		t.Fatalf("unexpected preview: %#v", preview)
+	}
+	if !strings.Contains(preview.Content, "\n  \"title\": \"ゲーム\"") {
+		t.Fatalf("expected indented JSON, got %q", preview.Content)
+	}
+}
+
+func TestBuildCloudObjectPreviewKeepsTruncatedJSONAsIs(t *testing.T) {
+	t.Parallel()
+
+	full := []byte(`{"title":"ゲーム"}`)
+	// マルチバイト文字の途中で切れたケース。
+	head := full[:15]
+	preview := BuildCloudObjectPreview("games.json", head, int64(len(full)), "")
+	if preview.Kind != CloudObjectPreviewJSON || !preview.Truncated || preview.Size != int64(len(full)) {
+		t.Fatalf("unexpected preview: %#v", preview)
+	}
+	if preview.Content != `{"title":"ゲ` {
+		t.Fatalf("expected raw head without partial rune, got %q", preview.Content)
+	}
+}
+
+func TestBuildCloudObjectPreviewDetectsMarkdownAndText(t *testing.T) {
+	t.Parallel()
+
+	markdown := BuildCloudObjectPreview("memos/g1/note.md", []byte("# 見出し\n本文"), 0, "")
+	if markdown.Kind != CloudObjectPreviewMarkdown || markdown.Content != "# 見出し\n本文" {
+		t.Fatalf("unexpected markdown preview: %#v", markdown)
+	}
+	text := BuildCloudObjectPreview("logs/run.txt", []byte("plain"), 5, "text/plain")
+	if text.Kind != CloudObjectPreviewText || text.Content != "plain" {
+		t.Fatalf("unexpected text preview: %#v", text)
+	}
+}
+
+func TestBuildCloudObjectPreviewHexdumpsBinary(t *testing.T) {
+	t.Parallel()
+
+	preview := BuildCloudObjectPreview("games/g1/objects/abc", []byte{0x00, 0xff, 0x10}, 3, "application/octet-stream")
+	if preview.Kind != CloudObjectPreviewBinary || !strings.HasPrefix(preview.Content, "00000000  00 ff 10") {
+		t.Fatalf("unexpected binary preview: %#v", preview)
+	}
+}
+
+func TestBuildCloudObjectPreviewHidesCiphertext(t *testing.T) {
+	t.Parallel()
+
+	preview := BuildCloudObjectPreview("games/g1/objects/abc", []byte("CLE1\x01\x02\x03"), 7, "application/octet-stream")
+	if preview.Kind != CloudObjectPreviewEncrypted || preview.Content != "" {
+		t.Fatalf("unexpected encrypted preview: %#v", preview)
+	}
+}
+
+func TestClampCloudObjectPreviewBytes(t *testing.T) {
+	t.Parallel()
+
+	if got := ClampCloudObjectPreviewBytes(0); got != DefaultCloudObjectPreviewBytes {
+		t.Fatalf("expected default, got %d", got)
+	}
+	if got := ClampCloudObjectPreviewBytes(MaxCloudObjectPreviewBytes + 1); got != MaxCloudObjectPreviewBytes {
+		t.Fatalf("expected max, got %d", got)
+	}
+	if got := ClampCloudObjectPreviewBytes(100); got != 100 {
+		t.Fatalf("expected 100, got %d", got)
+	}
+}
@@ -0,0 +1,230 @@
+// タイトル名ベースの旧クラウドパスを ID ベースへ移行し、移行前データの読み出しを補助する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+	"CloudLaunch_Go/internal/memo"
+)
+
+// cloudPathMigratedSettingPrefix はゲーム単位の移行完了を記録する Settings キーの接頭辞。
+const cloudPathMigratedSettingPrefix = "cloud_path_migrated:"
+
+// CloudPathMigrationResult は旧パス移行1回分の結果を表す。
+type CloudPathMigrationResult struct {
+	Migrated int `json:"migrated"`
+	Copied   int `json:"copied"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+// CloudPathMigrationService は games/{サニタイズ済みタイトル}/ 配下の旧データを
+// games/{ゲームID}/ 配下へコピーする。元オブジェクトは削除しないので、移行途中で
+// 止まっても旧パスからの読み出し（dual-read）は引き続き成立する。
+type CloudPathMigrationService struct {
+	config      config.Config
+	store       credentials.Store
+	objectStore cloudObjectStore
+	repository  CloudPathMigrationRepository
+	logger      *slog.Logger
+	running     atomic.Bool
+}
+
+// NewCloudPathMigrationService は CloudPathMigrationService を生成する。
+func NewCloudPathMigrationService(
+	cfg config.Config,
+	store credentials.Store,
+	repository CloudPathMigrationRepository,
+	logger *slog.Logger,
+) *CloudPathMigrationService {
+	return &CloudPathMigrationService{
+		config:      cfg,
+		store:       store,
+		objectStore: storageCloudObjectStore{},
+		repository:  repository,
+		logger:      logger,
+	}
+}
+
+func (service *CloudPathMigrationService) SetS3ForcePathStyle(enabled bool) {
+	service.config.S3ForcePathStyle = enabled
+}
+
+func (service *CloudPathMigrationService) SetS3UseTLS(enabled bool) {
+	service.config.S3UseTLS = enabled
+}
+
+// SetCredentialKey は使用する認証情報プロファイルを切り替える。
+func (service *CloudPathMigrationService) SetCredentialKey(key string) {
+	service.config.CredentialKey = key
+}
+
+// Migrate は未移行のゲームについて旧パスのオブジェクトを ID パスへコピーする。
+// ID パスに同じキーが既にあればそちらを正としてコピーしない。ゲーム内の全オブジェクトを
+// コピーできたときだけ完了を記録し、失敗したゲームは次回起動時に再試行する。
+// 認証情報が未設定の場合はクラウドを使っていないとみなして何もしない。
+func (service *CloudPathMigrationService) Migrate(ctx context.Context) (CloudPathMigrationResult, error) {
+	if !service.running.CompareAndSwap(false, true) {
+		return CloudPathMigrationResult{}, nil
+	}
+	defer service.running.Store(false)
+
+	games, err := service.repository.ListGames(ctx, "", domain.GameFilterAll, "title", "asc")
+	if err != nil {
+		return CloudPathMigrationResult{}, err
+	}
+	pending := make([]domain.Game, 0, len(games))
+	for _, game := range games {
+		done, err := service.repository.GetSetting(ctx, cloudPathMigratedSettingPrefix+game.ID)
+		if err != nil {
+			return CloudPathMigrationResult{}, err
+		}
+		if done == "" {
+			pending = append(pending, game)
+		}
+	}
+	if len(pending) == 0 {
+		return CloudPathMigrationResult{}, nil
+	}
+
+	credential, err := service.store.Load(ctx, credentialKeyOf(service.config))
+	if err != nil || credential == nil {
+		service.logger.Debug("認証情報がないためクラウドパス移行をスキップしました")
+		return CloudPathMigrationResult{}, nil
+	}
+	cfg := resolveS3Config(service.config, credential)
+	objects, err := service.objectStore.ListObjects(ctx, cfg, *credential, "games/")
+	if err != nil {
+		return CloudPathMigrationResult{}, err
+	}
+	existing := make(map[string]struct{}, len(objects))
+	bySegment := make(map[string][]string)
+	for _, obj := range objects {
+		existing[obj.Key] = struct{}{}
+		rest := strings.TrimPrefix(obj.Key, "games/")
+		segment, _, ok := strings.Cut(rest, "/")
+		if !ok || segment == "" {
+			continue
+		}
+		bySegment[segment] = append(bySegment[segment], obj.Key)
+	}
+
+	segments := legacyCloudGameSegments(games)
+	resultData := CloudPathMigrationResult{}
+	for _, game := range pending {
+		if ctx.Err() != nil {
+			return resultData, ctx.Err()
+		}
+		segment := legacyCloudGameSegment(game.Title)
+		target, known := segments[segment]
+		if !known {
+			// 空タイトルやゲームIDと一致するディレクトリ名は旧パスを持ち得ない。
+			service.markMigrated(ctx, game.ID)
+			continue
+		}
+		if target == "" {
+			// 同名タイトルが複数あると移行先を決められないので、改名されるまで保留する。
+			service.logger.Warn("同名タイトルのゲームが複数あるためクラウドパス移行を保留しました", "gameId", game.ID, "title", game.Title)
+			resultData.Skipped++
+			continue
+		}
+		keys := bySegment[segment]
+		if len(keys) == 0 {
+			service.markMigrated(ctx, game.ID)
+			continue
+		}
+		copied, failed := service.copyLegacyObjects(ctx, cfg, *credential, game.ID, segment, keys, existing)
+		resultData.Copied += copied
+		if failed > 0 {
+			resultData.Failed++
+			continue
+		}
+		service.logger.Info("旧クラウドパスを移行しました", "gameId", game.ID, "legacyPrefix", "games/"+segment+"/", "copied", copied)
+		service.markMigrated(ctx, game.ID)
+		resultData.Migrated++
+	}
+	return resultData, nil
+}
+
+// copyLegacyObjects は1ゲーム分の旧キーを ID パスへコピーし、(コピー件数, 失敗件数) を返す。
+// HEAD は参照先の blob が揃ってから見えるよう最後にコピーする。
+func (service *CloudPathMigrationService) copyLegacyObjects(
+	ctx context.Context,
+	cfg storage.S3Config,
+	credential credentials.Credential,
+	gameID string,
+	segment string,
+	keys []string,
+	existing map[string]struct{},
+) (int, int) {
+	legacyPrefix := "games/" + segment + "/"
+	ordered := append([]string(nil), keys...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return !isHeadKey(ordered[i]) && isHeadKey(ordered[j])
+	})
+	copied, failed := 0, 0
+	for _, key := range ordered {
+		dest := "games/" + gameID + "/" + strings.TrimPrefix(key, legacyPrefix)
+		if _, ok := existing[dest]; ok {
+			continue
+		}
+		if err := service.objectStore.CopyObject(ctx, cfg, credential, key, dest); err != nil {
+			service.logger.Warn("旧クラウドパスのコピーに失敗しました", "gameId", gameID, "key", key, "error", err)
+			failed++
+			continue
+		}
+		existing[dest] = struct{}{}
+		copied++
+	}
+	return copied, failed
+}
+
+func (service *CloudPathMigrationService) markMigrated(ctx context.Context, gameID string) {
+	if err := service.repository.UpsertSetting(ctx, cloudPathMigratedSettingPrefix+gameID, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		service.logger.Warn("クラウドパス移行の完了記録に失敗しました", "gameId", gameID, "error", err)
+	}
+}
+
+func isHeadKey(key string) bool {
+	return strings.HasSuffix(key, "/HEAD")
+}
+
+// legacyCloudGameSegment は旧クラウドパスで使われていたゲームディレクトリ名を返す。
+func legacyCloudGameSegment(title string) string {
+	return memo.SanitizeForCloudPath(title)
+}
+
+// legacyCloudGameSegments は旧パスのディレクトリ名からゲームIDへの対応表を返す。
+// 別ゲームの ID と一致するディレクトリ名は ID パスなので含めない。同名タイトルが
+// 複数あるディレクトリ名は移行先を特定できないため空文字を値にする。
+func legacyCloudGameSegments(games []domain.Game) map[string]string {
+	ids := make(map[string]struct{}, len(games))
+	for _, game := range games {
+		ids[game.ID] = struct{}{}
+	}
+	segments := make(map[string]string, len(games))
+	for _, game := range games {
+		segment := legacyCloudGameSegment(game.Title)
+		if segment == "" {
+			continue
+		}
+		if _, isID := ids[segment]; isID {
+			continue
+		}
+		if _, dup := segments[segment]; dup {
+			segments[segment] = ""
+			continue
+		}
+		segments[segment] = game.ID
+	}
+	return segments
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+type fakeCloudPathMigrationRepository struct {
+	fakeSettingsRepository
+	games []domain.Game
+}
+
+func (repository *fakeCloudPathMigrationRepository) ListGames(_ context.Context, _ string, _ domain.PlayStatus, _ string, _ string) ([]domain.Game, error) {
+	return repository.games, nil
+}
+
+func newTestCloudPathMigrationService(repository *fakeCloudPathMigrationRepository, objectStore *fakeCloudObjectStore) *CloudPathMigrationService {
+	service := NewCloudPathMigrationService(
+		config.Config{},
+		&fakeCredentialStore{loadResult: &credentials.Credential{BucketName: "bucket"}},
+		repository,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.objectStore = objectStore
+	return service
+}
+
+func TestCloudPathMigrationCopiesLegacyObjectsAndRecordsCompletion(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeCloudPathMigrationRepository{games: []domain.Game{
+		{ID: "game-1", Title: "My Game"},
+		{ID: "game-2", Title: "Other"},
+	}}
+	objectStore := &fakeCloudObjectStore{listObjects: []storage.ObjectInfo{
+		{Key: "games/My_Game/HEAD"},
+		{Key: "games/My_Game/blobs/aa"},
+		{Key: "games/My_Game/memo/Intro_memo1.md"},
+		{Key: "games/game-1/memo/Intro_memo1.md"},
+	}}
+	service := newTestCloudPathMigrationService(repository, objectStore)
+
+	result, err := service.Migrate(context.Background())
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if result.Migrated != 1 || result.Copied != 2 || result.Failed != 0 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	want := [][2]string{
+		{"games/My_Game/blobs/aa", "games/game-1/blobs/aa"},
+		{"games/My_Game/HEAD", "games/game-1/HEAD"},
+	}
+	if len(objectStore.copiedKeys) != len(want) {
+		t.Fatalf("expected existing ID key to be kept and HEAD copied last, got %v", objectStore.copiedKeys)
+	}
+	for i := range want {
+		if objectStore.copiedKeys[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, objectStore.copiedKeys)
+		}
+	}
+	for _, id := range []string{"game-1", "game-2"} {
+		if repository.values[cloudPathMigratedSettingPrefix+id] == "" {
+			t.Fatalf("expected completion recorded for %s", id)
+		}
+	}
+
+	objectStore.copiedKeys = nil
+	if _, err := service.Migrate(context.Background()); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+	if len(objectStore.copiedKeys) != 0 {
+		t.Fatalf("expected migrated games to be skipped, got %v", objectStore.copiedKeys)
+	}
+}
+
+func TestCloudPathMigrationRetriesGameAfterCopyFailure(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeCloudPathMigrationRepository{games: []domain.Game{{ID: "game-1", Title: "My Game"}}}
+	objectStore := &fakeCloudObjectStore{
+		listObjects: []storage.ObjectInfo{{Key: "games/My_Game/HEAD"}},
+		copyErr:     errors.New("AccessDenied"),
+	}
+	service := newTestCloudPathMigrationService(repository, objectStore)
+
+	result, err := service.Migrate(context.Background())
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if result.Failed != 1 || result.Migrated != 0 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if repository.values[cloudPathMigratedSettingPrefix+"game-1"] != "" {
+		t.Fatal("expected failed game to stay pending")
+	}
+}
+
+func TestCloudPathMigrationHoldsDuplicateTitles(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeCloudPathMigrationRepository{games: []domain.Game{
+		{ID: "game-1", Title: "Same"},
+		{ID: "game-2", Title: "Same"},
+	}}
+	objectStore := &fakeCloudObjectStore{listObjects: []storage.ObjectInfo{{Key: "games/Same/HEAD"}}}
+	service := newTestCloudPathMigrationService(repository, objectStore)
+
+	result, err := service.Migrate(context.Background())
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if result.Skipped != 2 || len(objectStore.copiedKeys) != 0 {
+		t.Fatalf("expected ambiguous titles to be held, got %#v copied=%v", result, objectStore.copiedKeys)
+	}
+}
@@ -94,12 +94,16 @@ func walkSaveFiles(saveDir string, fn func(absPath, relPath string) error) error
 
 // buildSaveTree はセーブディレクトリを走査し、パス→ハッシュのみの SaveSnapshot を返す
 // （ブロブ本体を RAM に保持しない）。状態確認や差分判定など、アップロード本体が不要な箇所に使う。
-func buildSaveTree(saveDir string) (domain.SaveSnapshot, error) {
+// filter が対象外とするファイルは含めない。
+func buildSaveTree(saveDir string, filter saveFileFilter) (domain.SaveSnapshot, error) {
 	if err := validateSaveDir(saveDir); err != nil {
 		return domain.SaveSnapshot{}, err
 	}
 	files := make(map[string]domain.BlobHash)
 	err := walkSaveFiles(saveDir, func(absPath, relPath string) error {
+		if !filter.allows(relPath) {
+			return nil
+		}
 		hash, herr := hashFileStream(absPath)
 		if herr != nil {
 			return herr
@@ -113,15 +117,31 @@ func buildSaveTree(saveDir string) (domain.SaveSnapshot, error) {
 	return domain.SaveSnapshot{Files: files}, nil
 }
 
+// saveTreeHash はセーブディレクトリの現在の内容を表すハッシュ（Game.LocalSaveHash に保存する値）を返す。
+func saveTreeHash(saveDir string, filter saveFileFilter) (string, error) {
+	snap, err := buildSaveTree(saveDir, filter)
+	if err != nil {
+		return "", err
+	}
+	snapJSON, err := json.Marshal(snap)
+	if err != nil {
+		return "", err
+	}
+	return hashBytes(snapJSON), nil
+}
+
 // buildSaveSnapshot はセーブディレクトリを走査し SaveSnapshot とブロブマップを返す。
-// saveFolderPath が未設定またはディレクトリが存在しない場合はエラー。
-func buildSaveSnapshot(saveDir string) (domain.SaveSnapshot, map[domain.BlobHash][]byte, error) {
+// saveFolderPath が未設定またはディレクトリが存在しない場合はエラー。filter が対象外とするファイルは含めない。
+func buildSaveSnapshot(saveDir string, filter saveFileFilter) (domain.SaveSnapshot, map[domain.BlobHash][]byte, error) {
 	if err := validateSaveDir(saveDir); err != nil {
 		return domain.SaveSnapshot{}, nil, err
 	}
 	files := make(map[string]domain.BlobHash)
 	blobs := make(map[domain.BlobHash][]byte)
 	err := walkSaveFiles(saveDir, func(absPath, relPath string) error {
+		if !filter.allows(relPath) {
+			return nil
+		}
 		hash, data, herr := hashFile(absPath)
 		if herr != nil {
 			return herr
@@ -144,9 +164,13 @@ func buildSaveSnapshot(saveDir string) (domain.SaveSnapshot, map[domain.BlobHash
 //   - untracked : 同期が一度も認識していないローカル固有のファイル。
 //     saveFolderPath の誤設定で混入した無関係ファイルもここに入る。確認なしで消さない。
 //
+// filter が対象外とするファイル（ログ・キャッシュ等）は同期で管理しないため、どちらにも含めず残す。
 // いずれも相対パス（スラッシュ区切り）を昇順で返す。この関数はファイルを削除しない。
-func planDeletions(saveDir string, snapshot domain.SaveSnapshot, baseTree map[string]struct{}) (tracked, untracked []string, err error) {
+func planDeletions(saveDir string, filter saveFileFilter, snapshot domain.SaveSnapshot, baseTree map[string]struct{}) (tracked, untracked []string, err error) {
 	walkErr := walkSaveFiles(saveDir, func(_, relPath string) error {
+		if !filter.allows(relPath) {
+			return nil
+		}
 		if _, ok := snapshot.Files[relPath]; ok {
 			return nil // 新スナップショットに含まれる → 残す
 		}
@@ -250,6 +274,26 @@ type CloudGameInfo struct {
 	CurrentRouteID *string           `json:"currentRouteId,omitempty"`
 	CreatedAt      time.Time         `json:"createdAt"`
 	UpdatedAt      time.Time         `json:"updatedAt"`
+	Links          []domain.GameLink `json:"links,omitempty"`
+	Description    *string           `json:"description,omitempty"`
+	ReleaseDate    *string           `json:"releaseDate,omitempty"`
+	Rating         *int              `json:"rating,omitempty"`
+	Genres         []string          `json:"genres,omitempty"`
+	CustomStatus   *string           `json:"customStatus,omitempty"`
+	IsFavorite     bool              `json:"isFavorite,omitempty"`
+	// SaveIncludePatterns / SaveExcludePatterns はセーブの同期対象を絞り込む glob パターン。
+	SaveIncludePatterns []string `json:"saveIncludePatterns,omitempty"`
+	SaveExcludePatterns []string `json:"saveExcludePatterns,omitempty"`
+	// LastModifiedBy / LastModifiedAt はリモート HEAD の commit を作成した端末と日時。
+	LastModifiedBy LastModifiedBy `json:"lastModifiedBy"`
+	LastModifiedAt time.Time      `json:"lastModifiedAt"`
+}
+
+// LastModifiedBy はクラウド上のデータを最後に更新した端末を表す。
+// DeviceID はデバイスID導入以前の端末が作成した commit では空。
+type LastModifiedBy struct {
+	DeviceID   string `json:"deviceId,omitempty"`
+	DeviceName string `json:"deviceName"`
 }
 
 // cloudGame は game.json のクラウド保存フォーマット。
@@ -265,6 +309,44 @@ type cloudGame struct {
 	CurrentRouteID *string           `json:"currentRouteId,omitempty"`
 	CreatedAt      time.Time         `json:"createdAt"`
 	UpdatedAt      time.Time         `json:"updatedAt"`
+	// Links はリンクが無いとき省略し、リンク機能以前の game.json とハッシュを一致させる。
+	Links []cloudGameLink `json:"links,omitempty"`
+	// 作品情報も未設定なら省略し、作品情報の導入以前の game.json とハッシュを一致させる。
+	Description *string  `json:"description,omitempty"`
+	ReleaseDate *string  `json:"releaseDate,omitempty"`
+	Rating      *int     `json:"rating,omitempty"`
+	Genres      []string `json:"genres,omitempty"`
+	// カスタムステータスも未設定なら省略し、導入以前の game.json とハッシュを一致させる。
+	CustomStatus *string `json:"customStatus,omitempty"`
+	// お気に入りも false なら省略し、導入以前の game.json とハッシュを一致させる。
+	IsFavorite bool `json:"isFavorite,omitempty"`
+	// セーブの同期パターンも未設定なら省略し、導入以前の game.json とハッシュを一致させる。
+	SaveIncludePatterns []string `json:"saveIncludePatterns,omitempty"`
+	SaveExcludePatterns []string `json:"saveExcludePatterns,omitempty"`
+}
+
+// cloudGameLink は game.json に含めるゲームリンクのクラウド保存フォーマット。
+type cloudGameLink struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	URL       string    `json:"url"`
+	SortOrder int64     `json:"sortOrder"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// emptyRoutesJSONHash はルートが無いゲームの routes.json（空の一覧）のハッシュ。
+var emptyRoutesJSONHash = hashBytes([]byte("[]"))
+
+// cloudRoute は routes.json のクラウド保存フォーマット。
+type cloudRoute struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Order     int64     `json:"order"`
+	CreatedAt time.Time `json:"createdAt"`
+	// 見込み時間・クリア日時は omitempty にして、未設定のルートの routes.json（とハッシュ）を変えない。
+	EstimatedTime *int64     `json:"estimatedTime,omitempty"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
 }
 
 // cloudSession は sessions.json のクラウド保存フォーマット。
@@ -273,8 +355,19 @@ type cloudSession struct {
 	PlayedAt    time.Time `json:"playedAt"`
 	Duration    int64     `json:"duration"`
 	SessionName *string   `json:"sessionName,omitempty"`
-	RouteID     *string   `json:"routeId,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	// Notes も omitempty にして、メモの無いセッションの sessions.json（とハッシュ）を変えない。
+	Notes     *string   `json:"notes,omitempty"`
+	RouteID   *string   `json:"routeId,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Partial は omitempty にして、既存セッションの sessions.json（とハッシュ）を変えない。
+	Partial bool `json:"partial,omitempty"`
+	// IdleDuration も omitempty にして、無操作時間の無いセッションのハッシュを変えない。
+	IdleDuration int64 `json:"idleDuration,omitempty"`
+	// CPU* / Memory* も omitempty にして、使用量を採っていないセッションのハッシュを変えない。
+	CPUPeakPercent     float64 `json:"cpuPeakPercent,omitempty"`
+	CPUAveragePercent  float64 `json:"cpuAveragePercent,omitempty"`
+	MemoryPeakBytes    int64   `json:"memoryPeakBytes,omitempty"`
+	MemoryAverageBytes int64   `json:"memoryAverageBytes,omitempty"`
 }
 
 // metaBuildResult は buildMetaSnapshot の戻り値。
@@ -283,9 +376,13 @@ type metaBuildResult struct {
 	SnapshotBytes []byte
 	GameJSON      []byte
 	SessionsJSON  []byte
+	// RoutesJSON はルートが無いときも空の一覧（"[]"）。
+	RoutesJSON []byte
+	// SessionChunks は月ごとのセッションログ（ハッシュ→JSON）。セッションが無いとき nil。
+	SessionChunks map[domain.BlobHash][]byte
 }
 
-// buildMetaSnapshot はゲーム情報・セッション・セーブハッシュから MetaSnapshot を構築する。
+// buildMetaSnapshot はゲーム情報・セッション・ルート・セーブハッシュから MetaSnapshot を構築する。
 //
 // fileCount / totalSize はクラウド一覧での表示用キャッシュ。Push 経路では実値を、
 // Status 経路（buildLocalMeta）では 0,0 を渡して構わない（fingerprint 比較・UI 表示
@@ -293,54 +390,75 @@ type metaBuildResult struct {
 func buildMetaSnapshot(
 	game domain.Game,
 	sessions []domain.PlaySession,
+	links []domain.GameLink,
+	routes []domain.Route,
 	imageHash domain.BlobHash,
 	savesHash domain.BlobHash,
-	deviceName string,
+	device DeviceIdentity,
 	fileCount int64,
 	totalSize int64,
 ) (metaBuildResult, error) {
 	gameJSON, err := json.Marshal(cloudGame{
-		ID:             game.ID,
-		Title:          game.Title,
-		Publisher:      game.Publisher,
-		ImageHash:      imageHash,
-		PlayStatus:     game.PlayStatus,
-		TotalPlayTime:  game.TotalPlayTime,
-		LastPlayed:     game.LastPlayed,
-		ClearedAt:      game.ClearedAt,
-		CurrentRouteID: game.CurrentRouteID,
-		CreatedAt:      game.CreatedAt,
-		UpdatedAt:      game.UpdatedAt,
+		ID:                  game.ID,
+		Title:               game.Title,
+		Publisher:           game.Publisher,
+		ImageHash:           imageHash,
+		PlayStatus:          game.PlayStatus,
+		TotalPlayTime:       game.TotalPlayTime,
+		LastPlayed:          game.LastPlayed,
+		ClearedAt:           game.ClearedAt,
+		CurrentRouteID:      game.CurrentRouteID,
+		CreatedAt:           game.CreatedAt,
+		UpdatedAt:           game.UpdatedAt,
+		Links:               toCloudGameLinks(links),
+		Description:         game.Description,
+		ReleaseDate:         game.ReleaseDate,
+		Rating:              game.Rating,
+		Genres:              game.Genres,
+		CustomStatus:        game.CustomStatus,
+		IsFavorite:          game.IsFavorite,
+		SaveIncludePatterns: game.SaveIncludePatterns,
+		SaveExcludePatterns: game.SaveExcludePatterns,
 	})
 	if err != nil {
 		return metaBuildResult{}, err
 	}
 
 	cs := make([]cloudSession, 0, len(sessions))
-	for _, s := range sessions {
-		cs = append(cs, cloudSession{
-			ID:          s.ID,
-			PlayedAt:    s.PlayedAt,
-			Duration:    s.Duration,
-			SessionName: s.SessionName,
-			RouteID:     s.RouteID,
-			UpdatedAt:   s.UpdatedAt,
-		})
+	for _, session := range sessions {
+		cs = append(cs, toCloudSession(session))
 	}
 	sessionsJSON, err := json.Marshal(cs)
 	if err != nil {
 		return metaBuildResult{}, err
 	}
+	var chunkHashes map[string]domain.BlobHash
+	var chunks map[domain.BlobHash][]byte
+	if len(cs) > 0 {
+		chunkHashes, chunks, err = buildSessionChunks(cs)
+		if err != nil {
+			return metaBuildResult{}, err
+		}
+	}
+
+	// ルートが無くても空の一覧を置き、別の端末でルートを全て消したことが伝わるようにする。
+	routesJSON, err := json.Marshal(toCloudRoutes(routes))
+	if err != nil {
+		return metaBuildResult{}, err
+	}
 
 	meta := domain.MetaSnapshot{
-		GameJSON:     hashBytes(gameJSON),
-		SessionsJSON: hashBytes(sessionsJSON),
-		Saves:        savesHash,
-		DeviceName:   deviceName,
-		CreatedAt:    time.Now().UTC(),
-		FileCount:    fileCount,
-		TotalSize:    totalSize,
+		GameJSON:      hashBytes(gameJSON),
+		SessionsJSON:  hashBytes(sessionsJSON),
+		Saves:         savesHash,
+		DeviceID:      device.ID,
+		DeviceName:    device.Name,
+		CreatedAt:     time.Now().UTC(),
+		FileCount:     fileCount,
+		TotalSize:     totalSize,
+		SessionChunks: chunkHashes,
 	}
+	meta.RoutesJSON = hashBytes(routesJSON)
 	metaBytes, err := json.Marshal(meta)
 	if err != nil {
 		return metaBuildResult{}, err
@@ -351,5 +469,78 @@ func buildMetaSnapshot(
 		SnapshotBytes: metaBytes,
 		GameJSON:      gameJSON,
 		SessionsJSON:  sessionsJSON,
+		RoutesJSON:    routesJSON,
+		SessionChunks: chunks,
 	}, nil
 }
+
+func toCloudRoutes(routes []domain.Route) []cloudRoute {
+	result := make([]cloudRoute, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, cloudRoute{
+			ID:            route.ID,
+			Name:          route.Name,
+			Order:         route.Order,
+			CreatedAt:     route.CreatedAt,
+			EstimatedTime: route.EstimatedTime,
+			CompletedAt:   route.CompletedAt,
+		})
+	}
+	return result
+}
+
+// fromCloudRoutes は routes.json のルートを domain.Route に変換する。
+// routes.json を持たない commit（routes が nil）では、ローカルのルートを維持させるため nil を返す。
+func fromCloudRoutes(gameID string, routes []cloudRoute) []domain.Route {
+	if routes == nil {
+		return nil
+	}
+	result := make([]domain.Route, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, domain.Route{
+			ID:            route.ID,
+			Name:          route.Name,
+			Order:         route.Order,
+			GameID:        gameID,
+			CreatedAt:     route.CreatedAt,
+			EstimatedTime: route.EstimatedTime,
+			Completed:     route.CompletedAt != nil,
+			CompletedAt:   route.CompletedAt,
+		})
+	}
+	return result
+}
+
+func toCloudGameLinks(links []domain.GameLink) []cloudGameLink {
+	if len(links) == 0 {
+		return nil
+	}
+	result := make([]cloudGameLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, cloudGameLink{
+			ID:        link.ID,
+			Label:     link.Label,
+			URL:       link.URL,
+			SortOrder: link.SortOrder,
+			CreatedAt: link.CreatedAt,
+			UpdatedAt: link.UpdatedAt,
+		})
+	}
+	return result
+}
+
+func fromCloudGameLinks(gameID string, links []cloudGameLink) []domain.GameLink {
+	result := make([]domain.GameLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, domain.GameLink{
+			ID:        link.ID,
+			GameID:    gameID,
+			Label:     link.Label,
+			URL:       link.URL,
+			SortOrder: link.SortOrder,
+			CreatedAt: link.CreatedAt,
+			UpdatedAt: link.UpdatedAt,
+		})
+	}
+	return result
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,7 +40,7 @@ func TestHashBytesDifferentInputProducesDifferentHash(t *testing.T) {
 func TestBuildSaveSnapshotReturnsErrorForMissingDir(t *testing.T) {
 	t.Parallel()
 
-	_, _, err := buildSaveSnapshot("/nonexistent/path/that/does/not/exist")
+	_, _, err := buildSaveSnapshot("/nonexistent/path/that/does/not/exist", saveFileFilter{})
 	if err == nil {
 		t.Fatal("expected error for missing directory")
 	}
@@ -48,7 +49,7 @@ func TestBuildSaveSnapshotReturnsErrorForMissingDir(t *testing.T) {
 func TestBuildSaveSnapshotReturnsErrorForEmptyPath(t *testing.T) {
 	t.Parallel()
 
-	_, _, err := buildSaveSnapshot("")
+	_, _, err := buildSaveSnapshot("", saveFileFilter{})
 	if err == nil {
 		t.Fatal("expected error for empty path")
 	}
@@ -63,7 +64,7 @@ func TestBuildSaveSnapshotReturnsErrorForFile(t *testing.T) {
 	}
 	_ = f.Close()
 
-	_, _, err = buildSaveSnapshot(f.Name())
+	_, _, err = buildSaveSnapshot(f.Name(), saveFileFilter{})
 	if err == nil {
 		t.Fatal("expected error when path is a file, not directory")
 	}
@@ -80,7 +81,7 @@ func TestBuildSaveSnapshotWalksFiles(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	snap, blobs, err := buildSaveSnapshot(dir)
+	snap, blobs, err := buildSaveSnapshot(dir, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -111,11 +112,11 @@ func TestBuildSaveSnapshotIsDeteministic(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	snap1, _, err := buildSaveSnapshot(dir)
+	snap1, _, err := buildSaveSnapshot(dir, saveFileFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	snap2, _, err := buildSaveSnapshot(dir)
+	snap2, _, err := buildSaveSnapshot(dir, saveFileFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,7 +153,7 @@ func TestRemoveFilesNotInSnapshotRemovesStaleFiles(t *testing.T) {
 		"nested/keep.sav":  {},
 		"nested/stale.sav": {},
 	}
-	tracked, untracked, err := planDeletions(dir, snapshot, baseTree)
+	tracked, untracked, err := planDeletions(dir, saveFileFilter{}, snapshot, baseTree)
 	if err != nil {
 		t.Fatalf("planDeletions: %v", err)
 	}
@@ -185,7 +186,7 @@ func TestPlanDeletionsClassifiesUntracked(t *testing.T) {
 	}
 
 	// 新スナップショットにも base tree にも無いファイル → untracked
-	tracked, untracked, err := planDeletions(dir, domain.SaveSnapshot{Files: map[string]domain.BlobHash{}}, map[string]struct{}{})
+	tracked, untracked, err := planDeletions(dir, saveFileFilter{}, domain.SaveSnapshot{Files: map[string]domain.BlobHash{}}, map[string]struct{}{})
 	if err != nil {
 		t.Fatalf("planDeletions: %v", err)
 	}
@@ -239,7 +240,7 @@ func TestBuildMetaSnapshotReturnsConsistentHashes(t *testing.T) {
 		{ID: "s1", GameID: "game-1", PlayedAt: now, Duration: 3600, UpdatedAt: now},
 	}
 
-	result, err := buildMetaSnapshot(game, sessions, "", "sha256_of_saves", "TestPC", 0, 0)
+	result, err := buildMetaSnapshot(game, sessions, nil, nil, "", "sha256_of_saves", DeviceIdentity{Name: "TestPC"}, 0, 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -280,7 +281,7 @@ func TestBuildMetaSnapshotPersistsStatsCache(t *testing.T) {
 	const wantFileCount int64 = 42
 	const wantTotalSize int64 = 1024 * 1024 * 7
 
-	result, err := buildMetaSnapshot(game, nil, "", "savehash", "PC", wantFileCount, wantTotalSize)
+	result, err := buildMetaSnapshot(game, nil, nil, nil, "", "savehash", DeviceIdentity{Name: "PC"}, wantFileCount, wantTotalSize)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -364,7 +365,7 @@ func TestBuildMetaSnapshotImageHashOmittedWhenEmpty(t *testing.T) {
 	now := time.Now().UTC()
 	game := domain.Game{ID: "g1", Title: "T", PlayStatus: domain.PlayStatusUnplayed, CreatedAt: now, UpdatedAt: now}
 
-	result, err := buildMetaSnapshot(game, nil, "", "savehash", "PC", 0, 0)
+	result, err := buildMetaSnapshot(game, nil, nil, nil, "", "savehash", DeviceIdentity{Name: "PC"}, 0, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -378,6 +379,37 @@ func TestBuildMetaSnapshotImageHashOmittedWhenEmpty(t *testing.T) {
 	}
 }
 
+func TestBuildMetaSnapshotGameDetailsOmittedWhenUnsetAndRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	game := domain.Game{ID: "g1", Title: "T", PlayStatus: domain.PlayStatusUnplayed, CreatedAt: now, UpdatedAt: now}
+	plain, err := buildMetaSnapshot(game, nil, nil, nil, "", "savehash", DeviceIdentity{Name: "PC"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"description", "releaseDate", "rating", "genres"} {
+		if strings.Contains(string(plain.GameJSON), `"`+key+`"`) {
+			t.Errorf("%s should be omitted when unset: %s", key, plain.GameJSON)
+		}
+	}
+
+	description, releaseDate, rating := "概要", "2024-05-31", 85
+	game.Description, game.ReleaseDate, game.Rating, game.Genres = &description, &releaseDate, &rating, []string{"ADV", "SF"}
+	detailed, err := buildMetaSnapshot(game, nil, nil, nil, "", "savehash", DeviceIdentity{Name: "PC"}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed cloudGame
+	if err := json.Unmarshal(detailed.GameJSON, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Description == nil || *parsed.Description != description || parsed.ReleaseDate == nil || *parsed.ReleaseDate != releaseDate ||
+		parsed.Rating == nil || *parsed.Rating != rating || len(parsed.Genres) != 2 {
+		t.Fatalf("game details should round-trip through game.json: %+v", parsed)
+	}
+}
+
 // TestHashFileStreamMatchesHashBytes は hashFileStream が hashBytes と同じハッシュを返すことを確認する。
 func TestHashFileStreamMatchesHashBytes(t *testing.T) {
 	t.Parallel()
@@ -411,11 +443,11 @@ func TestBuildSaveTreeMatchesSnapshot(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tree, err := buildSaveTree(dir)
+	tree, err := buildSaveTree(dir, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("buildSaveTree: %v", err)
 	}
-	snap, _, err := buildSaveSnapshot(dir)
+	snap, _, err := buildSaveSnapshot(dir, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("buildSaveSnapshot: %v", err)
 	}
@@ -432,7 +464,7 @@ func TestBuildSaveTreeMatchesSnapshot(t *testing.T) {
 // TestBuildSaveTreeRejectsMissingDir は存在しないディレクトリでエラーを返すことを確認する。
 func TestBuildSaveTreeRejectsMissingDir(t *testing.T) {
 	t.Parallel()
-	if _, err := buildSaveTree(filepath.Join(t.TempDir(), "nope")); err == nil {
+	if _, err := buildSaveTree(filepath.Join(t.TempDir(), "nope"), saveFileFilter{}); err == nil {
 		t.Fatal("expected error for missing dir")
 	}
 }
@@ -461,7 +493,7 @@ func TestBuildSaveTreeIgnoresSymlinks(t *testing.T) {
 		t.Skipf("symlink を作成できない環境: %v", err)
 	}
 
-	tree, err := buildSaveTree(saveDir)
+	tree, err := buildSaveTree(saveDir, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("buildSaveTree: %v", err)
 	}
@@ -473,7 +505,7 @@ func TestBuildSaveTreeIgnoresSymlinks(t *testing.T) {
 	}
 
 	// buildSaveSnapshot 側も同様にリンクを無視すること
-	snap, blobs, err := buildSaveSnapshot(saveDir)
+	snap, blobs, err := buildSaveSnapshot(saveDir, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("buildSaveSnapshot: %v", err)
 	}
@@ -515,7 +547,7 @@ func TestBuildSaveTreeFollowsSymlinkedRoot(t *testing.T) {
 		t.Skipf("symlink を作成できない環境: %v", err)
 	}
 
-	tree, err := buildSaveTree(linkPath)
+	tree, err := buildSaveTree(linkPath, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("buildSaveTree: %v", err)
 	}
@@ -526,7 +558,7 @@ func TestBuildSaveTreeFollowsSymlinkedRoot(t *testing.T) {
 		t.Fatal("symlink root 配下のサブディレクトリ内ファイルが抜けている")
 	}
 
-	snap, blobs, err := buildSaveSnapshot(linkPath)
+	snap, blobs, err := buildSaveSnapshot(linkPath, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("buildSaveSnapshot: %v", err)
 	}
@@ -0,0 +1,265 @@
+// クラウドから取得した HEAD とコミット・tree・meta の JSON をローカルにキャッシュし、変わっていないものを再取得しない。
+package services
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+const (
+	// remoteCacheDirName は AppDataDir 配下のキャッシュフォルダ名。
+	remoteCacheDirName = "cloud_cache"
+	// remoteCacheMaxBytes はブロブのキャッシュの合計サイズの上限。超えたら最後に使ってから長いものから消す。
+	remoteCacheMaxBytes = 64 << 20
+)
+
+// remoteCache はリモートの JSON のキャッシュ。
+// コミット・tree・meta のブロブはハッシュで名前が決まり中身が変わらないため、dir にハッシュの名前で保存し、次からは
+// 取得しない（暗号化した保存先でも復号後の内容を置く。DB と同じくこの PC の中だけのデータ）。
+// HEAD は書き換わるので ETag と一緒にメモリへ覚え、If-None-Match で変わっていないことだけを確かめる。
+// 古い HEAD のブロブも残り続けるため、合計が maxBytes を超えたら更新日時（読み出しでも更新する）の古い順に消す。
+type remoteCache struct {
+	// dir は空ならブロブはキャッシュしない（HEAD の ETag だけを使う）。
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	heads map[string]cachedHead // scope + "\x00" + gameID → 前回取得した HEAD
+	// size はブロブの合計サイズ。sizeKnown が false の間は未計測で、最初の書き込みでフォルダを数える。
+	size      int64
+	sizeKnown bool
+}
+
+// cachedHead は前回取得した HEAD の値と ETag。
+type cachedHead struct {
+	hash string
+	etag string
+}
+
+func newRemoteCache(dir string) *remoteCache {
+	return &remoteCache{dir: dir, maxBytes: remoteCacheMaxBytes, heads: make(map[string]cachedHead)}
+}
+
+func remoteHeadKey(scope, gameID string) string {
+	return scope + "\x00" + gameID
+}
+
+// head は scope（保存先）の gameID について覚えている HEAD を返す。
+func (c *remoteCache) head(scope, gameID string) (cachedHead, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	head, ok := c.heads[remoteHeadKey(scope, gameID)]
+	return head, ok
+}
+
+// rememberHead は取得した HEAD を覚える。ETag が無ければ（条件付き取得に対応しない保存先）覚えない。
+func (c *remoteCache) rememberHead(scope, gameID string, head cachedHead) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if head.etag == "" {
+		delete(c.heads, remoteHeadKey(scope, gameID))
+		return
+	}
+	c.heads[remoteHeadKey(scope, gameID)] = head
+}
+
+// forgetHead は HEAD を書き換えた・消したときに覚えていた値を捨てる。
+func (c *remoteCache) forgetHead(scope, gameID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.heads, remoteHeadKey(scope, gameID))
+}
+
+// cacheableBlobKind はローカルにキャッシュするブロブの種類か（セーブファイル・画像の objects は対象外）。
+func cacheableBlobKind(kind string) bool {
+	return kind == storage.BlobKindCommit || kind == storage.BlobKindTree || kind == storage.BlobKindMeta
+}
+
+// blobPath はキャッシュ上のブロブのパスを返す。キャッシュしない場合やパスとして使えない値なら "" を返す。
+func (c *remoteCache) blobPath(gameID, kind, hash string) string {
+	if c.dir == "" || !cacheableBlobKind(kind) || !safeCacheName(gameID) || !safeCacheName(hash) {
+		return ""
+	}
+	return filepath.Join(c.dir, gameID, kind, hash)
+}
+
+func safeCacheName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\:`)
+}
+
+// readBlob はキャッシュしたブロブを返す。無い・ハッシュが合わない（壊れている）ときは false。
+func (c *remoteCache) readBlob(gameID, kind, hash string) ([]byte, bool) {
+	path := c.blobPath(gameID, kind, hash)
+	if path == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	if hashBytes(data) != hash {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// writeBlob はブロブをキャッシュに置く。キャッシュは取得を省くためだけのものなので、失敗しても無視する。
+func (c *remoteCache) writeBlob(gameID, kind, hash string, data []byte) {
+	path := c.blobPath(gameID, kind, hash)
+	if path == "" || hashBytes(data) != hash {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+hash+".*.part")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	c.addSize(int64(len(data)))
+}
+
+// addSize は書き込んだ分を合計に足し、上限を超えたら古いブロブを消す。
+func (c *remoteCache) addSize(written int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sizeKnown {
+		c.size += written
+	} else {
+		// 初回はフォルダを数える（書き込んだ分も含まれる）。
+		c.size, c.sizeKnown = c.usage(), true
+	}
+	if c.maxBytes > 0 && c.size > c.maxBytes {
+		c.evict()
+	}
+}
+
+// cachedBlobFile はキャッシュ上のブロブ1件。
+type cachedBlobFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (c *remoteCache) blobFiles() []cachedBlobFile {
+	files := make([]cachedBlobFile, 0)
+	_ = filepath.WalkDir(c.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, cachedBlobFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files
+}
+
+// usage はキャッシュのフォルダの合計サイズを返す。c.mu を保持した状態で呼ぶ。
+func (c *remoteCache) usage() int64 {
+	var total int64
+	for _, file := range c.blobFiles() {
+		total += file.size
+	}
+	return total
+}
+
+// evict は最後に使ってから長いブロブから消し、合計を上限の 3/4 まで減らす（書き込みのたびに消さずに済むよう余裕を持たせる）。
+// c.mu を保持した状態で呼ぶ。
+func (c *remoteCache) evict() {
+	files := c.blobFiles()
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	target := c.maxBytes / 4 * 3
+	for _, file := range files {
+		if total <= target {
+			break
+		}
+		if err := os.Remove(file.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+			total -= file.size
+		}
+	}
+	c.size = total
+}
+
+// removeGame はクラウドから消したゲームのキャッシュを消す。
+func (c *remoteCache) removeGame(scope, gameID string) {
+	c.forgetHead(scope, gameID)
+	c.removeGameBlobs(gameID)
+}
+
+// forgetGame はローカルで削除したゲームについて、すべての保存先の HEAD とブロブのキャッシュを消す。
+func (c *remoteCache) forgetGame(gameID string) {
+	c.mu.Lock()
+	suffix := "\x00" + gameID
+	for key := range c.heads {
+		if strings.HasSuffix(key, suffix) {
+			delete(c.heads, key)
+		}
+	}
+	c.mu.Unlock()
+	c.removeGameBlobs(gameID)
+}
+
+func (c *remoteCache) removeGameBlobs(gameID string) {
+	if c.dir == "" || !safeCacheName(gameID) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = os.RemoveAll(filepath.Join(c.dir, gameID))
+	// 消した分は次の書き込みで数え直す。
+	c.sizeKnown = false
+}
+
+// readCachedHEAD は前回の ETag を付けて HEAD を取得し、変わっていなければ覚えていた値を返す。
+func (b *objectBlobStore) readCachedHEAD(ctx context.Context, gameID string) (string, error) {
+	cached, _ := b.cache.head(b.scope, gameID)
+	hash, etag, err := storage.ReadHEADIfNoneMatch(ctx, b.objects, gameID, cached.etag)
+	if errors.Is(err, storage.ErrNotModified) {
+		return cached.hash, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	b.cache.rememberHead(b.scope, gameID, cachedHead{hash: hash, etag: etag})
+	return hash, nil
+}
+
+// withoutCache はキャッシュを使わないブロブストアを返す。整合性チェックのように、クラウドに実際にあるかを確かめたいときに使う。
+func withoutCache(bstore contentBlobStore) contentBlobStore {
+	if b, ok := bstore.(*objectBlobStore); ok && b.cache != nil {
+		uncached := *b
+		uncached.cache = nil
+		return &uncached
+	}
+	return bstore
+}
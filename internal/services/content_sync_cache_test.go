@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// conditionalObjectStore は ETag 付きの取得に対応するローカルストア。HEAD の取得回数と、未変更で返した回数を数える。
+type conditionalObjectStore struct {
+	*storage.LocalObjectStore
+	etags       map[string]string
+	downloads   int
+	notModified int
+}
+
+func (store *conditionalObjectStore) Upload(ctx context.Context, key string, payload []byte, contentType string) error {
+	if err := store.LocalObjectStore.Upload(ctx, key, payload, contentType); err != nil {
+		return err
+	}
+	store.etags[key] = `"` + hashBytes(payload) + `"`
+	return nil
+}
+
+func (store *conditionalObjectStore) DownloadIfNoneMatch(ctx context.Context, key, etag string) ([]byte, string, error) {
+	if etag != "" && store.etags[key] == etag {
+		store.notModified++
+		return nil, "", storage.ErrNotModified
+	}
+	store.downloads++
+	data, err := store.Download(ctx, key)
+	return data, store.etags[key], err
+}
+
+func newCachedTestBlobStore(t *testing.T) (*objectBlobStore, *conditionalObjectStore, string) {
+	t.Helper()
+	local, err := storage.NewLocalObjectStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalObjectStore: %v", err)
+	}
+	objects := &conditionalObjectStore{LocalObjectStore: local, etags: make(map[string]string)}
+	cacheDir := t.TempDir()
+	return &objectBlobStore{objects: objects, cache: newRemoteCache(cacheDir), scope: "test"}, objects, cacheDir
+}
+
+func TestObjectBlobStoreReadHEADSkipsUnchangedDownload(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bstore, objects, _ := newCachedTestBlobStore(t)
+	if err := bstore.writeHEAD(ctx, "game-1", "head-1"); err != nil {
+		t.Fatalf("writeHEAD: %v", err)
+	}
+	for range 3 {
+		head, err := bstore.readHEAD(ctx, "game-1")
+		if err != nil || head != "head-1" {
+			t.Fatalf("readHEAD = %q, %v", head, err)
+		}
+	}
+	if objects.downloads != 1 || objects.notModified != 2 {
+		t.Fatalf("downloads = %d, notModified = %d", objects.downloads, objects.notModified)
+	}
+
+	if err := bstore.writeHEAD(ctx, "game-1", "head-2"); err != nil {
+		t.Fatalf("writeHEAD: %v", err)
+	}
+	if head, err := bstore.readHEAD(ctx, "game-1"); err != nil || head != "head-2" {
+		t.Fatalf("readHEAD after write = %q, %v", head, err)
+	}
+}
+
+func TestObjectBlobStoreServesJSONBlobsFromCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bstore, objects, cacheDir := newCachedTestBlobStore(t)
+	data := []byte(`{"title":"Game"}`)
+	hash := hashBytes(data)
+	if err := bstore.putBlob(ctx, "game-1", storage.BlobKindMeta, hash, data); err != nil {
+		t.Fatalf("putBlob: %v", err)
+	}
+	if err := objects.Delete(ctx, "games/game-1/meta/"+hash); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	got, err := bstore.getBlob(ctx, "game-1", storage.BlobKindMeta, hash)
+	if err != nil || string(got) != string(data) {
+		t.Fatalf("getBlob from cache = %q, %v", got, err)
+	}
+	if _, err := withoutCache(bstore).getBlob(ctx, "game-1", storage.BlobKindMeta, hash); err == nil {
+		t.Fatal("uncached read should notice the missing object")
+	}
+
+	// 壊れたキャッシュは使わない。
+	if err := os.WriteFile(filepath.Join(cacheDir, "game-1", storage.BlobKindMeta, hash), []byte("broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bstore.getBlob(ctx, "game-1", storage.BlobKindMeta, hash); err == nil {
+		t.Fatal("corrupted cache entry should not be returned")
+	}
+
+	// セーブファイルの実データはキャッシュしない。
+	object := []byte("save data")
+	if err := bstore.putBlob(ctx, "game-1", storage.BlobKindObject, hashBytes(object), object); err != nil {
+		t.Fatalf("putBlob object: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "game-1", storage.BlobKindObject)); !os.IsNotExist(err) {
+		t.Fatalf("objects should not be cached: %v", err)
+	}
+
+	if err := bstore.deleteByPrefix(ctx, "games/game-1/"); err != nil {
+		t.Fatalf("deleteByPrefix: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "game-1")); !os.IsNotExist(err) {
+		t.Fatalf("cache of a deleted game should be removed: %v", err)
+	}
+}
+
+func TestRemoteCacheEvictsLeastRecentlyUsedBlobsOverLimit(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	cache := newRemoteCache(cacheDir)
+	blob := func(i int) ([]byte, string) {
+		data := []byte(fmt.Sprintf(`{"n":%03d}`, i))
+		return data, hashBytes(data)
+	}
+	// 上限はちょうど4件分。5件目で古いものから上限の 3/4（3件分）まで消す。
+	sample, _ := blob(0)
+	cache.maxBytes = int64(4 * len(sample))
+	base := time.Now().Add(-time.Hour)
+	for i := range 4 {
+		data, hash := blob(i)
+		cache.writeBlob("game-1", storage.BlobKindMeta, hash, data)
+		path := filepath.Join(cacheDir, "game-1", storage.BlobKindMeta, hash)
+		stamp := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 最初のブロブを読むと最近使ったものになり、消されずに残る。
+	_, firstHash := blob(0)
+	if _, ok := cache.readBlob("game-1", storage.BlobKindMeta, firstHash); !ok {
+		t.Fatal("first blob should be cached")
+	}
+	data, hash := blob(4)
+	cache.writeBlob("game-2", storage.BlobKindMeta, hash, data)
+
+	for i, want := range []bool{true, false, false, true, true} {
+		_, blobHash := blob(i)
+		gameID := "game-1"
+		if i == 4 {
+			gameID = "game-2"
+		}
+		if _, ok := cache.readBlob(gameID, storage.BlobKindMeta, blobHash); ok != want {
+			t.Errorf("blob %d cached = %v, want %v", i, ok, want)
+		}
+	}
+
+	cache.rememberHead("s3:bucket", "game-2", cachedHead{hash: "head", etag: "etag"})
+	cache.forgetGame("game-2")
+	if _, ok := cache.head("s3:bucket", "game-2"); ok {
+		t.Fatal("forgetGame should drop the cached HEAD")
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "game-2")); !os.IsNotExist(err) {
+		t.Fatalf("forgetGame should remove the game's blobs: %v", err)
+	}
+}
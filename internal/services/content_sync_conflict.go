@@ -0,0 +1,206 @@
+// 自動同期でコンフリクトを見つけたときの解決方針と、ユーザーの判断待ちのコンフリクト一覧を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// SyncConflictPolicyPreferLocal はローカルの内容でクラウドを上書きする。
+	SyncConflictPolicyPreferLocal = "prefer-local"
+	// SyncConflictPolicyPreferCloud はクラウドの内容をローカルへ取り込む。
+	SyncConflictPolicyPreferCloud = "prefer-cloud"
+	// SyncConflictPolicyNewestWins はゲーム情報の最終更新（最後にプレイした日時を含む）が新しい側を採用する。
+	SyncConflictPolicyNewestWins = "newest-wins"
+	// SyncConflictPolicyAlwaysAsk は解決せずに判断待ちの一覧へ入れ、ユーザーに選んでもらう。
+	SyncConflictPolicyAlwaysAsk = "always-ask"
+)
+
+// ErrRemoteChanged は Push の開始時・HEAD の書き換え直前に、リモートがローカルの同期基準から進んでいたことを表す。
+var ErrRemoteChanged = errors.New("リモートが更新されています。同期状態を確認してコンフリクトを解決してください")
+
+// ErrSyncConflictPending はコンフリクトを自動では解決せず、ユーザーの判断待ちにしたことを表す。
+var ErrSyncConflictPending = errors.New("コンフリクトの解決方法の選択を待っています")
+
+// NormalizeSyncConflictPolicy はコンフリクトの解決方針を prefer-local|prefer-cloud|newest-wins|always-ask に正規化する。
+// 空は always-ask（方針を導入する前と同じく、自動では解決しない）。
+func NormalizeSyncConflictPolicy(policy string) (string, bool) {
+	switch normalized := strings.ToLower(strings.TrimSpace(policy)); normalized {
+	case "":
+		return SyncConflictPolicyAlwaysAsk, true
+	case SyncConflictPolicyPreferLocal, SyncConflictPolicyPreferCloud, SyncConflictPolicyNewestWins, SyncConflictPolicyAlwaysAsk:
+		return normalized, true
+	default:
+		return "", false
+	}
+}
+
+// SetConflictPolicy は自動同期でのコンフリクトの解決方針を設定する。不正な値は無視する。
+func (s *ContentSyncService) SetConflictPolicy(policy string) {
+	normalized, ok := NormalizeSyncConflictPolicy(policy)
+	if !ok {
+		return
+	}
+	s.config.SyncConflictPolicy = normalized
+}
+
+// conflictPolicy は現在の解決方針を返す。
+func (s *ContentSyncService) conflictPolicy() string {
+	policy, ok := NormalizeSyncConflictPolicy(s.config.SyncConflictPolicy)
+	if !ok {
+		return SyncConflictPolicyAlwaysAsk
+	}
+	return policy
+}
+
+// PushWithConflictPolicy は自動同期用の Push。リモートも更新されていてコンフリクトになった場合は解決方針に従って
+// ローカル採用（上書き Push）かクラウド採用（Pull）で解決する。always-ask のとき、newest-wins で新旧を決められないとき、
+// クラウド採用に未追跡ファイルの削除確認が必要なときは判断待ちの一覧に入れて ErrSyncConflictPending を返す。
+func (s *ContentSyncService) PushWithConflictPolicy(ctx context.Context, gameID string, onProgress TransferProgressFunc) error {
+	err := s.Push(ctx, gameID, onProgress)
+	if !errors.Is(err, ErrRemoteChanged) {
+		return err
+	}
+	detail, statusErr := s.Status(ctx, gameID)
+	if statusErr != nil {
+		s.logger.Warn("コンフリクトの確認に失敗", "gameId", gameID, "error", statusErr)
+		return err
+	}
+	if detail.Status != domain.SyncStatusConflict {
+		// ローカルに変更が無くリモートだけが進んでいる（pull_needed）ならコンフリクトではない。
+		return err
+	}
+	return s.applyConflictPolicy(ctx, gameID, detail)
+}
+
+// applyConflictPolicy は解決方針に従ってコンフリクトを解決するか、判断待ちの一覧へ入れる。
+func (s *ContentSyncService) applyConflictPolicy(ctx context.Context, gameID string, detail domain.SyncStatusDetail) error {
+	policy := s.conflictPolicy()
+	var useLocal bool
+	switch policy {
+	case SyncConflictPolicyPreferLocal:
+		useLocal = true
+	case SyncConflictPolicyPreferCloud:
+		useLocal = false
+	case SyncConflictPolicyNewestWins:
+		newer, err := s.compareConflictSides(ctx, gameID)
+		if err != nil {
+			return err
+		}
+		if newer == 0 {
+			return s.recordPendingConflict(ctx, gameID, detail)
+		}
+		useLocal = newer > 0
+	default:
+		return s.recordPendingConflict(ctx, gameID, detail)
+	}
+	res, err := s.ResolveConflict(ctx, gameID, useLocal, false)
+	if err != nil {
+		return err
+	}
+	if !res.Applied {
+		// 未追跡ファイルの削除はユーザーの確認なしには行わない。
+		return s.recordPendingConflict(ctx, gameID, detail)
+	}
+	s.logger.Info("コンフリクトを同期方針で解決", "gameId", gameID, "policy", policy, "useLocal", useLocal)
+	return nil
+}
+
+// compareConflictSides はローカルとクラウドのゲーム情報の最終更新を比べ、ローカルが新しければ 1、
+// クラウドが新しければ -1、同じなら 0 を返す。最終更新はゲーム情報の更新日時と最後にプレイした日時の遅い方。
+func (s *ContentSyncService) compareConflictSides(ctx context.Context, gameID string) (int, error) {
+	localGame, err := s.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		return 0, err
+	}
+	if localGame == nil {
+		return 0, fmt.Errorf("ゲームが見つかりません: %s", gameID)
+	}
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return 0, err
+	}
+	remote, err := s.fetchRemoteCommit(ctx, bstore, gameID, nil)
+	if err != nil {
+		return 0, err
+	}
+	localAt := *laterTime(&localGame.UpdatedAt, localGame.LastPlayed)
+	remoteAt := *laterTime(&remote.Game.UpdatedAt, remote.Game.LastPlayed)
+	return localAt.Compare(remoteAt), nil
+}
+
+// recordPendingConflict はコンフリクトを判断待ちの一覧に入れ（既にあれば検出時点の内容で置き換え）、ErrSyncConflictPending を返す。
+func (s *ContentSyncService) recordPendingConflict(ctx context.Context, gameID string, detail domain.SyncStatusDetail) error {
+	conflict := domain.SyncConflict{GameID: gameID, DetectedAt: time.Now()}
+	if game, err := s.repository.GetGameByID(ctx, gameID); err == nil && game != nil {
+		conflict.Title = game.Title
+	}
+	if detail.LocalMeta != nil {
+		conflict.LocalMeta = *detail.LocalMeta
+	}
+	if detail.RemoteMeta != nil {
+		conflict.RemoteMeta = *detail.RemoteMeta
+	}
+	s.conflictsMu.Lock()
+	if s.pendingConflicts == nil {
+		s.pendingConflicts = make(map[string]domain.SyncConflict)
+	}
+	s.pendingConflicts[gameID] = conflict
+	s.conflictsMu.Unlock()
+	s.logger.Info("コンフリクトを判断待ちにしました", "gameId", gameID)
+	return ErrSyncConflictPending
+}
+
+// clearPendingConflict は gameID を判断待ちの一覧から外す。Push・Pull・コンフリクトの解決が成功したときに呼ぶ。
+func (s *ContentSyncService) clearPendingConflict(gameID string) {
+	s.conflictsMu.Lock()
+	defer s.conflictsMu.Unlock()
+	delete(s.pendingConflicts, gameID)
+}
+
+// PendingConflicts はユーザーの判断待ちのコンフリクトをゲームID順に返す。一覧はメモリ上にだけ持ち、再起動で消える
+// （次の自動同期で再び検出される）。
+func (s *ContentSyncService) PendingConflicts() []domain.SyncConflict {
+	s.conflictsMu.Lock()
+	defer s.conflictsMu.Unlock()
+	conflicts := make([]domain.SyncConflict, 0, len(s.pendingConflicts))
+	for _, conflict := range s.pendingConflicts {
+		conflicts = append(conflicts, conflict)
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].GameID < conflicts[j].GameID })
+	return conflicts
+}
+
+// ResolveConflicts はユーザーの判断をゲームごとに ResolveConflict で適用する。失敗したゲームがあっても残りは続ける。
+// クラウド採用で未追跡ファイルの削除確認が必要になったゲームは PendingConfirmation に入れ、一覧に残す。
+func (s *ContentSyncService) ResolveConflicts(ctx context.Context, decisions []domain.SyncConflictDecision) domain.SyncConflictResolveResult {
+	res := domain.SyncConflictResolveResult{
+		Resolved:            []string{},
+		PendingConfirmation: []string{},
+		Failed:              []domain.PullAllFailed{},
+	}
+	for _, decision := range decisions {
+		gameID := strings.TrimSpace(decision.GameID)
+		if gameID == "" {
+			continue
+		}
+		pulled, err := s.ResolveConflict(ctx, gameID, decision.UseLocal, decision.DeleteUntracked)
+		switch {
+		case err != nil:
+			s.logger.Warn("コンフリクトの解決に失敗（続行）", "gameId", gameID, "error", err)
+			res.Failed = append(res.Failed, domain.PullAllFailed{GameID: gameID, Message: err.Error()})
+		case !pulled.Applied:
+			res.PendingConfirmation = append(res.PendingConfirmation, gameID)
+		default:
+			res.Resolved = append(res.Resolved, gameID)
+		}
+	}
+	return res
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// setupConflict はローカルとリモートの両方が同期基準から変更された状態を作り、リモートの HEAD を返す。
+// remoteUpdatedAt はリモート側の game.json の更新日時。
+func setupConflict(t *testing.T, remoteUpdatedAt time.Time) (*fakeContentSyncRepository, *fakeBlobStore, domain.Game, string) {
+	t.Helper()
+	saveDir := t.TempDir()
+	savePath := filepath.Join(saveDir, "save.dat")
+	if err := os.WriteFile(savePath, []byte("base"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	bstore := newFakeBlobStore()
+	baseFP := contentFingerprint(setupRemoteState(t, bstore, game.ID, game, nil, saveDir))
+	game.LocalSyncHead = &baseFP
+
+	if err := os.WriteFile(savePath, []byte("local"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	remoteDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(remoteDir, "save.dat"), []byte("remote"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	remoteGame := game
+	remoteGame.UpdatedAt = remoteUpdatedAt
+	setupRemoteState(t, bstore, game.ID, remoteGame, nil, remoteDir)
+	return newFakeRepo(&game, nil), bstore, game, bstore.heads[game.ID]
+}
+
+func TestNormalizeSyncConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	if policy, ok := NormalizeSyncConflictPolicy(""); !ok || policy != SyncConflictPolicyAlwaysAsk {
+		t.Fatalf("empty policy = %q, %v", policy, ok)
+	}
+	if policy, ok := NormalizeSyncConflictPolicy(" Newest-Wins "); !ok || policy != SyncConflictPolicyNewestWins {
+		t.Fatalf("newest-wins = %q, %v", policy, ok)
+	}
+	if _, ok := NormalizeSyncConflictPolicy("merge"); ok {
+		t.Fatal("unknown policy should be rejected")
+	}
+}
+
+func TestPushWithConflictPolicyAlwaysAskQueuesConflict(t *testing.T) {
+	t.Parallel()
+
+	repo, bstore, game, remoteHead := setupConflict(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	svc := newTestService(repo, bstore)
+	ctx := context.Background()
+
+	if err := svc.PushWithConflictPolicy(ctx, game.ID, nil); !errors.Is(err, ErrSyncConflictPending) {
+		t.Fatalf("PushWithConflictPolicy error = %v, want ErrSyncConflictPending", err)
+	}
+	if bstore.heads[game.ID] != remoteHead {
+		t.Fatal("remote HEAD should be left as is while waiting for a decision")
+	}
+	pending := svc.PendingConflicts()
+	if len(pending) != 1 || pending[0].GameID != game.ID || pending[0].Title != game.Title || pending[0].RemoteMeta.Saves == "" {
+		t.Fatalf("unexpected pending conflicts: %+v", pending)
+	}
+
+	res := svc.ResolveConflicts(ctx, []domain.SyncConflictDecision{{GameID: game.ID, UseLocal: true}})
+	if len(res.Resolved) != 1 || len(res.Failed) != 0 || len(res.PendingConfirmation) != 0 {
+		t.Fatalf("unexpected resolve result: %+v", res)
+	}
+	if bstore.heads[game.ID] == remoteHead {
+		t.Fatal("local decision should overwrite the remote HEAD")
+	}
+	if pending := svc.PendingConflicts(); len(pending) != 0 {
+		t.Fatalf("resolved conflict should leave the pending list: %+v", pending)
+	}
+}
+
+func TestPushWithConflictPolicyPreferLocalOverwritesRemote(t *testing.T) {
+	t.Parallel()
+
+	repo, bstore, game, remoteHead := setupConflict(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	svc := newTestService(repo, bstore)
+	svc.SetConflictPolicy(SyncConflictPolicyPreferLocal)
+
+	if err := svc.PushWithConflictPolicy(context.Background(), game.ID, nil); err != nil {
+		t.Fatalf("PushWithConflictPolicy: %v", err)
+	}
+	if bstore.heads[game.ID] == remoteHead {
+		t.Fatal("expected remote HEAD to be overwritten")
+	}
+	if pending := svc.PendingConflicts(); len(pending) != 0 {
+		t.Fatalf("no conflict should be pending: %+v", pending)
+	}
+}
+
+func TestPushWithConflictPolicyNewestWinsPullsNewerRemote(t *testing.T) {
+	t.Parallel()
+
+	repo, bstore, game, remoteHead := setupConflict(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	svc := newTestService(repo, bstore)
+	svc.SetConflictPolicy(SyncConflictPolicyNewestWins)
+
+	if err := svc.PushWithConflictPolicy(context.Background(), game.ID, nil); err != nil {
+		t.Fatalf("PushWithConflictPolicy: %v", err)
+	}
+	if bstore.heads[game.ID] != remoteHead {
+		t.Fatal("newer remote should be kept")
+	}
+	data, err := os.ReadFile(filepath.Join(*game.SaveFolderPath, "save.dat"))
+	if err != nil || string(data) != "remote" {
+		t.Fatalf("local save should be replaced by the remote one, got %q (%v)", data, err)
+	}
+}
+
+func TestPushWithConflictPolicyNewestWinsAsksOnTie(t *testing.T) {
+	t.Parallel()
+
+	repo, bstore, game, _ := setupConflict(t, baseGame("").UpdatedAt)
+	svc := newTestService(repo, bstore)
+	svc.SetConflictPolicy(SyncConflictPolicyNewestWins)
+
+	if err := svc.PushWithConflictPolicy(context.Background(), game.ID, nil); !errors.Is(err, ErrSyncConflictPending) {
+		t.Fatalf("PushWithConflictPolicy error = %v, want ErrSyncConflictPending", err)
+	}
+}
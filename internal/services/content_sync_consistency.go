@@ -0,0 +1,286 @@
+// クラウド上のメタデータとオブジェクトの整合性チェック・記録・修復を提供する。
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// cloudConsistencyLogKey は Settings テーブル上で整合性チェック結果の履歴（同期ログ）を保存するキー。
+const cloudConsistencyLogKey = "cloud_consistency_log"
+
+// cloudConsistencyLogRetention は保持するチェック結果の件数。週1回の実行で約2か月半分。
+const cloudConsistencyLogRetention = 10
+
+// CheckCloudConsistency はクラウド上の全ゲームについて、HEAD から辿れるコミット・セーブスナップショット・
+// game.json・sessions.json・routes.json が読めるか、画像とセーブのオブジェクトが存在するかを確認する。
+// 結果は同期ログへ記録する。オフラインモード時は ErrOffline を返す。
+func (s *ContentSyncService) CheckCloudConsistency(ctx context.Context) (domain.CloudConsistencyReport, error) {
+	if s.offline.Load() {
+		return domain.CloudConsistencyReport{}, ErrOffline
+	}
+	report, err := s.checkCloudConsistency(ctx)
+	if err != nil {
+		return domain.CloudConsistencyReport{}, err
+	}
+	s.recordCloudConsistency(ctx, report)
+	return report, nil
+}
+
+func (s *ContentSyncService) checkCloudConsistency(ctx context.Context) (domain.CloudConsistencyReport, error) {
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return domain.CloudConsistencyReport{}, err
+	}
+	// キャッシュにあってもクラウドから消えていれば問題として見つける。
+	bstore = withoutCache(bstore)
+	gameIDs, err := bstore.listGameIDs(ctx)
+	if err != nil {
+		return domain.CloudConsistencyReport{}, err
+	}
+	sort.Strings(gameIDs)
+
+	var mu sync.Mutex
+	problems := make([]domain.CloudConsistencyProblem, 0)
+	fanOutGames(gameIDs, s.config.S3UploadConcurrency, func(id string) *struct{} {
+		found := s.checkCloudGameConsistency(ctx, bstore, id)
+		mu.Lock()
+		problems = append(problems, found...)
+		mu.Unlock()
+		return nil
+	})
+	// 並列実行で順序が崩れるため、ゲームID順に並べ直す（同一ゲーム内はチェック順を保つ）。
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].GameID < problems[j].GameID })
+
+	return domain.CloudConsistencyReport{
+		CheckedAt:    time.Now().UTC(),
+		GameCount:    len(gameIDs),
+		Problems:     problems,
+		Repaired:     []string{},
+		RepairFailed: []domain.CloudRepairFailure{},
+	}, nil
+}
+
+// checkCloudGameConsistency はゲーム1件の問題を列挙する。
+// コミットが読めない場合はそこから先を辿れないため、その時点で打ち切る。
+func (s *ContentSyncService) checkCloudGameConsistency(ctx context.Context, bstore contentBlobStore, gameID string) []domain.CloudConsistencyProblem {
+	var problems []domain.CloudConsistencyProblem
+	title := ""
+	add := func(kind domain.CloudProblemKind, detail string) {
+		problems = append(problems, domain.CloudConsistencyProblem{GameID: gameID, Kind: kind, Detail: detail})
+	}
+	finish := func(meta *domain.MetaSnapshot) []domain.CloudConsistencyProblem {
+		repairable := s.cloudProblemRepairable(ctx, gameID, meta)
+		for i := range problems {
+			problems[i].Title = title
+			problems[i].Repairable = repairable
+		}
+		return problems
+	}
+
+	head, err := bstore.readHEAD(ctx, gameID)
+	if err != nil {
+		add(domain.CloudProblemHeadUnreadable, err.Error())
+		return finish(nil)
+	}
+	if head == "" {
+		return nil
+	}
+	var meta domain.MetaSnapshot
+	if err := readCloudJSON(ctx, bstore, gameID, storage.BlobKindCommit, head, &meta); err != nil {
+		add(domain.CloudProblemCommitUnreadable, err.Error())
+		return finish(nil)
+	}
+
+	var saveSnap domain.SaveSnapshot
+	saveSnapOK := true
+	if err := readCloudJSON(ctx, bstore, gameID, storage.BlobKindTree, meta.Saves, &saveSnap); err != nil {
+		add(domain.CloudProblemTreeUnreadable, err.Error())
+		saveSnapOK = false
+	}
+
+	var cloudG cloudGame
+	gameOK := true
+	if err := readCloudJSON(ctx, bstore, gameID, storage.BlobKindMeta, meta.GameJSON, &cloudG); err != nil {
+		add(domain.CloudProblemGameJSONUnreadable, err.Error())
+		gameOK = false
+	} else if cloudG.ID != gameID {
+		add(domain.CloudProblemGameJSONUnreadable, fmt.Sprintf("別のゲームIDが記録されています: %s", cloudG.ID))
+		gameOK = false
+	} else {
+		title = cloudG.Title
+	}
+
+	if _, err := loadCloudSessions(ctx, bstore, gameID, meta, nil); err != nil {
+		add(domain.CloudProblemSessionsJSONUnreadable, err.Error())
+	}
+	if meta.RoutesJSON != "" {
+		var cloudRoutes []cloudRoute
+		if err := readCloudJSON(ctx, bstore, gameID, storage.BlobKindMeta, meta.RoutesJSON, &cloudRoutes); err != nil {
+			add(domain.CloudProblemRoutesJSONUnreadable, err.Error())
+		}
+	}
+
+	hashes, err := bstore.listObjectHashes(ctx, gameID)
+	if err != nil {
+		// 一覧が取れないだけでは欠損とは言えないため、オブジェクトの確認だけを見送る。
+		s.logger.Warn("クラウドオブジェクトの一覧取得に失敗（整合性チェックの一部を省略）", "gameId", gameID, "error", err)
+		return finish(&meta)
+	}
+	if gameOK && cloudG.ImageHash != "" {
+		if _, ok := hashes[cloudG.ImageHash]; !ok {
+			add(domain.CloudProblemImageMissing, fmt.Sprintf("画像 %s がありません", cloudG.ImageHash))
+		}
+	}
+	if meta.SavesPack != "" {
+		if _, ok := hashes[meta.SavesPack]; !ok {
+			add(domain.CloudProblemSaveObjectsMissing, fmt.Sprintf("セーブパック %s がありません", meta.SavesPack))
+		}
+	} else if saveSnapOK {
+		missing := 0
+		for _, hash := range saveSnap.Files {
+			if _, ok := hashes[hash]; !ok {
+				missing++
+			}
+		}
+		if missing > 0 {
+			add(domain.CloudProblemSaveObjectsMissing, fmt.Sprintf("セーブファイル %d 件のデータがありません", missing))
+		}
+	}
+	return finish(&meta)
+}
+
+// readCloudJSON はブロブを取得して JSON として読み込む。ハッシュが空ならコミットに記録が無いものとして扱う。
+func readCloudJSON(ctx context.Context, bstore contentBlobStore, gameID, kind, hash string, out any) error {
+	if strings.TrimSpace(hash) == "" {
+		return fmt.Errorf("コミットに %s の参照がありません", kind)
+	}
+	data, err := bstore.getBlob(ctx, gameID, kind, hash)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("JSON の解析に失敗: %w", err)
+	}
+	return nil
+}
+
+// cloudProblemRepairable はローカルの状態を push し直して問題を解消できるかを返す。
+// ローカルにゲームとセーブフォルダがあり、リモートが前回同期以降に他端末で更新されていないこと
+// （コミット自体が読めない場合は比較できないので、ローカルがあれば可）を条件とする。
+func (s *ContentSyncService) cloudProblemRepairable(ctx context.Context, gameID string, remoteMeta *domain.MetaSnapshot) bool {
+	game, err := s.repository.GetGameByID(ctx, gameID)
+	if err != nil || game == nil {
+		return false
+	}
+	if game.SaveFolderPath == nil || *game.SaveFolderPath == "" {
+		return false
+	}
+	if remoteMeta == nil {
+		return true
+	}
+	return game.LocalSyncHead != nil && *game.LocalSyncHead == contentFingerprint(*remoteMeta)
+}
+
+// RepairCloudConsistency は整合性チェックをやり直し、修復可能な問題を持つゲームをローカルから push し直す。
+// コミットが読めないゲームはリモート HEAD と比較できないため強制 push する（旧 HEAD は履歴に残る）。
+// 修復後にもう一度チェックし、その結果（Repaired / RepairFailed 付き）を同期ログへ記録して返す。
+func (s *ContentSyncService) RepairCloudConsistency(ctx context.Context) (domain.CloudConsistencyReport, error) {
+	if s.offline.Load() {
+		return domain.CloudConsistencyReport{}, ErrOffline
+	}
+	before, err := s.checkCloudConsistency(ctx)
+	if err != nil {
+		return domain.CloudConsistencyReport{}, err
+	}
+
+	repaired := []string{}
+	failed := []domain.CloudRepairFailure{}
+	for _, gameID := range before.RepairableGameIDs() {
+		force := false
+		for _, problem := range before.Problems {
+			if problem.GameID == gameID && (problem.Kind == domain.CloudProblemHeadUnreadable || problem.Kind == domain.CloudProblemCommitUnreadable) {
+				force = true
+			}
+		}
+		if err := s.repairCloudGame(ctx, gameID, force); err != nil {
+			s.logger.Warn("クラウド整合性の修復に失敗（続行）", "gameId", gameID, "error", err)
+			failed = append(failed, domain.CloudRepairFailure{GameID: gameID, Message: err.Error()})
+			continue
+		}
+		repaired = append(repaired, gameID)
+	}
+
+	after, err := s.checkCloudConsistency(ctx)
+	if err != nil {
+		return domain.CloudConsistencyReport{}, err
+	}
+	after.Repaired = repaired
+	after.RepairFailed = failed
+	s.recordCloudConsistency(ctx, after)
+	s.logger.Info("クラウド整合性を修復", "repaired", len(repaired), "failed", len(failed), "remaining", len(after.Problems))
+	return after, nil
+}
+
+func (s *ContentSyncService) repairCloudGame(ctx context.Context, gameID string, force bool) error {
+	defer s.lockGame(gameID)()
+	return s.push(ctx, gameID, nil, force)
+}
+
+// CloudConsistencyLog は記録済みの整合性チェック結果を新しい順に返す。
+func (s *ContentSyncService) CloudConsistencyLog(ctx context.Context) ([]domain.CloudConsistencyReport, error) {
+	raw, err := s.repository.GetSetting(ctx, cloudConsistencyLogKey)
+	if err != nil {
+		return nil, err
+	}
+	reports := []domain.CloudConsistencyReport{}
+	if strings.TrimSpace(raw) == "" {
+		return reports, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &reports); err != nil {
+		return nil, fmt.Errorf("同期ログの解析に失敗: %w", err)
+	}
+	return reports, nil
+}
+
+// LastCloudConsistencyCheck は最後に整合性チェックを行った時刻を返す。未実行ならゼロ値。
+func (s *ContentSyncService) LastCloudConsistencyCheck(ctx context.Context) (time.Time, error) {
+	reports, err := s.CloudConsistencyLog(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(reports) == 0 {
+		return time.Time{}, nil
+	}
+	return reports[0].CheckedAt, nil
+}
+
+// recordCloudConsistency はチェック結果を同期ログの先頭に追加し、保持件数を超えた古いものを捨てる。
+// チェック自体は完了しているため、保存の失敗はログのみに留める。
+func (s *ContentSyncService) recordCloudConsistency(ctx context.Context, report domain.CloudConsistencyReport) {
+	reports, err := s.CloudConsistencyLog(ctx)
+	if err != nil {
+		s.logger.Warn("同期ログの読み込みに失敗（作り直します）", "error", err)
+		reports = nil
+	}
+	reports = append([]domain.CloudConsistencyReport{report}, reports...)
+	if len(reports) > cloudConsistencyLogRetention {
+		reports = reports[:cloudConsistencyLogRetention]
+	}
+	payload, err := json.Marshal(reports)
+	if err != nil {
+		s.logger.Warn("同期ログの保存に失敗", "error", err)
+		return
+	}
+	if err := s.repository.UpsertSetting(ctx, cloudConsistencyLogKey, string(payload)); err != nil {
+		s.logger.Warn("同期ログの保存に失敗", "error", err)
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// setupConsistencyState はセーブファイル1件を持つゲームをリモートへ置き、ローカルを同期済みの状態にする。
+func setupConsistencyState(t *testing.T) (*ContentSyncService, *fakeContentSyncRepository, *fakeBlobStore, domain.MetaSnapshot) {
+	t.Helper()
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save01.dat"), []byte("save-data"), 0o600); err != nil {
+		t.Fatalf("write save: %v", err)
+	}
+	game := baseGame(saveDir)
+	repo := newFakeRepo(&game, nil)
+	bstore := newFakeBlobStore()
+	meta := setupRemoteState(t, bstore, game.ID, game, nil, saveDir)
+	fingerprint := contentFingerprint(meta)
+	game.LocalSyncHead = &fingerprint
+	return newTestService(repo, bstore), repo, bstore, meta
+}
+
+func TestCheckCloudConsistencyHealthyRemoteRecordsLog(t *testing.T) {
+	t.Parallel()
+
+	svc, _, _, _ := setupConsistencyState(t)
+	ctx := context.Background()
+
+	report, err := svc.CheckCloudConsistency(ctx)
+	if err != nil {
+		t.Fatalf("CheckCloudConsistency: %v", err)
+	}
+	if report.GameCount != 1 || len(report.Problems) != 0 {
+		t.Fatalf("expected healthy report, got %#v", report)
+	}
+	logs, err := svc.CloudConsistencyLog(ctx)
+	if err != nil {
+		t.Fatalf("CloudConsistencyLog: %v", err)
+	}
+	if len(logs) != 1 || !logs[0].CheckedAt.Equal(report.CheckedAt) {
+		t.Fatalf("expected report to be recorded, got %#v", logs)
+	}
+}
+
+func TestCheckCloudConsistencyDetectsMissingBlobsAndRepairs(t *testing.T) {
+	t.Parallel()
+
+	svc, _, bstore, meta := setupConsistencyState(t)
+	ctx := context.Background()
+	bstore.mu.Lock()
+	delete(bstore.blobs, bstore.blobKey("game-1", storage.BlobKindMeta, meta.SessionsJSON))
+	for key := range bstore.blobs {
+		if filepath.Dir(key) == "game-1/"+storage.BlobKindObject {
+			delete(bstore.blobs, key)
+		}
+	}
+	bstore.mu.Unlock()
+
+	report, err := svc.CheckCloudConsistency(ctx)
+	if err != nil {
+		t.Fatalf("CheckCloudConsistency: %v", err)
+	}
+	kinds := map[domain.CloudProblemKind]bool{}
+	for _, problem := range report.Problems {
+		kinds[problem.Kind] = true
+		if !problem.Repairable || problem.Title != "Test Game" {
+			t.Fatalf("expected repairable problem with title, got %#v", problem)
+		}
+	}
+	// セッションもルートも無いゲームの sessions.json と routes.json は同じ空の一覧で、ブロブを共有する。
+	if !kinds[domain.CloudProblemSessionsJSONUnreadable] || !kinds[domain.CloudProblemRoutesJSONUnreadable] ||
+		!kinds[domain.CloudProblemSaveObjectsMissing] || len(kinds) != 3 {
+		t.Fatalf("unexpected problems: %#v", report.Problems)
+	}
+
+	repaired, err := svc.RepairCloudConsistency(ctx)
+	if err != nil {
+		t.Fatalf("RepairCloudConsistency: %v", err)
+	}
+	if len(repaired.Repaired) != 1 || repaired.Repaired[0] != "game-1" || len(repaired.RepairFailed) != 0 {
+		t.Fatalf("expected game-1 repaired, got %#v", repaired)
+	}
+	if len(repaired.Problems) != 0 {
+		t.Fatalf("expected no remaining problems, got %#v", repaired.Problems)
+	}
+	logs, _ := svc.CloudConsistencyLog(ctx)
+	if len(logs) != 2 || len(logs[0].Repaired) != 1 {
+		t.Fatalf("expected repair result at head of log, got %#v", logs)
+	}
+}
+
+func TestCheckCloudConsistencyRemoteUpdatedElsewhereIsNotRepairable(t *testing.T) {
+	t.Parallel()
+
+	svc, repo, bstore, meta := setupConsistencyState(t)
+	ctx := context.Background()
+	stale := "stale-fingerprint"
+	repo.game.LocalSyncHead = &stale
+	bstore.mu.Lock()
+	delete(bstore.blobs, bstore.blobKey("game-1", storage.BlobKindMeta, meta.SessionsJSON))
+	bstore.mu.Unlock()
+
+	report, err := svc.CheckCloudConsistency(ctx)
+	if err != nil {
+		t.Fatalf("CheckCloudConsistency: %v", err)
+	}
+	if len(report.Problems) == 0 {
+		t.Fatal("expected problems")
+	}
+	for _, problem := range report.Problems {
+		if problem.Repairable {
+			t.Fatalf("expected non-repairable problems, got %#v", report.Problems)
+		}
+	}
+	if ids := report.RepairableGameIDs(); len(ids) != 0 {
+		t.Fatalf("expected no repairable games, got %v", ids)
+	}
+}
+
+func TestCheckCloudConsistencyUnreadableCommit(t *testing.T) {
+	t.Parallel()
+
+	svc, _, bstore, _ := setupConsistencyState(t)
+	ctx := context.Background()
+	head, _ := bstore.readHEAD(ctx, "game-1")
+	_ = bstore.putBlob(ctx, "game-1", storage.BlobKindCommit, head, []byte("{broken"))
+
+	report, err := svc.CheckCloudConsistency(ctx)
+	if err != nil {
+		t.Fatalf("CheckCloudConsistency: %v", err)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].Kind != domain.CloudProblemCommitUnreadable || !report.Problems[0].Repairable {
+		t.Fatalf("expected repairable unreadable commit, got %#v", report.Problems)
+	}
+}
+
+func TestCheckCloudConsistencyOffline(t *testing.T) {
+	t.Parallel()
+
+	svc, _, _, _ := setupConsistencyState(t)
+	svc.SetOfflineMode(true)
+	if _, err := svc.CheckCloudConsistency(context.Background()); err != ErrOffline {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+}
+
+func TestCloudConsistencyJobRunsWeekly(t *testing.T) {
+	t.Parallel()
+
+	svc, _, bstore, meta := setupConsistencyState(t)
+	ctx := context.Background()
+	job := NewCloudConsistencyJob(svc, svc.logger)
+	now := time.Now()
+	job.now = func() time.Time { return now }
+	var reports []domain.CloudConsistencyReport
+	job.SetOnReport(func(report domain.CloudConsistencyReport) { reports = append(reports, report) })
+
+	if !job.RunIfDue(ctx) {
+		t.Fatal("expected first run to execute")
+	}
+	if len(reports) != 0 {
+		t.Fatalf("expected no notification for healthy remote, got %#v", reports)
+	}
+	if job.RunIfDue(ctx) {
+		t.Fatal("expected run within a week to be skipped")
+	}
+
+	bstore.mu.Lock()
+	delete(bstore.blobs, bstore.blobKey("game-1", storage.BlobKindMeta, meta.SessionsJSON))
+	bstore.mu.Unlock()
+	now = now.Add(8 * 24 * time.Hour)
+	if !job.RunIfDue(ctx) {
+		t.Fatal("expected run after a week to execute")
+	}
+	if len(reports) != 1 || len(reports[0].RepairableGameIDs()) != 1 {
+		t.Fatalf("expected notification with repairable game, got %#v", reports)
+	}
+}
@@ -0,0 +1,85 @@
+// クラウド同期ブロブのクライアントサイド暗号化の鍵解決を提供する。
+package services
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+	"CloudLaunch_Go/internal/util"
+)
+
+// blobCipherCache は導出済みの BlobCipher を保持する。
+// 鍵導出（PBKDF2）は意図的に重いため、Push/Pull/Status のたびに導出し直さない。
+type blobCipherCache struct {
+	mu     sync.Mutex
+	key    string
+	cipher *storage.BlobCipher
+}
+
+func (cache *blobCipherCache) get(key string) *storage.BlobCipher {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.key != key {
+		return nil
+	}
+	return cache.cipher
+}
+
+func (cache *blobCipherCache) put(key string, blobCipher *storage.BlobCipher) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.key = key
+	cache.cipher = blobCipher
+}
+
+// resolveBlobCipher は同期先の暗号化設定とパスフレーズから BlobCipher を返す。bucket はログとキャッシュキー用の同期先の名前。
+//   - パスフレーズ無し・バケット未暗号化: nil（平文）
+//   - パスフレーズ有り・バケット未暗号化: 新しいソルトで暗号化設定を作成してから暗号化する
+//   - パスフレーズ無し・バケット暗号化済み: 平文の混入を防ぐため ErrEncryptionPassphraseRequired
+//   - パスフレーズ不一致: ErrEncryptionPassphraseMismatch
+func (s *ContentSyncService) resolveBlobCipher(ctx context.Context, objects storage.ObjectStore, bucket, passphrase string) (*storage.BlobCipher, error) {
+	encryptionCfg, err := storage.ReadEncryptionConfig(ctx, objects)
+	if err != nil {
+		return nil, err
+	}
+	if encryptionCfg == nil {
+		if passphrase == "" {
+			return nil, nil
+		}
+		created, blobCipher, err := storage.NewEncryptionConfig(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		stored, err := storage.CreateEncryptionConfig(ctx, objects, created)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(stored.Salt, created.Salt) {
+			s.logger.Info("バケットの暗号化を有効化", "bucket", bucket)
+			s.cipherCache.put(blobCipherCacheKey(bucket, passphrase, created.Salt), blobCipher)
+			return blobCipher, nil
+		}
+		// 別の端末が同時に暗号化を有効にしていた。その端末の設定（ソルト）に合わせる。
+		encryptionCfg = &stored
+	}
+	if passphrase == "" {
+		return nil, storage.ErrEncryptionPassphraseRequired
+	}
+	cacheKey := blobCipherCacheKey(bucket, passphrase, encryptionCfg.Salt)
+	if cached := s.cipherCache.get(cacheKey); cached != nil {
+		return cached, nil
+	}
+	blobCipher, err := storage.NewBlobCipher(passphrase, *encryptionCfg)
+	if err != nil {
+		return nil, err
+	}
+	s.cipherCache.put(cacheKey, blobCipher)
+	return blobCipher, nil
+}
+
+// blobCipherCacheKey はパスフレーズを平文で保持しないようハッシュ化したキャッシュキーを返す。
+func blobCipherCacheKey(bucket, passphrase string, salt []byte) string {
+	return util.Sha256Hex([]byte(bucket + "\x00" + passphrase + "\x00" + string(salt)))
+}
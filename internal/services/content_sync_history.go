@@ -0,0 +1,156 @@
+// リモートHEAD履歴（メタデータスナップショット）の記録・一覧・復元を提供する。
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// metadataHistoryRetention はゲームごとに保持する HEAD 履歴の件数。
+// コミット本体（commits/）は削除しないため、履歴エントリ自体は小さいが、
+// 一覧取得時にエントリごとにコミットを読むので件数は抑える。
+const metadataHistoryRetention = 20
+
+// MetadataSnapshot は HEAD 書き換え前に退避したリモート状態1件を表す。
+// CreatedAt / DeviceID / DeviceName / FileCount / TotalSize は参照先コミットから読み取った値で、
+// コミットが読めない場合はゼロ値のまま返す。
+type MetadataSnapshot struct {
+	ID         string    `json:"id"`
+	Head       string    `json:"head"`
+	RecordedAt time.Time `json:"recordedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+	DeviceID   string    `json:"deviceId,omitempty"`
+	DeviceName string    `json:"deviceName"`
+	FileCount  int64     `json:"fileCount"`
+	TotalSize  int64     `json:"totalSize"`
+}
+
+// recordHeadHistory は HEAD を nextHead に書き換える前に、現在の HEAD を履歴へ退避する。
+// 初回 push（HEAD 未設定）や同一コミットへの書き換えでは何もしない。
+// 退避に失敗した場合は HEAD を書き換えさせない（戻し先を失ったまま上書きしない）。
+func (s *ContentSyncService) recordHeadHistory(ctx context.Context, bstore contentBlobStore, gameID, currentHead, nextHead string) error {
+	if currentHead == "" || currentHead == nextHead {
+		return nil
+	}
+	now := time.Now().UTC()
+	entry := storage.HeadHistoryEntry{
+		ID:         now.Format(storage.HeadHistoryIDLayout),
+		Head:       currentHead,
+		RecordedAt: now,
+	}
+	if err := bstore.writeHeadHistory(ctx, gameID, entry); err != nil {
+		return fmt.Errorf("HEAD履歴の保存に失敗: %w", err)
+	}
+	s.pruneHeadHistory(ctx, bstore, gameID)
+	return nil
+}
+
+// pruneHeadHistory は保持件数を超えた古い履歴エントリを削除する。
+// HEAD 書き換え自体は成功させたいので、失敗はログのみに留める。
+func (s *ContentSyncService) pruneHeadHistory(ctx context.Context, bstore contentBlobStore, gameID string) {
+	ids, err := bstore.listHeadHistoryIDs(ctx, gameID)
+	if err != nil {
+		s.logger.Warn("HEAD履歴の一覧取得に失敗", "gameId", gameID, "error", err)
+		return
+	}
+	if len(ids) <= metadataHistoryRetention {
+		return
+	}
+	for _, id := range ids[:len(ids)-metadataHistoryRetention] {
+		if err := bstore.deleteHeadHistory(ctx, gameID, id); err != nil {
+			s.logger.Warn("古いHEAD履歴の削除に失敗", "gameId", gameID, "id", id, "error", err)
+		}
+	}
+}
+
+// ListMetadataSnapshots は指定ゲームの HEAD 履歴を新しい順に返す。
+func (s *ContentSyncService) ListMetadataSnapshots(ctx context.Context, gameID string) ([]MetadataSnapshot, error) {
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := bstore.listHeadHistoryIDs(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]MetadataSnapshot, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		entry, err := bstore.readHeadHistory(ctx, gameID, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		// 一覧取得と読み取りの間に prune されたエントリは単に飛ばす。
+		if entry == nil {
+			continue
+		}
+		snapshot := MetadataSnapshot{
+			ID:         entry.ID,
+			Head:       entry.Head,
+			RecordedAt: entry.RecordedAt,
+		}
+		if metaBytes, gerr := bstore.getBlob(ctx, gameID, storage.BlobKindCommit, entry.Head); gerr == nil {
+			var meta domain.MetaSnapshot
+			if json.Unmarshal(metaBytes, &meta) == nil {
+				snapshot.CreatedAt = meta.CreatedAt
+				snapshot.DeviceID = meta.DeviceID
+				snapshot.DeviceName = meta.DeviceName
+				snapshot.FileCount = meta.FileCount
+				snapshot.TotalSize = meta.TotalSize
+			}
+		} else {
+			s.logger.Warn("履歴コミットの取得に失敗", "gameId", gameID, "id", entry.ID, "error", gerr)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// RestoreMetadataSnapshot は指定した履歴エントリのコミットをリモート HEAD に戻す。同一ゲームの同期と直列化される。
+// 復元直前の HEAD も履歴に退避するため、復元自体も取り消せる。
+// ローカルには触れないので、反映するには続けて Pull する（Status は pull_needed / conflict になる）。
+// オフラインモード時は ErrOffline を返す。
+func (s *ContentSyncService) RestoreMetadataSnapshot(ctx context.Context, gameID, snapshotID string) error {
+	if s.offline.Load() {
+		return ErrOffline
+	}
+	if err := storage.ValidateHeadHistoryID(snapshotID); err != nil {
+		return err
+	}
+	defer s.lockGame(gameID)()
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry, err := bstore.readHeadHistory(ctx, gameID, snapshotID)
+	if err != nil {
+		return err
+	}
+	if entry == nil || entry.Head == "" {
+		return fmt.Errorf("履歴が見つかりません: %s", snapshotID)
+	}
+	// 参照先コミットが欠けた HEAD を公開すると以後の Status/Pull が全て失敗するため、先に検証する。
+	metaBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindCommit, entry.Head)
+	if err != nil {
+		return fmt.Errorf("履歴のコミット取得に失敗: %w", err)
+	}
+	var meta domain.MetaSnapshot
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		return fmt.Errorf("履歴のコミット解析に失敗: %w", err)
+	}
+
+	currentHead, err := bstore.readHEAD(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if err := s.recordHeadHistory(ctx, bstore, gameID, currentHead, entry.Head); err != nil {
+		return err
+	}
+	return bstore.writeHEAD(ctx, gameID, entry.Head)
+}
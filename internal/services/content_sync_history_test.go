@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func TestContentSyncServicePushRecordsPreviousHeadInHistory(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	repo := newFakeRepo(&game, nil)
+	bstore := newFakeBlobStore()
+	svc := newTestService(repo, bstore)
+	ctx := context.Background()
+
+	if err := svc.Push(ctx, game.ID, nil); err != nil {
+		t.Fatalf("first Push: %v", err)
+	}
+	if len(bstore.history[game.ID]) != 0 {
+		t.Fatalf("first push should not record history, got %d", len(bstore.history[game.ID]))
+	}
+	firstHead := bstore.heads[game.ID]
+
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("v2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Push(ctx, game.ID, nil); err != nil {
+		t.Fatalf("second Push: %v", err)
+	}
+
+	snapshots, err := svc.ListMetadataSnapshots(ctx, game.ID)
+	if err != nil {
+		t.Fatalf("ListMetadataSnapshots: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("snapshots = %d, want 1", len(snapshots))
+	}
+	if snapshots[0].Head != firstHead {
+		t.Errorf("snapshot head = %q, want %q", snapshots[0].Head, firstHead)
+	}
+	if snapshots[0].DeviceName == "" {
+		t.Error("expected device name to be read from commit")
+	}
+}
+
+func TestContentSyncServiceRestoreMetadataSnapshotRewritesHead(t *testing.T) {
+	t.Parallel()
+
+	game := baseGame(t.TempDir())
+	repo := newFakeRepo(&game, nil)
+	bstore := newFakeBlobStore()
+	svc := newTestService(repo, bstore)
+	ctx := context.Background()
+
+	setupRemoteState(t, bstore, game.ID, game, nil, *game.SaveFolderPath)
+	oldHead := bstore.heads[game.ID]
+	bstore.heads[game.ID] = "broken-head"
+	id := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Format(storage.HeadHistoryIDLayout)
+	_ = bstore.writeHeadHistory(ctx, game.ID, storage.HeadHistoryEntry{ID: id, Head: oldHead})
+
+	if err := svc.RestoreMetadataSnapshot(ctx, game.ID, id); err != nil {
+		t.Fatalf("RestoreMetadataSnapshot: %v", err)
+	}
+	if bstore.heads[game.ID] != oldHead {
+		t.Errorf("HEAD = %q, want %q", bstore.heads[game.ID], oldHead)
+	}
+	// 復元前の HEAD も履歴に残り、復元を取り消せる
+	found := false
+	for _, entry := range bstore.history[game.ID] {
+		if entry.Head == "broken-head" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected HEAD before restore to be recorded in history")
+	}
+}
+
+func TestContentSyncServiceRestoreMetadataSnapshotRejectsMissingCommit(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepo(nil, nil)
+	bstore := newFakeBlobStore()
+	svc := newTestService(repo, bstore)
+	ctx := context.Background()
+
+	bstore.heads["game-1"] = "current"
+	id := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Format(storage.HeadHistoryIDLayout)
+	_ = bstore.writeHeadHistory(ctx, "game-1", storage.HeadHistoryEntry{ID: id, Head: "missing"})
+
+	if err := svc.RestoreMetadataSnapshot(ctx, "game-1", id); err == nil {
+		t.Fatal("expected error for missing commit")
+	}
+	if bstore.heads["game-1"] != "current" {
+		t.Errorf("HEAD should be unchanged, got %q", bstore.heads["game-1"])
+	}
+}
+
+func TestContentSyncServiceRestoreMetadataSnapshotRejectsInvalidID(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(newFakeRepo(nil, nil), newFakeBlobStore())
+	if err := svc.RestoreMetadataSnapshot(context.Background(), "game-1", "../HEAD"); err == nil {
+		t.Fatal("expected error for invalid id")
+	}
+}
+
+func TestContentSyncServiceRestoreMetadataSnapshotReturnsErrOffline(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(newFakeRepo(nil, nil), newFakeBlobStore())
+	svc.SetOfflineMode(true)
+	err := svc.RestoreMetadataSnapshot(context.Background(), "game-1", "20260101T000000.000Z")
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("err = %v, want ErrOffline", err)
+	}
+}
+
+func TestContentSyncServicePruneHeadHistoryKeepsRetention(t *testing.T) {
+	t.Parallel()
+
+	bstore := newFakeBlobStore()
+	svc := newTestService(newFakeRepo(nil, nil), bstore)
+	ctx := context.Background()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	total := metadataHistoryRetention + 5
+	for i := 0; i < total; i++ {
+		id := base.Add(time.Duration(i) * time.Minute).Format(storage.HeadHistoryIDLayout)
+		_ = bstore.writeHeadHistory(ctx, "game-1", storage.HeadHistoryEntry{ID: id, Head: fmt.Sprintf("h%d", i)})
+	}
+
+	svc.pruneHeadHistory(ctx, bstore, "game-1")
+
+	if got := len(bstore.history["game-1"]); got != metadataHistoryRetention {
+		t.Fatalf("history = %d, want %d", got, metadataHistoryRetention)
+	}
+	oldest := base.Format(storage.HeadHistoryIDLayout)
+	if _, ok := bstore.history["game-1"][oldest]; ok {
+		t.Error("expected oldest entry to be pruned")
+	}
+}
@@ -0,0 +1,265 @@
+// コンフリクト時にローカルとクラウドのゲーム情報・セッションをフィールド単位で統合する。
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// MergeConflict はコンフリクトしたゲームを、どちらか一方の丸ごと採用ではなくフィールド単位で統合する。
+// 統合規則は mergeGameRecords を参照。セーブファイルは統合できないため keepLocalSaves で
+// どちらを採るかを選ぶ（false ならリモートを取り込み、Pull と同様に未追跡ファイルの削除確認を経由する）。
+// 統合結果はローカルへ反映したうえで Push し、両端末が同じ内容に揃うようにする。
+// 同一ゲームの同期と直列化される。オフラインモード時は ErrOffline を返す。
+func (s *ContentSyncService) MergeConflict(ctx context.Context, gameID string, keepLocalSaves, deleteUntracked bool) (domain.PullResult, error) {
+	if s.offline.Load() {
+		return domain.PullResult{}, ErrOffline
+	}
+	defer s.lockGame(gameID)()
+	res, err := s.mergeConflict(ctx, gameID, keepLocalSaves, deleteUntracked)
+	if err == nil && res.Applied {
+		s.clearPendingConflict(gameID)
+	}
+	return res, err
+}
+
+func (s *ContentSyncService) mergeConflict(ctx context.Context, gameID string, keepLocalSaves, deleteUntracked bool) (domain.PullResult, error) {
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return domain.PullResult{}, err
+	}
+	remote, err := s.fetchRemoteCommit(ctx, bstore, gameID, nil)
+	if err != nil {
+		return domain.PullResult{}, err
+	}
+	localGame, err := s.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		return domain.PullResult{}, err
+	}
+	if localGame == nil {
+		return domain.PullResult{}, fmt.Errorf("ゲームが見つかりません: %s", gameID)
+	}
+	localSessions, err := s.repository.ListPlaySessionsByGame(ctx, gameID)
+	if err != nil {
+		return domain.PullResult{}, err
+	}
+	localLinks, err := s.repository.ListGameLinksByGame(ctx, gameID)
+	if err != nil {
+		return domain.PullResult{}, err
+	}
+	localRoutes, err := s.repository.ListRoutesByGame(ctx, gameID)
+	if err != nil {
+		return domain.PullResult{}, err
+	}
+
+	merged, sessions := mergeGameRecords(*localGame, localSessions, remote.Game, remote.Sessions)
+	links := mergeGameLinks(localLinks, fromCloudGameLinks(gameID, remote.Game.Links))
+	routes, routeRemap := mergeRoutes(localRoutes, fromCloudRoutes(gameID, remote.Routes), remote.Game.UpdatedAt.After(localGame.UpdatedAt))
+	remapRouteReferences(&merged, sessions, routeRemap)
+	saveFolderPath := localGame.SaveFolderPath
+	if !keepLocalSaves {
+		// remote 採用時と同じく、ローカルに副作用を与える前に削除確認の要否を判定する。
+		trackedDeletes, untrackedDeletes, err := s.pullPlanDeletions(ctx, gameID, saveFolderPath, newSaveFileFilter(merged.SaveIncludePatterns, merged.SaveExcludePatterns), remote.SaveSnap)
+		if err != nil {
+			return domain.PullResult{}, err
+		}
+		if len(untrackedDeletes) > 0 && !deleteUntracked {
+			return domain.PullResult{Applied: false, UntrackedDeletes: untrackedDeletes}, nil
+		}
+		if err := s.pullDownloadSaves(ctx, bstore, gameID, nil, saveFolderPath, remote.SaveSnap, remote.Meta.SavesPack, trackedDeletes, untrackedDeletes); err != nil {
+			return domain.PullResult{}, err
+		}
+	}
+	// 画像はタイトル等と同じくゲーム情報の新しい側に従う。
+	if remote.Game.UpdatedAt.After(localGame.UpdatedAt) {
+		imagePath, err := s.pullDownloadImage(ctx, bstore, gameID, remote.Game, localGame.ImagePath)
+		if err != nil {
+			return domain.PullResult{}, err
+		}
+		merged.ImagePath = imagePath
+	}
+
+	// 同期基準をリモートに合わせて保存すると、統合で生じた差分は push_needed として扱われ、
+	// 直後の Push（!force）がリモート HEAD の再確認付きで通る。
+	upserts, deletes, err := diffSessions(localSessions, sessions)
+	if err != nil {
+		return domain.PullResult{}, err
+	}
+	if err := s.repository.ApplyPullResult(ctx, merged, upserts, deletes, links, routes, contentFingerprint(remote.Meta), string(remote.SaveSnapBytes)); err != nil {
+		return domain.PullResult{}, err
+	}
+	if saveFolderPath == nil || *saveFolderPath == "" {
+		// セーブフォルダ未設定のゲームは Push できない。統合結果はローカルにのみ残る。
+		return domain.PullResult{Applied: true}, nil
+	}
+	if err := s.push(ctx, gameID, nil, false); err != nil {
+		return domain.PullResult{Applied: true}, fmt.Errorf("統合結果をローカルに反映しましたが、アップロードに失敗しました: %w", err)
+	}
+	return domain.PullResult{Applied: true}, nil
+}
+
+// mergeGameRecords はローカルとクラウドのゲーム情報・セッションを統合する。
+//
+//   - 累計プレイ時間は両側の値と統合後のセッション合計のうち最大のもの（別の端末で遊んだ分を失わない）、最終プレイ日時は新しい方、クリア日時は先にクリアした方、作成日時は古い方。
+//   - タイトル・ブランド・プレイ状況（カスタムステータスを含む）・お気に入り・セーブの同期パターン・現在のルート・作品情報は UpdatedAt が新しい側の値（同時刻ならローカル）。
+//   - セッションは ID で和集合を取り、同じ ID は UpdatedAt が新しい側を採る（リンクも mergeGameLinks で同様）。
+//   - ルートは mergeRoutes で ID の和集合を取り、クリア状態はどちらかでクリア済みならクリア済みにする。
+//
+// 実行ファイル・セーブフォルダ・画像などのマシン固有フィールドはローカルの値を引き継ぐ。
+func mergeGameRecords(local domain.Game, localSessions []domain.PlaySession, remote cloudGame, remoteSessions []cloudSession) (domain.Game, []domain.PlaySession) {
+	merged := local
+	if remote.UpdatedAt.After(local.UpdatedAt) {
+		merged.Title = remote.Title
+		merged.Publisher = remote.Publisher
+		merged.PlayStatus = remote.PlayStatus
+		merged.CurrentRouteID = remote.CurrentRouteID
+		merged.Description = remote.Description
+		merged.ReleaseDate = remote.ReleaseDate
+		merged.Rating = remote.Rating
+		merged.Genres = remote.Genres
+		merged.CustomStatus = remote.CustomStatus
+		merged.IsFavorite = remote.IsFavorite
+		merged.SaveIncludePatterns = remote.SaveIncludePatterns
+		merged.SaveExcludePatterns = remote.SaveExcludePatterns
+		merged.UpdatedAt = remote.UpdatedAt
+	}
+	merged.TotalPlayTime = max(local.TotalPlayTime, remote.TotalPlayTime)
+	merged.LastPlayed = laterTime(local.LastPlayed, remote.LastPlayed)
+	merged.ClearedAt = earlierTime(local.ClearedAt, remote.ClearedAt)
+	if !remote.CreatedAt.IsZero() && remote.CreatedAt.Before(merged.CreatedAt) {
+		merged.CreatedAt = remote.CreatedAt
+	}
+
+	byID := make(map[string]domain.PlaySession, len(localSessions)+len(remoteSessions))
+	for _, session := range localSessions {
+		byID[session.ID] = session
+	}
+	for _, cs := range remoteSessions {
+		if existing, ok := byID[cs.ID]; ok && !cs.UpdatedAt.After(existing.UpdatedAt) {
+			continue
+		}
+		byID[cs.ID] = fromCloudSession(local.ID, cs)
+	}
+	sessions := make([]domain.PlaySession, 0, len(byID))
+	for _, session := range byID {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].PlayedAt.Equal(sessions[j].PlayedAt) {
+			return sessions[i].PlayedAt.Before(sessions[j].PlayedAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	// 累計はどちらの端末でも手動調整され得るため、セッション合計で置き換えずに下限として使う。
+	sessionTotal, _ := aggregateSessions(sessions, nil)
+	merged.TotalPlayTime = max(merged.TotalPlayTime, sessionTotal)
+	return merged, sessions
+}
+
+// mergeGameLinks はリンクを ID で和集合にし、同じ ID は UpdatedAt が新しい側を採る。
+func mergeGameLinks(local, remote []domain.GameLink) []domain.GameLink {
+	byID := make(map[string]domain.GameLink, len(local)+len(remote))
+	for _, link := range local {
+		byID[link.ID] = link
+	}
+	for _, link := range remote {
+		if existing, ok := byID[link.ID]; ok && !link.UpdatedAt.After(existing.UpdatedAt) {
+			continue
+		}
+		byID[link.ID] = link
+	}
+	links := make([]domain.GameLink, 0, len(byID))
+	for _, link := range byID {
+		links = append(links, link)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].SortOrder != links[j].SortOrder {
+			return links[i].SortOrder < links[j].SortOrder
+		}
+		return links[i].ID < links[j].ID
+	})
+	return links
+}
+
+// mergeRoutes はルートを ID で和集合にする。Route は UpdatedAt を持たないため、同じ ID・同じ名前の
+// 衝突はゲーム情報が新しい側（preferRemote）を採る。順序は (order, 採用側優先) で並べたうえで
+// 0 から振り直し、UNIQUE(gameId, order) に違反しないようにする。
+// クリア状態は和集合で、どちらかでクリア済みならクリア済みにし、クリア日時はゲームの ClearedAt と同じく先の方を採る
+// （別の端末でクリア済みにしたルートが未クリアに戻らないようにする）。見込み時間は採用側に無ければもう一方を引き継ぐ。
+// 同じ名前で ID の違うルートは採用側に寄せ、捨てた ID → 残した ID の対応を remap で返す。
+// セッション・現在のルートの参照は remapRouteReferences でこの対応に合わせる。
+func mergeRoutes(local, remote []domain.Route, preferRemote bool) (routes []domain.Route, remap map[string]string) {
+	primary, secondary := local, remote
+	if preferRemote {
+		primary, secondary = remote, local
+	}
+	seenIDs := make(map[string]int, len(primary)+len(secondary))
+	seenNames := make(map[string]int, len(primary)+len(secondary))
+	routes = make([]domain.Route, 0, len(primary)+len(secondary))
+	remap = make(map[string]string)
+	for _, side := range [][]domain.Route{primary, secondary} {
+		for _, route := range side {
+			index, ok := seenIDs[route.ID]
+			if !ok {
+				if index, ok = seenNames[route.Name]; ok {
+					remap[route.ID] = routes[index].ID
+				}
+			}
+			if ok {
+				kept := &routes[index]
+				kept.Completed = kept.Completed || route.Completed || route.CompletedAt != nil
+				kept.CompletedAt = earlierTime(kept.CompletedAt, route.CompletedAt)
+				if kept.EstimatedTime == nil && route.EstimatedTime != nil {
+					kept.EstimatedTime = route.EstimatedTime
+				}
+				continue
+			}
+			seenIDs[route.ID] = len(routes)
+			seenNames[route.Name] = len(routes)
+			routes = append(routes, route)
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Order < routes[j].Order })
+	for i := range routes {
+		routes[i].Order = int64(i)
+	}
+	return routes, remap
+}
+
+// remapRouteReferences は mergeRoutes で捨てたルートを指す現在のルートとセッションの参照を、残したルートへ付け替える。
+func remapRouteReferences(game *domain.Game, sessions []domain.PlaySession, remap map[string]string) {
+	if len(remap) == 0 {
+		return
+	}
+	if game.CurrentRouteID != nil {
+		if kept, ok := remap[*game.CurrentRouteID]; ok {
+			game.CurrentRouteID = &kept
+		}
+	}
+	for i := range sessions {
+		if sessions[i].RouteID == nil {
+			continue
+		}
+		if kept, ok := remap[*sessions[i].RouteID]; ok {
+			sessions[i].RouteID = &kept
+		}
+	}
+}
+
+func laterTime(left, right *time.Time) *time.Time {
+	if left == nil || (right != nil && right.After(*left)) {
+		return right
+	}
+	return left
+}
+
+func earlierTime(left, right *time.Time) *time.Time {
+	if left == nil || (right != nil && right.Before(*left)) {
+		return right
+	}
+	return left
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestMergeGameRecordsMergesFieldsAndUnionsSessions(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	localPlayed := base.Add(3 * time.Hour)
+	remotePlayed := base.Add(2 * time.Hour)
+	localCleared := base.Add(5 * time.Hour)
+	remoteCleared := base.Add(4 * time.Hour)
+	local := domain.Game{
+		ID:            "game-1",
+		Title:         "Local Title",
+		ExePath:       `C:\game\game.exe`,
+		TotalPlayTime: 30,
+		LastPlayed:    &localPlayed,
+		ClearedAt:     &localCleared,
+		CreatedAt:     base,
+		UpdatedAt:     base.Add(time.Hour),
+	}
+	remote := cloudGame{
+		ID:            "game-1",
+		Title:         "Remote Title",
+		PlayStatus:    domain.PlayStatusPlayed,
+		TotalPlayTime: 45,
+		LastPlayed:    &remotePlayed,
+		ClearedAt:     &remoteCleared,
+		CreatedAt:     base.Add(-time.Hour),
+		UpdatedAt:     base.Add(2 * time.Hour),
+	}
+	localSessions := []domain.PlaySession{
+		{ID: "shared", GameID: "game-1", PlayedAt: base, Duration: 10, UpdatedAt: base},
+		{ID: "local-only", GameID: "game-1", PlayedAt: base.Add(time.Hour), Duration: 20, UpdatedAt: base},
+	}
+	remoteSessions := []cloudSession{
+		{ID: "shared", PlayedAt: base, Duration: 15, UpdatedAt: base.Add(time.Minute)},
+		{ID: "remote-only", PlayedAt: base.Add(30 * time.Minute), Duration: 30, UpdatedAt: base},
+	}
+
+	merged, sessions := mergeGameRecords(local, localSessions, remote, remoteSessions)
+
+	if merged.Title != "Remote Title" || merged.PlayStatus != domain.PlayStatusPlayed {
+		t.Fatalf("expected newer remote fields to win, got %#v", merged)
+	}
+	// 各側の累計は自分のセッションの合計（30・45）。統合後はどちらか一方にしか無い分も含めて 15+20+30。
+	if merged.TotalPlayTime != 65 || !merged.LastPlayed.Equal(localPlayed) {
+		t.Fatalf("expected play time of the merged sessions and latest last played, got %#v", merged)
+	}
+	if !merged.ClearedAt.Equal(remoteCleared) || !merged.CreatedAt.Equal(remote.CreatedAt) {
+		t.Fatalf("expected earliest cleared/created, got %#v", merged)
+	}
+	if merged.ExePath != local.ExePath {
+		t.Fatalf("expected machine specific fields to be kept, got %q", merged.ExePath)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("expected 3 sessions, got %#v", sessions)
+	}
+	if sessions[0].ID != "shared" || sessions[0].Duration != 15 || sessions[1].ID != "remote-only" || sessions[2].ID != "local-only" {
+		t.Fatalf("unexpected sessions: %#v", sessions)
+	}
+	if sessions[1].GameID != "game-1" {
+		t.Fatalf("expected remote session to be bound to game, got %#v", sessions[1])
+	}
+}
+
+func TestContentSyncServiceMergeConflictAppliesMergeAndPushes(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("local"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	remoteGame := baseGame(saveDir)
+	remoteGame.Title = "Renamed"
+	remoteGame.TotalPlayTime = 200
+	remoteGame.UpdatedAt = remoteGame.UpdatedAt.Add(time.Hour)
+	remoteSessions := []domain.PlaySession{{ID: "remote-session", GameID: remoteGame.ID, Duration: 200, PlayedAt: remoteGame.UpdatedAt}}
+	bstore := newFakeBlobStore()
+	setupRemoteState(t, bstore, remoteGame.ID, remoteGame, remoteSessions, saveDir)
+	headBefore, _ := bstore.readHEAD(context.Background(), remoteGame.ID)
+
+	localGame := baseGame(saveDir)
+	localGame.TotalPlayTime = 100
+	staleHead := "stale"
+	localGame.LocalSyncHead = &staleHead
+	localSessions := []domain.PlaySession{{ID: "local-session", GameID: localGame.ID, Duration: 100, PlayedAt: localGame.UpdatedAt}}
+	repo := newFakeRepo(&localGame, localSessions)
+	svc := newTestService(repo, bstore)
+
+	res, err := svc.MergeConflict(context.Background(), localGame.ID, true, false)
+	if err != nil {
+		t.Fatalf("MergeConflict: %v", err)
+	}
+	if !res.Applied {
+		t.Fatalf("expected merge to be applied, got %#v", res)
+	}
+	if repo.upsertedGame == nil || repo.upsertedGame.Title != "Renamed" || repo.upsertedGame.TotalPlayTime != 300 {
+		t.Fatalf("unexpected merged game: %#v", repo.upsertedGame)
+	}
+	if len(repo.sessions) != 2 {
+		t.Fatalf("expected sessions from both sides, got %#v", repo.sessions)
+	}
+	// ローカルにあるセッションは書き直さない。
+	if len(repo.upsertedSessions) != 1 || repo.upsertedSessions[0].ID != "remote-session" {
+		t.Fatalf("expected only the remote session to be written, got %#v", repo.upsertedSessions)
+	}
+	if head, _ := bstore.readHEAD(context.Background(), localGame.ID); head == headBefore {
+		t.Fatalf("expected merged result to be pushed")
+	}
+}
+
+func TestMergeRoutesUnionsAndRenumbers(t *testing.T) {
+	t.Parallel()
+
+	local := []domain.Route{
+		{ID: "shared", Name: "共通(ローカル)", Order: 0},
+		{ID: "local-only", Name: "ヒロインA", Order: 1},
+		{ID: "local-extra", Name: "ヒロインC", Order: 5},
+	}
+	remote := []domain.Route{
+		{ID: "shared", Name: "共通", Order: 0},
+		{ID: "remote-only", Name: "ヒロインB", Order: 1},
+		{ID: "remote-dup", Name: "ヒロインA", Order: 2},
+	}
+
+	// 同じ ID は採用側の内容、同じ名前は採用側の ID に寄せ、order は 0 から振り直す。
+	routes, remap := mergeRoutes(local, remote, true)
+	want := []string{"shared", "remote-only", "remote-dup", "local-extra"}
+	if len(routes) != len(want) {
+		t.Fatalf("expected %d routes, got %#v", len(want), routes)
+	}
+	for i, route := range routes {
+		if route.ID != want[i] || route.Order != int64(i) {
+			t.Fatalf("unexpected merged routes: %#v", routes)
+		}
+	}
+	if routes[0].Name != "共通" {
+		t.Fatalf("expected remote side to win for shared ID, got %#v", routes[0])
+	}
+	if len(remap) != 1 || remap["local-only"] != "remote-dup" {
+		t.Fatalf("expected dropped duplicate to map to the kept route, got %v", remap)
+	}
+}
+
+func TestRemapRouteReferencesPointsToKeptRoute(t *testing.T) {
+	t.Parallel()
+
+	completedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	local := []domain.Route{{ID: "local-a", Name: "ヒロインA", Order: 0, Completed: true, CompletedAt: &completedAt}}
+	remote := []domain.Route{{ID: "remote-a", Name: "ヒロインA", Order: 0}}
+	routes, remap := mergeRoutes(local, remote, true)
+	if len(routes) != 1 || routes[0].ID != "remote-a" || routes[0].CompletedAt == nil {
+		t.Fatalf("expected the kept route to inherit the clear, got %#v", routes)
+	}
+
+	game := domain.Game{CurrentRouteID: strPtr("local-a")}
+	sessions := []domain.PlaySession{{ID: "s1", RouteID: strPtr("local-a")}, {ID: "s2"}}
+	remapRouteReferences(&game, sessions, remap)
+	if *game.CurrentRouteID != "remote-a" || *sessions[0].RouteID != "remote-a" || sessions[1].RouteID != nil {
+		t.Fatalf("unexpected references: game=%v sessions=%#v", *game.CurrentRouteID, sessions)
+	}
+}
+
+func TestMergeRoutesKeepsCompletionFromEitherSide(t *testing.T) {
+	t.Parallel()
+
+	completedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	local := []domain.Route{{ID: "shared", Name: "共通", Order: 0, Completed: true, CompletedAt: &completedAt}}
+	remote := []domain.Route{{ID: "shared", Name: "共通", Order: 0}}
+
+	routes, _ := mergeRoutes(local, remote, true)
+	if len(routes) != 1 || !routes[0].Completed || routes[0].CompletedAt == nil || !routes[0].CompletedAt.Equal(completedAt) {
+		t.Fatalf("expected completion to be carried over, got %#v", routes)
+	}
+}
+
+func TestContentSyncServiceMergeConflictKeepsClearsFromBothSides(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("save"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	remoteClear := base.Add(2 * time.Hour)
+	localClear := base.Add(3 * time.Hour)
+	laterRemoteClear := base.Add(4 * time.Hour)
+
+	// 別の端末（こちらより後に更新）では B と A をクリアし、C は別 ID で登録した。
+	remoteGame := baseGame(saveDir)
+	remoteGame.UpdatedAt = base.Add(time.Hour)
+	remoteRepo := newFakeRepo(&remoteGame, nil)
+	remoteRepo.routes = []domain.Route{
+		{ID: "route-a", Name: "ヒロインA", Order: 0, GameID: remoteGame.ID, Completed: true, CompletedAt: &laterRemoteClear},
+		{ID: "route-b", Name: "ヒロインB", Order: 1, GameID: remoteGame.ID, Completed: true, CompletedAt: &remoteClear},
+		{ID: "route-c-remote", Name: "ヒロインC", Order: 2, GameID: remoteGame.ID},
+	}
+	bstore := newFakeBlobStore()
+	if err := newTestService(remoteRepo, bstore).Push(context.Background(), remoteGame.ID, nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	// こちらでは A（先に）と C をクリアし、B は未クリアのまま。ゲームもクリア済みにした。
+	localGame := baseGame(saveDir)
+	localGame.ClearedAt = &localClear
+	staleHead := "stale"
+	localGame.LocalSyncHead = &staleHead
+	repo := newFakeRepo(&localGame, nil)
+	repo.routes = []domain.Route{
+		{ID: "route-a", Name: "ヒロインA", Order: 0, GameID: localGame.ID, Completed: true, CompletedAt: &localClear},
+		{ID: "route-b", Name: "ヒロインB", Order: 1, GameID: localGame.ID},
+		{ID: "route-c-local", Name: "ヒロインC", Order: 2, GameID: localGame.ID, Completed: true, CompletedAt: &localClear},
+	}
+	res, err := newTestService(repo, bstore).MergeConflict(context.Background(), localGame.ID, true, false)
+	if err != nil || !res.Applied {
+		t.Fatalf("MergeConflict: %#v, %v", res, err)
+	}
+
+	wantClears := map[string]time.Time{"route-a": localClear, "route-b": remoteClear, "route-c-remote": localClear}
+	assertClears := func(label string, routes []domain.Route) {
+		t.Helper()
+		if len(routes) != len(wantClears) {
+			t.Fatalf("%s: expected %d routes, got %#v", label, len(wantClears), routes)
+		}
+		for _, route := range routes {
+			want, ok := wantClears[route.ID]
+			if !ok || !route.Completed || route.CompletedAt == nil || !route.CompletedAt.Equal(want) {
+				t.Fatalf("%s: expected %s to be cleared at %v, got %#v", label, route.ID, want, route)
+			}
+		}
+	}
+	assertClears("merged", repo.appliedRoutes)
+	if repo.upsertedGame == nil || repo.upsertedGame.ClearedAt == nil || !repo.upsertedGame.ClearedAt.Equal(localClear) {
+		t.Fatalf("expected the local game clear to survive the newer remote, got %#v", repo.upsertedGame)
+	}
+
+	// 統合結果は Push され、もう一方の端末にもクリアが届く。
+	pullGame := baseGame(saveDir)
+	pullRepo := newFakeRepo(&pullGame, nil)
+	if _, err := newTestService(pullRepo, bstore).Pull(context.Background(), pullGame.ID, nil, false); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	assertClears("pulled", pullRepo.appliedRoutes)
+}
@@ -0,0 +1,58 @@
+// クラウドデータ閲覧向けに、同期先の単一オブジェクトを復号して読み出す処理を提供する。
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// MaxEncryptedCloudObjectPreviewBytes は暗号化されたオブジェクトをプレビューするときに取得できる最大サイズ。
+// AES-GCM は全体が揃わないと復号できないため、プレビューでも丸ごと取得する必要がある。
+const MaxEncryptedCloudObjectPreviewBytes = 16 * 1024 * 1024
+
+// ReadCloudObject は設定中のストレージバックエンドから key のオブジェクトを取得し、
+// 暗号化されていれば現在のパスフレーズで復号して返す。
+func (s *ContentSyncService) ReadCloudObject(ctx context.Context, key string) ([]byte, error) {
+	store, err := s.newStorageBlobStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.objects.Download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return storage.OpenObject(store.cipher, key, data)
+}
+
+// DownloadCloudObject は key のオブジェクトを復号して dir へ重複しない名前で保存し、保存先のパスとバイト数を返す。
+// 途中で失敗しても中途半端なファイルを残さないよう、一時ファイルに書いてから名前を変える。
+func (s *ContentSyncService) DownloadCloudObject(ctx context.Context, key string, dir string) (string, int64, error) {
+	data, err := s.ReadCloudObject(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	localPath, err := UniqueLocalFilePath(dir, CloudObjectFileName(key))
+	if err != nil {
+		return "", 0, err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(localPath)+".*.part")
+	if err != nil {
+		return "", 0, err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, localPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return "", 0, err
+	}
+	return localPath, int64(len(data)), nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func TestDownloadCloudObjectDecryptsFromLocalBackend(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storageDir := t.TempDir()
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("game data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	svc := newLocalBackendTestService(t, newFakeRepo(&game, nil), storageDir)
+	svc.store = &fakeCredentialStore{loadResult: &credentials.Credential{EncryptionPassphrase: "pass"}}
+	if err := svc.Push(ctx, game.ID, nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	objects, err := filepath.Glob(filepath.Join(storageDir, "games", game.ID, storage.BlobKindObject, "*"))
+	if err != nil || len(objects) != 1 {
+		t.Fatalf("expected one object blob, got %v, %v", objects, err)
+	}
+	key := "games/" + game.ID + "/" + storage.BlobKindObject + "/" + filepath.Base(objects[0])
+	raw, err := os.ReadFile(objects[0])
+	if err != nil || !storage.IsEncryptedEnvelope(raw) {
+		t.Fatalf("blob should be stored encrypted: %v", err)
+	}
+
+	targetDir := t.TempDir()
+	localPath, written, err := svc.DownloadCloudObject(ctx, key, targetDir)
+	if err != nil {
+		t.Fatalf("DownloadCloudObject: %v", err)
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil || !bytes.Equal(data, []byte("game data")) || written != int64(len(data)) {
+		t.Fatalf("downloaded %q (%d bytes), %v", data, written, err)
+	}
+	if entries, _ := os.ReadDir(targetDir); len(entries) != 1 {
+		t.Fatalf("temporary files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func TestContentSyncServicePushWithSaveCompressionRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	saveDirA := t.TempDir()
+	files := map[string]string{
+		"save1.dat":      "slot 1",
+		"data/save2.dat": "slot 2",
+	}
+	for rel, content := range files {
+		path := filepath.Join(saveDirA, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gameA := baseGame(saveDirA)
+	repoA := newFakeRepo(&gameA, nil)
+	bstore := newFakeBlobStore()
+	svcA := newTestService(repoA, bstore)
+	svcA.SetSaveCompression(true)
+	ctx := context.Background()
+
+	if err := svcA.Push(ctx, gameA.ID, nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	// commit にパックが記録され、セーブファイルは個別ブロブとしては置かれない
+	commit, err := bstore.getBlob(ctx, gameA.ID, storage.BlobKindCommit, bstore.heads[gameA.ID])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta domain.MetaSnapshot
+	if err := json.Unmarshal(commit, &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.SavesPack == "" {
+		t.Fatal("expected SavesPack to be recorded in commit")
+	}
+	if _, err := bstore.getBlob(ctx, gameA.ID, storage.BlobKindObject, hashBytes([]byte("slot 1"))); err == nil {
+		t.Error("save file should not be uploaded as an individual blob")
+	}
+
+	// 形式の違いはセーブ差分に見えない
+	head := repoA.localSyncHeadSet
+	gameA.LocalSyncHead = &head
+	detail, err := svcA.Status(ctx, gameA.ID)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if detail.Status != domain.SyncStatusInSync || detail.SavesDiffer {
+		t.Errorf("status = %#v, want in_sync without saves diff", detail)
+	}
+
+	// 圧縮を無効にしている端末でも取り込める
+	saveDirB := t.TempDir()
+	gameB := baseGame(saveDirB)
+	svcB := newTestService(newFakeRepo(&gameB, nil), bstore)
+	if _, err := svcB.Pull(ctx, gameB.ID, nil, false); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	for rel, content := range files {
+		data, err := os.ReadFile(filepath.Join(saveDirB, filepath.FromSlash(rel)))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q (%v), want %q", rel, data, err, content)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// 変更を加えずに同期計画（アップロード・ダウンロード・スキップとその理由）を算出する。
+package services
+
+import (
+	"context"
+	"sort"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// PreviewSync はローカルとクラウドの全ゲームについて、同期したら何が起きるかを返す。
+// 判定は Status() と同じ fingerprint 比較で、ローカル・クラウドとも一切変更しない。
+// ゲーム単位の判定失敗は Error に残して続行する。オフラインモード時は ErrOffline を返す。
+func (s *ContentSyncService) PreviewSync(ctx context.Context) (domain.SyncPlan, error) {
+	if s.offline.Load() {
+		return domain.SyncPlan{}, ErrOffline
+	}
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return domain.SyncPlan{}, err
+	}
+	remoteIDs, err := bstore.listGameIDs(ctx)
+	if err != nil {
+		return domain.SyncPlan{}, err
+	}
+	localGames, err := s.repository.ListGames(ctx, "", domain.GameFilterAll, "title", "asc")
+	if err != nil {
+		return domain.SyncPlan{}, err
+	}
+
+	localByID := make(map[string]domain.Game, len(localGames))
+	for _, game := range localGames {
+		localByID[game.ID] = game
+	}
+	remoteSet := make(map[string]struct{}, len(remoteIDs))
+	gameIDs := make([]string, 0, len(localGames)+len(remoteIDs))
+	for _, id := range remoteIDs {
+		remoteSet[id] = struct{}{}
+		gameIDs = append(gameIDs, id)
+	}
+	for _, game := range localGames {
+		if _, ok := remoteSet[game.ID]; !ok {
+			gameIDs = append(gameIDs, game.ID)
+		}
+	}
+	sort.Strings(gameIDs)
+
+	items := fanOutGames(gameIDs, pullAllConcurrency, func(id string) *domain.SyncPlanItem {
+		var localGame *domain.Game
+		if game, ok := localByID[id]; ok {
+			localGame = &game
+		}
+		_, hasRemote := remoteSet[id]
+		item := s.planGameSync(ctx, bstore, id, localGame, hasRemote)
+		return &item
+	})
+
+	plan := domain.SyncPlan{Items: items}
+	for _, item := range items {
+		switch item.Action {
+		case domain.SyncPlanActionUpload:
+			plan.Upload++
+		case domain.SyncPlanActionDownload:
+			plan.Download++
+		case domain.SyncPlanActionConflict:
+			plan.Conflict++
+		default:
+			plan.Skip++
+		}
+	}
+	return plan, nil
+}
+
+// planGameSync は1ゲーム分の同期予定を判定する。
+func (s *ContentSyncService) planGameSync(ctx context.Context, bstore contentBlobStore, gameID string, localGame *domain.Game, hasRemote bool) domain.SyncPlanItem {
+	item := domain.SyncPlanItem{GameID: gameID}
+	if localGame == nil {
+		item.Action = domain.SyncPlanActionDownload
+		item.Reason = "ローカルに未登録のゲームです"
+		if info := s.loadCloudGameInfo(ctx, bstore, gameID); info != nil {
+			item.Title = info.Title
+		}
+		return item
+	}
+	item.Title = localGame.Title
+
+	hasSaveFolder := localGame.SaveFolderPath != nil && *localGame.SaveFolderPath != ""
+	neverSyncedHere := localGame.LocalSyncHead == nil || *localGame.LocalSyncHead == ""
+	if !hasSaveFolder {
+		// Status / Push はセーブフォルダ必須。PullAll と同じく未同期ならクラウドから取り込む。
+		switch {
+		case hasRemote && neverSyncedHere:
+			item.Action = domain.SyncPlanActionDownload
+			item.Reason = "このPCではまだ同期していません"
+		case hasRemote:
+			item.Action = domain.SyncPlanActionSkip
+			item.Reason = "セーブフォルダが未設定のため比較できません"
+		default:
+			item.Action = domain.SyncPlanActionSkip
+			item.Reason = "セーブフォルダが未設定のためアップロードできません"
+		}
+		return item
+	}
+
+	detail, err := s.Status(ctx, gameID)
+	if err != nil {
+		s.logger.Warn("同期プレビューの判定に失敗", "gameId", gameID, "error", err)
+		item.Action = domain.SyncPlanActionSkip
+		item.Reason = "同期状態を判定できませんでした"
+		item.Error = err.Error()
+		return item
+	}
+	item.Status = detail.Status
+	item.SavesDiffer = detail.SavesDiffer
+	switch detail.Status {
+	case domain.SyncStatusNeverSynced:
+		item.Action = domain.SyncPlanActionUpload
+		item.Reason = "クラウドにデータがありません"
+	case domain.SyncStatusInSync:
+		item.Action = domain.SyncPlanActionSkip
+		item.Reason = "前回の同期から変更がありません"
+	case domain.SyncStatusPushNeeded:
+		item.Action = domain.SyncPlanActionUpload
+		item.Reason = "前回の同期以降にローカルが変更されています"
+	case domain.SyncStatusPullNeeded:
+		item.Action = domain.SyncPlanActionDownload
+		item.Reason = "前回の同期以降にクラウドが更新されています"
+		if neverSyncedHere {
+			item.Reason = "このPCではまだ同期していません"
+		}
+	default:
+		item.Action = domain.SyncPlanActionConflict
+		item.Reason = "ローカルとクラウドの両方が変更されています"
+	}
+	return item
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestContentSyncServicePreviewSyncReportsPlannedActions(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("original"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	bstore := newFakeBlobStore()
+	remoteMeta := setupRemoteState(t, bstore, game.ID, game, nil, saveDir)
+	fp := contentFingerprint(remoteMeta)
+	game.LocalSyncHead = &fp
+	svc := newTestService(newFakeRepo(&game, nil), bstore)
+	headBefore, _ := bstore.readHEAD(context.Background(), game.ID)
+
+	plan, err := svc.PreviewSync(context.Background())
+	if err != nil {
+		t.Fatalf("PreviewSync: %v", err)
+	}
+	if len(plan.Items) != 1 || plan.Items[0].Action != domain.SyncPlanActionSkip || plan.Skip != 1 {
+		t.Fatalf("expected in-sync game to be skipped, got %#v", plan)
+	}
+
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("changed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = svc.PreviewSync(context.Background())
+	if err != nil {
+		t.Fatalf("PreviewSync: %v", err)
+	}
+	item := plan.Items[0]
+	if item.Action != domain.SyncPlanActionUpload || item.Status != domain.SyncStatusPushNeeded || !item.SavesDiffer {
+		t.Fatalf("expected locally changed game to upload, got %#v", item)
+	}
+	if plan.Upload != 1 || item.Title != game.Title || item.Reason == "" {
+		t.Fatalf("unexpected plan: %#v", plan)
+	}
+	// プレビューはリモートに書き込まない。
+	if head, _ := bstore.readHEAD(context.Background(), game.ID); head != headBefore {
+		t.Fatalf("expected remote HEAD to remain %q, got %q", headBefore, head)
+	}
+}
+
+func TestContentSyncServicePreviewSyncIncludesRemoteOnlyAndLocalOnlyGames(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	remoteGame := baseGame(saveDir)
+	remoteGame.ID = "remote-only"
+	remoteGame.Title = "Remote Game"
+	bstore := newFakeBlobStore()
+	setupRemoteState(t, bstore, remoteGame.ID, remoteGame, nil, saveDir)
+
+	localGame := baseGame(t.TempDir())
+	localGame.ID = "local-only"
+	svc := newTestService(newFakeRepo(&localGame, nil), bstore)
+
+	plan, err := svc.PreviewSync(context.Background())
+	if err != nil {
+		t.Fatalf("PreviewSync: %v", err)
+	}
+	if len(plan.Items) != 2 {
+		t.Fatalf("expected 2 items, got %#v", plan.Items)
+	}
+	// Items はゲームID順。
+	local, remote := plan.Items[0], plan.Items[1]
+	if local.GameID != "local-only" || local.Action != domain.SyncPlanActionUpload || local.Status != domain.SyncStatusNeverSynced {
+		t.Fatalf("expected local-only game to upload, got %#v", local)
+	}
+	if remote.GameID != "remote-only" || remote.Action != domain.SyncPlanActionDownload || remote.Title != "Remote Game" {
+		t.Fatalf("expected remote-only game to download, got %#v", remote)
+	}
+	if plan.Upload != 1 || plan.Download != 1 {
+		t.Fatalf("unexpected counts: %#v", plan)
+	}
+}
+
+func TestContentSyncServicePreviewSyncReturnsErrOfflineWhenOffline(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(newFakeRepo(nil, nil), newFakeBlobStore())
+	svc.SetOfflineMode(true)
+
+	if _, err := svc.PreviewSync(context.Background()); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+// クラウド上の全ゲームを並列に取り込む一括 Pull を提供する。
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// pullAllConcurrency は一括 Pull でゲームを同時に処理する数。
+// 各ゲームの Pull 内部でもセーブファイルを S3UploadConcurrency 並列で落とすため、
+// 外側は小さく抑えて同時接続数が掛け算で膨らまないようにする。
+const pullAllConcurrency = 4
+
+type pullAllOutcome int
+
+const (
+	pullAllApplied pullAllOutcome = iota
+	pullAllSkipped
+	pullAllPending
+	pullAllFailed
+)
+
+// PullAll はクラウド上の全ゲームのうち、ローカルに未取り込み、または pull_needed のものを並列に取り込む。
+// push_needed / conflict のゲームはローカル変更を上書きしないようスキップする。
+// onProgress には「処理済みゲーム数 / 全ゲーム数」を渡す（画像・セッション・セーブを含むゲーム単位の進捗）。
+// 未追跡ファイルの削除確認が必要なゲームは取り込まずに PendingConfirmation へ回す。
+// オフラインモード時は ErrOffline を返す。
+func (s *ContentSyncService) PullAll(ctx context.Context, onProgress ProgressFunc) (domain.PullAllResult, error) {
+	if s.offline.Load() {
+		return domain.PullAllResult{}, ErrOffline
+	}
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return domain.PullAllResult{}, err
+	}
+	gameIDs, err := bstore.listGameIDs(ctx)
+	if err != nil {
+		return domain.PullAllResult{}, err
+	}
+	sort.Strings(gameIDs)
+
+	res := domain.PullAllResult{
+		Applied:             []string{},
+		Skipped:             []string{},
+		PendingConfirmation: []string{},
+		Failed:              []domain.PullAllFailed{},
+	}
+	total := len(gameIDs)
+	if total == 0 {
+		return res, nil
+	}
+	if onProgress != nil {
+		onProgress(0, total)
+	}
+
+	var mu sync.Mutex
+	var done atomic.Int32
+	fanOutGames(gameIDs, pullAllConcurrency, func(id string) *struct{} {
+		outcome, perr := s.pullAllOne(ctx, id)
+		mu.Lock()
+		switch outcome {
+		case pullAllApplied:
+			res.Applied = append(res.Applied, id)
+		case pullAllSkipped:
+			res.Skipped = append(res.Skipped, id)
+		case pullAllPending:
+			res.PendingConfirmation = append(res.PendingConfirmation, id)
+		case pullAllFailed:
+			s.logger.Warn("一括Pullでゲームの取り込みに失敗（続行）", "gameId", id, "error", perr)
+			res.Failed = append(res.Failed, domain.PullAllFailed{GameID: id, Message: perr.Error()})
+		}
+		mu.Unlock()
+		if onProgress != nil {
+			onProgress(int(done.Add(1)), total)
+		}
+		return nil
+	})
+
+	sort.Strings(res.Applied)
+	sort.Strings(res.Skipped)
+	sort.Strings(res.PendingConfirmation)
+	sort.Slice(res.Failed, func(i, j int) bool { return res.Failed[i].GameID < res.Failed[j].GameID })
+	return res, nil
+}
+
+// pullAllOne は1ゲーム分の取り込み要否を判定し、必要なら Pull する。
+func (s *ContentSyncService) pullAllOne(ctx context.Context, gameID string) (pullAllOutcome, error) {
+	needed, err := s.needsBulkPull(ctx, gameID)
+	if err != nil {
+		return pullAllFailed, err
+	}
+	if !needed {
+		return pullAllSkipped, nil
+	}
+	// 1ゲームの進捗まで UI に流すと複数ゲームの進捗が混ざるため、ゲーム内の進捗は渡さない。
+	pulled, err := s.Pull(ctx, gameID, nil, false)
+	if err != nil {
+		return pullAllFailed, err
+	}
+	if !pulled.Applied {
+		return pullAllPending, nil
+	}
+	return pullAllApplied, nil
+}
+
+// needsBulkPull は一括 Pull でこのゲームを取り込むべきかを返す。
+// Status はセーブフォルダ必須のため、未設定のゲームは「一度も同期していないか」だけで判定する。
+func (s *ContentSyncService) needsBulkPull(ctx context.Context, gameID string) (bool, error) {
+	localGame, err := s.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	if localGame == nil {
+		return true, nil
+	}
+	if localGame.SaveFolderPath == nil || *localGame.SaveFolderPath == "" {
+		return localGame.LocalSyncHead == nil || *localGame.LocalSyncHead == "", nil
+	}
+	detail, err := s.Status(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	return detail.Status == domain.SyncStatusPullNeeded, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestContentSyncServicePullAllAppliesNewGamesAndReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	remoteDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(remoteDir, "save.dat"), []byte("remote"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(remoteDir)
+	repo := newFakeRepo(nil, nil)
+	bstore := newFakeBlobStore()
+	svc := newTestService(repo, bstore)
+	setupRemoteState(t, bstore, game.ID, game, nil, remoteDir)
+	// コミット本体が欠けたゲーム（途中で壊れたリモート）
+	bstore.heads["broken"] = "missing-commit"
+
+	var mu sync.Mutex
+	var progress [][2]int
+	res, err := svc.PullAll(context.Background(), func(current, total int) {
+		mu.Lock()
+		progress = append(progress, [2]int{current, total})
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("PullAll: %v", err)
+	}
+
+	if len(res.Applied) != 1 || res.Applied[0] != game.ID {
+		t.Errorf("applied = %v, want [%s]", res.Applied, game.ID)
+	}
+	if len(res.Failed) != 1 || res.Failed[0].GameID != "broken" {
+		t.Errorf("failed = %v, want broken", res.Failed)
+	}
+	if repo.upsertedGame == nil || repo.upsertedGame.Title != game.Title {
+		t.Errorf("expected remote game to be applied, got %#v", repo.upsertedGame)
+	}
+	last := progress[len(progress)-1]
+	if last != [2]int{2, 2} {
+		t.Errorf("last progress = %v, want [2 2]", last)
+	}
+}
+
+func TestContentSyncServicePullAllSkipsAlreadyImportedGames(t *testing.T) {
+	t.Parallel()
+
+	remoteDir := t.TempDir()
+	game := baseGame(remoteDir)
+	repo := newFakeRepo(nil, nil)
+	bstore := newFakeBlobStore()
+	svc := newTestService(repo, bstore)
+	setupRemoteState(t, bstore, game.ID, game, nil, remoteDir)
+	ctx := context.Background()
+
+	if _, err := svc.PullAll(ctx, nil); err != nil {
+		t.Fatalf("first PullAll: %v", err)
+	}
+	// 取り込み結果をローカル DB の状態として反映する（fake は GetGameByID に反映しないため）
+	imported := *repo.upsertedGame
+	head := repo.localSyncHeadSet
+	imported.LocalSyncHead = &head
+	repo.game = &imported
+
+	res, err := svc.PullAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("second PullAll: %v", err)
+	}
+	if len(res.Applied) != 0 || len(res.Skipped) != 1 {
+		t.Errorf("expected game to be skipped on resume, got %#v", res)
+	}
+}
+
+func TestContentSyncServicePullAllReturnsErrOffline(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(newFakeRepo(nil, nil), newFakeBlobStore())
+	svc.SetOfflineMode(true)
+	if _, err := svc.PullAll(context.Background(), nil); !errors.Is(err, ErrOffline) {
+		t.Fatalf("err = %v, want ErrOffline", err)
+	}
+}
@@ -0,0 +1,229 @@
+// クラウドのセーブデータを、既存のセーブを退避したうえでゲームのセーブフォルダへ復元する処理を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// SaveBackupDirName は復元前のセーブファイルを退避する AppData 配下のディレクトリ名。
+// 退避先は <AppData>/save_backups/<gameID>/<日時> になる。
+const SaveBackupDirName = "save_backups"
+
+// saveBackupTimeLayout は退避ディレクトリ名に使う日時の書式。
+const saveBackupTimeLayout = "20060102-150405"
+
+// restoreDiskSpaceMargin は復元に必要な容量に上乗せする余裕（展開途中の一時ファイル等）。
+const restoreDiskSpaceMargin = 64 << 20
+
+// ErrInsufficientDiskSpace はセーブフォルダのドライブに復元に必要な空き容量が無いことを表す。
+var ErrInsufficientDiskSpace = errors.New("復元に必要な空き容量がありません")
+
+// RestoreSaveData はクラウドのセーブデータをゲームに設定されたセーブフォルダへ復元する。同一ゲームの同期と直列化される。
+// snapshotID が空なら現在のリモート HEAD、指定すれば ListMetadataSnapshots の履歴の時点のセーブを復元する。
+//
+// 始める前にセーブフォルダのドライブの空き容量を確かめ、既存のセーブファイル（同期対象のものだけ）を
+// <AppData>/save_backups/<gameID>/<日時> へ移してから、空になったフォルダへダウンロードする。
+// ダウンロードに失敗した場合は退避したファイルを元に戻す。ゲーム情報・セッションと同期基準は変えないため、
+// 履歴の時点を復元した後の Status はローカル変更あり（push_needed 等）になる。
+// オフラインモード時は ErrOffline を返す。
+func (s *ContentSyncService) RestoreSaveData(ctx context.Context, gameID, snapshotID string, onProgress TransferProgressFunc) (domain.SaveRestoreResult, error) {
+	if s.offline.Load() {
+		return domain.SaveRestoreResult{}, ErrOffline
+	}
+	defer s.lockGame(gameID)()
+
+	game, err := s.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		return domain.SaveRestoreResult{}, err
+	}
+	if game == nil {
+		return domain.SaveRestoreResult{}, fmt.Errorf("ゲームが見つかりません: %s", gameID)
+	}
+	saveDir := saveFolderRoot(*game)
+	if saveDir == "" {
+		return domain.SaveRestoreResult{}, fmt.Errorf("セーブフォルダが未設定です")
+	}
+
+	bstore, err := s.newBlobStore(ctx)
+	if err != nil {
+		return domain.SaveRestoreResult{}, err
+	}
+	head, err := s.resolveRestoreHead(ctx, bstore, gameID, snapshotID)
+	if err != nil {
+		return domain.SaveRestoreResult{}, err
+	}
+	commit, err := s.fetchCommit(ctx, bstore, gameID, head, nil)
+	if err != nil {
+		return domain.SaveRestoreResult{}, err
+	}
+	if err := s.ensureRestoreDiskSpace(saveDir, commit.Meta.TotalSize); err != nil {
+		return domain.SaveRestoreResult{}, err
+	}
+
+	filter := newSaveFileFilter(game.SaveIncludePatterns, game.SaveExcludePatterns)
+	now := time.Now()
+	backupDir := filepath.Join(s.config.AppDataDir, SaveBackupDirName, gameID, now.Format(saveBackupTimeLayout))
+	moved, err := backupSaveFiles(saveDir, backupDir, filter)
+	if err != nil {
+		return domain.SaveRestoreResult{}, fmt.Errorf("既存のセーブの退避に失敗: %w", err)
+	}
+	if len(moved) == 0 {
+		backupDir = ""
+	}
+
+	if err := s.pullDownloadSaves(ctx, bstore, gameID, onProgress, &saveDir, commit.SaveSnap, commit.Meta.SavesPack, nil, nil); err != nil {
+		if len(moved) > 0 {
+			if rollbackErr := rollbackSaveBackup(saveDir, backupDir, moved); rollbackErr != nil {
+				s.logger.Error("退避したセーブを戻せません", "gameId", gameID, "backupDir", backupDir, "error", rollbackErr)
+				return domain.SaveRestoreResult{}, fmt.Errorf("セーブの復元に失敗しました（退避先: %s）: %w", backupDir, err)
+			}
+		}
+		return domain.SaveRestoreResult{}, err
+	}
+
+	if hash, herr := saveTreeHash(saveDir, filter); herr == nil {
+		if err := s.repository.SetLocalSaveHash(ctx, gameID, hash, now); err != nil {
+			s.logger.Warn("ローカルセーブハッシュの更新に失敗", "gameId", gameID, "error", err)
+		}
+	} else {
+		s.logger.Warn("ローカルセーブハッシュの計算に失敗", "gameId", gameID, "error", herr)
+	}
+	s.logger.Info("クラウドのセーブを復元", "gameId", gameID, "head", head, "saveDir", saveDir, "backupDir", backupDir, "backupFiles", len(moved))
+	return domain.SaveRestoreResult{
+		GameID:     gameID,
+		Head:       head,
+		SaveDir:    saveDir,
+		BackupDir:  backupDir,
+		FileCount:  len(commit.SaveSnap.Files),
+		RestoredAt: now,
+	}, nil
+}
+
+// resolveRestoreHead は復元するコミットを返す。snapshotID が空なら現在のリモート HEAD。
+func (s *ContentSyncService) resolveRestoreHead(ctx context.Context, bstore contentBlobStore, gameID, snapshotID string) (string, error) {
+	if snapshotID == "" {
+		head, err := bstore.readHEAD(ctx, gameID)
+		if err != nil {
+			return "", err
+		}
+		if head == "" {
+			return "", fmt.Errorf("リモートにデータがありません")
+		}
+		return head, nil
+	}
+	if err := storage.ValidateHeadHistoryID(snapshotID); err != nil {
+		return "", err
+	}
+	entry, err := bstore.readHeadHistory(ctx, gameID, snapshotID)
+	if err != nil {
+		return "", err
+	}
+	if entry == nil || entry.Head == "" {
+		return "", fmt.Errorf("履歴が見つかりません: %s", snapshotID)
+	}
+	return entry.Head, nil
+}
+
+// ensureRestoreDiskSpace はセーブフォルダのドライブに required バイト（と余裕分）の空きがあるかを確かめる。
+// 必要量が分からない古いコミット（TotalSize=0）や、空き容量を取得できない環境では確認を省略する。
+func (s *ContentSyncService) ensureRestoreDiskSpace(saveDir string, required int64) error {
+	if required <= 0 {
+		return nil
+	}
+	free, err := s.availableDiskSpace(existingAncestor(saveDir))
+	if err != nil {
+		s.logger.Debug("空き容量を取得できないため確認を省略", "saveDir", saveDir, "error", err)
+		return nil
+	}
+	needed := uint64(required) + restoreDiskSpaceMargin
+	if free < needed {
+		return fmt.Errorf("%w: 必要 %d バイト / 空き %d バイト", ErrInsufficientDiskSpace, needed, free)
+	}
+	return nil
+}
+
+func (s *ContentSyncService) availableDiskSpace(dir string) (uint64, error) {
+	if s.diskFree != nil {
+		return s.diskFree(dir)
+	}
+	return diskFreeBytes(dir)
+}
+
+// existingAncestor は path 自身か、存在する最も近い祖先ディレクトリを返す（まだ無いセーブフォルダのドライブを調べるため）。
+func existingAncestor(path string) string {
+	current := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(current); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return current
+		}
+		current = parent
+	}
+}
+
+// backupSaveFiles は saveDir 内の同期対象のファイルを backupDir の同じ相対パスへ移し、移したファイルの相対パスを返す。
+// 同期対象外のファイル（ログ・設定等）はそのまま残し、移した後に空になったディレクトリは取り除く。
+// saveDir が無ければ何もしない。途中で失敗した場合は移したファイルを元に戻す。
+func backupSaveFiles(saveDir, backupDir string, filter saveFileFilter) ([]string, error) {
+	if _, err := os.Stat(saveDir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	var moved []string
+	err := walkSaveFiles(saveDir, func(absPath, relPath string) error {
+		if !filter.allows(relPath) {
+			return nil
+		}
+		if err := moveFile(absPath, filepath.Join(backupDir, filepath.FromSlash(relPath))); err != nil {
+			return err
+		}
+		moved = append(moved, relPath)
+		return nil
+	})
+	if err != nil {
+		if rollbackErr := rollbackSaveBackup(saveDir, backupDir, moved); rollbackErr != nil {
+			return nil, errors.Join(err, rollbackErr)
+		}
+		return nil, err
+	}
+	if err := applyDeletions(saveDir, moved); err != nil {
+		return moved, err
+	}
+	return moved, nil
+}
+
+// rollbackSaveBackup は backupSaveFiles で退避したファイルを saveDir へ戻す。ダウンロード済みのファイルは上書きする。
+func rollbackSaveBackup(saveDir, backupDir string, moved []string) error {
+	var errs []error
+	for _, relPath := range moved {
+		source := filepath.Join(backupDir, filepath.FromSlash(relPath))
+		if err := moveFile(source, filepath.Join(saveDir, filepath.FromSlash(relPath))); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// moveFile は source を dest へ移す。別ドライブなどで名前の変更ができなければ、コピーしてから元を消す。
+func moveFile(source, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o700); err != nil {
+		return err
+	}
+	if err := os.Rename(source, dest); err == nil {
+		return nil
+	}
+	if err := CopyFilePath(source, dest); err != nil {
+		return err
+	}
+	return os.Remove(source)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestContentSyncServiceRestoreSaveDataBacksUpAndRestores(t *testing.T) {
+	t.Parallel()
+
+	remoteDir := t.TempDir()
+	writeTestFiles(t, remoteDir, map[string]string{"save.dat": "remote", "sub/slot.dat": "slot"})
+	saveDir := t.TempDir()
+	writeTestFiles(t, saveDir, map[string]string{"save.dat": "local", "extra.dat": "local-only", "settings.ini": "keep"})
+
+	game := baseGame(saveDir)
+	game.SaveExcludePatterns = []string{"*.ini"}
+	bstore := newFakeBlobStore()
+	setupRemoteState(t, bstore, game.ID, baseGame(remoteDir), nil, remoteDir)
+	repo := newFakeRepo(&game, nil)
+	svc := newTestService(repo, bstore)
+	svc.config.AppDataDir = t.TempDir()
+
+	res, err := svc.RestoreSaveData(context.Background(), game.ID, "", nil)
+	if err != nil {
+		t.Fatalf("RestoreSaveData: %v", err)
+	}
+	if res.FileCount != 2 || res.BackupDir == "" || res.SaveDir != saveDir {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if got := readTestFile(t, filepath.Join(saveDir, "save.dat")); got != "remote" {
+		t.Fatalf("save.dat should be restored, got %q", got)
+	}
+	if got := readTestFile(t, filepath.Join(saveDir, "sub", "slot.dat")); got != "slot" {
+		t.Fatalf("sub/slot.dat should be restored, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(saveDir, "extra.dat")); !os.IsNotExist(err) {
+		t.Fatalf("local-only save should be moved to the backup, stat err: %v", err)
+	}
+	// 同期対象外のファイルは退避せずに残す。
+	if got := readTestFile(t, filepath.Join(saveDir, "settings.ini")); got != "keep" {
+		t.Fatalf("excluded file should be kept, got %q", got)
+	}
+
+	if filepath.Dir(filepath.Dir(res.BackupDir)) != filepath.Join(svc.config.AppDataDir, SaveBackupDirName) {
+		t.Fatalf("unexpected backup dir: %s", res.BackupDir)
+	}
+	if got := readTestFile(t, filepath.Join(res.BackupDir, "save.dat")); got != "local" {
+		t.Fatalf("backup should hold the previous save, got %q", got)
+	}
+	if got := readTestFile(t, filepath.Join(res.BackupDir, "extra.dat")); got != "local-only" {
+		t.Fatalf("backup should hold the previous save, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(res.BackupDir, "settings.ini")); !os.IsNotExist(err) {
+		t.Fatalf("excluded file should not be backed up, stat err: %v", err)
+	}
+	if game.LocalSaveHash == nil {
+		t.Fatal("local save hash should be refreshed after restore")
+	}
+}
+
+func TestContentSyncServiceRestoreSaveDataFromHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	remoteDir := t.TempDir()
+	writeTestFiles(t, remoteDir, map[string]string{"save.dat": "old"})
+	saveDir := filepath.Join(t.TempDir(), "not-yet-created")
+
+	game := baseGame(saveDir)
+	bstore := newFakeBlobStore()
+	setupRemoteState(t, bstore, game.ID, baseGame(remoteDir), nil, remoteDir)
+	oldHead, _ := bstore.readHEAD(ctx, game.ID)
+	const snapshotID = "20260101T000000.000Z"
+	if err := bstore.writeHeadHistory(ctx, game.ID, storage.HeadHistoryEntry{ID: snapshotID, Head: oldHead}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bstore.writeHEAD(ctx, game.ID, "newer-head"); err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestService(newFakeRepo(&game, nil), bstore)
+	svc.config.AppDataDir = t.TempDir()
+
+	res, err := svc.RestoreSaveData(ctx, game.ID, snapshotID, nil)
+	if err != nil {
+		t.Fatalf("RestoreSaveData: %v", err)
+	}
+	if res.Head != oldHead || res.BackupDir != "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if got := readTestFile(t, filepath.Join(saveDir, "save.dat")); got != "old" {
+		t.Fatalf("history save should be restored, got %q", got)
+	}
+	if _, err := svc.RestoreSaveData(ctx, game.ID, "20990101T000000.000Z", nil); err == nil {
+		t.Fatal("unknown snapshot should be rejected")
+	}
+}
+
+func TestContentSyncServiceRestoreSaveDataChecksDiskSpace(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	remoteDir := t.TempDir()
+	writeTestFiles(t, remoteDir, map[string]string{"save.dat": "remote"})
+	saveDir := t.TempDir()
+	writeTestFiles(t, saveDir, map[string]string{"save.dat": "local"})
+
+	game := baseGame(saveDir)
+	bstore := newFakeBlobStore()
+	meta := setupRemoteState(t, bstore, game.ID, baseGame(remoteDir), nil, remoteDir)
+	// 容量の分かるコミットに差し替える。
+	meta.TotalSize = 1 << 30
+	metaBytes, _ := json.Marshal(meta)
+	metaHash := hashBytes(metaBytes)
+	if err := bstore.putBlob(ctx, game.ID, storage.BlobKindCommit, metaHash, metaBytes); err != nil {
+		t.Fatal(err)
+	}
+	if err := bstore.writeHEAD(ctx, game.ID, metaHash); err != nil {
+		t.Fatal(err)
+	}
+	svc := newTestService(newFakeRepo(&game, nil), bstore)
+	svc.config.AppDataDir = t.TempDir()
+	svc.diskFree = func(string) (uint64, error) { return 1 << 20, nil }
+
+	if _, err := svc.RestoreSaveData(ctx, game.ID, "", nil); !errors.Is(err, ErrInsufficientDiskSpace) {
+		t.Fatalf("expected ErrInsufficientDiskSpace, got %v", err)
+	}
+	if got := readTestFile(t, filepath.Join(saveDir, "save.dat")); got != "local" {
+		t.Fatalf("local save should be untouched, got %q", got)
+	}
+}
@@ -20,8 +20,6 @@ import (
 	"CloudLaunch_Go/internal/domain"
 	"CloudLaunch_Go/internal/infrastructure/credentials"
 	"CloudLaunch_Go/internal/infrastructure/storage"
-
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
 // ErrOffline はオフラインモード設定時に Push/Pull/DeleteFromCloud が返すセンチネル。
@@ -29,49 +27,86 @@ import (
 // ユーザー操作パスでは UI に「オフラインモードです」を表示する。
 var ErrOffline = errors.New("オフラインモードのため同期しません")
 
-// ProgressFunc はセーブファイルの転送進捗を報告するコールバック。
+// ProgressFunc は件数単位（ゲーム数・ファイル数）の進捗を報告するコールバック。
+// セーブファイル転送のバイト数・残り時間まで必要な場合は TransferProgressFunc を使う。
 type ProgressFunc func(current, total int)
 
-// contentBlobStore はS3のブロブ操作を抽象化する（テスト差し替え用）。
+// contentBlobStore は同期先のブロブ操作を抽象化する（テスト差し替え用）。
 type contentBlobStore interface {
 	readHEAD(ctx context.Context, gameID string) (string, error)
 	writeHEAD(ctx context.Context, gameID, hash string) error
 	getBlob(ctx context.Context, gameID, kind, hash string) ([]byte, error)
 	putBlob(ctx context.Context, gameID, kind, hash string, data []byte) error
-	putBlobs(ctx context.Context, gameID string, blobs map[string][]byte, concurrency int, onProgress func(int, int)) error
-	downloadBlobs(ctx context.Context, gameID, saveDir string, blobs map[string]string, concurrency int, onProgress func(int, int)) error
+	putBlobs(ctx context.Context, gameID string, blobs map[string][]byte, concurrency int, onBlob storage.BlobTransferFunc) error
+	downloadBlobs(ctx context.Context, gameID, saveDir string, blobs map[string]string, concurrency int, onFile storage.BlobTransferFunc) error
 	deleteByPrefix(ctx context.Context, prefix string) error
 	listGameIDs(ctx context.Context) ([]string, error)
+	listObjectHashes(ctx context.Context, gameID string) (map[string]struct{}, error)
+	writeHeadHistory(ctx context.Context, gameID string, entry storage.HeadHistoryEntry) error
+	readHeadHistory(ctx context.Context, gameID, id string) (*storage.HeadHistoryEntry, error)
+	listHeadHistoryIDs(ctx context.Context, gameID string) ([]string, error)
+	deleteHeadHistory(ctx context.Context, gameID, id string) error
 }
 
-type s3BlobStore struct {
-	client *s3.Client
-	bucket string
+// objectBlobStore は storage.ObjectStore（S3 またはローカルフォルダ）上で contentBlobStore を実装する。
+type objectBlobStore struct {
+	objects storage.ObjectStore
+	cipher  *storage.BlobCipher // nil なら平文で読み書きする
+	// cache はリモートの JSON のキャッシュ（nil なら使わない）。scope は HEAD のキャッシュを保存先ごとに分けるキー。
+	cache *remoteCache
+	scope string
 }
 
-func (b *s3BlobStore) readHEAD(ctx context.Context, gameID string) (string, error) {
-	return storage.ReadHEAD(ctx, b.client, b.bucket, gameID)
+func (b *objectBlobStore) readHEAD(ctx context.Context, gameID string) (string, error) {
+	if b.cache != nil {
+		return b.readCachedHEAD(ctx, gameID)
+	}
+	return storage.ReadHEAD(ctx, b.objects, gameID)
 }
-func (b *s3BlobStore) writeHEAD(ctx context.Context, gameID, hash string) error {
-	return storage.WriteHEAD(ctx, b.client, b.bucket, gameID, hash)
+func (b *objectBlobStore) writeHEAD(ctx context.Context, gameID, hash string) error {
+	if b.cache != nil {
+		defer b.cache.forgetHead(b.scope, gameID)
+	}
+	return storage.WriteHEAD(ctx, b.objects, gameID, hash)
 }
-func (b *s3BlobStore) getBlob(ctx context.Context, gameID, kind, hash string) ([]byte, error) {
-	return storage.GetBlob(ctx, b.client, b.bucket, gameID, kind, hash)
+func (b *objectBlobStore) getBlob(ctx context.Context, gameID, kind, hash string) ([]byte, error) {
+	if b.cache != nil {
+		if data, ok := b.cache.readBlob(gameID, kind, hash); ok {
+			return data, nil
+		}
+	}
+	data, err := storage.GetBlob(ctx, b.objects, b.cipher, gameID, kind, hash)
+	if err == nil && b.cache != nil {
+		b.cache.writeBlob(gameID, kind, hash, data)
+	}
+	return data, err
 }
-func (b *s3BlobStore) putBlob(ctx context.Context, gameID, kind, hash string, data []byte) error {
-	return storage.PutBlob(ctx, b.client, b.bucket, gameID, kind, hash, data)
+func (b *objectBlobStore) putBlob(ctx context.Context, gameID, kind, hash string, data []byte) error {
+	if err := storage.PutBlob(ctx, b.objects, b.cipher, gameID, kind, hash, data); err != nil {
+		return err
+	}
+	if b.cache != nil {
+		b.cache.writeBlob(gameID, kind, hash, data)
+	}
+	return nil
 }
-func (b *s3BlobStore) putBlobs(ctx context.Context, gameID string, blobs map[string][]byte, concurrency int, onProgress func(int, int)) error {
-	return storage.PutBlobs(ctx, b.client, b.bucket, gameID, blobs, concurrency, onProgress)
+func (b *objectBlobStore) putBlobs(ctx context.Context, gameID string, blobs map[string][]byte, concurrency int, onBlob storage.BlobTransferFunc) error {
+	return storage.PutBlobs(ctx, b.objects, b.cipher, gameID, blobs, concurrency, onBlob)
 }
-func (b *s3BlobStore) downloadBlobs(ctx context.Context, gameID, saveDir string, blobs map[string]string, concurrency int, onProgress func(int, int)) error {
-	return storage.DownloadBlobs(ctx, b.client, b.bucket, gameID, saveDir, blobs, concurrency, onProgress)
+func (b *objectBlobStore) downloadBlobs(ctx context.Context, gameID, saveDir string, blobs map[string]string, concurrency int, onFile storage.BlobTransferFunc) error {
+	return storage.DownloadBlobs(ctx, b.objects, b.cipher, gameID, saveDir, blobs, concurrency, onFile)
 }
-func (b *s3BlobStore) deleteByPrefix(ctx context.Context, prefix string) error {
-	return storage.DeleteObjectsByPrefix(ctx, b.client, b.bucket, prefix)
+func (b *objectBlobStore) deleteByPrefix(ctx context.Context, prefix string) error {
+	if err := b.objects.DeleteByPrefix(ctx, prefix); err != nil {
+		return err
+	}
+	if gameID, ok := strings.CutPrefix(prefix, "games/"); ok && b.cache != nil {
+		b.cache.removeGame(b.scope, strings.TrimSuffix(gameID, "/"))
+	}
+	return nil
 }
-func (b *s3BlobStore) listGameIDs(ctx context.Context) ([]string, error) {
-	objects, err := storage.ListObjects(ctx, b.client, b.bucket, "games/")
+func (b *objectBlobStore) listGameIDs(ctx context.Context) ([]string, error) {
+	objects, err := b.objects.ListObjects(ctx, "games/")
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +123,21 @@ func (b *s3BlobStore) listGameIDs(ctx context.Context) ([]string, error) {
 	}
 	return ids, nil
 }
+func (b *objectBlobStore) listObjectHashes(ctx context.Context, gameID string) (map[string]struct{}, error) {
+	return storage.ListBlobHashes(ctx, b.objects, gameID)
+}
+func (b *objectBlobStore) writeHeadHistory(ctx context.Context, gameID string, entry storage.HeadHistoryEntry) error {
+	return storage.WriteHeadHistory(ctx, b.objects, gameID, entry)
+}
+func (b *objectBlobStore) readHeadHistory(ctx context.Context, gameID, id string) (*storage.HeadHistoryEntry, error) {
+	return storage.ReadHeadHistory(ctx, b.objects, gameID, id)
+}
+func (b *objectBlobStore) listHeadHistoryIDs(ctx context.Context, gameID string) ([]string, error) {
+	return storage.ListHeadHistoryIDs(ctx, b.objects, gameID)
+}
+func (b *objectBlobStore) deleteHeadHistory(ctx context.Context, gameID, id string) error {
+	return storage.DeleteHeadHistory(ctx, b.objects, gameID, id)
+}
 
 // ContentSyncService はコンテンツアドレッシングによるゲームデータ同期を提供する。
 type ContentSyncService struct {
@@ -98,6 +148,33 @@ type ContentSyncService struct {
 	newBlobStore func(ctx context.Context) (contentBlobStore, error)
 	gameLocks    sync.Map // gameID → *sync.Mutex（同一ゲームの Push/Pull/ResolveConflict/DeleteFromCloud を直列化）
 	offline      atomic.Bool
+	cipherCache  blobCipherCache
+	// googleDrive は保存先が Google ドライブのときのオブジェクトストアの取得元。
+	googleDrive *GoogleDriveService
+	// diskFree は空き容量の取得（テストで差し替える。nil なら diskFreeBytes）。
+	diskFree func(dir string) (uint64, error)
+	// remoteCache はクラウドから取得した HEAD・JSON のキャッシュ。
+	remoteCache *remoteCache
+	// pendingConflicts は自動同期で見つかり、ユーザーの判断を待っているコンフリクト（gameID → 検出時点の内容）。
+	conflictsMu      sync.Mutex
+	pendingConflicts map[string]domain.SyncConflict
+	// events はゲーム1件の Push / Pull の終了を知らせるイベントバス（nil 可）。
+	events *EventBus
+}
+
+// SetEventBus はゲーム1件の Push / Pull（コンフリクト解決を含む）の終了を EventSyncCompleted で知らせる先を設定する。
+// オフラインモードで始めなかった同期は知らせない。
+func (s *ContentSyncService) SetEventBus(bus *EventBus) {
+	s.events = bus
+}
+
+// publishSyncCompleted は同期の結果を EventSyncCompleted で知らせる。
+func (s *ContentSyncService) publishSyncCompleted(gameID, direction string, applied bool, err error) {
+	event := SyncCompletedEvent{GameID: gameID, Direction: direction, Applied: applied && err == nil}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.events.Publish(EventSyncCompleted, event)
 }
 
 // SetOfflineMode はオフラインモードの ON/OFF を切り替える。
@@ -112,6 +189,13 @@ func (s *ContentSyncService) IsOffline() bool {
 	return s.offline.Load()
 }
 
+// SetCredentialKey は使用する認証情報プロファイルを切り替える。
+// リモート HEAD は接続先バケットごとに別物なので、切り替え直後の Status は
+// 新しい接続先との比較になる（pull_needed / conflict になり得る）。
+func (s *ContentSyncService) SetCredentialKey(key string) {
+	s.config.CredentialKey = key
+}
+
 // SetUploadConcurrency はアップロード／ダウンロード並列度を更新する。
 // NewContentSyncService は config を値コピーするため、app.Config だけ書き換えても
 // Push/Pull に反映されない。設定 UI からの変更は必ずここを経由する。
@@ -122,6 +206,12 @@ func (s *ContentSyncService) SetUploadConcurrency(value int) {
 	s.config.S3UploadConcurrency = value
 }
 
+// SetSaveCompression はセーブフォルダを zip 1つにまとめてアップロードするかを更新する。
+// 次回 Push から反映される。Pull は常にスナップショットの形式に従う。
+func (s *ContentSyncService) SetSaveCompression(enabled bool) {
+	s.config.SaveCompression = enabled
+}
+
 // SetS3ForcePathStyle は path-style アドレス指定の有効/無効を更新する。
 func (s *ContentSyncService) SetS3ForcePathStyle(enabled bool) {
 	s.config.S3ForcePathStyle = enabled
@@ -132,6 +222,18 @@ func (s *ContentSyncService) SetS3UseTLS(enabled bool) {
 	s.config.S3UseTLS = enabled
 }
 
+// SetStorageBackend は同期データの保存先を切り替える。保存先ごとに HEAD は別物なので、
+// 切り替え直後の Status は新しい保存先との比較になる（SetCredentialKey と同じ）。
+func (s *ContentSyncService) SetStorageBackend(backend, localDir string) {
+	s.config.StorageBackend = NormalizeStorageBackend(backend)
+	s.config.LocalStorageDir = localDir
+}
+
+// SetGoogleDrive は保存先が Google ドライブのときに使う接続を設定する。
+func (s *ContentSyncService) SetGoogleDrive(drive *GoogleDriveService) {
+	s.googleDrive = drive
+}
+
 // NewContentSyncService は ContentSyncService を生成する。
 func NewContentSyncService(cfg config.Config, store credentials.Store, repo ContentSyncRepository, logger *slog.Logger) *ContentSyncService {
 	svc := &ContentSyncService{
@@ -140,12 +242,13 @@ func NewContentSyncService(cfg config.Config, store credentials.Store, repo Cont
 		repository: repo,
 		logger:     logger,
 	}
+	cacheDir := ""
+	if cfg.AppDataDir != "" {
+		cacheDir = filepath.Join(cfg.AppDataDir, remoteCacheDirName)
+	}
+	svc.remoteCache = newRemoteCache(cacheDir)
 	svc.newBlobStore = func(ctx context.Context) (contentBlobStore, error) {
-		client, s3cfg, err := svc.newClient(ctx)
-		if err != nil {
-			return nil, err
-		}
-		return &s3BlobStore{client: client, bucket: s3cfg.Bucket}, nil
+		return svc.newStorageBlobStore(ctx)
 	}
 	return svc
 }
@@ -161,20 +264,82 @@ func (s *ContentSyncService) lockGame(gameID string) func() {
 	return mu.Unlock
 }
 
-func (s *ContentSyncService) newClient(ctx context.Context) (*s3.Client, storage.S3Config, error) {
-	credential, err := s.store.Load(ctx, "default")
+// newStorageBlobStore は設定中のストレージバックエンド（S3・ローカルフォルダ・Google ドライブ）のブロブストアを作る。
+func (s *ContentSyncService) newStorageBlobStore(ctx context.Context) (*objectBlobStore, error) {
+	switch NormalizeStorageBackend(s.config.StorageBackend) {
+	case StorageBackendLocal:
+		return s.newLocalBlobStore(ctx)
+	case StorageBackendGoogleDrive:
+		return s.newGoogleDriveBlobStore(ctx)
+	default:
+		return s.newS3BlobStore(ctx)
+	}
+}
+
+// newS3BlobStore は現在の認証情報で S3 ブロブストアを作る。
+// 認証情報に暗号化パスフレーズがあれば、バケットの暗号化設定と照合した BlobCipher を持たせる。
+func (s *ContentSyncService) newS3BlobStore(ctx context.Context) (*objectBlobStore, error) {
+	credential, err := s.store.Load(ctx, credentialKeyOf(s.config))
 	if err != nil {
-		return nil, storage.S3Config{}, fmt.Errorf("認証情報取得に失敗: %w", err)
+		return nil, fmt.Errorf("認証情報取得に失敗: %w", err)
 	}
 	if credential == nil {
-		return nil, storage.S3Config{}, fmt.Errorf("認証情報が見つかりません")
+		return nil, fmt.Errorf("認証情報が見つかりません")
 	}
 	cfg := resolveS3Config(s.config, credential)
 	client, err := storage.NewClient(ctx, cfg, *credential)
 	if err != nil {
-		return nil, storage.S3Config{}, fmt.Errorf("S3クライアント作成に失敗: %w", err)
+		return nil, fmt.Errorf("S3クライアント作成に失敗: %w", err)
 	}
-	return client, cfg, nil
+	objects := storage.NewS3ObjectStore(client, cfg.Bucket)
+	blobCipher, err := s.resolveBlobCipher(ctx, objects, cfg.Bucket, credential.EncryptionPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &objectBlobStore{objects: objects, cipher: blobCipher, cache: s.remoteCache, scope: "s3:" + cfg.Endpoint + "/" + cfg.Bucket}, nil
+}
+
+// newLocalBlobStore は LocalStorageDir を同期先にするブロブストアを作る。クラウドの認証情報は不要で、
+// 保存済みの認証情報に暗号化パスフレーズがあればローカルフォルダでも同じように暗号化する。
+func (s *ContentSyncService) newLocalBlobStore(ctx context.Context) (*objectBlobStore, error) {
+	objects, err := storage.NewLocalObjectStore(s.config.LocalStorageDir)
+	if err != nil {
+		return nil, err
+	}
+	blobCipher, err := s.resolveBlobCipher(ctx, objects, "local:"+objects.Root(), s.activePassphrase(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &objectBlobStore{objects: objects, cipher: blobCipher, cache: s.remoteCache, scope: "local:" + objects.Root()}, nil
+}
+
+// newGoogleDriveBlobStore は接続済みの Google ドライブを同期先にするブロブストアを作る。
+// 暗号化パスフレーズはローカルフォルダと同じく保存済みの認証情報のものを使う。
+func (s *ContentSyncService) newGoogleDriveBlobStore(ctx context.Context) (*objectBlobStore, error) {
+	if s.googleDrive == nil {
+		return nil, ErrGoogleDriveNotConnected
+	}
+	objects, err := s.googleDrive.ObjectStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blobCipher, err := s.resolveBlobCipher(ctx, objects, StorageBackendGoogleDrive, s.activePassphrase(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &objectBlobStore{objects: objects, cipher: blobCipher, cache: s.remoteCache, scope: StorageBackendGoogleDrive}, nil
+}
+
+// activePassphrase は使用中の認証情報プロファイルの暗号化パスフレーズを返す。取得できなければ空（平文）。
+func (s *ContentSyncService) activePassphrase(ctx context.Context) string {
+	if s.store == nil {
+		return ""
+	}
+	credential, err := s.store.Load(ctx, credentialKeyOf(s.config))
+	if err != nil || credential == nil {
+		return ""
+	}
+	return credential.EncryptionPassphrase
 }
 
 // contentFingerprint は MetaSnapshot のコンテンツ部分（タイムスタンプ・デバイス名を除く）からハッシュを生成する。
@@ -184,29 +349,15 @@ func contentFingerprint(meta domain.MetaSnapshot) string {
 		G string `json:"g"`
 		S string `json:"s"`
 		V string `json:"v"`
+		// R はルートが無いとき（routes.json が無い、または空の一覧）省略し、ルート同期以前の fingerprint と一致させる。
+		R string `json:"r,omitempty"`
 	}
-	data, _ := json.Marshal(fp{G: meta.GameJSON, S: meta.SessionsJSON, V: meta.Saves})
-	return hashBytes(data)
-}
-
-func (s *ContentSyncService) getOrInitDeviceName(ctx context.Context) (string, error) {
-	name, err := s.repository.GetSetting(ctx, "device_name")
-	if err != nil {
-		return "", err
+	routesHash := meta.RoutesJSON
+	if routesHash == emptyRoutesJSONHash {
+		routesHash = ""
 	}
-	if name != "" {
-		return name, nil
-	}
-	hostname, err := os.Hostname()
-	if err != nil || hostname == "" {
-		hostname = "Unknown Device"
-	}
-	// 保存に失敗しても致命的ではない（次回再取得・再保存される）が、
-	// 書き込みエラーを完全に握り潰さないようログに残す。
-	if err := s.repository.UpsertSetting(ctx, "device_name", hostname); err != nil {
-		s.logger.Warn("device_name の保存に失敗", "error", err)
-	}
-	return hostname, nil
+	data, _ := json.Marshal(fp{G: meta.GameJSON, S: meta.SessionsJSON, V: meta.Saves, R: routesHash})
+	return hashBytes(data)
 }
 
 // buildLocalMeta はゲームの現在のローカル状態から MetaSnapshot を構築する。
@@ -215,7 +366,15 @@ func (s *ContentSyncService) buildLocalMeta(ctx context.Context, game domain.Gam
 	if err != nil {
 		return metaBuildResult{}, err
 	}
-	deviceName, err := s.getOrInitDeviceName(ctx)
+	links, err := s.repository.ListGameLinksByGame(ctx, game.ID)
+	if err != nil {
+		return metaBuildResult{}, err
+	}
+	routes, err := s.repository.ListRoutesByGame(ctx, game.ID)
+	if err != nil {
+		return metaBuildResult{}, err
+	}
+	device, err := s.DeviceIdentity(ctx)
 	if err != nil {
 		return metaBuildResult{}, err
 	}
@@ -229,7 +388,7 @@ func (s *ContentSyncService) buildLocalMeta(ctx context.Context, game domain.Gam
 			s.logger.Warn("画像のハッシュ計算に失敗（imageHash を空として扱う）", "gameId", game.ID, "path", *game.ImagePath, "error", herr)
 		}
 	}
-	saveSnap, err := buildSaveTree(saveFolderPath)
+	saveSnap, err := buildSaveTree(saveFolderPath, newSaveFileFilter(game.SaveIncludePatterns, game.SaveExcludePatterns))
 	if err != nil {
 		return metaBuildResult{}, err
 	}
@@ -240,7 +399,7 @@ func (s *ContentSyncService) buildLocalMeta(ctx context.Context, game domain.Gam
 	savesHash := hashBytes(saveSnapJSON)
 	// Status 経路は contentFingerprint しか参照しないため、サマリ表示用キャッシュは
 	// 埋めない（0 を渡す）。実値は Push 時の pushBuildLocalMeta 側で書き込む。
-	return buildMetaSnapshot(game, sessions, imageHash, savesHash, deviceName, 0, 0)
+	return buildMetaSnapshot(game, sessions, links, routes, imageHash, savesHash, device, 0, 0)
 }
 
 // Status は現在の同期状態を返す。
@@ -324,15 +483,21 @@ func (s *ContentSyncService) Status(ctx context.Context, gameID string) (domain.
 
 // Push はローカルデータをリモートにアップロードする。同一ゲームの同期と直列化される。
 // オフラインモード時は ErrOffline を返す（自動同期は process_monitor 側で握りつぶす）。
-func (s *ContentSyncService) Push(ctx context.Context, gameID string, onProgress ProgressFunc) error {
+func (s *ContentSyncService) Push(ctx context.Context, gameID string, onProgress TransferProgressFunc) error {
 	if s.offline.Load() {
 		return ErrOffline
 	}
 	defer s.lockGame(gameID)()
-	return s.push(ctx, gameID, onProgress, false)
+	err := s.push(ctx, gameID, onProgress, false)
+	s.publishSyncCompleted(gameID, OperationPush, true, err)
+	if err != nil {
+		return err
+	}
+	s.clearPendingConflict(gameID)
+	return nil
 }
 
-func (s *ContentSyncService) push(ctx context.Context, gameID string, onProgress ProgressFunc, force bool) error {
+func (s *ContentSyncService) push(ctx context.Context, gameID string, onProgress TransferProgressFunc, force bool) error {
 	bstore, err := s.newBlobStore(ctx)
 	if err != nil {
 		return err
@@ -401,7 +566,7 @@ func (s *ContentSyncService) pushCheckRemoteHead(ctx context.Context, bstore con
 		return "", fmt.Errorf("リモートにデータがあります。Pull で取り込むか、ローカルを採用するなら同期確認画面から選択してください")
 	}
 	if contentFingerprint(remoteMeta) != localSyncHead {
-		return "", fmt.Errorf("リモートが更新されています。同期状態を確認してコンフリクトを解決してください")
+		return "", ErrRemoteChanged
 	}
 	return remoteHead, nil
 }
@@ -413,15 +578,42 @@ func (s *ContentSyncService) pushBuildLocalMeta(ctx context.Context, gameID stri
 	if err != nil {
 		return metaBuildResult{}, nil, "", nil, "", nil, err
 	}
-	deviceName, err := s.getOrInitDeviceName(ctx)
+	links, err := s.repository.ListGameLinksByGame(ctx, gameID)
+	if err != nil {
+		return metaBuildResult{}, nil, "", nil, "", nil, err
+	}
+	routes, err := s.repository.ListRoutesByGame(ctx, gameID)
+	if err != nil {
+		return metaBuildResult{}, nil, "", nil, "", nil, err
+	}
+	device, err := s.DeviceIdentity(ctx)
 	if err != nil {
 		return metaBuildResult{}, nil, "", nil, "", nil, err
 	}
 
-	saveSnap, saveBlobs, err := buildSaveSnapshot(*game.SaveFolderPath)
+	saveSnap, saveBlobs, err := buildSaveSnapshot(*game.SaveFolderPath, newSaveFileFilter(game.SaveIncludePatterns, game.SaveExcludePatterns))
 	if err != nil {
 		return metaBuildResult{}, nil, "", nil, "", nil, err
 	}
+	// クラウド一覧の表示で別 GET を増やさずにファイル数 / 総サイズを出せるよう、
+	// commit にスナップショット時点のキャッシュとして書き込む。dedup を意識せず
+	// 「ユーザーが見るファイル単位」で合計するため saveSnap.Files を走査する。
+	fileCount := int64(len(saveSnap.Files))
+	var totalSize int64
+	for _, h := range saveSnap.Files {
+		totalSize += int64(len(saveBlobs[h]))
+	}
+	packHash := ""
+	if s.config.SaveCompression && len(saveSnap.Files) > 0 {
+		// 小さなファイルが大量にあるセーブは1オブジェクトずつの往復が支配的になるため、
+		// zip 1つにまとめてアップロードする。
+		var packData []byte
+		packHash, packData, err = storage.BuildSavePack(saveSnap.Files, saveBlobs)
+		if err != nil {
+			return metaBuildResult{}, nil, "", nil, "", nil, err
+		}
+		saveBlobs = map[domain.BlobHash][]byte{packHash: packData}
+	}
 	saveSnapJSON, err := json.Marshal(saveSnap)
 	if err != nil {
 		return metaBuildResult{}, nil, "", nil, "", nil, err
@@ -442,26 +634,38 @@ func (s *ContentSyncService) pushBuildLocalMeta(ctx context.Context, gameID stri
 		}
 	}
 
-	// クラウド一覧の表示で別 GET を増やさずにファイル数 / 総サイズを出せるよう、
-	// commit にスナップショット時点のキャッシュとして書き込む。dedup を意識せず
-	// 「ユーザーが見るファイル単位」で合計するため saveSnap.Files を走査する。
-	fileCount := int64(len(saveSnap.Files))
-	var totalSize int64
-	for _, h := range saveSnap.Files {
-		totalSize += int64(len(saveBlobs[h]))
-	}
-	meta, err := buildMetaSnapshot(*game, sessions, imageHash, savesHash, deviceName, fileCount, totalSize)
+	meta, err := buildMetaSnapshot(*game, sessions, links, routes, imageHash, savesHash, device, fileCount, totalSize)
 	if err != nil {
 		return metaBuildResult{}, nil, "", nil, "", nil, err
 	}
+	if packHash != "" {
+		meta.Snapshot.SavesPack = packHash
+		if meta.SnapshotBytes, err = json.Marshal(meta.Snapshot); err != nil {
+			return metaBuildResult{}, nil, "", nil, "", nil, err
+		}
+	}
 	return meta, saveSnapJSON, savesHash, saveBlobs, imageHash, imageData, nil
 }
 
-// pushUploadBlobs はセーブブロブ・セーブスナップショット・画像・game.json・sessions.json・
-// コミットブロブを HEAD 書き換え前にアップロードする。
-func (s *ContentSyncService) pushUploadBlobs(ctx context.Context, bstore contentBlobStore, gameID string, onProgress ProgressFunc, meta metaBuildResult, saveSnapJSON []byte, savesHash domain.BlobHash, saveBlobs map[string][]byte, imageHash domain.BlobHash, imageData []byte, metaHash domain.BlobHash) error {
+// pushUploadBlobs はセーブブロブ・セーブスナップショット・画像・game.json・セッション（月ごとのログか sessions.json）・
+// コミットブロブを HEAD 書き換え前にアップロードする。月ごとのログは内容が変わった月だけが新しいブロブになり、
+// 既にある月は putBlob が送らずに済ませる。
+func (s *ContentSyncService) pushUploadBlobs(ctx context.Context, bstore contentBlobStore, gameID string, onProgress TransferProgressFunc, meta metaBuildResult, saveSnapJSON []byte, savesHash domain.BlobHash, saveBlobs map[string][]byte, imageHash domain.BlobHash, imageData []byte, metaHash domain.BlobHash) error {
 	// HEAD より先にブロブを置く。途中失敗しても古い HEAD のままなので、中途半端なコミットを公開しない。
-	if err := bstore.putBlobs(ctx, gameID, saveBlobs, s.config.S3UploadConcurrency, onProgress); err != nil {
+	var onBlob storage.BlobTransferFunc
+	if tracker := newTransferTracker(onProgress, len(saveBlobs), sumBlobSizes(saveBlobs)); tracker != nil {
+		names := blobDisplayNames(saveSnapJSON)
+		tracker.start()
+		onBlob = func(hash string, size int64, skipped bool) {
+			name, ok := names[hash]
+			if !ok {
+				// スナップショットに無いハッシュはセーブをまとめた zip パック。
+				name = savePackDisplayName
+			}
+			tracker.fileDone(name, size, skipped)
+		}
+	}
+	if err := bstore.putBlobs(ctx, gameID, saveBlobs, s.config.S3UploadConcurrency, onBlob); err != nil {
 		return err
 	}
 
@@ -476,8 +680,20 @@ func (s *ContentSyncService) pushUploadBlobs(ctx context.Context, bstore content
 	if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, meta.Snapshot.GameJSON, meta.GameJSON); err != nil {
 		return err
 	}
-	if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, meta.Snapshot.SessionsJSON, meta.SessionsJSON); err != nil {
-		return err
+	if len(meta.SessionChunks) == 0 {
+		if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, meta.Snapshot.SessionsJSON, meta.SessionsJSON); err != nil {
+			return err
+		}
+	}
+	for hash, data := range meta.SessionChunks {
+		if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, hash, data); err != nil {
+			return err
+		}
+	}
+	if meta.Snapshot.RoutesJSON != "" {
+		if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, meta.Snapshot.RoutesJSON, meta.RoutesJSON); err != nil {
+			return err
+		}
 	}
 	if err := bstore.putBlob(ctx, gameID, storage.BlobKindCommit, metaHash, meta.SnapshotBytes); err != nil {
 		return err
@@ -490,14 +706,15 @@ func (s *ContentSyncService) pushFinalizeHead(ctx context.Context, bstore conten
 	// HEAD 書き換え直前に再度リモート HEAD を確認し、push 開始時から変化していれば中断する。
 	// S3 に CAS が無いため完全な排他はできないが、アップロード中に別デバイスが push した場合の
 	// ロストアップデートの窓を大幅に縮小する（force 時はユーザーが上書きを選択済みのため省略）。
-	if !force {
-		currentHead, err := bstore.readHEAD(ctx, gameID)
-		if err != nil {
-			return err
-		}
-		if currentHead != expectedHead {
-			return fmt.Errorf("リモートが更新されています。同期状態を確認してコンフリクトを解決してください")
-		}
+	currentHead, err := bstore.readHEAD(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if !force && currentHead != expectedHead {
+		return ErrRemoteChanged
+	}
+	if err := s.recordHeadHistory(ctx, bstore, gameID, currentHead, metaHash); err != nil {
+		return err
 	}
 	if err := bstore.writeHEAD(ctx, gameID, metaHash); err != nil {
 		return err
@@ -512,12 +729,17 @@ func (s *ContentSyncService) pushFinalizeHead(ctx context.Context, bstore conten
 // Pull はリモートデータをローカルに適用する。同一ゲームの同期と直列化される。
 // 詳細な挙動（deleteUntracked による未追跡ファイル削除確認）は内部の pull を参照。
 // オフラインモード時は ErrOffline を返す。
-func (s *ContentSyncService) Pull(ctx context.Context, gameID string, onProgress ProgressFunc, deleteUntracked bool) (domain.PullResult, error) {
+func (s *ContentSyncService) Pull(ctx context.Context, gameID string, onProgress TransferProgressFunc, deleteUntracked bool) (domain.PullResult, error) {
 	if s.offline.Load() {
 		return domain.PullResult{}, ErrOffline
 	}
 	defer s.lockGame(gameID)()
-	return s.pull(ctx, gameID, onProgress, deleteUntracked)
+	res, err := s.pull(ctx, gameID, onProgress, deleteUntracked)
+	s.publishSyncCompleted(gameID, OperationPull, res.Applied, err)
+	if err == nil && res.Applied {
+		s.clearPendingConflict(gameID)
+	}
+	return res, err
 }
 
 // pull はリモートデータをローカルに適用する。
@@ -526,58 +748,23 @@ func (s *ContentSyncService) Pull(ctx context.Context, gameID string, onProgress
 // （untracked）を削除する必要があると分かった時点で、ローカルに一切変更を加えずに
 // PullResult{Applied:false, UntrackedDeletes:...} を返す。呼び出し側でユーザーに
 // 確認を取り、承認後に deleteUntracked=true で再実行する。
-func (s *ContentSyncService) pull(ctx context.Context, gameID string, onProgress ProgressFunc, deleteUntracked bool) (domain.PullResult, error) {
+func (s *ContentSyncService) pull(ctx context.Context, gameID string, onProgress TransferProgressFunc, deleteUntracked bool) (domain.PullResult, error) {
 	bstore, err := s.newBlobStore(ctx)
 	if err != nil {
 		return domain.PullResult{}, err
 	}
 
-	remoteHead, err := bstore.readHEAD(ctx, gameID)
+	// ローカルと同じ内容の月はセッションログをダウンロードせず、DB も書き換えない。
+	localSessions, localChunks, err := s.localSessionChunks(ctx, gameID)
 	if err != nil {
 		return domain.PullResult{}, err
 	}
-	if remoteHead == "" {
-		return domain.PullResult{}, fmt.Errorf("リモートにデータがありません")
-	}
-
-	metaBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindCommit, remoteHead)
+	remote, err := s.fetchRemoteCommit(ctx, bstore, gameID, localChunks)
 	if err != nil {
 		return domain.PullResult{}, err
 	}
-	var meta domain.MetaSnapshot
-	if err := json.Unmarshal(metaBytes, &meta); err != nil {
-		return domain.PullResult{}, err
-	}
-
-	saveSnapBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindTree, meta.Saves)
-	if err != nil {
-		return domain.PullResult{}, err
-	}
-	var saveSnap domain.SaveSnapshot
-	if err := json.Unmarshal(saveSnapBytes, &saveSnap); err != nil {
-		return domain.PullResult{}, err
-	}
-
-	gameJSONBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindMeta, meta.GameJSON)
-	if err != nil {
-		return domain.PullResult{}, err
-	}
-	var cloudG cloudGame
-	if err := json.Unmarshal(gameJSONBytes, &cloudG); err != nil {
-		return domain.PullResult{}, err
-	}
-	if cloudG.ID != gameID {
-		return domain.PullResult{}, fmt.Errorf("リモートのゲームIDが一致しません: %s", cloudG.ID)
-	}
-
-	sessionsJSONBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindMeta, meta.SessionsJSON)
-	if err != nil {
-		return domain.PullResult{}, err
-	}
-	var cloudSessions []cloudSession
-	if err := json.Unmarshal(sessionsJSONBytes, &cloudSessions); err != nil {
-		return domain.PullResult{}, err
-	}
+	meta, saveSnapBytes, saveSnap, cloudG := remote.Meta, remote.SaveSnapBytes, remote.SaveSnap, remote.Game
+	sessions := pulledSessions(gameID, meta, localSessions, localChunks, remote.Sessions)
 
 	// exe/save/image はマシン固有。クラウド game.json で上書きしないよう先に取る。
 	localGame, err := s.repository.GetGameByID(ctx, gameID)
@@ -596,7 +783,7 @@ func (s *ContentSyncService) pull(ctx context.Context, gameID string, onProgress
 	// ── 削除計画を先に立て、未追跡ファイルの削除が必要なら変更前に確認へ回す ──
 	// ローカルに副作用を与える前（画像・セーブのダウンロード前）に判定することで、
 	// 「確認待ち」を返したときはディスクが一切変更されていないことを保証する。
-	trackedDeletes, untrackedDeletes, err := s.pullPlanDeletions(ctx, gameID, saveFolderPath, saveSnap)
+	trackedDeletes, untrackedDeletes, err := s.pullPlanDeletions(ctx, gameID, saveFolderPath, newSaveFileFilter(cloudG.SaveIncludePatterns, cloudG.SaveExcludePatterns), saveSnap)
 	if err != nil {
 		return domain.PullResult{}, err
 	}
@@ -610,16 +797,92 @@ func (s *ContentSyncService) pull(ctx context.Context, gameID string, onProgress
 		return domain.PullResult{}, err
 	}
 
-	if err := s.pullDownloadSaves(ctx, bstore, gameID, onProgress, saveFolderPath, saveSnap, trackedDeletes, untrackedDeletes); err != nil {
+	if err := s.pullDownloadSaves(ctx, bstore, gameID, onProgress, saveFolderPath, saveSnap, meta.SavesPack, trackedDeletes, untrackedDeletes); err != nil {
 		return domain.PullResult{}, err
 	}
 
-	return s.pullApplyToDB(ctx, gameID, cloudG, cloudSessions, imagePath, exePath, saveFolderPath, localGame, meta, saveSnapBytes)
+	return s.pullApplyToDB(ctx, gameID, cloudG, localSessions, sessions, remote.Routes, imagePath, exePath, saveFolderPath, localGame, meta, saveSnapBytes)
+}
+
+// remoteCommit はリモート HEAD が指すコミットと、そこから辿れるセーブスナップショット・game.json・sessions.json・routes.json。
+// Routes は routes.json を持たない commit では nil。Sessions は fetchCommit の haveChunks で読み飛ばした月を含まない。
+type remoteCommit struct {
+	Meta          domain.MetaSnapshot
+	SaveSnapBytes []byte
+	SaveSnap      domain.SaveSnapshot
+	Game          cloudGame
+	Sessions      []cloudSession
+	Routes        []cloudRoute
+}
+
+// fetchRemoteCommit はリモート HEAD のコミットを読み込む。リモートにデータが無ければエラーを返す。
+// haveChunks は loadCloudSessions に渡す、ローカルにある月ごとのセッションログのハッシュ（nil なら全セッションを読む）。
+func (s *ContentSyncService) fetchRemoteCommit(ctx context.Context, bstore contentBlobStore, gameID string, haveChunks map[string]domain.BlobHash) (remoteCommit, error) {
+	remoteHead, err := bstore.readHEAD(ctx, gameID)
+	if err != nil {
+		return remoteCommit{}, err
+	}
+	if remoteHead == "" {
+		return remoteCommit{}, fmt.Errorf("リモートにデータがありません")
+	}
+	return s.fetchCommit(ctx, bstore, gameID, remoteHead, haveChunks)
+}
+
+// fetchCommit は指定したコミットとそのセーブツリー・game.json・セッション・routes.json を読み込む。
+// haveChunks にローカルと同じハッシュがある月のセッションは読まない（Sessions にも含めない）。
+func (s *ContentSyncService) fetchCommit(ctx context.Context, bstore contentBlobStore, gameID, head string, haveChunks map[string]domain.BlobHash) (remoteCommit, error) {
+	metaBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindCommit, head)
+	if err != nil {
+		return remoteCommit{}, err
+	}
+	var meta domain.MetaSnapshot
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		return remoteCommit{}, err
+	}
+
+	saveSnapBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindTree, meta.Saves)
+	if err != nil {
+		return remoteCommit{}, err
+	}
+	var saveSnap domain.SaveSnapshot
+	if err := json.Unmarshal(saveSnapBytes, &saveSnap); err != nil {
+		return remoteCommit{}, err
+	}
+
+	gameJSONBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindMeta, meta.GameJSON)
+	if err != nil {
+		return remoteCommit{}, err
+	}
+	var cloudG cloudGame
+	if err := json.Unmarshal(gameJSONBytes, &cloudG); err != nil {
+		return remoteCommit{}, err
+	}
+	if cloudG.ID != gameID {
+		return remoteCommit{}, fmt.Errorf("リモートのゲームIDが一致しません: %s", cloudG.ID)
+	}
+
+	cloudSessions, err := loadCloudSessions(ctx, bstore, gameID, meta, haveChunks)
+	if err != nil {
+		return remoteCommit{}, err
+	}
+
+	var cloudRoutes []cloudRoute
+	if meta.RoutesJSON != "" {
+		routesJSONBytes, err := bstore.getBlob(ctx, gameID, storage.BlobKindMeta, meta.RoutesJSON)
+		if err != nil {
+			return remoteCommit{}, err
+		}
+		if err := json.Unmarshal(routesJSONBytes, &cloudRoutes); err != nil {
+			return remoteCommit{}, err
+		}
+	}
+	return remoteCommit{Meta: meta, SaveSnapBytes: saveSnapBytes, SaveSnap: saveSnap, Game: cloudG, Sessions: cloudSessions, Routes: cloudRoutes}, nil
 }
 
 // pullPlanDeletions はリモートのセーブスナップショットとローカルの base tree を突き合わせ、
 // 削除すべき tracked / untracked ファイルの一覧を返す。ディスクには一切変更を加えない。
-func (s *ContentSyncService) pullPlanDeletions(ctx context.Context, gameID string, saveFolderPath *string, saveSnap domain.SaveSnapshot) ([]string, []string, error) {
+// filter は取り込み後に有効になる同期対象のパターンで、対象外のファイルは削除しない。
+func (s *ContentSyncService) pullPlanDeletions(ctx context.Context, gameID string, saveFolderPath *string, filter saveFileFilter, saveSnap domain.SaveSnapshot) ([]string, []string, error) {
 	var trackedDeletes, untrackedDeletes []string
 	if saveFolderPath != nil && *saveFolderPath != "" {
 		if _, statErr := os.Stat(*saveFolderPath); statErr == nil {
@@ -632,7 +895,7 @@ func (s *ContentSyncService) pullPlanDeletions(ctx context.Context, gameID strin
 				return nil, nil, terr
 			}
 			var err error
-			trackedDeletes, untrackedDeletes, err = planDeletions(*saveFolderPath, saveSnap, baseTree)
+			trackedDeletes, untrackedDeletes, err = planDeletions(*saveFolderPath, filter, saveSnap, baseTree)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -675,7 +938,8 @@ func (s *ContentSyncService) pullDownloadImage(ctx context.Context, bstore conte
 }
 
 // pullDownloadSaves はセーブファイルの差分を並列ダウンロードし、計画済みの削除を適用する。
-func (s *ContentSyncService) pullDownloadSaves(ctx context.Context, bstore contentBlobStore, gameID string, onProgress ProgressFunc, saveFolderPath *string, saveSnap domain.SaveSnapshot, trackedDeletes, untrackedDeletes []string) error {
+// savesPack が空でなければ、個別ブロブの代わりにその zip ブロブを1回取得して差分だけ展開する。
+func (s *ContentSyncService) pullDownloadSaves(ctx context.Context, bstore contentBlobStore, gameID string, onProgress TransferProgressFunc, saveFolderPath *string, saveSnap domain.SaveSnapshot, savesPack domain.BlobHash, trackedDeletes, untrackedDeletes []string) error {
 	if saveFolderPath != nil && *saveFolderPath != "" {
 		saveDir := *saveFolderPath
 		total := len(saveSnap.Files)
@@ -696,15 +960,24 @@ func (s *ContentSyncService) pullDownloadSaves(ctx context.Context, bstore conte
 			}
 		}
 
-		var wrappedProgress func(int, int)
-		if onProgress != nil {
-			alreadyDone := total - len(needsDownload)
-			onProgress(alreadyDone, total)
-			wrappedProgress = func(downloaded, _ int) {
-				onProgress(alreadyDone+downloaded, total)
-			}
+		// ダウンロード前にはサイズが分からないため、総バイト数は不明（0）としてファイル数で見積もる。
+		var wrappedProgress storage.BlobTransferFunc
+		if tracker := newTransferTracker(onProgress, total, 0); tracker != nil {
+			tracker.skip(total - len(needsDownload))
+			tracker.start()
+			wrappedProgress = tracker.fileDone
 		}
-		if err := bstore.downloadBlobs(ctx, gameID, saveDir, needsDownload, s.config.S3UploadConcurrency, wrappedProgress); err != nil {
+		if savesPack != "" {
+			if len(needsDownload) > 0 {
+				packData, err := bstore.getBlob(ctx, gameID, storage.BlobKindObject, savesPack)
+				if err != nil {
+					return err
+				}
+				if err := storage.ExtractSavePack(packData, saveDir, needsDownload, wrappedProgress); err != nil {
+					return err
+				}
+			}
+		} else if err := bstore.downloadBlobs(ctx, gameID, saveDir, needsDownload, s.config.S3UploadConcurrency, wrappedProgress); err != nil {
 			return err
 		}
 
@@ -725,25 +998,34 @@ func (s *ContentSyncService) pullDownloadSaves(ctx context.Context, bstore conte
 	return nil
 }
 
-// pullApplyToDB はリモートのゲーム情報・セッション・同期基準・base tree を単一トランザクションで反映する。
+// pullApplyToDB はリモートのゲーム情報・セッション・リンク・同期基準・base tree を単一トランザクションで反映する。
 // localGame はマシン固有フィールド（LocalSaveHash / LocalSaveHashUpdatedAt 等）の引き継ぎに使う。
-func (s *ContentSyncService) pullApplyToDB(ctx context.Context, gameID string, cloudG cloudGame, cloudSessions []cloudSession, imagePath *string, exePath string, saveFolderPath *string, localGame *domain.Game, meta domain.MetaSnapshot, saveSnapBytes []byte) (domain.PullResult, error) {
+// セッションは localSessions との差分（変わったセッションの書き込みと、無くなったセッションの削除）だけを反映する。
+func (s *ContentSyncService) pullApplyToDB(ctx context.Context, gameID string, cloudG cloudGame, localSessions, sessions []domain.PlaySession, cloudRoutes []cloudRoute, imagePath *string, exePath string, saveFolderPath *string, localGame *domain.Game, meta domain.MetaSnapshot, saveSnapBytes []byte) (domain.PullResult, error) {
 	// 単一トランザクションにまとめる理由: 部分失敗による DB 不整合と、
-	// 同期対象外 Route 参照による FK 違反を防ぐため。
+	// ローカルに無い Route 参照による FK 違反を防ぐため。
 	updatedGame := domain.Game{
-		ID:             cloudG.ID,
-		Title:          cloudG.Title,
-		Publisher:      cloudG.Publisher,
-		ImagePath:      imagePath,
-		ExePath:        exePath,
-		SaveFolderPath: saveFolderPath,
-		PlayStatus:     cloudG.PlayStatus,
-		TotalPlayTime:  cloudG.TotalPlayTime,
-		LastPlayed:     cloudG.LastPlayed,
-		ClearedAt:      cloudG.ClearedAt,
-		CurrentRouteID: cloudG.CurrentRouteID,
-		CreatedAt:      cloudG.CreatedAt,
-		UpdatedAt:      cloudG.UpdatedAt,
+		ID:                  cloudG.ID,
+		Title:               cloudG.Title,
+		Publisher:           cloudG.Publisher,
+		ImagePath:           imagePath,
+		ExePath:             exePath,
+		SaveFolderPath:      saveFolderPath,
+		PlayStatus:          cloudG.PlayStatus,
+		TotalPlayTime:       cloudG.TotalPlayTime,
+		LastPlayed:          cloudG.LastPlayed,
+		ClearedAt:           cloudG.ClearedAt,
+		CurrentRouteID:      cloudG.CurrentRouteID,
+		CreatedAt:           cloudG.CreatedAt,
+		UpdatedAt:           cloudG.UpdatedAt,
+		Description:         cloudG.Description,
+		ReleaseDate:         cloudG.ReleaseDate,
+		Rating:              cloudG.Rating,
+		Genres:              cloudG.Genres,
+		CustomStatus:        cloudG.CustomStatus,
+		IsFavorite:          cloudG.IsFavorite,
+		SaveIncludePatterns: cloudG.SaveIncludePatterns,
+		SaveExcludePatterns: cloudG.SaveExcludePatterns,
 	}
 	// マシン固有フィールド（process_monitor.saveSession が書き込む LocalSaveHash 等）は
 	// ApplyPullResult が ON CONFLICT DO UPDATE で excluded.* を書くため、ここで明示的に
@@ -752,20 +1034,14 @@ func (s *ContentSyncService) pullApplyToDB(ctx context.Context, gameID string, c
 		updatedGame.LocalSaveHash = localGame.LocalSaveHash
 		updatedGame.LocalSaveHashUpdatedAt = localGame.LocalSaveHashUpdatedAt
 	}
-	sessions := make([]domain.PlaySession, 0, len(cloudSessions))
-	for _, cs := range cloudSessions {
-		sessions = append(sessions, domain.PlaySession{
-			ID:          cs.ID,
-			GameID:      gameID,
-			PlayedAt:    cs.PlayedAt,
-			Duration:    cs.Duration,
-			SessionName: cs.SessionName,
-			RouteID:     cs.RouteID,
-			UpdatedAt:   cs.UpdatedAt,
-		})
+	upserts, deletes, err := diffSessions(localSessions, sessions)
+	if err != nil {
+		return domain.PullResult{}, err
 	}
 	// ApplyPullResult に saveSnap を渡して base tree も更新する。残さないと次回 Pull が untracked 誤判定する。
-	if err := s.repository.ApplyPullResult(ctx, updatedGame, sessions, contentFingerprint(meta), string(saveSnapBytes)); err != nil {
+	links := fromCloudGameLinks(gameID, cloudG.Links)
+	routes := fromCloudRoutes(gameID, cloudRoutes)
+	if err := s.repository.ApplyPullResult(ctx, updatedGame, upserts, deletes, links, routes, contentFingerprint(meta), string(saveSnapBytes)); err != nil {
 		return domain.PullResult{}, err
 	}
 	return domain.PullResult{Applied: true}, nil
@@ -780,12 +1056,20 @@ func (s *ContentSyncService) ResolveConflict(ctx context.Context, gameID string,
 	}
 	defer s.lockGame(gameID)()
 	if useLocal {
-		if err := s.push(ctx, gameID, nil, true); err != nil {
+		err := s.push(ctx, gameID, nil, true)
+		s.publishSyncCompleted(gameID, OperationPush, true, err)
+		if err != nil {
 			return domain.PullResult{}, err
 		}
+		s.clearPendingConflict(gameID)
 		return domain.PullResult{Applied: true}, nil
 	}
-	return s.pull(ctx, gameID, nil, deleteUntracked)
+	res, err := s.pull(ctx, gameID, nil, deleteUntracked)
+	s.publishSyncCompleted(gameID, OperationPull, res.Applied, err)
+	if err == nil && res.Applied {
+		s.clearPendingConflict(gameID)
+	}
+	return res, err
 }
 
 // DeleteFromCloud はゲームのリモートデータを削除し、ローカルの同期基準もクリアする。同一ゲームの同期と直列化される。
@@ -815,6 +1099,15 @@ func (s *ContentSyncService) DeleteFromCloud(ctx context.Context, gameID string)
 	return nil
 }
 
+// ForgetGameCache はローカルで削除したゲームについて、クラウドから取得した HEAD・JSON のキャッシュを消す。
+// クラウド上のデータは残るため、クラウドから取り込み直したときは改めて取得する。
+func (s *ContentSyncService) ForgetGameCache(gameID string) {
+	if s.remoteCache == nil {
+		return
+	}
+	s.remoteCache.forgetGame(strings.TrimSpace(gameID))
+}
+
 // fanOutGames は gameIDs をまたいで fn を最大 concurrency 並列で実行し、
 // nil でない結果を gameIDs の順序で集めて返す。concurrency<=0 のときは既定値 6 を使う。
 func fanOutGames[T any](gameIDs []string, concurrency int, fn func(gameID string) *T) []T {
@@ -885,11 +1178,10 @@ type CloudGameView struct {
 // GetCloudGameView は1ゲームの最新コミットから論理セーブファイル一覧を復元する。
 // HEAD 未設定や解析失敗時は (nil, nil)（=クラウドデータ無し扱い）を返す。エラーは取得失敗時のみ返す。
 func (s *ContentSyncService) GetCloudGameView(ctx context.Context, gameID string) (*CloudGameView, error) {
-	client, cfg, err := s.newClient(ctx)
+	bstore, err := s.newStorageBlobStore(ctx)
 	if err != nil {
 		return nil, err
 	}
-	bstore := &s3BlobStore{client: client, bucket: cfg.Bucket}
 	return s.buildCloudGameView(ctx, bstore, gameID)
 }
 
@@ -932,7 +1224,7 @@ func (s *ContentSyncService) loadCloudCommit(ctx context.Context, bstore content
 
 // buildCloudGameView は与えられた blob store を使って1ゲームの論理ビューを復元する。
 // サイズ解決のため bstore は内部に S3 クライアントとバケットを保持している必要がある。
-func (s *ContentSyncService) buildCloudGameView(ctx context.Context, bstore *s3BlobStore, gameID string) (*CloudGameView, error) {
+func (s *ContentSyncService) buildCloudGameView(ctx context.Context, bstore *objectBlobStore, gameID string) (*CloudGameView, error) {
 	meta, title, err := s.loadCloudCommit(ctx, bstore, gameID)
 	if err != nil {
 		return nil, err
@@ -957,7 +1249,7 @@ func (s *ContentSyncService) buildCloudGameView(ctx context.Context, bstore *s3B
 	sizeMap := make(map[string]int64)
 	if len(saveSnap.Files) > 0 {
 		prefix := fmt.Sprintf("games/%s/%s/", gameID, storage.BlobKindObject)
-		objects, oerr := storage.ListObjects(ctx, bstore.client, bstore.bucket, prefix)
+		objects, oerr := bstore.objects.ListObjects(ctx, prefix)
 		if oerr != nil {
 			return nil, oerr
 		}
@@ -977,6 +1269,10 @@ func (s *ContentSyncService) buildCloudGameView(ctx context.Context, bstore *s3B
 		totalSize += size
 		files = append(files, CloudLogicalFile{RelPath: relPath, Size: size})
 	}
+	if meta.SavesPack != "" {
+		// パック保存ではファイル単位のオブジェクトが無いため、クラウド上の実サイズ（zip）を合計とする。
+		totalSize = sizeMap[meta.SavesPack]
+	}
 	sort.Slice(files, func(i, j int) bool { return files[i].RelPath < files[j].RelPath })
 
 	return &CloudGameView{
@@ -991,11 +1287,10 @@ func (s *ContentSyncService) buildCloudGameView(ctx context.Context, bstore *s3B
 // ListCloudGameViews は全ゲームの論理ビューを返す（Title 昇順）。
 // 個別ゲームの取得に失敗した場合は警告ログを出してスキップする。
 func (s *ContentSyncService) ListCloudGameViews(ctx context.Context) ([]CloudGameView, error) {
-	client, cfg, err := s.newClient(ctx)
+	bstore, err := s.newStorageBlobStore(ctx)
 	if err != nil {
 		return nil, err
 	}
-	bstore := &s3BlobStore{client: client, bucket: cfg.Bucket}
 
 	gameIDs, err := bstore.listGameIDs(ctx)
 	if err != nil {
@@ -1030,7 +1325,7 @@ type CloudGameSummary struct {
 
 // buildCloudGameSummary は HEAD→commit→game.json のみを読み取り、軽量サマリを復元する。
 // buildCloudGameView と異なりセーブツリーの解析・objects の列挙は行わないため高速。
-func (s *ContentSyncService) buildCloudGameSummary(ctx context.Context, bstore *s3BlobStore, gameID string) (*CloudGameSummary, error) {
+func (s *ContentSyncService) buildCloudGameSummary(ctx context.Context, bstore *objectBlobStore, gameID string) (*CloudGameSummary, error) {
 	meta, title, err := s.loadCloudCommit(ctx, bstore, gameID)
 	if err != nil {
 		return nil, err
@@ -1050,11 +1345,10 @@ func (s *ContentSyncService) buildCloudGameSummary(ctx context.Context, bstore *
 // ListCloudGameSummaries は全ゲームの軽量サマリ（Title 昇順）を返す。
 // ファイル数・サイズは含まず、各ゲームの詳細は GetCloudGameView で個別に遅延取得する。
 func (s *ContentSyncService) ListCloudGameSummaries(ctx context.Context) ([]CloudGameSummary, error) {
-	client, cfg, err := s.newClient(ctx)
+	bstore, err := s.newStorageBlobStore(ctx)
 	if err != nil {
 		return nil, err
 	}
-	bstore := &s3BlobStore{client: client, bucket: cfg.Bucket}
 
 	gameIDs, err := bstore.listGameIDs(ctx)
 	if err != nil {
@@ -1103,16 +1397,27 @@ func (s *ContentSyncService) loadCloudGameInfo(ctx context.Context, bstore conte
 		return nil
 	}
 	return &CloudGameInfo{
-		ID:             cg.ID,
-		Title:          cg.Title,
-		Publisher:      cg.Publisher,
-		ImageHash:      cg.ImageHash,
-		PlayStatus:     cg.PlayStatus,
-		TotalPlayTime:  cg.TotalPlayTime,
-		LastPlayed:     cg.LastPlayed,
-		ClearedAt:      cg.ClearedAt,
-		CurrentRouteID: cg.CurrentRouteID,
-		CreatedAt:      cg.CreatedAt,
-		UpdatedAt:      cg.UpdatedAt,
+		ID:                  cg.ID,
+		Title:               cg.Title,
+		Publisher:           cg.Publisher,
+		ImageHash:           cg.ImageHash,
+		PlayStatus:          cg.PlayStatus,
+		TotalPlayTime:       cg.TotalPlayTime,
+		LastPlayed:          cg.LastPlayed,
+		ClearedAt:           cg.ClearedAt,
+		CurrentRouteID:      cg.CurrentRouteID,
+		CreatedAt:           cg.CreatedAt,
+		UpdatedAt:           cg.UpdatedAt,
+		Links:               fromCloudGameLinks(cg.ID, cg.Links),
+		Description:         cg.Description,
+		ReleaseDate:         cg.ReleaseDate,
+		Rating:              cg.Rating,
+		Genres:              cg.Genres,
+		CustomStatus:        cg.CustomStatus,
+		IsFavorite:          cg.IsFavorite,
+		SaveIncludePatterns: cg.SaveIncludePatterns,
+		SaveExcludePatterns: cg.SaveExcludePatterns,
+		LastModifiedBy:      LastModifiedBy{DeviceID: meta.DeviceID, DeviceName: meta.DeviceName},
+		LastModifiedAt:      meta.CreatedAt,
 	}
 }
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -24,6 +26,8 @@ type fakeContentSyncRepository struct {
 
 	game     *domain.Game
 	sessions []domain.PlaySession
+	links    []domain.GameLink
+	routes   []domain.Route
 	settings map[string]string
 	saveTree string
 
@@ -33,6 +37,10 @@ type fakeContentSyncRepository struct {
 	upsertedGame     *domain.Game
 	deletedSessions  bool
 	upsertedSessions []domain.PlaySession
+	// deletedSessionIDs は ApplyPullResult で削除を指示されたセッション。
+	deletedSessionIDs []string
+	appliedLinks      []domain.GameLink
+	appliedRoutes     []domain.Route
 
 	// エラー注入
 	getGameErr error
@@ -55,10 +63,31 @@ func (r *fakeContentSyncRepository) GetGameByID(_ context.Context, _ string) (*d
 	return r.game, nil
 }
 
+func (r *fakeContentSyncRepository) ListGames(_ context.Context, _ string, _ domain.PlayStatus, _ string, _ string) ([]domain.Game, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.game == nil {
+		return nil, nil
+	}
+	return []domain.Game{*r.game}, nil
+}
+
 func (r *fakeContentSyncRepository) ListPlaySessionsByGame(_ context.Context, _ string) ([]domain.PlaySession, error) {
 	return r.sessions, nil
 }
 
+func (r *fakeContentSyncRepository) ListGameLinksByGame(_ context.Context, _ string) ([]domain.GameLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.links, nil
+}
+
+func (r *fakeContentSyncRepository) ListRoutesByGame(_ context.Context, _ string) ([]domain.Route, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.routes, nil
+}
+
 func (r *fakeContentSyncRepository) SetLocalSyncHead(_ context.Context, _ string, hash string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -83,6 +112,16 @@ func (r *fakeContentSyncRepository) SetLocalSaveTree(_ context.Context, _ string
 	return nil
 }
 
+func (r *fakeContentSyncRepository) SetLocalSaveHash(_ context.Context, _ string, hash string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.game != nil {
+		r.game.LocalSaveHash = &hash
+		r.game.LocalSaveHashUpdatedAt = &updatedAt
+	}
+	return nil
+}
+
 func (r *fakeContentSyncRepository) UpsertGameSync(_ context.Context, game domain.Game) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -108,13 +147,23 @@ func (r *fakeContentSyncRepository) ApplyPullResult(
 	_ context.Context,
 	game domain.Game,
 	sessions []domain.PlaySession,
+	deleteSessionIDs []string,
+	links []domain.GameLink,
+	routes []domain.Route,
 	syncHead, saveTree string,
 ) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.upsertedGame = &game
-	r.deletedSessions = true
+	r.appliedRoutes = routes
+	// routes が nil（ルート同期以前の commit）ならローカルのルートを残す。
+	if routes != nil {
+		r.routes = routes
+	}
 	r.upsertedSessions = append([]domain.PlaySession{}, sessions...)
+	r.deletedSessionIDs = append([]string{}, deleteSessionIDs...)
+	r.sessions = applySessionDiff(r.sessions, sessions, deleteSessionIDs)
+	r.appliedLinks = append([]domain.GameLink{}, links...)
 	r.localSyncHeadSet = syncHead
 	if r.game != nil {
 		r.game.LocalSyncHead = &syncHead
@@ -124,6 +173,24 @@ func (r *fakeContentSyncRepository) ApplyPullResult(
 	return nil
 }
 
+// applySessionDiff は ApplyPullResult と同じく、ID で削除・上書きしたセッション一覧を返す。
+func applySessionDiff(current, upserts []domain.PlaySession, deleteIDs []string) []domain.PlaySession {
+	drop := make(map[string]struct{}, len(deleteIDs)+len(upserts))
+	for _, id := range deleteIDs {
+		drop[id] = struct{}{}
+	}
+	for _, session := range upserts {
+		drop[session.ID] = struct{}{}
+	}
+	result := make([]domain.PlaySession, 0, len(current)+len(upserts))
+	for _, session := range current {
+		if _, ok := drop[session.ID]; !ok {
+			result = append(result, session)
+		}
+	}
+	return append(result, upserts...)
+}
+
 func (r *fakeContentSyncRepository) GetSetting(_ context.Context, key string) (string, error) {
 	return r.settings[key], nil
 }
@@ -141,6 +208,8 @@ type fakeBlobStore struct {
 	blobs map[string][]byte // キー: "gameID/hash"
 	heads map[string]string // gameID → metaHash
 
+	history map[string]map[string]storage.HeadHistoryEntry // gameID → id → entry
+
 	// 記録された呼び出し
 	downloadedBlobs []map[string]string // 各呼び出しの blobs 引数
 	deletedPrefixes []string
@@ -152,8 +221,9 @@ type fakeBlobStore struct {
 
 func newFakeBlobStore() *fakeBlobStore {
 	return &fakeBlobStore{
-		blobs: make(map[string][]byte),
-		heads: make(map[string]string),
+		blobs:   make(map[string][]byte),
+		heads:   make(map[string]string),
+		history: make(map[string]map[string]storage.HeadHistoryEntry),
 	}
 }
 
@@ -191,25 +261,22 @@ func (f *fakeBlobStore) putBlob(_ context.Context, gameID, kind, hash string, da
 	return nil
 }
 
-func (f *fakeBlobStore) putBlobs(_ context.Context, gameID string, blobs map[string][]byte, _ int, onProgress func(int, int)) error {
+func (f *fakeBlobStore) putBlobs(_ context.Context, gameID string, blobs map[string][]byte, _ int, onBlob storage.BlobTransferFunc) error {
 	if f.onPutBlobs != nil {
 		f.onPutBlobs()
 	}
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	total := len(blobs)
-	done := 0
 	for hash, data := range blobs {
 		f.blobs[f.blobKey(gameID, storage.BlobKindObject, hash)] = data
-		done++
-		if onProgress != nil {
-			onProgress(done, total)
+		if onBlob != nil {
+			onBlob(hash, int64(len(data)), false)
 		}
 	}
 	return nil
 }
 
-func (f *fakeBlobStore) downloadBlobs(_ context.Context, gameID, saveDir string, blobs map[string]string, _ int, onProgress func(int, int)) error {
+func (f *fakeBlobStore) downloadBlobs(_ context.Context, gameID, saveDir string, blobs map[string]string, _ int, onFile storage.BlobTransferFunc) error {
 	// 呼び出しを記録する
 	snapshot := make(map[string]string, len(blobs))
 	for k, v := range blobs {
@@ -219,8 +286,6 @@ func (f *fakeBlobStore) downloadBlobs(_ context.Context, gameID, saveDir string,
 	f.downloadedBlobs = append(f.downloadedBlobs, snapshot)
 	f.mu.Unlock()
 
-	total := len(blobs)
-	done := 0
 	for relPath, hash := range blobs {
 		f.mu.Lock()
 		data, ok := f.blobs[f.blobKey(gameID, storage.BlobKindObject, hash)]
@@ -238,9 +303,8 @@ func (f *fakeBlobStore) downloadBlobs(_ context.Context, gameID, saveDir string,
 		if err := os.WriteFile(targetPath, data, 0o600); err != nil {
 			return err
 		}
-		done++
-		if onProgress != nil {
-			onProgress(done, total)
+		if onFile != nil {
+			onFile(relPath, int64(len(data)), false)
 		}
 	}
 	return nil
@@ -263,6 +327,57 @@ func (f *fakeBlobStore) listGameIDs(_ context.Context) ([]string, error) {
 	return ids, nil
 }
 
+func (f *fakeBlobStore) listObjectHashes(_ context.Context, gameID string) (map[string]struct{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	prefix := f.blobKey(gameID, storage.BlobKindObject, "")
+	hashes := make(map[string]struct{})
+	for key := range f.blobs {
+		if strings.HasPrefix(key, prefix) {
+			hashes[strings.TrimPrefix(key, prefix)] = struct{}{}
+		}
+	}
+	return hashes, nil
+}
+
+func (f *fakeBlobStore) writeHeadHistory(_ context.Context, gameID string, entry storage.HeadHistoryEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.history[gameID] == nil {
+		f.history[gameID] = make(map[string]storage.HeadHistoryEntry)
+	}
+	f.history[gameID][entry.ID] = entry
+	return nil
+}
+
+func (f *fakeBlobStore) readHeadHistory(_ context.Context, gameID, id string) (*storage.HeadHistoryEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry, ok := f.history[gameID][id]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (f *fakeBlobStore) listHeadHistoryIDs(_ context.Context, gameID string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.history[gameID]))
+	for id := range f.history[gameID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (f *fakeBlobStore) deleteHeadHistory(_ context.Context, gameID, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.history[gameID], id)
+	return nil
+}
+
 // ─── helpers ─────────────────────────────────────────────────────────────────
 
 func newTestService(repo *fakeContentSyncRepository, bstore *fakeBlobStore) *ContentSyncService {
@@ -292,7 +407,7 @@ func setupRemoteState(
 	t.Helper()
 	ctx := context.Background()
 
-	saveSnap, saveBlobs, err := buildSaveSnapshot(saveDir)
+	saveSnap, saveBlobs, err := buildSaveSnapshot(saveDir, saveFileFilter{})
 	if err != nil {
 		t.Fatalf("buildSaveSnapshot: %v", err)
 	}
@@ -308,7 +423,7 @@ func setupRemoteState(
 		t.Fatalf("putBlob saveSnap: %v", err)
 	}
 
-	meta, err := buildMetaSnapshot(game, sessions, "", savesHash, "testdevice", 0, 0)
+	meta, err := buildMetaSnapshot(game, sessions, nil, nil, "", savesHash, DeviceIdentity{Name: "testdevice"}, 0, 0)
 	if err != nil {
 		t.Fatalf("buildMetaSnapshot: %v", err)
 	}
@@ -320,6 +435,14 @@ func setupRemoteState(
 	if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, meta.Snapshot.SessionsJSON, meta.SessionsJSON); err != nil {
 		t.Fatalf("putBlob sessionsJSON: %v", err)
 	}
+	for hash, data := range meta.SessionChunks {
+		if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, hash, data); err != nil {
+			t.Fatalf("putBlob session chunk: %v", err)
+		}
+	}
+	if err := bstore.putBlob(ctx, gameID, storage.BlobKindMeta, meta.Snapshot.RoutesJSON, meta.RoutesJSON); err != nil {
+		t.Fatalf("putBlob routesJSON: %v", err)
+	}
 	if err := bstore.putBlob(ctx, gameID, storage.BlobKindCommit, metaHash, meta.SnapshotBytes); err != nil {
 		t.Fatalf("putBlob meta: %v", err)
 	}
@@ -432,15 +555,18 @@ func TestContentSyncServicePushSetsProgressCallback(t *testing.T) {
 	bstore := newFakeBlobStore()
 	svc := newTestService(repo, bstore)
 
-	var progressCalled bool
-	err := svc.Push(context.Background(), game.ID, func(current, total int) {
-		progressCalled = true
+	var last domain.TransferProgress
+	err := svc.Push(context.Background(), game.ID, func(progress domain.TransferProgress) {
+		last = progress
 	})
 	if err != nil {
 		t.Fatalf("Push: %v", err)
 	}
-	if !progressCalled {
-		t.Error("expected progress callback to be called")
+	if last.FilesTotal != 3 || last.FilesDone != 3 {
+		t.Errorf("expected all files reported, got %+v", last)
+	}
+	if last.BytesTotal != 15 || last.BytesDone != 15 || last.CurrentFile == "" {
+		t.Errorf("expected byte totals and file name, got %+v", last)
 	}
 }
 
@@ -592,10 +718,7 @@ func TestContentSyncServicePullRestoresFilesAndMetadata(t *testing.T) {
 		t.Error("expected UpsertGameSync to be called")
 	}
 
-	// セッションが差し替えられた
-	if !repo.deletedSessions {
-		t.Error("expected DeletePlaySessionsByGame to be called")
-	}
+	// セッションが取り込まれた
 	if len(repo.upsertedSessions) == 0 {
 		t.Error("expected sessions to be upserted")
 	}
@@ -606,6 +729,85 @@ func TestContentSyncServicePullRestoresFilesAndMetadata(t *testing.T) {
 	}
 }
 
+func TestContentSyncServicePushThenPullRoundTripsRoutes(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("game data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	routeID := "route-b"
+	game.CurrentRouteID = &routeID
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pushRepo := newFakeRepo(&game, nil)
+	pushRepo.routes = []domain.Route{
+		{ID: "route-a", Name: "共通", Order: 0, GameID: game.ID, CreatedAt: createdAt},
+		{ID: routeID, Name: "ヒロインB", Order: 1, GameID: game.ID, CreatedAt: createdAt},
+	}
+	bstore := newFakeBlobStore()
+	if err := newTestService(pushRepo, bstore).Push(context.Background(), game.ID, nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	// 別PC（ルート未登録）で Pull する。
+	pullGame := baseGame(saveDir)
+	pullRepo := newFakeRepo(&pullGame, nil)
+	if _, err := newTestService(pullRepo, bstore).Pull(context.Background(), game.ID, nil, false); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if len(pullRepo.appliedRoutes) != 2 || pullRepo.appliedRoutes[1].ID != routeID || pullRepo.appliedRoutes[1].Name != "ヒロインB" {
+		t.Fatalf("expected routes to be pulled, got %#v", pullRepo.appliedRoutes)
+	}
+	if pullRepo.appliedRoutes[1].GameID != game.ID || !pullRepo.appliedRoutes[1].CreatedAt.Equal(createdAt) {
+		t.Fatalf("unexpected pulled route: %#v", pullRepo.appliedRoutes[1])
+	}
+	if pullRepo.upsertedGame == nil || pullRepo.upsertedGame.CurrentRouteID == nil || *pullRepo.upsertedGame.CurrentRouteID != routeID {
+		t.Fatalf("expected currentRouteId to survive, got %#v", pullRepo.upsertedGame)
+	}
+}
+
+func TestContentSyncServicePullKeepsLocalRoutesForCommitWithoutRoutes(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	game := baseGame(saveDir)
+	bstore := newFakeBlobStore()
+	meta := setupRemoteState(t, bstore, game.ID, game, nil, saveDir)
+	if meta.RoutesJSON != emptyRoutesJSONHash {
+		t.Fatalf("expected an empty routes.json for a game without routes, got %q", meta.RoutesJSON)
+	}
+	repo := newFakeRepo(&game, nil)
+	if _, err := newTestService(repo, bstore).Pull(context.Background(), game.ID, nil, false); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if repo.appliedRoutes == nil || len(repo.appliedRoutes) != 0 {
+		t.Fatalf("expected empty routes to delete local routes, got %#v", repo.appliedRoutes)
+	}
+
+	// ルート同期以前の commit（routes.json 無し）に差し替える。
+	meta.RoutesJSON = ""
+	legacyBytes, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyHash := hashBytes(legacyBytes)
+	ctx := context.Background()
+	if err := bstore.putBlob(ctx, game.ID, storage.BlobKindCommit, legacyHash, legacyBytes); err != nil {
+		t.Fatalf("putBlob legacy commit: %v", err)
+	}
+	if err := bstore.writeHEAD(ctx, game.ID, legacyHash); err != nil {
+		t.Fatalf("writeHEAD: %v", err)
+	}
+	repo = newFakeRepo(&game, nil)
+	if _, err := newTestService(repo, bstore).Pull(context.Background(), game.ID, nil, false); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if repo.appliedRoutes != nil {
+		t.Fatalf("expected nil routes (keep local), got %#v", repo.appliedRoutes)
+	}
+}
+
 func TestContentSyncServicePullSkipsUnchangedFiles(t *testing.T) {
 	t.Parallel()
 
@@ -681,7 +883,7 @@ func TestContentSyncServicePullRejectsEscapingSavePath(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	meta, err := buildMetaSnapshot(game, nil, "", savesHash, "testdevice", 0, 0)
+	meta, err := buildMetaSnapshot(game, nil, nil, nil, "", savesHash, DeviceIdentity{Name: "testdevice"}, 0, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -719,7 +921,7 @@ func TestContentSyncServicePullRejectsMismatchedRemoteGameID(t *testing.T) {
 	game := baseGame(saveDir)
 	bstore := newFakeBlobStore()
 
-	saveSnap, saveBlobs, err := buildSaveSnapshot(saveDir)
+	saveSnap, saveBlobs, err := buildSaveSnapshot(saveDir, saveFileFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -739,7 +941,7 @@ func TestContentSyncServicePullRejectsMismatchedRemoteGameID(t *testing.T) {
 
 	remoteGame := game
 	remoteGame.ID = "other-game"
-	meta, err := buildMetaSnapshot(remoteGame, nil, "", savesHash, "testdevice", 0, 0)
+	meta, err := buildMetaSnapshot(remoteGame, nil, nil, nil, "", savesHash, DeviceIdentity{Name: "testdevice"}, 0, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -788,17 +990,15 @@ func TestContentSyncServicePullReportsProgress(t *testing.T) {
 	repo := newFakeRepo(&game, nil)
 	svc := newTestService(repo, bstore)
 
-	var maxCurrent int
-	_, err := svc.Pull(context.Background(), game.ID, func(current, total int) {
-		if current > maxCurrent {
-			maxCurrent = current
-		}
+	var last domain.TransferProgress
+	_, err := svc.Pull(context.Background(), game.ID, func(progress domain.TransferProgress) {
+		last = progress
 	}, false)
 	if err != nil {
 		t.Fatalf("Pull: %v", err)
 	}
-	if maxCurrent == 0 {
-		t.Error("expected progress to be reported")
+	if last.FilesDone == 0 || last.FilesDone != last.FilesTotal || last.CurrentFile != "save.dat" || last.BytesDone == 0 {
+		t.Errorf("expected progress to be reported, got %+v", last)
 	}
 }
 
@@ -895,13 +1095,13 @@ func TestContentSyncServiceStatusReturnsPullNeeded(t *testing.T) {
 	sessions := []domain.PlaySession{}
 
 	// ローカルの状態を fingerprint として LocalSyncHead に設定
-	localSaveSnap, _, err := buildSaveSnapshot(saveDir)
+	localSaveSnap, _, err := buildSaveSnapshot(saveDir, saveFileFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
 	localSaveSnapJSON, _ := json.Marshal(localSaveSnap)
 	localSavesHash := hashBytes(localSaveSnapJSON)
-	localMeta, err := buildMetaSnapshot(game, sessions, "", localSavesHash, "testdevice", 0, 0)
+	localMeta, err := buildMetaSnapshot(game, sessions, nil, nil, "", localSavesHash, DeviceIdentity{Name: "testdevice"}, 0, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -945,13 +1145,13 @@ func TestContentSyncServiceStatusReturnsConflict(t *testing.T) {
 
 	// 基準: 空のフォルダ状態として LocalSyncHead を設定
 	emptyDir := t.TempDir()
-	baseSaveSnap, _, err := buildSaveSnapshot(emptyDir)
+	baseSaveSnap, _, err := buildSaveSnapshot(emptyDir, saveFileFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
 	baseSaveSnapJSON, _ := json.Marshal(baseSaveSnap)
 	baseSavesHash := hashBytes(baseSaveSnapJSON)
-	baseMeta, err := buildMetaSnapshot(game, nil, "", baseSavesHash, "testdevice", 0, 0)
+	baseMeta, err := buildMetaSnapshot(game, nil, nil, nil, "", baseSavesHash, DeviceIdentity{Name: "testdevice"}, 0, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,192 @@
+// プレイセッションを月ごとのログ（sessions/<年-月>.json 相当）に分けて同期し、変わった月だけを転送・反映する。
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// sessionChunkKey はセッションを月ごとに分けるときのキー（playedAt の UTC の "2006-01"）。
+func sessionChunkKey(playedAt time.Time) string {
+	return playedAt.UTC().Format("2006-01")
+}
+
+// toCloudSession は DB のセッションを sessions.json の形式にする。
+func toCloudSession(session domain.PlaySession) cloudSession {
+	return cloudSession{
+		ID:                 session.ID,
+		PlayedAt:           session.PlayedAt,
+		Duration:           session.Duration,
+		SessionName:        session.SessionName,
+		Notes:              session.Notes,
+		RouteID:            session.RouteID,
+		UpdatedAt:          session.UpdatedAt,
+		Partial:            session.Partial,
+		IdleDuration:       session.IdleDuration,
+		CPUPeakPercent:     session.CPUPeakPercent,
+		CPUAveragePercent:  session.CPUAveragePercent,
+		MemoryPeakBytes:    session.MemoryPeakBytes,
+		MemoryAverageBytes: session.MemoryAverageBytes,
+	}
+}
+
+// fromCloudSession は sessions.json のセッションを gameID のセッションとして DB の形式にする。
+func fromCloudSession(gameID string, cs cloudSession) domain.PlaySession {
+	return domain.PlaySession{
+		ID:                 cs.ID,
+		GameID:             gameID,
+		PlayedAt:           cs.PlayedAt,
+		Duration:           cs.Duration,
+		SessionName:        cs.SessionName,
+		Notes:              cs.Notes,
+		RouteID:            cs.RouteID,
+		UpdatedAt:          cs.UpdatedAt,
+		Partial:            cs.Partial,
+		IdleDuration:       cs.IdleDuration,
+		CPUPeakPercent:     cs.CPUPeakPercent,
+		CPUAveragePercent:  cs.CPUAveragePercent,
+		MemoryPeakBytes:    cs.MemoryPeakBytes,
+		MemoryAverageBytes: cs.MemoryAverageBytes,
+	}
+}
+
+// buildSessionChunks はセッションを月ごとに分け、月→ハッシュと、ハッシュ→JSON を返す。
+// 月の中は playedAt・ID の順に並べ、DB の並び順によらず同じ内容なら同じハッシュになるようにする。
+func buildSessionChunks(sessions []cloudSession) (map[string]domain.BlobHash, map[domain.BlobHash][]byte, error) {
+	byMonth := make(map[string][]cloudSession)
+	for _, session := range sessions {
+		key := sessionChunkKey(session.PlayedAt)
+		byMonth[key] = append(byMonth[key], session)
+	}
+	hashes := make(map[string]domain.BlobHash, len(byMonth))
+	blobs := make(map[domain.BlobHash][]byte, len(byMonth))
+	for key, chunk := range byMonth {
+		sort.Slice(chunk, func(i, j int) bool {
+			if !chunk[i].PlayedAt.Equal(chunk[j].PlayedAt) {
+				return chunk[i].PlayedAt.Before(chunk[j].PlayedAt)
+			}
+			return chunk[i].ID < chunk[j].ID
+		})
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return nil, nil, err
+		}
+		hash := hashBytes(data)
+		hashes[key] = hash
+		blobs[hash] = data
+	}
+	return hashes, blobs, nil
+}
+
+// loadCloudSessions はコミットのセッションを読み込む。月ごとのログ（SessionChunks）があればそれを、無ければ sessions.json を読む。
+// have にローカルと同じハッシュが入っている月はダウンロードせず、返すセッションにも含めない。
+func loadCloudSessions(ctx context.Context, bstore contentBlobStore, gameID string, meta domain.MetaSnapshot, have map[string]domain.BlobHash) ([]cloudSession, error) {
+	if len(meta.SessionChunks) == 0 {
+		data, err := bstore.getBlob(ctx, gameID, storage.BlobKindMeta, meta.SessionsJSON)
+		if err != nil {
+			return nil, err
+		}
+		var sessions []cloudSession
+		if err := json.Unmarshal(data, &sessions); err != nil {
+			return nil, err
+		}
+		return sessions, nil
+	}
+	keys := make([]string, 0, len(meta.SessionChunks))
+	for key := range meta.SessionChunks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sessions := make([]cloudSession, 0)
+	for _, key := range keys {
+		hash := meta.SessionChunks[key]
+		if have[key] == hash {
+			continue
+		}
+		data, err := bstore.getBlob(ctx, gameID, storage.BlobKindMeta, hash)
+		if err != nil {
+			return nil, fmt.Errorf("%s のセッションを取得できません: %w", key, err)
+		}
+		var chunk []cloudSession
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return nil, fmt.Errorf("%s のセッションを解析できません: %w", key, err)
+		}
+		sessions = append(sessions, chunk...)
+	}
+	return sessions, nil
+}
+
+// localSessionChunks はローカルのセッションと、その月ごとのハッシュを返す。
+func (s *ContentSyncService) localSessionChunks(ctx context.Context, gameID string) ([]domain.PlaySession, map[string]domain.BlobHash, error) {
+	sessions, err := s.repository.ListPlaySessionsByGame(ctx, gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cloudSessions := make([]cloudSession, 0, len(sessions))
+	for _, session := range sessions {
+		cloudSessions = append(cloudSessions, toCloudSession(session))
+	}
+	hashes, _, err := buildSessionChunks(cloudSessions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sessions, hashes, nil
+}
+
+// pulledSessions は Pull 後にあるべきセッションを返す。loadCloudSessions で読み飛ばした月（ローカルと同じ内容）は
+// ローカルのセッションをそのまま使う。SessionChunks の無いコミットでは fetched が全セッション。
+func pulledSessions(gameID string, meta domain.MetaSnapshot, localSessions []domain.PlaySession, localChunks map[string]domain.BlobHash, fetched []cloudSession) []domain.PlaySession {
+	sessions := make([]domain.PlaySession, 0, len(fetched)+len(localSessions))
+	if len(meta.SessionChunks) > 0 {
+		for _, session := range localSessions {
+			key := sessionChunkKey(session.PlayedAt)
+			if hash, ok := meta.SessionChunks[key]; ok && localChunks[key] == hash {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+	for _, cs := range fetched {
+		sessions = append(sessions, fromCloudSession(gameID, cs))
+	}
+	return sessions
+}
+
+// diffSessions は current を desired に揃えるために書き込むセッションと削除するセッションIDを返す。
+// 同じ ID で同期する内容（sessions.json の項目）が変わっていないセッションは書き込まない。
+func diffSessions(current, desired []domain.PlaySession) ([]domain.PlaySession, []string, error) {
+	currentByID := make(map[string][]byte, len(current))
+	for _, session := range current {
+		data, err := json.Marshal(toCloudSession(session))
+		if err != nil {
+			return nil, nil, err
+		}
+		currentByID[session.ID] = data
+	}
+	upserts := make([]domain.PlaySession, 0)
+	desiredIDs := make(map[string]struct{}, len(desired))
+	for _, session := range desired {
+		desiredIDs[session.ID] = struct{}{}
+		data, err := json.Marshal(toCloudSession(session))
+		if err != nil {
+			return nil, nil, err
+		}
+		if existing, ok := currentByID[session.ID]; ok && bytes.Equal(existing, data) {
+			continue
+		}
+		upserts = append(upserts, session)
+	}
+	deletes := make([]string, 0)
+	for _, session := range current {
+		if _, ok := desiredIDs[session.ID]; !ok {
+			deletes = append(deletes, session.ID)
+		}
+	}
+	return upserts, deletes, nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func TestBuildSessionChunksGroupsByMonthIndependentOfOrder(t *testing.T) {
+	t.Parallel()
+
+	jan := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	sessions := []cloudSession{
+		{ID: "b", PlayedAt: jan, Duration: 10},
+		{ID: "c", PlayedAt: feb, Duration: 20},
+		{ID: "a", PlayedAt: jan, Duration: 30},
+	}
+	hashes, blobs, err := buildSessionChunks(sessions)
+	if err != nil {
+		t.Fatalf("buildSessionChunks: %v", err)
+	}
+	if len(hashes) != 2 || hashes["2026-01"] == "" || hashes["2026-02"] == "" || len(blobs) != 2 {
+		t.Fatalf("unexpected chunks: %v", hashes)
+	}
+
+	reordered, _, err := buildSessionChunks([]cloudSession{sessions[2], sessions[1], sessions[0]})
+	if err != nil {
+		t.Fatalf("buildSessionChunks: %v", err)
+	}
+	if reordered["2026-01"] != hashes["2026-01"] || reordered["2026-02"] != hashes["2026-02"] {
+		t.Fatalf("chunk hashes should not depend on order: %v vs %v", reordered, hashes)
+	}
+}
+
+func TestLoadCloudSessionsSkipsMonthsAlreadyHeld(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bstore := newFakeBlobStore()
+	game := baseGame("")
+	jan := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	meta := setupRemoteState(t, bstore, game.ID, game, []domain.PlaySession{
+		{ID: "jan", GameID: game.ID, PlayedAt: jan, Duration: 10},
+		{ID: "feb", GameID: game.ID, PlayedAt: feb, Duration: 20},
+	}, t.TempDir())
+
+	// 1 月分を取得しようとすれば失敗するように消しておく。
+	delete(bstore.blobs, bstore.blobKey(game.ID, storage.BlobKindMeta, meta.SessionChunks["2026-01"]))
+	have := map[string]domain.BlobHash{"2026-01": meta.SessionChunks["2026-01"]}
+
+	sessions, err := loadCloudSessions(ctx, bstore, game.ID, meta, have)
+	if err != nil {
+		t.Fatalf("loadCloudSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "feb" {
+		t.Fatalf("expected only the February session, got %+v", sessions)
+	}
+	if _, err := loadCloudSessions(ctx, bstore, game.ID, meta, nil); err == nil {
+		t.Fatal("expected an error when a needed month is missing")
+	}
+}
+
+func TestLoadCloudSessionsReadsSessionsJSONWithoutChunks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bstore := newFakeBlobStore()
+	game := baseGame("")
+	meta := setupRemoteState(t, bstore, game.ID, game, []domain.PlaySession{
+		{ID: "s1", GameID: game.ID, PlayedAt: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), Duration: 10},
+	}, t.TempDir())
+
+	// 月ごとのログを持たない古いコミット。
+	meta.SessionChunks = nil
+	sessions, err := loadCloudSessions(ctx, bstore, game.ID, meta, nil)
+	if err != nil {
+		t.Fatalf("loadCloudSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "s1" {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+}
+
+func TestDiffSessionsReturnsOnlyChanges(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	current := []domain.PlaySession{
+		{ID: "same", GameID: "game-1", PlayedAt: at, Duration: 10},
+		{ID: "changed", GameID: "game-1", PlayedAt: at, Duration: 20},
+		{ID: "removed", GameID: "game-1", PlayedAt: at, Duration: 30},
+	}
+	desired := []domain.PlaySession{
+		{ID: "same", GameID: "game-1", PlayedAt: at, Duration: 10},
+		{ID: "changed", GameID: "game-1", PlayedAt: at, Duration: 25},
+		{ID: "added", GameID: "game-1", PlayedAt: at, Duration: 40},
+	}
+	upserts, deletes, err := diffSessions(current, desired)
+	if err != nil {
+		t.Fatalf("diffSessions: %v", err)
+	}
+	if len(upserts) != 2 || upserts[0].ID != "changed" || upserts[1].ID != "added" {
+		t.Fatalf("unexpected upserts: %+v", upserts)
+	}
+	if len(deletes) != 1 || deletes[0] != "removed" {
+		t.Fatalf("unexpected deletes: %v", deletes)
+	}
+}
+
+func TestContentSyncServicePullAppliesOnlyChangedMonths(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	jan := domain.PlaySession{ID: "jan", GameID: game.ID, PlayedAt: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), Duration: 10}
+	feb := domain.PlaySession{ID: "feb", GameID: game.ID, PlayedAt: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), Duration: 20}
+	bstore := newFakeBlobStore()
+	remoteFeb := feb
+	remoteFeb.Duration = 50
+	meta := setupRemoteState(t, bstore, game.ID, game, []domain.PlaySession{jan, remoteFeb}, saveDir)
+	// ローカルと同じ 1 月分はダウンロードしない。
+	delete(bstore.blobs, bstore.blobKey(game.ID, storage.BlobKindMeta, meta.SessionChunks["2026-01"]))
+
+	repo := newFakeRepo(&game, []domain.PlaySession{jan, feb})
+	svc := newTestService(repo, bstore)
+
+	res, err := svc.Pull(context.Background(), game.ID, nil, false)
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if !res.Applied {
+		t.Fatalf("expected Pull to apply, got %+v", res)
+	}
+	if len(repo.upsertedSessions) != 1 || repo.upsertedSessions[0].ID != "feb" || repo.upsertedSessions[0].Duration != 50 {
+		t.Fatalf("only the changed session should be written, got %+v", repo.upsertedSessions)
+	}
+	if len(repo.deletedSessionIDs) != 0 {
+		t.Fatalf("no session should be deleted, got %v", repo.deletedSessionIDs)
+	}
+	if len(repo.sessions) != 2 {
+		t.Fatalf("expected both sessions to remain, got %+v", repo.sessions)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"sort"
 	"strings"
 
 	"CloudLaunch_Go/internal/infrastructure/credentials"
@@ -34,6 +35,12 @@ func (service *CredentialService) SaveCredential(ctx context.Context, key string
 		BucketName:      strings.TrimSpace(input.BucketName),
 		Region:          strings.TrimSpace(input.Region),
 		Endpoint:        strings.TrimSpace(input.Endpoint),
+		SessionToken:    strings.TrimSpace(input.SessionToken),
+		RoleARN:         strings.TrimSpace(input.RoleARN),
+		RoleSessionName: strings.TrimSpace(input.RoleSessionName),
+		ExternalID:      strings.TrimSpace(input.ExternalID),
+		// パスフレーズは前後の空白も鍵の一部として扱う（他端末と入力を揃えるため変形しない）。
+		EncryptionPassphrase: input.EncryptionPassphrase,
 	}
 
 	if error := service.store.Save(ctx, strings.TrimSpace(key), credential); error != nil {
@@ -67,21 +74,44 @@ func (service *CredentialService) DeleteCredential(ctx context.Context, key stri
 	return nil
 }
 
+// ListCredentialKeys は保存済みの認証情報プロファイルのキーを昇順で返す。
+func (service *CredentialService) ListCredentialKeys(ctx context.Context) ([]string, error) {
+	keys, error := service.store.List(ctx)
+	if error != nil {
+		service.logger.Error("認証情報一覧の取得に失敗", "error", error)
+		return nil, newServiceError("認証情報一覧の取得に失敗しました", error.Error())
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
 // CredentialInput は認証情報入力を表す。
+// SessionToken・RoleARN 等は一時認証情報 / AssumeRole を使う場合のみ指定する。
 type CredentialInput struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	BucketName      string
 	Region          string
 	Endpoint        string
+	SessionToken    string
+	RoleARN         string
+	RoleSessionName string
+	ExternalID      string
+	// EncryptionPassphrase はクライアントサイド暗号化を使う場合のみ指定する。
+	EncryptionPassphrase string
 }
 
 // CredentialOutput はUIに返す認証情報の最小情報を表す。
 type CredentialOutput struct {
-	AccessKeyID string
-	BucketName  string
-	Region      string
-	Endpoint    string
+	AccessKeyID     string
+	BucketName      string
+	Region          string
+	Endpoint        string
+	HasSessionToken bool
+	RoleARN         string
+	RoleSessionName string
+	// HasEncryptionPassphrase は暗号化パスフレーズが設定済みかを表す（値自体は返さない）。
+	HasEncryptionPassphrase bool
 }
 
 // validateCredentialInput は認証情報入力の基本チェックを行う。
@@ -101,5 +131,8 @@ func validateCredentialInput(input CredentialInput) error {
 	if _, detail, ok := requireNonEmpty(input.SecretAccessKey, "secretAccessKey"); !ok {
 		return errors.New(detail)
 	}
+	if roleARN := strings.TrimSpace(input.RoleARN); roleARN != "" && !strings.HasPrefix(roleARN, "arn:") {
+		return errors.New("roleARN must start with arn:")
+	}
 	return nil
 }
@@ -15,10 +15,12 @@ type fakeCredentialStore struct {
 	savedCredential credentials.Credential
 	loadedKey       string
 	deletedKey      string
+	listResult      []string
 	loadResult      *credentials.Credential
 	saveErr         error
 	loadErr         error
 	deleteErr       error
+	listErr         error
 }
 
 func (store *fakeCredentialStore) Save(ctx context.Context, key string, credential credentials.Credential) error {
@@ -37,6 +39,10 @@ func (store *fakeCredentialStore) Delete(ctx context.Context, key string) error
 	return store.deleteErr
 }
 
+func (store *fakeCredentialStore) List(ctx context.Context) ([]string, error) {
+	return store.listResult, store.listErr
+}
+
 func TestCredentialServiceSaveCredentialUsesStoreBoundary(t *testing.T) {
 	t.Parallel()
 
@@ -78,3 +84,79 @@ func TestCredentialServiceDeleteCredentialReturnsStoreError(t *testing.T) {
 		t.Fatalf("expected delete key to be forwarded, got %q", store.deletedKey)
 	}
 }
+
+func TestCredentialServiceListCredentialKeysReturnsSortedKeys(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeCredentialStore{listResult: []string{"nas", "default", "r2"}}
+	service := NewCredentialService(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	keys, err := service.ListCredentialKeys(context.Background())
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(keys) != 3 || keys[0] != "default" || keys[1] != "nas" || keys[2] != "r2" {
+		t.Fatalf("expected sorted keys, got %#v", keys)
+	}
+}
+
+func TestCredentialServiceListCredentialKeysReturnsServiceError(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeCredentialStore{listErr: errors.New("boom")}
+	service := NewCredentialService(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := service.ListCredentialKeys(context.Background())
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatalf("expected ServiceError, got %v", err)
+	}
+}
+
+func TestCredentialServiceSaveCredentialKeepsAssumeRoleFields(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeCredentialStore{}
+	service := NewCredentialService(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := service.SaveCredential(context.Background(), "work", CredentialInput{
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+		BucketName:      "bucket",
+		Region:          "ap-northeast-1",
+		Endpoint:        "s3.amazonaws.com",
+		SessionToken:    " token ",
+		RoleARN:         " arn:aws:iam::123456789012:role/sync ",
+		ExternalID:      " ext ",
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	saved := store.savedCredential
+	if saved.SessionToken != "token" || saved.RoleARN != "arn:aws:iam::123456789012:role/sync" || saved.ExternalID != "ext" {
+		t.Fatalf("expected trimmed assume-role fields, got %#v", saved)
+	}
+}
+
+func TestCredentialServiceSaveCredentialRejectsInvalidRoleARN(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeCredentialStore{}
+	service := NewCredentialService(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := service.SaveCredential(context.Background(), "work", CredentialInput{
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+		BucketName:      "bucket",
+		Region:          "region",
+		Endpoint:        "endpoint",
+		RoleARN:         "role/sync",
+	})
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatalf("expected ServiceError, got %v", err)
+	}
+	if store.savedKey != "" {
+		t.Fatal("invalid credential should not be saved")
+	}
+}
@@ -0,0 +1,84 @@
+// 端末ごとのデバイスID・デバイス名を管理し、同期コミットの作成元を識別できるようにする。
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+const (
+	deviceIDSettingKey   = "device_id"
+	deviceNameSettingKey = "device_name"
+)
+
+// DeviceIdentity はこのインストールを識別する情報を表す。
+// ID は初回起動時に生成して以後変えない。Name はホスト名由来の表示用で、PC 名の変更や
+// 同名 PC の存在で重複しうるため、端末の突き合わせには ID を使う。
+type DeviceIdentity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// DeviceIdentity はこの端末のデバイスID・デバイス名を返す。未生成なら生成して設定に保存する。
+func (s *ContentSyncService) DeviceIdentity(ctx context.Context) (DeviceIdentity, error) {
+	id, err := s.getOrInitDeviceID(ctx)
+	if err != nil {
+		return DeviceIdentity{}, err
+	}
+	name, err := s.getOrInitDeviceName(ctx)
+	if err != nil {
+		return DeviceIdentity{}, err
+	}
+	return DeviceIdentity{ID: id, Name: name}, nil
+}
+
+func (s *ContentSyncService) getOrInitDeviceID(ctx context.Context) (string, error) {
+	id, err := s.repository.GetSetting(ctx, deviceIDSettingKey)
+	if err != nil {
+		return "", err
+	}
+	if id != "" {
+		return id, nil
+	}
+	id, err = newDeviceID()
+	if err != nil {
+		return "", err
+	}
+	// 保存できないまま使うと push のたびに別IDになり作成元の追跡ができないため、エラーにする。
+	if err := s.repository.UpsertSetting(ctx, deviceIDSettingKey, id); err != nil {
+		return "", fmt.Errorf("デバイスIDの保存に失敗: %w", err)
+	}
+	return id, nil
+}
+
+func (s *ContentSyncService) getOrInitDeviceName(ctx context.Context) (string, error) {
+	name, err := s.repository.GetSetting(ctx, deviceNameSettingKey)
+	if err != nil {
+		return "", err
+	}
+	if name != "" {
+		return name, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "Unknown Device"
+	}
+	// 保存に失敗しても致命的ではない（次回再取得・再保存される）が、
+	// 書き込みエラーを完全に握り潰さないようログに残す。
+	if err := s.repository.UpsertSetting(ctx, deviceNameSettingKey, hostname); err != nil {
+		s.logger.Warn("device_name の保存に失敗", "error", err)
+	}
+	return hostname, nil
+}
+
+// newDeviceID はランダムな 128bit のデバイスIDを16進文字列で生成する。
+func newDeviceID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("デバイスIDの生成に失敗: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
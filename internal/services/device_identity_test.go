@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func TestContentSyncServiceDeviceIdentityIsGeneratedOnceAndPersisted(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepo(nil, nil)
+	svc := newTestService(repo, newFakeBlobStore())
+
+	first, err := svc.DeviceIdentity(context.Background())
+	if err != nil {
+		t.Fatalf("DeviceIdentity: %v", err)
+	}
+	if len(first.ID) != 32 || first.Name == "" {
+		t.Fatalf("unexpected identity: %#v", first)
+	}
+	if repo.settings[deviceIDSettingKey] != first.ID {
+		t.Fatalf("expected device id to be persisted, got %q", repo.settings[deviceIDSettingKey])
+	}
+
+	second, err := svc.DeviceIdentity(context.Background())
+	if err != nil {
+		t.Fatalf("DeviceIdentity: %v", err)
+	}
+	if second != first {
+		t.Fatalf("expected stable identity, got %#v then %#v", first, second)
+	}
+}
+
+func TestContentSyncServicePushRecordsDeviceProvenance(t *testing.T) {
+	t.Parallel()
+
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("game data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	repo := newFakeRepo(&game, nil)
+	repo.settings[deviceIDSettingKey] = "device-a"
+	repo.settings[deviceNameSettingKey] = "Desktop-A"
+	bstore := newFakeBlobStore()
+	svc := newTestService(repo, bstore)
+
+	if err := svc.Push(context.Background(), game.ID, nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	metaBytes, err := bstore.getBlob(context.Background(), game.ID, storage.BlobKindCommit, bstore.heads[game.ID])
+	if err != nil {
+		t.Fatalf("getBlob: %v", err)
+	}
+	var meta domain.MetaSnapshot
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.DeviceID != "device-a" || meta.DeviceName != "Desktop-A" {
+		t.Fatalf("expected commit to record the device, got %q / %q", meta.DeviceID, meta.DeviceName)
+	}
+
+	infos, err := svc.LoadCloudMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("LoadCloudMetadata: %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 game, got %#v", infos)
+	}
+	if infos[0].LastModifiedBy != (LastModifiedBy{DeviceID: "device-a", DeviceName: "Desktop-A"}) || !infos[0].LastModifiedAt.Equal(meta.CreatedAt) {
+		t.Fatalf("unexpected provenance: %#v / %v", infos[0].LastModifiedBy, infos[0].LastModifiedAt)
+	}
+}
@@ -0,0 +1,160 @@
+// 不具合報告に貼り付ける診断情報（バージョン・DB・パス・認証情報の有無・補助ツールの配置など）を定義する。
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+)
+
+// diagnosticHelperExecutables は実行ファイルと同じフォルダに同梱する補助ツール。
+var diagnosticHelperExecutables = []string{"screencap-cli.exe"}
+
+// Diagnostics はサポート向けの診断情報を表す。秘密情報（キー・パスフレーズ・トークン）は含めない。
+// 取得できなかった項目は Errors に理由を残し、他の項目は返す。
+type Diagnostics struct {
+	AppVersion  string                  `json:"appVersion"`
+	GoVersion   string                  `json:"goVersion"`
+	OS          string                  `json:"os"`
+	Arch        string                  `json:"arch"`
+	GeneratedAt time.Time               `json:"generatedAt"`
+	Schema      domain.SchemaVersion    `json:"schema"`
+	Database    DiagnosticsDatabase     `json:"database"`
+	Paths       DiagnosticsPaths        `json:"paths"`
+	Credentials []DiagnosticsCredential `json:"credentials"`
+	GoogleDrive GoogleDriveStatus       `json:"googleDrive"`
+	Storage     DiagnosticsStorage      `json:"storage"`
+	Helpers     []DiagnosticsHelper     `json:"helpers"`
+	Hotkey      DiagnosticsHotkey       `json:"hotkey"`
+	Monitor     DiagnosticsMonitor      `json:"monitor"`
+	Errors      []string                `json:"errors"`
+}
+
+// DiagnosticsDatabase は DB ファイルのサイズを表す。WAL / SHM は存在しなければ 0。
+type DiagnosticsDatabase struct {
+	SizeBytes    int64 `json:"sizeBytes"`
+	WalSizeBytes int64 `json:"walSizeBytes"`
+	ShmSizeBytes int64 `json:"shmSizeBytes"`
+}
+
+// DiagnosticsPaths はアプリが使うフォルダ・ファイルの場所を表す。
+type DiagnosticsPaths struct {
+	AppDataDir    string `json:"appDataDir"`
+	DatabasePath  string `json:"databasePath"`
+	LogDir        string `json:"logDir"`
+	ExecutableDir string `json:"executableDir"`
+}
+
+// DiagnosticsCredential は保存済み認証情報1件の設定状況を表す。値そのものは返さない。
+type DiagnosticsCredential struct {
+	Key                     string `json:"key"`
+	Active                  bool   `json:"active"`
+	HasAccessKey            bool   `json:"hasAccessKey"`
+	HasSecretKey            bool   `json:"hasSecretKey"`
+	HasSessionToken         bool   `json:"hasSessionToken"`
+	HasEncryptionPassphrase bool   `json:"hasEncryptionPassphrase"`
+	HasBucket               bool   `json:"hasBucket"`
+	UsesAssumeRole          bool   `json:"usesAssumeRole"`
+}
+
+// DiagnosticsStorage は同期先とクラウドへの到達状況を表す。
+type DiagnosticsStorage struct {
+	Backend string        `json:"backend"`
+	Network NetworkStatus `json:"network"`
+	Offline bool          `json:"offline"`
+}
+
+// DiagnosticsHelper は補助ツール1件の配置状況を表す。
+type DiagnosticsHelper struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Present bool   `json:"present"`
+}
+
+// DiagnosticsHotkey はスクリーンショット用ホットキーの登録状況を表す。
+type DiagnosticsHotkey struct {
+	Combo      string `json:"combo"`
+	Registered bool   `json:"registered"`
+}
+
+// DiagnosticsMonitor はプロセス監視の状態を表す。
+type DiagnosticsMonitor struct {
+	Running        bool `json:"running"`
+	AutoTracking   bool `json:"autoTracking"`
+	TrackedGames   int  `json:"trackedGames"`
+	ActiveSessions int  `json:"activeSessions"`
+}
+
+// DiagnoseDatabaseFile は DB 本体と WAL / SHM ファイルのサイズを返す。本体が読めなければエラーを返す。
+func DiagnoseDatabaseFile(path string) (DiagnosticsDatabase, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return DiagnosticsDatabase{}, err
+	}
+	result := DiagnosticsDatabase{SizeBytes: info.Size()}
+	if wal, err := os.Stat(path + "-wal"); err == nil {
+		result.WalSizeBytes = wal.Size()
+	}
+	if shm, err := os.Stat(path + "-shm"); err == nil {
+		result.ShmSizeBytes = shm.Size()
+	}
+	return result, nil
+}
+
+// DiagnoseHelpers は dir に同梱の補助ツールがあるかを返す。dir が空なら全て未配置として返す。
+func DiagnoseHelpers(dir string) []DiagnosticsHelper {
+	helpers := make([]DiagnosticsHelper, 0, len(diagnosticHelperExecutables))
+	for _, name := range diagnosticHelperExecutables {
+		helper := DiagnosticsHelper{Name: name}
+		if dir != "" {
+			helper.Path = filepath.Join(dir, name)
+			if info, err := os.Stat(helper.Path); err == nil && !info.IsDir() {
+				helper.Present = true
+			}
+		}
+		helpers = append(helpers, helper)
+	}
+	return helpers
+}
+
+// DiagnoseCredential は認証情報の各項目が設定済みかだけを返す。credential が nil なら全て未設定。
+func DiagnoseCredential(key string, credential *credentials.Credential, active bool) DiagnosticsCredential {
+	result := DiagnosticsCredential{Key: key, Active: active}
+	if credential == nil {
+		return result
+	}
+	result.HasAccessKey = credential.AccessKeyID != ""
+	result.HasSecretKey = credential.SecretAccessKey != ""
+	result.HasSessionToken = credential.SessionToken != ""
+	result.HasEncryptionPassphrase = credential.EncryptionPassphrase != ""
+	result.HasBucket = credential.BucketName != ""
+	result.UsesAssumeRole = credential.RoleARN != ""
+	return result
+}
+
+// DiagnoseCredentials は保存済みの認証情報ごとの設定状況を返す。使用中のキーが未保存でも、未設定として含める。
+func (service *CredentialService) DiagnoseCredentials(ctx context.Context, cfg config.Config) ([]DiagnosticsCredential, error) {
+	keys, err := service.ListCredentialKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	activeKey := credentialKeyOf(cfg)
+	if !slices.Contains(keys, activeKey) {
+		keys = append([]string{activeKey}, keys...)
+	}
+	result := make([]DiagnosticsCredential, 0, len(keys))
+	for _, key := range keys {
+		credential, err := service.store.Load(ctx, key)
+		if err != nil {
+			return nil, newServiceError("認証情報取得に失敗しました", err.Error())
+		}
+		result = append(result, DiagnoseCredential(key, credential, key == activeKey))
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+)
+
+func TestDiagnoseDatabaseFileReportsWalSize(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "app.db")
+	if err := os.WriteFile(dbPath, make([]byte, 4096), 0o600); err != nil {
+		t.Fatalf("write db: %v", err)
+	}
+	if err := os.WriteFile(dbPath+"-wal", make([]byte, 512), 0o600); err != nil {
+		t.Fatalf("write wal: %v", err)
+	}
+
+	database, err := DiagnoseDatabaseFile(dbPath)
+	if err != nil {
+		t.Fatalf("DiagnoseDatabaseFile: %v", err)
+	}
+	if database.SizeBytes != 4096 || database.WalSizeBytes != 512 || database.ShmSizeBytes != 0 {
+		t.Fatalf("unexpected sizes: %+v", database)
+	}
+	if _, err := DiagnoseDatabaseFile(filepath.Join(dir, "missing.db")); err == nil {
+		t.Fatal("expected error for missing database")
+	}
+}
+
+func TestDiagnoseHelpersChecksExecutableDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "screencap-cli.exe"), []byte("MZ"), 0o600); err != nil {
+		t.Fatalf("write helper: %v", err)
+	}
+	helpers := DiagnoseHelpers(dir)
+	if len(helpers) != len(diagnosticHelperExecutables) || !helpers[0].Present || helpers[0].Path != filepath.Join(dir, "screencap-cli.exe") {
+		t.Fatalf("unexpected helpers: %+v", helpers)
+	}
+	if missing := DiagnoseHelpers(""); missing[0].Present || missing[0].Path != "" {
+		t.Fatalf("expected helper to be missing without a dir: %+v", missing)
+	}
+}
+
+func TestDiagnoseCredentialsReportsPresenceOnly(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeCredentialStore{
+		listResult: []string{"work"},
+		loadResult: &credentials.Credential{AccessKeyID: "AKIA", SecretAccessKey: "secret", BucketName: "bucket"},
+	}
+	service := NewCredentialService(store, newTestLogger())
+
+	entries, err := service.DiagnoseCredentials(context.Background(), config.Config{CredentialKey: "home"})
+	if err != nil {
+		t.Fatalf("DiagnoseCredentials: %v", err)
+	}
+	// 使用中のキーは未保存でも先頭に含める。
+	if len(entries) != 2 || entries[0].Key != "home" || !entries[0].Active || entries[1].Key != "work" || entries[1].Active {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	work := entries[1]
+	if !work.HasAccessKey || !work.HasSecretKey || !work.HasBucket || work.HasEncryptionPassphrase || work.UsesAssumeRole {
+		t.Fatalf("unexpected presence flags: %+v", work)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+// 非Windows向けの空き容量取得のスタブ実装。
+package services
+
+import "errors"
+
+// errDiskSpaceUnsupported は空き容量を取得できない環境であることを表す。呼び出し側は確認を省略する。
+var errDiskSpaceUnsupported = errors.New("disk space check is only supported on Windows")
+
+// diskFreeBytes は非Windowsではサポート外。
+func diskFreeBytes(string) (uint64, error) {
+	return 0, errDiskSpaceUnsupported
+}
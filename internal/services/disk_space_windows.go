@@ -0,0 +1,19 @@
+//go:build windows
+
+// Windows向けにドライブの空き容量を取得する。
+package services
+
+import "golang.org/x/sys/windows"
+
+// diskFreeBytes は dir があるドライブで現在のユーザーが使える空き容量（バイト）を返す。
+func diskFreeBytes(dir string) (uint64, error) {
+	path, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var available, total, free uint64
+	if err := windows.GetDiskFreeSpaceEx(path, &available, &total, &free); err != nil {
+		return 0, err
+	}
+	return available, nil
+}
@@ -1,15 +1,17 @@
-// 批評空間取り込み用のエラー型を定義する。
+// 批評空間などの外部サイトからの取り込み用のエラー型を定義する。
 package services
 
 import "fmt"
 
 // InvalidUrlError はURLからIDを抽出できない場合のエラーを表す。
+// Provider は取り込み元の ID で、空なら批評空間として扱う。
 type InvalidUrlError struct {
-	URL string
+	URL      string
+	Provider string
 }
 
 func (err InvalidUrlError) Error() string {
-	return fmt.Sprintf("invalid erogamescape url: %s", err.URL)
+	return fmt.Sprintf("invalid %s url: %s", importSourceLabel(err.Provider), err.URL)
 }
 
 // FetchError はHTML取得に失敗した場合のエラーを表す。
@@ -31,16 +33,18 @@ func (err FetchError) Unwrap() error {
 }
 
 // ParseError はDOM解析に失敗した場合のエラーを表す。
+// Provider は取り込み元の ID で、空なら批評空間として扱う。
 type ParseError struct {
-	Field string
-	Err   error
+	Field    string
+	Err      error
+	Provider string
 }
 
 func (err ParseError) Error() string {
 	if err.Field == "" {
-		return "failed to parse erogamescape page"
+		return fmt.Sprintf("failed to parse %s page", importSourceLabel(err.Provider))
 	}
-	return fmt.Sprintf("failed to parse erogamescape page (%s)", err.Field)
+	return fmt.Sprintf("failed to parse %s page (%s)", importSourceLabel(err.Provider), err.Field)
 }
 
 func (err ParseError) Unwrap() error {
@@ -60,3 +64,11 @@ func (err ImageError) Error() string {
 func (err ImageError) Unwrap() error {
 	return err.Err
 }
+
+// importSourceLabel はエラーメッセージに出す取り込み元の名前を返す。
+func importSourceLabel(provider string) string {
+	if provider == "" {
+		return MetadataProviderErogameScape
+	}
+	return provider
+}
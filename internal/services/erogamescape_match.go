@@ -0,0 +1,255 @@
+// 登録済みのゲームをタイトルで批評空間と一括照合し、確度の高い候補を反映する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// erogameScapeAutoMatchScore 以上の類似度で、次点と erogameScapeAutoMatchMargin 以上離れた候補は自動で反映する。
+	erogameScapeAutoMatchScore  = 0.9
+	erogameScapeAutoMatchMargin = 0.1
+	// erogameScapeMatchMaxCandidates は手動で選ぶために返す候補の上限。
+	erogameScapeMatchMaxCandidates = 5
+)
+
+// erogameScapeClient は一括照合で使う批評空間の検索と取り込み（ErogameScapeService）。
+type erogameScapeClient interface {
+	SearchErogameScape(ctx context.Context, query string, pageURL string) (domain.ErogameScapeSearchResult, error)
+	FetchFromErogameScape(ctx context.Context, gamePageURL string) (domain.GameImport, error)
+}
+
+// ErogameScapeMatchService は登録済みのゲームと批評空間の作品を照合する。
+type ErogameScapeMatchService struct {
+	client     erogameScapeClient
+	repository GameRepository
+	logger     *slog.Logger
+}
+
+// NewErogameScapeMatchService は ErogameScapeMatchService を生成する。
+func NewErogameScapeMatchService(client *ErogameScapeService, repository GameRepository, logger *slog.Logger) *ErogameScapeMatchService {
+	return &ErogameScapeMatchService{client: client, repository: repository, logger: logger}
+}
+
+// MatchGames は gameIDs のゲームをタイトルで批評空間から検索し、候補をタイトルの類似度で採点する。
+// 類似度が十分に高く次点と差がある候補は取り込んで反映し、それ以外は候補を返して手動で選ばせる。
+// 1件の失敗では止めず Failed に記録する。キャンセルされた場合は、そこまでの結果を Canceled 付きでエラーなしに返す
+// （反映済みのゲームは取り消さない）。キャンセルで中断した照合中のゲームは Failed に入れない。
+func (service *ErogameScapeMatchService) MatchGames(ctx context.Context, gameIDs []string, onProgress ProgressFunc) (domain.ErogameScapeMatchResult, error) {
+	result := domain.ErogameScapeMatchResult{
+		Applied:   []domain.ErogameScapeMatchApplied{},
+		Ambiguous: []domain.ErogameScapeMatchAmbiguous{},
+		NotFound:  []string{},
+		Failed:    []domain.ErogameScapeMatchFailed{},
+	}
+	ids := uniqueTrimmedIDs(gameIDs)
+	if onProgress != nil {
+		onProgress(0, len(ids))
+	}
+	for index, gameID := range ids {
+		if ctx.Err() != nil {
+			result.Canceled = true
+			break
+		}
+		service.matchGame(ctx, gameID, &result)
+		if ctx.Err() != nil {
+			result.Canceled = true
+			break
+		}
+		if onProgress != nil {
+			onProgress(index+1, len(ids))
+		}
+	}
+	service.logger.Info("批評空間と一括照合", "games", len(ids), "applied", len(result.Applied),
+		"ambiguous", len(result.Ambiguous), "notFound", len(result.NotFound), "failed", len(result.Failed), "canceled", result.Canceled)
+	return result, nil
+}
+
+func (service *ErogameScapeMatchService) matchGame(ctx context.Context, gameID string, result *domain.ErogameScapeMatchResult) {
+	fail := func(message string, error error) {
+		if ctx.Err() != nil {
+			return
+		}
+		service.logger.Warn(message, "gameId", gameID, "error", error)
+		result.Failed = append(result.Failed, domain.ErogameScapeMatchFailed{GameID: gameID, Message: message + ": " + error.Error()})
+	}
+	game, error := service.repository.GetGameByID(ctx, gameID)
+	if error != nil {
+		fail("ゲーム取得に失敗しました", error)
+		return
+	}
+	if game == nil {
+		result.Failed = append(result.Failed, domain.ErogameScapeMatchFailed{GameID: gameID, Message: "ゲームが見つかりません"})
+		return
+	}
+	found, error := service.client.SearchErogameScape(ctx, game.Title, "")
+	if error != nil {
+		fail("批評空間の検索に失敗しました", error)
+		return
+	}
+	candidates := scoreErogameScapeCandidates(game.Title, found.Items)
+	if len(candidates) == 0 {
+		result.NotFound = append(result.NotFound, gameID)
+		return
+	}
+	best := candidates[0]
+	if !isConfidentMatch(candidates) {
+		if len(candidates) > erogameScapeMatchMaxCandidates {
+			candidates = candidates[:erogameScapeMatchMaxCandidates]
+		}
+		result.Ambiguous = append(result.Ambiguous, domain.ErogameScapeMatchAmbiguous{GameID: gameID, Title: game.Title, Candidates: candidates})
+		return
+	}
+	if _, error := service.apply(ctx, game, best.GameURL); error != nil {
+		fail("批評空間の情報の反映に失敗しました", error)
+		return
+	}
+	result.Applied = append(result.Applied, domain.ErogameScapeMatchApplied{
+		GameID:         gameID,
+		ErogameScapeID: best.ErogameScapeID,
+		Title:          best.Title,
+		Score:          best.Score,
+	})
+}
+
+// ApplyMatch は手動で選んだ候補（批評空間のゲームページ）を取り込み、ゲームに反映する。
+func (service *ErogameScapeMatchService) ApplyMatch(ctx context.Context, gameID string, gamePageURL string) (*domain.Game, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	game, error := service.repository.GetGameByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if game == nil {
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+	updated, error := service.apply(ctx, game, strings.TrimSpace(gamePageURL))
+	if error != nil {
+		service.logger.Error("批評空間の情報の反映に失敗", "gameId", trimmedID, "error", error)
+		return nil, newServiceError("批評空間の情報の反映に失敗しました", error.Error())
+	}
+	return updated, nil
+}
+
+// apply は批評空間のタイトルとブランドをゲームに反映する。
+// 画像はゲームに未設定の場合だけ取り込んだカバーを使い、利用者が選んだ画像は置き換えない。
+func (service *ErogameScapeMatchService) apply(ctx context.Context, game *domain.Game, gamePageURL string) (*domain.Game, error) {
+	imported, error := service.client.FetchFromErogameScape(ctx, gamePageURL)
+	if error != nil {
+		return nil, error
+	}
+	next := *game
+	next.Title = imported.Title
+	next.Publisher = imported.Brand
+	if (next.ImagePath == nil || strings.TrimSpace(*next.ImagePath) == "") && imported.ImagePath != "" {
+		imagePath := imported.ImagePath
+		next.ImagePath = &imagePath
+	}
+	if error := gameDetailsFromImport(next, imported).applyTo(&next); error != nil {
+		// 読み取れない作品情報は反映しないだけで、タイトル等の反映は続ける。
+		service.logger.Warn("批評空間の作品情報の一部を反映できません", "gameId", game.ID, "error", error)
+	}
+	updated, error := service.repository.UpdateGame(ctx, next)
+	if error != nil {
+		return nil, error
+	}
+	service.logger.Info("批評空間の情報を反映", "gameId", game.ID, "erogameScapeId", imported.ErogameScapeID, "title", imported.Title)
+	return updated, nil
+}
+
+// isConfidentMatch は最上位の候補を自動で反映してよいかを返す（類似度が高く、次点と十分に差がある）。
+func isConfidentMatch(candidates []domain.ErogameScapeMatchCandidate) bool {
+	if len(candidates) == 0 || candidates[0].Score < erogameScapeAutoMatchScore {
+		return false
+	}
+	return len(candidates) == 1 || candidates[0].Score-candidates[1].Score >= erogameScapeAutoMatchMargin
+}
+
+// scoreErogameScapeCandidates は検索結果を title との類似度で採点し、高い順に並べる。
+func scoreErogameScapeCandidates(title string, items []domain.ErogameScapeSearchItem) []domain.ErogameScapeMatchCandidate {
+	candidates := make([]domain.ErogameScapeMatchCandidate, 0, len(items))
+	for _, item := range items {
+		candidates = append(candidates, domain.ErogameScapeMatchCandidate{
+			ErogameScapeID: item.ErogameScapeID,
+			Title:          item.Title,
+			Brand:          item.Brand,
+			GameURL:        item.GameURL,
+			Score:          titleSimilarity(title, item.Title),
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates
+}
+
+// titleSimilarity は表記ゆれ（全角・半角、大文字・小文字、空白と記号）を除いたタイトルの編集距離から類似度（0〜1）を返す。
+func titleSimilarity(left string, right string) float64 {
+	a := normalizeMatchTitle(left)
+	b := normalizeMatchTitle(right)
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	longest := max(len(a), len(b))
+	return 1 - float64(levenshteinDistance(a, b))/float64(longest)
+}
+
+func normalizeMatchTitle(title string) []rune {
+	normalized := make([]rune, 0, len(title))
+	for _, char := range title {
+		// 全角英数字・記号（！〜～）を半角にそろえる。
+		if char >= '！' && char <= '～' {
+			char -= 0xFEE0
+		}
+		if unicode.IsSpace(char) || unicode.IsPunct(char) || unicode.IsSymbol(char) {
+			continue
+		}
+		normalized = append(normalized, unicode.ToLower(char))
+	}
+	return normalized
+}
+
+func levenshteinDistance(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// uniqueTrimmedIDs は空の ID と重複を除き、指定順を保って返す。
+func uniqueTrimmedIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		trimmed := strings.TrimSpace(id)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		unique = append(unique, trimmed)
+	}
+	return unique
+}
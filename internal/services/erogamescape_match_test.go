@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+type fakeErogameScapeClient struct {
+	searchResults map[string][]domain.ErogameScapeSearchItem
+	imports       map[string]domain.GameImport
+	fetched       []string
+}
+
+func (client *fakeErogameScapeClient) SearchErogameScape(_ context.Context, query string, _ string) (domain.ErogameScapeSearchResult, error) {
+	items, ok := client.searchResults[query]
+	if !ok {
+		return domain.ErogameScapeSearchResult{}, errors.New("search failed")
+	}
+	return domain.ErogameScapeSearchResult{Items: items}, nil
+}
+
+func (client *fakeErogameScapeClient) FetchFromErogameScape(_ context.Context, gamePageURL string) (domain.GameImport, error) {
+	client.fetched = append(client.fetched, gamePageURL)
+	return client.imports[gamePageURL], nil
+}
+
+func TestErogameScapeMatchServiceAppliesConfidentMatches(t *testing.T) {
+	t.Parallel()
+
+	existingImage := "/images/custom.png"
+	games := map[string]*domain.Game{
+		"g1": {ID: "g1", Title: "ＷＨＩＴＥ　ＡＬＢＵＭ２"},
+		"g2": {ID: "g2", Title: "サクラノ", ImagePath: &existingImage},
+		"g3": {ID: "g3", Title: "存在しない作品"},
+		"g4": {ID: "g4", Title: "検索失敗"},
+	}
+	updated := map[string]domain.Game{}
+	repository := fakeGameRepository{
+		getGameByIDFn: func(_ context.Context, gameID string) (*domain.Game, error) {
+			return games[gameID], nil
+		},
+		updateGameFn: func(_ context.Context, game domain.Game) (*domain.Game, error) {
+			updated[game.ID] = game
+			return &game, nil
+		},
+	}
+	client := &fakeErogameScapeClient{
+		searchResults: map[string][]domain.ErogameScapeSearchItem{
+			"ＷＨＩＴＥ　ＡＬＢＵＭ２": {
+				{ErogameScapeID: "1", Title: "WHITE ALBUM2", GameURL: "https://example.com/game.php?game=1"},
+				{ErogameScapeID: "2", Title: "WHITE ALBUM2 EXTENDED EDITION", GameURL: "https://example.com/game.php?game=2"},
+			},
+			"サクラノ": {
+				{ErogameScapeID: "3", Title: "サクラノ詩", GameURL: "https://example.com/game.php?game=3"},
+				{ErogameScapeID: "4", Title: "サクラノ刻", GameURL: "https://example.com/game.php?game=4"},
+			},
+			"存在しない作品": {},
+		},
+		imports: map[string]domain.GameImport{
+			"https://example.com/game.php?game=1": {ErogameScapeID: "1", Title: "WHITE ALBUM2", Brand: "Leaf", ImagePath: "/thumbs/1.jpg"},
+		},
+	}
+	service := &ErogameScapeMatchService{client: client, repository: &repository, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	var progress []int
+	result, err := service.MatchGames(context.Background(), []string{"g1", " g2 ", "g3", "g4", "g1", ""}, func(current, _ int) {
+		progress = append(progress, current)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Applied) != 1 || result.Applied[0].GameID != "g1" || result.Applied[0].ErogameScapeID != "1" || result.Applied[0].Score != 1 {
+		t.Fatalf("unexpected applied: %+v", result.Applied)
+	}
+	if game := updated["g1"]; game.Title != "WHITE ALBUM2" || game.Publisher != "Leaf" || game.ImagePath == nil || *game.ImagePath != "/thumbs/1.jpg" {
+		t.Fatalf("unexpected updated game: %+v", game)
+	}
+	if len(result.Ambiguous) != 1 || result.Ambiguous[0].GameID != "g2" || len(result.Ambiguous[0].Candidates) != 2 {
+		t.Fatalf("unexpected ambiguous: %+v", result.Ambiguous)
+	}
+	if len(result.NotFound) != 1 || result.NotFound[0] != "g3" {
+		t.Fatalf("unexpected not found: %v", result.NotFound)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].GameID != "g4" {
+		t.Fatalf("unexpected failed: %+v", result.Failed)
+	}
+	if len(progress) != 5 || progress[4] != 4 {
+		t.Fatalf("unexpected progress: %v", progress)
+	}
+
+	client.imports["https://example.com/game.php?game=3"] = domain.GameImport{ErogameScapeID: "3", Title: "サクラノ詩", Brand: "枕", ImagePath: "/thumbs/3.jpg"}
+	if _, err := service.ApplyMatch(context.Background(), "g2", "https://example.com/game.php?game=3"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if game := updated["g2"]; game.Title != "サクラノ詩" || *game.ImagePath != existingImage {
+		t.Fatalf("expected custom image to be kept, got %+v", game)
+	}
+}
+
+func TestTitleSimilarityIgnoresNotationDifferences(t *testing.T) {
+	t.Parallel()
+
+	if got := titleSimilarity("Fate/stay night", "ｆａｔｅ／ｓｔａｙ　ｎｉｇｈｔ"); got != 1 {
+		t.Fatalf("expected identical titles, got %v", got)
+	}
+	if got := titleSimilarity("サクラノ詩", "サクラノ刻"); got >= erogameScapeAutoMatchScore {
+		t.Fatalf("expected different titles to score low, got %v", got)
+	}
+	if got := titleSimilarity("", "title"); got != 0 {
+		t.Fatalf("expected empty title to score 0, got %v", got)
+	}
+}
+
+func TestErogameScapeMatchServiceReturnsPartialResultsWhenCanceled(t *testing.T) {
+	t.Parallel()
+
+	games := map[string]*domain.Game{
+		"g1": {ID: "g1", Title: "存在しない作品"},
+		"g2": {ID: "g2", Title: "検索失敗"},
+		"g3": {ID: "g3", Title: "存在しない作品"},
+	}
+	repository := fakeGameRepository{
+		getGameByIDFn: func(_ context.Context, gameID string) (*domain.Game, error) {
+			return games[gameID], nil
+		},
+	}
+	client := &fakeErogameScapeClient{searchResults: map[string][]domain.ErogameScapeSearchItem{"存在しない作品": {}}}
+	service := &ErogameScapeMatchService{client: client, repository: &repository, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// 1件目の照合が終わったところでキャンセルし、残りは照合しない。
+	result, err := service.MatchGames(ctx, []string{"g1", "g2", "g3"}, func(current, _ int) {
+		if current == 1 {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("expected partial result without error, got %v", err)
+	}
+	if !result.Canceled || len(result.NotFound) != 1 || result.NotFound[0] != "g1" {
+		t.Fatalf("expected the first game's result with canceled flag, got %+v", result)
+	}
+	if len(result.Failed) != 0 {
+		t.Fatalf("expected games interrupted by the cancel not to be reported as failures, got %+v", result.Failed)
+	}
+}
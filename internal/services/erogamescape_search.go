@@ -33,7 +33,7 @@ func (service *ErogameScapeService) SearchErogameScape(
 		targetURL = erogameScapeSearchBaseURL + "?" + params.Encode()
 	}
 
-	html, error := service.fetchHTML(ctx, targetURL)
+	html, error := service.fetchCachedHTML(ctx, targetURL, "", service.cache, service.limiter)
 	if error != nil {
 		return domain.ErogameScapeSearchResult{}, error
 	}
@@ -2,10 +2,7 @@
 package services
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"image"
@@ -15,12 +12,11 @@ import (
 	"io"
 	"log/slog"
 	"mime"
-	"net/http"
 	"net/url"
-	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,26 +27,65 @@ import (
 	"golang.org/x/image/draw"
 )
 
-const erogameScapeShortEdgePx = 200
-
 var erogameScapeGameIDRegex = regexp.MustCompile(`game=(\d+)`)
 
+const (
+	// erogameScapeRequestInterval は批評空間へのリクエストの最小間隔。個人運営のサイトのため、
+	// ゲーム登録中に検索を繰り返してもアクセスが集中しないようにする。
+	erogameScapeRequestInterval = time.Second
+	// MaxErogameScapeCacheTTLMinutes は批評空間のページキャッシュの有効期間（分）の上限（7日）。
+	MaxErogameScapeCacheTTLMinutes = 7 * 24 * 60
+)
+
+// ValidateErogameScapeCacheTTL は批評空間のページキャッシュの有効期間（分）が許容範囲かを検証する。0 はキャッシュしない。
+func ValidateErogameScapeCacheTTL(minutes int) error {
+	if minutes < 0 || minutes > MaxErogameScapeCacheTTLMinutes {
+		return fmt.Errorf("erogameScapeCacheTtlMinutes must be 0-%d", MaxErogameScapeCacheTTLMinutes)
+	}
+	return nil
+}
+
 // ErogameScapeService は批評空間から情報を取得する。
+// HTTP クライアントとサムネイル設定は metadataScraper に持たせ、DLSite・DMM の取り込みと共有する。
+// 検索結果とゲームページは cache に保存し、サイトへのリクエストは limiter で間隔を空ける。
 type ErogameScapeService struct {
-	appDataDir string
-	logger     *slog.Logger
-	httpClient *http.Client
+	*metadataScraper
+	cache   *pageCache
+	limiter *requestLimiter
 }
 
 // NewErogameScapeService は ErogameScapeService を生成する。
 func NewErogameScapeService(cfg config.Config, logger *slog.Logger) *ErogameScapeService {
 	return &ErogameScapeService{
-		appDataDir: cfg.AppDataDir,
-		logger:     logger,
-		httpClient: &http.Client{Timeout: 15 * time.Second},
+		metadataScraper: newMetadataScraper(cfg, logger),
+		cache:           newPageCache(filepath.Join(cfg.AppDataDir, pageCacheDirName), erogameScapeCacheTTL(cfg.ErogameScapeCacheTTLMinutes)),
+		limiter:         newRequestLimiter(erogameScapeRequestInterval),
 	}
 }
 
+// SetCacheTTL はページキャッシュの有効期間（分）を更新する。0 で以降はキャッシュを使わない。
+func (service *ErogameScapeService) SetCacheTTL(minutes int) {
+	service.cache.SetTTL(erogameScapeCacheTTL(minutes))
+}
+
+// PurgeCache は保存済みのページキャッシュをすべて削除し、削除した件数を返す。
+func (service *ErogameScapeService) PurgeCache() (int, error) {
+	removed, error := service.cache.Purge()
+	if error != nil {
+		service.logger.Error("批評空間のキャッシュ削除に失敗", "error", error)
+		return removed, newServiceError("キャッシュの削除に失敗しました", error.Error())
+	}
+	service.logger.Info("批評空間のキャッシュを削除", "removed", removed)
+	return removed, nil
+}
+
+func erogameScapeCacheTTL(minutes int) time.Duration {
+	if ValidateErogameScapeCacheTTL(minutes) != nil {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
 // FetchFromErogameScape は批評空間のURLからゲーム情報を取得する。
 func (service *ErogameScapeService) FetchFromErogameScape(ctx context.Context, gamePageURL string) (domain.GameImport, error) {
 	gameID, error := extractErogameScapeID(gamePageURL)
@@ -58,7 +93,7 @@ func (service *ErogameScapeService) FetchFromErogameScape(ctx context.Context, g
 		return domain.GameImport{}, error
 	}
 
-	pageHTML, error := service.fetchHTML(ctx, gamePageURL)
+	pageHTML, error := service.fetchCachedHTML(ctx, gamePageURL, "", service.cache, service.limiter)
 	if error != nil {
 		return domain.GameImport{}, error
 	}
@@ -88,6 +123,9 @@ func (service *ErogameScapeService) FetchFromErogameScape(ctx context.Context, g
 		return domain.GameImport{}, ParseError{Field: "imageUrl", Err: error}
 	}
 
+	if error := service.limiter.Wait(ctx); error != nil {
+		return domain.GameImport{}, ImageError{URL: imageURL, Err: error}
+	}
 	imagePath, error := service.downloadAndSaveImage(ctx, imageURL, gameID)
 	if error != nil {
 		return domain.GameImport{}, error
@@ -95,137 +133,68 @@ func (service *ErogameScapeService) FetchFromErogameScape(ctx context.Context, g
 
 	return domain.GameImport{
 		ErogameScapeID: gameID,
+		Provider:       MetadataProviderErogameScape,
+		SourceID:       gameID,
 		Title:          title,
 		Brand:          brand,
 		ImagePath:      imagePath,
 		ImageURL:       imageURL,
+		ReleaseDate:    parseReleaseDate(doc.Find("#sellday > td").First().Text()),
+		Genres:         erogameScapeGenres(doc),
+		Score:          erogameScapeMedian(doc),
 	}, nil
 }
 
-func extractErogameScapeID(gamePageURL string) (string, error) {
-	matches := erogameScapeGameIDRegex.FindStringSubmatch(gamePageURL)
-	if len(matches) < 2 {
-		return "", InvalidUrlError{URL: gamePageURL}
-	}
-	return matches[1], nil
-}
-
-func (service *ErogameScapeService) fetchHTML(ctx context.Context, gamePageURL string) (string, error) {
-	html, error := service.fetchHTMLOnce(ctx, gamePageURL)
-	if error == nil {
-		return html, nil
-	}
-
-	parsed, parseErr := url.Parse(gamePageURL)
-	if parseErr != nil {
-		return "", error
+// erogameScapeGenres はジャンル欄（無い作品もある）の表記をジャンルの一覧にする。
+func erogameScapeGenres(doc *goquery.Document) []string {
+	text := collapseSpaces(doc.Find("#genre > td").First().Text())
+	if text == "" {
+		return nil
 	}
-	if strings.EqualFold(parsed.Scheme, "https") {
-		parsed.Scheme = "http"
-		fallbackURL := parsed.String()
-		fallbackHTML, fallbackErr := service.fetchHTMLOnce(ctx, fallbackURL)
-		if fallbackErr == nil {
-			return fallbackHTML, nil
+	var genres []string
+	for _, genre := range strings.FieldsFunc(text, func(r rune) bool { return r == '、' || r == ',' || r == '／' || r == '/' }) {
+		if trimmed := strings.TrimSpace(genre); trimmed != "" {
+			genres = append(genres, trimmed)
 		}
 	}
-	return "", error
+	return genres
 }
 
-func (service *ErogameScapeService) fetchHTMLOnce(ctx context.Context, gamePageURL string) (string, error) {
-	request, error := http.NewRequestWithContext(ctx, http.MethodGet, gamePageURL, nil)
-	if error != nil {
-		return "", FetchError{URL: gamePageURL, Err: error}
-	}
-	request.Header.Set("User-Agent", "CloudLaunch/1.0")
-
-	response, error := service.httpClient.Do(request)
-	if error != nil {
-		return "", FetchError{URL: gamePageURL, Err: error}
-	}
-	defer func() {
-		if closeErr := response.Body.Close(); closeErr != nil {
-			service.logger.Warn("批評空間HTMLレスポンスのクローズに失敗", "error", closeErr)
-		}
-	}()
-
-	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
-		return "", FetchError{URL: gamePageURL, StatusCode: response.StatusCode, Err: errors.New(response.Status)}
-	}
-
-	body, error := io.ReadAll(response.Body)
-	if error != nil {
-		return "", FetchError{URL: gamePageURL, Err: error}
+// erogameScapeMedian は得点の中央値（0〜100）を返す。得点が付いていなければ 0 を返す。
+func erogameScapeMedian(doc *goquery.Document) int {
+	median, error := strconv.Atoi(strings.TrimSpace(doc.Find("#median > td").First().Text()))
+	if error != nil || median < 0 || median > MaxGameRating {
+		return 0
 	}
-	return string(body), nil
+	return median
 }
 
-func (service *ErogameScapeService) downloadAndSaveImage(ctx context.Context, imageURL string, gameID string) (string, error) {
-	request, error := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
-	if error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
-	}
-	request.Header.Set("User-Agent", "CloudLaunch/1.0")
-
-	response, error := service.httpClient.Do(request)
-	if error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
-	}
-	defer func() {
-		if closeErr := response.Body.Close(); closeErr != nil {
-			service.logger.Warn("批評空間画像レスポンスのクローズに失敗", "error", closeErr)
-		}
-	}()
-
-	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
-		return "", ImageError{URL: imageURL, Err: errors.New(response.Status)}
-	}
-
-	raw, error := io.ReadAll(response.Body)
-	if error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
-	}
-
-	decoded, format, error := image.Decode(bytes.NewReader(raw))
-	if error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
-	}
-
-	resized := resizeToShortEdge(decoded, erogameScapeShortEdgePx)
-	ext := chooseImageExtension(imageURL, response.Header.Get("Content-Type"), format)
-	if ext == "" {
-		ext = ".jpg"
-	}
-
-	var encoded bytes.Buffer
-	if error := encodeImage(&encoded, resized, ext); error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
-	}
-	hash := sha256.Sum256(encoded.Bytes())
-	hashHex := hex.EncodeToString(hash[:])
+// ID は取り込み元の ID を返す（MetadataProvider の実装）。
+func (service *ErogameScapeService) ID() string {
+	return MetadataProviderErogameScape
+}
 
-	targetDir := filepath.Join(service.appDataDir, "thumbnails")
-	if error := os.MkdirAll(targetDir, 0o700); error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
-	}
+// Name は取り込み元の名前を返す。
+func (service *ErogameScapeService) Name() string {
+	return "批評空間"
+}
 
-	filename := fmt.Sprintf("%s_%s%s", hashHex, gameID, ext)
-	fullPath := filepath.Join(targetDir, filename)
+// Matches は批評空間のゲームページの URL かを返す。
+func (service *ErogameScapeService) Matches(pageURL string) bool {
+	return strings.Contains(strings.ToLower(pageURL), "erogamescape") && erogameScapeGameIDRegex.MatchString(pageURL)
+}
 
-	file, error := os.Create(fullPath)
-	if error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
-	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
-			service.logger.Warn("サムネイル保存後のファイルクローズに失敗", "error", closeErr)
-		}
-	}()
+// Fetch は FetchFromErogameScape と同じ。
+func (service *ErogameScapeService) Fetch(ctx context.Context, pageURL string) (domain.GameImport, error) {
+	return service.FetchFromErogameScape(ctx, pageURL)
+}
 
-	if _, error := encoded.WriteTo(file); error != nil {
-		return "", ImageError{URL: imageURL, Err: error}
+func extractErogameScapeID(gamePageURL string) (string, error) {
+	matches := erogameScapeGameIDRegex.FindStringSubmatch(gamePageURL)
+	if len(matches) < 2 {
+		return "", InvalidUrlError{URL: gamePageURL}
 	}
-
-	return fullPath, nil
+	return matches[1], nil
 }
 
 func resizeToShortEdge(source image.Image, shortEdge int) image.Image {
@@ -0,0 +1,193 @@
+// サービス間で出来事を知らせ合うイベントバスを提供する。
+package services
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// EventTopic はイベントの種類。
+type EventTopic string
+
+// イベントの種類。ペイロードの型は各定数のコメントのとおり。
+const (
+	// EventGameUpdated はゲームの作成・更新・削除。ペイロードは GameUpdatedEvent。
+	EventGameUpdated EventTopic = "game.updated"
+	// EventSessionCreated はプレイセッションの記録（自動計測・手動追加）。ペイロードは SessionCreatedEvent。
+	EventSessionCreated EventTopic = "session.created"
+	// EventSessionEnded はプロセス監視による計測の終了（日付で分けたセッションの保存後に1回）。ペイロードは SessionEndedEvent。
+	EventSessionEnded EventTopic = "session.ended"
+	// EventScreenshotCaptured はスクリーンショットの保存。ペイロードは domain.CaptureResult。
+	EventScreenshotCaptured EventTopic = "screenshot.captured"
+	// EventSyncCompleted はゲーム1件のクラウド同期（Push / Pull）の終了。失敗も含む。ペイロードは SyncCompletedEvent。
+	EventSyncCompleted EventTopic = "sync.completed"
+)
+
+// GameUpdatedEvent は EventGameUpdated のペイロード。削除時の Game は nil。
+type GameUpdatedEvent struct {
+	GameID  string       `json:"gameId"`
+	Game    *domain.Game `json:"game,omitempty"`
+	Deleted bool         `json:"deleted"`
+	// Cleared はこの更新で未クリアからクリア済みになったか。
+	Cleared bool `json:"cleared"`
+}
+
+// SessionCreatedEvent は EventSessionCreated のペイロード。
+type SessionCreatedEvent struct {
+	Session domain.PlaySession `json:"session"`
+	// Auto はプロセス監視による自動計測か（false なら手動追加）。
+	Auto bool `json:"auto"`
+}
+
+// SessionEndedEvent は EventSessionEnded のペイロード。
+type SessionEndedEvent struct {
+	GameID string `json:"gameId"`
+	// Duration は計測したプレイ時間（秒）の合計。
+	Duration int64     `json:"duration"`
+	EndedAt  time.Time `json:"endedAt"`
+}
+
+// SyncCompletedEvent は EventSyncCompleted のペイロード。Direction は OperationPush か OperationPull。
+type SyncCompletedEvent struct {
+	GameID    string `json:"gameId"`
+	Direction string `json:"direction"`
+	// Applied は Pull でローカルに反映したか（Push では成功時に常に true）。
+	Applied bool `json:"applied"`
+	// Error は失敗した場合のエラー内容（成功時は空）。
+	Error string `json:"error,omitempty"`
+}
+
+// Event はバスを流れるイベント。
+type Event struct {
+	Topic   EventTopic `json:"topic"`
+	Payload any        `json:"payload"`
+	At      time.Time  `json:"at"`
+}
+
+type eventSubscriber struct {
+	id    uint64
+	topic EventTopic // 空なら全イベント
+	fn    func(Event)
+}
+
+// EventBus はサービスが発行したイベントを購読者へ配る。発行側は購読者を知らずに済み、
+// プレイ後のアップロードや通知などの反応を、サービス同士の直接の依存を増やさずに足せる。
+//
+// 配信は専用の goroutine で発行順に1件ずつ行い、Publish は購読者を待たずに返る。
+// ロックを保持したまま発行しても、購読者が発行元のサービスを呼び返してデッドロックすることはない。
+// nil の EventBus でも Publish・Subscribe は使え、その場合は何もしない。
+type EventBus struct {
+	mu          sync.Mutex
+	logger      *slog.Logger
+	now         func() time.Time
+	seq         uint64
+	subscribers []eventSubscriber
+	queue       []Event
+	working     bool
+	idle        *sync.Cond
+}
+
+// NewEventBus は EventBus を生成する。
+func NewEventBus(logger *slog.Logger) *EventBus {
+	bus := &EventBus{logger: logger, now: time.Now}
+	bus.idle = sync.NewCond(&bus.mu)
+	return bus
+}
+
+// Subscribe は topic のイベントを受け取る fn を登録し、登録を解除する関数を返す。
+// fn は登録順に、イベントの発行順で呼ばれる。重い処理は fn の中で goroutine に移すこと（後続のイベントが遅れる）。
+func (bus *EventBus) Subscribe(topic EventTopic, fn func(Event)) (unsubscribe func()) {
+	return bus.subscribe(topic, fn)
+}
+
+// SubscribeAll はすべてのイベントを受け取る fn を登録する。フロントエンドへの中継などに使う。
+func (bus *EventBus) SubscribeAll(fn func(Event)) (unsubscribe func()) {
+	return bus.subscribe("", fn)
+}
+
+func (bus *EventBus) subscribe(topic EventTopic, fn func(Event)) func() {
+	if bus == nil || fn == nil {
+		return func() {}
+	}
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.seq++
+	id := bus.seq
+	bus.subscribers = append(bus.subscribers, eventSubscriber{id: id, topic: topic, fn: fn})
+	return func() {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		for index, subscriber := range bus.subscribers {
+			if subscriber.id == id {
+				bus.subscribers = append(bus.subscribers[:index:index], bus.subscribers[index+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish は topic のイベントを発行する。配信を待たずに返る。
+func (bus *EventBus) Publish(topic EventTopic, payload any) {
+	if bus == nil {
+		return
+	}
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.queue = append(bus.queue, Event{Topic: topic, Payload: payload, At: bus.now()})
+	if bus.working {
+		return
+	}
+	bus.working = true
+	go bus.dispatch()
+}
+
+// Wait は発行済みのイベントをすべて配り終えるまで待つ（終了処理やテスト用）。
+func (bus *EventBus) Wait() {
+	if bus == nil {
+		return
+	}
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	for bus.working {
+		bus.idle.Wait()
+	}
+}
+
+// dispatch はキューのイベントを発行順に配り、キューが空になったら終了する。
+func (bus *EventBus) dispatch() {
+	for {
+		bus.mu.Lock()
+		if len(bus.queue) == 0 {
+			bus.working = false
+			bus.idle.Broadcast()
+			bus.mu.Unlock()
+			return
+		}
+		event := bus.queue[0]
+		bus.queue = bus.queue[1:]
+		subscribers := make([]eventSubscriber, 0, len(bus.subscribers))
+		for _, subscriber := range bus.subscribers {
+			if subscriber.topic == "" || subscriber.topic == event.Topic {
+				subscribers = append(subscribers, subscriber)
+			}
+		}
+		bus.mu.Unlock()
+
+		for _, subscriber := range subscribers {
+			bus.deliver(subscriber, event)
+		}
+	}
+}
+
+// deliver は購読者1件にイベントを渡す。panic は回収して記録し、他の購読者への配信を続ける。
+func (bus *EventBus) deliver(subscriber eventSubscriber, event Event) {
+	defer func() {
+		if recovered := recover(); recovered != nil && bus.logger != nil {
+			bus.logger.Error("イベントの購読者で panic を回収", "topic", event.Topic, "recovered", recovered)
+		}
+	}()
+	subscriber.fn(event)
+}
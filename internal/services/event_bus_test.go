@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestEventBusDeliversInPublishOrderByTopic(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus(newTestLogger())
+	var mu sync.Mutex
+	var games []string
+	var all []EventTopic
+	bus.Subscribe(EventGameUpdated, func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		games = append(games, event.Payload.(GameUpdatedEvent).GameID)
+	})
+	bus.SubscribeAll(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		all = append(all, event.Topic)
+	})
+
+	for _, id := range []string{"game-1", "game-2", "game-3"} {
+		bus.Publish(EventGameUpdated, GameUpdatedEvent{GameID: id})
+	}
+	bus.Publish(EventSyncCompleted, SyncCompletedEvent{GameID: "game-1", Direction: OperationPush})
+	bus.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(games) != 3 || games[0] != "game-1" || games[1] != "game-2" || games[2] != "game-3" {
+		t.Fatalf("game.updated deliveries = %v", games)
+	}
+	if len(all) != 4 || all[3] != EventSyncCompleted {
+		t.Fatalf("all deliveries = %v", all)
+	}
+}
+
+func TestEventBusUnsubscribeAndPanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus(newTestLogger())
+	var removedCalls, survivorCalls int
+	unsubscribe := bus.Subscribe(EventSessionCreated, func(Event) { removedCalls++ })
+	bus.Subscribe(EventSessionCreated, func(Event) { panic("boom") })
+	bus.Subscribe(EventSessionCreated, func(Event) { survivorCalls++ })
+
+	bus.Publish(EventSessionCreated, SessionCreatedEvent{})
+	bus.Wait()
+	unsubscribe()
+	bus.Publish(EventSessionCreated, SessionCreatedEvent{})
+	bus.Wait()
+
+	if removedCalls != 1 || survivorCalls != 2 {
+		t.Fatalf("removed = %d, survivor = %d", removedCalls, survivorCalls)
+	}
+}
+
+func TestEventBusPublishDoesNotWaitForSubscribers(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus(newTestLogger())
+	var mu sync.Mutex
+	delivered := make(chan struct{})
+	// 購読者が発行元と同じロックを取っても、ロックを保持したままの Publish がデッドロックしない。
+	bus.Subscribe(EventScreenshotCaptured, func(Event) {
+		mu.Lock()
+		mu.Unlock()
+		close(delivered)
+	})
+	mu.Lock()
+	bus.Publish(EventScreenshotCaptured, domain.CaptureResult{Path: "shot.png"})
+	mu.Unlock()
+	<-delivered
+
+	var nilBus *EventBus
+	nilBus.Publish(EventGameUpdated, GameUpdatedEvent{})
+	nilBus.Subscribe(EventGameUpdated, func(Event) {})()
+	nilBus.Wait()
+}
+
+func TestGameServicePublishesGameUpdated(t *testing.T) {
+	t.Parallel()
+
+	game := domain.Game{ID: "game-1", Title: "Game"}
+	service := NewGameService(&fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			copied := game
+			return &copied, nil
+		},
+		updateGameFn: func(ctx context.Context, updated domain.Game) (*domain.Game, error) { return &updated, nil },
+		deleteGameFn: func(ctx context.Context, gameID string) error { return nil },
+	}, newTestLogger())
+	bus := NewEventBus(newTestLogger())
+	var events []GameUpdatedEvent
+	bus.Subscribe(EventGameUpdated, func(event Event) { events = append(events, event.Payload.(GameUpdatedEvent)) })
+	service.SetEventBus(bus)
+
+	if _, err := service.ToggleFavorite(context.Background(), "game-1"); err != nil {
+		t.Fatalf("ToggleFavorite: %v", err)
+	}
+	if err := service.DeleteGame(context.Background(), "game-1"); err != nil {
+		t.Fatalf("DeleteGame: %v", err)
+	}
+	bus.Wait()
+
+	if len(events) != 2 || events[0].Game == nil || !events[0].Game.IsFavorite || events[0].Deleted {
+		t.Fatalf("unexpected update event: %+v", events)
+	}
+	if events[1].GameID != "game-1" || !events[1].Deleted || events[1].Game != nil {
+		t.Fatalf("unexpected delete event: %+v", events[1])
+	}
+}
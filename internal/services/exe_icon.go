@@ -0,0 +1,71 @@
+// ゲームの実行ファイルのアイコンを取り出し、カバー画像が無いゲームの代わりのカバー画像にする。
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// SetGameCoverFromExeIcon はゲームの実行ファイルに含まれる最大のアイコンをカバー画像に設定する（Windows のみ）。
+// アイコンは PNG にして手動設定のカバー画像と同様に原寸画像も残すため、サムネイルの再生成でも描き直せる。
+func (service *ThumbnailService) SetGameCoverFromExeIcon(ctx context.Context, gameID string, shortEdge int) (*domain.Game, error) {
+	gameID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	game, err := service.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		service.logger.Error("ゲーム取得に失敗", "gameId", gameID, "error", err)
+		return nil, newServiceError("ゲームの取得に失敗しました", err.Error())
+	}
+	if game == nil {
+		return nil, newServiceError("ゲームが見つかりません", gameID)
+	}
+	exePath := strings.TrimSpace(game.ExePath)
+	if exePath == "" {
+		return nil, newServiceError("実行ファイルが不正です", "exePath is empty")
+	}
+	icon, err := extractExeIcon(exePath)
+	if err != nil {
+		service.logger.Warn("実行ファイルのアイコンを取得できません", "gameId", gameID, "path", exePath, "error", err)
+		return nil, newServiceError("実行ファイルのアイコンを取得できませんでした", err.Error())
+	}
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, icon); err != nil {
+		return nil, newServiceError("実行ファイルのアイコンを取得できませんでした", err.Error())
+	}
+	return service.setGameCover(ctx, gameID, encoded.Bytes(), shortEdge)
+}
+
+// iconPixelsToImage は GetDIBits で得た 32bit BGRA（上から下の順）の画素をアルファ付きの画像にする。
+// 古い形式のアイコンはアルファが全て 0 のため、その場合は AND マスク（黒が不透明）から透明度を決める。
+// mask が nil なら不透明として扱う。
+func iconPixelsToImage(color []byte, mask []byte, width, height int) *image.NRGBA {
+	picture := image.NewNRGBA(image.Rect(0, 0, width, height))
+	hasAlpha := false
+	for i := 3; i < len(color); i += 4 {
+		if color[i] != 0 {
+			hasAlpha = true
+			break
+		}
+	}
+	for i := 0; i+3 < len(color) && i+3 < len(picture.Pix); i += 4 {
+		alpha := color[i+3]
+		if !hasAlpha {
+			alpha = 0xff
+			if mask != nil && i+2 < len(mask) && mask[i]|mask[i+1]|mask[i+2] != 0 {
+				alpha = 0
+			}
+		}
+		picture.Pix[i] = color[i+2]
+		picture.Pix[i+1] = color[i+1]
+		picture.Pix[i+2] = color[i]
+		picture.Pix[i+3] = alpha
+	}
+	return picture
+}
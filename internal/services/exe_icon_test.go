@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestIconPixelsToImageUsesAlphaChannel(t *testing.T) {
+	t.Parallel()
+
+	// BGRA の2画素（半透明の赤・不透明の青）。マスクはアルファがあれば使わない。
+	color := []byte{0, 0, 255, 128, 255, 0, 0, 255}
+	mask := []byte{255, 255, 255, 0, 255, 255, 255, 0}
+	picture := iconPixelsToImage(color, mask, 2, 1)
+	if got := picture.Pix[0:4]; got[0] != 255 || got[2] != 0 || got[3] != 128 {
+		t.Fatalf("unexpected first pixel: %v", got)
+	}
+	if got := picture.Pix[4:8]; got[0] != 0 || got[2] != 255 || got[3] != 255 {
+		t.Fatalf("unexpected second pixel: %v", got)
+	}
+}
+
+func TestIconPixelsToImageFallsBackToMask(t *testing.T) {
+	t.Parallel()
+
+	color := []byte{10, 20, 30, 0, 40, 50, 60, 0}
+	// 白は透明、黒は不透明。
+	mask := []byte{255, 255, 255, 0, 0, 0, 0, 0}
+	picture := iconPixelsToImage(color, mask, 2, 1)
+	if picture.Pix[3] != 0 || picture.Pix[7] != 255 {
+		t.Fatalf("mask should decide alpha: %v", picture.Pix)
+	}
+	if opaque := iconPixelsToImage(color, nil, 2, 1); opaque.Pix[3] != 255 || opaque.Pix[7] != 255 {
+		t.Fatalf("missing mask should be opaque: %v", opaque.Pix)
+	}
+}
+
+func TestThumbnailServiceSetGameCoverFromExeIconRequiresExePath(t *testing.T) {
+	t.Parallel()
+
+	var updated domain.Game
+	service := newCoverTestService(t, t.TempDir(), &updated)
+	if _, err := service.SetGameCoverFromExeIcon(context.Background(), "game-1", 100); err == nil {
+		t.Fatal("game without exePath should fail")
+	}
+	if _, err := service.SetGameCoverFromExeIcon(context.Background(), "missing", 100); err == nil {
+		t.Fatal("missing game should fail")
+	}
+	if updated.ImagePath != nil {
+		t.Fatalf("imagePath should not change: %+v", updated)
+	}
+}
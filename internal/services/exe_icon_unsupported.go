@@ -0,0 +1,14 @@
+//go:build !windows
+
+// 非Windows向けの実行ファイルのアイコン取得のスタブ実装。
+package services
+
+import (
+	"errors"
+	"image"
+)
+
+// extractExeIcon は非Windowsではサポート外。
+func extractExeIcon(exePath string) (image.Image, error) {
+	return nil, errors.New("exe icon extraction is only supported on Windows")
+}
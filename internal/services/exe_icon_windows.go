@@ -0,0 +1,160 @@
+//go:build windows
+
+// Windows向けに実行ファイルのアイコンを取り出し、画像にする。
+package services
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// exeIconMaxSize は取り出すアイコンの一辺。Vista 以降のアイコンが持つ最大サイズ。
+	exeIconMaxSize = 256
+	dibRGBColors   = 0
+	biRGB          = 0
+)
+
+var (
+	gdi32dll                 = windows.NewLazySystemDLL("gdi32.dll")
+	procPrivateExtractIconsW = user32.NewProc("PrivateExtractIconsW")
+	procExtractIconExW       = shell32dll.NewProc("ExtractIconExW")
+	procDestroyIcon          = user32.NewProc("DestroyIcon")
+	procGetIconInfo          = user32.NewProc("GetIconInfo")
+	procCreateCompatibleDC   = gdi32dll.NewProc("CreateCompatibleDC")
+	procDeleteDC             = gdi32dll.NewProc("DeleteDC")
+	procDeleteObject         = gdi32dll.NewProc("DeleteObject")
+	procGetObjectW           = gdi32dll.NewProc("GetObjectW")
+	procGetDIBits            = gdi32dll.NewProc("GetDIBits")
+)
+
+type iconInfo struct {
+	FIcon    int32
+	XHotspot uint32
+	YHotspot uint32
+	HbmMask  uintptr
+	HbmColor uintptr
+}
+
+type bitmapObject struct {
+	BmType       int32
+	BmWidth      int32
+	BmHeight     int32
+	BmWidthBytes int32
+	BmPlanes     uint16
+	BmBitsPixel  uint16
+	BmBits       uintptr
+}
+
+type bitmapInfoHeader struct {
+	BiSize          uint32
+	BiWidth         int32
+	BiHeight        int32
+	BiPlanes        uint16
+	BiBitCount      uint16
+	BiCompression   uint32
+	BiSizeImage     uint32
+	BiXPelsPerMeter int32
+	BiYPelsPerMeter int32
+	BiClrUsed       uint32
+	BiClrImportant  uint32
+}
+
+// bitmapInfo は BITMAPINFO。32bit の BI_RGB では色テーブルを使わないが、構造体の大きさを合わせる。
+type bitmapInfo struct {
+	Header bitmapInfoHeader
+	Colors [1]uint32
+}
+
+// extractExeIcon は exePath の先頭のアイコンを、含まれる中で最も大きいサイズ（最大 256px）で取り出す。
+// ExtractIconEx は 32px の大きいアイコンまでしか返さないため、先に PrivateExtractIcons でサイズを指定して取り出し、
+// 取れなかったときだけ ExtractIconEx に戻る。
+func extractExeIcon(exePath string) (image.Image, error) {
+	path, err := windows.UTF16PtrFromString(exePath)
+	if err != nil {
+		return nil, err
+	}
+	var icon uintptr
+	var iconID uint32
+	count, _, _ := procPrivateExtractIconsW.Call(
+		uintptr(unsafe.Pointer(path)), 0, exeIconMaxSize, exeIconMaxSize,
+		uintptr(unsafe.Pointer(&icon)), uintptr(unsafe.Pointer(&iconID)), 1, 0,
+	)
+	if count == 0 || count == 0xffffffff || icon == 0 {
+		icon = 0
+		count, _, _ = procExtractIconExW.Call(uintptr(unsafe.Pointer(path)), 0, uintptr(unsafe.Pointer(&icon)), 0, 1)
+		if count == 0 || icon == 0 {
+			return nil, errors.New("executable has no icon")
+		}
+	}
+	defer procDestroyIcon.Call(icon)
+	return iconToImage(icon)
+}
+
+// iconToImage は HICON のカラービットマップとマスクを読み出して画像にする。
+func iconToImage(icon uintptr) (image.Image, error) {
+	var info iconInfo
+	if ret, _, callErr := procGetIconInfo.Call(icon, uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return nil, callErr
+	}
+	defer func() {
+		if info.HbmColor != 0 {
+			procDeleteObject.Call(info.HbmColor)
+		}
+		if info.HbmMask != 0 {
+			procDeleteObject.Call(info.HbmMask)
+		}
+	}()
+	if info.HbmColor == 0 {
+		return nil, errors.New("monochrome icon is not supported")
+	}
+	var bitmap bitmapObject
+	if ret, _, callErr := procGetObjectW.Call(info.HbmColor, unsafe.Sizeof(bitmap), uintptr(unsafe.Pointer(&bitmap))); ret == 0 {
+		return nil, callErr
+	}
+	width, height := int(bitmap.BmWidth), int(bitmap.BmHeight)
+	if width <= 0 || height <= 0 || width > exeIconMaxSize || height > exeIconMaxSize {
+		return nil, fmt.Errorf("unexpected icon size %dx%d", width, height)
+	}
+
+	dc, _, callErr := procCreateCompatibleDC.Call(0)
+	if dc == 0 {
+		return nil, callErr
+	}
+	defer procDeleteDC.Call(dc)
+	color, err := readBitmapBGRA(dc, info.HbmColor, width, height)
+	if err != nil {
+		return nil, err
+	}
+	var mask []byte
+	if info.HbmMask != 0 {
+		// マスクは古い形式のアイコンの透明度にだけ使うため、読めなくても不透明として続ける。
+		mask, _ = readBitmapBGRA(dc, info.HbmMask, width, height)
+	}
+	return iconPixelsToImage(color, mask, width, height), nil
+}
+
+// readBitmapBGRA はビットマップを上から下の順の 32bit BGRA として読み出す。
+func readBitmapBGRA(dc uintptr, bitmap uintptr, width, height int) ([]byte, error) {
+	info := bitmapInfo{Header: bitmapInfoHeader{
+		BiWidth:       int32(width),
+		BiHeight:      -int32(height),
+		BiPlanes:      1,
+		BiBitCount:    32,
+		BiCompression: biRGB,
+	}}
+	info.Header.BiSize = uint32(unsafe.Sizeof(info.Header))
+	pixels := make([]byte, width*height*4)
+	lines, _, callErr := procGetDIBits.Call(
+		dc, bitmap, 0, uintptr(height),
+		uintptr(unsafe.Pointer(&pixels[0])), uintptr(unsafe.Pointer(&info)), dibRGBColors,
+	)
+	if lines == 0 {
+		return nil, callErr
+	}
+	return pixels, nil
+}
@@ -14,3 +14,11 @@ func execCommandHidden(ctx context.Context, name string, args ...string) *exec.C
 	command.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
 	return command
 }
+
+// execShellHidden はコマンド文字列を cmd.exe で実行する。
+// 引数として渡すと Go のクォート規則で二重に囲まれるため、コマンドラインをそのまま組み立てて渡す。
+func execShellHidden(ctx context.Context, script string) *exec.Cmd {
+	command := exec.CommandContext(ctx, "cmd.exe")
+	command.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CmdLine: `cmd.exe /S /C "` + script + `"`}
+	return command
+}
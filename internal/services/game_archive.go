@@ -0,0 +1,71 @@
+// ゲームのアーカイブ（完了したゲームのデータ凍結）を提供する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// gameGetter はアーカイブ状態の確認に必要な最小の永続化境界。
+type gameGetter interface {
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
+}
+
+// ensureGameWritable はアーカイブ済みゲームへのセッション・メモの変更を拒否する。
+// ゲームが存在しない場合の扱いは呼び出し側の既存の検証に任せる。
+func ensureGameWritable(ctx context.Context, repository gameGetter, logger *slog.Logger, gameID string) error {
+	game, error := repository.GetGameByID(ctx, gameID)
+	if error != nil {
+		logger.Error("ゲーム取得に失敗", "error", error)
+		return newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if game != nil && game.IsArchived() {
+		logger.Warn("アーカイブ済みのゲームは変更できません", "gameId", gameID)
+		return newServiceError("アーカイブ済みのゲームは変更できません", "アーカイブを解除してから変更してください")
+	}
+	return nil
+}
+
+// ArchiveGame はゲームをアーカイブする。既にアーカイブ済みなら現在の状態をそのまま返す。
+func (service *GameService) ArchiveGame(ctx context.Context, gameID string) (*domain.Game, error) {
+	now := time.Now()
+	return service.setArchived(ctx, gameID, &now)
+}
+
+// UnarchiveGame はゲームのアーカイブを解除する。
+func (service *GameService) UnarchiveGame(ctx context.Context, gameID string) (*domain.Game, error) {
+	return service.setArchived(ctx, gameID, nil)
+}
+
+func (service *GameService) setArchived(ctx context.Context, gameID string, archivedAt *time.Time) (*domain.Game, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		service.logger.Warn("ゲームIDが不正です", "detail", detail, "gameId", gameID)
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+
+	current, error := service.repository.GetGameByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if current == nil {
+		service.logger.Warn("ゲームが見つかりません", "gameId", trimmedID)
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+	if current.IsArchived() == (archivedAt != nil) {
+		return current, nil
+	}
+
+	if error := service.repository.SetGameArchived(ctx, trimmedID, archivedAt); error != nil {
+		service.logger.Error("アーカイブ状態の更新に失敗", "error", error)
+		return nil, newServiceError("アーカイブ状態の更新に失敗しました", error.Error())
+	}
+	current.ArchivedAt = archivedAt
+	service.logger.Info("アーカイブ状態を更新", "gameId", trimmedID, "archived", archivedAt != nil)
+	service.publishGameUpdated(current)
+	return current, nil
+}
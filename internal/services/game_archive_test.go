@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/memo"
+)
+
+func TestGameServiceArchiveGameIsIdempotent(t *testing.T) {
+	t.Parallel()
+
+	archivedAt := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	game := &domain.Game{ID: "game-1", Title: "Title", ArchivedAt: &archivedAt}
+	repository := &fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			copied := *game
+			return &copied, nil
+		},
+	}
+	service := NewGameService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	archived, err := service.ArchiveGame(context.Background(), "game-1")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if repository.archiveCalls != 0 || !archived.ArchivedAt.Equal(archivedAt) {
+		t.Fatalf("already archived game should be returned as is: calls=%d game=%#v", repository.archiveCalls, archived)
+	}
+
+	restored, err := service.UnarchiveGame(context.Background(), "game-1")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if repository.archiveCalls != 1 || repository.archivedAt != nil || restored.IsArchived() {
+		t.Fatalf("expected unarchive to clear archivedAt: calls=%d game=%#v", repository.archiveCalls, restored)
+	}
+}
+
+func TestSessionServiceCreateSessionRejectsArchivedGame(t *testing.T) {
+	t.Parallel()
+
+	archivedAt := time.Now()
+	repository := &fakeSessionRepository{game: &domain.Game{ID: "game-1", ArchivedAt: &archivedAt}}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := service.CreateSession(context.Background(), SessionInput{
+		GameID:   "game-1",
+		PlayedAt: time.Now(),
+		Duration: 300,
+	})
+
+	if err == nil {
+		t.Fatalf("expected archived game to be rejected")
+	}
+	if repository.touchedGameID != "" {
+		t.Fatalf("archived game should not be updated")
+	}
+}
+
+func TestMemoServiceCreateMemoRejectsArchivedGame(t *testing.T) {
+	t.Parallel()
+
+	archivedAt := time.Now()
+	repository := &trackingMemoRepository{game: &domain.Game{ID: "game-1", ArchivedAt: &archivedAt}}
+	service := NewMemoService(repository, memo.NewFileManager(t.TempDir()), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := service.CreateMemo(context.Background(), MemoInput{
+		Title:   "Memo",
+		Content: "Body",
+		GameID:  "game-1",
+	})
+
+	if err == nil {
+		t.Fatalf("expected archived game to be rejected")
+	}
+	if repository.lastCreated != nil {
+		t.Fatalf("memo should not be created for archived game")
+	}
+}
+
+func TestScreenshotServiceCompressAndRestoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	appDataDir := t.TempDir()
+	gameDir := filepath.Join(appDataDir, "screenshots", "game-1")
+	if err := os.MkdirAll(filepath.Join(gameDir, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"a.png":     "first",
+		"sub/b.png": "second",
+	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(gameDir, filepath.FromSlash(rel)), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service := NewScreenshotService(config.Config{AppDataDir: appDataDir}, fakeScreenshotRepository{}, resolverReturning(), newTestLogger())
+
+	compressed, err := service.CompressGameScreenshots("game-1")
+	if err != nil {
+		t.Fatalf("CompressGameScreenshots: %v", err)
+	}
+	if compressed != len(files) {
+		t.Fatalf("compressed = %d, want %d", compressed, len(files))
+	}
+	if _, err := os.Stat(gameDir); !os.IsNotExist(err) {
+		t.Fatalf("source directory should be removed after compression, err=%v", err)
+	}
+
+	restored, err := service.RestoreGameScreenshots("game-1")
+	if err != nil {
+		t.Fatalf("RestoreGameScreenshots: %v", err)
+	}
+	if restored != len(files) {
+		t.Fatalf("restored = %d, want %d", restored, len(files))
+	}
+	for rel, content := range files {
+		data, err := os.ReadFile(filepath.Join(gameDir, filepath.FromSlash(rel)))
+		if err != nil || string(data) != content {
+			t.Fatalf("%s = %q (%v), want %q", rel, data, err, content)
+		}
+	}
+	if _, err := os.Stat(service.screenshotArchivePath("game-1")); !os.IsNotExist(err) {
+		t.Fatalf("zip should be removed after restore, err=%v", err)
+	}
+}
@@ -0,0 +1,131 @@
+// 任意の画像ファイル・クリップボードの画像からゲームのカバー画像を設定する機能を提供する。
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+
+	// 取り込み元では扱わない BMP（クリップボードの画像を含む）・WebP も読めるようにする。
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// maxCoverImageBytes はカバー画像として読み込む画像ファイルの上限サイズ。
+	maxCoverImageBytes = 32 << 20
+	// customCoverKeyPrefix は手動で設定したカバー画像の作品キーの接頭辞（"custom_<ゲームID>"）。
+	customCoverKeyPrefix = "custom_"
+)
+
+// SetGameCoverFromFile は画像ファイルを短辺 shortEdge のサムネイルにしてゲームのカバー画像に設定する。
+// 原寸画像も残すため、サムネイルサイズを変えたときは取り込んだ画像と同様に描き直せる。
+func (service *ThumbnailService) SetGameCoverFromFile(ctx context.Context, gameID string, filePath string, shortEdge int) (*domain.Game, error) {
+	trimmed := strings.TrimSpace(filePath)
+	if trimmed == "" {
+		return nil, newServiceError("画像ファイルが指定されていません", "path is empty")
+	}
+	raw, err := readCoverImageFile(trimmed)
+	if err != nil {
+		service.logger.Warn("カバー画像の読み込みに失敗", "gameId", gameID, "path", trimmed, "error", err)
+		return nil, newServiceError("画像ファイルを読み込めませんでした", err.Error())
+	}
+	return service.setGameCover(ctx, gameID, raw, shortEdge)
+}
+
+// SetGameCoverFromClipboard はクリップボードの画像をゲームのカバー画像に設定する（Windows のみ）。
+func (service *ThumbnailService) SetGameCoverFromClipboard(ctx context.Context, gameID string, shortEdge int) (*domain.Game, error) {
+	raw, err := readClipboardImage()
+	if err != nil {
+		service.logger.Warn("クリップボードの画像を取得できません", "gameId", gameID, "error", err)
+		return nil, newServiceError("クリップボードに画像がありません", err.Error())
+	}
+	return service.setGameCover(ctx, gameID, raw, shortEdge)
+}
+
+// setGameCover は raw をサムネイルと原寸画像として保存し、ゲームの imagePath を差し替える。
+// imagePath が変われば game.json の imageHash も変わるため、次回の同期で新しい画像がアップロードされる。
+func (service *ThumbnailService) setGameCover(ctx context.Context, gameID string, raw []byte, shortEdge int) (*domain.Game, error) {
+	gameID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	if err := ValidateThumbnailShortEdge(shortEdge); err != nil {
+		return nil, newServiceError("サムネイルサイズが不正です", err.Error())
+	}
+	game, err := service.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		service.logger.Error("ゲーム取得に失敗", "gameId", gameID, "error", err)
+		return nil, newServiceError("ゲームの取得に失敗しました", err.Error())
+	}
+	if game == nil {
+		return nil, newServiceError("ゲームが見つかりません", gameID)
+	}
+
+	original, ext, err := normalizeCoverImage(raw)
+	if err != nil {
+		service.logger.Warn("カバー画像を解釈できません", "gameId", gameID, "error", err)
+		return nil, newServiceError("画像として読み込めませんでした", err.Error())
+	}
+	thumbnailsDir := filepath.Join(service.appDataDir, thumbnailsDirName)
+	imageKey := customCoverKeyPrefix + strings.ReplaceAll(gameID, "-", "_")
+	if err := saveThumbnailOriginal(thumbnailsDir, imageKey, ext, original); err != nil {
+		service.logger.Error("カバー画像の保存に失敗", "gameId", gameID, "error", err)
+		return nil, newServiceError("カバー画像の保存に失敗しました", err.Error())
+	}
+	imagePath, err := renderThumbnail(thumbnailsDir, imageKey, ext, original, shortEdge)
+	if err != nil {
+		service.logger.Error("カバー画像の保存に失敗", "gameId", gameID, "error", err)
+		return nil, newServiceError("カバー画像の保存に失敗しました", err.Error())
+	}
+
+	game.ImagePath = &imagePath
+	updated, err := service.repository.UpdateGame(ctx, *game)
+	if err != nil {
+		service.logger.Error("カバー画像の設定に失敗", "gameId", gameID, "error", err)
+		return nil, newServiceError("カバー画像の設定に失敗しました", err.Error())
+	}
+	service.logger.Info("カバー画像を設定", "gameId", gameID, "path", imagePath)
+	return updated, nil
+}
+
+// readCoverImageFile は画像ファイルを maxCoverImageBytes まで読み込む。
+func readCoverImageFile(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	raw, err := io.ReadAll(io.LimitReader(file, maxCoverImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxCoverImageBytes {
+		return nil, fmt.Errorf("image must be at most %d bytes", maxCoverImageBytes)
+	}
+	return raw, nil
+}
+
+// normalizeCoverImage は raw を原寸画像として保存する形式と拡張子にする。
+// 拡張子はファイル名ではなく中身の形式で決め、JPEG・PNG・GIF はそのまま、それ以外（BMP・WebP）は PNG に変換する。
+func normalizeCoverImage(raw []byte) ([]byte, string, error) {
+	decoded, format, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, "", err
+	}
+	if ext := chooseImageExtension("", "", format); ext != "" {
+		return raw, ext, nil
+	}
+	var converted bytes.Buffer
+	if err := encodeImage(&converted, decoded, ".png"); err != nil {
+		return nil, "", err
+	}
+	return converted.Bytes(), ".png", nil
+}
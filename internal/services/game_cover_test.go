@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+
+	"golang.org/x/image/bmp"
+)
+
+func newCoverTestService(t *testing.T, appDataDir string, updated *domain.Game) *ThumbnailService {
+	t.Helper()
+	return NewThumbnailService(fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			if gameID != "game-1" {
+				return nil, nil
+			}
+			return &domain.Game{ID: gameID, Title: "Game"}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) {
+			*updated = game
+			return &game, nil
+		},
+	}, appDataDir, newTestLogger())
+}
+
+func TestThumbnailServiceSetGameCoverFromFileConvertsAndKeepsOriginal(t *testing.T) {
+	t.Parallel()
+
+	appDataDir := t.TempDir()
+	var bmpData bytes.Buffer
+	if err := bmp.Encode(&bmpData, image.NewRGBA(image.Rect(0, 0, 300, 150))); err != nil {
+		t.Fatalf("encode bmp: %v", err)
+	}
+	// 拡張子と中身が食い違っていても中身の形式で扱う。
+	source := filepath.Join(t.TempDir(), "cover.jpg")
+	if err := os.WriteFile(source, bmpData.Bytes(), 0o600); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	var updated domain.Game
+	service := newCoverTestService(t, appDataDir, &updated)
+	game, err := service.SetGameCoverFromFile(context.Background(), "game-1", source, 100)
+	if err != nil {
+		t.Fatalf("SetGameCoverFromFile: %v", err)
+	}
+	if game.ImagePath == nil || updated.ImagePath == nil || *updated.ImagePath != *game.ImagePath {
+		t.Fatalf("imagePath should be updated: %+v", updated)
+	}
+	if filepath.Ext(*game.ImagePath) != ".png" {
+		t.Fatalf("bmp should be converted to png: %s", *game.ImagePath)
+	}
+	if width, height := decodeTestImageSize(t, *game.ImagePath); width != 200 || height != 100 {
+		t.Fatalf("unexpected thumbnail size %dx%d", width, height)
+	}
+	// 原寸画像が残るため、サムネイルサイズの変更で描き直せる。
+	regenerated, err := service.regenerate(filepath.Join(appDataDir, thumbnailsDirName), *game.ImagePath, 50)
+	if err != nil {
+		t.Fatalf("custom cover should be regenerable: %v", err)
+	}
+	if width, height := decodeTestImageSize(t, regenerated); width != 100 || height != 50 {
+		t.Fatalf("unexpected regenerated size %dx%d", width, height)
+	}
+}
+
+func TestThumbnailServiceSetGameCoverFromFileRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	notImage := filepath.Join(t.TempDir(), "note.txt")
+	if err := os.WriteFile(notImage, []byte("not an image"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	png := filepath.Join(t.TempDir(), "cover.png")
+	if err := os.WriteFile(png, encodeTestPNG(t, 20, 20), 0o600); err != nil {
+		t.Fatalf("write png: %v", err)
+	}
+	cases := map[string]struct {
+		gameID string
+		path   string
+	}{
+		"missing file": {gameID: "game-1", path: filepath.Join(t.TempDir(), "missing.png")},
+		"not image":    {gameID: "game-1", path: notImage},
+		"empty path":   {gameID: "game-1", path: " "},
+		"unknown game": {gameID: "game-9", path: png},
+	}
+	for name, tc := range cases {
+		var updated domain.Game
+		service := newCoverTestService(t, t.TempDir(), &updated)
+		if _, err := service.SetGameCoverFromFile(context.Background(), tc.gameID, tc.path, 100); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if updated.ID != "" {
+			t.Errorf("%s: game should not be updated", name)
+		}
+	}
+}
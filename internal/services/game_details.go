@@ -0,0 +1,136 @@
+// ゲームの作品情報（概要・発売日・評価・ジャンル）の検証と正規化を提供する。
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// MaxGameRating はゲームの評価の上限（1〜100 で評価し、0 は未評価）。
+	MaxGameRating = 100
+	// maxGameGenres は1ゲームに保存するジャンル数の上限。
+	maxGameGenres = 20
+)
+
+// normalizeGameDescription は概要を保存形式にする。空（空白のみ）なら nil（未設定）。
+func normalizeGameDescription(description string) *string {
+	trimmed := strings.TrimSpace(description)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// normalizeReleaseDate は発売日（YYYY-MM-DD）を検証する。空なら nil（未設定）。
+func normalizeReleaseDate(releaseDate string) (*string, error) {
+	trimmed := strings.TrimSpace(releaseDate)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if _, err := time.Parse("2006-01-02", trimmed); err != nil {
+		return nil, fmt.Errorf("releaseDate must be YYYY-MM-DD: %s", trimmed)
+	}
+	return &trimmed, nil
+}
+
+// normalizeGameRating は評価を検証する。0 なら nil（未評価）。
+func normalizeGameRating(rating int) (*int, error) {
+	if rating < 0 || rating > MaxGameRating {
+		return nil, fmt.Errorf("rating must be 0-%d", MaxGameRating)
+	}
+	if rating == 0 {
+		return nil, nil
+	}
+	return &rating, nil
+}
+
+// normalizeGenres はジャンルの空白と重複（大文字・小文字の違いを含む）を除き、指定順を保って返す。
+func normalizeGenres(genres []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(genres))
+	normalized := make([]string, 0, len(genres))
+	for _, genre := range genres {
+		trimmed := strings.TrimSpace(genre)
+		key := strings.ToLower(trimmed)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		normalized = append(normalized, trimmed)
+	}
+	if len(normalized) > maxGameGenres {
+		return nil, fmt.Errorf("genres must be at most %d", maxGameGenres)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// gameDetailsPatch は作品情報の変更内容。nil の項目は現状維持とする。
+type gameDetailsPatch struct {
+	Description *string
+	ReleaseDate *string
+	Rating      *int
+	Genres      *[]string
+}
+
+// applyTo は変更内容を検証して game に反映する。空文字・0・空のジャンルは未設定に戻す。
+func (patch gameDetailsPatch) applyTo(game *domain.Game) error {
+	var errs []error
+	if patch.Description != nil {
+		game.Description = normalizeGameDescription(*patch.Description)
+	}
+	if patch.ReleaseDate != nil {
+		releaseDate, err := normalizeReleaseDate(*patch.ReleaseDate)
+		errs = append(errs, err)
+		if err == nil {
+			game.ReleaseDate = releaseDate
+		}
+	}
+	if patch.Rating != nil {
+		rating, err := normalizeGameRating(*patch.Rating)
+		errs = append(errs, err)
+		if err == nil {
+			game.Rating = rating
+		}
+	}
+	if patch.Genres != nil {
+		genres, err := normalizeGenres(*patch.Genres)
+		errs = append(errs, err)
+		if err == nil {
+			game.Genres = genres
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// gameDetailsFromImport は取り込み元から取得した作品情報を、未設定の項目だけ埋める変更内容にする。
+// 利用者が入力済みの値（特に評価）は取り込み直しても上書きしない。
+func gameDetailsFromImport(game domain.Game, imported domain.GameImport) gameDetailsPatch {
+	var patch gameDetailsPatch
+	if game.Description == nil && imported.Description != "" {
+		patch.Description = &imported.Description
+	}
+	if game.ReleaseDate == nil && imported.ReleaseDate != "" {
+		patch.ReleaseDate = &imported.ReleaseDate
+	}
+	if game.Rating == nil && imported.Score > 0 && imported.Score <= MaxGameRating {
+		patch.Rating = &imported.Score
+	}
+	if len(game.Genres) == 0 && len(imported.Genres) > 0 {
+		genres := imported.Genres
+		if len(genres) > maxGameGenres {
+			genres = genres[:maxGameGenres]
+		}
+		patch.Genres = &genres
+	}
+	return patch
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func newGameDetailsTestService(existing domain.Game, updated *domain.Game) *GameService {
+	return NewGameService(&fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			game := existing
+			return &game, nil
+		},
+		createGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) {
+			*updated = game
+			return &game, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) {
+			*updated = game
+			return &game, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestGameServiceCreateGameNormalizesDetails(t *testing.T) {
+	t.Parallel()
+
+	var created domain.Game
+	service := newGameDetailsTestService(domain.Game{}, &created)
+	_, err := service.CreateGame(context.Background(), GameInput{
+		Title:       "Game",
+		Publisher:   "Pub",
+		ExePath:     "/games/game.exe",
+		Description: "  あらすじ  ",
+		ReleaseDate: "2024-05-31",
+		Rating:      80,
+		Genres:      []string{" ADV ", "adv", "", "SF"},
+	})
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	if created.Description == nil || *created.Description != "あらすじ" {
+		t.Fatalf("description should be trimmed: %#v", created.Description)
+	}
+	if created.ReleaseDate == nil || *created.ReleaseDate != "2024-05-31" || created.Rating == nil || *created.Rating != 80 {
+		t.Fatalf("unexpected releaseDate/rating: %#v %#v", created.ReleaseDate, created.Rating)
+	}
+	if !reflect.DeepEqual(created.Genres, []string{"ADV", "SF"}) {
+		t.Fatalf("genres should be trimmed and deduplicated: %#v", created.Genres)
+	}
+}
+
+func TestGameServiceUpdateGameDetailsKeepWhenNilAndClearWhenEmpty(t *testing.T) {
+	t.Parallel()
+
+	description, releaseDate, rating := "概要", "2020-01-01", 70
+	existing := domain.Game{
+		ID: "game-1", Title: "Game", Publisher: "Pub", ExePath: "/game.exe", PlayStatus: domain.PlayStatusPlaying,
+		Description: &description, ReleaseDate: &releaseDate, Rating: &rating, Genres: []string{"ADV"},
+	}
+	base := GameUpdateInput{Title: "Game", Publisher: "Pub", ExePath: "/game.exe"}
+
+	var kept domain.Game
+	if _, err := newGameDetailsTestService(existing, &kept).UpdateGame(context.Background(), "game-1", base); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if kept.Description == nil || kept.ReleaseDate == nil || kept.Rating == nil || len(kept.Genres) != 1 {
+		t.Fatalf("details should be kept when not specified: %+v", kept)
+	}
+
+	var cleared domain.Game
+	empty, zero, noGenres := "", 0, []string{}
+	clearInput := base
+	clearInput.Description, clearInput.ReleaseDate, clearInput.Rating, clearInput.Genres = &empty, &empty, &zero, &noGenres
+	if _, err := newGameDetailsTestService(existing, &cleared).UpdateGame(context.Background(), "game-1", clearInput); err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if cleared.Description != nil || cleared.ReleaseDate != nil || cleared.Rating != nil || cleared.Genres != nil {
+		t.Fatalf("details should be cleared by empty values: %+v", cleared)
+	}
+}
+
+func TestGameServiceUpdateGameRejectsInvalidDetails(t *testing.T) {
+	t.Parallel()
+
+	existing := domain.Game{ID: "game-1", Title: "Game", Publisher: "Pub", ExePath: "/game.exe"}
+	badDate, badRating := "2024/13/01", MaxGameRating+1
+	cases := map[string]GameUpdateInput{
+		"releaseDate": {Title: "Game", Publisher: "Pub", ExePath: "/game.exe", ReleaseDate: &badDate},
+		"rating":      {Title: "Game", Publisher: "Pub", ExePath: "/game.exe", Rating: &badRating},
+	}
+	for name, input := range cases {
+		var updated domain.Game
+		if _, err := newGameDetailsTestService(existing, &updated).UpdateGame(context.Background(), "game-1", input); err == nil {
+			t.Errorf("%s: expected invalid detail to fail", name)
+		}
+	}
+}
+
+func TestGameDetailsFromImportFillsOnlyUnsetFields(t *testing.T) {
+	t.Parallel()
+
+	rating := 60
+	game := domain.Game{Rating: &rating, Genres: []string{"既存"}}
+	imported := domain.GameImport{Description: "概要", ReleaseDate: "2019-04-26", Score: 85, Genres: []string{"ADV"}}
+	if err := gameDetailsFromImport(game, imported).applyTo(&game); err != nil {
+		t.Fatalf("applyTo: %v", err)
+	}
+	if game.Description == nil || *game.Description != "概要" || game.ReleaseDate == nil || *game.ReleaseDate != "2019-04-26" {
+		t.Fatalf("unset details should be filled: %+v", game)
+	}
+	if *game.Rating != 60 || !reflect.DeepEqual(game.Genres, []string{"既存"}) {
+		t.Fatalf("existing rating and genres should be kept: %+v", game)
+	}
+}
@@ -0,0 +1,187 @@
+// ゲームの外部リンク（公式サイト・販売ページ・パッチ配布ページ等）の管理を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// GameLinkService はゲームリンク関連の操作を提供する。
+type GameLinkService struct {
+	repository GameLinkRepository
+	logger     *slog.Logger
+}
+
+// NewGameLinkService は GameLinkService を生成する。
+func NewGameLinkService(repository GameLinkRepository, logger *slog.Logger) *GameLinkService {
+	return &GameLinkService{repository: repository, logger: logger}
+}
+
+// GameLinkInput はリンク作成入力を表す。
+type GameLinkInput struct {
+	GameID string
+	Label  string
+	URL    string
+}
+
+// GameLinkUpdateInput はリンク更新入力を表す。
+type GameLinkUpdateInput struct {
+	Label     string
+	URL       string
+	SortOrder int64
+}
+
+// ListGameLinksByGame はゲームのリンク一覧を表示順で取得する。
+func (service *GameLinkService) ListGameLinksByGame(ctx context.Context, gameID string) ([]domain.GameLink, error) {
+	trimmedGameID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	links, err := service.repository.ListGameLinksByGame(ctx, trimmedGameID)
+	if err != nil {
+		service.logger.Error("リンク取得に失敗", "error", err)
+		return nil, newServiceError("リンク取得に失敗しました", err.Error())
+	}
+	return links, nil
+}
+
+// CreateGameLink はリンクをゲームの末尾に追加する。
+func (service *GameLinkService) CreateGameLink(ctx context.Context, input GameLinkInput) (*domain.GameLink, error) {
+	gameID, detail, ok := requireNonEmpty(input.GameID, "gameID")
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	label, linkURL, err := normalizeGameLinkFields(input.Label, input.URL)
+	if err != nil {
+		service.logger.Warn("リンク入力が不正です", "error", err)
+		return nil, newServiceError("リンク入力が不正です", err.Error())
+	}
+	game, err := service.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", err)
+		return nil, newServiceError("ゲーム取得に失敗しました", err.Error())
+	}
+	if game == nil {
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+
+	created, err := service.repository.CreateGameLink(ctx, domain.GameLink{GameID: gameID, Label: label, URL: linkURL})
+	if err != nil {
+		service.logger.Error("リンク作成に失敗", "error", err)
+		return nil, newServiceError("リンク作成に失敗しました", err.Error())
+	}
+	return created, nil
+}
+
+// UpdateGameLink はリンクのラベル・URL・表示順を更新する。
+func (service *GameLinkService) UpdateGameLink(ctx context.Context, linkID string, input GameLinkUpdateInput) (*domain.GameLink, error) {
+	link, err := service.getGameLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	label, linkURL, err := normalizeGameLinkFields(input.Label, input.URL)
+	if err != nil {
+		service.logger.Warn("リンク入力が不正です", "error", err)
+		return nil, newServiceError("リンク入力が不正です", err.Error())
+	}
+	if input.SortOrder < 0 {
+		return nil, newServiceError("リンク入力が不正です", "sortOrderが不正です")
+	}
+	link.Label = label
+	link.URL = linkURL
+	link.SortOrder = input.SortOrder
+
+	updated, err := service.repository.UpdateGameLink(ctx, *link)
+	if err != nil {
+		service.logger.Error("リンク更新に失敗", "error", err)
+		return nil, newServiceError("リンク更新に失敗しました", err.Error())
+	}
+	return updated, nil
+}
+
+// DeleteGameLink はリンクを削除し、削除したリンクを返す（呼び出し側の同期要求に使う）。
+func (service *GameLinkService) DeleteGameLink(ctx context.Context, linkID string) (*domain.GameLink, error) {
+	link, err := service.getGameLink(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.repository.DeleteGameLink(ctx, link.ID); err != nil {
+		service.logger.Error("リンク削除に失敗", "error", err)
+		return nil, newServiceError("リンク削除に失敗しました", err.Error())
+	}
+	return link, nil
+}
+
+// ResolveGameLinkURL はブラウザで開くリンクの URL を返す。
+// 保存済みの値も開く前に再検証し、http / https 以外（file: やカスタムスキーム）は開かない。
+func (service *GameLinkService) ResolveGameLinkURL(ctx context.Context, linkID string) (string, error) {
+	link, err := service.getGameLink(ctx, linkID)
+	if err != nil {
+		return "", err
+	}
+	linkURL, err := normalizeGameLinkURL(link.URL)
+	if err != nil {
+		service.logger.Warn("リンクのURLが不正です", "linkId", link.ID, "error", err)
+		return "", newServiceError("リンクのURLが不正です", err.Error())
+	}
+	return linkURL, nil
+}
+
+func (service *GameLinkService) getGameLink(ctx context.Context, linkID string) (*domain.GameLink, error) {
+	trimmedID, detail, ok := requireNonEmpty(linkID, "linkID")
+	if !ok {
+		return nil, newServiceError("リンクIDが不正です", detail)
+	}
+	link, err := service.repository.GetGameLinkByID(ctx, trimmedID)
+	if err != nil {
+		service.logger.Error("リンク取得に失敗", "error", err)
+		return nil, newServiceError("リンク取得に失敗しました", err.Error())
+	}
+	if link == nil {
+		service.logger.Warn("リンクが見つかりません", "linkId", trimmedID)
+		return nil, newServiceError("リンクが見つかりません", "指定されたIDが存在しません")
+	}
+	return link, nil
+}
+
+func normalizeGameLinkFields(label string, rawURL string) (string, string, error) {
+	linkURL, err := normalizeGameLinkURL(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	trimmedLabel := strings.TrimSpace(label)
+	if trimmedLabel == "" {
+		// ラベル未入力ならホスト名を表示に使う。
+		parsed, _ := url.Parse(linkURL)
+		trimmedLabel = parsed.Host
+	}
+	return trimmedLabel, linkURL, nil
+}
+
+// normalizeGameLinkURL は URL を検証して前後の空白を除いた値を返す。スキーム省略時は https を補う。
+func normalizeGameLinkURL(rawURL string) (string, error) {
+	trimmed := strings.TrimSpace(rawURL)
+	if trimmed == "" {
+		return "", errors.New("urlが空です")
+	}
+	if !strings.Contains(trimmed, "://") {
+		trimmed = "https://" + trimmed
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return "", errors.New("urlが不正です")
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", errors.New("http / https のURLのみ登録できます")
+	}
+	if parsed.Host == "" {
+		return "", errors.New("urlにホストがありません")
+	}
+	return trimmed, nil
+}
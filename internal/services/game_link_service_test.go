@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+type fakeGameLinkRepository struct {
+	links   map[string]domain.GameLink
+	created []domain.GameLink
+}
+
+func (r *fakeGameLinkRepository) ListGameLinksByGame(_ context.Context, _ string) ([]domain.GameLink, error) {
+	return nil, nil
+}
+
+func (r *fakeGameLinkRepository) GetGameLinkByID(_ context.Context, linkID string) (*domain.GameLink, error) {
+	link, ok := r.links[linkID]
+	if !ok {
+		return nil, nil
+	}
+	return &link, nil
+}
+
+func (r *fakeGameLinkRepository) CreateGameLink(_ context.Context, link domain.GameLink) (*domain.GameLink, error) {
+	link.ID = "created"
+	r.created = append(r.created, link)
+	return &link, nil
+}
+
+func (r *fakeGameLinkRepository) UpdateGameLink(_ context.Context, link domain.GameLink) (*domain.GameLink, error) {
+	return &link, nil
+}
+
+func (r *fakeGameLinkRepository) DeleteGameLink(_ context.Context, _ string) error {
+	return nil
+}
+
+func (r *fakeGameLinkRepository) GetGameByID(_ context.Context, gameID string) (*domain.Game, error) {
+	return &domain.Game{ID: gameID}, nil
+}
+
+func TestGameLinkServiceCreateGameLinkNormalizesURL(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeGameLinkRepository{}
+	service := NewGameLinkService(repository, newTestLogger())
+
+	created, err := service.CreateGameLink(context.Background(), GameLinkInput{GameID: "game-1", URL: "  www.example.com/game  "})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if created.URL != "https://www.example.com/game" || created.Label != "www.example.com" {
+		t.Fatalf("unexpected link: %#v", created)
+	}
+}
+
+func TestGameLinkServiceRejectsNonHTTPURLs(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeGameLinkRepository{links: map[string]domain.GameLink{
+		"stored": {ID: "stored", GameID: "game-1", Label: "local", URL: "file:///C:/Windows/System32/calc.exe"},
+	}}
+	service := NewGameLinkService(repository, newTestLogger())
+
+	for _, rawURL := range []string{"file:///etc/passwd", "javascript:alert(1)", "https://", ""} {
+		if _, err := service.CreateGameLink(context.Background(), GameLinkInput{GameID: "game-1", Label: "x", URL: rawURL}); err == nil {
+			t.Fatalf("expected %q to be rejected", rawURL)
+		}
+	}
+	if len(repository.created) != 0 {
+		t.Fatalf("expected nothing to be created, got %#v", repository.created)
+	}
+	if _, err := service.ResolveGameLinkURL(context.Background(), "stored"); err == nil {
+		t.Fatalf("expected stored non-http link not to be opened")
+	}
+}
@@ -15,11 +15,34 @@ import (
 type GameService struct {
 	repository GameRepository
 	logger     *slog.Logger
+	withTx     TxRunner[GameRepository]
+	// hookRunner は起動前コマンドの実行（テストで差し替える）。
+	hookRunner launchHookRunner
+	// events はゲームの作成・更新・削除を知らせるイベントバス（nil 可）。
+	events *EventBus
 }
 
 // NewGameService は GameService を生成する。
 func NewGameService(repository GameRepository, logger *slog.Logger) *GameService {
-	return &GameService{repository: repository, logger: logger}
+	return &GameService{repository: repository, logger: logger, hookRunner: runShellCommand}
+}
+
+// SetTxRunner はゲーム作成と初期ルート作成をまとめるトランザクションを設定する。
+func (service *GameService) SetTxRunner(runner TxRunner[GameRepository]) {
+	service.withTx = runner
+}
+
+// SetEventBus はゲームの作成・更新・削除を EventGameUpdated で知らせる先を設定する。
+func (service *GameService) SetEventBus(bus *EventBus) {
+	service.events = bus
+}
+
+// publishGameUpdated は保存後のゲームを EventGameUpdated で知らせる。
+func (service *GameService) publishGameUpdated(game *domain.Game) {
+	if game == nil {
+		return
+	}
+	service.events.Publish(EventGameUpdated, GameUpdatedEvent{GameID: game.ID, Game: game})
 }
 
 // ListGames は検索・フィルタ・ソート付きでゲーム一覧を取得する。
@@ -38,6 +61,85 @@ func (service *GameService) ListGames(
 	return games, nil
 }
 
+// ListGamesSummary は ListGames と同じ条件で、一覧表示に必要な項目だけのゲーム一覧を返す。
+func (service *GameService) ListGamesSummary(
+	ctx context.Context,
+	searchText string,
+	filter domain.PlayStatus,
+	sortBy string,
+	sortDirection string,
+) ([]domain.GameSummary, error) {
+	games, error := service.ListGames(ctx, searchText, filter, sortBy, sortDirection)
+	if error != nil {
+		return nil, error
+	}
+	summaries := make([]domain.GameSummary, 0, len(games))
+	for _, game := range games {
+		summaries = append(summaries, game.Summary())
+	}
+	return summaries, nil
+}
+
+// ListRecentlyPlayed は最終プレイ日時の新しい順に、最後のセッションと現在のルートを含めてゲームを返す。
+// limit が 0 以下なら既定件数、上限を超える場合は上限に丸める。
+func (service *GameService) ListRecentlyPlayed(ctx context.Context, limit int) ([]domain.RecentlyPlayedGame, error) {
+	if limit <= 0 {
+		limit = domain.DefaultRecentlyPlayedLimit
+	}
+	limit = min(limit, domain.MaxRecentlyPlayedLimit)
+	games, error := service.repository.ListRecentlyPlayed(ctx, limit)
+	if error != nil {
+		service.logger.Error("最近遊んだゲームの取得に失敗", "error", error)
+		return nil, newServiceError("最近遊んだゲームの取得に失敗しました", error.Error())
+	}
+	return games, nil
+}
+
+// ListGamesPage は ListGames と同じ条件で1ページ分のゲームと全件数を返す。
+// ライブラリが大きいときに一覧を分割して読み込むために使う。
+func (service *GameService) ListGamesPage(
+	ctx context.Context,
+	searchText string,
+	filter domain.PlayStatus,
+	sortBy string,
+	sortDirection string,
+	page domain.PageRequest,
+) (domain.Page[domain.Game], error) {
+	page = page.Normalize()
+	games, total, error := service.repository.ListGamesPage(ctx, strings.TrimSpace(searchText), filter, sortBy, sortDirection, page)
+	if error != nil {
+		service.logger.Error("ゲーム一覧取得に失敗", "error", error)
+		return domain.Page[domain.Game]{}, newServiceError("ゲーム一覧取得に失敗しました", error.Error())
+	}
+	return domain.NewPage(games, total, page), nil
+}
+
+// ListGamesSummaryPage は ListGamesPage の結果を一覧表示用の項目に縮めて返す。
+func (service *GameService) ListGamesSummaryPage(
+	ctx context.Context,
+	searchText string,
+	filter domain.PlayStatus,
+	sortBy string,
+	sortDirection string,
+	page domain.PageRequest,
+) (domain.Page[domain.GameSummary], error) {
+	games, error := service.ListGamesPage(ctx, searchText, filter, sortBy, sortDirection, page)
+	if error != nil {
+		return domain.Page[domain.GameSummary]{}, error
+	}
+	summaries := make([]domain.GameSummary, 0, len(games.Items))
+	for _, game := range games.Items {
+		summaries = append(summaries, game.Summary())
+	}
+	return domain.Page[domain.GameSummary]{
+		Items:   summaries,
+		Total:   games.Total,
+		Limit:   games.Limit,
+		Offset:  games.Offset,
+		HasMore: games.HasMore,
+	}, nil
+}
+
 // GetGameByID はID指定でゲームを取得する。
 func (service *GameService) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
 	game, error := service.repository.GetGameByID(ctx, strings.TrimSpace(gameID))
@@ -48,6 +150,16 @@ func (service *GameService) GetGameByID(ctx context.Context, gameID string) (*do
 	return game, nil
 }
 
+// GetGameByExePath は実行ファイルパスに一致するゲームを取得する。登録されていなければ nil を返す。
+func (service *GameService) GetGameByExePath(ctx context.Context, exePath string) (*domain.Game, error) {
+	game, error := service.repository.GetGameByExePath(ctx, exePath)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	return game, nil
+}
+
 // CreateGame はゲームを新規作成する。
 func (service *GameService) CreateGame(ctx context.Context, input GameInput) (*domain.Game, error) {
 	if error := validateGameInput(input); error != nil {
@@ -64,22 +176,39 @@ func (service *GameService) CreateGame(ctx context.Context, input GameInput) (*d
 		PlayStatus:     domain.PlayStatusUnplayed,
 		TotalPlayTime:  0,
 	}
-
-	created, error := service.repository.CreateGame(ctx, game)
-	if error != nil {
-		service.logger.Error("ゲーム作成に失敗", "error", error)
-		return nil, newServiceError("ゲーム作成に失敗しました", error.Error())
+	details := gameDetailsPatch{Description: &input.Description, ReleaseDate: &input.ReleaseDate, Rating: &input.Rating, Genres: &input.Genres}
+	if error := details.applyTo(&game); error != nil {
+		service.logger.Warn("ゲーム入力が不正です", "error", error)
+		return nil, newServiceError("ゲーム入力が不正です", error.Error())
+	}
+	customStatus, err := service.resolveCustomStatus(ctx, input.CustomStatus)
+	if err != nil {
+		return nil, err
 	}
+	game.CustomStatus = customStatus
 
-	if created != nil {
-		_, _ = service.repository.CreateRoute(ctx, domain.Route{
+	var created *domain.Game
+	createErr := runInTx(ctx, service.withTx, service.repository, func(repository GameRepository) error {
+		var err error
+		created, err = repository.CreateGame(ctx, game)
+		if err != nil || created == nil {
+			return err
+		}
+		// 初期ルートの無いゲームが残らないよう、ゲーム作成と同じトランザクションで作る。
+		_, err = repository.CreateRoute(ctx, domain.Route{
 			Name:   "メインルート",
 			Order:  1,
 			GameID: created.ID,
 		})
+		return err
+	})
+	if createErr != nil {
+		service.logger.Error("ゲーム作成に失敗", "error", createErr)
+		return nil, newServiceError("ゲーム作成に失敗しました", createErr.Error())
 	}
 
 	service.logger.Info("ゲームを作成", "title", game.Title)
+	service.publishGameUpdated(created)
 	return created, nil
 }
 
@@ -101,10 +230,42 @@ func (service *GameService) UpdateGame(ctx context.Context, gameID string, input
 		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
 	}
 
+	wasCleared := current.ClearedAt != nil
 	if input.PlayStatus != "" && !domain.IsValidPlayStatus(input.PlayStatus) {
 		service.logger.Warn("playStatus が不正です", "playStatus", input.PlayStatus)
 		return nil, newServiceError("playStatus が不正です", string(input.PlayStatus))
 	}
+	details := gameDetailsPatch{Description: input.Description, ReleaseDate: input.ReleaseDate, Rating: input.Rating, Genres: input.Genres}
+	if error := details.applyTo(current); error != nil {
+		service.logger.Warn("ゲーム入力が不正です", "error", error)
+		return nil, newServiceError("ゲーム入力が不正です", error.Error())
+	}
+	if input.CustomStatus != nil {
+		customStatus, error := service.resolveCustomStatus(ctx, *input.CustomStatus)
+		if error != nil {
+			return nil, error
+		}
+		current.CustomStatus = customStatus
+	}
+	if input.IsFavorite != nil {
+		current.IsFavorite = *input.IsFavorite
+	}
+	if input.SaveIncludePatterns != nil {
+		patterns, error := normalizeSaveFilePatterns(*input.SaveIncludePatterns)
+		if error != nil {
+			service.logger.Warn("セーブの対象パターンが不正です", "error", error)
+			return nil, newServiceError("セーブの対象パターンが不正です", error.Error())
+		}
+		current.SaveIncludePatterns = patterns
+	}
+	if input.SaveExcludePatterns != nil {
+		patterns, error := normalizeSaveFilePatterns(*input.SaveExcludePatterns)
+		if error != nil {
+			service.logger.Warn("セーブの除外パターンが不正です", "error", error)
+			return nil, newServiceError("セーブの除外パターンが不正です", error.Error())
+		}
+		current.SaveExcludePatterns = patterns
+	}
 
 	current.Title = strings.TrimSpace(input.Title)
 	current.Publisher = strings.TrimSpace(input.Publisher)
@@ -128,12 +289,21 @@ func (service *GameService) UpdateGame(ctx context.Context, gameID string, input
 	if input.CurrentRouteID != nil {
 		current.CurrentRouteID = input.CurrentRouteID
 	}
+	if input.PreLaunchCommand != nil {
+		current.PreLaunchCommand = normalizeLaunchHook(*input.PreLaunchCommand)
+	}
+	if input.PostExitCommand != nil {
+		current.PostExitCommand = normalizeLaunchHook(*input.PostExitCommand)
+	}
 
 	updated, error := service.repository.UpdateGame(ctx, *current)
 	if error != nil {
 		service.logger.Error("ゲーム更新に失敗", "error", error)
 		return nil, newServiceError("ゲーム更新に失敗しました", error.Error())
 	}
+	if updated != nil {
+		service.events.Publish(EventGameUpdated, GameUpdatedEvent{GameID: updated.ID, Game: updated, Cleared: !wasCleared && updated.ClearedAt != nil})
+	}
 	return updated, nil
 }
 
@@ -163,6 +333,7 @@ func (service *GameService) UpdatePlayTime(ctx context.Context, gameID string, t
 		service.logger.Error("プレイ時間更新に失敗", "error", error)
 		return nil, newServiceError("プレイ時間更新に失敗しました", error.Error())
 	}
+	service.publishGameUpdated(updated)
 	return updated, nil
 }
 
@@ -178,16 +349,68 @@ func (service *GameService) DeleteGame(ctx context.Context, gameID string) error
 		service.logger.Error("ゲーム削除に失敗", "error", error)
 		return newServiceError("ゲーム削除に失敗しました", error.Error())
 	}
+	service.events.Publish(EventGameUpdated, GameUpdatedEvent{GameID: trimmedID, Deleted: true})
 	return nil
 }
 
+// ToggleFavorite はゲームのお気に入りを切り替え、更新後のゲームを返す。
+func (service *GameService) ToggleFavorite(ctx context.Context, gameID string) (*domain.Game, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		service.logger.Warn("ゲームIDが不正です", "detail", detail, "gameId", gameID)
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	current, error := service.repository.GetGameByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if current == nil {
+		service.logger.Warn("ゲームが見つかりません", "gameId", trimmedID)
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+	current.IsFavorite = !current.IsFavorite
+	updated, error := service.repository.UpdateGame(ctx, *current)
+	if error != nil {
+		service.logger.Error("お気に入りの更新に失敗", "error", error)
+		return nil, newServiceError("お気に入りの更新に失敗しました", error.Error())
+	}
+	service.publishGameUpdated(updated)
+	return updated, nil
+}
+
+// resolveCustomStatus はカスタムステータスの slug を検証する。空なら未設定（nil）を返す。
+func (service *GameService) resolveCustomStatus(ctx context.Context, value string) (*string, error) {
+	slug := strings.ToLower(strings.TrimSpace(value))
+	if slug == "" {
+		return nil, nil
+	}
+	definition, error := service.repository.GetPlayStatusDefinition(ctx, slug)
+	if error != nil {
+		service.logger.Error("プレイ状況の取得に失敗", "error", error)
+		return nil, newServiceError("プレイ状況の取得に失敗しました", error.Error())
+	}
+	if definition == nil {
+		service.logger.Warn("プレイ状況が見つかりません", "customStatus", slug)
+		return nil, newServiceError("プレイ状況が見つかりません", slug)
+	}
+	return &slug, nil
+}
+
 // GameInput はゲーム作成入力を表す。
+// Description / ReleaseDate（YYYY-MM-DD）/ Rating（1〜100）/ Genres は任意で、空・0 なら未設定。
+// CustomStatus はカスタムステータスの slug で、空なら未設定。
 type GameInput struct {
 	Title          string
 	Publisher      string
 	ImagePath      *string
 	ExePath        string
 	SaveFolderPath *string
+	Description    string
+	ReleaseDate    string
+	Rating         int
+	Genres         []string
+	CustomStatus   string
 }
 
 // GameUpdateInput はゲーム更新入力を表す。
@@ -200,6 +423,21 @@ type GameUpdateInput struct {
 	PlayStatus     domain.PlayStatus
 	ClearedAt      *time.Time
 	CurrentRouteID *string
+	// PreLaunchCommand / PostExitCommand も未指定（nil）なら現状維持で、空文字（空白のみ）で解除する。
+	PreLaunchCommand *string
+	PostExitCommand  *string
+	// Description / ReleaseDate / Rating / Genres も未指定（nil）なら現状維持で、空文字・0・空のジャンルで未設定に戻す。
+	Description *string
+	ReleaseDate *string
+	Rating      *int
+	Genres      *[]string
+	// CustomStatus も未指定（nil）なら現状維持で、空文字でカスタムステータスを外す。
+	CustomStatus *string
+	// IsFavorite も未指定（nil）なら現状維持。
+	IsFavorite *bool
+	// SaveIncludePatterns / SaveExcludePatterns も未指定（nil）なら現状維持で、空の一覧で絞り込みを外す。
+	SaveIncludePatterns *[]string
+	SaveExcludePatterns *[]string
 }
 
 // validateGameInput はゲーム作成入力の簡易検証を行う。
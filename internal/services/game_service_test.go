@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"reflect"
 	"testing"
 	"time"
 
@@ -18,12 +19,31 @@ type fakeGameRepository struct {
 	updateGameFn     func(ctx context.Context, game domain.Game) (*domain.Game, error)
 	deleteGameFn     func(ctx context.Context, gameID string) error
 	createRouteCalls int
+	archivedAt       *time.Time
+	archiveCalls     int
+	launchWrapper    *domain.LaunchWrapper
+	trackingMode     domain.TrackingMode
+	trackingExcluded bool
+	matchPattern     *string
+	byExePath        *domain.Game
+	customStatuses   []domain.PlayStatusDefinition
+	recentLimit      int
 }
 
 func (repository fakeGameRepository) ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 	return repository.listGamesFn(ctx, searchText, filter, sortBy, sortDirection)
 }
 
+func (repository fakeGameRepository) ListGamesPage(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string, page domain.PageRequest) ([]domain.Game, int, error) {
+	games, err := repository.listGamesFn(ctx, searchText, filter, sortBy, sortDirection)
+	if err != nil {
+		return nil, 0, err
+	}
+	start := min(page.Offset, len(games))
+	end := min(start+page.Limit, len(games))
+	return games[start:end], len(games), nil
+}
+
 func (repository fakeGameRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
 	return repository.getGameByIDFn(ctx, gameID)
 }
@@ -40,6 +60,50 @@ func (repository fakeGameRepository) DeleteGame(ctx context.Context, gameID stri
 	return repository.deleteGameFn(ctx, gameID)
 }
 
+func (repository *fakeGameRepository) SetGameArchived(ctx context.Context, gameID string, archivedAt *time.Time) error {
+	repository.archiveCalls++
+	repository.archivedAt = archivedAt
+	return nil
+}
+
+func (repository *fakeGameRepository) SetGameLaunchWrapper(ctx context.Context, gameID string, wrapper *domain.LaunchWrapper) error {
+	repository.launchWrapper = wrapper
+	return nil
+}
+
+func (repository *fakeGameRepository) SetGameTrackingMode(ctx context.Context, gameID string, mode domain.TrackingMode) error {
+	repository.trackingMode = mode
+	return nil
+}
+
+func (repository *fakeGameRepository) SetGameAutoTrackingExcluded(ctx context.Context, gameID string, excluded bool) error {
+	repository.trackingExcluded = excluded
+	return nil
+}
+
+func (repository *fakeGameRepository) SetGameProcessMatchPattern(ctx context.Context, gameID string, pattern *string) error {
+	repository.matchPattern = pattern
+	return nil
+}
+
+func (repository *fakeGameRepository) GetPlayStatusDefinition(ctx context.Context, slug string) (*domain.PlayStatusDefinition, error) {
+	for _, definition := range repository.customStatuses {
+		if definition.Slug == slug {
+			return &definition, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repository *fakeGameRepository) ListRecentlyPlayed(ctx context.Context, limit int) ([]domain.RecentlyPlayedGame, error) {
+	repository.recentLimit = limit
+	return []domain.RecentlyPlayedGame{}, nil
+}
+
+func (repository *fakeGameRepository) GetGameByExePath(ctx context.Context, exePath string) (*domain.Game, error) {
+	return repository.byExePath, nil
+}
+
 func (repository *fakeGameRepository) CreateRoute(ctx context.Context, route domain.Route) (*domain.Route, error) {
 	repository.createRouteCalls++
 	return &route, nil
@@ -381,6 +445,50 @@ func TestGameServiceListGamesTrimsSearchText(t *testing.T) {
 	}
 }
 
+func TestGameServiceListGamesSummaryProjectsListFields(t *testing.T) {
+	t.Parallel()
+
+	imagePath := "/images/game.png"
+	saveFolder := "/saves/game"
+	service := NewGameService(&fakeGameRepository{
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return []domain.Game{{
+				ID:             "game-1",
+				Title:          "Game",
+				Publisher:      "Brand",
+				ImagePath:      &imagePath,
+				ExePath:        "/games/game.exe",
+				SaveFolderPath: &saveFolder,
+				PlayStatus:     domain.PlayStatusPlaying,
+				TotalPlayTime:  120,
+			}}, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	summaries, err := service.ListGamesSummary(context.Background(), "", domain.PlayStatus(""), "title", "asc")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	want := domain.GameSummary{ID: "game-1", Title: "Game", Publisher: "Brand", ImagePath: &imagePath, PlayStatus: domain.PlayStatusPlaying, TotalPlayTime: 120}
+	if len(summaries) != 1 || !reflect.DeepEqual(summaries[0], want) {
+		t.Fatalf("unexpected summaries: %#v", summaries)
+	}
+}
+
+func TestGameServiceListGamesSummaryReturnsServiceError(t *testing.T) {
+	t.Parallel()
+
+	service := NewGameService(&fakeGameRepository{
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, errors.New("db down")
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := service.ListGamesSummary(context.Background(), "", domain.PlayStatus(""), "title", "asc"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
 func TestGameServiceUpdatePlayTimeStoresLastPlayed(t *testing.T) {
 	t.Parallel()
 
@@ -412,3 +520,118 @@ func TestGameServiceUpdatePlayTimeStoresLastPlayed(t *testing.T) {
 		t.Fatalf("expected total play time to be updated")
 	}
 }
+
+func TestGameServiceListGamesPageNormalizesRequest(t *testing.T) {
+	t.Parallel()
+
+	games := make([]domain.Game, 3)
+	for i := range games {
+		games[i] = domain.Game{ID: string(rune('a' + i)), Title: "Game"}
+	}
+	repository := fakeGameRepository{
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return games, nil
+		},
+	}
+	service := NewGameService(&repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	page, err := service.ListGamesSummaryPage(context.Background(), "", domain.PlayStatus(""), "title", "asc", domain.PageRequest{Limit: 2, Offset: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 || page.Offset != 0 || len(page.Items) != 2 || !page.HasMore || page.Items[0].ID != "a" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	page, _ = service.ListGamesSummaryPage(context.Background(), "", domain.PlayStatus(""), "title", "asc", domain.PageRequest{Offset: 2})
+	if page.Limit != domain.DefaultPageLimit || len(page.Items) != 1 || page.HasMore {
+		t.Fatalf("unexpected last page: %+v", page)
+	}
+}
+
+func TestGameServiceListRecentlyPlayedClampsLimit(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeGameRepository{}
+	service := NewGameService(repo, newTestLogger())
+	for _, tc := range []struct{ limit, want int }{
+		{0, domain.DefaultRecentlyPlayedLimit},
+		{3, 3},
+		{1000, domain.MaxRecentlyPlayedLimit},
+	} {
+		if _, err := service.ListRecentlyPlayed(context.Background(), tc.limit); err != nil {
+			t.Fatalf("ListRecentlyPlayed(%d): %v", tc.limit, err)
+		}
+		if repo.recentLimit != tc.want {
+			t.Fatalf("ListRecentlyPlayed(%d) used limit %d, want %d", tc.limit, repo.recentLimit, tc.want)
+		}
+	}
+}
+
+func TestGameServiceToggleFavoriteFlipsFlag(t *testing.T) {
+	t.Parallel()
+
+	game := domain.Game{ID: "game-1", Title: "Game", Publisher: "Pub", ExePath: "/game.exe"}
+	repo := &fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			copied := game
+			return &copied, nil
+		},
+		updateGameFn: func(ctx context.Context, updated domain.Game) (*domain.Game, error) {
+			game = updated
+			return &updated, nil
+		},
+	}
+	service := NewGameService(repo, newTestLogger())
+
+	first, err := service.ToggleFavorite(context.Background(), "game-1")
+	if err != nil || !first.IsFavorite {
+		t.Fatalf("expected game to become favorite, got %+v (%v)", first, err)
+	}
+	second, err := service.ToggleFavorite(context.Background(), "game-1")
+	if err != nil || second.IsFavorite {
+		t.Fatalf("expected favorite to be cleared, got %+v (%v)", second, err)
+	}
+}
+
+func TestGameServiceUpdateGameNormalizesSavePatterns(t *testing.T) {
+	t.Parallel()
+
+	game := domain.Game{ID: "game-1", Title: "Game", Publisher: "Pub", ExePath: "/game.exe", SaveExcludePatterns: []string{"*.log"}}
+	repo := &fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			copied := game
+			return &copied, nil
+		},
+		updateGameFn: func(ctx context.Context, updated domain.Game) (*domain.Game, error) {
+			game = updated
+			return &updated, nil
+		},
+	}
+	service := NewGameService(repo, newTestLogger())
+	input := GameUpdateInput{Title: "Game", Publisher: "Pub", ExePath: "/game.exe"}
+
+	// 未指定なら現状維持。
+	updated, err := service.UpdateGame(context.Background(), "game-1", input)
+	if err != nil || len(updated.SaveExcludePatterns) != 1 {
+		t.Fatalf("patterns should be kept when omitted, got %+v (%v)", updated, err)
+	}
+
+	include := []string{` saves\*.dat `}
+	exclude := []string{}
+	input.SaveIncludePatterns = &include
+	input.SaveExcludePatterns = &exclude
+	updated, err = service.UpdateGame(context.Background(), "game-1", input)
+	if err != nil {
+		t.Fatalf("UpdateGame: %v", err)
+	}
+	if len(updated.SaveIncludePatterns) != 1 || updated.SaveIncludePatterns[0] != "saves/*.dat" || updated.SaveExcludePatterns != nil {
+		t.Fatalf("unexpected patterns: include=%v exclude=%v", updated.SaveIncludePatterns, updated.SaveExcludePatterns)
+	}
+
+	invalid := []string{"[broken"}
+	input.SaveExcludePatterns = &invalid
+	if _, err := service.UpdateGame(context.Background(), "game-1", input); err == nil {
+		t.Fatal("invalid pattern should be rejected")
+	}
+}
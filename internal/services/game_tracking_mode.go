@@ -0,0 +1,116 @@
+// ゲームごとのプレイ時間計測の設定（数え方・自動計測からの除外・プロセスの一致条件）を提供する。
+package services
+
+import (
+	"context"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// SetTrackingMode はゲームのプレイ時間の数え方を設定する。空文字は既定の process として扱う。
+func (service *GameService) SetTrackingMode(ctx context.Context, gameID string, mode domain.TrackingMode) (*domain.Game, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		service.logger.Warn("ゲームIDが不正です", "detail", detail, "gameId", gameID)
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	normalized := domain.TrackingMode(strings.ToLower(strings.TrimSpace(string(mode))))
+	if normalized == "" {
+		normalized = domain.TrackingModeProcess
+	}
+	if !domain.IsValidTrackingMode(normalized) {
+		service.logger.Warn("プレイ時間の数え方が不正です", "mode", mode, "gameId", trimmedID)
+		return nil, newServiceError("プレイ時間の数え方が不正です", "mode must be process|foreground")
+	}
+
+	current, error := service.repository.GetGameByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if current == nil {
+		service.logger.Warn("ゲームが見つかりません", "gameId", trimmedID)
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+	if current.TrackingMode == normalized {
+		return current, nil
+	}
+
+	if error := service.repository.SetGameTrackingMode(ctx, trimmedID, normalized); error != nil {
+		service.logger.Error("プレイ時間の数え方の保存に失敗", "error", error)
+		return nil, newServiceError("プレイ時間の数え方の保存に失敗しました", error.Error())
+	}
+	current.TrackingMode = normalized
+	service.logger.Info("プレイ時間の数え方を更新", "gameId", trimmedID, "mode", normalized)
+	service.publishGameUpdated(current)
+	return current, nil
+}
+
+// SetAutoTrackingExcluded はゲームをプロセス監視の自動計測から外すかを設定する。
+func (service *GameService) SetAutoTrackingExcluded(ctx context.Context, gameID string, excluded bool) (*domain.Game, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		service.logger.Warn("ゲームIDが不正です", "detail", detail, "gameId", gameID)
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+
+	current, error := service.repository.GetGameByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if current == nil {
+		service.logger.Warn("ゲームが見つかりません", "gameId", trimmedID)
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+	if current.AutoTrackingExcluded == excluded {
+		return current, nil
+	}
+
+	if error := service.repository.SetGameAutoTrackingExcluded(ctx, trimmedID, excluded); error != nil {
+		service.logger.Error("自動計測の除外設定の保存に失敗", "error", error)
+		return nil, newServiceError("自動計測の除外設定の保存に失敗しました", error.Error())
+	}
+	current.AutoTrackingExcluded = excluded
+	service.logger.Info("自動計測の除外設定を更新", "gameId", trimmedID, "excluded", excluded)
+	service.publishGameUpdated(current)
+	return current, nil
+}
+
+// SetProcessMatchPattern はゲームのプロセスのコマンドラインの一致条件を設定する。空（空白のみ）なら解除する。
+// 同じエミュレーターで起動する ROM を別々のゲームとして数えるために使う。
+func (service *GameService) SetProcessMatchPattern(ctx context.Context, gameID string, pattern string) (*domain.Game, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		service.logger.Warn("ゲームIDが不正です", "detail", detail, "gameId", gameID)
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	var normalized *string
+	if trimmed := strings.TrimSpace(pattern); trimmed != "" {
+		if _, error := compileProcessMatchPattern(trimmed); error != nil {
+			service.logger.Warn("プロセスの一致条件が不正です", "detail", error, "gameId", trimmedID)
+			return nil, newServiceError("プロセスの一致条件が不正です", error.Error())
+		}
+		normalized = &trimmed
+	}
+
+	current, error := service.repository.GetGameByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if current == nil {
+		service.logger.Warn("ゲームが見つかりません", "gameId", trimmedID)
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+
+	if error := service.repository.SetGameProcessMatchPattern(ctx, trimmedID, normalized); error != nil {
+		service.logger.Error("プロセスの一致条件の保存に失敗", "error", error)
+		return nil, newServiceError("プロセスの一致条件の保存に失敗しました", error.Error())
+	}
+	current.ProcessMatchPattern = normalized
+	service.logger.Info("プロセスの一致条件を更新", "gameId", trimmedID, "enabled", normalized != nil)
+	service.publishGameUpdated(current)
+	return current, nil
+}
@@ -0,0 +1,224 @@
+// Google ドライブへの OAuth 接続（デバイス認可フロー）と、同期に使う Drive のオブジェクトストアを提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+	"CloudLaunch_Go/internal/infrastructure/httpclient"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// GoogleDriveCredentialKey は Google ドライブのリフレッシュトークンを保存するキー。
+const GoogleDriveCredentialKey = "default"
+
+// ErrGoogleDriveNotConnected は Google ドライブに未接続（リフレッシュトークンが無い）ことを表す。
+var ErrGoogleDriveNotConnected = errors.New("Google ドライブに接続されていません")
+
+// GoogleDriveStatus は Google ドライブへの接続状態を表す。
+type GoogleDriveStatus struct {
+	// Configured は OAuth クライアント ID が設定され、接続を始められるか。
+	Configured bool `json:"configured"`
+	Connected  bool `json:"connected"`
+}
+
+// GoogleDriveDeviceCode は接続のためにユーザーへ提示するコード。VerificationURL を開いて UserCode を入力してもらう。
+type GoogleDriveDeviceCode struct {
+	UserCode        string    `json:"userCode"`
+	VerificationURL string    `json:"verificationUrl"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+}
+
+// GoogleDriveService は Google ドライブへの接続を管理する。リフレッシュトークンは認証情報ストアに保存し、
+// アクセストークンとフォルダ ID は同じリフレッシュトークンの間、オブジェクトストアごと使い回す。
+type GoogleDriveService struct {
+	store  credentials.Store
+	logger *slog.Logger
+
+	mu         sync.Mutex
+	auth       *storage.DriveAuth
+	httpClient *http.Client
+	objects    *storage.DriveObjectStore
+	tokens     *storage.DriveTokenSource
+	pending    *storage.DriveDeviceCode
+	// cancelWait は待機中の CompleteAuthorization を止める（接続をやり直したとき）。
+	cancelWait context.CancelFunc
+}
+
+// NewGoogleDriveService は GoogleDriveService を生成する。store は Google ドライブ専用の名前空間のストア。
+// HTTP 設定が不正な場合はプロキシ等を使わない既定クライアントで続行する。
+func NewGoogleDriveService(cfg config.Config, store credentials.Store, logger *slog.Logger) *GoogleDriveService {
+	service := &GoogleDriveService{store: store, logger: logger}
+	client, err := NewGoogleDriveHTTPClient(cfg)
+	if err != nil {
+		logger.Warn("HTTP設定が不正なため既定のクライアントを使用", "error", err)
+		client, _ = httpclient.New(httpclient.Options{Timeout: maxHTTPTimeoutSeconds * time.Second})
+	}
+	service.SetClient(cfg.GoogleDriveClientID, cfg.GoogleDriveClientSecret, client)
+	return service
+}
+
+// SetClient は OAuth クライアントと HTTP クライアントを設定する。httpClient が nil なら既定のクライアントを使う。
+// 使い回していたアクセストークンは捨てる。
+func (service *GoogleDriveService) SetClient(clientID, clientSecret string, httpClient *http.Client) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.httpClient = httpClient
+	service.auth = storage.NewDriveAuth(clientID, clientSecret, httpClient)
+	service.objects = nil
+	service.tokens = nil
+	service.pending = nil
+	if service.cancelWait != nil {
+		service.cancelWait()
+		service.cancelWait = nil
+	}
+}
+
+// SetHTTPClient は実行時に HTTP 通信設定が変わった際、通信に使うクライアントだけを差し替える。
+// SetClient と違い、使い回しているアクセストークン・フォルダ ID や接続待ちのコードはそのまま残す。
+func (service *GoogleDriveService) SetHTTPClient(httpClient *http.Client) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.httpClient = httpClient
+	service.auth.SetHTTPClient(httpClient)
+	if service.objects != nil {
+		service.objects.SetHTTPClient(httpClient)
+	}
+}
+
+// Status は接続状態を返す。
+func (service *GoogleDriveService) Status(ctx context.Context) (GoogleDriveStatus, error) {
+	service.mu.Lock()
+	configured := service.auth.Configured()
+	service.mu.Unlock()
+	refreshToken, err := service.refreshToken(ctx)
+	if err != nil {
+		return GoogleDriveStatus{}, err
+	}
+	return GoogleDriveStatus{Configured: configured, Connected: refreshToken != ""}, nil
+}
+
+// StartAuthorization はデバイス認可フローを開始し、ユーザーに提示するコードを返す。
+// 続けて CompleteAuthorization を呼ぶと、ユーザーが認可を終えるまで待ってから接続を保存する。
+func (service *GoogleDriveService) StartAuthorization(ctx context.Context) (GoogleDriveDeviceCode, error) {
+	service.mu.Lock()
+	auth := service.auth
+	service.mu.Unlock()
+	if !auth.Configured() {
+		return GoogleDriveDeviceCode{}, newServiceError("Google ドライブの OAuth クライアントが未設定です", "CLOUDLAUNCH_GDRIVE_CLIENT_ID is empty")
+	}
+	if service.store == nil {
+		return GoogleDriveDeviceCode{}, newServiceError("接続情報の保存先が未初期化です", "credential store is nil")
+	}
+	code, err := auth.RequestDeviceCode(ctx)
+	if err != nil {
+		return GoogleDriveDeviceCode{}, newServiceError("Google ドライブへの接続を開始できません", err.Error())
+	}
+	service.mu.Lock()
+	if service.cancelWait != nil {
+		service.cancelWait()
+		service.cancelWait = nil
+	}
+	service.pending = &code
+	service.mu.Unlock()
+	return GoogleDriveDeviceCode{UserCode: code.UserCode, VerificationURL: code.VerificationURL, ExpiresAt: code.ExpiresAt}, nil
+}
+
+// CompleteAuthorization は StartAuthorization で提示したコードの認可を待ち、リフレッシュトークンを保存する。
+// 期限切れ・拒否・ctx の終了でエラーを返し、StartAuthorization でやり直した場合は context.Canceled を返す。
+// 既存の暗号化パスフレーズは引き継ぐ。
+func (service *GoogleDriveService) CompleteAuthorization(ctx context.Context) error {
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	service.mu.Lock()
+	auth, pending := service.auth, service.pending
+	if pending != nil {
+		if service.cancelWait != nil {
+			service.cancelWait()
+		}
+		service.cancelWait = cancel
+	}
+	service.mu.Unlock()
+	if pending == nil {
+		return newServiceError("Google ドライブへの接続が開始されていません", "no pending device code")
+	}
+	token, err := auth.WaitForToken(waitCtx, *pending)
+	service.mu.Lock()
+	if service.pending == pending {
+		service.pending = nil
+		service.cancelWait = nil
+	}
+	service.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken == "" {
+		return newServiceError("Google ドライブのリフレッシュトークンを取得できません", "refresh_token is empty")
+	}
+	credential := credentials.Credential{OAuthRefreshToken: token.RefreshToken}
+	if existing, err := service.store.Load(ctx, GoogleDriveCredentialKey); err == nil && existing != nil {
+		credential.EncryptionPassphrase = existing.EncryptionPassphrase
+	}
+	if err := service.store.Save(ctx, GoogleDriveCredentialKey, credential); err != nil {
+		return newServiceError("Google ドライブの接続情報の保存に失敗しました", err.Error())
+	}
+	service.logger.Info("Google ドライブに接続")
+	return nil
+}
+
+// Disconnect は保存したリフレッシュトークンを削除する。Google 側の許可はアカウントの設定から取り消す。
+func (service *GoogleDriveService) Disconnect(ctx context.Context) error {
+	if service.store == nil {
+		return newServiceError("接続情報の保存先が未初期化です", "credential store is nil")
+	}
+	if err := service.store.Delete(ctx, GoogleDriveCredentialKey); err != nil {
+		return newServiceError("Google ドライブの接続情報の削除に失敗しました", err.Error())
+	}
+	service.mu.Lock()
+	service.objects = nil
+	service.tokens = nil
+	service.mu.Unlock()
+	service.logger.Info("Google ドライブの接続を解除")
+	return nil
+}
+
+// ObjectStore は保存済みのリフレッシュトークンで接続した Drive のオブジェクトストアを返す。
+// 未接続なら ErrGoogleDriveNotConnected を返す。
+func (service *GoogleDriveService) ObjectStore(ctx context.Context) (*storage.DriveObjectStore, error) {
+	refreshToken, err := service.refreshToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if refreshToken == "" {
+		return nil, ErrGoogleDriveNotConnected
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.objects != nil && service.tokens.RefreshToken() == refreshToken {
+		return service.objects, nil
+	}
+	service.tokens = storage.NewDriveTokenSource(service.auth, refreshToken)
+	service.objects = storage.NewDriveObjectStore(service.tokens, service.httpClient)
+	return service.objects, nil
+}
+
+func (service *GoogleDriveService) refreshToken(ctx context.Context) (string, error) {
+	if service.store == nil {
+		return "", nil
+	}
+	credential, err := service.store.Load(ctx, GoogleDriveCredentialKey)
+	if err != nil {
+		return "", newServiceError("Google ドライブの接続情報の取得に失敗しました", err.Error())
+	}
+	if credential == nil {
+		return "", nil
+	}
+	return strings.TrimSpace(credential.OAuthRefreshToken), nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+)
+
+func TestGoogleDriveServiceObjectStoreRequiresConnection(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &fakeCredentialStore{}
+	service := NewGoogleDriveService(config.Config{GoogleDriveClientID: "client"}, store, newTestLogger())
+
+	status, err := service.Status(ctx)
+	if err != nil || !status.Configured || status.Connected {
+		t.Fatalf("unexpected status before connecting: %+v %v", status, err)
+	}
+	if _, err := service.ObjectStore(ctx); !errors.Is(err, ErrGoogleDriveNotConnected) {
+		t.Fatalf("ObjectStore without a token should fail, got %v", err)
+	}
+
+	store.loadResult = &credentials.Credential{OAuthRefreshToken: "refresh"}
+	first, err := service.ObjectStore(ctx)
+	if err != nil {
+		t.Fatalf("ObjectStore: %v", err)
+	}
+	second, _ := service.ObjectStore(ctx)
+	if first != second {
+		t.Fatal("the object store should be reused while the refresh token is unchanged")
+	}
+	store.loadResult = &credentials.Credential{OAuthRefreshToken: "other"}
+	if third, _ := service.ObjectStore(ctx); third == first {
+		t.Fatal("a new refresh token should create a new object store")
+	}
+	if status, _ := service.Status(ctx); !status.Connected {
+		t.Fatalf("status should be connected: %+v", status)
+	}
+}
+
+func TestGoogleDriveServiceSetHTTPClientKeepsSession(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &fakeCredentialStore{loadResult: &credentials.Credential{OAuthRefreshToken: "refresh"}}
+	service := NewGoogleDriveService(config.Config{GoogleDriveClientID: "client"}, store, newTestLogger())
+	first, err := service.ObjectStore(ctx)
+	if err != nil {
+		t.Fatalf("ObjectStore: %v", err)
+	}
+
+	service.SetHTTPClient(&http.Client{})
+	if second, _ := service.ObjectStore(ctx); second != first {
+		t.Fatal("changing the HTTP client should keep the cached object store")
+	}
+	service.SetClient("other", "", nil)
+	if third, _ := service.ObjectStore(ctx); third == first {
+		t.Fatal("changing the OAuth client should drop the cached object store")
+	}
+}
+
+func TestGoogleDriveServiceStartAuthorizationRequiresClientID(t *testing.T) {
+	t.Parallel()
+
+	service := NewGoogleDriveService(config.Config{}, &fakeCredentialStore{}, newTestLogger())
+	if _, err := service.StartAuthorization(context.Background()); err == nil {
+		t.Fatal("StartAuthorization without a client ID should fail")
+	}
+	if err := service.CompleteAuthorization(context.Background()); err == nil {
+		t.Fatal("CompleteAuthorization without a pending code should fail")
+	}
+}
+
+func TestContentSyncServiceGoogleDriveBackendRequiresConnection(t *testing.T) {
+	t.Parallel()
+
+	game := baseGame(t.TempDir())
+	svc := newTestService(newFakeRepo(&game, nil), nil)
+	svc.SetStorageBackend(StorageBackendGoogleDrive, "")
+	if _, err := svc.newStorageBlobStore(context.Background()); !errors.Is(err, ErrGoogleDriveNotConnected) {
+		t.Fatalf("unconnected Google Drive backend should fail, got %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"strings"
 	"testing"
+
+	"CloudLaunch_Go/internal/config"
 )
 
 func TestParseHotkeyCombo(t *testing.T) {
@@ -94,3 +96,28 @@ func TestValidateHotkeyCombo(t *testing.T) {
 		t.Fatal("expected error for Nope")
 	}
 }
+
+func TestNormalizeHotkeyActions(t *testing.T) {
+	t.Parallel()
+
+	normalized, err := NormalizeHotkeyActions(config.HotkeyActions{PauseResume: " Ctrl+Alt+P ", SyncNow: "F9"}, "Ctrl+Alt+S")
+	if err != nil {
+		t.Fatalf("NormalizeHotkeyActions: %v", err)
+	}
+	if normalized.PauseResume != "Ctrl+Alt+P" || normalized.EndSession != "" || normalized.SyncNow != "F9" {
+		t.Fatalf("unexpected actions: %+v", normalized)
+	}
+
+	// 表記が違っても同じキーの組み合わせは重複とみなす。
+	if _, err := NormalizeHotkeyActions(config.HotkeyActions{EndSession: "alt+ctrl+s"}, "Ctrl+Alt+S"); err == nil ||
+		!strings.Contains(err.Error(), "screenshot") {
+		t.Fatalf("expected conflict with screenshot hotkey, got %v", err)
+	}
+	if _, err := NormalizeHotkeyActions(config.HotkeyActions{PauseResume: "F9", SyncNow: "F9"}, ""); err == nil {
+		t.Fatal("expected conflict between actions")
+	}
+	if _, err := NormalizeHotkeyActions(config.HotkeyActions{SyncNow: "Ctrl+Foo"}, ""); err == nil ||
+		!strings.Contains(err.Error(), "syncNow") {
+		t.Fatalf("expected invalid combo error, got %v", err)
+	}
+}
@@ -1,15 +1,47 @@
 // グローバルホットキーの共通定義。
 package services
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"CloudLaunch_Go/internal/config"
+)
+
+// ホットキーに割り当てられる操作。スクリーンショット撮影は ScreenshotHotkey で別に設定する。
+const (
+	// HotkeyActionPauseResume は現在のセッションの中断・再開を切り替える。
+	HotkeyActionPauseResume = "pauseResume"
+	// HotkeyActionEndSession は現在のセッションを終了して保存する。
+	HotkeyActionEndSession = "endSession"
+	// HotkeyActionSyncNow は現在（直近）のゲームをすぐにクラウドへアップロードする。
+	HotkeyActionSyncNow = "syncNow"
+	// HotkeyActionSaveClip は録画中の直近の映像を動画クリップとして保存する。
+	HotkeyActionSaveClip = "saveClip"
+)
+
+// HotkeyActionNames は割り当てられる操作の一覧。
+var HotkeyActionNames = []string{HotkeyActionPauseResume, HotkeyActionEndSession, HotkeyActionSyncNow, HotkeyActionSaveClip}
 
 // HotkeyHandler はホットキー押下時の処理を受け取る。
 type HotkeyHandler func() bool
 
-// HotkeyConfig はホットキー設定を保持する。
+// HotkeyActionHandler は追加のホットキー押下時の処理を受け取る。ok が true なら message を通知する。
+type HotkeyActionHandler func() (message string, ok bool)
+
+// HotkeyBinding はスクリーンショット以外に登録するホットキー1件を表す。
+type HotkeyBinding struct {
+	Action  string
+	Combo   string
+	Handler HotkeyActionHandler
+}
+
+// HotkeyConfig はホットキー設定を保持する。Combo はスクリーンショット撮影用（空なら登録しない）。
 type HotkeyConfig struct {
-	Combo  string
-	Notify bool
+	Combo    string
+	Notify   bool
+	Bindings []HotkeyBinding
 }
 
 // HotkeyService はグローバルホットキーを管理する。
@@ -24,3 +56,49 @@ type HotkeyService interface {
 func NewHotkeyService(logger *slog.Logger, config HotkeyConfig, handler HotkeyHandler) HotkeyService {
 	return newHotkeyService(logger, config, handler)
 }
+
+// HotkeyActionCombo は割り当てから action のキーの組み合わせを返す。
+func HotkeyActionCombo(actions config.HotkeyActions, action string) string {
+	switch action {
+	case HotkeyActionPauseResume:
+		return actions.PauseResume
+	case HotkeyActionEndSession:
+		return actions.EndSession
+	case HotkeyActionSyncNow:
+		return actions.SyncNow
+	case HotkeyActionSaveClip:
+		return actions.SaveClip
+	default:
+		return ""
+	}
+}
+
+// NormalizeHotkeyActions は操作ごとのホットキーを検証し、前後の空白を除いて返す。
+// 同じキーの組み合わせを複数の操作やスクリーンショット撮影（screenshotCombo）に割り当てることはできない。
+func NormalizeHotkeyActions(actions config.HotkeyActions, screenshotCombo string) (config.HotkeyActions, error) {
+	used := make(map[[2]uint32]string, len(HotkeyActionNames)+1)
+	if modifiers, key, err := parseHotkeyCombo(screenshotCombo); err == nil {
+		used[[2]uint32{modifiers, key}] = "screenshot"
+	}
+	normalized := config.HotkeyActions{
+		PauseResume: strings.TrimSpace(actions.PauseResume),
+		EndSession:  strings.TrimSpace(actions.EndSession),
+		SyncNow:     strings.TrimSpace(actions.SyncNow),
+		SaveClip:    strings.TrimSpace(actions.SaveClip),
+	}
+	for _, action := range HotkeyActionNames {
+		combo := HotkeyActionCombo(normalized, action)
+		if combo == "" {
+			continue
+		}
+		modifiers, key, err := parseHotkeyCombo(combo)
+		if err != nil {
+			return config.HotkeyActions{}, fmt.Errorf("hotkeyActions.%s: %w", action, err)
+		}
+		if other, exists := used[[2]uint32{modifiers, key}]; exists {
+			return config.HotkeyActions{}, fmt.Errorf("hotkeyActions.%s: %s is already assigned to %s", action, combo, other)
+		}
+		used[[2]uint32{modifiers, key}] = action
+	}
+	return normalized, nil
+}
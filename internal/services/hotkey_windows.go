@@ -59,17 +59,25 @@ var (
 )
 
 type hotkeyServiceWindows struct {
-	logger     *slog.Logger
-	handler    HotkeyHandler
-	modifiers  uint32
-	key        uint32
-	notify     atomic.Bool
-	notifyHWND windows.Handle
-	started    atomic.Bool
-	capturing  atomic.Bool
-	threadID   uint32
-	stoppedCh  chan struct{}
-	mu         sync.Mutex
+	logger        *slog.Logger
+	registrations []*hotkeyRegistration
+	notify        atomic.Bool
+	notifyHWND    windows.Handle
+	started       atomic.Bool
+	threadID      uint32
+	stoppedCh     chan struct{}
+	mu            sync.Mutex
+}
+
+// hotkeyRegistration は RegisterHotKey で登録する1件。id は hotkeyID からの連番。
+// running は処理中に同じホットキーが押されたときに読み飛ばすためのフラグ。
+type hotkeyRegistration struct {
+	id        uintptr
+	label     string
+	modifiers uint32
+	key       uint32
+	handler   HotkeyActionHandler
+	running   atomic.Bool
 }
 
 type hotkeyPoint struct {
@@ -125,20 +133,35 @@ var (
 )
 
 func newHotkeyService(logger *slog.Logger, config HotkeyConfig, handler HotkeyHandler) HotkeyService {
-	modifiers, key, err := parseHotkeyCombo(config.Combo)
-	if err != nil {
-		if logger != nil {
-			logger.Warn("ホットキー設定の解析に失敗しました", "combo", config.Combo, "error", err)
+	service := &hotkeyServiceWindows{logger: logger}
+	service.notify.Store(config.Notify)
+	add := func(label string, combo string, actionHandler HotkeyActionHandler) {
+		modifiers, key, err := parseHotkeyCombo(combo)
+		if err != nil {
+			if logger != nil {
+				logger.Warn("ホットキー設定の解析に失敗しました", "action", label, "combo", combo, "error", err)
+			}
+			return
 		}
-		return &hotkeyServiceWindows{logger: logger}
-	}
-	service := &hotkeyServiceWindows{
-		logger:    logger,
-		handler:   handler,
-		modifiers: modifiers,
-		key:       key,
+		service.registrations = append(service.registrations, &hotkeyRegistration{
+			id:        uintptr(hotkeyID + len(service.registrations)),
+			label:     label,
+			modifiers: modifiers,
+			key:       key,
+			handler:   actionHandler,
+		})
+	}
+	if strings.TrimSpace(config.Combo) != "" && handler != nil {
+		add("screenshot", config.Combo, func() (string, bool) {
+			return "スクリーンショットを保存しました", handler()
+		})
+	}
+	for _, binding := range config.Bindings {
+		if strings.TrimSpace(binding.Combo) == "" || binding.Handler == nil {
+			continue
+		}
+		add(binding.Action, binding.Combo, binding.Handler)
 	}
-	service.notify.Store(config.Notify)
 	return service
 }
 
@@ -146,12 +169,9 @@ func (service *hotkeyServiceWindows) Start() error {
 	if service == nil {
 		return errors.New("hotkey service is nil")
 	}
-	if service.modifiers == 0 || service.key == 0 {
+	if len(service.registrations) == 0 {
 		return errors.New("hotkey is not configured")
 	}
-	if service.handler == nil {
-		return errors.New("hotkey handler is nil")
-	}
 	if service.started.Swap(true) {
 		return nil
 	}
@@ -243,25 +263,37 @@ func (service *hotkeyServiceWindows) run(readyCh chan<- error) {
 	service.threadID = uint32(threadID)
 	service.mu.Unlock()
 
-	ok, _, err := procRegisterHotKey.Call(0, hotkeyID, uintptr(service.modifiers), uintptr(service.key))
-	if ok == 0 {
-		regErr := fmt.Errorf("RegisterHotKey failed: %w", err)
-		if service.logger != nil {
-			service.logger.Error("ホットキー登録に失敗しました", "error", err)
+	// 1件でも登録できなければ全て取り消し、設定した割り当てが一部だけ効く状態にしない。
+	for index, registration := range service.registrations {
+		ok, _, err := procRegisterHotKey.Call(0, registration.id, uintptr(registration.modifiers), uintptr(registration.key))
+		if ok == 0 {
+			combo := formatHotkey(registration.modifiers, registration.key)
+			regErr := fmt.Errorf("RegisterHotKey failed for %s (%s): %w", combo, registration.label, err)
+			if service.logger != nil {
+				service.logger.Error("ホットキー登録に失敗しました", "action", registration.label, "combo", combo, "error", err)
+			}
+			for _, registered := range service.registrations[:index] {
+				procUnregisterHotKey.Call(0, registered.id)
+			}
+			readyCh <- regErr
+			close(service.stoppedCh)
+			return
 		}
-		readyCh <- regErr
-		close(service.stoppedCh)
-		return
 	}
 	service.initNotifyIcon()
 	if service.logger != nil {
-		service.logger.Info("ホットキーを登録しました", "combo", formatHotkey(service.modifiers, service.key))
+		for _, registration := range service.registrations {
+			service.logger.Info("ホットキーを登録しました", "action", registration.label,
+				"combo", formatHotkey(registration.modifiers, registration.key))
+		}
 	}
 	readyCh <- nil
 
 	defer func() {
 		service.cleanupNotifyIcon()
-		procUnregisterHotKey.Call(0, hotkeyID)
+		for _, registration := range service.registrations {
+			procUnregisterHotKey.Call(0, registration.id)
+		}
 		close(service.stoppedCh)
 	}()
 
@@ -273,19 +305,23 @@ func (service *hotkeyServiceWindows) run(readyCh chan<- error) {
 		}
 		switch msg.Message {
 		case wmHotkey:
+			registration := service.registrationByID(msg.WParam)
+			if registration == nil {
+				continue
+			}
 			if service.logger != nil {
-				service.logger.Debug("ホットキーを受信しました")
+				service.logger.Debug("ホットキーを受信しました", "action", registration.label)
 			}
-			if !service.capturing.CompareAndSwap(false, true) {
+			if !registration.running.CompareAndSwap(false, true) {
 				if service.logger != nil {
-					service.logger.Debug("キャプチャ実行中のためホットキー入力をスキップ")
+					service.logger.Debug("実行中のためホットキー入力をスキップ", "action", registration.label)
 				}
 				continue
 			}
 			go func() {
-				defer service.capturing.Store(false)
-				if service.handler() {
-					service.showHotkeyNotification("スクリーンショットを保存しました")
+				defer registration.running.Store(false)
+				if message, ok := registration.handler(); ok {
+					service.showHotkeyNotification(message)
 				}
 			}()
 		case wmInitNotifyIcon:
@@ -297,6 +333,15 @@ func (service *hotkeyServiceWindows) run(readyCh chan<- error) {
 	}
 }
 
+func (service *hotkeyServiceWindows) registrationByID(id uintptr) *hotkeyRegistration {
+	for _, registration := range service.registrations {
+		if registration.id == id {
+			return registration
+		}
+	}
+	return nil
+}
+
 func formatHotkey(modifiers uint32, key uint32) string {
 	parts := make([]string, 0, 4)
 	if modifiers&modControl != 0 {
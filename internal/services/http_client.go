@@ -0,0 +1,43 @@
+// 外部サイト（批評空間・Webhook 等）への HTTP 通信に使うクライアントを設定から生成する。
+package services
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/infrastructure/httpclient"
+)
+
+const (
+	maxHTTPTimeoutSeconds = 300
+	maxHTTPRetries        = 5
+)
+
+// ValidateHTTPSettings は HTTP 通信設定（タイムアウト秒・プロキシURL・リトライ回数）を検証する。
+func ValidateHTTPSettings(timeoutSeconds int, proxyURL string, maxRetries int) error {
+	if timeoutSeconds < 1 || timeoutSeconds > maxHTTPTimeoutSeconds {
+		return errors.New("httpTimeoutSeconds must be 1-300")
+	}
+	if maxRetries < 0 || maxRetries > maxHTTPRetries {
+		return errors.New("httpMaxRetries must be 0-5")
+	}
+	return httpclient.ValidateProxyURL(proxyURL)
+}
+
+// NewHTTPClient は Config の HTTP 通信設定からクライアントを生成する。
+func NewHTTPClient(cfg config.Config) (*http.Client, error) {
+	return httpclient.New(httpclient.Options{
+		Timeout:    time.Duration(cfg.HTTPTimeoutSeconds) * time.Second,
+		ProxyURL:   cfg.HTTPProxyURL,
+		MaxRetries: cfg.HTTPMaxRetries,
+	})
+}
+
+// NewGoogleDriveHTTPClient は Google ドライブ用のクライアントを生成する。プロキシ・リトライ回数は Config に従うが、
+// セーブデータの送受信は API 呼び出しより長くかかるため、1リクエストのタイムアウトは上限値（300秒）を下回らせない。
+func NewGoogleDriveHTTPClient(cfg config.Config) (*http.Client, error) {
+	cfg.HTTPTimeoutSeconds = max(cfg.HTTPTimeoutSeconds, maxHTTPTimeoutSeconds)
+	return NewHTTPClient(cfg)
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+// 非Windows向けの無操作時間取得のスタブ実装。
+package services
+
+import (
+	"errors"
+	"time"
+)
+
+// systemIdleDuration は非Windowsではサポート外。無操作検出は行われず、計測は従来どおり続く。
+func systemIdleDuration() (time.Duration, error) {
+	return 0, errors.New("idle detection is only supported on Windows")
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+// Windows向けに最後のユーザー入力からの経過時間を取得する。
+package services
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+var (
+	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
+	procGetTickCount     = kernel32dll.NewProc("GetTickCount")
+)
+
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// systemIdleDuration はキーボード・マウスの最後の入力からの経過時間を返す。
+// dwTime と GetTickCount はどちらも約49日で一周する32ビットのミリ秒なので、差は uint32 のまま取る。
+func systemIdleDuration() (time.Duration, error) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	ret, _, callErr := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		if callErr != nil {
+			return 0, callErr
+		}
+		return 0, errors.New("GetLastInputInfo failed")
+	}
+	tick, _, _ := procGetTickCount.Call()
+	elapsed := uint32(tick) - info.dwTime
+	return time.Duration(elapsed) * time.Millisecond, nil
+}
@@ -0,0 +1,144 @@
+// ゲームの起動前・終了後に実行するコマンド（起動フック）の実行を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// launchHookTimeout は起動フック1回の実行時間の上限。超えたらプロセスを止めて失敗として扱う。
+	launchHookTimeout = 2 * time.Minute
+	// launchHookWaitDelay はフックの終了後、出力パイプが閉じられるのを待つ時間。
+	// start で起動した常駐ツール（コントローラー設定ツール等）がパイプを引き継ぐと Wait が戻らないため打ち切る。
+	launchHookWaitDelay = 2 * time.Second
+	// launchHookOutputLimit はログに残すフックの出力の上限（バイト）。
+	launchHookOutputLimit = 2048
+)
+
+// 起動フックの種類（ログ用）。
+const (
+	launchHookPreLaunch = "preLaunch"
+	launchHookPostExit  = "postExit"
+)
+
+// launchHookRunner はコマンド文字列を作業フォルダ dir で実行し、標準出力と標準エラーをまとめて返す（テストで差し替える）。
+type launchHookRunner func(ctx context.Context, script string, dir string) ([]byte, error)
+
+// runShellCommand はコマンド文字列をシェル（Windows では cmd.exe）で実行する。
+func runShellCommand(ctx context.Context, script string, dir string) ([]byte, error) {
+	command := execShellHidden(ctx, script)
+	command.Dir = dir
+	command.WaitDelay = launchHookWaitDelay
+	output, err := command.CombinedOutput()
+	if errors.Is(err, exec.ErrWaitDelay) {
+		// フック自体は正常終了しており、残っているのは起動した常駐ツールのパイプだけ。
+		err = nil
+	}
+	return output, err
+}
+
+// normalizeLaunchHook は起動フックの入力を保存形式にする。空（空白のみ）なら nil（解除）。
+func normalizeLaunchHook(command string) *string {
+	trimmed := strings.TrimSpace(command)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// runLaunchHook は起動フックをゲームのフォルダで実行して終了を待ち、結果をログに残す。
+// 終了コードが 0 以外、または timeout 以内に終わらなかった場合はエラーを返す。
+func runLaunchHook(
+	ctx context.Context,
+	logger *slog.Logger,
+	runner launchHookRunner,
+	stage string,
+	game domain.Game,
+	script string,
+	timeout time.Duration,
+) error {
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	logger.Info("起動フックを実行", "stage", stage, "gameId", game.ID, "command", script)
+	output, err := runner(hookCtx, script, filepath.Dir(game.ExePath))
+	elapsed := time.Since(startedAt)
+	if errors.Is(hookCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%s 以内に終了しませんでした", timeout)
+	}
+	trimmedOutput := truncateHookOutput(output)
+	if err != nil {
+		logger.Warn("起動フックに失敗", "stage", stage, "gameId", game.ID, "elapsed", elapsed, "error", err, "output", trimmedOutput)
+		return err
+	}
+	logger.Info("起動フックが完了", "stage", stage, "gameId", game.ID, "elapsed", elapsed, "output", trimmedOutput)
+	return nil
+}
+
+// truncateHookOutput はログ用にフックの出力を前後の空白を除いて上限まで切り詰める。
+func truncateHookOutput(output []byte) string {
+	trimmed := strings.TrimSpace(string(output))
+	if len(trimmed) <= launchHookOutputLimit {
+		return trimmed
+	}
+	return strings.ToValidUTF8(trimmed[:launchHookOutputLimit], "") + "…"
+}
+
+// RunPreLaunchHook は実行ファイルパスに対応するゲームの起動前コマンドを実行し、終了を待つ。
+// 登録されていない実行ファイルや、起動前コマンドが未設定なら何もしない。
+// 失敗・タイムアウトはエラーとして返し、呼び出し側はゲームの起動を中止する（マウント前に起動しても動かないため）。
+func (service *GameService) RunPreLaunchHook(ctx context.Context, exePath string) error {
+	game, error := service.repository.GetGameByExePath(ctx, exePath)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if game == nil || game.PreLaunchCommand == nil {
+		return nil
+	}
+	if error := runLaunchHook(ctx, service.logger, service.hookRunner, launchHookPreLaunch, *game, *game.PreLaunchCommand, launchHookTimeout); error != nil {
+		return newServiceError("起動前コマンドの実行に失敗しました", error.Error())
+	}
+	return nil
+}
+
+// takePostExitHook は今回のプレイの終了後コマンドをまだ実行へ回していなければ実行済みにして true を返す。
+// セッションの終了（確認・アプリ終了時の保存）と監視からの除去の両方で呼ぶため、1回のプレイで二重に実行しない。
+// service.mu を保持した状態で呼ぶ。
+func (game *MonitoringGame) takePostExitHook() bool {
+	if game.PostExitDone {
+		return false
+	}
+	game.PostExitDone = true
+	return true
+}
+
+// startPostExitHook は終了後コマンドを BackgroundTasks で実行する。
+func (service *ProcessMonitorService) startPostExitHook(gameID string) {
+	service.tasks.Go(func() { service.runPostExitHook(gameID) })
+}
+
+// runPostExitHook はゲームの終了後コマンドを実行する。監視ループを止めないよう別 goroutine で呼ぶ。
+// 設定の変更を反映するため、実行時点のゲーム情報を読み直す。失敗はログに残すだけにする。
+func (service *ProcessMonitorService) runPostExitHook(gameID string) {
+	ctx := service.context()
+	game, err := service.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		service.logger.Warn("終了後コマンドのためのゲーム取得に失敗", "gameId", gameID, "error", err)
+		return
+	}
+	if game == nil || game.PostExitCommand == nil {
+		return
+	}
+	_ = runLaunchHook(ctx, service.logger, service.hookRunner, launchHookPostExit, *game, *game.PostExitCommand, launchHookTimeout)
+}
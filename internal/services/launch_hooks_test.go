@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestRunLaunchHookReportsTimeout(t *testing.T) {
+	t.Parallel()
+
+	runner := func(ctx context.Context, script string, dir string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	err := runLaunchHook(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), runner,
+		launchHookPreLaunch, domain.Game{ID: "game-1", ExePath: "/games/game.exe"}, "mount.bat", 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "以内に終了しませんでした") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestRunShellCommandReturnsOutput(t *testing.T) {
+	t.Parallel()
+
+	output, err := runShellCommand(context.Background(), "echo hook", t.TempDir())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "hook" {
+		t.Fatalf("unexpected output: %q", output)
+	}
+}
+
+func TestGameServiceRunPreLaunchHookRunsInGameFolder(t *testing.T) {
+	t.Parallel()
+
+	command := "mount.bat"
+	repository := &fakeGameRepository{byExePath: &domain.Game{ID: "game-1", ExePath: "/games/title/game.exe", PreLaunchCommand: &command}}
+	service := NewGameService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var gotScript, gotDir string
+	service.hookRunner = func(ctx context.Context, script string, dir string) ([]byte, error) {
+		gotScript, gotDir = script, dir
+		return []byte("mounted"), nil
+	}
+
+	if err := service.RunPreLaunchHook(context.Background(), "/games/title/game.exe"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotScript != "mount.bat" || gotDir != "/games/title" {
+		t.Fatalf("unexpected hook call: script=%q dir=%q", gotScript, gotDir)
+	}
+
+	service.hookRunner = func(ctx context.Context, script string, dir string) ([]byte, error) {
+		return []byte("drive not found"), errors.New("exit status 1")
+	}
+	err := service.RunPreLaunchHook(context.Background(), "/games/title/game.exe")
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) || serviceErr.Message != "起動前コマンドの実行に失敗しました" {
+		t.Fatalf("expected service error, got %v", err)
+	}
+}
+
+func TestGameServiceRunPreLaunchHookSkipsGamesWithoutHook(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeGameRepository{byExePath: &domain.Game{ID: "game-1", ExePath: "/games/game.exe"}}
+	service := NewGameService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.hookRunner = func(ctx context.Context, script string, dir string) ([]byte, error) {
+		t.Fatal("hook should not run")
+		return nil, nil
+	}
+	if err := service.RunPreLaunchHook(context.Background(), "/games/game.exe"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	repository.byExePath = nil
+	if err := service.RunPreLaunchHook(context.Background(), "/other/tool.exe"); err != nil {
+		t.Fatalf("expected unregistered exe to be ignored, got %v", err)
+	}
+}
+
+func TestGameServiceUpdateGameSetsAndClearsLaunchHooks(t *testing.T) {
+	t.Parallel()
+
+	existing := "sync.bat"
+	var updatedGame domain.Game
+	service := NewGameService(&fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game", Publisher: "Pub", ExePath: "/games/game.exe", PostExitCommand: &existing}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) {
+			updatedGame = game
+			return &game, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	pre := "  mount.bat  "
+	if _, err := service.UpdateGame(context.Background(), "game-1", GameUpdateInput{
+		Title: "Game", Publisher: "Pub", ExePath: "/games/game.exe", PreLaunchCommand: &pre,
+	}); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if updatedGame.PreLaunchCommand == nil || *updatedGame.PreLaunchCommand != "mount.bat" {
+		t.Fatalf("expected trimmed pre-launch command, got %#v", updatedGame.PreLaunchCommand)
+	}
+	if updatedGame.PostExitCommand == nil || *updatedGame.PostExitCommand != "sync.bat" {
+		t.Fatalf("expected post-exit command to be kept, got %#v", updatedGame.PostExitCommand)
+	}
+
+	empty := " "
+	if _, err := service.UpdateGame(context.Background(), "game-1", GameUpdateInput{
+		Title: "Game", Publisher: "Pub", ExePath: "/games/game.exe", PostExitCommand: &empty,
+	}); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if updatedGame.PostExitCommand != nil {
+		t.Fatalf("expected post-exit command to be cleared, got %#v", *updatedGame.PostExitCommand)
+	}
+}
+
+func TestProcessMonitorServiceRunsPostExitHookWhenGameIsCleanedUp(t *testing.T) {
+	t.Parallel()
+
+	command := "taskkill /IM mapper.exe"
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, ExePath: `C:\games\game.exe`, PostExitCommand: &command}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ran := make(chan string, 1)
+	service.hookRunner = func(ctx context.Context, script string, dir string) ([]byte, error) {
+		ran <- script
+		return nil, nil
+	}
+	service.processProvider = func() ([]ProcessInfo, string) { return nil, "test" }
+	notFound := time.Now().Add(-time.Minute)
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1", ExeName: "game.exe", ExePath: `C:\games\game.exe`, LastNotFound: &notFound}
+
+	service.checkProcesses()
+
+	if _, exists := service.monitoredGames["game-1"]; exists {
+		t.Fatal("expected game to be removed from monitoring")
+	}
+	select {
+	case script := <-ran:
+		if script != command {
+			t.Fatalf("unexpected hook script: %q", script)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected post-exit hook to run")
+	}
+}
+
+func newPostExitTestMonitor(t *testing.T, command string) (*ProcessMonitorService, *BackgroundTasks, *atomic.Int32) {
+	t.Helper()
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, ExePath: `C:\games\game.exe`, PostExitCommand: &command}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var runs atomic.Int32
+	service.hookRunner = func(ctx context.Context, script string, dir string) ([]byte, error) {
+		runs.Add(1)
+		return nil, nil
+	}
+	service.processProvider = func() ([]ProcessInfo, string) { return nil, "test" }
+	tasks := NewBackgroundTasks()
+	service.SetBackgroundTasks(tasks)
+	return service, tasks, &runs
+}
+
+func TestProcessMonitorServiceRunsPostExitHookOnceWhenSessionEnds(t *testing.T) {
+	t.Parallel()
+
+	service, tasks, runs := newPostExitTestMonitor(t, "backup.bat")
+	started := time.Now().Add(-time.Hour)
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1", ExeName: "game.exe", ExePath: `C:\games\game.exe`, PlayStartTime: &started}
+
+	if !service.EndSession("game-1") {
+		t.Fatal("expected session to end")
+	}
+	tasks.Wait()
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("expected the hook to run when the session ended, got %d runs", got)
+	}
+
+	// 終了を確認したゲームが監視から外れても、同じプレイの終了後コマンドは繰り返さない。
+	notFound := time.Now().Add(-time.Hour)
+	service.monitoredGames["game-1"].LastNotFound = &notFound
+	service.checkProcesses()
+	tasks.Wait()
+	if _, exists := service.monitoredGames["game-1"]; exists {
+		t.Fatal("expected game to be removed from monitoring")
+	}
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("expected the hook to run once per play, got %d runs", got)
+	}
+}
+
+func TestProcessMonitorServiceRunsPostExitHookForSessionsSavedAtShutdown(t *testing.T) {
+	t.Parallel()
+
+	service, tasks, runs := newPostExitTestMonitor(t, "backup.bat")
+	started := time.Now().Add(-time.Hour)
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1", ExeName: "game.exe", ExePath: `C:\games\game.exe`, PlayStartTime: &started}
+	service.monitoredGames["idle"] = &MonitoringGame{GameID: "idle", ExeName: "idle.exe", ExePath: `C:\games\idle.exe`}
+
+	service.saveAllActiveSessions()
+	tasks.Close()
+	tasks.Wait()
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("expected the hook to run only for the game with a saved session, got %d runs", got)
+	}
+}
@@ -0,0 +1,262 @@
+// ゲーム起動ラッパー（Locale Emulator / NTLEA など）の設定・検出・起動コマンド組み立てを提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// 引数テンプレートで置換するプレースホルダー。
+const (
+	launchPlaceholderExe  = "{exe}"
+	launchPlaceholderDir  = "{dir}"
+	launchPlaceholderName = "{name}"
+)
+
+// knownLaunchWrapper は検出対象の既知ラッパーと、既定の引数テンプレート・探索先を表す。
+// dirs は環境変数名とその配下の相対パスの組。
+type knownLaunchWrapper struct {
+	kind    string
+	name    string
+	exeName string
+	args    string
+	dirs    [][2]string
+}
+
+// knownLaunchWrappers は日本語ゲームでよく使われるロケール変換ツール。
+// Locale Emulator は既定プロファイルで起動する -run、NTLEA は日本語コードページ/ロケールを指定する。
+var knownLaunchWrappers = []knownLaunchWrapper{
+	{
+		kind:    "locale-emulator",
+		name:    "Locale Emulator",
+		exeName: "LEProc.exe",
+		args:    `-run "{exe}"`,
+		dirs: [][2]string{
+			{"ProgramFiles", "Locale Emulator"},
+			{"ProgramFiles(x86)", "Locale Emulator"},
+			{"LOCALAPPDATA", `Programs\Locale Emulator`},
+		},
+	},
+	{
+		kind:    "ntlea",
+		name:    "NTLEA",
+		exeName: "ntleas.exe",
+		args:    `"{exe}" C932 L1041`,
+		dirs: [][2]string{
+			{"ProgramFiles", "NTLEA"},
+			{"ProgramFiles(x86)", "NTLEA"},
+			{"LOCALAPPDATA", `Programs\NTLEA`},
+		},
+	},
+	{
+		kind:    "applocale",
+		name:    "Microsoft AppLocale",
+		exeName: "AppLoc.exe",
+		args:    `"{exe}" /L0411`,
+		dirs: [][2]string{
+			{"WINDIR", "AppPatch"},
+		},
+	},
+}
+
+// isLaunchWrapperExe は実行ファイル名（またはパス）が既知のラッパーかを返す。
+// ラッパーはゲーム本体を起動してすぐ終了するため、ゲームのプロセスとして扱わない。
+func isLaunchWrapperExe(exePath string) bool {
+	name := normalizeProcessToken(windowsPathBase(exePath))
+	if name == "" {
+		return false
+	}
+	for _, wrapper := range knownLaunchWrappers {
+		if name == normalizeProcessToken(wrapper.exeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectLaunchWrappers は既知のラッパーを既定のインストール先と PATH から探す。
+func DetectLaunchWrappers() []domain.LaunchWrapperCandidate {
+	return detectLaunchWrappers(os.Getenv, fileExists)
+}
+
+func detectLaunchWrappers(getenv func(string) string, exists func(string) bool) []domain.LaunchWrapperCandidate {
+	candidates := make([]domain.LaunchWrapperCandidate, 0, len(knownLaunchWrappers))
+	for _, wrapper := range knownLaunchWrappers {
+		if path := findLaunchWrapper(wrapper, getenv, exists); path != "" {
+			candidates = append(candidates, domain.LaunchWrapperCandidate{
+				Kind: wrapper.kind,
+				Name: wrapper.name,
+				Path: path,
+				Args: wrapper.args,
+			})
+		}
+	}
+	return candidates
+}
+
+func findLaunchWrapper(wrapper knownLaunchWrapper, getenv func(string) string, exists func(string) bool) string {
+	for _, dir := range wrapper.dirs {
+		root := strings.TrimSpace(getenv(dir[0]))
+		if root == "" {
+			continue
+		}
+		candidate := filepath.Join(root, filepath.FromSlash(strings.ReplaceAll(dir[1], `\`, "/")), wrapper.exeName)
+		if exists(candidate) {
+			return candidate
+		}
+	}
+	for _, dir := range filepath.SplitList(getenv("PATH")) {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		candidate := filepath.Join(dir, wrapper.exeName)
+		if exists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// BuildLaunchCommand はゲームを起動するコマンド（実行ファイルと引数）を組み立てる。
+// ラッパー未設定なら exePath をそのまま返し、設定済みならラッパーの引数テンプレートを展開する。
+func BuildLaunchCommand(exePath string, wrapper *domain.LaunchWrapper) (string, []string, error) {
+	if wrapper == nil || strings.TrimSpace(wrapper.Path) == "" {
+		return exePath, nil, nil
+	}
+	tokens, err := splitLaunchArgs(wrapper.Args)
+	if err != nil {
+		return "", nil, err
+	}
+	replacer := strings.NewReplacer(
+		launchPlaceholderExe, exePath,
+		launchPlaceholderDir, strings.ReplaceAll(windowsPathDir(exePath), "/", `\`),
+		launchPlaceholderName, windowsPathBase(exePath),
+	)
+	hasExe := false
+	args := make([]string, 0, len(tokens)+1)
+	for _, token := range tokens {
+		if strings.Contains(token, launchPlaceholderExe) {
+			hasExe = true
+		}
+		args = append(args, replacer.Replace(token))
+	}
+	if !hasExe {
+		args = append(args, exePath)
+	}
+	return strings.TrimSpace(wrapper.Path), args, nil
+}
+
+// splitLaunchArgs は引数テンプレートを空白で区切る。ダブルクォートで囲んだ部分は1つの引数として扱う。
+func splitLaunchArgs(template string) ([]string, error) {
+	tokens := make([]string, 0, 4)
+	var current strings.Builder
+	inQuote := false
+	hasToken := false
+	for _, r := range template {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			hasToken = true
+		case !inQuote && (r == ' ' || r == '\t'):
+			if hasToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	if inQuote {
+		return nil, errors.New("引数テンプレートのダブルクォートが閉じられていません")
+	}
+	if hasToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// SetLaunchWrapper はゲームの起動ラッパーを設定する。wrapper が nil かパスが空なら解除する。
+func (service *GameService) SetLaunchWrapper(ctx context.Context, gameID string, wrapper *domain.LaunchWrapper) (*domain.Game, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		service.logger.Warn("ゲームIDが不正です", "detail", detail, "gameId", gameID)
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+
+	current, error := service.repository.GetGameByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	if current == nil {
+		service.logger.Warn("ゲームが見つかりません", "gameId", trimmedID)
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+
+	var normalized *domain.LaunchWrapper
+	if wrapper != nil && strings.TrimSpace(wrapper.Path) != "" {
+		normalized = &domain.LaunchWrapper{Path: strings.TrimSpace(wrapper.Path), Args: strings.TrimSpace(wrapper.Args)}
+		if detail := validateLaunchWrapper(*current, *normalized); detail != "" {
+			service.logger.Warn("起動ラッパーの設定が不正です", "detail", detail, "gameId", trimmedID)
+			return nil, newServiceError("起動ラッパーの設定が不正です", detail)
+		}
+	}
+
+	if error := service.repository.SetGameLaunchWrapper(ctx, trimmedID, normalized); error != nil {
+		service.logger.Error("起動ラッパーの保存に失敗", "error", error)
+		return nil, newServiceError("起動ラッパーの保存に失敗しました", error.Error())
+	}
+	current.LaunchWrapper = normalized
+	service.logger.Info("起動ラッパーを更新", "gameId", trimmedID, "enabled", normalized != nil)
+	service.publishGameUpdated(current)
+	return current, nil
+}
+
+// validateLaunchWrapper はラッパー設定の問題点を返す。問題が無ければ空文字。
+func validateLaunchWrapper(game domain.Game, wrapper domain.LaunchWrapper) string {
+	if !strings.EqualFold(filepath.Ext(windowsPathBase(wrapper.Path)), ".exe") {
+		return "ラッパーには .exe ファイルを指定してください"
+	}
+	if strings.EqualFold(normalizeWindowsPathSeparators(wrapper.Path), normalizeWindowsPathSeparators(game.ExePath)) {
+		return "ラッパーとゲームの実行ファイルが同じです"
+	}
+	if isLaunchWrapperExe(game.ExePath) {
+		return "ゲームの実行ファイルにラッパーが指定されています。ゲーム本体の実行ファイルを指定してください"
+	}
+	if _, err := splitLaunchArgs(wrapper.Args); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// ResolveLaunchCommand は実行ファイルパスに対応するゲームのラッパー設定を反映した起動コマンドを返す。
+// 登録されていない実行ファイルはそのまま起動する。
+func (service *GameService) ResolveLaunchCommand(ctx context.Context, exePath string) (string, []string, error) {
+	game, error := service.repository.GetGameByExePath(ctx, exePath)
+	if error != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", error)
+		return "", nil, newServiceError("ゲーム取得に失敗しました", error.Error())
+	}
+	var wrapper *domain.LaunchWrapper
+	if game != nil {
+		wrapper = game.LaunchWrapper
+	}
+	name, args, error := BuildLaunchCommand(exePath, wrapper)
+	if error != nil {
+		service.logger.Warn("起動ラッパーの設定が不正です", "error", error, "exePath", exePath)
+		return "", nil, newServiceError("起動ラッパーの設定が不正です", error.Error())
+	}
+	return name, args, nil
+}
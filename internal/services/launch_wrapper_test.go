@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestBuildLaunchCommandExpandsTemplate(t *testing.T) {
+	t.Parallel()
+
+	exePath := `C:\Games\My Game\game.exe`
+	tests := []struct {
+		name     string
+		wrapper  *domain.LaunchWrapper
+		wantName string
+		wantArgs []string
+	}{
+		{
+			name:     "no wrapper",
+			wantName: exePath,
+		},
+		{
+			name:     "locale emulator",
+			wrapper:  &domain.LaunchWrapper{Path: `C:\LE\LEProc.exe`, Args: `-run "{exe}"`},
+			wantName: `C:\LE\LEProc.exe`,
+			wantArgs: []string{"-run", exePath},
+		},
+		{
+			name:     "placeholders inside tokens",
+			wrapper:  &domain.LaunchWrapper{Path: `C:\NTLEA\ntleas.exe`, Args: `"{exe}" C932 "D{dir}" N{name}`},
+			wantName: `C:\NTLEA\ntleas.exe`,
+			wantArgs: []string{exePath, "C932", `DC:\Games\My Game`, "Ngame.exe"},
+		},
+		{
+			name:     "exe appended when template omits it",
+			wrapper:  &domain.LaunchWrapper{Path: `C:\wrap.exe`, Args: "--jp"},
+			wantName: `C:\wrap.exe`,
+			wantArgs: []string{"--jp", exePath},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			name, args, err := BuildLaunchCommand(exePath, tc.wrapper)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tc.wantName || !slices.Equal(args, tc.wantArgs) {
+				t.Fatalf("got %q %q, want %q %q", name, args, tc.wantName, tc.wantArgs)
+			}
+		})
+	}
+
+	if _, _, err := BuildLaunchCommand(exePath, &domain.LaunchWrapper{Path: `C:\wrap.exe`, Args: `"{exe}`}); err == nil {
+		t.Fatalf("expected unterminated quote to be rejected")
+	}
+}
+
+func TestDetectLaunchWrappersFindsKnownInstallDirs(t *testing.T) {
+	t.Parallel()
+
+	programFiles := t.TempDir()
+	leProc := filepath.Join(programFiles, "Locale Emulator", "LEProc.exe")
+	pathDir := t.TempDir()
+	ntleas := filepath.Join(pathDir, "ntleas.exe")
+	env := map[string]string{"ProgramFiles": programFiles, "PATH": pathDir}
+	existing := map[string]bool{leProc: true, ntleas: true}
+
+	candidates := detectLaunchWrappers(
+		func(key string) string { return env[key] },
+		func(path string) bool { return existing[path] },
+	)
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v", candidates)
+	}
+	if candidates[0].Kind != "locale-emulator" || candidates[0].Path != leProc || candidates[0].Args == "" {
+		t.Fatalf("unexpected locale emulator candidate: %+v", candidates[0])
+	}
+	if candidates[1].Kind != "ntlea" || candidates[1].Path != ntleas {
+		t.Fatalf("unexpected ntlea candidate: %+v", candidates[1])
+	}
+}
+
+func TestGameServiceSetLaunchWrapperValidatesAndClears(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, ExePath: `C:\games\game.exe`}, nil
+		},
+	}
+	service := NewGameService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	invalid := []domain.LaunchWrapper{
+		{Path: `C:\LE\LEProc.txt`},
+		{Path: `C:\games\game.exe`},
+		{Path: `C:\LE\LEProc.exe`, Args: `-run "{exe}`},
+	}
+	for _, wrapper := range invalid {
+		if _, err := service.SetLaunchWrapper(ctx, "game-1", &wrapper); err == nil {
+			t.Fatalf("expected %+v to be rejected", wrapper)
+		}
+	}
+
+	game, err := service.SetLaunchWrapper(ctx, "game-1", &domain.LaunchWrapper{Path: ` C:\LE\LEProc.exe `, Args: `-run "{exe}"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repository.launchWrapper == nil || repository.launchWrapper.Path != `C:\LE\LEProc.exe` || game.LaunchWrapper == nil {
+		t.Fatalf("expected trimmed wrapper to be saved, got %+v", repository.launchWrapper)
+	}
+
+	if _, err := service.SetLaunchWrapper(ctx, "game-1", &domain.LaunchWrapper{Path: "  "}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repository.launchWrapper != nil {
+		t.Fatalf("expected blank path to clear the wrapper")
+	}
+}
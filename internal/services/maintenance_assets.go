@@ -0,0 +1,238 @@
+// AppDataDir 配下の参照されていないサムネイル・スクリーンショット・メモファイルの検出と削除を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/memo"
+)
+
+// CleanupOrphanedAssets はどの DB の行からも参照されていない画像・スクリーンショット・メモファイルを探し、
+// 種類ごとの件数とサイズを返す。deleteFiles が true なら見つけたファイルを削除し、空になったディレクトリも消す。
+// 参照の判定に DB を読むため、一覧の取得に失敗した場合は何も削除せずにエラーを返す。
+func (service *MaintenanceService) CleanupOrphanedAssets(ctx context.Context, deleteFiles bool) (domain.OrphanedAssetReport, error) {
+	games, err := service.repository.ListGames(ctx, "", domain.GameFilterAll, "title", "asc")
+	if err != nil {
+		service.logger.Error("ゲーム一覧の取得に失敗", "operation", "CleanupOrphanedAssets", "error", err)
+		return domain.OrphanedAssetReport{}, newServiceError("未使用ファイルの検出に失敗しました", err.Error())
+	}
+	memos, err := service.repository.ListAllMemos(ctx)
+	if err != nil {
+		service.logger.Error("メモ一覧の取得に失敗", "operation", "CleanupOrphanedAssets", "error", err)
+		return domain.OrphanedAssetReport{}, newServiceError("未使用ファイルの検出に失敗しました", err.Error())
+	}
+
+	appDataDir := service.config.AppDataDir
+	var assets []domain.OrphanedAsset
+	for _, find := range []func() ([]domain.OrphanedAsset, error){
+		func() ([]domain.OrphanedAsset, error) { return findOrphanedThumbnails(appDataDir, games) },
+		func() ([]domain.OrphanedAsset, error) { return findOrphanedScreenshots(appDataDir, games) },
+		func() ([]domain.OrphanedAsset, error) { return findOrphanedMemoFiles(appDataDir, memos) },
+	} {
+		found, err := find()
+		if err != nil {
+			service.logger.Error("未使用ファイルの検出に失敗", "operation", "CleanupOrphanedAssets", "error", err)
+			return domain.OrphanedAssetReport{}, newServiceError("未使用ファイルの検出に失敗しました", err.Error())
+		}
+		assets = append(assets, found...)
+	}
+
+	report := summarizeOrphanedAssets(assets)
+	if !deleteFiles {
+		service.logger.Info("未使用ファイルを検出", "count", report.TotalCount, "bytes", report.TotalBytes)
+		return report, nil
+	}
+	report.Deleted = true
+	for _, asset := range assets {
+		if err := os.Remove(asset.Path); err != nil && !os.IsNotExist(err) {
+			service.logger.Warn("未使用ファイルの削除に失敗", "path", asset.Path, "error", err)
+			report.Failed = append(report.Failed, asset.Path)
+			continue
+		}
+		removeEmptyParents(filepath.Dir(asset.Path), appDataDir)
+	}
+	service.logger.Info("未使用ファイルを削除", "count", report.TotalCount-len(report.Failed), "bytes", report.TotalBytes, "failed", len(report.Failed))
+	return report, nil
+}
+
+// findOrphanedThumbnails は thumbnails 直下でどのゲームの imagePath でもない画像と、
+// 使われているサムネイルと作品キー・拡張子が対応しない原寸画像を返す。
+func findOrphanedThumbnails(appDataDir string, games []domain.Game) ([]domain.OrphanedAsset, error) {
+	thumbnailsDir := filepath.Join(appDataDir, thumbnailsDirName)
+	referenced := make(map[string]struct{}, len(games))
+	keptOriginals := make(map[string]struct{}, len(games))
+	for _, game := range games {
+		if game.ImagePath == nil || strings.TrimSpace(*game.ImagePath) == "" {
+			continue
+		}
+		imagePath := filepath.Clean(*game.ImagePath)
+		referenced[imagePath] = struct{}{}
+		if matches := thumbnailFileRegex.FindStringSubmatch(filepath.Base(imagePath)); matches != nil {
+			keptOriginals[thumbnailOriginalPath(thumbnailsDir, matches[1], matches[2])] = struct{}{}
+		}
+	}
+
+	var assets []domain.OrphanedAsset
+	thumbnails, err := listOrphanFiles(thumbnailsDir, func(path string) bool {
+		_, ok := referenced[path]
+		return !ok
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	assets = append(assets, tagOrphanedAssets(thumbnails, domain.OrphanedAssetThumbnail)...)
+	originals, err := listOrphanFiles(filepath.Join(thumbnailsDir, thumbnailOriginalsDirName), func(path string) bool {
+		_, ok := keptOriginals[path]
+		return !ok
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(assets, tagOrphanedAssets(originals, domain.OrphanedAssetThumbnailOriginal)...), nil
+}
+
+// findOrphanedScreenshots は screenshots/<ゲームID> と _archived/<ゲームID>.zip のうち、ゲームが存在しないものを返す。
+// ゲームを特定できなかったホットキー撮影の保存先（default）はゲームに属さないため対象外。
+func findOrphanedScreenshots(appDataDir string, games []domain.Game) ([]domain.OrphanedAsset, error) {
+	root := localScreenshotsRoot(appDataDir)
+	known := make(map[string]struct{}, len(games))
+	for _, game := range games {
+		known[game.ID] = struct{}{}
+	}
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []domain.OrphanedAsset
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if entry.Name() == screenshotArchiveDirName {
+			archived, err := listOrphanFiles(filepath.Join(root, screenshotArchiveDirName), func(path string) bool {
+				_, ok := known[strings.TrimSuffix(filepath.Base(path), ".zip")]
+				return !ok && strings.HasSuffix(path, ".zip")
+			}, false)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, archived...)
+			continue
+		}
+		if _, ok := known[entry.Name()]; ok || entry.Name() == hotkeyDefaultDirID {
+			continue
+		}
+		orphaned, err := listOrphanFiles(filepath.Join(root, entry.Name()), func(string) bool { return true }, true)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, orphaned...)
+	}
+	return tagOrphanedAssets(files, domain.OrphanedAssetScreenshot), nil
+}
+
+// findOrphanedMemoFiles は memos 配下の .md のうち、DB のメモ（ゲームID・メモID・タイトル）から決まるパスでないものを返す。
+// タイトル変更前の古いファイルや、削除済みのゲーム・メモのファイルが該当する。
+func findOrphanedMemoFiles(appDataDir string, memos []domain.Memo) ([]domain.OrphanedAsset, error) {
+	manager := memo.NewFileManager(appDataDir)
+	expected := make(map[string]struct{}, len(memos))
+	for _, item := range memos {
+		expected[filepath.Clean(manager.MemoFilePath(item.GameID, item.ID, item.Title))] = struct{}{}
+	}
+	files, err := listOrphanFiles(manager.RootDir(), func(path string) bool {
+		_, ok := expected[path]
+		return !ok && strings.EqualFold(filepath.Ext(path), ".md")
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	return tagOrphanedAssets(files, domain.OrphanedAssetMemoFile), nil
+}
+
+// listOrphanFiles は dir 配下（recursive でなければ直下のみ）の通常ファイルのうち orphaned が true のものを返す。
+// dir が無ければ空を返す。Kind は呼び出し側で付ける。
+func listOrphanFiles(dir string, orphaned func(path string) bool, recursive bool) ([]domain.OrphanedAsset, error) {
+	var files []domain.OrphanedAsset
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path == dir && errors.Is(walkErr, os.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return walkErr
+		}
+		if entry.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !orphaned(filepath.Clean(path)) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, domain.OrphanedAsset{Path: path, Size: info.Size()})
+		return nil
+	})
+	return files, err
+}
+
+func tagOrphanedAssets(files []domain.OrphanedAsset, kind domain.OrphanedAssetKind) []domain.OrphanedAsset {
+	for index := range files {
+		files[index].Kind = kind
+	}
+	return files
+}
+
+// summarizeOrphanedAssets は検出したファイルを種類ごとに集計する（種類は定義順、件数0の種類は含めない）。
+func summarizeOrphanedAssets(assets []domain.OrphanedAsset) domain.OrphanedAssetReport {
+	report := domain.OrphanedAssetReport{Assets: []domain.OrphanedAsset{}, Summary: []domain.OrphanedAssetSummary{}, Failed: []string{}}
+	byKind := make(map[domain.OrphanedAssetKind]*domain.OrphanedAssetSummary)
+	for _, asset := range assets {
+		summary, ok := byKind[asset.Kind]
+		if !ok {
+			summary = &domain.OrphanedAssetSummary{Kind: asset.Kind}
+			byKind[asset.Kind] = summary
+		}
+		summary.Count++
+		summary.Bytes += asset.Size
+		report.TotalCount++
+		report.TotalBytes += asset.Size
+	}
+	report.Assets = append(report.Assets, assets...)
+	sort.SliceStable(report.Assets, func(i, j int) bool { return report.Assets[i].Path < report.Assets[j].Path })
+	for _, kind := range []domain.OrphanedAssetKind{
+		domain.OrphanedAssetThumbnail,
+		domain.OrphanedAssetThumbnailOriginal,
+		domain.OrphanedAssetScreenshot,
+		domain.OrphanedAssetMemoFile,
+	} {
+		if summary, ok := byKind[kind]; ok {
+			report.Summary = append(report.Summary, *summary)
+		}
+	}
+	return report
+}
+
+// removeEmptyParents は dir から stopDir の手前まで、空になったディレクトリを削除する。
+func removeEmptyParents(dir string, stopDir string) {
+	stop := filepath.Clean(stopDir)
+	for current := filepath.Clean(dir); current != stop && strings.HasPrefix(current, stop+string(filepath.Separator)); current = filepath.Dir(current) {
+		if err := os.Remove(current); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/memo"
+)
+
+func writeAssetFixture(t *testing.T, path string, size int) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestMaintenanceServiceCleanupOrphanedAssetsReportsAndDeletes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	runtime := newMaintenanceServiceRuntime(t)
+	appDataDir := runtime.cfg.AppDataDir
+	thumbnailsDir := filepath.Join(appDataDir, thumbnailsDirName)
+	hash := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	usedThumbnail := writeAssetFixture(t, filepath.Join(thumbnailsDir, hash+"_12345.png"), 10)
+	game, err := runtime.repository.CreateGame(ctx, domain.Game{Title: "Game", Publisher: "Pub", ExePath: "/game.exe", ImagePath: &usedThumbnail, PlayStatus: domain.PlayStatusUnplayed})
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	memoRow, err := runtime.repository.CreateMemo(ctx, domain.Memo{Title: "攻略", Content: "本文", GameID: game.ID})
+	if err != nil {
+		t.Fatalf("CreateMemo: %v", err)
+	}
+	manager := memo.NewFileManager(appDataDir)
+	keep := []string{
+		usedThumbnail,
+		writeAssetFixture(t, thumbnailOriginalPath(thumbnailsDir, "12345", ".png"), 10),
+		writeAssetFixture(t, filepath.Join(appDataDir, "screenshots", game.ID, "shot.png"), 10),
+		writeAssetFixture(t, filepath.Join(appDataDir, "screenshots", hotkeyDefaultDirID, "unassigned.png"), 10),
+		writeAssetFixture(t, filepath.Join(appDataDir, "screenshots", screenshotArchiveDirName, game.ID+".zip"), 10),
+		writeAssetFixture(t, manager.MemoFilePath(game.ID, memoRow.ID, memoRow.Title), 10),
+	}
+	orphans := map[string]domain.OrphanedAssetKind{
+		writeAssetFixture(t, filepath.Join(thumbnailsDir, hash+"_99999.png"), 100):                                domain.OrphanedAssetThumbnail,
+		writeAssetFixture(t, thumbnailOriginalPath(thumbnailsDir, "99999", ".png"), 200):                          domain.OrphanedAssetThumbnailOriginal,
+		writeAssetFixture(t, filepath.Join(appDataDir, "screenshots", "deleted-game", "shot.png"), 300):           domain.OrphanedAssetScreenshot,
+		writeAssetFixture(t, filepath.Join(appDataDir, "screenshots", screenshotArchiveDirName, "gone.zip"), 400): domain.OrphanedAssetScreenshot,
+		writeAssetFixture(t, manager.MemoFilePath(game.ID, "missing-memo", "古い"), 500):                            domain.OrphanedAssetMemoFile,
+	}
+
+	report, err := runtime.service.CleanupOrphanedAssets(ctx, false)
+	if err != nil {
+		t.Fatalf("CleanupOrphanedAssets: %v", err)
+	}
+	if report.Deleted || report.TotalCount != len(orphans) || report.TotalBytes != 1500 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for _, asset := range report.Assets {
+		if kind, ok := orphans[asset.Path]; !ok || kind != asset.Kind {
+			t.Errorf("unexpected orphan %+v", asset)
+		}
+	}
+	if len(report.Summary) != 4 || report.Summary[2].Kind != domain.OrphanedAssetScreenshot || report.Summary[2].Count != 2 || report.Summary[2].Bytes != 700 {
+		t.Fatalf("unexpected summary: %+v", report.Summary)
+	}
+	for path := range orphans {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("dry run should not delete %s: %v", path, err)
+		}
+	}
+
+	report, err = runtime.service.CleanupOrphanedAssets(ctx, true)
+	if err != nil || !report.Deleted || len(report.Failed) != 0 {
+		t.Fatalf("delete run: %+v err=%v", report, err)
+	}
+	for path := range orphans {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("orphan should be deleted: %s", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(appDataDir, "screenshots", "deleted-game")); !os.IsNotExist(err) {
+		t.Errorf("empty screenshot dir should be removed")
+	}
+	for _, path := range keep {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("referenced file should be kept: %s (%v)", path, err)
+		}
+	}
+}
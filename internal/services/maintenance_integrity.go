@@ -0,0 +1,45 @@
+// データベースの整合性チェック・孤立データの修復・スキーマバージョンの確認を提供する。
+package services
+
+import (
+	"context"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// CheckDatabaseIntegrity は DB の整合性（ファイル破損・外部キー違反・ゲームの無いセッション等）を調べる。
+// repair が true なら孤立データを削除し、壊れたルート参照を外す。
+func (service *MaintenanceService) CheckDatabaseIntegrity(ctx context.Context, repair bool) (domain.DatabaseIntegrityReport, error) {
+	report, err := service.repository.CheckIntegrity(ctx, repair)
+	if err != nil {
+		service.logger.Error("整合性チェックに失敗しました", "error", err, "operation", "CheckDatabaseIntegrity", "repair", repair)
+		return domain.DatabaseIntegrityReport{}, newServiceError("整合性チェックに失敗しました", err.Error())
+	}
+
+	orphaned, repaired := 0, 0
+	for _, orphan := range report.Orphans {
+		orphaned += orphan.Count
+		repaired += orphan.Repaired
+	}
+	if report.Healthy && orphaned == 0 {
+		service.logger.Info("整合性チェック完了（問題なし）")
+		return report, nil
+	}
+	service.logger.Warn("整合性チェックで問題を検出しました",
+		"integrityMessages", len(report.IntegrityMessages),
+		"foreignKeyViolations", len(report.ForeignKeyViolations),
+		"orphans", orphaned,
+		"repaired", repaired,
+	)
+	return report, nil
+}
+
+// GetSchemaVersion は DB に適用済みのマイグレーションとこのアプリの最新スキーマを返す。
+func (service *MaintenanceService) GetSchemaVersion(ctx context.Context) (domain.SchemaVersion, error) {
+	version, err := service.repository.SchemaVersion(ctx)
+	if err != nil {
+		service.logger.Error("スキーマバージョンの取得に失敗しました", "error", err, "operation", "GetSchemaVersion")
+		return domain.SchemaVersion{}, newServiceError("スキーマバージョンの取得に失敗しました", err.Error())
+	}
+	return version, nil
+}
@@ -35,6 +35,7 @@ type GameExportPayload struct {
 	Games       []domain.Game         `json:"games"`
 	Statistics  []GameExportStatistic `json:"statistics"`
 	SessionRows []domain.PlaySession  `json:"sessions"`
+	Links       []domain.GameLink     `json:"links"`
 }
 
 type GameExportResult struct {
@@ -64,6 +65,8 @@ type MaintenanceService struct {
 	repository MaintenanceRepository
 	logger     *slog.Logger
 	hooks      MaintenanceRuntimeHooks
+	// readTx はエクスポートの読み込みを1つのスナップショットにまとめる読み込み専用トランザクション（nil なら使わない）。
+	readTx TxRunner[MaintenanceRepository]
 }
 
 func NewMaintenanceService(
@@ -80,6 +83,11 @@ func NewMaintenanceService(
 	}
 }
 
+// SetReadTxRunner はエクスポート時のゲーム・セッション・リンクの読み込みをまとめる読み込み専用トランザクションを設定する。
+func (service *MaintenanceService) SetReadTxRunner(runner TxRunner[MaintenanceRepository]) {
+	service.readTx = runner
+}
+
 func (service *MaintenanceService) ExportGameData(ctx context.Context, outputDir string) (GameExportResult, error) {
 	trimmed := strings.TrimSpace(outputDir)
 	if trimmed == "" {
@@ -90,27 +98,59 @@ func (service *MaintenanceService) ExportGameData(ctx context.Context, outputDir
 		return GameExportResult{}, newServiceError("出力先フォルダの作成に失敗しました", err.Error())
 	}
 
-	games, err := service.repository.ListGames(ctx, "", domain.PlayStatus(""), "title", "asc")
+	payload, err := service.ExportPayload(ctx)
 	if err != nil {
-		service.logger.Error("ゲーム一覧の取得に失敗しました", "error", err, "operation", "ExportGameData.listGames")
-		return GameExportResult{}, newServiceError("ゲーム一覧の取得に失敗しました", err.Error())
+		return GameExportResult{}, err
 	}
+	stamp := payload.ExportedAt.Format("20060102_150405")
+	jsonPath := filepath.Join(trimmed, fmt.Sprintf("cloudlaunch_export_%s.json", stamp))
+	csvPath := filepath.Join(trimmed, fmt.Sprintf("cloudlaunch_export_%s.csv", stamp))
 
-	gameIDs := make([]string, 0, len(games))
-	for _, game := range games {
-		gameIDs = append(gameIDs, game.ID)
+	jsonData, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		service.logger.Error("JSONの生成に失敗しました", "error", err, "operation", "ExportGameData.marshal")
+		return GameExportResult{}, newServiceError("JSONの生成に失敗しました", err.Error())
 	}
-	sessionsByGame, err := service.repository.ListPlaySessionsByGames(ctx, gameIDs)
+	if err := os.WriteFile(jsonPath, jsonData, 0o600); err != nil {
+		service.logger.Error("JSONファイルの保存に失敗しました", "error", err, "operation", "ExportGameData.writeJSON", "path", jsonPath)
+		return GameExportResult{}, newServiceError("JSONファイルの保存に失敗しました", err.Error())
+	}
+	if err := writeExportCSV(csvPath, payload.Games, payload.Statistics); err != nil {
+		service.logger.Error("CSVファイルの保存に失敗しました", "error", err, "operation", "ExportGameData.writeCSV", "path", csvPath)
+		return GameExportResult{}, newServiceError("CSVファイルの保存に失敗しました", err.Error())
+	}
+
+	return GameExportResult{JSONPath: jsonPath, CSVPath: csvPath}, nil
+}
+
+// ExportPayload はエクスポートする内容（全ゲーム・統計・セッション・リンク）を組み立てる。ファイルには書き出さない。
+func (service *MaintenanceService) ExportPayload(ctx context.Context) (GameExportPayload, error) {
+	// 出力中にプレイが終わってセッションが保存されても、ゲームの累計と統計が食い違わないよう同じスナップショットから読む。
+	var games []domain.Game
+	var sessionsByGame map[string][]domain.PlaySession
+	var linksByGame map[string][]domain.GameLink
+	err := runInTx(ctx, service.readTx, service.repository, func(repository MaintenanceRepository) error {
+		var loadErr error
+		games, sessionsByGame, linksByGame, loadErr = service.loadExportData(ctx, repository)
+		return loadErr
+	})
 	if err != nil {
-		service.logger.Error("セッション取得に失敗しました", "error", err, "operation", "ExportGameData.listSessions")
-		return GameExportResult{}, newServiceError("セッション取得に失敗しました", err.Error())
+		var serviceErr *ServiceError
+		if errors.As(err, &serviceErr) {
+			return GameExportPayload{}, err
+		}
+		// トランザクションを始められなかった場合。
+		service.logger.Error("ゲーム一覧の取得に失敗しました", "error", err, "operation", "ExportGameData.readTx")
+		return GameExportPayload{}, newServiceError("ゲーム一覧の取得に失敗しました", err.Error())
 	}
 
 	stats := make([]GameExportStatistic, 0, len(games))
 	sessionRows := make([]domain.PlaySession, 0, len(games)*2)
+	linkRows := make([]domain.GameLink, 0)
 	for _, game := range games {
 		sessions := sessionsByGame[game.ID]
 		sessionRows = append(sessionRows, sessions...)
+		linkRows = append(linkRows, linksByGame[game.ID]...)
 
 		var total int64
 		for _, session := range sessions {
@@ -135,32 +175,41 @@ func (service *MaintenanceService) ExportGameData(ctx context.Context, outputDir
 		})
 	}
 
-	now := time.Now()
-	stamp := now.Format("20060102_150405")
-	jsonPath := filepath.Join(trimmed, fmt.Sprintf("cloudlaunch_export_%s.json", stamp))
-	csvPath := filepath.Join(trimmed, fmt.Sprintf("cloudlaunch_export_%s.csv", stamp))
-
-	payload := GameExportPayload{
-		ExportedAt:  now,
+	return GameExportPayload{
+		ExportedAt:  time.Now(),
 		Games:       games,
 		Statistics:  stats,
 		SessionRows: sessionRows,
-	}
-	jsonData, err := json.MarshalIndent(payload, "", "  ")
+		Links:       linkRows,
+	}, nil
+}
+
+// loadExportData はエクスポートするゲームと、ゲームごとのセッション・リンクを読み込む。
+func (service *MaintenanceService) loadExportData(ctx context.Context, repository MaintenanceRepository) (
+	[]domain.Game, map[string][]domain.PlaySession, map[string][]domain.GameLink, error,
+) {
+	games, err := repository.ListGames(ctx, "", domain.GameFilterAll, "title", "asc")
 	if err != nil {
-		service.logger.Error("JSONの生成に失敗しました", "error", err, "operation", "ExportGameData.marshal")
-		return GameExportResult{}, newServiceError("JSONの生成に失敗しました", err.Error())
+		service.logger.Error("ゲーム一覧の取得に失敗しました", "error", err, "operation", "ExportGameData.listGames")
+		return nil, nil, nil, newServiceError("ゲーム一覧の取得に失敗しました", err.Error())
 	}
-	if err := os.WriteFile(jsonPath, jsonData, 0o600); err != nil {
-		service.logger.Error("JSONファイルの保存に失敗しました", "error", err, "operation", "ExportGameData.writeJSON", "path", jsonPath)
-		return GameExportResult{}, newServiceError("JSONファイルの保存に失敗しました", err.Error())
+
+	gameIDs := make([]string, 0, len(games))
+	for _, game := range games {
+		gameIDs = append(gameIDs, game.ID)
 	}
-	if err := writeExportCSV(csvPath, games, stats); err != nil {
-		service.logger.Error("CSVファイルの保存に失敗しました", "error", err, "operation", "ExportGameData.writeCSV", "path", csvPath)
-		return GameExportResult{}, newServiceError("CSVファイルの保存に失敗しました", err.Error())
+	sessionsByGame, err := repository.ListPlaySessionsByGames(ctx, gameIDs)
+	if err != nil {
+		service.logger.Error("セッション取得に失敗しました", "error", err, "operation", "ExportGameData.listSessions")
+		return nil, nil, nil, newServiceError("セッション取得に失敗しました", err.Error())
 	}
 
-	return GameExportResult{JSONPath: jsonPath, CSVPath: csvPath}, nil
+	linksByGame, err := repository.ListGameLinksByGames(ctx, gameIDs)
+	if err != nil {
+		service.logger.Error("リンク取得に失敗しました", "error", err, "operation", "ExportGameData.listLinks")
+		return nil, nil, nil, newServiceError("リンク取得に失敗しました", err.Error())
+	}
+	return games, sessionsByGame, linksByGame, nil
 }
 
 func (service *MaintenanceService) CreateFullBackup(outputDir string) (string, error) {
@@ -50,6 +50,15 @@ func TestMaintenanceServiceExportGameDataWritesArtifacts(t *testing.T) {
 	if len(payload.SessionRows) != len(sessions) {
 		t.Fatalf("expected %d sessions, got %d", len(sessions), len(payload.SessionRows))
 	}
+	notedSessions := 0
+	for _, session := range payload.SessionRows {
+		if session.Notes != nil && *session.Notes == "共通ルート終了" {
+			notedSessions++
+		}
+	}
+	if notedSessions != 1 {
+		t.Fatalf("expected session notes to be exported, got %#v", payload.SessionRows)
+	}
 	if len(payload.Statistics) != 1 {
 		t.Fatalf("expected 1 statistic row, got %d", len(payload.Statistics))
 	}
@@ -248,6 +257,9 @@ func newMaintenanceServiceRuntime(t *testing.T) *maintenanceTestRuntime {
 
 	runtime.service = NewMaintenanceService(cfg, repository, logger, MaintenanceRuntimeHooks{
 		CreateDatabaseSnapshot: func(destinationPath string) error {
+			if err := db.Checkpoint(connection); err != nil {
+				return err
+			}
 			return CopyFilePath(databasePath, destinationPath)
 		},
 		StopRuntimeServices: func() {},
@@ -270,6 +282,9 @@ func newMaintenanceServiceRuntime(t *testing.T) *maintenanceTestRuntime {
 		},
 		ResumeRuntimeServices: func() error { return nil },
 	})
+	runtime.service.SetReadTxRunner(func(ctx context.Context, fn func(MaintenanceRepository) error) error {
+		return runtime.repository.WithReadTx(ctx, func(tx *db.Repository) error { return fn(tx) })
+	})
 
 	t.Cleanup(func() {
 		_ = connection.Close()
@@ -293,10 +308,12 @@ func seedMaintenanceFixture(t *testing.T, repository *db.Repository) (*domain.Ga
 
 	firstPlayedAt := time.Date(2026, 4, 27, 20, 0, 0, 0, time.UTC)
 	secondPlayedAt := time.Date(2026, 4, 28, 21, 0, 0, 0, time.UTC)
+	notes := "共通ルート終了"
 	session1 := createMaintenanceSession(t, repository, domain.PlaySession{
 		GameID:   game.ID,
 		PlayedAt: firstPlayedAt,
 		Duration: 1800,
+		Notes:    &notes,
 	})
 	session2 := createMaintenanceSession(t, repository, domain.PlaySession{
 		GameID:   game.ID,
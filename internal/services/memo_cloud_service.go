@@ -73,6 +73,11 @@ func (service *MemoCloudService) SetS3UseTLS(enabled bool) {
 	service.config.S3UseTLS = enabled
 }
 
+// SetCredentialKey は使用する認証情報プロファイルを切り替える。
+func (service *MemoCloudService) SetCredentialKey(key string) {
+	service.config.CredentialKey = key
+}
+
 func (service *MemoCloudService) GetCloudMemos(ctx context.Context) ([]CloudMemoInfo, error) {
 	cfg, credential, err := service.resolveS3OrError(ctx, "GetCloudMemos", "クラウドメモ取得に失敗しました")
 	if err != nil {
@@ -84,7 +89,11 @@ func (service *MemoCloudService) GetCloudMemos(ctx context.Context) ([]CloudMemo
 		return nil, newServiceError("クラウドメモ取得に失敗しました", err.Error())
 	}
 
+	legacySegments := service.legacyMemoSegments(ctx)
+
 	memos := make([]CloudMemoInfo, 0)
+	idBased := map[string]bool{}
+	legacy := make([]CloudMemoInfo, 0)
 	for _, obj := range objects {
 		if !memo.IsMemoPath(obj.Key) {
 			continue
@@ -94,7 +103,7 @@ func (service *MemoCloudService) GetCloudMemos(ctx context.Context) ([]CloudMemo
 			continue
 		}
 		fileName := obj.Key[strings.LastIndex(obj.Key, "/")+1:]
-		memos = append(memos, CloudMemoInfo{
+		info := CloudMemoInfo{
 			Key:          obj.Key,
 			FileName:     fileName,
 			GameID:       gameID,
@@ -102,11 +111,37 @@ func (service *MemoCloudService) GetCloudMemos(ctx context.Context) ([]CloudMemo
 			MemoID:       memoID,
 			LastModified: time.UnixMilli(obj.LastModified),
 			Size:         obj.Size,
-		})
+		}
+		// 旧パス（サニタイズ済みタイトル）のメモはローカルのゲームIDに読み替える。
+		if target := legacySegments[gameID]; target != "" {
+			info.GameID = target
+			legacy = append(legacy, info)
+			continue
+		}
+		idBased[info.GameID+":"+info.MemoID] = true
+		memos = append(memos, info)
+	}
+	// 移行済みで ID パスにも同じメモがある場合は ID パス側を正とする。
+	for _, info := range legacy {
+		if idBased[info.GameID+":"+info.MemoID] {
+			continue
+		}
+		memos = append(memos, info)
 	}
 	return memos, nil
 }
 
+// legacyMemoSegments は旧クラウドパスのディレクトリ名からローカルのゲームIDへの対応表を返す。
+// ゲーム一覧を取得できない場合は旧パスの読み替えを諦め、ID パスのメモだけを扱う。
+func (service *MemoCloudService) legacyMemoSegments(ctx context.Context) map[string]string {
+	games, err := service.gameService.ListGames(ctx, "", domain.GameFilterAll, "title", "asc")
+	if err != nil {
+		service.logger.Warn("旧クラウドパスの解決用ゲーム一覧取得に失敗しました", "operation", "GetCloudMemos", "error", err)
+		return nil
+	}
+	return legacyCloudGameSegments(games)
+}
+
 func (service *MemoCloudService) DownloadMemoFromCloud(ctx context.Context, gameID string, memoFileName string) (string, error) {
 	cfg, credential, err := service.resolveS3OrError(ctx, "DownloadMemoFromCloud", "メモのダウンロードに失敗しました")
 	if err != nil {
@@ -126,12 +161,40 @@ func (service *MemoCloudService) DownloadMemoFromCloud(ctx context.Context, game
 	key := fmt.Sprintf("games/%s/memo/%s", trimmedGameID, trimmedFileName)
 	payload, err := service.objectStore.DownloadObject(ctx, cfg, credential, key)
 	if err != nil {
+		if legacyPayload, ok := service.downloadLegacyMemo(ctx, cfg, credential, trimmedGameID, trimmedFileName); ok {
+			return string(legacyPayload), nil
+		}
 		service.logger.Error("メモのダウンロードに失敗しました", "error", err, "operation", "DownloadMemoFromCloud.downloadObject", "key", key)
 		return "", newServiceError("メモのダウンロードに失敗しました", err.Error())
 	}
 	return string(payload), nil
 }
 
+// downloadLegacyMemo は ID パスにないメモを旧パス（サニタイズ済みタイトル）から読み出す。
+// 旧パスも無ければ ok=false を返し、呼び出し側は ID パスのエラーを報告する。
+func (service *MemoCloudService) downloadLegacyMemo(
+	ctx context.Context,
+	cfg storage.S3Config,
+	credential credentials.Credential,
+	gameID string,
+	memoFileName string,
+) ([]byte, bool) {
+	game, err := service.gameService.GetGameByID(ctx, gameID)
+	if err != nil || game == nil {
+		return nil, false
+	}
+	segment := legacyCloudGameSegment(game.Title)
+	if segment == "" || segment == game.ID {
+		return nil, false
+	}
+	key := fmt.Sprintf("games/%s/memo/%s", segment, memoFileName)
+	payload, err := service.objectStore.DownloadObject(ctx, cfg, credential, key)
+	if err != nil {
+		return nil, false
+	}
+	return payload, true
+}
+
 func (service *MemoCloudService) UploadMemoToCloud(ctx context.Context, memoID string) error {
 	cfg, credential, err := service.resolveS3OrError(ctx, "UploadMemoToCloud", "メモのアップロードに失敗しました")
 	if err != nil {
@@ -218,7 +281,7 @@ func (service *MemoCloudService) buildSyncIndexes(ctx context.Context, gameID st
 		cloudMap[fmt.Sprintf("%s:%s", cloudMemo.GameID, cloudMemo.MemoID)] = cloudMemo
 	}
 
-	games, err := service.gameService.ListGames(ctx, "", domain.PlayStatus(""), "title", "asc")
+	games, err := service.gameService.ListGames(ctx, "", domain.GameFilterAll, "title", "asc")
 	if err != nil {
 		return nil, nil, nil, wrapServiceError(err, "メモ同期に失敗しました")
 	}
@@ -422,7 +485,7 @@ func (service *MemoCloudService) fetchLocalMemos(ctx context.Context, gameID str
 }
 
 func (service *MemoCloudService) resolveDefaultS3Config(ctx context.Context) (storage.S3Config, credentials.Credential, error) {
-	credential, err := service.store.Load(ctx, "default")
+	credential, err := service.store.Load(ctx, credentialKeyOf(service.config))
 	if err != nil || credential == nil {
 		return storage.S3Config{}, credentials.Credential{}, errors.New("認証情報がありません")
 	}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"testing"
@@ -22,6 +23,10 @@ func (repository fakeMemoCloudGameRepository) ListGames(ctx context.Context, sea
 	return repository.games, nil
 }
 
+func (repository fakeMemoCloudGameRepository) ListGamesPage(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string, page domain.PageRequest) ([]domain.Game, int, error) {
+	return repository.games, len(repository.games), nil
+}
+
 func (repository fakeMemoCloudGameRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
 	return repository.game, nil
 }
@@ -42,6 +47,38 @@ func (repository fakeMemoCloudGameRepository) CreateRoute(ctx context.Context, r
 	return nil, nil
 }
 
+func (repository fakeMemoCloudGameRepository) SetGameArchived(ctx context.Context, gameID string, archivedAt *time.Time) error {
+	return nil
+}
+
+func (repository fakeMemoCloudGameRepository) SetGameLaunchWrapper(ctx context.Context, gameID string, wrapper *domain.LaunchWrapper) error {
+	return nil
+}
+
+func (repository fakeMemoCloudGameRepository) SetGameTrackingMode(ctx context.Context, gameID string, mode domain.TrackingMode) error {
+	return nil
+}
+
+func (repository fakeMemoCloudGameRepository) SetGameAutoTrackingExcluded(ctx context.Context, gameID string, excluded bool) error {
+	return nil
+}
+
+func (repository fakeMemoCloudGameRepository) SetGameProcessMatchPattern(ctx context.Context, gameID string, pattern *string) error {
+	return nil
+}
+
+func (repository fakeMemoCloudGameRepository) GetPlayStatusDefinition(ctx context.Context, slug string) (*domain.PlayStatusDefinition, error) {
+	return nil, nil
+}
+
+func (repository fakeMemoCloudGameRepository) ListRecentlyPlayed(ctx context.Context, limit int) ([]domain.RecentlyPlayedGame, error) {
+	return nil, nil
+}
+
+func (repository fakeMemoCloudGameRepository) GetGameByExePath(ctx context.Context, exePath string) (*domain.Game, error) {
+	return nil, nil
+}
+
 type fakeMemoCloudMemoRepository struct {
 	memo       *domain.Memo
 	memoByGame []domain.Memo
@@ -75,11 +112,18 @@ func (repository fakeMemoCloudMemoRepository) DeleteMemo(ctx context.Context, me
 	return nil
 }
 
+func (repository fakeMemoCloudMemoRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
+	return nil, nil
+}
+
 type fakeCloudObjectStore struct {
 	listObjects    []storage.ObjectInfo
 	uploadedKeys   []string
 	downloadedKeys []string
 	downloadData   []byte
+	missingKeys    map[string]bool
+	copiedKeys     [][2]string
+	copyErr        error
 }
 
 func (f *fakeCloudObjectStore) ListObjects(_ context.Context, _ storage.S3Config, _ credentials.Credential, _ string) ([]storage.ObjectInfo, error) {
@@ -93,12 +137,23 @@ func (f *fakeCloudObjectStore) UploadBytes(_ context.Context, _ storage.S3Config
 
 func (f *fakeCloudObjectStore) DownloadObject(_ context.Context, _ storage.S3Config, _ credentials.Credential, key string) ([]byte, error) {
 	f.downloadedKeys = append(f.downloadedKeys, key)
+	if f.missingKeys[key] {
+		return nil, errors.New("NoSuchKey")
+	}
 	if f.downloadData != nil {
 		return f.downloadData, nil
 	}
 	return []byte("content"), nil
 }
 
+func (f *fakeCloudObjectStore) CopyObject(_ context.Context, _ storage.S3Config, _ credentials.Credential, sourceKey string, destKey string) error {
+	if f.copyErr != nil {
+		return f.copyErr
+	}
+	f.copiedKeys = append(f.copiedKeys, [2]string{sourceKey, destKey})
+	return nil
+}
+
 func TestMemoCloudServiceGetCloudMemosUsesObjectStorePort(t *testing.T) {
 	t.Parallel()
 
@@ -168,3 +223,66 @@ func TestMemoCloudServiceUploadMemoToCloudUsesObjectStorePort(t *testing.T) {
 		t.Fatal("expected upload key to be recorded")
 	}
 }
+
+func TestMemoCloudServiceGetCloudMemosMapsLegacyTitlePaths(t *testing.T) {
+	t.Parallel()
+
+	games := []domain.Game{{ID: "game-1", Title: "My Game"}}
+	service := NewMemoCloudService(
+		config.Config{},
+		&fakeCredentialStore{loadResult: &credentials.Credential{BucketName: "bucket"}},
+		NewGameService(fakeMemoCloudGameRepository{games: games}, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		NewMemoService(fakeMemoCloudMemoRepository{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.objectStore = &fakeCloudObjectStore{
+		listObjects: []storage.ObjectInfo{
+			{Key: "games/My_Game/memo/Intro_memo1.md"},
+			{Key: "games/My_Game/memo/Route_memo2.md"},
+			{Key: "games/game-1/memo/Intro_memo1.md"},
+		},
+	}
+
+	result, err := service.GetCloudMemos(context.Background())
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected legacy duplicate to be dropped, got %#v", result)
+	}
+	if result[0].Key != "games/game-1/memo/Intro_memo1.md" {
+		t.Fatalf("expected ID path to win, got %#v", result[0])
+	}
+	if result[1].GameID != "game-1" || result[1].Key != "games/My_Game/memo/Route_memo2.md" {
+		t.Fatalf("expected legacy memo mapped to game ID, got %#v", result[1])
+	}
+}
+
+func TestMemoCloudServiceDownloadMemoFallsBackToLegacyTitlePath(t *testing.T) {
+	t.Parallel()
+
+	game := &domain.Game{ID: "game-1", Title: "My Game"}
+	objectStore := &fakeCloudObjectStore{
+		missingKeys:  map[string]bool{"games/game-1/memo/Intro_memo1.md": true},
+		downloadData: []byte("legacy"),
+	}
+	service := NewMemoCloudService(
+		config.Config{},
+		&fakeCredentialStore{loadResult: &credentials.Credential{BucketName: "bucket"}},
+		NewGameService(fakeMemoCloudGameRepository{game: game}, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		NewMemoService(fakeMemoCloudMemoRepository{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil))),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.objectStore = objectStore
+
+	content, err := service.DownloadMemoFromCloud(context.Background(), "game-1", "Intro_memo1.md")
+	if err != nil {
+		t.Fatalf("expected fallback success, got %v", err)
+	}
+	if content != "legacy" {
+		t.Fatalf("unexpected content %q", content)
+	}
+	if len(objectStore.downloadedKeys) != 2 || objectStore.downloadedKeys[1] != "games/My_Game/memo/Intro_memo1.md" {
+		t.Fatalf("expected ID path then legacy path, got %v", objectStore.downloadedKeys)
+	}
+}
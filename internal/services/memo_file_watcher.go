@@ -0,0 +1,244 @@
+// エディタ等で直接編集されたメモファイル（memos 配下の .md）を検出して DB のメモへ反映する監視を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/logging"
+	"CloudLaunch_Go/internal/memo"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultMemoWatchDebounce は同じファイルへの連続した書き込みをまとめて1回の反映にする待ち時間。
+// エディタは保存時に一時ファイルの作成・置き換えや複数回の書き込みを行うため、落ち着いてから読む。
+const defaultMemoWatchDebounce = 500 * time.Millisecond
+
+// MemoFileWatcher はメモファイルの外部編集を監視し、本文が DB と異なれば Memo 行を更新する。
+// ファイルの見出し・メタコメントは取り除いて本文だけを比べるため、アプリ自身が書き出した直後の
+// 変更通知は DB と同じ本文になって無視され、書き込みが循環しない。反映時もファイルは書き換えない。
+// ファイルの削除・タイトル（ファイル名・見出し）の変更は反映しない。
+type MemoFileWatcher struct {
+	repository MemoRepository
+	files      *memo.FileManager
+	logger     *slog.Logger
+	debounce   time.Duration
+
+	mu        sync.Mutex
+	watcher   *fsnotify.Watcher
+	ctx       context.Context
+	pending   map[string]*time.Timer
+	onUpdated func(domain.Memo)
+	done      chan struct{}
+	// applying は反映中（debounce 後のコールバック内）の変更。Stop はこれを待ってから戻る。
+	applying sync.WaitGroup
+}
+
+// NewMemoFileWatcher は MemoFileWatcher を生成する。
+func NewMemoFileWatcher(repository MemoRepository, files *memo.FileManager, logger *slog.Logger) *MemoFileWatcher {
+	return &MemoFileWatcher{
+		repository: repository,
+		files:      files,
+		logger:     logger,
+		debounce:   defaultMemoWatchDebounce,
+		pending:    make(map[string]*time.Timer),
+	}
+}
+
+// SetOnUpdated は外部編集を DB に反映したときの通知先を設定する（画面の再読み込み・クラウドへのアップロード用）。
+func (watcher *MemoFileWatcher) SetOnUpdated(fn func(domain.Memo)) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	watcher.onUpdated = fn
+}
+
+// Start は memos 配下（ゲームごとのディレクトリを含む）の監視を開始する。既に開始済みなら何もしない。
+func (watcher *MemoFileWatcher) Start(ctx context.Context) error {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.watcher != nil {
+		return nil
+	}
+	if err := watcher.files.EnsureBaseDir(); err != nil {
+		return err
+	}
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	root := watcher.files.RootDir()
+	if err := fsWatcher.Add(root); err != nil {
+		_ = fsWatcher.Close()
+		return err
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		_ = fsWatcher.Close()
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			watcher.addDir(fsWatcher, filepath.Join(root, entry.Name()))
+		}
+	}
+	watcher.watcher = fsWatcher
+	watcher.ctx = ctx
+	watcher.done = make(chan struct{})
+	go watcher.loop(fsWatcher, watcher.done)
+	return nil
+}
+
+// Stop は監視を停止し、反映待ちの変更を破棄する。反映中の変更は終わるまで待つため、
+// 戻った後は DB（バックアップの復元で差し替える場合を含む）に書き込まない。
+func (watcher *MemoFileWatcher) Stop() {
+	watcher.mu.Lock()
+	fsWatcher, done := watcher.watcher, watcher.done
+	watcher.watcher = nil
+	for path, timer := range watcher.pending {
+		timer.Stop()
+		delete(watcher.pending, path)
+	}
+	watcher.mu.Unlock()
+	if fsWatcher == nil {
+		return
+	}
+	_ = fsWatcher.Close()
+	<-done
+	watcher.applying.Wait()
+}
+
+func (watcher *MemoFileWatcher) loop(fsWatcher *fsnotify.Watcher, done chan struct{}) {
+	defer close(done)
+	defer logging.Recover(watcher.logger, "memo-watcher.loop")
+	for {
+		select {
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return
+			}
+			watcher.handleEvent(fsWatcher, event)
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			watcher.logger.Warn("メモファイルの監視でエラー", "error", err)
+		}
+	}
+}
+
+func (watcher *MemoFileWatcher) handleEvent(fsWatcher *fsnotify.Watcher, event fsnotify.Event) {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+		return
+	}
+	if event.Has(fsnotify.Create) && filepath.Dir(event.Name) == filepath.Clean(watcher.files.RootDir()) {
+		// 新しいゲームのメモディレクトリは作成時点から監視する。
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			watcher.addDir(fsWatcher, event.Name)
+			return
+		}
+	}
+	if !strings.EqualFold(filepath.Ext(event.Name), ".md") {
+		return
+	}
+	watcher.schedule(event.Name)
+}
+
+func (watcher *MemoFileWatcher) addDir(fsWatcher *fsnotify.Watcher, dir string) {
+	if err := fsWatcher.Add(dir); err != nil {
+		watcher.logger.Warn("メモディレクトリを監視できません", "dir", dir, "error", err)
+	}
+}
+
+// schedule は path の反映を debounce 後に行うよう予約する。待機中に再び変更されたら待ち直す。
+func (watcher *MemoFileWatcher) schedule(path string) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.watcher == nil {
+		return
+	}
+	if timer, ok := watcher.pending[path]; ok {
+		timer.Reset(watcher.debounce)
+		return
+	}
+	watcher.pending[path] = time.AfterFunc(watcher.debounce, func() {
+		watcher.mu.Lock()
+		delete(watcher.pending, path)
+		ctx, onUpdated, running := watcher.ctx, watcher.onUpdated, watcher.watcher != nil
+		if running {
+			// Stop と同じロックの中で数え、Stop が見落とさないようにする。
+			watcher.applying.Add(1)
+		}
+		watcher.mu.Unlock()
+		if !running {
+			return
+		}
+		defer watcher.applying.Done()
+		defer logging.Recover(watcher.logger, "memo-watcher.apply")
+		updated, err := watcher.applyFileChange(ctx, path)
+		if err != nil {
+			watcher.logger.Warn("メモファイルの変更を反映できません", "path", path, "error", err)
+			return
+		}
+		if updated != nil && onUpdated != nil {
+			onUpdated(*updated)
+		}
+	})
+}
+
+// applyFileChange は path のメモファイルの本文を対応する Memo 行へ反映し、更新したメモを返す。
+// 対応するメモが無い・パスが一致しない（タイトル変更前の古いファイル等）・本文が同じ・
+// アーカイブ済みのゲームのメモである場合は何もせず nil を返す。
+func (watcher *MemoFileWatcher) applyFileChange(ctx context.Context, path string) (*domain.Memo, error) {
+	memoID := memoIDFromFileName(filepath.Base(path))
+	if memoID == "" {
+		return nil, nil
+	}
+	current, err := watcher.repository.GetMemoByID(ctx, memoID)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	if filepath.Clean(watcher.files.MemoFilePath(current.GameID, current.ID, current.Title)) != filepath.Clean(path) {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// DB の本文も書き出し→読み取りを通してから比べる。本文中のコメント行など、ファイル形式では
+	// 表せない部分の違いを外部編集と取り違えて、DB の本文を削ってしまわないようにする。
+	content := memo.ExtractMemoContent(string(raw))
+	if content == memo.ExtractMemoContent(memo.GenerateLocalMemoFileContent(current.Title, current.Content)) {
+		return nil, nil
+	}
+	if err := ensureGameWritable(ctx, watcher.repository, watcher.logger, current.GameID); err != nil {
+		return nil, nil
+	}
+	current.Content = content
+	updated, err := watcher.repository.UpdateMemo(ctx, *current)
+	if err != nil {
+		return nil, err
+	}
+	watcher.logger.Info("メモファイルの外部編集を反映", "memoId", current.ID, "gameId", current.GameID)
+	return updated, nil
+}
+
+// memoIDFromFileName は "<タイトル>_<メモID>.md" からメモIDを取り出す。形式が違えば空文字を返す。
+func memoIDFromFileName(name string) string {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	index := strings.LastIndex(base, "_")
+	if index < 0 || index == len(base)-1 {
+		return ""
+	}
+	return base[index+1:]
+}
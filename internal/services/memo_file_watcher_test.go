@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/db"
+	"CloudLaunch_Go/internal/memo"
+)
+
+func newMemoWatcherFixture(t *testing.T, content string) (*db.Repository, *memo.FileManager, *domain.Memo, string) {
+	t.Helper()
+	ctx := context.Background()
+	appDataDir := t.TempDir()
+	connection, err := db.Open(filepath.Join(appDataDir, "app.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = connection.Close() })
+	if err := db.ApplyMigrations(connection); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repository := db.NewRepository(connection)
+	files := memo.NewFileManager(appDataDir)
+	game, err := repository.CreateGame(ctx, domain.Game{Title: "Game", Publisher: "Pub", ExePath: "/game.exe", PlayStatus: domain.PlayStatusUnplayed})
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	created, err := NewMemoService(repository, files, newTestLogger()).CreateMemo(ctx, MemoInput{Title: "攻略", Content: content, GameID: game.ID})
+	if err != nil {
+		t.Fatalf("CreateMemo: %v", err)
+	}
+	return repository, files, created, files.MemoFilePath(game.ID, created.ID, created.Title)
+}
+
+func TestMemoFileWatcherApplyFileChangeIgnoresOwnWritesAndAppliesEdits(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	// 本文中のコメント行はファイル形式では読み戻せないが、それを外部編集と誤認しない。
+	repository, files, created, path := newMemoWatcherFixture(t, "本文\n<!-- note -->\n二行目")
+	watcher := NewMemoFileWatcher(repository, files, newTestLogger())
+
+	updated, err := watcher.applyFileChange(ctx, path)
+	if err != nil || updated != nil {
+		t.Fatalf("file written by the app should not update the memo: %+v err=%v", updated, err)
+	}
+
+	if err := os.WriteFile(path, []byte(memo.GenerateLocalMemoFileContent(created.Title, "エディタで編集")), 0o600); err != nil {
+		t.Fatalf("write memo file: %v", err)
+	}
+	updated, err = watcher.applyFileChange(ctx, path)
+	if err != nil || updated == nil || updated.Content != "エディタで編集" {
+		t.Fatalf("external edit should update the memo: %+v err=%v", updated, err)
+	}
+	stored, _ := repository.GetMemoByID(ctx, created.ID)
+	if stored.Content != "エディタで編集" || stored.Title != created.Title {
+		t.Fatalf("unexpected stored memo: %+v", stored)
+	}
+
+	// 古いタイトルのファイルやメモIDの無いファイルは反映しない。
+	stale := files.MemoFilePath(created.GameID, created.ID, "旧タイトル")
+	if err := os.WriteFile(stale, []byte("# 旧タイトル\n\n古い本文\n"), 0o600); err != nil {
+		t.Fatalf("write stale file: %v", err)
+	}
+	for _, ignored := range []string{stale, filepath.Join(filepath.Dir(path), "notes.md")} {
+		if updated, err := watcher.applyFileChange(ctx, ignored); err != nil || updated != nil {
+			t.Errorf("%s should be ignored: %+v err=%v", ignored, updated, err)
+		}
+	}
+}
+
+func TestMemoFileWatcherDebouncesAndNotifies(t *testing.T) {
+	t.Parallel()
+
+	repository, files, created, path := newMemoWatcherFixture(t, "初期")
+	watcher := NewMemoFileWatcher(repository, files, newTestLogger())
+	watcher.debounce = 50 * time.Millisecond
+	notified := make(chan domain.Memo, 4)
+	watcher.SetOnUpdated(func(updated domain.Memo) { notified <- updated })
+	if err := watcher.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer watcher.Stop()
+
+	for _, content := range []string{"途中", "保存後"} {
+		if err := os.WriteFile(path, []byte(memo.GenerateLocalMemoFileContent(created.Title, content)), 0o600); err != nil {
+			t.Fatalf("write memo file: %v", err)
+		}
+	}
+	select {
+	case updated := <-notified:
+		if !strings.Contains(updated.Content, "保存後") {
+			t.Fatalf("expected the last write to be applied, got %q", updated.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("external edit was not applied")
+	}
+	select {
+	case extra := <-notified:
+		t.Fatalf("consecutive writes should be applied once, got extra %+v", extra)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestMemoFileWatcherStopWaitsForInFlightApply(t *testing.T) {
+	t.Parallel()
+
+	repository, files, created, path := newMemoWatcherFixture(t, "初期")
+	watcher := NewMemoFileWatcher(repository, files, newTestLogger())
+	watcher.debounce = 10 * time.Millisecond
+	applying := make(chan struct{})
+	release := make(chan struct{})
+	watcher.SetOnUpdated(func(domain.Memo) {
+		close(applying)
+		<-release
+	})
+	if err := watcher.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(memo.GenerateLocalMemoFileContent(created.Title, "編集")), 0o600); err != nil {
+		t.Fatalf("write memo file: %v", err)
+	}
+	select {
+	case <-applying:
+	case <-time.After(5 * time.Second):
+		t.Fatal("external edit was not applied")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		watcher.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while a change was still being applied")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the apply finished")
+	}
+}
@@ -29,6 +29,9 @@ func (service *MemoService) CreateMemo(ctx context.Context, input MemoInput) (*d
 		service.logger.Warn("メモ入力が不正です", "error", error)
 		return nil, newServiceError("メモ入力が不正です", error.Error())
 	}
+	if error := ensureGameWritable(ctx, service.repository, service.logger, strings.TrimSpace(input.GameID)); error != nil {
+		return nil, error
+	}
 
 	memo := domain.Memo{
 		ID:      strings.TrimSpace(input.ID),
@@ -70,6 +73,9 @@ func (service *MemoService) UpdateMemo(ctx context.Context, memoID string, input
 		service.logger.Warn("メモが見つかりません", "memoId", trimmedID)
 		return nil, newServiceError("メモが見つかりません", "指定されたIDが存在しません")
 	}
+	if error := ensureGameWritable(ctx, service.repository, service.logger, memo.GameID); error != nil {
+		return nil, error
+	}
 
 	oldTitle := memo.Title
 	oldContent := memo.Content
@@ -110,6 +116,19 @@ func (service *MemoService) GetMemoByID(ctx context.Context, memoID string) (*do
 	return memo, nil
 }
 
+// GetMemoOutline はメモ本文を解析し、目次・チェックリスト進捗・語数を返す。
+func (service *MemoService) GetMemoOutline(ctx context.Context, memoID string) (domain.MemoOutline, error) {
+	found, error := service.GetMemoByID(ctx, memoID)
+	if error != nil {
+		return domain.MemoOutline{}, error
+	}
+	if found == nil {
+		service.logger.Warn("メモが見つかりません", "memoId", memoID)
+		return domain.MemoOutline{}, newServiceError("メモが見つかりません", "指定されたIDが存在しません")
+	}
+	return memo.ParseOutline(found.ID, found.Content), nil
+}
+
 // FindMemoByTitle はゲームIDとタイトルでメモを取得する。
 func (service *MemoService) FindMemoByTitle(ctx context.Context, gameID string, title string) (*domain.Memo, error) {
 	trimmedGameID, detail, ok := requireNonEmpty(gameID, "gameID")
@@ -168,6 +187,9 @@ func (service *MemoService) DeleteMemo(ctx context.Context, memoID string) error
 		service.logger.Warn("メモが見つかりません", "memoId", trimmedID)
 		return newServiceError("メモが見つかりません", "指定されたIDが存在しません")
 	}
+	if error := ensureGameWritable(ctx, service.repository, service.logger, memo.GameID); error != nil {
+		return error
+	}
 
 	if error := service.repository.DeleteMemo(ctx, trimmedID); error != nil {
 		service.logger.Error("メモ削除に失敗", "error", error)
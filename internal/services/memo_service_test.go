@@ -22,6 +22,7 @@ type fakeMemoRepository struct {
 	listMemosByGame func(ctx context.Context, gameID string) ([]domain.Memo, error)
 	listAllMemosFn  func(ctx context.Context) ([]domain.Memo, error)
 	deleteMemoFn    func(ctx context.Context, memoID string) error
+	getGameByIDFn   func(ctx context.Context, gameID string) (*domain.Game, error)
 }
 
 func (repository fakeMemoRepository) CreateMemo(ctx context.Context, memo domain.Memo) (*domain.Memo, error) {
@@ -52,6 +53,13 @@ func (repository fakeMemoRepository) DeleteMemo(ctx context.Context, memoID stri
 	return repository.deleteMemoFn(ctx, memoID)
 }
 
+func (repository fakeMemoRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
+	if repository.getGameByIDFn == nil {
+		return nil, nil
+	}
+	return repository.getGameByIDFn(ctx, gameID)
+}
+
 func TestMemoServiceGetMemoByIDUsesRepositoryBoundary(t *testing.T) {
 	t.Parallel()
 
@@ -260,6 +268,7 @@ type trackingMemoRepository struct {
 	updateResults   []*domain.Memo
 	updateMemoCalls int
 	deleteMemoCalls int
+	game            *domain.Game
 }
 
 func (repository *trackingMemoRepository) CreateMemo(ctx context.Context, memo domain.Memo) (*domain.Memo, error) {
@@ -285,6 +294,10 @@ func (repository *trackingMemoRepository) UpdateMemo(ctx context.Context, memo d
 func (repository *trackingMemoRepository) GetMemoByID(ctx context.Context, memoID string) (*domain.Memo, error) {
 	return repository.getResult, nil
 }
+
+func (repository *trackingMemoRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
+	return repository.game, nil
+}
 func (repository *trackingMemoRepository) FindMemoByTitle(ctx context.Context, gameID string, title string) (*domain.Memo, error) {
 	return repository.findResult, nil
 }
@@ -357,3 +370,28 @@ func TestMemoServiceDeleteMemoRemovesDatabaseRecordAndLocalFile(t *testing.T) {
 		t.Fatalf("expected local memo file to be removed, got %v", err)
 	}
 }
+
+func TestMemoServiceGetMemoOutlineParsesContent(t *testing.T) {
+	t.Parallel()
+
+	service := NewMemoService(fakeMemoRepository{
+		getMemoByIDFn: func(ctx context.Context, memoID string) (*domain.Memo, error) {
+			if memoID == "missing" {
+				return nil, nil
+			}
+			return &domain.Memo{ID: memoID, Content: "# 攻略\n- [x] 共通ルート\n- [ ] 個別ルート\n"}, nil
+		},
+	}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	outline, err := service.GetMemoOutline(context.Background(), "memo-1")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if outline.MemoID != "memo-1" || len(outline.Headings) != 1 || outline.Checklist.Percent != 50 {
+		t.Fatalf("unexpected outline: %+v", outline)
+	}
+
+	if _, err := service.GetMemoOutline(context.Background(), "missing"); err == nil {
+		t.Fatalf("expected missing memo to return an error")
+	}
+}
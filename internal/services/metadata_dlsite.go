@@ -0,0 +1,79 @@
+// DLSite の作品ページからゲーム情報を取得する取り込み元を提供する。
+package services
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// dlsiteWorkIDRegex は DLSite の作品番号（RJ01234567 など）。
+var dlsiteWorkIDRegex = regexp.MustCompile(`(?i)\b((?:RJ|RE|VJ|BJ)\d{6,8})\b`)
+
+// dlsiteCookie は年齢確認を済ませた扱いにし、日本語ページを取得するための Cookie。
+const dlsiteCookie = "adultchecked=1; locale=ja_JP"
+
+// DLSiteProvider は DLSite の作品ページからゲーム情報を取得する。
+type DLSiteProvider struct {
+	scraper *metadataScraper
+}
+
+// ID は取り込み元の ID を返す。
+func (provider *DLSiteProvider) ID() string {
+	return MetadataProviderDLSite
+}
+
+// Name は取り込み元の名前を返す。
+func (provider *DLSiteProvider) Name() string {
+	return "DLsite"
+}
+
+// Matches は DLSite の作品ページの URL かを返す。
+func (provider *DLSiteProvider) Matches(pageURL string) bool {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || !strings.HasSuffix(strings.ToLower(parsed.Hostname()), "dlsite.com") {
+		return false
+	}
+	return dlsiteWorkIDRegex.MatchString(parsed.Path)
+}
+
+// Fetch は DLSite の作品ページからタイトル・サークル（ブランド）・カバー画像・販売日を取得する。
+func (provider *DLSiteProvider) Fetch(ctx context.Context, pageURL string) (domain.GameImport, error) {
+	matches := dlsiteWorkIDRegex.FindStringSubmatch(pageURL)
+	if matches == nil {
+		return domain.GameImport{}, InvalidUrlError{URL: pageURL, Provider: MetadataProviderDLSite}
+	}
+	workID := strings.ToUpper(matches[1])
+	return provider.scraper.fetchStoreWork(ctx, MetadataProviderDLSite, workID, pageURL, dlsiteCookie, parseDLSiteWork)
+}
+
+func parseDLSiteWork(doc *goquery.Document) storeWork {
+	title := firstText(doc, "#work_name")
+	if title == "" {
+		// og:title は "タイトル [サークル] | DLsite ..." の形式。
+		title, _, _ = strings.Cut(metaContent(doc, "og:title"), " | ")
+		if index := strings.LastIndex(title, " ["); index > 0 {
+			title = title[:index]
+		}
+		title = strings.TrimSpace(title)
+	}
+	brand := firstText(doc, "#work_maker .maker_name a", ".maker_name a")
+	if brand == "" {
+		brand = labeledValue(doc, "サークル名", "ブランド名", "ブランド", "メーカー")
+	}
+	imageSrc := metaContent(doc, "og:image")
+	if imageSrc == "" {
+		imageSrc = strings.TrimSpace(doc.Find(".product-slider-data > div").First().AttrOr("data-src", ""))
+	}
+	return storeWork{
+		title:       title,
+		brand:       brand,
+		imageSrc:    imageSrc,
+		releaseDate: parseReleaseDate(labeledValue(doc, "販売日", "発売日")),
+	}
+}
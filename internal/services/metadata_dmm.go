@@ -0,0 +1,74 @@
+// DMM / FANZA の作品ページからゲーム情報を取得する取り込み元を提供する。
+package services
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// dmmContentIDRegex は DMM の作品 ID（URL の cid=xxx または /detail/xxx/）。
+var dmmContentIDRegex = regexp.MustCompile(`(?:cid=|/detail/)([A-Za-z0-9_]+)`)
+
+// dmmCookie は年齢確認を済ませた扱いにするための Cookie。
+const dmmCookie = "age_check_done=1"
+
+// DMMProvider は DMM / FANZA（PC ゲーム・同人）の作品ページからゲーム情報を取得する。
+type DMMProvider struct {
+	scraper *metadataScraper
+}
+
+// ID は取り込み元の ID を返す。
+func (provider *DMMProvider) ID() string {
+	return MetadataProviderDMM
+}
+
+// Name は取り込み元の名前を返す。
+func (provider *DMMProvider) Name() string {
+	return "DMM / FANZA"
+}
+
+// Matches は DMM の作品ページの URL かを返す。
+func (provider *DMMProvider) Matches(pageURL string) bool {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if !strings.HasSuffix(host, "dmm.co.jp") && !strings.HasSuffix(host, "dmm.com") {
+		return false
+	}
+	return dmmContentIDRegex.MatchString(parsed.Path)
+}
+
+// Fetch は DMM の作品ページからタイトル・ブランド（サークル）・カバー画像・発売日を取得する。
+func (provider *DMMProvider) Fetch(ctx context.Context, pageURL string) (domain.GameImport, error) {
+	matches := dmmContentIDRegex.FindStringSubmatch(pageURL)
+	if matches == nil {
+		return domain.GameImport{}, InvalidUrlError{URL: pageURL, Provider: MetadataProviderDMM}
+	}
+	contentID := strings.ToLower(matches[1])
+	return provider.scraper.fetchStoreWork(ctx, MetadataProviderDMM, contentID, pageURL, dmmCookie, parseDMMWork)
+}
+
+func parseDMMWork(doc *goquery.Document) storeWork {
+	title := firstText(doc, "h1#title", "h1.productTitle__txt", ".productTitle__headline")
+	if title == "" {
+		title = metaContent(doc, "og:title")
+	}
+	brand := labeledValue(doc, "ブランド", "サークル名", "メーカー")
+	if brand == "" {
+		brand = firstText(doc, ".circleName__txt")
+	}
+	return storeWork{
+		title:       title,
+		brand:       brand,
+		imageSrc:    metaContent(doc, "og:image"),
+		releaseDate: parseReleaseDate(labeledValue(doc, "発売日", "配信開始日", "販売日")),
+	}
+}
@@ -0,0 +1,268 @@
+// IGDB（Twitch の API）からゲーム情報を取得する取り込み元を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	igdbTokenURL     = "https://id.twitch.tv/oauth2/token"
+	igdbGamesURL     = "https://api.igdb.com/v4/games"
+	igdbImageBaseURL = "https://images.igdb.com/igdb/image/upload/t_cover_big/"
+	// igdbRequestInterval は IGDB の上限（毎秒4リクエスト）を超えないためのリクエスト間隔。
+	igdbRequestInterval = 260 * time.Millisecond
+	// igdbTokenMargin はアクセストークンの期限切れ直前の利用を避けるための余裕。
+	igdbTokenMargin = time.Minute
+)
+
+// igdbSlugRegex は IGDB のゲームページ（https://www.igdb.com/games/<slug>）の slug。
+var igdbSlugRegex = regexp.MustCompile(`igdb\.com/games/([a-z0-9\-]+)`)
+
+// IGDBProvider は IGDB からタイトル・パブリッシャー・カバー画像・発売日・ジャンル・概要を取得する。
+// 利用には Twitch の開発者コンソールで発行したクライアント ID とシークレットが必要。
+type IGDBProvider struct {
+	scraper      *metadataScraper
+	limiter      *requestLimiter
+	tokenURL     string
+	gamesURL     string
+	imageBaseURL string
+	// clientID / clientSecret / token / tokenExpiresAt は mu で保護する。mu は値の読み書きの間だけ持ち、通信中は持たない。
+	// refreshMu はトークンの取り直しを1つに絞り、同時の照会がそれぞれ取りに行かないようにする。
+	refreshMu      sync.Mutex
+	mu             sync.Mutex
+	clientID       string
+	clientSecret   string
+	token          string
+	tokenExpiresAt time.Time
+}
+
+func newIGDBProvider(scraper *metadataScraper) *IGDBProvider {
+	return &IGDBProvider{
+		scraper:      scraper,
+		limiter:      newRequestLimiter(igdbRequestInterval),
+		tokenURL:     igdbTokenURL,
+		gamesURL:     igdbGamesURL,
+		imageBaseURL: igdbImageBaseURL,
+	}
+}
+
+// ID は取り込み元の ID を返す。
+func (provider *IGDBProvider) ID() string {
+	return MetadataProviderIGDB
+}
+
+// Name は取り込み元の名前を返す。
+func (provider *IGDBProvider) Name() string {
+	return "IGDB"
+}
+
+// Matches は IGDB のゲームページの URL かを返す。
+func (provider *IGDBProvider) Matches(pageURL string) bool {
+	return igdbSlugRegex.MatchString(strings.ToLower(pageURL))
+}
+
+// SetCredentials は API のクライアント ID とシークレットを設定する。変わった場合は取得済みのトークンを捨てる。
+func (provider *IGDBProvider) SetCredentials(clientID string, clientSecret string) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.clientID == clientID && provider.clientSecret == clientSecret {
+		return
+	}
+	provider.clientID = clientID
+	provider.clientSecret = clientSecret
+	provider.token = ""
+	provider.tokenExpiresAt = time.Time{}
+}
+
+// Configured はクライアント ID とシークレットが設定済みかを返す。
+func (provider *IGDBProvider) Configured() bool {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	return provider.clientID != "" && provider.clientSecret != ""
+}
+
+type igdbGame struct {
+	Name             string  `json:"name"`
+	Slug             string  `json:"slug"`
+	Summary          string  `json:"summary"`
+	FirstReleaseDate int64   `json:"first_release_date"`
+	TotalRating      float64 `json:"total_rating"`
+	Cover            *struct {
+		ImageID string `json:"image_id"`
+	} `json:"cover"`
+	Genres []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	InvolvedCompanies []struct {
+		Company struct {
+			Name string `json:"name"`
+		} `json:"company"`
+		Publisher bool `json:"publisher"`
+		Developer bool `json:"developer"`
+	} `json:"involved_companies"`
+}
+
+// Fetch は URL の slug に一致するゲームを IGDB から取得する。
+func (provider *IGDBProvider) Fetch(ctx context.Context, pageURL string) (domain.GameImport, error) {
+	matches := igdbSlugRegex.FindStringSubmatch(strings.ToLower(pageURL))
+	if matches == nil {
+		return domain.GameImport{}, InvalidUrlError{URL: pageURL, Provider: MetadataProviderIGDB}
+	}
+	slug := matches[1]
+
+	clientID, token, error := provider.accessToken(ctx)
+	if error != nil {
+		return domain.GameImport{}, error
+	}
+	query := fmt.Sprintf(`fields name,slug,summary,first_release_date,total_rating,cover.image_id,genres.name,`+
+		`involved_companies.company.name,involved_companies.publisher,involved_companies.developer; where slug = "%s"; limit 1;`, slug)
+	if error := provider.limiter.Wait(ctx); error != nil {
+		return domain.GameImport{}, FetchError{URL: provider.gamesURL, Err: error}
+	}
+	request, error := http.NewRequestWithContext(ctx, http.MethodPost, provider.gamesURL, strings.NewReader(query))
+	if error != nil {
+		return domain.GameImport{}, FetchError{URL: provider.gamesURL, Err: error}
+	}
+	request.Header.Set("Client-ID", clientID)
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set("Accept", "application/json")
+	var games []igdbGame
+	if error := provider.scraper.fetchJSON(request, &games); error != nil {
+		var fetchErr FetchError
+		if errors.As(error, &fetchErr) && fetchErr.StatusCode == http.StatusUnauthorized {
+			// 失効したトークンは次回取り直す。
+			provider.clearToken(token)
+		}
+		return domain.GameImport{}, error
+	}
+	if len(games) == 0 || strings.TrimSpace(games[0].Name) == "" {
+		return domain.GameImport{}, ParseError{Field: "title", Err: errors.New("game not found"), Provider: MetadataProviderIGDB}
+	}
+	game := games[0]
+	if game.Cover == nil || game.Cover.ImageID == "" {
+		return domain.GameImport{}, ParseError{Field: "imageUrl", Err: errors.New("cover not found"), Provider: MetadataProviderIGDB}
+	}
+
+	imageURL := provider.imageBaseURL + game.Cover.ImageID + ".jpg"
+	imagePath, error := provider.scraper.downloadAndSaveImage(ctx, imageURL, MetadataProviderIGDB+"_"+strings.ReplaceAll(slug, "-", "_"))
+	if error != nil {
+		return domain.GameImport{}, error
+	}
+	imported := domain.GameImport{
+		Provider:    MetadataProviderIGDB,
+		SourceID:    slug,
+		Title:       strings.TrimSpace(game.Name),
+		Brand:       igdbBrand(game),
+		ImagePath:   imagePath,
+		ImageURL:    imageURL,
+		Description: strings.TrimSpace(game.Summary),
+	}
+	if game.FirstReleaseDate > 0 {
+		imported.ReleaseDate = time.Unix(game.FirstReleaseDate, 0).UTC().Format("2006-01-02")
+	}
+	if game.TotalRating > 0 {
+		imported.Score = int(math.Round(game.TotalRating))
+	}
+	for _, genre := range game.Genres {
+		if name := strings.TrimSpace(genre.Name); name != "" {
+			imported.Genres = append(imported.Genres, name)
+		}
+	}
+	return imported, nil
+}
+
+// igdbBrand はパブリッシャー、無ければ開発元、それも無ければ最初の関係会社の名前を返す。
+func igdbBrand(game igdbGame) string {
+	for _, want := range []func(publisher, developer bool) bool{
+		func(publisher, _ bool) bool { return publisher },
+		func(_, developer bool) bool { return developer },
+		func(_, _ bool) bool { return true },
+	} {
+		for _, company := range game.InvolvedCompanies {
+			if name := strings.TrimSpace(company.Company.Name); name != "" && want(company.Publisher, company.Developer) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// accessToken はクライアント ID と有効なアクセストークンを返す。未取得・期限切れならクライアント認証で取り直す。
+func (provider *IGDBProvider) accessToken(ctx context.Context) (string, string, error) {
+	if clientID, token, ok, error := provider.cachedToken(); error != nil || ok {
+		return clientID, token, error
+	}
+	provider.refreshMu.Lock()
+	defer provider.refreshMu.Unlock()
+	// 待っている間に他の照会が取り直していれば、それを使う。
+	if clientID, token, ok, error := provider.cachedToken(); error != nil || ok {
+		return clientID, token, error
+	}
+
+	provider.mu.Lock()
+	clientID, clientSecret := provider.clientID, provider.clientSecret
+	provider.mu.Unlock()
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+	form.Set("grant_type", "client_credentials")
+	if error := provider.limiter.Wait(ctx); error != nil {
+		return "", "", FetchError{URL: provider.tokenURL, Err: error}
+	}
+	request, error := http.NewRequestWithContext(ctx, http.MethodPost, provider.tokenURL, strings.NewReader(form.Encode()))
+	if error != nil {
+		return "", "", FetchError{URL: provider.tokenURL, Err: error}
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if error := provider.scraper.fetchJSON(request, &response); error != nil {
+		return "", "", error
+	}
+	if response.AccessToken == "" {
+		return "", "", FetchError{URL: provider.tokenURL, Err: errors.New("access token is empty")}
+	}
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	// 取得中に認証情報が変わった場合、古い認証情報のトークンは保存しない。
+	if provider.clientID == clientID && provider.clientSecret == clientSecret {
+		provider.token = response.AccessToken
+		provider.tokenExpiresAt = time.Now().Add(time.Duration(response.ExpiresIn)*time.Second - igdbTokenMargin)
+	}
+	return clientID, response.AccessToken, nil
+}
+
+// cachedToken は取得済みで期限内のトークンがあれば ok を true にして返す。認証情報が未設定ならエラーを返す。
+func (provider *IGDBProvider) cachedToken() (string, string, bool, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.clientID == "" || provider.clientSecret == "" {
+		return "", "", false, errors.New("IGDB のクライアント ID とシークレットが設定されていません")
+	}
+	if provider.token != "" && time.Now().Before(provider.tokenExpiresAt) {
+		return provider.clientID, provider.token, true, nil
+	}
+	return "", "", false, nil
+}
+
+// clearToken は失効した token を捨てる。別の照会が取り直した新しいトークンは残す。
+func (provider *IGDBProvider) clearToken(token string) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.token == token {
+		provider.token = ""
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+)
+
+func newTestAPIScraper(t *testing.T) *metadataScraper {
+	t.Helper()
+	return newMetadataScraper(config.Config{AppDataDir: t.TempDir(), ThumbnailShortEdgePx: 64}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func writeTestCover(t *testing.T, writer http.ResponseWriter) {
+	t.Helper()
+	var cover bytes.Buffer
+	if err := png.Encode(&cover, image.NewRGBA(image.Rect(0, 0, 120, 160))); err != nil {
+		t.Fatal(err)
+	}
+	writer.Header().Set("Content-Type", "image/png")
+	_, _ = writer.Write(cover.Bytes())
+}
+
+func TestIGDBProviderFetchesGameWithCachedToken(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	tokenRequests := 0
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/oauth2/token":
+			_ = request.ParseForm()
+			if request.PostForm.Get("client_id") != "client" || request.PostForm.Get("client_secret") != "secret" {
+				http.Error(writer, "bad credentials", http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			tokenRequests++
+			mu.Unlock()
+			_, _ = io.WriteString(writer, `{"access_token":"token-1","expires_in":3600}`)
+		case "/v4/games":
+			if request.Header.Get("Client-ID") != "client" || request.Header.Get("Authorization") != "Bearer token-1" {
+				http.Error(writer, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			body, _ := io.ReadAll(request.Body)
+			mu.Lock()
+			queries = append(queries, string(body))
+			mu.Unlock()
+			_, _ = io.WriteString(writer, `[{"name":"Hollow Knight","slug":"hollow-knight","summary":"地下王国を探索する。",
+"first_release_date":1487894400,"cover":{"image_id":"co93cr"},"genres":[{"name":"Platform"},{"name":"Adventure"}],
+"involved_companies":[{"company":{"name":"Team Cherry"},"developer":true},{"company":{"name":"Publisher X"},"publisher":true}]}]`)
+		case "/covers/co93cr.jpg":
+			writeTestCover(t, writer)
+		default:
+			http.NotFound(writer, request)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider := newIGDBProvider(newTestAPIScraper(t))
+	provider.limiter = newRequestLimiter(0)
+	provider.tokenURL = server.URL + "/oauth2/token"
+	provider.gamesURL = server.URL + "/v4/games"
+	provider.imageBaseURL = server.URL + "/covers/"
+
+	if _, err := provider.Fetch(context.Background(), "https://www.igdb.com/games/hollow-knight"); err == nil {
+		t.Fatal("expected error without credentials")
+	}
+	provider.SetCredentials("client", "secret")
+	for range 2 {
+		imported, err := provider.Fetch(context.Background(), "https://www.igdb.com/games/hollow-knight")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if imported.Provider != MetadataProviderIGDB || imported.SourceID != "hollow-knight" || imported.Title != "Hollow Knight" ||
+			imported.Brand != "Publisher X" || imported.ReleaseDate != "2017-02-24" || imported.Description != "地下王国を探索する。" ||
+			strings.Join(imported.Genres, ",") != "Platform,Adventure" || imported.ImagePath == "" {
+			t.Fatalf("unexpected import: %+v", imported)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected token to be cached, got %d token requests", tokenRequests)
+	}
+	if len(queries) != 2 || !strings.Contains(queries[0], `where slug = "hollow-knight"`) {
+		t.Fatalf("unexpected queries: %v", queries)
+	}
+}
+
+func TestIGDBProviderDoesNotHoldLockDuringTokenRequest(t *testing.T) {
+	t.Parallel()
+
+	requested := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		close(requested)
+		<-release
+		_, _ = io.WriteString(writer, `{"access_token":"old-token","expires_in":3600}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := newIGDBProvider(newTestAPIScraper(t))
+	provider.limiter = newRequestLimiter(0)
+	provider.tokenURL = server.URL
+	provider.SetCredentials("client", "secret")
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := provider.accessToken(context.Background())
+		done <- err
+	}()
+	<-requested
+	// トークンの取得を待っている間も、設定の読み書きは止まらない。
+	changed := make(chan struct{})
+	go func() {
+		provider.SetCredentials("client", "new-secret")
+		_ = provider.Configured()
+		close(changed)
+	}()
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected credentials to be updated while the token request is in flight")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// 古い認証情報で取ったトークンは保存しない。
+	if _, _, ok, _ := provider.cachedToken(); ok {
+		t.Fatal("expected the token for the old credentials to be discarded")
+	}
+}
+
+func TestMetadataServiceSavesAndLoadsProviderCredential(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	erogameScape := NewErogameScapeService(config.Config{AppDataDir: t.TempDir()}, logger)
+	store := &fakeCredentialStore{loadResult: &credentials.Credential{AccessKeyID: "saved", SecretAccessKey: "secret"}}
+	service := NewMetadataService(erogameScape, store, logger)
+	if store.loadedKey != MetadataProviderIGDB {
+		t.Fatalf("expected igdb credential to be loaded, got %q", store.loadedKey)
+	}
+	igdb := findProviderInfo(t, service, MetadataProviderIGDB)
+	if !igdb.RequiresCredential || !igdb.Configured {
+		t.Fatalf("expected igdb to be configured, got %+v", igdb)
+	}
+	if steam := findProviderInfo(t, service, MetadataProviderSteam); steam.RequiresCredential {
+		t.Fatalf("expected steam not to require credential, got %+v", steam)
+	}
+
+	if err := service.SetProviderCredential(context.Background(), "IGDB", " id ", " key "); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if store.savedKey != MetadataProviderIGDB || store.savedCredential.AccessKeyID != "id" || store.savedCredential.SecretAccessKey != "key" {
+		t.Fatalf("unexpected saved credential: %s %+v", store.savedKey, store.savedCredential)
+	}
+	if err := service.SetProviderCredential(context.Background(), MetadataProviderIGDB, "", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if store.deletedKey != MetadataProviderIGDB || findProviderInfo(t, service, MetadataProviderIGDB).Configured {
+		t.Fatal("expected credential to be deleted")
+	}
+	if err := service.SetProviderCredential(context.Background(), MetadataProviderSteam, "id", "key"); err == nil {
+		t.Fatal("expected steam credential to be rejected")
+	}
+}
+
+func findProviderInfo(t *testing.T, service *MetadataService, id string) domain.MetadataProviderInfo {
+	t.Helper()
+	for _, info := range service.Providers() {
+		if info.ID == id {
+			return info
+		}
+	}
+	t.Fatalf("provider %s not found", id)
+	return domain.MetadataProviderInfo{}
+}
@@ -0,0 +1,164 @@
+// 作品ページの URL からゲーム情報を取得する取り込み元（批評空間・DLSite・DMM・IGDB・Steam）の切り替えを提供する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+)
+
+// 取り込み元の ID。
+const (
+	MetadataProviderErogameScape = "erogamescape"
+	MetadataProviderDLSite       = "dlsite"
+	MetadataProviderDMM          = "dmm"
+	MetadataProviderIGDB         = "igdb"
+	MetadataProviderSteam        = "steam"
+)
+
+// MetadataProvider は作品ページの URL からゲーム情報（タイトル・ブランド・カバー画像・発売日）を取得する取り込み元。
+type MetadataProvider interface {
+	// ID は取り込み元の ID（MetadataProviderXxx）を返す。
+	ID() string
+	// Name は画面に出す取り込み元の名前を返す。
+	Name() string
+	// Matches は URL がこの取り込み元の作品ページかを返す。
+	Matches(pageURL string) bool
+	// Fetch は作品ページからゲーム情報を取得し、カバー画像をサムネイルとして保存する。
+	Fetch(ctx context.Context, pageURL string) (domain.GameImport, error)
+}
+
+// credentialedMetadataProvider は API キー（クライアント ID とシークレット）が必要な取り込み元。
+type credentialedMetadataProvider interface {
+	MetadataProvider
+	SetCredentials(clientID string, clientSecret string)
+	Configured() bool
+}
+
+// MetadataService は取り込み元を ID で選んでゲーム情報を取得する。
+// API キーは取り込み元の ID をキーとして credentialStore に保存する（AccessKeyID にクライアント ID、SecretAccessKey にシークレット）。
+type MetadataService struct {
+	providers       []MetadataProvider
+	credentialStore credentials.Store
+	logger          *slog.Logger
+}
+
+// NewMetadataService は批評空間・DLSite・DMM・IGDB・Steam を取り込み元とする MetadataService を生成し、保存済みの API キーを読み込む。
+// 他の取り込み元は批評空間と HTTP クライアント・サムネイル設定を共有するため、
+// ErogameScapeService の SetHTTPClient / SetThumbnailShortEdge がすべての取り込み元に反映される。
+func NewMetadataService(erogameScape *ErogameScapeService, credentialStore credentials.Store, logger *slog.Logger) *MetadataService {
+	service := newMetadataService(logger,
+		erogameScape,
+		&DLSiteProvider{scraper: erogameScape.metadataScraper},
+		&DMMProvider{scraper: erogameScape.metadataScraper},
+		newIGDBProvider(erogameScape.metadataScraper),
+		newSteamProvider(erogameScape.metadataScraper),
+	)
+	service.credentialStore = credentialStore
+	service.loadCredentials(context.Background())
+	return service
+}
+
+func newMetadataService(logger *slog.Logger, providers ...MetadataProvider) *MetadataService {
+	return &MetadataService{providers: providers, logger: logger}
+}
+
+// Providers は選択できる取り込み元を表示順に返す。
+func (service *MetadataService) Providers() []domain.MetadataProviderInfo {
+	infos := make([]domain.MetadataProviderInfo, 0, len(service.providers))
+	for _, provider := range service.providers {
+		info := domain.MetadataProviderInfo{ID: provider.ID(), Name: provider.Name()}
+		if credentialed, ok := provider.(credentialedMetadataProvider); ok {
+			info.RequiresCredential = true
+			info.Configured = credentialed.Configured()
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// SetProviderCredential は API キーが必要な取り込み元のクライアント ID とシークレットを保存して反映する。
+// どちらかが空なら保存済みのキーを削除する。
+func (service *MetadataService) SetProviderCredential(ctx context.Context, providerID string, clientID string, clientSecret string) error {
+	trimmedID := strings.ToLower(strings.TrimSpace(providerID))
+	credentialed, ok := service.resolve(trimmedID, "").(credentialedMetadataProvider)
+	if trimmedID == "" || !ok {
+		return newServiceError("API キーを設定できない取り込み元です", "providerID="+providerID)
+	}
+	if service.credentialStore == nil {
+		return newServiceError("API キーの保存先が未初期化です", "credential store is nil")
+	}
+	clientID = strings.TrimSpace(clientID)
+	clientSecret = strings.TrimSpace(clientSecret)
+	if clientID == "" || clientSecret == "" {
+		if error := service.credentialStore.Delete(ctx, trimmedID); error != nil {
+			return newServiceError("API キーの削除に失敗しました", error.Error())
+		}
+		credentialed.SetCredentials("", "")
+		service.logger.Info("取り込み元の API キーを削除", "provider", trimmedID)
+		return nil
+	}
+	credential := credentials.Credential{AccessKeyID: clientID, SecretAccessKey: clientSecret}
+	if error := service.credentialStore.Save(ctx, trimmedID, credential); error != nil {
+		return newServiceError("API キーの保存に失敗しました", error.Error())
+	}
+	credentialed.SetCredentials(clientID, clientSecret)
+	service.logger.Info("取り込み元の API キーを保存", "provider", trimmedID)
+	return nil
+}
+
+// loadCredentials は保存済みの API キーを取り込み元に反映する。未保存の取り込み元は未設定のままにする。
+func (service *MetadataService) loadCredentials(ctx context.Context) {
+	if service.credentialStore == nil {
+		return
+	}
+	for _, provider := range service.providers {
+		credentialed, ok := provider.(credentialedMetadataProvider)
+		if !ok {
+			continue
+		}
+		credential, error := service.credentialStore.Load(ctx, provider.ID())
+		if error != nil {
+			service.logger.Warn("取り込み元の API キーの読み込みに失敗", "provider", provider.ID(), "error", error)
+			continue
+		}
+		if credential != nil {
+			credentialed.SetCredentials(credential.AccessKeyID, credential.SecretAccessKey)
+		}
+	}
+}
+
+// Fetch は providerID の取り込み元で作品ページからゲーム情報を取得する。
+// providerID が空なら URL から取り込み元を判定する。
+func (service *MetadataService) Fetch(ctx context.Context, providerID string, pageURL string) (domain.GameImport, error) {
+	trimmedURL := strings.TrimSpace(pageURL)
+	provider := service.resolve(strings.ToLower(strings.TrimSpace(providerID)), trimmedURL)
+	if provider == nil {
+		return domain.GameImport{}, InvalidUrlError{URL: trimmedURL, Provider: strings.TrimSpace(providerID)}
+	}
+	imported, error := provider.Fetch(ctx, trimmedURL)
+	if error != nil {
+		return domain.GameImport{}, error
+	}
+	imported.Provider = provider.ID()
+	service.logger.Info("ゲーム情報を取得", "provider", provider.ID(), "sourceId", imported.SourceID, "title", imported.Title)
+	return imported, nil
+}
+
+func (service *MetadataService) resolve(providerID string, pageURL string) MetadataProvider {
+	for _, provider := range service.providers {
+		if providerID == "" {
+			if provider.Matches(pageURL) {
+				return provider
+			}
+			continue
+		}
+		if provider.ID() == providerID {
+			return provider
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+)
+
+func newTestMetadataService(t *testing.T, pages map[string]string, cookies map[string]string) (*MetadataService, string) {
+	t.Helper()
+	var cover bytes.Buffer
+	if err := png.Encode(&cover, image.NewRGBA(image.Rect(0, 0, 120, 160))); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/img/cover.png" {
+			writer.Header().Set("Content-Type", "image/png")
+			_, _ = writer.Write(cover.Bytes())
+			return
+		}
+		page, ok := pages[request.URL.Path]
+		if !ok {
+			http.NotFound(writer, request)
+			return
+		}
+		cookies[request.URL.Path] = request.Header.Get("Cookie")
+		_, _ = io.WriteString(writer, page)
+	}))
+	t.Cleanup(server.Close)
+
+	appDataDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	erogameScape := NewErogameScapeService(config.Config{AppDataDir: appDataDir, ThumbnailShortEdgePx: 64}, logger)
+	return NewMetadataService(erogameScape, &fakeCredentialStore{}, logger), server.URL
+}
+
+func TestMetadataServiceFetchesDLSiteWork(t *testing.T) {
+	t.Parallel()
+
+	cookies := map[string]string{}
+	path := "/maniax/work/=/product_id/RJ01234567.html"
+	service, baseURL := newTestMetadataService(t, map[string]string{path: `<html><head>
+<meta property="og:image" content="/img/cover.png"></head><body>
+<h1 id="work_name">ゲームタイトル</h1>
+<table id="work_maker"><tr><th>サークル名</th><td><span class="maker_name"><a href="#">サークルA</a></span></td></tr></table>
+<table id="work_outline"><tr><th>販売日</th><td><a href="#">2024年03月09日</a></td></tr></table>
+</body></html>`}, cookies)
+
+	imported, err := service.Fetch(context.Background(), MetadataProviderDLSite, baseURL+path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if imported.Provider != MetadataProviderDLSite || imported.SourceID != "RJ01234567" || imported.Title != "ゲームタイトル" ||
+		imported.Brand != "サークルA" || imported.ReleaseDate != "2024-03-09" || imported.ImageURL != baseURL+"/img/cover.png" {
+		t.Fatalf("unexpected import: %+v", imported)
+	}
+	if matches := thumbnailFileRegex.FindStringSubmatch(filepath.Base(imported.ImagePath)); matches == nil || matches[1] != "dlsite_RJ01234567" {
+		t.Fatalf("expected thumbnail to be regenerable, got %q", imported.ImagePath)
+	}
+	if cookies[path] != dlsiteCookie {
+		t.Fatalf("expected age check cookie, got %q", cookies[path])
+	}
+}
+
+func TestMetadataServiceFetchesDMMWork(t *testing.T) {
+	t.Parallel()
+
+	cookies := map[string]string{}
+	path := "/dc/doujin/-/detail/=/cid=d_123456/"
+	service, baseURL := newTestMetadataService(t, map[string]string{path: `<html><head>
+<meta property="og:title" content="og タイトル">
+<meta property="og:image" content="/img/cover.png"></head><body>
+<h1 class="productTitle__txt">
+  作品タイトル
+</h1>
+<dl><dt>サークル名：</dt><dd> ブランドB </dd><dt>配信開始日</dt><dd>2023/07/01 10:00</dd></dl>
+</body></html>`}, cookies)
+
+	imported, err := service.Fetch(context.Background(), "DMM", baseURL+path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if imported.Provider != MetadataProviderDMM || imported.SourceID != "d_123456" || imported.Title != "作品タイトル" ||
+		imported.Brand != "ブランドB" || imported.ReleaseDate != "2023-07-01" {
+		t.Fatalf("unexpected import: %+v", imported)
+	}
+	if cookies[path] != dmmCookie {
+		t.Fatalf("expected age check cookie, got %q", cookies[path])
+	}
+}
+
+func TestMetadataServiceRequiresTitle(t *testing.T) {
+	t.Parallel()
+
+	path := "/work/=/product_id/RJ000001.html"
+	service, baseURL := newTestMetadataService(t, map[string]string{path: `<html><body><p>年齢確認</p></body></html>`}, map[string]string{})
+
+	_, err := service.Fetch(context.Background(), MetadataProviderDLSite, baseURL+path)
+	var parseErr ParseError
+	if !errors.As(err, &parseErr) || parseErr.Field != "title" || parseErr.Error() != "failed to parse dlsite page (title)" {
+		t.Fatalf("expected title parse error, got %v", err)
+	}
+}
+
+func TestMetadataServiceResolvesProviderFromURL(t *testing.T) {
+	t.Parallel()
+
+	service, _ := newTestMetadataService(t, nil, map[string]string{})
+	cases := map[string]string{
+		"https://erogamescape.dyndns.org/~ap2/ero/toukei_kaiseki/game.php?game=12345": MetadataProviderErogameScape,
+		"https://www.dlsite.com/maniax/work/=/product_id/RJ01234567.html":             MetadataProviderDLSite,
+		"https://www.dlsite.com/pro/work/=/product_id/VJ012345.html":                  MetadataProviderDLSite,
+		"https://dlsoft.dmm.co.jp/detail/abcd_0001/":                                  MetadataProviderDMM,
+		"https://www.dmm.co.jp/mono/pcgame/-/detail/=/cid=1234abcd/":                  MetadataProviderDMM,
+		"https://www.dmm.co.jp/dc/doujin/-/detail/=/cid=d_123456/?dmmref=ranking":     MetadataProviderDMM,
+		"https://www.igdb.com/games/hollow-knight":                                    MetadataProviderIGDB,
+		"https://store.steampowered.com/app/367520/Hollow_Knight/":                    MetadataProviderSteam,
+	}
+	for pageURL, want := range cases {
+		provider := service.resolve("", pageURL)
+		if provider == nil || provider.ID() != want {
+			t.Fatalf("expected %s for %s, got %v", want, pageURL, provider)
+		}
+	}
+	if service.resolve("", "https://example.com/game/1") != nil {
+		t.Fatal("expected unknown sites not to resolve")
+	}
+
+	_, err := service.Fetch(context.Background(), "gog", "https://www.gog.com/game/1")
+	var invalid InvalidUrlError
+	if !errors.As(err, &invalid) || invalid.Error() != "invalid gog url: https://www.gog.com/game/1" {
+		t.Fatalf("expected invalid url error, got %v", err)
+	}
+	if got := len(service.Providers()); got != 5 {
+		t.Fatalf("expected 5 providers, got %d", got)
+	}
+}
+
+func TestParseReleaseDate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"2024年03月09日":      "2024-03-09",
+		"2024年3月9日 0時":     "2024-03-09",
+		"2023/07/01 10:00": "2023-07-01",
+		"発売日：2022-12-31":   "2022-12-31",
+		"2024年02月30日":      "",
+		"近日発売":             "",
+	}
+	for text, want := range cases {
+		if got := parseReleaseDate(text); got != want {
+			t.Fatalf("parseReleaseDate(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+// 外部サイトの作品ページの取得と、カバー画像のサムネイル保存を提供する（各取り込み元で共有する）。
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/httpclient"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// releaseDateRegex は作品ページの日付表記（"2024年03月29日"・"2024/03/29" など）。
+var releaseDateRegex = regexp.MustCompile(`(\d{4})\s*[年/.\-]\s*(\d{1,2})\s*[月/.\-]\s*(\d{1,2})`)
+
+// metadataScraper は作品ページの HTML と画像を取得する。
+// HTTP クライアントとサムネイル短辺は実行時に差し替えられ、同じ scraper を使う取り込み元すべてに反映される。
+type metadataScraper struct {
+	appDataDir string
+	logger     *slog.Logger
+	httpClient atomic.Pointer[http.Client]
+	// thumbnailShortEdge はサムネイルの短辺ピクセル数。設定変更時に SetThumbnailShortEdge で更新する。
+	thumbnailShortEdge atomic.Int64
+}
+
+// newMetadataScraper は metadataScraper を生成する。
+// HTTP 設定が不正な場合はプロキシ等を使わない既定クライアントで続行する。
+func newMetadataScraper(cfg config.Config, logger *slog.Logger) *metadataScraper {
+	scraper := &metadataScraper{
+		appDataDir: cfg.AppDataDir,
+		logger:     logger,
+	}
+	scraper.SetThumbnailShortEdge(cfg.ThumbnailShortEdgePx)
+	client, error := NewHTTPClient(cfg)
+	if error != nil {
+		logger.Warn("HTTP設定が不正なため既定のクライアントを使用", "error", error)
+		client, _ = httpclient.New(httpclient.Options{})
+	}
+	scraper.SetHTTPClient(client)
+	return scraper
+}
+
+// SetHTTPClient は実行時に HTTP 通信設定が変わった際、取得に使うクライアントを差し替える。
+func (scraper *metadataScraper) SetHTTPClient(client *http.Client) {
+	scraper.httpClient.Store(client)
+}
+
+// SetThumbnailShortEdge は以降に取り込む画像のサムネイル短辺ピクセル数を更新する。
+// 範囲外の値は既定値として扱う。
+func (scraper *metadataScraper) SetThumbnailShortEdge(shortEdge int) {
+	if ValidateThumbnailShortEdge(shortEdge) != nil {
+		shortEdge = DefaultThumbnailShortEdgePx
+	}
+	scraper.thumbnailShortEdge.Store(int64(shortEdge))
+}
+
+// fetchHTML はページの HTML を取得する。https で取得できなければ http で1回だけ取り直す。
+// cookie は年齢確認を済ませた扱いにするためのもので、空なら送らない。
+func (scraper *metadataScraper) fetchHTML(ctx context.Context, gamePageURL string, cookie string) (string, error) {
+	page, error := scraper.fetchPage(ctx, gamePageURL, cookie, nil)
+	if error != nil {
+		return "", error
+	}
+	return page.Body, nil
+}
+
+// fetchCachedHTML は cache と limiter を通してページの HTML を取得する。
+// TTL 内のキャッシュがあれば通信せずに返し、期限切れなら条件付き GET で再検証する（304 なら保存済みの本文を使う）。
+// 取得に失敗しても期限切れのキャッシュがあれば、そちらを返して処理を続ける。
+func (scraper *metadataScraper) fetchCachedHTML(ctx context.Context, pageURL string, cookie string, cache *pageCache, limiter *requestLimiter) (string, error) {
+	cached, hit := cache.lookup(pageURL)
+	if hit && cache.fresh(cached) {
+		return cached.Body, nil
+	}
+	if error := limiter.Wait(ctx); error != nil {
+		return "", FetchError{URL: pageURL, Err: error}
+	}
+	var conditional *cachedPage
+	if hit {
+		conditional = &cached
+	}
+	page, error := scraper.fetchPage(ctx, pageURL, cookie, conditional)
+	if error != nil {
+		if hit && ctx.Err() == nil {
+			scraper.logger.Warn("ページの取得に失敗したため期限切れのキャッシュを使用", "url", pageURL, "error", error)
+			return cached.Body, nil
+		}
+		return "", error
+	}
+	page.URL = pageURL
+	page.FetchedAt = cache.now()
+	if error := cache.store(page); error != nil {
+		scraper.logger.Warn("ページのキャッシュ保存に失敗", "url", pageURL, "error", error)
+	}
+	return page.Body, nil
+}
+
+// fetchPage は fetchHTML と同じくページを取得し、本文と再検証用のヘッダーを返す。
+// conditional を渡すと If-None-Match / If-Modified-Since を付け、304 なら conditional の本文を返す。
+func (scraper *metadataScraper) fetchPage(ctx context.Context, gamePageURL string, cookie string, conditional *cachedPage) (cachedPage, error) {
+	page, error := scraper.fetchPageOnce(ctx, gamePageURL, cookie, conditional)
+	if error == nil {
+		return page, nil
+	}
+
+	parsed, parseErr := url.Parse(gamePageURL)
+	if parseErr != nil {
+		return cachedPage{}, error
+	}
+	if strings.EqualFold(parsed.Scheme, "https") {
+		parsed.Scheme = "http"
+		fallbackURL := parsed.String()
+		fallbackPage, fallbackErr := scraper.fetchPageOnce(ctx, fallbackURL, cookie, conditional)
+		if fallbackErr == nil {
+			return fallbackPage, nil
+		}
+	}
+	return cachedPage{}, error
+}
+
+func (scraper *metadataScraper) fetchPageOnce(ctx context.Context, gamePageURL string, cookie string, conditional *cachedPage) (cachedPage, error) {
+	request, error := http.NewRequestWithContext(ctx, http.MethodGet, gamePageURL, nil)
+	if error != nil {
+		return cachedPage{}, FetchError{URL: gamePageURL, Err: error}
+	}
+	if cookie != "" {
+		request.Header.Set("Cookie", cookie)
+	}
+	if conditional != nil {
+		if conditional.ETag != "" {
+			request.Header.Set("If-None-Match", conditional.ETag)
+		}
+		if conditional.LastModified != "" {
+			request.Header.Set("If-Modified-Since", conditional.LastModified)
+		}
+	}
+
+	response, error := scraper.httpClient.Load().Do(request)
+	if error != nil {
+		return cachedPage{}, FetchError{URL: gamePageURL, Err: error}
+	}
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			scraper.logger.Warn("HTMLレスポンスのクローズに失敗", "error", closeErr)
+		}
+	}()
+
+	if response.StatusCode == http.StatusNotModified && conditional != nil {
+		page := *conditional
+		if etag := response.Header.Get("ETag"); etag != "" {
+			page.ETag = etag
+		}
+		if lastModified := response.Header.Get("Last-Modified"); lastModified != "" {
+			page.LastModified = lastModified
+		}
+		return page, nil
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return cachedPage{}, FetchError{URL: gamePageURL, StatusCode: response.StatusCode, Err: errors.New(response.Status)}
+	}
+
+	body, error := io.ReadAll(response.Body)
+	if error != nil {
+		return cachedPage{}, FetchError{URL: gamePageURL, Err: error}
+	}
+	return cachedPage{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		Body:         string(body),
+	}, nil
+}
+
+// downloadAndSaveImage は画像を取得してサムネイルと原寸画像を保存し、サムネイルのパスを返す。
+// imageKey はファイル名に使う取り込み元の作品 ID（批評空間は数字のみ、それ以外は "<取り込み元>_<ID>"）。
+func (scraper *metadataScraper) downloadAndSaveImage(ctx context.Context, imageURL string, imageKey string) (string, error) {
+	request, error := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if error != nil {
+		return "", ImageError{URL: imageURL, Err: error}
+	}
+
+	response, error := scraper.httpClient.Load().Do(request)
+	if error != nil {
+		return "", ImageError{URL: imageURL, Err: error}
+	}
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			scraper.logger.Warn("画像レスポンスのクローズに失敗", "error", closeErr)
+		}
+	}()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return "", ImageError{URL: imageURL, Err: errors.New(response.Status)}
+	}
+
+	raw, error := io.ReadAll(response.Body)
+	if error != nil {
+		return "", ImageError{URL: imageURL, Err: error}
+	}
+
+	_, format, error := image.DecodeConfig(bytes.NewReader(raw))
+	if error != nil {
+		return "", ImageError{URL: imageURL, Err: error}
+	}
+	ext := chooseImageExtension(imageURL, response.Header.Get("Content-Type"), format)
+	if ext == "" {
+		ext = ".jpg"
+	}
+
+	thumbnailsDir := filepath.Join(scraper.appDataDir, thumbnailsDirName)
+	// サイズ変更時に再生成できるよう、縮小前の画像を残しておく。
+	if error := saveThumbnailOriginal(thumbnailsDir, imageKey, ext, raw); error != nil {
+		return "", ImageError{URL: imageURL, Err: error}
+	}
+	fullPath, error := renderThumbnail(thumbnailsDir, imageKey, ext, raw, int(scraper.thumbnailShortEdge.Load()))
+	if error != nil {
+		return "", ImageError{URL: imageURL, Err: error}
+	}
+	return fullPath, nil
+}
+
+// parseReleaseDate は作品ページの日付表記を YYYY-MM-DD にする。読み取れなければ空文字を返す。
+func parseReleaseDate(text string) string {
+	matches := releaseDateRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return ""
+	}
+	date, err := time.Parse("2006-1-2", fmt.Sprintf("%s-%s-%s", matches[1], matches[2], matches[3]))
+	if err != nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+// labeledValue は表（th/td）と定義リスト（dt/dd）から、見出しが labels のいずれかに一致する項目の値を返す。
+// 見出し末尾のコロンは無視する。見つからなければ空文字を返す。
+func labeledValue(doc *goquery.Document, labels ...string) string {
+	matchesLabel := func(text string) bool {
+		label := strings.TrimRight(strings.TrimSpace(text), ":：")
+		for _, candidate := range labels {
+			if label == candidate {
+				return true
+			}
+		}
+		return false
+	}
+	value := ""
+	doc.Find("tr").EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		cells := row.Children().Filter("th, td")
+		if cells.Length() < 2 || !matchesLabel(cells.Eq(0).Text()) {
+			return true
+		}
+		value = collapseSpaces(cells.Eq(1).Text())
+		return value == ""
+	})
+	if value != "" {
+		return value
+	}
+	doc.Find("dt").EachWithBreak(func(_ int, term *goquery.Selection) bool {
+		if !matchesLabel(term.Text()) {
+			return true
+		}
+		value = collapseSpaces(term.NextFiltered("dd").Text())
+		return value == ""
+	})
+	return value
+}
+
+// metaContent は <meta property="..."> の content を返す。
+func metaContent(doc *goquery.Document, property string) string {
+	return strings.TrimSpace(doc.Find(`meta[property="`+property+`"]`).First().AttrOr("content", ""))
+}
+
+// firstText は selectors を順に探し、最初に見つかった空でないテキストを返す。
+func firstText(doc *goquery.Document, selectors ...string) string {
+	for _, selector := range selectors {
+		if text := collapseSpaces(doc.Find(selector).First().Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// collapseSpaces は改行やインデントを含むテキストを1行にまとめる。
+func collapseSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// storeWork は DLSite・DMM の作品ページから読み取った項目。
+type storeWork struct {
+	title       string
+	brand       string
+	imageSrc    string
+	releaseDate string
+}
+
+// fetchStoreWork は販売サイトの作品ページを取得して extract で項目を読み取り、カバー画像を保存した GameImport を返す。
+// タイトルとカバー画像は必須で、ブランドと発売日は読み取れた場合のみ設定する。
+func (scraper *metadataScraper) fetchStoreWork(
+	ctx context.Context,
+	providerID string,
+	sourceID string,
+	pageURL string,
+	cookie string,
+	extract func(doc *goquery.Document) storeWork,
+) (domain.GameImport, error) {
+	pageHTML, error := scraper.fetchHTML(ctx, pageURL, cookie)
+	if error != nil {
+		return domain.GameImport{}, error
+	}
+	doc, error := goquery.NewDocumentFromReader(strings.NewReader(pageHTML))
+	if error != nil {
+		return domain.GameImport{}, ParseError{Field: "document", Err: error, Provider: providerID}
+	}
+
+	work := extract(doc)
+	if work.title == "" {
+		return domain.GameImport{}, ParseError{Field: "title", Err: errors.New("title not found"), Provider: providerID}
+	}
+	if work.imageSrc == "" {
+		return domain.GameImport{}, ParseError{Field: "imageUrl", Err: errors.New("image url not found"), Provider: providerID}
+	}
+	imageURL, error := resolveURL(pageURL, work.imageSrc)
+	if error != nil {
+		return domain.GameImport{}, ParseError{Field: "imageUrl", Err: error, Provider: providerID}
+	}
+	imagePath, error := scraper.downloadAndSaveImage(ctx, imageURL, providerID+"_"+sourceID)
+	if error != nil {
+		return domain.GameImport{}, error
+	}
+
+	return domain.GameImport{
+		Provider:    providerID,
+		SourceID:    sourceID,
+		Title:       work.title,
+		Brand:       work.brand,
+		ImagePath:   imagePath,
+		ImageURL:    imageURL,
+		ReleaseDate: work.releaseDate,
+	}, nil
+}
+
+// fetchJSON はリクエストを送り、2xx の応答本文を out にデコードする。
+func (scraper *metadataScraper) fetchJSON(request *http.Request, out any) error {
+	requestURL := request.URL.String()
+	response, error := scraper.httpClient.Load().Do(request)
+	if error != nil {
+		return FetchError{URL: requestURL, Err: error}
+	}
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			scraper.logger.Warn("APIレスポンスのクローズに失敗", "error", closeErr)
+		}
+	}()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return FetchError{URL: requestURL, StatusCode: response.StatusCode, Err: errors.New(response.Status)}
+	}
+	if error := json.NewDecoder(response.Body).Decode(out); error != nil {
+		return FetchError{URL: requestURL, Err: error}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Steam ストアの API からゲーム情報を取得する取り込み元を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	steamAppDetailsURL = "https://store.steampowered.com/api/appdetails"
+	steamLibraryArtURL = "https://shared.cloudflare.steamstatic.com/store_item_assets/steam/apps/%s/library_600x900.jpg"
+	// steamRequestInterval はストア API の上限（5分あたり約200リクエスト）を超えないためのリクエスト間隔。
+	steamRequestInterval = 1500 * time.Millisecond
+)
+
+// steamAppIDRegex は Steam ストアのページ（https://store.steampowered.com/app/<appid>/...）の AppID。
+var steamAppIDRegex = regexp.MustCompile(`store\.steampowered\.com/app/(\d+)`)
+
+// steamEnglishDateLayouts は日本語以外で返ってきた発売日の表記。
+var steamEnglishDateLayouts = []string{"2 Jan, 2006", "Jan 2, 2006", "2 January 2006", "January 2, 2006"}
+
+// SteamProvider は Steam ストアの API からタイトル・パブリッシャー・カバー画像・発売日・ジャンル・概要を取得する。
+// API キーは不要。
+type SteamProvider struct {
+	scraper       *metadataScraper
+	limiter       *requestLimiter
+	appDetailsURL string
+	libraryArtURL string
+}
+
+func newSteamProvider(scraper *metadataScraper) *SteamProvider {
+	return &SteamProvider{
+		scraper:       scraper,
+		limiter:       newRequestLimiter(steamRequestInterval),
+		appDetailsURL: steamAppDetailsURL,
+		libraryArtURL: steamLibraryArtURL,
+	}
+}
+
+// ID は取り込み元の ID を返す。
+func (provider *SteamProvider) ID() string {
+	return MetadataProviderSteam
+}
+
+// Name は取り込み元の名前を返す。
+func (provider *SteamProvider) Name() string {
+	return "Steam"
+}
+
+// Matches は Steam ストアのゲームページの URL かを返す。
+func (provider *SteamProvider) Matches(pageURL string) bool {
+	return steamAppIDRegex.MatchString(strings.ToLower(pageURL))
+}
+
+type steamAppDetails struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Name             string   `json:"name"`
+		ShortDescription string   `json:"short_description"`
+		HeaderImage      string   `json:"header_image"`
+		Publishers       []string `json:"publishers"`
+		Developers       []string `json:"developers"`
+		Genres           []struct {
+			Description string `json:"description"`
+		} `json:"genres"`
+		ReleaseDate struct {
+			Date string `json:"date"`
+		} `json:"release_date"`
+	} `json:"data"`
+}
+
+// Fetch は URL の AppID のゲーム情報を Steam ストアから日本語で取得する。
+// カバーは縦長のライブラリ画像を優先し、無いゲームはストアのヘッダー画像を使う。
+func (provider *SteamProvider) Fetch(ctx context.Context, pageURL string) (domain.GameImport, error) {
+	matches := steamAppIDRegex.FindStringSubmatch(strings.ToLower(pageURL))
+	if matches == nil {
+		return domain.GameImport{}, InvalidUrlError{URL: pageURL, Provider: MetadataProviderSteam}
+	}
+	appID := matches[1]
+
+	params := url.Values{}
+	params.Set("appids", appID)
+	params.Set("l", "japanese")
+	params.Set("cc", "jp")
+	requestURL := provider.appDetailsURL + "?" + params.Encode()
+	if error := provider.limiter.Wait(ctx); error != nil {
+		return domain.GameImport{}, FetchError{URL: requestURL, Err: error}
+	}
+	request, error := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if error != nil {
+		return domain.GameImport{}, FetchError{URL: requestURL, Err: error}
+	}
+	var response map[string]steamAppDetails
+	if error := provider.scraper.fetchJSON(request, &response); error != nil {
+		return domain.GameImport{}, error
+	}
+	details, ok := response[appID]
+	if !ok || !details.Success || strings.TrimSpace(details.Data.Name) == "" {
+		return domain.GameImport{}, ParseError{Field: "title", Err: errors.New("app not found"), Provider: MetadataProviderSteam}
+	}
+
+	imageKey := MetadataProviderSteam + "_" + appID
+	imageURL := fmt.Sprintf(provider.libraryArtURL, appID)
+	imagePath, error := provider.scraper.downloadAndSaveImage(ctx, imageURL, imageKey)
+	if error != nil {
+		if details.Data.HeaderImage == "" {
+			return domain.GameImport{}, error
+		}
+		imageURL = details.Data.HeaderImage
+		imagePath, error = provider.scraper.downloadAndSaveImage(ctx, imageURL, imageKey)
+		if error != nil {
+			return domain.GameImport{}, error
+		}
+	}
+
+	imported := domain.GameImport{
+		Provider:    MetadataProviderSteam,
+		SourceID:    appID,
+		Title:       strings.TrimSpace(details.Data.Name),
+		ImagePath:   imagePath,
+		ImageURL:    imageURL,
+		ReleaseDate: parseSteamReleaseDate(details.Data.ReleaseDate.Date),
+		Description: strings.TrimSpace(html.UnescapeString(details.Data.ShortDescription)),
+	}
+	if len(details.Data.Publishers) > 0 {
+		imported.Brand = strings.TrimSpace(details.Data.Publishers[0])
+	} else if len(details.Data.Developers) > 0 {
+		imported.Brand = strings.TrimSpace(details.Data.Developers[0])
+	}
+	for _, genre := range details.Data.Genres {
+		if name := strings.TrimSpace(genre.Description); name != "" {
+			imported.Genres = append(imported.Genres, name)
+		}
+	}
+	return imported, nil
+}
+
+// parseSteamReleaseDate は Steam の発売日表記（"2020年3月23日" や "23 Mar, 2020"）を YYYY-MM-DD にする。
+// 未定（"近日登場" など）なら空文字を返す。
+func parseSteamReleaseDate(text string) string {
+	if date := parseReleaseDate(text); date != "" {
+		return date
+	}
+	trimmed := strings.TrimSpace(text)
+	for _, layout := range steamEnglishDateLayouts {
+		if date, err := time.Parse(layout, trimmed); err == nil {
+			return date.Format("2006-01-02")
+		}
+	}
+	return ""
+}
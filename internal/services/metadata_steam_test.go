@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSteamProviderFallsBackToHeaderImage(t *testing.T) {
+	t.Parallel()
+
+	var headerURL string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/api/appdetails":
+			if request.URL.Query().Get("appids") != "367520" || request.URL.Query().Get("l") != "japanese" {
+				http.Error(writer, "bad query", http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(writer, `{"367520":{"success":true,"data":{"name":"Hollow Knight",
+"short_description":"虫たちの王国 &amp; 探索","header_image":"`+headerURL+`","publishers":["Team Cherry"],
+"genres":[{"description":"アクション"},{"description":"インディー"}],"release_date":{"date":"2017年2月24日"}}}}`)
+		case "/header.jpg":
+			writeTestCover(t, writer)
+		default:
+			http.NotFound(writer, request)
+		}
+	}))
+	t.Cleanup(server.Close)
+	headerURL = server.URL + "/header.jpg"
+
+	provider := newSteamProvider(newTestAPIScraper(t))
+	provider.limiter = newRequestLimiter(0)
+	provider.appDetailsURL = server.URL + "/api/appdetails"
+	provider.libraryArtURL = server.URL + "/library/%s.jpg"
+
+	imported, err := provider.Fetch(context.Background(), "https://store.steampowered.com/app/367520/Hollow_Knight/")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if imported.SourceID != "367520" || imported.Title != "Hollow Knight" || imported.Brand != "Team Cherry" ||
+		imported.ReleaseDate != "2017-02-24" || imported.Description != "虫たちの王国 & 探索" ||
+		strings.Join(imported.Genres, ",") != "アクション,インディー" || imported.ImageURL != headerURL {
+		t.Fatalf("unexpected import: %+v", imported)
+	}
+	if matches := thumbnailFileRegex.FindStringSubmatch(filepath.Base(imported.ImagePath)); matches == nil || matches[1] != "steam_367520" {
+		t.Fatalf("expected thumbnail to be regenerable, got %q", imported.ImagePath)
+	}
+}
+
+func TestParseSteamReleaseDate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"2017年2月24日":   "2017-02-24",
+		"24 Feb, 2017": "2017-02-24",
+		"Feb 24, 2017": "2017-02-24",
+		"近日登場":         "",
+	}
+	for text, want := range cases {
+		if got := parseSteamReleaseDate(text); got != want {
+			t.Fatalf("parseSteamReleaseDate(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// S3 エンドポイントへの疎通を定期的に確認し、オンライン/オフラインの変化を通知する。
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+	"CloudLaunch_Go/internal/logging"
+)
+
+const (
+	defaultNetworkProbeInterval = 30 * time.Second
+	defaultNetworkProbeTimeout  = 5 * time.Second
+	// オフラインと判定している間は回復をすぐ反映できるよう、短い間隔で確認し直す。
+	defaultOfflineProbeInterval = 10 * time.Second
+	// networkOfflineThreshold 回連続で到達できなかったときだけオフラインとみなす。
+	// 一時的な瞬断でバナーが点滅しないようにするため。
+	networkOfflineThreshold = 2
+)
+
+// errNetworkProbeSkipped は認証情報未設定などで疎通確認を行わなかったことを表す。
+var errNetworkProbeSkipped = errors.New("network probe skipped")
+
+// NetworkStatus はクラウドへの接続状態を表す。
+type NetworkStatus struct {
+	Online    bool      `json:"online"`
+	CheckedAt time.Time `json:"checkedAt"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// NetworkMonitor は設定中のバケットへ HeadBucket を送り、到達できるかを監視する。
+// HTTP 応答が返るエラー（権限不足など）は到達できているのでオンラインとして扱い、
+// 通信自体が失敗したときだけオフラインへ切り替える。
+type NetworkMonitor struct {
+	config   config.Config
+	store    credentials.Store
+	logger   *slog.Logger
+	probe    func(ctx context.Context) error
+	interval time.Duration
+	// offlineInterval はオフラインと判定している間の確認間隔。
+	offlineInterval time.Duration
+	timeout         time.Duration
+	onChange        func(NetworkStatus)
+	mu              sync.Mutex
+	status          NetworkStatus
+	failures        int
+	stop            chan struct{}
+	checkLock       sync.Mutex
+}
+
+// NewNetworkMonitor は NetworkMonitor を生成する。初期状態はオンライン。
+func NewNetworkMonitor(cfg config.Config, store credentials.Store, logger *slog.Logger) *NetworkMonitor {
+	monitor := &NetworkMonitor{
+		config:          cfg,
+		store:           store,
+		logger:          logger,
+		interval:        defaultNetworkProbeInterval,
+		offlineInterval: defaultOfflineProbeInterval,
+		timeout:         defaultNetworkProbeTimeout,
+		status:          NetworkStatus{Online: true},
+	}
+	monitor.probe = monitor.headBucket
+	return monitor
+}
+
+func (monitor *NetworkMonitor) SetS3ForcePathStyle(enabled bool) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	monitor.config.S3ForcePathStyle = enabled
+}
+
+func (monitor *NetworkMonitor) SetS3UseTLS(enabled bool) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	monitor.config.S3UseTLS = enabled
+}
+
+// SetStorageBackend は同期データの保存先を切り替える。S3 以外が保存先のときは疎通確認をせず、
+// 接続断でオフラインにしていた場合はオンラインへ戻して通知する。
+func (monitor *NetworkMonitor) SetStorageBackend(backend string) {
+	monitor.mu.Lock()
+	monitor.config.StorageBackend = backend
+	if NormalizeStorageBackend(backend) == StorageBackendS3 || monitor.status.Online {
+		monitor.mu.Unlock()
+		return
+	}
+	monitor.failures = 0
+	monitor.status = NetworkStatus{Online: true, CheckedAt: time.Now()}
+	next, onChange := monitor.status, monitor.onChange
+	monitor.mu.Unlock()
+	if onChange != nil {
+		onChange(next)
+	}
+}
+
+// SetCredentialKey は使用する認証情報プロファイルを切り替える。
+func (monitor *NetworkMonitor) SetCredentialKey(key string) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	monitor.config.CredentialKey = key
+}
+
+// SetOnChange はオンライン/オフラインが切り替わったときの通知先を設定する。
+func (monitor *NetworkMonitor) SetOnChange(fn func(NetworkStatus)) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	monitor.onChange = fn
+}
+
+// Status は直近の接続状態を返す。
+func (monitor *NetworkMonitor) Status() NetworkStatus {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	return monitor.status
+}
+
+// Start は定期的な疎通確認を開始する。起動直後にも1回確認する。
+// オフラインと判定している間も確認を続け、到達できた時点でオンラインへ戻す。
+func (monitor *NetworkMonitor) Start(ctx context.Context) {
+	monitor.mu.Lock()
+	if monitor.stop != nil {
+		monitor.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	monitor.stop = stop
+	monitor.mu.Unlock()
+
+	go func() {
+		tick := func() {
+			defer logging.Recover(monitor.logger, "network-monitor.check")
+			monitor.CheckNow(ctx)
+		}
+		tick()
+		timer := time.NewTimer(monitor.nextInterval())
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+				tick()
+				timer.Reset(monitor.nextInterval())
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// nextInterval は現在の接続状態に応じた次の確認までの間隔を返す。
+func (monitor *NetworkMonitor) nextInterval() time.Duration {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	if !monitor.status.Online && monitor.offlineInterval > 0 {
+		return monitor.offlineInterval
+	}
+	return monitor.interval
+}
+
+// Stop は疎通確認を停止する。
+func (monitor *NetworkMonitor) Stop() {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	if monitor.stop == nil {
+		return
+	}
+	close(monitor.stop)
+	monitor.stop = nil
+}
+
+// CheckNow はすぐに疎通を確認し、更新後の接続状態を返す。
+// 状態が切り替わった場合は onChange を呼ぶ。認証情報が未設定なら状態を変えないが、
+// オフラインと判定していた場合は確認できる相手がいなくなったのでオンラインへ戻す。
+func (monitor *NetworkMonitor) CheckNow(ctx context.Context) NetworkStatus {
+	monitor.checkLock.Lock()
+	defer monitor.checkLock.Unlock()
+
+	probeCtx, cancel := context.WithTimeout(ctx, monitor.timeout)
+	err := monitor.probe(probeCtx)
+	cancel()
+	if ctx.Err() != nil {
+		return monitor.Status()
+	}
+
+	monitor.mu.Lock()
+	previous := monitor.status
+	// 確認を省いた場合は、オフラインからの復帰だけを反映する。
+	if errors.Is(err, errNetworkProbeSkipped) && previous.Online {
+		monitor.mu.Unlock()
+		return previous
+	}
+	next := NetworkStatus{Online: true, CheckedAt: time.Now()}
+	if storage.IsUnreachableError(err) {
+		monitor.failures++
+		next.Error = err.Error()
+		next.Online = previous.Online && monitor.failures < networkOfflineThreshold
+	} else {
+		monitor.failures = 0
+	}
+	monitor.status = next
+	onChange := monitor.onChange
+	monitor.mu.Unlock()
+
+	if next.Online != previous.Online {
+		if next.Online {
+			monitor.logger.Info("クラウドへの接続が回復しました")
+		} else {
+			monitor.logger.Warn("クラウドに接続できないためオフラインに切り替えます", "error", err)
+		}
+		if onChange != nil {
+			onChange(next)
+		}
+	}
+	return next
+}
+
+// headBucket は現在の認証情報でバケットへ HeadBucket を送る。保存先が S3 以外なら確認しない。
+func (monitor *NetworkMonitor) headBucket(ctx context.Context) error {
+	monitor.mu.Lock()
+	cfg := monitor.config
+	monitor.mu.Unlock()
+	if NormalizeStorageBackend(cfg.StorageBackend) != StorageBackendS3 {
+		return errNetworkProbeSkipped
+	}
+	credential, err := monitor.store.Load(ctx, credentialKeyOf(cfg))
+	if err != nil || credential == nil {
+		return errNetworkProbeSkipped
+	}
+	s3Config := resolveS3Config(cfg, credential)
+	if s3Config.Bucket == "" {
+		return errNetworkProbeSkipped
+	}
+	client, err := storage.NewClient(ctx, s3Config, *credential)
+	if err != nil {
+		return err
+	}
+	return storage.HeadBucket(ctx, client, s3Config.Bucket)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+)
+
+func TestNetworkMonitorSwitchesOfflineAfterConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	monitor := NewNetworkMonitor(config.Config{}, &fakeCredentialStore{}, newTestLogger())
+	var probeErr error
+	monitor.probe = func(context.Context) error { return probeErr }
+	var changes []NetworkStatus
+	monitor.SetOnChange(func(status NetworkStatus) { changes = append(changes, status) })
+
+	probeErr = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	if status := monitor.CheckNow(context.Background()); !status.Online {
+		t.Fatal("expected a single failure to keep online")
+	}
+	if status := monitor.CheckNow(context.Background()); status.Online || status.Error == "" {
+		t.Fatalf("expected offline after consecutive failures, got %#v", status)
+	}
+
+	probeErr = nil
+	if status := monitor.CheckNow(context.Background()); !status.Online {
+		t.Fatal("expected recovery on first success")
+	}
+	if len(changes) != 2 || changes[0].Online || !changes[1].Online {
+		t.Fatalf("expected offline then online notifications, got %#v", changes)
+	}
+}
+
+func TestNetworkMonitorTreatsHTTPErrorsAndSkippedProbesAsOnline(t *testing.T) {
+	t.Parallel()
+
+	monitor := NewNetworkMonitor(config.Config{}, &fakeCredentialStore{}, newTestLogger())
+	monitor.probe = func(context.Context) error { return errors.New("AccessDenied") }
+	for i := 0; i < networkOfflineThreshold+1; i++ {
+		if status := monitor.CheckNow(context.Background()); !status.Online {
+			t.Fatal("expected non-network error to stay online")
+		}
+	}
+
+	// 認証情報が無い場合は probe が errNetworkProbeSkipped を返し、状態は変わらない。
+	monitor.probe = monitor.headBucket
+	if status := monitor.CheckNow(context.Background()); !status.Online {
+		t.Fatal("expected skipped probe to keep status")
+	}
+}
+
+func TestNetworkMonitorKeepsProbingWhileOffline(t *testing.T) {
+	t.Parallel()
+
+	monitor := NewNetworkMonitor(config.Config{}, &fakeCredentialStore{}, newTestLogger())
+	monitor.interval = time.Hour
+	monitor.offlineInterval = time.Millisecond
+	var probes atomic.Int32
+	monitor.probe = func(context.Context) error {
+		if probes.Add(1) <= networkOfflineThreshold {
+			return &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+		}
+		return nil
+	}
+	recovered := make(chan NetworkStatus, 1)
+	monitor.SetOnChange(func(status NetworkStatus) {
+		if status.Online {
+			recovered <- status
+		}
+	})
+	// 1回目の失敗ではオンラインのままなので、2回目は手動で確認してオフラインにする。
+	monitor.CheckNow(context.Background())
+	monitor.CheckNow(context.Background())
+	if monitor.Status().Online {
+		t.Fatal("expected offline after consecutive failures")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor.Start(ctx)
+	defer monitor.Stop()
+	select {
+	case <-recovered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the monitor to switch back online while offline")
+	}
+}
+
+func TestNetworkMonitorSkippedProbeClearsOffline(t *testing.T) {
+	t.Parallel()
+
+	monitor := NewNetworkMonitor(config.Config{}, &fakeCredentialStore{}, newTestLogger())
+	monitor.probe = func(context.Context) error { return &net.OpError{Op: "dial", Err: errors.New("connection refused")} }
+	for i := 0; i < networkOfflineThreshold; i++ {
+		monitor.CheckNow(context.Background())
+	}
+	if monitor.Status().Online {
+		t.Fatal("expected offline")
+	}
+	// 認証情報を消すなどして確認できなくなったら、オフラインのままにしない。
+	monitor.probe = func(context.Context) error { return errNetworkProbeSkipped }
+	if status := monitor.CheckNow(context.Background()); !status.Online {
+		t.Fatal("expected skipped probe to clear auto-offline")
+	}
+}
@@ -0,0 +1,17 @@
+// デスクトップ通知（Windows のトースト通知）の共通定義。
+package services
+
+import "log/slog"
+
+// NotificationService はデスクトップ通知を表示する。
+type NotificationService interface {
+	// Notify は title と message の通知を表示する。非Windowsではエラーを返す。
+	Notify(title string, message string) error
+	// Close は通知用のアイコン・ウィンドウを片付ける。
+	Close()
+}
+
+// NewNotificationService はプラットフォームに応じた通知サービスを生成する。
+func NewNotificationService(logger *slog.Logger) NotificationService {
+	return newNotificationService(logger)
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+// 非Windows向けのデスクトップ通知のスタブ実装。
+package services
+
+import (
+	"errors"
+	"log/slog"
+)
+
+type notificationServiceUnsupported struct{}
+
+func newNotificationService(logger *slog.Logger) NotificationService {
+	return &notificationServiceUnsupported{}
+}
+
+func (service *notificationServiceUnsupported) Notify(title string, message string) error {
+	return errors.New("notification is only supported on Windows")
+}
+
+func (service *notificationServiceUnsupported) Close() {}
@@ -0,0 +1,166 @@
+//go:build windows
+
+// Windows の通知領域アイコンのバルーン（Windows 10 以降はトースト通知として表示される）で通知を出す。
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"CloudLaunch_Go/internal/util"
+
+	"golang.org/x/sys/windows"
+)
+
+// notificationIconID は通知用アイコンの ID。ホットキーの通知アイコンとはウィンドウが別のため重なっても構わないが、区別しやすくする。
+const notificationIconID = 0x202
+
+// notificationStopTimeout は Close がメッセージループの終了を待つ上限。
+const notificationStopTimeout = 2 * time.Second
+
+type notificationServiceWindows struct {
+	logger    *slog.Logger
+	mu        sync.Mutex
+	threadID  uint32
+	hwnd      windows.Handle
+	stoppedCh chan struct{}
+}
+
+func newNotificationService(logger *slog.Logger) NotificationService {
+	return &notificationServiceWindows{logger: logger}
+}
+
+// Notify は初回呼び出し時に通知用のウィンドウとアイコンを作り、バルーン通知を表示する。
+func (service *notificationServiceWindows) Notify(title string, message string) error {
+	trimmed := strings.TrimSpace(message)
+	if trimmed == "" {
+		return errors.New("notification message is empty")
+	}
+	hwnd, err := service.ensureStarted()
+	if err != nil {
+		return err
+	}
+	data := notifyIconData{
+		Size:      uint32(unsafe.Sizeof(notifyIconData{})),
+		Wnd:       hwnd,
+		ID:        notificationIconID,
+		Flags:     nifInfo,
+		InfoFlags: niifInfo,
+	}
+	copyUTF16(data.InfoTitle[:len(data.InfoTitle)-1], util.FirstNonEmpty(strings.TrimSpace(title), "CloudLaunch"))
+	copyUTF16(data.Info[:len(data.Info)-1], trimmed)
+	if ok, _, callErr := procShellNotifyIconW.Call(nimModify, uintptr(unsafe.Pointer(&data))); ok == 0 {
+		return callErr
+	}
+	return nil
+}
+
+// Close は通知用のメッセージループを止め、アイコンを削除する。
+func (service *notificationServiceWindows) Close() {
+	service.mu.Lock()
+	threadID := service.threadID
+	stoppedCh := service.stoppedCh
+	service.mu.Unlock()
+	if threadID == 0 || stoppedCh == nil {
+		return
+	}
+	procPostThreadMessage.Call(uintptr(threadID), wmQuit, 0, 0)
+	select {
+	case <-stoppedCh:
+	case <-time.After(notificationStopTimeout):
+		if service.logger != nil {
+			service.logger.Warn("通知の停止を待機できませんでした")
+		}
+	}
+}
+
+func (service *notificationServiceWindows) ensureStarted() (windows.Handle, error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.hwnd != 0 {
+		return service.hwnd, nil
+	}
+	readyCh := make(chan error, 1)
+	service.stoppedCh = make(chan struct{})
+	go service.run(readyCh)
+	if err := <-readyCh; err != nil {
+		return 0, err
+	}
+	return service.hwnd, nil
+}
+
+// run は通知用のウィンドウを作ってメッセージループを回す。ウィンドウは作成したスレッドに結び付くため OS スレッドを固定する。
+// service.mu は ensureStarted が保持したまま readyCh を待つため、準備完了までの書き込みはロックを取らない。
+func (service *notificationServiceWindows) run(readyCh chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	stoppedCh := service.stoppedCh
+	defer close(stoppedCh)
+
+	if err := ensureNotifyClass(); err != nil {
+		readyCh <- err
+		return
+	}
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	hwnd, _, err := procCreateWindowExW.Call(
+		0,
+		uintptr(unsafe.Pointer(notifyClassName)),
+		uintptr(unsafe.Pointer(notifyClassName)),
+		0, 0, 0, 0, 0,
+		uintptr(hwndMessage),
+		0,
+		instance,
+		0,
+	)
+	if hwnd == 0 {
+		readyCh <- err
+		return
+	}
+	icon, _, _ := procLoadIconW.Call(0, uintptr(idiApplication))
+	data := notifyIconData{
+		Size:            uint32(unsafe.Sizeof(notifyIconData{})),
+		Wnd:             windows.Handle(hwnd),
+		ID:              notificationIconID,
+		Flags:           nifMessage | nifIcon | nifTip,
+		CallbackMessage: notifyCallbackMessage,
+		Icon:            windows.Handle(icon),
+	}
+	copyUTF16(data.Tip[:len(data.Tip)-1], "CloudLaunch")
+	if ok, _, addErr := procShellNotifyIconW.Call(nimAdd, uintptr(unsafe.Pointer(&data))); ok == 0 {
+		procDestroyWindow.Call(hwnd)
+		readyCh <- addErr
+		return
+	}
+	threadID, _, _ := procGetThreadID.Call()
+	service.threadID = uint32(threadID)
+	service.hwnd = windows.Handle(hwnd)
+	readyCh <- nil
+
+	defer func() {
+		procShellNotifyIconW.Call(nimDelete, uintptr(unsafe.Pointer(&notifyIconData{
+			Size: uint32(unsafe.Sizeof(notifyIconData{})),
+			Wnd:  windows.Handle(hwnd),
+			ID:   notificationIconID,
+		})))
+		procDestroyWindow.Call(hwnd)
+		service.mu.Lock()
+		service.threadID = 0
+		service.hwnd = 0
+		service.mu.Unlock()
+	}()
+
+	var msg hotkeyMsg
+	for {
+		ret, _, _ := procGetMessage.Call(uintptr(unsafe.Pointer(&msg)), 0, 0, 0)
+		if int32(ret) <= 0 {
+			return
+		}
+		procTranslateMessage.Call(uintptr(unsafe.Pointer(&msg)))
+		procDispatchMessage.Call(uintptr(unsafe.Pointer(&msg)))
+	}
+}
@@ -0,0 +1,115 @@
+// 初回セットアップ（オンボーディング）の完了状況の永続化を提供する。
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// onboardingSettingKey は Settings テーブル上で手順ごとの完了時刻を保存するキー。
+const onboardingSettingKey = "onboarding"
+
+// OnboardingService は初回セットアップの手順ごとの完了時刻を記録する。
+// 一度完了した手順は、後で条件を満たさなくなっても（ゲームを全て削除したなど）完了のまま扱う。
+type OnboardingService struct {
+	repository SettingsRepository
+	logger     *slog.Logger
+	now        func() time.Time
+	// mu は CompleteSteps の読み込み→追記→保存を直列化する（同時に完了した手順を上書きで失わないため）。
+	mu sync.Mutex
+}
+
+// NewOnboardingService は OnboardingService を生成する。
+func NewOnboardingService(repository SettingsRepository, logger *slog.Logger) *OnboardingService {
+	return &OnboardingService{repository: repository, logger: logger, now: time.Now}
+}
+
+// State は保存済みの完了状況を返す。
+func (service *OnboardingService) State(ctx context.Context) (domain.OnboardingState, error) {
+	completed, err := service.load(ctx)
+	if err != nil {
+		return domain.OnboardingState{}, err
+	}
+	return buildOnboardingState(completed), nil
+}
+
+// CompleteSteps は手順を完了として記録し、記録後の完了状況を返す。完了済みの手順は最初の完了時刻を残す。
+func (service *OnboardingService) CompleteSteps(ctx context.Context, steps ...string) (domain.OnboardingState, error) {
+	normalized := make([]string, 0, len(steps))
+	for _, step := range steps {
+		trimmed := strings.TrimSpace(step)
+		if !slices.Contains(domain.OnboardingSteps, trimmed) {
+			service.logger.Warn("初回セットアップの手順が不正です", "step", step)
+			return domain.OnboardingState{}, newServiceError("初回セットアップの手順が不正です", "stepはcredentials|firstGame|firstSyncのいずれかです")
+		}
+		normalized = append(normalized, trimmed)
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	completed, err := service.load(ctx)
+	if err != nil {
+		return domain.OnboardingState{}, err
+	}
+	changed := false
+	for _, step := range normalized {
+		if _, ok := completed[step]; ok {
+			continue
+		}
+		completed[step] = service.now()
+		changed = true
+	}
+	if changed {
+		payload, err := json.Marshal(completed)
+		if err != nil {
+			return domain.OnboardingState{}, newServiceError("初回セットアップの状態の保存に失敗しました", err.Error())
+		}
+		if err := service.repository.UpsertSetting(ctx, onboardingSettingKey, string(payload)); err != nil {
+			service.logger.Error("初回セットアップの状態の保存に失敗", "error", err)
+			return domain.OnboardingState{}, newServiceError("初回セットアップの状態の保存に失敗しました", err.Error())
+		}
+	}
+	return buildOnboardingState(completed), nil
+}
+
+func (service *OnboardingService) load(ctx context.Context) (map[string]time.Time, error) {
+	raw, err := service.repository.GetSetting(ctx, onboardingSettingKey)
+	if err != nil {
+		service.logger.Error("初回セットアップの状態の取得に失敗", "error", err)
+		return nil, newServiceError("初回セットアップの状態の取得に失敗しました", err.Error())
+	}
+	completed := make(map[string]time.Time)
+	if strings.TrimSpace(raw) == "" {
+		return completed, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &completed); err != nil {
+		// 壊れていても案内をやり直すだけなので、未完了として扱う。
+		service.logger.Warn("初回セットアップの状態の解析に失敗（未完了として扱う）", "error", err)
+		return make(map[string]time.Time), nil
+	}
+	return completed, nil
+}
+
+func buildOnboardingState(completed map[string]time.Time) domain.OnboardingState {
+	state := domain.OnboardingState{Steps: make([]domain.OnboardingStep, 0, len(domain.OnboardingSteps)), Completed: true}
+	for _, id := range domain.OnboardingSteps {
+		step := domain.OnboardingStep{ID: id}
+		if at, ok := completed[id]; ok {
+			step.Completed = true
+			step.CompletedAt = &at
+		} else {
+			state.Completed = false
+			if state.NextStep == "" {
+				state.NextStep = id
+			}
+		}
+		state.Steps = append(state.Steps, step)
+	}
+	return state
+}
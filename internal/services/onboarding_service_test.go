@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestOnboardingServiceTracksStepsInOrder(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeSettingsRepository{}
+	service := NewOnboardingService(repo, newTestLogger())
+	first := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return first }
+	ctx := context.Background()
+
+	state, err := service.State(ctx)
+	if err != nil {
+		t.Fatalf("State: %v", err)
+	}
+	if state.Completed || state.NextStep != domain.OnboardingStepCredentials || len(state.Steps) != len(domain.OnboardingSteps) {
+		t.Fatalf("unexpected initial state: %+v", state)
+	}
+
+	state, err = service.CompleteSteps(ctx, domain.OnboardingStepCredentials, " firstGame ")
+	if err != nil {
+		t.Fatalf("CompleteSteps: %v", err)
+	}
+	if state.NextStep != domain.OnboardingStepFirstSync || !state.Steps[1].Completed {
+		t.Fatalf("unexpected state after two steps: %+v", state)
+	}
+
+	// 完了済みの手順は最初の完了時刻を残す。
+	service.now = func() time.Time { return first.Add(time.Hour) }
+	state, err = service.CompleteSteps(ctx, domain.OnboardingStepCredentials, domain.OnboardingStepFirstSync)
+	if err != nil {
+		t.Fatalf("CompleteSteps: %v", err)
+	}
+	if !state.Completed || state.NextStep != "" || !state.Steps[0].CompletedAt.Equal(first) {
+		t.Fatalf("unexpected final state: %+v", state)
+	}
+
+	reloaded, err := NewOnboardingService(repo, newTestLogger()).State(ctx)
+	if err != nil || !reloaded.Completed {
+		t.Fatalf("expected persisted state, got %+v err=%v", reloaded, err)
+	}
+}
+
+func TestOnboardingServiceRejectsUnknownStep(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeSettingsRepository{}
+	service := NewOnboardingService(repo, newTestLogger())
+	if _, err := service.CompleteSteps(context.Background(), "tutorial"); err == nil {
+		t.Fatal("expected error for unknown step")
+	}
+	if _, ok := repo.values[onboardingSettingKey]; ok {
+		t.Fatal("expected nothing to be saved")
+	}
+}
+
+func TestOnboardingServiceTreatsBrokenValueAsNotStarted(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeSettingsRepository{values: map[string]string{onboardingSettingKey: "{broken"}}
+	state, err := NewOnboardingService(repo, newTestLogger()).State(context.Background())
+	if err != nil || state.Completed || state.NextStep != domain.OnboardingStepCredentials {
+		t.Fatalf("unexpected state: %+v err=%v", state, err)
+	}
+}
+
+func TestOnboardingServiceConcurrentCompletionsKeepEveryStep(t *testing.T) {
+	t.Parallel()
+
+	service := NewOnboardingService(&fakeSettingsRepository{}, newTestLogger())
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for range 10 {
+		for _, step := range domain.OnboardingSteps {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := service.CompleteSteps(ctx, step); err != nil {
+					t.Errorf("CompleteSteps(%s) failed: %v", step, err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	state, err := service.State(ctx)
+	if err != nil || !state.Completed {
+		t.Fatalf("every step should stay completed: %+v, %v", state, err)
+	}
+}
@@ -0,0 +1,213 @@
+// 実行中の長時間処理の一覧・進捗・キャンセルを管理するレジストリを提供する。
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// 長時間処理の種類。
+const (
+	OperationPush          = "push"
+	OperationPull          = "pull"
+	OperationPullAll       = "pullAll"
+	OperationRestoreSave   = "restoreSave"
+	OperationScreenshots   = "screenshots"
+	OperationCloudCheck    = "cloudCheck"
+	OperationCloudRepair   = "cloudRepair"
+	OperationMetadataMatch = "metadataMatch"
+	// OperationScreenshotSeries はタイマー撮影・連写。
+	OperationScreenshotSeries = "screenshotSeries"
+	// OperationAutoSync は自動同期キューからの Push。
+	OperationAutoSync = "autoSync"
+	// OperationResolveConflict はコンフリクトの解決・統合（判断待ちの一括解決を含む）。
+	OperationResolveConflict = "resolveConflict"
+	// OperationDownloadFile はクラウドのファイル1件のダウンロード。
+	OperationDownloadFile = "downloadFile"
+	// OperationMemoSync はメモのクラウド同期。
+	OperationMemoSync = "memoSync"
+	// OperationSyncQueueReplay はオフライン中の送信待ちの再送。
+	OperationSyncQueueReplay = "syncQueueReplay"
+)
+
+// OperationRegistry は実行中の長時間処理を ID で管理する。
+// 全体のアクティビティ表示向けに一覧を返し、ID を指定したキャンセルを受け付ける。
+// nil のレジストリでも Begin は使え、その場合は登録せずに処理だけを行う。
+type OperationRegistry struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	seq        uint64
+	operations map[string]*Operation
+	onChange   func([]domain.ActiveOperation)
+}
+
+// NewOperationRegistry は OperationRegistry を生成する。
+func NewOperationRegistry() *OperationRegistry {
+	return &OperationRegistry{
+		now:        time.Now,
+		operations: make(map[string]*Operation),
+	}
+}
+
+// SetOnChange は処理の開始・終了・キャンセル要求時の通知先を設定する（進捗の更新では呼ばない）。
+func (registry *OperationRegistry) SetOnChange(fn func([]domain.ActiveOperation)) {
+	if registry == nil {
+		return
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.onChange = fn
+}
+
+// Begin は処理を登録し、キャンセル可能な子コンテキストと進捗報告用の Operation を返す。
+// 呼び出し側は処理の終了時に必ず Operation.Finish を呼ぶ。
+func (registry *OperationRegistry) Begin(ctx context.Context, kind, gameID string) (context.Context, *Operation) {
+	opCtx, cancel := context.WithCancel(ctx)
+	if registry == nil {
+		return opCtx, &Operation{cancel: cancel, state: domain.ActiveOperation{Kind: kind, GameID: gameID}}
+	}
+	registry.mu.Lock()
+	registry.seq++
+	now := registry.now()
+	op := &Operation{
+		registry:   registry,
+		cancel:     cancel,
+		now:        registry.now,
+		throughput: newThroughputEstimator(),
+		state: domain.ActiveOperation{
+			ID:        fmt.Sprintf("%s-%d", kind, registry.seq),
+			Kind:      kind,
+			GameID:    gameID,
+			StartedAt: now,
+		},
+	}
+	registry.operations[op.state.ID] = op
+	registry.mu.Unlock()
+	registry.notify()
+	return opCtx, op
+}
+
+// List は実行中の処理を開始順に返す。
+func (registry *OperationRegistry) List() []domain.ActiveOperation {
+	if registry == nil {
+		return []domain.ActiveOperation{}
+	}
+	registry.mu.Lock()
+	operations := make([]*Operation, 0, len(registry.operations))
+	for _, op := range registry.operations {
+		operations = append(operations, op)
+	}
+	registry.mu.Unlock()
+
+	list := make([]domain.ActiveOperation, 0, len(operations))
+	for _, op := range operations {
+		list = append(list, op.Snapshot())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartedAt.Equal(list[j].StartedAt) {
+			return list[i].StartedAt.Before(list[j].StartedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Cancel は指定 ID の処理のコンテキストをキャンセルする。該当する処理が無ければ false を返す。
+// 処理は中断できる地点でコンテキストのエラーを返して終了し、その時点で一覧から外れる。
+func (registry *OperationRegistry) Cancel(id string) bool {
+	if registry == nil {
+		return false
+	}
+	registry.mu.Lock()
+	op, ok := registry.operations[id]
+	registry.mu.Unlock()
+	if !ok {
+		return false
+	}
+	op.mu.Lock()
+	op.state.Canceling = true
+	op.mu.Unlock()
+	op.cancel()
+	registry.notify()
+	return true
+}
+
+func (registry *OperationRegistry) remove(id string) {
+	registry.mu.Lock()
+	_, ok := registry.operations[id]
+	delete(registry.operations, id)
+	registry.mu.Unlock()
+	if ok {
+		registry.notify()
+	}
+}
+
+func (registry *OperationRegistry) notify() {
+	registry.mu.Lock()
+	onChange := registry.onChange
+	registry.mu.Unlock()
+	if onChange != nil {
+		onChange(registry.List())
+	}
+}
+
+// Operation は登録済みの処理1件の進捗報告とキャンセルのハンドル。
+type Operation struct {
+	registry   *OperationRegistry
+	cancel     context.CancelFunc
+	now        func() time.Time
+	mu         sync.Mutex
+	state      domain.ActiveOperation
+	throughput *throughputEstimator
+}
+
+// ID はキャンセル用のハンドル（CancelOperation に渡す ID）を返す。
+func (op *Operation) ID() string {
+	return op.state.ID
+}
+
+// Snapshot は現在の進捗を返す。
+func (op *Operation) Snapshot() domain.ActiveOperation {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.state
+}
+
+// ReportCount は件数単位の進捗を記録し、直近の処理速度から残り時間を見積もった状態を返す。
+func (op *Operation) ReportCount(current, total int) domain.ActiveOperation {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.state.Current = current
+	op.state.Total = total
+	if op.throughput != nil {
+		op.throughput.observe(op.now(), float64(current))
+		op.state.EtaSeconds = op.throughput.remaining(float64(total - current))
+	}
+	return op.state
+}
+
+// ReportTransfer はセーブ転送の進捗（transferTracker が見積もった残り時間を含む）を記録する。
+func (op *Operation) ReportTransfer(progress domain.TransferProgress) domain.ActiveOperation {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.state.Current = progress.FilesDone
+	op.state.Total = progress.FilesTotal
+	op.state.CurrentFile = progress.CurrentFile
+	op.state.BytesDone = progress.BytesDone
+	op.state.BytesTotal = progress.BytesTotal
+	op.state.EtaSeconds = progress.EtaSeconds
+	return op.state
+}
+
+// Finish は処理の終了を記録して一覧から外し、子コンテキストを解放する。複数回呼んでもよい。
+func (op *Operation) Finish() {
+	op.cancel()
+	if op.registry != nil {
+		op.registry.remove(op.state.ID)
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestOperationRegistryListsAndCancelsOperations(t *testing.T) {
+	t.Parallel()
+
+	registry := NewOperationRegistry()
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return clock }
+	var changes [][]domain.ActiveOperation
+	registry.SetOnChange(func(list []domain.ActiveOperation) { changes = append(changes, list) })
+
+	pushCtx, push := registry.Begin(context.Background(), OperationPush, "game-1")
+	clock = clock.Add(time.Second)
+	_, pullAll := registry.Begin(context.Background(), OperationPullAll, "")
+
+	list := registry.List()
+	if len(list) != 2 || list[0].ID != push.ID() || list[1].ID != pullAll.ID() {
+		t.Fatalf("unexpected operations: %+v", list)
+	}
+	if list[0].Kind != OperationPush || list[0].GameID != "game-1" {
+		t.Fatalf("unexpected push operation: %+v", list[0])
+	}
+
+	if !registry.Cancel(push.ID()) {
+		t.Fatal("expected cancel to find the operation")
+	}
+	if !errors.Is(pushCtx.Err(), context.Canceled) {
+		t.Fatalf("expected canceled context, got %v", pushCtx.Err())
+	}
+	if !registry.List()[0].Canceling {
+		t.Fatal("expected canceling flag")
+	}
+
+	push.Finish()
+	push.Finish()
+	pullAll.Finish()
+	if len(registry.List()) != 0 {
+		t.Fatalf("expected no operations, got %+v", registry.List())
+	}
+	if registry.Cancel(push.ID()) {
+		t.Fatal("finished operation should not be cancelable")
+	}
+	// 開始2回・キャンセル1回・終了2回。
+	if len(changes) != 5 {
+		t.Fatalf("expected 5 change notifications, got %d", len(changes))
+	}
+}
+
+func TestOperationReportCountEstimatesRemaining(t *testing.T) {
+	t.Parallel()
+
+	registry := NewOperationRegistry()
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return clock }
+	_, op := registry.Begin(context.Background(), OperationScreenshots, "game-1")
+	defer op.Finish()
+
+	if state := op.ReportCount(0, 10); state.EtaSeconds != 0 {
+		t.Fatalf("expected no estimate before progress, got %+v", state)
+	}
+	clock = clock.Add(4 * time.Second)
+	state := op.ReportCount(2, 10)
+	if state.Current != 2 || state.Total != 10 || state.EtaSeconds != 16 {
+		t.Fatalf("unexpected progress: %+v", state)
+	}
+	if got := registry.List()[0]; got.EtaSeconds != 16 {
+		t.Fatalf("expected listed operation to carry the estimate, got %+v", got)
+	}
+}
+
+func TestNilOperationRegistryStillProvidesCancelableContext(t *testing.T) {
+	t.Parallel()
+
+	var registry *OperationRegistry
+	ctx, op := registry.Begin(context.Background(), OperationPull, "game-1")
+	op.ReportCount(1, 2)
+	op.ReportTransfer(domain.TransferProgress{FilesDone: 1, FilesTotal: 2})
+	op.Finish()
+	if ctx.Err() == nil {
+		t.Fatal("expected context to be released on finish")
+	}
+	if len(registry.List()) != 0 || registry.Cancel("x") {
+		t.Fatal("nil registry should be empty")
+	}
+}
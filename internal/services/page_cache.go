@@ -0,0 +1,129 @@
+// 外部サイトのページ本文を URL ごとにディスクへ保存する HTTP キャッシュを提供する。
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pageCacheDirName は AppDataDir 配下のページキャッシュの保存先。
+const pageCacheDirName = "cache/pages"
+
+// cachedPage はキャッシュしたページ1件。ETag / LastModified は再検証（条件付き GET）に使う。
+type cachedPage struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	Body         string    `json:"body"`
+}
+
+// pageCache は URL の SHA-256 をファイル名にしてページを保存する。
+// TTL 内のページは通信せずに返し、TTL を過ぎたページは ETag / Last-Modified で再検証する。
+// TTL が 0 以下ならキャッシュを使わない。
+type pageCache struct {
+	dir string
+	ttl atomic.Int64
+	now func() time.Time
+	// mu は同じディレクトリへの書き込みと削除の競合を防ぐ。
+	mu sync.Mutex
+}
+
+func newPageCache(dir string, ttl time.Duration) *pageCache {
+	cache := &pageCache{dir: dir, now: time.Now}
+	cache.SetTTL(ttl)
+	return cache
+}
+
+// SetTTL はキャッシュの有効期間を更新する。保存済みのページにも新しい期間が適用される。
+func (cache *pageCache) SetTTL(ttl time.Duration) {
+	cache.ttl.Store(int64(ttl))
+}
+
+func (cache *pageCache) enabled() bool {
+	return cache != nil && cache.ttl.Load() > 0
+}
+
+func (cache *pageCache) path(pageURL string) string {
+	sum := sha256.Sum256([]byte(pageURL))
+	return filepath.Join(cache.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// lookup は保存済みのページを返す。無い・読めない場合は false を返す。
+func (cache *pageCache) lookup(pageURL string) (cachedPage, bool) {
+	if !cache.enabled() {
+		return cachedPage{}, false
+	}
+	raw, error := os.ReadFile(cache.path(pageURL))
+	if error != nil {
+		return cachedPage{}, false
+	}
+	var page cachedPage
+	if error := json.Unmarshal(raw, &page); error != nil || page.URL != pageURL {
+		return cachedPage{}, false
+	}
+	return page, true
+}
+
+// fresh はページが TTL 内で、再検証せずに使えるかを返す。
+func (cache *pageCache) fresh(page cachedPage) bool {
+	return cache.now().Sub(page.FetchedAt) < time.Duration(cache.ttl.Load())
+}
+
+// store はページを保存する。書きかけのファイルを読まないよう、一時ファイルに書いてから置き換える。
+func (cache *pageCache) store(page cachedPage) error {
+	if !cache.enabled() {
+		return nil
+	}
+	raw, error := json.Marshal(page)
+	if error != nil {
+		return error
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if error := os.MkdirAll(cache.dir, 0o755); error != nil {
+		return error
+	}
+	target := cache.path(page.URL)
+	temp := target + ".tmp"
+	if error := os.WriteFile(temp, raw, 0o644); error != nil {
+		return error
+	}
+	if error := os.Rename(temp, target); error != nil {
+		_ = os.Remove(temp)
+		return error
+	}
+	return nil
+}
+
+// Purge は保存済みのページをすべて削除し、削除した件数を返す。
+func (cache *pageCache) Purge() (int, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entries, error := os.ReadDir(cache.dir)
+	if error != nil {
+		if errors.Is(error, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, error
+	}
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if error := os.Remove(filepath.Join(cache.dir, entry.Name())); error != nil && !errors.Is(error, os.ErrNotExist) {
+			return removed, error
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+)
+
+func TestFetchCachedHTMLRevalidatesWithETag(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	var conditional atomic.Value
+	conditional.Store("")
+	failing := atomic.Bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(writer, "blocked", http.StatusForbidden)
+			return
+		}
+		if match := request.Header.Get("If-None-Match"); match != "" {
+			conditional.Store(match)
+			writer.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writer.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(writer, "<html>page</html>")
+	}))
+	t.Cleanup(server.Close)
+
+	scraper := newMetadataScraper(config.Config{AppDataDir: t.TempDir()}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	cache := newPageCache(t.TempDir(), time.Hour)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	limiter := newRequestLimiter(0)
+	pageURL := server.URL + "/kensaku.php?word=test"
+
+	for range 2 {
+		body, err := scraper.fetchCachedHTML(context.Background(), pageURL, "", cache, limiter)
+		if err != nil || body != "<html>page</html>" {
+			t.Fatalf("unexpected result: %q %v", body, err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("expected fresh cache to skip request, got %d requests", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	body, err := scraper.fetchCachedHTML(context.Background(), pageURL, "", cache, limiter)
+	if err != nil || body != "<html>page</html>" || conditional.Load() != `"v1"` {
+		t.Fatalf("expected revalidation with etag, got %q %v (If-None-Match=%v)", body, err, conditional.Load())
+	}
+
+	now = now.Add(2 * time.Hour)
+	failing.Store(true)
+	if body, err := scraper.fetchCachedHTML(context.Background(), pageURL, "", cache, limiter); err != nil || body != "<html>page</html>" {
+		t.Fatalf("expected stale cache on failure, got %q %v", body, err)
+	}
+
+	removed, err := cache.Purge()
+	if err != nil || removed != 1 {
+		t.Fatalf("expected 1 purged page, got %d %v", removed, err)
+	}
+	_, err = scraper.fetchCachedHTML(context.Background(), pageURL, "", cache, limiter)
+	var fetchErr FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected fetch error after purge, got %v", err)
+	}
+}
+
+func TestPageCacheDisabledWithZeroTTL(t *testing.T) {
+	t.Parallel()
+
+	cache := newPageCache(t.TempDir(), 0)
+	if err := cache.store(cachedPage{URL: "https://example.com/", Body: "x", FetchedAt: time.Now()}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := cache.lookup("https://example.com/"); ok {
+		t.Fatal("expected disabled cache not to return pages")
+	}
+}
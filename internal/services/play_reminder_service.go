@@ -0,0 +1,224 @@
+// プレイ時間のリマインダー（「3時間プレイしています」の通知）を提供する。
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// playReminderSettingKey は Settings テーブル上でゲームごとのリマインダー間隔（分）を保存するキー。
+	playReminderSettingKey = "play_reminders"
+	// MaxPlayReminderMinutes はリマインダー間隔の上限（24時間）。
+	MaxPlayReminderMinutes = 24 * 60
+)
+
+// ValidatePlayReminderMinutes はリマインダー間隔の分数が 0（無効）から MaxPlayReminderMinutes の範囲かを検証する。
+func ValidatePlayReminderMinutes(minutes int) error {
+	if minutes < 0 || minutes > MaxPlayReminderMinutes {
+		return fmt.Errorf("playReminderMinutes must be 0-%d", MaxPlayReminderMinutes)
+	}
+	return nil
+}
+
+// playReminderProgress は計測中のセッションで通知済みの回数を表す。interval は notified を数えたときの間隔（秒）。
+type playReminderProgress struct {
+	notified int64
+	playTime int64
+	interval int64
+}
+
+// PlayReminderService はプロセス監視の累計プレイ時間を見て、設定した間隔ごとに通知を出す。
+// 間隔はゲームごとの設定（0 ならそのゲームは通知しない）を優先し、無ければ全体の設定を使う。
+type PlayReminderService struct {
+	repository SettingsRepository
+	notifier   NotificationService
+	logger     *slog.Logger
+
+	mu             sync.Mutex
+	defaultMinutes int
+	overrides      map[string]int
+	overridesReady bool
+	progress       map[string]playReminderProgress
+}
+
+// NewPlayReminderService は PlayReminderService を生成する。defaultMinutes は全体のリマインダー間隔（0 で無効）。
+func NewPlayReminderService(repository SettingsRepository, notifier NotificationService, defaultMinutes int, logger *slog.Logger) *PlayReminderService {
+	return &PlayReminderService{
+		repository:     repository,
+		notifier:       notifier,
+		logger:         logger,
+		defaultMinutes: defaultMinutes,
+		progress:       make(map[string]playReminderProgress),
+	}
+}
+
+// SetDefaultMinutes は全体のリマインダー間隔を更新する。計測中のセッションは次の区切りから新しい間隔で通知する。
+func (service *PlayReminderService) SetDefaultMinutes(minutes int) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.defaultMinutes = minutes
+}
+
+// ListGameReminders はゲームごとのリマインダー間隔（分、0 は通知しない）を返す。
+func (service *PlayReminderService) ListGameReminders(ctx context.Context) (map[string]int, error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	overrides, err := service.loadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	copied := make(map[string]int, len(overrides))
+	for gameID, minutes := range overrides {
+		copied[gameID] = minutes
+	}
+	return copied, nil
+}
+
+// SetGameReminder はゲームのリマインダー間隔を設定する。minutes が nil なら全体の設定に戻し、0 ならそのゲームは通知しない。
+func (service *PlayReminderService) SetGameReminder(ctx context.Context, gameID string, minutes *int) error {
+	gameID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return newServiceError("ゲームIDが不正です", detail)
+	}
+	if minutes != nil {
+		if err := ValidatePlayReminderMinutes(*minutes); err != nil {
+			service.logger.Warn("リマインダー間隔が不正です", "gameId", gameID, "value", *minutes)
+			return newServiceError("リマインダー間隔が不正です", err.Error())
+		}
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	overrides, err := service.loadOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	updated := make(map[string]int, len(overrides)+1)
+	for id, value := range overrides {
+		updated[id] = value
+	}
+	if minutes == nil {
+		delete(updated, gameID)
+	} else {
+		updated[gameID] = *minutes
+	}
+	payload, err := json.Marshal(updated)
+	if err != nil {
+		return newServiceError("リマインダー設定の保存に失敗しました", err.Error())
+	}
+	if err := service.repository.UpsertSetting(ctx, playReminderSettingKey, string(payload)); err != nil {
+		service.logger.Error("リマインダー設定の保存に失敗", "gameId", gameID, "error", err)
+		return newServiceError("リマインダー設定の保存に失敗しました", err.Error())
+	}
+	service.overrides = updated
+	return nil
+}
+
+// Check は監視中のゲームの累計プレイ時間を見て、間隔の区切りを超えたゲームに通知を出す。
+// プロセス監視のスキャンごとに呼ばれる想定で、同じ区切りでは1回だけ通知する。
+// プレイ時間が前回より減ったとき（新しいセッション）は数え直す。セッションの途中で間隔が変わったときは、
+// 新しい間隔で既に過ぎた区切りは通知済みとし、次の区切りから通知する。
+func (service *PlayReminderService) Check(ctx context.Context, statuses []domain.MonitoringGameStatus) {
+	type reminder struct {
+		title   string
+		elapsed int64
+	}
+	reminders := make([]reminder, 0)
+
+	service.mu.Lock()
+	overrides, err := service.loadOverrides(ctx)
+	if err != nil {
+		// 読めない間は全体の設定だけで通知する。
+		overrides = nil
+	}
+	active := make(map[string]struct{}, len(statuses))
+	for _, status := range statuses {
+		active[status.GameID] = struct{}{}
+		minutes := service.defaultMinutes
+		if value, ok := overrides[status.GameID]; ok {
+			minutes = value
+		}
+		interval := int64(minutes * 60)
+		progress, tracked := service.progress[status.GameID]
+		if status.PlayTime < progress.playTime {
+			progress, tracked = playReminderProgress{}, false
+		}
+		if tracked && progress.interval != interval && interval > 0 {
+			progress.notified = status.PlayTime / interval
+		}
+		progress.playTime = status.PlayTime
+		progress.interval = interval
+		if interval > 0 && status.IsPlaying {
+			reached := status.PlayTime / interval
+			if reached > progress.notified {
+				progress.notified = reached
+				reminders = append(reminders, reminder{title: status.GameTitle, elapsed: reached * interval})
+			}
+		}
+		service.progress[status.GameID] = progress
+	}
+	for gameID := range service.progress {
+		if _, ok := active[gameID]; !ok {
+			delete(service.progress, gameID)
+		}
+	}
+	service.mu.Unlock()
+
+	for _, item := range reminders {
+		message := fmt.Sprintf("「%s」を%sプレイしています。休憩しませんか？", item.title, formatReminderDuration(item.elapsed))
+		if err := service.notifier.Notify("プレイ時間のお知らせ", message); err != nil {
+			service.logger.Warn("リマインダー通知の表示に失敗", "title", item.title, "error", err)
+			continue
+		}
+		service.logger.Info("プレイ時間のリマインダーを通知", "title", item.title, "elapsedSeconds", item.elapsed)
+	}
+}
+
+// loadOverrides はゲームごとの設定を読み込む（初回のみ DB から読み、以降はキャッシュを返す）。
+// service.mu を保持した状態で呼ぶ。
+func (service *PlayReminderService) loadOverrides(ctx context.Context) (map[string]int, error) {
+	if service.overridesReady {
+		return service.overrides, nil
+	}
+	raw, err := service.repository.GetSetting(ctx, playReminderSettingKey)
+	if err != nil {
+		service.logger.Error("リマインダー設定の取得に失敗", "error", err)
+		return nil, newServiceError("リマインダー設定の取得に失敗しました", err.Error())
+	}
+	overrides := make(map[string]int)
+	if strings.TrimSpace(raw) != "" {
+		if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+			// 壊れていても全体の設定で通知できるため、ゲームごとの設定は無いものとして扱う。
+			service.logger.Warn("リマインダー設定の解析に失敗（ゲームごとの設定を無視）", "error", err)
+			overrides = make(map[string]int)
+		}
+	}
+	for gameID, minutes := range overrides {
+		if ValidatePlayReminderMinutes(minutes) != nil {
+			delete(overrides, gameID)
+		}
+	}
+	service.overrides = overrides
+	service.overridesReady = true
+	return overrides, nil
+}
+
+// formatReminderDuration は秒数を「3時間」「1時間30分」「45分」の形にする。
+func formatReminderDuration(seconds int64) string {
+	hours := seconds / 3600
+	minutes := (seconds % 3600) / 60
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%d時間%d分", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%d時間", hours)
+	default:
+		return fmt.Sprintf("%d分", minutes)
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+type recordingNotifier struct {
+	messages []string
+}
+
+func (notifier *recordingNotifier) Notify(title string, message string) error {
+	notifier.messages = append(notifier.messages, message)
+	return nil
+}
+
+func (notifier *recordingNotifier) Close() {}
+
+func playingStatus(gameID string, playTime int64) domain.MonitoringGameStatus {
+	return domain.MonitoringGameStatus{GameID: gameID, GameTitle: "Game " + gameID, IsPlaying: true, PlayTime: playTime}
+}
+
+func TestPlayReminderServiceNotifiesOncePerInterval(t *testing.T) {
+	t.Parallel()
+
+	notifier := &recordingNotifier{}
+	service := NewPlayReminderService(&fakeSettingsRepository{}, notifier, 60, newTestLogger())
+	ctx := context.Background()
+
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 59*60)})
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 60*60)})
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 61*60)})
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 3*60*60+10)})
+	if len(notifier.messages) != 2 {
+		t.Fatalf("expected 2 reminders, got %v", notifier.messages)
+	}
+	if !strings.Contains(notifier.messages[0], "1時間") || !strings.Contains(notifier.messages[1], "3時間") {
+		t.Fatalf("unexpected messages: %v", notifier.messages)
+	}
+
+	// 新しいセッション（プレイ時間が減った）では数え直す。
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 10)})
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 60*60)})
+	if len(notifier.messages) != 3 {
+		t.Fatalf("new session should be counted again: %v", notifier.messages)
+	}
+}
+
+func TestPlayReminderServiceChangingIntervalMidSessionWaitsForNextBoundary(t *testing.T) {
+	t.Parallel()
+
+	notifier := &recordingNotifier{}
+	service := NewPlayReminderService(&fakeSettingsRepository{}, notifier, 60, newTestLogger())
+	ctx := context.Background()
+
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 50*60)})
+	service.SetDefaultMinutes(15)
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 51*60)})
+	if len(notifier.messages) != 0 {
+		t.Fatalf("boundaries already passed should not notify: %v", notifier.messages)
+	}
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 60*60)})
+	if len(notifier.messages) != 1 || !strings.Contains(notifier.messages[0], "1時間") {
+		t.Fatalf("expected the next 15-minute boundary, got %v", notifier.messages)
+	}
+
+	short := 10
+	if err := service.SetGameReminder(ctx, "a", &short); err != nil {
+		t.Fatalf("SetGameReminder failed: %v", err)
+	}
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 65*60)})
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("a", 70*60)})
+	if len(notifier.messages) != 2 || !strings.Contains(notifier.messages[1], "1時間10分") {
+		t.Fatalf("expected only the 70-minute reminder after the override, got %v", notifier.messages)
+	}
+}
+
+func TestPlayReminderServiceAppliesGameOverrides(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeSettingsRepository{}
+	notifier := &recordingNotifier{}
+	service := NewPlayReminderService(repository, notifier, 60, newTestLogger())
+	ctx := context.Background()
+	disabled, short := 0, 30
+	if err := service.SetGameReminder(ctx, "quiet", &disabled); err != nil {
+		t.Fatalf("SetGameReminder: %v", err)
+	}
+	if err := service.SetGameReminder(ctx, "short", &short); err != nil {
+		t.Fatalf("SetGameReminder: %v", err)
+	}
+
+	service.Check(ctx, []domain.MonitoringGameStatus{playingStatus("quiet", 2*60*60), playingStatus("short", 30*60)})
+	if len(notifier.messages) != 1 || !strings.Contains(notifier.messages[0], "Game short") {
+		t.Fatalf("unexpected reminders: %v", notifier.messages)
+	}
+
+	// 保存した設定は別のインスタンスからも読める。nil で全体の設定に戻る。
+	reloaded := NewPlayReminderService(repository, notifier, 60, newTestLogger())
+	reminders, err := reloaded.ListGameReminders(ctx)
+	if err != nil || len(reminders) != 2 || reminders["quiet"] != 0 || reminders["short"] != 30 {
+		t.Fatalf("unexpected reminders: %v (err=%v)", reminders, err)
+	}
+	if err := reloaded.SetGameReminder(ctx, "quiet", nil); err != nil {
+		t.Fatalf("SetGameReminder(nil): %v", err)
+	}
+	if reminders, _ := reloaded.ListGameReminders(ctx); len(reminders) != 1 {
+		t.Fatalf("override should be removed: %v", reminders)
+	}
+}
+
+func TestPlayReminderServiceRejectsInvalidMinutes(t *testing.T) {
+	t.Parallel()
+
+	service := NewPlayReminderService(&fakeSettingsRepository{}, &recordingNotifier{}, 0, newTestLogger())
+	invalid := MaxPlayReminderMinutes + 1
+	if err := service.SetGameReminder(context.Background(), "a", &invalid); err == nil {
+		t.Fatal("too long interval should be rejected")
+	}
+	if err := service.SetGameReminder(context.Background(), " ", nil); err == nil {
+		t.Fatal("empty gameID should be rejected")
+	}
+}
+
+func TestPlayReminderServiceSkipsPausedGames(t *testing.T) {
+	t.Parallel()
+
+	notifier := &recordingNotifier{}
+	service := NewPlayReminderService(&fakeSettingsRepository{}, notifier, 30, newTestLogger())
+	paused := playingStatus("a", 60*60)
+	paused.IsPlaying = false
+	service.Check(context.Background(), []domain.MonitoringGameStatus{paused})
+	if len(notifier.messages) != 0 {
+		t.Fatalf("paused game should not be reminded: %v", notifier.messages)
+	}
+	if got := formatReminderDuration(90 * 60); got != "1時間30分" {
+		t.Fatalf("formatReminderDuration = %q", got)
+	}
+}
@@ -0,0 +1,177 @@
+// 利用者が追加できるプレイ状況（カスタムステータス）の管理を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// maxPlayStatusLabelLength はカスタムステータスの表示名の最大文字数。
+const maxPlayStatusLabelLength = 50
+
+var (
+	// playStatusSlugPattern は slug に使える文字列（英小文字・数字・ハイフン・アンダースコア、32文字まで）。
+	playStatusSlugPattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+	playStatusColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// reservedPlayStatusSlugs は組み込みのプレイ状況と一覧フィルタの値で、カスタムステータスには使えない。
+var reservedPlayStatusSlugs = map[string]struct{}{
+	string(domain.PlayStatusUnplayed): {},
+	string(domain.PlayStatusPlaying):  {},
+	string(domain.PlayStatusPlayed):   {},
+	string(domain.GameFilterArchived): {},
+	string(domain.GameFilterAll):      {},
+}
+
+// PlayStatusService はプレイ状況の定義に関する操作を提供する。
+type PlayStatusService struct {
+	repository PlayStatusRepository
+	logger     *slog.Logger
+}
+
+// NewPlayStatusService は PlayStatusService を生成する。
+func NewPlayStatusService(repository PlayStatusRepository, logger *slog.Logger) *PlayStatusService {
+	return &PlayStatusService{repository: repository, logger: logger}
+}
+
+// PlayStatusDefinitionInput はカスタムステータスの作成・更新入力を表す。作成時のみ Slug を使う。
+type PlayStatusDefinitionInput struct {
+	Slug      string
+	Label     string
+	Color     string
+	SortOrder int64
+}
+
+// ListPlayStatusDefinitions は組み込みのプレイ状況に続けて、カスタムステータスを表示順で返す。
+func (service *PlayStatusService) ListPlayStatusDefinitions(ctx context.Context) ([]domain.PlayStatusDefinition, error) {
+	custom, err := service.repository.ListPlayStatusDefinitions(ctx)
+	if err != nil {
+		service.logger.Error("プレイ状況の取得に失敗", "error", err)
+		return nil, newServiceError("プレイ状況の取得に失敗しました", err.Error())
+	}
+	return append(domain.BuiltinPlayStatusDefinitions(), custom...), nil
+}
+
+// CreatePlayStatusDefinition はカスタムステータスを末尾に追加する。
+func (service *PlayStatusService) CreatePlayStatusDefinition(ctx context.Context, input PlayStatusDefinitionInput) (*domain.PlayStatusDefinition, error) {
+	slug, err := normalizePlayStatusSlug(input.Slug)
+	if err != nil {
+		service.logger.Warn("プレイ状況の入力が不正です", "error", err)
+		return nil, newServiceError("プレイ状況の入力が不正です", err.Error())
+	}
+	label, color, err := normalizePlayStatusFields(input.Label, input.Color)
+	if err != nil {
+		service.logger.Warn("プレイ状況の入力が不正です", "error", err)
+		return nil, newServiceError("プレイ状況の入力が不正です", err.Error())
+	}
+	existing, err := service.repository.GetPlayStatusDefinition(ctx, slug)
+	if err != nil {
+		service.logger.Error("プレイ状況の取得に失敗", "error", err)
+		return nil, newServiceError("プレイ状況の取得に失敗しました", err.Error())
+	}
+	if existing != nil {
+		return nil, newServiceError("同じ ID のプレイ状況が既にあります", slug)
+	}
+
+	created, err := service.repository.CreatePlayStatusDefinition(ctx, domain.PlayStatusDefinition{Slug: slug, Label: label, Color: color})
+	if err != nil {
+		service.logger.Error("プレイ状況の作成に失敗", "error", err)
+		return nil, newServiceError("プレイ状況の作成に失敗しました", err.Error())
+	}
+	return created, nil
+}
+
+// UpdatePlayStatusDefinition はカスタムステータスの表示名・色・表示順を更新する。slug は変更できない。
+func (service *PlayStatusService) UpdatePlayStatusDefinition(ctx context.Context, slug string, input PlayStatusDefinitionInput) (*domain.PlayStatusDefinition, error) {
+	current, err := service.getCustomDefinition(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	label, color, err := normalizePlayStatusFields(input.Label, input.Color)
+	if err != nil {
+		service.logger.Warn("プレイ状況の入力が不正です", "error", err)
+		return nil, newServiceError("プレイ状況の入力が不正です", err.Error())
+	}
+	if input.SortOrder < 0 {
+		return nil, newServiceError("プレイ状況の入力が不正です", "sortOrderが不正です")
+	}
+	current.Label = label
+	current.Color = color
+	current.SortOrder = input.SortOrder
+
+	updated, err := service.repository.UpdatePlayStatusDefinition(ctx, *current)
+	if err != nil {
+		service.logger.Error("プレイ状況の更新に失敗", "error", err)
+		return nil, newServiceError("プレイ状況の更新に失敗しました", err.Error())
+	}
+	return updated, nil
+}
+
+// DeletePlayStatusDefinition はカスタムステータスを削除し、ステータスを外したゲームの ID を返す（呼び出し側の同期要求に使う）。
+func (service *PlayStatusService) DeletePlayStatusDefinition(ctx context.Context, slug string) ([]string, error) {
+	current, err := service.getCustomDefinition(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	gameIDs, err := service.repository.DeletePlayStatusDefinition(ctx, current.Slug)
+	if err != nil {
+		service.logger.Error("プレイ状況の削除に失敗", "error", err)
+		return nil, newServiceError("プレイ状況の削除に失敗しました", err.Error())
+	}
+	service.logger.Info("プレイ状況を削除", "slug", current.Slug, "games", len(gameIDs))
+	return gameIDs, nil
+}
+
+func (service *PlayStatusService) getCustomDefinition(ctx context.Context, slug string) (*domain.PlayStatusDefinition, error) {
+	trimmed, detail, ok := requireNonEmpty(slug, "slug")
+	if !ok {
+		return nil, newServiceError("プレイ状況の ID が不正です", detail)
+	}
+	if _, reserved := reservedPlayStatusSlugs[trimmed]; reserved {
+		return nil, newServiceError("組み込みのプレイ状況は変更できません", trimmed)
+	}
+	definition, err := service.repository.GetPlayStatusDefinition(ctx, trimmed)
+	if err != nil {
+		service.logger.Error("プレイ状況の取得に失敗", "error", err)
+		return nil, newServiceError("プレイ状況の取得に失敗しました", err.Error())
+	}
+	if definition == nil {
+		return nil, newServiceError("プレイ状況が見つかりません", "指定されたIDが存在しません")
+	}
+	return definition, nil
+}
+
+// normalizePlayStatusSlug は slug を小文字に揃えて検証する。
+func normalizePlayStatusSlug(value string) (string, error) {
+	slug := strings.ToLower(strings.TrimSpace(value))
+	if !playStatusSlugPattern.MatchString(slug) {
+		return "", fmt.Errorf("slug は英小文字・数字・ハイフン・アンダースコアの32文字以内で指定してください: %q", value)
+	}
+	if _, reserved := reservedPlayStatusSlugs[slug]; reserved {
+		return "", fmt.Errorf("slug %q は組み込みのプレイ状況と重複します", slug)
+	}
+	return slug, nil
+}
+
+// normalizePlayStatusFields は表示名と色を検証する。色は空（既定色）か "#RRGGBB" で、小文字に揃える。
+func normalizePlayStatusFields(label, color string) (string, string, error) {
+	trimmedLabel, detail, ok := requireNonEmpty(label, "label")
+	if !ok {
+		return "", "", errors.New(detail)
+	}
+	if len([]rune(trimmedLabel)) > maxPlayStatusLabelLength {
+		return "", "", fmt.Errorf("labelは%d文字以内で指定してください", maxPlayStatusLabelLength)
+	}
+	trimmedColor := strings.TrimSpace(color)
+	if trimmedColor != "" && !playStatusColorPattern.MatchString(trimmedColor) {
+		return "", "", fmt.Errorf("colorは #RRGGBB 形式で指定してください: %q", color)
+	}
+	return trimmedLabel, strings.ToLower(trimmedColor), nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestNormalizePlayStatusSlugRejectsReservedAndInvalid(t *testing.T) {
+	t.Parallel()
+
+	if slug, err := normalizePlayStatusSlug("  Wish-List "); err != nil || slug != "wish-list" {
+		t.Fatalf("expected slug to be normalized, got %q (%v)", slug, err)
+	}
+	for _, value := range []string{"", "played", "all", "日本語", "has space", "-leading"} {
+		if _, err := normalizePlayStatusSlug(value); err == nil {
+			t.Fatalf("expected %q to be rejected", value)
+		}
+	}
+	if _, color, err := normalizePlayStatusFields("積みゲー", "#ABCDEF"); err != nil || color != "#abcdef" {
+		t.Fatalf("expected color to be normalized, got %q (%v)", color, err)
+	}
+	if _, _, err := normalizePlayStatusFields("積みゲー", "red"); err == nil {
+		t.Fatal("expected invalid color to be rejected")
+	}
+}
+
+func TestPlayStatusServiceListsBuiltinsFirst(t *testing.T) {
+	t.Parallel()
+	runtime := newMaintenanceServiceRuntime(t)
+	service := NewPlayStatusService(runtime.repository, newTestLogger())
+
+	definitions, err := service.ListPlayStatusDefinitions(context.Background())
+	if err != nil {
+		t.Fatalf("ListPlayStatusDefinitions: %v", err)
+	}
+	if len(definitions) != 5 || !definitions[0].Builtin || definitions[0].Slug != string(domain.PlayStatusUnplayed) || definitions[3].Builtin {
+		t.Fatalf("unexpected definitions: %+v", definitions)
+	}
+	if _, err := service.UpdatePlayStatusDefinition(context.Background(), "played", PlayStatusDefinitionInput{Label: "x"}); err == nil {
+		t.Fatal("expected builtin status to be read-only")
+	}
+	if _, err := service.CreatePlayStatusDefinition(context.Background(), PlayStatusDefinitionInput{Slug: "wishlist", Label: "重複"}); err == nil {
+		t.Fatal("expected duplicate slug to be rejected")
+	}
+}
+
+func TestGameServiceUpdateGameValidatesCustomStatus(t *testing.T) {
+	t.Parallel()
+
+	game := &domain.Game{ID: "game-1", Title: "Game", Publisher: "Pub", ExePath: "/game.exe", PlayStatus: domain.PlayStatusUnplayed}
+	repo := &fakeGameRepository{
+		getGameByIDFn:  func(ctx context.Context, gameID string) (*domain.Game, error) { copied := *game; return &copied, nil },
+		updateGameFn:   func(ctx context.Context, updated domain.Game) (*domain.Game, error) { return &updated, nil },
+		customStatuses: []domain.PlayStatusDefinition{{Slug: "backlog", Label: "積みゲー"}},
+	}
+	service := NewGameService(repo, newTestLogger())
+	input := GameUpdateInput{Title: "Game", Publisher: "Pub", ExePath: "/game.exe"}
+
+	unknown := "missing"
+	input.CustomStatus = &unknown
+	if _, err := service.UpdateGame(context.Background(), "game-1", input); err == nil {
+		t.Fatal("expected unknown custom status to be rejected")
+	}
+	backlog := " Backlog "
+	input.CustomStatus = &backlog
+	updated, err := service.UpdateGame(context.Background(), "game-1", input)
+	if err != nil || updated.CustomStatus == nil || *updated.CustomStatus != "backlog" {
+		t.Fatalf("expected custom status to be set, got %+v (%v)", updated, err)
+	}
+	if updated.PlayStatus != domain.PlayStatusUnplayed {
+		t.Fatalf("expected builtin play status to be kept, got %s", updated.PlayStatus)
+	}
+}
@@ -0,0 +1,243 @@
+// 周回（期間またはルート集合）ごとのプレイ時間を章単位で比較する処理を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// unassignedChapterName はルート未設定のセッションをまとめる章の表示名。
+const unassignedChapterName = "未分類"
+
+// PlaythroughSelector は比較する1周分のセッションの選び方を表す。
+// From / To はプレイ日時の範囲（両端を含む、nil は無制限）。
+// RouteIDs を指定した場合は「章の集合」での比較になり、A と B の同じ位置のルート同士を
+// 対応させる（2周目を別ルートとして記録している場合向け）。指定しない場合は期間で分け、
+// 同じルート同士を対応させる。
+type PlaythroughSelector struct {
+	From     *time.Time
+	To       *time.Time
+	RouteIDs []string
+}
+
+type chapterTally struct {
+	time     int64
+	sessions int64
+}
+
+// ComparePlaythroughs は2周分のプレイを章ごとに比較する。
+func (service *RouteService) ComparePlaythroughs(
+	ctx context.Context,
+	gameID string,
+	selectorA PlaythroughSelector,
+	selectorB PlaythroughSelector,
+) (domain.PlaythroughComparison, error) {
+	trimmedGameID, err := service.requireField(gameID, "gameID", "ゲームIDが不正です")
+	if err != nil {
+		return domain.PlaythroughComparison{}, err
+	}
+	if err := validatePlaythroughSelectors(selectorA, selectorB); err != nil {
+		service.logger.Warn("比較条件が不正です", "gameId", trimmedGameID, "error", err)
+		return domain.PlaythroughComparison{}, newServiceError("比較条件が不正です", err.Error())
+	}
+
+	routes, err := service.repository.ListRoutesByGame(ctx, trimmedGameID)
+	if err != nil {
+		service.logger.Error("ルート取得に失敗", "error", err)
+		return domain.PlaythroughComparison{}, newServiceError("周回比較に失敗しました", err.Error())
+	}
+	sessions, err := service.repository.ListPlaySessionsByGame(ctx, trimmedGameID)
+	if err != nil {
+		service.logger.Error("セッション取得に失敗", "error", err)
+		return domain.PlaythroughComparison{}, newServiceError("周回比較に失敗しました", err.Error())
+	}
+	routeByID := make(map[string]domain.Route, len(routes))
+	for _, route := range routes {
+		routeByID[route.ID] = route
+	}
+	for _, routeID := range append(append([]string{}, selectorA.RouteIDs...), selectorB.RouteIDs...) {
+		if _, ok := routeByID[strings.TrimSpace(routeID)]; !ok {
+			return domain.PlaythroughComparison{}, newServiceError("ルートが見つかりません", routeID)
+		}
+	}
+
+	sessionsA := selectPlaythroughSessions(sessions, selectorA)
+	sessionsB := selectPlaythroughSessions(sessions, selectorB)
+	tallyA := tallyByChapter(sessionsA)
+	tallyB := tallyByChapter(sessionsB)
+	comparison := domain.PlaythroughComparison{
+		GameID: trimmedGameID,
+		A:      summarizePlaythrough(sessionsA, len(tallyA)),
+		B:      summarizePlaythrough(sessionsB, len(tallyB)),
+	}
+	comparison.TotalDelta = comparison.B.TotalTime - comparison.A.TotalTime
+
+	if len(selectorA.RouteIDs) > 0 {
+		comparison.Chapters = make([]domain.ChapterComparison, 0, len(selectorA.RouteIDs))
+		for i := range selectorA.RouteIDs {
+			routeA := routeByID[strings.TrimSpace(selectorA.RouteIDs[i])]
+			routeB := routeByID[strings.TrimSpace(selectorB.RouteIDs[i])]
+			name := routeA.Name
+			if routeB.Name != routeA.Name {
+				name = routeA.Name + " / " + routeB.Name
+			}
+			comparison.Chapters = append(comparison.Chapters, compareChapter(
+				&routeA.ID, &routeB.ID, name, tallyA[routeA.ID], tallyB[routeB.ID], comparison,
+			))
+		}
+		return comparison, nil
+	}
+
+	keys := make([]string, 0, len(tallyA)+len(tallyB))
+	seen := make(map[string]struct{}, len(tallyA)+len(tallyB))
+	for _, tally := range []map[string]chapterTally{tallyA, tallyB} {
+		for key := range tally {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	// ルートの並び順どおりに並べ、未分類は末尾に置く。
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "") != (keys[j] == "") {
+			return keys[j] == ""
+		}
+		left, right := routeByID[keys[i]], routeByID[keys[j]]
+		if left.Order != right.Order {
+			return left.Order < right.Order
+		}
+		return keys[i] < keys[j]
+	})
+	comparison.Chapters = make([]domain.ChapterComparison, 0, len(keys))
+	for _, key := range keys {
+		var routeID *string
+		name := unassignedChapterName
+		if key != "" {
+			id := key
+			routeID = &id
+			name = key
+			if route, ok := routeByID[key]; ok {
+				name = route.Name
+			}
+		}
+		comparison.Chapters = append(comparison.Chapters, compareChapter(
+			routeID, routeID, name, tallyA[key], tallyB[key], comparison,
+		))
+	}
+	return comparison, nil
+}
+
+func validatePlaythroughSelectors(selectorA, selectorB PlaythroughSelector) error {
+	for _, selector := range []PlaythroughSelector{selectorA, selectorB} {
+		if selector.From != nil && selector.To != nil && selector.From.After(*selector.To) {
+			return errors.New("fromがtoより後です")
+		}
+		for _, routeID := range selector.RouteIDs {
+			if strings.TrimSpace(routeID) == "" {
+				return errors.New("routeIDsに空の値があります")
+			}
+		}
+	}
+	if len(selectorA.RouteIDs) != len(selectorB.RouteIDs) {
+		return errors.New("比較するルートの数が一致しません")
+	}
+	return nil
+}
+
+func selectPlaythroughSessions(sessions []domain.PlaySession, selector PlaythroughSelector) []domain.PlaySession {
+	var routeSet map[string]struct{}
+	if len(selector.RouteIDs) > 0 {
+		routeSet = make(map[string]struct{}, len(selector.RouteIDs))
+		for _, routeID := range selector.RouteIDs {
+			routeSet[strings.TrimSpace(routeID)] = struct{}{}
+		}
+	}
+	selected := make([]domain.PlaySession, 0, len(sessions))
+	for _, session := range sessions {
+		if selector.From != nil && session.PlayedAt.Before(*selector.From) {
+			continue
+		}
+		if selector.To != nil && session.PlayedAt.After(*selector.To) {
+			continue
+		}
+		if routeSet != nil {
+			if session.RouteID == nil {
+				continue
+			}
+			if _, ok := routeSet[*session.RouteID]; !ok {
+				continue
+			}
+		}
+		selected = append(selected, session)
+	}
+	return selected
+}
+
+// tallyByChapter はセッションをルートIDごとに集計する。ルート未設定は空文字キーになる。
+func tallyByChapter(sessions []domain.PlaySession) map[string]chapterTally {
+	tally := make(map[string]chapterTally)
+	for _, session := range sessions {
+		key := ""
+		if session.RouteID != nil {
+			key = *session.RouteID
+		}
+		entry := tally[key]
+		entry.time += session.Duration
+		entry.sessions++
+		tally[key] = entry
+	}
+	return tally
+}
+
+func summarizePlaythrough(sessions []domain.PlaySession, chapterCount int) domain.PlaythroughSummary {
+	summary := domain.PlaythroughSummary{ChapterCount: int64(chapterCount)}
+	for i := range sessions {
+		session := sessions[i]
+		summary.TotalTime += session.Duration
+		summary.SessionCount++
+		if summary.FirstPlayedAt == nil || session.PlayedAt.Before(*summary.FirstPlayedAt) {
+			summary.FirstPlayedAt = &sessions[i].PlayedAt
+		}
+		if summary.LastPlayedAt == nil || session.PlayedAt.After(*summary.LastPlayedAt) {
+			summary.LastPlayedAt = &sessions[i].PlayedAt
+		}
+	}
+	if summary.SessionCount > 0 {
+		summary.AverageTime = float64(summary.TotalTime) / float64(summary.SessionCount)
+	}
+	return summary
+}
+
+func compareChapter(
+	routeIDA, routeIDB *string,
+	name string,
+	tallyA, tallyB chapterTally,
+	comparison domain.PlaythroughComparison,
+) domain.ChapterComparison {
+	chapter := domain.ChapterComparison{
+		RouteIDA:  routeIDA,
+		RouteIDB:  routeIDB,
+		Name:      name,
+		TimeA:     tallyA.time,
+		TimeB:     tallyB.time,
+		SessionsA: tallyA.sessions,
+		SessionsB: tallyB.sessions,
+		Delta:     tallyB.time - tallyA.time,
+	}
+	if tallyA.time > 0 {
+		chapter.DeltaRatio = float64(chapter.Delta) / float64(tallyA.time)
+	}
+	if comparison.A.TotalTime > 0 {
+		chapter.ShareA = float64(tallyA.time) / float64(comparison.A.TotalTime)
+	}
+	if comparison.B.TotalTime > 0 {
+		chapter.ShareB = float64(tallyB.time) / float64(comparison.B.TotalTime)
+	}
+	return chapter
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func newPlaythroughRouteRepository(routes []domain.Route, sessions []domain.PlaySession) fakeRouteRepository {
+	repo := newFullFakeRouteRepository()
+	repo.listRoutesByGameFn = func(ctx context.Context, gameID string) ([]domain.Route, error) { return routes, nil }
+	repo.listSessionsFn = func(ctx context.Context, gameID string) ([]domain.PlaySession, error) { return sessions, nil }
+	return repo
+}
+
+func playthroughSession(day int, duration int64, routeID string) domain.PlaySession {
+	session := domain.PlaySession{
+		GameID:   "game-1",
+		PlayedAt: time.Date(2026, 1, day, 12, 0, 0, 0, time.UTC),
+		Duration: duration,
+	}
+	if routeID != "" {
+		session.RouteID = &routeID
+	}
+	return session
+}
+
+func TestComparePlaythroughsByRangeMatchesSameRoute(t *testing.T) {
+	t.Parallel()
+
+	routes := []domain.Route{
+		{ID: "r2", Name: "二章", Order: 2, GameID: "game-1"},
+		{ID: "r1", Name: "一章", Order: 1, GameID: "game-1"},
+	}
+	sessions := []domain.PlaySession{
+		playthroughSession(1, 3600, "r1"),
+		playthroughSession(2, 1800, "r2"),
+		playthroughSession(2, 600, ""),
+		playthroughSession(20, 1800, "r1"),
+		playthroughSession(21, 2700, "r2"),
+	}
+	service := NewRouteService(newPlaythroughRouteRepository(routes, sessions), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	splitA := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	splitB := splitA.Add(time.Second)
+	got, err := service.ComparePlaythroughs(context.Background(), "game-1",
+		PlaythroughSelector{To: &splitA}, PlaythroughSelector{From: &splitB})
+	if err != nil {
+		t.Fatalf("ComparePlaythroughs: %v", err)
+	}
+	if got.A.TotalTime != 6000 || got.B.TotalTime != 4500 || got.TotalDelta != -1500 {
+		t.Fatalf("unexpected totals: %+v", got)
+	}
+	if len(got.Chapters) != 3 || got.Chapters[0].Name != "一章" || got.Chapters[1].Name != "二章" || got.Chapters[2].Name != unassignedChapterName {
+		t.Fatalf("unexpected chapter order: %+v", got.Chapters)
+	}
+	first := got.Chapters[0]
+	if first.Delta != -1800 || first.DeltaRatio != -0.5 || first.ShareA != 0.6 || first.ShareB != 0.4 {
+		t.Fatalf("unexpected first chapter: %+v", first)
+	}
+	if got.Chapters[2].TimeB != 0 || got.Chapters[2].RouteIDA != nil {
+		t.Fatalf("unexpected unassigned chapter: %+v", got.Chapters[2])
+	}
+}
+
+func TestComparePlaythroughsByChapterSetPairsByPosition(t *testing.T) {
+	t.Parallel()
+
+	routes := []domain.Route{
+		{ID: "r1", Name: "一章", Order: 1, GameID: "game-1"},
+		{ID: "r1b", Name: "一章(2周目)", Order: 2, GameID: "game-1"},
+	}
+	sessions := []domain.PlaySession{
+		playthroughSession(1, 3600, "r1"),
+		playthroughSession(5, 1200, "r1b"),
+	}
+	service := NewRouteService(newPlaythroughRouteRepository(routes, sessions), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	got, err := service.ComparePlaythroughs(context.Background(), "game-1",
+		PlaythroughSelector{RouteIDs: []string{"r1"}}, PlaythroughSelector{RouteIDs: []string{"r1b"}})
+	if err != nil {
+		t.Fatalf("ComparePlaythroughs: %v", err)
+	}
+	if len(got.Chapters) != 1 || got.Chapters[0].TimeA != 3600 || got.Chapters[0].TimeB != 1200 {
+		t.Fatalf("unexpected chapters: %+v", got.Chapters)
+	}
+
+	if _, err := service.ComparePlaythroughs(context.Background(), "game-1",
+		PlaythroughSelector{RouteIDs: []string{"r1"}}, PlaythroughSelector{}); err == nil {
+		t.Fatal("expected error for mismatched route sets")
+	}
+	if _, err := service.ComparePlaythroughs(context.Background(), "game-1",
+		PlaythroughSelector{RouteIDs: []string{"missing"}}, PlaythroughSelector{RouteIDs: []string{"r1"}}); err == nil {
+		t.Fatal("expected error for unknown route")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+// 非Windows向けのプロセスのコマンドライン取得のスタブ実装。
+package services
+
+import "errors"
+
+// processCommandLine は非Windowsではサポート外。一致条件を設定したゲームは検出されない。
+func processCommandLine(pid int) (string, error) {
+	return "", errors.New("process command line lookup is only supported on Windows")
+}
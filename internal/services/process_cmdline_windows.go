@@ -0,0 +1,49 @@
+//go:build windows
+
+// Windows向けにプロセスのコマンドラインを取得する。
+package services
+
+import (
+	"errors"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// processCommandLine は指定 PID のプロセスのコマンドラインを返す。
+// PROCESS_QUERY_LIMITED_INFORMATION で読める ProcessCommandLineInformation（Windows 8.1 以降）を使う。
+func processCommandLine(pid int) (string, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+
+	buffer := make([]byte, 1024)
+	for {
+		var size uint32
+		err := windows.NtQueryInformationProcess(
+			handle,
+			windows.ProcessCommandLineInformation,
+			unsafe.Pointer(&buffer[0]),
+			uint32(len(buffer)),
+			&size,
+		)
+		if err == nil {
+			break
+		}
+		if isBufferTooSmall(err) && int(size) > len(buffer) {
+			buffer = make([]byte, size)
+			continue
+		}
+		return "", err
+	}
+	commandLine := (*windows.NTUnicodeString)(unsafe.Pointer(&buffer[0]))
+	return commandLine.String(), nil
+}
+
+func isBufferTooSmall(err error) bool {
+	return errors.Is(err, windows.STATUS_INFO_LENGTH_MISMATCH) ||
+		errors.Is(err, windows.STATUS_BUFFER_TOO_SMALL) ||
+		errors.Is(err, windows.STATUS_BUFFER_OVERFLOW)
+}
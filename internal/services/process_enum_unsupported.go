@@ -0,0 +1,11 @@
+//go:build !windows
+
+// 非Windows向けのプロセス列挙のスタブ実装。
+package services
+
+import "errors"
+
+// enumerateProcesses は非Windowsではサポート外。getProcesses は PowerShell / wmic のフォールバックへ進む。
+func enumerateProcesses() ([]ProcessInfo, error) {
+	return nil, errors.New("native process enumeration is only supported on Windows")
+}
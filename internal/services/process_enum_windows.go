@@ -0,0 +1,65 @@
+//go:build windows
+
+// Windows向けに Toolhelp スナップショットでプロセス一覧を取得する。
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// enumerateProcesses は実行中のプロセスを外部コマンドを使わずに列挙する。
+// 実行ファイルのパスは QueryFullProcessImageName で取得し、権限不足などで取れないプロセスは名前で代用する。
+func enumerateProcesses() ([]ProcessInfo, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = windows.CloseHandle(snapshot) }()
+
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	if err := windows.Process32First(snapshot, &entry); err != nil {
+		return nil, err
+	}
+
+	buffer := make([]uint16, windows.MAX_LONG_PATH)
+	processes := make([]ProcessInfo, 0, 256)
+	for {
+		pid := int(entry.ProcessID)
+		name := strings.TrimSpace(windows.UTF16ToString(entry.ExeFile[:]))
+		if pid > 0 && name != "" {
+			fullPath := processImagePath(entry.ProcessID, buffer)
+			if fullPath == "" {
+				fullPath = name
+			}
+			if strings.EqualFold(filepath.Ext(fullPath), ".exe") {
+				processes = append(processes, ProcessInfo{Name: name, Pid: pid, Cmd: fullPath})
+			}
+		}
+		if err := windows.Process32Next(snapshot, &entry); err != nil {
+			if errors.Is(err, windows.ERROR_NO_MORE_FILES) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return processes, nil
+}
+
+// processImagePath はプロセスの実行ファイルのフルパスを返す。開けない・取得できない場合は空文字。
+func processImagePath(pid uint32, buffer []uint16) string {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+	size := uint32(len(buffer))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buffer[0], &size); err != nil {
+		return ""
+	}
+	return windows.UTF16ToString(buffer[:size])
+}
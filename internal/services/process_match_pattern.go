@@ -0,0 +1,124 @@
+// エミュレーター経由のゲームを ROM ごとに見分ける、コマンドラインの一致条件を提供する。
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// processMatchRegexPrefix はこの接頭辞で始まる一致条件を正規表現として扱う（大文字小文字は区別しない）。
+// 接頭辞の無い条件は、区切り文字と大文字小文字を揃えた部分一致で判定する。
+const processMatchRegexPrefix = "regex:"
+
+// processMatcher はコンパイル済みのコマンドラインの一致条件。
+type processMatcher struct {
+	substring string
+	pattern   *regexp.Regexp
+}
+
+// compileProcessMatchPattern は一致条件をコンパイルする。空（空白のみ）の場合は nil を返す。
+func compileProcessMatchPattern(pattern string) (*processMatcher, error) {
+	trimmed := strings.TrimSpace(pattern)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(strings.ToLower(trimmed), processMatchRegexPrefix) {
+		expr := strings.TrimSpace(trimmed[len(processMatchRegexPrefix):])
+		if expr == "" {
+			return nil, errors.New("regex is empty")
+		}
+		compiled, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, err
+		}
+		return &processMatcher{pattern: compiled}, nil
+	}
+	return &processMatcher{substring: normalizeProcessPathToken(trimmed)}, nil
+}
+
+// match はコマンドラインが一致条件を満たすかを返す。
+func (matcher *processMatcher) match(commandLine string) bool {
+	if commandLine == "" {
+		return false
+	}
+	if matcher.pattern != nil {
+		return matcher.pattern.MatchString(commandLine)
+	}
+	return strings.Contains(normalizeProcessPathToken(commandLine), matcher.substring)
+}
+
+// commandLineKey はコマンドラインのキャッシュのキー。PID の再利用で別プロセスの値を返さないよう名前も含める。
+type commandLineKey struct {
+	pid  int
+	name string
+}
+
+// matchCommandLine はプロセスのコマンドラインが一致条件を満たすかを返す。matcher が nil なら常に true。
+// コマンドラインはプロセスの生存中に変わらないため PID ごとにキャッシュする（取得失敗も空文字として保持する）。
+func (service *ProcessMonitorService) matchCommandLine(matcher *processMatcher, proc normalizedProcess) bool {
+	if matcher == nil {
+		return true
+	}
+	key := commandLineKey{pid: proc.info.Pid, name: proc.normalized}
+	service.commandLineMu.Lock()
+	commandLine, cached := service.commandLines[key]
+	provider := service.commandLineProvider
+	service.commandLineMu.Unlock()
+	if !cached && provider != nil {
+		fetched, err := provider(proc.info.Pid)
+		if err != nil {
+			service.logger.Debug("コマンドラインの取得に失敗", "pid", proc.info.Pid, "error", err)
+		}
+		commandLine = fetched
+		service.commandLineMu.Lock()
+		if service.commandLines == nil {
+			service.commandLines = make(map[commandLineKey]string)
+		}
+		service.commandLines[key] = commandLine
+		service.commandLineMu.Unlock()
+	}
+	return matcher.match(commandLine)
+}
+
+// pruneCommandLines は終了したプロセスのコマンドラインをキャッシュから消す。
+func (service *ProcessMonitorService) pruneCommandLines(processes []normalizedProcess) {
+	alive := make(map[commandLineKey]struct{}, len(processes))
+	for _, proc := range processes {
+		alive[commandLineKey{pid: proc.info.Pid, name: proc.normalized}] = struct{}{}
+	}
+	service.commandLineMu.Lock()
+	defer service.commandLineMu.Unlock()
+	for key := range service.commandLines {
+		if _, ok := alive[key]; !ok {
+			delete(service.commandLines, key)
+		}
+	}
+}
+
+// gameProcessMatcher はゲームの一致条件をコンパイルする。不正な条件はログに残して無効として扱う。
+func (service *ProcessMonitorService) gameProcessMatcher(gameID string, pattern *string) (*processMatcher, bool) {
+	if pattern == nil {
+		return nil, true
+	}
+	matcher, err := compileProcessMatchPattern(*pattern)
+	if err != nil {
+		service.logger.Warn("プロセスの一致条件が不正です", "gameId", gameID, "error", err)
+		return nil, false
+	}
+	return matcher, true
+}
+
+// SetGameProcessMatchPattern は監視中のゲームの一致条件を即座に反映する。
+// 新しい条件に一致しなくなったプロセスは、次のスキャンで未検出として扱われる。
+func (service *ProcessMonitorService) SetGameProcessMatchPattern(gameID string, pattern *string) {
+	matcher, ok := service.gameProcessMatcher(gameID, pattern)
+	if !ok {
+		return
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if game, exists := service.monitoredGames[gameID]; exists {
+		game.Matcher = matcher
+	}
+}
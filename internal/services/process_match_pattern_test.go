@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestCompileProcessMatchPattern(t *testing.T) {
+	t.Parallel()
+
+	commandLine := `"C:\RetroArch\retroarch.exe" -L cores\snes9x_libretro.dll "D:/ROMs/SNES/Chrono Trigger (USA).sfc"`
+	cases := []struct {
+		pattern string
+		want    bool
+	}{
+		{pattern: `d:\roms\snes\chrono trigger`, want: true},
+		{pattern: "Final Fantasy", want: false},
+		{pattern: `regex:snes9x.*chrono`, want: true},
+		{pattern: `REGEX: \.gba"?$`, want: false},
+	}
+	for _, c := range cases {
+		matcher, err := compileProcessMatchPattern(c.pattern)
+		if err != nil || matcher == nil {
+			t.Fatalf("compile %q: %v", c.pattern, err)
+		}
+		if got := matcher.match(commandLine); got != c.want {
+			t.Fatalf("pattern %q: expected %v, got %v", c.pattern, c.want, got)
+		}
+	}
+
+	if matcher, err := compileProcessMatchPattern("  "); matcher != nil || err != nil {
+		t.Fatalf("expected empty pattern to disable matching, got %v %v", matcher, err)
+	}
+	for _, invalid := range []string{"regex:", "regex:(unclosed"} {
+		if _, err := compileProcessMatchPattern(invalid); err == nil {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestProcessMonitorServiceTracksEmulatorGamesByCommandLine(t *testing.T) {
+	t.Parallel()
+
+	chrono := "Chrono Trigger"
+	mario := "regex:super mario world"
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) { return nil, nil },
+		updateGameFn:  func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return []domain.Game{
+				{ID: "chrono", Title: "Chrono Trigger", ExePath: `C:\RetroArch\retroarch.exe`, ProcessMatchPattern: &chrono},
+				{ID: "mario", Title: "Super Mario World", ExePath: `C:\RetroArch\retroarch.exe`, ProcessMatchPattern: &mario},
+			}, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	lookups := 0
+	service.commandLineProvider = func(pid int) (string, error) {
+		lookups++
+		return `"C:\RetroArch\retroarch.exe" "D:\ROMs\Chrono Trigger.sfc"`, nil
+	}
+
+	processes := []ProcessInfo{{Name: "retroarch.exe", Pid: 42, Cmd: `C:\RetroArch\retroarch.exe`}}
+	normalized := normalizeProcessList(processes)
+	service.autoAddGamesFromDatabase(processes, normalized)
+
+	if _, ok := service.monitoredGames["chrono"]; !ok {
+		t.Fatalf("expected the ROM in the command line to be tracked")
+	}
+	if _, ok := service.monitoredGames["mario"]; ok {
+		t.Fatalf("expected a different ROM not to be tracked")
+	}
+	if lookups != 1 {
+		t.Fatalf("expected command line to be cached per process, got %d lookups", lookups)
+	}
+
+	service.pruneCommandLines(nil)
+	if len(service.commandLines) != 0 {
+		t.Fatalf("expected exited processes to be pruned, got %v", service.commandLines)
+	}
+}
+
+func TestGameServiceSetProcessMatchPatternValidatesAndClears(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID}, nil
+		},
+	}
+	service := NewGameService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	if _, err := service.SetProcessMatchPattern(ctx, "game-1", "regex:[a-"); err == nil {
+		t.Fatalf("expected invalid regex to be rejected")
+	}
+	game, err := service.SetProcessMatchPattern(ctx, "game-1", "  Chrono Trigger  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repository.matchPattern == nil || *repository.matchPattern != "Chrono Trigger" || game.ProcessMatchPattern == nil {
+		t.Fatalf("expected trimmed pattern to be saved, got %v", repository.matchPattern)
+	}
+	if _, err := service.SetProcessMatchPattern(ctx, "game-1", " "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repository.matchPattern != nil {
+		t.Fatalf("expected pattern to be cleared")
+	}
+}
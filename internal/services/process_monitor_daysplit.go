@@ -0,0 +1,103 @@
+// 日付をまたいだプレイセッションを、ローカル時刻の 0:00 で日ごとのセッションに分けて記録する。
+package services
+
+import "time"
+
+// dayMark は日付が変わった時点までにセッションで数えたプレイ時間と無操作時間（いずれも累計、秒）。
+// EndedAt はその日の最後の時刻（23:59:59）で、分けたセッションの playedAt に使う。
+type dayMark struct {
+	EndedAt     time.Time `json:"endedAt"`
+	Seconds     int64     `json:"seconds"`
+	IdleSeconds int64     `json:"idleSeconds,omitempty"`
+}
+
+// SetSplitAtMidnight は日付をまたいだセッションを 0:00 で分けて記録するか設定する。
+// 区切りは設定によらず記録しておき、保存する時点の設定で分けるかを決める。
+func (service *ProcessMonitorService) SetSplitAtMidnight(enabled bool) {
+	service.splitAtMidnight.Store(enabled)
+}
+
+// markDayBoundary はスキャンのたびに呼び、セッション中に日付が変わっていたら、変わった時点までの累計を区切りとして残す。
+// 中断中に日付が変わった場合も、それまでに数えた時間は前の日の分になる。service.mu を保持した状態で呼ぶ。
+func (game *MonitoringGame) markDayBoundary(now time.Time) {
+	if game.PlayStartTime == nil && !game.suspended() && !game.IsPaused && !game.PendingEnd && game.AccumulatedTime == 0 {
+		game.resetDayMarks()
+		return
+	}
+	today := localMidnight(now)
+	if game.SessionDay.IsZero() {
+		game.SessionDay = today
+		if game.PlayStartTime != nil {
+			game.SessionDay = localMidnight(*game.PlayStartTime)
+		}
+	}
+	if !today.After(game.SessionDay) {
+		return
+	}
+	seconds := game.AccumulatedTime
+	if game.PlayStartTime != nil && game.PlayStartTime.Before(today) {
+		seconds += int64(today.Sub(*game.PlayStartTime).Seconds())
+	}
+	idleSeconds := game.IdleTime
+	if game.IdleSince != nil && game.IdleSince.Before(today) {
+		idleSeconds += int64(today.Sub(*game.IdleSince).Seconds())
+	}
+	game.DayMarks = append(game.DayMarks, dayMark{
+		EndedAt:     game.SessionDay.AddDate(0, 0, 1).Add(-time.Second),
+		Seconds:     seconds,
+		IdleSeconds: idleSeconds,
+	})
+	game.SessionDay = today
+}
+
+// resetDayMarks はセッションの開始・終了時に区切りを消す。
+func (game *MonitoringGame) resetDayMarks() {
+	game.SessionDay = time.Time{}
+	game.DayMarks = nil
+}
+
+// splitSessionByDay は pending を DayMarks の区切りで日ごとのセッションに分ける。区切りが無ければ pending だけを返す。
+// 分けた後のプレイ時間・無操作時間の合計は元のセッションと同じになる。Partial は最初の日だけに残し、
+// CPU・メモリの使用量はセッション全体の値をそれぞれに持たせる。プレイ時間が 0 の日は作らない。
+func splitSessionByDay(pending spooledSession) []spooledSession {
+	if len(pending.DayMarks) == 0 {
+		return []spooledSession{pending}
+	}
+	parts := make([]spooledSession, 0, len(pending.DayMarks)+1)
+	var countedSeconds, countedIdle int64
+	for _, mark := range pending.DayMarks {
+		seconds := min(mark.Seconds, pending.Duration) - countedSeconds
+		if seconds <= 0 {
+			continue
+		}
+		idle := max(min(mark.IdleSeconds, pending.IdleTime)-countedIdle, 0)
+		part := pending
+		part.EndedAt = mark.EndedAt
+		part.Duration = seconds
+		part.IdleTime = idle
+		parts = append(parts, part)
+		countedSeconds += seconds
+		countedIdle += idle
+	}
+	if rest := pending.Duration - countedSeconds; rest > 0 || len(parts) == 0 {
+		part := pending
+		part.Duration = rest
+		part.IdleTime = max(pending.IdleTime-countedIdle, 0)
+		parts = append(parts, part)
+	} else {
+		parts[len(parts)-1].IdleTime += max(pending.IdleTime-countedIdle, 0)
+	}
+	for i := range parts {
+		parts[i].DayMarks = nil
+		if i > 0 {
+			parts[i].Partial = false
+		}
+	}
+	return parts
+}
+
+// localMidnight は at のローカル日付の 0:00 を返す。
+func localMidnight(at time.Time) time.Time {
+	local := at.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestSplitSessionByDayKeepsTotals(t *testing.T) {
+	t.Parallel()
+
+	firstDayEnd := time.Date(2026, 5, 1, 23, 59, 59, 0, time.Local)
+	endedAt := time.Date(2026, 5, 2, 1, 0, 0, 0, time.Local)
+	parts := splitSessionByDay(spooledSession{
+		GameID:   "game-1",
+		EndedAt:  endedAt,
+		Duration: 7200,
+		IdleTime: 600,
+		Partial:  true,
+		DayMarks: []dayMark{{EndedAt: firstDayEnd, Seconds: 3600, IdleSeconds: 500}},
+	})
+	if len(parts) != 2 {
+		t.Fatalf("expected two parts, got %+v", parts)
+	}
+	if !parts[0].EndedAt.Equal(firstDayEnd) || parts[0].Duration != 3600 || parts[0].IdleTime != 500 || !parts[0].Partial {
+		t.Fatalf("unexpected first part: %+v", parts[0])
+	}
+	if !parts[1].EndedAt.Equal(endedAt) || parts[1].Duration != 3600 || parts[1].IdleTime != 100 || parts[1].Partial {
+		t.Fatalf("unexpected second part: %+v", parts[1])
+	}
+	for _, part := range parts {
+		if len(part.DayMarks) != 0 {
+			t.Fatalf("parts should not carry day marks: %+v", part)
+		}
+	}
+}
+
+func TestSplitSessionByDaySkipsEmptyDays(t *testing.T) {
+	t.Parallel()
+
+	// 中断したまま日付が変わり、前の日の分で全体の長さに達している。
+	parts := splitSessionByDay(spooledSession{
+		EndedAt:  time.Date(2026, 5, 3, 9, 0, 0, 0, time.Local),
+		Duration: 100,
+		IdleTime: 10,
+		DayMarks: []dayMark{
+			{EndedAt: time.Date(2026, 5, 1, 23, 59, 59, 0, time.Local), Seconds: 100},
+			{EndedAt: time.Date(2026, 5, 2, 23, 59, 59, 0, time.Local), Seconds: 100},
+		},
+	})
+	if len(parts) != 1 || parts[0].Duration != 100 || parts[0].IdleTime != 10 {
+		t.Fatalf("unexpected parts: %+v", parts)
+	}
+}
+
+func TestMarkDayBoundaryRecordsTimeUntilMidnight(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 5, 1, 23, 0, 0, 0, time.Local)
+	game := &MonitoringGame{GameID: "game-1", PlayStartTime: &start, AccumulatedTime: 120}
+	game.markDayBoundary(start.Add(30 * time.Minute))
+	if len(game.DayMarks) != 0 {
+		t.Fatalf("no boundary should be recorded yet: %+v", game.DayMarks)
+	}
+	game.markDayBoundary(start.Add(90 * time.Minute))
+	if len(game.DayMarks) != 1 {
+		t.Fatalf("expected one boundary, got %+v", game.DayMarks)
+	}
+	mark := game.DayMarks[0]
+	if mark.Seconds != 120+3600 || !mark.EndedAt.Equal(time.Date(2026, 5, 1, 23, 59, 59, 0, time.Local)) {
+		t.Fatalf("unexpected mark: %+v", mark)
+	}
+	game.markDayBoundary(start.Add(2 * time.Hour))
+	if len(game.DayMarks) != 1 {
+		t.Fatalf("the same day should not be marked twice: %+v", game.DayMarks)
+	}
+
+	game.PlayStartTime = nil
+	game.AccumulatedTime = 0
+	game.markDayBoundary(start.Add(3 * time.Hour))
+	if len(game.DayMarks) != 0 || !game.SessionDay.IsZero() {
+		t.Fatalf("marks should be cleared without a session: %+v", game)
+	}
+}
+
+func TestProcessMonitorServiceSplitsSessionAtMidnight(t *testing.T) {
+	t.Parallel()
+
+	var saved []domain.PlaySession
+	var updated domain.Game
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			saved = append(saved, session)
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game", TotalPlayTime: 1000}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) {
+			updated = game
+			return &game, nil
+		},
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetSplitAtMidnight(true)
+	bus := NewEventBus(newTestLogger())
+	service.SetEventBus(bus)
+	var ended []SessionEndedEvent
+	bus.Subscribe(EventSessionEnded, func(event Event) { ended = append(ended, event.Payload.(SessionEndedEvent)) })
+
+	endedAt := time.Now()
+	game := MonitoringGame{
+		GameID:          "game-1",
+		GameTitle:       "Game",
+		ExeName:         "game.exe",
+		AccumulatedTime: 5400,
+		DayMarks:        []dayMark{{EndedAt: endedAt.Add(-time.Hour), Seconds: 1800}},
+	}
+	service.saveSession(game, endedAt)
+
+	if len(saved) != 2 || saved[0].Duration != 1800 || saved[1].Duration != 3600 {
+		t.Fatalf("unexpected saved sessions: %+v", saved)
+	}
+	if !saved[1].PlayedAt.Equal(endedAt) {
+		t.Fatalf("last part should end at the session end: %v", saved[1].PlayedAt)
+	}
+	if updated.TotalPlayTime != 1000+5400 {
+		t.Fatalf("total play time should include the whole session, got %d", updated.TotalPlayTime)
+	}
+	bus.Wait()
+	if len(ended) != 1 || ended[0].GameID != "game-1" || ended[0].Duration != 5400 {
+		t.Fatalf("expected one session.ended for the whole session, got %+v", ended)
+	}
+
+	saved = nil
+	service.SetSplitAtMidnight(false)
+	service.saveSession(game, endedAt)
+	if len(saved) != 1 || saved[0].Duration != 5400 {
+		t.Fatalf("session should not be split when disabled: %+v", saved)
+	}
+}
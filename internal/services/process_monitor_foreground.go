@@ -0,0 +1,127 @@
+// 前面表示中だけプレイ時間を数えるゲームの、背面時の計測停止と再開を提供する。
+package services
+
+import (
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// foregroundWindow は監視周期ごとに取得した前面ウィンドウの所有プロセス。
+// known が false のとき（取得失敗・非Windows）は前面判定を行わず、プロセス検出どおりに数える。
+type foregroundWindow struct {
+	pid   int
+	path  string
+	known bool
+}
+
+// SetGameTrackingMode は監視中のゲームのプレイ時間の数え方を即座に反映する。
+// 監視していないゲームは次に検出した時点で DB の設定が使われる。
+func (service *ProcessMonitorService) SetGameTrackingMode(gameID string, mode domain.TrackingMode) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	game, exists := service.monitoredGames[gameID]
+	if !exists {
+		return
+	}
+	game.ForegroundOnly = mode == domain.TrackingModeForeground
+	if !game.ForegroundOnly {
+		game.resumeFromBackground(time.Now())
+	}
+}
+
+// currentForeground は前面表示のみで数えるゲームを監視している場合に限り、前面ウィンドウを取得する。
+// OS への問い合わせを伴うため service.mu を保持せずに呼ぶ。
+func (service *ProcessMonitorService) currentForeground() foregroundWindow {
+	service.mu.Lock()
+	needed := false
+	for _, game := range service.monitoredGames {
+		if game.ForegroundOnly {
+			needed = true
+			break
+		}
+	}
+	provider := service.foregroundProvider
+	service.mu.Unlock()
+	if !needed || provider == nil {
+		return foregroundWindow{}
+	}
+	pid, path, err := provider()
+	if err != nil {
+		service.logger.Debug("前面ウィンドウの取得に失敗", "error", err)
+		return foregroundWindow{}
+	}
+	return foregroundWindow{pid: pid, path: path, known: true}
+}
+
+// applyForeground はプロセス検出中のゲームについて、背面にある間の計測停止と前面に戻ったときの再開を反映する。
+// matching はそのゲームに一致したプロセス。service.mu を保持した状態で呼ばれる前提。
+func (service *ProcessMonitorService) applyForeground(
+	game *MonitoringGame,
+	matching []normalizedProcess,
+	foreground foregroundWindow,
+	now time.Time,
+) {
+	if !game.ForegroundOnly || game.IsPaused || game.PendingEnd {
+		return
+	}
+	if !foreground.known || service.isForegroundGame(game, matching, foreground) {
+		if game.BackgroundSince != nil {
+			game.resumeFromBackground(now)
+			service.logger.Info("ゲームが前面に戻ったためプレイ時間の計測を再開", "title", game.GameTitle)
+		}
+		return
+	}
+	if game.BackgroundSince != nil {
+		return
+	}
+	if game.PlayStartTime != nil {
+		if duration := int64(now.Sub(*game.PlayStartTime).Seconds()); duration > 0 {
+			game.AccumulatedTime += duration
+		}
+		game.PlayStartTime = nil
+	}
+	game.BackgroundSince = &now
+	service.logger.Info("ゲームが背面にあるためプレイ時間の計測を停止", "title", game.GameTitle)
+}
+
+// isForegroundGame は前面ウィンドウがゲームのプロセスのものかを返す。
+// PID で一致を見て、PID が取れない（別プロセスのウィンドウ等）場合は実行ファイルのパスで比べる。
+func (service *ProcessMonitorService) isForegroundGame(
+	game *MonitoringGame,
+	matching []normalizedProcess,
+	foreground foregroundWindow,
+) bool {
+	for _, proc := range matching {
+		if proc.info.Pid == foreground.pid && service.matchGameProcess(game.ExeName, game.ExePath, proc) &&
+			service.matchCommandLine(game.Matcher, proc) {
+			return true
+		}
+	}
+	if foreground.path == "" {
+		return false
+	}
+	return normalizeProcessPathToken(foreground.path) == normalizeProcessPathToken(game.ExePath)
+}
+
+// resumeFromBackground は背面による停止を解除する。無操作で停止中なら再開は無操作側に任せる。
+func (game *MonitoringGame) resumeFromBackground(now time.Time) {
+	if game.BackgroundSince == nil {
+		return
+	}
+	game.BackgroundSince = nil
+	if game.IdleSince == nil && !game.IsPaused && !game.PendingEnd {
+		game.PlayStartTime = &now
+	}
+}
+
+// suspended は無操作または背面のため計測を止めているかを返す。このとき PlayStartTime は nil になる。
+func (game *MonitoringGame) suspended() bool {
+	return game.IdleSince != nil || game.BackgroundSince != nil
+}
+
+// endSuspension はセッションの区切り（中断・終了・保存）で停止状態を片付ける。無操作区間は IdleTime に加える。
+func (game *MonitoringGame) endSuspension(at time.Time) {
+	game.closeIdle(at)
+	game.BackgroundSince = nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestProcessMonitorServiceForegroundOnlyStopsWhileInBackground(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	game := &MonitoringGame{GameID: "game-1", GameTitle: "Game", ExeName: "game.exe", ExePath: `C:\games\game.exe`, ForegroundOnly: true}
+	service.monitoredGames[game.GameID] = game
+	running := map[string][]normalizedProcess{
+		normalizeProcessToken("game.exe"): normalizeProcessList([]ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}),
+	}
+	front := userActivity{foreground: foregroundWindow{pid: 20, known: true}}
+	behind := userActivity{foreground: foregroundWindow{pid: 99, path: `C:\Windows\explorer.exe`, known: true}}
+
+	service.updateMonitoredGameState(game, running, front, start)
+	service.updateMonitoredGameState(game, running, behind, start.Add(10*time.Minute))
+	if game.BackgroundSince == nil || game.PlayStartTime != nil {
+		t.Fatalf("expected tracking to stop in background, got %#v", game)
+	}
+	if game.AccumulatedTime != int64((10 * time.Minute).Seconds()) {
+		t.Fatalf("expected 10 minutes in foreground, got %d", game.AccumulatedTime)
+	}
+
+	// 背面のまま30分。計測は止まったまま。
+	service.updateMonitoredGameState(game, running, behind, start.Add(40*time.Minute))
+	front.foreground = foregroundWindow{pid: 0, path: `C:\Games\GAME.exe`, known: true}
+	service.updateMonitoredGameState(game, running, front, start.Add(40*time.Minute))
+	if game.BackgroundSince != nil || game.PlayStartTime == nil || !game.PlayStartTime.Equal(start.Add(40*time.Minute)) {
+		t.Fatalf("expected tracking to resume when in front again, got %#v", game)
+	}
+	if game.AccumulatedTime != int64((10 * time.Minute).Seconds()) {
+		t.Fatalf("expected background time to be excluded, got %d", game.AccumulatedTime)
+	}
+}
+
+func TestProcessMonitorServiceForegroundUnknownKeepsTracking(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	game := &MonitoringGame{GameID: "game-1", ExeName: "game.exe", ExePath: `C:\games\game.exe`, ForegroundOnly: true}
+	service.monitoredGames[game.GameID] = game
+	running := map[string][]normalizedProcess{
+		normalizeProcessToken("game.exe"): normalizeProcessList([]ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}),
+	}
+
+	service.updateMonitoredGameState(game, running, userActivity{}, start)
+	service.updateMonitoredGameState(game, running, userActivity{}, start.Add(time.Minute))
+	if game.BackgroundSince != nil || game.PlayStartTime == nil {
+		t.Fatalf("expected tracking to continue without foreground info, got %#v", game)
+	}
+}
+
+func TestProcessMonitorServiceCurrentForegroundOnlyWhenNeeded(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	calls := 0
+	service.foregroundProvider = func() (int, string, error) {
+		calls++
+		return 20, `C:\games\game.exe`, nil
+	}
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1"}
+	if fg := service.currentForeground(); fg.known || calls != 0 {
+		t.Fatalf("expected no lookup without foreground-only games, got %#v (%d calls)", fg, calls)
+	}
+
+	service.SetGameTrackingMode("game-1", domain.TrackingModeForeground)
+	if fg := service.currentForeground(); !fg.known || fg.pid != 20 || calls != 1 {
+		t.Fatalf("expected foreground lookup, got %#v (%d calls)", fg, calls)
+	}
+}
+
+func TestGameServiceSetTrackingModeValidates(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeGameRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, TrackingMode: domain.TrackingModeProcess}, nil
+		},
+	}
+	service := NewGameService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	if _, err := service.SetTrackingMode(ctx, "game-1", "window"); err == nil {
+		t.Fatalf("expected invalid mode to be rejected")
+	}
+	game, err := service.SetTrackingMode(ctx, "game-1", " Foreground ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repository.trackingMode != domain.TrackingModeForeground || game.TrackingMode != domain.TrackingModeForeground {
+		t.Fatalf("expected foreground mode to be saved, got %q", repository.trackingMode)
+	}
+}
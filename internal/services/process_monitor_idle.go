@@ -0,0 +1,115 @@
+// 無操作（AFK）中のプレイ時間計測の一時停止と再開を提供する。
+package services
+
+import "time"
+
+// maxIdleThreshold は設定できる無操作しきい値の上限。
+const maxIdleThreshold = 4 * time.Hour
+
+// SetIdleThreshold は無操作とみなすまでの時間を設定する。0 以下で無操作検出を無効にする。
+// 無効にした時点で無操作中のゲームは計測を再開する。
+func (service *ProcessMonitorService) SetIdleThreshold(threshold time.Duration) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	if threshold > maxIdleThreshold {
+		threshold = maxIdleThreshold
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.idleThreshold = threshold
+	if threshold > 0 {
+		return
+	}
+	now := time.Now()
+	for _, game := range service.monitoredGames {
+		if game.IdleSince != nil {
+			game.closeIdle(now)
+			if game.BackgroundSince == nil {
+				game.PlayStartTime = &now
+			}
+		}
+	}
+}
+
+// currentIdle は最後の入力からの経過時間を返す。無操作検出が無効、または取得に失敗した場合は ok=false。
+// OS への問い合わせを伴うため service.mu を保持せずに呼ぶ。
+func (service *ProcessMonitorService) currentIdle() (time.Duration, bool) {
+	service.mu.Lock()
+	threshold := service.idleThreshold
+	provider := service.idleProvider
+	service.mu.Unlock()
+	if threshold <= 0 || provider == nil {
+		return 0, false
+	}
+	idle, err := provider()
+	if err != nil {
+		service.logger.Debug("無操作時間の取得に失敗", "error", err)
+		return 0, false
+	}
+	if idle < 0 {
+		return 0, false
+	}
+	return idle, true
+}
+
+// userActivity は監視周期ごとに取得したユーザーの操作状況（最後の入力からの経過時間と前面ウィンドウ）。
+// idleKnown が false のときは無操作検出が無効か、経過時間を取得できなかったことを表す。
+type userActivity struct {
+	idle       time.Duration
+	idleKnown  bool
+	foreground foregroundWindow
+}
+
+// applyIdle はプロセス検出中のゲームについて、無操作による計測の停止・再開を反映する。
+// service.mu を保持した状態で呼ばれる前提。
+// 停止時は最後の入力時刻までを加算し、再開時は入力が戻った時刻から計測を始めるため、
+// しきい値に達するまでの無操作時間もプレイ時間には含めない。
+func (service *ProcessMonitorService) applyIdle(game *MonitoringGame, idle time.Duration, known bool, now time.Time) {
+	if game.IsPaused || game.PendingEnd {
+		return
+	}
+	if game.IdleSince == nil {
+		if !known || game.PlayStartTime == nil || idle < service.idleThreshold {
+			return
+		}
+		idleStart := now.Add(-idle)
+		if idleStart.Before(*game.PlayStartTime) {
+			idleStart = *game.PlayStartTime
+		}
+		if duration := int64(idleStart.Sub(*game.PlayStartTime).Seconds()); duration > 0 {
+			game.AccumulatedTime += duration
+		}
+		game.PlayStartTime = nil
+		game.IdleSince = &idleStart
+		service.logger.Info("無操作のためプレイ時間の計測を停止", "title", game.GameTitle, "idleSince", idleStart)
+		return
+	}
+	// 取得に失敗した場合や検出を無効にした場合も、時間を失わないよう計測を再開する。
+	resumedAt := now
+	if known {
+		if idle >= service.idleThreshold {
+			return
+		}
+		resumedAt = now.Add(-idle)
+		if resumedAt.Before(*game.IdleSince) {
+			resumedAt = *game.IdleSince
+		}
+	}
+	game.closeIdle(resumedAt)
+	if game.BackgroundSince == nil {
+		game.PlayStartTime = &resumedAt
+	}
+	service.logger.Info("入力が戻ったためプレイ時間の計測を再開", "title", game.GameTitle, "idleTime", game.IdleTime)
+}
+
+// closeIdle は無操作区間を at で閉じ、その長さを IdleTime に加える。無操作中でなければ何もしない。
+func (game *MonitoringGame) closeIdle(at time.Time) {
+	if game.IdleSince == nil {
+		return
+	}
+	if at.After(*game.IdleSince) {
+		game.IdleTime += int64(at.Sub(*game.IdleSince).Seconds())
+	}
+	game.IdleSince = nil
+}
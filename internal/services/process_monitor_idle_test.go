@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestProcessMonitorServiceIdlePausesAndResumesTracking(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	service.idleThreshold = 5 * time.Minute
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	game := &MonitoringGame{GameID: "game-1", GameTitle: "Game", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+	service.monitoredGames[game.GameID] = game
+	running := map[string][]normalizedProcess{
+		normalizeProcessToken("game.exe"): normalizeProcessList([]ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}),
+	}
+
+	service.updateMonitoredGameState(game, running, userActivity{idleKnown: true}, start)
+	// 20分プレイ → 最後の入力から6分経過（しきい値超え）。最後の入力時刻までを計測する。
+	service.updateMonitoredGameState(game, running, userActivity{idle: 6 * time.Minute, idleKnown: true}, start.Add(26*time.Minute))
+	if game.IdleSince == nil || game.PlayStartTime != nil {
+		t.Fatalf("expected game to be idle, got %#v", game)
+	}
+	if game.AccumulatedTime != int64((20 * time.Minute).Seconds()) {
+		t.Fatalf("expected 20 minutes before idle, got %d", game.AccumulatedTime)
+	}
+
+	status := service.GetMonitoringStatus()
+	if len(status) != 1 || !status[0].IsIdle || status[0].IsPlaying {
+		t.Fatalf("expected idle status, got %#v", status)
+	}
+
+	// 40分後に入力が戻った（直近の入力は10秒前）。無操作区間は IdleTime に入る。
+	service.updateMonitoredGameState(game, running, userActivity{idle: 10 * time.Second, idleKnown: true}, start.Add(60*time.Minute+10*time.Second))
+	if game.IdleSince != nil || game.PlayStartTime == nil {
+		t.Fatalf("expected tracking to resume, got %#v", game)
+	}
+	if !game.PlayStartTime.Equal(start.Add(60 * time.Minute)) {
+		t.Fatalf("expected resume at last input, got %v", game.PlayStartTime)
+	}
+	if game.IdleTime != int64((40 * time.Minute).Seconds()) {
+		t.Fatalf("expected 40 minutes idle, got %d", game.IdleTime)
+	}
+}
+
+func TestProcessMonitorServiceIdleIgnoredWhenDisabledOrUnknown(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	game := &MonitoringGame{GameID: "game-1", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+	service.monitoredGames[game.GameID] = game
+	running := map[string][]normalizedProcess{
+		normalizeProcessToken("game.exe"): normalizeProcessList([]ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}),
+	}
+
+	service.updateMonitoredGameState(game, running, userActivity{}, start)
+	service.updateMonitoredGameState(game, running, userActivity{idle: time.Hour}, start.Add(time.Hour))
+	if game.IdleSince != nil || game.PlayStartTime == nil {
+		t.Fatalf("expected tracking to continue without idle info, got %#v", game)
+	}
+
+	service.idleProvider = func() (time.Duration, error) { return time.Hour, nil }
+	if _, ok := service.currentIdle(); ok {
+		t.Fatalf("expected idle detection to be disabled by default")
+	}
+}
+
+func TestProcessMonitorServiceEndSessionRecordsIdleDuration(t *testing.T) {
+	t.Parallel()
+
+	var saved []domain.PlaySession
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			saved = append(saved, session)
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game"}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	idleSince := time.Now().Add(-10 * time.Minute)
+	service.monitoredGames["game-1"] = &MonitoringGame{
+		GameID:          "game-1",
+		GameTitle:       "Game",
+		ExeName:         "game.exe",
+		AccumulatedTime: 600,
+		IdleSince:       &idleSince,
+		IdleTime:        60,
+	}
+
+	if !service.EndSession("game-1") {
+		t.Fatalf("expected end session to succeed")
+	}
+	if len(saved) != 1 {
+		t.Fatalf("expected one saved session, got %d", len(saved))
+	}
+	if saved[0].Duration != 600 {
+		t.Fatalf("expected idle time excluded from duration, got %d", saved[0].Duration)
+	}
+	if saved[0].IdleDuration < 660 {
+		t.Fatalf("expected idle duration to include open idle gap, got %d", saved[0].IdleDuration)
+	}
+	game := service.monitoredGames["game-1"]
+	if game.IdleSince != nil || game.IdleTime != 0 {
+		t.Fatalf("expected idle state to reset, got %#v", game)
+	}
+}
+
+func TestProcessMonitorServiceDisablingIdleResumesTracking(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	service.SetIdleThreshold(10 * time.Minute)
+	idleSince := time.Now().Add(-time.Minute)
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1", IdleSince: &idleSince}
+
+	service.SetIdleThreshold(0)
+	game := service.monitoredGames["game-1"]
+	if game.IdleSince != nil || game.PlayStartTime == nil || game.IdleTime < 60 {
+		t.Fatalf("expected tracking to resume, got %#v", game)
+	}
+}
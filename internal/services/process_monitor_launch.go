@@ -0,0 +1,65 @@
+// アプリから起動したゲームをプロセス監視へ直接登録し、次のスキャンを待たずに計測を始める処理を提供する。
+package services
+
+import (
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// AttachLaunchedGame は LaunchGame で起動したゲームを起動したプロセスの PID とともに監視へ登録し、その場でセッションを始める。
+// 起動ラッパー経由ではゲーム本体と実行ファイル名が一致しないため、PID のプロセスが動いている間も起動中とみなす。
+// 自動計測から外したゲームは登録しない。監視中のゲームは状態を変えず、PID だけを記録する。
+func (service *ProcessMonitorService) AttachLaunchedGame(game domain.Game, pid int) bool {
+	if game.AutoTrackingExcluded || pid <= 0 {
+		return false
+	}
+	matcher, ok := service.gameProcessMatcher(game.ID, game.ProcessMatchPattern)
+	if !ok {
+		return false
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	monitored, exists := service.monitoredGames[game.ID]
+	if !exists {
+		service.addMonitoredGame(game.ID, game.Title, game.ExePath)
+		monitored = service.monitoredGames[game.ID]
+		monitored.ForegroundOnly = game.TrackingMode == domain.TrackingModeForeground
+		monitored.Matcher = matcher
+	}
+	monitored.LaunchedPID = pid
+	now := time.Now()
+	monitored.LastDetected = &now
+	monitored.LastNotFound = nil
+	if monitored.PlayStartTime == nil && !monitored.suspended() && !monitored.IsPaused && !monitored.PendingEnd {
+		monitored.PlayStartTime = &now
+		monitored.AccumulatedTime = 0
+		monitored.IdleTime = 0
+		monitored.Usage = resourceUsage{}
+		monitored.resetDayMarks()
+		monitored.PostExitDone = false
+		service.logger.Info("起動したゲームの計測を開始", "title", game.Title, "pid", pid, "gameId", game.ID)
+	}
+	service.lastTrackedGameID = game.ID
+	service.lastTrackedAt = now
+	return true
+}
+
+// launchedProcess は起動時に登録した PID のプロセスを探す。見つからなければ PID を忘れる
+// （終了後に PID が再利用されても別のプロセスをゲームとみなさないため）。
+// service.mu を保持した状態で呼ばれる前提。
+func launchedProcess(game *MonitoringGame, processMap map[string][]normalizedProcess) (normalizedProcess, bool) {
+	if game.LaunchedPID == 0 {
+		return normalizedProcess{}, false
+	}
+	for _, processes := range processMap {
+		for _, proc := range processes {
+			if proc.info.Pid == game.LaunchedPID {
+				return proc, true
+			}
+		}
+	}
+	game.LaunchedPID = 0
+	return normalizedProcess{}, false
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestProcessMonitorServiceAttachLaunchedGameStartsSessionImmediately(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	game := domain.Game{ID: "game-1", Title: "Game", ExePath: `C:\games\game.exe`, TrackingMode: domain.TrackingModeForeground}
+
+	if !service.AttachLaunchedGame(game, 4242) {
+		t.Fatal("expected launched game to be attached")
+	}
+	monitored := service.monitoredGames["game-1"]
+	if monitored == nil || monitored.PlayStartTime == nil || monitored.LaunchedPID != 4242 || !monitored.ForegroundOnly {
+		t.Fatalf("expected session to start with the launched pid, got %#v", monitored)
+	}
+	if gameID, _ := service.LastTrackedGame(); gameID != "game-1" {
+		t.Fatalf("expected last tracked game to be updated, got %q", gameID)
+	}
+
+	excluded := domain.Game{ID: "game-2", ExePath: `C:\tools\emu.exe`, AutoTrackingExcluded: true}
+	if service.AttachLaunchedGame(excluded, 100) {
+		t.Fatal("expected games excluded from auto tracking not to be attached")
+	}
+}
+
+func TestProcessMonitorServiceAttachLaunchedGameKeepsRunningSession(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	start := time.Now().Add(-time.Hour)
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1", ExeName: "game.exe", PlayStartTime: &start, AccumulatedTime: 30}
+
+	service.AttachLaunchedGame(domain.Game{ID: "game-1", ExePath: `C:\games\game.exe`}, 7)
+	monitored := service.monitoredGames["game-1"]
+	if !monitored.PlayStartTime.Equal(start) || monitored.AccumulatedTime != 30 || monitored.LaunchedPID != 7 {
+		t.Fatalf("expected running session to be kept, got %#v", monitored)
+	}
+}
+
+func TestProcessMonitorServiceLaunchedPIDCountsAsRunningUntilItExits(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	service.AttachLaunchedGame(domain.Game{ID: "game-1", Title: "Game", ExePath: `C:\games\game.exe`}, 55)
+	game := service.monitoredGames["game-1"]
+
+	// 起動ラッパーなど、ゲーム本体と名前が違うプロセスでも PID が一致すれば起動中とみなす。
+	wrapper := map[string][]normalizedProcess{
+		"leproc.exe": {{info: ProcessInfo{Name: "LEProc.exe", Pid: 55}, normalized: "leproc.exe"}},
+	}
+	now := time.Now()
+	service.updateMonitoredGameState(game, wrapper, userActivity{}, now)
+	if game.PlayStartTime == nil || game.LastNotFound != nil || game.PendingEnd {
+		t.Fatalf("expected launched process to keep the session running, got %#v", game)
+	}
+
+	service.updateMonitoredGameState(game, map[string][]normalizedProcess{}, userActivity{}, now.Add(time.Second))
+	if game.LaunchedPID != 0 || !game.PendingEnd {
+		t.Fatalf("expected pid to be forgotten once the process exits, got %#v", game)
+	}
+}
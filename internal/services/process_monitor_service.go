@@ -5,14 +5,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
-	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
@@ -25,6 +26,15 @@ import (
 	"golang.org/x/text/unicode/norm"
 )
 
+const (
+	// sessionSaveTimeout はセッション1件の保存（DB 書き込み）の上限。超えたら退避して後で再試行する。
+	sessionSaveTimeout = 30 * time.Second
+	// monitorQueryTimeout は監視ループから行う DB の読み書きや PowerShell・wmic の実行1回の上限。
+	monitorQueryTimeout = 5 * time.Second
+	// gamesCacheTTL は自動検出に使うゲーム一覧のキャッシュを、書き込みの通知が無くても読み直すまでの時間。
+	gamesCacheTTL = 30 * time.Second
+)
+
 // MonitoringGame は監視対象のゲーム情報を保持する。
 type MonitoringGame struct {
 	GameID          string
@@ -40,6 +50,32 @@ type MonitoringGame struct {
 	PendingEnd      bool
 	PendingResume   bool
 	SuppressResume  bool
+	// Partial はアプリ起動前から起動していたため、開始時刻がアプリ起動時刻になっていることを表す。
+	Partial bool
+	// WarmupStartedAt は初回スキャンで検出した時刻（開始時刻の見積もりの基準、Partial 時のみ）。
+	WarmupStartedAt *time.Time
+	// NeedsStartEstimate は prompt 方針で開始時刻の見積もりを待っていることを表す。
+	NeedsStartEstimate bool
+	// IdleSince は無操作のため計測を止めた時刻（最後の入力時刻）。無操作中は PlayStartTime が nil になる。
+	IdleSince *time.Time
+	// IdleTime は現在のセッションで無操作のため計測から除いた時間（秒）。
+	IdleTime int64
+	// ForegroundOnly はウィンドウが前面にある間だけ数えるゲームか（TrackingModeForeground）。
+	// BackgroundSince は背面にあるため計測を止めた時刻。停止中は PlayStartTime が nil になる。
+	ForegroundOnly  bool
+	BackgroundSince *time.Time
+	// Matcher はコマンドラインの一致条件（エミュレーターの ROM 等、nil なら実行ファイルだけで判定）。
+	Matcher *processMatcher
+	// LaunchedPID は LaunchGame で起動したプロセスの PID（0 なら無し）。実行ファイル名で一致しない間も起動中とみなす。
+	LaunchedPID int
+	// RunningPIDs は直近のスキャンで一致したプロセスの PID 群、Usage はこのセッションで採った CPU・メモリの使用量。
+	RunningPIDs []int
+	Usage       resourceUsage
+	// SessionDay はセッションの集計中の日付（ローカル時刻の 0:00）、DayMarks はセッション中に日付が変わった時点の累計。
+	SessionDay time.Time
+	DayMarks   []dayMark
+	// PostExitDone は今回のプレイの終了後コマンドを実行へ回したこと。次に計測を始めたときに戻す。
+	PostExitDone bool
 }
 
 // ProcessInfo はプロセス情報を保持する。
@@ -72,16 +108,10 @@ func normalizeProcessList(processes []ProcessInfo) []normalizedProcess {
 	return normalizedProcesses
 }
 
-// afterPlaySyncer はプレイ終了後の自動 Push を抽象化するインターフェース。
-type afterPlaySyncer interface {
-	Push(ctx context.Context, gameID string, onProgress ProgressFunc) error
-}
-
 // ProcessMonitorService はゲームプロセス監視を提供する。
 type ProcessMonitorService struct {
 	repository         ProcessMonitorRepository
 	logger             *slog.Logger
-	cloudSync          afterPlaySyncer
 	processProvider    func() ([]ProcessInfo, string)
 	monitoredGames     map[string]*MonitoringGame
 	autoTracking       bool
@@ -95,29 +125,134 @@ type ProcessMonitorService struct {
 	// 監視ループが定期更新するため、ホットキー撮影時の再列挙をほぼ不要にする。
 	lastProcesses   []ProcessInfo
 	lastProcessesAt time.Time
+	// lastTrackedGameID / lastTrackedAt は最後にプロセスを検出したゲームと日時（service.mu で保護）。
+	// 終了確認待ちや監視解除で MonitoringGame から検出日時が消えた後も保持する。
+	lastTrackedGameID string
+	lastTrackedAt     time.Time
+	// spoolDir は保存に失敗したセッションの退避先。spoolMu で保護し、退避ファイルの操作も直列化する。
+	spoolMu        sync.Mutex
+	spoolDir       string
+	lastSpoolRetry time.Time
+	// withTx はセッション作成とゲームの累計更新をまとめるトランザクション（nil なら非トランザクション）。
+	withTx TxRunner[ProcessMonitorRepository]
+	// warmupPolicy は初回スキャンで検出したゲームの扱い（service.mu で保護）。
+	// firstScanDone はプロセス一覧を取得できた最初のスキャンが済んだか（service.mu で保護）。
+	warmupPolicy   string
+	firstScanDone  bool
+	warmupListener func(gameIDs []string)
+	// scanListener はスキャンごとに監視状態を受け取る通知先（service.mu で保護、nil 可）。プレイ時間のリマインダーに使う。
+	scanListener func(statuses []domain.MonitoringGameStatus)
+	// idleThreshold は無操作とみなすまでの時間（service.mu で保護、0 で無効）。
+	// idleProvider は最後の入力からの経過時間の取得（テストで差し替える）。
+	idleThreshold time.Duration
+	idleProvider  func() (time.Duration, error)
+	// foregroundProvider は前面ウィンドウの所有プロセスの取得（テストで差し替える）。
+	foregroundProvider func() (int, string, error)
+	// commandLines は一致条件の判定に使ったプロセスのコマンドラインのキャッシュ（commandLineMu で保護）。
+	// commandLineProvider は PID からコマンドラインを取得する（テストで差し替える）。
+	commandLineMu       sync.Mutex
+	commandLines        map[commandLineKey]string
+	commandLineProvider func(pid int) (string, error)
+	// hookRunner は終了後コマンドの実行（テストで差し替える）。
+	hookRunner launchHookRunner
+	// usageProvider は PID 群の CPU 時間・メモリ使用量の取得、cpuCount は使用率の分母にする論理コア数（テストで差し替える）。
+	usageProvider func(pids []int) (processUsage, error)
+	cpuCount      int
+	// splitAtMidnight は日付をまたいだセッションを 0:00 で分けて保存するか。
+	// saveSession は service.mu を保持したまま呼ばれることがあるため atomic にする。
+	splitAtMidnight atomic.Bool
+	// ctx は StartMonitoring で受け取ったアプリのコンテキスト（ctxMu で保護、nil なら Background）。
+	// 監視ループや保存・同期の goroutine はここから期限付きのコンテキストを作り、アプリの終了で打ち切られる。
+	// saveSession が service.mu を保持したまま参照するため、別のロックで守る。
+	ctxMu sync.Mutex
+	ctx   context.Context
+	// tasks は終了後コマンドの goroutine を数える（nil なら追跡しない）。
+	// アプリの終了時にこれらが終わるのを待ってから DB を閉じる。
+	tasks *BackgroundTasks
+	// events は記録したセッションとゲームの累計の更新を知らせるイベントバス（nil 可）。
+	events *EventBus
+	// gamesCache は自動検出に使うゲーム一覧のスナップショット（gamesCacheMu で保護）。監視ループは数秒ごとに
+	// 走るため、gamesCacheTTL が過ぎるか InvalidateGamesCache で破棄されるまで DB を読み直さない。
+	// gamesCacheGeneration は破棄のたびに増え、読み直しの途中で破棄された一覧を保存しないために使う。
+	gamesCacheMu         sync.Mutex
+	gamesCache           []domain.Game
+	gamesCacheValid      bool
+	gamesCachedAt        time.Time
+	gamesCacheGeneration uint64
 }
 
 // NewProcessMonitorService は ProcessMonitorService を生成する。
-func NewProcessMonitorService(repository ProcessMonitorRepository, logger *slog.Logger, cloudSync afterPlaySyncer) *ProcessMonitorService {
+func NewProcessMonitorService(repository ProcessMonitorRepository, logger *slog.Logger) *ProcessMonitorService {
 	return &ProcessMonitorService{
-		repository:         repository,
-		logger:             logger,
-		cloudSync:          cloudSync,
-		monitoredGames:     make(map[string]*MonitoringGame),
-		autoTracking:       true,
-		interval:           2 * time.Second,
-		sessionTimeout:     0,
-		gameCleanupTimeout: 20 * time.Second,
+		repository:          repository,
+		logger:              logger,
+		monitoredGames:      make(map[string]*MonitoringGame),
+		autoTracking:        true,
+		interval:            2 * time.Second,
+		sessionTimeout:      0,
+		gameCleanupTimeout:  20 * time.Second,
+		warmupPolicy:        WarmupPolicyPartial,
+		idleProvider:        systemIdleDuration,
+		foregroundProvider:  foregroundProcess,
+		commandLineProvider: processCommandLine,
+		hookRunner:          runShellCommand,
+		usageProvider:       sampleProcessUsage,
+		cpuCount:            runtime.NumCPU(),
+	}
+}
+
+// SetBackgroundTasks は終了後コマンドの goroutine を数える先を設定する。
+// Close された後は新しい同期やコマンドを始めない。
+func (service *ProcessMonitorService) SetBackgroundTasks(tasks *BackgroundTasks) {
+	service.tasks = tasks
+}
+
+// SetEventBus は記録したセッションを EventSessionCreated、ゲームの累計の更新を EventGameUpdated で知らせる先を設定する。
+func (service *ProcessMonitorService) SetEventBus(bus *EventBus) {
+	service.events = bus
+}
+
+// SetTxRunner はセッション作成とゲームの累計プレイ時間更新をまとめるトランザクションを設定する。
+func (service *ProcessMonitorService) SetTxRunner(runner TxRunner[ProcessMonitorRepository]) {
+	service.withTx = runner
+}
+
+// SetInterval は監視間隔を更新する。監視中ならティッカーも即座に差し替える。
+func (service *ProcessMonitorService) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.interval = interval
+	if service.monitoringInterval != nil {
+		service.monitoringInterval.Reset(interval)
+	}
+}
+
+// SetTimeouts は終了確認待ちにするまでの未検出時間と、監視対象から外すまでの未検出時間を更新する。
+// sessionTimeout が 0 なら、プロセスが見つからなくなった最初のスキャンで終了確認待ちにする。
+func (service *ProcessMonitorService) SetTimeouts(sessionTimeout, cleanupTimeout time.Duration) {
+	if sessionTimeout < 0 || cleanupTimeout <= 0 {
+		return
 	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.sessionTimeout = sessionTimeout
+	service.gameCleanupTimeout = cleanupTimeout
 }
 
-// StartMonitoring は監視を開始する。
-func (service *ProcessMonitorService) StartMonitoring() {
+// StartMonitoring は監視を開始する。ctx はアプリの終了で取り消されるコンテキストで、
+// 以後のセッション保存やプレイ後の同期はこれを親にする。
+func (service *ProcessMonitorService) StartMonitoring(ctx context.Context) {
 	service.mu.Lock()
 	if service.monitoringInterval != nil {
 		service.mu.Unlock()
 		return
 	}
+	service.ctxMu.Lock()
+	service.ctx = ctx
+	service.ctxMu.Unlock()
 	service.monitoringStop = make(chan struct{})
 	service.monitoringInterval = time.NewTicker(service.interval)
 	service.mu.Unlock()
@@ -130,6 +265,7 @@ func (service *ProcessMonitorService) StartMonitoring() {
 		tick := func() {
 			defer logging.Recover(service.logger, "process-monitor.checkProcesses")
 			service.checkProcesses()
+			service.retrySpooledSessionsIfDue(time.Now())
 		}
 		tick()
 		for {
@@ -160,6 +296,16 @@ func (service *ProcessMonitorService) StopMonitoring() {
 	service.logger.Info("プロセス監視を停止しました")
 }
 
+// context は監視の親コンテキストを返す。StartMonitoring 前は Background を返す。
+func (service *ProcessMonitorService) context() context.Context {
+	service.ctxMu.Lock()
+	defer service.ctxMu.Unlock()
+	if service.ctx != nil {
+		return service.ctx
+	}
+	return context.Background()
+}
+
 // IsMonitoring は監視中かどうかを返す。
 func (service *ProcessMonitorService) IsMonitoring() bool {
 	service.mu.Lock()
@@ -178,6 +324,17 @@ func (service *ProcessMonitorService) UpdateAutoTracking(enabled bool) {
 	}
 }
 
+// ExcludeGame は自動計測から外したゲームの監視をやめる。計測中・中断中ならそこまでのセッションを保存する。
+func (service *ProcessMonitorService) ExcludeGame(gameID string) {
+	if !service.EndSession(gameID) {
+		return
+	}
+	service.mu.Lock()
+	delete(service.monitoredGames, gameID)
+	service.mu.Unlock()
+	service.logger.Info("自動計測の除外によりゲーム監視を削除", "gameId", gameID)
+}
+
 // GetMonitoringStatus は監視状態を返す。
 func (service *ProcessMonitorService) GetMonitoringStatus() []domain.MonitoringGameStatus {
 	service.mu.Lock()
@@ -190,20 +347,48 @@ func (service *ProcessMonitorService) GetMonitoringStatus() []domain.MonitoringG
 		if game.PlayStartTime != nil && !game.IsPaused && !game.PendingEnd {
 			playTime += int64(now.Sub(*game.PlayStartTime).Seconds())
 		}
+		idleTime := game.IdleTime
+		if game.IdleSince != nil {
+			idleTime += int64(now.Sub(*game.IdleSince).Seconds())
+		}
+		usage := game.Usage.summary()
 		status = append(status, domain.MonitoringGameStatus{
-			GameID:            game.GameID,
-			GameTitle:         game.GameTitle,
-			ExeName:           game.ExeName,
-			IsPlaying:         game.PlayStartTime != nil && !game.IsPaused && !game.PendingEnd,
-			PlayTime:          playTime,
-			IsPaused:          game.IsPaused,
-			NeedsConfirmation: game.PendingEnd,
-			NeedsResume:       game.PendingResume,
+			GameID:             game.GameID,
+			GameTitle:          game.GameTitle,
+			ExeName:            game.ExeName,
+			IsPlaying:          game.PlayStartTime != nil && !game.IsPaused && !game.PendingEnd,
+			PlayTime:           playTime,
+			IsPaused:           game.IsPaused,
+			NeedsConfirmation:  game.PendingEnd,
+			NeedsResume:        game.PendingResume,
+			Partial:            game.Partial,
+			NeedsStartEstimate: game.NeedsStartEstimate,
+			IsIdle:             game.IdleSince != nil,
+			IsBackground:       game.BackgroundSince != nil,
+			IdleTime:           idleTime,
+			CPUPercent:         roundTenth(game.Usage.cpuPercent),
+			MemoryBytes:        game.Usage.memoryBytes,
+			CPUPeakPercent:     usage.CPUPeakPercent,
+			CPUAveragePercent:  usage.CPUAveragePercent,
+			MemoryPeakBytes:    usage.MemoryPeakBytes,
+			MemoryAverageBytes: usage.MemoryAverageBytes,
 		})
 	}
 	return status
 }
 
+// HasActiveSession はゲームのセッションが計測中・中断中・終了確認待ちなど、まだ保存されていない状態かを返す。
+func (service *ProcessMonitorService) HasActiveSession(gameID string) bool {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	game, exists := service.monitoredGames[gameID]
+	if !exists {
+		return false
+	}
+	return game.PlayStartTime != nil || game.AccumulatedTime > 0 || game.IsPaused || game.PendingEnd ||
+		game.IdleSince != nil || game.BackgroundSince != nil
+}
+
 // GetHotkeyTargetGameID はホットキー撮影時に保存先とするゲームIDを返す。
 // 監視中ゲームのうち「現在プレイ中」を優先し、なければ「中断中」を返す。
 func (service *ProcessMonitorService) GetHotkeyTargetGameID() string {
@@ -220,7 +405,7 @@ func (service *ProcessMonitorService) GetHotkeyTargetGameID() string {
 			continue
 		}
 
-		isPlaying := game.PlayStartTime != nil && !game.IsPaused
+		isPlaying := (game.PlayStartTime != nil || game.suspended()) && !game.IsPaused
 		if isPlaying {
 			if bestPlaying == nil || isLaterGameActivity(game, bestPlaying) {
 				bestPlaying = game
@@ -244,6 +429,14 @@ func (service *ProcessMonitorService) GetHotkeyTargetGameID() string {
 	return ""
 }
 
+// LastTrackedGame は直近に検出したゲーム（監視から外したものを含む）と最終検出日時を返す。
+// ホットキー撮影で対象ゲームが無いときに、終了・切り替え直後のゲームを優先するために使う。
+func (service *ProcessMonitorService) LastTrackedGame() (string, time.Time) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.lastTrackedGameID, service.lastTrackedAt
+}
+
 func isLaterGameActivity(left *MonitoringGame, right *MonitoringGame) bool {
 	leftTime := latestGameActivityAt(left)
 	rightTime := latestGameActivityAt(right)
@@ -315,6 +508,13 @@ func (service *ProcessMonitorService) removeMonitoredGame(gameID string) {
 		duration := int64(now.Sub(*game.PlayStartTime).Seconds())
 		if duration > 0 {
 			game.AccumulatedTime += duration
+			game.endSuspension(now)
+			service.saveSession(*game, now)
+		}
+	} else if game.suspended() {
+		now := time.Now()
+		game.endSuspension(now)
+		if game.AccumulatedTime > 0 {
 			service.saveSession(*game, now)
 		}
 	}
@@ -334,6 +534,7 @@ func (service *ProcessMonitorService) PauseSession(gameID string) bool {
 	if game.PlayStartTime != nil && !game.IsPaused {
 		game.AccumulatedTime += int64(now.Sub(*game.PlayStartTime).Seconds())
 	}
+	game.endSuspension(now)
 	game.PlayStartTime = nil
 	game.IsPaused = true
 	game.PendingEnd = false
@@ -354,7 +555,7 @@ func (service *ProcessMonitorService) ResumeSession(gameID string) bool {
 	if !exists {
 		return false
 	}
-	if !service.isGameProcessRunning(game.ExeName, game.ExePath, normalizedProcesses) {
+	if !service.isGameProcessRunning(game.ExeName, game.ExePath, game.Matcher, normalizedProcesses) {
 		return false
 	}
 	now := time.Now()
@@ -380,6 +581,7 @@ func (service *ProcessMonitorService) EndSession(gameID string) bool {
 	if game.PlayStartTime != nil {
 		game.AccumulatedTime += int64(now.Sub(*game.PlayStartTime).Seconds())
 	}
+	game.endSuspension(now)
 	game.PlayStartTime = nil
 	game.IsPaused = false
 	game.PendingEnd = false
@@ -394,11 +596,19 @@ func (service *ProcessMonitorService) EndSession(gameID string) bool {
 	// 一時的に書き戻してから再 Lock で 0 戻し、というかつての二重書きが原因だった）。
 	snapshot := *game
 	snapshot.AccumulatedTime = accumulated
+	game.IdleTime = 0
+	game.Usage = resourceUsage{}
+	game.resetDayMarks()
+	game.clearWarmup()
+	runHook := game.takePostExitHook()
 	service.mu.Unlock()
 
 	if accumulated > 0 {
 		service.saveSession(snapshot, now)
 	}
+	if runHook {
+		service.startPostExitHook(gameID)
+	}
 	return true
 }
 
@@ -406,8 +616,13 @@ func (service *ProcessMonitorService) checkProcesses() {
 	processes, _ := service.getProcesses()
 
 	normalizedProcesses := normalizeProcessList(processes)
+	if len(processes) > 0 {
+		service.pruneCommandLines(normalizedProcesses)
+	}
 
 	service.autoAddGamesFromDatabase(processes, normalizedProcesses)
+	activity := userActivity{foreground: service.currentForeground()}
+	activity.idle, activity.idleKnown = service.currentIdle()
 
 	processMap := make(map[string][]normalizedProcess)
 	for _, proc := range normalizedProcesses {
@@ -423,38 +638,93 @@ func (service *ProcessMonitorService) checkProcesses() {
 	gameIDsToCleanup := make([]string, 0)
 
 	service.mu.Lock()
+	// プロセス一覧を取れた最初のスキャンで見つかったゲームは、アプリ起動前から起動していたとみなす。
+	warmup := !service.firstScanDone && len(processes) > 0
+	warmupPrompts := make([]string, 0)
+	usageTargets := make([]usageTarget, 0)
 	for _, game := range service.monitoredGames {
-		service.updateMonitoredGameState(game, processMap, now)
+		wasIdle := game.PlayStartTime == nil
+		service.updateMonitoredGameState(game, processMap, activity, now)
+		game.markDayBoundary(now)
+		if warmup && wasIdle && game.PlayStartTime != nil && service.markWarmupGame(game, now) {
+			warmupPrompts = append(warmupPrompts, game.GameID)
+		}
+		if game.counting() && len(game.RunningPIDs) > 0 {
+			usageTargets = append(usageTargets, usageTarget{gameID: game.GameID, pids: game.RunningPIDs})
+		} else {
+			game.Usage.pause()
+		}
+	}
+	if len(processes) > 0 {
+		service.firstScanDone = true
 	}
 	gameIDsToCleanup = service.collectGameIDsToCleanup(now, gameIDsToCleanup)
+	warmupListener := service.warmupListener
+	scanListener := service.scanListener
 	service.mu.Unlock()
 
+	service.sampleUsage(usageTargets, now)
+
+	if len(warmupPrompts) > 0 && warmupListener != nil {
+		warmupListener(warmupPrompts)
+	}
+
 	for _, session := range sessionsToSave {
 		service.saveSession(session.Game, session.EndedAt)
 	}
 	for _, gameID := range gameIDsToCleanup {
 		service.mu.Lock()
+		runHook := false
+		if game, exists := service.monitoredGames[gameID]; exists {
+			runHook = game.takePostExitHook()
+		}
 		service.removeMonitoredGame(gameID)
 		service.mu.Unlock()
+		// セッションの終了時にまだ実行していなければ、監視から外れたところで終了後コマンドを実行する。
+		if runHook {
+			service.startPostExitHook(gameID)
+		}
+	}
+	if scanListener != nil {
+		scanListener(service.GetMonitoringStatus())
 	}
 }
 
+// SetScanListener はスキャンのたびに監視中のゲームの状態（累計プレイ時間を含む）を受け取る通知先を設定する。
+// 監視ループ上で呼ぶため、時間のかかる処理は fn の中で別ゴルーチンにする。
+func (service *ProcessMonitorService) SetScanListener(fn func(statuses []domain.MonitoringGameStatus)) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.scanListener = fn
+}
+
 // updateMonitoredGameState は 1 ゲーム分の検知状態を更新する。
 // service.mu を保持した状態で呼ばれる前提（ロックの取得/解放は呼び出し側）。
 // 元コードの paused-running 分岐における continue は、本メソッドでは早期 return に対応する。
 func (service *ProcessMonitorService) updateMonitoredGameState(
 	game *MonitoringGame,
 	processMap map[string][]normalizedProcess,
+	activity userActivity,
 	now time.Time,
 ) {
 	normalizedExeName := normalizeProcessToken(game.ExeName)
 	matching := processMap[normalizedExeName]
 	isRunning := false
 	if len(matching) > 0 {
-		isRunning = service.isGameProcessRunning(game.ExeName, game.ExePath, matching)
+		isRunning = service.isGameProcessRunning(game.ExeName, game.ExePath, game.Matcher, matching)
+	}
+	if !isRunning {
+		if launched, ok := launchedProcess(game, processMap); ok {
+			isRunning = true
+			matching = []normalizedProcess{launched}
+		}
 	}
 
+	game.RunningPIDs = nil
 	if isRunning {
+		game.RunningPIDs = processPIDs(matching)
+		service.lastTrackedGameID = game.GameID
+		service.lastTrackedAt = now
 		if game.IsPaused {
 			if !game.SuppressResume {
 				game.PendingResume = true
@@ -465,11 +735,19 @@ func (service *ProcessMonitorService) updateMonitoredGameState(
 		}
 		game.LastDetected = &now
 		game.LastNotFound = nil
-		if game.PlayStartTime == nil && !game.IsPaused && !game.PendingEnd {
+		if game.PlayStartTime == nil && !game.suspended() && !game.IsPaused && !game.PendingEnd {
 			game.PlayStartTime = &now
 			game.AccumulatedTime = 0
+			game.IdleTime = 0
+			game.Usage = resourceUsage{}
+			game.resetDayMarks()
+			game.PostExitDone = false
 			service.logger.Info("ゲーム開始を検知", "title", game.GameTitle, "exeName", game.ExeName)
+			// 開始を検知したスキャンでは停止しない（ウォームアップ判定を先に済ませる）。
+			return
 		}
+		service.applyIdle(game, activity.idle, activity.idleKnown, now)
+		service.applyForeground(game, matching, activity.foreground, now)
 	} else {
 		if game.PendingResume {
 			game.PendingResume = false
@@ -480,12 +758,15 @@ func (service *ProcessMonitorService) updateMonitoredGameState(
 		if game.LastNotFound == nil {
 			game.LastNotFound = &now
 		}
-		if game.PlayStartTime != nil && !game.IsPaused && !game.PendingEnd {
+		if (game.PlayStartTime != nil || game.suspended()) && !game.IsPaused && !game.PendingEnd {
 			if now.Sub(*game.LastDetected) > service.sessionTimeout {
-				duration := int64(now.Sub(*game.PlayStartTime).Seconds())
-				if duration > 0 {
-					game.AccumulatedTime += duration
+				if game.PlayStartTime != nil {
+					duration := int64(now.Sub(*game.PlayStartTime).Seconds())
+					if duration > 0 {
+						game.AccumulatedTime += duration
+					}
 				}
+				game.endSuspension(*game.LastDetected)
 				game.PlayStartTime = nil
 				game.PendingEnd = true
 				game.LastDetected = nil
@@ -499,7 +780,7 @@ func (service *ProcessMonitorService) updateMonitoredGameState(
 // service.mu を保持した状態で呼ばれる前提（ロックの取得/解放は呼び出し側）。
 func (service *ProcessMonitorService) collectGameIDsToCleanup(now time.Time, gameIDsToCleanup []string) []string {
 	for gameID, game := range service.monitoredGames {
-		if game.PlayStartTime == nil && game.LastNotFound != nil && !game.IsPaused && !game.PendingEnd {
+		if game.PlayStartTime == nil && !game.suspended() && game.LastNotFound != nil && !game.IsPaused && !game.PendingEnd {
 			if now.Sub(*game.LastNotFound) > service.gameCleanupTimeout {
 				gameIDsToCleanup = append(gameIDsToCleanup, gameID)
 			}
@@ -509,58 +790,112 @@ func (service *ProcessMonitorService) collectGameIDsToCleanup(now time.Time, gam
 }
 
 func (service *ProcessMonitorService) saveSession(game MonitoringGame, endedAt time.Time) {
-	sessionName := "自動記録 - " + game.ExeName
-	ctx := context.Background()
-	_, err := service.repository.CreatePlaySession(ctx, domain.PlaySession{
-		GameID:      game.GameID,
-		PlayedAt:    endedAt,
-		Duration:    game.AccumulatedTime,
-		SessionName: &sessionName,
-	})
-	if err != nil {
-		service.logger.Error("プレイセッション保存に失敗", "error", err)
-		return
+	usage := game.Usage.summary()
+	pending := spooledSession{
+		GameID:             game.GameID,
+		ExeName:            game.ExeName,
+		EndedAt:            endedAt,
+		Duration:           game.AccumulatedTime,
+		Partial:            game.Partial,
+		IdleTime:           game.IdleTime,
+		CPUPeakPercent:     usage.CPUPeakPercent,
+		CPUAveragePercent:  usage.CPUAveragePercent,
+		MemoryPeakBytes:    usage.MemoryPeakBytes,
+		MemoryAverageBytes: usage.MemoryAverageBytes,
+	}
+	if service.splitAtMidnight.Load() {
+		pending.DayMarks = game.DayMarks
+	}
+	ctx, cancel := context.WithTimeout(service.context(), sessionSaveTimeout)
+	defer cancel()
+	if err := service.persistSession(ctx, pending); err != nil {
+		service.logger.Error("プレイセッション保存に失敗", "gameId", game.GameID, "error", err)
+		// プレイ時間を失わないよう退避し、後で再試行する。
+		service.spoolSession(pending)
 	}
+}
 
-	current, err := service.repository.GetGameByID(ctx, game.GameID)
-	if err != nil || current == nil {
-		service.logger.Error("ゲーム取得に失敗", "error", err)
-		return
-	}
-	current.TotalPlayTime += game.AccumulatedTime
-	current.LastPlayed = &endedAt
-	if current.SaveFolderPath != nil {
-		saveFolderPath := strings.TrimSpace(*current.SaveFolderPath)
-		if saveFolderPath != "" {
-			if snap, hashErr := buildSaveTree(saveFolderPath); hashErr != nil {
-				service.logger.Warn("ローカルセーブハッシュの計算に失敗", "error", hashErr)
-			} else if snapJSON, merr := json.Marshal(snap); merr == nil {
-				h := hashBytes(snapJSON)
-				current.LocalSaveHash = &h
-				current.LocalSaveHashUpdatedAt = &endedAt
+// persistSession はセッションを作成し、ゲームの累計プレイ時間などを更新する。
+// セッション作成とゲーム更新は1トランザクションで行い、どちらかが失敗すれば両方とも残らないため、
+// エラーを返した場合は再試行しても二重登録にならない。クラウド同期の失敗はログに残すだけにする。
+func (service *ProcessMonitorService) persistSession(ctx context.Context, pending spooledSession) error {
+	sessionName := "自動記録 - " + pending.ExeName
+	endedAt := pending.EndedAt
+	// セーブフォルダのハッシュ計算はファイル読み込みを伴うため、書き込みロックを持つ前に済ませる。
+	localSaveHash := service.localSaveHash(ctx, pending.GameID)
+	// 日付の区切りがあれば日ごとに分ける。分けても合計は変わらないため、ゲームの累計は元の長さで更新する。
+	parts := splitSessionByDay(pending)
+	var created []domain.PlaySession
+	var updated *domain.Game
+	err := runInTx(ctx, service.withTx, service.repository, func(repository ProcessMonitorRepository) error {
+		for _, part := range parts {
+			session, err := repository.CreatePlaySession(ctx, domain.PlaySession{
+				GameID:             part.GameID,
+				PlayedAt:           part.EndedAt,
+				Duration:           part.Duration,
+				SessionName:        &sessionName,
+				Partial:            part.Partial,
+				IdleDuration:       part.IdleTime,
+				CPUPeakPercent:     part.CPUPeakPercent,
+				CPUAveragePercent:  part.CPUAveragePercent,
+				MemoryPeakBytes:    part.MemoryPeakBytes,
+				MemoryAverageBytes: part.MemoryAverageBytes,
+			})
+			if err != nil {
+				return err
+			}
+			if session != nil {
+				created = append(created, *session)
 			}
 		}
+		current, err := repository.GetGameByID(ctx, pending.GameID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return errors.New("game not found: " + pending.GameID)
+		}
+		current.TotalPlayTime += pending.Duration
+		current.LastPlayed = &endedAt
+		if localSaveHash != nil {
+			current.LocalSaveHash = localSaveHash
+			current.LocalSaveHashUpdatedAt = &endedAt
+		}
+		updated, err = repository.UpdateGame(ctx, *current)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	if _, err := service.repository.UpdateGame(ctx, *current); err != nil {
-		service.logger.Error("プレイ時間更新に失敗", "error", err)
-		return
+	service.logger.Info("プレイセッションを保存", "exeName", pending.ExeName, "duration", pending.Duration, "records", len(parts))
+	for _, session := range created {
+		service.events.Publish(EventSessionCreated, SessionCreatedEvent{Session: session, Auto: true})
 	}
+	if updated != nil {
+		service.events.Publish(EventGameUpdated, GameUpdatedEvent{GameID: updated.ID, Game: updated})
+	}
+	// プレイ後の自動同期などはこのイベントの購読側で行い、監視はクラウド同期を直接呼ばない。
+	service.events.Publish(EventSessionEnded, SessionEndedEvent{GameID: pending.GameID, Duration: pending.Duration, EndedAt: endedAt})
+	return nil
+}
 
-	service.logger.Info("プレイセッションを保存", "exeName", game.ExeName, "duration", game.AccumulatedTime)
-	if service.cloudSync != nil {
-		go func(gameID string) {
-			defer logging.Recover(service.logger, "process-monitor.afterPlayPush")
-			if err := service.cloudSync.Push(context.Background(), gameID, nil); err != nil {
-				// オフラインモードはユーザーが明示的に同期を抑止しているので warn 級にしない。
-				if errors.Is(err, ErrOffline) {
-					service.logger.Debug("オフラインモードのためクラウド同期をスキップ", "gameId", gameID)
-					return
-				}
-				service.logger.Warn("クラウド同期に失敗", "gameId", gameID, "detail", err)
-			}
-		}(game.GameID)
+// localSaveHash はゲームのセーブフォルダの現在のハッシュを返す。未設定や計算失敗時は nil。
+func (service *ProcessMonitorService) localSaveHash(ctx context.Context, gameID string) *string {
+	game, err := service.repository.GetGameByID(ctx, gameID)
+	if err != nil || game == nil || game.SaveFolderPath == nil {
+		return nil
+	}
+	saveFolderPath := strings.TrimSpace(*game.SaveFolderPath)
+	if saveFolderPath == "" {
+		return nil
+	}
+	h, err := saveTreeHash(saveFolderPath, newSaveFileFilter(game.SaveIncludePatterns, game.SaveExcludePatterns))
+	if err != nil {
+		service.logger.Warn("ローカルセーブハッシュの計算に失敗", "error", err)
+		return nil
 	}
+	return &h
 }
 
 func (service *ProcessMonitorService) saveAllActiveSessions() {
@@ -570,6 +905,7 @@ func (service *ProcessMonitorService) saveAllActiveSessions() {
 		EndedAt time.Time
 	}
 	sessions := make([]pendingSession, 0, len(service.monitoredGames))
+	hookGameIDs := make([]string, 0, len(service.monitoredGames))
 	now := time.Now()
 	for _, game := range service.monitoredGames {
 		if game.PlayStartTime != nil {
@@ -578,11 +914,15 @@ func (service *ProcessMonitorService) saveAllActiveSessions() {
 				game.AccumulatedTime += duration
 			}
 		}
+		game.endSuspension(now)
 		if game.AccumulatedTime > 0 {
 			sessions = append(sessions, pendingSession{
 				Game:    *game,
 				EndedAt: now,
 			})
+			if game.takePostExitHook() {
+				hookGameIDs = append(hookGameIDs, game.GameID)
+			}
 		}
 	}
 	service.mu.Unlock()
@@ -590,6 +930,10 @@ func (service *ProcessMonitorService) saveAllActiveSessions() {
 	for _, session := range sessions {
 		service.saveSession(session.Game, session.EndedAt)
 	}
+	// 終了処理は BackgroundTasks を待ってから DB を閉じるため、ここで回した終了後コマンドも待たれる。
+	for _, gameID := range hookGameIDs {
+		service.startPostExitHook(gameID)
+	}
 }
 
 func (service *ProcessMonitorService) autoAddGamesFromDatabase(processes []ProcessInfo, normalized []normalizedProcess) {
@@ -600,8 +944,7 @@ func (service *ProcessMonitorService) autoAddGamesFromDatabase(processes []Proce
 		return
 	}
 
-	ctx := context.Background()
-	games, err := service.repository.ListGames(ctx, "", domain.PlayStatus(""), "title", "asc")
+	games, err := service.cachedGames()
 	if err != nil || len(games) == 0 {
 		return
 	}
@@ -618,36 +961,87 @@ func (service *ProcessMonitorService) autoAddGamesFromDatabase(processes []Proce
 		if game.ExePath == "" || game.ExePath == UnconfiguredExePath {
 			continue
 		}
+		if game.AutoTrackingExcluded {
+			continue
+		}
 		exeName := windowsPathBase(game.ExePath)
 		normalizedExe := normalizeProcessToken(exeName)
 		if _, ok := processNames[normalizedExe]; !ok {
 			continue
 		}
-		if !service.isGameProcessRunning(exeName, game.ExePath, normalized) {
+		matcher, ok := service.gameProcessMatcher(game.ID, game.ProcessMatchPattern)
+		if !ok {
+			continue
+		}
+		if !service.isGameProcessRunning(exeName, game.ExePath, matcher, normalized) {
 			continue
 		}
 
 		service.mu.Lock()
 		if _, exists := service.monitoredGames[game.ID]; !exists {
 			service.addMonitoredGame(game.ID, game.Title, game.ExePath)
+			service.monitoredGames[game.ID].ForegroundOnly = game.TrackingMode == domain.TrackingModeForeground
+			service.monitoredGames[game.ID].Matcher = matcher
 		}
 		service.mu.Unlock()
 	}
 }
 
+// InvalidateGamesCache は自動検出に使うゲーム一覧のキャッシュを破棄し、次のスキャンで DB から読み直させる。
+// ゲームの追加・更新・削除の後に呼ぶ（Repository の書き込みフックに登録する）。
+func (service *ProcessMonitorService) InvalidateGamesCache() {
+	service.gamesCacheMu.Lock()
+	defer service.gamesCacheMu.Unlock()
+	service.gamesCache = nil
+	service.gamesCacheValid = false
+	service.gamesCacheGeneration++
+}
+
+// cachedGames は自動検出に使うゲーム一覧を返す。キャッシュが有効ならそれを返し、無ければ DB から読み直す。
+// 返す一覧は呼び出し側で変更しないこと。
+func (service *ProcessMonitorService) cachedGames() ([]domain.Game, error) {
+	service.gamesCacheMu.Lock()
+	if service.gamesCacheValid && time.Since(service.gamesCachedAt) < gamesCacheTTL {
+		games := service.gamesCache
+		service.gamesCacheMu.Unlock()
+		return games, nil
+	}
+	generation := service.gamesCacheGeneration
+	service.gamesCacheMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(service.context(), monitorQueryTimeout)
+	defer cancel()
+	games, err := service.repository.ListGames(ctx, "", domain.PlayStatus(""), "title", "asc")
+	if err != nil {
+		return nil, err
+	}
+	service.gamesCacheMu.Lock()
+	if generation == service.gamesCacheGeneration {
+		service.gamesCache = games
+		service.gamesCacheValid = true
+		service.gamesCachedAt = time.Now()
+	}
+	service.gamesCacheMu.Unlock()
+	return games, nil
+}
+
+// isGameProcessRunning はゲームの実行ファイルに一致し、一致条件（matcher、nil 可）も満たすプロセスがあるかを返す。
 func (service *ProcessMonitorService) isGameProcessRunning(
 	gameExeName string,
 	gameExePath string,
+	matcher *processMatcher,
 	processes []normalizedProcess,
 ) bool {
 	for _, proc := range processes {
-		if service.matchGameProcess(gameExeName, gameExePath, proc) {
+		if service.matchGameProcess(gameExeName, gameExePath, proc) && service.matchCommandLine(matcher, proc) {
 			return true
 		}
 	}
 	return false
 }
 
+// matchGameProcess はプロセスがゲームの実行ファイルかを返す。プロセス名が実行ファイル名と一致し、
+// コマンドラインに実行ファイルのパスまたはそのフォルダが含まれる（またはパスの一部である）ときに一致とみなす。
 func (service *ProcessMonitorService) matchGameProcess(
 	gameExeName string,
 	gameExePath string,
@@ -656,6 +1050,11 @@ func (service *ProcessMonitorService) matchGameProcess(
 	if proc.info.Name == "" || proc.info.Cmd == "" {
 		return false
 	}
+	// ラッパーはゲーム本体を起動して終了するため、既知のラッパーのプロセスはどのゲームにも一致させない。
+	// ゲームのラッパー設定の有無は見ないので、実行ファイルにラッパーを登録したゲームも検出されない。
+	if isLaunchWrapperExe(proc.info.Name) {
+		return false
+	}
 	normalizedExeName := normalizeProcessToken(gameExeName)
 	if proc.normalized != normalizedExeName {
 		return false
@@ -709,12 +1108,21 @@ func (service *ProcessMonitorService) FindProcessIDsByExe(exePath string) ([]int
 	return ids, nil
 }
 
+// getProcessesNative は Win32 API（Toolhelp スナップショット）でプロセス一覧を取得する。
+// 監視周期ごとに PowerShell を起動する負荷を避け、PowerShell が制限された環境でも動くようにする。
 func (service *ProcessMonitorService) getProcessesNative() ([]ProcessInfo, error) {
-	return service.getProcessesPowerShell()
+	processes, err := enumerateProcesses()
+	if err != nil {
+		return nil, err
+	}
+	if len(processes) == 0 {
+		return nil, errors.New("no processes enumerated")
+	}
+	return processes, nil
 }
 
 func (service *ProcessMonitorService) getProcessesPowerShell() ([]ProcessInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(service.context(), monitorQueryTimeout)
 	defer cancel()
 	command := execCommandHidden(
 		ctx,
@@ -761,12 +1169,18 @@ func (service *ProcessMonitorService) getProcessesPowerShell() ([]ProcessInfo, e
 	return processes, nil
 }
 
+// getProcessesFallback はネイティブ列挙に失敗したときに PowerShell、次に wmic で取得する。
 func (service *ProcessMonitorService) getProcessesFallback() ([]ProcessInfo, error) {
+	processes, err := service.getProcessesPowerShell()
+	if err == nil {
+		return processes, nil
+	}
+	service.logger.Debug("PowerShell でのプロセス取得に失敗", "error", err)
 	return service.getProcessesWmic()
 }
 
 func (service *ProcessMonitorService) getProcessesWmic() ([]ProcessInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(service.context(), monitorQueryTimeout)
 	defer cancel()
 	command := execCommandHidden(
 		ctx,
@@ -869,7 +1283,7 @@ func (service *ProcessMonitorService) getProcesses() ([]ProcessInfo, string) {
 		return processes, "native"
 	}
 
-	service.logger.Warn("ネイティブコマンドが失敗しました。フォールバックを使用します", "error", err)
+	service.logger.Warn("ネイティブのプロセス列挙に失敗しました。フォールバックを使用します", "error", err)
 	processes, err = service.getProcessesFallback()
 	if err != nil {
 		service.logger.Error("フォールバックも失敗しました", "error", err)
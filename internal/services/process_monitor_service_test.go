@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -49,7 +50,7 @@ func TestProcessMonitorServiceAutoAddGamesFromDatabaseAddsMatchingGame(t *testin
 				ExePath: `C:\games\game.exe`,
 			}}, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	processes := []ProcessInfo{{Name: "game.exe", Pid: 123, Cmd: `C:\games\game.exe`}}
 	normalized := []normalizedProcess{{
@@ -77,7 +78,7 @@ func TestProcessMonitorServiceAutoAddGamesFromDatabaseRespectsDisabledAutoTracki
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return []domain.Game{{ID: "game-1", Title: "Game", ExePath: `C:\games\game.exe`}}, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	service.UpdateAutoTracking(false)
 
 	processes := []ProcessInfo{{Name: "game.exe", Pid: 123, Cmd: `C:\games\game.exe`}}
@@ -94,6 +95,93 @@ func TestProcessMonitorServiceAutoAddGamesFromDatabaseRespectsDisabledAutoTracki
 	}
 }
 
+func TestProcessMonitorServiceAutoAddGamesFromDatabaseSkipsExcludedGames(t *testing.T) {
+	t.Parallel()
+
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) { return nil, nil },
+		updateGameFn:  func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return []domain.Game{
+				{ID: "emulator", Title: "Emulator", ExePath: `C:\emu\retroarch.exe`, AutoTrackingExcluded: true},
+				{ID: "game-1", Title: "Game", ExePath: `C:\emu\retroarch.exe`},
+			}, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	processes := []ProcessInfo{{Name: "retroarch.exe", Pid: 123, Cmd: `C:\emu\retroarch.exe`}}
+	service.autoAddGamesFromDatabase(processes, normalizeProcessList(processes))
+
+	if _, ok := service.monitoredGames["emulator"]; ok {
+		t.Fatalf("expected excluded game not to be added")
+	}
+	if _, ok := service.monitoredGames["game-1"]; !ok {
+		t.Fatalf("expected other game sharing the exe to be added")
+	}
+}
+
+func TestProcessMonitorServiceExcludeGameSavesAndStopsMonitoring(t *testing.T) {
+	t.Parallel()
+
+	var saved []domain.PlaySession
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			saved = append(saved, session)
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game"}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	pausedAt := time.Now()
+	service.monitoredGames["game-1"] = &MonitoringGame{
+		GameID:          "game-1",
+		ExeName:         "game.exe",
+		AccumulatedTime: 300,
+		IsPaused:        true,
+		PausedAt:        &pausedAt,
+	}
+
+	service.ExcludeGame("game-1")
+	if _, ok := service.monitoredGames["game-1"]; ok {
+		t.Fatalf("expected game to be removed from monitoring")
+	}
+	if len(saved) != 1 || saved[0].Duration != 300 {
+		t.Fatalf("expected paused session to be saved, got %#v", saved)
+	}
+}
+
+func TestProcessMonitorServiceSetTimeoutsDelaysPendingEnd(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	service.SetTimeouts(30*time.Second, time.Minute)
+	service.SetTimeouts(-time.Second, 0)
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	game := &MonitoringGame{GameID: "game-1", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+	service.monitoredGames[game.GameID] = game
+	running := map[string][]normalizedProcess{
+		normalizeProcessToken("game.exe"): normalizeProcessList([]ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}),
+	}
+
+	service.updateMonitoredGameState(game, running, userActivity{}, start)
+	service.updateMonitoredGameState(game, map[string][]normalizedProcess{}, userActivity{}, start.Add(10*time.Second))
+	if game.PendingEnd {
+		t.Fatalf("expected game not to be pending end within the session timeout")
+	}
+	service.updateMonitoredGameState(game, map[string][]normalizedProcess{}, userActivity{}, start.Add(31*time.Second))
+	if !game.PendingEnd {
+		t.Fatalf("expected game to be pending end after the session timeout")
+	}
+}
+
 func TestProcessMonitorServiceSaveSessionUpdatesGameTotals(t *testing.T) {
 	t.Parallel()
 
@@ -112,7 +200,7 @@ func TestProcessMonitorServiceSaveSessionUpdatesGameTotals(t *testing.T) {
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	endedAt := time.Date(2026, 4, 24, 20, 0, 0, 0, time.UTC)
 	service.saveSession(MonitoringGame{
@@ -142,7 +230,7 @@ func TestProcessMonitorServicePauseSessionMarksGamePaused(t *testing.T) {
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	start := time.Now().Add(-30 * time.Second)
 	service.monitoredGames["game-1"] = &MonitoringGame{
 		GameID:        "game-1",
@@ -175,7 +263,7 @@ func TestProcessMonitorServiceGetHotkeyTargetGameIDPrefersCurrentPlayingGame(t *
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	oldActivity := time.Date(2026, 4, 24, 10, 0, 0, 0, time.UTC)
 	newActivity := oldActivity.Add(time.Hour)
 	service.monitoredGames["paused"] = &MonitoringGame{
@@ -194,6 +282,31 @@ func TestProcessMonitorServiceGetHotkeyTargetGameIDPrefersCurrentPlayingGame(t *
 	}
 }
 
+// TestProcessMonitorServiceLastTrackedGameSurvivesPendingEnd は、プロセス終了で終了確認待ちに
+// なった後も、直近に検出したゲームと日時が残ることを検証する。
+func TestProcessMonitorServiceLastTrackedGameSurvivesPendingEnd(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	game := &MonitoringGame{GameID: "game-1", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+	service.monitoredGames[game.GameID] = game
+	running := map[string][]normalizedProcess{
+		normalizeProcessToken("game.exe"): normalizeProcessList([]ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}),
+	}
+
+	service.updateMonitoredGameState(game, running, userActivity{}, start)
+	service.updateMonitoredGameState(game, map[string][]normalizedProcess{}, userActivity{}, start.Add(5*time.Second))
+	if !game.PendingEnd {
+		t.Fatalf("expected game to be pending end")
+	}
+
+	gameID, lastSeen := service.LastTrackedGame()
+	if gameID != "game-1" || !lastSeen.Equal(start) {
+		t.Fatalf("unexpected last tracked game: %q %v", gameID, lastSeen)
+	}
+}
+
 func TestProcessMonitorServiceEndSessionResetsAccumulatedTime(t *testing.T) {
 	t.Parallel()
 
@@ -208,7 +321,7 @@ func TestProcessMonitorServiceEndSessionResetsAccumulatedTime(t *testing.T) {
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	start := time.Now().Add(-10 * time.Second)
 	service.monitoredGames["game-1"] = &MonitoringGame{
 		GameID:        "game-1",
@@ -242,7 +355,7 @@ func TestProcessMonitorServiceMatchGameProcess(t *testing.T) {
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	match := service.matchGameProcess("game.exe", `C:\games\game.exe`, normalizedProcess{
 		info:          ProcessInfo{Name: "game.exe", Cmd: `C:\games\game.exe`},
@@ -261,6 +374,15 @@ func TestProcessMonitorServiceMatchGameProcess(t *testing.T) {
 	if noMatch {
 		t.Fatalf("expected different executable to not match")
 	}
+
+	wrapperMatch := service.matchGameProcess("LEProc.exe", `C:\Program Files\Locale Emulator\LEProc.exe`, normalizedProcess{
+		info:          ProcessInfo{Name: "LEProc.exe", Cmd: `C:\Program Files\Locale Emulator\LEProc.exe`},
+		normalized:    normalizeProcessToken("LEProc.exe"),
+		normalizedCmd: normalizeProcessPathToken(`C:\Program Files\Locale Emulator\LEProc.exe`),
+	})
+	if wrapperMatch {
+		t.Fatalf("expected launch wrapper process to never match a game")
+	}
 }
 
 func TestProcessMonitorServiceIsGameProcessRunning(t *testing.T) {
@@ -275,7 +397,7 @@ func TestProcessMonitorServiceIsGameProcessRunning(t *testing.T) {
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 
 	processes := []normalizedProcess{
 		{
@@ -290,10 +412,10 @@ func TestProcessMonitorServiceIsGameProcessRunning(t *testing.T) {
 		},
 	}
 
-	if !service.isGameProcessRunning("game.exe", `C:\games\game.exe`, processes) {
+	if !service.isGameProcessRunning("game.exe", `C:\games\game.exe`, nil, processes) {
 		t.Fatalf("expected running game to be detected")
 	}
-	if service.isGameProcessRunning("missing.exe", `C:\games\missing.exe`, processes) {
+	if service.isGameProcessRunning("missing.exe", `C:\games\missing.exe`, nil, processes) {
 		t.Fatalf("expected missing game to not be detected")
 	}
 }
@@ -310,7 +432,7 @@ func TestProcessMonitorServiceResumeSessionUsesInjectedProcesses(t *testing.T) {
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	service.processProvider = func() ([]ProcessInfo, string) {
 		return []ProcessInfo{{Name: "game.exe", Pid: 100, Cmd: `C:\games\game.exe`}}, "test"
 	}
@@ -346,7 +468,7 @@ func TestProcessMonitorServiceGetProcessSnapshotUsesInjectedProcesses(t *testing
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	service.processProvider = func() ([]ProcessInfo, string) {
 		return []ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}, "test"
 	}
@@ -376,7 +498,7 @@ func TestProcessMonitorServiceFindProcessIDsByExeUsesInjectedProcesses(t *testin
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 	service.processProvider = func() ([]ProcessInfo, string) {
 		return []ProcessInfo{
 			{Name: "other.exe", Pid: 10, Cmd: `C:\games\other.exe`},
@@ -404,7 +526,7 @@ func newTestProcessMonitorService() *ProcessMonitorService {
 		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
 			return nil, nil
 		},
-	}, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
 }
 
 // TestProcessMonitorServiceFindProcessIDsByExeErrorsOnEmptyList は、プロセス列挙が空
@@ -449,3 +571,36 @@ func TestProcessMonitorServiceFindProcessIDsByExeUsesCache(t *testing.T) {
 		t.Fatalf("unexpected process ids: %#v", ids)
 	}
 }
+
+func TestProcessMonitorServiceAutoAddGamesFromDatabaseCachesGames(t *testing.T) {
+	t.Parallel()
+
+	var lists atomic.Int32
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			lists.Add(1)
+			return []domain.Game{{ID: "game-1", Title: "Game", ExePath: `C:\games\game.exe`}}, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for range 3 {
+		service.autoAddGamesFromDatabase(nil, nil)
+	}
+	if got := lists.Load(); got != 1 {
+		t.Fatalf("ListGames calls within TTL = %d, want 1", got)
+	}
+
+	service.InvalidateGamesCache()
+	service.autoAddGamesFromDatabase(nil, nil)
+	if got := lists.Load(); got != 2 {
+		t.Fatalf("ListGames calls after invalidation = %d, want 2", got)
+	}
+
+	service.gamesCacheMu.Lock()
+	service.gamesCachedAt = time.Now().Add(-gamesCacheTTL)
+	service.gamesCacheMu.Unlock()
+	service.autoAddGamesFromDatabase(nil, nil)
+	if got := lists.Load(); got != 3 {
+		t.Fatalf("ListGames calls after TTL = %d, want 3", got)
+	}
+}
@@ -0,0 +1,135 @@
+// 計測中のゲームのプロセスの CPU 使用率・メモリ使用量のサンプリングと、セッションごとの集計を提供する。
+package services
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// processUsage は PID 群の累計 CPU 時間（カーネル + ユーザー）と現在のワーキングセットの合計。
+type processUsage struct {
+	cpuTime     time.Duration
+	memoryBytes int64
+}
+
+// usageTarget はスキャンで使用量を採るゲームと、そのプロセスの PID 群。
+type usageTarget struct {
+	gameID string
+	pids   []int
+}
+
+// sessionResourceUsage はセッションで採った使用量の最大値と平均値。CPU は全コアに対する %。
+type sessionResourceUsage struct {
+	CPUPeakPercent     float64
+	CPUAveragePercent  float64
+	MemoryPeakBytes    int64
+	MemoryAverageBytes int64
+}
+
+// resourceUsage は1セッション分の使用量の集計。CPU 使用率は前回のサンプルからの累計 CPU 時間の差分で求めるため、
+// 最初のサンプルと、計測を止めていた後・PID 群が変わった後の最初のサンプルでは CPU を数えずメモリだけ数える。
+type resourceUsage struct {
+	baselineCPU  time.Duration
+	baselineAt   time.Time
+	baselinePIDs []int
+	// cpuPercent / memoryBytes は直近のサンプルの値（計測を止めている間は 0）。
+	cpuPercent    float64
+	memoryBytes   int64
+	cpuSamples    int64
+	cpuSum        float64
+	cpuPeak       float64
+	memorySamples int64
+	memorySum     int64
+	memoryPeak    int64
+}
+
+// record は at に採ったサンプルを集計に加える。cpuCount は論理コア数。
+func (usage *resourceUsage) record(sample processUsage, pids []int, at time.Time, cpuCount int) {
+	if !usage.baselineAt.IsZero() && slices.Equal(usage.baselinePIDs, pids) && at.After(usage.baselineAt) && cpuCount > 0 {
+		elapsed := at.Sub(usage.baselineAt)
+		used := sample.cpuTime - usage.baselineCPU
+		if used >= 0 {
+			percent := math.Min(100, float64(used)/float64(elapsed)/float64(cpuCount)*100)
+			usage.cpuPercent = percent
+			usage.cpuSamples++
+			usage.cpuSum += percent
+			usage.cpuPeak = math.Max(usage.cpuPeak, percent)
+		}
+	}
+	usage.baselineCPU = sample.cpuTime
+	usage.baselineAt = at
+	usage.baselinePIDs = append(usage.baselinePIDs[:0], pids...)
+	if sample.memoryBytes > 0 {
+		usage.memoryBytes = sample.memoryBytes
+		usage.memorySamples++
+		usage.memorySum += sample.memoryBytes
+		usage.memoryPeak = max(usage.memoryPeak, sample.memoryBytes)
+	}
+}
+
+// pause は計測を止めている間に呼び、次のサンプルで止めていた間の CPU 時間を数えないようにする。
+func (usage *resourceUsage) pause() {
+	usage.baselineAt = time.Time{}
+	usage.baselinePIDs = usage.baselinePIDs[:0]
+	usage.cpuPercent = 0
+	usage.memoryBytes = 0
+}
+
+// summary はセッションの最大値と平均値を返す。CPU は小数第1位に丸める。
+func (usage resourceUsage) summary() sessionResourceUsage {
+	summary := sessionResourceUsage{
+		CPUPeakPercent:  roundTenth(usage.cpuPeak),
+		MemoryPeakBytes: usage.memoryPeak,
+	}
+	if usage.cpuSamples > 0 {
+		summary.CPUAveragePercent = roundTenth(usage.cpuSum / float64(usage.cpuSamples))
+	}
+	if usage.memorySamples > 0 {
+		summary.MemoryAverageBytes = usage.memorySum / usage.memorySamples
+	}
+	return summary
+}
+
+// sampleUsage は計測中のゲームのプロセスの使用量を採って集計に加える。
+// OS への問い合わせを伴うため service.mu を保持せずに呼び、取得に失敗したゲームはこのスキャンでは数えない。
+func (service *ProcessMonitorService) sampleUsage(targets []usageTarget, now time.Time) {
+	service.mu.Lock()
+	provider := service.usageProvider
+	cpuCount := service.cpuCount
+	service.mu.Unlock()
+	if provider == nil {
+		return
+	}
+	for _, target := range targets {
+		sample, err := provider(target.pids)
+		if err != nil {
+			service.logger.Debug("プロセスの使用量を取得できません", "gameId", target.gameID, "error", err)
+			continue
+		}
+		service.mu.Lock()
+		if game, ok := service.monitoredGames[target.gameID]; ok && game.counting() {
+			game.Usage.record(sample, target.pids, now, cpuCount)
+		}
+		service.mu.Unlock()
+	}
+}
+
+// counting はプレイ時間を数えている（中断・無操作・背面・終了確認待ちのいずれでもない）かを返す。
+func (game *MonitoringGame) counting() bool {
+	return game.PlayStartTime != nil && !game.IsPaused && !game.PendingEnd
+}
+
+// processPIDs はプロセス群の PID を昇順で返す（スキャンごとの並びの違いで PID 群が変わったとみなさないようにする）。
+func processPIDs(processes []normalizedProcess) []int {
+	pids := make([]int, 0, len(processes))
+	for _, process := range processes {
+		pids = append(pids, process.info.Pid)
+	}
+	slices.Sort(pids)
+	return pids
+}
+
+func roundTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
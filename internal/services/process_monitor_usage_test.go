@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestResourceUsageAveragesAndPeaksAcrossSamples(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := resourceUsage{}
+	pids := []int{20}
+	// 4 コアで 10 秒ごと。CPU 時間の増分 8 秒 → 20%、20 秒 → 50%。
+	usage.record(processUsage{cpuTime: 0, memoryBytes: 100}, pids, start, 4)
+	usage.record(processUsage{cpuTime: 8 * time.Second, memoryBytes: 300}, pids, start.Add(10*time.Second), 4)
+	usage.record(processUsage{cpuTime: 28 * time.Second, memoryBytes: 200}, pids, start.Add(20*time.Second), 4)
+
+	summary := usage.summary()
+	if summary.CPUPeakPercent != 50 || summary.CPUAveragePercent != 35 {
+		t.Fatalf("unexpected cpu summary: %+v", summary)
+	}
+	if summary.MemoryPeakBytes != 300 || summary.MemoryAverageBytes != 200 {
+		t.Fatalf("unexpected memory summary: %+v", summary)
+	}
+}
+
+func TestResourceUsageSkipsCPUAfterPauseAndPIDChange(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := resourceUsage{}
+	usage.record(processUsage{cpuTime: 0, memoryBytes: 100}, []int{20}, start, 1)
+	usage.pause()
+	// 止めていた間に使った CPU 時間は数えない。
+	usage.record(processUsage{cpuTime: time.Minute, memoryBytes: 100}, []int{20}, start.Add(time.Hour), 1)
+	// 子プロセスが増えたときも差分をとらない。
+	usage.record(processUsage{cpuTime: 2 * time.Minute, memoryBytes: 100}, []int{20, 21}, start.Add(time.Hour+time.Second), 1)
+	if summary := usage.summary(); summary.CPUPeakPercent != 0 || summary.CPUAveragePercent != 0 {
+		t.Fatalf("expected no cpu samples, got %+v", summary)
+	}
+	if usage.memorySamples != 3 {
+		t.Fatalf("memory should be sampled every time, got %d", usage.memorySamples)
+	}
+}
+
+func TestProcessMonitorServiceRecordsUsageOnSession(t *testing.T) {
+	t.Parallel()
+
+	var saved []domain.PlaySession
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			saved = append(saved, session)
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game"}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.cpuCount = 2
+	cpuTime := time.Duration(0)
+	service.usageProvider = func(pids []int) (processUsage, error) {
+		if len(pids) != 1 || pids[0] != 20 {
+			t.Errorf("unexpected pids: %v", pids)
+		}
+		return processUsage{cpuTime: cpuTime, memoryBytes: 512 << 20}, nil
+	}
+	game := &MonitoringGame{GameID: "game-1", GameTitle: "Game", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+	service.monitoredGames[game.GameID] = game
+	running := map[string][]normalizedProcess{
+		normalizeProcessToken("game.exe"): normalizeProcessList([]ProcessInfo{{Name: "game.exe", Pid: 20, Cmd: `C:\games\game.exe`}}),
+	}
+
+	start := time.Now().Add(-time.Minute)
+	for i := 0; i < 3; i++ {
+		at := start.Add(time.Duration(i) * 10 * time.Second)
+		service.mu.Lock()
+		service.updateMonitoredGameState(game, running, userActivity{}, at)
+		service.mu.Unlock()
+		service.sampleUsage([]usageTarget{{gameID: game.GameID, pids: game.RunningPIDs}}, at)
+		cpuTime += 5 * time.Second
+	}
+
+	status := service.GetMonitoringStatus()
+	if len(status) != 1 || status[0].CPUPercent != 25 || status[0].MemoryBytes != 512<<20 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if !service.EndSession(game.GameID) {
+		t.Fatal("expected end session to succeed")
+	}
+	if len(saved) != 1 || saved[0].CPUPeakPercent != 25 || saved[0].CPUAveragePercent != 25 || saved[0].MemoryPeakBytes != 512<<20 {
+		t.Fatalf("unexpected saved session: %+v", saved)
+	}
+	if game.Usage.memorySamples != 0 {
+		t.Fatalf("usage should reset after the session, got %+v", game.Usage)
+	}
+}
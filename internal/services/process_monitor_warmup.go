@@ -0,0 +1,103 @@
+// アプリ起動時点で既に起動していたゲームのセッション（ウォームアップ）の扱いを提供する。
+package services
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// WarmupPolicyPartial は起動済みゲームをアプリ起動時刻から計測し、セッションに partial を付ける。
+	WarmupPolicyPartial = "partial"
+	// WarmupPolicyPrompt は partial に加えて、実際の開始時刻の見積もりをユーザーに尋ねる。
+	WarmupPolicyPrompt = "prompt"
+)
+
+// maxWarmupEstimate は見積もり開始時刻をさかのぼれる上限。入力ミスで総プレイ時間が
+// 大きく膨らまないよう、丸1日より前は受け付けない。
+const maxWarmupEstimate = 24 * time.Hour
+
+// NormalizeWarmupPolicy は起動済みゲームの扱いを partial|prompt に正規化する。空は partial。
+func NormalizeWarmupPolicy(policy string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case "", WarmupPolicyPartial:
+		return WarmupPolicyPartial, true
+	case WarmupPolicyPrompt:
+		return WarmupPolicyPrompt, true
+	default:
+		return "", false
+	}
+}
+
+// SetWarmupPolicy は起動済みゲームの扱いを設定する。不正な値は無視する。
+func (service *ProcessMonitorService) SetWarmupPolicy(policy string) {
+	normalized, ok := NormalizeWarmupPolicy(policy)
+	if !ok {
+		return
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.warmupPolicy = normalized
+}
+
+// SetWarmupListener は prompt 方針で開始時刻の見積もりが必要なゲームを検出したときの通知先を設定する。
+func (service *ProcessMonitorService) SetWarmupListener(fn func(gameIDs []string)) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.warmupListener = fn
+}
+
+// markWarmupGame は初回スキャンで検出したゲームを partial 扱いにする。service.mu を保持して呼ぶ。
+// prompt 方針なら見積もり待ちにし、true を返す。
+func (service *ProcessMonitorService) markWarmupGame(game *MonitoringGame, detectedAt time.Time) bool {
+	game.Partial = true
+	game.WarmupStartedAt = &detectedAt
+	service.logger.Info("アプリ起動前から起動中のゲームを検知（起動時刻から計測）", "title", game.GameTitle, "exeName", game.ExeName)
+	if service.warmupPolicy != WarmupPolicyPrompt {
+		return false
+	}
+	game.NeedsStartEstimate = true
+	return true
+}
+
+// EstimateWarmupStart は起動済みゲームの実際の開始時刻の見積もりを反映する。
+// 起動時刻から見積もり時刻までの差をプレイ時間に加え、partial を外す。
+// 見積もりは検出時刻より前かつ maxWarmupEstimate 以内に限る。
+func (service *ProcessMonitorService) EstimateWarmupStart(gameID string, startedAt time.Time) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	game, exists := service.monitoredGames[strings.TrimSpace(gameID)]
+	if !exists || game.WarmupStartedAt == nil {
+		return newServiceError("開始時刻を反映できません", "見積もり対象のセッションがありません")
+	}
+	detectedAt := *game.WarmupStartedAt
+	if startedAt.After(detectedAt) {
+		return newServiceError("開始時刻が不正です", "検出時刻より後の時刻は指定できません")
+	}
+	if detectedAt.Sub(startedAt) > maxWarmupEstimate {
+		return newServiceError("開始時刻が不正です", "24時間より前の時刻は指定できません")
+	}
+	game.AccumulatedTime += int64(detectedAt.Sub(startedAt).Seconds())
+	game.clearWarmup()
+	service.logger.Info("起動済みゲームの開始時刻を反映", "title", game.GameTitle, "startedAt", startedAt)
+	return nil
+}
+
+// DismissWarmupPrompt は開始時刻の見積もりを尋ねずに閉じる。セッションは partial のまま残る。
+func (service *ProcessMonitorService) DismissWarmupPrompt(gameID string) bool {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	game, exists := service.monitoredGames[strings.TrimSpace(gameID)]
+	if !exists || !game.NeedsStartEstimate {
+		return false
+	}
+	game.NeedsStartEstimate = false
+	return true
+}
+
+// clearWarmup はウォームアップ由来の状態を消す。セッション保存後や見積もり反映後に呼ぶ。
+func (game *MonitoringGame) clearWarmup() {
+	game.Partial = false
+	game.WarmupStartedAt = nil
+	game.NeedsStartEstimate = false
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestProcessMonitorServiceMarksGamesFoundInFirstScanAsPartial(t *testing.T) {
+	t.Parallel()
+
+	var saved []domain.PlaySession
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			saved = append(saved, session)
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) { return nil, nil },
+		updateGameFn:  func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return nil, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.processProvider = func() ([]ProcessInfo, string) {
+		return []ProcessInfo{{Name: "game.exe", Pid: 100, Cmd: `C:\games\game.exe`}}, "test"
+	}
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1", GameTitle: "Game", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+
+	service.checkProcesses()
+	game := service.monitoredGames["game-1"]
+	if !game.Partial || game.WarmupStartedAt == nil || game.NeedsStartEstimate {
+		t.Fatalf("expected partial without prompt, got %#v", game)
+	}
+
+	// 2回目以降のスキャンで新たに始まったゲームは通常のセッションとして扱う。
+	service.monitoredGames["game-2"] = &MonitoringGame{GameID: "game-2", GameTitle: "Other", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+	service.checkProcesses()
+	if service.monitoredGames["game-2"].Partial {
+		t.Fatal("expected games started after the first scan not to be partial")
+	}
+
+	game.AccumulatedTime = 60
+	if !service.EndSession("game-1") {
+		t.Fatal("expected end session to succeed")
+	}
+	if len(saved) != 1 || !saved[0].Partial {
+		t.Fatalf("expected partial session to be saved, got %#v", saved)
+	}
+	if game.Partial || game.WarmupStartedAt != nil {
+		t.Fatal("expected warmup state to be cleared after saving")
+	}
+}
+
+func TestProcessMonitorServicePromptPolicyAppliesEstimatedStart(t *testing.T) {
+	t.Parallel()
+
+	service := newTestProcessMonitorService()
+	service.SetWarmupPolicy(WarmupPolicyPrompt)
+	service.processProvider = func() ([]ProcessInfo, string) {
+		return []ProcessInfo{{Name: "game.exe", Pid: 100, Cmd: `C:\games\game.exe`}}, "test"
+	}
+	var prompted []string
+	service.SetWarmupListener(func(gameIDs []string) { prompted = gameIDs })
+	service.monitoredGames["game-1"] = &MonitoringGame{GameID: "game-1", GameTitle: "Game", ExeName: "game.exe", ExePath: `C:\games\game.exe`}
+
+	service.checkProcesses()
+	if len(prompted) != 1 || prompted[0] != "game-1" {
+		t.Fatalf("expected prompt for game-1, got %#v", prompted)
+	}
+	game := service.monitoredGames["game-1"]
+	detectedAt := *game.WarmupStartedAt
+
+	if err := service.EstimateWarmupStart("game-1", detectedAt.Add(time.Minute)); err == nil {
+		t.Fatal("expected estimate after detection to be rejected")
+	}
+	if err := service.EstimateWarmupStart("game-1", detectedAt.Add(-25*time.Hour)); err == nil {
+		t.Fatal("expected estimate older than a day to be rejected")
+	}
+	if err := service.EstimateWarmupStart("game-1", detectedAt.Add(-30*time.Minute)); err != nil {
+		t.Fatalf("expected estimate to succeed, got %v", err)
+	}
+	if game.AccumulatedTime != 1800 || game.Partial || game.NeedsStartEstimate {
+		t.Fatalf("expected estimated time to be added and partial cleared, got %#v", game)
+	}
+	if service.DismissWarmupPrompt("game-1") {
+		t.Fatal("expected dismiss to report nothing pending")
+	}
+}
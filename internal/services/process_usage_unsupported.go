@@ -0,0 +1,11 @@
+//go:build !windows
+
+// 非Windows向けのプロセス使用量取得のスタブ実装。
+package services
+
+import "errors"
+
+// sampleProcessUsage は非Windowsではサポート外。使用量は採られず、セッションの値は 0 のままになる。
+func sampleProcessUsage(pids []int) (processUsage, error) {
+	return processUsage{}, errors.New("process usage sampling is only supported on Windows")
+}
@@ -0,0 +1,64 @@
+//go:build windows
+
+// Windows向けにプロセスの累計 CPU 時間とワーキングセットを取得する。
+package services
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procK32GetProcessMemoryInfo = kernel32dll.NewProc("K32GetProcessMemoryInfo")
+
+// processMemoryCounters は PROCESS_MEMORY_COUNTERS。
+type processMemoryCounters struct {
+	Cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// sampleProcessUsage は pids の累計 CPU 時間とワーキングセットを合計する。
+// 管理者権限で動くプロセスなど開けないものは除き、1つも開けなければエラーを返す。
+func sampleProcessUsage(pids []int) (processUsage, error) {
+	usage := processUsage{}
+	opened := 0
+	for _, pid := range pids {
+		handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION|windows.PROCESS_VM_READ, false, uint32(pid))
+		if err != nil {
+			// メモリは読めなくても CPU 時間だけは取れることがある。
+			handle, err = windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+		}
+		if err != nil {
+			continue
+		}
+		var creation, exit, kernel, user windows.Filetime
+		if err := windows.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err == nil {
+			usage.cpuTime += filetimeDuration(kernel) + filetimeDuration(user)
+			opened++
+		}
+		counters := processMemoryCounters{Cb: uint32(unsafe.Sizeof(processMemoryCounters{}))}
+		if ret, _, _ := procK32GetProcessMemoryInfo.Call(uintptr(handle), uintptr(unsafe.Pointer(&counters)), uintptr(counters.Cb)); ret != 0 {
+			usage.memoryBytes += int64(counters.WorkingSetSize)
+		}
+		_ = windows.CloseHandle(handle)
+	}
+	if opened == 0 {
+		return processUsage{}, errors.New("ゲームのプロセスを開けません")
+	}
+	return usage, nil
+}
+
+// filetimeDuration は GetProcessTimes が返す経過時間（100 ナノ秒単位）を Duration にする。
+func filetimeDuration(value windows.Filetime) time.Duration {
+	return time.Duration(uint64(value.HighDateTime)<<32|uint64(value.LowDateTime)) * 100
+}
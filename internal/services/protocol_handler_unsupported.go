@@ -0,0 +1,9 @@
+//go:build !windows
+
+// 非Windows向けの cloudlaunch:// URI ハンドラ登録のスタブ実装。
+package services
+
+// RegisterProtocolHandler は非Windowsでは何もしない（デスクトップ環境ごとの登録はパッケージ側で行う）。
+func RegisterProtocolHandler(exePath string) error {
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+// Windows向けの cloudlaunch:// URI ハンドラ登録（HKCU\Software\Classes）を実装する。
+package services
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// RegisterProtocolHandler は cloudlaunch:// を exePath で開くよう現在のユーザーに登録する。
+// HKCU に書くため管理者権限は不要。既に同じ内容で登録済みなら書き換えない。
+func RegisterProtocolHandler(exePath string) error {
+	command := fmt.Sprintf(`"%s" "%%1"`, exePath)
+	base := `Software\Classes\` + ProtocolScheme
+	commandKey, _, err := registry.CreateKey(registry.CURRENT_USER, base+`\shell\open\command`, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("open command key: %w", err)
+	}
+	defer commandKey.Close()
+	if current, _, err := commandKey.GetStringValue(""); err == nil && current == command {
+		return nil
+	}
+
+	rootKey, _, err := registry.CreateKey(registry.CURRENT_USER, base, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("open protocol key: %w", err)
+	}
+	defer rootKey.Close()
+	if err := rootKey.SetStringValue("", "URL:CloudLaunch Protocol"); err != nil {
+		return err
+	}
+	if err := rootKey.SetStringValue("URL Protocol", ""); err != nil {
+		return err
+	}
+	iconKey, _, err := registry.CreateKey(registry.CURRENT_USER, base+`\DefaultIcon`, registry.SET_VALUE)
+	if err == nil {
+		_ = iconKey.SetStringValue("", fmt.Sprintf(`"%s",0`, exePath))
+		iconKey.Close()
+	}
+	return commandKey.SetStringValue("", command)
+}
@@ -0,0 +1,71 @@
+// cloudlaunch:// URI（デスクトップのショートカットや Stream Deck から渡される操作）の解釈を提供する。
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ProtocolScheme は OS に登録するカスタム URI のスキーム。
+const ProtocolScheme = "cloudlaunch"
+
+// cloudlaunch:// URI の操作。
+const (
+	// ProtocolActionLaunch はゲームを起動する（cloudlaunch://launch/<gameID>）。
+	ProtocolActionLaunch = "launch"
+	// ProtocolActionScreenshot はスクリーンショットを撮る（cloudlaunch://screenshot、ゲーム指定は cloudlaunch://screenshot/<gameID>）。
+	ProtocolActionScreenshot = "screenshot"
+)
+
+// ProtocolCommand は URI から取り出した操作を表す。GameID は指定が無ければ空。
+type ProtocolCommand struct {
+	Action string
+	GameID string
+}
+
+// ParseProtocolURI は cloudlaunch:// URI を操作に変換する。
+// ブラウザ等が付ける末尾のスラッシュは無視し、操作名は大文字小文字を区別しない。
+func ParseProtocolURI(raw string) (ProtocolCommand, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ProtocolCommand{}, fmt.Errorf("URI を解釈できません: %w", err)
+	}
+	if !strings.EqualFold(parsed.Scheme, ProtocolScheme) {
+		return ProtocolCommand{}, fmt.Errorf("%s:// 以外の URI です: %s", ProtocolScheme, parsed.Scheme)
+	}
+	action := strings.ToLower(parsed.Host)
+	path := strings.Trim(parsed.Path, "/")
+	if action == "" {
+		// cloudlaunch:launch/<id> のように // を省いた形も受ける。
+		action, path, _ = strings.Cut(strings.Trim(parsed.Opaque, "/"), "/")
+		action = strings.ToLower(action)
+	}
+	if strings.Contains(path, "/") {
+		return ProtocolCommand{}, fmt.Errorf("URI のパスが不正です: %s", path)
+	}
+	switch action {
+	case ProtocolActionLaunch:
+		if path == "" {
+			return ProtocolCommand{}, errors.New("起動するゲームIDが指定されていません")
+		}
+		return ProtocolCommand{Action: action, GameID: path}, nil
+	case ProtocolActionScreenshot:
+		return ProtocolCommand{Action: action, GameID: path}, nil
+	default:
+		return ProtocolCommand{}, fmt.Errorf("未対応の操作です: %s", action)
+	}
+}
+
+// FindProtocolURI はコマンドライン引数から cloudlaunch:// URI を探す。
+func FindProtocolURI(args []string) (string, bool) {
+	prefix := ProtocolScheme + ":"
+	for _, arg := range args {
+		trimmed := strings.TrimSpace(arg)
+		if len(trimmed) >= len(prefix) && strings.EqualFold(trimmed[:len(prefix)], prefix) {
+			return trimmed, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,53 @@
+package services
+
+import "testing"
+
+func TestParseProtocolURI(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		raw    string
+		action string
+		gameID string
+	}{
+		{"cloudlaunch://launch/game-1", ProtocolActionLaunch, "game-1"},
+		{"CloudLaunch://Launch/game-1/", ProtocolActionLaunch, "game-1"},
+		{"cloudlaunch:launch/game-1", ProtocolActionLaunch, "game-1"},
+		{"cloudlaunch://launch/%E3%82%B2%E3%83%BC%E3%83%A0", ProtocolActionLaunch, "ゲーム"},
+		{"cloudlaunch://screenshot", ProtocolActionScreenshot, ""},
+		{"cloudlaunch://screenshot/", ProtocolActionScreenshot, ""},
+		{"cloudlaunch://screenshot/game-2", ProtocolActionScreenshot, "game-2"},
+	}
+	for _, tc := range cases {
+		command, err := ParseProtocolURI(tc.raw)
+		if err != nil {
+			t.Fatalf("ParseProtocolURI(%q): %v", tc.raw, err)
+		}
+		if command.Action != tc.action || command.GameID != tc.gameID {
+			t.Fatalf("ParseProtocolURI(%q) = %+v", tc.raw, command)
+		}
+	}
+
+	for _, raw := range []string{
+		"https://launch/game-1",
+		"cloudlaunch://launch",
+		"cloudlaunch://launch/a/b",
+		"cloudlaunch://delete/game-1",
+		"cloudlaunch://",
+	} {
+		if _, err := ParseProtocolURI(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestFindProtocolURI(t *testing.T) {
+	t.Parallel()
+
+	if uri, ok := FindProtocolURI([]string{"--flag", " CLOUDLAUNCH://screenshot "}); !ok || uri != "CLOUDLAUNCH://screenshot" {
+		t.Fatalf("unexpected result: %q %v", uri, ok)
+	}
+	if _, ok := FindProtocolURI([]string{"--flag", "cloud"}); ok {
+		t.Fatal("expected no URI")
+	}
+}
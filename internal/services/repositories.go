@@ -8,28 +8,67 @@ import (
 	"CloudLaunch_Go/internal/domain"
 )
 
+// TxRunner は fn をトランザクション内で実行し、トランザクションに束ねたリポジトリ R を fn に渡す。
+// fn がエラーを返した場合はロールバックする。
+type TxRunner[R any] func(ctx context.Context, fn func(repository R) error) error
+
+// runInTx は runner があればトランザクション内で、無ければ repository のまま fn を実行する。
+// runner 未設定（テスト用フェイクなど）でも同じコードパスを通せるようにするため。
+func runInTx[R any](ctx context.Context, runner TxRunner[R], repository R, fn func(repository R) error) error {
+	if runner == nil {
+		return fn(repository)
+	}
+	return runner(ctx, fn)
+}
+
 // GameRepository は GameService が必要とする永続化境界を定義する。
 type GameRepository interface {
 	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
+	ListGamesPage(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string, page domain.PageRequest) ([]domain.Game, int, error)
 	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
 	CreateGame(ctx context.Context, game domain.Game) (*domain.Game, error)
 	UpdateGame(ctx context.Context, game domain.Game) (*domain.Game, error)
+	SetGameArchived(ctx context.Context, gameID string, archivedAt *time.Time) error
+	SetGameLaunchWrapper(ctx context.Context, gameID string, wrapper *domain.LaunchWrapper) error
+	SetGameTrackingMode(ctx context.Context, gameID string, mode domain.TrackingMode) error
+	SetGameAutoTrackingExcluded(ctx context.Context, gameID string, excluded bool) error
+	SetGameProcessMatchPattern(ctx context.Context, gameID string, pattern *string) error
+	GetGameByExePath(ctx context.Context, exePath string) (*domain.Game, error)
 	DeleteGame(ctx context.Context, gameID string) error
 	CreateRoute(ctx context.Context, route domain.Route) (*domain.Route, error)
+	GetPlayStatusDefinition(ctx context.Context, slug string) (*domain.PlayStatusDefinition, error)
+	ListRecentlyPlayed(ctx context.Context, limit int) ([]domain.RecentlyPlayedGame, error)
 }
 
 // SessionRepository は SessionService が必要とする永続化境界を定義する。
 type SessionRepository interface {
 	CreatePlaySession(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error)
 	ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error)
+	ListPlaySessionsByGamePage(ctx context.Context, gameID string, page domain.PageRequest) ([]domain.PlaySession, int, error)
 	GetPlaySessionByID(ctx context.Context, sessionID string) (*domain.PlaySession, error)
 	DeletePlaySession(ctx context.Context, sessionID string) error
 	UpdatePlaySessionRoute(ctx context.Context, sessionID string, routeID *string) error
+	UpdatePlaySessionTiming(ctx context.Context, sessionID string, playedAt time.Time, duration int64) error
 	UpdatePlaySessionName(ctx context.Context, sessionID string, sessionName string) error
+	UpdatePlaySessionNotes(ctx context.Context, sessionID string, notes string) error
 	TouchGameUpdatedAt(ctx context.Context, gameID string) error
 	SumPlaySessionDurationsByGame(ctx context.Context, gameID string) (int64, error)
 	UpdateGameTotalPlayTime(ctx context.Context, gameID string, totalPlayTime int64) error
 	UpdateGameTotalPlayTimeWithLastPlayed(ctx context.Context, gameID string, totalPlayTime int64, playedAt time.Time) error
+	SetGameAggregates(ctx context.Context, gameID string, totalPlayTime int64, lastPlayed *time.Time) error
+	GetRouteStats(ctx context.Context, gameID string) ([]domain.RouteStat, error)
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
+	ListPlayTimeBuckets(ctx context.Context, from, to time.Time) ([]domain.PlayTimeBucket, error)
+}
+
+// GameLinkRepository は GameLinkService が必要とする永続化境界を定義する。
+type GameLinkRepository interface {
+	ListGameLinksByGame(ctx context.Context, gameID string) ([]domain.GameLink, error)
+	GetGameLinkByID(ctx context.Context, linkID string) (*domain.GameLink, error)
+	CreateGameLink(ctx context.Context, link domain.GameLink) (*domain.GameLink, error)
+	UpdateGameLink(ctx context.Context, link domain.GameLink) (*domain.GameLink, error)
+	DeleteGameLink(ctx context.Context, linkID string) error
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
 }
 
 // MemoRepository は MemoService が必要とする永続化境界を定義する。
@@ -41,6 +80,7 @@ type MemoRepository interface {
 	ListMemosByGame(ctx context.Context, gameID string) ([]domain.Memo, error)
 	ListAllMemos(ctx context.Context) ([]domain.Memo, error)
 	DeleteMemo(ctx context.Context, memoID string) error
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
 }
 
 // RouteRepository は RouteService が必要とする永続化境界を定義する。
@@ -55,22 +95,93 @@ type RouteRepository interface {
 	// gameID を指定外の Route ID は無視する（更新行数 0）。
 	UpdateRouteOrders(ctx context.Context, gameID string, items []domain.RouteOrderItem) error
 	GetRouteStats(ctx context.Context, gameID string) ([]domain.RouteStat, error)
+	ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error)
 	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
 	UpdateGame(ctx context.Context, game domain.Game) (*domain.Game, error)
 }
 
+// RouteTemplateRepository は RouteTemplateService が必要とする永続化境界を定義する。
+type RouteTemplateRepository interface {
+	ListRouteTemplates(ctx context.Context) ([]domain.RouteTemplate, error)
+	GetRouteTemplateByID(ctx context.Context, templateID string) (*domain.RouteTemplate, error)
+	SaveRouteTemplate(ctx context.Context, template domain.RouteTemplate) (*domain.RouteTemplate, error)
+	DeleteRouteTemplate(ctx context.Context, templateID string) error
+	ListRoutesByGame(ctx context.Context, gameID string) ([]domain.Route, error)
+	CreateRoute(ctx context.Context, route domain.Route) (*domain.Route, error)
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
+}
+
+// PlayStatusRepository は PlayStatusService が必要とする永続化境界を定義する。
+type PlayStatusRepository interface {
+	ListPlayStatusDefinitions(ctx context.Context) ([]domain.PlayStatusDefinition, error)
+	GetPlayStatusDefinition(ctx context.Context, slug string) (*domain.PlayStatusDefinition, error)
+	CreatePlayStatusDefinition(ctx context.Context, definition domain.PlayStatusDefinition) (*domain.PlayStatusDefinition, error)
+	UpdatePlayStatusDefinition(ctx context.Context, definition domain.PlayStatusDefinition) (*domain.PlayStatusDefinition, error)
+	// DeletePlayStatusDefinition は定義を削除し、そのステータスを外したゲームの ID を返す。
+	DeletePlayStatusDefinition(ctx context.Context, slug string) ([]string, error)
+}
+
 // ContentSyncRepository は ContentSyncService が必要とする永続化境界を定義する。
 type ContentSyncRepository interface {
 	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
+	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
 	ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error)
+	ListGameLinksByGame(ctx context.Context, gameID string) ([]domain.GameLink, error)
+	ListRoutesByGame(ctx context.Context, gameID string) ([]domain.Route, error)
 	SetLocalSyncHead(ctx context.Context, gameID, hash string) error
 	GetLocalSaveTree(ctx context.Context, gameID string) (string, error)
 	SetLocalSaveTree(ctx context.Context, gameID, tree string) error
+	SetLocalSaveHash(ctx context.Context, gameID, hash string, updatedAt time.Time) error
 	// ApplyPullResult は Pull で取得したリモート状態を単一トランザクションで反映する。
-	// Game の upsert・セッションの全削除と再投入・localSyncHead・localSaveTree を all-or-nothing で書き込む。
+	// Game の upsert・セッションの差分（sessions の upsert と deleteSessionIDs の削除）・localSyncHead・localSaveTree を
+	// all-or-nothing で書き込む。どちらにも含まれないセッションはそのまま残す。
 	// game.CurrentRouteID および各 session.RouteID のうち、ローカルに対応する Route が存在しないものは
-	// NULL に正規化する（Route は同期対象外のため、別PCで FK 違反になるのを防ぐ）。
-	ApplyPullResult(ctx context.Context, game domain.Game, sessions []domain.PlaySession, syncHead, saveTree string) error
+	// NULL に正規化する（routes.json を持たない旧 commit では別PCで FK 違反になるのを防ぐ）。
+	// links はゲームのリンク一覧で、ローカルの既存リンクを置き換える。
+	// routes が nil でなければローカルのルートを置き換え、nil ならローカルのルートを維持する。
+	ApplyPullResult(ctx context.Context, game domain.Game, sessions []domain.PlaySession, deleteSessionIDs []string, links []domain.GameLink, routes []domain.Route, syncHead, saveTree string) error
+	GetSetting(ctx context.Context, key string) (string, error)
+	UpsertSetting(ctx context.Context, key, value string) error
+}
+
+// SyncQueueRepository は SyncQueueService が必要とする永続化境界を定義する。
+type SyncQueueRepository interface {
+	EnqueueSyncItem(ctx context.Context, kind domain.SyncQueueKind, targetID string) error
+	ListSyncQueue(ctx context.Context) ([]domain.SyncQueueItem, error)
+	CompleteSyncItem(ctx context.Context, id int64, revision int64) (bool, error)
+	DeleteSyncItem(ctx context.Context, id int64) error
+	RecordSyncItemFailure(ctx context.Context, id int64, message string) error
+}
+
+// ChangeJournalRepository は ChangeJournalService が必要とする永続化境界を定義する。
+type ChangeJournalRepository interface {
+	ListChangeHistory(ctx context.Context, entityType, entityID string, limit int) ([]domain.ChangeEntry, error)
+	PruneChangeJournal(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ActivityLogRepository は ActivityLogService が必要とする永続化境界を定義する。
+type ActivityLogRepository interface {
+	InsertActivity(ctx context.Context, entry domain.ActivityEntry) error
+	ListActivityPage(ctx context.Context, filter domain.ActivityFilter, page domain.PageRequest) ([]domain.ActivityEntry, int, error)
+	PruneActivityLog(ctx context.Context, before time.Time) (int64, error)
+}
+
+// CloudPathMigrationRepository は CloudPathMigrationService が必要とする永続化境界を定義する。
+type CloudPathMigrationRepository interface {
+	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
+	GetSetting(ctx context.Context, key string) (string, error)
+	UpsertSetting(ctx context.Context, key, value string) error
+}
+
+// SettingsRepository は SettingsService が必要とする永続化境界を定義する。
+type SettingsRepository interface {
+	GetSetting(ctx context.Context, key string) (string, error)
+	UpsertSetting(ctx context.Context, key, value string) error
+}
+
+// WebhookRepository は WebhookService が必要とする永続化境界を定義する。
+type WebhookRepository interface {
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
 	GetSetting(ctx context.Context, key string) (string, error)
 	UpsertSetting(ctx context.Context, key, value string) error
 }
@@ -79,6 +190,17 @@ type ContentSyncRepository interface {
 type MaintenanceRepository interface {
 	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
 	ListPlaySessionsByGames(ctx context.Context, gameIDs []string) (map[string][]domain.PlaySession, error)
+	ListGameLinksByGames(ctx context.Context, gameIDs []string) (map[string][]domain.GameLink, error)
+	ListAllMemos(ctx context.Context) ([]domain.Memo, error)
+	CheckIntegrity(ctx context.Context, repair bool) (domain.DatabaseIntegrityReport, error)
+	SchemaVersion(ctx context.Context) (domain.SchemaVersion, error)
+}
+
+// ThumbnailRepository は ThumbnailService が必要とする永続化境界を定義する。
+type ThumbnailRepository interface {
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
+	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
+	UpdateGame(ctx context.Context, game domain.Game) (*domain.Game, error)
 }
 
 // ScreenshotRepository は ScreenshotService が必要とする永続化境界を定義する。
@@ -91,6 +213,11 @@ type ProcessIDResolver interface {
 	FindProcessIDsByExe(exePath string) ([]int, error)
 }
 
+// RecentGameTracker は直近に監視していたゲームとその最終検出日時を返す境界。
+type RecentGameTracker interface {
+	LastTrackedGame() (string, time.Time)
+}
+
 // ProcessMonitorRepository は ProcessMonitorService が必要とする永続化境界を定義する。
 type ProcessMonitorRepository interface {
 	CreatePlaySession(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error)
@@ -98,3 +225,10 @@ type ProcessMonitorRepository interface {
 	UpdateGame(ctx context.Context, game domain.Game) (*domain.Game, error)
 	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
 }
+
+// SaveFolderWatchRepository は SaveFolderWatcher が必要とする永続化境界を定義する。
+type SaveFolderWatchRepository interface {
+	GetGameByID(ctx context.Context, gameID string) (*domain.Game, error)
+	SetLocalSaveHash(ctx context.Context, gameID, hash string, updatedAt time.Time) error
+	ListGames(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error)
+}
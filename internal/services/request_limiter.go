@@ -0,0 +1,45 @@
+// 外部 API へのリクエスト間隔を空けるレート制限を提供する。
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestLimiter は同じ相手へのリクエストの開始間隔を interval 以上に空ける。
+// 呼び出しごとに次の送信枠を予約するため、並行して呼ばれても順に間隔が空く。
+type requestLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRequestLimiter(interval time.Duration) *requestLimiter {
+	return &requestLimiter{interval: interval}
+}
+
+// Wait は予約した送信枠まで待つ。待っている間に ctx が終了したらそのエラーを返す。
+func (limiter *requestLimiter) Wait(ctx context.Context) error {
+	limiter.mu.Lock()
+	now := time.Now()
+	start := limiter.next
+	if start.Before(now) {
+		start = now
+	}
+	limiter.next = start.Add(limiter.interval)
+	limiter.mu.Unlock()
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
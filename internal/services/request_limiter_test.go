@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestLimiterSpacesRequests(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRequestLimiter(30 * time.Millisecond)
+	start := time.Now()
+	for range 3 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("expected requests to be spaced, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Fatal("expected canceled context to stop waiting")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"time"
 
 	"CloudLaunch_Go/internal/domain"
 )
@@ -144,6 +145,93 @@ func (service *RouteService) GetRouteStats(ctx context.Context, gameID string) (
 	return stats, nil
 }
 
+// GetRouteProgress はルートごとの達成率・残り見込み時間と、ゲーム全体のクリア状況を取得する。
+func (service *RouteService) GetRouteProgress(ctx context.Context, gameID string) (domain.RouteProgress, error) {
+	stats, err := service.GetRouteStats(ctx, gameID)
+	if err != nil {
+		return domain.RouteProgress{}, err
+	}
+	return summarizeRouteProgress(strings.TrimSpace(gameID), stats), nil
+}
+
+// SetRouteEstimatedTime はルートのクリアまでの見込み時間（秒）を設定する。0 なら未設定に戻す。
+func (service *RouteService) SetRouteEstimatedTime(ctx context.Context, routeID string, seconds int64) (*domain.Route, error) {
+	if seconds < 0 {
+		service.logger.Warn("見込み時間が不正です", "routeId", routeID, "seconds", seconds)
+		return nil, newServiceError("見込み時間が不正です", "estimatedTimeが不正です")
+	}
+	route, err := service.getRoute(ctx, routeID)
+	if err != nil {
+		return nil, err
+	}
+	if seconds == 0 {
+		route.EstimatedTime = nil
+	} else {
+		route.EstimatedTime = &seconds
+	}
+	return service.saveRouteProgress(ctx, route)
+}
+
+// SetRouteCompleted はルートのクリア状態を切り替える。既にクリア済みのルートのクリア日時は維持する。
+func (service *RouteService) SetRouteCompleted(ctx context.Context, routeID string, completed bool) (*domain.Route, error) {
+	route, err := service.getRoute(ctx, routeID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case completed && route.CompletedAt == nil:
+		now := time.Now()
+		route.CompletedAt = &now
+	case !completed:
+		route.CompletedAt = nil
+	}
+	route.Completed = route.CompletedAt != nil
+	return service.saveRouteProgress(ctx, route)
+}
+
+func (service *RouteService) getRoute(ctx context.Context, routeID string) (*domain.Route, error) {
+	trimmedID, err := service.requireField(routeID, "routeID", "ルートIDが不正です")
+	if err != nil {
+		return nil, err
+	}
+	route, error := service.repository.GetRouteByID(ctx, trimmedID)
+	if error != nil {
+		service.logger.Error("ルート取得に失敗", "error", error)
+		return nil, newServiceError("ルート取得に失敗しました", error.Error())
+	}
+	if route == nil {
+		service.logger.Warn("ルートが見つかりません", "routeId", trimmedID)
+		return nil, newServiceError("ルートが見つかりません", "指定されたIDが存在しません")
+	}
+	return route, nil
+}
+
+func (service *RouteService) saveRouteProgress(ctx context.Context, route *domain.Route) (*domain.Route, error) {
+	updated, error := service.repository.UpdateRoute(ctx, *route)
+	if error != nil {
+		service.logger.Error("ルート進捗更新に失敗", "error", error)
+		return nil, newServiceError("ルート進捗更新に失敗しました", error.Error())
+	}
+	return updated, nil
+}
+
+// summarizeRouteProgress はルート統計からゲーム全体のクリア状況をまとめる。
+func summarizeRouteProgress(gameID string, stats []domain.RouteStat) domain.RouteProgress {
+	progress := domain.RouteProgress{GameID: gameID, RouteCount: len(stats), Routes: stats}
+	for _, stat := range stats {
+		progress.TotalTime += stat.TotalTime
+		progress.EstimatedTime += stat.EstimatedTime
+		progress.RemainingTime += stat.RemainingTime
+		if stat.Completed {
+			progress.CompletedCount++
+		}
+	}
+	if progress.RouteCount > 0 {
+		progress.CompletionPercent = float64(progress.CompletedCount) * 100 / float64(progress.RouteCount)
+	}
+	return progress
+}
+
 // SetCurrentRoute はゲームの現在ルートを設定する。
 func (service *RouteService) SetCurrentRoute(ctx context.Context, gameID string, routeID string) error {
 	trimmedGameID, err := service.requireField(gameID, "gameID", "ゲームIDが不正です")
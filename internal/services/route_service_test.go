@@ -6,6 +6,7 @@ import (
 	"io"
 	"log/slog"
 	"testing"
+	"time"
 
 	"CloudLaunch_Go/internal/domain"
 )
@@ -19,6 +20,7 @@ type fakeRouteRepository struct {
 	updateRouteOrderFn  func(ctx context.Context, routeID string, order int64) error
 	updateRouteOrdersFn func(ctx context.Context, gameID string, items []domain.RouteOrderItem) error
 	getRouteStatsFn     func(ctx context.Context, gameID string) ([]domain.RouteStat, error)
+	listSessionsFn      func(ctx context.Context, gameID string) ([]domain.PlaySession, error)
 	getGameByIDFn       func(ctx context.Context, gameID string) (*domain.Game, error)
 	updateGameFn        func(ctx context.Context, game domain.Game) (*domain.Game, error)
 }
@@ -55,6 +57,10 @@ func (r fakeRouteRepository) GetRouteStats(ctx context.Context, gameID string) (
 	return r.getRouteStatsFn(ctx, gameID)
 }
 
+func (r fakeRouteRepository) ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error) {
+	return r.listSessionsFn(ctx, gameID)
+}
+
 func (r fakeRouteRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
 	return r.getGameByIDFn(ctx, gameID)
 }
@@ -75,6 +81,7 @@ func newFullFakeRouteRepository() fakeRouteRepository {
 			return nil
 		},
 		getRouteStatsFn: func(ctx context.Context, gameID string) ([]domain.RouteStat, error) { return nil, nil },
+		listSessionsFn:  func(ctx context.Context, gameID string) ([]domain.PlaySession, error) { return nil, nil },
 		getGameByIDFn:   func(ctx context.Context, gameID string) (*domain.Game, error) { return nil, nil },
 		updateGameFn:    func(ctx context.Context, game domain.Game) (*domain.Game, error) { return &game, nil },
 	}
@@ -205,3 +212,48 @@ func TestRouteServiceUpdateRouteOrdersForwardsGameID(t *testing.T) {
 		t.Fatalf("items を順序通りに引き渡せていない: %#v", capturedItems)
 	}
 }
+
+func TestRouteServiceSetRouteCompletedKeepsExistingCompletedAt(t *testing.T) {
+	t.Parallel()
+
+	completedAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	route := domain.Route{ID: "route-1", Name: "Route 1", GameID: "game-1", Completed: true, CompletedAt: &completedAt}
+	repo := newFullFakeRouteRepository()
+	repo.getRouteByIDFn = func(ctx context.Context, routeID string) (*domain.Route, error) {
+		copied := route
+		return &copied, nil
+	}
+	repo.updateRouteFn = func(ctx context.Context, updated domain.Route) (*domain.Route, error) {
+		return &updated, nil
+	}
+	service := NewRouteService(repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	kept, err := service.SetRouteCompleted(context.Background(), "route-1", true)
+	if err != nil || kept.CompletedAt == nil || !kept.CompletedAt.Equal(completedAt) {
+		t.Fatalf("expected completedAt to be kept, got %#v (%v)", kept, err)
+	}
+	cleared, err := service.SetRouteCompleted(context.Background(), "route-1", false)
+	if err != nil || cleared.Completed || cleared.CompletedAt != nil {
+		t.Fatalf("expected completion to be cleared, got %#v (%v)", cleared, err)
+	}
+	if _, err := service.SetRouteEstimatedTime(context.Background(), "route-1", -1); err == nil {
+		t.Fatal("expected negative estimate to be rejected")
+	}
+}
+
+func TestSummarizeRouteProgressCountsCompletedRoutes(t *testing.T) {
+	t.Parallel()
+
+	progress := summarizeRouteProgress("game-1", []domain.RouteStat{
+		{RouteID: "a", TotalTime: 600, EstimatedTime: 1200, RemainingTime: 600},
+		{RouteID: "b", TotalTime: 1800, EstimatedTime: 1800, Completed: true, CompletionPercent: 100},
+		{RouteID: "c"},
+		{RouteID: "d", Completed: true, CompletionPercent: 100},
+	})
+	if progress.RouteCount != 4 || progress.CompletedCount != 2 || progress.CompletionPercent != 50 {
+		t.Fatalf("unexpected progress counts: %+v", progress)
+	}
+	if progress.TotalTime != 2400 || progress.EstimatedTime != 3000 || progress.RemainingTime != 600 {
+		t.Fatalf("unexpected progress times: %+v", progress)
+	}
+}
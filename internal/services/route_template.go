@@ -0,0 +1,168 @@
+// ゲームのルート構成をテンプレートとして保存し、別のゲームへ適用する操作を提供する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// maxRouteTemplateNameLength はテンプレート名の最大文字数。
+const maxRouteTemplateNameLength = 100
+
+// RouteTemplateService はルートテンプレート関連の操作を提供する。
+type RouteTemplateService struct {
+	repository RouteTemplateRepository
+	logger     *slog.Logger
+	withTx     TxRunner[RouteTemplateRepository]
+}
+
+// NewRouteTemplateService は RouteTemplateService を生成する。
+func NewRouteTemplateService(repository RouteTemplateRepository, logger *slog.Logger) *RouteTemplateService {
+	return &RouteTemplateService{repository: repository, logger: logger}
+}
+
+// SetTxRunner はテンプレート適用時の複数ルート作成をまとめるトランザクションを設定する。
+func (service *RouteTemplateService) SetTxRunner(runner TxRunner[RouteTemplateRepository]) {
+	service.withTx = runner
+}
+
+// ListRouteTemplates は保存済みのルートテンプレートを名前順で取得する。
+func (service *RouteTemplateService) ListRouteTemplates(ctx context.Context) ([]domain.RouteTemplate, error) {
+	templates, err := service.repository.ListRouteTemplates(ctx)
+	if err != nil {
+		service.logger.Error("ルートテンプレート取得に失敗", "error", err)
+		return nil, newServiceError("ルートテンプレート取得に失敗しました", err.Error())
+	}
+	return templates, nil
+}
+
+// SaveRouteTemplate はゲームのルートを表示順のままテンプレートとして保存する。
+// 同名のテンプレートがあればルート構成を上書きする。
+func (service *RouteTemplateService) SaveRouteTemplate(ctx context.Context, gameID string, name string) (*domain.RouteTemplate, error) {
+	trimmedGameID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	trimmedName, detail, ok := requireNonEmpty(name, "name")
+	if !ok {
+		return nil, newServiceError("テンプレート名が不正です", detail)
+	}
+	if len([]rune(trimmedName)) > maxRouteTemplateNameLength {
+		return nil, newServiceError("テンプレート名が不正です", "テンプレート名が長すぎます")
+	}
+	if _, err := service.getGame(ctx, service.repository, trimmedGameID); err != nil {
+		return nil, err
+	}
+	routes, err := service.repository.ListRoutesByGame(ctx, trimmedGameID)
+	if err != nil {
+		service.logger.Error("ルート取得に失敗", "error", err)
+		return nil, newServiceError("ルート取得に失敗しました", err.Error())
+	}
+	if len(routes) == 0 {
+		return nil, newServiceError("テンプレートにするルートがありません", "ゲームにルートが登録されていません")
+	}
+
+	names := make([]string, 0, len(routes))
+	for _, route := range routes {
+		names = append(names, route.Name)
+	}
+	saved, err := service.repository.SaveRouteTemplate(ctx, domain.RouteTemplate{Name: trimmedName, RouteNames: names})
+	if err != nil {
+		service.logger.Error("ルートテンプレート保存に失敗", "error", err)
+		return nil, newServiceError("ルートテンプレート保存に失敗しました", err.Error())
+	}
+	service.logger.Info("ルートテンプレートを保存", "templateId", saved.ID, "gameId", trimmedGameID, "routes", len(names))
+	return saved, nil
+}
+
+// ApplyRouteTemplate はテンプレートのルートをゲームの既存ルートの後ろへ追加し、追加したルートを返す。
+// 同名のルートが既にある場合は作成しないため、同じテンプレートを繰り返し適用しても重複しない。
+func (service *RouteTemplateService) ApplyRouteTemplate(ctx context.Context, templateID string, gameID string) ([]domain.Route, error) {
+	trimmedTemplateID, detail, ok := requireNonEmpty(templateID, "templateID")
+	if !ok {
+		return nil, newServiceError("テンプレートIDが不正です", detail)
+	}
+	trimmedGameID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return nil, newServiceError("ゲームIDが不正です", detail)
+	}
+	template, err := service.repository.GetRouteTemplateByID(ctx, trimmedTemplateID)
+	if err != nil {
+		service.logger.Error("ルートテンプレート取得に失敗", "error", err)
+		return nil, newServiceError("ルートテンプレート取得に失敗しました", err.Error())
+	}
+	if template == nil {
+		return nil, newServiceError("ルートテンプレートが見つかりません", "指定されたIDが存在しません")
+	}
+
+	created := []domain.Route{}
+	err = runInTx(ctx, service.withTx, service.repository, func(repository RouteTemplateRepository) error {
+		if _, err := service.getGame(ctx, repository, trimmedGameID); err != nil {
+			return err
+		}
+		existing, err := repository.ListRoutesByGame(ctx, trimmedGameID)
+		if err != nil {
+			service.logger.Error("ルート取得に失敗", "error", err)
+			return newServiceError("ルート取得に失敗しました", err.Error())
+		}
+		seen := make(map[string]struct{}, len(existing)+len(template.RouteNames))
+		nextOrder := int64(0)
+		for _, route := range existing {
+			seen[route.Name] = struct{}{}
+			if route.Order >= nextOrder {
+				nextOrder = route.Order + 1
+			}
+		}
+		for _, name := range template.RouteNames {
+			trimmed := strings.TrimSpace(name)
+			if trimmed == "" {
+				continue
+			}
+			if _, ok := seen[trimmed]; ok {
+				continue
+			}
+			seen[trimmed] = struct{}{}
+			route, err := repository.CreateRoute(ctx, domain.Route{Name: trimmed, Order: nextOrder, GameID: trimmedGameID})
+			if err != nil {
+				service.logger.Error("ルート作成に失敗", "error", err)
+				return newServiceError("ルート作成に失敗しました", err.Error())
+			}
+			created = append(created, *route)
+			nextOrder++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	service.logger.Info("ルートテンプレートを適用", "templateId", template.ID, "gameId", trimmedGameID, "created", len(created))
+	return created, nil
+}
+
+// DeleteRouteTemplate はルートテンプレートを削除する。適用済みのゲームのルートには影響しない。
+func (service *RouteTemplateService) DeleteRouteTemplate(ctx context.Context, templateID string) error {
+	trimmedTemplateID, detail, ok := requireNonEmpty(templateID, "templateID")
+	if !ok {
+		return newServiceError("テンプレートIDが不正です", detail)
+	}
+	if err := service.repository.DeleteRouteTemplate(ctx, trimmedTemplateID); err != nil {
+		service.logger.Error("ルートテンプレート削除に失敗", "error", err)
+		return newServiceError("ルートテンプレート削除に失敗しました", err.Error())
+	}
+	return nil
+}
+
+func (service *RouteTemplateService) getGame(ctx context.Context, repository RouteTemplateRepository, gameID string) (*domain.Game, error) {
+	game, err := repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		service.logger.Error("ゲーム取得に失敗", "error", err)
+		return nil, newServiceError("ゲーム取得に失敗しました", err.Error())
+	}
+	if game == nil {
+		return nil, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+	return game, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func routeTemplateTestGame(t *testing.T, runtime *maintenanceTestRuntime, title string) string {
+	t.Helper()
+	return createMaintenanceGame(t, runtime.repository, domain.Game{
+		Title: title, Publisher: "Pub", ExePath: "/" + title + ".exe", PlayStatus: domain.PlayStatusUnplayed,
+	}).ID
+}
+
+func TestRouteTemplateSaveAndApplyAppendsMissingRoutes(t *testing.T) {
+	t.Parallel()
+	runtime := newMaintenanceServiceRuntime(t)
+	ctx := context.Background()
+	service := NewRouteTemplateService(runtime.repository, newTestLogger())
+
+	source := routeTemplateTestGame(t, runtime, "Series 1")
+	target := routeTemplateTestGame(t, runtime, "Series 2")
+	for i, name := range []string{"共通", "ヒロインA", "ヒロインB"} {
+		if _, err := runtime.repository.CreateRoute(ctx, domain.Route{GameID: source, Name: name, Order: int64(i)}); err != nil {
+			t.Fatalf("CreateRoute: %v", err)
+		}
+	}
+	if _, err := runtime.repository.CreateRoute(ctx, domain.Route{GameID: target, Name: "共通", Order: 0}); err != nil {
+		t.Fatalf("CreateRoute: %v", err)
+	}
+
+	template, err := service.SaveRouteTemplate(ctx, source, "  シリーズ  ")
+	if err != nil {
+		t.Fatalf("SaveRouteTemplate: %v", err)
+	}
+	if template.Name != "シリーズ" || len(template.RouteNames) != 3 {
+		t.Fatalf("unexpected template: %+v", template)
+	}
+
+	created, err := service.ApplyRouteTemplate(ctx, template.ID, target)
+	if err != nil {
+		t.Fatalf("ApplyRouteTemplate: %v", err)
+	}
+	if len(created) != 2 || created[0].Name != "ヒロインA" || created[0].Order != 1 || created[1].Order != 2 {
+		t.Fatalf("expected missing routes to be appended, got %+v", created)
+	}
+
+	again, err := service.ApplyRouteTemplate(ctx, template.ID, target)
+	if err != nil {
+		t.Fatalf("ApplyRouteTemplate (again): %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("expected re-applying to create nothing, got %+v", again)
+	}
+	routes, err := runtime.repository.ListRoutesByGame(ctx, target)
+	if err != nil {
+		t.Fatalf("ListRoutesByGame: %v", err)
+	}
+	if len(routes) != 3 {
+		t.Fatalf("expected 3 routes on target, got %d", len(routes))
+	}
+}
+
+func TestRouteTemplateSaveRejectsGameWithoutRoutes(t *testing.T) {
+	t.Parallel()
+	runtime := newMaintenanceServiceRuntime(t)
+	service := NewRouteTemplateService(runtime.repository, newTestLogger())
+	gameID := routeTemplateTestGame(t, runtime, "Empty")
+
+	if _, err := service.SaveRouteTemplate(context.Background(), gameID, "空"); err == nil {
+		t.Fatal("expected error for a game without routes")
+	}
+	if _, err := service.ApplyRouteTemplate(context.Background(), "missing", gameID); err == nil {
+		t.Fatal("expected error for an unknown template")
+	}
+}
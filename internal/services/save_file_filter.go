@@ -0,0 +1,116 @@
+// セーブフォルダ内で同期するファイルを glob パターンで絞り込むフィルタを提供する。
+package services
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// maxSaveFilePatterns はゲームごとの include / exclude パターンそれぞれの上限。
+const maxSaveFilePatterns = 50
+
+// saveFileFilter はセーブフォルダからの相対パス（スラッシュ区切り）を同期対象にするかを判定する。
+// ゼロ値はすべてのファイルを対象にする。
+//
+// パターンは path.Match の glob で、次の規則で相対パスと照合する。
+//   - "/" を含まないパターン（"*.log"、"cache"）はどの階層のファイル名・ディレクトリ名にも一致する。
+//   - "/" を含むパターン（"saves/*.dat"）はセーブフォルダ直下から照合し、"**" は0個以上の階層に一致する。
+//   - ディレクトリに一致したパターンは、その配下のファイルすべてに一致する。
+type saveFileFilter struct {
+	include []string
+	exclude []string
+}
+
+func newSaveFileFilter(include, exclude []string) saveFileFilter {
+	return saveFileFilter{include: include, exclude: exclude}
+}
+
+// allows は relPath を同期対象にするかを返す。include があればいずれかに一致し、exclude のどれにも一致しないものが対象。
+func (filter saveFileFilter) allows(relPath string) bool {
+	if len(filter.include) > 0 && !matchAnySavePattern(filter.include, relPath) {
+		return false
+	}
+	return !matchAnySavePattern(filter.exclude, relPath)
+}
+
+func matchAnySavePattern(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if matchSavePattern(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSavePattern は pattern が relPath（またはその祖先ディレクトリ）に一致するかを返す。
+func matchSavePattern(pattern, relPath string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(relPath, "/")
+	if len(patternSegments) == 1 {
+		for _, segment := range pathSegments {
+			if ok, _ := path.Match(pattern, segment); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return matchSaveSegments(patternSegments, pathSegments)
+}
+
+// matchSaveSegments はパターンの階層を先頭から照合する。パターンを使い切れば残りの階層は一致したディレクトリの配下とみなす。
+func matchSaveSegments(patternSegments, pathSegments []string) bool {
+	if len(patternSegments) == 0 {
+		return true
+	}
+	if patternSegments[0] == "**" {
+		for i := 0; i <= len(pathSegments); i++ {
+			if matchSaveSegments(patternSegments[1:], pathSegments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(pathSegments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(patternSegments[0], pathSegments[0]); !ok {
+		return false
+	}
+	return matchSaveSegments(patternSegments[1:], pathSegments[1:])
+}
+
+// normalizeSaveFilePatterns はパターンの前後の空白・区切り文字を整え、重複と空を除いて検証する。空なら nil を返す。
+// Windows の区切り "\" は "/" に、先頭の "./" と "/" および末尾の "/" は取り除く。
+func normalizeSaveFilePatterns(patterns []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(patterns))
+	normalized := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		trimmed := strings.ReplaceAll(strings.TrimSpace(pattern), `\`, "/")
+		trimmed = strings.TrimPrefix(trimmed, "./")
+		trimmed = strings.Trim(trimmed, "/")
+		if trimmed == "" {
+			continue
+		}
+		for _, segment := range strings.Split(trimmed, "/") {
+			if segment == "" || segment == "." || segment == ".." {
+				return nil, fmt.Errorf("invalid pattern: %s", pattern)
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern: %s", pattern)
+			}
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		normalized = append(normalized, trimmed)
+	}
+	if len(normalized) > maxSaveFilePatterns {
+		return nil, fmt.Errorf("patterns must be at most %d", maxSaveFilePatterns)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
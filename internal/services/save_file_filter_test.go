@@ -0,0 +1,122 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestSaveFileFilterAllows(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		include []string
+		exclude []string
+		path    string
+		want    bool
+	}{
+		{name: "zero value allows everything", path: "logs/debug.log", want: true},
+		{name: "basename pattern at any depth", exclude: []string{"*.log"}, path: "a/b/debug.log", want: false},
+		{name: "directory name excludes its contents", exclude: []string{"cache"}, path: "data/cache/shader.bin", want: false},
+		{name: "anchored pattern", exclude: []string{"data/*.tmp"}, path: "other/data/x.tmp", want: true},
+		{name: "anchored directory prefix", exclude: []string{"data/cache"}, path: "data/cache/a/b.bin", want: false},
+		{name: "double star", exclude: []string{"**/crash/*.dmp"}, path: "a/b/crash/1.dmp", want: false},
+		{name: "double star at root", exclude: []string{"**/crash/*.dmp"}, path: "crash/1.dmp", want: false},
+		{name: "include limits to matches", include: []string{"saves/*.dat"}, path: "config.ini", want: false},
+		{name: "include match", include: []string{"saves/*.dat"}, path: "saves/slot1.dat", want: true},
+		{name: "exclude wins over include", include: []string{"saves"}, exclude: []string{"*.bak"}, path: "saves/slot1.bak", want: false},
+	}
+	for _, tc := range cases {
+		filter := newSaveFileFilter(tc.include, tc.exclude)
+		if got := filter.allows(tc.path); got != tc.want {
+			t.Errorf("%s: allows(%q) = %v, want %v", tc.name, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeSaveFilePatterns(t *testing.T) {
+	t.Parallel()
+
+	normalized, err := normalizeSaveFilePatterns([]string{" ./logs/ ", `cache\shaders`, "logs", "", "*.log"})
+	if err != nil {
+		t.Fatalf("normalizeSaveFilePatterns: %v", err)
+	}
+	want := []string{"logs", "cache/shaders", "*.log"}
+	if len(normalized) != len(want) {
+		t.Fatalf("unexpected patterns: %v", normalized)
+	}
+	for i := range want {
+		if normalized[i] != want[i] {
+			t.Fatalf("unexpected patterns: %v", normalized)
+		}
+	}
+
+	if normalized, err := normalizeSaveFilePatterns([]string{" ", "/"}); err != nil || normalized != nil {
+		t.Fatalf("blank patterns should clear: %v err=%v", normalized, err)
+	}
+	for _, invalid := range []string{"[abc", "../outside", "a//b"} {
+		if _, err := normalizeSaveFilePatterns([]string{invalid}); err == nil {
+			t.Errorf("%q should be rejected", invalid)
+		}
+	}
+}
+
+func TestSaveFileFilterAppliesToSnapshotsAndDeletions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for rel, content := range map[string]string{
+		"slot1.sav":        "save",
+		"logs/output.log":  "log",
+		"cache/shader.bin": "cache",
+	} {
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filter := newSaveFileFilter(nil, []string{"*.log", "cache"})
+
+	tree, err := buildSaveTree(dir, filter)
+	if err != nil {
+		t.Fatalf("buildSaveTree: %v", err)
+	}
+	snap, blobs, err := buildSaveSnapshot(dir, filter)
+	if err != nil {
+		t.Fatalf("buildSaveSnapshot: %v", err)
+	}
+	for _, files := range []map[string]domain.BlobHash{tree.Files, snap.Files} {
+		if len(files) != 1 || files["slot1.sav"] == "" {
+			t.Fatalf("only slot1.sav should be included, got %v", files)
+		}
+	}
+	if len(blobs) != 1 {
+		t.Fatalf("excluded files should not be read into blobs, got %d", len(blobs))
+	}
+	unfiltered, err := saveTreeHash(dir, saveFileFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered, err := saveTreeHash(dir, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unfiltered == filtered {
+		t.Fatal("excluded files should be left out of the hash")
+	}
+
+	// 対象外のファイルはリモートに無くても削除候補にしない。
+	tracked, untracked, err := planDeletions(dir, filter, domain.SaveSnapshot{Files: map[string]domain.BlobHash{}}, map[string]struct{}{"slot1.sav": {}})
+	if err != nil {
+		t.Fatalf("planDeletions: %v", err)
+	}
+	if len(tracked) != 1 || tracked[0] != "slot1.sav" || len(untracked) != 0 {
+		t.Fatalf("unexpected deletions: tracked=%v untracked=%v", tracked, untracked)
+	}
+}
@@ -0,0 +1,324 @@
+// セーブフォルダへの書き込みを検出してローカルセーブハッシュを更新する監視を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/logging"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultSaveWatchDebounce は同じゲームのセーブフォルダへの連続した書き込みをまとめて1回の再計算にする待ち時間。
+// ゲームは終了時に複数のセーブファイルを続けて書き出すため、メモの監視より長めに待ってからハッシュを取り直す。
+const defaultSaveWatchDebounce = 2 * time.Second
+
+// SaveFolderWatcher は各ゲームのセーブフォルダ（サブディレクトリを含む）への書き込みを監視し、
+// 内容のハッシュが Game.LocalSaveHash と異なれば更新して通知する。
+// プレイ中のゲームはセッション終了時にプロセス監視がハッシュを記録するため、変更を検出しても何もしない。
+// 監視対象はアーカイブされていない、セーブフォルダが存在するゲームで、ゲームの追加・変更後は Reload で取り直す。
+type SaveFolderWatcher struct {
+	repository SaveFolderWatchRepository
+	logger     *slog.Logger
+	debounce   time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	watcher   *fsnotify.Watcher
+	ctx       context.Context
+	roots     map[string]string // セーブフォルダ → ゲームID
+	dirs      map[string]string // 監視中のディレクトリ → 属するセーブフォルダ
+	pending   map[string]*time.Timer
+	onChanged func(domain.Game)
+	isPlaying func(gameID string) bool
+	done      chan struct{}
+}
+
+// NewSaveFolderWatcher は SaveFolderWatcher を生成する。
+func NewSaveFolderWatcher(repository SaveFolderWatchRepository, logger *slog.Logger) *SaveFolderWatcher {
+	return &SaveFolderWatcher{
+		repository: repository,
+		logger:     logger,
+		debounce:   defaultSaveWatchDebounce,
+		now:        time.Now,
+		roots:      make(map[string]string),
+		dirs:       make(map[string]string),
+		pending:    make(map[string]*time.Timer),
+	}
+}
+
+// SetOnChanged はローカルセーブハッシュを更新したときの通知先を設定する（画面の更新・アップロードの確認用）。
+func (watcher *SaveFolderWatcher) SetOnChanged(fn func(domain.Game)) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	watcher.onChanged = fn
+}
+
+// SetPlayingChecker はゲームがプレイ中かを判定する関数を設定する。プレイ中のゲームの変更は反映しない。
+func (watcher *SaveFolderWatcher) SetPlayingChecker(fn func(gameID string) bool) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	watcher.isPlaying = fn
+}
+
+// Start はセーブフォルダの監視を開始する。既に開始済みなら何もしない。
+func (watcher *SaveFolderWatcher) Start(ctx context.Context) error {
+	watcher.mu.Lock()
+	if watcher.watcher != nil {
+		watcher.mu.Unlock()
+		return nil
+	}
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		watcher.mu.Unlock()
+		return err
+	}
+	watcher.watcher = fsWatcher
+	watcher.ctx = ctx
+	watcher.done = make(chan struct{})
+	go watcher.loop(fsWatcher, watcher.done)
+	watcher.mu.Unlock()
+	return watcher.Reload(ctx)
+}
+
+// Stop は監視を停止し、反映待ちの変更を破棄する。
+func (watcher *SaveFolderWatcher) Stop() {
+	watcher.mu.Lock()
+	fsWatcher, done := watcher.watcher, watcher.done
+	watcher.watcher = nil
+	watcher.roots = make(map[string]string)
+	watcher.dirs = make(map[string]string)
+	for gameID, timer := range watcher.pending {
+		timer.Stop()
+		delete(watcher.pending, gameID)
+	}
+	watcher.mu.Unlock()
+	if fsWatcher == nil {
+		return
+	}
+	_ = fsWatcher.Close()
+	<-done
+}
+
+// Reload はゲーム一覧から監視対象のセーブフォルダを取り直す。
+// 外れたゲームのディレクトリは監視をやめ、新しく加わったセーブフォルダは配下を含めて監視する。
+// セーブフォルダがまだ存在しないゲームは、作成後の Reload から監視する。開始前は何もしない。
+func (watcher *SaveFolderWatcher) Reload(ctx context.Context) error {
+	watcher.mu.Lock()
+	running := watcher.watcher != nil
+	watcher.mu.Unlock()
+	if !running {
+		return nil
+	}
+	games, err := watcher.repository.ListGames(ctx, "", "", "title", "asc")
+	if err != nil {
+		return err
+	}
+	desired := make(map[string]string, len(games))
+	for _, game := range games {
+		if root := saveFolderRoot(game); root != "" {
+			desired[root] = game.ID
+		}
+	}
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.watcher == nil {
+		return nil
+	}
+	for dir, root := range watcher.dirs {
+		if gameID, ok := desired[root]; ok && gameID == watcher.roots[root] {
+			continue
+		}
+		_ = watcher.watcher.Remove(dir)
+		delete(watcher.dirs, dir)
+	}
+	watcher.roots = desired
+	for root := range desired {
+		if _, ok := watcher.dirs[root]; ok {
+			continue
+		}
+		watcher.addTree(root, root)
+	}
+	return nil
+}
+
+func (watcher *SaveFolderWatcher) loop(fsWatcher *fsnotify.Watcher, done chan struct{}) {
+	defer close(done)
+	defer logging.Recover(watcher.logger, "save-watcher.loop")
+	for {
+		select {
+		case event, ok := <-fsWatcher.Events:
+			if !ok {
+				return
+			}
+			watcher.handleEvent(event)
+		case err, ok := <-fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			watcher.logger.Warn("セーブフォルダの監視でエラー", "error", err)
+		}
+	}
+}
+
+func (watcher *SaveFolderWatcher) handleEvent(event fsnotify.Event) {
+	if event.Op == fsnotify.Chmod {
+		return
+	}
+	watcher.mu.Lock()
+	root, gameID := watcher.ownerLocked(event.Name)
+	if gameID == "" {
+		watcher.mu.Unlock()
+		return
+	}
+	path := filepath.Clean(event.Name)
+	if event.Has(fsnotify.Create) {
+		// セーブフォルダ内に作られたサブディレクトリも作成時点から監視する。
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			watcher.addTree(path, root)
+		}
+	}
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		delete(watcher.dirs, path)
+	}
+	watcher.mu.Unlock()
+	watcher.schedule(gameID)
+}
+
+// ownerLocked は path を含むセーブフォルダとそのゲームIDを返す。監視対象外なら空文字を返す。mu を保持して呼ぶ。
+func (watcher *SaveFolderWatcher) ownerLocked(path string) (string, string) {
+	current := filepath.Clean(path)
+	for {
+		if gameID, ok := watcher.roots[current]; ok {
+			return current, gameID
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", ""
+		}
+		current = parent
+	}
+}
+
+// addTree は dir と配下のディレクトリを監視に加える。mu を保持して呼ぶ。
+func (watcher *SaveFolderWatcher) addTree(dir, root string) {
+	err := filepath.WalkDir(dir, func(walkPath string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if walkPath == dir {
+				return walkErr
+			}
+			return nil
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if _, ok := watcher.dirs[walkPath]; ok {
+			return nil
+		}
+		if err := watcher.watcher.Add(walkPath); err != nil {
+			watcher.logger.Warn("セーブフォルダを監視できません", "dir", walkPath, "error", err)
+			return nil
+		}
+		watcher.dirs[walkPath] = root
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		watcher.logger.Warn("セーブフォルダを監視できません", "dir", dir, "error", err)
+	}
+}
+
+// schedule は gameID のハッシュ再計算を debounce 後に行うよう予約する。待機中に再び変更されたら待ち直す。
+func (watcher *SaveFolderWatcher) schedule(gameID string) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.watcher == nil {
+		return
+	}
+	if timer, ok := watcher.pending[gameID]; ok {
+		timer.Reset(watcher.debounce)
+		return
+	}
+	watcher.pending[gameID] = time.AfterFunc(watcher.debounce, func() {
+		watcher.mu.Lock()
+		delete(watcher.pending, gameID)
+		ctx, onChanged, running := watcher.ctx, watcher.onChanged, watcher.watcher != nil
+		watcher.mu.Unlock()
+		if !running {
+			return
+		}
+		defer logging.Recover(watcher.logger, "save-watcher.apply")
+		updated, err := watcher.applySaveChange(ctx, gameID)
+		if err != nil {
+			watcher.logger.Warn("セーブフォルダの変更を反映できません", "gameId", gameID, "error", err)
+			return
+		}
+		if updated != nil && onChanged != nil {
+			onChanged(*updated)
+		}
+	})
+}
+
+// applySaveChange はゲームのセーブフォルダのハッシュを計算し直し、変わっていれば LocalSaveHash を更新したゲームを返す。
+// プレイ中・アーカイブ済み・セーブフォルダが未設定か存在しない・ハッシュが同じ場合は何もせず nil を返す。
+func (watcher *SaveFolderWatcher) applySaveChange(ctx context.Context, gameID string) (*domain.Game, error) {
+	watcher.mu.Lock()
+	isPlaying := watcher.isPlaying
+	watcher.mu.Unlock()
+	if isPlaying != nil && isPlaying(gameID) {
+		return nil, nil
+	}
+	game, err := watcher.repository.GetGameByID(ctx, gameID)
+	if err != nil || game == nil {
+		return nil, err
+	}
+	if game.IsArchived() {
+		return nil, nil
+	}
+	root := saveFolderRoot(*game)
+	if root == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	hash, err := saveTreeHash(root, newSaveFileFilter(game.SaveIncludePatterns, game.SaveExcludePatterns))
+	if err != nil {
+		return nil, err
+	}
+	if game.LocalSaveHash != nil && *game.LocalSaveHash == hash {
+		return nil, nil
+	}
+	// ハッシュの計算中に画面から行われた編集（プレイ時間・お気に入り等）を上書きしないよう、
+	// 行全体ではなくハッシュの列だけを更新し、更新後のゲームを読み直す。
+	if err := watcher.repository.SetLocalSaveHash(ctx, gameID, hash, watcher.now()); err != nil {
+		return nil, err
+	}
+	updated, err := watcher.repository.GetGameByID(ctx, gameID)
+	if err != nil || updated == nil {
+		return nil, err
+	}
+	watcher.logger.Info("セーブフォルダの変更を検出してローカルセーブハッシュを更新", "gameId", gameID)
+	return updated, nil
+}
+
+// saveFolderRoot は監視対象にするセーブフォルダのパスを返す。アーカイブ済み・未設定なら空文字を返す。
+func saveFolderRoot(game domain.Game) string {
+	if game.IsArchived() || game.SaveFolderPath == nil {
+		return ""
+	}
+	trimmed := strings.TrimSpace(*game.SaveFolderPath)
+	if trimmed == "" {
+		return ""
+	}
+	return filepath.Clean(trimmed)
+}
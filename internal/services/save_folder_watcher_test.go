@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/db"
+)
+
+func newSaveWatcherFixture(t *testing.T) (*db.Repository, *domain.Game, string) {
+	t.Helper()
+	appDataDir := t.TempDir()
+	connection, err := db.Open(filepath.Join(appDataDir, "app.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = connection.Close() })
+	if err := db.ApplyMigrations(connection); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repository := db.NewRepository(connection)
+	saveDir := filepath.Join(appDataDir, "saves")
+	if err := os.MkdirAll(filepath.Join(saveDir, "slot"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(saveDir, "slot", "save1.dat"), []byte("v1"), 0o600); err != nil {
+		t.Fatalf("write save: %v", err)
+	}
+	game, err := repository.CreateGame(context.Background(), domain.Game{
+		Title: "Game", Publisher: "Pub", ExePath: "/game.exe", PlayStatus: domain.PlayStatusUnplayed, SaveFolderPath: &saveDir,
+	})
+	if err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+	return repository, game, saveDir
+}
+
+func TestSaveFolderWatcherApplySaveChangeUpdatesHashOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repository, game, saveDir := newSaveWatcherFixture(t)
+	watcher := NewSaveFolderWatcher(repository, newTestLogger())
+	fixed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	watcher.now = func() time.Time { return fixed }
+
+	updated, err := watcher.applySaveChange(ctx, game.ID)
+	if err != nil || updated == nil || updated.LocalSaveHash == nil {
+		t.Fatalf("first scan should record the hash: %+v err=%v", updated, err)
+	}
+	if updated.LocalSaveHashUpdatedAt == nil || !updated.LocalSaveHashUpdatedAt.Equal(fixed) {
+		t.Fatalf("unexpected LocalSaveHashUpdatedAt: %v", updated.LocalSaveHashUpdatedAt)
+	}
+	first := *updated.LocalSaveHash
+
+	if again, err := watcher.applySaveChange(ctx, game.ID); err != nil || again != nil {
+		t.Fatalf("unchanged folder should be ignored: %+v err=%v", again, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(saveDir, "slot", "save1.dat"), []byte("v2"), 0o600); err != nil {
+		t.Fatalf("write save: %v", err)
+	}
+	updated, err = watcher.applySaveChange(ctx, game.ID)
+	if err != nil || updated == nil || updated.LocalSaveHash == nil || *updated.LocalSaveHash == first {
+		t.Fatalf("changed folder should update the hash: %+v err=%v", updated, err)
+	}
+	stored, _ := repository.GetGameByID(ctx, game.ID)
+	if stored.LocalSaveHash == nil || *stored.LocalSaveHash != *updated.LocalSaveHash {
+		t.Fatalf("hash was not stored: %+v", stored.LocalSaveHash)
+	}
+}
+
+func TestSaveFolderWatcherApplySaveChangeSkipsActiveSession(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repository, game, _ := newSaveWatcherFixture(t)
+	watcher := NewSaveFolderWatcher(repository, newTestLogger())
+	watcher.SetPlayingChecker(func(gameID string) bool { return gameID == game.ID })
+
+	if updated, err := watcher.applySaveChange(ctx, game.ID); err != nil || updated != nil {
+		t.Fatalf("changes during a session should be left to the process monitor: %+v err=%v", updated, err)
+	}
+	stored, _ := repository.GetGameByID(ctx, game.ID)
+	if stored.LocalSaveHash != nil {
+		t.Fatalf("hash should not be recorded during a session: %v", *stored.LocalSaveHash)
+	}
+}
+
+func TestSaveFolderWatcherDetectsWritesInSubdirectories(t *testing.T) {
+	t.Parallel()
+
+	repository, game, saveDir := newSaveWatcherFixture(t)
+	watcher := NewSaveFolderWatcher(repository, newTestLogger())
+	watcher.debounce = 50 * time.Millisecond
+	notified := make(chan domain.Game, 4)
+	watcher.SetOnChanged(func(updated domain.Game) { notified <- updated })
+	if err := watcher.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer watcher.Stop()
+
+	for _, content := range []string{"a", "b"} {
+		if err := os.WriteFile(filepath.Join(saveDir, "slot", "save2.dat"), []byte(content), 0o600); err != nil {
+			t.Fatalf("write save: %v", err)
+		}
+	}
+	select {
+	case updated := <-notified:
+		if updated.ID != game.ID || updated.LocalSaveHash == nil {
+			t.Fatalf("unexpected notification: %+v", updated)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("save change was not detected")
+	}
+	select {
+	case extra := <-notified:
+		t.Fatalf("consecutive writes should be applied once, got extra %+v", extra)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// editDuringHashRepository は SetLocalSaveHash の直前に、ハッシュ計算中の画面からの編集を模してタイトルを書き換える。
+type editDuringHashRepository struct {
+	*db.Repository
+	edit func()
+}
+
+func (repository editDuringHashRepository) SetLocalSaveHash(ctx context.Context, gameID, hash string, updatedAt time.Time) error {
+	repository.edit()
+	return repository.Repository.SetLocalSaveHash(ctx, gameID, hash, updatedAt)
+}
+
+func TestSaveFolderWatcherApplySaveChangeKeepsConcurrentEdits(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	repository, game, _ := newSaveWatcherFixture(t)
+	var edited *domain.Game
+	watcher := NewSaveFolderWatcher(editDuringHashRepository{Repository: repository, edit: func() {
+		current, _ := repository.GetGameByID(ctx, game.ID)
+		current.Title = "renamed while hashing"
+		var err error
+		if edited, err = repository.UpdateGame(ctx, *current); err != nil {
+			t.Errorf("edit failed: %v", err)
+		}
+	}}, newTestLogger())
+
+	updated, err := watcher.applySaveChange(ctx, game.ID)
+	if err != nil || updated == nil || updated.LocalSaveHash == nil {
+		t.Fatalf("scan should record the hash: %+v err=%v", updated, err)
+	}
+	if updated.Title != "renamed while hashing" {
+		t.Fatalf("concurrent edit was overwritten: %q", updated.Title)
+	}
+	if !updated.UpdatedAt.Equal(edited.UpdatedAt) {
+		t.Fatalf("hash-only change bumped updatedAt: %v -> %v", edited.UpdatedAt, updated.UpdatedAt)
+	}
+}
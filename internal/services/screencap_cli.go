@@ -10,9 +10,19 @@ import (
 	"strings"
 )
 
+// screencapMethod は screencap-cli に指定するキャプチャ方式。CaptureResult.Backend にもこの値を返す。
+const screencapMethod = "wgc-window"
+
+// captureDetail は captureFunc が返す撮影の詳細。保存先やかかった時間は呼び出し側で埋める。
+type captureDetail struct {
+	Backend  string
+	Hwnd     uint64
+	Warnings []string
+}
+
 // buildScreencapArgs は screencap-cli.exe の cap サブコマンド引数を組み立てる。
 func buildScreencapArgs(pid int, outPath string, localJpeg bool, jpegQuality int, clientOnly bool) []string {
-	args := []string{"cap", "--method", "wgc-window"}
+	args := []string{"cap", "--method", screencapMethod}
 	if pid > 0 {
 		args = append(args, "--pid", strconv.Itoa(pid))
 	} else {
@@ -40,7 +50,9 @@ type screencapResult struct {
 	OK         bool                `json:"ok"`
 	OutPath    string              `json:"out_path"`
 	ImageStats screencapImageStats `json:"image_stats"`
-	Error      *screencapError     `json:"error"`
+	// Hwnd は撮影したウィンドウのハンドル。数値/"0x..." 文字列いずれの表現もあり得るため RawMessage で受ける。
+	Hwnd  json.RawMessage `json:"hwnd"`
+	Error *screencapError `json:"error"`
 }
 
 // screencapImageStats はキャプチャ画像の統計情報。使用するフィールドのみ定義する。
@@ -69,6 +81,19 @@ func parseScreencapResult(stdout []byte) (*screencapResult, error) {
 	return &result, nil
 }
 
+// parseScreencapHwnd は結果JSONの hwnd を数値に変換する。無い・解釈できない場合は 0。
+func parseScreencapHwnd(raw json.RawMessage) uint64 {
+	text := strings.TrimSpace(strings.Trim(strings.TrimSpace(string(raw)), `"`))
+	if text == "" || text == "null" {
+		return 0
+	}
+	value, err := strconv.ParseUint(text, 0, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
 // screencapErrorFromResult は screencap-cli の結果JSONから失敗エラーを組み立てる。
 // error 情報があれば message/where/hresult を含め、無ければ汎用文言を返す。
 func screencapErrorFromResult(result *screencapResult) error {
@@ -0,0 +1,235 @@
+// アーカイブしたゲームのローカルスクリーンショットを zip にまとめる・戻す処理を提供する。
+package services
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// screenshotArchiveDirName は screenshots 配下で zip をまとめて置くディレクトリ名。
+// ゲームID のディレクトリと衝突しないよう、ID に使われない記号を含める。
+const screenshotArchiveDirName = "_archived"
+
+func (service *ScreenshotService) screenshotsRoot() string {
+	return localScreenshotsRoot(service.appDataDir)
+}
+
+func (service *ScreenshotService) screenshotArchivePath(gameID string) string {
+	return filepath.Join(service.screenshotsRoot(), screenshotArchiveDirName, gameID+".zip")
+}
+
+// CompressGameScreenshots はゲームのスクリーンショットを zip にまとめ、元ファイルを削除する。
+// 既に zip がある場合（アーカイブ中に撮影された分など）は既存の中身を引き継いで追記する。
+// まとめたファイル数を返す。スクリーンショットが無ければ 0 を返す。
+func (service *ScreenshotService) CompressGameScreenshots(gameID string) (int, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return 0, newServiceError("ゲームIDが不正です", detail)
+	}
+	sourceDir := filepath.Join(service.screenshotsRoot(), trimmedID)
+	files, err := listScreenshotFiles(sourceDir)
+	if err != nil {
+		service.logger.Error("スクリーンショット一覧の取得に失敗", "gameId", trimmedID, "error", err)
+		return 0, newServiceError("スクリーンショットの圧縮に失敗しました", err.Error())
+	}
+	if len(files) == 0 {
+		return 0, nil
+	}
+
+	archivePath := service.screenshotArchivePath(trimmedID)
+	if err := writeScreenshotArchive(archivePath, sourceDir, files); err != nil {
+		service.logger.Error("スクリーンショットの圧縮に失敗", "gameId", trimmedID, "error", err)
+		return 0, newServiceError("スクリーンショットの圧縮に失敗しました", err.Error())
+	}
+	// zip の書き込みが完了してから元ファイルを消す（途中失敗で消失しないように）。
+	if err := os.RemoveAll(sourceDir); err != nil {
+		service.logger.Warn("圧縮済みスクリーンショットの削除に失敗", "gameId", trimmedID, "error", err)
+	}
+	service.logger.Info("スクリーンショットを圧縮", "gameId", trimmedID, "count", len(files))
+	return len(files), nil
+}
+
+// RestoreGameScreenshots は CompressGameScreenshots で作った zip を展開し、zip を削除する。
+// 同名ファイルが既にある場合は上書きしない。展開したファイル数を返す。zip が無ければ 0 を返す。
+func (service *ScreenshotService) RestoreGameScreenshots(gameID string) (int, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return 0, newServiceError("ゲームIDが不正です", detail)
+	}
+	archivePath := service.screenshotArchivePath(trimmedID)
+	if _, err := os.Stat(archivePath); errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+
+	restored, err := extractScreenshotArchive(archivePath, filepath.Join(service.screenshotsRoot(), trimmedID))
+	if err != nil {
+		service.logger.Error("スクリーンショットの展開に失敗", "gameId", trimmedID, "error", err)
+		return 0, newServiceError("スクリーンショットの展開に失敗しました", err.Error())
+	}
+	if err := os.Remove(archivePath); err != nil {
+		service.logger.Warn("展開済み zip の削除に失敗", "gameId", trimmedID, "error", err)
+	}
+	service.logger.Info("スクリーンショットを展開", "gameId", trimmedID, "count", restored)
+	return restored, nil
+}
+
+// listScreenshotFiles は dir 配下のファイルをスラッシュ区切りの相対パスで返す。dir が無ければ空。
+func listScreenshotFiles(dir string) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path == dir && errors.Is(walkErr, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return walkErr
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+// writeScreenshotArchive は一時ファイルに zip を書き出してから archivePath へ置き換える。
+func writeScreenshotArchive(archivePath, sourceDir string, files []string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0o700); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(archivePath), ".screenshots-*.zip")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	defer func() {
+		if err != nil {
+			_ = tempFile.Close()
+			_ = os.Remove(tempPath)
+		}
+	}()
+
+	writer := zip.NewWriter(tempFile)
+	written := make(map[string]struct{}, len(files))
+	for _, rel := range files {
+		if err := addFileToZip(writer, filepath.Join(sourceDir, filepath.FromSlash(rel)), rel); err != nil {
+			return err
+		}
+		written[rel] = struct{}{}
+	}
+	if err := copyExistingArchive(writer, archivePath, written); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, archivePath)
+}
+
+func addFileToZip(writer *zip.Writer, sourcePath, name string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	target, err := writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(target, source)
+	return err
+}
+
+// copyExistingArchive は既存 zip のエントリのうち、今回追加していないものを writer へ移す。
+func copyExistingArchive(writer *zip.Writer, archivePath string, skip map[string]struct{}) error {
+	existing, err := zip.OpenReader(archivePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = existing.Close() }()
+	for _, entry := range existing.File {
+		if _, ok := skip[entry.Name]; ok {
+			continue
+		}
+		if err := writer.Copy(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractScreenshotArchive(archivePath, targetDir string) (int, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	restored := 0
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		targetPath, err := storage.ResolveSafeRelativePath(targetDir, entry.Name)
+		if err != nil {
+			return restored, err
+		}
+		if _, err := os.Stat(targetPath); err == nil {
+			continue
+		}
+		if err := extractZipEntry(entry, targetPath); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+func extractZipEntry(entry *zip.File, targetPath string) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0o700); err != nil {
+		return err
+	}
+	source, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+	target, err := os.OpenFile(targetPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(target, source); err != nil {
+		_ = target.Close()
+		return err
+	}
+	if err := target.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(targetPath, entry.Modified, entry.Modified)
+}
@@ -0,0 +1,96 @@
+// プレイ中の定期スクリーンショット（自動撮影）の撮影と、枚数上限による整理を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// autoScreenshotSuffix は自動撮影したファイル名に付ける印。枚数上限の整理は印のあるファイルだけを対象にし、
+// 手動・ホットキーで撮ったスクリーンショットは消さない。
+const autoScreenshotSuffix = "_auto"
+
+// CaptureAutoScreenshot はゲームのウィンドウが前面にあるときだけ1枚撮影し、自動撮影分を maxCount 枚に整理する。
+// 前面に無い・プロセスが見つからない場合は撮影せず captured=false を返す（エラーにはしない）。
+func (service *ScreenshotService) CaptureAutoScreenshot(ctx context.Context, gameID string, maxCount int) (domain.CaptureResult, bool, error) {
+	game, err := service.repository.GetGameByID(ctx, gameID)
+	if err != nil {
+		return domain.CaptureResult{}, false, err
+	}
+	if game == nil || service.resolver == nil || service.foregroundFunc == nil || strings.TrimSpace(game.ExePath) == "" {
+		return domain.CaptureResult{}, false, nil
+	}
+	foregroundPID, _, err := service.foregroundFunc()
+	if err != nil {
+		return domain.CaptureResult{}, false, nil
+	}
+	pids, err := service.resolver.FindProcessIDsByExe(strings.TrimSpace(game.ExePath))
+	if err != nil {
+		return domain.CaptureResult{}, false, err
+	}
+	if foregroundPID == 0 || !slices.Contains(pids, foregroundPID) {
+		return domain.CaptureResult{}, false, nil
+	}
+
+	saveDir := filepath.Join(service.screenshotsRoot(), game.ID)
+	if err := os.MkdirAll(saveDir, 0o700); err != nil {
+		return domain.CaptureResult{}, false, err
+	}
+	fullPath := filepath.Join(saveDir, service.screenshotFileName(service.now(), game.ID, autoScreenshotSuffix))
+	captured, err := service.capture(ctx, foregroundPID, fullPath, game.ID)
+	if err != nil {
+		service.logCapture(slog.LevelWarn, "定期スクリーンショットの取得に失敗", "gameId", game.ID, "error", err)
+		return domain.CaptureResult{}, false, err
+	}
+	service.logCapture(slog.LevelInfo, "定期スクリーンショット保存完了", "gameId", game.ID, "output", fullPath)
+	if removed, err := pruneAutoScreenshots(saveDir, game.ID, maxCount); err != nil {
+		service.logCapture(slog.LevelWarn, "定期スクリーンショットの整理に失敗", "gameId", game.ID, "error", err)
+	} else if removed > 0 {
+		service.logCapture(slog.LevelInfo, "古い定期スクリーンショットを削除", "gameId", game.ID, "count", removed)
+	}
+	return captured, true, nil
+}
+
+// pruneAutoScreenshots は saveDir 内の自動撮影分を新しい順に maxCount 枚だけ残し、削除した枚数を返す。
+// ファイル名は撮影日時から始まるため、名前順がそのまま撮影順になる。
+func pruneAutoScreenshots(saveDir, gameID string, maxCount int) (int, error) {
+	if maxCount <= 0 {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(saveDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	marker := fmt.Sprintf("_%s%s.", gameID, autoScreenshotSuffix)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.Contains(entry.Name(), marker) {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= maxCount {
+		return 0, nil
+	}
+	sort.Strings(names)
+	removed := 0
+	for _, name := range names[:len(names)-maxCount] {
+		if err := os.Remove(filepath.Join(saveDir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
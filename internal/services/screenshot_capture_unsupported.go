@@ -8,8 +8,13 @@ import (
 	"errors"
 )
 
-// captureWithScreencap は非Windowsではサポート外。captureFunc がエラーを返すため、
+// captureWindow は非Windowsではサポート外。captureFunc がエラーを返すため、
 // CaptureHotkey / CaptureGameScreenshot のオーケストレーション自体は共有ファイルで検証できる。
-func (service *ScreenshotService) captureWithScreencap(ctx context.Context, pid int, outPath string) error {
-	return errors.New("screenshot capture is only supported on Windows")
+func (service *ScreenshotService) captureWindow(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+	return captureDetail{}, errors.New("screenshot capture is only supported on Windows")
+}
+
+// foregroundProcess は非Windowsではサポート外。
+func foregroundProcess() (int, string, error) {
+	return 0, "", errors.New("foreground window lookup is only supported on Windows")
 }
@@ -1,6 +1,6 @@
 //go:build windows
 
-// Windows向け screencap-cli によるスクリーンショット撮影を実装する。
+// Windows向けのスクリーンショット撮影（プロセス内の WGC、だめなら screencap-cli、最後に画面からの切り出し）を実装する。
 package services
 
 import (
@@ -31,12 +31,43 @@ var (
 	screencapPathCache string
 )
 
+// captureWindow はウィンドウを撮影して outPath に保存する。pid が 0 のときはフォアグラウンドウィンドウを対象にする。
+// まずプロセス内の WGC（captureWithBuiltinWGC）で撮り、撮れなければ同梱の screencap-cli.exe に任せる。
+// screencap-cli が無い配布形態（単一の exe）でも、WGC が使える環境ならそのまま撮影できる。
+func (service *ScreenshotService) captureWindow(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+	detail, err := service.captureWithBuiltinWGC(ctx, pid, outPath)
+	if err == nil || ctx.Err() != nil {
+		return detail, err
+	}
+	service.logCapture(slog.LevelInfo, "プロセス内の WGC で撮影できないため screencap-cli を使います", "pid", pid, "error", err)
+	return service.captureWithScreencap(ctx, pid, outPath)
+}
+
 // captureWithScreencap は同梱の screencap-cli.exe を呼び出して outPath に画像を保存する。
 // pid が 0 のときはフォアグラウンドウィンドウを対象にする。
-func (service *ScreenshotService) captureWithScreencap(ctx context.Context, pid int, outPath string) error {
+// screencap-cli が使えない・失敗した場合は、ウィンドウを含むモニターの画面から切り出す（captureFromMonitors）。
+// 切り出しにも失敗したときは screencap-cli のエラーを返す。
+func (service *ScreenshotService) captureWithScreencap(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+	detail, err := service.runScreencap(ctx, pid, outPath)
+	if err == nil || ctx.Err() != nil {
+		return detail, err
+	}
+	service.logCapture(slog.LevelWarn, "screencap-cli で撮影できないため画面からの切り出しを試します", "pid", pid, "error", err)
+	fallback, fallbackErr := service.captureFromMonitors(pid, outPath)
+	if fallbackErr != nil {
+		service.logCapture(slog.LevelWarn, "画面からの切り出しにも失敗", "pid", pid, "error", fallbackErr)
+		return detail, err
+	}
+	fallback.Warnings = append(fallback.Warnings, "ウィンドウ単位で撮影できなかったため、画面から切り出しました")
+	return fallback, nil
+}
+
+// runScreencap は screencap-cli.exe を1回実行し、結果JSONから撮影の詳細を返す。
+func (service *ScreenshotService) runScreencap(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+	detail := captureDetail{Backend: screencapMethod}
 	cliPath, err := resolveScreencapCLIPath()
 	if err != nil {
-		return err
+		return detail, err
 	}
 
 	args := buildScreencapArgs(pid, outPath, service.localJpeg, service.jpegQuality, service.clientOnly)
@@ -55,18 +86,20 @@ func (service *ScreenshotService) captureWithScreencap(ctx context.Context, pid
 		if parseErr != nil {
 			// exit 0 でも結果JSONを解釈できない場合は、出力ファイルの実在で成否を判断する。
 			if _, statErr := os.Stat(outPath); statErr != nil {
-				return fmt.Errorf(
+				return detail, fmt.Errorf(
 					"screencap-cli は正常終了しましたが出力ファイルが確認できません: %v (parse=%v)",
 					statErr,
 					parseErr,
 				)
 			}
 			service.logCapture(slog.LevelWarn, "screencap-cli の結果JSONを解析できませんでした", "error", parseErr)
-			return nil
+			detail.Warnings = append(detail.Warnings, "撮影結果の詳細を取得できませんでした")
+			return detail, nil
 		}
 		if !result.OK {
-			return screencapErrorFromResult(result)
+			return detail, screencapErrorFromResult(result)
 		}
+		detail.Hwnd = parseScreencapHwnd(result.Hwnd)
 		if result.ImageStats.BlackRatio > screencapBlackWarnRatio {
 			service.logCapture(
 				slog.LevelWarn,
@@ -74,25 +107,26 @@ func (service *ScreenshotService) captureWithScreencap(ctx context.Context, pid
 				"blackRatio", result.ImageStats.BlackRatio,
 				"outPath", result.OutPath,
 			)
+			detail.Warnings = append(detail.Warnings, "画像がほぼ真っ黒です（ウィンドウが最小化されている可能性があります）")
 		}
 		service.logCapture(slog.LevelDebug, "screencap-cli 実行成功", "outPath", result.OutPath)
-		return nil
+		return detail, nil
 	}
 
 	// タイムアウトは他の失敗と区別して明示する。
 	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
-		return fmt.Errorf("screencap-cli がタイムアウトしました (%s)", screencapTimeout)
+		return detail, fmt.Errorf("screencap-cli がタイムアウトしました (%s)", screencapTimeout)
 	}
 
 	// 失敗時は結果JSONの error 情報を優先し、解析できなければ生の出力を含めて返す。
 	var exitErr *exec.ExitError
 	if errors.As(runErr, &exitErr) {
 		if result, parseErr := parseScreencapResult(stdout.Bytes()); parseErr == nil && result.Error != nil {
-			return screencapErrorFromResult(result)
+			return detail, screencapErrorFromResult(result)
 		}
-		return screencapExitError(exitErr.ExitCode(), stdout.String(), stderr.String())
+		return detail, screencapExitError(exitErr.ExitCode(), stdout.String(), stderr.String())
 	}
-	return fmt.Errorf("screencap-cli の起動に失敗しました: %w", runErr)
+	return detail, fmt.Errorf("screencap-cli の起動に失敗しました: %w", runErr)
 }
 
 // resolveScreencapCLIPath は実行ファイルと同じディレクトリの screencap-cli.exe のみを解決する。
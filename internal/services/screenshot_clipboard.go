@@ -0,0 +1,71 @@
+// スクリーンショットの画像をクリップボードへコピーする（PNG 形式と DIB の両方を置く）。
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// bitmapInfoHeaderSz は BITMAPINFOHEADER のバイト数。
+const bitmapInfoHeaderSz = 40
+
+// CopyImageFileToClipboard は画像ファイル（PNG / JPEG）をクリップボードへコピーする（Windows のみ）。
+// 透過を扱えるアプリ向けの "PNG" 形式と、多くのアプリが読める CF_DIB の両方を置く。
+func CopyImageFileToClipboard(path string) error {
+	trimmed := strings.TrimSpace(path)
+	if trimmed == "" {
+		return newServiceError("画像ファイルが指定されていません", "path is empty")
+	}
+	raw, err := os.ReadFile(trimmed)
+	if err != nil {
+		return newServiceError("画像ファイルを読み込めませんでした", err.Error())
+	}
+	img, format, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return newServiceError("画像ファイルを読み込めませんでした", err.Error())
+	}
+	pngData := raw
+	if format != "png" {
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, img); err != nil {
+			return newServiceError("クリップボードへのコピーに失敗しました", err.Error())
+		}
+		pngData = buffer.Bytes()
+	}
+	if err := writeClipboardImage(pngData, imageToDIB(img)); err != nil {
+		return newServiceError("クリップボードへのコピーに失敗しました", err.Error())
+	}
+	return nil
+}
+
+// imageToDIB は画像を CF_DIB 用の BITMAPINFOHEADER（32bpp, BI_RGB, ボトムアップ）と BGRA の画素データにする。
+func imageToDIB(img image.Image) []byte {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	stride := width * 4
+	dib := make([]byte, bitmapInfoHeaderSz+stride*height)
+	binary.LittleEndian.PutUint32(dib[0:4], bitmapInfoHeaderSz)
+	binary.LittleEndian.PutUint32(dib[4:8], uint32(width))
+	binary.LittleEndian.PutUint32(dib[8:12], uint32(height))
+	binary.LittleEndian.PutUint16(dib[12:14], 1)
+	binary.LittleEndian.PutUint16(dib[14:16], 32)
+	binary.LittleEndian.PutUint32(dib[20:24], uint32(stride*height))
+
+	pixels := dib[bitmapInfoHeaderSz:]
+	for y := 0; y < height; y++ {
+		// ボトムアップなので、画像の最終行から書く。
+		row := pixels[(height-1-y)*stride:]
+		for x := 0; x < width; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			row[x*4] = byte(b >> 8)
+			row[x*4+1] = byte(g >> 8)
+			row[x*4+2] = byte(r >> 8)
+			row[x*4+3] = byte(a >> 8)
+		}
+	}
+	return dib
+}
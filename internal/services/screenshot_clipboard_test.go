@@ -0,0 +1,44 @@
+package services
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestImageToDIBWritesBottomUpBGRA(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 1, color.NRGBA{B: 255, A: 255})
+
+	dib := imageToDIB(img)
+	if len(dib) != bitmapInfoHeaderSz+2*2*4 {
+		t.Fatalf("unexpected size %d", len(dib))
+	}
+	if width, height := binary.LittleEndian.Uint32(dib[4:8]), binary.LittleEndian.Uint32(dib[8:12]); width != 2 || height != 2 {
+		t.Fatalf("unexpected dimensions %dx%d", width, height)
+	}
+	if bitCount := binary.LittleEndian.Uint16(dib[14:16]); bitCount != 32 {
+		t.Fatalf("expected 32bpp, got %d", bitCount)
+	}
+	pixels := dib[bitmapInfoHeaderSz:]
+	// 画像の先頭行（赤）は DIB の最終行に入る。
+	if got := pixels[8:12]; got[0] != 0 || got[1] != 0 || got[2] != 255 || got[3] != 255 {
+		t.Fatalf("expected red top-left pixel in last row, got %v", got)
+	}
+	// 画像の最終行の右端（青）は DIB の先頭行の右端に入る。
+	if got := pixels[4:8]; got[0] != 255 || got[1] != 0 || got[2] != 0 || got[3] != 255 {
+		t.Fatalf("expected blue bottom-right pixel in first row, got %v", got)
+	}
+}
+
+func TestCopyImageFileToClipboardRejectsEmptyPath(t *testing.T) {
+	t.Parallel()
+
+	if err := CopyImageFileToClipboard("  "); err == nil {
+		t.Fatalf("expected error for empty path")
+	}
+}
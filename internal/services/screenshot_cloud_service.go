@@ -0,0 +1,219 @@
+// クラウド上のスクリーンショットを端末へ一括で取り込む処理を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// screenshotCloudPrefix はクラウド上でスクリーンショットを置くプレフィックス。
+// キーは screenshots/{gameID}/{ファイル名} になる（app.uploadScreenshot と揃える）。
+const screenshotCloudPrefix = "screenshots/"
+
+// ScreenshotDownloadResult はスクリーンショット一括ダウンロードの結果を表す。
+type ScreenshotDownloadResult struct {
+	Destination string   `json:"destination"`
+	Downloaded  int      `json:"downloaded"`
+	Skipped     int      `json:"skipped"`
+	Failed      []string `json:"failed"`
+}
+
+// ScreenshotCloudService はクラウドのスクリーンショット取得を提供する。
+type ScreenshotCloudService struct {
+	config      config.Config
+	store       credentials.Store
+	objectStore cloudObjectStore
+	logger      *slog.Logger
+}
+
+// NewScreenshotCloudService は ScreenshotCloudService を生成する。
+func NewScreenshotCloudService(cfg config.Config, store credentials.Store, logger *slog.Logger) *ScreenshotCloudService {
+	return &ScreenshotCloudService{
+		config:      cfg,
+		store:       store,
+		objectStore: storageCloudObjectStore{},
+		logger:      logger,
+	}
+}
+
+func (service *ScreenshotCloudService) SetS3ForcePathStyle(enabled bool) {
+	service.config.S3ForcePathStyle = enabled
+}
+
+func (service *ScreenshotCloudService) SetS3UseTLS(enabled bool) {
+	service.config.S3UseTLS = enabled
+}
+
+// SetCredentialKey は使用する認証情報プロファイルを切り替える。
+func (service *ScreenshotCloudService) SetCredentialKey(key string) {
+	service.config.CredentialKey = key
+}
+
+// SetUploadConcurrency はダウンロードの同時実行数を更新する。
+func (service *ScreenshotCloudService) SetUploadConcurrency(value int) {
+	service.config.S3UploadConcurrency = value
+}
+
+// DownloadGameScreenshots はクラウド上のゲームのスクリーンショットを destination へ並列に取り込む。
+// destination が空ならローカルのスクリーンショットフォルダ（screenshots/{gameID}）を使う。
+// 拡張子を除いた名前が同じファイルが既にあればスキップする（JPEG 変換してアップロードした
+// 撮影元の PNG を二重に持たないため）。1件の失敗では止めず、失敗したキーを Failed に返す。
+func (service *ScreenshotCloudService) DownloadGameScreenshots(
+	ctx context.Context,
+	gameID string,
+	destination string,
+	onProgress ProgressFunc,
+) (ScreenshotDownloadResult, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		return ScreenshotDownloadResult{}, newServiceError("ゲームIDが不正です", detail)
+	}
+	targetDir := strings.TrimSpace(destination)
+	if targetDir == "" {
+		targetDir = filepath.Join(localScreenshotsRoot(service.config.AppDataDir), trimmedID)
+	}
+
+	cfg, credential, err := service.resolveDefaultS3Config(ctx)
+	if err != nil {
+		service.logger.Error("スクリーンショットのダウンロードに失敗しました", "error", err, "operation", "DownloadGameScreenshots.getDefaultS3Client")
+		return ScreenshotDownloadResult{}, newServiceError("スクリーンショットのダウンロードに失敗しました", err.Error())
+	}
+	prefix := screenshotCloudPrefix + trimmedID + "/"
+	objects, err := service.objectStore.ListObjects(ctx, cfg, credential, prefix)
+	if err != nil {
+		service.logger.Error("クラウドのスクリーンショット一覧取得に失敗", "gameId", trimmedID, "error", err)
+		return ScreenshotDownloadResult{}, newServiceError("スクリーンショットのダウンロードに失敗しました", err.Error())
+	}
+	existing, err := existingScreenshotStems(targetDir)
+	if err != nil {
+		service.logger.Error("ローカルのスクリーンショット一覧取得に失敗", "dir", targetDir, "error", err)
+		return ScreenshotDownloadResult{}, newServiceError("スクリーンショットのダウンロードに失敗しました", err.Error())
+	}
+
+	res := ScreenshotDownloadResult{Destination: targetDir, Failed: []string{}}
+	pending := make([]string, 0, len(objects))
+	for _, object := range objects {
+		rel := strings.TrimPrefix(object.Key, prefix)
+		if rel == "" || strings.HasSuffix(rel, "/") {
+			continue
+		}
+		if _, ok := existing[screenshotStem(rel)]; ok {
+			res.Skipped++
+			continue
+		}
+		pending = append(pending, object.Key)
+	}
+	if len(pending) == 0 {
+		return res, nil
+	}
+	if err := os.MkdirAll(targetDir, 0o700); err != nil {
+		service.logger.Error("保存先フォルダの作成に失敗", "dir", targetDir, "error", err)
+		return ScreenshotDownloadResult{}, newServiceError("スクリーンショットのダウンロードに失敗しました", err.Error())
+	}
+
+	total := len(pending)
+	if onProgress != nil {
+		onProgress(0, total)
+	}
+	var mu sync.Mutex
+	var done atomic.Int32
+	fanOutGames(pending, service.config.S3UploadConcurrency, func(key string) *struct{} {
+		err := service.downloadScreenshot(ctx, cfg, credential, key, targetDir, strings.TrimPrefix(key, prefix))
+		mu.Lock()
+		if err != nil {
+			service.logger.Warn("スクリーンショットのダウンロードに失敗（続行）", "key", key, "error", err)
+			res.Failed = append(res.Failed, key)
+		} else {
+			res.Downloaded++
+		}
+		mu.Unlock()
+		if onProgress != nil {
+			onProgress(int(done.Add(1)), total)
+		}
+		return nil
+	})
+	sort.Strings(res.Failed)
+	service.logger.Info("スクリーンショットを一括ダウンロード", "gameId", trimmedID, "downloaded", res.Downloaded, "skipped", res.Skipped, "failed", len(res.Failed))
+	return res, nil
+}
+
+// downloadScreenshot は1件を一時ファイルへ書き出してから置き換える（途中失敗で壊れたファイルを残さない）。
+func (service *ScreenshotCloudService) downloadScreenshot(
+	ctx context.Context,
+	cfg storage.S3Config,
+	credential credentials.Credential,
+	key, targetDir, rel string,
+) error {
+	targetPath, err := storage.ResolveSafeRelativePath(targetDir, rel)
+	if err != nil {
+		return err
+	}
+	payload, err := service.objectStore.DownloadObject(ctx, cfg, credential, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0o700); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(targetPath), ".download-*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	if _, err := tempFile.Write(payload); err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	return os.Rename(tempPath, targetPath)
+}
+
+func (service *ScreenshotCloudService) resolveDefaultS3Config(ctx context.Context) (storage.S3Config, credentials.Credential, error) {
+	credential, err := service.store.Load(ctx, credentialKeyOf(service.config))
+	if err != nil || credential == nil {
+		return storage.S3Config{}, credentials.Credential{}, errors.New("認証情報がありません")
+	}
+	return resolveS3Config(service.config, credential), *credential, nil
+}
+
+// existingScreenshotStems は dir 配下のファイルを拡張子抜きの相対パスの集合で返す。
+func existingScreenshotStems(dir string) (map[string]struct{}, error) {
+	files, err := listScreenshotFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	stems := make(map[string]struct{}, len(files))
+	for _, rel := range files {
+		stems[screenshotStem(rel)] = struct{}{}
+	}
+	return stems, nil
+}
+
+func screenshotStem(rel string) string {
+	return strings.TrimSuffix(rel, path.Ext(rel))
+}
+
+// localScreenshotsRoot はローカルのスクリーンショット保存ルートを返す。
+func localScreenshotsRoot(appDataDir string) string {
+	baseDir := strings.TrimSpace(appDataDir)
+	if baseDir == "" {
+		baseDir = os.TempDir()
+	}
+	return filepath.Join(baseDir, "screenshots")
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/infrastructure/credentials"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func TestScreenshotCloudServiceDownloadGameScreenshotsSkipsExisting(t *testing.T) {
+	t.Parallel()
+
+	appDataDir := t.TempDir()
+	localDir := filepath.Join(appDataDir, "screenshots", "game-1")
+	if err := os.MkdirAll(localDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// JPEG でアップロードされた撮影元 PNG はローカルに残っている
+	if err := os.WriteFile(filepath.Join(localDir, "shot-1.png"), []byte("original"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	objectStore := &fakeCloudObjectStore{
+		listObjects: []storage.ObjectInfo{
+			{Key: "screenshots/game-1/shot-1.jpg"},
+			{Key: "screenshots/game-1/shot-2.jpg"},
+		},
+		downloadData: []byte("remote"),
+	}
+	service := NewScreenshotCloudService(
+		config.Config{AppDataDir: appDataDir, S3UploadConcurrency: 1},
+		&fakeCredentialStore{loadResult: &credentials.Credential{BucketName: "bucket"}},
+		newTestLogger(),
+	)
+	service.objectStore = objectStore
+
+	var lastProgress [2]int
+	res, err := service.DownloadGameScreenshots(context.Background(), "game-1", "", func(current, total int) {
+		lastProgress = [2]int{current, total}
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if res.Downloaded != 1 || res.Skipped != 1 || len(res.Failed) != 0 {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+	if len(objectStore.downloadedKeys) != 1 || objectStore.downloadedKeys[0] != "screenshots/game-1/shot-2.jpg" {
+		t.Fatalf("unexpected downloads: %v", objectStore.downloadedKeys)
+	}
+	data, err := os.ReadFile(filepath.Join(localDir, "shot-2.jpg"))
+	if err != nil || string(data) != "remote" {
+		t.Fatalf("downloaded file = %q (%v)", data, err)
+	}
+	if lastProgress != [2]int{1, 1} {
+		t.Fatalf("last progress = %v, want [1 1]", lastProgress)
+	}
+}
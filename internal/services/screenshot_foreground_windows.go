@@ -0,0 +1,38 @@
+//go:build windows
+
+// Windows向けにフォアグラウンドウィンドウの所有プロセスを取得する。
+package services
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// foregroundProcess は前面ウィンドウを所有するプロセスの PID と実行ファイルパスを返す。
+// パスが取得できない（権限不足など）場合は空文字を返し、除外リストの判定のみ行わない。
+func foregroundProcess() (int, string, error) {
+	hwnd := windows.GetForegroundWindow()
+	if hwnd == 0 {
+		return 0, "", errors.New("foreground window not found")
+	}
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(hwnd, &pid); err != nil {
+		return 0, "", err
+	}
+	if pid == 0 {
+		return 0, "", errors.New("foreground process not found")
+	}
+
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return int(pid), "", nil
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+	buffer := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buffer))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buffer[0], &size); err != nil {
+		return int(pid), "", nil
+	}
+	return int(pid), windows.UTF16ToString(buffer[:size]), nil
+}
@@ -0,0 +1,191 @@
+// screencap-cli で撮影できないときの、画面（モニター）からの切り出しに使うモニター選択と画像処理を提供する。
+package services
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+)
+
+// screenMonitorMethod は画面から切り出したときに CaptureResult.Backend に返す撮影方式。
+const screenMonitorMethod = "gdi-monitor"
+
+// モニターを選んだ理由。ログで判断を追えるようにする。
+const (
+	monitorReasonCenter  = "center"
+	monitorReasonOverlap = "overlap"
+)
+
+// screenRect は仮想スクリーン座標の矩形（Right / Bottom を含まない）。
+type screenRect struct {
+	Left, Top, Right, Bottom int32
+}
+
+func (rect screenRect) empty() bool {
+	return rect.Right <= rect.Left || rect.Bottom <= rect.Top
+}
+
+func (rect screenRect) area() int64 {
+	if rect.empty() {
+		return 0
+	}
+	return int64(rect.Right-rect.Left) * int64(rect.Bottom-rect.Top)
+}
+
+func (rect screenRect) contains(x, y int32) bool {
+	return x >= rect.Left && x < rect.Right && y >= rect.Top && y < rect.Bottom
+}
+
+func (rect screenRect) intersect(other screenRect) screenRect {
+	return screenRect{
+		Left:   max(rect.Left, other.Left),
+		Top:    max(rect.Top, other.Top),
+		Right:  min(rect.Right, other.Right),
+		Bottom: min(rect.Bottom, other.Bottom),
+	}
+}
+
+// displayMonitor はモニター1台の範囲を表す。
+type displayMonitor struct {
+	Bounds  screenRect
+	Primary bool
+}
+
+// monitorChoice は撮影を試すモニターと、その順にした理由を表す。
+type monitorChoice struct {
+	Monitor displayMonitor
+	Reason  string
+}
+
+// windowCaptureState は画面から切り出してよいかを判断するための、撮影時点のウィンドウの状態。
+type windowCaptureState struct {
+	Window    screenRect
+	Minimized bool
+	// Foreground は前面のウィンドウがゲームのプロセスのものか。
+	Foreground bool
+	// Covering は z 順でウィンドウより手前にある、他のプロセスの表示中のウィンドウの範囲。
+	Covering []screenRect
+}
+
+// checkScreenCapturable は画面からウィンドウの範囲を切り出してよいかを確かめる。
+// 画面の切り出しはウィンドウ自身の内容ではなく画面の画素を写すため、最小化・背面・画面外のとき、
+// または他のウィンドウが重なっているときは、ゲーム以外の内容を保存・アップロードしないよう撮影しない。
+func checkScreenCapturable(state windowCaptureState, monitors []displayMonitor) error {
+	if state.Minimized {
+		return errors.New("ウィンドウが最小化されています")
+	}
+	if !state.Foreground {
+		return errors.New("ウィンドウが前面にないため画面から切り出せません")
+	}
+	onScreen := false
+	for _, monitor := range monitors {
+		if !state.Window.intersect(monitor.Bounds).empty() {
+			onScreen = true
+			break
+		}
+	}
+	if !onScreen {
+		return errors.New("ウィンドウが画面外にあります")
+	}
+	for _, covering := range state.Covering {
+		if !state.Window.intersect(covering).empty() {
+			return errors.New("他のウィンドウが重なっているため画面から切り出せません")
+		}
+	}
+	return nil
+}
+
+// orderMonitorsForWindow はウィンドウを切り出すモニターを試す順に並べる。
+//  1. ウィンドウの中心を含むモニター
+//  2. ウィンドウと重なる残りのモニター（重なりの大きい順）
+//
+// ウィンドウと重ならないモニターは、撮るとゲーム以外の画面が写るため含めない。
+func orderMonitorsForWindow(window screenRect, monitors []displayMonitor) []monitorChoice {
+	choices := make([]monitorChoice, 0, len(monitors))
+	used := make([]bool, len(monitors))
+	centerX := window.Left + (window.Right-window.Left)/2
+	centerY := window.Top + (window.Bottom-window.Top)/2
+	for index, monitor := range monitors {
+		if monitor.Bounds.contains(centerX, centerY) {
+			choices = append(choices, monitorChoice{Monitor: monitor, Reason: monitorReasonCenter})
+			used[index] = true
+			break
+		}
+	}
+	for {
+		best, bestArea := -1, int64(0)
+		for index, monitor := range monitors {
+			if used[index] {
+				continue
+			}
+			if area := window.intersect(monitor.Bounds).area(); area > bestArea {
+				best, bestArea = index, area
+			}
+		}
+		if best < 0 {
+			break
+		}
+		used[best] = true
+		choices = append(choices, monitorChoice{Monitor: monitors[best], Reason: monitorReasonOverlap})
+	}
+	return choices
+}
+
+// captureRegion はモニター上で切り出す範囲（ウィンドウとモニターの重なり）を返す。
+// 重ならないときは false を返す（モニター全体は撮らない）。
+func captureRegion(window screenRect, monitor screenRect) (screenRect, bool) {
+	region := window.intersect(monitor)
+	return region, !region.empty()
+}
+
+// screenPixelsToImage は画面から読み出した上から下の順の BGRA を不透明な画像にする。
+// 画面のビットマップのアルファは意味を持たない（多くは 0）ため使わない。
+func screenPixelsToImage(pixels []byte, width, height int) *image.RGBA {
+	picture := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i+3 < len(pixels) && i+3 < len(picture.Pix); i += 4 {
+		picture.Pix[i] = pixels[i+2]
+		picture.Pix[i+1] = pixels[i+1]
+		picture.Pix[i+2] = pixels[i]
+		picture.Pix[i+3] = 0xff
+	}
+	return picture
+}
+
+// blackPixelRatio は真っ黒（RGB がすべて 0）の画素の割合を返す。screencap-cli の black_ratio と同じ尺度。
+func blackPixelRatio(picture *image.RGBA) float64 {
+	total := len(picture.Pix) / 4
+	if total == 0 {
+		return 1
+	}
+	black := 0
+	for i := 0; i+3 < len(picture.Pix); i += 4 {
+		if picture.Pix[i]|picture.Pix[i+1]|picture.Pix[i+2] == 0 {
+			black++
+		}
+	}
+	return float64(black) / float64(total)
+}
+
+// writeCaptureImage は画像を outPath に PNG（localJpeg なら JPEG）で保存する。
+func writeCaptureImage(outPath string, picture image.Image, localJpeg bool, jpegQuality int) error {
+	file, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	if localJpeg {
+		err = jpeg.Encode(file, picture, &jpeg.Options{Quality: normalizeJpegQuality(jpegQuality)})
+	} else {
+		err = png.Encode(file, picture)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(outPath)
+		return fmt.Errorf("画像の保存に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestOrderMonitorsForWindowPrefersMonitorContainingCenter(t *testing.T) {
+	t.Parallel()
+
+	left := displayMonitor{Bounds: screenRect{Left: 0, Top: 0, Right: 1920, Bottom: 1080}, Primary: true}
+	right := displayMonitor{Bounds: screenRect{Left: 1920, Top: 0, Right: 3840, Bottom: 1080}}
+	far := displayMonitor{Bounds: screenRect{Left: 3840, Top: 0, Right: 5760, Bottom: 1080}}
+	// 左右のモニターにまたがり、中心は右のモニターにあるウィンドウ。
+	window := screenRect{Left: 1500, Top: 100, Right: 3000, Bottom: 900}
+
+	choices := orderMonitorsForWindow(window, []displayMonitor{left, right, far})
+	if len(choices) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v", choices)
+	}
+	if choices[0].Monitor != right || choices[0].Reason != monitorReasonCenter {
+		t.Fatalf("expected right monitor first by center, got %+v", choices[0])
+	}
+	if choices[1].Monitor != left || choices[1].Reason != monitorReasonOverlap {
+		t.Fatalf("expected left monitor next by overlap, got %+v", choices[1])
+	}
+}
+
+func TestOrderMonitorsForWindowOrdersByOverlapWhenCenterIsOffscreen(t *testing.T) {
+	t.Parallel()
+
+	small := displayMonitor{Bounds: screenRect{Left: 0, Top: 0, Right: 1000, Bottom: 1000}}
+	large := displayMonitor{Bounds: screenRect{Left: 1000, Top: 0, Right: 3000, Bottom: 1000}, Primary: true}
+	// 中心 (1500, 1200) はどのモニターにも無い。
+	window := screenRect{Left: 500, Top: 900, Right: 2500, Bottom: 1500}
+
+	choices := orderMonitorsForWindow(window, []displayMonitor{small, large})
+	if len(choices) != 2 || choices[0].Monitor != large || choices[1].Monitor != small {
+		t.Fatalf("expected larger overlap first, got %+v", choices)
+	}
+	for _, choice := range choices {
+		if choice.Reason != monitorReasonOverlap {
+			t.Fatalf("expected overlap reason, got %+v", choice)
+		}
+	}
+}
+
+func TestOrderMonitorsForWindowSkipsMonitorsWithoutOverlap(t *testing.T) {
+	t.Parallel()
+
+	primary := displayMonitor{Bounds: screenRect{Left: 0, Top: 0, Right: 1920, Bottom: 1080}, Primary: true}
+	secondary := displayMonitor{Bounds: screenRect{Left: 1920, Top: 0, Right: 3840, Bottom: 1080}}
+	window := screenRect{Left: -32000, Top: -32000, Right: -31800, Bottom: -31900}
+
+	if choices := orderMonitorsForWindow(window, []displayMonitor{secondary, primary}); len(choices) != 0 {
+		t.Fatalf("expected no monitors for an offscreen window, got %+v", choices)
+	}
+	if region, ok := captureRegion(window, primary.Bounds); ok {
+		t.Fatalf("expected no region for an offscreen window, got %+v", region)
+	}
+}
+
+func TestCheckScreenCapturableRefusesHiddenOrCoveredWindows(t *testing.T) {
+	t.Parallel()
+
+	monitors := []displayMonitor{{Bounds: screenRect{Left: 0, Top: 0, Right: 1920, Bottom: 1080}, Primary: true}}
+	window := screenRect{Left: 100, Top: 100, Right: 900, Bottom: 700}
+	visible := windowCaptureState{Window: window, Foreground: true}
+	if err := checkScreenCapturable(visible, monitors); err != nil {
+		t.Fatalf("expected a visible foreground window to be capturable, got %v", err)
+	}
+	// 重ならない位置にある他のウィンドウは問題にしない。
+	aside := visible
+	aside.Covering = []screenRect{{Left: 1000, Top: 0, Right: 1500, Bottom: 500}}
+	if err := checkScreenCapturable(aside, monitors); err != nil {
+		t.Fatalf("expected a non-overlapping window to be ignored, got %v", err)
+	}
+
+	cases := map[string]windowCaptureState{
+		// 最小化したウィンドウは -32000 付近へ移動する。
+		"minimized":  {Window: screenRect{Left: -32000, Top: -32000, Right: -31840, Bottom: -31972}, Minimized: true, Foreground: true},
+		"offscreen":  {Window: screenRect{Left: 3000, Top: 0, Right: 3800, Bottom: 600}, Foreground: true},
+		"background": {Window: window},
+		"covered":    {Window: window, Foreground: true, Covering: []screenRect{{Left: 800, Top: 600, Right: 1200, Bottom: 900}}},
+	}
+	for name, state := range cases {
+		if err := checkScreenCapturable(state, monitors); err == nil {
+			t.Errorf("%s: expected capture to be refused", name)
+		}
+	}
+}
+
+func TestBlackPixelRatioCountsOpaqueBlackPixels(t *testing.T) {
+	t.Parallel()
+
+	picture := screenPixelsToImage([]byte{0, 0, 0, 0, 10, 20, 30, 0}, 2, 1)
+	if ratio := blackPixelRatio(picture); ratio != 0.5 {
+		t.Fatalf("expected 0.5, got %v", ratio)
+	}
+	if picture.Pix[4] != 30 || picture.Pix[6] != 10 || picture.Pix[7] != 0xff {
+		t.Fatalf("expected BGRA converted to opaque RGBA, got %v", picture.Pix)
+	}
+}
@@ -0,0 +1,298 @@
+//go:build windows
+
+// Windows向けに、ウィンドウを含むモニターの画面からスクリーンショットを切り出す（screencap-cli のフォールバック）。
+package services
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	gwHwndPrev         = 3
+	gwOwner            = 4
+	monitorInfoPrimary = 0x1
+	// bitBltScreenCopy は SRCCOPY | CAPTUREBLT。CAPTUREBLT で重ねウィンドウも含めて写す。
+	bitBltScreenCopy = 0x00CC0020 | 0x40000000
+)
+
+var (
+	procGetWindowRect          = user32.NewProc("GetWindowRect")
+	procGetClientRect          = user32.NewProc("GetClientRect")
+	procClientToScreen         = user32.NewProc("ClientToScreen")
+	procIsIconic               = user32.NewProc("IsIconic")
+	procGetWindow              = user32.NewProc("GetWindow")
+	procEnumDisplayMonitors    = user32.NewProc("EnumDisplayMonitors")
+	procGetMonitorInfoW        = user32.NewProc("GetMonitorInfoW")
+	procGetDC                  = user32.NewProc("GetDC")
+	procReleaseDC              = user32.NewProc("ReleaseDC")
+	procCreateCompatibleBitmap = gdi32dll.NewProc("CreateCompatibleBitmap")
+	procSelectObject           = gdi32dll.NewProc("SelectObject")
+	procBitBlt                 = gdi32dll.NewProc("BitBlt")
+)
+
+type monitorInfo struct {
+	CbSize    uint32
+	RcMonitor windows.Rect
+	RcWork    windows.Rect
+	DwFlags   uint32
+}
+
+// captureFromMonitors は pid のメインウィンドウ（pid が 0 なら前面のウィンドウ）の範囲を、
+// ウィンドウの中心を含むモニターから順に画面から切り出して outPath に保存する。
+// ウィンドウが前面で他のウィンドウに覆われていないときだけ撮り、それ以外はエラーにする（checkScreenCapturable）。
+// 切り出した画像がほぼ真っ黒なら次のモニターを試し、どれも真っ黒なら最初の画像を警告付きで残す。
+func (service *ScreenshotService) captureFromMonitors(pid int, outPath string) (captureDetail, error) {
+	detail := captureDetail{Backend: screenMonitorMethod}
+	hwnd := windows.GetForegroundWindow()
+	if pid > 0 {
+		hwnd = findProcessWindow(uint32(pid))
+	}
+	if hwnd == 0 {
+		return detail, errors.New("撮影するウィンドウが見つかりません")
+	}
+	detail.Hwnd = uint64(hwnd)
+	iconic, _, _ := procIsIconic.Call(uintptr(hwnd))
+	window, err := windowScreenRect(hwnd, service.clientOnly)
+	if err != nil && iconic == 0 {
+		return detail, err
+	}
+	monitors := enumDisplayMonitors()
+	var windowPID uint32
+	if _, err := windows.GetWindowThreadProcessId(hwnd, &windowPID); err != nil {
+		return detail, err
+	}
+	state := windowCaptureState{
+		Window:     window,
+		Minimized:  iconic != 0,
+		Foreground: isForegroundProcess(windowPID),
+		Covering:   coveringWindowRects(hwnd, windowPID),
+	}
+	if err := checkScreenCapturable(state, monitors); err != nil {
+		return detail, err
+	}
+	choices := orderMonitorsForWindow(window, monitors)
+	if len(choices) == 0 {
+		return detail, errors.New("ウィンドウを表示しているモニターが見つかりません")
+	}
+
+	var fallback *image.RGBA
+	for index, choice := range choices {
+		region, ok := captureRegion(window, choice.Monitor.Bounds)
+		if !ok {
+			continue
+		}
+		picture, err := captureScreenRegion(region)
+		if err != nil {
+			service.logCapture(slog.LevelWarn, "モニターからの切り出しに失敗", "monitor", index, "reason", choice.Reason, "error", err)
+			continue
+		}
+		ratio := blackPixelRatio(picture)
+		service.logCapture(slog.LevelInfo, "モニターから切り出し",
+			"monitor", index, "reason", choice.Reason, "primary", choice.Monitor.Primary,
+			"region", fmt.Sprintf("%d,%d-%d,%d", region.Left, region.Top, region.Right, region.Bottom),
+			"blackRatio", ratio)
+		if ratio <= screencapBlackWarnRatio {
+			return detail, writeCaptureImage(outPath, picture, service.localJpeg, service.jpegQuality)
+		}
+		if fallback == nil {
+			fallback = picture
+		}
+	}
+	if fallback == nil {
+		return detail, errors.New("どのモニターからも切り出せませんでした")
+	}
+	detail.Warnings = append(detail.Warnings, "画像がほぼ真っ黒です（ウィンドウが最小化されている可能性があります）")
+	return detail, writeCaptureImage(outPath, fallback, service.localJpeg, service.jpegQuality)
+}
+
+// windows.NewCallback で作ったコールバックは解放されず、作れる数にも上限があるため、
+// 列挙のコールバックは一度だけ作って使い回し、列挙中の状態は enumMu で守ったパッケージ変数に置く。
+var (
+	enumMu               sync.Mutex
+	enumWindowsCallback  = sync.OnceValue(func() uintptr { return windows.NewCallback(enumWindowsProc) })
+	enumMonitorsCallback = sync.OnceValue(func() uintptr { return windows.NewCallback(enumMonitorsProc) })
+	enumWindowsPID       uint32
+	enumWindowsFound     windows.HWND
+	enumWindowsFoundArea int64
+	enumMonitorsResult   []displayMonitor
+)
+
+// findProcessWindow は pid が持つ表示中のトップレベルウィンドウのうち、ランチャーの小窓などより
+// ゲーム本体を選べるよう最も大きいもの（所有者のいないもの）を返す。見つからなければ 0。
+func findProcessWindow(pid uint32) windows.HWND {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+	enumWindowsPID, enumWindowsFound, enumWindowsFoundArea = pid, 0, 0
+	_ = windows.EnumWindows(enumWindowsCallback(), nil)
+	return enumWindowsFound
+}
+
+func enumWindowsProc(hwnd windows.HWND, _ uintptr) uintptr {
+	var owner uint32
+	if _, err := windows.GetWindowThreadProcessId(hwnd, &owner); err != nil || owner != enumWindowsPID {
+		return 1
+	}
+	if !windows.IsWindowVisible(hwnd) {
+		return 1
+	}
+	if parent, _, _ := procGetWindow.Call(uintptr(hwnd), gwOwner); parent != 0 {
+		return 1
+	}
+	var rect windows.Rect
+	if ret, _, _ := procGetWindowRect.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&rect))); ret == 0 {
+		return 1
+	}
+	if area := toScreenRect(rect).area(); area > enumWindowsFoundArea {
+		enumWindowsFound, enumWindowsFoundArea = hwnd, area
+	}
+	return 1
+}
+
+// isForegroundProcess は前面のウィンドウが pid のプロセスのものかを返す。
+func isForegroundProcess(pid uint32) bool {
+	foreground := windows.GetForegroundWindow()
+	if foreground == 0 {
+		return false
+	}
+	var owner uint32
+	if _, err := windows.GetWindowThreadProcessId(foreground, &owner); err != nil {
+		return false
+	}
+	return owner == pid
+}
+
+// coveringWindowRects は z 順で hwnd より手前にある、pid 以外のプロセスの表示中のウィンドウの範囲を返す。
+// 仮想デスクトップ等で隠されている（cloaked）ウィンドウは画面に写らないため除く。
+func coveringWindowRects(hwnd windows.HWND, pid uint32) []screenRect {
+	rects := make([]screenRect, 0)
+	current := hwnd
+	for {
+		previous, _, _ := procGetWindow.Call(uintptr(current), gwHwndPrev)
+		if previous == 0 {
+			return rects
+		}
+		current = windows.HWND(previous)
+		if !windows.IsWindowVisible(current) {
+			continue
+		}
+		var owner uint32
+		if _, err := windows.GetWindowThreadProcessId(current, &owner); err == nil && owner == pid {
+			continue
+		}
+		var cloaked uint32
+		if err := windows.DwmGetWindowAttribute(current, windows.DWMWA_CLOAKED, unsafe.Pointer(&cloaked), uint32(unsafe.Sizeof(cloaked))); err == nil && cloaked != 0 {
+			continue
+		}
+		var rect windows.Rect
+		if ret, _, _ := procGetWindowRect.Call(uintptr(current), uintptr(unsafe.Pointer(&rect))); ret == 0 {
+			continue
+		}
+		if screen := toScreenRect(rect); !screen.empty() {
+			rects = append(rects, screen)
+		}
+	}
+}
+
+// windowScreenRect はウィンドウの画面上の範囲を返す。clientOnly ならクライアント領域を、
+// そうでなければ影を除いた見た目の枠（DWM の拡張フレーム）を使う。
+func windowScreenRect(hwnd windows.HWND, clientOnly bool) (screenRect, error) {
+	if clientOnly {
+		var client windows.Rect
+		if ret, _, callErr := procGetClientRect.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&client))); ret == 0 {
+			return screenRect{}, callErr
+		}
+		origin := struct{ X, Y int32 }{}
+		if ret, _, callErr := procClientToScreen.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&origin))); ret == 0 {
+			return screenRect{}, callErr
+		}
+		rect := screenRect{Left: origin.X, Top: origin.Y, Right: origin.X + client.Right, Bottom: origin.Y + client.Bottom}
+		if !rect.empty() {
+			return rect, nil
+		}
+	}
+	var frame windows.Rect
+	if err := windows.DwmGetWindowAttribute(hwnd, windows.DWMWA_EXTENDED_FRAME_BOUNDS, unsafe.Pointer(&frame), uint32(unsafe.Sizeof(frame))); err == nil {
+		if rect := toScreenRect(frame); !rect.empty() {
+			return rect, nil
+		}
+	}
+	if ret, _, callErr := procGetWindowRect.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&frame))); ret == 0 {
+		return screenRect{}, callErr
+	}
+	rect := toScreenRect(frame)
+	if rect.empty() {
+		return screenRect{}, errors.New("ウィンドウの大きさを取得できません")
+	}
+	return rect, nil
+}
+
+// enumDisplayMonitors は接続中のモニターを列挙順に返す。
+func enumDisplayMonitors() []displayMonitor {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+	enumMonitorsResult = make([]displayMonitor, 0, 4)
+	procEnumDisplayMonitors.Call(0, 0, enumMonitorsCallback(), 0)
+	monitors := enumMonitorsResult
+	enumMonitorsResult = nil
+	return monitors
+}
+
+func enumMonitorsProc(monitor uintptr, _ uintptr, _ uintptr, _ uintptr) uintptr {
+	info := monitorInfo{}
+	info.CbSize = uint32(unsafe.Sizeof(info))
+	if ret, _, _ := procGetMonitorInfoW.Call(monitor, uintptr(unsafe.Pointer(&info))); ret != 0 {
+		enumMonitorsResult = append(enumMonitorsResult, displayMonitor{
+			Bounds:  toScreenRect(info.RcMonitor),
+			Primary: info.DwFlags&monitorInfoPrimary != 0,
+		})
+	}
+	return 1
+}
+
+// captureScreenRegion は画面の region を BitBlt で写し取る。
+func captureScreenRegion(region screenRect) (*image.RGBA, error) {
+	width, height := int(region.Right-region.Left), int(region.Bottom-region.Top)
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("切り出す範囲が空です")
+	}
+	screenDC, _, callErr := procGetDC.Call(0)
+	if screenDC == 0 {
+		return nil, callErr
+	}
+	defer procReleaseDC.Call(0, screenDC)
+	memoryDC, _, callErr := procCreateCompatibleDC.Call(screenDC)
+	if memoryDC == 0 {
+		return nil, callErr
+	}
+	defer procDeleteDC.Call(memoryDC)
+	bitmap, _, callErr := procCreateCompatibleBitmap.Call(screenDC, uintptr(width), uintptr(height))
+	if bitmap == 0 {
+		return nil, callErr
+	}
+	defer procDeleteObject.Call(bitmap)
+	previous, _, _ := procSelectObject.Call(memoryDC, bitmap)
+	ret, _, callErr := procBitBlt.Call(
+		memoryDC, 0, 0, uintptr(width), uintptr(height),
+		screenDC, uintptr(region.Left), uintptr(region.Top), bitBltScreenCopy,
+	)
+	procSelectObject.Call(memoryDC, previous)
+	if ret == 0 {
+		return nil, callErr
+	}
+	pixels, err := readBitmapBGRA(memoryDC, bitmap, width, height)
+	if err != nil {
+		return nil, err
+	}
+	return screenPixelsToImage(pixels, width, height), nil
+}
+
+func toScreenRect(rect windows.Rect) screenRect {
+	return screenRect{Left: rect.Left, Top: rect.Top, Right: rect.Right, Bottom: rect.Bottom}
+}
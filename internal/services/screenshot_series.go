@@ -0,0 +1,127 @@
+// スクリーンショットのタイマー撮影と連写（ムービーシーンの記録向け）を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// MaxScreenshotDelaySeconds はタイマー撮影で指定できる最大の待ち秒数。
+	MaxScreenshotDelaySeconds = 300
+	// MaxScreenshotBurstCount は連写1回で撮れる最大枚数。
+	MaxScreenshotBurstCount = 60
+	// MinScreenshotBurstIntervalMs / MaxScreenshotBurstIntervalMs は連写の撮影間隔の範囲。
+	// 下限は screencap-cli 1回分の所要時間の目安で、これより短くしても撮影が追いつかない。
+	MinScreenshotBurstIntervalMs = 200
+	MaxScreenshotBurstIntervalMs = 60_000
+)
+
+// ScreenshotSeries はタイマー撮影・連写の指定を表す。
+// DelaySeconds 秒待ってから Count 枚を IntervalMs ミリ秒間隔で撮る。Count が 1 のとき IntervalMs は使わない。
+type ScreenshotSeries struct {
+	DelaySeconds int
+	Count        int
+	IntervalMs   int
+}
+
+// validate は指定が範囲内か確認する。
+func (series ScreenshotSeries) validate() error {
+	if series.DelaySeconds < 0 || series.DelaySeconds > MaxScreenshotDelaySeconds {
+		return newServiceError("撮影までの待ち時間が不正です", fmt.Sprintf("delaySecondsは0から%dの範囲で指定してください", MaxScreenshotDelaySeconds))
+	}
+	if series.Count < 1 || series.Count > MaxScreenshotBurstCount {
+		return newServiceError("連写の枚数が不正です", fmt.Sprintf("countは1から%dの範囲で指定してください", MaxScreenshotBurstCount))
+	}
+	if series.Count > 1 && (series.IntervalMs < MinScreenshotBurstIntervalMs || series.IntervalMs > MaxScreenshotBurstIntervalMs) {
+		return newServiceError("連写の間隔が不正です",
+			fmt.Sprintf("intervalMsは%dから%dの範囲で指定してください", MinScreenshotBurstIntervalMs, MaxScreenshotBurstIntervalMs))
+	}
+	return nil
+}
+
+// CaptureScreenshotSeries は指定ゲームのウィンドウを、待ち時間のあと指定枚数だけ撮影する。
+// ゲームと PID は開始時に1度だけ解決し、ファイル名は開始時刻を共有した連番（"<日時>_<ゲームID>_001.png" …）にする。
+// 撮影ごとに onProgress（nil 可）へ撮影済み枚数を通知する。
+// ctx がキャンセルされた場合は、そこまでに撮れた分を Canceled 付きでエラーなしに返す（待ち時間中なら0枚）。
+// 2枚目以降の撮影失敗（ゲーム終了など）も撮れた分を返したいので、1枚も撮れていないときだけエラーにする。
+func (service *ScreenshotService) CaptureScreenshotSeries(
+	ctx context.Context,
+	gameID string,
+	series ScreenshotSeries,
+	onProgress ProgressFunc,
+) (domain.ScreenshotSeriesResult, error) {
+	if err := series.validate(); err != nil {
+		return domain.ScreenshotSeriesResult{}, err
+	}
+	game, pid, err := service.resolveGameCapture(ctx, gameID)
+	if err != nil {
+		return domain.ScreenshotSeriesResult{}, err
+	}
+	outcome := domain.ScreenshotSeriesResult{Captures: make([]domain.CaptureResult, 0, series.Count)}
+	service.logCapture(slog.LevelInfo, "連続撮影開始",
+		"gameId", game.ID, "pid", pid, "delaySeconds", series.DelaySeconds, "count", series.Count, "intervalMs", series.IntervalMs)
+
+	if err := service.wait(ctx, time.Duration(series.DelaySeconds)*time.Second); err != nil {
+		outcome.Canceled = true
+		service.logCapture(slog.LevelInfo, "連続撮影をキャンセル", "gameId", game.ID, "captured", 0)
+		return outcome, nil
+	}
+
+	saveDir := filepath.Join(service.screenshotsRoot(), game.ID)
+	if err := os.MkdirAll(saveDir, 0o700); err != nil {
+		return domain.ScreenshotSeriesResult{}, err
+	}
+	startedAt := service.now()
+	interval := time.Duration(series.IntervalMs) * time.Millisecond
+	for index := 0; index < series.Count; index++ {
+		if index > 0 {
+			if err := service.wait(ctx, interval); err != nil {
+				outcome.Canceled = true
+				break
+			}
+		}
+		fullPath := filepath.Join(saveDir, service.screenshotFileName(startedAt, game.ID, fmt.Sprintf("_%03d", index+1)))
+		captured, err := service.capture(ctx, pid, fullPath, game.ID)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+				outcome.Canceled = true
+				break
+			}
+			service.logCapture(slog.LevelWarn, "連続撮影の途中で失敗", "gameId", game.ID, "index", index+1, "error", err)
+			if len(outcome.Captures) == 0 {
+				return domain.ScreenshotSeriesResult{}, err
+			}
+			break
+		}
+		outcome.Captures = append(outcome.Captures, captured)
+		if onProgress != nil {
+			onProgress(len(outcome.Captures), series.Count)
+		}
+	}
+	service.logCapture(slog.LevelInfo, "連続撮影完了",
+		"gameId", game.ID, "captured", len(outcome.Captures), "requested", series.Count, "canceled", outcome.Canceled)
+	return outcome, nil
+}
+
+// waitContext は delay だけ待つ。ctx がキャンセルされたらその時点でエラーを返す。
+func waitContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
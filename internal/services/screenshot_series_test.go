@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+)
+
+func newSeriesTestService(t *testing.T) *ScreenshotService {
+	t.Helper()
+	service := NewScreenshotService(config.Config{AppDataDir: t.TempDir()}, fakeScreenshotRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game", ExePath: "game.exe"}, nil
+		},
+	}, resolverReturning(1234), newTestLogger())
+	service.wait = func(ctx context.Context, delay time.Duration) error { return ctx.Err() }
+	return service
+}
+
+func TestCaptureScreenshotSeriesNamesFilesSequentially(t *testing.T) {
+	t.Parallel()
+
+	service := newSeriesTestService(t)
+	waits := make([]time.Duration, 0)
+	service.wait = func(ctx context.Context, delay time.Duration) error {
+		waits = append(waits, delay)
+		return nil
+	}
+	paths := make([]string, 0)
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		paths = append(paths, outPath)
+		return captureDetail{}, nil
+	}
+	progress := make([]int, 0)
+
+	outcome, err := service.CaptureScreenshotSeries(context.Background(), "game-1",
+		ScreenshotSeries{DelaySeconds: 3, Count: 3, IntervalMs: 500},
+		func(current, total int) { progress = append(progress, current) })
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if outcome.Canceled || len(outcome.Captures) != 3 {
+		t.Fatalf("expected 3 captures, got %+v", outcome)
+	}
+	prefix := strings.TrimSuffix(filepath.Base(paths[0]), "_001.png")
+	for index, path := range paths {
+		want := prefix + []string{"_001.png", "_002.png", "_003.png"}[index]
+		if filepath.Base(path) != want {
+			t.Fatalf("capture %d: expected %s, got %s", index, want, filepath.Base(path))
+		}
+	}
+	if !strings.HasSuffix(prefix, "_game-1") {
+		t.Fatalf("expected game id in file name, got %s", prefix)
+	}
+	wantWaits := []time.Duration{3 * time.Second, 500 * time.Millisecond, 500 * time.Millisecond}
+	if len(waits) != len(wantWaits) || waits[0] != wantWaits[0] || waits[1] != wantWaits[1] || waits[2] != wantWaits[2] {
+		t.Fatalf("expected waits %v, got %v", wantWaits, waits)
+	}
+	if len(progress) != 3 || progress[2] != 3 {
+		t.Fatalf("expected progress per capture, got %v", progress)
+	}
+}
+
+func TestCaptureScreenshotSeriesReturnsPartialResultOnCancel(t *testing.T) {
+	t.Parallel()
+
+	service := newSeriesTestService(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		cancel()
+		return captureDetail{}, nil
+	}
+
+	outcome, err := service.CaptureScreenshotSeries(ctx, "game-1", ScreenshotSeries{Count: 5, IntervalMs: 200}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !outcome.Canceled || len(outcome.Captures) != 1 {
+		t.Fatalf("expected 1 capture and canceled, got %+v", outcome)
+	}
+}
+
+func TestCaptureScreenshotSeriesCanceledDuringDelayCapturesNothing(t *testing.T) {
+	t.Parallel()
+
+	service := newSeriesTestService(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	captured := false
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		captured = true
+		return captureDetail{}, nil
+	}
+
+	outcome, err := service.CaptureScreenshotSeries(ctx, "game-1", ScreenshotSeries{DelaySeconds: 10, Count: 1}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !outcome.Canceled || len(outcome.Captures) != 0 || captured {
+		t.Fatalf("expected cancel before capture, got %+v captured=%v", outcome, captured)
+	}
+}
+
+func TestCaptureScreenshotSeriesRejectsOutOfRangeParameters(t *testing.T) {
+	t.Parallel()
+
+	service := newSeriesTestService(t)
+	for _, series := range []ScreenshotSeries{
+		{DelaySeconds: -1, Count: 1},
+		{DelaySeconds: MaxScreenshotDelaySeconds + 1, Count: 1},
+		{Count: 0},
+		{Count: MaxScreenshotBurstCount + 1, IntervalMs: 1000},
+		{Count: 2, IntervalMs: MinScreenshotBurstIntervalMs - 1},
+	} {
+		if _, err := service.CaptureScreenshotSeries(context.Background(), "game-1", series, nil); err == nil {
+			t.Fatalf("expected validation error for %+v", series)
+		}
+	}
+}
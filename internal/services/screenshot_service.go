@@ -5,6 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -13,6 +17,7 @@ import (
 
 	"CloudLaunch_Go/internal/config"
 	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/logging"
 )
 
 // hotkeyDefaultDirID は対象ゲームが特定できない場合のホットキー保存先ディレクトリID。
@@ -29,10 +34,21 @@ type ScreenshotService struct {
 	localJpeg   bool
 	jpegQuality int
 	fileLogger  *slog.Logger
-	logFile     *os.File
+	logFile     io.Closer
 	// captureFunc はプラットフォーム依存のキャプチャ実装。テストで差し替え可能。
 	// pid が 0 のときはフォアグラウンドウィンドウを対象にする。
-	captureFunc func(ctx context.Context, pid int, outPath string) error
+	captureFunc func(ctx context.Context, pid int, outPath string) (captureDetail, error)
+	// foregroundFunc は前面ウィンドウのプロセスID・実行ファイルパスを返す。テストで差し替え可能。
+	foregroundFunc func() (int, string, error)
+	// tracker は対象ゲームが無いときに直近の監視ゲームを引く（nil 可）。
+	tracker RecentGameTracker
+	// excludedApps はフォアグラウンド撮影から除外する実行ファイル名（小文字、.exe 付き）。
+	excludedApps []string
+	now          func() time.Time
+	// wait はタイマー撮影・連写の待ち時間。ctx のキャンセルで中断する。テストで差し替え可能。
+	wait func(ctx context.Context, delay time.Duration) error
+	// events は保存したスクリーンショットを知らせるイベントバス（nil 可）。
+	events *EventBus
 }
 
 // NewScreenshotService は ScreenshotService を生成する。
@@ -45,17 +61,21 @@ func NewScreenshotService(
 ) *ScreenshotService {
 	fileLogger, logFile := newScreenshotFileLogger(cfg.AppDataDir, cfg.LogLevel)
 	s := &ScreenshotService{
-		repository:  repository,
-		resolver:    resolver,
-		logger:      logger,
-		appDataDir:  cfg.AppDataDir,
-		clientOnly:  cfg.ScreenshotClientOnly,
-		localJpeg:   cfg.ScreenshotLocalJpeg,
-		jpegQuality: cfg.ScreenshotJpegQuality,
-		fileLogger:  fileLogger,
-		logFile:     logFile,
-	}
-	s.captureFunc = s.captureWithScreencap
+		repository:   repository,
+		resolver:     resolver,
+		logger:       logger,
+		appDataDir:   cfg.AppDataDir,
+		clientOnly:   cfg.ScreenshotClientOnly,
+		localJpeg:    cfg.ScreenshotLocalJpeg,
+		jpegQuality:  cfg.ScreenshotJpegQuality,
+		fileLogger:   fileLogger,
+		logFile:      logFile,
+		excludedApps: parseExcludedApps(cfg.ScreenshotExcludedApps),
+		now:          time.Now,
+	}
+	s.captureFunc = s.captureWindow
+	s.foregroundFunc = foregroundProcess
+	s.wait = waitContext
 	return s
 }
 
@@ -88,39 +108,28 @@ func (service *ScreenshotService) SetJpegQuality(value int) {
 	service.jpegQuality = value
 }
 
-// CaptureGameScreenshot は指定ゲームのスクリーンショットを保存し、保存先パスを返す。
-func (service *ScreenshotService) CaptureGameScreenshot(ctx context.Context, gameID string) (string, error) {
-	trimmed := strings.TrimSpace(gameID)
-	if trimmed == "" {
-		return "", errors.New("gameID is empty")
-	}
-	game, err := service.repository.GetGameByID(ctx, trimmed)
-	if err != nil {
-		return "", err
-	}
-	if game == nil {
-		return "", errors.New("game not found")
-	}
+// SetRecentGameTracker は直近に監視していたゲームの取得元を設定する。
+func (service *ScreenshotService) SetRecentGameTracker(tracker RecentGameTracker) {
+	service.tracker = tracker
+}
 
-	// 明示キャプチャでは起動中プロセスの PID が必須。ディレクトリ作成より前に解決し、
-	// 見つからなければ空ディレクトリを作らずにエラーで返す。
-	pid, err := service.resolvePID(game.ExePath)
+// SetEventBus は保存したスクリーンショット（手動・ホットキー・定期・連写）を EventScreenshotCaptured で知らせる先を設定する。
+func (service *ScreenshotService) SetEventBus(bus *EventBus) {
+	service.events = bus
+}
+
+// CaptureGameScreenshot は指定ゲームのスクリーンショットを保存し、撮影結果を返す。
+func (service *ScreenshotService) CaptureGameScreenshot(ctx context.Context, gameID string) (domain.CaptureResult, error) {
+	game, pid, err := service.resolveGameCapture(ctx, gameID)
 	if err != nil {
-		return "", err
-	}
-	if pid == 0 {
-		return "", newServiceError("ゲームのプロセスが見つかりません", "ゲームが起動しているか確認してください")
+		return domain.CaptureResult{}, err
 	}
 
-	baseDir := strings.TrimSpace(service.appDataDir)
-	if baseDir == "" {
-		baseDir = os.TempDir()
-	}
-	saveDir := filepath.Join(baseDir, "screenshots", game.ID)
+	saveDir := filepath.Join(service.screenshotsRoot(), game.ID)
 
 	fullPath, err := service.buildScreenshotPaths(game.ID, saveDir)
 	if err != nil {
-		return "", err
+		return domain.CaptureResult{}, err
 	}
 	service.logCapture(
 		slog.LevelInfo,
@@ -133,42 +142,60 @@ func (service *ScreenshotService) CaptureGameScreenshot(ctx context.Context, gam
 		"localJpeg", service.localJpeg,
 	)
 
-	if err := service.captureFunc(ctx, pid, fullPath); err != nil {
+	captured, err := service.capture(ctx, pid, fullPath, game.ID)
+	if err != nil {
 		service.logCapture(slog.LevelWarn, "スクリーンショット取得に失敗", "gameId", game.ID, "error", err)
-		return "", err
+		return domain.CaptureResult{}, err
+	}
+	service.logCapture(slog.LevelInfo, "スクリーンショット保存完了", "gameId", game.ID, "output", fullPath, "durationMs", captured.DurationMs)
+	return captured, nil
+}
+
+// resolveGameCapture は明示キャプチャの対象ゲームと、撮影するプロセスの PID を引く。
+// 明示キャプチャでは起動中プロセスの PID が必須。ディレクトリ作成より前に解決し、
+// 見つからなければ空ディレクトリを作らずにエラーで返す。
+func (service *ScreenshotService) resolveGameCapture(ctx context.Context, gameID string) (*domain.Game, int, error) {
+	trimmed := strings.TrimSpace(gameID)
+	if trimmed == "" {
+		return nil, 0, errors.New("gameID is empty")
+	}
+	game, err := service.repository.GetGameByID(ctx, trimmed)
+	if err != nil {
+		return nil, 0, err
+	}
+	if game == nil {
+		return nil, 0, errors.New("game not found")
+	}
+	pid, err := service.resolvePID(game.ExePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	if pid == 0 {
+		return nil, 0, newServiceError("ゲームのプロセスが見つかりません", "ゲームが起動しているか確認してください")
 	}
-	service.logCapture(slog.LevelInfo, "スクリーンショット保存完了", "gameId", game.ID, "output", fullPath)
-	return fullPath, nil
+	return game, pid, nil
 }
 
-// CaptureHotkey はホットキー経由でキャプチャし、(保存先ゲームID, 保存パス, error) を返す。
-// 対象ゲームがある場合は PID が必須（プライバシー保護のため、PID が引けないときに
-// 無関係なフォアグラウンドウィンドウを撮って当該ゲームのフォルダにアップロードしない）。
-// 対象ゲームが無い場合のみ pid 0（フォアグラウンド）でキャプチャし default ディレクトリに保存する（アップロードなし）。
-func (service *ScreenshotService) CaptureHotkey(ctx context.Context, preferredGameID string) (string, string, error) {
-	game, err := service.resolveHotkeyGame(ctx, preferredGameID)
+// CaptureHotkey はホットキー経由でキャプチャし、撮影結果を返す。対象ゲームが無いときの GameID は空。
+// 対象の決め方は resolveCaptureTarget を参照。対象ゲームがある場合は PID が必須
+// （プライバシー保護のため、PID が引けないときに無関係なフォアグラウンドウィンドウを撮って
+// 当該ゲームのフォルダにアップロードしない）。対象ゲームが無い場合のみフォアグラウンドを撮り、
+// default ディレクトリに保存する（アップロードなし）。
+func (service *ScreenshotService) CaptureHotkey(ctx context.Context, preferredGameID string) (domain.CaptureResult, error) {
+	target, err := service.resolveCaptureTarget(ctx, preferredGameID)
 	if err != nil {
-		return "", "", err
+		return domain.CaptureResult{}, err
 	}
 
+	game := target.game
 	gameID := hotkeyDefaultDirID
 	gameTitle := "default"
 	gameExePath := ""
-	pid := 0
+	pid := target.pid
 	if game != nil {
 		gameID = game.ID
 		gameTitle = game.Title
 		gameExePath = game.ExePath
-
-		// 対象ゲームがある場合は PID 必須。フォアグラウンドへのフォールバックはしない。
-		resolvedPID, err := service.resolvePID(gameExePath)
-		if err != nil {
-			return "", "", err
-		}
-		if resolvedPID == 0 {
-			return "", "", newServiceError("ゲームのプロセスが見つかりません", "ゲームが起動しているか確認してください")
-		}
-		pid = resolvedPID
 	}
 
 	baseDir := strings.TrimSpace(service.appDataDir)
@@ -178,7 +205,7 @@ func (service *ScreenshotService) CaptureHotkey(ctx context.Context, preferredGa
 	saveDir := filepath.Join(baseDir, "screenshots", gameID)
 	fullPath, err := service.buildScreenshotPaths(gameID, saveDir)
 	if err != nil {
-		return "", "", err
+		return domain.CaptureResult{}, err
 	}
 
 	service.logCapture(
@@ -191,15 +218,50 @@ func (service *ScreenshotService) CaptureHotkey(ctx context.Context, preferredGa
 		"output", fullPath,
 	)
 
-	if err := service.captureFunc(ctx, pid, fullPath); err != nil {
+	resultGameID := ""
+	if game != nil {
+		resultGameID = game.ID
+	}
+	captured, err := service.capture(ctx, pid, fullPath, resultGameID)
+	if err != nil {
 		service.logCapture(slog.LevelWarn, "スクリーンショット取得に失敗", "error", err)
-		return "", "", err
+		return domain.CaptureResult{}, err
 	}
+	return captured, nil
+}
 
-	if game == nil {
-		return "", fullPath, nil
+// capture は captureFunc で outPath に撮影し、所要時間と画像サイズを含む撮影結果を組み立てる。
+func (service *ScreenshotService) capture(ctx context.Context, pid int, outPath string, gameID string) (domain.CaptureResult, error) {
+	startedAt := service.now()
+	detail, err := service.captureFunc(ctx, pid, outPath)
+	if err != nil {
+		return domain.CaptureResult{}, err
 	}
-	return game.ID, fullPath, nil
+	captured := domain.CaptureResult{
+		Path:       outPath,
+		GameID:     gameID,
+		Hwnd:       detail.Hwnd,
+		Backend:    detail.Backend,
+		DurationMs: service.now().Sub(startedAt).Milliseconds(),
+		Warnings:   detail.Warnings,
+	}
+	captured.Width, captured.Height = imageDimensions(outPath)
+	service.events.Publish(EventScreenshotCaptured, captured)
+	return captured, nil
+}
+
+// imageDimensions は画像ファイルのヘッダだけを読んで幅と高さを返す。読めない場合は 0, 0。
+func imageDimensions(path string) (int, int) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+	imageConfig, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0
+	}
+	return imageConfig.Width, imageConfig.Height
 }
 
 func (service *ScreenshotService) resolveHotkeyGame(
@@ -227,15 +289,18 @@ func (service *ScreenshotService) buildScreenshotPaths(gameID string, saveDir st
 	if err := os.MkdirAll(saveDir, 0o700); err != nil {
 		return "", err
 	}
-	// 同一秒内の連続キャプチャで --overwrite により上書き消失しないよう、ミリ秒まで含める。
-	now := time.Now()
+	return filepath.Join(saveDir, service.screenshotFileName(time.Now(), gameID, "")), nil
+}
+
+// screenshotFileName は "<日時>_<ゲームID><suffix><拡張子>" のファイル名を返す。
+// 同一秒内の連続キャプチャで --overwrite により上書き消失しないよう、ミリ秒まで含める。
+func (service *ScreenshotService) screenshotFileName(now time.Time, gameID string, suffix string) string {
 	timestamp := fmt.Sprintf("%s_%03d", now.Format("20060102_150405"), now.Nanosecond()/int(time.Millisecond))
 	ext := ".png"
 	if service.localJpeg {
 		ext = ".jpg"
 	}
-	fullPath := filepath.Join(saveDir, fmt.Sprintf("%s_%s%s", timestamp, gameID, ext))
-	return fullPath, nil
+	return fmt.Sprintf("%s_%s%s%s", timestamp, gameID, suffix, ext)
 }
 
 func (service *ScreenshotService) Close() error {
@@ -247,36 +312,17 @@ func (service *ScreenshotService) Close() error {
 	return err
 }
 
-func newScreenshotFileLogger(appDataDir string, level string) (*slog.Logger, *os.File) {
+// newScreenshotFileLogger は撮影の詳細を残す screenshot.log のロガーを生成する。開けなければ nil を返す。
+func newScreenshotFileLogger(appDataDir string, level string) (*slog.Logger, io.Closer) {
 	baseDir := strings.TrimSpace(appDataDir)
 	if baseDir == "" {
 		baseDir = os.TempDir()
 	}
-	logDir := filepath.Join(baseDir, "logs")
-	if err := os.MkdirAll(logDir, 0o700); err != nil {
-		return nil, nil
-	}
-	logPath := filepath.Join(logDir, "screenshot.log")
-	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	logger, closer, err := logging.NewFileLogger(baseDir, "screenshot.log", level)
 	if err != nil {
 		return nil, nil
 	}
-
-	logLevel := parseLogLevel(level)
-	return slog.New(slog.NewJSONHandler(file, &slog.HandlerOptions{Level: logLevel})), file
-}
-
-func parseLogLevel(level string) slog.Level {
-	switch strings.ToLower(strings.TrimSpace(level)) {
-	case "debug":
-		return slog.LevelDebug
-	case "warn", "warning":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
-	}
+	return logger, closer
 }
 
 func (service *ScreenshotService) logCapture(level slog.Level, msg string, attrs ...any) {
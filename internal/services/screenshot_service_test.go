@@ -2,9 +2,13 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"image"
+	"image/png"
 	"io"
 	"log/slog"
+	"os"
 	"strings"
 	"testing"
 
@@ -116,9 +120,9 @@ func TestScreenshotServiceCaptureGameScreenshotFailsWhenProcessNotRunning(t *tes
 	}, resolverReturning(), newTestLogger())
 
 	captured := false
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
 		captured = true
-		return nil
+		return captureDetail{}, nil
 	}
 
 	_, err := service.CaptureGameScreenshot(context.Background(), "game-1")
@@ -140,9 +144,9 @@ func TestScreenshotServiceCaptureGameScreenshotPassesResolvedPID(t *testing.T) {
 	}, resolverReturning(4242, 9999), newTestLogger())
 
 	gotPID := -1
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
 		gotPID = pid
-		return nil
+		return captureDetail{}, nil
 	}
 
 	if _, err := service.CaptureGameScreenshot(context.Background(), "game-1"); err != nil {
@@ -166,9 +170,9 @@ func TestScreenshotServiceCaptureGameScreenshotReturnsResolverError(t *testing.T
 	}}, newTestLogger())
 
 	captured := false
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
 		captured = true
-		return nil
+		return captureDetail{}, nil
 	}
 
 	_, err := service.CaptureGameScreenshot(context.Background(), "game-1")
@@ -194,8 +198,8 @@ func TestScreenshotServiceCaptureGameScreenshotReturnsCaptureError(t *testing.T)
 			return &domain.Game{ID: gameID, Title: "Game", ExePath: "game.exe"}, nil
 		},
 	}, resolverReturning(1234), newTestLogger())
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
-		return captureErr
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		return captureDetail{}, captureErr
 	}
 
 	_, err := service.CaptureGameScreenshot(context.Background(), "game-1")
@@ -212,19 +216,33 @@ func TestScreenshotServiceCaptureGameScreenshotReturnsPathOnSuccess(t *testing.T
 			return &domain.Game{ID: gameID, Title: "Game", ExePath: "game.exe"}, nil
 		},
 	}, resolverReturning(1234), newTestLogger())
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
-		return nil
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		file, err := os.Create(outPath)
+		if err != nil {
+			return captureDetail{}, err
+		}
+		defer file.Close()
+		if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, 64, 48))); err != nil {
+			return captureDetail{}, err
+		}
+		return captureDetail{Backend: screencapMethod, Hwnd: 0x1234, Warnings: []string{"black"}}, nil
 	}
 
-	path, err := service.CaptureGameScreenshot(context.Background(), "game-1")
+	captured, err := service.CaptureGameScreenshot(context.Background(), "game-1")
 	if err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
-	if !strings.HasSuffix(path, ".png") {
-		t.Fatalf("expected png path, got %s", path)
+	if !strings.HasSuffix(captured.Path, ".png") {
+		t.Fatalf("expected png path, got %s", captured.Path)
 	}
-	if !strings.Contains(path, "game-1") {
-		t.Fatalf("expected path to contain game id, got %s", path)
+	if !strings.Contains(captured.Path, "game-1") {
+		t.Fatalf("expected path to contain game id, got %s", captured.Path)
+	}
+	if captured.GameID != "game-1" || captured.Backend != screencapMethod || captured.Hwnd != 0x1234 {
+		t.Fatalf("expected capture details to be returned, got %#v", captured)
+	}
+	if captured.Width != 64 || captured.Height != 48 || len(captured.Warnings) != 1 {
+		t.Fatalf("expected image size and warnings, got %#v", captured)
 	}
 }
 
@@ -308,12 +326,12 @@ func TestScreenshotServiceCaptureHotkeyRequiresPIDForTargetGame(t *testing.T) {
 	}, resolverReturning(), newTestLogger())
 
 	captured := false
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
 		captured = true
-		return nil
+		return captureDetail{}, nil
 	}
 
-	_, _, err := service.CaptureHotkey(context.Background(), "game-1")
+	_, err := service.CaptureHotkey(context.Background(), "game-1")
 	if err == nil {
 		t.Fatalf("expected error when target game process is not found")
 	}
@@ -335,18 +353,18 @@ func TestScreenshotServiceCaptureHotkeyPropagatesResolverError(t *testing.T) {
 		return nil, errors.New("boom")
 	}}, newTestLogger())
 
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
-		return nil
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		return captureDetail{}, nil
 	}
 
-	_, _, err := service.CaptureHotkey(context.Background(), "game-1")
+	_, err := service.CaptureHotkey(context.Background(), "game-1")
 	if err == nil {
 		t.Fatalf("expected resolver error to propagate")
 	}
 }
 
-// TestScreenshotServiceCaptureHotkeyNoTargetUsesForeground は、対象ゲームが無いとき
-// pid 0（フォアグラウンド）で captureFunc が呼ばれ、gameID 空で返ることを検証する。
+// TestScreenshotServiceCaptureHotkeyNoTargetUsesForeground は、対象ゲームが無く前面ウィンドウも
+// 特定できないとき pid 0（フォアグラウンド）で captureFunc が呼ばれ、gameID 空で返ることを検証する。
 func TestScreenshotServiceCaptureHotkeyNoTargetUsesForeground(t *testing.T) {
 	t.Parallel()
 
@@ -355,24 +373,27 @@ func TestScreenshotServiceCaptureHotkeyNoTargetUsesForeground(t *testing.T) {
 			return nil, nil
 		},
 	}, resolverReturning(4242), newTestLogger())
+	service.foregroundFunc = func() (int, string, error) {
+		return 0, "", errors.New("unsupported")
+	}
 
 	gotPID := -1
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
 		gotPID = pid
-		return nil
+		return captureDetail{}, nil
 	}
 
-	gameID, path, err := service.CaptureHotkey(context.Background(), "")
+	captured, err := service.CaptureHotkey(context.Background(), "")
 	if err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
 	if gotPID != 0 {
 		t.Fatalf("expected foreground pid 0, got %d", gotPID)
 	}
-	if gameID != "" {
-		t.Fatalf("expected empty gameID for foreground capture, got %q", gameID)
+	if captured.GameID != "" {
+		t.Fatalf("expected empty gameID for foreground capture, got %q", captured.GameID)
 	}
-	if path == "" {
+	if captured.Path == "" {
 		t.Fatalf("expected non-empty path")
 	}
 }
@@ -389,20 +410,20 @@ func TestScreenshotServiceCaptureHotkeyPassesResolvedPID(t *testing.T) {
 	}, resolverReturning(7777, 8888), newTestLogger())
 
 	gotPID := -1
-	service.captureFunc = func(ctx context.Context, pid int, outPath string) error {
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
 		gotPID = pid
-		return nil
+		return captureDetail{}, nil
 	}
 
-	gameID, _, err := service.CaptureHotkey(context.Background(), "game-1")
+	captured, err := service.CaptureHotkey(context.Background(), "game-1")
 	if err != nil {
 		t.Fatalf("expected success, got %v", err)
 	}
 	if gotPID != 7777 {
 		t.Fatalf("expected pid 7777, got %d", gotPID)
 	}
-	if gameID != "game-1" {
-		t.Fatalf("expected gameID game-1, got %q", gameID)
+	if captured.GameID != "game-1" {
+		t.Fatalf("expected gameID game-1, got %q", captured.GameID)
 	}
 }
 
@@ -588,3 +609,22 @@ func TestParseScreencapResult(t *testing.T) {
 		}
 	})
 }
+
+func TestParseScreencapHwnd(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]uint64{
+		`394862`:     394862,
+		`"0x6067E"`:  0x6067E,
+		`null`:       0,
+		``:           0,
+		`"unknown"`:  0,
+		`-1`:         0,
+		`"  4660  "`: 4660,
+	}
+	for raw, want := range cases {
+		if got := parseScreencapHwnd(json.RawMessage(raw)); got != want {
+			t.Errorf("%s: want %d got %d", raw, want, got)
+		}
+	}
+}
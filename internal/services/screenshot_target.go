@@ -0,0 +1,135 @@
+// ホットキー撮影の対象ウィンドウ（ゲーム・直近の監視ゲーム・フォアグラウンド）を決定する。
+package services
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// hotkeyLookbackWindow は対象ゲームが無いとき、直近に監視していたゲームを優先する期間。
+// ゲーム終了直後やランチャーから本体への切り替え中に CloudLaunch 自身が前面に来ても、
+// その間はゲームのウィンドウを撮る。
+const hotkeyLookbackWindow = 15 * time.Second
+
+// captureTarget はホットキー撮影の対象を表す。game が nil のときは default ディレクトリに保存する。
+type captureTarget struct {
+	game *domain.Game
+	pid  int
+}
+
+// resolveCaptureTarget はホットキー撮影の対象を決める。
+//  1. preferredGameID（監視中ゲーム）があればその PID（必須）
+//  2. 直近 hotkeyLookbackWindow 以内に監視していたゲームの PID が引ければそれ
+//  3. それ以外はフォアグラウンドウィンドウ。ただし自プロセスと除外リストのアプリは撮らない
+func (service *ScreenshotService) resolveCaptureTarget(ctx context.Context, preferredGameID string) (captureTarget, error) {
+	game, err := service.resolveHotkeyGame(ctx, preferredGameID)
+	if err != nil {
+		return captureTarget{}, err
+	}
+	if game != nil {
+		// 対象ゲームがある場合は PID 必須。フォアグラウンドへのフォールバックはしない。
+		pid, err := service.resolvePID(game.ExePath)
+		if err != nil {
+			return captureTarget{}, err
+		}
+		if pid == 0 {
+			return captureTarget{}, newServiceError("ゲームのプロセスが見つかりません", "ゲームが起動しているか確認してください")
+		}
+		return captureTarget{game: game, pid: pid}, nil
+	}
+
+	if target, ok := service.resolveRecentTarget(ctx); ok {
+		return target, nil
+	}
+
+	if service.foregroundFunc == nil {
+		return captureTarget{}, nil
+	}
+	pid, exePath, err := service.foregroundFunc()
+	if err != nil {
+		// 前面ウィンドウを特定できない場合は従来どおり screencap-cli に任せる。
+		service.logCapture(slog.LevelWarn, "フォアグラウンドウィンドウを特定できません", "error", err)
+		return captureTarget{}, nil
+	}
+	if pid == os.Getpid() {
+		return captureTarget{}, newServiceError("CloudLaunch のウィンドウは撮影できません", "ゲームのウィンドウを前面にしてから撮影してください")
+	}
+	if service.isExcludedApp(exePath) {
+		return captureTarget{}, newServiceError("除外対象のアプリが前面にあるため撮影しませんでした", windowsPathBase(exePath))
+	}
+	// 判定後に前面が切り替わっても判定したウィンドウを撮るよう PID を指定する。
+	return captureTarget{pid: pid}, nil
+}
+
+// resolveRecentTarget は直近に監視していたゲームがまだ起動していればそれを対象にする。
+// 見つからない・期限切れ・取得失敗のときは ok=false を返し、フォアグラウンドの判定に進む。
+func (service *ScreenshotService) resolveRecentTarget(ctx context.Context) (captureTarget, bool) {
+	if service.tracker == nil {
+		return captureTarget{}, false
+	}
+	gameID, lastSeen := service.tracker.LastTrackedGame()
+	if strings.TrimSpace(gameID) == "" || lastSeen.IsZero() || service.now().Sub(lastSeen) > hotkeyLookbackWindow {
+		return captureTarget{}, false
+	}
+	game, err := service.resolveHotkeyGame(ctx, gameID)
+	if err != nil || game == nil {
+		return captureTarget{}, false
+	}
+	pid, err := service.resolvePID(game.ExePath)
+	if err != nil || pid == 0 {
+		return captureTarget{}, false
+	}
+	return captureTarget{game: game, pid: pid}, true
+}
+
+// SetExcludedApps はホットキー撮影の対象から除外するアプリ（実行ファイル名の一覧）を更新する。
+func (service *ScreenshotService) SetExcludedApps(value string) {
+	service.excludedApps = parseExcludedApps(value)
+}
+
+func (service *ScreenshotService) isExcludedApp(exePath string) bool {
+	name := strings.ToLower(windowsPathBase(strings.TrimSpace(exePath)))
+	if name == "" {
+		return false
+	}
+	for _, excluded := range service.excludedApps {
+		if name == excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeScreenshotExcludedApps は除外アプリ一覧（カンマ・セミコロン・改行区切り）を
+// 重複を除いたカンマ区切りに正規化する。パスが指定された場合はファイル名のみを残す。
+func NormalizeScreenshotExcludedApps(value string) string {
+	return strings.Join(parseExcludedApps(value), ",")
+}
+
+func parseExcludedApps(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n' || r == '\r'
+	})
+	apps := make([]string, 0, len(fields))
+	seen := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		name := strings.ToLower(windowsPathBase(strings.TrimSpace(field)))
+		if name == "" {
+			continue
+		}
+		if !strings.HasSuffix(name, ".exe") {
+			name += ".exe"
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		apps = append(apps, name)
+	}
+	return apps
+}
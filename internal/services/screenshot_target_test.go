@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+)
+
+// fakeRecentGameTracker は RecentGameTracker のテスト用スタブ。
+type fakeRecentGameTracker struct {
+	gameID   string
+	lastSeen time.Time
+}
+
+func (tracker fakeRecentGameTracker) LastTrackedGame() (string, time.Time) {
+	return tracker.gameID, tracker.lastSeen
+}
+
+func newHotkeyTargetTestService(t *testing.T, cfg config.Config, resolver ProcessIDResolver) (*ScreenshotService, *int) {
+	t.Helper()
+	cfg.AppDataDir = t.TempDir()
+	service := NewScreenshotService(cfg, fakeScreenshotRepository{
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game", ExePath: `C:\games\game.exe`}, nil
+		},
+	}, resolver, newTestLogger())
+	gotPID := -1
+	service.captureFunc = func(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+		gotPID = pid
+		return captureDetail{}, nil
+	}
+	return service, &gotPID
+}
+
+func TestScreenshotServiceCaptureHotkeyRejectsOwnWindow(t *testing.T) {
+	t.Parallel()
+
+	service, gotPID := newHotkeyTargetTestService(t, config.Config{}, resolverReturning())
+	service.foregroundFunc = func() (int, string, error) {
+		return os.Getpid(), `C:\apps\CloudLaunch.exe`, nil
+	}
+
+	_, err := service.CaptureHotkey(context.Background(), "")
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatalf("expected service error, got %v", err)
+	}
+	if *gotPID != -1 {
+		t.Fatalf("captureFunc must not run for own window, got pid %d", *gotPID)
+	}
+}
+
+func TestScreenshotServiceCaptureHotkeyRejectsExcludedApp(t *testing.T) {
+	t.Parallel()
+
+	service, gotPID := newHotkeyTargetTestService(t, config.Config{ScreenshotExcludedApps: "discord, OBS64.exe"}, resolverReturning())
+	service.foregroundFunc = func() (int, string, error) {
+		return 5150, `C:\Program Files\obs-studio\bin\64bit\obs64.exe`, nil
+	}
+
+	if _, err := service.CaptureHotkey(context.Background(), ""); err == nil {
+		t.Fatalf("expected excluded app to be rejected")
+	}
+	if *gotPID != -1 {
+		t.Fatalf("captureFunc must not run for excluded app, got pid %d", *gotPID)
+	}
+
+	service.SetExcludedApps("")
+	if _, err := service.CaptureHotkey(context.Background(), ""); err != nil {
+		t.Fatalf("expected success after clearing exclusions, got %v", err)
+	}
+	if *gotPID != 5150 {
+		t.Fatalf("expected foreground pid 5150, got %d", *gotPID)
+	}
+}
+
+func TestScreenshotServiceCaptureHotkeyPrefersRecentTrackedGame(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		lastSeen   time.Time
+		resolver   ProcessIDResolver
+		wantGameID string
+		wantPID    int
+	}{
+		{
+			name:       "recent game still running",
+			lastSeen:   now.Add(-5 * time.Second),
+			resolver:   resolverReturning(7777),
+			wantGameID: "recent",
+			wantPID:    7777,
+		},
+		{
+			name:     "stale game falls back to foreground",
+			lastSeen: now.Add(-time.Minute),
+			resolver: resolverReturning(7777),
+			wantPID:  4242,
+		},
+		{
+			name:     "recent game not running falls back to foreground",
+			lastSeen: now.Add(-5 * time.Second),
+			resolver: resolverReturning(),
+			wantPID:  4242,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			service, gotPID := newHotkeyTargetTestService(t, config.Config{}, tc.resolver)
+			service.now = func() time.Time { return now }
+			service.SetRecentGameTracker(fakeRecentGameTracker{gameID: "recent", lastSeen: tc.lastSeen})
+			service.foregroundFunc = func() (int, string, error) {
+				return 4242, `C:\apps\browser.exe`, nil
+			}
+
+			captured, err := service.CaptureHotkey(context.Background(), "")
+			if err != nil {
+				t.Fatalf("expected success, got %v", err)
+			}
+			if captured.GameID != tc.wantGameID {
+				t.Fatalf("gameID: want %q got %q", tc.wantGameID, captured.GameID)
+			}
+			if *gotPID != tc.wantPID {
+				t.Fatalf("pid: want %d got %d", tc.wantPID, *gotPID)
+			}
+		})
+	}
+}
+
+func TestNormalizeScreenshotExcludedApps(t *testing.T) {
+	t.Parallel()
+
+	got := NormalizeScreenshotExcludedApps(" Discord ;C:\\tools\\OBS64.exe\ndiscord.exe,, ")
+	if got != "discord.exe,obs64.exe" {
+		t.Fatalf("unexpected normalized list: %q", got)
+	}
+}
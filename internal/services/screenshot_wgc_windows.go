@@ -0,0 +1,435 @@
+//go:build windows
+
+// Windows向けに Windows.Graphics.Capture（WGC）でウィンドウをプロセス内で撮影する。
+// 外部の screencap-cli.exe を使わずに撮れるようにし、撮れないときだけ screencap-cli に任せる。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// builtinWGCMethod はプロセス内の WGC で撮影したときに CaptureResult.Backend に返す撮影方式。
+	builtinWGCMethod = "wgc-builtin"
+	// builtinWGCFrameTimeout は最初のフレームが届くまで待つ時間。届かなければ screencap-cli に任せる。
+	builtinWGCFrameTimeout = 2 * time.Second
+	builtinWGCPollInterval = 10 * time.Millisecond
+
+	d3dDriverTypeHardware   = 1
+	d3d11CreateBGRASupport  = 0x20
+	d3d11SDKVersion         = 7
+	d3d11UsageStaging       = 3
+	d3d11CPUAccessRead      = 0x20000
+	d3d11MapRead            = 1
+	dxgiFormatB8G8R8A8UNorm = 87
+	roInitMultithreaded     = 1
+)
+
+// COM / WinRT インターフェースの vtable 上の位置（IUnknown: 0-2、IInspectable: 3-5 の後に各メソッドが続く）。
+const (
+	vtblQueryInterface = 0
+	vtblRelease        = 2
+
+	vtblCaptureItemInteropCreateForWindow  = 3
+	vtblCaptureItemGetSize                 = 7
+	vtblFramePoolStaticsCreateFreeThreaded = 6
+	vtblFramePoolTryGetNextFrame           = 7
+	vtblFramePoolCreateCaptureSession      = 10
+	vtblCaptureSessionStartCapture         = 6
+	vtblCaptureSessionPutCursorEnabled     = 7
+	vtblCaptureSessionPutBorderRequired    = 7
+	vtblCaptureFrameGetSurface             = 6
+	vtblCaptureFrameGetContentSize         = 8
+	vtblClosableClose                      = 6
+	vtblDxgiInterfaceAccessGetInterface    = 3
+	vtblTexture2DGetDesc                   = 10
+	vtblDeviceCreateTexture2D              = 5
+	vtblContextMap                         = 14
+	vtblContextUnmap                       = 15
+	vtblContextCopyResource                = 47
+)
+
+var (
+	d3d11dll                                 = windows.NewLazySystemDLL("d3d11.dll")
+	combasedll                               = windows.NewLazySystemDLL("combase.dll")
+	procD3D11CreateDevice                    = d3d11dll.NewProc("D3D11CreateDevice")
+	procCreateDirect3D11DeviceFromDXGIDevice = d3d11dll.NewProc("CreateDirect3D11DeviceFromDXGIDevice")
+	procRoInitialize                         = combasedll.NewProc("RoInitialize")
+	procRoUninitialize                       = combasedll.NewProc("RoUninitialize")
+	procRoGetActivationFactory               = combasedll.NewProc("RoGetActivationFactory")
+	procWindowsCreateString                  = combasedll.NewProc("WindowsCreateString")
+	procWindowsDeleteString                  = combasedll.NewProc("WindowsDeleteString")
+
+	iidIDXGIDevice                         = windows.GUID{Data1: 0x54ec77fa, Data2: 0x1377, Data3: 0x44e6, Data4: [8]byte{0x8c, 0x32, 0x88, 0xfd, 0x5f, 0x44, 0xc8, 0x4c}}
+	iidIDirect3DDevice                     = windows.GUID{Data1: 0xa37624ab, Data2: 0x8d5f, Data3: 0x4650, Data4: [8]byte{0x9d, 0x3e, 0x9e, 0xae, 0x3d, 0x9b, 0xc6, 0x70}}
+	iidIGraphicsCaptureItemInterop         = windows.GUID{Data1: 0x3628e81b, Data2: 0x3cac, Data3: 0x4c60, Data4: [8]byte{0xb7, 0xf4, 0x23, 0xce, 0x0e, 0x0c, 0x33, 0x56}}
+	iidIGraphicsCaptureItem                = windows.GUID{Data1: 0x79c3f95b, Data2: 0x31f7, Data3: 0x4ec2, Data4: [8]byte{0xa4, 0x64, 0x63, 0x2e, 0xf5, 0xd3, 0x07, 0x60}}
+	iidIDirect3D11CaptureFramePoolStatics2 = windows.GUID{Data1: 0x589b103f, Data2: 0x6bbc, Data3: 0x5df5, Data4: [8]byte{0xa9, 0x91, 0x02, 0xe2, 0x8b, 0x3b, 0x66, 0xd5}}
+	iidIClosable                           = windows.GUID{Data1: 0x30d5a829, Data2: 0x7fa4, Data3: 0x4026, Data4: [8]byte{0x83, 0xbb, 0xd7, 0x5b, 0xae, 0x4e, 0xa9, 0x9e}}
+	iidIGraphicsCaptureSession2            = windows.GUID{Data1: 0x2c39ae40, Data2: 0x7d2e, Data3: 0x5044, Data4: [8]byte{0x80, 0x4e, 0x8b, 0x67, 0x99, 0xd4, 0xcf, 0x9e}}
+	iidIGraphicsCaptureSession3            = windows.GUID{Data1: 0xf2cdd966, Data2: 0x22ae, Data3: 0x5ea1, Data4: [8]byte{0x95, 0x96, 0x3a, 0x28, 0x93, 0x44, 0xc3, 0xbe}}
+	iidIDirect3DDxgiInterfaceAccess        = windows.GUID{Data1: 0xa9b3d012, Data2: 0x3df2, Data3: 0x4ee3, Data4: [8]byte{0xb8, 0xd1, 0x86, 0x95, 0xf4, 0x57, 0xd3, 0xc1}}
+	iidID3D11Texture2D                     = windows.GUID{Data1: 0x6f15aaf2, Data2: 0xd208, Data3: 0x4e89, Data4: [8]byte{0x9a, 0xb4, 0x48, 0x95, 0x35, 0xd3, 0x4f, 0x9c}}
+)
+
+// comObject は COM オブジェクトの先頭（vtable へのポインタ）を表す。
+// vtable はシステムのメモリにあるため、Go のポインタとして持っても GC の対象にはならない。
+type comObject struct {
+	vtbl *[64]uintptr
+}
+
+// call は vtable の index 番目のメソッドを this 付きで呼び、HRESULT の失敗をエラーにする。
+// 出力引数には Go のローカル変数のアドレスを渡すため、uintptrescapes で参照先をヒープに置き、呼び出し中に動かないようにする。
+//
+//go:uintptrescapes
+func (object *comObject) call(index int, args ...uintptr) error {
+	hr, _, _ := syscall.SyscallN(object.vtbl[index], append([]uintptr{uintptr(unsafe.Pointer(object))}, args...)...)
+	return hresultError(hr)
+}
+
+func (object *comObject) queryInterface(iid *windows.GUID) (*comObject, error) {
+	var out *comObject
+	if err := object.call(vtblQueryInterface, uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&out))); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (object *comObject) release() {
+	if object != nil {
+		syscall.SyscallN(object.vtbl[vtblRelease], uintptr(unsafe.Pointer(object)))
+	}
+}
+
+// close は IClosable.Close を呼ぶ（WinRT のフレームプール・セッション・フレームはこれで資源を返す）。
+func (object *comObject) close() {
+	closable, err := object.queryInterface(&iidIClosable)
+	if err != nil {
+		return
+	}
+	_ = closable.call(vtblClosableClose)
+	closable.release()
+}
+
+func hresultError(hr uintptr) error {
+	if int32(hr) < 0 {
+		return fmt.Errorf("HRESULT 0x%08X", uint32(hr))
+	}
+	return nil
+}
+
+// sizeInt32 は Windows.Graphics.SizeInt32。
+type sizeInt32 struct {
+	Width, Height int32
+}
+
+// packed は値渡しの SizeInt32（8 バイトの構造体）を1つのレジスタ値にする（x64 / ARM64 の呼び出し規約）。
+func (size sizeInt32) packed() uintptr {
+	return uintptr(uint32(size.Width)) | uintptr(uint32(size.Height))<<32
+}
+
+type d3d11Texture2DDesc struct {
+	Width          uint32
+	Height         uint32
+	MipLevels      uint32
+	ArraySize      uint32
+	Format         uint32
+	SampleCount    uint32
+	SampleQuality  uint32
+	Usage          uint32
+	BindFlags      uint32
+	CPUAccessFlags uint32
+	MiscFlags      uint32
+}
+
+type d3d11MappedSubresource struct {
+	Data       uintptr
+	RowPitch   uint32
+	DepthPitch uint32
+}
+
+// captureWithBuiltinWGC は pid のメインウィンドウ（pid が 0 なら前面のウィンドウ）を WGC で1フレーム撮り、outPath に保存する。
+// WGC が使えない環境（古い Windows・GPU なし）や最小化中のウィンドウではエラーを返し、呼び出し側が screencap-cli に任せる。
+func (service *ScreenshotService) captureWithBuiltinWGC(ctx context.Context, pid int, outPath string) (captureDetail, error) {
+	detail := captureDetail{Backend: builtinWGCMethod}
+	hwnd := windows.GetForegroundWindow()
+	if pid > 0 {
+		hwnd = findProcessWindow(uint32(pid))
+	}
+	if hwnd == 0 {
+		return detail, errors.New("撮影するウィンドウが見つかりません")
+	}
+	detail.Hwnd = uint64(hwnd)
+	if iconic, _, _ := procIsIconic.Call(uintptr(hwnd)); iconic != 0 {
+		return detail, errors.New("ウィンドウが最小化されています")
+	}
+
+	// WinRT の呼び出しは初期化したスレッドで行う。
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if hr, _, _ := procRoInitialize.Call(roInitMultithreaded); int32(hr) >= 0 {
+		defer procRoUninitialize.Call()
+	} else if uint32(hr) != uint32(windows.RPC_E_CHANGED_MODE) {
+		return detail, fmt.Errorf("RoInitialize: %w", hresultError(hr))
+	}
+
+	picture, err := grabWindowFrame(ctx, hwnd)
+	if err != nil {
+		return detail, err
+	}
+	if service.clientOnly {
+		picture = cropToClientArea(picture, hwnd)
+	}
+	if ratio := blackPixelRatio(picture); ratio > screencapBlackWarnRatio {
+		detail.Warnings = append(detail.Warnings, "画像がほぼ真っ黒です（ウィンドウが最小化されている可能性があります）")
+	}
+	return detail, writeCaptureImage(outPath, picture, service.localJpeg, service.jpegQuality)
+}
+
+// grabWindowFrame は D3D11 デバイスを作り、ウィンドウの GraphicsCaptureItem から最初のフレームを読み出す。
+func grabWindowFrame(ctx context.Context, hwnd windows.HWND) (*image.RGBA, error) {
+	device, deviceContext, winrtDevice, err := createCaptureDevice()
+	if err != nil {
+		return nil, err
+	}
+	defer device.release()
+	defer deviceContext.release()
+	defer winrtDevice.release()
+
+	item, err := createCaptureItemForWindow(hwnd)
+	if err != nil {
+		return nil, err
+	}
+	defer item.release()
+	var size sizeInt32
+	if err := item.call(vtblCaptureItemGetSize, uintptr(unsafe.Pointer(&size))); err != nil {
+		return nil, fmt.Errorf("GraphicsCaptureItem.Size: %w", err)
+	}
+	if size.Width <= 0 || size.Height <= 0 {
+		return nil, errors.New("ウィンドウの大きさを取得できません")
+	}
+
+	statics, err := activationFactory("Windows.Graphics.Capture.Direct3D11CaptureFramePool", &iidIDirect3D11CaptureFramePoolStatics2)
+	if err != nil {
+		return nil, err
+	}
+	defer statics.release()
+	var framePool *comObject
+	if err := statics.call(vtblFramePoolStaticsCreateFreeThreaded,
+		uintptr(unsafe.Pointer(winrtDevice)), dxgiFormatB8G8R8A8UNorm, 1, size.packed(), uintptr(unsafe.Pointer(&framePool)),
+	); err != nil {
+		return nil, fmt.Errorf("Direct3D11CaptureFramePool.CreateFreeThreaded: %w", err)
+	}
+	defer framePool.release()
+	defer framePool.close()
+
+	var session *comObject
+	if err := framePool.call(vtblFramePoolCreateCaptureSession, uintptr(unsafe.Pointer(item)), uintptr(unsafe.Pointer(&session))); err != nil {
+		return nil, fmt.Errorf("CreateCaptureSession: %w", err)
+	}
+	defer session.release()
+	defer session.close()
+	// カーソルと撮影中の黄色い枠は写さない。古い Windows では該当インターフェースが無いので黙って諦める。
+	if session2, err := session.queryInterface(&iidIGraphicsCaptureSession2); err == nil {
+		_ = session2.call(vtblCaptureSessionPutCursorEnabled, 0)
+		session2.release()
+	}
+	if session3, err := session.queryInterface(&iidIGraphicsCaptureSession3); err == nil {
+		_ = session3.call(vtblCaptureSessionPutBorderRequired, 0)
+		session3.release()
+	}
+	if err := session.call(vtblCaptureSessionStartCapture); err != nil {
+		return nil, fmt.Errorf("StartCapture: %w", err)
+	}
+
+	frame, err := waitForFrame(ctx, framePool)
+	if err != nil {
+		return nil, err
+	}
+	defer frame.release()
+	defer frame.close()
+	return readFramePixels(device, deviceContext, frame)
+}
+
+// createCaptureDevice は BGRA 対応の D3D11 デバイスと、WGC に渡す WinRT の IDirect3DDevice を作る。
+func createCaptureDevice() (device, deviceContext, winrtDevice *comObject, err error) {
+	var featureLevel uint32
+	hr, _, _ := procD3D11CreateDevice.Call(
+		0, d3dDriverTypeHardware, 0, d3d11CreateBGRASupport, 0, 0, d3d11SDKVersion,
+		uintptr(unsafe.Pointer(&device)), uintptr(unsafe.Pointer(&featureLevel)), uintptr(unsafe.Pointer(&deviceContext)),
+	)
+	if err := hresultError(hr); err != nil {
+		return nil, nil, nil, fmt.Errorf("D3D11CreateDevice: %w", err)
+	}
+	dxgiDevice, err := device.queryInterface(&iidIDXGIDevice)
+	if err != nil {
+		device.release()
+		deviceContext.release()
+		return nil, nil, nil, fmt.Errorf("IDXGIDevice: %w", err)
+	}
+	defer dxgiDevice.release()
+	var inspectable *comObject
+	hr, _, _ = procCreateDirect3D11DeviceFromDXGIDevice.Call(uintptr(unsafe.Pointer(dxgiDevice)), uintptr(unsafe.Pointer(&inspectable)))
+	if err := hresultError(hr); err != nil {
+		device.release()
+		deviceContext.release()
+		return nil, nil, nil, fmt.Errorf("CreateDirect3D11DeviceFromDXGIDevice: %w", err)
+	}
+	defer inspectable.release()
+	winrtDevice, err = inspectable.queryInterface(&iidIDirect3DDevice)
+	if err != nil {
+		device.release()
+		deviceContext.release()
+		return nil, nil, nil, fmt.Errorf("IDirect3DDevice: %w", err)
+	}
+	return device, deviceContext, winrtDevice, nil
+}
+
+// createCaptureItemForWindow は IGraphicsCaptureItemInterop.CreateForWindow でウィンドウの GraphicsCaptureItem を作る。
+func createCaptureItemForWindow(hwnd windows.HWND) (*comObject, error) {
+	interop, err := activationFactory("Windows.Graphics.Capture.GraphicsCaptureItem", &iidIGraphicsCaptureItemInterop)
+	if err != nil {
+		return nil, err
+	}
+	defer interop.release()
+	var item *comObject
+	if err := interop.call(vtblCaptureItemInteropCreateForWindow,
+		uintptr(hwnd), uintptr(unsafe.Pointer(&iidIGraphicsCaptureItem)), uintptr(unsafe.Pointer(&item)),
+	); err != nil {
+		return nil, fmt.Errorf("CreateForWindow: %w", err)
+	}
+	return item, nil
+}
+
+// activationFactory は WinRT クラスの活性化ファクトリ（静的メソッドのインターフェース）を取得する。
+func activationFactory(className string, iid *windows.GUID) (*comObject, error) {
+	name, err := windows.UTF16FromString(className)
+	if err != nil {
+		return nil, err
+	}
+	var hstring uintptr
+	hr, _, _ := procWindowsCreateString.Call(uintptr(unsafe.Pointer(&name[0])), uintptr(len(name)-1), uintptr(unsafe.Pointer(&hstring)))
+	if err := hresultError(hr); err != nil {
+		return nil, fmt.Errorf("WindowsCreateString: %w", err)
+	}
+	defer procWindowsDeleteString.Call(hstring)
+	var factory *comObject
+	hr, _, _ = procRoGetActivationFactory.Call(hstring, uintptr(unsafe.Pointer(iid)), uintptr(unsafe.Pointer(&factory)))
+	if err := hresultError(hr); err != nil {
+		return nil, fmt.Errorf("RoGetActivationFactory(%s): %w", className, err)
+	}
+	return factory, nil
+}
+
+// waitForFrame はフレームプールに最初のフレームが届くまでポーリングする。
+func waitForFrame(ctx context.Context, framePool *comObject) (*comObject, error) {
+	deadline := time.Now().Add(builtinWGCFrameTimeout)
+	for {
+		var frame *comObject
+		if err := framePool.call(vtblFramePoolTryGetNextFrame, uintptr(unsafe.Pointer(&frame))); err != nil {
+			return nil, fmt.Errorf("TryGetNextFrame: %w", err)
+		}
+		if frame != nil {
+			return frame, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("WGC のフレームが %s 以内に届きませんでした", builtinWGCFrameTimeout)
+		}
+		if err := waitContext(ctx, builtinWGCPollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readFramePixels はフレームのテクスチャを CPU から読めるステージングテクスチャに写し、内容の範囲を画像にする。
+func readFramePixels(device, deviceContext, frame *comObject) (*image.RGBA, error) {
+	var contentSize sizeInt32
+	if err := frame.call(vtblCaptureFrameGetContentSize, uintptr(unsafe.Pointer(&contentSize))); err != nil {
+		return nil, fmt.Errorf("ContentSize: %w", err)
+	}
+	var surface *comObject
+	if err := frame.call(vtblCaptureFrameGetSurface, uintptr(unsafe.Pointer(&surface))); err != nil {
+		return nil, fmt.Errorf("Surface: %w", err)
+	}
+	defer surface.release()
+	access, err := surface.queryInterface(&iidIDirect3DDxgiInterfaceAccess)
+	if err != nil {
+		return nil, fmt.Errorf("IDirect3DDxgiInterfaceAccess: %w", err)
+	}
+	defer access.release()
+	var texture *comObject
+	if err := access.call(vtblDxgiInterfaceAccessGetInterface, uintptr(unsafe.Pointer(&iidID3D11Texture2D)), uintptr(unsafe.Pointer(&texture))); err != nil {
+		return nil, fmt.Errorf("ID3D11Texture2D: %w", err)
+	}
+	defer texture.release()
+
+	var desc d3d11Texture2DDesc
+	syscall.SyscallN(texture.vtbl[vtblTexture2DGetDesc], uintptr(unsafe.Pointer(texture)), uintptr(unsafe.Pointer(&desc)))
+	staging := desc
+	staging.MipLevels, staging.ArraySize = 1, 1
+	staging.SampleCount, staging.SampleQuality = 1, 0
+	staging.Usage = d3d11UsageStaging
+	staging.BindFlags = 0
+	staging.CPUAccessFlags = d3d11CPUAccessRead
+	staging.MiscFlags = 0
+	var stagingTexture *comObject
+	if err := device.call(vtblDeviceCreateTexture2D, uintptr(unsafe.Pointer(&staging)), 0, uintptr(unsafe.Pointer(&stagingTexture))); err != nil {
+		return nil, fmt.Errorf("CreateTexture2D: %w", err)
+	}
+	defer stagingTexture.release()
+	syscall.SyscallN(deviceContext.vtbl[vtblContextCopyResource],
+		uintptr(unsafe.Pointer(deviceContext)), uintptr(unsafe.Pointer(stagingTexture)), uintptr(unsafe.Pointer(texture)))
+
+	var mapped d3d11MappedSubresource
+	if err := deviceContext.call(vtblContextMap, uintptr(unsafe.Pointer(stagingTexture)), 0, d3d11MapRead, 0, uintptr(unsafe.Pointer(&mapped))); err != nil {
+		return nil, fmt.Errorf("Map: %w", err)
+	}
+	defer syscall.SyscallN(deviceContext.vtbl[vtblContextUnmap], uintptr(unsafe.Pointer(deviceContext)), uintptr(unsafe.Pointer(stagingTexture)), 0)
+
+	width := min(int(contentSize.Width), int(desc.Width))
+	height := min(int(contentSize.Height), int(desc.Height))
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("WGC のフレームが空です")
+	}
+	// マップしたメモリは Go のポインタにせず、行ごとに RtlMoveMemory で Go 側のバッファへ写す。
+	pixels := make([]byte, width*height*4)
+	rowBytes := uintptr(width * 4)
+	for y := 0; y < height; y++ {
+		procRtlMoveMemory.Call(uintptr(unsafe.Pointer(&pixels[y*width*4])), mapped.Data+uintptr(y)*uintptr(mapped.RowPitch), rowBytes)
+	}
+	return screenPixelsToImage(pixels, width, height), nil
+}
+
+// cropToClientArea は WGC が撮るウィンドウの見た目の枠（DWM の拡張フレーム）からクライアント領域を切り出す。
+// 位置を取得できないときは切り出さずにそのまま返す。
+func cropToClientArea(picture *image.RGBA, hwnd windows.HWND) *image.RGBA {
+	frame, err := windowScreenRect(hwnd, false)
+	if err != nil {
+		return picture
+	}
+	client, err := windowScreenRect(hwnd, true)
+	if err != nil {
+		return picture
+	}
+	region := image.Rect(
+		int(client.Left-frame.Left), int(client.Top-frame.Top),
+		int(client.Right-frame.Left), int(client.Bottom-frame.Top),
+	).Intersect(picture.Bounds())
+	if region.Empty() || region == picture.Bounds() {
+		return picture
+	}
+	cropped := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+	for y := 0; y < region.Dy(); y++ {
+		copy(cropped.Pix[y*cropped.Stride:(y+1)*cropped.Stride], picture.Pix[(region.Min.Y+y)*picture.Stride+region.Min.X*4:])
+	}
+	return cropped
+}
@@ -0,0 +1,95 @@
+// セッションの一括変更・取り込み後に、ゲームの集計値を再計算する処理を提供する。
+package services
+
+import (
+	"context"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// RecalculateGameAggregates はゲームの全セッションから累計プレイ時間と最終プレイ日時を計算し直し、
+// ルート統計と併せて返す。書き込みとルート統計の取得は1トランザクションで行う。
+// 値が変わった場合だけ updatedAt を進め、同期対象の変更として扱わせる。
+// セッションが1件も無い場合、最終プレイ日時は手動で設定された可能性があるため維持する。
+func (service *SessionService) RecalculateGameAggregates(ctx context.Context, gameID string) (domain.GameAggregates, error) {
+	trimmedID, detail, ok := requireNonEmpty(gameID, "gameID")
+	if !ok {
+		service.logger.Warn("ゲームIDが不正です", "detail", detail, "gameId", gameID)
+		return domain.GameAggregates{}, newServiceError("ゲームIDが不正です", detail)
+	}
+
+	var aggregates domain.GameAggregates
+	var notFound bool
+	err := runInTx(ctx, service.withTx, service.repository, func(repository SessionRepository) error {
+		game, err := repository.GetGameByID(ctx, trimmedID)
+		if err != nil {
+			return err
+		}
+		if game == nil {
+			notFound = true
+			return nil
+		}
+		sessions, err := repository.ListPlaySessionsByGame(ctx, trimmedID)
+		if err != nil {
+			return err
+		}
+
+		aggregates = domain.GameAggregates{GameID: trimmedID, SessionCount: len(sessions)}
+		aggregates.TotalPlayTime, aggregates.LastPlayed = aggregateSessions(sessions, game.LastPlayed)
+		aggregates.Changed = aggregates.TotalPlayTime != game.TotalPlayTime || !sameTimePtr(aggregates.LastPlayed, game.LastPlayed)
+		if aggregates.Changed {
+			if err := repository.SetGameAggregates(ctx, trimmedID, aggregates.TotalPlayTime, aggregates.LastPlayed); err != nil {
+				return err
+			}
+			if err := repository.TouchGameUpdatedAt(ctx, trimmedID); err != nil {
+				return err
+			}
+		}
+
+		stats, err := repository.GetRouteStats(ctx, trimmedID)
+		if err != nil {
+			return err
+		}
+		aggregates.RouteStats = stats
+		return nil
+	})
+	if err != nil {
+		service.logger.Error("ゲーム集計の再計算に失敗", "error", err, "gameId", trimmedID)
+		return domain.GameAggregates{}, newServiceError("ゲーム集計の再計算に失敗しました", err.Error())
+	}
+	if notFound {
+		service.logger.Warn("ゲームが見つかりません", "gameId", trimmedID)
+		return domain.GameAggregates{}, newServiceError("ゲームが見つかりません", "指定されたIDが存在しません")
+	}
+	if aggregates.Changed {
+		service.logger.Info("ゲーム集計を再計算", "gameId", trimmedID, "totalPlayTime", aggregates.TotalPlayTime, "sessions", aggregates.SessionCount)
+	}
+	return aggregates, nil
+}
+
+// aggregateSessions はセッションの合計時間と最終プレイ日時（セッション日時の最大値）を返す。
+// 自動記録のセッション日時は終了時刻なので、最大値は最後に遊び終えた日時になる。
+// セッションが無い場合、最終プレイ日時は手動で設定された可能性があるため lastPlayed を返す。
+func aggregateSessions(sessions []domain.PlaySession, lastPlayed *time.Time) (int64, *time.Time) {
+	var total int64
+	var latest *time.Time
+	for _, session := range sessions {
+		total += session.Duration
+		if latest == nil || session.PlayedAt.After(*latest) {
+			playedAt := session.PlayedAt
+			latest = &playedAt
+		}
+	}
+	if latest == nil {
+		return total, lastPlayed
+	}
+	return total, latest
+}
+
+func sameTimePtr(left, right *time.Time) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	return left.Equal(*right)
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,11 +17,25 @@ import (
 type SessionService struct {
 	repository SessionRepository
 	logger     *slog.Logger
+	withTx     TxRunner[SessionRepository]
+	now        func() time.Time
+	// events は手動で追加したセッションを知らせるイベントバス（nil 可）。
+	events *EventBus
 }
 
 // NewSessionService は SessionService を生成する。
 func NewSessionService(repository SessionRepository, logger *slog.Logger) *SessionService {
-	return &SessionService{repository: repository, logger: logger}
+	return &SessionService{repository: repository, logger: logger, now: time.Now}
+}
+
+// SetTxRunner はセッション変更と累計プレイ時間の再計算をまとめるトランザクションを設定する。
+func (service *SessionService) SetTxRunner(runner TxRunner[SessionRepository]) {
+	service.withTx = runner
+}
+
+// SetEventBus は手動で追加したセッションを EventSessionCreated で知らせる先を設定する。
+func (service *SessionService) SetEventBus(bus *EventBus) {
+	service.events = bus
 }
 
 // SessionMutationResult はセッション書き込み後に Wails アダプターが使用するメタデータを表す。
@@ -33,6 +49,9 @@ func (service *SessionService) CreateSession(ctx context.Context, input SessionI
 		service.logger.Warn("セッション入力が不正です", "error", error)
 		return nil, newServiceError("セッション入力が不正です", error.Error())
 	}
+	if error := ensureGameWritable(ctx, service.repository, service.logger, strings.TrimSpace(input.GameID)); error != nil {
+		return nil, error
+	}
 
 	session := domain.PlaySession{
 		GameID:      strings.TrimSpace(input.GameID),
@@ -42,13 +61,21 @@ func (service *SessionService) CreateSession(ctx context.Context, input SessionI
 		RouteID:     input.RouteID,
 	}
 
-	created, error := service.repository.CreatePlaySession(ctx, session)
-	if error != nil {
-		service.logger.Error("セッション作成に失敗", "error", error)
-		return nil, newServiceError("セッション作成に失敗しました", error.Error())
+	var created *domain.PlaySession
+	createErr := runInTx(ctx, service.withTx, service.repository, func(repository SessionRepository) error {
+		var err error
+		created, err = repository.CreatePlaySession(ctx, session)
+		if err != nil || created == nil {
+			return err
+		}
+		return service.afterSessionChange(ctx, repository, created.GameID, &input.PlayedAt)
+	})
+	if createErr != nil {
+		service.logger.Error("セッション作成に失敗", "error", createErr)
+		return nil, newServiceError("セッション作成に失敗しました", createErr.Error())
 	}
 	if created != nil {
-		service.afterSessionChange(ctx, created.GameID, &input.PlayedAt)
+		service.events.Publish(EventSessionCreated, SessionCreatedEvent{Session: *created})
 	}
 	return created, nil
 }
@@ -63,7 +90,18 @@ func (service *SessionService) ListSessionsByGame(ctx context.Context, gameID st
 	return sessions, nil
 }
 
-// DeleteSession はセッションを削除する。
+// ListSessionsByGamePage はゲームIDで1ページ分のセッション（新しい順）と全件数を返す。
+func (service *SessionService) ListSessionsByGamePage(ctx context.Context, gameID string, page domain.PageRequest) (domain.Page[domain.PlaySession], error) {
+	page = page.Normalize()
+	sessions, total, error := service.repository.ListPlaySessionsByGamePage(ctx, strings.TrimSpace(gameID), page)
+	if error != nil {
+		service.logger.Error("セッション取得に失敗", "error", error)
+		return domain.Page[domain.PlaySession]{}, newServiceError("セッション取得に失敗しました", error.Error())
+	}
+	return domain.NewPage(sessions, total, page), nil
+}
+
+// DeleteSession はセッションを削除し、ゲームの累計プレイ時間と最終プレイ日時を残りのセッションから計算し直す。
 func (service *SessionService) DeleteSession(ctx context.Context, sessionID string) (SessionMutationResult, error) {
 	trimmedID, detail, ok := requireNonEmpty(sessionID, "sessionID")
 	if !ok {
@@ -71,22 +109,24 @@ func (service *SessionService) DeleteSession(ctx context.Context, sessionID stri
 		return SessionMutationResult{}, newServiceError("セッションIDが不正です", detail)
 	}
 
-	session, error := service.repository.GetPlaySessionByID(ctx, trimmedID)
-	if error != nil {
-		service.logger.Error("セッション取得に失敗", "error", error)
-		return SessionMutationResult{}, newServiceError("セッション取得に失敗しました", error.Error())
-	}
-	if error := service.repository.DeletePlaySession(ctx, trimmedID); error != nil {
-		service.logger.Error("セッション削除に失敗", "error", error)
-		return SessionMutationResult{}, newServiceError("セッション削除に失敗しました", error.Error())
+	session, err := service.loadWritableSession(ctx, trimmedID)
+	if err != nil {
+		return SessionMutationResult{}, err
 	}
-
-	mutation := SessionMutationResult{}
-	if session != nil {
-		service.afterSessionChange(ctx, session.GameID, nil)
-		mutation.GameID = session.GameID
+	err = runInTx(ctx, service.withTx, service.repository, func(repository SessionRepository) error {
+		if err := repository.DeletePlaySession(ctx, trimmedID); err != nil {
+			return err
+		}
+		if session == nil {
+			return nil
+		}
+		return service.afterSessionRewrite(ctx, repository, session.GameID)
+	})
+	if err != nil {
+		service.logger.Error("セッション削除に失敗", "error", err)
+		return SessionMutationResult{}, newServiceError("セッション削除に失敗しました", err.Error())
 	}
-	return mutation, nil
+	return sessionMutationResult(session), nil
 }
 
 // UpdateSessionRoute はセッションのルートを更新する。
@@ -97,22 +137,55 @@ func (service *SessionService) UpdateSessionRoute(ctx context.Context, sessionID
 		return SessionMutationResult{}, newServiceError("セッションIDが不正です", detail)
 	}
 
-	session, error := service.repository.GetPlaySessionByID(ctx, trimmedID)
-	if error != nil {
-		service.logger.Error("セッション取得に失敗", "error", error)
-		return SessionMutationResult{}, newServiceError("セッション取得に失敗しました", error.Error())
+	session, err := service.loadWritableSession(ctx, trimmedID)
+	if err != nil {
+		return SessionMutationResult{}, err
 	}
-	if error := service.repository.UpdatePlaySessionRoute(ctx, trimmedID, chapterID); error != nil {
-		service.logger.Error("セッションルート更新に失敗", "error", error)
-		return SessionMutationResult{}, newServiceError("セッションルート更新に失敗しました", error.Error())
+	err = service.mutateSession(ctx, session, func(repository SessionRepository) error {
+		return repository.UpdatePlaySessionRoute(ctx, trimmedID, chapterID)
+	})
+	if err != nil {
+		service.logger.Error("セッションルート更新に失敗", "error", err)
+		return SessionMutationResult{}, newServiceError("セッションルート更新に失敗しました", err.Error())
 	}
+	return sessionMutationResult(session), nil
+}
 
-	mutation := SessionMutationResult{}
-	if session != nil {
-		service.afterSessionChange(ctx, session.GameID, nil)
-		mutation.GameID = session.GameID
+// UpdateSession はセッションの開始日時とプレイ時間（秒）を手動で補正する。
+// 自動記録で一時停止したまま放置した時間が含まれた場合などの修正用で、
+// 変更後にゲームの累計プレイ時間と最終プレイ日時をセッションから計算し直すため、
+// 最新のセッションを前の日時へずらした場合は最終プレイ日時も戻る。
+func (service *SessionService) UpdateSession(ctx context.Context, sessionID string, playedAt time.Time, duration int64) (SessionMutationResult, error) {
+	trimmedID, detail, ok := requireNonEmpty(sessionID, "sessionID")
+	if !ok {
+		service.logger.Warn("セッションIDが不正です", "detail", detail, "sessionId", sessionID)
+		return SessionMutationResult{}, newServiceError("セッションIDが不正です", detail)
+	}
+	if error := validateSessionTiming(playedAt, duration, service.now()); error != nil {
+		service.logger.Warn("セッション入力が不正です", "error", error, "sessionId", trimmedID)
+		return SessionMutationResult{}, newServiceError("セッション入力が不正です", error.Error())
+	}
+
+	session, err := service.loadWritableSession(ctx, trimmedID)
+	if err != nil {
+		return SessionMutationResult{}, err
+	}
+	if session == nil {
+		service.logger.Warn("セッションが見つかりません", "sessionId", trimmedID)
+		return SessionMutationResult{}, newServiceError("セッションが見つかりません", "指定されたIDが存在しません")
+	}
+	err = runInTx(ctx, service.withTx, service.repository, func(repository SessionRepository) error {
+		if err := repository.UpdatePlaySessionTiming(ctx, trimmedID, playedAt, duration); err != nil {
+			return err
+		}
+		return service.afterSessionRewrite(ctx, repository, session.GameID)
+	})
+	if err != nil {
+		service.logger.Error("セッション更新に失敗", "error", err)
+		return SessionMutationResult{}, newServiceError("セッション更新に失敗しました", err.Error())
 	}
-	return mutation, nil
+	service.logger.Info("セッションを補正", "sessionId", trimmedID, "gameId", session.GameID, "duration", duration, "previousDuration", session.Duration)
+	return sessionMutationResult(session), nil
 }
 
 // UpdateSessionName はセッション名を更新する。
@@ -126,44 +199,198 @@ func (service *SessionService) UpdateSessionName(ctx context.Context, sessionID
 	}
 	trimmedName := strings.TrimSpace(sessionName)
 
-	session, error := service.repository.GetPlaySessionByID(ctx, trimmedID)
+	session, err := service.loadWritableSession(ctx, trimmedID)
+	if err != nil {
+		return SessionMutationResult{}, err
+	}
+	err = service.mutateSession(ctx, session, func(repository SessionRepository) error {
+		return repository.UpdatePlaySessionName(ctx, trimmedID, trimmedName)
+	})
+	if err != nil {
+		service.logger.Error("セッション名更新に失敗", "error", err)
+		return SessionMutationResult{}, newServiceError("セッション名更新に失敗しました", err.Error())
+	}
+	return sessionMutationResult(session), nil
+}
+
+// UpdateSessionNotes はセッションのメモを更新する。
+// 前後の空白だけを取り除き、改行などの本文は保持する。空なら未記入（NULL）に戻す。
+func (service *SessionService) UpdateSessionNotes(ctx context.Context, sessionID string, notes string) (SessionMutationResult, error) {
+	trimmedID, detail, ok := requireNonEmpty(sessionID, "sessionID")
+	if !ok {
+		service.logger.Warn("セッションIDが不正です", "detail", detail, "sessionId", sessionID)
+		return SessionMutationResult{}, newServiceError("セッションIDが不正です", detail)
+	}
+	trimmedNotes := strings.TrimSpace(notes)
+
+	session, err := service.loadWritableSession(ctx, trimmedID)
+	if err != nil {
+		return SessionMutationResult{}, err
+	}
+	err = service.mutateSession(ctx, session, func(repository SessionRepository) error {
+		return repository.UpdatePlaySessionNotes(ctx, trimmedID, trimmedNotes)
+	})
+	if err != nil {
+		service.logger.Error("セッションメモ更新に失敗", "error", err)
+		return SessionMutationResult{}, newServiceError("セッションメモ更新に失敗しました", err.Error())
+	}
+	return sessionMutationResult(session), nil
+}
+
+// loadWritableSession は変更対象のセッションを取得し、ゲームがアーカイブ済みでないことを確認する。
+// セッションが見つからない場合は nil を返す。
+func (service *SessionService) loadWritableSession(ctx context.Context, sessionID string) (*domain.PlaySession, error) {
+	session, error := service.repository.GetPlaySessionByID(ctx, sessionID)
 	if error != nil {
 		service.logger.Error("セッション取得に失敗", "error", error)
-		return SessionMutationResult{}, newServiceError("セッション取得に失敗しました", error.Error())
+		return nil, newServiceError("セッション取得に失敗しました", error.Error())
 	}
-	if error := service.repository.UpdatePlaySessionName(ctx, trimmedID, trimmedName); error != nil {
-		service.logger.Error("セッション名更新に失敗", "error", error)
-		return SessionMutationResult{}, newServiceError("セッション名更新に失敗しました", error.Error())
+	if session != nil {
+		if error := ensureGameWritable(ctx, service.repository, service.logger, session.GameID); error != nil {
+			return nil, error
+		}
 	}
+	return session, nil
+}
 
-	mutation := SessionMutationResult{}
-	if session != nil {
-		service.afterSessionChange(ctx, session.GameID, nil)
-		mutation.GameID = session.GameID
+// mutateSession は既存セッションへの変更 mutate と累計プレイ時間の再計算を1トランザクションで行う。
+// session が nil（対象が既に無い）の場合は再計算せず mutate だけを実行する。
+func (service *SessionService) mutateSession(ctx context.Context, session *domain.PlaySession, mutate func(SessionRepository) error) error {
+	return runInTx(ctx, service.withTx, service.repository, func(repository SessionRepository) error {
+		if err := mutate(repository); err != nil {
+			return err
+		}
+		if session == nil {
+			return nil
+		}
+		return service.afterSessionChange(ctx, repository, session.GameID, nil)
+	})
+}
+
+func sessionMutationResult(session *domain.PlaySession) SessionMutationResult {
+	if session == nil {
+		return SessionMutationResult{}
 	}
-	return mutation, nil
+	return SessionMutationResult{GameID: session.GameID}
 }
 
-func (service *SessionService) afterSessionChange(ctx context.Context, gameID string, playedAt *time.Time) {
-	_ = service.repository.TouchGameUpdatedAt(ctx, gameID)
-	service.recalculateTotalPlayTime(ctx, gameID, playedAt)
+// afterSessionChange はゲームの更新日時と累計プレイ時間を更新する。
+// 失敗時はセッション変更ごとロールバックさせるため、エラーを返す。
+func (service *SessionService) afterSessionChange(ctx context.Context, repository SessionRepository, gameID string, playedAt *time.Time) error {
+	if err := repository.TouchGameUpdatedAt(ctx, gameID); err != nil {
+		return err
+	}
+	return service.recalculateTotalPlayTime(ctx, repository, gameID, playedAt)
 }
 
-func (service *SessionService) recalculateTotalPlayTime(ctx context.Context, gameID string, playedAt *time.Time) {
-	total, sumErr := service.repository.SumPlaySessionDurationsByGame(ctx, gameID)
+// afterSessionRewrite は既存セッションの日時変更・削除の後に、ゲームの更新日時を進め、
+// 累計プレイ時間と最終プレイ日時をセッションから計算し直す。最終プレイ日時は過去へ戻ることもある。
+func (service *SessionService) afterSessionRewrite(ctx context.Context, repository SessionRepository, gameID string) error {
+	if err := repository.TouchGameUpdatedAt(ctx, gameID); err != nil {
+		return err
+	}
+	game, err := repository.GetGameByID(ctx, gameID)
+	if err != nil || game == nil {
+		return err
+	}
+	sessions, err := repository.ListPlaySessionsByGame(ctx, gameID)
+	if err != nil {
+		service.logger.Error("セッション取得に失敗", "error", err, "gameId", gameID)
+		return err
+	}
+	total, lastPlayed := aggregateSessions(sessions, game.LastPlayed)
+	if err := repository.SetGameAggregates(ctx, gameID, total, lastPlayed); err != nil {
+		service.logger.Error("プレイ時間更新に失敗", "error", err, "gameId", gameID)
+		return err
+	}
+	return nil
+}
+
+func (service *SessionService) recalculateTotalPlayTime(ctx context.Context, repository SessionRepository, gameID string, playedAt *time.Time) error {
+	total, sumErr := repository.SumPlaySessionDurationsByGame(ctx, gameID)
 	if sumErr != nil {
 		service.logger.Error("セッション合計時間の取得に失敗", "error", sumErr, "gameId", gameID)
-		return
+		return sumErr
 	}
+	var err error
 	if playedAt != nil {
-		if err := service.repository.UpdateGameTotalPlayTimeWithLastPlayed(ctx, gameID, total, *playedAt); err != nil {
-			service.logger.Error("プレイ時間更新に失敗", "error", err, "gameId", gameID)
-		}
-		return
+		err = repository.UpdateGameTotalPlayTimeWithLastPlayed(ctx, gameID, total, *playedAt)
+	} else {
+		err = repository.UpdateGameTotalPlayTime(ctx, gameID, total)
 	}
-	if err := service.repository.UpdateGameTotalPlayTime(ctx, gameID, total); err != nil {
+	if err != nil {
 		service.logger.Error("プレイ時間更新に失敗", "error", err, "gameId", gameID)
 	}
+	return err
+}
+
+// GetPlayCalendar は year 年のローカル日付ごとのプレイ時間（分）を返す。
+// includeGames が true なら日ごとのゲーム別内訳も含める。集計は1時間単位のグループ化クエリ1回で行い、
+// 0.5時間単位などのタイムゾーンで記録された時間帯だけは日付の境界付近が前後の日に寄ることがある。
+// 分は切り上げで、少しでも遊んだ日は1分以上になる。
+func (service *SessionService) GetPlayCalendar(ctx context.Context, year int, includeGames bool) (domain.PlayCalendar, error) {
+	if year < 1970 || year > 9999 {
+		service.logger.Warn("年の指定が不正です", "year", year)
+		return domain.PlayCalendar{}, newServiceError("年の指定が不正です", "yearが範囲外です")
+	}
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(1, 0, 0)
+	buckets, err := service.repository.ListPlayTimeBuckets(ctx, start.AddDate(0, 0, -1), end.AddDate(0, 0, 1))
+	if err != nil {
+		service.logger.Error("プレイカレンダーの集計に失敗", "error", err)
+		return domain.PlayCalendar{}, newServiceError("プレイカレンダーの取得に失敗しました", err.Error())
+	}
+
+	secondsByDay := map[string]int64{}
+	secondsByDayGame := map[string]map[string]int64{}
+	for _, bucket := range buckets {
+		local := bucket.Start.In(time.Local)
+		if local.Before(start) || !local.Before(end) {
+			continue
+		}
+		day := local.Format(time.DateOnly)
+		secondsByDay[day] += bucket.Duration
+		if includeGames {
+			if secondsByDayGame[day] == nil {
+				secondsByDayGame[day] = map[string]int64{}
+			}
+			secondsByDayGame[day][bucket.GameID] += bucket.Duration
+		}
+	}
+
+	days := make([]string, 0, len(secondsByDay))
+	for day := range secondsByDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	calendar := domain.PlayCalendar{Year: year, Days: make([]domain.PlayCalendarDay, 0, len(days))}
+	for _, day := range days {
+		entry := domain.PlayCalendarDay{Date: day, Minutes: secondsToCeilMinutes(secondsByDay[day])}
+		if includeGames {
+			for gameID, seconds := range secondsByDayGame[day] {
+				entry.Games = append(entry.Games, domain.PlayCalendarGameDay{GameID: gameID, Minutes: secondsToCeilMinutes(seconds)})
+			}
+			sort.Slice(entry.Games, func(i, j int) bool {
+				if entry.Games[i].Minutes != entry.Games[j].Minutes {
+					return entry.Games[i].Minutes > entry.Games[j].Minutes
+				}
+				return entry.Games[i].GameID < entry.Games[j].GameID
+			})
+		}
+		calendar.Days = append(calendar.Days, entry)
+		calendar.TotalMinutes += entry.Minutes
+		if entry.Minutes > calendar.MaxMinutes {
+			calendar.MaxMinutes = entry.Minutes
+		}
+	}
+	return calendar, nil
+}
+
+func secondsToCeilMinutes(seconds int64) int64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return (seconds + 59) / 60
 }
 
 // SessionInput はセッション作成入力を表す。
@@ -175,6 +402,24 @@ type SessionInput struct {
 	RouteID     *string
 }
 
+// sessionClockSkew は未来の日時を拒否するときに許容する端末間の時計のずれ。
+const sessionClockSkew = 5 * time.Minute
+
+// validateSessionTiming はセッションの開始日時とプレイ時間を検証する。
+// 終了（開始 + プレイ時間）が未来になる値は記録として成り立たないため拒否する。
+func validateSessionTiming(playedAt time.Time, duration int64, now time.Time) error {
+	if playedAt.IsZero() {
+		return errors.New("playedAtが空です")
+	}
+	if duration < 0 || duration > math.MaxInt64/int64(time.Second) {
+		return errors.New("durationが不正です")
+	}
+	if playedAt.Add(time.Duration(duration) * time.Second).After(now.Add(sessionClockSkew)) {
+		return errors.New("終了日時が未来になっています")
+	}
+	return nil
+}
+
 // validateSessionInput はセッション入力を検証する。
 func validateSessionInput(input SessionInput) error {
 	if _, detail, ok := requireNonEmpty(input.GameID, "gameID"); !ok {
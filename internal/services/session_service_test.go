@@ -13,10 +13,14 @@ import (
 
 type fakeSessionRepository struct {
 	session               *domain.PlaySession
+	buckets               []domain.PlayTimeBucket
 	totalDuration         int64
 	touchedGameID         string
 	updatedWithLastPlayed *time.Time
 	updateTotalCalls      int
+	game                  *domain.Game
+	sessions              []domain.PlaySession
+	aggregatesCalls       int
 }
 
 func (repository *fakeSessionRepository) CreatePlaySession(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
@@ -26,17 +30,39 @@ func (repository *fakeSessionRepository) CreatePlaySession(ctx context.Context,
 }
 
 func (repository *fakeSessionRepository) ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error) {
+	if repository.sessions != nil {
+		return repository.sessions, nil
+	}
 	if repository.session == nil {
 		return nil, nil
 	}
 	return []domain.PlaySession{*repository.session}, nil
 }
 
+func (repository *fakeSessionRepository) ListPlaySessionsByGamePage(ctx context.Context, gameID string, page domain.PageRequest) ([]domain.PlaySession, int, error) {
+	sessions, _ := repository.ListPlaySessionsByGame(ctx, gameID)
+	if page.Offset >= len(sessions) {
+		return nil, len(sessions), nil
+	}
+	return sessions[page.Offset:], len(sessions), nil
+}
+
 func (repository *fakeSessionRepository) GetPlaySessionByID(ctx context.Context, sessionID string) (*domain.PlaySession, error) {
 	return repository.session, nil
 }
 
+func (repository *fakeSessionRepository) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
+	return repository.game, nil
+}
+
 func (repository *fakeSessionRepository) DeletePlaySession(ctx context.Context, sessionID string) error {
+	remaining := make([]domain.PlaySession, 0, len(repository.sessions))
+	for _, session := range repository.sessions {
+		if session.ID != sessionID {
+			remaining = append(remaining, session)
+		}
+	}
+	repository.sessions = remaining
 	return nil
 }
 
@@ -54,11 +80,31 @@ func (repository *fakeSessionRepository) UpdatePlaySessionName(ctx context.Conte
 	return nil
 }
 
+func (repository *fakeSessionRepository) UpdatePlaySessionTiming(ctx context.Context, sessionID string, playedAt time.Time, duration int64) error {
+	if repository.session != nil {
+		repository.session.PlayedAt = playedAt
+		repository.session.Duration = duration
+		repository.session.Partial = false
+	}
+	return nil
+}
+
+func (repository *fakeSessionRepository) UpdatePlaySessionNotes(ctx context.Context, sessionID string, notes string) error {
+	if repository.session != nil {
+		repository.session.Notes = &notes
+	}
+	return nil
+}
+
 func (repository *fakeSessionRepository) TouchGameUpdatedAt(ctx context.Context, gameID string) error {
 	repository.touchedGameID = gameID
 	return nil
 }
 
+func (repository *fakeSessionRepository) ListPlayTimeBuckets(ctx context.Context, from, to time.Time) ([]domain.PlayTimeBucket, error) {
+	return repository.buckets, nil
+}
+
 func (repository *fakeSessionRepository) SumPlaySessionDurationsByGame(ctx context.Context, gameID string) (int64, error) {
 	if repository.totalDuration != 0 {
 		return repository.totalDuration, nil
@@ -81,16 +127,36 @@ func (repository *fakeSessionRepository) UpdateGameTotalPlayTimeWithLastPlayed(c
 	return nil
 }
 
+func (repository *fakeSessionRepository) SetGameAggregates(ctx context.Context, gameID string, totalPlayTime int64, lastPlayed *time.Time) error {
+	repository.aggregatesCalls++
+	if repository.game != nil {
+		repository.game.TotalPlayTime = totalPlayTime
+		repository.game.LastPlayed = lastPlayed
+	}
+	return nil
+}
+
+func (repository *fakeSessionRepository) GetRouteStats(ctx context.Context, gameID string) ([]domain.RouteStat, error) {
+	return []domain.RouteStat{}, nil
+}
+
 func TestSessionServiceDeleteSessionReturnsGameIDForAdapterUse(t *testing.T) {
 	t.Parallel()
 
+	older := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	latest := older.Add(24 * time.Hour)
 	repository := &fakeSessionRepository{
 		session: &domain.PlaySession{
 			ID:       "session-1",
 			GameID:   "game-1",
-			PlayedAt: time.Now(),
+			PlayedAt: latest,
 			Duration: 120,
 		},
+		sessions: []domain.PlaySession{
+			{ID: "session-1", GameID: "game-1", PlayedAt: latest, Duration: 120},
+			{ID: "session-0", GameID: "game-1", PlayedAt: older, Duration: 60},
+		},
+		game: &domain.Game{ID: "game-1", TotalPlayTime: 180, LastPlayed: &latest},
 	}
 	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
 
@@ -104,8 +170,10 @@ func TestSessionServiceDeleteSessionReturnsGameIDForAdapterUse(t *testing.T) {
 	if repository.touchedGameID != "game-1" {
 		t.Fatalf("expected touch updated at to be called")
 	}
-	if repository.updateTotalCalls != 1 {
-		t.Fatalf("expected total play time recalculation without playedAt")
+	// 最新のセッションを消したら、最終プレイ日時は残ったセッションまで戻る。
+	if repository.aggregatesCalls != 1 || repository.game.TotalPlayTime != 60 ||
+		repository.game.LastPlayed == nil || !repository.game.LastPlayed.Equal(older) {
+		t.Fatalf("expected aggregates to be recalculated from the remaining session, got %+v", repository.game)
 	}
 }
 
@@ -156,6 +224,27 @@ func TestSessionServiceCreateSessionRecalculatesTotalWithLastPlayed(t *testing.T
 	}
 }
 
+func TestSessionServiceCreateSessionRunsRecalculationInsideTransaction(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeSessionRepository{}
+	txRepository := &fakeSessionRepository{}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetTxRunner(func(ctx context.Context, fn func(SessionRepository) error) error {
+		return fn(txRepository)
+	})
+
+	if _, err := service.CreateSession(context.Background(), SessionInput{GameID: "game-1", PlayedAt: time.Now(), Duration: 300}); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if repository.session != nil || txRepository.session == nil {
+		t.Fatal("expected session to be created through the transaction")
+	}
+	if txRepository.touchedGameID != "game-1" || txRepository.totalDuration != 300 {
+		t.Fatalf("expected totals to be recalculated in the same transaction, got %#v", txRepository)
+	}
+}
+
 func TestSessionServiceUpdateSessionNameAllowsEmptyToClear(t *testing.T) {
 	t.Parallel()
 
@@ -203,6 +292,98 @@ func TestSessionServiceUpdateSessionNameTrimsNameAndRecalculatesTotal(t *testing
 	}
 }
 
+func TestSessionServiceUpdateSessionCorrectsDurationAndRecalculatesTotal(t *testing.T) {
+	t.Parallel()
+
+	playedAt := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)
+	recorded := playedAt.Add(10 * time.Hour)
+	repository := &fakeSessionRepository{
+		session: &domain.PlaySession{ID: "session-1", GameID: "game-1", PlayedAt: recorded, Duration: 10 * 3600, Partial: true},
+		game:    &domain.Game{ID: "game-1", TotalPlayTime: 10 * 3600, LastPlayed: &recorded},
+	}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.now = func() time.Time { return time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) }
+
+	result, err := service.UpdateSession(context.Background(), " session-1 ", playedAt, 5400)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if result.GameID != "game-1" {
+		t.Fatalf("expected affected game id to be returned")
+	}
+	if repository.session.Duration != 5400 || repository.session.Partial {
+		t.Fatalf("expected corrected duration without partial flag, got %+v", repository.session)
+	}
+	if repository.game.TotalPlayTime != 5400 || repository.touchedGameID != "game-1" {
+		t.Fatalf("expected total play time to be recalculated, total=%d", repository.game.TotalPlayTime)
+	}
+	// 唯一のセッションを前へずらしたので、最終プレイ日時も戻る。
+	if repository.game.LastPlayed == nil || !repository.game.LastPlayed.Equal(playedAt) {
+		t.Fatalf("expected last played to move back to the corrected session, got %v", repository.game.LastPlayed)
+	}
+}
+
+func TestSessionServiceUpdateSessionRejectsInvalidTiming(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	repository := &fakeSessionRepository{
+		session: &domain.PlaySession{ID: "session-1", GameID: "game-1", PlayedAt: now.Add(-time.Hour), Duration: 60},
+	}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.now = func() time.Time { return now }
+
+	cases := []struct {
+		name     string
+		playedAt time.Time
+		duration int64
+	}{
+		{name: "zero playedAt", playedAt: time.Time{}, duration: 60},
+		{name: "negative duration", playedAt: now.Add(-time.Hour), duration: -1},
+		{name: "ends in the future", playedAt: now.Add(-time.Hour), duration: 2 * 3600},
+	}
+	for _, tc := range cases {
+		if _, err := service.UpdateSession(context.Background(), "session-1", tc.playedAt, tc.duration); err == nil {
+			t.Fatalf("%s: expected validation error", tc.name)
+		}
+	}
+	if repository.session.Duration != 60 || repository.aggregatesCalls != 0 {
+		t.Fatalf("expected session to stay unchanged, got %+v", repository.session)
+	}
+
+	repository.session = nil
+	if _, err := service.UpdateSession(context.Background(), "missing", now.Add(-time.Hour), 60); err == nil {
+		t.Fatal("expected missing session to be rejected")
+	}
+}
+
+func TestSessionServiceUpdateSessionNotesKeepsLineBreaks(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeSessionRepository{
+		session: &domain.PlaySession{ID: "session-1", GameID: "game-1", Duration: 60},
+	}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result, err := service.UpdateSessionNotes(context.Background(), "session-1", "\n 共通ルート終了\n次はヒロインA \n")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if result.GameID != "game-1" {
+		t.Fatalf("expected affected game id to be returned")
+	}
+	if repository.session.Notes == nil || *repository.session.Notes != "共通ルート終了\n次はヒロインA" {
+		t.Fatalf("expected notes to be trimmed with inner line breaks kept, got %v", repository.session.Notes)
+	}
+	if repository.touchedGameID != "game-1" {
+		t.Fatalf("expected game updated timestamp to be touched")
+	}
+
+	if _, err := service.UpdateSessionNotes(context.Background(), " ", "memo"); err == nil {
+		t.Fatal("expected empty session id to be rejected")
+	}
+}
+
 func TestSessionServiceUpdateSessionRouteStoresRouteAndRecalculatesTotal(t *testing.T) {
 	t.Parallel()
 
@@ -249,9 +430,16 @@ func (repository *fakeSessionRepositoryWithError) CreatePlaySession(ctx context.
 func (repository *fakeSessionRepositoryWithError) ListPlaySessionsByGame(ctx context.Context, gameID string) ([]domain.PlaySession, error) {
 	return nil, nil
 }
+func (repository *fakeSessionRepositoryWithError) ListPlaySessionsByGamePage(ctx context.Context, gameID string, page domain.PageRequest) ([]domain.PlaySession, int, error) {
+	return nil, 0, nil
+}
 func (repository *fakeSessionRepositoryWithError) GetPlaySessionByID(ctx context.Context, sessionID string) (*domain.PlaySession, error) {
 	return nil, repository.getErr
 }
+
+func (repository *fakeSessionRepositoryWithError) GetGameByID(ctx context.Context, gameID string) (*domain.Game, error) {
+	return nil, nil
+}
 func (repository *fakeSessionRepositoryWithError) DeletePlaySession(ctx context.Context, sessionID string) error {
 	return nil
 }
@@ -261,9 +449,18 @@ func (repository *fakeSessionRepositoryWithError) UpdatePlaySessionRoute(ctx con
 func (repository *fakeSessionRepositoryWithError) UpdatePlaySessionName(ctx context.Context, sessionID string, sessionName string) error {
 	return nil
 }
+func (repository *fakeSessionRepositoryWithError) UpdatePlaySessionTiming(ctx context.Context, sessionID string, playedAt time.Time, duration int64) error {
+	return nil
+}
+func (repository *fakeSessionRepositoryWithError) UpdatePlaySessionNotes(ctx context.Context, sessionID string, notes string) error {
+	return nil
+}
 func (repository *fakeSessionRepositoryWithError) TouchGameUpdatedAt(ctx context.Context, gameID string) error {
 	return nil
 }
+func (repository *fakeSessionRepositoryWithError) ListPlayTimeBuckets(ctx context.Context, from, to time.Time) ([]domain.PlayTimeBucket, error) {
+	return nil, nil
+}
 func (repository *fakeSessionRepositoryWithError) SumPlaySessionDurationsByGame(ctx context.Context, gameID string) (int64, error) {
 	return 0, nil
 }
@@ -273,3 +470,118 @@ func (repository *fakeSessionRepositoryWithError) UpdateGameTotalPlayTime(ctx co
 func (repository *fakeSessionRepositoryWithError) UpdateGameTotalPlayTimeWithLastPlayed(ctx context.Context, gameID string, totalPlayTime int64, playedAt time.Time) error {
 	return nil
 }
+
+func (repository *fakeSessionRepositoryWithError) SetGameAggregates(ctx context.Context, gameID string, totalPlayTime int64, lastPlayed *time.Time) error {
+	return nil
+}
+
+func (repository *fakeSessionRepositoryWithError) GetRouteStats(ctx context.Context, gameID string) ([]domain.RouteStat, error) {
+	return nil, nil
+}
+
+func TestSessionServiceGetPlayCalendarGroupsByLocalDay(t *testing.T) {
+	t.Parallel()
+
+	local := time.Local
+	repository := &fakeSessionRepository{buckets: []domain.PlayTimeBucket{
+		{GameID: "game-a", Start: time.Date(2024, 3, 1, 10, 0, 0, 0, local), Duration: 1800},
+		{GameID: "game-b", Start: time.Date(2024, 3, 1, 22, 0, 0, 0, local), Duration: 3600},
+		{GameID: "game-a", Start: time.Date(2024, 3, 2, 0, 0, 0, 0, local), Duration: 10},
+		// 範囲の余裕分として渡される前年・翌年のバケットは除外される。
+		{GameID: "game-a", Start: time.Date(2023, 12, 31, 23, 0, 0, 0, local), Duration: 600},
+		{GameID: "game-a", Start: time.Date(2025, 1, 1, 0, 0, 0, 0, local), Duration: 600},
+	}}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	calendar, err := service.GetPlayCalendar(context.Background(), 2024, true)
+	if err != nil {
+		t.Fatalf("GetPlayCalendar: %v", err)
+	}
+	if len(calendar.Days) != 2 || calendar.Days[0].Date != "2024-03-01" || calendar.Days[1].Date != "2024-03-02" {
+		t.Fatalf("unexpected days: %#v", calendar.Days)
+	}
+	first := calendar.Days[0]
+	if first.Minutes != 90 || len(first.Games) != 2 || first.Games[0].GameID != "game-b" {
+		t.Fatalf("unexpected first day: %#v", first)
+	}
+	if calendar.Days[1].Minutes != 1 {
+		t.Fatalf("expected short play to round up to 1 minute, got %d", calendar.Days[1].Minutes)
+	}
+	if calendar.TotalMinutes != 91 || calendar.MaxMinutes != 90 {
+		t.Fatalf("unexpected totals: %#v", calendar)
+	}
+
+	withoutGames, err := service.GetPlayCalendar(context.Background(), 2024, false)
+	if err != nil || withoutGames.Days[0].Games != nil {
+		t.Fatalf("expected no per-game breakdown, got %#v (%v)", withoutGames.Days[0], err)
+	}
+	if _, err := service.GetPlayCalendar(context.Background(), 0, false); err == nil {
+		t.Fatal("expected invalid year to fail")
+	}
+}
+
+func TestSessionServiceRecalculateGameAggregatesRewritesTotals(t *testing.T) {
+	t.Parallel()
+
+	stale := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	earlier := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	repository := &fakeSessionRepository{
+		game: &domain.Game{ID: "game-1", TotalPlayTime: 999, LastPlayed: &stale},
+		sessions: []domain.PlaySession{
+			{ID: "s1", GameID: "game-1", PlayedAt: earlier, Duration: 60},
+			{ID: "s2", GameID: "game-1", PlayedAt: later, Duration: 30},
+		},
+	}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	aggregates, err := service.RecalculateGameAggregates(context.Background(), " game-1 ")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !aggregates.Changed || aggregates.TotalPlayTime != 90 || aggregates.SessionCount != 2 {
+		t.Fatalf("unexpected aggregates: %#v", aggregates)
+	}
+	if aggregates.LastPlayed == nil || !aggregates.LastPlayed.Equal(later) {
+		t.Fatalf("expected lastPlayed to move back to latest session, got %v", aggregates.LastPlayed)
+	}
+	if repository.aggregatesCalls != 1 || repository.touchedGameID != "game-1" {
+		t.Fatalf("expected aggregates write and touch, got calls=%d touched=%q", repository.aggregatesCalls, repository.touchedGameID)
+	}
+
+	again, err := service.RecalculateGameAggregates(context.Background(), "game-1")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if again.Changed || repository.aggregatesCalls != 1 {
+		t.Fatalf("expected no write when already consistent, got %#v calls=%d", again, repository.aggregatesCalls)
+	}
+}
+
+func TestSessionServiceRecalculateGameAggregatesKeepsLastPlayedWithoutSessions(t *testing.T) {
+	t.Parallel()
+
+	manual := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repository := &fakeSessionRepository{
+		game:     &domain.Game{ID: "game-1", TotalPlayTime: 120, LastPlayed: &manual},
+		sessions: []domain.PlaySession{},
+	}
+	service := NewSessionService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	aggregates, err := service.RecalculateGameAggregates(context.Background(), "game-1")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if aggregates.TotalPlayTime != 0 || aggregates.LastPlayed == nil || !aggregates.LastPlayed.Equal(manual) {
+		t.Fatalf("unexpected aggregates: %#v", aggregates)
+	}
+}
+
+func TestSessionServiceRecalculateGameAggregatesMissingGame(t *testing.T) {
+	t.Parallel()
+
+	service := NewSessionService(&fakeSessionRepository{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, err := service.RecalculateGameAggregates(context.Background(), "missing"); err == nil {
+		t.Fatal("expected error for missing game")
+	}
+}
@@ -0,0 +1,183 @@
+// DB 保存に失敗したプレイセッションをディスクへ退避し、後から再試行する仕組みを提供する。
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// SessionSpoolDirName は退避セッションを置く appData 配下のディレクトリ名。
+	SessionSpoolDirName = "session_spool"
+	// sessionRetryInterval は退避セッションを再試行する間隔。
+	sessionRetryInterval = time.Minute
+	// sessionRetryTimeout は1回の再試行（退避セッションの保存し直し）全体の上限。
+	sessionRetryTimeout = time.Minute
+	sessionSpoolFileExt = ".json"
+)
+
+// spooledSession は保存待ちのプレイセッション。退避ファイルの JSON 形式を兼ねる。
+type spooledSession struct {
+	GameID    string    `json:"gameId"`
+	ExeName   string    `json:"exeName"`
+	EndedAt   time.Time `json:"endedAt"`
+	Duration  int64     `json:"duration"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+	Partial   bool      `json:"partial,omitempty"`
+	// IdleTime は無操作のため計測を止めていた時間（秒）。
+	IdleTime int64 `json:"idleTime,omitempty"`
+	// CPU* / Memory* はセッション中に採ったプロセスの使用量の最大値と平均値。
+	CPUPeakPercent     float64 `json:"cpuPeakPercent,omitempty"`
+	CPUAveragePercent  float64 `json:"cpuAveragePercent,omitempty"`
+	MemoryPeakBytes    int64   `json:"memoryPeakBytes,omitempty"`
+	MemoryAverageBytes int64   `json:"memoryAverageBytes,omitempty"`
+	// DayMarks は日付をまたいだセッションを 0:00 で分けて保存するときの区切り（分けないときは空）。
+	DayMarks []dayMark `json:"dayMarks,omitempty"`
+}
+
+// SetSessionSpoolDir は保存失敗時のセッション退避先ディレクトリを設定する。
+// 未設定（空文字）の場合は退避せず、従来どおりログに残すだけになる。
+func (service *ProcessMonitorService) SetSessionSpoolDir(dir string) {
+	service.spoolMu.Lock()
+	defer service.spoolMu.Unlock()
+	service.spoolDir = dir
+}
+
+// spoolSession は pending を退避ディレクトリへ書き出す。
+func (service *ProcessMonitorService) spoolSession(pending spooledSession) {
+	service.spoolMu.Lock()
+	defer service.spoolMu.Unlock()
+	if service.spoolDir == "" {
+		service.logger.Error("セッション退避先が未設定のためプレイ時間を保存できません", "gameId", pending.GameID, "duration", pending.Duration)
+		return
+	}
+	path, err := writeSpooledSession(service.spoolDir, sessionSpoolFileName(pending), pending)
+	if err != nil {
+		service.logger.Error("プレイセッションの退避に失敗", "gameId", pending.GameID, "duration", pending.Duration, "error", err)
+		return
+	}
+	service.logger.Warn("プレイセッションを退避しました（後で再試行します）", "gameId", pending.GameID, "path", path)
+}
+
+// retrySpooledSessionsIfDue は前回の再試行から sessionRetryInterval 経過していれば再試行する。
+// 起動直後の初回呼び出しでは必ず実行される。
+func (service *ProcessMonitorService) retrySpooledSessionsIfDue(now time.Time) {
+	service.spoolMu.Lock()
+	due := service.spoolDir != "" && (service.lastSpoolRetry.IsZero() || now.Sub(service.lastSpoolRetry) >= sessionRetryInterval)
+	if due {
+		service.lastSpoolRetry = now
+	}
+	service.spoolMu.Unlock()
+	if due {
+		ctx, cancel := context.WithTimeout(service.context(), sessionRetryTimeout)
+		defer cancel()
+		service.RetrySpooledSessions(ctx)
+	}
+}
+
+// RetrySpooledSessions は退避済みセッションを古い順に保存し直し、保存できた件数を返す。
+// 保存に失敗した時点で DB がまだ使えないとみなして今回の再試行を打ち切る。
+// 対象ゲームが削除済みのセッションは保存先が無いため破棄する。
+func (service *ProcessMonitorService) RetrySpooledSessions(ctx context.Context) int {
+	service.spoolMu.Lock()
+	defer service.spoolMu.Unlock()
+	if service.spoolDir == "" {
+		return 0
+	}
+	entries, err := os.ReadDir(service.spoolDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			service.logger.Warn("退避セッション一覧の取得に失敗", "error", err)
+		}
+		return 0
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), sessionSpoolFileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	// ファイル名は終了日時のナノ秒で始まるため、名前順が古い順になる。
+	sort.Strings(names)
+
+	recovered := 0
+	for _, name := range names {
+		path := filepath.Join(service.spoolDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			service.logger.Warn("退避セッションの読み込みに失敗", "path", path, "error", err)
+			continue
+		}
+		var pending spooledSession
+		if err := json.Unmarshal(data, &pending); err != nil || pending.GameID == "" {
+			// 壊れたファイルは再試行しても直らないので残したまま飛ばす（手動確認用）。
+			service.logger.Warn("退避セッションの形式が不正です", "path", path, "error", err)
+			continue
+		}
+
+		game, err := service.repository.GetGameByID(ctx, pending.GameID)
+		if err == nil && game == nil {
+			service.logger.Warn("ゲームが削除済みのため退避セッションを破棄します", "gameId", pending.GameID, "duration", pending.Duration)
+			service.removeSpoolFile(path)
+			continue
+		}
+		if err == nil {
+			err = service.persistSession(ctx, pending)
+		}
+		if err != nil {
+			pending.Attempts++
+			pending.LastError = err.Error()
+			if _, writeErr := writeSpooledSession(service.spoolDir, name, pending); writeErr != nil {
+				service.logger.Warn("退避セッションの更新に失敗", "path", path, "error", writeErr)
+			}
+			service.logger.Warn("退避セッションの再保存に失敗", "gameId", pending.GameID, "attempts", pending.Attempts, "error", err)
+			break
+		}
+		service.removeSpoolFile(path)
+		recovered++
+	}
+	if recovered > 0 {
+		service.logger.Info("退避セッションを保存しました", "count", recovered)
+	}
+	return recovered
+}
+
+func (service *ProcessMonitorService) removeSpoolFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		service.logger.Warn("退避セッションの削除に失敗", "path", path, "error", err)
+	}
+}
+
+// sessionSpoolFileName は <終了日時ナノ秒>_<ゲームIDのハッシュ>.json を返す。
+// 同時刻に終了した複数ゲーム（停止時の一括保存）でも衝突しない。
+func sessionSpoolFileName(pending spooledSession) string {
+	return fmt.Sprintf("%020d_%s%s", pending.EndedAt.UnixNano(), hashBytes([]byte(pending.GameID))[:16], sessionSpoolFileExt)
+}
+
+// writeSpooledSession は一時ファイルへ書いてから rename し、書き込み途中のファイルを残さない。
+func writeSpooledSession(dir string, name string, pending spooledSession) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestProcessMonitorServiceSpoolsFailedSessionAndRetries(t *testing.T) {
+	t.Parallel()
+
+	spoolDir := t.TempDir()
+	dbLocked := true
+	var created []domain.PlaySession
+	var updatedGame domain.Game
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			if dbLocked {
+				return nil, errors.New("database is locked")
+			}
+			created = append(created, session)
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game", TotalPlayTime: 100}, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) {
+			updatedGame = game
+			return &game, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetSessionSpoolDir(spoolDir)
+
+	endedAt := time.Date(2026, 4, 24, 20, 0, 0, 0, time.UTC)
+	service.saveSession(MonitoringGame{GameID: "game-1", ExeName: "game.exe", AccumulatedTime: 30}, endedAt)
+
+	entries, err := os.ReadDir(spoolDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one spooled session, got %v (err=%v)", entries, err)
+	}
+	if recovered := service.RetrySpooledSessions(context.Background()); recovered != 0 {
+		t.Fatalf("expected retry to fail while db is locked, got %d", recovered)
+	}
+	if entries, _ := os.ReadDir(spoolDir); len(entries) != 1 {
+		t.Fatalf("expected spooled session to be kept after failed retry, got %v", entries)
+	}
+
+	dbLocked = false
+	if recovered := service.RetrySpooledSessions(context.Background()); recovered != 1 {
+		t.Fatalf("expected one recovered session, got %d", recovered)
+	}
+	if len(created) != 1 || created[0].Duration != 30 || !created[0].PlayedAt.Equal(endedAt) {
+		t.Fatalf("unexpected created sessions: %#v", created)
+	}
+	if updatedGame.TotalPlayTime != 130 {
+		t.Fatalf("expected total play time to be updated, got %d", updatedGame.TotalPlayTime)
+	}
+	if entries, _ := os.ReadDir(spoolDir); len(entries) != 0 {
+		t.Fatalf("expected spool to be empty, got %v", entries)
+	}
+}
+
+func TestProcessMonitorServiceRetryDropsSessionsOfDeletedGames(t *testing.T) {
+	t.Parallel()
+
+	spoolDir := t.TempDir()
+	if _, err := writeSpooledSession(spoolDir, "1_deleted.json", spooledSession{GameID: "deleted", Duration: 10}); err != nil {
+		t.Fatal(err)
+	}
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			t.Fatalf("session of deleted game must not be created")
+			return nil, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) { return nil, nil },
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetSessionSpoolDir(spoolDir)
+
+	if recovered := service.RetrySpooledSessions(context.Background()); recovered != 0 {
+		t.Fatalf("expected nothing recovered, got %d", recovered)
+	}
+	if entries, _ := os.ReadDir(spoolDir); len(entries) != 0 {
+		t.Fatalf("expected spooled session to be dropped, got %v", entries)
+	}
+}
+
+func TestProcessMonitorServiceSpoolsSessionAfterShutdown(t *testing.T) {
+	t.Parallel()
+
+	spoolDir := t.TempDir()
+	service := NewProcessMonitorService(fakeProcessMonitorRepository{
+		createPlaySessionFn: func(ctx context.Context, session domain.PlaySession) (*domain.PlaySession, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("session save should have a deadline")
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &session, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			return &domain.Game{ID: gameID, Title: "Game"}, nil
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service.SetSessionSpoolDir(spoolDir)
+	ctx, cancel := context.WithCancel(context.Background())
+	service.ctx = ctx
+	cancel()
+
+	service.saveSession(MonitoringGame{GameID: "game-1", ExeName: "game.exe", AccumulatedTime: 30}, time.Now())
+
+	if entries, err := os.ReadDir(spoolDir); err != nil || len(entries) != 1 {
+		t.Fatalf("session saved after shutdown should be spooled, got %v (err=%v)", entries, err)
+	}
+}
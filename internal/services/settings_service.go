@@ -0,0 +1,310 @@
+// アプリ設定の永続化（Settings テーブル）を提供する。
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/i18n"
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+// appSettingsKey は Settings テーブル上でアプリ設定 JSON を保存するキー。
+// 項目ごとに行を分けると追加・削除のたびに移行が要るため、1行の JSON にまとめる。
+const appSettingsKey = "app_settings"
+
+// AppSettings は UI から変更できるアプリ設定を表す。
+// 環境変数（config.Config）は既定値として扱い、保存済みの値があればそちらを優先する。
+type AppSettings struct {
+	LogLevel                     string `json:"logLevel"`
+	Language                     string `json:"language"`
+	AutoTracking                 bool   `json:"autoTracking"`
+	MonitorIntervalSeconds       int    `json:"monitorIntervalSeconds"`
+	MonitorWarmupPolicy          string `json:"monitorWarmupPolicy"`
+	MonitorIdleThresholdMinutes  int    `json:"monitorIdleThresholdMinutes"`
+	MonitorSessionTimeoutSeconds int    `json:"monitorSessionTimeoutSeconds"`
+	MonitorCleanupTimeoutSeconds int    `json:"monitorCleanupTimeoutSeconds"`
+	MonitorSplitAtMidnight       bool   `json:"monitorSplitAtMidnight"`
+	PlayReminderMinutes          int    `json:"playReminderMinutes"`
+	OfflineMode                  bool   `json:"offlineMode"`
+	S3ForcePathStyle             bool   `json:"s3ForcePathStyle"`
+	S3UseTLS                     bool   `json:"s3UseTls"`
+	S3UploadConcurrency          int    `json:"s3UploadConcurrency"`
+	S3MultipartPartSizeMB        int    `json:"s3MultipartPartSizeMb"`
+	SaveCompression              bool   `json:"saveCompression"`
+	SyncConflictPolicy           string `json:"syncConflictPolicy"`
+	ActiveCredentialKey          string `json:"activeCredentialKey"`
+	ScreenshotSyncEnabled        bool   `json:"screenshotSyncEnabled"`
+	ScreenshotUploadJpeg         bool   `json:"screenshotUploadJpeg"`
+	ScreenshotJpegQuality        int    `json:"screenshotJpegQuality"`
+	ScreenshotClientOnly         bool   `json:"screenshotClientOnly"`
+	ScreenshotLocalJpeg          bool   `json:"screenshotLocalJpeg"`
+	ScreenshotHotkey             string `json:"screenshotHotkey"`
+	ScreenshotHotkeyNotify       bool   `json:"screenshotHotkeyNotify"`
+	ScreenshotExcludedApps       string `json:"screenshotExcludedApps"`
+	ScreenshotCopyToClipboard    bool   `json:"screenshotCopyToClipboard"`
+	ClipBufferSeconds            int    `json:"clipBufferSeconds"`
+	ClipFFmpegPath               string `json:"clipFfmpegPath"`
+	ThumbnailShortEdgePx         int    `json:"thumbnailShortEdgePx"`
+	ErogameScapeCacheTTLMinutes  int    `json:"erogameScapeCacheTtlMinutes"`
+	MemoExternalEditUpload       bool   `json:"memoExternalEditUpload"`
+	SaveWatchAutoUpload          bool   `json:"saveWatchAutoUpload"`
+	StorageBackend               string `json:"storageBackend"`
+	LocalStorageDir              string `json:"localStorageDir"`
+	HTTPTimeoutSeconds           int    `json:"httpTimeoutSeconds"`
+	HTTPProxyURL                 string `json:"httpProxyUrl"`
+	HTTPMaxRetries               int    `json:"httpMaxRetries"`
+
+	// HotkeyActions はスクリーンショット以外の操作（中断・再開 / 終了 / 今すぐ同期 / クリップ保存）に割り当てたホットキー。
+	HotkeyActions config.HotkeyActions `json:"hotkeyActions"`
+}
+
+// AppSettingsFromConfig は Config の値から AppSettings を作る。
+// Config に無い AutoTracking / OfflineMode は既定値（true / false）になる。
+func AppSettingsFromConfig(cfg config.Config) AppSettings {
+	return AppSettings{
+		LogLevel:                     cfg.LogLevel,
+		Language:                     cfg.Language,
+		AutoTracking:                 true,
+		MonitorIntervalSeconds:       cfg.MonitorIntervalSeconds,
+		MonitorWarmupPolicy:          cfg.MonitorWarmupPolicy,
+		MonitorIdleThresholdMinutes:  cfg.MonitorIdleThresholdMinutes,
+		MonitorSessionTimeoutSeconds: cfg.MonitorSessionTimeoutSeconds,
+		MonitorCleanupTimeoutSeconds: cfg.MonitorCleanupTimeoutSeconds,
+		MonitorSplitAtMidnight:       cfg.MonitorSplitAtMidnight,
+		PlayReminderMinutes:          cfg.PlayReminderMinutes,
+		OfflineMode:                  false,
+		S3ForcePathStyle:             cfg.S3ForcePathStyle,
+		S3UseTLS:                     cfg.S3UseTLS,
+		S3UploadConcurrency:          cfg.S3UploadConcurrency,
+		S3MultipartPartSizeMB:        cfg.S3MultipartPartSizeMB,
+		SaveCompression:              cfg.SaveCompression,
+		SyncConflictPolicy:           cfg.SyncConflictPolicy,
+		ActiveCredentialKey:          cfg.CredentialKey,
+		ScreenshotSyncEnabled:        cfg.ScreenshotSyncEnabled,
+		ScreenshotUploadJpeg:         cfg.ScreenshotUploadJpeg,
+		ScreenshotJpegQuality:        cfg.ScreenshotJpegQuality,
+		ScreenshotClientOnly:         cfg.ScreenshotClientOnly,
+		ScreenshotLocalJpeg:          cfg.ScreenshotLocalJpeg,
+		ScreenshotHotkey:             cfg.ScreenshotHotkey,
+		ScreenshotHotkeyNotify:       cfg.ScreenshotHotkeyNotify,
+		ScreenshotExcludedApps:       cfg.ScreenshotExcludedApps,
+		ScreenshotCopyToClipboard:    cfg.ScreenshotCopyToClipboard,
+		ClipBufferSeconds:            cfg.ClipBufferSeconds,
+		ClipFFmpegPath:               cfg.ClipFFmpegPath,
+		ThumbnailShortEdgePx:         cfg.ThumbnailShortEdgePx,
+		ErogameScapeCacheTTLMinutes:  cfg.ErogameScapeCacheTTLMinutes,
+		MemoExternalEditUpload:       cfg.MemoExternalEditUpload,
+		SaveWatchAutoUpload:          cfg.SaveWatchAutoUpload,
+		StorageBackend:               NormalizeStorageBackend(cfg.StorageBackend),
+		LocalStorageDir:              cfg.LocalStorageDir,
+		HTTPTimeoutSeconds:           cfg.HTTPTimeoutSeconds,
+		HTTPProxyURL:                 cfg.HTTPProxyURL,
+		HTTPMaxRetries:               cfg.HTTPMaxRetries,
+		HotkeyActions:                cfg.HotkeyActions,
+	}
+}
+
+// ApplyTo は Config 由来の項目を cfg に書き戻す。
+// AutoTracking / OfflineMode は Config に無いため呼び出し側でサービスへ反映する。
+func (settings AppSettings) ApplyTo(cfg *config.Config) {
+	cfg.LogLevel = settings.LogLevel
+	cfg.Language = settings.Language
+	cfg.MonitorIntervalSeconds = settings.MonitorIntervalSeconds
+	cfg.MonitorWarmupPolicy = settings.MonitorWarmupPolicy
+	cfg.MonitorIdleThresholdMinutes = settings.MonitorIdleThresholdMinutes
+	cfg.MonitorSessionTimeoutSeconds = settings.MonitorSessionTimeoutSeconds
+	cfg.MonitorCleanupTimeoutSeconds = settings.MonitorCleanupTimeoutSeconds
+	cfg.MonitorSplitAtMidnight = settings.MonitorSplitAtMidnight
+	cfg.PlayReminderMinutes = settings.PlayReminderMinutes
+	cfg.S3ForcePathStyle = settings.S3ForcePathStyle
+	cfg.S3UseTLS = settings.S3UseTLS
+	cfg.S3UploadConcurrency = settings.S3UploadConcurrency
+	cfg.S3MultipartPartSizeMB = settings.S3MultipartPartSizeMB
+	cfg.SaveCompression = settings.SaveCompression
+	cfg.SyncConflictPolicy = settings.SyncConflictPolicy
+	cfg.CredentialKey = settings.ActiveCredentialKey
+	cfg.ScreenshotSyncEnabled = settings.ScreenshotSyncEnabled
+	cfg.ScreenshotUploadJpeg = settings.ScreenshotUploadJpeg
+	cfg.ScreenshotJpegQuality = settings.ScreenshotJpegQuality
+	cfg.ScreenshotClientOnly = settings.ScreenshotClientOnly
+	cfg.ScreenshotLocalJpeg = settings.ScreenshotLocalJpeg
+	cfg.ScreenshotHotkey = settings.ScreenshotHotkey
+	cfg.ScreenshotHotkeyNotify = settings.ScreenshotHotkeyNotify
+	cfg.ScreenshotExcludedApps = settings.ScreenshotExcludedApps
+	cfg.ScreenshotCopyToClipboard = settings.ScreenshotCopyToClipboard
+	cfg.ClipBufferSeconds = settings.ClipBufferSeconds
+	cfg.ClipFFmpegPath = settings.ClipFFmpegPath
+	cfg.ThumbnailShortEdgePx = settings.ThumbnailShortEdgePx
+	cfg.ErogameScapeCacheTTLMinutes = settings.ErogameScapeCacheTTLMinutes
+	cfg.MemoExternalEditUpload = settings.MemoExternalEditUpload
+	cfg.SaveWatchAutoUpload = settings.SaveWatchAutoUpload
+	cfg.StorageBackend = settings.StorageBackend
+	cfg.LocalStorageDir = settings.LocalStorageDir
+	cfg.HTTPTimeoutSeconds = settings.HTTPTimeoutSeconds
+	cfg.HTTPProxyURL = settings.HTTPProxyURL
+	cfg.HTTPMaxRetries = settings.HTTPMaxRetries
+	cfg.HotkeyActions = settings.HotkeyActions
+}
+
+// SettingsService はアプリ設定の読み書きを提供する。
+type SettingsService struct {
+	repository SettingsRepository
+	logger     *slog.Logger
+}
+
+// NewSettingsService は SettingsService を生成する。
+func NewSettingsService(repository SettingsRepository, logger *slog.Logger) *SettingsService {
+	return &SettingsService{repository: repository, logger: logger}
+}
+
+// LoadSettings は保存済み設定を defaults に重ねて返す。
+// 保存時に無かった項目（後から追加された設定）は defaults のまま残る。
+// 保存値が壊れている・不正な場合は警告ログを出して defaults を返す（起動を止めない）。
+func (service *SettingsService) LoadSettings(ctx context.Context, defaults AppSettings) (AppSettings, error) {
+	raw, error := service.repository.GetSetting(ctx, appSettingsKey)
+	if error != nil {
+		service.logger.Error("設定の取得に失敗", "error", error)
+		return defaults, newServiceError("設定の取得に失敗しました", error.Error())
+	}
+	if strings.TrimSpace(raw) == "" {
+		return defaults, nil
+	}
+	settings := defaults
+	if error := json.Unmarshal([]byte(raw), &settings); error != nil {
+		service.logger.Warn("保存済み設定の解析に失敗（既定値を使用）", "error", error)
+		return defaults, nil
+	}
+	normalized, error := NormalizeAppSettings(settings)
+	if error != nil {
+		service.logger.Warn("保存済み設定が不正です（既定値を使用）", "error", error)
+		return defaults, nil
+	}
+	return normalized, nil
+}
+
+// SaveSettings は設定を検証して保存し、正規化後の値を返す。
+func (service *SettingsService) SaveSettings(ctx context.Context, settings AppSettings) (AppSettings, error) {
+	normalized, error := NormalizeAppSettings(settings)
+	if error != nil {
+		service.logger.Warn("設定が不正です", "error", error)
+		return AppSettings{}, newServiceError("設定が不正です", error.Error())
+	}
+	payload, error := json.Marshal(normalized)
+	if error != nil {
+		return AppSettings{}, newServiceError("設定の保存に失敗しました", error.Error())
+	}
+	if error := service.repository.UpsertSetting(ctx, appSettingsKey, string(payload)); error != nil {
+		service.logger.Error("設定の保存に失敗", "error", error)
+		return AppSettings{}, newServiceError("設定の保存に失敗しました", error.Error())
+	}
+	return normalized, nil
+}
+
+// NormalizeLogLevel はログレベル文字列を debug/info/warn/error に正規化する。
+func NormalizeLogLevel(level string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(level))
+	switch normalized {
+	case "debug", "info", "warn", "error":
+		return normalized, true
+	case "warning":
+		return "warn", true
+	default:
+		return "", false
+	}
+}
+
+// ValidateMonitorTimeouts はプロセス監視の終了確認待ちまでの秒数（0-600）と、
+// 監視対象から外すまでの秒数（5-600）を検証する。
+func ValidateMonitorTimeouts(sessionTimeoutSeconds, cleanupTimeoutSeconds int) error {
+	if sessionTimeoutSeconds < 0 || sessionTimeoutSeconds > 600 {
+		return errors.New("monitorSessionTimeoutSeconds must be 0-600")
+	}
+	if cleanupTimeoutSeconds < 5 || cleanupTimeoutSeconds > 600 {
+		return errors.New("monitorCleanupTimeoutSeconds must be 5-600")
+	}
+	return nil
+}
+
+// NormalizeAppSettings は設定値を検証し、ログレベル・ホットキーの表記を正規化する。
+func NormalizeAppSettings(settings AppSettings) (AppSettings, error) {
+	level, ok := NormalizeLogLevel(settings.LogLevel)
+	if !ok {
+		return AppSettings{}, errors.New("logLevel must be debug|info|warn|error")
+	}
+	settings.LogLevel = level
+	if settings.MonitorIntervalSeconds < 1 || settings.MonitorIntervalSeconds > 60 {
+		return AppSettings{}, errors.New("monitorIntervalSeconds must be 1-60")
+	}
+	policy, ok := NormalizeWarmupPolicy(settings.MonitorWarmupPolicy)
+	if !ok {
+		return AppSettings{}, errors.New("monitorWarmupPolicy must be partial|prompt")
+	}
+	settings.MonitorWarmupPolicy = policy
+	if settings.MonitorIdleThresholdMinutes < 0 || settings.MonitorIdleThresholdMinutes > 240 {
+		return AppSettings{}, errors.New("monitorIdleThresholdMinutes must be 0-240")
+	}
+	if error := ValidateMonitorTimeouts(settings.MonitorSessionTimeoutSeconds, settings.MonitorCleanupTimeoutSeconds); error != nil {
+		return AppSettings{}, error
+	}
+	if error := ValidatePlayReminderMinutes(settings.PlayReminderMinutes); error != nil {
+		return AppSettings{}, error
+	}
+	if settings.S3UploadConcurrency <= 0 {
+		return AppSettings{}, errors.New("s3UploadConcurrency must be positive")
+	}
+	if error := storage.ValidateMultipartPartSizeMB(settings.S3MultipartPartSizeMB); error != nil {
+		return AppSettings{}, error
+	}
+	if settings.ScreenshotJpegQuality < 1 || settings.ScreenshotJpegQuality > 100 {
+		return AppSettings{}, errors.New("screenshotJpegQuality must be 1-100")
+	}
+	settings.ActiveCredentialKey = strings.TrimSpace(settings.ActiveCredentialKey)
+	if settings.ActiveCredentialKey == "" {
+		return AppSettings{}, errors.New("activeCredentialKey is empty")
+	}
+	settings.ScreenshotHotkey = strings.TrimSpace(settings.ScreenshotHotkey)
+	if error := ValidateHotkeyCombo(settings.ScreenshotHotkey); error != nil {
+		return AppSettings{}, error
+	}
+	actions, error := NormalizeHotkeyActions(settings.HotkeyActions, settings.ScreenshotHotkey)
+	if error != nil {
+		return AppSettings{}, error
+	}
+	settings.HotkeyActions = actions
+	settings.ScreenshotExcludedApps = NormalizeScreenshotExcludedApps(settings.ScreenshotExcludedApps)
+	if error := ValidateClipBufferSeconds(settings.ClipBufferSeconds); error != nil {
+		return AppSettings{}, error
+	}
+	settings.ClipFFmpegPath = strings.TrimSpace(settings.ClipFFmpegPath)
+	if error := ValidateThumbnailShortEdge(settings.ThumbnailShortEdgePx); error != nil {
+		return AppSettings{}, error
+	}
+	if error := ValidateErogameScapeCacheTTL(settings.ErogameScapeCacheTTLMinutes); error != nil {
+		return AppSettings{}, error
+	}
+	language, error := i18n.NormalizeLanguage(settings.Language)
+	if error != nil {
+		return AppSettings{}, error
+	}
+	settings.Language = language
+	if error := ValidateHTTPSettings(settings.HTTPTimeoutSeconds, settings.HTTPProxyURL, settings.HTTPMaxRetries); error != nil {
+		return AppSettings{}, error
+	}
+	settings.HTTPProxyURL = strings.TrimSpace(settings.HTTPProxyURL)
+	conflictPolicy, ok := NormalizeSyncConflictPolicy(settings.SyncConflictPolicy)
+	if !ok {
+		return AppSettings{}, errors.New("syncConflictPolicy must be prefer-local|prefer-cloud|newest-wins|always-ask")
+	}
+	settings.SyncConflictPolicy = conflictPolicy
+	settings.StorageBackend = NormalizeStorageBackend(settings.StorageBackend)
+	settings.LocalStorageDir = strings.TrimSpace(settings.LocalStorageDir)
+	if settings.StorageBackend == StorageBackendLocal && settings.LocalStorageDir == "" {
+		return AppSettings{}, errors.New("localStorageDir is required for the local storage backend")
+	}
+	return settings, nil
+}
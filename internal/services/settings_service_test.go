@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"CloudLaunch_Go/internal/config"
+)
+
+type fakeSettingsRepository struct {
+	values map[string]string
+	getErr error
+}
+
+func (repository *fakeSettingsRepository) GetSetting(_ context.Context, key string) (string, error) {
+	if repository.getErr != nil {
+		return "", repository.getErr
+	}
+	return repository.values[key], nil
+}
+
+func (repository *fakeSettingsRepository) UpsertSetting(_ context.Context, key, value string) error {
+	if repository.values == nil {
+		repository.values = make(map[string]string)
+	}
+	repository.values[key] = value
+	return nil
+}
+
+func testDefaultSettings() AppSettings {
+	return AppSettingsFromConfig(config.Config{
+		LogLevel:                     "info",
+		MonitorIntervalSeconds:       2,
+		MonitorWarmupPolicy:          "partial",
+		MonitorCleanupTimeoutSeconds: 20,
+		S3UseTLS:                     true,
+		S3UploadConcurrency:          6,
+		S3MultipartPartSizeMB:        16,
+		ThumbnailShortEdgePx:         200,
+		CredentialKey:                "default",
+		ScreenshotJpegQuality:        85,
+		ScreenshotHotkey:             "Ctrl+Alt+S",
+		HTTPTimeoutSeconds:           15,
+		HTTPMaxRetries:               2,
+	})
+}
+
+func TestSettingsServiceLoadSettingsReturnsDefaultsWhenUnset(t *testing.T) {
+	t.Parallel()
+
+	service := NewSettingsService(&fakeSettingsRepository{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defaults := testDefaultSettings()
+
+	got, err := service.LoadSettings(context.Background(), defaults)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if got != defaults {
+		t.Fatalf("expected defaults, got %#v", got)
+	}
+}
+
+func TestSettingsServiceSaveThenLoadRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeSettingsRepository{}
+	service := NewSettingsService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	settings := testDefaultSettings()
+	settings.LogLevel = " WARNING "
+	settings.OfflineMode = true
+	settings.ScreenshotJpegQuality = 70
+
+	saved, err := service.SaveSettings(context.Background(), settings)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if saved.LogLevel != "warn" {
+		t.Fatalf("expected normalized log level, got %q", saved.LogLevel)
+	}
+
+	loaded, err := service.LoadSettings(context.Background(), testDefaultSettings())
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if loaded != saved {
+		t.Fatalf("expected %#v, got %#v", saved, loaded)
+	}
+}
+
+func TestSettingsServiceLoadSettingsKeepsDefaultsForMissingFields(t *testing.T) {
+	t.Parallel()
+
+	payload, _ := json.Marshal(map[string]any{"screenshotJpegQuality": 50})
+	repository := &fakeSettingsRepository{values: map[string]string{appSettingsKey: string(payload)}}
+	service := NewSettingsService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	got, err := service.LoadSettings(context.Background(), testDefaultSettings())
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if got.ScreenshotJpegQuality != 50 {
+		t.Fatalf("expected stored quality, got %d", got.ScreenshotJpegQuality)
+	}
+	if got.S3UploadConcurrency != 6 || !got.AutoTracking {
+		t.Fatalf("expected defaults for missing fields, got %#v", got)
+	}
+}
+
+func TestSettingsServiceLoadSettingsFallsBackOnInvalidStoredValue(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeSettingsRepository{values: map[string]string{appSettingsKey: `{"screenshotJpegQuality":0}`}}
+	service := NewSettingsService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defaults := testDefaultSettings()
+
+	got, err := service.LoadSettings(context.Background(), defaults)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if got != defaults {
+		t.Fatalf("expected defaults, got %#v", got)
+	}
+}
+
+func TestSettingsServiceLoadSettingsReturnsServiceErrorOnRepositoryFailure(t *testing.T) {
+	t.Parallel()
+
+	repository := &fakeSettingsRepository{getErr: errors.New("db down")}
+	service := NewSettingsService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := service.LoadSettings(context.Background(), testDefaultSettings())
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatalf("expected ServiceError, got %v", err)
+	}
+}
+
+func TestSettingsServiceSaveSettingsRejectsInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]func(*AppSettings){
+		"logLevel":        func(s *AppSettings) { s.LogLevel = "verbose" },
+		"monitorInterval": func(s *AppSettings) { s.MonitorIntervalSeconds = 0 },
+		"warmupPolicy":    func(s *AppSettings) { s.MonitorWarmupPolicy = "ask" },
+		"idleThreshold":   func(s *AppSettings) { s.MonitorIdleThresholdMinutes = 241 },
+		"sessionTimeout":  func(s *AppSettings) { s.MonitorSessionTimeoutSeconds = -1 },
+		"cleanupTimeout":  func(s *AppSettings) { s.MonitorCleanupTimeoutSeconds = 0 },
+		"concurrency":     func(s *AppSettings) { s.S3UploadConcurrency = 0 },
+		"partSize":        func(s *AppSettings) { s.S3MultipartPartSizeMB = 4 },
+		"thumbnailSize":   func(s *AppSettings) { s.ThumbnailShortEdgePx = 10 },
+		"cacheTtl":        func(s *AppSettings) { s.ErogameScapeCacheTTLMinutes = -1 },
+		"jpegQuality":     func(s *AppSettings) { s.ScreenshotJpegQuality = 101 },
+		"credentialKey":   func(s *AppSettings) { s.ActiveCredentialKey = " " },
+		"hotkey":          func(s *AppSettings) { s.ScreenshotHotkey = "" },
+		"httpTimeout":     func(s *AppSettings) { s.HTTPTimeoutSeconds = 0 },
+		"httpRetries":     func(s *AppSettings) { s.HTTPMaxRetries = 6 },
+		"httpProxy":       func(s *AppSettings) { s.HTTPProxyURL = "ftp://proxy" },
+	}
+	for name, mutate := range cases {
+		repository := &fakeSettingsRepository{}
+		service := NewSettingsService(repository, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		settings := testDefaultSettings()
+		mutate(&settings)
+		if _, err := service.SaveSettings(context.Background(), settings); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if _, ok := repository.values[appSettingsKey]; ok {
+			t.Errorf("%s: invalid settings should not be stored", name)
+		}
+	}
+}
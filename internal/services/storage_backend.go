@@ -0,0 +1,26 @@
+// 同期データの保存先（S3 互換ストレージ・ローカルフォルダ・Google ドライブ）の種類を提供する。
+package services
+
+import "strings"
+
+// 同期データの保存先の種類。
+const (
+	// StorageBackendS3 は S3 互換ストレージのバケットに保存する（既定）。
+	StorageBackendS3 = "s3"
+	// StorageBackendLocal は LocalStorageDir のフォルダ（NAS の共有フォルダ、クラウドドライブの同期フォルダ等）に保存する。
+	StorageBackendLocal = "local"
+	// StorageBackendGoogleDrive は OAuth で接続した Google ドライブの CloudLaunch フォルダに保存する。
+	StorageBackendGoogleDrive = "gdrive"
+)
+
+// NormalizeStorageBackend は設定値を保存先の種類に正規化する。未知の値・空は S3 として扱う。
+func NormalizeStorageBackend(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case StorageBackendLocal:
+		return StorageBackendLocal
+	case StorageBackendGoogleDrive:
+		return StorageBackendGoogleDrive
+	default:
+		return StorageBackendS3
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newLocalBackendTestService(t *testing.T, repo *fakeContentSyncRepository, storageDir string) *ContentSyncService {
+	t.Helper()
+	svc := newTestService(repo, nil)
+	svc.SetStorageBackend(StorageBackendLocal, storageDir)
+	svc.newBlobStore = func(ctx context.Context) (contentBlobStore, error) {
+		return svc.newStorageBlobStore(ctx)
+	}
+	return svc
+}
+
+func TestContentSyncServiceLocalBackendPushThenPull(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storageDir := t.TempDir()
+	saveDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(saveDir, "save.dat"), []byte("game data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	game := baseGame(saveDir)
+	if err := newLocalBackendTestService(t, newFakeRepo(&game, nil), storageDir).Push(ctx, game.ID, nil); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(storageDir, "games", game.ID, "HEAD")); err != nil {
+		t.Fatalf("HEAD should be written into the storage folder: %v", err)
+	}
+
+	// 同じフォルダを見る別PCで取り込む。
+	otherDir := filepath.Join(t.TempDir(), "saves")
+	pullGame := baseGame(otherDir)
+	if _, err := newLocalBackendTestService(t, newFakeRepo(&pullGame, nil), storageDir).Pull(ctx, game.ID, nil, false); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(otherDir, "save.dat"))
+	if err != nil || string(data) != "game data" {
+		t.Fatalf("pulled save = %q, %v", data, err)
+	}
+}
+
+func TestContentSyncServiceLocalBackendRequiresFolder(t *testing.T) {
+	t.Parallel()
+
+	game := baseGame(t.TempDir())
+	svc := newLocalBackendTestService(t, newFakeRepo(&game, nil), filepath.Join(t.TempDir(), "missing"))
+	if err := svc.Push(context.Background(), game.ID, nil); err == nil {
+		t.Fatal("push to a missing storage folder should fail")
+	}
+}
+
+func TestNormalizeStorageBackend(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{"": StorageBackendS3, "s3": StorageBackendS3, " Local ": StorageBackendLocal, "GDrive": StorageBackendGoogleDrive, "ftp": StorageBackendS3}
+	for input, want := range cases {
+		if got := NormalizeStorageBackend(input); got != want {
+			t.Errorf("NormalizeStorageBackend(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// 認証情報設定で選べる S3 互換ストレージのプリセットを提供する。
+package services
+
+import "strings"
+
+// StorageProviderPreset は S3 互換ストレージごとの接続設定の既定値を表す。
+// Endpoint は "{region}" / "{accountId}" を含むテンプレートで、Placeholders の値を埋めてから使う。
+type StorageProviderPreset struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Endpoint       string   `json:"endpoint"`
+	DefaultRegion  string   `json:"defaultRegion"`
+	ForcePathStyle bool     `json:"forcePathStyle"`
+	UseTLS         bool     `json:"useTls"`
+	Placeholders   []string `json:"placeholders"`
+}
+
+// ストレージプリセットのテンプレートで置き換える値。
+const (
+	PresetPlaceholderRegion    = "region"
+	PresetPlaceholderAccountID = "accountId"
+)
+
+// storageProviderPresets は一覧に出す順のプリセット定義。Amazon S3 はエンドポイント未指定（SDK 既定）で使う。
+var storageProviderPresets = []StorageProviderPreset{
+	{
+		ID:            "aws",
+		Name:          "Amazon S3",
+		DefaultRegion: "ap-northeast-1",
+		UseTLS:        true,
+	},
+	{
+		ID:             "cloudflare-r2",
+		Name:           "Cloudflare R2",
+		Endpoint:       "https://{accountId}.r2.cloudflarestorage.com",
+		DefaultRegion:  "auto",
+		ForcePathStyle: true,
+		UseTLS:         true,
+		Placeholders:   []string{PresetPlaceholderAccountID},
+	},
+	{
+		ID:            "backblaze-b2",
+		Name:          "Backblaze B2",
+		Endpoint:      "https://s3.{region}.backblazeb2.com",
+		DefaultRegion: "us-west-004",
+		UseTLS:        true,
+		Placeholders:  []string{PresetPlaceholderRegion},
+	},
+	{
+		ID:            "wasabi",
+		Name:          "Wasabi",
+		Endpoint:      "https://s3.{region}.wasabisys.com",
+		DefaultRegion: "ap-northeast-1",
+		UseTLS:        true,
+		Placeholders:  []string{PresetPlaceholderRegion},
+	},
+	{
+		ID:             "minio",
+		Name:           "MinIO",
+		Endpoint:       "http://localhost:9000",
+		DefaultRegion:  "us-east-1",
+		ForcePathStyle: true,
+	},
+}
+
+// StorageProviderPresets はプリセットの一覧を返す。呼び出し側で書き換えても元の定義には影響しない。
+func StorageProviderPresets() []StorageProviderPreset {
+	presets := make([]StorageProviderPreset, len(storageProviderPresets))
+	for i, preset := range storageProviderPresets {
+		preset.Placeholders = append([]string{}, preset.Placeholders...)
+		presets[i] = preset
+	}
+	return presets
+}
+
+// ResolveEndpoint はテンプレートのプレースホルダーを values で置き換えたエンドポイントを返す。
+// "{region}" の値が無ければ DefaultRegion を使う。置き換えられなかったものはそのまま残る。
+func (preset StorageProviderPreset) ResolveEndpoint(values map[string]string) string {
+	endpoint := preset.Endpoint
+	region := strings.TrimSpace(values[PresetPlaceholderRegion])
+	if region == "" {
+		region = preset.DefaultRegion
+	}
+	endpoint = strings.ReplaceAll(endpoint, "{"+PresetPlaceholderRegion+"}", region)
+	for key, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			endpoint = strings.ReplaceAll(endpoint, "{"+key+"}", trimmed)
+		}
+	}
+	return endpoint
+}
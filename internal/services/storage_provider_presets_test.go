@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"CloudLaunch_Go/internal/infrastructure/storage"
+)
+
+func TestStorageProviderPresetsResolveToValidEndpoints(t *testing.T) {
+	t.Parallel()
+
+	presets := StorageProviderPresets()
+	seen := map[string]bool{}
+	for _, preset := range presets {
+		if preset.ID == "" || seen[preset.ID] {
+			t.Fatalf("preset IDs must be unique and non-empty: %+v", preset)
+		}
+		seen[preset.ID] = true
+		if preset.DefaultRegion == "" {
+			t.Errorf("%s: default region is empty", preset.ID)
+		}
+		endpoint := preset.ResolveEndpoint(map[string]string{PresetPlaceholderAccountID: "abc123"})
+		if err := storage.ValidateEndpoint(endpoint); err != nil {
+			t.Errorf("%s: resolved endpoint %q is invalid: %v", preset.ID, endpoint, err)
+		}
+	}
+	for _, id := range []string{"cloudflare-r2", "backblaze-b2", "wasabi", "minio"} {
+		if !seen[id] {
+			t.Errorf("preset %s is missing", id)
+		}
+	}
+}
+
+func TestStorageProviderPresetResolveEndpoint(t *testing.T) {
+	t.Parallel()
+
+	presets := StorageProviderPresets()
+	var r2, wasabi StorageProviderPreset
+	for _, preset := range presets {
+		switch preset.ID {
+		case "cloudflare-r2":
+			r2 = preset
+		case "wasabi":
+			wasabi = preset
+		}
+	}
+	if got := r2.ResolveEndpoint(nil); storage.ValidateEndpoint(got) == nil {
+		t.Fatalf("unfilled account ID should stay invalid: %s", got)
+	}
+	if got := wasabi.ResolveEndpoint(map[string]string{PresetPlaceholderRegion: "eu-central-1"}); got != "https://s3.eu-central-1.wasabisys.com" {
+		t.Fatalf("unexpected wasabi endpoint: %s", got)
+	}
+
+	presets[0].Placeholders = append(presets[0].Placeholders, "mutated")
+	if len(StorageProviderPresets()[0].Placeholders) != 0 {
+		t.Fatal("returned presets should be copies")
+	}
+}
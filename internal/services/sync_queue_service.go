@@ -0,0 +1,152 @@
+// オフライン中のクラウド操作を記録し、オンライン復帰後に順番に再送する送信待ち行列を提供する。
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// ErrSyncTargetGone は再送対象（ゲーム・メモ等）が既に存在しない・送る物が無いことを表す。
+// ハンドラがこれを返した操作は失敗扱いにせず行列から取り除く。
+var ErrSyncTargetGone = errors.New("再送対象が存在しません")
+
+// SyncQueueHandler は送信待ち操作1件を実際に送る処理。
+type SyncQueueHandler func(ctx context.Context, targetID string) error
+
+// SyncQueueReplayResult は再送1回分の結果を表す。
+// Dropped は対象が消えていたため送らずに取り除いた件数、Remaining は行列に残った件数。
+type SyncQueueReplayResult struct {
+	Replayed  int `json:"replayed"`
+	Dropped   int `json:"dropped"`
+	Failed    int `json:"failed"`
+	Remaining int `json:"remaining"`
+}
+
+// SyncQueueService はオフライン中の操作を SQLite の行列に記録し、復帰後に再送する。
+// 同じ対象への操作は1件にまとめる（Push もメモのアップロードも最新の状態を送れば足りるため）。
+type SyncQueueService struct {
+	repository SyncQueueRepository
+	logger     *slog.Logger
+
+	handlersMu sync.RWMutex
+	handlers   map[domain.SyncQueueKind]SyncQueueHandler
+	replaying  atomic.Bool
+}
+
+// NewSyncQueueService は SyncQueueService を生成する。
+func NewSyncQueueService(repository SyncQueueRepository, logger *slog.Logger) *SyncQueueService {
+	return &SyncQueueService{
+		repository: repository,
+		logger:     logger,
+		handlers:   make(map[domain.SyncQueueKind]SyncQueueHandler),
+	}
+}
+
+// SetHandler は種別ごとの再送処理を登録する。
+func (service *SyncQueueService) SetHandler(kind domain.SyncQueueKind, handler SyncQueueHandler) {
+	service.handlersMu.Lock()
+	defer service.handlersMu.Unlock()
+	service.handlers[kind] = handler
+}
+
+// Enqueue は送信待ち操作を追加する。
+func (service *SyncQueueService) Enqueue(ctx context.Context, kind domain.SyncQueueKind, targetID string) error {
+	trimmed, detail, ok := requireNonEmpty(targetID, "targetID")
+	if !ok {
+		return newServiceError("送信待ちへの追加に失敗しました", detail)
+	}
+	if err := service.repository.EnqueueSyncItem(ctx, kind, trimmed); err != nil {
+		service.logger.Error("送信待ちへの追加に失敗", "kind", kind, "targetId", trimmed, "error", err)
+		return newServiceError("送信待ちへの追加に失敗しました", err.Error())
+	}
+	return nil
+}
+
+// List は送信待ち操作を追加順に返す。
+func (service *SyncQueueService) List(ctx context.Context) ([]domain.SyncQueueItem, error) {
+	items, err := service.repository.ListSyncQueue(ctx)
+	if err != nil {
+		service.logger.Error("送信待ち一覧の取得に失敗", "error", err)
+		return nil, newServiceError("送信待ち一覧の取得に失敗しました", err.Error())
+	}
+	return items, nil
+}
+
+// Discard は送信待ち操作を再送せずに取り除く。
+func (service *SyncQueueService) Discard(ctx context.Context, id int64) error {
+	if err := service.repository.DeleteSyncItem(ctx, id); err != nil {
+		service.logger.Error("送信待ちの削除に失敗", "id", id, "error", err)
+		return newServiceError("送信待ちの削除に失敗しました", err.Error())
+	}
+	return nil
+}
+
+// Replay は送信待ち操作を追加順に再送する。
+// 失敗した操作は失敗回数と理由を記録して残し、次の操作へ進む。ハンドラが ErrOffline を返した時点で
+// （再びオフラインになったとみなして）中断する。既に再送中なら何もせずゼロ値を返す。
+func (service *SyncQueueService) Replay(ctx context.Context) (SyncQueueReplayResult, error) {
+	if !service.replaying.CompareAndSwap(false, true) {
+		return SyncQueueReplayResult{}, nil
+	}
+	defer service.replaying.Store(false)
+
+	items, err := service.List(ctx)
+	if err != nil {
+		return SyncQueueReplayResult{}, err
+	}
+	res := SyncQueueReplayResult{}
+	for i, item := range items {
+		if ctx.Err() != nil {
+			res.Remaining += len(items) - i
+			break
+		}
+		err := service.replayItem(ctx, item)
+		if errors.Is(err, ErrOffline) {
+			res.Remaining += len(items) - i
+			break
+		}
+		switch {
+		case err == nil:
+			res.Replayed++
+			if done, cerr := service.repository.CompleteSyncItem(ctx, item.ID, item.Revision); cerr != nil {
+				service.logger.Warn("送信済みの送信待ちの削除に失敗", "id", item.ID, "error", cerr)
+				res.Remaining++
+			} else if !done {
+				// 再送中に同じ対象が再追加された。次回の再送で改めて送る。
+				res.Remaining++
+			}
+		case errors.Is(err, ErrSyncTargetGone):
+			res.Dropped++
+			if derr := service.repository.DeleteSyncItem(ctx, item.ID); derr != nil {
+				service.logger.Warn("送信待ちの削除に失敗", "id", item.ID, "error", derr)
+			}
+		default:
+			res.Failed++
+			res.Remaining++
+			service.logger.Warn("送信待ちの再送に失敗", "kind", item.Kind, "targetId", item.TargetID, "error", err)
+			if rerr := service.repository.RecordSyncItemFailure(ctx, item.ID, err.Error()); rerr != nil {
+				service.logger.Warn("再送失敗の記録に失敗", "id", item.ID, "error", rerr)
+			}
+		}
+	}
+	if res.Replayed > 0 || res.Dropped > 0 || res.Failed > 0 {
+		service.logger.Info("送信待ちを再送", "replayed", res.Replayed, "dropped", res.Dropped, "failed", res.Failed, "remaining", res.Remaining)
+	}
+	return res, nil
+}
+
+func (service *SyncQueueService) replayItem(ctx context.Context, item domain.SyncQueueItem) error {
+	service.handlersMu.RLock()
+	handler, ok := service.handlers[item.Kind]
+	service.handlersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("未対応の送信待ち種別です: %s", item.Kind)
+	}
+	return handler(ctx, item.TargetID)
+}
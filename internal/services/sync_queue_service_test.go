@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+type fakeSyncQueueRepository struct {
+	mu     sync.Mutex
+	nextID int64
+	items  []domain.SyncQueueItem
+}
+
+func (r *fakeSyncQueueRepository) EnqueueSyncItem(_ context.Context, kind domain.SyncQueueKind, targetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].Kind == kind && r.items[i].TargetID == targetID {
+			r.items[i].Revision++
+			return nil
+		}
+	}
+	r.nextID++
+	r.items = append(r.items, domain.SyncQueueItem{ID: r.nextID, Kind: kind, TargetID: targetID})
+	return nil
+}
+
+func (r *fakeSyncQueueRepository) ListSyncQueue(_ context.Context) ([]domain.SyncQueueItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.SyncQueueItem{}, r.items...), nil
+}
+
+func (r *fakeSyncQueueRepository) CompleteSyncItem(_ context.Context, id int64, revision int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].ID == id && r.items[i].Revision == revision {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeSyncQueueRepository) DeleteSyncItem(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].ID == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *fakeSyncQueueRepository) RecordSyncItemFailure(_ context.Context, id int64, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].ID == id {
+			r.items[i].Attempts++
+			r.items[i].LastError = &message
+		}
+	}
+	return nil
+}
+
+func TestSyncQueueServiceReplayRunsInOrderAndKeepsFailures(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeSyncQueueRepository{}
+	service := NewSyncQueueService(repo, newTestLogger())
+	ctx := context.Background()
+	for _, id := range []string{"game-ok", "game-gone", "game-fail"} {
+		if err := service.Enqueue(ctx, domain.SyncQueueKindGamePush, id); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	if err := service.Enqueue(ctx, domain.SyncQueueKindMemoUpload, "memo-1"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	var calls []string
+	service.SetHandler(domain.SyncQueueKindGamePush, func(_ context.Context, id string) error {
+		calls = append(calls, id)
+		switch id {
+		case "game-gone":
+			return ErrSyncTargetGone
+		case "game-fail":
+			return errors.New("timeout")
+		}
+		return nil
+	})
+	service.SetHandler(domain.SyncQueueKindMemoUpload, func(_ context.Context, id string) error {
+		calls = append(calls, id)
+		return nil
+	})
+
+	res, err := service.Replay(ctx)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if want := (SyncQueueReplayResult{Replayed: 2, Dropped: 1, Failed: 1, Remaining: 1}); res != want {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+	if len(calls) != 4 || calls[0] != "game-ok" || calls[3] != "memo-1" {
+		t.Fatalf("expected items to be replayed in order, got %v", calls)
+	}
+	items, _ := service.List(ctx)
+	if len(items) != 1 || items[0].TargetID != "game-fail" || items[0].Attempts != 1 || items[0].LastError == nil {
+		t.Fatalf("expected failed item to remain with its error, got %#v", items)
+	}
+}
+
+func TestSyncQueueServiceReplayStopsWhenOfflineAgain(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeSyncQueueRepository{}
+	service := NewSyncQueueService(repo, newTestLogger())
+	ctx := context.Background()
+	for _, id := range []string{"game-1", "game-2"} {
+		if err := service.Enqueue(ctx, domain.SyncQueueKindGamePush, id); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	calls := 0
+	service.SetHandler(domain.SyncQueueKindGamePush, func(_ context.Context, _ string) error {
+		calls++
+		return ErrOffline
+	})
+
+	res, err := service.Replay(ctx)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if calls != 1 || res.Remaining != 2 || res.Failed != 0 {
+		t.Fatalf("expected replay to stop at the first offline error, got calls=%d result=%#v", calls, res)
+	}
+	items, _ := service.List(ctx)
+	if len(items) != 2 || items[0].Attempts != 0 {
+		t.Fatalf("expected queue to be untouched, got %#v", items)
+	}
+}
+
+func TestSyncQueueServiceReplayKeepsItemReenqueuedDuringSend(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeSyncQueueRepository{}
+	service := NewSyncQueueService(repo, newTestLogger())
+	ctx := context.Background()
+	if err := service.Enqueue(ctx, domain.SyncQueueKindGamePush, "game-1"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	service.SetHandler(domain.SyncQueueKindGamePush, func(ctx context.Context, id string) error {
+		return service.Enqueue(ctx, domain.SyncQueueKindGamePush, id)
+	})
+
+	res, err := service.Replay(ctx)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if res.Replayed != 1 || res.Remaining != 1 {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+	if items, _ := service.List(ctx); len(items) != 1 {
+		t.Fatalf("expected re-enqueued item to remain, got %#v", items)
+	}
+}
+
+func TestSyncQueueServiceEnqueueRejectsEmptyTarget(t *testing.T) {
+	t.Parallel()
+
+	service := NewSyncQueueService(&fakeSyncQueueRepository{}, newTestLogger())
+	if err := service.Enqueue(context.Background(), domain.SyncQueueKindGamePush, "  "); err == nil {
+		t.Fatal("expected error for empty target")
+	}
+}
@@ -0,0 +1,53 @@
+// 直近の処理速度から残り時間を見積もる移動窓の推定器を提供する。
+package services
+
+import "time"
+
+// defaultThroughputWindow は処理速度の計算に使う直近の区間。
+// 回線状況の変化や、小さいファイルと大きいファイルが混在する転送でも見積もりが追従するようにする。
+const defaultThroughputWindow = 20 * time.Second
+
+type throughputSample struct {
+	at   time.Time
+	done float64
+}
+
+// throughputEstimator は「時刻 → 累計処理量」の観測を移動窓で保持し、窓内の速度から残り時間を見積もる。
+// 窓の始点をまたぐ観測を1件残すため、観測間隔が窓より長くても直近2点の速度で見積もれる。
+type throughputEstimator struct {
+	window  time.Duration
+	samples []throughputSample
+}
+
+func newThroughputEstimator() *throughputEstimator {
+	return &throughputEstimator{window: defaultThroughputWindow}
+}
+
+// observe は時刻 at 時点の累計処理量 done を記録する。
+func (estimator *throughputEstimator) observe(at time.Time, done float64) {
+	estimator.samples = append(estimator.samples, throughputSample{at: at, done: done})
+	cutoff := at.Add(-estimator.window)
+	drop := 0
+	for drop+1 < len(estimator.samples) && !estimator.samples[drop+1].at.After(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		estimator.samples = append(estimator.samples[:0], estimator.samples[drop:]...)
+	}
+}
+
+// remaining は残り処理量 left を窓内の速度で割った残り時間（秒）を返す。
+// 観測が足りない、または窓内で進捗が無い場合は見積もれないため 0 を返す。
+func (estimator *throughputEstimator) remaining(left float64) float64 {
+	if left <= 0 || len(estimator.samples) < 2 {
+		return 0
+	}
+	first := estimator.samples[0]
+	last := estimator.samples[len(estimator.samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	progressed := last.done - first.done
+	if elapsed <= 0 || progressed <= 0 {
+		return 0
+	}
+	return left * elapsed / progressed
+}
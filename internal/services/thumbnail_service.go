@@ -0,0 +1,195 @@
+// ゲーム画像サムネイルの生成（サイズ指定）と原寸画像からの再生成を提供する。
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+const (
+	// MinThumbnailShortEdgePx / MaxThumbnailShortEdgePx はサムネイル短辺の許容範囲。
+	MinThumbnailShortEdgePx = 64
+	MaxThumbnailShortEdgePx = 2160
+	// DefaultThumbnailShortEdgePx はサムネイル短辺の既定値。
+	DefaultThumbnailShortEdgePx = 200
+
+	thumbnailsDirName         = "thumbnails"
+	thumbnailOriginalsDirName = "originals"
+)
+
+// thumbnailFileRegex は取り込んだサムネイルのファイル名（<sha256>_<作品キー><拡張子>）。
+// 作品キーは批評空間なら ID（数字のみ）、DLSite・DMM なら "<取り込み元>_<作品 ID>"。
+// 同期で取得した画像（<sha256>_<ゲームID>）は原寸画像を持たないため一致させない。
+var thumbnailFileRegex = regexp.MustCompile(`^[0-9a-f]{64}_(\d+|[a-z]+_[A-Za-z0-9_]+)(\.(?:jpg|png|gif))$`)
+
+// ValidateThumbnailShortEdge はサムネイル短辺ピクセル数が許容範囲かを検証する。
+func ValidateThumbnailShortEdge(shortEdge int) error {
+	if shortEdge < MinThumbnailShortEdgePx || shortEdge > MaxThumbnailShortEdgePx {
+		return fmt.Errorf("thumbnailShortEdgePx must be %d-%d", MinThumbnailShortEdgePx, MaxThumbnailShortEdgePx)
+	}
+	return nil
+}
+
+// ThumbnailService はサムネイルの再生成を提供する。
+type ThumbnailService struct {
+	repository ThumbnailRepository
+	appDataDir string
+	logger     *slog.Logger
+}
+
+// NewThumbnailService は ThumbnailService を生成する。
+func NewThumbnailService(repository ThumbnailRepository, appDataDir string, logger *slog.Logger) *ThumbnailService {
+	return &ThumbnailService{repository: repository, appDataDir: appDataDir, logger: logger}
+}
+
+// RegenerateThumbnails は全ゲームの画像を原寸画像から shortEdge で描き直し、imagePath を差し替える。
+// 原寸画像が残っていない画像（同期で取得したもの・手動設定したもの・本機能以前に取り込んだもの）は
+// Skipped に数えてそのまま残す。アーカイブ済みのゲームは変更しないため、これも Skipped に数える。
+// 描き直しには時間がかかるため、書き込む直前にゲームを読み直し、その間のプレイ時間などの更新を上書きしない。
+// 差し替え後、どのゲームからも参照されなくなった旧サムネイルは削除する。
+func (service *ThumbnailService) RegenerateThumbnails(ctx context.Context, shortEdge int) (domain.ThumbnailRegenerationResult, error) {
+	if err := ValidateThumbnailShortEdge(shortEdge); err != nil {
+		return domain.ThumbnailRegenerationResult{}, newServiceError("サムネイルサイズが不正です", err.Error())
+	}
+	games, err := service.repository.ListGames(ctx, "", domain.GameFilterAll, "title", "asc")
+	if err != nil {
+		service.logger.Error("ゲーム一覧の取得に失敗", "error", err)
+		return domain.ThumbnailRegenerationResult{}, newServiceError("サムネイルの再生成に失敗しました", err.Error())
+	}
+
+	thumbnailsDir := filepath.Join(service.appDataDir, thumbnailsDirName)
+	result := domain.ThumbnailRegenerationResult{Failed: []domain.ThumbnailRegenerationFailed{}}
+	// rendered は同じ旧サムネイルを共有するゲームで描き直しを1回にするためのキャッシュ。
+	rendered := make(map[string]string)
+	stillReferenced := make(map[string]struct{})
+	replaced := make(map[string]struct{})
+	for _, game := range games {
+		if game.ImagePath == nil || strings.TrimSpace(*game.ImagePath) == "" {
+			continue
+		}
+		oldPath := *game.ImagePath
+		if game.IsArchived() {
+			result.Skipped++
+			stillReferenced[oldPath] = struct{}{}
+			continue
+		}
+		newPath, ok := rendered[oldPath]
+		if !ok {
+			newPath, err = service.regenerate(thumbnailsDir, oldPath, shortEdge)
+			if errors.Is(err, os.ErrNotExist) {
+				result.Skipped++
+				stillReferenced[oldPath] = struct{}{}
+				continue
+			}
+			if err != nil {
+				service.logger.Warn("サムネイルの再生成に失敗", "gameId", game.ID, "path", oldPath, "error", err)
+				result.Failed = append(result.Failed, domain.ThumbnailRegenerationFailed{GameID: game.ID, Message: err.Error()})
+				stillReferenced[oldPath] = struct{}{}
+				continue
+			}
+			rendered[oldPath] = newPath
+		}
+		if newPath != oldPath {
+			current, err := service.repository.GetGameByID(ctx, game.ID)
+			if err != nil {
+				service.logger.Warn("サムネイルパスの更新に失敗", "gameId", game.ID, "error", err)
+				result.Failed = append(result.Failed, domain.ThumbnailRegenerationFailed{GameID: game.ID, Message: err.Error()})
+				stillReferenced[oldPath] = struct{}{}
+				continue
+			}
+			// 描き直しの間に削除・画像の変更・アーカイブがあったゲームは触らない。
+			if current == nil || current.ImagePath == nil || *current.ImagePath != oldPath {
+				continue
+			}
+			if current.IsArchived() {
+				result.Skipped++
+				stillReferenced[oldPath] = struct{}{}
+				continue
+			}
+			current.ImagePath = &newPath
+			if _, err := service.repository.UpdateGame(ctx, *current); err != nil {
+				service.logger.Warn("サムネイルパスの更新に失敗", "gameId", game.ID, "error", err)
+				result.Failed = append(result.Failed, domain.ThumbnailRegenerationFailed{GameID: game.ID, Message: err.Error()})
+				stillReferenced[oldPath] = struct{}{}
+				continue
+			}
+			replaced[oldPath] = struct{}{}
+		}
+		result.Regenerated++
+	}
+
+	for oldPath := range replaced {
+		if _, ok := stillReferenced[oldPath]; ok {
+			continue
+		}
+		if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+			service.logger.Warn("旧サムネイルの削除に失敗", "path", oldPath, "error", err)
+		}
+	}
+	return result, nil
+}
+
+// regenerate は imagePath に対応する原寸画像から新しいサムネイルを作り、そのパスを返す。
+// 原寸画像が無い場合は os.ErrNotExist を返す。
+func (service *ThumbnailService) regenerate(thumbnailsDir string, imagePath string, shortEdge int) (string, error) {
+	if filepath.Dir(filepath.Clean(imagePath)) != filepath.Clean(thumbnailsDir) {
+		return "", os.ErrNotExist
+	}
+	matches := thumbnailFileRegex.FindStringSubmatch(filepath.Base(imagePath))
+	if matches == nil {
+		return "", os.ErrNotExist
+	}
+	imageKey, ext := matches[1], matches[2]
+	raw, err := os.ReadFile(thumbnailOriginalPath(thumbnailsDir, imageKey, ext))
+	if err != nil {
+		return "", err
+	}
+	return renderThumbnail(thumbnailsDir, imageKey, ext, raw, shortEdge)
+}
+
+func thumbnailOriginalPath(thumbnailsDir string, imageKey string, ext string) string {
+	return filepath.Join(thumbnailsDir, thumbnailOriginalsDirName, imageKey+ext)
+}
+
+// saveThumbnailOriginal は縮小前の画像を thumbnails/originals/<作品キー><拡張子> に保存する。
+func saveThumbnailOriginal(thumbnailsDir string, imageKey string, ext string, raw []byte) error {
+	originalPath := thumbnailOriginalPath(thumbnailsDir, imageKey, ext)
+	if err := os.MkdirAll(filepath.Dir(originalPath), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(originalPath, raw, 0o600)
+}
+
+// renderThumbnail は raw を短辺 shortEdge に縮小して thumbnailsDir に保存し、そのパスを返す。
+// ファイル名は内容のハッシュを含むため、サイズが変われば別ファイルになる。
+func renderThumbnail(thumbnailsDir string, imageKey string, ext string, raw []byte, shortEdge int) (string, error) {
+	decoded, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+	var encoded bytes.Buffer
+	if err := encodeImage(&encoded, resizeToShortEdge(decoded, shortEdge), ext); err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(encoded.Bytes())
+	if err := os.MkdirAll(thumbnailsDir, 0o700); err != nil {
+		return "", err
+	}
+	fullPath := filepath.Join(thumbnailsDir, fmt.Sprintf("%s_%s%s", hex.EncodeToString(hash[:]), imageKey, ext))
+	if err := os.WriteFile(fullPath, encoded.Bytes(), 0o600); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decodeTestImageSize(t *testing.T, path string) (int, int) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read image: %v", err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode image: %v", err)
+	}
+	return cfg.Width, cfg.Height
+}
+
+func TestThumbnailServiceRegenerateThumbnailsRendersFromOriginals(t *testing.T) {
+	t.Parallel()
+
+	appDataDir := t.TempDir()
+	thumbnailsDir := filepath.Join(appDataDir, thumbnailsDirName)
+	raw := encodeTestPNG(t, 400, 600)
+	if err := saveThumbnailOriginal(thumbnailsDir, "12345", ".png", raw); err != nil {
+		t.Fatalf("save original: %v", err)
+	}
+	oldPath, err := renderThumbnail(thumbnailsDir, "12345", ".png", raw, 100)
+	if err != nil {
+		t.Fatalf("render thumbnail: %v", err)
+	}
+	// 同期で取得した画像は原寸画像が無いのでスキップされる。
+	pulledPath := filepath.Join(thumbnailsDir, "abc_game-2.png")
+	if err := os.WriteFile(pulledPath, encodeTestPNG(t, 10, 10), 0o600); err != nil {
+		t.Fatalf("write pulled image: %v", err)
+	}
+
+	// アーカイブ済みのゲームは原寸画像があっても描き直さない。
+	if err := saveThumbnailOriginal(thumbnailsDir, "67890", ".png", raw); err != nil {
+		t.Fatalf("save original: %v", err)
+	}
+	archivedPath, err := renderThumbnail(thumbnailsDir, "67890", ".png", raw, 100)
+	if err != nil {
+		t.Fatalf("render thumbnail: %v", err)
+	}
+	archivedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	games := []domain.Game{
+		{ID: "game-1", ImagePath: &oldPath},
+		{ID: "game-2", ImagePath: &pulledPath},
+		{ID: "game-3"},
+		{ID: "game-4", ImagePath: &archivedPath, ArchivedAt: &archivedAt},
+	}
+	updated := map[string]domain.Game{}
+	repository := fakeGameRepository{
+		listGamesFn: func(ctx context.Context, searchText string, filter domain.PlayStatus, sortBy string, sortDirection string) ([]domain.Game, error) {
+			return games, nil
+		},
+		getGameByIDFn: func(ctx context.Context, gameID string) (*domain.Game, error) {
+			// 一覧の取得後にプロセス監視がプレイ時間を書き込んだ状態を返す。
+			for _, game := range games {
+				if game.ID == gameID {
+					game.TotalPlayTime = 120
+					return &game, nil
+				}
+			}
+			return nil, nil
+		},
+		updateGameFn: func(ctx context.Context, game domain.Game) (*domain.Game, error) {
+			updated[game.ID] = game
+			return &game, nil
+		},
+	}
+	service := NewThumbnailService(repository, appDataDir, newTestLogger())
+
+	result, err := service.RegenerateThumbnails(context.Background(), 300)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if result.Regenerated != 1 || result.Skipped != 2 || len(result.Failed) != 0 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if len(updated) != 1 || updated["game-1"].ImagePath == nil || *updated["game-1"].ImagePath == oldPath {
+		t.Fatalf("expected only game-1 image path to be replaced, got %#v", updated)
+	}
+	if updated["game-1"].TotalPlayTime != 120 {
+		t.Fatalf("expected the latest row to be written back, got %#v", updated["game-1"])
+	}
+	newPath := *updated["game-1"].ImagePath
+	if width, height := decodeTestImageSize(t, newPath); width != 300 || height != 450 {
+		t.Fatalf("expected 300x450 thumbnail, got %dx%d", width, height)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Fatalf("expected old thumbnail to be removed, stat err=%v", err)
+	}
+	if _, err := os.Stat(archivedPath); err != nil {
+		t.Fatalf("expected the archived game's thumbnail to be kept: %v", err)
+	}
+	if _, err := os.Stat(pulledPath); err != nil {
+		t.Fatalf("expected skipped image to be kept: %v", err)
+	}
+}
+
+func TestThumbnailServiceRegenerateThumbnailsRejectsInvalidSize(t *testing.T) {
+	t.Parallel()
+
+	service := NewThumbnailService(fakeGameRepository{}, t.TempDir(), newTestLogger())
+	if _, err := service.RegenerateThumbnails(context.Background(), MinThumbnailShortEdgePx-1); err == nil {
+		t.Fatalf("expected invalid size error")
+	}
+}
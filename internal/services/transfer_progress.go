@@ -0,0 +1,125 @@
+// セーブファイル転送の進捗（ファイル数・バイト数・残り時間）の集計を提供する。
+package services
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+// TransferProgressFunc はセーブファイル転送の進捗を報告するコールバック。
+type TransferProgressFunc func(progress domain.TransferProgress)
+
+// transferTracker は並列転送の完了通知を集計して TransferProgressFunc へ流す。
+// 転送不要だったファイル（skip）は件数にだけ数え、残り時間の見積もりには使わない。
+type transferTracker struct {
+	mu          sync.Mutex
+	emit        TransferProgressFunc
+	now         func() time.Time
+	progress    domain.TransferProgress
+	skipped     int
+	transferred int64
+	throughput  *throughputEstimator
+}
+
+// newTransferTracker は filesTotal 件・bytesTotal バイト（不明なら 0）の転送を集計する tracker を返す。
+// emit が nil の場合は nil を返し、各メソッドは何もしない。
+func newTransferTracker(emit TransferProgressFunc, filesTotal int, bytesTotal int64) *transferTracker {
+	if emit == nil {
+		return nil
+	}
+	return &transferTracker{
+		emit:       emit,
+		now:        time.Now,
+		progress:   domain.TransferProgress{FilesTotal: filesTotal, BytesTotal: bytesTotal},
+		throughput: newThroughputEstimator(),
+	}
+}
+
+// start は経過時間の計測を始め、初期状態を通知する。
+func (tracker *transferTracker) start() {
+	if tracker == nil {
+		return
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.throughput.observe(tracker.now(), 0)
+	tracker.emit(tracker.progress)
+}
+
+// fileDone はファイル1件の完了を記録して通知する。skipped は転送不要だったことを表す。
+func (tracker *transferTracker) fileDone(name string, size int64, skipped bool) {
+	if tracker == nil {
+		return
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.progress.CurrentFile = name
+	tracker.progress.FilesDone++
+	tracker.progress.BytesDone += size
+	if skipped {
+		tracker.skipped++
+	} else {
+		tracker.transferred += size
+		tracker.throughput.observe(tracker.now(), tracker.transferredUnits())
+	}
+	tracker.progress.EtaSeconds = tracker.estimateRemaining()
+	tracker.emit(tracker.progress)
+}
+
+// skip は転送前に一致が分かっているファイルをまとめて完了扱いにする（通知は start で行う）。
+func (tracker *transferTracker) skip(count int) {
+	if tracker == nil {
+		return
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.skipped += count
+	tracker.progress.FilesDone += count
+}
+
+// transferredUnits は速度の計算に使う累計転送量。総バイト数が分かればバイト数、分からなければファイル数。
+func (tracker *transferTracker) transferredUnits() float64 {
+	if tracker.progress.BytesTotal > 0 {
+		return float64(tracker.transferred)
+	}
+	return float64(tracker.progress.FilesDone - tracker.skipped)
+}
+
+// estimateRemaining は直近の転送速度（移動窓）から残り時間（秒）を見積もる。
+// 総バイト数が分かればバイト単位、分からなければファイル単位で按分する。
+func (tracker *transferTracker) estimateRemaining() float64 {
+	if tracker.progress.BytesTotal > 0 {
+		return tracker.throughput.remaining(float64(tracker.progress.BytesTotal - tracker.progress.BytesDone))
+	}
+	return tracker.throughput.remaining(float64(tracker.progress.FilesTotal - tracker.progress.FilesDone))
+}
+
+// savePackDisplayName はセーブを zip にまとめてアップロードするときの進捗表示名。
+const savePackDisplayName = "saves.zip"
+
+func sumBlobSizes(blobs map[string][]byte) int64 {
+	var total int64
+	for _, data := range blobs {
+		total += int64(len(data))
+	}
+	return total
+}
+
+// blobDisplayNames はセーブスナップショットからハッシュ → 表示用の相対パスを作る。
+// 同じ内容のファイルが複数ある場合は辞書順で最初のパスを使う。
+func blobDisplayNames(saveSnapJSON []byte) map[string]string {
+	names := make(map[string]string)
+	var snapshot domain.SaveSnapshot
+	if err := json.Unmarshal(saveSnapJSON, &snapshot); err != nil {
+		return names
+	}
+	for relPath, hash := range snapshot.Files {
+		if current, ok := names[hash]; !ok || relPath < current {
+			names[hash] = relPath
+		}
+	}
+	return names
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/domain"
+)
+
+func TestTransferTrackerEstimatesRemainingFromTransferredBytes(t *testing.T) {
+	t.Parallel()
+
+	var events []domain.TransferProgress
+	tracker := newTransferTracker(func(progress domain.TransferProgress) {
+		events = append(events, progress)
+	}, 3, 300)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return clock }
+
+	tracker.start()
+	// 転送不要だった分は速度の計算に含めない。
+	tracker.fileDone("a.dat", 100, true)
+	clock = clock.Add(10 * time.Second)
+	tracker.fileDone("b.dat", 100, false)
+
+	last := events[len(events)-1]
+	if last.FilesDone != 2 || last.BytesDone != 200 || last.CurrentFile != "b.dat" {
+		t.Fatalf("unexpected progress: %+v", last)
+	}
+	if last.EtaSeconds != 10 {
+		t.Fatalf("expected 10s remaining, got %v", last.EtaSeconds)
+	}
+}
+
+func TestTransferTrackerFallsBackToFileCountWithoutByteTotal(t *testing.T) {
+	t.Parallel()
+
+	var last domain.TransferProgress
+	tracker := newTransferTracker(func(progress domain.TransferProgress) { last = progress }, 4, 0)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return clock }
+
+	tracker.skip(2)
+	tracker.start()
+	if last.FilesDone != 2 || last.EtaSeconds != 0 {
+		t.Fatalf("unexpected initial progress: %+v", last)
+	}
+	clock = clock.Add(6 * time.Second)
+	tracker.fileDone("c.dat", 50, false)
+	if last.EtaSeconds != 6 {
+		t.Fatalf("expected 6s remaining, got %v", last.EtaSeconds)
+	}
+
+	if newTransferTracker(nil, 1, 0) != nil {
+		t.Fatal("nil emitter should disable tracking")
+	}
+}
+
+func TestTransferTrackerFollowsRecentThroughput(t *testing.T) {
+	t.Parallel()
+
+	var last domain.TransferProgress
+	tracker := newTransferTracker(func(progress domain.TransferProgress) { last = progress }, 10, 1000)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return clock }
+
+	tracker.start()
+	// 最初は 1 秒に 1 バイトしか進まないが、窓から外れた後は直近の速度で見積もる。
+	clock = clock.Add(100 * time.Second)
+	tracker.fileDone("slow.dat", 100, false)
+	for i := 0; i < 5; i++ {
+		clock = clock.Add(10 * time.Second)
+		tracker.fileDone("fast.dat", 100, false)
+	}
+	// 直近 20 秒で 200 バイト → 残り 400 バイトは 40 秒。
+	if last.EtaSeconds != 40 {
+		t.Fatalf("expected 40s remaining, got %v", last.EtaSeconds)
+	}
+}
@@ -0,0 +1,432 @@
+// ユーザーが設定した URL へ、プレイ終了・クリア・同期完了を JSON で POST する Webhook を提供する。
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+	"CloudLaunch_Go/internal/infrastructure/httpclient"
+	"CloudLaunch_Go/internal/logging"
+)
+
+// webhooksSettingKey は Settings テーブル上で Webhook の一覧（JSON）を保存するキー。
+const webhooksSettingKey = "webhooks"
+
+const (
+	// webhookAttemptTimeout は1回の POST の上限。
+	webhookAttemptTimeout = 10 * time.Second
+	// webhookMaxRetries は通信エラー・429・5xx のときに送り直す回数。間隔は httpclient の指数バックオフ。
+	webhookMaxRetries = 3
+	// webhookDeliveryTimeout は送り直しを含めた1件の送信の上限。
+	webhookDeliveryTimeout = time.Minute
+	// maxWebhooks は登録できる Webhook の数。
+	maxWebhooks = 20
+	// maxWebhookTemplateBytes は本文テンプレートの長さの上限。
+	maxWebhookTemplateBytes = 16 * 1024
+)
+
+// WebhookService は Webhook の設定を保存し、イベントバスの出来事に合わせて送信する。
+// 送信はバックグラウンドで行い、失敗はログに残すだけにする（ゲームの記録や同期は止めない）。
+type WebhookService struct {
+	repository WebhookRepository
+	logger     *slog.Logger
+	now        func() time.Time
+	// tasks は送信 goroutine を数える（nil なら追跡しない）。アプリの終了時に送信中のものを待つ。
+	tasks *BackgroundTasks
+	// client・ctx・unsubscribe は mu で保護する。ctx は Start で受け取ったアプリのコンテキスト。
+	mu          sync.Mutex
+	client      *http.Client
+	ctx         context.Context
+	unsubscribe []func()
+}
+
+// NewWebhookService は WebhookService を生成する。プロキシは cfg の HTTP 通信設定に従う。
+func NewWebhookService(cfg config.Config, repository WebhookRepository, logger *slog.Logger) *WebhookService {
+	service := &WebhookService{repository: repository, logger: logger, now: time.Now}
+	if err := service.SetProxyURL(cfg.HTTPProxyURL); err != nil {
+		logger.Warn("Webhook のプロキシ設定が不正です（環境変数のプロキシを使用）", "error", err)
+		_ = service.SetProxyURL("")
+	}
+	return service
+}
+
+// SetProxyURL は送信に使うプロキシを切り替える。空なら環境変数（HTTPS_PROXY 等）に従う。
+func (service *WebhookService) SetProxyURL(proxyURL string) error {
+	client, err := httpclient.New(httpclient.Options{
+		Timeout:    webhookAttemptTimeout,
+		ProxyURL:   proxyURL,
+		MaxRetries: webhookMaxRetries,
+	})
+	if err != nil {
+		return err
+	}
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.client = client
+	return nil
+}
+
+// SetBackgroundTasks は送信 goroutine を数える先を設定する。Close された後は新しい送信を始めない。
+func (service *WebhookService) SetBackgroundTasks(tasks *BackgroundTasks) {
+	service.tasks = tasks
+}
+
+// Start は bus のイベントの購読を始める。ctx はアプリの終了で取り消されるコンテキストで、送信はこれを親にする。
+// 既に購読中なら何もしない。
+func (service *WebhookService) Start(ctx context.Context, bus *EventBus) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.unsubscribe != nil {
+		return
+	}
+	service.ctx = ctx
+	service.unsubscribe = []func(){
+		bus.Subscribe(EventSessionCreated, service.handleSessionCreated),
+		bus.Subscribe(EventGameUpdated, service.handleGameUpdated),
+		bus.Subscribe(EventSyncCompleted, service.handleSyncCompleted),
+	}
+}
+
+// Close はイベントの購読をやめる。送信中のものは止めない。多重呼び出しは安全。
+func (service *WebhookService) Close() {
+	service.mu.Lock()
+	unsubscribe := service.unsubscribe
+	service.unsubscribe = nil
+	service.mu.Unlock()
+	for _, fn := range unsubscribe {
+		fn()
+	}
+}
+
+// Wait は送信中の Webhook が終わるまで待つ。Close の後に呼ぶ。
+func (service *WebhookService) Wait() {
+	service.tasks.Wait()
+}
+
+// ListWebhooks は保存済みの Webhook を返す。
+func (service *WebhookService) ListWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	raw, err := service.repository.GetSetting(ctx, webhooksSettingKey)
+	if err != nil {
+		service.logger.Error("Webhook 設定の取得に失敗", "error", err)
+		return nil, newServiceError("Webhook 設定の取得に失敗しました", err.Error())
+	}
+	webhooks := make([]domain.Webhook, 0)
+	if strings.TrimSpace(raw) == "" {
+		return webhooks, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &webhooks); err != nil {
+		service.logger.Error("Webhook 設定の解析に失敗", "error", err)
+		return nil, newServiceError("Webhook 設定の取得に失敗しました", err.Error())
+	}
+	return webhooks, nil
+}
+
+// SaveWebhooks は Webhook の一覧を検証して丸ごと置き換え、保存した一覧を返す。ID が空のものには新しい ID を振る。
+func (service *WebhookService) SaveWebhooks(ctx context.Context, webhooks []domain.Webhook) ([]domain.Webhook, error) {
+	if len(webhooks) > maxWebhooks {
+		return nil, newServiceError("Webhook の設定が不正です", fmt.Sprintf("Webhook は %d 件までです", maxWebhooks))
+	}
+	normalized := make([]domain.Webhook, 0, len(webhooks))
+	seen := make(map[string]struct{}, len(webhooks))
+	for index, webhook := range webhooks {
+		webhook, err := normalizeWebhook(webhook)
+		if err != nil {
+			service.logger.Warn("Webhook の設定が不正です", "index", index, "error", err)
+			return nil, newServiceError("Webhook の設定が不正です", fmt.Sprintf("%d 件目: %s", index+1, err.Error()))
+		}
+		if webhook.ID == "" {
+			if webhook.ID, err = newWebhookID(); err != nil {
+				return nil, newServiceError("Webhook 設定の保存に失敗しました", err.Error())
+			}
+		}
+		if _, ok := seen[webhook.ID]; ok {
+			return nil, newServiceError("Webhook の設定が不正です", "ID が重複しています: "+webhook.ID)
+		}
+		seen[webhook.ID] = struct{}{}
+		normalized = append(normalized, webhook)
+	}
+
+	payload, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, newServiceError("Webhook 設定の保存に失敗しました", err.Error())
+	}
+	if err := service.repository.UpsertSetting(ctx, webhooksSettingKey, string(payload)); err != nil {
+		service.logger.Error("Webhook 設定の保存に失敗", "error", err)
+		return nil, newServiceError("Webhook 設定の保存に失敗しました", err.Error())
+	}
+	service.logger.Info("Webhook 設定を保存", "count", len(normalized))
+	return normalized, nil
+}
+
+// SendTest は保存済みの Webhook へ見本の内容をすぐに送り、結果を返す（設定画面の「テスト送信」用）。
+// 無効にしている Webhook やきっかけに含まれない内容でも送る。
+func (service *WebhookService) SendTest(ctx context.Context, webhookID string) error {
+	trimmedID, detail, ok := requireNonEmpty(webhookID, "webhookID")
+	if !ok {
+		return newServiceError("IDが不正です", detail)
+	}
+	webhooks, err := service.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(webhooks, func(webhook domain.Webhook) bool { return webhook.ID == trimmedID })
+	if index < 0 {
+		return newServiceError("Webhook が見つかりません", trimmedID)
+	}
+	webhook := webhooks[index]
+	event := domain.WebhookEventSessionEnded
+	if len(webhook.Events) > 0 {
+		event = webhook.Events[0]
+	}
+	sample := sampleWebhookPayload(event, service.now())
+	if err := service.deliver(ctx, webhook, sample); err != nil {
+		service.logger.Warn("Webhook のテスト送信に失敗", "webhookId", webhook.ID, "error", err)
+		return newServiceError("Webhook の送信に失敗しました", err.Error())
+	}
+	return nil
+}
+
+func (service *WebhookService) handleSessionCreated(event Event) {
+	created, ok := event.Payload.(SessionCreatedEvent)
+	// 手動で追加した過去のセッションは「プレイの終了」ではないため送らない。
+	if !ok || !created.Auto {
+		return
+	}
+	session := created.Session
+	service.dispatchAsync(domain.WebhookPayload{
+		Event:     domain.WebhookEventSessionEnded,
+		Timestamp: event.At,
+		GameID:    session.GameID,
+		Session:   &session,
+	})
+}
+
+func (service *WebhookService) handleGameUpdated(event Event) {
+	updated, ok := event.Payload.(GameUpdatedEvent)
+	if !ok || !updated.Cleared || updated.Game == nil {
+		return
+	}
+	service.dispatchAsync(domain.WebhookPayload{
+		Event:     domain.WebhookEventGameCleared,
+		Timestamp: event.At,
+		GameID:    updated.GameID,
+		Game:      updated.Game,
+	})
+}
+
+func (service *WebhookService) handleSyncCompleted(event Event) {
+	completed, ok := event.Payload.(SyncCompletedEvent)
+	if !ok {
+		return
+	}
+	service.dispatchAsync(domain.WebhookPayload{
+		Event:     domain.WebhookEventSyncFinished,
+		Timestamp: event.At,
+		GameID:    completed.GameID,
+		Sync: &domain.WebhookSyncResult{
+			Direction: completed.Direction,
+			Applied:   completed.Applied,
+			Error:     completed.Error,
+		},
+	})
+}
+
+// dispatchAsync は payload のきっかけを購読している有効な Webhook へ、バックグラウンドで送る。
+// イベントバスの配信を止めないよう、設定の読み込みから goroutine で行う。
+func (service *WebhookService) dispatchAsync(payload domain.WebhookPayload) {
+	service.mu.Lock()
+	parent := service.ctx
+	service.mu.Unlock()
+	if parent == nil {
+		parent = context.Background()
+	}
+	started := service.tasks.Go(func() {
+		defer logging.Recover(service.logger, "webhook.dispatch")
+		ctx, cancel := context.WithTimeout(parent, webhookDeliveryTimeout)
+		defer cancel()
+		service.dispatch(ctx, payload)
+	})
+	if !started {
+		service.logger.Debug("終了処理中のため Webhook を送信しません", "event", payload.Event, "gameId", payload.GameID)
+	}
+}
+
+func (service *WebhookService) dispatch(ctx context.Context, payload domain.WebhookPayload) {
+	webhooks, err := service.ListWebhooks(ctx)
+	if err != nil {
+		return
+	}
+	targets := slices.DeleteFunc(webhooks, func(webhook domain.Webhook) bool {
+		return !webhook.Enabled || !slices.Contains(webhook.Events, payload.Event)
+	})
+	if len(targets) == 0 {
+		return
+	}
+	if payload.Game == nil && payload.GameID != "" {
+		game, err := service.repository.GetGameByID(ctx, payload.GameID)
+		if err != nil {
+			service.logger.Warn("Webhook 用のゲーム取得に失敗", "gameId", payload.GameID, "error", err)
+		}
+		payload.Game = game
+	}
+	if payload.Game != nil {
+		payload.GameTitle = payload.Game.Title
+	}
+
+	var wg sync.WaitGroup
+	for _, webhook := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer logging.Recover(service.logger, "webhook.deliver")
+			if err := service.deliver(ctx, webhook, payload); err != nil {
+				service.logger.Warn("Webhook の送信に失敗", "webhookId", webhook.ID, "name", webhook.Name, "event", payload.Event, "error", err)
+				return
+			}
+			service.logger.Info("Webhook を送信", "webhookId", webhook.ID, "name", webhook.Name, "event", payload.Event)
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver は webhook の本文を組み立てて POST する。再試行は HTTP クライアントが行い、2xx 以外は失敗とする。
+func (service *WebhookService) deliver(ctx context.Context, webhook domain.Webhook, payload domain.WebhookPayload) error {
+	body, err := renderWebhookBody(webhook.BodyTemplate, payload)
+	if err != nil {
+		return err
+	}
+	// bytes.Reader の本文は GetBody が設定され、再試行時に送り直せる。
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-CloudLaunch-Event", string(payload.Event))
+
+	service.mu.Lock()
+	client := service.client
+	service.mu.Unlock()
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", response.Status)
+	}
+	return nil
+}
+
+// renderWebhookBody は本文テンプレートで payload を JSON に組み立てる。テンプレートが空なら payload をそのまま JSON にする。
+func renderWebhookBody(bodyTemplate string, payload domain.WebhookPayload) ([]byte, error) {
+	if strings.TrimSpace(bodyTemplate) == "" {
+		return json.Marshal(payload)
+	}
+	parsed, err := parseWebhookTemplate(bodyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := parsed.Execute(&buffer, payload); err != nil {
+		return nil, fmt.Errorf("本文テンプレートの実行に失敗: %w", err)
+	}
+	if !json.Valid(buffer.Bytes()) {
+		return nil, errors.New("本文テンプレートの出力が JSON ではありません")
+	}
+	return buffer.Bytes(), nil
+}
+
+// parseWebhookTemplate は本文テンプレートを解析する。値を JSON として埋め込む json 関数を使える。
+func parseWebhookTemplate(bodyTemplate string) (*template.Template, error) {
+	parsed, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(value any) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+	}).Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("本文テンプレートが不正です: %w", err)
+	}
+	return parsed, nil
+}
+
+// normalizeWebhook は名前・URL・きっかけ・本文テンプレートを検証し、前後の空白を除いた設定を返す。
+func normalizeWebhook(webhook domain.Webhook) (domain.Webhook, error) {
+	webhook.ID = strings.TrimSpace(webhook.ID)
+	webhook.Name = strings.TrimSpace(webhook.Name)
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.Webhook{}, errors.New("URL は http または https の絶対URLにしてください")
+	}
+	if webhook.Name == "" {
+		webhook.Name = parsed.Host
+	}
+	if len(webhook.Events) == 0 {
+		return domain.Webhook{}, errors.New("送信するきっかけを1つ以上選んでください")
+	}
+	events := make([]domain.WebhookEvent, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		if !slices.Contains(domain.WebhookEvents, event) {
+			return domain.Webhook{}, fmt.Errorf("きっかけが不正です: %s", event)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	webhook.Events = events
+	if len(webhook.BodyTemplate) > maxWebhookTemplateBytes {
+		return domain.Webhook{}, fmt.Errorf("本文テンプレートは %d バイトまでです", maxWebhookTemplateBytes)
+	}
+	// 保存時に見本の内容で組み立ててみて、送信時まで誤りに気付かないことを防ぐ。
+	for _, event := range webhook.Events {
+		if _, err := renderWebhookBody(webhook.BodyTemplate, sampleWebhookPayload(event, time.Now())); err != nil {
+			return domain.Webhook{}, err
+		}
+	}
+	return webhook, nil
+}
+
+// sampleWebhookPayload はテスト送信と保存時の検証に使う、event の見本の内容を返す。
+func sampleWebhookPayload(event domain.WebhookEvent, now time.Time) domain.WebhookPayload {
+	sessionName := "自動記録 - sample.exe"
+	clearedAt := now
+	game := &domain.Game{ID: "sample-game", Title: "サンプルゲーム", PlayStatus: domain.PlayStatusPlaying, TotalPlayTime: 3600, LastPlayed: &now}
+	payload := domain.WebhookPayload{Event: event, Timestamp: now, GameID: game.ID, GameTitle: game.Title, Game: game}
+	switch event {
+	case domain.WebhookEventSessionEnded:
+		payload.Session = &domain.PlaySession{ID: "sample-session", GameID: game.ID, PlayedAt: now, Duration: 1800, SessionName: &sessionName}
+	case domain.WebhookEventGameCleared:
+		game.PlayStatus = domain.PlayStatusPlayed
+		game.ClearedAt = &clearedAt
+	case domain.WebhookEventSyncFinished:
+		payload.Sync = &domain.WebhookSyncResult{Direction: OperationPush, Applied: true}
+	}
+	return payload
+}
+
+// newWebhookID はランダムな 64bit の Webhook ID を16進文字列で生成する。
+func newWebhookID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("Webhook ID の生成に失敗: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
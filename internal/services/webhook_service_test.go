@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"CloudLaunch_Go/internal/config"
+	"CloudLaunch_Go/internal/domain"
+)
+
+type fakeWebhookRepository struct {
+	fakeSettingsRepository
+	games map[string]*domain.Game
+}
+
+func (repository *fakeWebhookRepository) GetGameByID(_ context.Context, gameID string) (*domain.Game, error) {
+	return repository.games[gameID], nil
+}
+
+func newTestWebhookService(t *testing.T, webhooks ...domain.Webhook) (*WebhookService, *EventBus, *BackgroundTasks) {
+	t.Helper()
+	repo := &fakeWebhookRepository{games: map[string]*domain.Game{"game-1": {ID: "game-1", Title: "テストゲーム"}}}
+	service := NewWebhookService(config.Config{}, repo, newTestLogger())
+	if _, err := service.SaveWebhooks(context.Background(), webhooks); err != nil {
+		t.Fatalf("SaveWebhooks failed: %v", err)
+	}
+	tasks := NewBackgroundTasks()
+	service.SetBackgroundTasks(tasks)
+	bus := NewEventBus(newTestLogger())
+	service.Start(context.Background(), bus)
+	t.Cleanup(service.Close)
+	return service, bus, tasks
+}
+
+func TestWebhookServicePostsDefaultPayloadForAutoSession(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(chan []byte, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-CloudLaunch-Event") != "session.ended" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer server.Close()
+
+	_, bus, tasks := newTestWebhookService(t, domain.Webhook{
+		Name: "home", URL: server.URL, Events: []domain.WebhookEvent{domain.WebhookEventSessionEnded}, Enabled: true,
+	})
+	bus.Publish(EventSessionCreated, SessionCreatedEvent{Session: domain.PlaySession{ID: "manual", GameID: "game-1"}})
+	bus.Publish(EventSessionCreated, SessionCreatedEvent{Session: domain.PlaySession{ID: "s-1", GameID: "game-1", Duration: 90}, Auto: true})
+	bus.Wait()
+	tasks.Wait()
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected only the auto session to be sent, got %d", len(bodies))
+	}
+	var payload domain.WebhookPayload
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if payload.Event != domain.WebhookEventSessionEnded || payload.GameTitle != "テストゲーム" ||
+		payload.Session == nil || payload.Session.ID != "s-1" || payload.Session.Duration != 90 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookServiceRendersTemplateAndFiltersEvents(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer server.Close()
+
+	_, bus, tasks := newTestWebhookService(t,
+		domain.Webhook{
+			URL:          server.URL,
+			Events:       []domain.WebhookEvent{domain.WebhookEventGameCleared},
+			BodyTemplate: `{"message": {{json (printf "%s をクリア" .GameTitle)}}}`,
+			Enabled:      true,
+		},
+		domain.Webhook{URL: server.URL, Events: []domain.WebhookEvent{domain.WebhookEventGameCleared}, Enabled: false},
+	)
+	game := &domain.Game{ID: "game-1", Title: "テストゲーム"}
+	bus.Publish(EventGameUpdated, GameUpdatedEvent{GameID: game.ID, Game: game})
+	bus.Publish(EventGameUpdated, GameUpdatedEvent{GameID: game.ID, Game: game, Cleared: true})
+	bus.Publish(EventSyncCompleted, SyncCompletedEvent{GameID: game.ID, Direction: OperationPush, Applied: true})
+	bus.Wait()
+	tasks.Wait()
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(bodies))
+	}
+	if got := <-bodies; got != `{"message": "テストゲーム をクリア"}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+}
+
+func TestWebhookServiceRetriesServerErrors(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !json.Valid(body) {
+			t.Errorf("retried body is not JSON: %q", body)
+		}
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	service, _, _ := newTestWebhookService(t, domain.Webhook{
+		URL: server.URL, Events: []domain.WebhookEvent{domain.WebhookEventSyncFinished}, Enabled: true,
+	})
+	webhooks, err := service.ListWebhooks(context.Background())
+	if err != nil || len(webhooks) != 1 || webhooks[0].ID == "" {
+		t.Fatalf("unexpected webhooks: %+v, %v", webhooks, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := service.SendTest(ctx, webhooks[0].ID); err != nil {
+		t.Fatalf("SendTest failed: %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Fatalf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestWebhookServiceReportsNonSuccessStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	service, _, _ := newTestWebhookService(t, domain.Webhook{
+		ID: "fixed", URL: server.URL, Events: []domain.WebhookEvent{domain.WebhookEventSessionEnded},
+	})
+	if err := service.SendTest(context.Background(), "fixed"); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	if err := service.SendTest(context.Background(), "missing"); err == nil {
+		t.Fatal("expected not found error")
+	}
+}
+
+func TestWebhookServiceValidatesSettings(t *testing.T) {
+	t.Parallel()
+
+	service := NewWebhookService(config.Config{}, &fakeWebhookRepository{}, newTestLogger())
+	ctx := context.Background()
+	cases := map[string]domain.Webhook{
+		"scheme":        {URL: "ftp://example.com", Events: []domain.WebhookEvent{domain.WebhookEventSessionEnded}},
+		"no events":     {URL: "https://example.com"},
+		"unknown event": {URL: "https://example.com", Events: []domain.WebhookEvent{"game.deleted"}},
+		"bad template":  {URL: "https://example.com", Events: []domain.WebhookEvent{domain.WebhookEventSessionEnded}, BodyTemplate: "{{.Missing"},
+		"not json":      {URL: "https://example.com", Events: []domain.WebhookEvent{domain.WebhookEventSessionEnded}, BodyTemplate: "title={{.GameTitle}}"},
+	}
+	for name, webhook := range cases {
+		if _, err := service.SaveWebhooks(ctx, []domain.Webhook{webhook}); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+
+	saved, err := service.SaveWebhooks(ctx, []domain.Webhook{{
+		URL:    " https://hooks.example.com/cloudlaunch ",
+		Events: []domain.WebhookEvent{domain.WebhookEventSyncFinished, domain.WebhookEventSyncFinished},
+	}})
+	if err != nil {
+		t.Fatalf("SaveWebhooks failed: %v", err)
+	}
+	if saved[0].ID == "" || saved[0].Name != "hooks.example.com" || saved[0].URL != "https://hooks.example.com/cloudlaunch" || len(saved[0].Events) != 1 {
+		t.Fatalf("unexpected normalized webhook: %+v", saved[0])
+	}
+}
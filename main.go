@@ -27,6 +27,9 @@ func main() {
 		panic(err)
 	}
 
+	// cloudlaunch:// URI から起動された場合は、Startup 後にその操作を実行する。
+	backend.SetLaunchArgs(os.Args[1:])
+
 	// 想定外の panic はログ（error.log）に残してから再送出する。
 	// バックグラウンド goroutine の panic は各 goroutine 側で回収するため、
 	// ここで拾うのは主に起動・実行系の致命的な panic。
@@ -57,6 +60,13 @@ func main() {
 		// フレームレス時の角の隙間が目立たないようにする。
 		BackgroundColour: &options.RGBA{R: 243, G: 243, B: 247, A: 1},
 		OnStartup:        backend.Startup,
+		// 2つ目の起動（ショートカットや Stream Deck からの cloudlaunch:// URI を含む）は起動中のインスタンスへ渡す。
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId: "com.fuyu.cloudlaunch",
+			OnSecondInstanceLaunch: func(data options.SecondInstanceData) {
+				backend.HandleSecondInstance(data.Args)
+			},
+		},
 		OnShutdown: func(ctx context.Context) {
 			_ = backend.Shutdown(ctx)
 		},